@@ -5,24 +5,50 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/bruteforce"
+	"github.com/melihgurlek/backend-path/internal/captcha"
 	"github.com/melihgurlek/backend-path/internal/config"
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/export"
+	"github.com/melihgurlek/backend-path/internal/fraud"
+	"github.com/melihgurlek/backend-path/internal/gateway"
+	"github.com/melihgurlek/backend-path/internal/geoip"
 	"github.com/melihgurlek/backend-path/internal/handler"
+	"github.com/melihgurlek/backend-path/internal/health"
+	"github.com/melihgurlek/backend-path/internal/kyc"
 	"github.com/melihgurlek/backend-path/internal/middleware"
+	"github.com/melihgurlek/backend-path/internal/migrate"
+	"github.com/melihgurlek/backend-path/internal/notification"
+	"github.com/melihgurlek/backend-path/internal/quota"
 	"github.com/melihgurlek/backend-path/internal/repository"
+	"github.com/melihgurlek/backend-path/internal/seed"
 	"github.com/melihgurlek/backend-path/internal/service"
+	"github.com/melihgurlek/backend-path/internal/tokenstore"
+	"github.com/melihgurlek/backend-path/internal/virusscan"
 	"github.com/melihgurlek/backend-path/internal/worker"
+	"github.com/melihgurlek/backend-path/migrations"
 	"github.com/melihgurlek/backend-path/pkg"
 	"github.com/melihgurlek/backend-path/pkg/cache"
+	"github.com/melihgurlek/backend-path/pkg/crypto"
+	"github.com/melihgurlek/backend-path/pkg/lifecycle"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+	"github.com/melihgurlek/backend-path/pkg/objectstore"
+	"github.com/melihgurlek/backend-path/pkg/password"
+	"github.com/melihgurlek/backend-path/pkg/secrets"
+	"github.com/melihgurlek/backend-path/pkg/tlsconfig"
 	"github.com/melihgurlek/backend-path/pkg/tracing"
 )
 
@@ -33,123 +59,640 @@ func main() {
 	}
 
 	// Load configuration
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load configuration")
+	}
 
-	// Initialize zerolog (logs to stdout by default)
+	// Initialize zerolog (logs to stdout by default). Code logging through
+	// log.Ctx outside a request falls back to the global logger.
+	zerolog.DefaultContextLogger = &log.Logger
 	log.Info().Msg("Backend Path API starting...")
-	log.Info().Str("port", cfg.Port).Str("db_url", cfg.DBUrl).Msg("Loaded configuration")
+	log.Info().Str("port", cfg.Server.Port).Int("worker_pool_size", cfg.Worker.PoolSize).Msg("Loaded configuration")
 
-	// Initialize OpenTelemetry tracing
-	jaegerURL := os.Getenv("JAEGER_URL")
-	if jaegerURL == "" {
-		jaegerURL = "jaeger:4318"
+	// Export GC, memory and process metrics alongside the application's own
+	if err := metrics.RegisterRuntimeCollectors(); err != nil {
+		log.Error().Err(err).Msg("Failed to register runtime metrics collectors")
 	}
 
-	traceCleanup, err := tracing.InitTracer("backend-path-api", "1.0.0", jaegerURL)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to initialize tracing")
-	} else {
-		defer traceCleanup()
-		log.Info().Msg("OpenTelemetry tracing initialized")
+	// Sample lock contention for the mutex profile served by the pprof routes
+	runtime.SetMutexProfileFraction(cfg.Server.MutexProfileFraction)
+
+	ctx := context.Background()
+
+	// Stop on a signal, also while still starting
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Subsystems are registered with a shutdown stage as they start. Intake
+	// stops first, then queued and background work drains, then pending
+	// notifications and spans are flushed, and connections close last.
+	lc := lifecycle.New()
+	intake := lc.Stage("intake", cfg.Server.ShutdownTimeout)
+	drain := lc.Stage("drain", cfg.Server.ShutdownDrainTimeout)
+	flush := lc.Stage("flush", cfg.Server.ShutdownDrainTimeout)
+	closing := lc.Stage("close", cfg.Server.ShutdownCloseTimeout)
+	defer func() {
+		if err := lc.Shutdown(context.Background()); err != nil {
+			log.Error().Err(err).Msg("Shutdown finished with errors")
+			return
+		}
+		log.Info().Msg("Shutdown complete.")
+	}()
+
+	// Resolve secrets kept in an external secrets manager
+	var jwtKeys pkg.KeySource = pkg.StaticKey(cfg.Auth.JWTSecret)
+	if cfg.Secrets.Backend != "env" {
+		secretsProvider := newSecretsProvider(cfg.Secrets)
+		if cfg.Secrets.ManagedDBURL() {
+			fetchCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			dbURL, err := secretsProvider.GetSecret(fetchCtx, cfg.Secrets.DBURLRef)
+			cancel()
+			if err != nil {
+				log.Fatal().Err(err).Msg("Failed to fetch database URL from secrets manager")
+			}
+			cfg.Database.URL = dbURL
+		}
+		if cfg.Secrets.ManagedJWTSecret() {
+			// Re-read lazily; the replaced secret keeps verifying tokens until they expire
+			rotatingJWTSecret := secrets.NewRotating(secretsProvider, cfg.Secrets.JWTSecretRef, cfg.Secrets.RefreshInterval, pkg.TokenTTL)
+			if _, err := rotatingJWTSecret.Current(ctx); err != nil {
+				log.Fatal().Err(err).Msg("Failed to fetch JWT secret from secrets manager")
+			}
+			jwtKeys = rotatingJWTSecret
+		}
+		log.Info().Str("backend", cfg.Secrets.Backend).Msg("Secrets manager initialized")
 	}
 
-	// Initialize Redis cache
-	redisURL := os.Getenv("REDIS_URL")
-	if redisURL == "" {
-		redisURL = "redis://redis:6379"
+	// Initialize OpenTelemetry tracing; when disabled the global no-op tracer is kept
+	if cfg.Tracing.Enabled {
+		traceCleanup, err := tracing.InitTracer(tracing.Config{
+			Endpoint:       cfg.Tracing.Endpoint,
+			ServiceName:    cfg.Tracing.ServiceName,
+			ServiceVersion: cfg.Tracing.ServiceVersion,
+			Environment:    cfg.Tracing.Environment,
+			Sampler:        cfg.Tracing.Sampler,
+			SampleRatio:    cfg.Tracing.SampleRatio,
+			RateLimit:      cfg.Tracing.RateLimit,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to initialize tracing")
+		} else {
+			flush.Add("tracing", lifecycle.Func(traceCleanup))
+			log.Info().Str("sampler", cfg.Tracing.Sampler).Msg("OpenTelemetry tracing initialized")
+		}
+	} else {
+		log.Info().Msg("OpenTelemetry tracing disabled")
 	}
 
-	redisCache, err := cache.NewRedisCache(redisURL)
+	poolConfig, err := cfg.Database.PoolConfig()
 	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid database configuration")
+	}
+	// Every query gets a span and database operation metrics
+	poolConfig.ConnConfig.Tracer = repository.NewQueryTracer()
+
+	// Connect to the dependencies concurrently, retrying each with backoff, so
+	// they may come up after this process
+	startupPolicy := domain.RetryPolicy{
+		InitialBackoff: cfg.Startup.InitialBackoff,
+		MaxBackoff:     cfg.Startup.MaxBackoff,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+	startup := health.NewStartup(cfg.Startup.WaitTimeout, startupPolicy)
+	var pool *pgxpool.Pool
+	startup.Connect(shutdownCtx, "postgres", func(ctx context.Context) error {
+		p, err := repository.ConnectDB(ctx, poolConfig)
+		if err != nil {
+			return err
+		}
+		pool = p
+		return nil
+	})
+	var redisCache *cache.RedisCache
+	startup.Connect(shutdownCtx, "redis", func(ctx context.Context) error {
+		c, err := cache.NewRedisCache(cfg.Redis.URL)
+		if err != nil {
+			return err
+		}
+		redisCache = c
+		return nil
+	})
+	if cfg.Tracing.Enabled {
+		startup.Connect(shutdownCtx, "tracing", func(ctx context.Context) error {
+			return tracing.PingCollector(ctx, cfg.Tracing.Endpoint)
+		})
+	}
+
+	// "backend migrate ..." manages the schema and "backend seed ..." fills it
+	// with demo data; both exit without serving
+	var command string
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+	serving := command != "migrate" && command != "seed"
+
+	// Serve the probes while starting; the API is served once every subsystem
+	// is wired up
+	startupHandler := handler.NewStartupHandler(handler.NewHealthHandler(health.NewService(cfg.Server.HealthCheckTimeout, startup.Checks()...)))
+	srv := &http.Server{
+		Addr:         ":" + cfg.Server.Port,
+		Handler:      startupHandler,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+	// With TLS the port speaks HTTPS and HTTP/2, and a plaintext port may
+	// redirect to it
+	var redirectSrv *http.Server
+	if cfg.Server.TLS.Enabled() {
+		tlsServer, err := tlsconfig.New(tlsconfig.Options{
+			CertFile:         cfg.Server.TLS.CertFile,
+			KeyFile:          cfg.Server.TLS.KeyFile,
+			AutocertDomains:  cfg.Server.TLS.AutocertDomains,
+			AutocertCacheDir: cfg.Server.TLS.AutocertCacheDir,
+			AutocertEmail:    cfg.Server.TLS.AutocertEmail,
+			MinVersion:       cfg.Server.TLS.MinVersion,
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to configure TLS")
+		}
+		srv.TLSConfig = tlsServer.Config
+		if cfg.Server.TLS.RedirectPort != "" {
+			redirectSrv = &http.Server{
+				Addr:         ":" + cfg.Server.TLS.RedirectPort,
+				Handler:      tlsServer.Redirect(cfg.Server.Port),
+				ReadTimeout:  cfg.Server.ReadTimeout,
+				WriteTimeout: cfg.Server.WriteTimeout,
+				IdleTimeout:  cfg.Server.IdleTimeout,
+			}
+		}
+	}
+	if serving {
+		intake.Add("http", srv.Shutdown)
+		go func() {
+			var err error
+			if srv.TLSConfig != nil {
+				log.Info().Str("port", cfg.Server.Port).Msg("HTTPS server listening")
+				err = srv.ListenAndServeTLS("", "")
+			} else {
+				log.Info().Str("port", cfg.Server.Port).Msg("HTTP server listening")
+				err = srv.ListenAndServe()
+			}
+			if err != nil && err != http.ErrServerClosed {
+				log.Fatal().Err(err).Msg("HTTP server error")
+			}
+		}()
+		if redirectSrv != nil {
+			intake.Add("http-redirect", redirectSrv.Shutdown)
+			go func() {
+				log.Info().Str("port", cfg.Server.TLS.RedirectPort).Msg("HTTP redirect server listening")
+				if err := redirectSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					log.Fatal().Err(err).Msg("HTTP redirect server error")
+				}
+			}()
+		}
+	}
+
+	unreachable := startup.Wait()
+	if shutdownCtx.Err() != nil {
+		log.Info().Msg("Shutdown requested while starting")
+		return
+	}
+	if err := unreachable["postgres"]; err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	log.Info().Msg("Connected to PostgreSQL database!")
+	closing.Add("postgres", lifecycle.Func(pool.Close))
+	if err := unreachable["redis"]; err != nil {
 		log.Error().Err(err).Msg("Failed to initialize Redis cache")
 	} else {
-		defer redisCache.Close()
+		closing.Add("redis", lifecycle.Closer(redisCache.Close))
 		log.Info().Msg("Redis cache initialized")
 	}
+	if err := unreachable["tracing"]; err != nil {
+		log.Warn().Err(err).Msg("Tracing collector unreachable, spans are dropped until it is up")
+	}
 
-	// Connect to PostgreSQL
-	ctx := context.Background()
-	pool, err := pgxpool.New(ctx, cfg.DBUrl)
-	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to connect to database")
+	// appCache is Redis, behind an in-process cache for hot keys when enabled
+	var appCache cache.Cache
+	if redisCache != nil {
+		appCache = redisCache
+		if cfg.Cache.L1Size > 0 {
+			tieredCache := cache.NewTieredCache(redisCache, cfg.Cache.L1Size, cfg.Cache.L1TTL)
+			closing.Add("l1_cache", lifecycle.Closer(tieredCache.Close))
+			appCache = tieredCache
+			log.Info().Int("l1_size", cfg.Cache.L1Size).Dur("l1_ttl", cfg.Cache.L1TTL).Msg("In-process L1 cache enabled")
+		}
+	}
+
+	if command == "migrate" {
+		if err := runMigrate(ctx, pool, os.Args[2:]); err != nil {
+			log.Fatal().Err(err).Msg("Migration command failed")
+		}
+		return
+	}
+	if cfg.Database.MigrateOnStart {
+		migrator, err := migrate.New(pool, migrations.FS)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load migrations")
+		}
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to apply migrations")
+		}
+		log.Info().Int("applied", len(applied)).Msg("Database migrations up to date")
 	}
-	log.Info().Msg("Connected to PostgreSQL database!")
-	defer func() {
-		pool.Close()
-		log.Info().Msg("Database connection pool closed.")
-	}()
 
 	// Set up repository, service, handler
-	userRepo := repository.NewUserPostgresRepository(pool)
-	userService := service.NewUserService(userRepo)
+	auditLogRepo := repository.NewAuditLogPostgresRepository(pool)
+	auditLogService := service.NewAuditLogService(auditLogRepo)
+	auditLogHandler := handler.NewAuditLogHandler(auditLogService)
 
+	fieldKeys, err := crypto.ParseKeyring(cfg.Auth.FieldEncryptionKeys, cfg.Auth.FieldEncryptionIndexKey)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid field encryption keys")
+	}
+	userRepo := repository.NewUserPostgresRepository(pool, fieldKeys)
+	// Encrypt legacy plaintext and rewrap values under retired keys in the background
+	go func() {
+		rotated, err := userRepo.RotateEncryption(ctx)
+		if err != nil {
+			log.Error().Err(err).Int("rotated", rotated).Msg("Failed to rotate encrypted user fields")
+			return
+		}
+		if rotated > 0 {
+			log.Info().Int("rotated", rotated).Msg("Rotated encrypted user fields")
+		}
+	}()
+	passwordPolicy := domain.PasswordPolicy{
+		MinLength:       cfg.Password.MinLength,
+		RequiredClasses: cfg.Password.RequiredClasses,
+	}
+	if cfg.Password.BreachFilter != "" {
+		breached, err := service.LoadBloomBreachedPasswords(cfg.Password.BreachFilter)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load breached password filter")
+		}
+		passwordPolicy.Breached = breached
+	}
+	argon2idHasher := password.NewArgon2idHasher(password.Argon2idParams{
+		Memory:      uint32(cfg.Password.Argon2Memory),
+		Iterations:  uint32(cfg.Password.Argon2Iterations),
+		Parallelism: uint8(cfg.Password.Argon2Parallelism),
+	})
+	bcryptHasher := password.NewBcryptHasher(cfg.Password.BcryptCost)
+	passwordHasher := password.NewChain(argon2idHasher, bcryptHasher)
+	if cfg.Password.HashAlgorithm == "bcrypt" {
+		passwordHasher = password.NewChain(bcryptHasher, argon2idHasher)
+	}
+	if command == "seed" {
+		seeder := seed.NewSeeder(userRepo, repository.NewSeedPostgresRepository(pool),
+			repository.NewTransactionPartitionPostgresRepository(pool), repository.NewScheduledTransactionPostgresRepository(pool),
+			repository.NewTransactionLimitPostgresRepository(pool), passwordHasher)
+		if err := runSeed(ctx, seeder, os.Args[2:]); err != nil {
+			log.Fatal().Err(err).Msg("Seed command failed")
+		}
+		return
+	}
 	var redisClient *redis.Client
+	// A nil *RedisCache must not become a non-nil interface
+	var cacheInvalidator domain.CacheInvalidator
 	if redisCache != nil {
 		redisClient = redisCache.GetClient()
+		cacheInvalidator = appCache
 	}
-	userHandler := handler.NewUserHandler(userService, cfg.JWTSecret, redisClient)
+	// Without Redis revoked tokens are only known to this instance
+	var tokenStore domain.TokenStore = tokenstore.NewMemoryStore()
+	if redisClient != nil {
+		tokenStore = tokenstore.NewRedisStore(redisClient)
+	}
+	// Notifications are delivered in the background; channels whose provider
+	// isn't configured are skipped. They are flushed after the drain stage, so
+	// the notifications sent while draining still go out.
+	notificationTemplates, err := notification.NewTemplateStore(cfg.Notifications.TemplateDir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load notification templates")
+	}
+	notificationService := notification.NewService(
+		repository.NewNotificationPostgresRepository(pool, fieldKeys),
+		userRepo,
+		notificationTemplates,
+		newNotificationSenders(cfg.Notifications),
+		notification.Config{Workers: cfg.Notifications.Workers, QueueSize: cfg.Notifications.QueueSize},
+	)
+	notificationService.Start(ctx)
+	flush.Add("notifications", lifecycle.Func(notificationService.Stop))
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+
+	// Logins from untrusted devices are verified with an emailed code, so
+	// verification is only on when email can be sent
+	loginSecurity := service.LoginSecurity{
+		VerifyDevices: cfg.Notifications.SMTPHost != "",
+		Travel: domain.TravelPolicy{
+			MaxSpeedKmh:   cfg.GeoIP.MaxTravelSpeedKmh,
+			MinDistanceKm: cfg.GeoIP.MinTravelDistanceKm,
+		},
+	}
+	if cfg.GeoIP.DBPath != "" {
+		locator, err := geoip.Load(cfg.GeoIP.DBPath)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load GeoIP database")
+		}
+		log.Info().Int("ranges", locator.Len()).Msg("Loaded GeoIP database")
+		loginSecurity.Locator = locator
+	}
+	referralService := service.NewReferralService(repository.NewReferralPostgresRepository(pool), repository.NewPostgresUnitOfWork(pool), cacheInvalidator, notificationService,
+		domain.ReferralProgram{
+			ReferrerReward: cfg.Referrals.ReferrerReward,
+			RefereeReward:  cfg.Referrals.RefereeReward,
+			MinAmount:      cfg.Referrals.MinAmount,
+			Window:         cfg.Referrals.Window,
+		})
+	referralHandler := handler.NewReferralHandler(referralService)
+	kycService := service.NewKYCService(repository.NewKYCPostgresRepository(pool), newKYCProvider(cfg.KYC), cacheInvalidator, notificationService, auditLogService)
+	kycHandler := handler.NewKYCHandler(kycService)
+	objectStore := newObjectStore(cfg.ObjectStore)
+	userService := service.NewUserService(userRepo, auditLogService, passwordPolicy, passwordHasher, cacheInvalidator, notificationService,
+		objectStore, loginSecurity, referralService)
+	var virusScanner domain.VirusScanner
+	if cfg.Documents.Scanner == "clamav" {
+		virusScanner = virusscan.NewClamAV(cfg.Documents.ClamAVAddress)
+	} else {
+		log.Warn().Msg("Document virus scanning disabled: DOCUMENT_SCANNER is not set")
+	}
+	documentService := service.NewDocumentService(repository.NewDocumentPostgresRepository(pool), userRepo, objectStore, virusScanner,
+		cfg.Auth.DocumentURLSecret, cfg.Documents.MaxBytes, cfg.Documents.URLTTL)
+	documentHandler := handler.NewDocumentHandler(documentService, cfg.Documents.MaxBytes)
+
+	userHandler := handler.NewUserHandler(userService, jwtKeys, tokenStore)
+
+	userPreferenceRepo := repository.NewUserPreferencePostgresRepository(pool)
+	userPreferenceHandler := handler.NewUserPreferenceHandler(service.NewUserPreferenceService(userPreferenceRepo))
+	userDataExportService := service.NewUserDataExportService(userRepo, userPreferenceRepo, notificationService, auditLogService)
+	userDataExportHandler := handler.NewUserDataExportHandler(userDataExportService)
 
 	balanceRepo := repository.NewBalancePostgresRepository(pool)
 	transactionRepo := repository.NewTransactionPostgresRepository(pool)
-	transactionService := service.NewTransactionService(transactionRepo, balanceRepo)
+	alertRuleService := service.NewAlertRuleService(repository.NewAlertRulePostgresRepository(pool), balanceRepo, notificationService)
+	alertRuleHandler := handler.NewAlertRuleHandler(alertRuleService)
+	feeScheduleService := service.NewFeeScheduleService(repository.NewFeeSchedulePostgresRepository(pool), userRepo, auditLogService)
+	feeScheduleHandler := handler.NewFeeScheduleHandler(feeScheduleService)
+	campaignService := service.NewCampaignService(repository.NewCampaignPostgresRepository(pool), repository.NewPostgresUnitOfWork(pool), cacheInvalidator, notificationService, auditLogService)
+	campaignHandler := handler.NewCampaignHandler(campaignService)
+	fraudService := fraud.NewService(repository.NewFraudPostgresRepository(pool), auditLogService, fraud.Config{
+		FlagScore:        cfg.Fraud.FlagScore,
+		HoldScore:        cfg.Fraud.HoldScore,
+		RejectScore:      cfg.Fraud.RejectScore,
+		VelocityWindow:   cfg.Fraud.VelocityWindow,
+		VelocityMaxCount: cfg.Fraud.VelocityMaxCount,
+		HistoryWindow:    cfg.Fraud.HistoryWindow,
+		MinHistory:       cfg.Fraud.MinHistory,
+		AmountMultiplier: cfg.Fraud.AmountMultiplier,
+		OddHoursStart:    cfg.Fraud.OddHoursStart,
+		OddHoursEnd:      cfg.Fraud.OddHoursEnd,
+	})
+	transactionService := service.NewTransactionService(transactionRepo, repository.NewPostgresUnitOfWork(pool), cacheInvalidator, notificationService, alertRuleService, feeScheduleService, domain.TransactionRewarders{campaignService, referralService},
+		service.TransactionReview{ApprovalThreshold: cfg.Limits.ApprovalThreshold, Audit: auditLogService, Fraud: fraudService})
+	disputeService := service.NewDisputeService(repository.NewDisputePostgresRepository(pool), transactionRepo, repository.NewDocumentPostgresRepository(pool),
+		repository.NewPostgresUnitOfWork(pool), cacheInvalidator, notificationService, auditLogService, cfg.Disputes.Window)
+	disputeHandler := handler.NewDisputeHandler(disputeService)
+	fundingService := service.NewFundingService(repository.NewFundingPostgresRepository(pool), userRepo, balanceRepo, transactionService,
+		repository.NewPostgresUnitOfWork(pool), cacheInvalidator, notificationService, auditLogService, domain.ChargebackPolicy{
+			AllowNegativeBalance: cfg.Chargebacks.AllowNegativeBalance,
+			FreezeThreshold:      cfg.Chargebacks.FreezeThreshold,
+		})
+	fundingHandler := handler.NewFundingHandler(fundingService)
+	// Every provider with a secret gets a webhook endpoint
+	paymentGateways := make(map[string]domain.PaymentGateway, len(cfg.Webhooks.Secrets))
+	for provider, secret := range cfg.Webhooks.Secrets {
+		paymentGateways[provider] = gateway.NewStripe(secret, cfg.Webhooks.Tolerance)
+	}
+	webhookService := service.NewWebhookService(repository.NewWebhookPostgresRepository(pool), paymentGateways, userRepo, transactionRepo,
+		transactionService, fundingService, cfg.Webhooks.Currency)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
 	transactionLimitRepo := repository.NewTransactionLimitPostgresRepository(pool)
 	transactionLimitService := service.NewTransactionLimitService(transactionLimitRepo)
 	transactionLimitHandler := handler.NewTransactionLimitHandler(transactionLimitService)
-	transactionHandler := handler.NewTransactionHandler(transactionService, transactionLimitService)
+	adminIPAllowlistService := service.NewAdminIPAllowlistService(repository.NewAdminIPAllowlistPostgresRepository(pool), userRepo, auditLogService)
+	adminIPAllowlistHandler := handler.NewAdminIPAllowlistHandler(adminIPAllowlistService)
+	// Admins with an IP allowlist may only use admin operations from it
+	adminIPs := middleware.AdminIPAllowlist(adminIPAllowlistService)
+	// Count requests against their user's quotas, if Redis can hold the counters
+	requestQuota := func(next http.Handler) http.Handler { return next }
+	var requestQuotaHandler *handler.RequestQuotaHandler
+	if redisClient != nil {
+		requestQuotaService := service.NewRequestQuotaService(repository.NewRequestQuotaPostgresRepository(pool), userRepo, quota.NewRedisCounter(redisClient), auditLogService)
+		requestQuota = middleware.RequestQuota(requestQuotaService)
+		requestQuotaHandler = handler.NewRequestQuotaHandler(requestQuotaService)
+	} else {
+		log.Warn().Msg("Request quotas disabled: Redis is unavailable")
+	}
+	approvalRepo := repository.NewApprovalPostgresRepository(pool)
+	approvalService := service.NewApprovalService(approvalRepo, transactionRepo, transactionService, auditLogService)
+	approvalHandler := handler.NewApprovalHandler(approvalService)
+	fraudHandler := handler.NewFraudHandler(fraudService)
+	transactionHandler := handler.NewTransactionHandler(transactionService)
 
 	balanceService := service.NewBalanceService(balanceRepo)
 	balanceHandler := handler.NewBalanceHandler(balanceService)
 
 	// Initialize scheduled transaction repository and service
 	scheduledRepo := repository.NewScheduledTransactionPostgresRepository(pool)
-	scheduledService := service.NewScheduledTransactionService(scheduledRepo, transactionService)
+	savingsGoalRepo := repository.NewSavingsGoalPostgresRepository(pool)
+	retryPolicy := domain.DefaultRetryPolicy()
+	retryPolicy.MaxAttempts = cfg.Scheduled.Retry.MaxAttempts
+	retryPolicy.InitialBackoff = cfg.Scheduled.Retry.InitialBackoff
+	retryPolicy.MaxBackoff = cfg.Scheduled.Retry.MaxBackoff
+	retryPolicy.Jitter = cfg.Scheduled.Retry.Jitter
+	// Admins hear about failed scheduled transactions only if configured to
+	var scheduledFailureAdmins domain.AdminNotifier
+	if cfg.Notifications.NotifyAdminsOnScheduledFailure {
+		scheduledFailureAdmins = notificationService
+	}
+	scheduledService := service.NewScheduledTransactionService(scheduledRepo, transactionService, balanceService, savingsGoalRepo, retryPolicy,
+		notificationService, scheduledFailureAdmins)
 	scheduledHandler := handler.NewScheduledTransactionHandler(scheduledService)
 
+	// Initialize hold service
+	holdRepo := repository.NewHoldPostgresRepository(pool)
+	holdService := service.NewHoldService(holdRepo, repository.NewPostgresUnitOfWork(pool), transactionService, cacheInvalidator)
+	holdHandler := handler.NewHoldHandler(holdService)
+
+	// Initialize money request service
+	moneyRequestRepo := repository.NewMoneyRequestPostgresRepository(pool)
+	moneyRequestService := service.NewMoneyRequestService(moneyRequestRepo, transactionService, notificationService, cacheInvalidator)
+	moneyRequestHandler := handler.NewMoneyRequestHandler(moneyRequestService)
+
+	// Initialize payment link service
+	paymentLinkRepo := repository.NewPaymentLinkPostgresRepository(pool)
+	paymentLinkService := service.NewPaymentLinkService(paymentLinkRepo, transactionService, cacheInvalidator, cfg.Auth.PaymentLinkSecret)
+	paymentLinkHandler := handler.NewPaymentLinkHandler(paymentLinkService)
+
+	// Initialize balance reconciliation
+	reconciliationRepo := repository.NewReconciliationPostgresRepository(pool)
+	reconciliationService := service.NewReconciliationService(reconciliationRepo, balanceRepo, cfg.Reconciliation.Hour)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationService)
+
+	// Initialize transactions partition maintenance
+	partitionRepo := repository.NewTransactionPartitionPostgresRepository(pool)
+	partitionService := service.NewTransactionPartitionService(partitionRepo,
+		cfg.Partitions.MonthsAhead, cfg.Partitions.RetentionMonths, cfg.Partitions.CheckInterval)
+
+	// Initialize transaction archiving
+	archiveRepo := repository.NewTransactionArchivePostgresRepository(pool)
+	archiveService := service.NewTransactionArchiveService(archiveRepo,
+		cfg.Archive.MaxAge, cfg.Archive.BatchSize, cfg.Archive.CheckInterval)
+	archiveHandler := handler.NewTransactionArchiveHandler(archiveService)
+
+	// Initialize savings goal service
+	savingsGoalService := service.NewSavingsGoalService(savingsGoalRepo, scheduledService, transactionService)
+	savingsGoalHandler := handler.NewSavingsGoalHandler(savingsGoalService)
+
+	// Initialize organization service
+	organizationRepo := repository.NewOrganizationPostgresRepository(pool)
+	organizationService := service.NewOrganizationService(organizationRepo, userRepo, transactionRepo, transactionService, balanceRepo, auditLogService)
+	organizationHandler := handler.NewOrganizationHandler(organizationService)
+
 	// Initialize business metrics service
 	businessMetricsService := service.NewBusinessMetricsService(
-		userRepo,
-		transactionRepo,
-		balanceRepo,
+		repository.NewBusinessMetricsPostgresRepository(pool),
+		repository.NewPoolStats(pool),
 	)
 
+	analyticsHandler := handler.NewAnalyticsHandler(service.NewAnalyticsService(repository.NewAnalyticsPostgresRepository(pool)))
+
+	cohortService := service.NewCohortService(repository.NewCohortPostgresRepository(pool), cfg.Analytics.CohortMonths, cfg.Analytics.CohortInterval)
+	cohortHandler := handler.NewCohortHandler(cohortService)
+
 	testHandler := handler.NewTestHandler()
+	pprofHandler := handler.NewPprofHandler()
 
 	// Initialize business metrics handler
 	businessMetricsHandler := handler.NewBusinessMetricsHandler(businessMetricsService)
 
 	// Initialize transaction processor (worker pool)
+	taskRepo := repository.NewTaskPostgresRepository(pool)
+	deadLetterRepo := repository.NewDeadLetterPostgresRepository(pool)
+	batchRepo := repository.NewBatchPostgresRepository(pool)
+	var callbackSender *worker.CallbackSender
+	if cfg.Callback.SigningSecret != "" {
+		callbackSender = worker.NewCallbackSender(cfg.Callback.SigningSecret, cfg.Callback.MaxAttempts, cfg.Callback.InitialBackoff)
+	} else {
+		log.Warn().Msg("CALLBACK_SIGNING_SECRET not set, task completion callbacks are disabled")
+	}
+	workerRetryPolicy := domain.DefaultRetryPolicy()
+	workerRetryPolicy.MaxAttempts = cfg.Worker.Retry.MaxAttempts
+	workerRetryPolicy.InitialBackoff = cfg.Worker.Retry.InitialBackoff
+	workerRetryPolicy.MaxBackoff = cfg.Worker.Retry.MaxBackoff
+	workerRetryPolicy.Jitter = cfg.Worker.Retry.Jitter
 	transactionProcessor := worker.NewTransactionProcessor(
 		transactionService,
 		balanceService,
-		5,   // 5 workers
-		100, // queue size of 100
+		taskRepo,
+		deadLetterRepo,
+		callbackSender,
+		cfg.Worker.PoolSize,
+		cfg.Worker.QueueSize,
+		workerRetryPolicy,
 	)
 
 	// Start the transaction processor
 	if err := transactionProcessor.Start(ctx); err != nil {
 		log.Fatal().Err(err).Msg("Failed to start transaction processor")
 	}
-	defer func() {
-		if err := transactionProcessor.Stop(ctx); err != nil {
-			log.Error().Err(err).Msg("Failed to stop transaction processor")
-		}
-	}()
+	intake.Add("task_intake", lifecycle.Func(transactionProcessor.StopIntake))
+	drain.Add("worker_pool", transactionProcessor.Stop)
 
 	// Start the business metrics service
 	businessMetricsService.Start(ctx)
-	defer businessMetricsService.Stop()
+	drain.Add("business_metrics", lifecycle.Func(businessMetricsService.Stop))
 
 	// Start the scheduled transaction service
 	scheduledService.Start(ctx)
-	defer scheduledService.Stop()
+	drain.Add("scheduler", lifecycle.Func(scheduledService.Stop))
 
-	batchProcessor := worker.NewBatchProcessor(transactionProcessor, 5, 30*time.Second)
+	// Start the nightly balance reconciliation
+	reconciliationService.Start(ctx)
+	drain.Add("reconciliation", lifecycle.Func(reconciliationService.Stop))
+
+	// Keep monthly transactions partitions created ahead and detach expired ones
+	partitionService.Start(ctx)
+	drain.Add("partitions", lifecycle.Func(partitionService.Stop))
+
+	// Move transactions past the retention age to the archive
+	archiveService.Start(ctx)
+	drain.Add("archive", lifecycle.Func(archiveService.Stop))
+
+	// Recompute the cohort retention and growth summary tables
+	cohortService.Start(ctx)
+	drain.Add("cohorts", lifecycle.Func(cohortService.Stop))
+
+	// Settle, or return, ACH and SEPA transfers once their settlement date comes
+	bankTransferService := service.NewBankTransferService(repository.NewBankTransferPostgresRepository(pool), repository.NewFundingPostgresRepository(pool),
+		userRepo, repository.NewPostgresUnitOfWork(pool), cacheInvalidator, notificationService, auditLogService,
+		map[string]int{
+			domain.BankRailACH:  cfg.BankTransfers.ACHSettlementDays,
+			domain.BankRailSEPA: cfg.BankTransfers.SEPASettlementDays,
+		}, cfg.BankTransfers.TestReturns, cfg.BankTransfers.SettleInterval)
+	bankTransferService.Start(ctx)
+	drain.Add("bank_settlement", lifecycle.Func(bankTransferService.Stop))
+	bankTransferHandler := handler.NewBankTransferHandler(bankTransferService)
+
+	// Receive-money QR codes carry their own signed payload, so nothing is stored
+	receiveCodeHandler := handler.NewReceiveCodeHandler(service.NewReceiveCodeService(userRepo, cfg.Auth.ReceiveQRSecret))
+
+	// Liveness and readiness probes; the in-process subsystems decide liveness
+	healthChecks := []domain.HealthCheck{
+		{Name: "postgres", Check: pool.Ping},
+		{Name: "worker_pool", Check: transactionProcessor.CheckHealth, Liveness: true},
+		{Name: "scheduler", Check: scheduledService.CheckHealth, Liveness: true},
+	}
+	if redisClient != nil {
+		healthChecks = append(healthChecks, domain.HealthCheck{Name: "redis", Check: func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}})
+	}
+	healthHandler := handler.NewHealthHandler(health.NewService(cfg.Server.HealthCheckTimeout, healthChecks...))
+
+	batchProcessor := worker.NewBatchProcessor(transactionProcessor, batchRepo, taskRepo, cfg.Worker.BatchMaxConcurrency, cfg.Worker.BatchTimeout)
 
 	// Initialize worker handler
 	workerHandler := handler.NewWorkerHandler(transactionProcessor, batchProcessor)
 
-	jwtValidator := pkg.NewJWTValidator(cfg.JWTSecret)
-	authMiddleware := middleware.NewAuthMiddleware(jwtValidator, redisClient)
+	// Pay merchants out on their schedule through batches on the worker pool
+	merchantService := service.NewMerchantService(repository.NewMerchantPostgresRepository(pool), userRepo, repository.NewFundingPostgresRepository(pool),
+		taskRepo, batchProcessor, auditLogService, cfg.Merchants.PayoutInterval)
+	merchantService.Start(ctx)
+	intake.Add("payouts", lifecycle.Func(merchantService.Stop))
+	merchantHandler := handler.NewMerchantHandler(merchantService)
+
+	// Invoice the fees of each month once it is over
+	invoiceService := service.NewInvoiceService(repository.NewInvoicePostgresRepository(pool), userRepo, organizationRepo, cacheInvalidator,
+		cfg.Invoices.Issuer, cfg.Invoices.Currency, cfg.Invoices.Interval)
+	invoiceService.Start(ctx)
+	intake.Add("invoicing", lifecycle.Func(invoiceService.Stop))
+	invoiceHandler := handler.NewInvoiceHandler(invoiceService)
+
+	// Initialize admin dashboard
+	adminOverviewService := service.NewAdminOverviewService(repository.NewAdminOverviewPostgresRepository(pool), transactionProcessor)
+	adminOverviewHandler := handler.NewAdminOverviewHandler(adminOverviewService)
+
+	// Initialize transaction history exports
+	transactionExportService := export.NewService(repository.NewTransactionExportPostgresRepository(pool), export.Config{
+		Currency:    cfg.Export.Currency,
+		Institution: cfg.Export.Institution,
+		BankID:      cfg.Export.BankID,
+	})
+	transactionExportHandler := handler.NewTransactionExportHandler(transactionExportService)
+
+	// Initialize historical transaction imports
+	transactionImportService := service.NewTransactionImportService(repository.NewTransactionImportPostgresRepository(pool), userRepo, cacheInvalidator, auditLogService)
+	transactionImportHandler := handler.NewTransactionImportHandler(transactionImportService)
+
+	jwtValidator := pkg.NewRotatingJWTValidator(jwtKeys)
+	authMiddleware := middleware.NewAuthMiddleware(jwtValidator, tokenStore)
 
 	// Set up chi router
 	r := chi.NewRouter()
@@ -160,104 +703,484 @@ func main() {
 	tracingMiddleware := middleware.NewTracingMiddleware()
 	r.Use(tracingMiddleware.Middleware)
 
+	// Log through a request-scoped logger carrying the request and trace IDs
+	r.Use(middleware.RequestLogger())
+
 	// Add metrics middleware
 	metricsMiddleware := middleware.NewMetricsMiddleware()
 	r.Use(metricsMiddleware.Middleware)
 
-	// Add cache middleware (if Redis is available)
+	// Serve unversioned /api paths by the version their API-Version header asks for
+	r.Use(middleware.NegotiateAPIVersion("/api", middleware.APIVersion1))
+
+	// Cache GET responses per user (if Redis is available); it runs after
+	// authentication, in the authenticated route group
+	cacheResponses := func(next http.Handler) http.Handler { return next }
 	if redisCache != nil {
-		cacheMiddleware := middleware.NewCacheMiddleware(redisCache, 5*time.Minute)
-		r.Use(cacheMiddleware.Middleware)
+		cacheResponses = middleware.NewCacheMiddleware(appCache, cfg.Cache.ResponseTTL,
+			middleware.WithStaleWhileRevalidate(cfg.Cache.StaleTTL),
+			middleware.WithRouteTTLs(cfg.Cache.RouteTTLs)).Middleware
 		log.Info().Msg("Cache middleware enabled")
 	}
 
+	// Ask for a CAPTCHA on registration and login after repeated failures
+	captchaFor := func(string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	if cfg.Captcha.Provider != "" {
+		verifier, err := captcha.New(cfg.Captcha.Provider, cfg.Captcha.Secret)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to configure captcha")
+		}
+		var failures middleware.CaptchaFailures = captcha.NewMemoryFailures(cfg.Captcha.FailureWindow)
+		if redisClient != nil {
+			failures = captcha.NewRedisFailures(redisClient, cfg.Captcha.FailureWindow)
+		}
+		captchaFor = middleware.NewCaptchaGuard(verifier, failures, cfg.Captcha.FailureThreshold).Middleware
+		log.Info().Str("provider", cfg.Captcha.Provider).Msg("CAPTCHA enabled")
+	}
+
+	// Slow down and ban clients failing to log in, if Redis can count their failures
+	bruteForce := func(func(*http.Request) string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	var authBanHandler *handler.AuthBanHandler
+	if redisClient != nil {
+		guard := bruteforce.NewGuard(redisClient, auditLogService, bruteforce.Policy{
+			Window:           cfg.BruteForce.Window,
+			TarpitAfter:      cfg.BruteForce.TarpitAfter,
+			TarpitDelay:      cfg.BruteForce.TarpitDelay,
+			MaxTarpitDelay:   cfg.BruteForce.MaxTarpitDelay,
+			IPBanAfter:       cfg.BruteForce.IPBanAfter,
+			UsernameBanAfter: cfg.BruteForce.UsernameBanAfter,
+			BanDuration:      cfg.BruteForce.BanDuration,
+		})
+		bruteForce = func(username func(*http.Request) string) func(http.Handler) http.Handler {
+			return middleware.BruteForceProtection(guard, username)
+		}
+		authBanHandler = handler.NewAuthBanHandler(guard)
+	} else {
+		log.Warn().Msg("Brute-force protection disabled: Redis is unavailable")
+	}
+
 	jsonValidator := &middleware.JSONValidator{}
 	validateRegister := middleware.ValidationMiddleware(jsonValidator, func() interface{} { return &handler.RegisterRequest{} })
 	validateLogin := middleware.ValidationMiddleware(jsonValidator, func() interface{} { return &handler.LoginRequest{} })
-	validateUpdate := middleware.ValidationMiddleware(jsonValidator, func() interface{} { return &handler.UpdateRequest{} })
-	validateCreateScheduledTx := middleware.ValidationMiddleware(jsonValidator, func() interface{} { return &handler.CreateScheduledTransactionRequest{} })
+	validateVerifyLogin := middleware.ValidationMiddleware(jsonValidator, func() interface{} { return &handler.VerifyLoginRequest{} })
+	validateIssueToken := middleware.ValidationMiddleware(jsonValidator, func() interface{} { return &handler.IssueTokenRequest{} })
 
-	r.Route("/api/v1", func(r chi.Router) {
-		r.With(validateRegister).Post("/auth/register", userHandler.Register)
-		r.With(validateLogin).Post("/auth/login", userHandler.Login)
+	authenticated := &authenticatedHandlers{
+		pprof:             pprofHandler,
+		transactionLimit:  transactionLimitHandler,
+		adminIPAllowlist:  adminIPAllowlistHandler,
+		authBan:           authBanHandler,
+		feeSchedule:       feeScheduleHandler,
+		campaign:          campaignHandler,
+		approval:          approvalHandler,
+		fraud:             fraudHandler,
+		auditLog:          auditLogHandler,
+		requestQuota:      requestQuotaHandler,
+		notification:      notificationHandler,
+		userPreference:    userPreferenceHandler,
+		userDataExport:    userDataExportHandler,
+		referral:          referralHandler,
+		kyc:               kycHandler,
+		document:          documentHandler,
+		dispute:           disputeHandler,
+		funding:           fundingHandler,
+		webhook:           webhookHandler,
+		merchant:          merchantHandler,
+		bankTransfer:      bankTransferHandler,
+		invoice:           invoiceHandler,
+		receiveCode:       receiveCodeHandler,
+		user:              userHandler,
+		alertRule:         alertRuleHandler,
+		adminOverview:     adminOverviewHandler,
+		transactionExport: transactionExportHandler,
+		transactionImport: transactionImportHandler,
+		organization:      organizationHandler,
+		scheduled:         scheduledHandler,
+		savingsGoal:       savingsGoalHandler,
+		moneyRequest:      moneyRequestHandler,
+		paymentLink:       paymentLinkHandler,
+		worker:            workerHandler,
+		analytics:         analyticsHandler,
+		transaction:       transactionHandler,
+		hold:              holdHandler,
+		balance:           balanceHandler,
+		reconciliation:    reconciliationHandler,
+		archive:           archiveHandler,
+	}
+
+	// Every API version serves the same routes; handlers adapt the bodies that
+	// differ between versions
+	apiRoutes := func(r chi.Router) {
+		r.With(captchaFor("register"), validateRegister).Post("/auth/register", userHandler.Register)
+		r.With(captchaFor("login"), validateLogin, bruteForce(handler.LoginUsername)).Post("/auth/login", userHandler.Login)
+		r.With(validateVerifyLogin, bruteForce(nil)).Post("/auth/login/verify", userHandler.VerifyLogin)
 		r.With(authMiddleware.Middleware).Post("/auth/logout", userHandler.Logout)
+		r.With(authMiddleware.Middleware, validateIssueToken).Post("/auth/tokens", userHandler.IssueToken)
 
 		// Test routes (no auth required)
 		r.Route("/test", func(r chi.Router) {
 			testHandler.RegisterRoutes(r)
 		})
 
+		// KYC provider callbacks (authenticated by the provider's signature)
+		r.Post("/kyc/callback", kycHandler.Callback)
+
+		// Payment provider webhooks (authenticated by the provider's signature)
+		r.Post("/webhooks/{provider}", webhookHandler.Receive)
+
+		// Document downloads (authenticated by the signed token in the URL)
+		r.Get("/documents/download/{token}", documentHandler.Download)
+
 		// Business metrics routes (no auth required for monitoring)
 		r.Route("/metrics", func(r chi.Router) {
 			businessMetricsHandler.RegisterRoutes(r)
+			cohortHandler.RegisterRoutes(r)
+		})
+
+		authenticatedRoutes(r, authenticated, authMiddleware.Middleware, requestQuota, adminIPs, cacheResponses)
+	}
+
+	v1DeprecatedAt, v1Sunset := cfg.API.V1Dates()
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(middleware.WithAPIVersion(middleware.APIVersion1))
+		r.Use(middleware.Deprecation(middleware.APIVersion1, middleware.APIVersion2, v1DeprecatedAt, v1Sunset))
+		apiRoutes(r)
+	})
+	r.Route("/api/v2", func(r chi.Router) {
+		r.Use(middleware.WithAPIVersion(middleware.APIVersion2))
+		apiRoutes(r)
+	})
+
+	// Metrics endpoint for Prometheus
+	r.Handle("/metrics", promhttp.Handler())
+
+	// Kubernetes liveness and readiness probes
+	healthHandler.RegisterRoutes(r)
+
+	// The internal listener serves the operational routes to clients with a
+	// certificate, and the public port stops serving them, so a stolen token
+	// alone can't reach them
+	publicHandler := http.Handler(r)
+	if cfg.Internal.Port != "" {
+		internalTLS, err := tlsconfig.NewMutual(cfg.Internal.CertFile, cfg.Internal.KeyFile, cfg.Internal.ClientCAFile, cfg.Server.TLS.MinVersion)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to configure internal listener TLS")
+		}
+		// Identities were validated when the config was loaded
+		identities, _ := cfg.Internal.Identities()
+		certClaims := make(map[string]middleware.UserClaims, len(identities))
+		for name, identity := range identities {
+			certClaims[name] = middleware.UserClaims{UserID: strconv.Itoa(identity.UserID), Role: identity.Role}
+		}
+		internalSrv := &http.Server{
+			Addr:         ":" + cfg.Internal.Port,
+			Handler:      middleware.ClientCertAuth(certClaims)(middleware.ServeOnlyPaths(cfg.Internal.Paths)(r)),
+			TLSConfig:    internalTLS,
+			ReadTimeout:  cfg.Server.ReadTimeout,
+			WriteTimeout: cfg.Server.WriteTimeout,
+			IdleTimeout:  cfg.Server.IdleTimeout,
+		}
+		intake.Add("internal-http", internalSrv.Shutdown)
+		go func() {
+			log.Info().Str("port", cfg.Internal.Port).Strs("paths", cfg.Internal.Paths).Msg("Internal mTLS server listening")
+			if err := internalSrv.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				log.Fatal().Err(err).Msg("Internal HTTP server error")
+			}
+		}()
+		publicHandler = middleware.HidePaths(cfg.Internal.Paths)(r)
+	}
+
+	// Serve the API from now on
+	startup.Started()
+	startupHandler.Ready(publicHandler)
+
+	log.Info().Msg("Press Ctrl+C to exit")
+	<-shutdownCtx.Done() // Wait for shutdown signal
+	log.Info().Msg("Shutting down gracefully...")
+}
+
+// authenticatedHandlers holds the handlers of the routes that need a token.
+type authenticatedHandlers struct {
+	pprof             *handler.PprofHandler
+	transactionLimit  *handler.TransactionLimitHandler
+	adminIPAllowlist  *handler.AdminIPAllowlistHandler
+	authBan           *handler.AuthBanHandler // nil without Redis
+	feeSchedule       *handler.FeeScheduleHandler
+	campaign          *handler.CampaignHandler
+	approval          *handler.ApprovalHandler
+	fraud             *handler.FraudHandler
+	auditLog          *handler.AuditLogHandler
+	requestQuota      *handler.RequestQuotaHandler // nil without Redis
+	notification      *handler.NotificationHandler
+	userPreference    *handler.UserPreferenceHandler
+	userDataExport    *handler.UserDataExportHandler
+	referral          *handler.ReferralHandler
+	kyc               *handler.KYCHandler
+	document          *handler.DocumentHandler
+	dispute           *handler.DisputeHandler
+	funding           *handler.FundingHandler
+	webhook           *handler.WebhookHandler
+	merchant          *handler.MerchantHandler
+	bankTransfer      *handler.BankTransferHandler
+	invoice           *handler.InvoiceHandler
+	receiveCode       *handler.ReceiveCodeHandler
+	user              *handler.UserHandler
+	alertRule         *handler.AlertRuleHandler
+	adminOverview     *handler.AdminOverviewHandler
+	transactionExport *handler.TransactionExportHandler
+	transactionImport *handler.TransactionImportHandler
+	organization      *handler.OrganizationHandler
+	scheduled         *handler.ScheduledTransactionHandler
+	savingsGoal       *handler.SavingsGoalHandler
+	moneyRequest      *handler.MoneyRequestHandler
+	paymentLink       *handler.PaymentLinkHandler
+	worker            *handler.WorkerHandler
+	analytics         *handler.AnalyticsHandler
+	transaction       *handler.TransactionHandler
+	hold              *handler.HoldHandler
+	balance           *handler.BalanceHandler
+	reconciliation    *handler.ReconciliationHandler
+	archive           *handler.TransactionArchiveHandler
+}
+
+// authenticatedRoutes registers the routes that need a token, checking admins' IPs on all of them.
+func authenticatedRoutes(r chi.Router, h *authenticatedHandlers, authenticate, requestQuota, adminIPs, cacheResponses func(http.Handler) http.Handler) {
+	jsonValidator := &middleware.JSONValidator{}
+	validateUpdate := middleware.ValidationMiddleware(jsonValidator, func() interface{} { return &handler.UpdateRequest{} })
+	validatePatchUser := middleware.ValidationMiddleware(jsonValidator, func() interface{} { return &handler.PatchUserRequest{} })
+	validateChangePassword := middleware.ValidationMiddleware(jsonValidator, func() interface{} { return &handler.ChangePasswordRequest{} })
+	validateCreateScheduledTx := middleware.ValidationMiddleware(jsonValidator, func() interface{} { return &handler.CreateScheduledTransactionRequest{} })
+	validatePreviewScheduledTx := middleware.ValidationMiddleware(jsonValidator, func() interface{} { return &handler.PreviewScheduledTransactionRequest{} })
+
+	// Tokens limited to scopes only reach the routes of their resources
+	requireScope := middleware.RequireRouteScope()
+
+	// Admin IPs are checked before a cached response could be served
+	r.With(authenticate, requireScope, requestQuota, adminIPs).Group(func(r chi.Router) {
+		// Routes that must not be cached: their responses change without
+		// invalidating the cache (rules enforced at once, review queues,
+		// usage, background payouts and settlements, provider callbacks,
+		// logins), or they serve downloads and expiring URLs
+		r.Group(func(r chi.Router) {
+			// --- Profiling Routes (admin only) ---
+			h.pprof.RegisterRoutes(r)
+
+			// --- Transaction Limit Routes ---
+			h.transactionLimit.RegisterRoutes(r)
+
+			// --- Admin IP Allowlist Routes (admin only) ---
+			h.adminIPAllowlist.RegisterRoutes(r)
+
+			// --- Brute-Force Ban Routes (admin only; need Redis) ---
+			if h.authBan != nil {
+				h.authBan.RegisterRoutes(r)
+			}
+
+			// --- Fee Schedule Routes (admin only) ---
+			h.feeSchedule.RegisterRoutes(r)
+
+			// --- Campaign Routes (admin only) ---
+			h.campaign.RegisterRoutes(r)
+
+			// --- Approval, Fraud Review and Audit Log Routes (admin only) ---
+			h.approval.RegisterRoutes(r)
+			h.fraud.RegisterRoutes(r)
+			h.auditLog.RegisterRoutes(r)
+
+			// --- Request Quota Routes (admin only, but for usage; need Redis) ---
+			if h.requestQuota != nil {
+				h.requestQuota.RegisterRoutes(r)
+			}
+
+			// --- Notification Settings Routes ---
+			h.notification.RegisterRoutes(r)
+
+			// --- User Preference and Data Export Routes ---
+			h.userPreference.RegisterRoutes(r)
+			h.userDataExport.RegisterRoutes(r)
+
+			// --- Referral Routes ---
+			h.referral.RegisterRoutes(r)
+
+			// --- KYC Routes ---
+			h.kyc.RegisterRoutes(r)
+
+			// --- Document Routes ---
+			h.document.RegisterRoutes(r)
+
+			// --- Dispute Routes ---
+			h.dispute.RegisterRoutes(r)
+
+			// --- Funding Routes ---
+			h.funding.RegisterRoutes(r)
+
+			// --- Webhook Event Routes (admin only) ---
+			h.webhook.RegisterRoutes(r)
+
+			// --- Merchant Routes ---
+			h.merchant.RegisterRoutes(r)
+
+			// --- Bank Transfer Routes ---
+			h.bankTransfer.RegisterRoutes(r)
+
+			// --- Invoice Routes ---
+			h.invoice.RegisterRoutes(r)
+
+			// --- Receive QR Code Routes ---
+			h.receiveCode.RegisterRoutes(r)
+
+			// --- Login History Routes ---
+			r.Get("/users/{id}/logins", h.user.ListLogins)
+
+			// --- Trusted Device Routes ---
+			r.Get("/users/{id}/devices", h.user.ListDevices)
+			r.Delete("/users/{id}/devices/{fingerprint}", h.user.RevokeDevice)
+
+			// --- Alert Rule Routes ---
+			h.alertRule.RegisterRoutes(r)
+
+			// --- Admin Dashboard Routes ---
+			h.adminOverview.RegisterRoutes(r)
+
+			// --- Transaction Export Routes ---
+			h.transactionExport.RegisterRoutes(r)
+
+			// --- Transaction Import Routes (admin only) ---
+			h.transactionImport.RegisterRoutes(r)
+
+			// --- Organization Routes ---
+			r.Route("/organizations", func(r chi.Router) {
+				h.organization.RegisterRoutes(r)
+				h.invoice.RegisterOrganizationRoutes(r)
+			})
 		})
 
-		r.With(authMiddleware.Middleware).Group(func(r chi.Router) {
+		// The other routes are cached per user
+		r.With(cacheResponses).Group(func(r chi.Router) {
 			// --- Scheduled Transaction Routes ---
 			r.Route("/scheduled-transactions", func(r chi.Router) {
-				r.With(validateCreateScheduledTx).Post("/", scheduledHandler.CreateScheduledTransaction)
-
-				r.Get("/", scheduledHandler.ListUserScheduledTransactions)
-				r.Get("/stats", scheduledHandler.GetScheduledTransactionStats)
-				r.Get("/{id}", scheduledHandler.GetScheduledTransaction)
-				r.Put("/{id}", scheduledHandler.UpdateScheduledTransaction)
-				r.Delete("/{id}", scheduledHandler.CancelScheduledTransaction)
-				r.Post("/execute", scheduledHandler.ExecuteScheduledTransactions)
+				r.With(validateCreateScheduledTx).Post("/", h.scheduled.CreateScheduledTransaction)
+				r.With(validatePreviewScheduledTx).Post("/preview", h.scheduled.PreviewScheduledTransaction)
+
+				r.Get("/", h.scheduled.ListUserScheduledTransactions)
+				r.Get("/stats", h.scheduled.GetScheduledTransactionStats)
+				r.Get("/{id}", h.scheduled.GetScheduledTransaction)
+				r.Put("/{id}", h.scheduled.UpdateScheduledTransaction)
+				r.Delete("/{id}", h.scheduled.CancelScheduledTransaction)
+				r.Post("/{id}/pause", h.scheduled.PauseScheduledTransaction)
+				r.Post("/{id}/resume", h.scheduled.ResumeScheduledTransaction)
+				r.Post("/execute", h.scheduled.ExecuteScheduledTransactions)
+			})
+
+			// --- Savings Goal Routes ---
+			r.Route("/savings-goals", func(r chi.Router) {
+				h.savingsGoal.RegisterRoutes(r)
+			})
+
+			// --- Money Request Routes ---
+			r.Route("/transfers/requests", func(r chi.Router) {
+				h.moneyRequest.RegisterRoutes(r)
+			})
+
+			// --- Payment Link Routes ---
+			r.Route("/payment-links", func(r chi.Router) {
+				h.paymentLink.RegisterRoutes(r)
 			})
 
 			// --- Worker Routes ---
 			r.Route("/worker", func(r chi.Router) {
-				workerHandler.RegisterRoutes(r)
+				h.worker.RegisterRoutes(r)
 			})
 
 			// --- User Routes ---
 			r.Route("/users", func(r chi.Router) {
-				r.With(middleware.RequireRoles("admin")).Get("/", userHandler.ListUsers)
-				r.Get("/{id}", userHandler.GetUserByID)
-				r.With(validateUpdate).Put("/{id}", userHandler.UpdateUser)
-				r.Delete("/{id}", userHandler.DeleteUser)
+				r.With(middleware.RequireRoles("admin")).Get("/", h.user.ListUsers)
+				r.Get("/{id}", h.user.GetUserByID)
+				r.With(validateUpdate).Put("/{id}", h.user.UpdateUser)
+				r.With(validatePatchUser).Patch("/{id}", h.user.PatchUser)
+				r.Get("/{id}/avatar", h.user.GetAvatar)
+				r.Put("/{id}/avatar", h.user.UploadAvatar)
+				r.Delete("/{id}/avatar", h.user.DeleteAvatar)
+				r.Delete("/{id}", h.user.DeleteUser)
+				r.With(validateChangePassword).Put("/{id}/password", h.user.ChangePassword)
+				r.Post("/{id}/erase", h.user.EraseUser)
+				r.Get("/{id}/analytics", h.analytics.GetUserAnalytics)
 			})
 
 			// --- Transaction Routes ---
-			transactionHandler.RegisterRoutes(r)
+			h.transaction.RegisterRoutes(r)
 
-			// --- Transaction Limit Routes ---
-			transactionLimitHandler.RegisterRoutes(r)
+			// --- Hold Routes ---
+			h.hold.RegisterRoutes(r)
 
 			// --- Balance Routes ---
-			balanceHandler.RegisterRoutes(r)
+			h.balance.RegisterRoutes(r)
 
+			// --- Reconciliation Routes ---
+			h.reconciliation.RegisterRoutes(r)
+
+			// --- Transaction Archive Routes ---
+			h.archive.RegisterRoutes(r)
 		})
 	})
+}
 
-	// Metrics endpoint for Prometheus
-	r.Handle("/metrics", promhttp.Handler())
-
-	// Start HTTP server in a goroutine
-	srv := &http.Server{
-		Addr:    ":" + cfg.Port,
-		Handler: r,
+// newNotificationSenders returns a sender for every configured notification channel.
+func newNotificationSenders(cfg config.NotificationConfig) map[string]notification.Sender {
+	senders := make(map[string]notification.Sender)
+	if cfg.SMTPHost != "" {
+		senders[domain.ChannelEmail] = notification.NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
 	}
-	go func() {
-		log.Info().Str("port", cfg.Port).Msg("HTTP server listening")
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatal().Err(err).Msg("HTTP server error")
+	if cfg.TwilioAccountSID != "" {
+		senders[domain.ChannelSMS] = notification.NewTwilioSender(cfg.TwilioAccountSID, cfg.TwilioAuthToken, cfg.TwilioFrom)
+	}
+	if cfg.FCMCredentialsFile != "" {
+		fcm, err := notification.NewFCMSender(cfg.FCMCredentialsFile)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to load FCM credentials, push notifications are disabled")
+		} else {
+			senders[domain.ChannelPush] = fcm
 		}
-	}()
+	}
+	return senders
+}
 
-	// Graceful shutdown setup
-	shutdownCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
+// newObjectStore builds the store for uploaded files for a validated config.
+func newObjectStore(cfg config.ObjectStoreConfig) domain.ObjectStore {
+	if cfg.Backend == "s3" {
+		return objectstore.NewS3Store(cfg.S3Bucket, cfg.S3Region, cfg.S3Endpoint, secrets.AWSCredentialsFromEnv())
+	}
+	return objectstore.NewLocalStore(cfg.LocalDir)
+}
 
-	log.Info().Msg("Press Ctrl+C to exit")
-	<-shutdownCtx.Done() // Wait for shutdown signal
-	log.Info().Msg("Shutting down gracefully...")
+// newKYCProvider builds the identity verification provider of a validated config.
+func newKYCProvider(cfg config.KYCConfig) domain.KYCProvider {
+	switch cfg.Provider {
+	case "http":
+		provider, err := kyc.NewHTTPProvider(cfg.APIURL, cfg.APIKey, cfg.WebhookSecret)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create KYC provider")
+		}
+		return provider
+	case "mock":
+		return kyc.NewMock(cfg.WebhookSecret)
+	default:
+		return nil
+	}
+}
 
-	// Shutdown HTTP server
-	ctxTimeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := srv.Shutdown(ctxTimeout); err != nil {
-		log.Error().Err(err).Msg("HTTP server shutdown error")
+// newSecretsProvider builds the secrets manager client for a validated config.
+func newSecretsProvider(cfg config.SecretsConfig) secrets.Provider {
+	switch cfg.Backend {
+	case "vault":
+		return secrets.NewVaultProvider(cfg.VaultAddress, cfg.VaultToken, cfg.VaultMount)
+	case "aws":
+		return secrets.NewAWSProvider(cfg.AWSRegion, secrets.AWSCredentialsFromEnv())
+	default:
+		return secrets.EnvProvider{}
 	}
-	log.Info().Msg("Shutdown complete.")
 }
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/handler"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// adminToken validates every token as admin 1's
+type adminToken struct{}
+
+func (adminToken) ValidateToken(tokenString string) (*middleware.UserClaims, error) {
+	return &middleware.UserClaims{UserID: "1", Role: "admin"}, nil
+}
+
+// noAdminIPs allows admins from no address
+type noAdminIPs struct{}
+
+func (noAdminIPs) Allowed(ctx context.Context, userID int, ip, path string) (bool, error) {
+	return false, nil
+}
+
+// routeParam matches the URL parameters and wildcards of a route pattern
+var routeParam = regexp.MustCompile(`\{[^}]+\}|\*`)
+
+// TestAuthenticatedRoutesCheckAdminIPs fails when an authenticated route,
+// cached or not, serves an admin outside their IP allowlist
+func TestAuthenticatedRoutesCheckAdminIPs(t *testing.T) {
+	passThrough := func(next http.Handler) http.Handler { return next }
+	// The other handlers are nil; no request may reach them
+	h := &authenticatedHandlers{authBan: &handler.AuthBanHandler{}, requestQuota: &handler.RequestQuotaHandler{}}
+	r := chi.NewRouter()
+	authenticatedRoutes(r, h, middleware.NewAuthMiddleware(adminToken{}, nil).Middleware, passThrough,
+		middleware.AdminIPAllowlist(noAdminIPs{}), passThrough)
+
+	var routes []string
+	err := chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes = append(routes, method+" "+route)
+		req := httptest.NewRequest(method, routeParam.ReplaceAllString(route, "1"), strings.NewReader("{}"))
+		req.Header.Set("Authorization", "Bearer admin-token")
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "198.51.100.1:4321"
+		rw := httptest.NewRecorder()
+		r.ServeHTTP(rw, req)
+
+		assert.Equal(t, http.StatusForbidden, rw.Code, "%s %s", method, route)
+		assert.Contains(t, rw.Body.String(), "admin_ip_not_allowed", "%s %s", method, route)
+		return nil
+	})
+	require.NoError(t, err)
+	for _, route := range []string{
+		"GET /admin/debug/pprof/{profile}",
+		"GET /admin/overview",
+		"POST /transactions/import",
+		"GET /transactions/user/{user_id}/export",
+		"PUT /users/{userID}/notifications/preferences",
+		"GET /users/{id}/logins",
+		"DELETE /users/{id}/devices/{fingerprint}",
+		"GET /users/",
+	} {
+		assert.Contains(t, routes, route)
+	}
+}
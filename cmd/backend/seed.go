@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/melihgurlek/backend-path/internal/seed"
+)
+
+// runSeed executes the seed subcommand with args (the words after "seed").
+func runSeed(ctx context.Context, seeder *seed.Seeder, args []string) error {
+	opts := seed.DefaultOptions()
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: backend seed [flags]\n\ncreates demo users, balances, transaction history, scheduled transactions and limit rules\n\nflags:")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&opts.Prefix, "prefix", opts.Prefix, "username prefix; must not have been seeded before")
+	flags.StringVar(&opts.Password, "password", opts.Password, "password of every seeded user")
+	flags.IntVar(&opts.Users, "users", opts.Users, "regular users to create, plus one admin")
+	flags.IntVar(&opts.Months, "months", opts.Months, "months of transaction history")
+	flags.IntVar(&opts.TransactionsPerMonth, "tx-per-month", opts.TransactionsPerMonth, "average transactions per user a month")
+	flags.IntVar(&opts.ScheduledPerUser, "scheduled", opts.ScheduledPerUser, "upcoming scheduled transactions per user")
+	flags.Float64Var(&opts.LimitRuleShare, "limit-share", opts.LimitRuleShare, "share of users given transaction limit rules, 0-1")
+	flags.Int64Var(&opts.RandomSeed, "seed", opts.RandomSeed, "random seed; the same seed generates the same data")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	summary, err := seeder.Run(ctx, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("seeded %d user(s), %d transaction(s), %d scheduled transaction(s) and %d limit rule(s)\n",
+		summary.Users, summary.Transactions, summary.ScheduledTransactions, summary.LimitRules)
+	fmt.Printf("log in as %s_admin or %s_user_00001 with password %q\n", opts.Prefix, opts.Prefix, opts.Password)
+	return nil
+}
@@ -0,0 +1,73 @@
+// Command breachfilter builds the breached-password Bloom filter read by the API's password policy.
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/melihgurlek/backend-path/pkg/bloom"
+)
+
+func main() {
+	in := flag.String("in", "-", "input list of SHA-1 hashes, - for stdin")
+	out := flag.String("out", "breached.bloom", "output filter file")
+	n := flag.Uint64("n", 1_000_000, "expected number of hashes")
+	p := flag.Float64("p", 0.001, "target false positive rate")
+	flag.Parse()
+
+	if err := run(*in, *out, *n, *p); err != nil {
+		fmt.Fprintln(os.Stderr, "breachfilter:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out string, n uint64, p float64) error {
+	var r io.Reader = os.Stdin
+	if in != "-" {
+		f, err := os.Open(in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	filter := bloom.NewWithEstimates(n, p)
+	scanner := bufio.NewScanner(r)
+	added := 0
+	for line := 1; scanner.Scan(); line++ {
+		hash, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if hash == "" {
+			continue
+		}
+		digest, err := hex.DecodeString(hash)
+		if err != nil || len(digest) != 20 {
+			return fmt.Errorf("line %d: %q is not a SHA-1 hex digest", line, hash)
+		}
+		filter.Add(digest)
+		added++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if _, err := filter.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %d hashes to %s\n", added, out)
+	return nil
+}
@@ -0,0 +1,322 @@
+// Command clientgen generates the request and response types of the Go client in pkg/client.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sourcePackages maps the packages types are copied from to their directories.
+var sourcePackages = map[string]string{
+	"handler":    "internal/handler",
+	"domain":     "internal/domain",
+	"middleware": "internal/middleware",
+}
+
+// rootTypes are the types the client uses directly, in generation order.
+var rootTypes = []string{
+	// Auth and users
+	"handler.RegisterRequest",
+	"handler.LoginRequest",
+	"handler.VerifyLoginRequest",
+	"domain.Device",
+	"handler.UpdateRequest",
+	"handler.PatchUserRequest",
+	"handler.ChangePasswordRequest",
+
+	// Transactions and balances
+	"domain.Transaction",
+	"domain.Balance",
+	"handler.BalanceV2Response",
+	"handler.AvailableBalanceV2Response",
+
+	// Scheduled transactions
+	"domain.ScheduledTransaction",
+	"handler.CreateScheduledTransactionRequest",
+	"handler.PreviewScheduledTransactionRequest",
+	"handler.UpdateScheduledTransactionRequest",
+	"domain.ScheduledTransactionPreview",
+	"domain.ScheduledTransactionStats",
+
+	// Worker
+	"handler.SubmitTaskRequest",
+	"handler.SubmitTaskResponse",
+	"handler.TaskStatusResponse",
+	"handler.SubmitBatchRequest",
+	"handler.SubmitBatchResponse",
+	"handler.BatchStatusResponse",
+	"handler.GetStatsResponse",
+	"handler.GetHealthResponse",
+	"domain.DeadLetter",
+
+	// Errors
+	"middleware.Problem",
+}
+
+func main() {
+	root := flag.String("root", ".", "repository root")
+	out := flag.String("out", "pkg/client/types_gen.go", "output file")
+	flag.Parse()
+
+	src, err := Generate(*root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "clientgen:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "clientgen:", err)
+		os.Exit(1)
+	}
+}
+
+// typeDecl is a type declaration found in a source package
+type typeDecl struct {
+	pkg  string
+	spec *ast.TypeSpec
+	doc  *ast.CommentGroup
+	file *ast.File
+}
+
+// Generate returns the formatted source of the client types
+func Generate(root string) ([]byte, error) {
+	fset := token.NewFileSet()
+	decls := make(map[string]*typeDecl)
+	for pkg, dir := range sourcePackages {
+		if err := parseTypes(fset, pkg, filepath.Join(root, dir), decls); err != nil {
+			return nil, err
+		}
+	}
+
+	g := &generator{decls: decls, seen: make(map[string]string), imports: make(map[string]bool)}
+	queue := append([]string(nil), rootTypes...)
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		refs, err := g.emit(key)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, refs...)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by clientgen from the API handlers; DO NOT EDIT.\n\n")
+	buf.WriteString("package client\n\n")
+	if len(g.imports) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			paths = append(paths, strconv.Quote(path))
+		}
+		sort.Strings(paths)
+		fmt.Fprintf(&buf, "import (\n%s\n)\n\n", strings.Join(paths, "\n"))
+	}
+	buf.Write(g.body.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+// parseTypes records the type declarations of the non-test files in dir
+func parseTypes(fset *token.FileSet, pkg, dir string, decls map[string]*typeDecl) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		for _, d := range file.Decls {
+			gen, ok := d.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range gen.Specs {
+				spec := s.(*ast.TypeSpec)
+				doc := spec.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				decls[pkg+"."+spec.Name.Name] = &typeDecl{pkg: pkg, spec: spec, doc: doc, file: file}
+			}
+		}
+	}
+	return nil
+}
+
+// generator writes copies of the declarations it is asked for
+type generator struct {
+	decls   map[string]*typeDecl
+	seen    map[string]string // generated type name to the declaration it came from
+	imports map[string]bool
+	body    bytes.Buffer
+}
+
+// emit writes the declaration of key and returns the source types it refers to.
+func (g *generator) emit(key string) ([]string, error) {
+	decl, ok := g.decls[key]
+	if !ok {
+		return nil, fmt.Errorf("type %s not found", key)
+	}
+	name := decl.spec.Name.Name
+	if from, ok := g.seen[name]; ok {
+		if from == key {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("types %s and %s would both be generated as %s", from, key, name)
+	}
+	g.seen[name] = key
+
+	var refs []string
+	typ := g.rewrite(decl, decl.spec.Type, &refs)
+
+	if decl.doc != nil {
+		for _, c := range decl.doc.List {
+			g.body.WriteString(c.Text + "\n")
+		}
+	}
+	fmt.Fprintf(&g.body, "type %s ", name)
+	if st, ok := typ.(*ast.StructType); ok {
+		if err := g.writeStruct(st); err != nil {
+			return nil, err
+		}
+	} else if err := printer.Fprint(&g.body, token.NewFileSet(), typ); err != nil {
+		return nil, err
+	}
+	g.body.WriteString("\n\n")
+	return refs, nil
+}
+
+// rewrite returns expr with source type references unqualified, collecting them.
+func (g *generator) rewrite(decl *typeDecl, expr ast.Expr, refs *[]string) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if _, ok := g.decls[decl.pkg+"."+e.Name]; ok && ast.IsExported(e.Name) {
+			*refs = append(*refs, decl.pkg+"."+e.Name)
+		}
+		return ast.NewIdent(e.Name)
+	case *ast.SelectorExpr:
+		pkgIdent := e.X.(*ast.Ident)
+		if _, ok := sourcePackages[pkgIdent.Name]; ok {
+			*refs = append(*refs, pkgIdent.Name+"."+e.Sel.Name)
+			return ast.NewIdent(e.Sel.Name)
+		}
+		g.imports[importPath(decl.file, pkgIdent.Name)] = true
+		return &ast.SelectorExpr{X: ast.NewIdent(pkgIdent.Name), Sel: ast.NewIdent(e.Sel.Name)}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: g.rewrite(decl, e.X, refs)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: g.rewrite(decl, e.Elt, refs)}
+	case *ast.MapType:
+		return &ast.MapType{Key: g.rewrite(decl, e.Key, refs), Value: g.rewrite(decl, e.Value, refs)}
+	case *ast.InterfaceType:
+		return &ast.InterfaceType{Methods: &ast.FieldList{}}
+	case *ast.StructType:
+		fields := &ast.FieldList{}
+		for _, f := range e.Fields.List {
+			names, tag, ok := encoded(f)
+			if !ok {
+				continue
+			}
+			fields.List = append(fields.List, &ast.Field{
+				Doc:     f.Doc,
+				Names:   names,
+				Type:    g.rewrite(decl, f.Type, refs),
+				Tag:     tag,
+				Comment: f.Comment,
+			})
+		}
+		return &ast.StructType{Fields: fields}
+	default:
+		panic(fmt.Sprintf("clientgen: unsupported type expression %T in %s", expr, decl.spec.Name.Name))
+	}
+}
+
+// encoded returns the names and json tag f is encoded with, or false if it never is.
+func encoded(f *ast.Field) ([]*ast.Ident, *ast.BasicLit, bool) {
+	var names []*ast.Ident
+	for _, n := range f.Names {
+		if n.IsExported() {
+			names = append(names, ast.NewIdent(n.Name))
+		}
+	}
+	if len(f.Names) > 0 && len(names) == 0 {
+		return nil, nil, false
+	}
+	if f.Tag == nil {
+		return names, nil, true
+	}
+	tag, _ := strconv.Unquote(f.Tag.Value)
+	jsonTag := reflect.StructTag(tag).Get("json")
+	switch jsonTag {
+	case "-":
+		return nil, nil, false
+	case "":
+		return names, nil, true
+	}
+	return names, &ast.BasicLit{Kind: token.STRING, Value: "`json:" + strconv.Quote(jsonTag) + "`"}, true
+}
+
+// writeStruct writes st along with the comments of its fields.
+func (g *generator) writeStruct(st *ast.StructType) error {
+	g.body.WriteString("struct {\n")
+	for _, f := range st.Fields.List {
+		if f.Doc != nil {
+			for _, c := range f.Doc.List {
+				g.body.WriteString(c.Text + "\n")
+			}
+		}
+		for i, n := range f.Names {
+			if i > 0 {
+				g.body.WriteString(", ")
+			}
+			g.body.WriteString(n.Name)
+		}
+		if len(f.Names) > 0 {
+			g.body.WriteString(" ")
+		}
+		if err := printer.Fprint(&g.body, token.NewFileSet(), f.Type); err != nil {
+			return err
+		}
+		if f.Tag != nil {
+			g.body.WriteString(" " + f.Tag.Value)
+		}
+		if f.Comment != nil {
+			for _, c := range f.Comment.List {
+				g.body.WriteString(" " + c.Text)
+			}
+		}
+		g.body.WriteString("\n")
+	}
+	g.body.WriteString("}")
+	return nil
+}
+
+// importPath resolves the package name used in file to its import path
+func importPath(file *ast.File, name string) string {
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if imp.Name != nil && imp.Name.Name == name {
+			return path
+		}
+		if imp.Name == nil && (path == name || strings.HasSuffix(path, "/"+name)) {
+			return path
+		}
+	}
+	return name
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGeneratedTypesUpToDate fails when a handler or domain type the client
+// copies has changed without regenerating pkg/client
+func TestGeneratedTypesUpToDate(t *testing.T) {
+	want, err := Generate("../..")
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../../pkg/client/types_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "pkg/client/types_gen.go is stale; run go generate ./pkg/client")
+}
@@ -0,0 +1,239 @@
+// Package bruteforce protects the auth endpoints against password guessing and credential stuffing.
+package bruteforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// banIndexKey is the sorted set of bans in force, scored by expiry
+const banIndexKey = "bruteforce:bans"
+
+// Policy decides how failed attempts are answered.
+type Policy struct {
+	Window           time.Duration
+	TarpitAfter      int
+	TarpitDelay      time.Duration
+	MaxTarpitDelay   time.Duration
+	IPBanAfter       int
+	UsernameBanAfter int
+	BanDuration      time.Duration
+}
+
+// Delay returns how long to hold back an attempt after failures failed ones
+func (p Policy) Delay(failures int) time.Duration {
+	if p.TarpitAfter <= 0 || failures < p.TarpitAfter {
+		return 0
+	}
+	delay := p.TarpitDelay * time.Duration(failures-p.TarpitAfter+1)
+	if delay > p.MaxTarpitDelay {
+		return p.MaxTarpitDelay
+	}
+	return delay
+}
+
+// banAfter returns the ban threshold of a kind of key
+func (p Policy) banAfter(kind string) int {
+	if kind == domain.AuthBanKindIP {
+		return p.IPBanAfter
+	}
+	return p.UsernameBanAfter
+}
+
+// Guard counts failed attempts and bans in Redis.
+type Guard struct {
+	client *redis.Client
+	audit  domain.AuditLogService
+	policy Policy
+}
+
+// NewGuard creates a guard enforcing policy with the counters on client.
+func NewGuard(client *redis.Client, audit domain.AuditLogService, policy Policy) *Guard {
+	return &Guard{client: client, audit: audit, policy: policy}
+}
+
+// counter is a key failures are counted under
+type counter struct {
+	kind  string
+	value string
+}
+
+// counters returns the keys an attempt by ip for username is counted under.
+func counters(ip, username string) []counter {
+	keys := []counter{{domain.AuthBanKindIP, ip}}
+	if username = normalizeUsername(username); username != "" {
+		keys = append(keys, counter{domain.AuthBanKindUsername, username})
+	}
+	return keys
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func (c counter) failuresKey() string { return "bruteforce:failures:" + c.kind + ":" + c.value }
+func (c counter) banKey() string      { return "bruteforce:ban:" + c.kind + ":" + c.value }
+func (c counter) member() string      { return c.kind + ":" + c.value }
+
+// Check returns how long an attempt by ip for username must wait or stay banned.
+func (g *Guard) Check(ctx context.Context, ip, username string) (delay, retryAfter time.Duration, err error) {
+	keys := counters(ip, username)
+	since := strconv.FormatInt(time.Now().Add(-g.policy.Window).UnixMilli(), 10)
+
+	pipe := g.client.Pipeline()
+	bans := make([]*redis.DurationCmd, len(keys))
+	counts := make([]*redis.IntCmd, len(keys))
+	for i, c := range keys {
+		bans[i] = pipe.PTTL(ctx, c.banKey())
+		counts[i] = pipe.ZCount(ctx, c.failuresKey(), "("+since, "+inf")
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, fmt.Errorf("failed to check failed attempts: %w", err)
+	}
+
+	failures := 0
+	for i := range keys {
+		// A missing ban has a negative TTL
+		if ttl := bans[i].Val(); ttl > retryAfter {
+			retryAfter = ttl
+		}
+		if n := int(counts[i].Val()); n > failures {
+			failures = n
+		}
+	}
+	if retryAfter > 0 {
+		return 0, retryAfter, nil
+	}
+	return g.policy.Delay(failures), 0, nil
+}
+
+// RecordFailure counts a failed attempt by ip for username, banning at the threshold.
+func (g *Guard) RecordFailure(ctx context.Context, ip, username string) error {
+	now := time.Now()
+	for _, c := range counters(ip, username) {
+		pipe := g.client.TxPipeline()
+		pipe.ZRemRangeByScore(ctx, c.failuresKey(), "-inf", strconv.FormatInt(now.Add(-g.policy.Window).UnixMilli(), 10))
+		pipe.ZAdd(ctx, c.failuresKey(), redis.Z{Score: float64(now.UnixMilli()), Member: uuid.NewString()})
+		count := pipe.ZCard(ctx, c.failuresKey())
+		pipe.PExpire(ctx, c.failuresKey(), g.policy.Window)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to count failed attempt: %w", err)
+		}
+
+		failures := int(count.Val())
+		if threshold := g.policy.banAfter(c.kind); threshold > 0 && failures >= threshold {
+			if err := g.ban(ctx, c, failures, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ban bans a key unless it is banned already; a ban isn't extended by the attempts it refuses
+func (g *Guard) ban(ctx context.Context, c counter, failures int, now time.Time) error {
+	ban := &domain.AuthBan{
+		Kind:      c.kind,
+		Value:     c.value,
+		Failures:  failures,
+		BannedAt:  now.UTC(),
+		ExpiresAt: now.Add(g.policy.BanDuration).UTC(),
+	}
+	data, err := json.Marshal(ban)
+	if err != nil {
+		return err
+	}
+	banned, err := g.client.SetNX(ctx, c.banKey(), data, g.policy.BanDuration).Result()
+	if err != nil {
+		return fmt.Errorf("failed to ban %s: %w", c.kind, err)
+	}
+	if !banned {
+		return nil
+	}
+	if err := g.client.ZAdd(ctx, banIndexKey, redis.Z{Score: float64(ban.ExpiresAt.Unix()), Member: c.member()}).Err(); err != nil {
+		return fmt.Errorf("failed to list ban: %w", err)
+	}
+
+	metrics.BruteForceEventsTotal.WithLabelValues("ban_" + c.kind).Inc()
+	log.Ctx(ctx).Warn().Str("kind", c.kind).Str("value", c.value).Int("failures", failures).
+		Time("expires_at", ban.ExpiresAt).Msg("Banned from auth endpoints after failed attempts")
+	return nil
+}
+
+// ListBans returns the bans in force, soonest to expire first.
+func (g *Guard) ListBans(ctx context.Context) ([]*domain.AuthBan, error) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	if err := g.client.ZRemRangeByScore(ctx, banIndexKey, "-inf", now).Err(); err != nil {
+		return nil, fmt.Errorf("failed to prune bans: %w", err)
+	}
+	members, err := g.client.ZRange(ctx, banIndexKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bans: %w", err)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(members))
+	for i, m := range members {
+		kind, value, _ := strings.Cut(m, ":")
+		keys[i] = counter{kind, value}.banKey()
+	}
+	values, err := g.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bans: %w", err)
+	}
+	bans := make([]*domain.AuthBan, 0, len(values))
+	for _, v := range values {
+		// The ban may have expired since the index was pruned
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var ban domain.AuthBan
+		if err := json.Unmarshal([]byte(s), &ban); err != nil {
+			return nil, fmt.Errorf("failed to decode ban: %w", err)
+		}
+		bans = append(bans, &ban)
+	}
+	return bans, nil
+}
+
+// LiftBan ends a ban and forgets the failures that led to it.
+func (g *Guard) LiftBan(ctx context.Context, actorID int, kind, value string) error {
+	switch kind {
+	case domain.AuthBanKindIP:
+	case domain.AuthBanKindUsername:
+		value = normalizeUsername(value)
+	default:
+		return &domain.ValidationError{Msg: "kind must be ip or username"}
+	}
+	c := counter{kind, value}
+
+	pipe := g.client.TxPipeline()
+	deleted := pipe.Del(ctx, c.banKey())
+	pipe.Del(ctx, c.failuresKey())
+	pipe.ZRem(ctx, banIndexKey, c.member())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to lift ban: %w", err)
+	}
+	if deleted.Val() == 0 {
+		return domain.ErrAuthBanNotFound
+	}
+
+	if err := g.audit.Record(ctx, &actorID, "auth_ban", 0, "lift_auth_ban", "kind="+kind+" value="+value); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("kind", kind).Msg("Failed to audit lifted ban")
+	}
+	return nil
+}
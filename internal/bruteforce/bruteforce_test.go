@@ -0,0 +1,31 @@
+package bruteforce
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+func TestPolicy_Delay(t *testing.T) {
+	p := Policy{TarpitAfter: 3, TarpitDelay: time.Second, MaxTarpitDelay: 5 * time.Second}
+
+	assert.Zero(t, p.Delay(0))
+	assert.Zero(t, p.Delay(2))
+	assert.Equal(t, time.Second, p.Delay(3))
+	assert.Equal(t, 3*time.Second, p.Delay(5))
+	assert.Equal(t, 5*time.Second, p.Delay(50), "the delay is capped")
+
+	p.TarpitAfter = 0
+	assert.Zero(t, p.Delay(50), "a zero threshold turns tarpitting off")
+}
+
+func TestCounters(t *testing.T) {
+	assert.Equal(t, []counter{{domain.AuthBanKindIP, "203.0.113.7"}}, counters("203.0.113.7", " "))
+	assert.Equal(t, []counter{
+		{domain.AuthBanKindIP, "203.0.113.7"},
+		{domain.AuthBanKindUsername, "alice"},
+	}, counters("203.0.113.7", " Alice"), "usernames are counted case-insensitively")
+}
@@ -0,0 +1,81 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		assert.Equal(t, "203.0.113.7", r.PostForm.Get("remoteip"))
+		if r.PostForm.Get("response") == "good" {
+			w.Write([]byte(`{"success": true}`))
+			return
+		}
+		w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-response"]}`))
+	}))
+	defer srv.Close()
+
+	v, err := New(ProviderTurnstile, "secret")
+	require.NoError(t, err)
+	v.verifyURL = srv.URL
+
+	ok, err := v.Verify(context.Background(), "good", "203.0.113.7")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = v.Verify(context.Background(), "bad", "203.0.113.7")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestVerifier_ProviderError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	v, err := New(ProviderHCaptcha, "secret")
+	require.NoError(t, err)
+	v.verifyURL = srv.URL
+
+	_, err = v.Verify(context.Background(), "token", "")
+	assert.ErrorContains(t, err, "hcaptcha returned status 502")
+}
+
+func TestNew_UnknownProvider(t *testing.T) {
+	_, err := New("captchaz", "secret")
+	assert.Error(t, err)
+	_, err = New(ProviderReCaptcha, "")
+	assert.Error(t, err)
+}
+
+func TestMemoryFailures(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := NewMemoryFailures(time.Minute)
+	f.now = func() time.Time { return now }
+
+	require.NoError(t, f.AddFailure(ctx, "login:203.0.113.7"))
+	require.NoError(t, f.AddFailure(ctx, "login:203.0.113.7"))
+	n, _ := f.Failures(ctx, "login:203.0.113.7")
+	assert.Equal(t, 2, n)
+	n, _ = f.Failures(ctx, "register:203.0.113.7")
+	assert.Equal(t, 0, n)
+
+	now = now.Add(time.Minute)
+	n, _ = f.Failures(ctx, "login:203.0.113.7")
+	assert.Equal(t, 0, n, "failures expire after the window")
+
+	require.NoError(t, f.AddFailure(ctx, "login:203.0.113.7"))
+	n, _ = f.Failures(ctx, "login:203.0.113.7")
+	assert.Equal(t, 1, n, "a new window starts with the next failure")
+}
@@ -0,0 +1,100 @@
+package captcha
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisFailures counts failed attempts in Redis, so every instance sees the same counts.
+type RedisFailures struct {
+	client *redis.Client
+	window time.Duration
+}
+
+// NewRedisFailures creates a failure counter on client.
+func NewRedisFailures(client *redis.Client, window time.Duration) *RedisFailures {
+	return &RedisFailures{client: client, window: window}
+}
+
+// failureKey is the Redis key holding the failures counted under key
+func failureKey(key string) string {
+	return "captcha_failures:" + key
+}
+
+// Failures returns the failures counted under key.
+func (f *RedisFailures) Failures(ctx context.Context, key string) (int, error) {
+	n, err := f.client.Get(ctx, failureKey(key)).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
+
+// AddFailure counts a failure under key.
+func (f *RedisFailures) AddFailure(ctx context.Context, key string) error {
+	n, err := f.client.Incr(ctx, failureKey(key)).Result()
+	if err != nil {
+		return err
+	}
+	if n == 1 {
+		return f.client.Expire(ctx, failureKey(key), f.window).Err()
+	}
+	return nil
+}
+
+// MemoryFailures counts failed attempts in process memory, for running without Redis.
+type MemoryFailures struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	counts    map[string]*failureCount
+	nextSweep time.Time
+}
+
+// failureCount is the failures counted under a key until expiresAt
+type failureCount struct {
+	n         int
+	expiresAt time.Time
+}
+
+// NewMemoryFailures creates an in-process failure counter.
+func NewMemoryFailures(window time.Duration) *MemoryFailures {
+	return &MemoryFailures{window: window, now: time.Now, counts: make(map[string]*failureCount)}
+}
+
+// Failures returns the failures counted under key.
+func (f *MemoryFailures) Failures(_ context.Context, key string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.counts[key]
+	if !ok || !f.now().Before(c.expiresAt) {
+		return 0, nil
+	}
+	return c.n, nil
+}
+
+// AddFailure counts a failure under key.
+func (f *MemoryFailures) AddFailure(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.now()
+	if !now.Before(f.nextSweep) {
+		for k, c := range f.counts {
+			if !now.Before(c.expiresAt) {
+				delete(f.counts, k)
+			}
+		}
+		f.nextSweep = now.Add(f.window)
+	}
+	c, ok := f.counts[key]
+	if !ok || !now.Before(c.expiresAt) {
+		c = &failureCount{expiresAt: now.Add(f.window)}
+		f.counts[key] = c
+	}
+	c.n++
+	return nil
+}
@@ -1,43 +1,1201 @@
 package config
 
 import (
-	"log"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopkg.in/yaml.v3"
 )
 
 // Config holds application configuration.
 type Config struct {
-	Port      string
-	DBUrl     string
-	JWTSecret string
+	Server         ServerConfig         `yaml:"server"`
+	Internal       InternalConfig       `yaml:"internal"`
+	Database       DatabaseConfig       `yaml:"database"`
+	Redis          RedisConfig          `yaml:"redis"`
+	Tracing        TracingConfig        `yaml:"tracing"`
+	Worker         WorkerConfig         `yaml:"worker"`
+	Cache          CacheConfig          `yaml:"cache"`
+	Limits         LimitsConfig         `yaml:"limits"`
+	Fraud          FraudConfig          `yaml:"fraud"`
+	Referrals      ReferralConfig       `yaml:"referrals"`
+	KYC            KYCConfig            `yaml:"kyc"`
+	Documents      DocumentConfig       `yaml:"documents"`
+	Disputes       DisputeConfig        `yaml:"disputes"`
+	Chargebacks    ChargebackConfig     `yaml:"chargebacks"`
+	Merchants      MerchantConfig       `yaml:"merchants"`
+	Invoices       InvoiceConfig        `yaml:"invoices"`
+	Webhooks       WebhookConfig        `yaml:"webhooks"`
+	BankTransfers  BankTransferConfig   `yaml:"bank_transfers"`
+	Auth           AuthConfig           `yaml:"auth"`
+	Password       PasswordConfig       `yaml:"password"`
+	GeoIP          GeoIPConfig          `yaml:"geoip"`
+	Captcha        CaptchaConfig        `yaml:"captcha"`
+	BruteForce     BruteForceConfig     `yaml:"brute_force"`
+	Scheduled      ScheduledConfig      `yaml:"scheduled"`
+	Callback       CallbackConfig       `yaml:"callback"`
+	Notifications  NotificationConfig   `yaml:"notifications"`
+	Reconciliation ReconciliationConfig `yaml:"reconciliation"`
+	Partitions     PartitionsConfig     `yaml:"partitions"`
+	Archive        ArchiveConfig        `yaml:"archive"`
+	Analytics      AnalyticsConfig      `yaml:"analytics"`
+	Export         ExportConfig         `yaml:"export"`
+	ObjectStore    ObjectStoreConfig    `yaml:"object_store"`
+	Secrets        SecretsConfig        `yaml:"secrets"`
+	API            APIConfig            `yaml:"api"`
+	Startup        StartupConfig        `yaml:"startup"`
+}
+
+// ServerConfig configures the HTTP server.
+type ServerConfig struct {
+	Port            string        `yaml:"port"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// Longest shutdown waits for queued tasks and running background jobs,
+	// then for connections to close
+	ShutdownDrainTimeout time.Duration `yaml:"shutdown_drain_timeout"`
+	ShutdownCloseTimeout time.Duration `yaml:"shutdown_close_timeout"`
+	// On average 1 in MutexProfileFraction mutex contention events is
+	// recorded for the mutex profile; 0 turns mutex profiling off
+	MutexProfileFraction int `yaml:"mutex_profile_fraction"`
+	// Longest each dependency check of /healthz and /readyz may take
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout"`
+	TLS                TLSConfig     `yaml:"tls"`
+}
+
+// TLSConfig turns on HTTPS and HTTP/2 on the server port.
+type TLSConfig struct {
+	CertFile         string   `yaml:"cert_file"`
+	KeyFile          string   `yaml:"key_file"`
+	AutocertDomains  []string `yaml:"autocert_domains"`
+	AutocertCacheDir string   `yaml:"autocert_cache_dir"`
+	AutocertEmail    string   `yaml:"autocert_email"`
+	MinVersion       string   `yaml:"min_version"` // "1.2" or "1.3"
+	RedirectPort     string   `yaml:"redirect_port"`
+}
+
+// Enabled reports whether the server speaks TLS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != "" || len(c.AutocertDomains) > 0
 }
 
-// Load reads configuration from environment variables.
-func Load() *Config {
-	// Get JWT_SECRET or exit
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Fatal("FATAL: JWT_SECRET environment variable is not set.")
+// InternalConfig configures the mTLS listener serving the operational routes.
+type InternalConfig struct {
+	Port             string            `yaml:"port"`
+	CertFile         string            `yaml:"cert_file"`
+	KeyFile          string            `yaml:"key_file"`
+	ClientCAFile     string            `yaml:"client_ca_file"`
+	ClientIdentities map[string]string `yaml:"client_identities"`
+	Paths            []string          `yaml:"paths"`
+}
+
+// InternalIdentity is the user a client certificate acts as
+type InternalIdentity struct {
+	UserID int
+	Role   string
+}
+
+// Identities parses ClientIdentities
+func (c InternalConfig) Identities() (map[string]InternalIdentity, error) {
+	identities := make(map[string]InternalIdentity, len(c.ClientIdentities))
+	for name, v := range c.ClientIdentities {
+		userID, role, ok := strings.Cut(v, ":")
+		id, err := strconv.Atoi(userID)
+		if !ok || err != nil || id <= 0 || (role != "admin" && role != "user") {
+			return nil, fmt.Errorf("internal client identity %q must be userID:role with role admin or user, got %q", name, v)
+		}
+		identities[name] = InternalIdentity{UserID: id, Role: role}
 	}
+	return identities, nil
+}
+
+// DatabaseConfig configures the PostgreSQL connection pool.
+type DatabaseConfig struct {
+	URL               string        `yaml:"url"`
+	MaxConns          int           `yaml:"max_conns"`
+	MinConns          int           `yaml:"min_conns"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period"`
+	// Apply pending embedded migrations before serving
+	MigrateOnStart bool `yaml:"migrate_on_start"`
+}
+
+// RedisConfig configures the Redis client used for caching and token revocation.
+type RedisConfig struct {
+	URL string `yaml:"url"`
+}
+
+// TracingConfig configures the OpenTelemetry exporter and sampling.
+type TracingConfig struct {
+	Enabled        bool   `yaml:"enabled"`
+	Endpoint       string `yaml:"endpoint"`
+	ServiceName    string `yaml:"service_name"`
+	ServiceVersion string `yaml:"service_version"`
+	Environment    string `yaml:"environment"` // reported as deployment.environment
+
+	// Sampler is always, ratio (keep SampleRatio of new traces) or
+	// rate_limited (keep at most RateLimit new traces a second). Spans with a
+	// parent follow the parent's decision.
+	Sampler     string  `yaml:"sampler"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+	RateLimit   float64 `yaml:"rate_limit"`
+}
+
+// RetryConfig is a backoff policy for retried work.
+type RetryConfig struct {
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	Jitter         float64       `yaml:"jitter"`
+}
+
+// WorkerConfig configures the background transaction processor.
+type WorkerConfig struct {
+	PoolSize            int           `yaml:"pool_size"`
+	QueueSize           int           `yaml:"queue_size"`
+	BatchMaxConcurrency int           `yaml:"batch_max_concurrency"`
+	BatchTimeout        time.Duration `yaml:"batch_timeout"`
+	// Retry policy for transient task failures
+	Retry RetryConfig `yaml:"retry"`
+}
+
+// CacheConfig configures HTTP response caching.
+type CacheConfig struct {
+	ResponseTTL time.Duration `yaml:"response_ttl"`
+	// How long past ResponseTTL a response is still served while it is
+	// refreshed in the background; 0 disables stale-while-revalidate
+	StaleTTL time.Duration `yaml:"stale_ttl"`
+	// Entries kept in the in-process cache in front of Redis; 0 disables it
+	L1Size int `yaml:"l1_size"`
+	// Longest an entry is served from the in-process cache without Redis
+	L1TTL time.Duration `yaml:"l1_ttl"`
+	// RouteTTLs overrides ResponseTTL for paths under a prefix; the longest
+	// matching prefix wins and 0 means the route is never cached
+	RouteTTLs map[string]time.Duration `yaml:"route_ttls"`
+}
+
+// LimitsConfig holds transaction limits that apply across all users.
+type LimitsConfig struct {
+	// Transactions above this amount need a second admin's approval; 0 disables approvals
+	ApprovalThreshold float64 `yaml:"approval_threshold"`
+}
+
+// FraudConfig configures the fraud scoring of debits and transfers.
+type FraudConfig struct {
+	FlagScore   float64 `yaml:"flag_score"`
+	HoldScore   float64 `yaml:"hold_score"`
+	RejectScore float64 `yaml:"reject_score"`
+	// More than VelocityMaxCount debits and transfers within VelocityWindow add to the score
+	VelocityWindow   time.Duration `yaml:"velocity_window"`
+	VelocityMaxCount int           `yaml:"velocity_max_count"`
+	// An amount over AmountMultiplier times the user's average within
+	// HistoryWindow adds to the score, once they have MinHistory transactions
+	HistoryWindow    time.Duration `yaml:"history_window"`
+	MinHistory       int           `yaml:"min_history"`
+	AmountMultiplier float64       `yaml:"amount_multiplier"`
+	// UTC hours [OddHoursStart, OddHoursEnd) add to the score; equal hours disable it
+	OddHoursStart int `yaml:"odd_hours_start"`
+	OddHoursEnd   int `yaml:"odd_hours_end"`
+}
+
+// ReferralConfig configures the rewards of the referral program.
+type ReferralConfig struct {
+	ReferrerReward float64       `yaml:"referrer_reward"`
+	RefereeReward  float64       `yaml:"referee_reward"`
+	MinAmount      float64       `yaml:"min_amount"`
+	Window         time.Duration `yaml:"window"`
+}
+
+// KYCConfig configures identity verification.
+type KYCConfig struct {
+	Provider      string `yaml:"provider"`
+	APIURL        string `yaml:"api_url"`
+	APIKey        string `yaml:"api_key"`
+	WebhookSecret string `yaml:"webhook_secret"`
+}
+
+// DocumentConfig configures the documents users upload.
+type DocumentConfig struct {
+	MaxBytes      int           `yaml:"max_bytes"`
+	URLTTL        time.Duration `yaml:"url_ttl"`
+	Scanner       string        `yaml:"scanner"`
+	ClamAVAddress string        `yaml:"clamav_address"`
+}
+
+// DisputeConfig configures how long payments can be disputed.
+type DisputeConfig struct {
+	Window time.Duration `yaml:"window"`
+}
+
+// ChargebackConfig configures what chargebacks take from their users.
+type ChargebackConfig struct {
+	AllowNegativeBalance bool    `yaml:"allow_negative_balance"`
+	FreezeThreshold      float64 `yaml:"freeze_threshold"`
+}
+
+// MerchantConfig configures how often merchant payouts run.
+type MerchantConfig struct {
+	PayoutInterval time.Duration `yaml:"payout_interval"`
+}
+
+// InvoiceConfig configures the monthly invoices of fees.
+type InvoiceConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	Issuer   string        `yaml:"issuer"`
+	Currency string        `yaml:"currency"`
+}
+
+// WebhookConfig configures payment provider webhooks.
+type WebhookConfig struct {
+	Secrets   map[string]string `yaml:"secrets"`
+	Tolerance time.Duration     `yaml:"tolerance"`
+	Currency  string            `yaml:"currency"`
+}
+
+// BankTransferConfig configures ACH and SEPA settlement times.
+type BankTransferConfig struct {
+	ACHSettlementDays  int               `yaml:"ach_settlement_days"`
+	SEPASettlementDays int               `yaml:"sepa_settlement_days"`
+	SettleInterval     time.Duration     `yaml:"settle_interval"`
+	TestReturns        map[string]string `yaml:"test_returns"`
+}
+
+// AuthConfig holds signing and encryption secrets.
+type AuthConfig struct {
+	JWTSecret string `yaml:"jwt_secret"`
+	// HMAC key for payment link tokens; defaults to a key derived from the JWT
+	// secret
+	PaymentLinkSecret string `yaml:"payment_link_secret"`
+	// HMAC key for document download URLs; defaults to a key derived from the
+	// JWT secret
+	DocumentURLSecret string `yaml:"document_url_secret"`
+	// HMAC key for receive-money QR code tokens; defaults to a key derived
+	// from the JWT secret
+	ReceiveQRSecret string `yaml:"receive_qr_secret"`
+	// Field-level encryption of PII: comma-separated "id:base64key" entries with
+	// the primary key first, plus the base64 blind index key. Both default to keys
+	// derived from the JWT secret.
+	FieldEncryptionKeys     string `yaml:"field_encryption_keys"`
+	FieldEncryptionIndexKey string `yaml:"field_encryption_index_key"`
+}
+
+// PasswordConfig holds the password policy and hashing parameters.
+type PasswordConfig struct {
+	// An empty breach filter path disables the breached-password check
+	MinLength       int    `yaml:"min_length"`
+	RequiredClasses int    `yaml:"required_classes"`
+	BreachFilter    string `yaml:"breach_filter"`
 
-	// Get DB_URL or exit
-	dbURL := os.Getenv("DB_URL")
-	if dbURL == "" {
-		log.Fatal("FATAL: DB_URL environment variable is not set.")
+	// "argon2id" or "bcrypt". Hashes made by the other algorithm still verify and
+	// are upgraded on the next successful login.
+	HashAlgorithm     string `yaml:"hash_algorithm"`
+	Argon2Memory      int    `yaml:"argon2_memory_kib"`
+	Argon2Iterations  int    `yaml:"argon2_iterations"`
+	Argon2Parallelism int    `yaml:"argon2_parallelism"`
+	BcryptCost        int    `yaml:"bcrypt_cost"`
+}
+
+// GeoIPConfig configures login geolocation and impossible travel detection.
+type GeoIPConfig struct {
+	// DB-IP city lite CSV, optionally gzipped; empty disables geolocation
+	DBPath string `yaml:"db_path"`
+	// A login is impossible travel if reaching it from the previous one
+	// needs more than MaxTravelSpeedKmh (0 disables the check). Logins less
+	// than MinTravelDistanceKm apart never are, as GeoIP is only approximate.
+	MaxTravelSpeedKmh   float64 `yaml:"max_travel_speed_kmh"`
+	MinTravelDistanceKm float64 `yaml:"min_travel_distance_km"`
+}
+
+// CaptchaConfig configures the CAPTCHA asked after repeated auth failures.
+type CaptchaConfig struct {
+	// "hcaptcha", "recaptcha" or "turnstile"; empty disables CAPTCHAs
+	Provider string `yaml:"provider"`
+	Secret   string `yaml:"secret"`
+	// Failed attempts within FailureWindow after which a CAPTCHA is required
+	FailureThreshold int           `yaml:"failure_threshold"`
+	FailureWindow    time.Duration `yaml:"failure_window"`
+}
+
+// BruteForceConfig configures how failed logins are slowed down and banned.
+type BruteForceConfig struct {
+	Window           time.Duration `yaml:"window"`
+	TarpitAfter      int           `yaml:"tarpit_after"`
+	TarpitDelay      time.Duration `yaml:"tarpit_delay"`
+	MaxTarpitDelay   time.Duration `yaml:"max_tarpit_delay"`
+	IPBanAfter       int           `yaml:"ip_ban_after"`
+	UsernameBanAfter int           `yaml:"username_ban_after"`
+	BanDuration      time.Duration `yaml:"ban_duration"`
+}
+
+// ScheduledConfig configures scheduled transaction execution.
+type ScheduledConfig struct {
+	// Retry policy for failed executions
+	Retry RetryConfig `yaml:"retry"`
+}
+
+// CallbackConfig configures worker task completion callbacks.
+type CallbackConfig struct {
+	SigningSecret  string        `yaml:"signing_secret"`
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+}
+
+// NotificationConfig configures user notifications.
+type NotificationConfig struct {
+	Workers   int `yaml:"workers"`
+	QueueSize int `yaml:"queue_size"`
+	// Directory of <event>.<channel>.tmpl files replacing the built-in templates
+	TemplateDir string `yaml:"template_dir"`
+	// Tell admins, not only the owner, about scheduled transactions that fail for good
+	NotifyAdminsOnScheduledFailure bool `yaml:"notify_admins_on_scheduled_failure"`
+
+	SMTPHost     string `yaml:"smtp_host"`
+	SMTPPort     int    `yaml:"smtp_port"`
+	SMTPUsername string `yaml:"smtp_username"`
+	SMTPPassword string `yaml:"smtp_password"`
+	SMTPFrom     string `yaml:"smtp_from"`
+
+	TwilioAccountSID string `yaml:"twilio_account_sid"`
+	TwilioAuthToken  string `yaml:"twilio_auth_token"`
+	TwilioFrom       string `yaml:"twilio_from"`
+
+	FCMCredentialsFile string `yaml:"fcm_credentials_file"`
+}
+
+// ReconciliationConfig configures the nightly balance reconciliation.
+type ReconciliationConfig struct {
+	// Hour of the day (UTC) at which the run starts
+	Hour int `yaml:"hour"`
+}
+
+// PartitionsConfig configures maintenance of the monthly transactions partitions.
+type PartitionsConfig struct {
+	// Future months that always have a partition ready
+	MonthsAhead int `yaml:"months_ahead"`
+	// Months of partitions kept attached before they are detached; 0 keeps all
+	RetentionMonths int           `yaml:"retention_months"`
+	CheckInterval   time.Duration `yaml:"check_interval"`
+}
+
+// ArchiveConfig configures moving old transactions to the archive table.
+type ArchiveConfig struct {
+	// Age after which final transactions are archived; 0 disables archiving
+	MaxAge        time.Duration `yaml:"max_age"`
+	BatchSize     int           `yaml:"batch_size"`
+	CheckInterval time.Duration `yaml:"check_interval"`
+}
+
+// AnalyticsConfig configures the cohort retention and growth aggregation job.
+type AnalyticsConfig struct {
+	// Months of cohorts recomputed on each run, the current one included
+	CohortMonths   int           `yaml:"cohort_months"`
+	CohortInterval time.Duration `yaml:"cohort_interval"`
+}
+
+// ExportConfig describes the account in transaction history exports.
+type ExportConfig struct {
+	// ISO 4217 code of the currency balances are kept in
+	Currency string `yaml:"currency"`
+	// Institution name and bank ID written to OFX statements
+	Institution string `yaml:"institution"`
+	BankID      string `yaml:"bank_id"`
+}
+
+// ObjectStoreConfig selects where uploaded files are kept.
+type ObjectStoreConfig struct {
+	Backend  string `yaml:"backend"`
+	LocalDir string `yaml:"local_dir"`
+
+	S3Bucket string `yaml:"s3_bucket"`
+	S3Region string `yaml:"s3_region"`
+	// Endpoint of an S3-compatible service such as MinIO; empty means AWS
+	S3Endpoint string `yaml:"s3_endpoint"`
+}
+
+// SecretsConfig selects where the JWT secret and database URL come from.
+type SecretsConfig struct {
+	Backend string `yaml:"backend"`
+	// How long a fetched JWT secret is used before it is re-read
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+	JWTSecretRef    string        `yaml:"jwt_secret_ref"`
+	DBURLRef        string        `yaml:"db_url_ref"`
+
+	VaultAddress string `yaml:"vault_address"`
+	VaultToken   string `yaml:"vault_token"`
+	VaultMount   string `yaml:"vault_mount"`
+	AWSRegion    string `yaml:"aws_region"`
+}
+
+// ManagedJWTSecret reports whether the JWT secret comes from a secrets manager.
+func (s SecretsConfig) ManagedJWTSecret() bool {
+	return s.Backend != "env" && s.JWTSecretRef != ""
+}
+
+// ManagedDBURL reports whether the database URL comes from a secrets manager.
+func (s SecretsConfig) ManagedDBURL() bool {
+	return s.Backend != "env" && s.DBURLRef != ""
+}
+
+// APIConfig configures API versioning.
+type APIConfig struct {
+	// When v1 was deprecated, sent in its Deprecation header; empty sends "true"
+	V1DeprecatedAt string `yaml:"v1_deprecated_at"`
+	// When v1 will be removed, sent in its Sunset header; empty omits the header
+	V1Sunset string `yaml:"v1_sunset"`
+}
+
+// StartupConfig configures how long startup waits for its dependencies.
+type StartupConfig struct {
+	WaitTimeout    time.Duration `yaml:"wait_timeout"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+}
+
+// apiDateLayout is the layout of APIConfig dates
+const apiDateLayout = "2006-01-02"
+
+// V1Dates returns the parsed v1 deprecation and sunset dates; unset ones are zero.
+func (a APIConfig) V1Dates() (deprecatedAt, sunset time.Time) {
+	deprecatedAt, _ = parseAPIDate(a.V1DeprecatedAt)
+	sunset, _ = parseAPIDate(a.V1Sunset)
+	return deprecatedAt, sunset
+}
+
+// currencyCode matches an ISO 4217 currency code such as "USD"
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func parseAPIDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
 	}
+	return time.Parse(apiDateLayout, s)
+}
+
+// Default returns the configuration used when nothing is overridden.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            "8080",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 5 * time.Second,
 
-	cfg := &Config{
-		Port:      getEnv("PORT", "8080"), // A default port is fine
-		DBUrl:     dbURL,
-		JWTSecret: jwtSecret,
+			ShutdownDrainTimeout: 30 * time.Second,
+			ShutdownCloseTimeout: 5 * time.Second,
+			MutexProfileFraction: 100,
+			HealthCheckTimeout:   2 * time.Second,
+			TLS: TLSConfig{
+				AutocertCacheDir: "certs",
+				MinVersion:       "1.2",
+			},
+		},
+		Internal: InternalConfig{
+			Paths: []string{"/admin", "/worker", "/metrics"},
+		},
+		Database: DatabaseConfig{
+			MaxConns:          20,
+			MinConns:          5,
+			MaxConnLifetime:   time.Hour,
+			MaxConnIdleTime:   30 * time.Minute,
+			HealthCheckPeriod: time.Minute,
+		},
+		Redis: RedisConfig{URL: "redis://redis:6379"},
+		Tracing: TracingConfig{
+			Enabled:        true,
+			Endpoint:       "jaeger:4318",
+			ServiceName:    "backend-path-api",
+			ServiceVersion: "1.0.0",
+			Environment:    "development",
+			Sampler:        "always",
+			SampleRatio:    1,
+			RateLimit:      100,
+		},
+		Worker: WorkerConfig{
+			PoolSize:            5,
+			QueueSize:           100,
+			BatchMaxConcurrency: 5,
+			BatchTimeout:        30 * time.Second,
+			Retry: RetryConfig{
+				MaxAttempts:    3,
+				InitialBackoff: 500 * time.Millisecond,
+				MaxBackoff:     30 * time.Second,
+				Jitter:         0.2,
+			},
+		},
+		Cache: CacheConfig{
+			ResponseTTL: 5 * time.Minute,
+			L1Size:      10000,
+			L1TTL:       10 * time.Second,
+			RouteTTLs: map[string]time.Duration{
+				"/api/v1/balances": 10 * time.Second,
+				"/api/v1/users":    5 * time.Minute,
+				"/api/v1/metrics":  0,
+				"/api/v2/balances": 10 * time.Second,
+				"/api/v2/users":    5 * time.Minute,
+				"/api/v2/metrics":  0,
+			},
+		},
+		Limits: LimitsConfig{ApprovalThreshold: 10000},
+		Fraud: FraudConfig{
+			FlagScore:        30,
+			HoldScore:        55,
+			RejectScore:      80,
+			VelocityWindow:   10 * time.Minute,
+			VelocityMaxCount: 5,
+			HistoryWindow:    90 * 24 * time.Hour,
+			MinHistory:       5,
+			AmountMultiplier: 5,
+			OddHoursStart:    1,
+			OddHoursEnd:      5,
+		},
+		Referrals: ReferralConfig{
+			ReferrerReward: 10,
+			MinAmount:      20,
+			Window:         30 * 24 * time.Hour,
+		},
+		Password: PasswordConfig{
+			MinLength:         10,
+			RequiredClasses:   3,
+			HashAlgorithm:     "argon2id",
+			Argon2Memory:      64 * 1024,
+			Argon2Iterations:  3,
+			Argon2Parallelism: 2,
+			BcryptCost:        10,
+		},
+		GeoIP: GeoIPConfig{
+			MaxTravelSpeedKmh:   1000,
+			MinTravelDistanceKm: 300,
+		},
+		Captcha: CaptchaConfig{
+			FailureThreshold: 3,
+			FailureWindow:    15 * time.Minute,
+		},
+		BruteForce: BruteForceConfig{
+			Window:           15 * time.Minute,
+			TarpitAfter:      5,
+			TarpitDelay:      time.Second,
+			MaxTarpitDelay:   10 * time.Second,
+			IPBanAfter:       50,
+			UsernameBanAfter: 20,
+			BanDuration:      time.Hour,
+		},
+		Scheduled: ScheduledConfig{
+			Retry: RetryConfig{
+				MaxAttempts:    3,
+				InitialBackoff: time.Minute,
+				MaxBackoff:     time.Hour,
+				Jitter:         0.2,
+			},
+		},
+		Callback: CallbackConfig{
+			MaxAttempts:    5,
+			InitialBackoff: 2 * time.Second,
+		},
+		Notifications: NotificationConfig{
+			Workers:   4,
+			QueueSize: 1000,
+			SMTPPort:  587,
+		},
+		Reconciliation: ReconciliationConfig{Hour: 2},
+		Partitions: PartitionsConfig{
+			MonthsAhead:   3,
+			CheckInterval: 24 * time.Hour,
+		},
+		Archive: ArchiveConfig{
+			BatchSize:     1000,
+			CheckInterval: 24 * time.Hour,
+		},
+		Analytics: AnalyticsConfig{
+			CohortMonths:   3,
+			CohortInterval: time.Hour,
+		},
+		Export: ExportConfig{
+			Currency:    "USD",
+			Institution: "Backend Path",
+		},
+		Documents: DocumentConfig{
+			MaxBytes:      10 << 20,
+			URLTTL:        15 * time.Minute,
+			ClamAVAddress: "localhost:3310",
+		},
+		Disputes: DisputeConfig{
+			Window: 120 * 24 * time.Hour,
+		},
+		Chargebacks: ChargebackConfig{
+			FreezeThreshold: 500,
+		},
+		Merchants: MerchantConfig{
+			PayoutInterval: time.Hour,
+		},
+		Invoices: InvoiceConfig{
+			Interval: time.Hour,
+			Issuer:   "Backend Path",
+			Currency: "USD",
+		},
+		Webhooks: WebhookConfig{
+			Tolerance: 5 * time.Minute,
+			Currency:  "USD",
+		},
+		BankTransfers: BankTransferConfig{
+			ACHSettlementDays:  3,
+			SEPASettlementDays: 1,
+			SettleInterval:     15 * time.Minute,
+		},
+		ObjectStore: ObjectStoreConfig{
+			Backend:  "local",
+			LocalDir: "data/objects",
+		},
+		Secrets: SecretsConfig{
+			Backend:         "env",
+			RefreshInterval: 5 * time.Minute,
+			VaultMount:      "secret",
+		},
+		Startup: StartupConfig{
+			WaitTimeout:    time.Minute,
+			InitialBackoff: 500 * time.Millisecond,
+			MaxBackoff:     5 * time.Second,
+		},
 	}
-	return cfg
 }
 
-// getEnv returns an env value or a default. Only use for non-sensitive data.
-func getEnv(key, defaultVal string) string {
-	if val := os.Getenv(key); val != "" {
-		return val
+// Load builds the configuration from defaults, CONFIG_FILE and the environment.
+func Load() (*Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
 	}
-	return defaultVal
+	cfg.applyDerivedDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadFile overlays the YAML file at path onto cfg.
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadEnv overlays environment variables onto cfg.
+func (c *Config) loadEnv() error {
+	env := &envReader{}
+
+	env.str("PORT", &c.Server.Port)
+	env.duration("SERVER_READ_TIMEOUT", &c.Server.ReadTimeout)
+	env.duration("SERVER_WRITE_TIMEOUT", &c.Server.WriteTimeout)
+	env.duration("SERVER_IDLE_TIMEOUT", &c.Server.IdleTimeout)
+	env.duration("SERVER_SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+	env.duration("SERVER_SHUTDOWN_DRAIN_TIMEOUT", &c.Server.ShutdownDrainTimeout)
+	env.duration("SERVER_SHUTDOWN_CLOSE_TIMEOUT", &c.Server.ShutdownCloseTimeout)
+	env.int("SERVER_MUTEX_PROFILE_FRACTION", &c.Server.MutexProfileFraction)
+	env.duration("SERVER_HEALTH_CHECK_TIMEOUT", &c.Server.HealthCheckTimeout)
+	env.str("TLS_CERT_FILE", &c.Server.TLS.CertFile)
+	env.str("TLS_KEY_FILE", &c.Server.TLS.KeyFile)
+	env.list("TLS_AUTOCERT_DOMAINS", &c.Server.TLS.AutocertDomains)
+	env.str("TLS_AUTOCERT_CACHE_DIR", &c.Server.TLS.AutocertCacheDir)
+	env.str("TLS_AUTOCERT_EMAIL", &c.Server.TLS.AutocertEmail)
+	env.str("TLS_MIN_VERSION", &c.Server.TLS.MinVersion)
+	env.str("TLS_REDIRECT_PORT", &c.Server.TLS.RedirectPort)
+
+	env.str("INTERNAL_PORT", &c.Internal.Port)
+	env.str("INTERNAL_TLS_CERT_FILE", &c.Internal.CertFile)
+	env.str("INTERNAL_TLS_KEY_FILE", &c.Internal.KeyFile)
+	env.str("INTERNAL_CLIENT_CA_FILE", &c.Internal.ClientCAFile)
+	env.stringMap("INTERNAL_CLIENT_IDENTITIES", &c.Internal.ClientIdentities)
+	env.list("INTERNAL_PATHS", &c.Internal.Paths)
+
+	env.str("DB_URL", &c.Database.URL)
+	env.int("DB_MAX_CONNS", &c.Database.MaxConns)
+	env.int("DB_MIN_CONNS", &c.Database.MinConns)
+	env.duration("DB_MAX_CONN_LIFETIME", &c.Database.MaxConnLifetime)
+	env.duration("DB_MAX_CONN_IDLE_TIME", &c.Database.MaxConnIdleTime)
+	env.duration("DB_HEALTH_CHECK_PERIOD", &c.Database.HealthCheckPeriod)
+	env.bool("DB_MIGRATE_ON_START", &c.Database.MigrateOnStart)
+
+	env.str("REDIS_URL", &c.Redis.URL)
+
+	env.bool("TRACING_ENABLED", &c.Tracing.Enabled)
+	env.str("JAEGER_URL", &c.Tracing.Endpoint)
+	env.str("TRACING_SERVICE_NAME", &c.Tracing.ServiceName)
+	env.str("TRACING_SERVICE_VERSION", &c.Tracing.ServiceVersion)
+	env.str("TRACING_ENVIRONMENT", &c.Tracing.Environment)
+	env.str("TRACING_SAMPLER", &c.Tracing.Sampler)
+	env.float("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio)
+	env.float("TRACING_RATE_LIMIT", &c.Tracing.RateLimit)
+
+	env.int("WORKER_POOL_SIZE", &c.Worker.PoolSize)
+	env.int("WORKER_QUEUE_SIZE", &c.Worker.QueueSize)
+	env.int("WORKER_BATCH_MAX_CONCURRENCY", &c.Worker.BatchMaxConcurrency)
+	env.duration("WORKER_BATCH_TIMEOUT", &c.Worker.BatchTimeout)
+	env.int("WORKER_RETRY_MAX_ATTEMPTS", &c.Worker.Retry.MaxAttempts)
+	env.duration("WORKER_RETRY_INITIAL_BACKOFF", &c.Worker.Retry.InitialBackoff)
+	env.duration("WORKER_RETRY_MAX_BACKOFF", &c.Worker.Retry.MaxBackoff)
+	env.float("WORKER_RETRY_JITTER", &c.Worker.Retry.Jitter)
+
+	env.duration("CACHE_RESPONSE_TTL", &c.Cache.ResponseTTL)
+	env.duration("CACHE_STALE_TTL", &c.Cache.StaleTTL)
+	env.int("CACHE_L1_SIZE", &c.Cache.L1Size)
+	env.duration("CACHE_L1_TTL", &c.Cache.L1TTL)
+	env.durationMap("CACHE_ROUTE_TTLS", &c.Cache.RouteTTLs)
+
+	env.float("APPROVAL_THRESHOLD", &c.Limits.ApprovalThreshold)
+
+	env.float("FRAUD_FLAG_SCORE", &c.Fraud.FlagScore)
+	env.float("FRAUD_HOLD_SCORE", &c.Fraud.HoldScore)
+	env.float("FRAUD_REJECT_SCORE", &c.Fraud.RejectScore)
+	env.duration("FRAUD_VELOCITY_WINDOW", &c.Fraud.VelocityWindow)
+	env.int("FRAUD_VELOCITY_MAX_COUNT", &c.Fraud.VelocityMaxCount)
+	env.duration("FRAUD_HISTORY_WINDOW", &c.Fraud.HistoryWindow)
+	env.int("FRAUD_MIN_HISTORY", &c.Fraud.MinHistory)
+	env.float("FRAUD_AMOUNT_MULTIPLIER", &c.Fraud.AmountMultiplier)
+	env.int("FRAUD_ODD_HOURS_START", &c.Fraud.OddHoursStart)
+	env.int("FRAUD_ODD_HOURS_END", &c.Fraud.OddHoursEnd)
+
+	env.float("REFERRAL_REFERRER_REWARD", &c.Referrals.ReferrerReward)
+	env.float("REFERRAL_REFEREE_REWARD", &c.Referrals.RefereeReward)
+	env.float("REFERRAL_MIN_AMOUNT", &c.Referrals.MinAmount)
+	env.duration("REFERRAL_WINDOW", &c.Referrals.Window)
+
+	env.str("KYC_PROVIDER", &c.KYC.Provider)
+	env.str("KYC_API_URL", &c.KYC.APIURL)
+	env.str("KYC_API_KEY", &c.KYC.APIKey)
+	env.str("KYC_WEBHOOK_SECRET", &c.KYC.WebhookSecret)
+
+	env.int("DOCUMENT_MAX_BYTES", &c.Documents.MaxBytes)
+	env.duration("DOCUMENT_URL_TTL", &c.Documents.URLTTL)
+	env.str("DOCUMENT_SCANNER", &c.Documents.Scanner)
+	env.str("DOCUMENT_CLAMAV_ADDRESS", &c.Documents.ClamAVAddress)
+
+	env.duration("DISPUTE_WINDOW", &c.Disputes.Window)
+	env.bool("CHARGEBACK_ALLOW_NEGATIVE_BALANCE", &c.Chargebacks.AllowNegativeBalance)
+	env.float("CHARGEBACK_FREEZE_THRESHOLD", &c.Chargebacks.FreezeThreshold)
+	env.duration("MERCHANT_PAYOUT_INTERVAL", &c.Merchants.PayoutInterval)
+	env.duration("INVOICE_INTERVAL", &c.Invoices.Interval)
+	env.str("INVOICE_ISSUER", &c.Invoices.Issuer)
+	env.str("INVOICE_CURRENCY", &c.Invoices.Currency)
+	env.stringMap("WEBHOOK_SECRETS", &c.Webhooks.Secrets)
+	env.duration("WEBHOOK_TOLERANCE", &c.Webhooks.Tolerance)
+	env.str("WEBHOOK_CURRENCY", &c.Webhooks.Currency)
+	env.int("BANK_TRANSFER_ACH_SETTLEMENT_DAYS", &c.BankTransfers.ACHSettlementDays)
+	env.int("BANK_TRANSFER_SEPA_SETTLEMENT_DAYS", &c.BankTransfers.SEPASettlementDays)
+	env.duration("BANK_TRANSFER_SETTLE_INTERVAL", &c.BankTransfers.SettleInterval)
+	env.stringMap("BANK_TRANSFER_TEST_RETURNS", &c.BankTransfers.TestReturns)
+
+	env.str("JWT_SECRET", &c.Auth.JWTSecret)
+	env.str("PAYMENT_LINK_SECRET", &c.Auth.PaymentLinkSecret)
+	env.str("DOCUMENT_URL_SECRET", &c.Auth.DocumentURLSecret)
+	env.str("RECEIVE_QR_SECRET", &c.Auth.ReceiveQRSecret)
+	env.str("FIELD_ENCRYPTION_KEYS", &c.Auth.FieldEncryptionKeys)
+	env.str("FIELD_ENCRYPTION_INDEX_KEY", &c.Auth.FieldEncryptionIndexKey)
+
+	env.int("PASSWORD_MIN_LENGTH", &c.Password.MinLength)
+	env.int("PASSWORD_REQUIRED_CLASSES", &c.Password.RequiredClasses)
+	env.str("PASSWORD_BREACH_FILTER", &c.Password.BreachFilter)
+	env.str("PASSWORD_HASH_ALGORITHM", &c.Password.HashAlgorithm)
+	env.int("ARGON2_MEMORY_KIB", &c.Password.Argon2Memory)
+	env.int("ARGON2_ITERATIONS", &c.Password.Argon2Iterations)
+	env.int("ARGON2_PARALLELISM", &c.Password.Argon2Parallelism)
+	env.int("BCRYPT_COST", &c.Password.BcryptCost)
+
+	env.str("GEOIP_DB", &c.GeoIP.DBPath)
+	env.float("IMPOSSIBLE_TRAVEL_MAX_SPEED_KMH", &c.GeoIP.MaxTravelSpeedKmh)
+	env.float("IMPOSSIBLE_TRAVEL_MIN_DISTANCE_KM", &c.GeoIP.MinTravelDistanceKm)
+
+	env.str("CAPTCHA_PROVIDER", &c.Captcha.Provider)
+	env.str("CAPTCHA_SECRET", &c.Captcha.Secret)
+	env.int("CAPTCHA_FAILURE_THRESHOLD", &c.Captcha.FailureThreshold)
+	env.duration("CAPTCHA_FAILURE_WINDOW", &c.Captcha.FailureWindow)
+
+	env.duration("BRUTE_FORCE_WINDOW", &c.BruteForce.Window)
+	env.int("BRUTE_FORCE_TARPIT_AFTER", &c.BruteForce.TarpitAfter)
+	env.duration("BRUTE_FORCE_TARPIT_DELAY", &c.BruteForce.TarpitDelay)
+	env.duration("BRUTE_FORCE_MAX_TARPIT_DELAY", &c.BruteForce.MaxTarpitDelay)
+	env.int("BRUTE_FORCE_IP_BAN_AFTER", &c.BruteForce.IPBanAfter)
+	env.int("BRUTE_FORCE_USERNAME_BAN_AFTER", &c.BruteForce.UsernameBanAfter)
+	env.duration("BRUTE_FORCE_BAN_DURATION", &c.BruteForce.BanDuration)
+
+	env.int("SCHEDULED_RETRY_MAX_ATTEMPTS", &c.Scheduled.Retry.MaxAttempts)
+	env.duration("SCHEDULED_RETRY_INITIAL_BACKOFF", &c.Scheduled.Retry.InitialBackoff)
+	env.duration("SCHEDULED_RETRY_MAX_BACKOFF", &c.Scheduled.Retry.MaxBackoff)
+	env.float("SCHEDULED_RETRY_JITTER", &c.Scheduled.Retry.Jitter)
+
+	env.str("CALLBACK_SIGNING_SECRET", &c.Callback.SigningSecret)
+	env.int("CALLBACK_MAX_ATTEMPTS", &c.Callback.MaxAttempts)
+	env.duration("CALLBACK_INITIAL_BACKOFF", &c.Callback.InitialBackoff)
+
+	env.int("NOTIFY_WORKERS", &c.Notifications.Workers)
+	env.int("NOTIFY_QUEUE_SIZE", &c.Notifications.QueueSize)
+	env.str("NOTIFY_TEMPLATE_DIR", &c.Notifications.TemplateDir)
+	env.bool("NOTIFY_ADMINS_ON_SCHEDULED_FAILURE", &c.Notifications.NotifyAdminsOnScheduledFailure)
+	env.str("SMTP_HOST", &c.Notifications.SMTPHost)
+	env.int("SMTP_PORT", &c.Notifications.SMTPPort)
+	env.str("SMTP_USERNAME", &c.Notifications.SMTPUsername)
+	env.str("SMTP_PASSWORD", &c.Notifications.SMTPPassword)
+	env.str("SMTP_FROM", &c.Notifications.SMTPFrom)
+	env.str("TWILIO_ACCOUNT_SID", &c.Notifications.TwilioAccountSID)
+	env.str("TWILIO_AUTH_TOKEN", &c.Notifications.TwilioAuthToken)
+	env.str("TWILIO_FROM", &c.Notifications.TwilioFrom)
+	env.str("FCM_CREDENTIALS_FILE", &c.Notifications.FCMCredentialsFile)
+
+	env.int("RECONCILIATION_HOUR", &c.Reconciliation.Hour)
+
+	env.int("PARTITION_MONTHS_AHEAD", &c.Partitions.MonthsAhead)
+	env.int("PARTITION_RETENTION_MONTHS", &c.Partitions.RetentionMonths)
+	env.duration("PARTITION_CHECK_INTERVAL", &c.Partitions.CheckInterval)
+
+	env.duration("ARCHIVE_MAX_AGE", &c.Archive.MaxAge)
+	env.int("ARCHIVE_BATCH_SIZE", &c.Archive.BatchSize)
+	env.duration("ARCHIVE_CHECK_INTERVAL", &c.Archive.CheckInterval)
+
+	env.int("ANALYTICS_COHORT_MONTHS", &c.Analytics.CohortMonths)
+	env.duration("ANALYTICS_COHORT_INTERVAL", &c.Analytics.CohortInterval)
+
+	env.str("EXPORT_CURRENCY", &c.Export.Currency)
+	env.str("EXPORT_INSTITUTION", &c.Export.Institution)
+	env.str("EXPORT_BANK_ID", &c.Export.BankID)
+
+	env.str("OBJECT_STORE_BACKEND", &c.ObjectStore.Backend)
+	env.str("OBJECT_STORE_DIR", &c.ObjectStore.LocalDir)
+	env.str("OBJECT_STORE_S3_BUCKET", &c.ObjectStore.S3Bucket)
+	env.str("OBJECT_STORE_S3_REGION", &c.ObjectStore.S3Region)
+	env.str("OBJECT_STORE_S3_ENDPOINT", &c.ObjectStore.S3Endpoint)
+
+	env.str("SECRETS_BACKEND", &c.Secrets.Backend)
+	env.duration("SECRETS_REFRESH_INTERVAL", &c.Secrets.RefreshInterval)
+	env.str("SECRETS_JWT_SECRET_REF", &c.Secrets.JWTSecretRef)
+	env.str("SECRETS_DB_URL_REF", &c.Secrets.DBURLRef)
+	env.str("VAULT_ADDR", &c.Secrets.VaultAddress)
+	env.str("VAULT_TOKEN", &c.Secrets.VaultToken)
+	env.str("VAULT_KV_MOUNT", &c.Secrets.VaultMount)
+	env.str("AWS_REGION", &c.Secrets.AWSRegion)
+
+	env.str("API_V1_DEPRECATED_AT", &c.API.V1DeprecatedAt)
+	env.str("API_V1_SUNSET", &c.API.V1Sunset)
+
+	env.duration("STARTUP_WAIT_TIMEOUT", &c.Startup.WaitTimeout)
+	env.duration("STARTUP_RETRY_INITIAL_BACKOFF", &c.Startup.InitialBackoff)
+	env.duration("STARTUP_RETRY_MAX_BACKOFF", &c.Startup.MaxBackoff)
+
+	return env.err()
+}
+
+// applyDerivedDefaults fills secrets that default to keys derived from the JWT secret.
+func (c *Config) applyDerivedDefaults() {
+	if c.Auth.JWTSecret == "" {
+		return
+	}
+	if c.Auth.PaymentLinkSecret == "" {
+		c.Auth.PaymentLinkSecret = deriveKey("payment-link", c.Auth.JWTSecret)
+	}
+	if c.Auth.DocumentURLSecret == "" {
+		c.Auth.DocumentURLSecret = deriveKey("document-url", c.Auth.JWTSecret)
+	}
+	if c.Auth.ReceiveQRSecret == "" {
+		c.Auth.ReceiveQRSecret = deriveKey("receive-qr", c.Auth.JWTSecret)
+	}
+	if c.Auth.FieldEncryptionKeys == "" {
+		c.Auth.FieldEncryptionKeys = "default:" + deriveKey("field-encryption", c.Auth.JWTSecret)
+	}
+	if c.Auth.FieldEncryptionIndexKey == "" {
+		c.Auth.FieldEncryptionIndexKey = deriveKey("field-index", c.Auth.JWTSecret)
+	}
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Auth.JWTSecret != "" || c.Secrets.ManagedJWTSecret(), "JWT_SECRET is required")
+	check(c.Database.URL != "" || c.Secrets.ManagedDBURL(), "DB_URL is required")
+	// Derived keys must not change when a managed JWT secret rotates
+	check(c.Auth.PaymentLinkSecret != "", "PAYMENT_LINK_SECRET is required when JWT_SECRET is not set")
+	check(c.Auth.DocumentURLSecret != "", "DOCUMENT_URL_SECRET is required when JWT_SECRET is not set")
+	check(c.Auth.ReceiveQRSecret != "", "RECEIVE_QR_SECRET is required when JWT_SECRET is not set")
+	check(c.Auth.FieldEncryptionKeys != "" && c.Auth.FieldEncryptionIndexKey != "",
+		"FIELD_ENCRYPTION_KEYS and FIELD_ENCRYPTION_INDEX_KEY are required when JWT_SECRET is not set")
+	check(c.Server.Port != "", "server port is required")
+
+	check(c.Server.ReadTimeout > 0, "server read timeout must be positive")
+	check(c.Server.WriteTimeout > 0, "server write timeout must be positive")
+	check(c.Server.IdleTimeout > 0, "server idle timeout must be positive")
+	check(c.Server.ShutdownTimeout > 0, "server shutdown timeout must be positive")
+	check(c.Server.ShutdownDrainTimeout > 0, "server shutdown_drain_timeout must be positive")
+	check(c.Server.ShutdownCloseTimeout > 0, "server shutdown_close_timeout must be positive")
+	check(c.Server.MutexProfileFraction >= 0, "server mutex_profile_fraction must not be negative")
+	check(c.Server.HealthCheckTimeout > 0, "server health_check_timeout must be positive")
+	if tlsCfg := c.Server.TLS; tlsCfg.Enabled() {
+		check(len(tlsCfg.AutocertDomains) == 0 || (tlsCfg.CertFile == "" && tlsCfg.KeyFile == ""),
+			"tls cert_file and key_file can't be combined with autocert_domains")
+		check(len(tlsCfg.AutocertDomains) > 0 || (tlsCfg.CertFile != "" && tlsCfg.KeyFile != ""),
+			"tls cert_file and key_file must be set together")
+		check(len(tlsCfg.AutocertDomains) == 0 || tlsCfg.AutocertCacheDir != "", "tls autocert_cache_dir is required with autocert_domains")
+		check(tlsCfg.MinVersion == "1.2" || tlsCfg.MinVersion == "1.3", "tls min_version must be 1.2 or 1.3, got %q", tlsCfg.MinVersion)
+		check(tlsCfg.RedirectPort != c.Server.Port, "tls redirect_port must differ from the server port")
+	}
+	if c.Internal.Port != "" {
+		check(c.Internal.Port != c.Server.Port && c.Internal.Port != c.Server.TLS.RedirectPort,
+			"internal port must differ from the server and redirect ports")
+		check(c.Internal.CertFile != "" && c.Internal.KeyFile != "" && c.Internal.ClientCAFile != "",
+			"internal cert_file, key_file and client_ca_file are required when internal port is set")
+		check(len(c.Internal.ClientIdentities) > 0, "internal client_identities are required when internal port is set")
+		check(len(c.Internal.Paths) > 0, "internal paths are required when internal port is set")
+		for _, p := range c.Internal.Paths {
+			check(strings.HasPrefix(p, "/"), "internal path %q must start with /", p)
+		}
+		if _, err := c.Internal.Identities(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	check(c.Database.MaxConns > 0, "database max_conns must be positive")
+	check(c.Database.MinConns >= 0 && c.Database.MinConns <= c.Database.MaxConns,
+		"database min_conns must be between 0 and max_conns (%d)", c.Database.MaxConns)
+	check(c.Database.MaxConnLifetime > 0, "database max_conn_lifetime must be positive")
+	check(c.Database.MaxConnIdleTime > 0, "database max_conn_idle_time must be positive")
+	check(c.Database.HealthCheckPeriod > 0, "database health_check_period must be positive")
+
+	if c.Tracing.Enabled {
+		check(c.Tracing.Sampler == "always" || c.Tracing.Sampler == "ratio" || c.Tracing.Sampler == "rate_limited",
+			"tracing sampler must be always, ratio or rate_limited, got %q", c.Tracing.Sampler)
+		check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing sample_ratio must be between 0 and 1")
+		check(c.Tracing.RateLimit > 0, "tracing rate_limit must be positive")
+	}
+
+	check(c.Worker.PoolSize > 0, "worker pool_size must be positive")
+	check(c.Worker.QueueSize > 0, "worker queue_size must be positive")
+	check(c.Worker.BatchMaxConcurrency > 0, "worker batch_max_concurrency must be positive")
+	check(c.Worker.BatchTimeout > 0, "worker batch_timeout must be positive")
+	errs = append(errs, c.Worker.Retry.validate("worker retry")...)
+	errs = append(errs, c.Scheduled.Retry.validate("scheduled retry")...)
+
+	check(c.Cache.ResponseTTL > 0, "cache response_ttl must be positive")
+	check(c.Cache.StaleTTL >= 0, "cache stale_ttl must not be negative")
+	check(c.Cache.L1Size >= 0, "cache l1_size must not be negative")
+	check(c.Cache.L1Size == 0 || c.Cache.L1TTL > 0, "cache l1_ttl must be positive")
+	for prefix, ttl := range c.Cache.RouteTTLs {
+		check(strings.HasPrefix(prefix, "/"), "cache route_ttls prefix %q must start with /", prefix)
+		check(ttl >= 0, "cache route_ttls[%q] must not be negative", prefix)
+	}
+	check(c.Limits.ApprovalThreshold >= 0, "approval threshold must not be negative")
+
+	check(c.Fraud.validThresholds(), "fraud flag, hold and reject scores must be between 0 and 100 and increase, ignoring zeros")
+	check(c.Fraud.VelocityWindow > 0, "fraud velocity window must be positive")
+	check(c.Fraud.VelocityMaxCount >= 0, "fraud velocity max count must not be negative")
+	check(c.Fraud.HistoryWindow > 0, "fraud history window must be positive")
+	check(c.Fraud.MinHistory >= 0, "fraud min history must not be negative")
+	check(c.Fraud.AmountMultiplier >= 0, "fraud amount multiplier must not be negative")
+	check(c.Fraud.OddHoursStart >= 0 && c.Fraud.OddHoursStart < 24 && c.Fraud.OddHoursEnd >= 0 && c.Fraud.OddHoursEnd < 24,
+		"fraud odd hours must be between 0 and 23")
+
+	check(c.Referrals.ReferrerReward >= 0 && c.Referrals.RefereeReward >= 0, "referral rewards must not be negative")
+	check(c.Referrals.MinAmount >= 0, "referral min amount must not be negative")
+	check(c.Referrals.Window >= 0, "referral window must not be negative")
+
+	check(c.KYC.Provider == "" || c.KYC.Provider == "mock" || c.KYC.Provider == "http", "kyc provider must be http, mock or empty")
+	check(c.KYC.Provider != "http" || (c.KYC.APIURL != "" && c.KYC.APIKey != "" && c.KYC.WebhookSecret != ""),
+		"kyc api_url, api_key and webhook_secret are required for the http provider")
+
+	check(c.Documents.MaxBytes > 0 && c.Documents.MaxBytes <= 32<<20, "document max bytes must be between 1 and 33554432")
+	check(c.Documents.URLTTL > 0, "document url ttl must be positive")
+	check(c.Documents.Scanner == "" || c.Documents.Scanner == "clamav", "document scanner must be clamav or empty")
+	check(c.Documents.Scanner != "clamav" || c.Documents.ClamAVAddress != "", "DOCUMENT_CLAMAV_ADDRESS is required for the clamav scanner")
+	check(c.Disputes.Window > 0, "dispute window must be positive")
+	check(c.Chargebacks.FreezeThreshold >= 0, "chargeback freeze threshold must not be negative")
+	check(c.Merchants.PayoutInterval > 0, "merchant payout interval must be positive")
+	check(c.Invoices.Interval > 0, "invoice interval must be positive")
+	check(c.Invoices.Issuer != "", "invoice issuer is required")
+	check(len(c.Invoices.Currency) == 3, "invoice currency must be a three-letter code")
+	for provider, secret := range c.Webhooks.Secrets {
+		check(provider != "" && len(provider) <= 20 && provider == strings.ToLower(provider) && secret != "",
+			"webhook secret of %q must be set for a lowercase provider name of at most 20 characters", provider)
+	}
+	check(c.Webhooks.Tolerance > 0, "webhook tolerance must be positive")
+	check(len(c.Webhooks.Currency) == 3, "webhook currency must be a three-letter code")
+	check(c.BankTransfers.ACHSettlementDays >= 0 && c.BankTransfers.SEPASettlementDays >= 0, "bank transfer settlement days must not be negative")
+	check(c.BankTransfers.SettleInterval > 0, "bank transfer settle interval must be positive")
+	for last4, code := range c.BankTransfers.TestReturns {
+		check(len(last4) == 4 && strings.Trim(last4, "0123456789") == "" && code != "",
+			"bank transfer test return of %q must map four digits to a return code", last4)
+	}
+
+	check(c.Callback.MaxAttempts > 0, "callback max_attempts must be positive")
+	check(c.Callback.InitialBackoff > 0, "callback initial_backoff must be positive")
+
+	check(c.Notifications.Workers > 0, "notification workers must be positive")
+	check(c.Notifications.QueueSize > 0, "notification queue_size must be positive")
+	check(c.Notifications.SMTPPort > 0 && c.Notifications.SMTPPort <= 65535, "smtp port must be between 1 and 65535")
+	check(c.Notifications.SMTPHost == "" || c.Notifications.SMTPFrom != "", "smtp from is required when smtp host is set")
+	check(c.Notifications.TwilioAccountSID == "" || (c.Notifications.TwilioAuthToken != "" && c.Notifications.TwilioFrom != ""),
+		"twilio auth_token and from are required when twilio account_sid is set")
+
+	check(c.Password.MinLength > 0, "password min_length must be positive")
+	check(c.Password.RequiredClasses >= 0 && c.Password.RequiredClasses <= 4,
+		"password required_classes must be between 0 and 4")
+	check(c.Password.HashAlgorithm == "argon2id" || c.Password.HashAlgorithm == "bcrypt",
+		"password hash_algorithm must be argon2id or bcrypt, got %q", c.Password.HashAlgorithm)
+	check(c.Password.Argon2Memory > 0, "argon2 memory must be positive")
+	check(c.Password.Argon2Iterations > 0, "argon2 iterations must be positive")
+	check(c.Password.Argon2Parallelism > 0 && c.Password.Argon2Parallelism <= 255,
+		"argon2 parallelism must be between 1 and 255")
+	check(c.Password.BcryptCost >= 4 && c.Password.BcryptCost <= 31, "bcrypt cost must be between 4 and 31")
+
+	check(c.GeoIP.MaxTravelSpeedKmh >= 0, "geoip max_travel_speed_kmh must not be negative")
+	check(c.GeoIP.MinTravelDistanceKm >= 0, "geoip min_travel_distance_km must not be negative")
+
+	if c.Captcha.Provider != "" {
+		check(c.Captcha.Provider == "hcaptcha" || c.Captcha.Provider == "recaptcha" || c.Captcha.Provider == "turnstile",
+			"captcha provider must be hcaptcha, recaptcha or turnstile, got %q", c.Captcha.Provider)
+		check(c.Captcha.Secret != "", "captcha secret is required when captcha provider is set")
+		check(c.Captcha.FailureThreshold >= 0, "captcha failure_threshold must not be negative")
+		check(c.Captcha.FailureWindow > 0, "captcha failure_window must be positive")
+	}
+
+	check(c.BruteForce.Window > 0, "brute_force window must be positive")
+	check(c.BruteForce.TarpitAfter >= 0, "brute_force tarpit_after must not be negative")
+	check(c.BruteForce.TarpitDelay >= 0 && c.BruteForce.MaxTarpitDelay >= c.BruteForce.TarpitDelay,
+		"brute_force tarpit_delay must not be negative or exceed max_tarpit_delay")
+	check(c.BruteForce.IPBanAfter >= 0, "brute_force ip_ban_after must not be negative")
+	check(c.BruteForce.UsernameBanAfter >= 0, "brute_force username_ban_after must not be negative")
+	check(c.BruteForce.BanDuration > 0, "brute_force ban_duration must be positive")
+
+	check(c.Reconciliation.Hour >= 0 && c.Reconciliation.Hour <= 23,
+		"reconciliation hour must be between 0 and 23")
+
+	check(c.Partitions.MonthsAhead >= 1, "partition months ahead must be at least 1")
+	check(c.Partitions.RetentionMonths >= 0, "partition retention months must not be negative")
+	check(c.Partitions.CheckInterval > 0, "partition check interval must be positive")
+
+	check(c.Archive.MaxAge >= 0, "archive max age must not be negative")
+	check(c.Archive.BatchSize > 0, "archive batch size must be positive")
+	check(c.Archive.CheckInterval > 0, "archive check interval must be positive")
+
+	check(c.Analytics.CohortMonths >= 1, "analytics cohort months must be at least 1")
+	check(c.Analytics.CohortInterval > 0, "analytics cohort interval must be positive")
+
+	check(currencyCode.MatchString(c.Export.Currency), "export currency must be a three-letter ISO 4217 code, got %q", c.Export.Currency)
+
+	switch c.ObjectStore.Backend {
+	case "local":
+		check(c.ObjectStore.LocalDir != "", "OBJECT_STORE_DIR is required for the local object store")
+	case "s3":
+		check(c.ObjectStore.S3Bucket != "" && c.ObjectStore.S3Region != "",
+			"OBJECT_STORE_S3_BUCKET and OBJECT_STORE_S3_REGION are required for the s3 object store")
+	default:
+		errs = append(errs, fmt.Errorf("object store backend must be local or s3, got %q", c.ObjectStore.Backend))
+	}
+
+	switch c.Secrets.Backend {
+	case "env":
+	case "vault":
+		check(c.Secrets.VaultAddress != "" && c.Secrets.VaultToken != "",
+			"VAULT_ADDR and VAULT_TOKEN are required for the vault secrets backend")
+	case "aws":
+		check(c.Secrets.AWSRegion != "", "AWS_REGION is required for the aws secrets backend")
+	default:
+		errs = append(errs, fmt.Errorf("secrets backend must be env, vault or aws, got %q", c.Secrets.Backend))
+	}
+	check(c.Secrets.RefreshInterval > 0, "secrets refresh_interval must be positive")
+
+	deprecatedAt, err := parseAPIDate(c.API.V1DeprecatedAt)
+	check(err == nil, "api v1_deprecated_at must be a YYYY-MM-DD date, got %q", c.API.V1DeprecatedAt)
+	sunset, err := parseAPIDate(c.API.V1Sunset)
+	check(err == nil, "api v1_sunset must be a YYYY-MM-DD date, got %q", c.API.V1Sunset)
+	check(deprecatedAt.IsZero() || sunset.IsZero() || !sunset.Before(deprecatedAt),
+		"api v1_sunset must not be before v1_deprecated_at")
+
+	check(c.Startup.WaitTimeout > 0, "startup wait_timeout must be positive")
+	check(c.Startup.InitialBackoff > 0 && c.Startup.MaxBackoff >= c.Startup.InitialBackoff,
+		"startup backoff must be positive with max_backoff >= initial_backoff")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func (r RetryConfig) validate(name string) []error {
+	var errs []error
+	if r.MaxAttempts <= 0 {
+		errs = append(errs, fmt.Errorf("%s max_attempts must be positive", name))
+	}
+	if r.InitialBackoff <= 0 || r.MaxBackoff < r.InitialBackoff {
+		errs = append(errs, fmt.Errorf("%s backoff must be positive with max_backoff >= initial_backoff", name))
+	}
+	if r.Jitter < 0 || r.Jitter > 1 {
+		errs = append(errs, fmt.Errorf("%s jitter must be between 0 and 1", name))
+	}
+	return errs
+}
+
+// validThresholds reports whether the enabled fraud thresholds are in order.
+func (f FraudConfig) validThresholds() bool {
+	last := 0.0
+	for _, t := range []float64{f.FlagScore, f.HoldScore, f.RejectScore} {
+		if t < 0 || t > 100 {
+			return false
+		}
+		if t == 0 {
+			continue
+		}
+		if t <= last {
+			return false
+		}
+		last = t
+	}
+	return true
+}
+
+// PoolConfig returns a pgxpool configuration for the database settings.
+func (d DatabaseConfig) PoolConfig() (*pgxpool.Config, error) {
+	pc, err := pgxpool.ParseConfig(d.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse DB_URL: %w", err)
+	}
+	pc.MaxConns = int32(d.MaxConns)
+	pc.MinConns = int32(d.MinConns)
+	pc.MaxConnLifetime = d.MaxConnLifetime
+	pc.MaxConnIdleTime = d.MaxConnIdleTime
+	pc.HealthCheckPeriod = d.HealthCheckPeriod
+	return pc, nil
+}
+
+// deriveKey derives a base64 encoded 32-byte key for purpose from secret.
+func deriveKey(purpose, secret string) string {
+	sum := sha256.Sum256([]byte(purpose + ":" + secret))
+	return base64.StdEncoding.EncodeToString(sum[:])
 }
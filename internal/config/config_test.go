@@ -0,0 +1,252 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setRequiredEnv(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("DB_URL", "postgres://localhost/test")
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	setRequiredEnv(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "8080", cfg.Server.Port)
+	assert.Equal(t, 5, cfg.Worker.PoolSize)
+	assert.Equal(t, 5*time.Minute, cfg.Cache.ResponseTTL)
+	// Secrets that are not set are derived from the JWT secret
+	assert.NotEmpty(t, cfg.Auth.PaymentLinkSecret)
+	assert.NotEqual(t, cfg.Auth.JWTSecret, cfg.Auth.PaymentLinkSecret, "a leaked link key must not sign JWTs")
+	assert.NotEmpty(t, cfg.Auth.FieldEncryptionKeys)
+	assert.NotEmpty(t, cfg.Auth.FieldEncryptionIndexKey)
+	assert.NotEmpty(t, cfg.Auth.DocumentURLSecret)
+	assert.NotEmpty(t, cfg.Auth.ReceiveQRSecret)
+}
+
+func TestLoad_FileThenEnv(t *testing.T) {
+	setRequiredEnv(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+server:
+  port: "9090"
+  shutdown_timeout: 10s
+worker:
+  pool_size: 8
+  queue_size: 500
+`), 0o600))
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("WORKER_POOL_SIZE", "12")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "9090", cfg.Server.Port)
+	assert.Equal(t, 10*time.Second, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, 12, cfg.Worker.PoolSize, "env overrides the file")
+	assert.Equal(t, 500, cfg.Worker.QueueSize)
+}
+
+func TestLoad_CacheRouteTTLs(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("CACHE_ROUTE_TTLS", "/api/v1/balances=30s, /api/v1/transactions=0s")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.Cache.RouteTTLs["/api/v1/balances"])
+	assert.Equal(t, time.Duration(0), cfg.Cache.RouteTTLs["/api/v1/transactions"])
+	assert.Equal(t, 5*time.Minute, cfg.Cache.RouteTTLs["/api/v1/users"], "unlisted defaults are kept")
+
+	t.Setenv("CACHE_ROUTE_TTLS", "/api/v1/balances")
+	_, err = Load()
+	assert.ErrorContains(t, err, "CACHE_ROUTE_TTLS")
+}
+
+func TestLoad_FraudThresholds(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("FRAUD_REJECT_SCORE", "0")
+
+	cfg, err := Load()
+	require.NoError(t, err, "a zero threshold disables its decision")
+	assert.Equal(t, 0.0, cfg.Fraud.RejectScore)
+
+	t.Setenv("FRAUD_HOLD_SCORE", "20")
+	_, err = Load()
+	assert.ErrorContains(t, err, "fraud flag, hold and reject scores")
+}
+
+func TestLoad_NotificationProviders(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "smtp from is required")
+
+	t.Setenv("SMTP_FROM", "noreply@example.com")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 587, cfg.Notifications.SMTPPort)
+}
+
+func TestLoad_ObjectStore(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("OBJECT_STORE_BACKEND", "s3")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "OBJECT_STORE_S3_BUCKET and OBJECT_STORE_S3_REGION are required")
+
+	t.Setenv("OBJECT_STORE_S3_BUCKET", "avatars")
+	t.Setenv("OBJECT_STORE_S3_REGION", "eu-central-1")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "avatars", cfg.ObjectStore.S3Bucket)
+}
+
+func TestLoad_ImpossibleTravel(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("IMPOSSIBLE_TRAVEL_MAX_SPEED_KMH", "-1")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "geoip max_travel_speed_kmh must not be negative")
+
+	t.Setenv("IMPOSSIBLE_TRAVEL_MAX_SPEED_KMH", "900")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 900.0, cfg.GeoIP.MaxTravelSpeedKmh)
+	assert.Equal(t, 300.0, cfg.GeoIP.MinTravelDistanceKm)
+}
+
+func TestLoad_Captcha(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("CAPTCHA_PROVIDER", "turnstile")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "captcha secret is required")
+
+	t.Setenv("CAPTCHA_SECRET", "secret")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 3, cfg.Captcha.FailureThreshold)
+
+	t.Setenv("CAPTCHA_PROVIDER", "recaptcha-v9")
+	_, err = Load()
+	assert.ErrorContains(t, err, "captcha provider must be hcaptcha, recaptcha or turnstile")
+}
+
+func TestLoad_BruteForce(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("BRUTE_FORCE_TARPIT_DELAY", "30s")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "brute_force tarpit_delay must not be negative or exceed max_tarpit_delay")
+
+	t.Setenv("BRUTE_FORCE_MAX_TARPIT_DELAY", "1m")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, cfg.BruteForce.MaxTarpitDelay)
+	assert.Equal(t, 50, cfg.BruteForce.IPBanAfter)
+}
+
+func TestLoad_TLS(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("TLS_CERT_FILE", "server.crt")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "tls cert_file and key_file must be set together")
+
+	t.Setenv("TLS_CERT_FILE", "")
+	t.Setenv("TLS_AUTOCERT_DOMAINS", "api.example.com, www.example.com")
+	t.Setenv("TLS_REDIRECT_PORT", "80")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Server.TLS.Enabled())
+	assert.Equal(t, []string{"api.example.com", "www.example.com"}, cfg.Server.TLS.AutocertDomains)
+}
+
+func TestLoad_Internal(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("INTERNAL_PORT", "9443")
+	t.Setenv("INTERNAL_TLS_CERT_FILE", "internal.crt")
+	t.Setenv("INTERNAL_TLS_KEY_FILE", "internal.key")
+	t.Setenv("INTERNAL_CLIENT_CA_FILE", "clients-ca.crt")
+	t.Setenv("INTERNAL_CLIENT_IDENTITIES", "ops-console=1:root")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, `internal client identity "ops-console" must be userID:role`)
+
+	t.Setenv("INTERNAL_CLIENT_IDENTITIES", "ops-console=1:admin,prometheus=2:user")
+	cfg, err := Load()
+	require.NoError(t, err)
+	identities, err := cfg.Internal.Identities()
+	require.NoError(t, err)
+	assert.Equal(t, InternalIdentity{UserID: 1, Role: "admin"}, identities["ops-console"])
+	assert.Equal(t, []string{"/admin", "/worker", "/metrics"}, cfg.Internal.Paths)
+}
+
+func TestLoad_TracingSampler(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("TRACING_SAMPLER", "ratio")
+	t.Setenv("TRACING_SAMPLE_RATIO", "1.5")
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "tracing sample_ratio must be between 0 and 1")
+
+	t.Setenv("TRACING_ENABLED", "false")
+	cfg, err := Load()
+	require.NoError(t, err, "sampling is not checked while tracing is disabled")
+	assert.False(t, cfg.Tracing.Enabled)
+}
+
+func TestLoad_APIV1Dates(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("API_V1_DEPRECATED_AT", "2026-07-01")
+	t.Setenv("API_V1_SUNSET", "2027-01-31")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	deprecatedAt, sunset := cfg.API.V1Dates()
+	assert.Equal(t, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), deprecatedAt)
+	assert.Equal(t, time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC), sunset)
+
+	t.Setenv("API_V1_SUNSET", "31/01/2027")
+	_, err = Load()
+	assert.ErrorContains(t, err, "api v1_sunset must be a YYYY-MM-DD date")
+}
+
+func TestLoad_UnknownFileKey(t *testing.T) {
+	setRequiredEnv(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("worker:\n  poolsize: 8\n"), 0o600))
+	t.Setenv("CONFIG_FILE", path)
+
+	_, err := Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_ReportsAllErrors(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("WORKER_POOL_SIZE", "many")
+	t.Setenv("CACHE_RESPONSE_TTL", "soon")
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WORKER_POOL_SIZE")
+	assert.Contains(t, err.Error(), "CACHE_RESPONSE_TTL")
+
+	t.Setenv("WORKER_POOL_SIZE", "")
+	t.Setenv("CACHE_RESPONSE_TTL", "")
+	t.Setenv("RECONCILIATION_HOUR", "24")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET is required")
+	assert.Contains(t, err.Error(), "reconciliation hour")
+}
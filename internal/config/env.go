@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envReader overlays environment variables onto config fields.
+type envReader struct {
+	errs []error
+}
+
+func (e *envReader) str(key string, dst *string) {
+	if val := os.Getenv(key); val != "" {
+		*dst = val
+	}
+}
+
+func (e *envReader) int(key string, dst *int) {
+	if val := os.Getenv(key); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: invalid integer %q", key, val))
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *envReader) float(key string, dst *float64) {
+	if val := os.Getenv(key); val != "" {
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: invalid number %q", key, val))
+			return
+		}
+		*dst = f
+	}
+}
+
+func (e *envReader) bool(key string, dst *bool) {
+	if val := os.Getenv(key); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: invalid boolean %q", key, val))
+			return
+		}
+		*dst = b
+	}
+}
+
+// duration accepts Go duration strings such as "30s" or "5m".
+func (e *envReader) duration(key string, dst *time.Duration) {
+	if val := os.Getenv(key); val != "" {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: invalid duration %q", key, val))
+			return
+		}
+		*dst = d
+	}
+}
+
+// list accepts a comma-separated list, replacing the field's entries.
+func (e *envReader) list(key string, dst *[]string) {
+	val := os.Getenv(key)
+	if val == "" {
+		return
+	}
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*dst = items
+}
+
+// stringMap accepts comma-separated key=value pairs, setting each listed key.
+func (e *envReader) stringMap(key string, dst *map[string]string) {
+	val := os.Getenv(key)
+	if val == "" {
+		return
+	}
+	parsed := make(map[string]string)
+	for _, pair := range strings.Split(val, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || k == "" {
+			e.errs = append(e.errs, fmt.Errorf("%s: invalid key=value pair %q", key, pair))
+			return
+		}
+		parsed[k] = v
+	}
+	if *dst == nil {
+		*dst = make(map[string]string, len(parsed))
+	}
+	for k, v := range parsed {
+		(*dst)[k] = v
+	}
+}
+
+// durationMap accepts comma-separated key=duration pairs, setting each listed key.
+func (e *envReader) durationMap(key string, dst *map[string]time.Duration) {
+	val := os.Getenv(key)
+	if val == "" {
+		return
+	}
+	parsed := make(map[string]time.Duration)
+	for _, pair := range strings.Split(val, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		d, err := time.ParseDuration(v)
+		if !ok || k == "" || err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: invalid key=duration pair %q", key, pair))
+			return
+		}
+		parsed[k] = d
+	}
+	if *dst == nil {
+		*dst = make(map[string]time.Duration, len(parsed))
+	}
+	for k, d := range parsed {
+		(*dst)[k] = d
+	}
+}
+
+func (e *envReader) err() error {
+	if len(e.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid environment: %w", errors.Join(e.errs...))
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AdminOverview is the operational summary of the admin dashboard
+type AdminOverview struct {
+	GeneratedAt  time.Time            `json:"generated_at"`
+	Users        UserOverview         `json:"users"`
+	Transactions []*TransactionWindow `json:"transactions"` // last 24 hours, 7 days and 30 days
+	Queue        QueueOverview        `json:"queue"`
+	Scheduled    ScheduledBacklog     `json:"scheduled"`
+}
+
+// UserOverview counts the users who are people: erased users and organization accounts are left out
+type UserOverview struct {
+	Total        int `json:"total"`
+	SignupsToday int `json:"signups_today"` // since midnight UTC
+}
+
+// TransactionWindow summarizes the transactions created in a recent period
+type TransactionWindow struct {
+	Window      string  `json:"window"` // "24h", "7d" or "30d"
+	Count       int     `json:"count"`
+	Completed   int     `json:"completed"`
+	Failed      int     `json:"failed"`
+	Volume      float64 `json:"volume"`       // total amount of the completed transactions
+	FailureRate float64 `json:"failure_rate"` // percentage of the transactions that failed
+}
+
+// QueueOverview is the current load of the worker pool
+type QueueOverview struct {
+	Depth         int `json:"depth"`
+	ActiveWorkers int `json:"active_workers"`
+}
+
+// ScheduledBacklog counts the pending scheduled transactions
+type ScheduledBacklog struct {
+	Pending  int `json:"pending"`
+	Overdue  int `json:"overdue"`  // due to run already but not run yet
+	Retrying int `json:"retrying"` // waiting to retry a failed run
+}
+
+// AdminOverviewRepository computes the aggregates of the admin overview.
+type AdminOverviewRepository interface {
+	// UserCounts counts users who are people, and those of them who signed up since the given time
+	UserCounts(ctx context.Context, since time.Time) (*UserOverview, error)
+	// TransactionWindows summarizes the transactions created since each of the
+	// given times, in the same order
+	TransactionWindows(ctx context.Context, since []time.Time) ([]*TransactionWindow, error)
+	// ScheduledBacklog counts pending scheduled transactions relative to now
+	ScheduledBacklog(ctx context.Context, now time.Time) (*ScheduledBacklog, error)
+}
+
+// AdminOverviewService defines business logic for the admin dashboard
+type AdminOverviewService interface {
+	// GetOverview computes the admin overview as of now
+	GetOverview(ctx context.Context) (*AdminOverview, error)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+)
+
+// ErrAlertRuleNotFound is returned when an alert rule does not exist or belongs to another user
+var ErrAlertRuleNotFound = NewError(ErrorKindNotFound, "alert_rule_not_found", "alert rule not found")
+
+// Alert rule types
+const (
+	// AlertLowBalance fires when a user's available balance drops below the threshold
+	AlertLowBalance = "low_balance"
+	// AlertLargeTransaction fires when a single transaction of the user exceeds the threshold
+	AlertLargeTransaction = "large_transaction"
+)
+
+// AlertRule is a user's request to be notified when a balance or transaction crosses a threshold
+type AlertRule struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Type      string    `json:"type"`
+	Threshold float64   `json:"threshold"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate validates the alert rule's business logic
+func (r *AlertRule) Validate() error {
+	if r.UserID <= 0 {
+		return &ValidationError{Msg: "user_id must be positive"}
+	}
+	if r.Type != AlertLowBalance && r.Type != AlertLargeTransaction {
+		return &ValidationError{Msg: "type must be low_balance or large_transaction"}
+	}
+	if r.Threshold <= 0 {
+		return &ValidationError{Msg: "threshold must be positive"}
+	}
+	return nil
+}
+
+// Event returns the notification event the rule sends when it fires
+func (r *AlertRule) Event() string {
+	if r.Type == AlertLowBalance {
+		return EventLowBalance
+	}
+	return EventLargeTransaction
+}
+
+// LowBalanceCrossed reports whether a change takes the balance below the threshold.
+func (r *AlertRule) LowBalanceCrossed(before, after float64) bool {
+	return before >= r.Threshold && after < r.Threshold
+}
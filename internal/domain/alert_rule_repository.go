@@ -0,0 +1,24 @@
+package domain
+
+import "context"
+
+// AlertRuleRepository defines the interface for alert rule data access
+type AlertRuleRepository interface {
+	// Create creates a new alert rule
+	Create(ctx context.Context, rule *AlertRule) error
+
+	// GetByID retrieves an alert rule by ID
+	GetByID(ctx context.Context, id int) (*AlertRule, error)
+
+	// ListByUser retrieves all alert rules of a user
+	ListByUser(ctx context.Context, userID int) ([]*AlertRule, error)
+
+	// ListActiveByUsers retrieves the active alert rules of the given users
+	ListActiveByUsers(ctx context.Context, userIDs []int) ([]*AlertRule, error)
+
+	// Update updates an alert rule's threshold and active flag
+	Update(ctx context.Context, rule *AlertRule) error
+
+	// Delete deletes one of the user's alert rules, returning ErrAlertRuleNotFound if there is none
+	Delete(ctx context.Context, userID, id int) error
+}
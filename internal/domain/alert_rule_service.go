@@ -0,0 +1,26 @@
+package domain
+
+import "context"
+
+// TransactionAlerter checks a committed transaction against the alert rules of the users involved
+type TransactionAlerter interface {
+	// CheckTransaction notifies users whose rules the transaction triggers
+	CheckTransaction(ctx context.Context, tx *Transaction)
+}
+
+// AlertRuleService defines the interface for alert rule business logic
+type AlertRuleService interface {
+	TransactionAlerter
+
+	// CreateRule validates and creates an alert rule
+	CreateRule(ctx context.Context, rule *AlertRule) error
+
+	// ListRules retrieves all alert rules of a user
+	ListRules(ctx context.Context, userID int) ([]*AlertRule, error)
+
+	// UpdateRule changes the threshold and active flag of one of the user's rules
+	UpdateRule(ctx context.Context, userID, id int, threshold float64, active bool) (*AlertRule, error)
+
+	// DeleteRule deletes one of the user's rules
+	DeleteRule(ctx context.Context, userID, id int) error
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// MaxAnalyticsMonths is the longest period user analytics cover
+const MaxAnalyticsMonths = 24
+
+// UserAnalytics summarizes a user's completed transactions over a period.
+type UserAnalytics struct {
+	UserID                   int             `json:"user_id"`
+	From                     time.Time       `json:"from"`
+	To                       time.Time       `json:"to"`
+	TransactionCount         int             `json:"transaction_count"`
+	AverageTransactionAmount float64         `json:"average_transaction_amount"`
+	SpendByType              []*TypeSpend    `json:"spend_by_type"`
+	MonthlyFlows             []*MonthlyFlow  `json:"monthly_flows"`
+	BalanceTrend             []*BalancePoint `json:"balance_trend"`
+}
+
+// TypeSpend is the money a user spent through one transaction type
+type TypeSpend struct {
+	Type   string  `json:"type"` // debit, transfer
+	Count  int     `json:"count"`
+	Amount float64 `json:"amount"`
+}
+
+// MonthlyFlow is the money that entered and left a user's account in a month
+type MonthlyFlow struct {
+	Month   string  `json:"month"` // YYYY-MM
+	Inflow  float64 `json:"inflow"`
+	Outflow float64 `json:"outflow"`
+	Net     float64 `json:"net"`
+}
+
+// BalancePoint is a user's balance at the end of a day on which it changed
+type BalancePoint struct {
+	Date    time.Time `json:"date"`
+	Balance float64   `json:"balance"`
+}
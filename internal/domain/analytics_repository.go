@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AnalyticsRepository computes a user's transaction analytics over [from, to).
+type AnalyticsRepository interface {
+	// TransactionSummary counts the user's transactions and their average amount
+	TransactionSummary(ctx context.Context, userID int, from, to time.Time) (count int, average float64, err error)
+
+	// SpendByType totals the money the user spent, per transaction type
+	SpendByType(ctx context.Context, userID int, from, to time.Time) ([]*TypeSpend, error)
+
+	// MonthlyFlows totals the money entering and leaving the account, per month, oldest first
+	MonthlyFlows(ctx context.Context, userID int, from, to time.Time) ([]*MonthlyFlow, error)
+
+	// BalanceTrend returns the end-of-day balance of each day in the period on which it changed, oldest first
+	BalanceTrend(ctx context.Context, userID int, from, to time.Time) ([]*BalancePoint, error)
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// AnalyticsService defines business logic for per-user analytics
+type AnalyticsService interface {
+	// GetUserAnalytics summarizes the user's transactions over the last months
+	// calendar months, the current one included
+	GetUserAnalytics(ctx context.Context, userID int, months int) (*UserAnalytics, error)
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	// ErrApprovalNotFound is returned when a transaction has no approval request
+	ErrApprovalNotFound = NewError(ErrorKindNotFound, "approval_not_found", "approval not found")
+	// ErrSelfApproval is returned when the user who requested a transaction tries to review it
+	ErrSelfApproval = NewError(ErrorKindForbidden, "self_approval", "a transaction cannot be reviewed by the user who requested it")
+	// ErrApprovalAlreadyReviewed is returned when another reviewer decided an
+	// approval request first
+	ErrApprovalAlreadyReviewed = NewError(ErrorKindConflict, "approval_already_reviewed", "transaction has already been reviewed")
+)
+
+// requesterKey is the context key of the user asking for a money movement
+type requesterKey struct{}
+
+// WithRequester returns ctx recording the user asking for its money movements.
+func WithRequester(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, requesterKey{}, userID)
+}
+
+// RequesterFromContext returns the user recorded by WithRequester, if any
+func RequesterFromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(requesterKey{}).(int)
+	return userID, ok
+}
+
+// Approval is the maker-checker record of a transaction held in "pending_approval".
+type Approval struct {
+	ID            int        `json:"id"`
+	TransactionID int        `json:"transaction_id"`
+	RequestedBy   int        `json:"requested_by"`
+	ReviewedBy    *int       `json:"reviewed_by,omitempty"`
+	Status        string     `json:"status"` // "pending", "approved", "rejected"
+	Reason        string     `json:"reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// NewApproval creates a pending approval for a transaction
+func NewApproval(transactionID, requestedBy int) *Approval {
+	return &Approval{
+		TransactionID: transactionID,
+		RequestedBy:   requestedBy,
+		Status:        "pending",
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+// Approve records the reviewer's approval
+func (a *Approval) Approve(reviewerID int) error {
+	return a.review(reviewerID, "approved", "")
+}
+
+// Reject records the reviewer's rejection and reason
+func (a *Approval) Reject(reviewerID int, reason string) error {
+	return a.review(reviewerID, "rejected", reason)
+}
+
+// review enforces that a pending approval is decided once, by someone other than the requester
+func (a *Approval) review(reviewerID int, status, reason string) error {
+	if a.Status != "pending" {
+		return &ValidationError{Msg: "transaction has already been " + a.Status}
+	}
+	if reviewerID == a.RequestedBy {
+		return ErrSelfApproval
+	}
+	now := time.Now().UTC()
+	a.ReviewedBy = &reviewerID
+	a.Status = status
+	a.Reason = reason
+	a.ReviewedAt = &now
+	return nil
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "context"
+
+// ApprovalRepository defines the interface for transaction approval data access
+type ApprovalRepository interface {
+	// Create creates a new approval request
+	Create(ctx context.Context, approval *Approval) error
+
+	// GetByTransactionID retrieves the approval request for a transaction
+	GetByTransactionID(ctx context.Context, transactionID int) (*Approval, error)
+
+	// ListPending retrieves approval requests awaiting review, oldest first
+	ListPending(ctx context.Context, limit, offset int) ([]*Approval, error)
+
+	// Update records the review of a pending approval request. It fails with
+	// ErrApprovalAlreadyReviewed if the request was reviewed in the meantime.
+	Update(ctx context.Context, approval *Approval) error
+}
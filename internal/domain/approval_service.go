@@ -0,0 +1,15 @@
+package domain
+
+import "context"
+
+// ApprovalService defines business logic for maker-checker approval of large transactions.
+type ApprovalService interface {
+	// Approve executes a pending transaction on behalf of a second admin
+	Approve(ctx context.Context, transactionID, reviewerID int) (*Transaction, error)
+
+	// Reject cancels a pending transaction without moving any money
+	Reject(ctx context.Context, transactionID, reviewerID int, reason string) (*Transaction, error)
+
+	// ListPending retrieves approval requests awaiting review
+	ListPending(ctx context.Context, limit, offset int) ([]*Approval, error)
+}
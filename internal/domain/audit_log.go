@@ -4,10 +4,22 @@ import "time"
 
 // AuditLog represents an audit log entry for tracking changes.
 type AuditLog struct {
-	ID         int
+	ID         int       `json:"id"`
+	ActorID    *int      `json:"actor_id,omitempty"`
+	EntityType string    `json:"entity_type"`
+	EntityID   int       `json:"entity_id"`
+	Action     string    `json:"action"`
+	Details    string    `json:"details,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AuditLogFilter selects audit log entries.
+type AuditLogFilter struct {
+	ActorID    *int
 	EntityType string
-	EntityID   int
 	Action     string
-	Details    string
-	CreatedAt  time.Time
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
 }
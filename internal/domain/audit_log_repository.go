@@ -1,7 +1,11 @@
 package domain
 
+import "context"
+
 // AuditLogRepository defines methods for audit log data access.
 type AuditLogRepository interface {
-	Create(log *AuditLog) error
-	ListByEntity(entityType string, entityID int) ([]*AuditLog, error)
+	Create(ctx context.Context, log *AuditLog) error
+	ListByEntity(ctx context.Context, entityType string, entityID int) ([]*AuditLog, error)
+	// Search returns the entries matching filter, newest first
+	Search(ctx context.Context, filter AuditLogFilter) ([]*AuditLog, error)
 }
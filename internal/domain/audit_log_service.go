@@ -0,0 +1,12 @@
+package domain
+
+import "context"
+
+// AuditLogService defines business logic for recording and reviewing audited actions
+type AuditLogService interface {
+	// Record stores an audit entry; actorID is nil for system actions
+	Record(ctx context.Context, actorID *int, entityType string, entityID int, action, details string) error
+
+	// Search retrieves the entries matching filter, newest first
+	Search(ctx context.Context, filter AuditLogFilter) ([]*AuditLog, error)
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Kinds of keys failed logins are counted and banned by
+const (
+	AuthBanKindIP       = "ip"
+	AuthBanKindUsername = "username"
+)
+
+// ErrAuthBanNotFound is returned when lifting a ban that doesn't exist or has expired
+var ErrAuthBanNotFound = NewError(ErrorKindNotFound, "auth_ban_not_found", "ban not found")
+
+// AuthBan is a temporary ban of an IP address or username from the auth endpoints.
+type AuthBan struct {
+	Kind      string    `json:"kind"`
+	Value     string    `json:"value"`
+	Failures  int       `json:"failures"`
+	BannedAt  time.Time `json:"banned_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AuthBanService lets admins see and lift brute-force bans.
+type AuthBanService interface {
+	// ListBans returns the bans in force, soonest to expire first
+	ListBans(ctx context.Context) ([]*AuthBan, error)
+	// LiftBan ends a ban and forgets the failures that led to it, returning
+	// ErrAuthBanNotFound if there is none
+	LiftBan(ctx context.Context, actorID int, kind, value string) error
+}
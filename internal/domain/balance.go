@@ -5,12 +5,23 @@ import (
 	"time"
 )
 
+var (
+	// ErrBalanceConflict is returned when a balance changed between being read and written
+	ErrBalanceConflict = NewError(ErrorKindConflict, "balance_conflict", "balance was modified concurrently")
+	// ErrAccountFrozen is returned when debiting, moving or holding the funds
+	// of a frozen balance
+	ErrAccountFrozen = NewError(ErrorKindForbidden, "account_frozen", "account is frozen")
+)
+
 // Balance represents a user's account balance with thread-safe operations.
 type Balance struct {
 	UserID        int
 	Amount        float64
+	HeldAmount    float64
+	Version       int
 	LastUpdatedAt time.Time
-	mu            sync.RWMutex // protects Amount and LastUpdatedAt
+	FrozenAt      *time.Time
+	mu            sync.RWMutex // protects Amount, HeldAmount and LastUpdatedAt
 }
 
 // NewBalance creates a new Balance instance
@@ -29,6 +40,18 @@ func (b *Balance) GetAmount() float64 {
 	return b.Amount
 }
 
+// AvailableAmount returns the balance that is not reserved by holds
+func (b *Balance) AvailableAmount() float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.Amount - b.HeldAmount
+}
+
+// IsFrozen reports whether the balance's funds may not leave it
+func (b *Balance) IsFrozen() bool {
+	return b.FrozenAt != nil
+}
+
 // SetAmount sets the balance amount in a thread-safe manner
 func (b *Balance) SetAmount(amount float64) {
 	b.mu.Lock()
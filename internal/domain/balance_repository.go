@@ -1,11 +1,18 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // BalanceRepository defines methods for balance data access.
 type BalanceRepository interface {
-	GetByUserID(userID int) (*Balance, error)
-	Update(balance *Balance) error
-	GetHistoricalBalance(userID int, limit int) ([]*Balance, error)
-	GetBalanceAtTime(userID int, t time.Time) (*Balance, error)
+	GetByUserID(ctx context.Context, userID int) (*Balance, error)
+	// Update stores balance only if its stored version still matches
+	// balance.Version, returning ErrBalanceConflict otherwise
+	Update(ctx context.Context, balance *Balance) error
+	GetHistoricalBalance(ctx context.Context, userID int, limit int) ([]*Balance, error)
+	GetBalanceAtTime(ctx context.Context, userID int, t time.Time) (*Balance, error)
+	// GetCurrentBalance recomputes the balance from the user's completed transactions
+	GetCurrentBalance(ctx context.Context, userID int) (*Balance, error)
 }
@@ -1,10 +1,14 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // BalanceService defines business logic for balances.
 type BalanceService interface {
-	GetCurrentBalance(userID int) (*Balance, error)
-	GetHistoricalBalance(userID int, limit int) ([]*Balance, error)
-	GetBalanceAtTime(userID int, time time.Time) (*Balance, error)
+	GetCurrentBalance(ctx context.Context, userID int) (*Balance, error)
+	GetHistoricalBalance(ctx context.Context, userID int, limit int) ([]*Balance, error)
+	GetBalanceAtTime(ctx context.Context, userID int, time time.Time) (*Balance, error)
+	GetAvailableBalance(ctx context.Context, userID int) (float64, error)
 }
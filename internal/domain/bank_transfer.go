@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Bank transfer directions.
+const (
+	BankTransferOutbound = "outbound"
+	BankTransferInbound  = "inbound"
+)
+
+// Bank transfer rails
+const (
+	BankRailACH  = "ach"
+	BankRailSEPA = "sepa"
+)
+
+// Bank transfer statuses.
+const (
+	BankTransferPending  = "pending"
+	BankTransferSettled  = "settled"
+	BankTransferReturned = "returned"
+)
+
+var (
+	// ErrBankTransferUserNotFound is returned when the user of a bank
+	// transfer does not exist
+	ErrBankTransferUserNotFound = NewError(ErrorKindNotFound, "user_not_found", "user not found")
+	// ErrBankTransferNotFound is returned when a bank transfer does not exist
+	ErrBankTransferNotFound = NewError(ErrorKindNotFound, "bank_transfer_not_found", "bank transfer not found")
+	// ErrBankAccountInvalid is returned when a bank transfer's funding source
+	// isn't one of its user's active bank accounts
+	ErrBankAccountInvalid = NewError(ErrorKindValidation, "bank_account_invalid", "bank transfers must use one of the user's active bank accounts")
+	// ErrBankTransferNotPending is returned when settling or returning a bank
+	// transfer that is already settled or returned
+	ErrBankTransferNotPending = NewError(ErrorKindConflict, "bank_transfer_not_pending", "bank transfer is no longer pending")
+)
+
+// BankReturnCode is a reason a bank refuses a transfer.
+type BankReturnCode struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+	Closes bool   `json:"closes_account"`
+}
+
+// bankReturnCodes are the return codes known for each rail
+var bankReturnCodes = map[string][]BankReturnCode{
+	BankRailACH: {
+		{Code: "R01", Reason: "insufficient funds"},
+		{Code: "R02", Reason: "account closed", Closes: true},
+		{Code: "R03", Reason: "no account or unable to locate account", Closes: true},
+		{Code: "R04", Reason: "invalid account number", Closes: true},
+		{Code: "R08", Reason: "payment stopped"},
+		{Code: "R10", Reason: "customer advises not authorized", Closes: true},
+		{Code: "R16", Reason: "account frozen", Closes: true},
+	},
+	BankRailSEPA: {
+		{Code: "AC01", Reason: "incorrect account number", Closes: true},
+		{Code: "AC04", Reason: "closed account number", Closes: true},
+		{Code: "AC06", Reason: "blocked account", Closes: true},
+		{Code: "AG01", Reason: "transaction forbidden", Closes: true},
+		{Code: "AM04", Reason: "insufficient funds"},
+		{Code: "MD01", Reason: "no mandate", Closes: true},
+		{Code: "MS03", Reason: "reason not specified"},
+	},
+}
+
+// BankReturnCodes returns the return codes known for a rail
+func BankReturnCodes(rail string) []BankReturnCode {
+	return bankReturnCodes[rail]
+}
+
+// LookupBankReturnCode returns a rail's return code, and whether it is known
+func LookupBankReturnCode(rail, code string) (BankReturnCode, bool) {
+	for _, c := range bankReturnCodes[rail] {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return BankReturnCode{}, false
+}
+
+// AddBusinessDays returns t moved forward by days weekdays, skipping Saturdays and Sundays (UTC)
+func AddBusinessDays(t time.Time, days int) time.Time {
+	t = t.UTC()
+	for days > 0 {
+		t = t.AddDate(0, 0, 1)
+		if wd := t.Weekday(); wd != time.Saturday && wd != time.Sunday {
+			days--
+		}
+	}
+	return t
+}
+
+// BankTransfer moves money between a balance and a bank account over ACH or SEPA.
+type BankTransfer struct {
+	ID              int        `json:"id"`
+	UserID          int        `json:"user_id"`
+	FundingSourceID int        `json:"funding_source_id"`
+	Direction       string     `json:"direction"`
+	Rail            string     `json:"rail"`
+	Amount          float64    `json:"amount"`
+	Status          string     `json:"status"`
+	ReturnCode      string     `json:"return_code,omitempty"`
+	ReturnReason    string     `json:"return_reason,omitempty"`
+	TransactionID   *int       `json:"transaction_id,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	SettleAt        time.Time  `json:"settle_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
+
+// Validate checks the transfer's direction, rail and amount
+func (t *BankTransfer) Validate() error {
+	if t.Direction != BankTransferOutbound && t.Direction != BankTransferInbound {
+		return &ValidationError{Msg: "direction must be outbound or inbound"}
+	}
+	if t.Rail != BankRailACH && t.Rail != BankRailSEPA {
+		return &ValidationError{Msg: "rail must be ach or sepa"}
+	}
+	if t.Amount <= 0 {
+		return &ValidationError{Msg: "amount must be positive"}
+	}
+	return nil
+}
+
+// TransactionKey returns the idempotency key of the transaction settling the transfer.
+func (t *BankTransfer) TransactionKey() string {
+	return "bank-transfer:" + strconv.Itoa(t.ID)
+}
+
+// BankTransferRun summarises a run of the bank transfer settlement job.
+type BankTransferRun struct {
+	Settled  int `json:"settled"`
+	Returned int `json:"returned"`
+}
+
+// BankTransferRepository defines methods for bank transfer data access
+type BankTransferRepository interface {
+	// Create stores a pending transfer
+	Create(ctx context.Context, transfer *BankTransfer) error
+	// Get fetches a transfer, or nil if there is none
+	Get(ctx context.Context, id int) (*BankTransfer, error)
+	// ListByUser fetches a page of a user's transfers, newest first
+	ListByUser(ctx context.Context, userID, limit, offset int) ([]*BankTransfer, error)
+	// ListByStatus fetches a page of the transfers with a status, oldest first
+	ListByStatus(ctx context.Context, status string, limit, offset int) ([]*BankTransfer, error)
+	// ListDue fetches up to limit pending transfers whose settlement date is
+	// at or before now, oldest first
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*BankTransfer, error)
+	// Complete stores the status, return code and transaction of a transfer
+	// that is still pending, returning ErrBankTransferNotPending otherwise
+	Complete(ctx context.Context, transfer *BankTransfer) error
+}
+
+// BankTransferService defines business logic for ACH and SEPA bank transfers
+type BankTransferService interface {
+	// Initiate starts a pending transfer between userID's balance and one of
+	// their bank accounts
+	Initiate(ctx context.Context, userID int, transfer *BankTransfer) (*BankTransfer, error)
+	// Get returns one of userID's transfers
+	Get(ctx context.Context, userID, id int) (*BankTransfer, error)
+	// ListByUser returns a page of a user's transfers, newest first
+	ListByUser(ctx context.Context, userID, limit, offset int) ([]*BankTransfer, error)
+	// ListByStatus returns a page of the transfers with a status, oldest first
+	ListByStatus(ctx context.Context, status string, limit, offset int) ([]*BankTransfer, error)
+	// Return records that the bank refused a pending transfer with code
+	Return(ctx context.Context, id int, actorID *int, code string) (*BankTransfer, error)
+	// SettleDue settles, or returns, every pending transfer that is due
+	SettleDue(ctx context.Context) (*BankTransferRun, error)
+	// Start begins settling due transfers periodically in the background
+	Start(ctx context.Context)
+	// Stop stops the background settlement
+	Stop()
+}
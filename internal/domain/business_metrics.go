@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UserActivityStats counts users by how recently they last logged in
+type UserActivityStats struct {
+	Total           int
+	ActiveLastHour  int
+	ActiveLastDay   int
+	ActiveLastMonth int
+}
+
+// TransactionTypeStats summarizes the transactions of one type in a period
+type TransactionTypeStats struct {
+	Type      string // credit, debit, transfer
+	Total     int
+	Completed int
+}
+
+// SuccessRate returns the percentage of the transactions that completed
+func (s *TransactionTypeStats) SuccessRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Completed) / float64(s.Total) * 100
+}
+
+// BalanceStats summarizes all balances.
+type BalanceStats struct {
+	Total          float64
+	Users          int
+	UsersAtOrBelow map[float64]int
+}
+
+// BusinessMetricsRepository computes the aggregates behind the business metrics.
+type BusinessMetricsRepository interface {
+	// UserActivity counts users not erased, by last login relative to now
+	UserActivity(ctx context.Context, now time.Time) (*UserActivityStats, error)
+	// TransactionStats summarizes transactions created since the given time, per type
+	TransactionStats(ctx context.Context, since time.Time) ([]*TransactionTypeStats, error)
+	// BalanceStats sums all balances and counts users at or below each bound
+	BalanceStats(ctx context.Context, bounds []float64) (*BalanceStats, error)
+	Ping(ctx context.Context) error
+}
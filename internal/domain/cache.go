@@ -0,0 +1,8 @@
+package domain
+
+import "context"
+
+// CacheInvalidator deletes cached entries whose keys match a pattern.
+type CacheInvalidator interface {
+	DeletePattern(ctx context.Context, pattern string) error
+}
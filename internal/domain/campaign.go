@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Campaign conditions: what a user does to earn a campaign's bonus
+const (
+	// CampaignFirstDeposit rewards a user's first deposit of at least the
+	// campaign's threshold
+	CampaignFirstDeposit = "first_deposit"
+	// CampaignVolumeThreshold rewards a user whose volume since the campaign
+	// started reaches its threshold
+	CampaignVolumeThreshold = "volume_threshold"
+)
+
+var (
+	// ErrCampaignNotFound is returned when a campaign does not exist
+	ErrCampaignNotFound = NewError(ErrorKindNotFound, "campaign_not_found", "campaign not found")
+	// ErrCampaignBonusAwarded is returned when a user was already awarded a
+	// campaign's bonus; each user earns it once
+	ErrCampaignBonusAwarded = NewError(ErrorKindConflict, "campaign_bonus_awarded", "campaign bonus already awarded")
+	// ErrCampaignBudgetExhausted is returned when a campaign's budget can't
+	// pay another bonus, or the campaign has ended
+	ErrCampaignBudgetExhausted = NewError(ErrorKindConflict, "campaign_budget_exhausted", "campaign budget exhausted")
+	// ErrCampaignBudgetBelowSpent is returned when a campaign's budget is cut
+	// below what it has already paid out
+	ErrCampaignBudgetBelowSpent = NewError(ErrorKindConflict, "campaign_budget_below_spent", "budget is below the amount already awarded")
+)
+
+// Campaign is a promotion crediting a bonus to each user who meets its condition.
+type Campaign struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Condition string    `json:"condition"` // first_deposit or volume_threshold
+	Threshold float64   `json:"threshold"` // the minimum deposit, or the volume to reach
+	Bonus     float64   `json:"bonus"`
+	Budget    float64   `json:"budget"`
+	Spent     float64   `json:"spent"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedBy int       `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the campaign's name, condition, amounts and period
+func (c *Campaign) Validate() error {
+	if c.Name == "" || len(c.Name) > 100 {
+		return &ValidationError{Msg: "name must be 1 to 100 characters"}
+	}
+	switch c.Condition {
+	case CampaignFirstDeposit:
+		if c.Threshold < 0 {
+			return &ValidationError{Msg: "threshold must not be negative"}
+		}
+	case CampaignVolumeThreshold:
+		if c.Threshold <= 0 {
+			return &ValidationError{Msg: "threshold must be positive"}
+		}
+	default:
+		return &ValidationError{Msg: "condition must be first_deposit or volume_threshold"}
+	}
+	if c.Bonus <= 0 {
+		return &ValidationError{Msg: "bonus must be positive"}
+	}
+	if c.Budget < c.Bonus {
+		return &ValidationError{Msg: "budget must cover at least one bonus"}
+	}
+	if !c.EndsAt.After(c.StartsAt) {
+		return &ValidationError{Msg: "ends_at must be after starts_at"}
+	}
+	return nil
+}
+
+// CampaignAward is a bonus a campaign paid to a user.
+type CampaignAward struct {
+	ID                 int       `json:"id"`
+	CampaignID         int       `json:"campaign_id"`
+	UserID             int       `json:"user_id"`
+	TransactionID      int       `json:"transaction_id"`
+	BonusTransactionID int       `json:"bonus_transaction_id"`
+	Amount             float64   `json:"amount"`
+	AwardedAt          time.Time `json:"awarded_at"`
+}
+
+// CampaignStats summarizes how a campaign performed
+type CampaignStats struct {
+	CampaignID       int        `json:"campaign_id"`
+	Awards           int        `json:"awards"`
+	Spent            float64    `json:"spent"`
+	RemainingBudget  float64    `json:"remaining_budget"`
+	BudgetUsed       float64    `json:"budget_used"`       // percent of the budget spent
+	QualifyingVolume float64    `json:"qualifying_volume"` // sum of the transactions that earned a bonus
+	FirstAwardAt     *time.Time `json:"first_award_at,omitempty"`
+	LastAwardAt      *time.Time `json:"last_award_at,omitempty"`
+}
+
+// TransactionRewarder credits the bonuses a committed transaction earns
+type TransactionRewarder interface {
+	// RewardTransaction pays the bonuses tx qualifies its payer for; failures
+	// are only logged
+	RewardTransaction(ctx context.Context, tx *Transaction)
+}
+
+// TransactionRewarders pays the bonuses of each of its rewarders in turn
+type TransactionRewarders []TransactionRewarder
+
+// RewardTransaction pays the bonuses tx earns from every rewarder
+func (r TransactionRewarders) RewardTransaction(ctx context.Context, tx *Transaction) {
+	for _, rewarder := range r {
+		rewarder.RewardTransaction(ctx, tx)
+	}
+}
+
+// CampaignRepository defines methods for campaign data access
+type CampaignRepository interface {
+	// List fetches every campaign, newest first
+	List(ctx context.Context) ([]*Campaign, error)
+	// GetByID fetches a campaign, or nil if there is none
+	GetByID(ctx context.Context, id int) (*Campaign, error)
+	// Create stores a campaign
+	Create(ctx context.Context, campaign *Campaign) error
+	// Update changes a campaign's name, budget and end, returning
+	// ErrCampaignBudgetBelowSpent if the budget is below what was spent
+	Update(ctx context.Context, campaign *Campaign) error
+	// Open fetches the campaigns of a condition running at a time with budget
+	// left for a bonus that the user has not been awarded
+	Open(ctx context.Context, condition string, userID int, at time.Time) ([]*Campaign, error)
+	// IsFirstDeposit reports whether a credit is the user's first, not
+	// counting bonuses
+	IsFirstDeposit(ctx context.Context, userID, transactionID int) (bool, error)
+	// Volume sums the completed transactions a user paid since a time, not
+	// counting bonuses
+	Volume(ctx context.Context, userID int, since time.Time) (float64, error)
+	// Award records a bonus and charges it to the campaign's budget. It
+	// returns ErrCampaignBonusAwarded if the user already has it, and
+	// ErrCampaignBudgetExhausted if the budget can't pay it.
+	Award(ctx context.Context, award *CampaignAward) error
+	// Stats summarizes a campaign's awards
+	Stats(ctx context.Context, campaignID int) (*CampaignStats, error)
+}
+
+// CampaignService defines business logic for campaigns
+type CampaignService interface {
+	TransactionRewarder
+	// List returns every campaign, newest first
+	List(ctx context.Context) ([]*Campaign, error)
+	// Get returns a campaign, or ErrCampaignNotFound
+	Get(ctx context.Context, id int) (*Campaign, error)
+	// Create creates a campaign on behalf of actorID
+	Create(ctx context.Context, actorID int, campaign *Campaign) error
+	// Update changes a campaign's name, budget and end on behalf of actorID
+	Update(ctx context.Context, actorID int, campaign *Campaign) error
+	// Stats returns how a campaign performed
+	Stats(ctx context.Context, id int) (*CampaignStats, error)
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// SignupCohort is the users who signed up in one month and how many of them kept transacting.
+type SignupCohort struct {
+	Month       time.Time `json:"month"`
+	Signups     int       `json:"signups"`
+	Eligible7d  int       `json:"eligible_7d"`
+	Retained7d  int       `json:"retained_7d"`
+	Eligible30d int       `json:"eligible_30d"`
+	Retained30d int       `json:"retained_30d"`
+	ComputedAt  time.Time `json:"computed_at"`
+}
+
+// Retention7d returns the percentage of eligible users retained at 7 days
+func (c *SignupCohort) Retention7d() float64 {
+	return percentOf(c.Retained7d, c.Eligible7d)
+}
+
+// Retention30d returns the percentage of eligible users retained at 30 days
+func (c *SignupCohort) Retention30d() float64 {
+	return percentOf(c.Retained30d, c.Eligible30d)
+}
+
+// MonthlyGrowth is the number of new and transacting users in a month
+type MonthlyGrowth struct {
+	Month       time.Time `json:"month"`
+	NewUsers    int       `json:"new_users"`
+	ActiveUsers int       `json:"active_users"`
+	ComputedAt  time.Time `json:"computed_at"`
+}
+
+// CohortReport is the cohort retention and month-over-month growth, oldest month first.
+type CohortReport struct {
+	Cohorts []*CohortRetention `json:"cohorts"`
+	Growth  []*GrowthRate      `json:"growth"`
+}
+
+// CohortRetention is a signup cohort with its retention rates
+type CohortRetention struct {
+	*SignupCohort
+	Retention7dPercent  float64 `json:"retention_7d_percent"`
+	Retention30dPercent float64 `json:"retention_30d_percent"`
+}
+
+// GrowthRate is a month's growth with its rates relative to the previous month
+type GrowthRate struct {
+	*MonthlyGrowth
+	NewUsersGrowthPercent    *float64 `json:"new_users_growth_percent"`
+	ActiveUsersGrowthPercent *float64 `json:"active_users_growth_percent"`
+}
+
+// CohortRepository defines the interface for cohort analytics data access
+type CohortRepository interface {
+	// RefreshCohorts recomputes the cohorts of every month since the given one
+	RefreshCohorts(ctx context.Context, since time.Time) error
+
+	// RefreshGrowth recomputes the growth of every month since the given one
+	RefreshGrowth(ctx context.Context, since time.Time) error
+
+	// ListCohorts retrieves the stored cohorts since the given month, oldest first
+	ListCohorts(ctx context.Context, since time.Time) ([]*SignupCohort, error)
+
+	// ListGrowth retrieves the stored growth since the given month, oldest first
+	ListGrowth(ctx context.Context, since time.Time) ([]*MonthlyGrowth, error)
+}
+
+// CohortService defines business logic for cohort retention and growth analytics
+type CohortService interface {
+	// Refresh recomputes the summary tables
+	Refresh(ctx context.Context) error
+
+	// GetReport retrieves the last months of cohorts and growth, the current month included
+	GetReport(ctx context.Context, months int) (*CohortReport, error)
+
+	// Start begins refreshing periodically in the background
+	Start(ctx context.Context)
+
+	// Stop stops the background refresh
+	Stop()
+}
+
+func percentOf(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Step-up verification of logins from untrusted devices
+const (
+	// LoginChallengeTTL is how long a login verification code stays valid
+	LoginChallengeTTL = 10 * time.Minute
+	// MaxLoginChallengeAttempts bounds the codes tried against one challenge
+	MaxLoginChallengeAttempts = 5
+	// LoginChallengeCodeDigits is the length of a login verification code
+	LoginChallengeCodeDigits = 6
+	// StepUpMethodEmail sends the verification code to the account's email
+	StepUpMethodEmail = "email"
+)
+
+var (
+	// ErrDeviceNotFound is returned when a user has no trusted device with a fingerprint
+	ErrDeviceNotFound = NewError(ErrorKindNotFound, "device_not_found", "trusted device not found")
+	// ErrLoginChallengeInvalid is returned for an unknown, expired or used-up login challenge,
+	// or one answered from another device than the login it belongs to
+	ErrLoginChallengeInvalid = NewError(ErrorKindForbidden, "login_challenge_invalid", "login verification expired or invalid; log in again")
+	// ErrWrongLoginCode is returned when a login verification code doesn't match
+	ErrWrongLoginCode = NewError(ErrorKindForbidden, "wrong_login_code", "verification code is incorrect")
+)
+
+// Device is a device a user has logged in from and trusts.
+type Device struct {
+	Fingerprint string    `json:"fingerprint"`
+	UserAgent   string    `json:"user_agent"`
+	FirstSeenAt time.Time `json:"first_seen_at"` // when the device became trusted
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// LoginChallenge is a login from an untrusted device waiting for its code.
+type LoginChallenge struct {
+	ID          string
+	UserID      int
+	Fingerprint string
+	UserAgent   string
+	CodeHash    string
+	Attempts    int
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+}
+
+// HashLoginCode hashes a verification code for storage.
+func HashLoginCode(challengeID, code string) string {
+	sum := sha256.Sum256([]byte(challengeID + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// StepUpRequiredError is returned by a login with the right password from an untrusted device.
+type StepUpRequiredError struct {
+	ChallengeID string
+	Method      string
+	ExpiresAt   time.Time
+}
+
+func (e *StepUpRequiredError) Error() string {
+	return "login from an unrecognized device must be verified"
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Dispute statuses.
+const (
+	DisputeOpen          = "open"
+	DisputeInvestigating = "investigating"
+	DisputeResolved      = "resolved" // closed without a refund
+	DisputeRefunded      = "refunded" // closed by reversing the payment
+)
+
+// DisputeReasons lists why a payment can be disputed
+var DisputeReasons = []string{"unauthorized", "not_received", "duplicate", "incorrect_amount", "other"}
+
+// Bounds of a dispute's free text
+const (
+	maxDisputeDescription = 2000
+	maxDisputeDocuments   = 10
+)
+
+var (
+	// ErrDisputeNotFound is returned when a dispute does not exist
+	ErrDisputeNotFound = NewError(ErrorKindNotFound, "dispute_not_found", "dispute not found")
+	// ErrDisputeTransactionNotFound is returned when disputing a transaction
+	// that does not exist
+	ErrDisputeTransactionNotFound = NewError(ErrorKindNotFound, "transaction_not_found", "transaction not found")
+	// ErrDisputeNotPayer is returned when disputing a transaction the user
+	// didn't pay
+	ErrDisputeNotPayer = NewError(ErrorKindForbidden, "dispute_not_payer", "only the payer can dispute a transaction")
+	// ErrDisputeExists is returned when disputing a transaction twice
+	ErrDisputeExists = NewError(ErrorKindConflict, "dispute_exists", "transaction has already been disputed")
+	// ErrDisputeWindowPassed is returned when disputing a transaction older
+	// than the dispute window
+	ErrDisputeWindowPassed = NewError(ErrorKindConflict, "dispute_window_passed", "transaction is too old to dispute")
+	// ErrDisputeClosed is returned when changing a dispute that is already
+	// closed, or moving it to a status it can't reach
+	ErrDisputeClosed = NewError(ErrorKindConflict, "dispute_closed", "dispute can't move to that status")
+)
+
+// Dispute is a user's claim that a payment they made was wrong.
+type Dispute struct {
+	ID                  int        `json:"id"`
+	TransactionID       int        `json:"transaction_id"`
+	UserID              int        `json:"user_id"`
+	Reason              string     `json:"reason"`
+	Description         string     `json:"description"`
+	DocumentIDs         []int      `json:"document_ids"`
+	Status              string     `json:"status"`
+	Resolution          string     `json:"resolution,omitempty"`
+	HandledBy           *int       `json:"handled_by,omitempty"`
+	RefundTransactionID *int       `json:"refund_transaction_id,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	ClosedAt            *time.Time `json:"closed_at,omitempty"`
+}
+
+// IsOpen reports whether the dispute is still being handled
+func (d *Dispute) IsOpen() bool {
+	return d.Status == DisputeOpen || d.Status == DisputeInvestigating
+}
+
+// DisputeClaim is what a user submits to dispute a transaction
+type DisputeClaim struct {
+	Reason      string `json:"reason"`
+	Description string `json:"description"`
+	DocumentIDs []int  `json:"document_ids"`
+}
+
+// Validate checks the claim's reason, description and number of documents
+func (c *DisputeClaim) Validate() error {
+	known := false
+	for _, r := range DisputeReasons {
+		known = known || c.Reason == r
+	}
+	if !known {
+		return &ValidationError{Msg: "reason must be unauthorized, not_received, duplicate, incorrect_amount or other"}
+	}
+	if n := utf8.RuneCountInString(strings.TrimSpace(c.Description)); n == 0 || n > maxDisputeDescription {
+		return &ValidationError{Msg: "description must be 1 to 2000 characters"}
+	}
+	if len(c.DocumentIDs) > maxDisputeDocuments {
+		return &ValidationError{Msg: "at most 10 documents can be attached"}
+	}
+	return nil
+}
+
+// DisputeRepository defines methods for dispute data access
+type DisputeRepository interface {
+	// Create stores a dispute, returning ErrDisputeExists if its transaction
+	// was already disputed
+	Create(ctx context.Context, dispute *Dispute) error
+	// GetByID fetches a dispute, or nil if there is none
+	GetByID(ctx context.Context, id int) (*Dispute, error)
+	// ListByUser fetches a page of a user's disputes, newest first
+	ListByUser(ctx context.Context, userID, limit, offset int) ([]*Dispute, error)
+	// ListByStatus fetches a page of the disputes with a status, oldest first
+	ListByStatus(ctx context.Context, status string, limit, offset int) ([]*Dispute, error)
+	// UpdateStatus stores a dispute's status, resolution, handler and refund
+	// if it is still open, returning ErrDisputeClosed otherwise
+	UpdateStatus(ctx context.Context, dispute *Dispute) error
+}
+
+// DisputeService defines business logic for transaction disputes
+type DisputeService interface {
+	// Open disputes a transaction userID paid
+	Open(ctx context.Context, userID, transactionID int, claim *DisputeClaim) (*Dispute, error)
+	// Get returns a dispute if userID opened it or isAdmin is set
+	Get(ctx context.Context, id, userID int, isAdmin bool) (*Dispute, error)
+	// ListByUser returns a page of a user's disputes, newest first
+	ListByUser(ctx context.Context, userID, limit, offset int) ([]*Dispute, error)
+	// ListByStatus returns a page of the disputes with a status, oldest first
+	ListByStatus(ctx context.Context, status string, limit, offset int) ([]*Dispute, error)
+	// Investigate marks an open dispute as being investigated by actorID
+	Investigate(ctx context.Context, id, actorID int) (*Dispute, error)
+	// Resolve closes a dispute without a refund
+	Resolve(ctx context.Context, id, actorID int, resolution string) (*Dispute, error)
+	// Refund closes a dispute by reversing the disputed payment
+	Refund(ctx context.Context, id, actorID int, resolution string) (*Dispute, error)
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Document scan statuses
+const (
+	DocumentScanClean     = "clean"     // the virus scanner found nothing
+	DocumentScanUnscanned = "unscanned" // no virus scanner is configured
+)
+
+// DocumentKinds lists what users may upload documents as
+var DocumentKinds = []string{"identity", "proof_of_address", "bank_statement", "other"}
+
+// DocumentExtensions maps the accepted document content types to file extensions.
+var DocumentExtensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+}
+
+var (
+	// ErrDocumentUserNotFound is returned when uploading for a user that does not exist
+	ErrDocumentUserNotFound = NewError(ErrorKindNotFound, "user_not_found", "user not found")
+	// ErrDocumentNotFound is returned when a document does not exist
+	ErrDocumentNotFound = NewError(ErrorKindNotFound, "document_not_found", "document not found")
+	// ErrDocumentInfected is returned when the virus scanner flags an upload
+	ErrDocumentInfected = NewError(ErrorKindValidation, "document_infected", "document failed the virus scan")
+	// ErrDocumentScanUnavailable is returned when an upload can't be scanned;
+	// unscanned uploads are refused rather than stored
+	ErrDocumentScanUnavailable = NewError(ErrorKindUnavailable, "document_scan_unavailable", "virus scanner is unavailable, try again later")
+	// ErrDocumentLinkInvalid is returned for a download URL that wasn't issued
+	// by the service
+	ErrDocumentLinkInvalid = NewError(ErrorKindNotFound, "document_link_invalid", "download link is invalid")
+	// ErrDocumentLinkExpired is returned for a download URL past its expiry
+	ErrDocumentLinkExpired = NewError(ErrorKindGone, "document_link_expired", "download link has expired")
+)
+
+// Document is the metadata of a file a user uploaded.
+type Document struct {
+	ID          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	Kind        string    `json:"kind"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int       `json:"size_bytes"`
+	SHA256      string    `json:"sha256"`
+	ScanStatus  string    `json:"scan_status"`
+	UploadedBy  int       `json:"uploaded_by"`
+	ObjectKey   string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DocumentUpload is a file a user uploads, with the kind and name they gave it
+type DocumentUpload struct {
+	Kind     string
+	Filename string
+	Data     []byte
+}
+
+// DocumentLink is a signed token granting the download of a document until it expires
+type DocumentLink struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// VirusScanner checks uploaded files for malware
+type VirusScanner interface {
+	// Scan returns the name of the threat found in data, or "" if it is clean
+	Scan(ctx context.Context, data []byte) (string, error)
+}
+
+// DocumentRepository defines methods for document metadata access
+type DocumentRepository interface {
+	Create(ctx context.Context, document *Document) error
+	// GetByID fetches a document, or nil if there is none
+	GetByID(ctx context.Context, id int) (*Document, error)
+	// ListByUser fetches a page of a user's documents, newest first
+	ListByUser(ctx context.Context, userID, limit, offset int) ([]*Document, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// DocumentService defines business logic for user documents
+type DocumentService interface {
+	// Upload validates, scans and stores a file for userID on behalf of actorID
+	Upload(ctx context.Context, userID, actorID int, upload *DocumentUpload) (*Document, error)
+	// List returns a page of a user's documents, newest first
+	List(ctx context.Context, userID, limit, offset int) ([]*Document, error)
+	// Get returns one of a user's documents, or ErrDocumentNotFound
+	Get(ctx context.Context, userID, id int) (*Document, error)
+	// Link signs a download link for a document
+	Link(document *Document) *DocumentLink
+	// Download returns the document a link grants and its contents
+	Download(ctx context.Context, token string) (*Document, []byte, error)
+	// Delete removes one of a user's documents and its file
+	Delete(ctx context.Context, userID, id int) error
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "errors"
+
+// ErrorKind classifies a domain error.
+type ErrorKind string
+
+// Error kinds
+const (
+	ErrorKindInternal          ErrorKind = "internal" // anything unexpected; never shown to clients
+	ErrorKindValidation        ErrorKind = "validation"
+	ErrorKindUnauthorized      ErrorKind = "unauthorized"
+	ErrorKindNotFound          ErrorKind = "not_found"
+	ErrorKindForbidden         ErrorKind = "forbidden"
+	ErrorKindConflict          ErrorKind = "conflict"
+	ErrorKindGone              ErrorKind = "gone"
+	ErrorKindInsufficientFunds ErrorKind = "insufficient_funds"
+	ErrorKindLimitExceeded     ErrorKind = "limit_exceeded"
+	ErrorKindUnavailable       ErrorKind = "unavailable"
+	ErrorKindPending           ErrorKind = "pending" // accepted, but waiting on someone else
+)
+
+// Error is a domain error with a stable, machine-readable code such as "hold_not_found".
+type Error struct {
+	Kind ErrorKind
+	Code string
+	Msg  string
+}
+
+// NewError creates an Error
+func NewError(kind ErrorKind, code, msg string) *Error {
+	return &Error{Kind: kind, Code: code, Msg: msg}
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+// ErrInsufficientFunds is returned when a balance cannot cover an amount
+var ErrInsufficientFunds = NewError(ErrorKindInsufficientFunds, "insufficient_funds", "insufficient balance")
+
+// ValidationError is a custom error type for validation failures
+type ValidationError struct {
+	Msg string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Msg
+}
+
+// ClassifyError returns the kind and code of the first domain error in err's chain.
+func ClassifyError(err error) (ErrorKind, string) {
+	var domainErr *Error
+	var valErr *ValidationError
+	var limitErr *LimitExceededError
+	switch {
+	case errors.As(err, &domainErr):
+		return domainErr.Kind, domainErr.Code
+	case errors.As(err, &valErr):
+		return ErrorKindValidation, "validation_failed"
+	case errors.As(err, &limitErr):
+		return ErrorKindLimitExceeded, "limit_exceeded"
+	default:
+		return ErrorKindInternal, "internal_error"
+	}
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// maxFeeTiers bounds the volume tiers of one fee schedule
+const maxFeeTiers = 20
+
+var (
+	// ErrFeeScheduleNotFound is returned when a fee schedule does not exist
+	ErrFeeScheduleNotFound = NewError(ErrorKindNotFound, "fee_schedule_not_found", "fee schedule not found")
+	// ErrFeeScheduleInEffect is returned when a schedule that took effect is
+	// changed or deleted; a new version has to be created instead
+	ErrFeeScheduleInEffect = NewError(ErrorKindConflict, "fee_schedule_in_effect", "fee schedule is already in effect; create a new version instead")
+	// ErrFeeScheduleExists is returned when a schedule already takes effect at
+	// the same time for the same transaction type and role
+	ErrFeeScheduleExists = NewError(ErrorKindConflict, "fee_schedule_exists", "a fee schedule already takes effect at this time")
+)
+
+// FeeTier is the fee charged once the payer's volume of the month reaches MinVolume
+type FeeTier struct {
+	MinVolume float64 `json:"min_volume"`
+	Percent   float64 `json:"percent"`
+	Fixed     float64 `json:"fixed"`
+}
+
+// FeeSchedule sets the fees of a transaction type from EffectiveFrom on.
+type FeeSchedule struct {
+	ID              int       `json:"id"`
+	TransactionType string    `json:"transaction_type"` // credit, debit or transfer
+	Role            string    `json:"role,omitempty"`   // empty for every role
+	EffectiveFrom   time.Time `json:"effective_from"`
+	Tiers           []FeeTier `json:"tiers"` // by MinVolume, the first from 0
+	CreatedBy       int       `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Validate checks the schedule's transaction type, role and tiers
+func (s *FeeSchedule) Validate() error {
+	if s.TransactionType != "credit" && s.TransactionType != "debit" && s.TransactionType != "transfer" {
+		return &ValidationError{Msg: "transaction_type must be credit, debit or transfer"}
+	}
+	if s.Role != "" && s.Role != "user" && s.Role != "admin" {
+		return &ValidationError{Msg: "role must be user or admin"}
+	}
+	if len(s.Tiers) == 0 || len(s.Tiers) > maxFeeTiers {
+		return &ValidationError{Msg: "a fee schedule needs 1 to 20 tiers"}
+	}
+	for i, tier := range s.Tiers {
+		if i == 0 && tier.MinVolume != 0 {
+			return &ValidationError{Msg: "the first tier must start at volume 0"}
+		}
+		if i > 0 && tier.MinVolume <= s.Tiers[i-1].MinVolume {
+			return &ValidationError{Msg: "tiers must be ordered by increasing min_volume"}
+		}
+		if tier.Percent < 0 || tier.Percent > 100 {
+			return &ValidationError{Msg: "percent must be between 0 and 100"}
+		}
+		if tier.Fixed < 0 {
+			return &ValidationError{Msg: "fixed fee must not be negative"}
+		}
+	}
+	return nil
+}
+
+// Fee returns the fee for amount given the payer's volume of the month, in cents.
+func (s *FeeSchedule) Fee(amount, volume float64) float64 {
+	var tier FeeTier
+	for _, t := range s.Tiers {
+		if volume < t.MinVolume {
+			break
+		}
+		tier = t
+	}
+	fee := math.Round((tier.Fixed+amount*tier.Percent/100)*100) / 100
+	return math.Min(fee, amount)
+}
+
+// FeeQuote is the fee a transaction is charged and the schedule setting it
+type FeeQuote struct {
+	Amount     float64
+	ScheduleID int
+}
+
+// FeeCalculator prices transactions by the fee schedules in effect
+type FeeCalculator interface {
+	// Quote returns the fee of a transaction of txType paid by payerID, or
+	// nil if no schedule applies
+	Quote(ctx context.Context, txType string, payerID int, amount float64) (*FeeQuote, error)
+}
+
+// FeeScheduleRepository defines methods for fee schedule data access
+type FeeScheduleRepository interface {
+	// List fetches the schedules, of txType unless it is empty, newest version first
+	List(ctx context.Context, txType string) ([]*FeeSchedule, error)
+	// GetByID fetches a schedule, or nil if there is none
+	GetByID(ctx context.Context, id int) (*FeeSchedule, error)
+	// Create stores a schedule, returning ErrFeeScheduleExists if its
+	// version is taken
+	Create(ctx context.Context, schedule *FeeSchedule) error
+	// Update changes a schedule's effective time and tiers
+	Update(ctx context.Context, schedule *FeeSchedule) error
+	// Delete removes a schedule
+	Delete(ctx context.Context, id int) error
+	// InEffect fetches the schedule applying at a time to transactions of
+	// txType by users of role, preferring one for the role, or nil if none does
+	InEffect(ctx context.Context, txType, role string, at time.Time) (*FeeSchedule, error)
+	// MonthlyVolume sums the completed transactions of txType a user paid since
+	MonthlyVolume(ctx context.Context, userID int, txType string, since time.Time) (float64, error)
+}
+
+// FeeScheduleService defines business logic for fee schedules
+type FeeScheduleService interface {
+	FeeCalculator
+	// List returns the schedules, of txType unless it is empty, newest version first
+	List(ctx context.Context, txType string) ([]*FeeSchedule, error)
+	// Get returns a schedule, or ErrFeeScheduleNotFound
+	Get(ctx context.Context, id int) (*FeeSchedule, error)
+	// Create creates a schedule version on behalf of actorID
+	Create(ctx context.Context, actorID int, schedule *FeeSchedule) error
+	// Update changes a schedule not yet in effect on behalf of actorID
+	Update(ctx context.Context, actorID int, schedule *FeeSchedule) error
+	// Delete deletes a schedule not yet in effect on behalf of actorID
+	Delete(ctx context.Context, actorID, id int) error
+}
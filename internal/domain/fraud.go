@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Fraud decisions, from least to most severe
+const (
+	FraudDecisionAllow  = "allow"
+	FraudDecisionFlag   = "flag"   // runs, but is queued for review
+	FraudDecisionHold   = "hold"   // held for approval and queued for review
+	FraudDecisionReject = "reject" // never runs
+)
+
+var (
+	// ErrFraudAssessmentNotFound is returned when a fraud assessment does not exist
+	ErrFraudAssessmentNotFound = NewError(ErrorKindNotFound, "fraud_assessment_not_found", "fraud assessment not found")
+	// ErrFraudRejected is returned when a transaction scores at or above the reject threshold
+	ErrFraudRejected = NewError(ErrorKindForbidden, "fraud_rejected", "transaction rejected by fraud screening")
+)
+
+// FraudAssessment is the fraud score a debit or transfer got before it ran.
+type FraudAssessment struct {
+	ID             int        `json:"id"`
+	TransactionID  *int       `json:"transaction_id,omitempty"`
+	UserID         int        `json:"user_id"`
+	CounterpartyID *int       `json:"counterparty_id,omitempty"`
+	Type           string     `json:"type"`
+	Amount         float64    `json:"amount"`
+	Score          float64    `json:"score"` // 0-100
+	Reasons        []string   `json:"reasons"`
+	Decision       string     `json:"decision"`
+	ReviewStatus   string     `json:"review_status,omitempty"` // "", "pending", "cleared", "confirmed"
+	ReviewedBy     *int       `json:"reviewed_by,omitempty"`
+	ReviewNote     string     `json:"review_note,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// Resolve records an admin's verdict on a queued assessment: confirmed fraud or cleared
+func (a *FraudAssessment) Resolve(reviewerID int, confirmed bool, note string) error {
+	if a.ReviewStatus != "pending" {
+		return &ValidationError{Msg: "fraud assessment is not awaiting review"}
+	}
+	now := time.Now().UTC()
+	a.ReviewStatus = "cleared"
+	if confirmed {
+		a.ReviewStatus = "confirmed"
+	}
+	a.ReviewedBy = &reviewerID
+	a.ReviewNote = note
+	a.ReviewedAt = &now
+	return nil
+}
+
+// FraudSignals is the account history a transaction is scored against
+type FraudSignals struct {
+	// Debits and transfers the user started within the velocity window, any status
+	RecentCount int
+	// Completed debits and transfers within the history window and their average amount
+	HistoryCount   int
+	HistoryAverage float64
+	// Whether the user has completed a transfer to the counterparty before
+	KnownCounterparty bool
+}
+
+// FraudRepository defines the interface for fraud assessment data access
+type FraudRepository interface {
+	// Signals gathers the user's history; counterpartyID is nil for debits
+	Signals(ctx context.Context, userID int, counterpartyID *int, velocitySince, historySince time.Time) (*FraudSignals, error)
+
+	// Create stores a new assessment
+	Create(ctx context.Context, assessment *FraudAssessment) error
+
+	// LinkTransaction records the transaction an assessment was made for
+	LinkTransaction(ctx context.Context, id, transactionID int) error
+
+	// GetByID retrieves an assessment, or nil if it does not exist
+	GetByID(ctx context.Context, id int) (*FraudAssessment, error)
+
+	// ListPendingReview retrieves assessments awaiting review, oldest first
+	ListPendingReview(ctx context.Context, limit, offset int) ([]*FraudAssessment, error)
+
+	// UpdateReview stores the review fields of an assessment
+	UpdateReview(ctx context.Context, assessment *FraudAssessment) error
+}
+
+// FraudService defines business logic for scoring transactions and reviewing the scores
+type FraudService interface {
+	// Assess scores a debit or transfer before it runs and stores the score.
+	// The caller acts on the returned decision.
+	Assess(ctx context.Context, tx *Transaction) (*FraudAssessment, error)
+
+	// LinkTransaction records the transaction created for an assessment
+	LinkTransaction(ctx context.Context, assessmentID, transactionID int) error
+
+	// ListReviewQueue retrieves flagged and held assessments awaiting review
+	ListReviewQueue(ctx context.Context, limit, offset int) ([]*FraudAssessment, error)
+
+	// Resolve records an admin's verdict on a queued assessment
+	Resolve(ctx context.Context, id, reviewerID int, confirmed bool, note string) (*FraudAssessment, error)
+}
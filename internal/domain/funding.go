@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Funding source kinds
+const (
+	FundingSourceCard        = "card"
+	FundingSourceBankAccount = "bank_account"
+)
+
+// Funding source statuses.
+const (
+	FundingSourceActive  = "active"
+	FundingSourceRemoved = "removed"
+)
+
+// Chargeback statuses.
+const (
+	ChargebackReceived = "received"
+	ChargebackDisputed = "disputed" // contested with the provider
+	ChargebackWon      = "won"      // the provider returned the funds
+	ChargebackLost     = "lost"     // the funds stay charged back
+)
+
+var (
+	// ErrFundingUserNotFound is returned when the user of a funding source
+	// does not exist
+	ErrFundingUserNotFound = NewError(ErrorKindNotFound, "user_not_found", "user not found")
+	// ErrFundingSourceNotFound is returned when a funding source does not exist
+	ErrFundingSourceNotFound = NewError(ErrorKindNotFound, "funding_source_not_found", "funding source not found")
+	// ErrFundingSourceExists is returned when adding a source the provider
+	// reference of which is already known
+	ErrFundingSourceExists = NewError(ErrorKindConflict, "funding_source_exists", "funding source already exists")
+	// ErrFundingSourceRemoved is returned when funding a credit from a
+	// removed source
+	ErrFundingSourceRemoved = NewError(ErrorKindConflict, "funding_source_removed", "funding source has been removed")
+	// ErrFundedCreditNotFound is returned when charging back a transaction
+	// that is not a funded credit
+	ErrFundedCreditNotFound = NewError(ErrorKindNotFound, "funded_credit_not_found", "funded credit not found")
+	// ErrChargebackNotFound is returned when a chargeback does not exist
+	ErrChargebackNotFound = NewError(ErrorKindNotFound, "chargeback_not_found", "chargeback not found")
+	// ErrChargebackExists is returned when a credit or provider chargeback ID
+	// was already charged back
+	ErrChargebackExists = NewError(ErrorKindConflict, "chargeback_exists", "credit has already been charged back")
+	// ErrChargebackClosed is returned when moving a chargeback to a status it
+	// can't reach from its current one
+	ErrChargebackClosed = NewError(ErrorKindConflict, "chargeback_closed", "chargeback can't move to that status")
+	// ErrGatewayRefundExists is returned when a provider's refund was
+	// already received
+	ErrGatewayRefundExists = NewError(ErrorKindConflict, "gateway_refund_exists", "refund has already been received")
+)
+
+// ChargebackPolicy decides what a chargeback takes from its user.
+type ChargebackPolicy struct {
+	AllowNegativeBalance bool
+	FreezeThreshold      float64
+}
+
+// FundingSource is a card or bank account outside the system that a user funds their balance from.
+type FundingSource struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	Kind        string     `json:"kind"`
+	Provider    string     `json:"provider"`
+	ExternalRef string     `json:"external_ref"`
+	Last4       string     `json:"last4,omitempty"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RemovedAt   *time.Time `json:"removed_at,omitempty"`
+}
+
+// Validate checks the source's kind, provider, reference and last four digits
+func (s *FundingSource) Validate() error {
+	if s.Kind != FundingSourceCard && s.Kind != FundingSourceBankAccount {
+		return &ValidationError{Msg: "kind must be card or bank_account"}
+	}
+	if s.Provider == "" || len(s.Provider) > 20 {
+		return &ValidationError{Msg: "provider must be 1 to 20 characters"}
+	}
+	if s.ExternalRef == "" || len(s.ExternalRef) > 255 {
+		return &ValidationError{Msg: "external_ref must be 1 to 255 characters"}
+	}
+	if s.Last4 != "" && (len(s.Last4) != 4 || strings.Trim(s.Last4, "0123456789") != "") {
+		return &ValidationError{Msg: "last4 must be four digits"}
+	}
+	return nil
+}
+
+// FundedCredit links a credit transaction to the funding source that paid it.
+type FundedCredit struct {
+	TransactionID   int        `json:"transaction_id"`
+	FundingSourceID int        `json:"funding_source_id"`
+	UserID          int        `json:"user_id"`
+	ExternalID      string     `json:"external_id"`
+	Amount          float64    `json:"amount"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ReversedAt      *time.Time `json:"reversed_at,omitempty"`
+}
+
+// ChargebackNotice is a provider's notice that a funded credit was charged back.
+type ChargebackNotice struct {
+	TransactionID int     `json:"transaction_id"`
+	ExternalID    string  `json:"external_id"`
+	ReasonCode    string  `json:"reason_code"`
+	Amount        float64 `json:"amount"`
+}
+
+// Chargeback is a funded credit taken back by the provider of its funding source.
+type Chargeback struct {
+	ID                     int                `json:"id"`
+	TransactionID          int                `json:"transaction_id"`
+	UserID                 int                `json:"user_id"`
+	ExternalID             string             `json:"external_id"`
+	ReasonCode             string             `json:"reason_code"`
+	Amount                 float64            `json:"amount"`
+	Recovered              float64            `json:"recovered"`
+	Status                 string             `json:"status"`
+	DebitTransactionID     *int               `json:"debit_transaction_id,omitempty"`
+	ReinstateTransactionID *int               `json:"reinstate_transaction_id,omitempty"`
+	FrozeAccount           bool               `json:"froze_account"`
+	CreatedAt              time.Time          `json:"created_at"`
+	UpdatedAt              time.Time          `json:"updated_at"`
+	ClosedAt               *time.Time         `json:"closed_at,omitempty"`
+	Events                 []*ChargebackEvent `json:"events,omitempty"`
+}
+
+// IsOpen reports whether the chargeback can still be won or lost
+func (c *Chargeback) IsOpen() bool {
+	return c.Status == ChargebackReceived || c.Status == ChargebackDisputed
+}
+
+// ChargebackEvent records a chargeback reaching a status.
+type ChargebackEvent struct {
+	ID           int       `json:"id"`
+	ChargebackID int       `json:"chargeback_id"`
+	Status       string    `json:"status"`
+	Note         string    `json:"note,omitempty"`
+	ActorID      *int      `json:"actor_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// GatewayRefund is a refund of a gateway payment, taken back like a chargeback.
+type GatewayRefund struct {
+	ID                  int       `json:"id"`
+	Provider            string    `json:"provider"`
+	ChargeID            string    `json:"charge_id"`
+	RefundID            string    `json:"refund_id"`
+	UserID              int       `json:"user_id"`
+	CreditTransactionID int       `json:"credit_transaction_id"`
+	Amount              float64   `json:"amount"`
+	Recovered           float64   `json:"recovered"`
+	DebitTransactionID  *int      `json:"debit_transaction_id,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// FundingRepository defines methods for funding source, funded credit and chargeback data access
+type FundingRepository interface {
+	// CreateSource stores a funding source, returning ErrFundingSourceExists
+	// if its provider reference is already known
+	CreateSource(ctx context.Context, source *FundingSource) error
+	// GetSource fetches a funding source, or nil if there is none
+	GetSource(ctx context.Context, id int) (*FundingSource, error)
+	// ListSources fetches a user's funding sources, newest first
+	ListSources(ctx context.Context, userID int) ([]*FundingSource, error)
+	// RemoveSource marks an active funding source removed
+	RemoveSource(ctx context.Context, id int) error
+	// CreateCredit links a credit to its funding source; linking the same
+	// payment again does nothing
+	CreateCredit(ctx context.Context, credit *FundedCredit) error
+	// GetCredit fetches the funded credit of a transaction, or nil if it
+	// wasn't funded from a source
+	GetCredit(ctx context.Context, transactionID int) (*FundedCredit, error)
+	// SetCreditReversed marks a funded credit reversed or, with reversed
+	// false, reinstated
+	SetCreditReversed(ctx context.Context, transactionID int, reversed bool) error
+	// CreateChargeback stores a chargeback, returning ErrChargebackExists if
+	// its credit or provider ID was already charged back
+	CreateChargeback(ctx context.Context, chargeback *Chargeback) error
+	// GetChargeback fetches a chargeback with its events, or nil if there is none
+	GetChargeback(ctx context.Context, id int) (*Chargeback, error)
+	// ListChargebacksByUser fetches a page of a user's chargebacks, newest first
+	ListChargebacksByUser(ctx context.Context, userID, limit, offset int) ([]*Chargeback, error)
+	// ListChargebacksByStatus fetches a page of the chargebacks with a
+	// status, oldest first
+	ListChargebacksByStatus(ctx context.Context, status string, limit, offset int) ([]*Chargeback, error)
+	// UpdateChargeback stores a chargeback's status and transactions if it
+	// is still open, returning ErrChargebackClosed otherwise
+	UpdateChargeback(ctx context.Context, chargeback *Chargeback) error
+	// AddChargebackEvent records a chargeback reaching a status
+	AddChargebackEvent(ctx context.Context, event *ChargebackEvent) error
+	// SumChargebacks totals the amounts of a user's chargebacks that weren't won
+	SumChargebacks(ctx context.Context, userID int) (float64, error)
+	// CreateGatewayRefund stores a gateway refund, returning
+	// ErrGatewayRefundExists if the provider's refund was already stored
+	CreateGatewayRefund(ctx context.Context, refund *GatewayRefund) error
+	// GetGatewayRefund fetches a provider's refund, or nil if there is none
+	GetGatewayRefund(ctx context.Context, provider, refundID string) (*GatewayRefund, error)
+	// SumGatewayRefunds totals the amounts refunded of a provider's payment
+	SumGatewayRefunds(ctx context.Context, provider, chargeID string) (float64, error)
+}
+
+// FundingService defines business logic for funding sources and chargebacks.
+type FundingService interface {
+	// AddSource registers a funding source of userID
+	AddSource(ctx context.Context, userID int, source *FundingSource) (*FundingSource, error)
+	// ListSources returns a user's funding sources, newest first
+	ListSources(ctx context.Context, userID int) ([]*FundingSource, error)
+	// RemoveSource removes one of userID's funding sources
+	RemoveSource(ctx context.Context, userID, id int) error
+	// Fund credits userID with a payment from one of their funding sources;
+	// repeating a payment's externalID returns its credit
+	Fund(ctx context.Context, userID, sourceID int, amount float64, externalID string) (*Transaction, error)
+	// ReceiveChargeback takes a funded credit back from its user
+	ReceiveChargeback(ctx context.Context, actorID *int, notice *ChargebackNotice) (*Chargeback, error)
+	// ReceiveRefund takes a refund of the gateway payment credit made back
+	// from its user, as a chargeback is; receiving a refund again returns it
+	ReceiveRefund(ctx context.Context, credit *Transaction, refund *GatewayRefund) (*GatewayRefund, error)
+	// GetChargeback returns a chargeback with its events
+	GetChargeback(ctx context.Context, id int) (*Chargeback, error)
+	// ListChargebacksByUser returns a page of a user's chargebacks, newest first
+	ListChargebacksByUser(ctx context.Context, userID, limit, offset int) ([]*Chargeback, error)
+	// ListChargebacksByStatus returns a page of the chargebacks with a
+	// status, oldest first
+	ListChargebacksByStatus(ctx context.Context, status string, limit, offset int) ([]*Chargeback, error)
+	// DisputeChargeback records that a received chargeback is contested
+	DisputeChargeback(ctx context.Context, id int, actorID *int, note string) (*Chargeback, error)
+	// WinChargeback closes a chargeback by giving the recovered funds back
+	WinChargeback(ctx context.Context, id int, actorID *int, note string) (*Chargeback, error)
+	// LoseChargeback closes a chargeback, leaving the funds charged back
+	LoseChargeback(ctx context.Context, id int, actorID *int, note string) (*Chargeback, error)
+	// Unfreeze lifts the freeze of a user's account
+	Unfreeze(ctx context.Context, userID, actorID int) error
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
+
+// GeoLocation is where an IP address is, as far as a GeoIP database knows.
+type GeoLocation struct {
+	Country   string  `json:"country"`
+	Region    string  `json:"region,omitempty"`
+	City      string  `json:"city,omitempty"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// String names the location for people, such as "Istanbul, TR"
+func (l *GeoLocation) String() string {
+	if l.City == "" {
+		return l.Country
+	}
+	return l.City + ", " + l.Country
+}
+
+// DistanceKm returns the great-circle distance between two locations
+func (l *GeoLocation) DistanceKm(other *GeoLocation) float64 {
+	lat1, lat2 := l.Latitude*math.Pi/180, other.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (other.Longitude - l.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// GeoLocator finds where IP addresses are
+type GeoLocator interface {
+	// Locate returns the location of ip, or nil if it is unknown, such as
+	// for private addresses
+	Locate(ip string) *GeoLocation
+}
+
+// TravelPolicy decides when two logins are too far apart for the time between them.
+type TravelPolicy struct {
+	MaxSpeedKmh   float64
+	MinDistanceKm float64
+}
+
+// ImpossibleTravel is a login from a place the user can't have reached since their previous login
+type ImpossibleTravel struct {
+	From       *GeoLocation
+	To         *GeoLocation
+	DistanceKm float64
+	SpeedKmh   float64
+	Elapsed    time.Duration
+}
+
+// Check compares a login from to at time at with the previous one, from from at time since.
+func (p TravelPolicy) Check(from *GeoLocation, since time.Time, to *GeoLocation, at time.Time) *ImpossibleTravel {
+	if from == nil || to == nil || p.MaxSpeedKmh <= 0 {
+		return nil
+	}
+	distance := from.DistanceKm(to)
+	if distance < p.MinDistanceKm {
+		return nil
+	}
+	elapsed := at.Sub(since)
+	// Logins within a minute of each other count as a minute apart
+	hours := math.Max(elapsed.Hours(), 1.0/60)
+	speed := distance / hours
+	if speed <= p.MaxSpeedKmh {
+		return nil
+	}
+	return &ImpossibleTravel{From: from, To: to, DistanceKm: distance, SpeedKmh: speed, Elapsed: elapsed}
+}
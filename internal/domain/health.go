@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Health statuses
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// HealthCheck probes one dependency, such as the database.
+type HealthCheck struct {
+	Name     string
+	Check    func(ctx context.Context) error
+	Liveness bool
+}
+
+// DependencyHealth is the outcome of one health check
+type DependencyHealth struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// HealthReport aggregates health checks; it is up only if every check is
+type HealthReport struct {
+	Status       string                       `json:"status"`
+	Timestamp    time.Time                    `json:"timestamp"`
+	Dependencies map[string]*DependencyHealth `json:"dependencies"`
+}
+
+// Up reports whether every check in the report passed
+func (r *HealthReport) Up() bool {
+	return r.Status == HealthStatusUp
+}
+
+// HealthService runs the health checks behind the liveness and readiness probes
+type HealthService interface {
+	// Liveness runs the liveness checks: whether the process must be restarted
+	Liveness(ctx context.Context) *HealthReport
+	// Readiness runs every check: whether the process can serve traffic
+	Readiness(ctx context.Context) *HealthReport
+}
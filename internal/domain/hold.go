@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"time"
+)
+
+var (
+	// ErrHoldNotFound is returned when a hold does not exist
+	ErrHoldNotFound = NewError(ErrorKindNotFound, "hold_not_found", "hold not found")
+	// ErrHoldNotActive is returned when a hold was captured or released in
+	// the meantime
+	ErrHoldNotActive = NewError(ErrorKindConflict, "hold_not_active", "hold was already captured or released")
+	// ErrHoldAwaitingApproval is returned when a hold's capture is held for
+	// approval
+	ErrHoldAwaitingApproval = NewError(ErrorKindConflict, "hold_awaiting_approval", "capture of the hold is awaiting approval")
+)
+
+// Hold reserves part of a user's balance until it is captured or released.
+type Hold struct {
+	ID            int        `json:"id"`
+	UserID        int        `json:"user_id"`
+	Amount        float64    `json:"amount"`
+	Status        string     `json:"status"`                   // "active", "captured", "released"
+	TransactionID *int       `json:"transaction_id,omitempty"` // debit recorded on capture, or awaiting approval
+	CreatedAt     time.Time  `json:"created_at"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+}
+
+// NewHold creates an active hold of amount on a user's balance
+func NewHold(userID int, amount float64) (*Hold, error) {
+	if userID <= 0 {
+		return nil, &ValidationError{Msg: "user_id must be positive"}
+	}
+	if amount <= 0 {
+		return nil, &ValidationError{Msg: "amount must be positive"}
+	}
+	return &Hold{
+		UserID:    userID,
+		Amount:    amount,
+		Status:    "active",
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// CanCapture returns an error unless the hold is active with no capture awaiting approval
+func (h *Hold) CanCapture() error {
+	if err := h.checkActive("capture"); err != nil {
+		return err
+	}
+	if h.AwaitingApproval() {
+		return ErrHoldAwaitingApproval
+	}
+	return nil
+}
+
+// AwaitingApproval reports whether the hold's capture is held for approval
+func (h *Hold) AwaitingApproval() bool {
+	return h.Status == "active" && h.TransactionID != nil
+}
+
+// Capture marks the hold as settled.
+func (h *Hold) Capture() error {
+	if err := h.checkActive("capture"); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	h.Status = "captured"
+	h.ResolvedAt = &now
+	return nil
+}
+
+// Release marks the hold as cancelled, returning the funds to the available balance
+func (h *Hold) Release() error {
+	if err := h.checkActive("release"); err != nil {
+		return err
+	}
+	if h.AwaitingApproval() {
+		return ErrHoldAwaitingApproval
+	}
+	now := time.Now().UTC()
+	h.Status = "released"
+	h.ResolvedAt = &now
+	return nil
+}
+
+// checkActive returns a validation error unless the hold can still be resolved
+func (h *Hold) checkActive(action string) error {
+	if h.Status != "active" {
+		return &ValidationError{Msg: "cannot " + action + " " + h.Status + " hold"}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "context"
+
+// HoldRepository defines the interface for hold data access
+type HoldRepository interface {
+	// Create creates a new hold
+	Create(ctx context.Context, hold *Hold) error
+
+	// GetByID retrieves a hold by ID
+	GetByID(ctx context.Context, id int) (*Hold, error)
+
+	// ListActiveByUser retrieves the unresolved holds on a user's balance
+	ListActiveByUser(ctx context.Context, userID int) ([]*Hold, error)
+
+	// GetByTransactionID retrieves the active hold whose capture is the
+	// pending transaction, if any
+	GetByTransactionID(ctx context.Context, transactionID int) (*Hold, error)
+
+	// Resolve stores the capture or release of an active hold. It fails with
+	// ErrHoldNotActive if the hold was resolved, or its capture held for
+	// another transaction, in the meantime.
+	Resolve(ctx context.Context, hold *Hold) error
+
+	// Bind marks an active hold as captured by a transaction held for
+	// approval. It fails with ErrHoldNotActive if the hold was resolved or
+	// bound in the meantime.
+	Bind(ctx context.Context, holdID, transactionID int) error
+
+	// Unbind frees the hold a rejected or failed pending transaction was to
+	// capture, if any
+	Unbind(ctx context.Context, transactionID int) error
+}
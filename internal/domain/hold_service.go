@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+// HoldService defines business logic for two-phase (authorize, then capture) transactions
+type HoldService interface {
+	// PlaceHold reserves amount from the user's available balance
+	PlaceHold(ctx context.Context, userID int, amount float64) (*Hold, error)
+
+	// GetHold retrieves a hold by ID
+	GetHold(ctx context.Context, id int) (*Hold, error)
+
+	// ListActiveHolds retrieves the unresolved holds on a user's balance
+	ListActiveHolds(ctx context.Context, userID int) ([]*Hold, error)
+
+	// CaptureHold debits the held amount and settles the hold. A debit held
+	// for approval returns the hold, still active, with ErrTransactionHeld.
+	CaptureHold(ctx context.Context, id int) (*Hold, error)
+
+	// ReleaseHold cancels the hold and makes the amount available again
+	ReleaseHold(ctx context.Context, id int) (*Hold, error)
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Invoice statuses.
+const (
+	InvoiceOpen = "open"
+	InvoicePaid = "paid"
+	InvoiceVoid = "void"
+)
+
+var (
+	// ErrInvoiceNotFound is returned when an invoice does not exist or isn't
+	// the caller's
+	ErrInvoiceNotFound = NewError(ErrorKindNotFound, "invoice_not_found", "invoice not found")
+	// ErrInvoiceExists is returned when a user's period is already invoiced,
+	// or one of its fees is on another invoice
+	ErrInvoiceExists = NewError(ErrorKindConflict, "invoice_exists", "period has already been invoiced")
+	// ErrInvoiceNotOpen is returned when settling an invoice that was paid
+	// or voided in the meantime
+	ErrInvoiceNotOpen = NewError(ErrorKindConflict, "invoice_not_open", "invoice is not open")
+)
+
+// Invoice bills a user the fees they were charged in [PeriodStart, PeriodEnd).
+type Invoice struct {
+	ID          int            `json:"id"`
+	Number      string         `json:"number"`
+	UserID      int            `json:"user_id"`
+	PeriodStart time.Time      `json:"period_start"`
+	PeriodEnd   time.Time      `json:"period_end"`
+	Currency    string         `json:"currency"`
+	Total       float64        `json:"total"`
+	Status      string         `json:"status"`
+	IssuedAt    time.Time      `json:"issued_at"`
+	PaidAt      *time.Time     `json:"paid_at,omitempty"`
+	Lines       []*InvoiceLine `json:"lines,omitempty"`
+}
+
+// InvoiceNumber returns the number of a user's invoice for a month, such as INV-202609-000042.
+func InvoiceNumber(userID int, periodStart time.Time) string {
+	return fmt.Sprintf("INV-%s-%06d", periodStart.UTC().Format("200601"), userID)
+}
+
+// FileName returns the name the invoice's PDF is downloaded as
+func (i *Invoice) FileName() string {
+	return i.Number + ".pdf"
+}
+
+// InvoiceLine is the fee a transaction was charged.
+type InvoiceLine struct {
+	TransactionID     int       `json:"transaction_id"`
+	TransactionType   string    `json:"transaction_type"`
+	TransactionAmount float64   `json:"transaction_amount"`
+	TransactionStatus string    `json:"-"`
+	FeeScheduleID     *int      `json:"fee_schedule_id,omitempty"`
+	Amount            float64   `json:"amount"`
+	OccurredAt        time.Time `json:"occurred_at"`
+}
+
+// InvoiceRun summarises a run of invoicing.
+type InvoiceRun struct {
+	PeriodStart time.Time         `json:"period_start"`
+	PeriodEnd   time.Time         `json:"period_end"`
+	Issued      int               `json:"issued"`
+	Settled     int               `json:"settled"`
+	Failed      []*InvoiceFailure `json:"failed,omitempty"`
+}
+
+// InvoiceFailure is why a run could not issue a user's invoice, or settle one.
+type InvoiceFailure struct {
+	UserID    int    `json:"user_id"`
+	InvoiceID int    `json:"invoice_id,omitempty"`
+	Error     string `json:"error"`
+}
+
+// InvoiceDocument is an invoice rendered as a PDF
+type InvoiceDocument struct {
+	FileName string
+	Content  []byte
+}
+
+// InvoiceRepository defines methods for invoice data access
+type InvoiceRepository interface {
+	// ListFeePayers fetches the users with fees of completed or pending
+	// transactions made before end that aren't invoiced yet, in user ID order
+	ListFeePayers(ctx context.Context, end time.Time) ([]int, error)
+	// ListFeeLines fetches a user's fees of completed or pending
+	// transactions made before end that aren't invoiced yet, oldest first
+	ListFeeLines(ctx context.Context, userID int, end time.Time) ([]*InvoiceLine, error)
+	// ListOpen fetches the open invoices with their lines, each with the
+	// current status and fee of its transaction
+	ListOpen(ctx context.Context) ([]*Invoice, error)
+	// Settle stores the status, total and paid time of an open invoice and
+	// keeps only its given lines, with their amounts. It returns
+	// ErrInvoiceNotOpen if the invoice isn't open anymore.
+	Settle(ctx context.Context, invoice *Invoice) error
+	// Create stores an invoice with its lines, returning ErrInvoiceExists if
+	// its period is already invoiced or one of its fees is on another invoice
+	Create(ctx context.Context, invoice *Invoice) error
+	// Get fetches an invoice with its lines, or nil if there is none
+	Get(ctx context.Context, id int) (*Invoice, error)
+	// ListByUser fetches a page of a user's invoices without their lines,
+	// newest period first
+	ListByUser(ctx context.Context, userID, limit, offset int) ([]*Invoice, error)
+}
+
+// InvoiceService defines business logic for the monthly invoices of fees
+type InvoiceService interface {
+	// List returns a page of a user's invoices, newest first
+	List(ctx context.Context, userID, limit, offset int) ([]*Invoice, error)
+	// Get returns one of a user's invoices with its lines
+	Get(ctx context.Context, userID, invoiceID int) (*Invoice, error)
+	// PDF renders one of a user's invoices
+	PDF(ctx context.Context, userID, invoiceID int) (*InvoiceDocument, error)
+	// ListForOrganization returns a page of an organization's invoices to one
+	// of its members, or to an admin
+	ListForOrganization(ctx context.Context, actorID int, isAdmin bool, organizationID, limit, offset int) ([]*Invoice, error)
+	// GetForOrganization returns one of an organization's invoices with its
+	// lines to one of its members, or to an admin
+	GetForOrganization(ctx context.Context, actorID int, isAdmin bool, organizationID, invoiceID int) (*Invoice, error)
+	// OrganizationPDF renders one of an organization's invoices for one of
+	// its members, or for an admin
+	OrganizationPDF(ctx context.Context, actorID int, isAdmin bool, organizationID, invoiceID int) (*InvoiceDocument, error)
+	// RunInvoicing settles the open invoices and invoices the last month
+	// that is over
+	RunInvoicing(ctx context.Context) (*InvoiceRun, error)
+	// Start begins invoicing periodically in the background
+	Start(ctx context.Context)
+	// Stop stops the background invoicing
+	Stop()
+}
@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// KYC levels a user can reach.
+const (
+	// KYCLevelNone is the level of users who haven't verified their identity
+	KYCLevelNone = 0
+	// KYCLevelBasic is reached by verifying name, date of birth and country
+	KYCLevelBasic = 1
+	// KYCLevelFull is reached by also verifying an identity document
+	KYCLevelFull = 2
+)
+
+// KYC verification statuses.
+const (
+	KYCStatusNone     = "none"
+	KYCStatusPending  = "pending"  // waiting for the provider
+	KYCStatusReview   = "review"   // waiting for an admin
+	KYCStatusApproved = "approved" // the user reached the verification's level
+	KYCStatusRejected = "rejected"
+)
+
+// KYCDocumentTypes lists the identity documents accepted for full verification
+var KYCDocumentTypes = []string{"passport", "national_id", "driving_license"}
+
+// minKYCAge is the age in years a user must have reached to be verified
+const minKYCAge = 18
+
+// countryPattern matches ISO 3166-1 alpha-2 country codes
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+var (
+	// ErrKYCVerificationNotFound is returned when a verification does not exist
+	ErrKYCVerificationNotFound = NewError(ErrorKindNotFound, "kyc_verification_not_found", "KYC verification not found")
+	// ErrKYCInProgress is returned when submitting while an earlier
+	// verification is still pending or in review
+	ErrKYCInProgress = NewError(ErrorKindConflict, "kyc_in_progress", "a KYC verification is already in progress")
+	// ErrKYCLevelReached is returned when submitting for a level the user has
+	// already reached
+	ErrKYCLevelReached = NewError(ErrorKindConflict, "kyc_level_reached", "KYC level already reached")
+	// ErrKYCNotPending is returned when deciding a verification that was
+	// already decided
+	ErrKYCNotPending = NewError(ErrorKindConflict, "kyc_not_pending", "KYC verification was already decided")
+	// ErrKYCInvalidCallback is returned when a provider callback's signature
+	// or body is invalid
+	ErrKYCInvalidCallback = NewError(ErrorKindForbidden, "kyc_invalid_callback", "invalid KYC callback")
+)
+
+// KYCSubmission is what a user submits to verify their identity at a level.
+type KYCSubmission struct {
+	Level          int    `json:"level"`
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+	DateOfBirth    string `json:"date_of_birth"` // YYYY-MM-DD
+	Country        string `json:"country"`       // ISO 3166-1 alpha-2
+	DocumentType   string `json:"document_type,omitempty"`
+	DocumentNumber string `json:"document_number,omitempty"`
+}
+
+// Validate checks the submission's level and fields at time now
+func (s *KYCSubmission) Validate(now time.Time) error {
+	if s.Level != KYCLevelBasic && s.Level != KYCLevelFull {
+		return &ValidationError{Msg: "level must be 1 or 2"}
+	}
+	for _, name := range []string{s.FirstName, s.LastName} {
+		if strings.TrimSpace(name) == "" || utf8.RuneCountInString(name) > maxNameLength {
+			return &ValidationError{Msg: "first_name and last_name must be 1 to 100 characters"}
+		}
+	}
+	dob, err := time.Parse(time.DateOnly, s.DateOfBirth)
+	if err != nil {
+		return &ValidationError{Msg: "date_of_birth must be a date such as 1990-04-23"}
+	}
+	if dob.AddDate(minKYCAge, 0, 0).After(now) {
+		return &ValidationError{Msg: "users must be at least 18 years old"}
+	}
+	if !countryPattern.MatchString(s.Country) {
+		return &ValidationError{Msg: "country must be an ISO 3166-1 alpha-2 code such as TR"}
+	}
+	if s.Level == KYCLevelBasic {
+		return nil
+	}
+	known := false
+	for _, t := range KYCDocumentTypes {
+		known = known || s.DocumentType == t
+	}
+	if !known {
+		return &ValidationError{Msg: "document_type must be passport, national_id or driving_license"}
+	}
+	if n := len(strings.TrimSpace(s.DocumentNumber)); n < 4 || n > 32 {
+		return &ValidationError{Msg: "document_number must be 4 to 32 characters"}
+	}
+	return nil
+}
+
+// DocumentLast4 returns the last four characters of the document number.
+func (s *KYCSubmission) DocumentLast4() string {
+	number := strings.TrimSpace(s.DocumentNumber)
+	if len(number) < 4 {
+		return ""
+	}
+	return number[len(number)-4:]
+}
+
+// KYCVerification is a user's submission and how it was decided.
+type KYCVerification struct {
+	ID            int        `json:"id"`
+	UserID        int        `json:"user_id"`
+	Level         int        `json:"level"`
+	Status        string     `json:"status"`
+	Provider      string     `json:"provider"`
+	ProviderRef   string     `json:"provider_ref,omitempty"`
+	Country       string     `json:"country"`
+	DocumentType  string     `json:"document_type,omitempty"`
+	DocumentLast4 string     `json:"document_last4,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+	ReviewedBy    *int       `json:"reviewed_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`
+}
+
+// IsOpen reports whether the verification still waits for a decision
+func (v *KYCVerification) IsOpen() bool {
+	return v.Status == KYCStatusPending || v.Status == KYCStatusReview
+}
+
+// KYCState is a user's KYC level, status, latest verification and limit rules.
+type KYCState struct {
+	UserID int                    `json:"user_id"`
+	Level  int                    `json:"level"`
+	Status string                 `json:"status"`
+	Latest *KYCVerification       `json:"latest,omitempty"`
+	Limits []TransactionLimitRule `json:"limits"`
+}
+
+// KYCResult is a provider's verdict on a verification.
+type KYCResult struct {
+	ProviderRef string
+	Status      string
+	Reason      string
+}
+
+// KYCCallback is a provider's signed request reporting a result.
+type KYCCallback struct {
+	Signature string
+	Timestamp string
+	Body      []byte
+}
+
+// KYCProvider verifies identities.
+type KYCProvider interface {
+	// Name identifies the provider in stored verifications
+	Name() string
+	// Submit starts verifying a user's submission, returning the provider's
+	// reference and the result so far
+	Submit(ctx context.Context, userID int, submission *KYCSubmission) (*KYCResult, error)
+	// ParseCallback authenticates a callback and returns the result it
+	// reports, or ErrKYCInvalidCallback
+	ParseCallback(callback *KYCCallback) (*KYCResult, error)
+}
+
+// KYCRepository defines methods for KYC data access
+type KYCRepository interface {
+	// Create stores a verification and sets the user's KYC status to its status
+	Create(ctx context.Context, verification *KYCVerification) error
+	// SetProviderRef stores the provider's reference of a verification
+	SetProviderRef(ctx context.Context, id int, ref string) error
+	// GetByID fetches a verification, or nil if there is none
+	GetByID(ctx context.Context, id int) (*KYCVerification, error)
+	// GetByProviderRef fetches a provider's verification, or nil if there is none
+	GetByProviderRef(ctx context.Context, provider, ref string) (*KYCVerification, error)
+	// GetLatest fetches a user's latest verification, or nil if there is none
+	GetLatest(ctx context.Context, userID int) (*KYCVerification, error)
+	// GetLevel fetches a user's KYC level and status
+	GetLevel(ctx context.Context, userID int) (int, string, error)
+	// ListByStatus fetches a page of the verifications with a status, oldest first
+	ListByStatus(ctx context.Context, status string, limit, offset int) ([]*KYCVerification, error)
+	// Decide stores the status, reason and reviewer of an open verification
+	// and updates the user's KYC status, and their level if it was approved.
+	// It returns ErrKYCNotPending if the verification was already decided.
+	Decide(ctx context.Context, verification *KYCVerification) error
+	// ListTierLimits fetches the limit rules of every level, by level
+	ListTierLimits(ctx context.Context) (map[int][]TransactionLimitRule, error)
+	// SetTierLimits replaces the limit rules of a level on behalf of actorID
+	SetTierLimits(ctx context.Context, level int, rules []TransactionLimitRule, actorID int) error
+}
+
+// KYCService defines business logic for identity verification
+type KYCService interface {
+	// Submit starts verifying a user's identity at a level
+	Submit(ctx context.Context, userID int, submission *KYCSubmission) (*KYCVerification, error)
+	// State returns a user's KYC level, status, latest verification and limits
+	State(ctx context.Context, userID int) (*KYCState, error)
+	// HandleCallback applies the result of a provider callback
+	HandleCallback(ctx context.Context, callback *KYCCallback) error
+	// ListVerifications returns a page of the verifications with a status, oldest first
+	ListVerifications(ctx context.Context, status string, limit, offset int) ([]*KYCVerification, error)
+	// GetVerification returns a verification, or ErrKYCVerificationNotFound
+	GetVerification(ctx context.Context, id int) (*KYCVerification, error)
+	// Review approves or rejects an open verification on behalf of actorID
+	Review(ctx context.Context, id int, approve bool, reason string, actorID int) (*KYCVerification, error)
+	// TierLimits returns the limit rules of every level, by level
+	TierLimits(ctx context.Context) (map[int][]TransactionLimitRule, error)
+	// SetTierLimits replaces the limit rules of a level on behalf of actorID
+	SetTierLimits(ctx context.Context, level int, rules []TransactionLimitRule, actorID int) error
+}
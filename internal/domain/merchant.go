@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Payout schedules.
+const (
+	PayoutScheduleDaily  = "daily"
+	PayoutScheduleWeekly = "weekly"
+)
+
+// Payout statuses.
+const (
+	PayoutProcessing = "processing"
+	PayoutPaid       = "paid"
+	PayoutFailed     = "failed"
+)
+
+var (
+	// ErrMerchantUserNotFound is returned when the user of a merchant account
+	// does not exist
+	ErrMerchantUserNotFound = NewError(ErrorKindNotFound, "user_not_found", "user not found")
+	// ErrMerchantNotFound is returned when a user has no merchant account
+	ErrMerchantNotFound = NewError(ErrorKindNotFound, "merchant_not_found", "merchant account not found")
+	// ErrSettlementSourceInvalid is returned when a merchant's settlement
+	// account isn't one of their active bank accounts
+	ErrSettlementSourceInvalid = NewError(ErrorKindValidation, "settlement_source_invalid", "settlement account must be one of the merchant's active bank accounts")
+	// ErrPayoutNotFound is returned when a payout does not exist
+	ErrPayoutNotFound = NewError(ErrorKindNotFound, "payout_not_found", "payout not found")
+	// ErrPayoutExists is returned when a merchant's period already has a
+	// payout that didn't fail
+	ErrPayoutExists = NewError(ErrorKindConflict, "payout_exists", "period has already been paid out")
+)
+
+// Merchant is a user taking payments as a business.
+type Merchant struct {
+	UserID             int       `json:"user_id"`
+	BusinessName       string    `json:"business_name"`
+	SettlementSourceID int       `json:"settlement_source_id"`
+	PayoutSchedule     string    `json:"payout_schedule"`
+	MinPayout          float64   `json:"min_payout"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// Validate checks the merchant's business name, schedule and minimum payout
+func (m *Merchant) Validate() error {
+	if m.BusinessName == "" || len(m.BusinessName) > 100 {
+		return &ValidationError{Msg: "business_name must be 1 to 100 characters"}
+	}
+	if m.PayoutSchedule != PayoutScheduleDaily && m.PayoutSchedule != PayoutScheduleWeekly {
+		return &ValidationError{Msg: "payout_schedule must be daily or weekly"}
+	}
+	if m.MinPayout < 0 {
+		return &ValidationError{Msg: "min_payout must not be negative"}
+	}
+	return nil
+}
+
+// PayoutCutoff returns the end of the latest period of the merchant's schedule that is over at now
+func (m *Merchant) PayoutCutoff(now time.Time) time.Time {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if m.PayoutSchedule == PayoutScheduleWeekly {
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day
+}
+
+// Payout pays a merchant the transfers they received in [PeriodStart, PeriodEnd).
+type Payout struct {
+	ID                 int        `json:"id"`
+	MerchantID         int        `json:"merchant_id"`
+	SettlementSourceID int        `json:"settlement_source_id"`
+	PeriodStart        time.Time  `json:"period_start"`
+	PeriodEnd          time.Time  `json:"period_end"`
+	TransferCount      int        `json:"transfer_count"`
+	Amount             float64    `json:"amount"`
+	Status             string     `json:"status"`
+	BatchID            string     `json:"batch_id,omitempty"`
+	TransactionID      *int       `json:"transaction_id,omitempty"`
+	Error              string     `json:"error,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty"`
+}
+
+// TaskID returns the ID of the worker task paying the payout.
+func (p *Payout) TaskID() string {
+	return "payout-" + strconv.Itoa(p.ID)
+}
+
+// PayoutStatement is a payout with the transfers it pays, oldest first
+type PayoutStatement struct {
+	Payout    *Payout        `json:"payout"`
+	Transfers []*Transaction `json:"transfers"`
+}
+
+// PayoutRun summarises a run of the payout scheduler.
+type PayoutRun struct {
+	Created int    `json:"created"`
+	BatchID string `json:"batch_id,omitempty"`
+	Paid    int    `json:"paid"`
+	Failed  int    `json:"failed"`
+}
+
+// BatchSubmitter submits tasks to the worker pool as a background batch.
+type BatchSubmitter interface {
+	StartBatch(ctx context.Context, tasks []*TransactionTask, rollbackOnFailure bool) (*BatchRecord, error)
+}
+
+// MerchantRepository defines methods for merchant and payout data access
+type MerchantRepository interface {
+	// SaveMerchant creates or updates a merchant account
+	SaveMerchant(ctx context.Context, merchant *Merchant) error
+	// GetMerchant fetches a user's merchant account, or nil if there is none
+	GetMerchant(ctx context.Context, userID int) (*Merchant, error)
+	// ListMerchants fetches every merchant account
+	ListMerchants(ctx context.Context) ([]*Merchant, error)
+	// LastPeriodEnd fetches the end of a merchant's latest period with a
+	// payout that didn't fail, or nil if there is none
+	LastPeriodEnd(ctx context.Context, merchantID int) (*time.Time, error)
+	// SumTransfers counts and totals the completed transfers a merchant
+	// received in [start, end)
+	SumTransfers(ctx context.Context, merchantID int, start, end time.Time) (int, float64, error)
+	// ListTransfers fetches the completed transfers a merchant received in
+	// [start, end), oldest first
+	ListTransfers(ctx context.Context, merchantID int, start, end time.Time) ([]*Transaction, error)
+	// CreatePayout stores a payout, returning ErrPayoutExists if its period
+	// already has one that didn't fail
+	CreatePayout(ctx context.Context, payout *Payout) error
+	// GetPayout fetches a payout, or nil if there is none
+	GetPayout(ctx context.Context, id int) (*Payout, error)
+	// ListPayouts fetches a page of a merchant's payouts, newest first
+	ListPayouts(ctx context.Context, merchantID, limit, offset int) ([]*Payout, error)
+	// ListProcessingPayouts fetches the payouts still processing, oldest first
+	ListProcessingPayouts(ctx context.Context) ([]*Payout, error)
+	// UpdatePayout stores the status, batch and outcome of a processing payout
+	UpdatePayout(ctx context.Context, payout *Payout) error
+}
+
+// MerchantService defines business logic for merchant accounts and their scheduled payouts
+type MerchantService interface {
+	// SaveMerchant makes userID a merchant or updates their merchant account
+	SaveMerchant(ctx context.Context, userID int, merchant *Merchant) (*Merchant, error)
+	// GetMerchant returns a user's merchant account
+	GetMerchant(ctx context.Context, userID int) (*Merchant, error)
+	// ListPayouts returns a page of a merchant's payouts, newest first
+	ListPayouts(ctx context.Context, userID, limit, offset int) ([]*Payout, error)
+	// GetStatement returns one of a merchant's payouts with the transfers it pays
+	GetStatement(ctx context.Context, userID, payoutID int) (*PayoutStatement, error)
+	// RunPayouts settles the processing payouts and pays out every merchant
+	// whose period is over
+	RunPayouts(ctx context.Context) (*PayoutRun, error)
+	// Start begins running payouts periodically in the background
+	Start(ctx context.Context)
+	// Stop stops the background payouts
+	Stop()
+}
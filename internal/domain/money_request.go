@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"time"
+)
+
+var (
+	// ErrMoneyRequestNotFound is returned when a money request does not exist
+	ErrMoneyRequestNotFound = NewError(ErrorKindNotFound, "money_request_not_found", "money request not found")
+	// ErrMoneyRequestChanged is returned when a money request was answered in
+	// the meantime
+	ErrMoneyRequestChanged = NewError(ErrorKindConflict, "money_request_changed", "money request was answered in the meantime")
+)
+
+// MoneyRequest asks another user (the payer) to send money to the requester.
+type MoneyRequest struct {
+	ID            int        `json:"id"`
+	RequesterID   int        `json:"requester_id"`
+	PayerID       int        `json:"payer_id"`
+	Amount        float64    `json:"amount"`
+	Note          string     `json:"note,omitempty"`
+	Status        string     `json:"status"`                   // "pending", "accepted", "declined", "cancelled"
+	TransactionID *int       `json:"transaction_id,omitempty"` // transfer executed on accept
+	CreatedAt     time.Time  `json:"created_at"`
+	RespondedAt   *time.Time `json:"responded_at,omitempty"`
+}
+
+// NewMoneyRequest creates a pending request for payerID to pay requesterID
+func NewMoneyRequest(requesterID, payerID int, amount float64, note string) (*MoneyRequest, error) {
+	if payerID <= 0 {
+		return nil, &ValidationError{Msg: "payer_id must be positive"}
+	}
+	if requesterID == payerID {
+		return nil, &ValidationError{Msg: "cannot request money from yourself"}
+	}
+	if amount <= 0 {
+		return nil, &ValidationError{Msg: "amount must be positive"}
+	}
+	if len(note) > 255 {
+		return nil, &ValidationError{Msg: "note must be at most 255 characters"}
+	}
+	return &MoneyRequest{
+		RequesterID: requesterID,
+		PayerID:     payerID,
+		Amount:      amount,
+		Note:        note,
+		Status:      "pending",
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// Accept marks the request as being paid.
+func (m *MoneyRequest) Accept() error {
+	return m.resolve("accepted")
+}
+
+// Reopen returns an accepted request whose transfer failed to pending
+func (m *MoneyRequest) Reopen() {
+	m.Status = "pending"
+	m.TransactionID = nil
+	m.RespondedAt = nil
+}
+
+// Decline marks the request as refused by the payer
+func (m *MoneyRequest) Decline() error {
+	return m.resolve("declined")
+}
+
+// Cancel marks the request as withdrawn by the requester
+func (m *MoneyRequest) Cancel() error {
+	return m.resolve("cancelled")
+}
+
+// CheckPending returns a validation error unless the request can still be answered
+func (m *MoneyRequest) CheckPending() error {
+	if m.Status != "pending" {
+		return &ValidationError{Msg: "money request has already been " + m.Status}
+	}
+	return nil
+}
+
+// resolve moves a pending request to its final status
+func (m *MoneyRequest) resolve(status string) error {
+	if err := m.CheckPending(); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	m.Status = status
+	m.RespondedAt = &now
+	return nil
+}
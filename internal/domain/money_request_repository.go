@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+// MoneyRequestRepository defines the interface for money request data access
+type MoneyRequestRepository interface {
+	// Create creates a new money request
+	Create(ctx context.Context, req *MoneyRequest) error
+
+	// GetByID retrieves a money request by ID
+	GetByID(ctx context.Context, id int) (*MoneyRequest, error)
+
+	// ListByPayer retrieves requests a user has been asked to pay, newest first
+	ListByPayer(ctx context.Context, payerID int) ([]*MoneyRequest, error)
+
+	// ListByRequester retrieves requests a user has sent, newest first
+	ListByRequester(ctx context.Context, requesterID int) ([]*MoneyRequest, error)
+
+	// Update stores the status and outcome of a money request that is still
+	// in fromStatus. It fails with ErrMoneyRequestChanged otherwise.
+	Update(ctx context.Context, req *MoneyRequest, fromStatus string) error
+}
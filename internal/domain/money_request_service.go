@@ -0,0 +1,34 @@
+package domain
+
+import "context"
+
+// MoneyRequestService defines business logic for requesting money from other users
+type MoneyRequestService interface {
+	// RequestMoney asks payerID to pay amount to requesterID and notifies the payer
+	RequestMoney(ctx context.Context, requesterID, payerID int, amount float64, note string) (*MoneyRequest, error)
+
+	// GetRequest retrieves a money request by ID
+	GetRequest(ctx context.Context, id int) (*MoneyRequest, error)
+
+	// ListIncoming retrieves requests a user has been asked to pay
+	ListIncoming(ctx context.Context, userID int) ([]*MoneyRequest, error)
+
+	// ListOutgoing retrieves requests a user has sent
+	ListOutgoing(ctx context.Context, userID int) ([]*MoneyRequest, error)
+
+	// Accept pays a pending request by transferring from the payer to the
+	// requester. A transfer held for approval returns the accepted request
+	// with ErrTransactionHeld.
+	Accept(ctx context.Context, id int) (*MoneyRequest, error)
+
+	// Decline refuses a pending request without moving money
+	Decline(ctx context.Context, id int) (*MoneyRequest, error)
+
+	// Cancel withdraws a pending request
+	Cancel(ctx context.Context, id int) (*MoneyRequest, error)
+}
+
+// MoneyRequestNotifier tells a payer that someone has requested money from them
+type MoneyRequestNotifier interface {
+	NotifyMoneyRequested(ctx context.Context, req *MoneyRequest) error
+}
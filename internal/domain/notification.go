@@ -0,0 +1,136 @@
+package domain
+
+import "context"
+
+// Notification channels
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+	ChannelPush  = "push"
+)
+
+// NotificationChannels lists every channel a notification can be sent on
+var NotificationChannels = []string{ChannelEmail, ChannelSMS, ChannelPush}
+
+// Notification events
+const (
+	EventUserRegistered       = "user_registered"
+	EventNewDeviceLogin       = "new_device_login"
+	EventTransactionCompleted = "transaction_completed"
+	EventMoneyRequested       = "money_requested"
+	EventLowBalance           = "low_balance"
+	EventLargeTransaction     = "large_transaction"
+	// EventScheduledTransactionFailed goes to the owner of a scheduled
+	// transaction that failed for good
+	EventScheduledTransactionFailed = "scheduled_transaction_failed"
+	// EventAdminScheduledTransactionFailed tells admins about the same failure
+	EventAdminScheduledTransactionFailed = "admin_scheduled_transaction_failed"
+	// EventSuspiciousLogin reports a login from a place the user can't have
+	// reached since their previous login
+	EventSuspiciousLogin = "suspicious_login"
+	// EventLoginVerification carries the code that confirms a login from an
+	// untrusted device
+	EventLoginVerification = "login_verification"
+	// EventBonusAwarded tells a user a campaign bonus was credited to them
+	EventBonusAwarded = "bonus_awarded"
+	// EventReferralRewarded tells a user a referral reward was credited to them
+	EventReferralRewarded = "referral_rewarded"
+	// EventKYCUpdated tells a user their identity verification was approved or rejected
+	EventKYCUpdated = "kyc_updated"
+	// EventDisputeUpdated tells a user a dispute they opened, or one that
+	// reversed a payment to them, changed status
+	EventDisputeUpdated = "dispute_updated"
+	// EventChargebackUpdated tells a user a credit to them was charged back,
+	// or how its chargeback ended
+	EventChargebackUpdated = "chargeback_updated"
+	// EventBankTransferUpdated tells a user their bank transfer settled or
+	// was returned
+	EventBankTransferUpdated = "bank_transfer_updated"
+)
+
+// NotificationEvents lists every event users can be notified of
+var NotificationEvents = []string{
+	EventUserRegistered, EventNewDeviceLogin, EventTransactionCompleted, EventMoneyRequested,
+	EventLowBalance, EventLargeTransaction, EventScheduledTransactionFailed, EventAdminScheduledTransactionFailed,
+	EventSuspiciousLogin, EventBonusAwarded, EventReferralRewarded,
+	EventKYCUpdated, EventDisputeUpdated, EventChargebackUpdated, EventBankTransferUpdated,
+}
+
+// SecurityNotificationEvents are always sent by email, whatever the preferences.
+var SecurityNotificationEvents = []string{EventLoginVerification}
+
+// IsSecurityNotificationEvent reports whether event ignores preferences
+func IsSecurityNotificationEvent(event string) bool {
+	return contains(SecurityNotificationEvents, event)
+}
+
+// NotificationPreference says whether a user wants an event on a channel
+type NotificationPreference struct {
+	Event   string `json:"event"`
+	Channel string `json:"channel"`
+	Enabled bool   `json:"enabled"`
+}
+
+// DefaultNotificationPreference is whether an event goes to a channel by default.
+func DefaultNotificationPreference(event, channel string) bool {
+	return channel != ChannelSMS
+}
+
+// Validate checks that the preference names a known event and channel
+func (p NotificationPreference) Validate() error {
+	if !contains(NotificationEvents, p.Event) {
+		return &ValidationError{Msg: "unknown notification event: " + p.Event}
+	}
+	if !contains(NotificationChannels, p.Channel) {
+		return &ValidationError{Msg: "unknown notification channel: " + p.Channel}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Notifier sends a user a notification of an event.
+type Notifier interface {
+	Notify(ctx context.Context, userID int, event string, data map[string]string)
+}
+
+// AdminNotifier sends every admin a notification of an event
+type AdminNotifier interface {
+	NotifyAdmins(ctx context.Context, event string, data map[string]string) error
+}
+
+// NotificationRepository defines the interface for notification settings data access
+type NotificationRepository interface {
+	// ListPreferences retrieves the preferences a user has set
+	ListPreferences(ctx context.Context, userID int) ([]NotificationPreference, error)
+
+	// SetPreferences stores preferences, replacing earlier choices for the same event and channel
+	SetPreferences(ctx context.Context, userID int, prefs []NotificationPreference) error
+
+	// GetContacts retrieves the user's SMS number and push token by channel
+	GetContacts(ctx context.Context, userID int) (map[string]string, error)
+
+	// SetContact stores the address a channel delivers to; an empty address removes it
+	SetContact(ctx context.Context, userID int, channel, address string) error
+}
+
+// NotificationService defines business logic for notifications and their settings
+type NotificationService interface {
+	Notifier
+
+	// GetPreferences returns the user's effective preference for every event and channel
+	GetPreferences(ctx context.Context, userID int) ([]NotificationPreference, error)
+
+	// SetPreferences stores the user's choices
+	SetPreferences(ctx context.Context, userID int, prefs []NotificationPreference) error
+
+	// SetContact stores the SMS number or push token the user is reached at
+	SetContact(ctx context.Context, userID int, channel, address string) error
+}
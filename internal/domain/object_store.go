@@ -0,0 +1,13 @@
+package domain
+
+import "context"
+
+// ObjectStore keeps binary objects, such as avatars, under slash-separated keys
+type ObjectStore interface {
+	// Put stores data under key, replacing any object already there
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Get returns the object under key and its content type, or nil if there is none
+	Get(ctx context.Context, key string) ([]byte, string, error)
+	// Delete removes the object under key; a missing object is not an error
+	Delete(ctx context.Context, key string) error
+}
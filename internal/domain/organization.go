@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrOrganizationNotFound is returned when an organization does not exist or the caller is not a member
+	ErrOrganizationNotFound = NewError(ErrorKindNotFound, "organization_not_found", "organization not found")
+	// ErrOrganizationRoleRequired is returned when a member's role does not allow an action
+	ErrOrganizationRoleRequired = NewError(ErrorKindForbidden, "organization_role_required", "your organization role does not allow this")
+	// ErrOrganizationMemberNotFound is returned when a user is not a member of the organization
+	ErrOrganizationMemberNotFound = NewError(ErrorKindNotFound, "organization_member_not_found", "organization member not found")
+	// ErrOrganizationMemberExists is returned when adding a user who is already a member
+	ErrOrganizationMemberExists = NewError(ErrorKindConflict, "organization_member_exists", "user is already a member of the organization")
+	// ErrLastOrganizationOwner is returned when a change would leave an organization without an owner
+	ErrLastOrganizationOwner = NewError(ErrorKindConflict, "last_organization_owner", "an organization must keep at least one owner")
+	// ErrOrganizationTransactionNotFound is returned when an organization has no such transaction awaiting sign-off
+	ErrOrganizationTransactionNotFound = NewError(ErrorKindNotFound, "organization_transaction_not_found", "organization transaction not found")
+)
+
+// OrganizationAccountRole is the role of the user that holds an organization's shared balance.
+const OrganizationAccountRole = "organization"
+
+// organizationAccountPasswordHash is stored as an organization account's password hash.
+const organizationAccountPasswordHash = "!organization"
+
+// NewOrganizationAccount returns the account user for a new organization.
+func NewOrganizationAccount() *User {
+	suffix := strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+	return &User{
+		Username:     "org-" + suffix,
+		Email:        fmt.Sprintf("org-%s@organizations.invalid", suffix),
+		PasswordHash: organizationAccountPasswordHash,
+		Role:         OrganizationAccountRole,
+	}
+}
+
+// Organization member roles
+const (
+	// OrgRoleOwner manages members and can spend and approve
+	OrgRoleOwner = "owner"
+	// OrgRoleApprover can spend and approve other members' spending
+	OrgRoleApprover = "approver"
+	// OrgRoleViewer can only see the organization's balance and transactions
+	OrgRoleViewer = "viewer"
+)
+
+// Organization is a group of users sharing a balance.
+type Organization struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	AccountUserID int       `json:"account_user_id"`
+	CreatedBy     int       `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Validate validates the organization's business logic
+func (o *Organization) Validate() error {
+	name := strings.TrimSpace(o.Name)
+	if name == "" || len(name) > 100 {
+		return &ValidationError{Msg: "name must be 1-100 characters"}
+	}
+	return nil
+}
+
+// OrganizationMember is a user's membership of an organization
+type OrganizationMember struct {
+	OrganizationID int       `json:"organization_id"`
+	UserID         int       `json:"user_id"`
+	Role           string    `json:"role"`
+	AddedAt        time.Time `json:"added_at"`
+}
+
+// ValidOrganizationRole reports whether role is one of the member roles
+func ValidOrganizationRole(role string) bool {
+	return role == OrgRoleOwner || role == OrgRoleApprover || role == OrgRoleViewer
+}
+
+// CanManage reports whether the member may add, change and remove members
+func (m *OrganizationMember) CanManage() bool {
+	return m.Role == OrgRoleOwner
+}
+
+// CanSpend reports whether the member may move the organization's money.
+func (m *OrganizationMember) CanSpend() bool {
+	return m.Role == OrgRoleOwner || m.Role == OrgRoleApprover
+}
+
+// OrganizationTransaction is the sign-off record of an organization's payment.
+type OrganizationTransaction struct {
+	Approval
+	OrganizationID int `json:"organization_id"`
+}
+
+// NewOrganizationTransaction creates a pending sign-off for a transaction
+func NewOrganizationTransaction(organizationID, transactionID, requestedBy int) *OrganizationTransaction {
+	return &OrganizationTransaction{
+		Approval:       *NewApproval(transactionID, requestedBy),
+		OrganizationID: organizationID,
+	}
+}
+
+// OrganizationDetails is an organization with its members and shared balance
+type OrganizationDetails struct {
+	*Organization
+	Members []*OrganizationMember `json:"members"`
+	Balance float64               `json:"balance"`
+}
@@ -0,0 +1,48 @@
+package domain
+
+import "context"
+
+// OrganizationRepository defines the interface for organization data access
+type OrganizationRepository interface {
+	// Create creates an organization together with its first owner
+	Create(ctx context.Context, org *Organization, owner *OrganizationMember) error
+
+	// GetByID retrieves an organization, or nil if it does not exist
+	GetByID(ctx context.Context, id int) (*Organization, error)
+
+	// ListByMember retrieves the organizations a user is a member of
+	ListByMember(ctx context.Context, userID int) ([]*Organization, error)
+
+	// GetMember retrieves a user's membership, or nil if they are not a member
+	GetMember(ctx context.Context, organizationID, userID int) (*OrganizationMember, error)
+
+	// ListMembers retrieves the members of an organization, oldest first
+	ListMembers(ctx context.Context, organizationID int) ([]*OrganizationMember, error)
+
+	// AddMember adds a member, returning ErrOrganizationMemberExists if they already are one
+	AddMember(ctx context.Context, member *OrganizationMember) error
+
+	// UpdateMemberRole changes a member's role
+	UpdateMemberRole(ctx context.Context, organizationID, userID int, role string) error
+
+	// RemoveMember removes a member
+	RemoveMember(ctx context.Context, organizationID, userID int) error
+
+	// CountOwners counts the owners of an organization
+	CountOwners(ctx context.Context, organizationID int) (int, error)
+
+	// CreateTransaction creates the sign-off record of an organization transaction
+	CreateTransaction(ctx context.Context, ot *OrganizationTransaction) error
+
+	// GetTransaction retrieves the sign-off record of a transaction of an
+	// organization, or nil if there is none
+	GetTransaction(ctx context.Context, organizationID, transactionID int) (*OrganizationTransaction, error)
+
+	// ListTransactions retrieves an organization's sign-off records, newest
+	// first, optionally only those with status
+	ListTransactions(ctx context.Context, organizationID int, status string, limit, offset int) ([]*OrganizationTransaction, error)
+
+	// UpdateTransaction stores the review of a sign-off record. It fails with
+	// ErrApprovalAlreadyReviewed if the record was reviewed in the meantime.
+	UpdateTransaction(ctx context.Context, ot *OrganizationTransaction) error
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "context"
+
+// OrganizationService defines business logic for organizations and their members.
+type OrganizationService interface {
+	// Create creates an organization owned by actorID, with an empty balance
+	Create(ctx context.Context, actorID int, name string) (*Organization, error)
+
+	// ListForUser retrieves the organizations a user is a member of
+	ListForUser(ctx context.Context, userID int) ([]*Organization, error)
+
+	// Get retrieves an organization with its members and balance
+	Get(ctx context.Context, actorID int, isAdmin bool, organizationID int) (*OrganizationDetails, error)
+
+	// AddMember adds a user with a role; only owners manage members
+	AddMember(ctx context.Context, actorID, organizationID, userID int, role string) (*OrganizationMember, error)
+
+	// UpdateMemberRole changes a member's role; only owners manage members
+	UpdateMemberRole(ctx context.Context, actorID, organizationID, userID int, role string) (*OrganizationMember, error)
+
+	// RemoveMember removes a member; owners remove anyone, members themselves
+	RemoveMember(ctx context.Context, actorID, organizationID, userID int) error
+
+	// Deposit transfers amount from actorID's own balance to the organization
+	Deposit(ctx context.Context, actorID, organizationID int, amount float64) (*Transaction, error)
+
+	// RequestTransaction holds a debit or transfer from the organization's
+	// balance until another owner or approver signs it off
+	RequestTransaction(ctx context.Context, actorID, organizationID int, txType string, toUserID *int, amount float64) (*OrganizationTransaction, error)
+
+	// ApproveTransaction signs off a held transaction and executes it
+	ApproveTransaction(ctx context.Context, actorID, organizationID, transactionID int) (*Transaction, error)
+
+	// RejectTransaction cancels a held transaction without moving any money
+	RejectTransaction(ctx context.Context, actorID, organizationID, transactionID int, reason string) (*Transaction, error)
+
+	// ListTransactions retrieves a page of the transactions of the organization's balance, newest first
+	ListTransactions(ctx context.Context, actorID int, isAdmin bool, organizationID int, limit, offset int) ([]*Transaction, error)
+
+	// ListSignOffs retrieves the organization's sign-off records, optionally only those with status
+	ListSignOffs(ctx context.Context, actorID int, isAdmin bool, organizationID int, status string, limit, offset int) ([]*OrganizationTransaction, error)
+}
@@ -0,0 +1,11 @@
+package domain
+
+// PasswordHasher creates and checks password hashes
+type PasswordHasher interface {
+	// Hash returns the encoded hash of password
+	Hash(password string) (string, error)
+	// Verify reports whether password matches encoded; a mismatch is not an error
+	Verify(encoded, password string) (bool, error)
+	// NeedsRehash reports whether encoded should be replaced by a fresh Hash
+	NeedsRehash(encoded string) bool
+}
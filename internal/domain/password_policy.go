@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MaxPasswordLength is the longest password accepted.
+const MaxPasswordLength = 72
+
+// BreachedPasswordChecker reports whether a password is known from a data breach
+type BreachedPasswordChecker interface {
+	IsBreached(password string) bool
+}
+
+// PasswordPolicy describes the passwords users may choose.
+type PasswordPolicy struct {
+	MinLength       int
+	RequiredClasses int
+	Breached        BreachedPasswordChecker
+}
+
+// Check returns a ValidationError listing every rule password breaks.
+func (p PasswordPolicy) Check(password, username, email string) error {
+	var problems []string
+
+	length := len([]rune(password))
+	if length < p.MinLength {
+		problems = append(problems, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+	if len(password) > MaxPasswordLength {
+		problems = append(problems, fmt.Sprintf("must be at most %d bytes", MaxPasswordLength))
+	}
+	if classes := characterClasses(password); classes < p.RequiredClasses {
+		problems = append(problems, fmt.Sprintf("must mix at least %d of lower case, upper case, digits and symbols", p.RequiredClasses))
+	}
+
+	lower := strings.ToLower(password)
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+	for _, personal := range []string{strings.ToLower(username), local} {
+		if len(personal) >= 3 && strings.Contains(lower, personal) {
+			problems = append(problems, "must not contain your username or email")
+			break
+		}
+	}
+
+	if p.Breached != nil && p.Breached.IsBreached(password) {
+		problems = append(problems, "has appeared in a data breach, choose another one")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Msg: "password " + strings.Join(problems, "; ")}
+	}
+	return nil
+}
+
+// characterClasses counts how many of lower case, upper case, digits and symbols appear in s
+func characterClasses(s string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	n := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+)
+
+var (
+	// ErrPaymentLinkNotFound is returned when a payment link token is malformed, forged or unknown
+	ErrPaymentLinkNotFound = NewError(ErrorKindNotFound, "payment_link_not_found", "payment link not found")
+	// ErrPaymentLinkUnavailable is returned when a payment link has expired or was already redeemed
+	ErrPaymentLinkUnavailable = NewError(ErrorKindGone, "payment_link_unavailable", "payment link has expired or was already used")
+)
+
+// MaxPaymentLinkTTL caps how long a payment link stays redeemable
+const MaxPaymentLinkTTL = 30 * 24 * time.Hour
+
+// PaymentLink is a shareable, one-time request for a fixed amount.
+type PaymentLink struct {
+	ID            int        `json:"id"`
+	CreatorID     int        `json:"creator_id"`
+	Amount        float64    `json:"amount"`
+	Description   string     `json:"description,omitempty"`
+	Status        string     `json:"status"` // "active", "redeemed"
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RedeemedBy    *int       `json:"redeemed_by,omitempty"`
+	TransactionID *int       `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	RedeemedAt    *time.Time `json:"redeemed_at,omitempty"`
+}
+
+// NewPaymentLink creates an active payment link that expires after ttl
+func NewPaymentLink(creatorID int, amount float64, description string, ttl time.Duration) (*PaymentLink, error) {
+	if amount <= 0 {
+		return nil, &ValidationError{Msg: "amount must be positive"}
+	}
+	if ttl <= 0 || ttl > MaxPaymentLinkTTL {
+		return nil, &ValidationError{Msg: "expiry must be between 1 second and 30 days"}
+	}
+	if len(description) > 255 {
+		return nil, &ValidationError{Msg: "description must be at most 255 characters"}
+	}
+	now := time.Now().UTC()
+	return &PaymentLink{
+		CreatorID:   creatorID,
+		Amount:      amount,
+		Description: description,
+		Status:      "active",
+		// Whole seconds so the expiry survives the round trip through the signed token
+		ExpiresAt: now.Add(ttl).Truncate(time.Second),
+		CreatedAt: now,
+	}, nil
+}
+
+// IsRedeemable reports whether the link is unused and not yet expired
+func (l *PaymentLink) IsRedeemable(now time.Time) bool {
+	return l.Status == "active" && now.Before(l.ExpiresAt)
+}
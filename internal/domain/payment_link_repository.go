@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+// PaymentLinkRepository defines the interface for payment link data access
+type PaymentLinkRepository interface {
+	// Create creates a new payment link
+	Create(ctx context.Context, link *PaymentLink) error
+
+	// GetByID retrieves a payment link by ID
+	GetByID(ctx context.Context, id int) (*PaymentLink, error)
+
+	// ListByCreator retrieves the links a user has created, newest first
+	ListByCreator(ctx context.Context, creatorID int) ([]*PaymentLink, error)
+
+	// Claim atomically marks an active, unexpired link as redeemed by payerID.
+	// It returns false if the link was already redeemed or has expired.
+	Claim(ctx context.Context, id, payerID int) (bool, error)
+
+	// Update updates a payment link
+	Update(ctx context.Context, link *PaymentLink) error
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PaymentLinkService defines business logic for shareable payment links
+type PaymentLinkService interface {
+	// CreateLink creates a payment link and returns it with its signed token
+	CreateLink(ctx context.Context, creatorID int, amount float64, description string, ttl time.Duration) (*PaymentLink, string, error)
+
+	// GetLink resolves a signed token to its payment link
+	GetLink(ctx context.Context, token string) (*PaymentLink, error)
+
+	// ListLinks retrieves the links a user has created
+	ListLinks(ctx context.Context, creatorID int) ([]*PaymentLink, error)
+
+	// Redeem pays the link's amount from payerID to the link's creator. A link can be redeemed once.
+	// A transfer held for approval returns the redeemed link with ErrTransactionHeld.
+	Redeem(ctx context.Context, token string, payerID int) (*PaymentLink, error)
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	// ErrReceiveCodeInvalid is returned when a receive code's token is
+	// malformed or forged
+	ErrReceiveCodeInvalid = NewError(ErrorKindNotFound, "receive_code_invalid", "receive code not found")
+	// ErrReceiveCodeExpired is returned when redeeming a receive code past
+	// its expiry
+	ErrReceiveCodeExpired = NewError(ErrorKindGone, "receive_code_expired", "receive code has expired")
+	// ErrReceiveUserNotFound is returned when the user a receive code pays
+	// does not exist
+	ErrReceiveUserNotFound = NewError(ErrorKindNotFound, "user_not_found", "user not found")
+)
+
+// MaxReceiveCodeTTL caps how long a receive code stays redeemable
+const MaxReceiveCodeTTL = 30 * 24 * time.Hour
+
+// ReceiveCode asks whoever scans it to pay UserID, optionally a fixed Amount.
+type ReceiveCode struct {
+	UserID    int       `json:"user_id"`
+	Amount    float64   `json:"amount,omitempty"`
+	Reference string    `json:"reference,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Validate checks the code's amount and reference
+func (c *ReceiveCode) Validate() error {
+	if c.Amount < 0 {
+		return &ValidationError{Msg: "amount must not be negative"}
+	}
+	if len(c.Reference) > 64 {
+		return &ValidationError{Msg: "reference must be at most 64 characters"}
+	}
+	return nil
+}
+
+// TransferDraft is a transfer pre-filled from a receive code.
+type TransferDraft struct {
+	FromUserID     int       `json:"from_user_id"`
+	ToUserID       int       `json:"to_user_id"`
+	ToUsername     string    `json:"to_username"`
+	Amount         float64   `json:"amount,omitempty"`
+	AmountFixed    bool      `json:"amount_fixed"`
+	Reference      string    `json:"reference,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	IdempotencyKey string    `json:"idempotency_key,omitempty"`
+}
+
+// ReceiveCodeService defines business logic for signed receive codes.
+type ReceiveCodeService interface {
+	// Issue returns a signed token of a receive code for its user, valid for ttl
+	Issue(ctx context.Context, code *ReceiveCode, ttl time.Duration) (string, error)
+	// Redeem verifies a token and pre-fills the transfer payerID makes to
+	// its user
+	Redeem(ctx context.Context, token string, payerID int) (*TransferDraft, error)
+}
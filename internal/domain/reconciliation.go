@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// ReconciliationTolerance is the largest balance difference still treated as equal.
+const ReconciliationTolerance = 0.005
+
+// ReconciliationRun is one pass comparing stored balances with recomputed ones.
+type ReconciliationRun struct {
+	ID              int        `json:"id"`
+	Status          string     `json:"status"` // "running", "completed", "failed"
+	BalancesChecked int        `json:"balances_checked"`
+	IssuesFound     int        `json:"issues_found"`
+	Error           string     `json:"error,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+
+// Complete marks the run as finished successfully
+func (r *ReconciliationRun) Complete(balancesChecked, issuesFound int) {
+	now := time.Now().UTC()
+	r.Status = "completed"
+	r.BalancesChecked = balancesChecked
+	r.IssuesFound = issuesFound
+	r.FinishedAt = &now
+}
+
+// Fail marks the run as aborted by err
+func (r *ReconciliationRun) Fail(err error) {
+	now := time.Now().UTC()
+	r.Status = "failed"
+	r.Error = err.Error()
+	r.FinishedAt = &now
+}
+
+// BalanceSnapshot is a user's stored balance as it was at the start of a run
+type BalanceSnapshot struct {
+	RunID      int       `json:"run_id"`
+	UserID     int       `json:"user_id"`
+	Amount     float64   `json:"amount"`
+	HeldAmount float64   `json:"held_amount"`
+	Version    int       `json:"version"`
+	TakenAt    time.Time `json:"taken_at"`
+}
+
+// ReconciliationIssue records a stored balance that disagrees with its ledger
+type ReconciliationIssue struct {
+	ID             int       `json:"id"`
+	RunID          int       `json:"run_id"`
+	UserID         int       `json:"user_id"`
+	StoredAmount   float64   `json:"stored_amount"`
+	ComputedAmount float64   `json:"computed_amount"`
+	Difference     float64   `json:"difference"` // stored minus computed
+	DetectedAt     time.Time `json:"detected_at"`
+}
+
+// NewReconciliationIssue returns an issue if a snapshot and computed amount differ.
+func NewReconciliationIssue(snapshot *BalanceSnapshot, computedAmount float64) *ReconciliationIssue {
+	diff := snapshot.Amount - computedAmount
+	if math.Abs(diff) < ReconciliationTolerance {
+		return nil
+	}
+	return &ReconciliationIssue{
+		RunID:          snapshot.RunID,
+		UserID:         snapshot.UserID,
+		StoredAmount:   snapshot.Amount,
+		ComputedAmount: computedAmount,
+		Difference:     math.Round(diff*100) / 100,
+		DetectedAt:     time.Now().UTC(),
+	}
+}
+
+// ReconciliationReport is the latest run together with the issues it found
+type ReconciliationReport struct {
+	LastRun *ReconciliationRun     `json:"last_run"`
+	Issues  []*ReconciliationIssue `json:"issues"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "context"
+
+// ReconciliationRepository defines the interface for balance reconciliation data access
+type ReconciliationRepository interface {
+	// CreateRun creates a new reconciliation run
+	CreateRun(ctx context.Context, run *ReconciliationRun) error
+
+	// UpdateRun updates a reconciliation run
+	UpdateRun(ctx context.Context, run *ReconciliationRun) error
+
+	// GetLatestRun retrieves the most recently started run
+	GetLatestRun(ctx context.Context) (*ReconciliationRun, error)
+
+	// SnapshotBalances copies every stored balance into the run's snapshot in a single statement
+	SnapshotBalances(ctx context.Context, runID int) ([]*BalanceSnapshot, error)
+
+	// CreateIssue records a discrepancy found by a run
+	CreateIssue(ctx context.Context, issue *ReconciliationIssue) error
+
+	// ListIssuesByRun retrieves the discrepancies found by a run, largest first
+	ListIssuesByRun(ctx context.Context, runID, limit, offset int) ([]*ReconciliationIssue, error)
+}
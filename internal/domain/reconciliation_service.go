@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+// ReconciliationService defines business logic for checking stored balances against the ledger
+type ReconciliationService interface {
+	// Run snapshots every stored balance, recomputes it from transactions and records discrepancies
+	Run(ctx context.Context) (*ReconciliationRun, error)
+
+	// GetReport retrieves the latest run and a page of the issues it found
+	GetReport(ctx context.Context, limit, offset int) (*ReconciliationReport, error)
+
+	// Start begins running reconciliation nightly in the background
+	Start(ctx context.Context)
+
+	// Stop stops the background reconciliation
+	Stop()
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Referral statuses.
+const (
+	ReferralPending  = "pending"
+	ReferralRewarded = "rewarded"
+	ReferralExpired  = "expired"
+)
+
+var (
+	// ErrReferralUserNotFound is returned when assigning a referral code to a
+	// user who does not exist
+	ErrReferralUserNotFound = NewError(ErrorKindNotFound, "user_not_found", "user not found")
+	// ErrReferralCodeInvalid is returned when registering with a referral code
+	// that belongs to no user
+	ErrReferralCodeInvalid = NewError(ErrorKindValidation, "referral_code_invalid", "referral code is invalid")
+	// ErrReferralCodeTaken is returned when a generated referral code is
+	// already some other user's
+	ErrReferralCodeTaken = NewError(ErrorKindConflict, "referral_code_taken", "referral code already exists")
+	// ErrReferralNotPending is returned when rewarding a referral that was
+	// already rewarded or has expired
+	ErrReferralNotPending = NewError(ErrorKindConflict, "referral_not_pending", "referral is not pending")
+)
+
+// ReferralProgram configures the rewards of referrals.
+type ReferralProgram struct {
+	ReferrerReward float64
+	RefereeReward  float64
+	MinAmount      float64
+	Window         time.Duration
+}
+
+// Referral records a referee registering with a referrer's code.
+type Referral struct {
+	ID                    int        `json:"id"`
+	ReferrerID            int        `json:"referrer_id"`
+	RefereeID             int        `json:"referee_id"`
+	RefereeUsername       string     `json:"referee_username"`
+	Status                string     `json:"status"` // pending, rewarded or expired
+	ExpiresAt             *time.Time `json:"expires_at,omitempty"`
+	TransactionID         *int       `json:"transaction_id,omitempty"`
+	ReferrerReward        float64    `json:"referrer_reward"`
+	RefereeReward         float64    `json:"referee_reward"`
+	ReferrerTransactionID *int       `json:"referrer_transaction_id,omitempty"`
+	RefereeTransactionID  *int       `json:"referee_transaction_id,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+	RewardedAt            *time.Time `json:"rewarded_at,omitempty"`
+}
+
+// ReferralSummary is a user's referral code and how their referrals went
+type ReferralSummary struct {
+	UserID   int     `json:"user_id"`
+	Code     string  `json:"code"`
+	Referred int     `json:"referred"`
+	Pending  int     `json:"pending"`
+	Rewarded int     `json:"rewarded"`
+	Expired  int     `json:"expired"`
+	Earnings float64 `json:"earnings"` // the referrer rewards credited to the user
+}
+
+// ReferralRepository defines methods for referral data access
+type ReferralRepository interface {
+	// GetCode fetches a user's referral code, or "" if they have none yet
+	GetCode(ctx context.Context, userID int) (string, error)
+	// SetCode assigns a referral code to a user who has none, returning
+	// ErrReferralCodeTaken if another user has it. It returns the user's
+	// code, which is an earlier one if they already had a code, or
+	// ErrReferralUserNotFound if there is no such user.
+	SetCode(ctx context.Context, userID int, code string) (string, error)
+	// GetReferrerByCode fetches the ID of the user, not erased, whose referral
+	// code it is, or 0 if there is none
+	GetReferrerByCode(ctx context.Context, code string) (int, error)
+	// Create stores a referral
+	Create(ctx context.Context, referral *Referral) error
+	// GetPendingByReferee fetches the unexpired pending referral of a referee
+	// at a time, or nil if there is none
+	GetPendingByReferee(ctx context.Context, refereeID int, at time.Time) (*Referral, error)
+	// ListByReferrer fetches a user's referrals, newest first
+	ListByReferrer(ctx context.Context, referrerID int) ([]*Referral, error)
+	// Summarize counts a user's referrals and sums their earnings
+	Summarize(ctx context.Context, referrerID int) (*ReferralSummary, error)
+	// MarkRewarded stores the rewards of a referral still pending and
+	// unexpired, returning ErrReferralNotPending otherwise
+	MarkRewarded(ctx context.Context, referral *Referral) error
+}
+
+// ReferralAttributor attributes new users to the users who referred them
+type ReferralAttributor interface {
+	// Referrer returns the ID of the user whose referral code it is, or
+	// ErrReferralCodeInvalid
+	Referrer(ctx context.Context, code string) (int, error)
+	// Attribute records that refereeID registered with referrerID's code
+	Attribute(ctx context.Context, referrerID, refereeID int) error
+}
+
+// ReferralService defines business logic for referrals
+type ReferralService interface {
+	TransactionRewarder
+	ReferralAttributor
+	// Summary returns a user's referral code, assigning one if they have
+	// none, and how their referrals went
+	Summary(ctx context.Context, userID int) (*ReferralSummary, error)
+	// List returns a user's referrals, newest first
+	List(ctx context.Context, userID int) ([]*Referral, error)
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Quota periods: requests are counted per UTC calendar day or month
+const (
+	QuotaPeriodDay   = "day"
+	QuotaPeriodMonth = "month"
+)
+
+var (
+	// ErrQuotaNotFound is returned when a role or user has no quota for a period
+	ErrQuotaNotFound = NewError(ErrorKindNotFound, "quota_not_found", "quota not found")
+	// ErrQuotaUserNotFound is returned when a quota is set for a user who doesn't exist
+	ErrQuotaUserNotFound = NewError(ErrorKindNotFound, "user_not_found", "user not found")
+)
+
+// RequestQuota caps the API requests a user makes in a period.
+type RequestQuota struct {
+	ID        int       `json:"id"`
+	Role      string    `json:"role,omitempty"`    // set for a role's quota
+	UserID    *int      `json:"user_id,omitempty"` // set for a user's quota
+	Period    string    `json:"period"`
+	Limit     int64     `json:"limit"`
+	UpdatedBy int       `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the quota's subject, period and limit
+func (q *RequestQuota) Validate() error {
+	if (q.Role == "") == (q.UserID == nil) {
+		return &ValidationError{Msg: "a quota applies to either a role or a user"}
+	}
+	if q.Role != "" && q.Role != "user" && q.Role != "admin" {
+		return &ValidationError{Msg: "role must be user or admin"}
+	}
+	if q.Period != QuotaPeriodDay && q.Period != QuotaPeriodMonth {
+		return &ValidationError{Msg: "period must be day or month"}
+	}
+	if q.Limit < 0 {
+		return &ValidationError{Msg: "limit must not be negative"}
+	}
+	return nil
+}
+
+// QuotaPeriodBounds returns the UTC day or month containing t
+func QuotaPeriodBounds(period string, t time.Time) (start, end time.Time) {
+	t = t.UTC()
+	if period == QuotaPeriodMonth {
+		start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+	start = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// QuotaUsage is a user's use of a quota in the current period
+type QuotaUsage struct {
+	Period    string    `json:"period"`
+	Limit     int64     `json:"limit"`
+	Used      int64     `json:"used"`
+	Remaining int64     `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+// Exceeded reports whether more requests were made than the quota allows
+func (u QuotaUsage) Exceeded() bool {
+	return u.Used > u.Limit
+}
+
+// RequestQuotaRepository defines methods for request quota data access
+type RequestQuotaRepository interface {
+	// List fetches every quota, role quotas first
+	List(ctx context.Context) ([]*RequestQuota, error)
+	// Set stores a quota, replacing the one of its subject and period
+	Set(ctx context.Context, quota *RequestQuota) error
+	// Delete removes the quota of a subject and period, returning
+	// ErrQuotaNotFound if there is none
+	Delete(ctx context.Context, role string, userID *int, period string) error
+}
+
+// QuotaCounter counts a user's requests per period
+type QuotaCounter interface {
+	// Increment counts a request in the period starting at start and ending
+	// at end, returning the requests counted so far
+	Increment(ctx context.Context, userID int, period string, start, end time.Time) (int64, error)
+	// Count returns the requests counted in the period starting at start
+	Count(ctx context.Context, userID int, period string, start time.Time) (int64, error)
+}
+
+// RequestQuotaService defines business logic for request quotas
+type RequestQuotaService interface {
+	// List returns every quota, role quotas first
+	List(ctx context.Context) ([]*RequestQuota, error)
+	// Set sets a quota on behalf of actorID
+	Set(ctx context.Context, actorID int, quota *RequestQuota) error
+	// Remove removes the quota of a role or user and period on behalf of actorID
+	Remove(ctx context.Context, actorID int, role string, userID *int, period string) error
+	// Usage returns a user's use of their quotas in the current periods
+	Usage(ctx context.Context, userID int) ([]QuotaUsage, error)
+	// Consume counts a request of a user against their quotas, returning
+	// their use including it
+	Consume(ctx context.Context, userID int, role string) ([]QuotaUsage, error)
+}
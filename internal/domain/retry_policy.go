@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how failed scheduled executions and worker tasks are retried
+type RetryPolicy struct {
+	MaxAttempts    int           // total attempts per run or task, including the first; 1 disables retries
+	InitialBackoff time.Duration // delay before the first retry
+	MaxBackoff     time.Duration // upper bound for any single delay
+	Multiplier     float64       // growth factor applied per retry
+	Jitter         float64       // fraction of the delay randomised in either direction, 0-1
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     time.Hour,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// CanRetry reports whether another attempt is allowed after retryCount retries
+func (p RetryPolicy) CanRetry(retryCount int) bool {
+	return retryCount+1 < p.MaxAttempts
+}
+
+// Backoff returns the delay before retry number retryCount+1 (zero-based retryCount)
+func (p RetryPolicy) Backoff(retryCount int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retryCount))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay += delay * jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// SavingsGoal represents a user-defined target amount to save towards, optionally by a deadline.
+type SavingsGoal struct {
+	ID                     int        `json:"id"`
+	UserID                 int        `json:"user_id"`
+	Name                   string     `json:"name"`
+	TargetAmount           float64    `json:"target_amount"`
+	CurrentAmount          float64    `json:"current_amount"`
+	Deadline               *time.Time `json:"deadline,omitempty"`
+	Status                 string     `json:"status"`                             // "active", "completed", "cancelled"
+	ScheduledTransactionID *int       `json:"scheduled_transaction_id,omitempty"` // recurring contribution, if any
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+}
+
+// SavingsGoalProgress describes how far a goal is from completion and when it is projected to finish.
+type SavingsGoalProgress struct {
+	GoalID              int        `json:"goal_id"`
+	TargetAmount        float64    `json:"target_amount"`
+	CurrentAmount       float64    `json:"current_amount"`
+	RemainingAmount     float64    `json:"remaining_amount"`
+	PercentComplete     float64    `json:"percent_complete"`
+	Deadline            *time.Time `json:"deadline,omitempty"`
+	ProjectedCompletion *time.Time `json:"projected_completion,omitempty"`
+	OnTrack             bool       `json:"on_track"`
+}
+
+// Validate validates the savings goal's business logic
+func (g *SavingsGoal) Validate() error {
+	if g.UserID <= 0 {
+		return &ValidationError{Msg: "user_id must be positive"}
+	}
+	if strings.TrimSpace(g.Name) == "" {
+		return &ValidationError{Msg: "name is required"}
+	}
+	if g.TargetAmount <= 0 {
+		return &ValidationError{Msg: "target_amount must be positive"}
+	}
+	if g.CurrentAmount < 0 {
+		return &ValidationError{Msg: "current_amount cannot be negative"}
+	}
+	if g.Status != "active" && g.Status != "completed" && g.Status != "cancelled" {
+		return &ValidationError{Msg: "status must be active, completed, or cancelled"}
+	}
+	if g.Deadline != nil && g.Deadline.Before(time.Now().UTC()) {
+		return &ValidationError{Msg: "deadline must be in the future"}
+	}
+	return nil
+}
+
+// RemainingAmount returns how much is still needed to reach the target.
+func (g *SavingsGoal) RemainingAmount() float64 {
+	if g.CurrentAmount >= g.TargetAmount {
+		return 0
+	}
+	return g.TargetAmount - g.CurrentAmount
+}
+
+// PercentComplete returns the progress towards the target as a percentage capped at 100.
+func (g *SavingsGoal) PercentComplete() float64 {
+	if g.TargetAmount <= 0 {
+		return 0
+	}
+	pct := g.CurrentAmount / g.TargetAmount * 100
+	if pct > 100 {
+		return 100
+	}
+	return pct
+}
+
+// IsReached reports whether the goal's target has been met.
+func (g *SavingsGoal) IsReached() bool {
+	return g.CurrentAmount >= g.TargetAmount
+}
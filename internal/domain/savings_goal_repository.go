@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+// SavingsGoalRepository defines the interface for savings goal data access
+type SavingsGoalRepository interface {
+	// Create creates a new savings goal
+	Create(ctx context.Context, goal *SavingsGoal) error
+
+	// GetByID retrieves a savings goal by ID
+	GetByID(ctx context.Context, id int) (*SavingsGoal, error)
+
+	// ListByUser retrieves all savings goals for a user
+	ListByUser(ctx context.Context, userID int) ([]*SavingsGoal, error)
+
+	// Update updates a savings goal
+	Update(ctx context.Context, goal *SavingsGoal) error
+
+	// AddContribution atomically adds amount to the goal and returns the updated goal.
+	// The goal is marked completed once the target is reached.
+	AddContribution(ctx context.Context, goalID int, amount float64) (*SavingsGoal, error)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// SavingsGoalService defines the interface for savings goal business logic
+type SavingsGoalService interface {
+	// CreateGoal creates a new savings goal
+	CreateGoal(ctx context.Context, goal *SavingsGoal) error
+
+	// GetGoal retrieves a savings goal by ID
+	GetGoal(ctx context.Context, id int) (*SavingsGoal, error)
+
+	// ListUserGoals retrieves all savings goals for a user
+	ListUserGoals(ctx context.Context, userID int) ([]*SavingsGoal, error)
+
+	// CancelGoal cancels a savings goal and its recurring contribution, if any
+	CancelGoal(ctx context.Context, id int) error
+
+	// Contribute moves amount from the user's balance into the goal
+	Contribute(ctx context.Context, goalID int, amount float64) (*SavingsGoal, error)
+
+	// SetupRecurringContribution links a recurring scheduled debit that funds the goal
+	SetupRecurringContribution(ctx context.Context, goalID int, amount float64, recurrence string, startAt time.Time) (*ScheduledTransaction, error)
+
+	// GetProgress returns the goal's progress and projected completion date
+	GetProgress(ctx context.Context, goalID int) (*SavingsGoalProgress, error)
+}
@@ -2,34 +2,32 @@ package domain
 
 import (
 	"time"
-)
-
-// ValidationError is a custom error type for validation failures
-type ValidationError struct {
-	Msg string
-}
 
-func (e *ValidationError) Error() string {
-	return e.Msg
-}
+	"github.com/melihgurlek/backend-path/pkg/cron"
+)
 
 // ScheduledTransaction represents a transaction that will be executed at a future time
 type ScheduledTransaction struct {
-	ID          int        `json:"id"`
-	UserID      int        `json:"user_id"`
-	ToUserID    *int       `json:"to_user_id,omitempty"` // for transfers
-	Amount      float64    `json:"amount"`
-	Type        string     `json:"type"`   // "credit", "debit", "transfer"
-	Status      string     `json:"status"` // "pending", "completed", "failed", "cancelled"
-	ScheduleAt  time.Time  `json:"schedule_at"`
-	Recurring   bool       `json:"recurring"`
-	Recurrence  string     `json:"recurrence,omitempty"` // "daily", "weekly", "monthly", "yearly"
-	NextRunAt   *time.Time `json:"next_run_at,omitempty"`
-	MaxRuns     *int       `json:"max_runs,omitempty"`
-	RunsCount   int        `json:"runs_count"`
-	Description string     `json:"description,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID             int        `json:"id"`
+	UserID         int        `json:"user_id"`
+	ToUserID       *int       `json:"to_user_id,omitempty"` // for transfers
+	Amount         float64    `json:"amount"`
+	Type           string     `json:"type"`   // "credit", "debit", "transfer"
+	Status         string     `json:"status"` // "pending", "paused", "completed", "failed", "cancelled"
+	ScheduleAt     time.Time  `json:"schedule_at"`
+	Recurring      bool       `json:"recurring"`
+	Recurrence     string     `json:"recurrence,omitempty"`      // "daily", "weekly", "monthly", "yearly", "cron"
+	CronExpression string     `json:"cron_expression,omitempty"` // required when recurrence is "cron"
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+	MaxRuns        *int       `json:"max_runs,omitempty"`
+	RunsCount      int        `json:"runs_count"`
+	RetryCount     int        `json:"retry_count"`             // failed attempts retried for the current run
+	NextRetryAt    *time.Time `json:"next_retry_at,omitempty"` // set while a failed run is waiting to be retried
+	Description    string     `json:"description,omitempty"`
+	GoalID         *int       `json:"goal_id,omitempty"` // savings goal funded by this transaction
+	TraceParent    string     `json:"-"`                 // W3C traceparent of the request that created it
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
 // Validate validates the scheduled transaction's business logic
@@ -43,14 +41,25 @@ func (st *ScheduledTransaction) Validate() error {
 	if st.Type != "credit" && st.Type != "debit" && st.Type != "transfer" {
 		return &ValidationError{Msg: "type must be credit, debit, or transfer"}
 	}
-	if st.Status != "pending" && st.Status != "completed" && st.Status != "failed" && st.Status != "cancelled" {
-		return &ValidationError{Msg: "status must be pending, completed, failed, or cancelled"}
+	if st.Status != "pending" && st.Status != "paused" && st.Status != "completed" && st.Status != "failed" && st.Status != "cancelled" {
+		return &ValidationError{Msg: "status must be pending, paused, completed, failed, or cancelled"}
 	}
 	if st.ScheduleAt.Before(time.Now().UTC().Add(-10 * time.Second)) {
 		return &ValidationError{Msg: "schedule_at must be in the future"}
 	}
-	if st.Recurring && (st.Recurrence != "daily" && st.Recurrence != "weekly" && st.Recurrence != "monthly" && st.Recurrence != "yearly") {
-		return &ValidationError{Msg: "recurrence must be daily, weekly, monthly, or yearly"}
+	if st.Recurring && (st.Recurrence != "daily" && st.Recurrence != "weekly" && st.Recurrence != "monthly" && st.Recurrence != "yearly" && st.Recurrence != "cron") {
+		return &ValidationError{Msg: "recurrence must be daily, weekly, monthly, yearly, or cron"}
+	}
+	if st.Recurring && st.Recurrence == "cron" {
+		if st.CronExpression == "" {
+			return &ValidationError{Msg: "cron_expression is required when recurrence is cron"}
+		}
+		if err := cron.Validate(st.CronExpression); err != nil {
+			return &ValidationError{Msg: "invalid cron_expression: " + err.Error()}
+		}
+	}
+	if st.CronExpression != "" && st.Recurrence != "cron" {
+		return &ValidationError{Msg: "cron_expression is only allowed when recurrence is cron"}
 	}
 	if st.Recurring && st.MaxRuns != nil && *st.MaxRuns <= 0 {
 		return &ValidationError{Msg: "max_runs must be positive"}
@@ -65,6 +74,10 @@ func (st *ScheduledTransaction) ShouldExecute() bool {
 		return false
 	}
 
+	if st.NextRetryAt != nil {
+		return time.Now().After(*st.NextRetryAt)
+	}
+
 	if st.Recurring {
 		return st.NextRunAt != nil && time.Now().After(*st.NextRunAt)
 	}
@@ -94,11 +107,75 @@ func (st *ScheduledTransaction) CalculateNextRun() *time.Time {
 		nextRun = nextRun.AddDate(0, 1, 0)
 	case "yearly":
 		nextRun = nextRun.AddDate(1, 0, 0)
+	case "cron":
+		schedule, err := cron.Parse(st.CronExpression)
+		if err != nil {
+			return nil
+		}
+		nextRun = schedule.Next(nextRun)
+		if nextRun.IsZero() {
+			return nil
+		}
 	}
 
 	return &nextRun
 }
 
+// FirstRun returns the time of the first execution of a recurring transaction.
+func (st *ScheduledTransaction) FirstRun() *time.Time {
+	if !st.Recurring {
+		return nil
+	}
+
+	first := st.ScheduleAt
+	if st.Recurrence == "cron" {
+		schedule, err := cron.Parse(st.CronExpression)
+		if err != nil {
+			return nil
+		}
+		first = schedule.Next(st.ScheduleAt.Add(-time.Second))
+		if first.IsZero() {
+			return nil
+		}
+	}
+
+	return &first
+}
+
+// Occurrences returns up to n upcoming execution times.
+func (st *ScheduledTransaction) Occurrences(n int) []time.Time {
+	if n <= 0 {
+		return nil
+	}
+	if !st.Recurring {
+		if st.RunsCount > 0 {
+			return nil
+		}
+		return []time.Time{st.ScheduleAt}
+	}
+
+	sim := *st
+	if sim.NextRunAt == nil {
+		sim.NextRunAt = sim.FirstRun()
+	}
+
+	var times []time.Time
+	for len(times) < n && sim.NextRunAt != nil && !sim.ShouldStop() {
+		times = append(times, *sim.NextRunAt)
+		sim.RunsCount++
+		sim.NextRunAt = sim.CalculateNextRun()
+	}
+	return times
+}
+
+// BalanceImpact returns the change a single execution makes to the owner's balance
+func (st *ScheduledTransaction) BalanceImpact() float64 {
+	if st.Type == "credit" {
+		return st.Amount
+	}
+	return -st.Amount // debits and outgoing transfers
+}
+
 // ShouldStop checks if the recurring transaction should stop
 func (st *ScheduledTransaction) ShouldStop() bool {
 	if !st.Recurring {
@@ -115,6 +192,8 @@ func (st *ScheduledTransaction) ShouldStop() bool {
 // MarkCompleted marks the transaction as completed and updates next run
 func (st *ScheduledTransaction) MarkCompleted() {
 	st.RunsCount++
+	st.RetryCount = 0
+	st.NextRetryAt = nil
 	st.UpdatedAt = time.Now()
 
 	if st.ShouldStop() {
@@ -127,7 +206,49 @@ func (st *ScheduledTransaction) MarkCompleted() {
 // MarkFailed marks the transaction as failed
 func (st *ScheduledTransaction) MarkFailed() {
 	st.Status = "failed"
+	st.NextRetryAt = nil
+	st.UpdatedAt = time.Now()
+}
+
+// ScheduleRetry keeps the transaction pending and defers the next attempt until retryAt
+func (st *ScheduledTransaction) ScheduleRetry(retryAt time.Time) {
+	st.RetryCount++
+	st.NextRetryAt = &retryAt
+	st.UpdatedAt = time.Now()
+}
+
+// Pause suspends a pending recurring transaction until it is resumed
+func (st *ScheduledTransaction) Pause() error {
+	if !st.Recurring {
+		return &ValidationError{Msg: "only recurring scheduled transactions can be paused"}
+	}
+	if st.Status != "pending" {
+		return &ValidationError{Msg: "cannot pause " + st.Status + " scheduled transaction"}
+	}
+
+	st.Status = "paused"
 	st.UpdatedAt = time.Now()
+	return nil
+}
+
+// Resume reactivates a paused transaction.
+func (st *ScheduledTransaction) Resume(now time.Time) error {
+	if st.Status != "paused" {
+		return &ValidationError{Msg: "cannot resume " + st.Status + " scheduled transaction"}
+	}
+
+	st.RetryCount = 0
+	st.NextRetryAt = nil
+	if st.NextRunAt == nil {
+		st.NextRunAt = st.FirstRun()
+	}
+	for st.NextRunAt != nil && !st.NextRunAt.After(now) {
+		st.NextRunAt = st.CalculateNextRun()
+	}
+
+	st.Status = "pending"
+	st.UpdatedAt = time.Now()
+	return nil
 }
 
 // MarkCancelled marks the transaction as cancelled
@@ -1,33 +1,36 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // ScheduledTransactionRepository defines the interface for scheduled transaction data access
 type ScheduledTransactionRepository interface {
 	// Create creates a new scheduled transaction
-	Create(st *ScheduledTransaction) error
+	Create(ctx context.Context, st *ScheduledTransaction) error
 
 	// GetByID retrieves a scheduled transaction by ID
-	GetByID(id int) (*ScheduledTransaction, error)
+	GetByID(ctx context.Context, id int) (*ScheduledTransaction, error)
 
 	// GetScheduledTransactionStats returns statistics about scheduled transactions
-	GetScheduledTransactionStats(userID int) (*ScheduledTransactionStats, error)
+	GetScheduledTransactionStats(ctx context.Context, userID int) (*ScheduledTransactionStats, error)
 
 	// ListByUser retrieves all scheduled transactions for a user
-	ListByUser(userID int) ([]*ScheduledTransaction, error)
+	ListByUser(ctx context.Context, userID int) ([]*ScheduledTransaction, error)
 
 	// ListPending retrieves all pending scheduled transactions that should be executed
-	ListPending() ([]*ScheduledTransaction, error)
+	ListPending(ctx context.Context) ([]*ScheduledTransaction, error)
 
 	// Update updates a scheduled transaction
-	Update(st *ScheduledTransaction) error
+	Update(ctx context.Context, st *ScheduledTransaction) error
 
 	// Delete deletes a scheduled transaction
-	Delete(id int) error
+	Delete(ctx context.Context, id int) error
 
 	// ListByStatus retrieves scheduled transactions by status
-	ListByStatus(status string) ([]*ScheduledTransaction, error)
+	ListByStatus(ctx context.Context, status string) ([]*ScheduledTransaction, error)
 
 	// ListByTimeRange retrieves scheduled transactions within a time range
-	ListByTimeRange(from, to time.Time) ([]*ScheduledTransaction, error)
+	ListByTimeRange(ctx context.Context, from, to time.Time) ([]*ScheduledTransaction, error)
 }
@@ -1,33 +1,65 @@
 package domain
 
+import (
+	"context"
+	"time"
+)
+
 // ScheduledTransactionService defines the interface for scheduled transaction business logic
 type ScheduledTransactionService interface {
 	// CreateScheduledTransaction creates a new scheduled transaction
-	CreateScheduledTransaction(st *ScheduledTransaction) error
+	CreateScheduledTransaction(ctx context.Context, st *ScheduledTransaction) error
 
 	// GetScheduledTransaction retrieves a scheduled transaction by ID
-	GetScheduledTransaction(id int) (*ScheduledTransaction, error)
+	GetScheduledTransaction(ctx context.Context, id int) (*ScheduledTransaction, error)
 
 	// ListUserScheduledTransactions retrieves all scheduled transactions for a user
-	ListUserScheduledTransactions(userID int) ([]*ScheduledTransaction, error)
+	ListUserScheduledTransactions(ctx context.Context, userID int) ([]*ScheduledTransaction, error)
 
 	// UpdateScheduledTransaction updates a scheduled transaction
-	UpdateScheduledTransaction(st *ScheduledTransaction) error
+	UpdateScheduledTransaction(ctx context.Context, st *ScheduledTransaction) error
 
 	// CancelScheduledTransaction cancels a scheduled transaction
-	CancelScheduledTransaction(id int) error
+	CancelScheduledTransaction(ctx context.Context, id int) error
+
+	// PauseScheduledTransaction suspends a recurring scheduled transaction
+	PauseScheduledTransaction(ctx context.Context, id int) (*ScheduledTransaction, error)
+
+	// ResumeScheduledTransaction reactivates a paused scheduled transaction
+	ResumeScheduledTransaction(ctx context.Context, id int) (*ScheduledTransaction, error)
+
+	// PreviewScheduledTransaction validates st and projects its next occurrences without persisting it
+	PreviewScheduledTransaction(ctx context.Context, st *ScheduledTransaction, occurrences int) (*ScheduledTransactionPreview, error)
 
 	// ExecuteScheduledTransactions executes all pending scheduled transactions
-	ExecuteScheduledTransactions() error
+	ExecuteScheduledTransactions(ctx context.Context) error
 
 	// GetScheduledTransactionStats returns statistics about scheduled transactions
-	GetScheduledTransactionStats() (*ScheduledTransactionStats, error)
+	GetScheduledTransactionStats(ctx context.Context) (*ScheduledTransactionStats, error)
+}
+
+// ScheduledTransactionPreview describes what a scheduled transaction would do if created
+type ScheduledTransactionPreview struct {
+	Occurrences      []ScheduledOccurrence `json:"occurrences"`
+	CurrentBalance   float64               `json:"current_balance"`
+	TotalImpact      float64               `json:"total_impact"`
+	ProjectedBalance float64               `json:"projected_balance"`
+	Truncated        bool                  `json:"truncated"` // more occurrences exist beyond those listed
+}
+
+// ScheduledOccurrence is a single projected execution in a preview
+type ScheduledOccurrence struct {
+	RunAt             time.Time `json:"run_at"`
+	BalanceImpact     float64   `json:"balance_impact"`
+	ProjectedBalance  float64   `json:"projected_balance"`
+	InsufficientFunds bool      `json:"insufficient_funds,omitempty"`
 }
 
 // ScheduledTransactionStats holds statistics about scheduled transactions
 type ScheduledTransactionStats struct {
 	TotalScheduled    int64
 	PendingCount      int64
+	PausedCount       int64
 	CompletedCount    int64
 	FailedCount       int64
 	CancelledCount    int64
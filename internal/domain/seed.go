@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// SeedRepository writes generated demo and load-test data in bulk
+type SeedRepository interface {
+	// InsertTransactions inserts transactions as given, keeping their CreatedAt
+	InsertTransactions(ctx context.Context, txs []*Transaction) error
+	// SetBalance creates or overwrites a user's balance
+	SetBalance(ctx context.Context, userID int, amount float64) error
+	// BackdateUser sets when a user signed up
+	BackdateUser(ctx context.Context, userID int, createdAt time.Time) error
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TokenStore keeps track of revoked access tokens.
+type TokenStore interface {
+	// Revoke invalidates the token with ID jti, which expires at expiresAt
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	// RevokeAll invalidates every token issued to a user so far
+	RevokeAll(ctx context.Context, userID int) error
+	// TokenVersion returns the version a user's new tokens are issued with
+	TokenVersion(ctx context.Context, userID int) (int64, error)
+	// IsRevoked reports whether the token with ID jti, issued to a user with
+	// version, has been revoked
+	IsRevoked(ctx context.Context, jti string, userID int, version int64) (bool, error)
+}
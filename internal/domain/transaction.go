@@ -2,9 +2,33 @@ package domain
 
 import (
 	"errors"
+	"strconv"
 	"time"
 )
 
+// ErrIdempotencyKeyReused is returned when a key already recorded another transaction.
+var ErrIdempotencyKeyReused = NewError(ErrorKindConflict, "idempotency_key_reused", "idempotency key was already used for a different transaction")
+
+var (
+	// ErrTransactionHeld is returned with a transaction recorded as
+	// "pending_approval" instead of moving money
+	ErrTransactionHeld = NewError(ErrorKindPending, "transaction_held", "transaction is held for approval")
+	// ErrTransactionNotPending is returned when a transaction held for
+	// approval was already approved, rejected or failed
+	ErrTransactionNotPending = NewError(ErrorKindConflict, "transaction_not_pending", "transaction is no longer pending approval")
+	// ErrTransactionNotCompleted is returned for an idempotency key whose
+	// transaction was rejected or failed while held for approval
+	ErrTransactionNotCompleted = NewError(ErrorKindConflict, "transaction_not_completed", "the transaction under this idempotency key was rejected or failed")
+)
+
+// clientIdempotencyKeyPrefix starts every idempotency key a client chose.
+const clientIdempotencyKeyPrefix = "client:"
+
+// ClientIdempotencyKey scopes an idempotency key a client sent to that client.
+func ClientIdempotencyKey(userID int, key string) string {
+	return clientIdempotencyKeyPrefix + strconv.Itoa(userID) + ":" + key
+}
+
 // Transaction represents a money transfer or operation.
 type Transaction struct {
 	ID         int
@@ -12,8 +36,36 @@ type Transaction struct {
 	ToUserID   *int
 	Amount     float64
 	Type       string // credit, debit, transfer
-	Status     string // pending, completed, failed
+	Status     string // pending, pending_approval, completed, failed, rejected
 	CreatedAt  time.Time
+
+	// IdempotencyKey identifies the request that produced the transaction, if any
+	IdempotencyKey string
+	// Description is free text, such as the memo of an imported transaction
+	Description string
+	// Fee is what the payer was charged by the fee schedule in effect at the
+	// time, FeeScheduleID; zero and nil if none applied
+	Fee           float64
+	FeeScheduleID *int
+}
+
+// TransactionFilter selects the transactions a search returns.
+type TransactionFilter struct {
+	// UserID matches transactions the user is on either side of
+	UserID *int
+	// CounterpartyID matches transactions the user is on either side of too;
+	// together with UserID, that is the side UserID is not on
+	CounterpartyID *int
+	// Query matches descriptions containing it, ignoring case
+	Query     string
+	MinAmount *float64
+	MaxAmount *float64
+	Types     []string
+	Statuses  []string
+	From      *time.Time // inclusive
+	To        *time.Time // exclusive
+	Limit     int
+	Offset    int
 }
 
 // Validate checks if the transaction fields are valid.
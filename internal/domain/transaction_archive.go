@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ErrArchivedTransactionNotFound is returned when a transaction is not in the archive
+var ErrArchivedTransactionNotFound = NewError(ErrorKindNotFound, "archived_transaction_not_found", "archived transaction not found")
+
+// ArchivableStatuses are the final statuses of transactions that can be archived.
+var ArchivableStatuses = []string{"completed", "failed", "rejected"}
+
+// ArchivedTransaction is a transaction moved out of the hot table by the retention job.
+type ArchivedTransaction struct {
+	ID          int       `json:"id"`
+	FromUserID  *int      `json:"from_user_id,omitempty"`
+	ToUserID    *int      `json:"to_user_id,omitempty"`
+	Amount      float64   `json:"amount"`
+	Type        string    `json:"type"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	Description string    `json:"description,omitempty"`
+	Fee         float64   `json:"fee"`
+	ArchivedAt  time.Time `json:"archived_at"`
+}
+
+// ArchivedTransactionFilter selects archived transactions.
+type ArchivedTransactionFilter struct {
+	UserID *int
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Offset int
+}
+
+// TransactionArchiveRepository defines the interface for archived transaction data access
+type TransactionArchiveRepository interface {
+	// ArchiveBefore moves up to limit final transactions created before cutoff
+	// into the archive, oldest first, and returns how many it moved
+	ArchiveBefore(ctx context.Context, cutoff time.Time, limit int) (int, error)
+
+	// GetByID retrieves an archived transaction
+	GetByID(ctx context.Context, id int) (*ArchivedTransaction, error)
+
+	// Search retrieves archived transactions matching filter, newest first
+	Search(ctx context.Context, filter ArchivedTransactionFilter) ([]*ArchivedTransaction, error)
+}
+
+// TransactionArchiveService defines business logic for transaction retention
+type TransactionArchiveService interface {
+	// Archive moves every final transaction older than the retention age into the archive
+	Archive(ctx context.Context) (int, error)
+
+	// GetArchived retrieves an archived transaction
+	GetArchived(ctx context.Context, id int) (*ArchivedTransaction, error)
+
+	// SearchArchived retrieves archived transactions matching filter
+	SearchArchived(ctx context.Context, filter ArchivedTransactionFilter) ([]*ArchivedTransaction, error)
+
+	// Start begins archiving periodically in the background
+	Start(ctx context.Context)
+
+	// Stop stops the background archiving
+	Stop()
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// LedgerEntry is a completed transaction as it affects one user's balance
+type LedgerEntry struct {
+	TransactionID  int
+	Type           string    // credit, debit, transfer
+	Amount         float64   // signed: positive adds to the balance, negative takes from it
+	CounterpartyID *int      // the other user of a transfer
+	CreatedAt      time.Time // UTC
+}
+
+// TransactionExportRepository reads a user's completed transactions for export.
+type TransactionExportRepository interface {
+	// OpeningBalance sums the effect of the user's completed transactions created before the given time
+	OpeningBalance(ctx context.Context, userID int, before time.Time) (float64, error)
+
+	// StreamLedger calls fn with each completed transaction of the user created
+	// in [from, to), oldest first, without loading them all at once. It stops
+	// at and returns the first error of fn.
+	StreamLedger(ctx context.Context, userID int, from, to time.Time, fn func(*LedgerEntry) error) error
+}
+
+// TransactionExport is a prepared export of a user's completed transactions created in [From, To)
+type TransactionExport struct {
+	UserID         int
+	Format         string // csv, ofx or qif
+	From           time.Time
+	To             time.Time
+	OpeningBalance float64 // balance at From
+	ContentType    string
+	FileName       string
+}
+
+// TransactionExportService exports transaction history for personal finance and accounting software
+type TransactionExportService interface {
+	// Prepare validates an export and reads its opening balance, so that
+	// errors surface before any of the file is written
+	Prepare(ctx context.Context, userID int, format string, from, to time.Time) (*TransactionExport, error)
+	// Write streams a prepared export to w. Once it has started writing, a
+	// failure leaves w with a truncated file.
+	Write(ctx context.Context, w io.Writer, export *TransactionExport) error
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"io"
+)
+
+var (
+	// ErrImportUserNotFound is returned when importing transactions for a user that does not exist
+	ErrImportUserNotFound = NewError(ErrorKindNotFound, "user_not_found", "user not found")
+	// ErrImportNegativeBalance is returned when an import would leave a balance below zero
+	ErrImportNegativeBalance = NewError(ErrorKindConflict, "import_negative_balance", "import would leave a balance below zero")
+	// ErrImportConcurrent is returned when rows of an import were imported by another request meanwhile
+	ErrImportConcurrent = NewError(ErrorKindConflict, "import_concurrent", "rows of the file were imported concurrently; retry the import")
+)
+
+// Statuses of an import file row
+const (
+	ImportRowImported  = "imported"
+	ImportRowDuplicate = "duplicate" // its external reference was imported before
+	ImportRowInvalid   = "invalid"
+)
+
+// ImportedTransaction is a settled transaction read from an import file
+type ImportedTransaction struct {
+	Transaction *Transaction
+	ExternalRef string
+}
+
+// ImportRowResult reports what became of one row of an import file
+type ImportRowResult struct {
+	Row           int    `json:"row"` // line in the file where the record starts, 1 being the header
+	ExternalRef   string `json:"external_ref,omitempty"`
+	Status        string `json:"status"`
+	TransactionID *int   `json:"transaction_id,omitempty"` // of the imported or earlier imported transaction
+	Error         string `json:"error,omitempty"`
+}
+
+// TransactionImportReport is the outcome of importing a file
+type TransactionImportReport struct {
+	UserID     int               `json:"user_id"`
+	DryRun     bool              `json:"dry_run"`
+	Imported   int               `json:"imported"`
+	Duplicates int               `json:"duplicates"`
+	Invalid    int               `json:"invalid"`
+	Balances   map[int]float64   `json:"balances,omitempty"` // recomputed balance of every user the import touched
+	Rows       []ImportRowResult `json:"rows"`
+}
+
+// TransactionImportRepository stores imported transactions
+type TransactionImportRepository interface {
+	// ImportedRefs returns which of refs were already imported for the user,
+	// mapped to the IDs of their transactions
+	ImportedRefs(ctx context.Context, userID int, refs []string) (map[string]int, error)
+
+	// Import inserts the transactions, keeping their CreatedAt, and records
+	// their external references, then recomputes the balance of every user
+	// they touch from the ledger. All of it commits together. It fails with
+	// ErrImportNegativeBalance if a recomputed balance is below zero and with
+	// ErrImportConcurrent if a reference is already recorded.
+	Import(ctx context.Context, userID int, txs []*ImportedTransaction) (map[int]float64, error)
+}
+
+// TransactionImportService imports historical transactions
+type TransactionImportService interface {
+	// Import reads a CSV file of a user's settled transactions. Valid rows
+	// not imported before are imported; the report says what became of every
+	// row. With dryRun nothing is stored.
+	Import(ctx context.Context, actorID, userID int, file io.Reader, dryRun bool) (*TransactionImportReport, error)
+}
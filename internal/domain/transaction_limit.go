@@ -5,14 +5,17 @@ import (
 	"time"
 )
 
+// ErrLimitRuleNotFound is returned when a limit rule does not exist or belongs to another user
+var ErrLimitRuleNotFound = NewError(ErrorKindNotFound, "limit_rule_not_found", "limit rule not found")
+
 // TransactionLimitRule defines a rule for limiting transactions.
 type TransactionLimitRule struct {
 	ID          string        // Unique rule ID
 	UserID      int           // User or Account the rule applies to
-	RuleType    RuleType      // e.g., MaxPerTransaction, DailyTotal, TxCount, MinInterval
+	RuleType    RuleType      // e.g., MaxPerTransaction, DailyTotal, RollingTotal, TxCount, MinInterval
 	LimitAmount float64       // Amount or count, depending on rule type
 	Currency    string        // Optional: for multicurrency support
-	Window      time.Duration // e.g., 24h for daily, 1h for hourly, 0 for per-tx
+	Window      time.Duration // For rolling totals, tx counts and min intervals; 0 for per-tx and calendar totals
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	Active      bool
@@ -24,10 +27,74 @@ type RuleType string
 const (
 	RuleMaxPerTransaction RuleType = "max_per_transaction"
 	RuleDailyTotal        RuleType = "daily_total"
+	RuleWeeklyTotal       RuleType = "weekly_total"
+	RuleMonthlyTotal      RuleType = "monthly_total"
+	RuleRollingTotal      RuleType = "rolling_total" // total over the last Window
 	RuleTxCount           RuleType = "tx_count"
 	RuleMinInterval       RuleType = "min_interval"
 )
 
+// IsTotal reports whether the rule limits the sum of amounts over a period
+func (t RuleType) IsTotal() bool {
+	switch t {
+	case RuleDailyTotal, RuleWeeklyTotal, RuleMonthlyTotal, RuleRollingTotal:
+		return true
+	}
+	return false
+}
+
+// NeedsWindow reports whether the rule type is measured over its Window
+func (t RuleType) NeedsWindow() bool {
+	return t == RuleRollingTotal || t == RuleTxCount || t == RuleMinInterval
+}
+
+// Validate checks the rule's type and amount, and that rules measured over a window have one
+func (r TransactionLimitRule) Validate() error {
+	switch r.RuleType {
+	case RuleMaxPerTransaction, RuleDailyTotal, RuleWeeklyTotal, RuleMonthlyTotal,
+		RuleRollingTotal, RuleTxCount, RuleMinInterval:
+		// valid
+	default:
+		return &ValidationError{Msg: "invalid rule type"}
+	}
+	if r.LimitAmount <= 0 {
+		return &ValidationError{Msg: "limit amount must be positive"}
+	}
+	if r.RuleType.NeedsWindow() && r.Window <= 0 {
+		return &ValidationError{Msg: "window must be positive for this rule type"}
+	}
+	return nil
+}
+
+// PeriodStart returns the earliest time a transaction at ts counts towards the rule.
+func (r TransactionLimitRule) PeriodStart(ts time.Time) time.Time {
+	ts = ts.UTC()
+	day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)
+	switch r.RuleType {
+	case RuleDailyTotal:
+		return day
+	case RuleWeeklyTotal:
+		daysSinceMonday := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -daysSinceMonday)
+	case RuleMonthlyTotal:
+		return time.Date(ts.Year(), ts.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return ts.Add(-r.Window).Add(time.Microsecond)
+	}
+}
+
+// LimitExceededError is returned when a transaction would break one of the user's limit rules.
+type LimitExceededError struct {
+	RuleID   string
+	RuleType RuleType
+	Limit    float64
+	Msg      string
+}
+
+func (e *LimitExceededError) Error() string {
+	return e.Msg
+}
+
 // TransactionLimitRepository abstracts rule and history storage.
 type TransactionLimitRepository interface {
 	GetRulesForUser(ctx context.Context, userID int) ([]TransactionLimitRule, error)
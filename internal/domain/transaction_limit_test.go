@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionLimitRule_PeriodStart(t *testing.T) {
+	istanbul := time.FixedZone("TRT", 3*60*60)
+	newYork := time.FixedZone("EST", -5*60*60)
+	utc := func(year int, month time.Month, day, hour, min, sec, nsec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, nsec, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		ruleType RuleType
+		window   time.Duration
+		ts       time.Time
+		want     time.Time
+	}{
+		{name: "daily: midday", ruleType: RuleDailyTotal, ts: utc(2026, 3, 4, 15, 30, 0, 0), want: utc(2026, 3, 4, 0, 0, 0, 0)},
+		{name: "daily: exactly midnight starts the new day", ruleType: RuleDailyTotal, ts: utc(2026, 3, 4, 0, 0, 0, 0), want: utc(2026, 3, 4, 0, 0, 0, 0)},
+		{name: "daily: last nanosecond of the day", ruleType: RuleDailyTotal, ts: utc(2026, 3, 4, 23, 59, 59, 999999999), want: utc(2026, 3, 4, 0, 0, 0, 0)},
+		{name: "daily: local early morning is the previous UTC day", ruleType: RuleDailyTotal, ts: time.Date(2026, 3, 4, 1, 0, 0, 0, istanbul), want: utc(2026, 3, 3, 0, 0, 0, 0)},
+		{name: "daily: local late evening is the next UTC day", ruleType: RuleDailyTotal, ts: time.Date(2026, 3, 4, 22, 0, 0, 0, newYork), want: utc(2026, 3, 5, 0, 0, 0, 0)},
+
+		{name: "weekly: Wednesday", ruleType: RuleWeeklyTotal, ts: utc(2026, 3, 4, 12, 0, 0, 0), want: utc(2026, 3, 2, 0, 0, 0, 0)},
+		{name: "weekly: Monday midnight starts the new week", ruleType: RuleWeeklyTotal, ts: utc(2026, 3, 2, 0, 0, 0, 0), want: utc(2026, 3, 2, 0, 0, 0, 0)},
+		{name: "weekly: Sunday belongs to the week before", ruleType: RuleWeeklyTotal, ts: utc(2026, 3, 8, 23, 59, 59, 0), want: utc(2026, 3, 2, 0, 0, 0, 0)},
+		{name: "weekly: across a month boundary", ruleType: RuleWeeklyTotal, ts: utc(2026, 4, 1, 9, 0, 0, 0), want: utc(2026, 3, 30, 0, 0, 0, 0)},
+		{name: "weekly: local Monday morning is still the UTC Sunday before", ruleType: RuleWeeklyTotal, ts: time.Date(2026, 3, 9, 2, 0, 0, 0, istanbul), want: utc(2026, 3, 2, 0, 0, 0, 0)},
+
+		{name: "monthly: mid-month", ruleType: RuleMonthlyTotal, ts: utc(2026, 3, 17, 8, 0, 0, 0), want: utc(2026, 3, 1, 0, 0, 0, 0)},
+		{name: "monthly: first instant of the month", ruleType: RuleMonthlyTotal, ts: utc(2026, 3, 1, 0, 0, 0, 0), want: utc(2026, 3, 1, 0, 0, 0, 0)},
+		{name: "monthly: leap day", ruleType: RuleMonthlyTotal, ts: utc(2028, 2, 29, 12, 0, 0, 0), want: utc(2028, 2, 1, 0, 0, 0, 0)},
+		{name: "monthly: local new year's eve is already January in UTC", ruleType: RuleMonthlyTotal, ts: time.Date(2026, 12, 31, 20, 0, 0, 0, newYork), want: utc(2027, 1, 1, 0, 0, 0, 0)},
+
+		{name: "rolling: one microsecond past the window start", ruleType: RuleRollingTotal, window: 24 * time.Hour, ts: utc(2026, 3, 4, 12, 0, 0, 0), want: utc(2026, 3, 3, 12, 0, 0, 1000)},
+		{name: "tx count: in UTC whatever the zone", ruleType: RuleTxCount, window: time.Hour, ts: time.Date(2026, 3, 4, 12, 0, 0, 0, istanbul), want: utc(2026, 3, 4, 8, 0, 0, 1000)},
+		{name: "min interval", ruleType: RuleMinInterval, window: 30 * time.Second, ts: utc(2026, 3, 4, 0, 0, 10, 0), want: utc(2026, 3, 3, 23, 59, 40, 1000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := TransactionLimitRule{RuleType: tt.ruleType, Window: tt.window}
+			got := rule.PeriodStart(tt.ts)
+			assert.True(t, tt.want.Equal(got), "got %s, want %s", got, tt.want)
+			assert.Equal(t, time.UTC, got.Location())
+		})
+	}
+
+	t.Run("a transaction exactly one window earlier no longer counts", func(t *testing.T) {
+		rule := TransactionLimitRule{RuleType: RuleRollingTotal, Window: time.Hour}
+		ts := utc(2026, 3, 4, 12, 0, 0, 0)
+		start := rule.PeriodStart(ts)
+
+		assert.True(t, ts.Add(-time.Hour).Before(start))
+		assert.False(t, ts.Add(-time.Hour).Add(time.Microsecond).Before(start))
+	})
+}
+
+func TestRuleType_IsTotalAndNeedsWindow(t *testing.T) {
+	tests := []struct {
+		ruleType    RuleType
+		isTotal     bool
+		needsWindow bool
+	}{
+		{RuleMaxPerTransaction, false, false},
+		{RuleDailyTotal, true, false},
+		{RuleWeeklyTotal, true, false},
+		{RuleMonthlyTotal, true, false},
+		{RuleRollingTotal, true, true},
+		{RuleTxCount, false, true},
+		{RuleMinInterval, false, true},
+		{RuleType("yearly_total"), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.ruleType), func(t *testing.T) {
+			assert.Equal(t, tt.isTotal, tt.ruleType.IsTotal())
+			assert.Equal(t, tt.needsWindow, tt.ruleType.NeedsWindow())
+		})
+	}
+}
+
+func TestTransactionLimitRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    TransactionLimitRule
+		wantErr string
+	}{
+		{name: "per transaction", rule: TransactionLimitRule{RuleType: RuleMaxPerTransaction, LimitAmount: 100}},
+		{name: "calendar total needs no window", rule: TransactionLimitRule{RuleType: RuleWeeklyTotal, LimitAmount: 100}},
+		{name: "rolling total with a window", rule: TransactionLimitRule{RuleType: RuleRollingTotal, LimitAmount: 100, Window: time.Hour}},
+		{name: "unknown type", rule: TransactionLimitRule{RuleType: "yearly_total", LimitAmount: 100}, wantErr: "invalid rule type"},
+		{name: "zero amount", rule: TransactionLimitRule{RuleType: RuleDailyTotal}, wantErr: "limit amount must be positive"},
+		{name: "windowed rule without a window", rule: TransactionLimitRule{RuleType: RuleTxCount, LimitAmount: 3}, wantErr: "window must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var verr *ValidationError
+			assert.ErrorAs(t, err, &verr)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TransactionPartition is one monthly partition of the transactions table.
+type TransactionPartition struct {
+	Name string    `json:"name"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// NewTransactionPartition returns the partition for the month containing t
+func NewTransactionPartition(t time.Time) TransactionPartition {
+	t = t.UTC()
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return TransactionPartition{
+		Name: fmt.Sprintf("transactions_p%04d%02d", from.Year(), int(from.Month())),
+		From: from,
+		To:   from.AddDate(0, 1, 0),
+	}
+}
+
+// TransactionPartitionRepository manages the monthly partitions of the transactions table
+type TransactionPartitionRepository interface {
+	// ListPartitions retrieves the monthly partitions currently attached, oldest first
+	ListPartitions(ctx context.Context) ([]TransactionPartition, error)
+
+	// CreatePartition creates and attaches a partition unless it already exists
+	CreatePartition(ctx context.Context, partition TransactionPartition) error
+
+	// DetachPartition detaches a partition, keeping it as a standalone table
+	DetachPartition(ctx context.Context, partition TransactionPartition) error
+}
+
+// TransactionPartitionService keeps partitions created ahead of time and detaches expired ones
+type TransactionPartitionService interface {
+	// Maintain creates missing future partitions and detaches those past retention
+	Maintain(ctx context.Context) error
+
+	// Start begins running maintenance periodically in the background
+	Start(ctx context.Context)
+
+	// Stop stops the background maintenance
+	Stop()
+}
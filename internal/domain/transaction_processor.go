@@ -1,24 +1,222 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Errors returned when operating on the dead-letter queue
+var (
+	ErrDeadLetterNotFound = NewError(ErrorKindNotFound, "dead_letter_not_found", "dead letter not found")
+	ErrDeadLetterRequeued = NewError(ErrorKindConflict, "dead_letter_requeued", "dead letter has already been requeued")
+)
+
+// ErrProcessorStopped is returned for tasks submitted once shutdown has begun
+var ErrProcessorStopped = NewError(ErrorKindUnavailable, "shutting_down", "transaction processor is shutting down")
 
 // TransactionTask represents a task to be processed by the worker pool
 type TransactionTask struct {
-	ID       string
-	Type     string // "credit", "debit", "transfer"
-	UserID   int
-	ToUserID *int // for transfers
-	Amount   float64
-	Priority int // higher number = higher priority
+	ID          string
+	Type        string // "credit", "debit", "transfer"
+	UserID      int
+	ToUserID    *int // for transfers
+	Amount      float64
+	Priority    int    // higher number = higher priority
+	CallbackURL string // optional URL that receives the result when the task finishes
+	Attempts    int    // processing attempts made so far
+	BatchID     string // set when the task was submitted as part of a batch
+	TraceParent string // W3C traceparent of the span that submitted the task
+
+	// Optional per-task retry overrides; zero values use the processor's policy
+	MaxAttempts  int
+	RetryBackoff time.Duration
 }
 
 // TransactionResult represents the result of processing a transaction task
 type TransactionResult struct {
-	TaskID    string
-	Success   bool
-	Error     error
-	Message   string
-	Timestamp int64
+	TaskID        string
+	Success       bool
+	Error         error
+	Message       string
+	TransactionID *int   // transaction recorded by a successful task
+	CallbackURL   string // copied from the task; empty when no callback was requested
+	Timestamp     int64
+}
+
+// TaskRecord is the persisted lifecycle and outcome of a submitted task
+type TaskRecord struct {
+	TaskID        string     `json:"task_id"`
+	Type          string     `json:"type"`
+	UserID        int        `json:"user_id"`
+	ToUserID      *int       `json:"to_user_id,omitempty"`
+	Amount        float64    `json:"amount"`
+	Priority      int        `json:"priority"`
+	Status        string     `json:"status"` // "queued", "processing", "succeeded", "failed"
+	Error         string     `json:"error,omitempty"`
+	TransactionID *int       `json:"transaction_id,omitempty"`
+	Attempts      int        `json:"attempts"`
+	BatchID       string     `json:"batch_id,omitempty"`
+	SubmittedAt   time.Time  `json:"submitted_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// NewTaskRecord creates a queued record for a task that is about to be submitted
+func NewTaskRecord(task *TransactionTask) *TaskRecord {
+	return &TaskRecord{
+		TaskID:      task.ID,
+		Type:        task.Type,
+		UserID:      task.UserID,
+		ToUserID:    task.ToUserID,
+		Amount:      task.Amount,
+		Priority:    task.Priority,
+		Status:      "queued",
+		BatchID:     task.BatchID,
+		SubmittedAt: time.Now().UTC(),
+	}
+}
+
+// QueueWait returns how long the task waited before a worker picked it up
+func (r *TaskRecord) QueueWait() time.Duration {
+	if r.StartedAt == nil {
+		return time.Since(r.SubmittedAt)
+	}
+	return r.StartedAt.Sub(r.SubmittedAt)
+}
+
+// ProcessingTime returns how long a worker spent on the task so far
+func (r *TaskRecord) ProcessingTime() time.Duration {
+	if r.StartedAt == nil {
+		return 0
+	}
+	if r.CompletedAt == nil {
+		return time.Since(*r.StartedAt)
+	}
+	return r.CompletedAt.Sub(*r.StartedAt)
+}
+
+// BatchRecord is the persisted metadata of a batch of tasks.
+type BatchRecord struct {
+	BatchID     string     `json:"batch_id"`
+	Status      string     `json:"status"` // "processing", "completed", "timed_out", "rolled_back"
+	TotalTasks  int        `json:"total_tasks"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// NewBatchRecord creates a processing record for a batch about to be submitted
+func NewBatchRecord(batchID string, totalTasks int) *BatchRecord {
+	return &BatchRecord{
+		BatchID:    batchID,
+		Status:     "processing",
+		TotalTasks: totalTasks,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+// BatchTaskError is the failure of a single task within a batch
+type BatchTaskError struct {
+	TaskID string `json:"task_id"`
+	Error  string `json:"error"`
+}
+
+// BatchProgress summarises how far a batch has got
+type BatchProgress struct {
+	SuccessfulTasks int              `json:"successful_tasks"`
+	FailedTasks     int              `json:"failed_tasks"`
+	PendingTasks    int              `json:"pending_tasks"`
+	ProgressPercent float64          `json:"progress_percent"`
+	Errors          []BatchTaskError `json:"errors"`
+}
+
+// Progress derives the batch's progress from the records of its tasks
+func (b *BatchRecord) Progress(tasks []*TaskRecord) BatchProgress {
+	progress := BatchProgress{Errors: []BatchTaskError{}}
+	for _, task := range tasks {
+		switch task.Status {
+		case "succeeded":
+			progress.SuccessfulTasks++
+		case "failed":
+			progress.FailedTasks++
+			progress.Errors = append(progress.Errors, BatchTaskError{TaskID: task.TaskID, Error: task.Error})
+		}
+	}
+
+	finished := progress.SuccessfulTasks + progress.FailedTasks
+	progress.PendingTasks = max(b.TotalTasks-finished, 0)
+	if b.TotalTasks > 0 {
+		progress.ProgressPercent = math.Round(float64(finished)/float64(b.TotalTasks)*10000) / 100
+	}
+	return progress
+}
+
+// BatchRepository persists batch metadata
+type BatchRepository interface {
+	Create(ctx context.Context, batch *BatchRecord) error
+	GetByID(ctx context.Context, batchID string) (*BatchRecord, error)
+	Update(ctx context.Context, batch *BatchRecord) error
+}
+
+// DeadLetter is a task that kept failing and was set aside for operator review
+type DeadLetter struct {
+	ID          int        `json:"id"`
+	TaskID      string     `json:"task_id"`
+	Type        string     `json:"type"`
+	UserID      int        `json:"user_id"`
+	ToUserID    *int       `json:"to_user_id,omitempty"`
+	Amount      float64    `json:"amount"`
+	Priority    int        `json:"priority"`
+	CallbackURL string     `json:"callback_url,omitempty"`
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"last_error"`
+	FailedAt    time.Time  `json:"failed_at"`
+	RequeuedAt  *time.Time `json:"requeued_at,omitempty"`
+}
+
+// NewDeadLetter captures a failed task and the error that exhausted it
+func NewDeadLetter(task *TransactionTask, lastErr error) *DeadLetter {
+	return &DeadLetter{
+		TaskID:      task.ID,
+		Type:        task.Type,
+		UserID:      task.UserID,
+		ToUserID:    task.ToUserID,
+		Amount:      task.Amount,
+		Priority:    task.Priority,
+		CallbackURL: task.CallbackURL,
+		Attempts:    task.Attempts,
+		LastError:   lastErr.Error(),
+		FailedAt:    time.Now().UTC(),
+	}
+}
+
+// Task rebuilds a fresh task from the dead letter so it can be processed again
+func (d *DeadLetter) Task() *TransactionTask {
+	return &TransactionTask{
+		ID:          d.TaskID,
+		Type:        d.Type,
+		UserID:      d.UserID,
+		ToUserID:    d.ToUserID,
+		Amount:      d.Amount,
+		Priority:    d.Priority,
+		CallbackURL: d.CallbackURL,
+	}
+}
+
+// DeadLetterRepository persists tasks that failed after all retries
+type DeadLetterRepository interface {
+	Create(ctx context.Context, entry *DeadLetter) error
+	GetByID(ctx context.Context, id int) (*DeadLetter, error)
+	List(ctx context.Context, includeRequeued bool, limit, offset int) ([]*DeadLetter, error)
+	MarkRequeued(ctx context.Context, id int) error
+}
+
+// TaskRepository persists task records so their outcome can be looked up later
+type TaskRepository interface {
+	Create(ctx context.Context, record *TaskRecord) error
+	GetByID(ctx context.Context, taskID string) (*TaskRecord, error)
+	ListByBatch(ctx context.Context, batchID string) ([]*TaskRecord, error)
+	Update(ctx context.Context, record *TaskRecord) error
 }
 
 // TransactionProcessor defines the interface for concurrent transaction processing
@@ -26,6 +224,9 @@ type TransactionProcessor interface {
 	// SubmitTask submits a transaction task to the processing queue
 	SubmitTask(ctx context.Context, task *TransactionTask) error
 
+	// SubmitTaskAndWait submits a task and waits for its final result
+	SubmitTaskAndWait(ctx context.Context, task *TransactionTask) (*TransactionResult, error)
+
 	// Start starts the worker pool
 	Start(ctx context.Context) error
 
@@ -34,6 +235,15 @@ type TransactionProcessor interface {
 
 	// GetStats returns current processing statistics
 	GetStats() *ProcessingStats
+
+	// GetTask returns the recorded status of a submitted task, or nil if unknown
+	GetTask(ctx context.Context, taskID string) (*TaskRecord, error)
+
+	// ListDeadLetters returns tasks that failed after all retries
+	ListDeadLetters(ctx context.Context, includeRequeued bool, limit, offset int) ([]*DeadLetter, error)
+
+	// RequeueDeadLetter resubmits a dead-lettered task and returns it
+	RequeueDeadLetter(ctx context.Context, id int) (*TransactionTask, error)
 }
 
 // ProcessingStats holds statistics about transaction processing
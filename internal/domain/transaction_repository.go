@@ -7,9 +7,20 @@ import (
 
 // TransactionRepository defines methods for transaction data access.
 type TransactionRepository interface {
-	Create(tx *Transaction) error
-	GetByID(id int) (*Transaction, error)
-	ListByUser(userID int) ([]*Transaction, error)
-	ListByUserAndTimeRange(userID int, from, to time.Time) ([]*Transaction, error)
+	Create(ctx context.Context, tx *Transaction) error
+	GetByID(ctx context.Context, id int) (*Transaction, error)
+	GetByIdempotencyKey(ctx context.Context, key string) (*Transaction, error)
+	// ListByUser fetches a page of the user's transactions, newest first
+	ListByUser(ctx context.Context, userID int, limit, offset int) ([]*Transaction, error)
+	CountByUser(ctx context.Context, userID int) (int, error)
+	ListByUserAndTimeRange(ctx context.Context, userID int, from, to time.Time) ([]*Transaction, error)
 	ListAll(ctx context.Context, limit int, offset int) ([]*Transaction, error)
+	// Search fetches the transactions matching filter, newest first
+	Search(ctx context.Context, filter TransactionFilter) ([]*Transaction, error)
+	UpdateStatus(ctx context.Context, id int, status string) error
+	// ResolvePending moves a transaction from "pending_approval" to status. It
+	// fails with ErrTransactionNotPending if the transaction already left it.
+	ResolvePending(ctx context.Context, id int, status string) error
+	// SetFee records the fee a transaction was charged and the schedule setting it
+	SetFee(ctx context.Context, id int, fee float64, scheduleID *int) error
 }
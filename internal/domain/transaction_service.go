@@ -4,10 +4,44 @@ import "context"
 
 // TransactionService defines business logic for transactions.
 type TransactionService interface {
-	Credit(userID int, amount float64) error
-	Debit(userID int, amount float64) error
-	Transfer(fromUserID, toUserID int, amount float64) error
-	GetTransaction(id int) (*Transaction, error)
-	ListUserTransactions(userID int) ([]*Transaction, error)
+	// Credit, Debit and Transfer take an optional idempotency key. A repeated
+	// call with the key of a completed transaction returns that transaction
+	// instead of moving money again, and fails with ErrIdempotencyKeyReused if
+	// that transaction is not the one asked for; an empty key disables the
+	// check. Keys clients choose are scoped with ClientIdempotencyKey. Debits
+	// and transfers fail with *LimitExceededError when they break one of the
+	// paying user's limit rules, and with ErrFraudRejected when fraud
+	// screening rejects them. A movement that RequiresApproval, or that fraud
+	// screening holds, is recorded as "pending_approval", without moving
+	// money, and returned with ErrTransactionHeld; the user in the context's
+	// WithRequester, or else the payer, may not approve it.
+	Credit(ctx context.Context, userID int, amount float64, idempotencyKey string) (*Transaction, error)
+	Debit(ctx context.Context, userID int, amount float64, idempotencyKey string) (*Transaction, error)
+	Transfer(ctx context.Context, fromUserID, toUserID int, amount float64, idempotencyKey string) (*Transaction, error)
+	// CaptureHold debits the amount an active hold reserves and settles the
+	// hold, reviewed and priced like Debit. A debit held for approval is
+	// returned with ErrTransactionHeld and leaves the hold active until it is
+	// approved. hold is updated as stored.
+	CaptureHold(ctx context.Context, hold *Hold) (*Transaction, error)
+	// ExecutePending moves the funds of a transaction held for approval. It
+	// fails with ErrTransactionNotPending if the transaction already left
+	// "pending_approval", such as when it was approved concurrently. record
+	// runs in the unit of work that completes or fails the transaction.
+	ExecutePending(ctx context.Context, tx *Transaction, record func(repos UnitOfWorkRepositories) error) error
+	// RejectPending rejects a transaction held for approval without moving
+	// money, with record in the same unit of work
+	RejectPending(ctx context.Context, tx *Transaction, record func(repos UnitOfWorkRepositories) error) error
+	// HoldForSignOff screens a debit or transfer for fraud, failing with
+	// ErrFraudRejected, and records it as "pending_approval" without moving
+	// money. record runs in the same unit of work to store who signs it off.
+	// ExecutePending later moves the money within the payer's limit rules.
+	HoldForSignOff(ctx context.Context, tx *Transaction, record func(repos UnitOfWorkRepositories) error) error
+	// RequiresApproval reports whether a movement of amount must be approved
+	// before it runs
+	RequiresApproval(amount float64) bool
+	GetTransaction(ctx context.Context, id int) (*Transaction, error)
+	ListUserTransactions(ctx context.Context, userID int, limit, offset int) ([]*Transaction, error)
+	CountUserTransactions(ctx context.Context, userID int) (int, error)
 	ListAllTransactions(ctx context.Context, limit int, offset int) ([]*Transaction, error)
+	SearchTransactions(ctx context.Context, filter TransactionFilter) ([]*Transaction, error)
 }
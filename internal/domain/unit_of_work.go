@@ -0,0 +1,23 @@
+package domain
+
+import "context"
+
+// UnitOfWorkRepositories are the repositories bound to a unit of work's transaction.
+type UnitOfWorkRepositories struct {
+	Balances      BalanceRepository
+	Transactions  TransactionRepository
+	Limits        TransactionLimitRepository
+	Campaigns     CampaignRepository
+	Referrals     ReferralRepository
+	Disputes      DisputeRepository
+	Funding       FundingRepository
+	BankTransfers BankTransferRepository
+	Approvals     ApprovalRepository
+	Holds         HoldRepository
+	Organizations OrganizationRepository
+}
+
+// UnitOfWork runs a function atomically.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos UnitOfWorkRepositories) error) error
+}
@@ -1,11 +1,61 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"regexp"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidCredentials is returned when logging in with an unknown
+	// username or a wrong password; which of the two is not revealed
+	ErrInvalidCredentials = NewError(ErrorKindUnauthorized, "invalid_credentials", "invalid username or password")
+	// ErrUserNotFound is returned when a user does not exist
+	ErrUserNotFound = NewError(ErrorKindNotFound, "user_not_found", "user not found")
+	// ErrUserErased is returned when a user's personal data has already been erased
+	ErrUserErased = NewError(ErrorKindConflict, "user_erased", "user has been erased")
+	// ErrWrongPassword is returned when a user's current password doesn't match
+	ErrWrongPassword = NewError(ErrorKindForbidden, "wrong_password", "current password is incorrect")
+	// ErrUsernameTaken is returned when registering with a username already in use
+	ErrUsernameTaken = NewError(ErrorKindConflict, "username_taken", "username already exists")
+	// ErrEmailTaken is returned when registering with an email already in use
+	ErrEmailTaken = NewError(ErrorKindConflict, "email_taken", "email already exists")
+	// ErrAvatarNotFound is returned when a user has no avatar
+	ErrAvatarNotFound = NewError(ErrorKindNotFound, "avatar_not_found", "user has no avatar")
+)
+
+// MaxAvatarBytes bounds the size of an uploaded avatar
+const MaxAvatarBytes = 2 << 20
+
+// AvatarExtensions maps the accepted avatar content types to file extensions.
+var AvatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// maxNameLength bounds first and last names, in characters
+const maxNameLength = 100
+
+var (
+	// phonePattern matches E.164 phone numbers, such as +905551234567
+	phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	// localePattern matches BCP 47 language tags with an optional region, such as tr or en-US
+	localePattern = regexp.MustCompile(`^[a-z]{2,3}(-([A-Z]{2}|[0-9]{3}))?$`)
 )
 
+// erasedPasswordHash is stored in place of an erased user's password hash.
+const erasedPasswordHash = "!erased"
+
 // User represents a system user.
 type User struct {
 	ID           int
@@ -13,8 +63,92 @@ type User struct {
 	Email        string
 	PasswordHash string
 	Role         string
+	FirstName    string
+	LastName     string
+	Phone        string // E.164
+	Locale       string // BCP 47 language tag
+	AvatarKey    string
 	CreatedAt    time.Time // Use time.Time in real code, string for simplicity now
 	UpdatedAt    time.Time
+	ErasedAt     *time.Time
+	LastLoginAt  *time.Time
+}
+
+// UserFilter selects and orders the users of an admin listing.
+type UserFilter struct {
+	Role   string
+	Erased *bool // whether the user's personal data has been erased
+	// Sort is "id" (the default), "created_at" or "-created_at" for newest first
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// Reasons a login attempt failed
+const (
+	LoginFailureUnknownUser   = "unknown_user"
+	LoginFailureWrongPassword = "wrong_password"
+	// LoginFailureStepUpRequired marks a correct password from an untrusted
+	// device, which waits for a verification code
+	LoginFailureStepUpRequired = "step_up_required"
+	LoginFailureWrongCode      = "wrong_code"
+)
+
+// LoginEvent records one login attempt.
+type LoginEvent struct {
+	ID            int64        `json:"id"`
+	UserID        *int         `json:"user_id,omitempty"`
+	Username      string       `json:"username"`
+	IP            string       `json:"ip"`
+	UserAgent     string       `json:"user_agent"`
+	Location      *GeoLocation `json:"location,omitempty"`
+	Success       bool         `json:"success"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	Suspicious    bool         `json:"suspicious,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// LoginClient describes the client a login comes from.
+type LoginClient struct {
+	IP        string
+	UserAgent string
+	DeviceID  string
+	Location  *GeoLocation
+}
+
+// Fingerprint identifies the client's device across logins.
+func (c LoginClient) Fingerprint() string {
+	key := c.UserAgent
+	if c.DeviceID != "" {
+		key += "\x00" + c.DeviceID
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsErased reports whether the user's personal data has been erased
+func (u *User) IsErased() bool {
+	return u.ErasedAt != nil
+}
+
+// IsOrganizationAccount reports whether the user only holds an organization's balance
+func (u *User) IsOrganizationAccount() bool {
+	return u.Role == OrganizationAccountRole
+}
+
+// Anonymize replaces the user's identifying fields with placeholders.
+func (u *User) Anonymize() error {
+	if u.IsErased() {
+		return ErrUserErased
+	}
+	suffix := strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+	now := time.Now().UTC()
+	u.Username = fmt.Sprintf("erased-%d-%s", u.ID, suffix)
+	u.Email = fmt.Sprintf("erased-%d-%s@erased.invalid", u.ID, suffix)
+	u.PasswordHash = erasedPasswordHash
+	u.FirstName, u.LastName, u.Phone, u.Locale, u.AvatarKey = "", "", "", "", ""
+	u.ErasedAt = &now
+	return nil
 }
 
 // Validate checks if the user fields are valid.
@@ -33,3 +167,24 @@ func (u *User) Validate() error {
 	}
 	return nil
 }
+
+// ValidateProfile checks the optional profile fields.
+func (u *User) ValidateProfile() error {
+	names := []struct{ field, value string }{{"first_name", u.FirstName}, {"last_name", u.LastName}}
+	for _, n := range names {
+		field, name := n.field, n.value
+		if utf8.RuneCountInString(name) > maxNameLength {
+			return &ValidationError{Msg: fmt.Sprintf("%s must be at most %d characters", field, maxNameLength)}
+		}
+		if strings.IndexFunc(name, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0 {
+			return &ValidationError{Msg: field + " must not contain control characters"}
+		}
+	}
+	if u.Phone != "" && !phonePattern.MatchString(u.Phone) {
+		return &ValidationError{Msg: "phone must be in E.164 format, such as +905551234567"}
+	}
+	if u.Locale != "" && !localePattern.MatchString(u.Locale) {
+		return &ValidationError{Msg: "locale must be a language tag such as en or en-US"}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// UserDataExport is a copy of the personal data kept about a user.
+type UserDataExport struct {
+	ExportedAt              time.Time                  `json:"exported_at"`
+	Account                 UserDataAccount            `json:"account"`
+	Preferences             map[string]json.RawMessage `json:"preferences"`
+	NotificationPreferences []NotificationPreference   `json:"notification_preferences"`
+	Logins                  []*LoginEvent              `json:"logins"` // newest first
+	Devices                 []*Device                  `json:"devices"`
+}
+
+// UserDataAccount is the account and profile part of a UserDataExport
+type UserDataAccount struct {
+	ID          int        `json:"id"`
+	Username    string     `json:"username"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	FirstName   string     `json:"first_name,omitempty"`
+	LastName    string     `json:"last_name,omitempty"`
+	Phone       string     `json:"phone,omitempty"`
+	Locale      string     `json:"locale,omitempty"`
+	HasAvatar   bool       `json:"has_avatar"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// UserDataExportService gathers a user's personal data for export
+type UserDataExportService interface {
+	// ExportUserData returns the data kept about userID on behalf of actorID,
+	// or nil if the user doesn't exist
+	ExportUserData(ctx context.Context, actorID, userID int) (*UserDataExport, error)
+}
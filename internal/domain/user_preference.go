@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+var (
+	// ErrPreferenceNotFound is returned when a user hasn't set a preference
+	ErrPreferenceNotFound = NewError(ErrorKindNotFound, "preference_not_found", "preference not set")
+	// ErrTooManyPreferences is returned when a user would have more than MaxUserPreferences preferences
+	ErrTooManyPreferences = NewError(ErrorKindLimitExceeded, "too_many_preferences",
+		fmt.Sprintf("a user can set at most %d preferences", MaxUserPreferences))
+)
+
+const (
+	// MaxUserPreferences bounds the number of preferences a user can set
+	MaxUserPreferences = 100
+	// maxPreferenceValueBytes bounds the encoded value of a preference
+	maxPreferenceValueBytes = 1024
+)
+
+// preferenceKeyPattern matches preference keys, such as ui.theme
+var preferenceKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+
+// PreferenceSpec describes the values a known preference key takes
+type PreferenceSpec struct {
+	Type    string   // "string", "bool" or "int"
+	Allowed []string // the values a string may take; nil allows any
+	Pattern *regexp.Regexp
+	Min     int
+	Max     int
+}
+
+// KnownPreferences maps the preference keys the application reads to the values they take.
+var KnownPreferences = map[string]PreferenceSpec{
+	"default_currency":      {Type: "string", Pattern: regexp.MustCompile(`^[A-Z]{3}$`)},
+	"notifications.digest":  {Type: "string", Allowed: []string{"off", "daily", "weekly"}},
+	"notifications.product": {Type: "bool"},
+	"ui.theme":              {Type: "string", Allowed: []string{"light", "dark", "system"}},
+	"ui.page_size":          {Type: "int", Min: 10, Max: 500},
+	"ui.compact":            {Type: "bool"},
+}
+
+// ValidatePreference checks a preference's key and, for known keys, its value.
+func ValidatePreference(key string, value json.RawMessage) error {
+	if len(key) > 64 || !preferenceKeyPattern.MatchString(key) {
+		return &ValidationError{Msg: fmt.Sprintf("invalid preference key %q: use lowercase dot-separated words such as ui.theme", key)}
+	}
+	if len(value) > maxPreferenceValueBytes {
+		return &ValidationError{Msg: fmt.Sprintf("preference %s must encode to at most %d bytes", key, maxPreferenceValueBytes)}
+	}
+	if !json.Valid(value) {
+		return &ValidationError{Msg: fmt.Sprintf("preference %s is not valid JSON", key)}
+	}
+	spec, known := KnownPreferences[key]
+	if !known {
+		return nil
+	}
+	switch spec.Type {
+	case "string":
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return &ValidationError{Msg: fmt.Sprintf("preference %s must be a string", key)}
+		}
+		if spec.Allowed != nil && !slices.Contains(spec.Allowed, s) {
+			return &ValidationError{Msg: fmt.Sprintf("preference %s must be one of %v", key, spec.Allowed)}
+		}
+		if spec.Pattern != nil && !spec.Pattern.MatchString(s) {
+			return &ValidationError{Msg: fmt.Sprintf("preference %s must match %s", key, spec.Pattern)}
+		}
+	case "bool":
+		var b bool
+		if err := json.Unmarshal(value, &b); err != nil {
+			return &ValidationError{Msg: fmt.Sprintf("preference %s must be a boolean", key)}
+		}
+	case "int":
+		var n int
+		if err := json.Unmarshal(value, &n); err != nil {
+			return &ValidationError{Msg: fmt.Sprintf("preference %s must be an integer", key)}
+		}
+		if n < spec.Min || n > spec.Max {
+			return &ValidationError{Msg: fmt.Sprintf("preference %s must be between %d and %d", key, spec.Min, spec.Max)}
+		}
+	}
+	return nil
+}
+
+// UserPreferenceRepository defines the data access methods of user preferences
+type UserPreferenceRepository interface {
+	// List retrieves the preferences a user has set by key
+	List(ctx context.Context, userID int) (map[string]json.RawMessage, error)
+	// Update stores set and removes the keys in remove in one transaction
+	Update(ctx context.Context, userID int, set map[string]json.RawMessage, remove []string) error
+	// Delete removes a preference, reporting whether it was set
+	Delete(ctx context.Context, userID int, key string) (bool, error)
+}
+
+// UserPreferenceService defines business logic for user preferences
+type UserPreferenceService interface {
+	// GetPreferences returns the preferences a user has set by key
+	GetPreferences(ctx context.Context, userID int) (map[string]json.RawMessage, error)
+	// UpdatePreferences merges changes into the user's preferences; a null value removes its key
+	UpdatePreferences(ctx context.Context, userID int, changes map[string]json.RawMessage) (map[string]json.RawMessage, error)
+	// SetPreference stores one preference
+	SetPreference(ctx context.Context, userID int, key string, value json.RawMessage) error
+	// DeletePreference removes one preference
+	DeletePreference(ctx context.Context, userID int, key string) error
+}
@@ -4,12 +4,51 @@ import "context"
 
 // UserRepository defines methods for user data access.
 type UserRepository interface {
-	Create(user *User) error
-	GetByID(id int) (*User, error)
-	GetByUsername(username string) (*User, error)
-	GetByEmail(email string) (*User, error)
-	Update(user *User) error
-	Delete(id int) error
-	List() ([]*User, error)
+	Create(ctx context.Context, user *User) error
+	GetByID(ctx context.Context, id int) (*User, error)
+	GetByUsername(ctx context.Context, username string) (*User, error)
+	GetByEmail(ctx context.Context, email string) (*User, error)
+	// Update stores a user's account and profile fields, leaving the password and avatar as they are
+	Update(ctx context.Context, user *User) error
+	// UpdateAvatar replaces the object store key of a user's avatar; an empty key removes it
+	UpdateAvatar(ctx context.Context, id int, key string) error
+	// UpdatePassword replaces a user's password hash
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+	// RecordLogin stores the current time as the user's last login
+	RecordLogin(ctx context.Context, id int) error
+	// RecordLoginEvent stores a login attempt
+	RecordLoginEvent(ctx context.Context, event *LoginEvent) error
+	// ListLoginEvents fetches a page of a user's login attempts, newest first
+	ListLoginEvents(ctx context.Context, userID, limit, offset int) ([]*LoginEvent, error)
+	// LastLocatedLogin fetches a user's latest successful login that could be located
+	LastLocatedLogin(ctx context.Context, userID int) (*LoginEvent, error)
+	// CountLoginEvents counts a user's login attempts
+	CountLoginEvents(ctx context.Context, userID int) (int, error)
+	// GetDevice fetches a trusted device of a user by fingerprint
+	GetDevice(ctx context.Context, userID int, fingerprint string) (*Device, error)
+	// HasDevices reports whether a user trusts any device
+	HasDevices(ctx context.Context, userID int) (bool, error)
+	// TrustDevice stores a device as trusted or refreshes its last use
+	TrustDevice(ctx context.Context, userID int, fingerprint, userAgent string) error
+	// ListDevices fetches a user's trusted devices, most recently used first
+	ListDevices(ctx context.Context, userID int) ([]*Device, error)
+	// DeleteDevice revokes the trust of a device, returning ErrDeviceNotFound if there is none
+	DeleteDevice(ctx context.Context, userID int, fingerprint string) error
+	// CreateLoginChallenge stores a login waiting for its verification code
+	CreateLoginChallenge(ctx context.Context, challenge *LoginChallenge) error
+	// UseLoginChallengeAttempt counts an attempt at an unexpired challenge
+	// that has attempts left and returns it, or nil if there is none
+	UseLoginChallengeAttempt(ctx context.Context, id string, maxAttempts int) (*LoginChallenge, error)
+	// DeleteLoginChallenge deletes a challenge, reporting whether it still existed
+	DeleteLoginChallenge(ctx context.Context, id string) (bool, error)
+	// Anonymize stores the anonymized fields of a user not yet erased, returning ErrUserErased otherwise
+	Anonymize(ctx context.Context, user *User) error
+	Delete(ctx context.Context, id int) error
+	// List fetches a page of the users matching filter
+	List(ctx context.Context, filter UserFilter) ([]*User, error)
+	// Count counts the users matching filter, ignoring its sort and page
+	Count(ctx context.Context, filter UserFilter) (int, error)
+	// ListIDsByRole retrieves the IDs of the users with a role who haven't been erased
+	ListIDsByRole(ctx context.Context, role string) ([]int, error)
 	Ping(ctx context.Context) error
 }
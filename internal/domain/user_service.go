@@ -1,11 +1,38 @@
 package domain
 
+import "context"
+
 // UserService defines business logic for users.
 type UserService interface {
-	Register(username, email, password string) (*User, error)
-	Login(username, password string) (*User, error)
-	GetUser(id int) (*User, error)
-	ListUsers() ([]*User, error)
-	UpdateUser(user *User) error
-	DeleteUser(id int) error
+	// Register creates a user; a non-empty referralCode attributes them to the
+	// user whose code it is
+	Register(ctx context.Context, username, email, password, referralCode string) (*User, error)
+	// Login checks a user's credentials; client identifies the device they log
+	// in from. A login from an untrusted device returns a *StepUpRequiredError.
+	Login(ctx context.Context, username, password string, client LoginClient) (*User, error)
+	// VerifyLogin completes a login that required step-up verification, trusting its device
+	VerifyLogin(ctx context.Context, challengeID, code string, client LoginClient) (*User, error)
+	// ListDevices returns a user's trusted devices, most recently used first
+	ListDevices(ctx context.Context, userID int) ([]*Device, error)
+	// RevokeDevice stops trusting a device of a user on behalf of actorID
+	RevokeDevice(ctx context.Context, userID int, fingerprint string, actorID int) error
+	GetUser(ctx context.Context, id int) (*User, error)
+	ListUsers(ctx context.Context, filter UserFilter) ([]*User, error)
+	CountUsers(ctx context.Context, filter UserFilter) (int, error)
+	UpdateUser(ctx context.Context, user *User) error
+	DeleteUser(ctx context.Context, id int) error
+	// ChangePassword replaces a user's password after verifying the current one
+	ChangePassword(ctx context.Context, id int, currentPassword, newPassword string) error
+	// ListLogins returns a page of a user's login attempts, newest first
+	ListLogins(ctx context.Context, userID, limit, offset int) ([]*LoginEvent, error)
+	// CountLogins counts a user's login attempts
+	CountLogins(ctx context.Context, userID int) (int, error)
+	// SetAvatar replaces a user's avatar with an uploaded image
+	SetAvatar(ctx context.Context, id int, data []byte) (*User, error)
+	// GetAvatar returns a user's avatar image and its content type
+	GetAvatar(ctx context.Context, id int) ([]byte, string, error)
+	// DeleteAvatar removes a user's avatar
+	DeleteAvatar(ctx context.Context, id int) error
+	// EraseUser anonymizes a user's personal data on behalf of actorID, keeping their transactions
+	EraseUser(ctx context.Context, id, actorID int) (*User, error)
 }
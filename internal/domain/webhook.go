@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Webhook event statuses.
+const (
+	WebhookReceived  = "received"
+	WebhookProcessed = "processed" // applied as a credit or refund
+	WebhookIgnored   = "ignored"   // a type nothing is done for
+	WebhookFailed    = "failed"    // can't be applied; see its error
+)
+
+// Gateway event actions
+const (
+	GatewayActionCredit = "credit" // a payment to credit to its user
+	GatewayActionRefund = "refund" // a refund of a credited payment to debit
+	GatewayActionIgnore = "ignore"
+)
+
+var (
+	// ErrWebhookProviderNotFound is returned for webhooks of a provider
+	// without a configured secret
+	ErrWebhookProviderNotFound = NewError(ErrorKindNotFound, "webhook_provider_not_found", "unknown webhook provider")
+	// ErrWebhookInvalidSignature is returned for webhooks whose signature or
+	// timestamp doesn't check out, or whose body can't be read
+	ErrWebhookInvalidSignature = NewError(ErrorKindForbidden, "webhook_invalid_signature", "invalid webhook signature")
+	// ErrWebhookEventNotFound is returned when a webhook event does not exist
+	ErrWebhookEventNotFound = NewError(ErrorKindNotFound, "webhook_event_not_found", "webhook event not found")
+	// ErrWebhookEventExists is returned when storing an event a provider has
+	// already delivered
+	ErrWebhookEventExists = NewError(ErrorKindConflict, "webhook_event_exists", "webhook event already received")
+)
+
+// WebhookDelivery is a provider's webhook request.
+type WebhookDelivery struct {
+	Provider  string
+	Signature string
+	Body      []byte
+}
+
+// GatewayEvent is a verified provider event translated into what it asks for.
+type GatewayEvent struct {
+	ID       string
+	Type     string
+	Action   string
+	ObjectID string
+	ChargeID string
+	UserID   int
+	Amount   float64
+	Currency string
+}
+
+// PaymentGateway verifies and translates the webhooks of a payment provider
+type PaymentGateway interface {
+	// ParseEvent checks a webhook's signature and translates its event,
+	// returning ErrWebhookInvalidSignature if it can't be trusted or read
+	ParseEvent(signature string, body []byte) (*GatewayEvent, error)
+}
+
+// WebhookEvent is a provider event as received, kept verbatim in Payload for audit.
+type WebhookEvent struct {
+	ID            int             `json:"id"`
+	Provider      string          `json:"provider"`
+	EventID       string          `json:"event_id"`
+	Type          string          `json:"type"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+	Status        string          `json:"status"`
+	TransactionID *int            `json:"transaction_id,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	ReceivedAt    time.Time       `json:"received_at"`
+	ProcessedAt   *time.Time      `json:"processed_at,omitempty"`
+}
+
+// WebhookRepository defines methods for webhook event data access
+type WebhookRepository interface {
+	// Create stores a received event, returning ErrWebhookEventExists if the
+	// provider already delivered its ID
+	Create(ctx context.Context, event *WebhookEvent) error
+	// GetByID fetches an event with its payload, or nil if there is none
+	GetByID(ctx context.Context, id int) (*WebhookEvent, error)
+	// GetByEventID fetches a provider's event by its ID, or nil if there is none
+	GetByEventID(ctx context.Context, provider, eventID string) (*WebhookEvent, error)
+	// List fetches a page of events, newest first, without their payloads.
+	// Empty provider and status match every event.
+	List(ctx context.Context, provider, status string, limit, offset int) ([]*WebhookEvent, error)
+	// Update stores the outcome of an event
+	Update(ctx context.Context, event *WebhookEvent) error
+}
+
+// WebhookService defines business logic for payment provider webhooks
+type WebhookService interface {
+	// Receive verifies a webhook, stores its event and applies it once
+	Receive(ctx context.Context, delivery *WebhookDelivery) (*WebhookEvent, error)
+	// GetEvent returns an event with its payload
+	GetEvent(ctx context.Context, id int) (*WebhookEvent, error)
+	// ListEvents returns a page of events, newest first
+	ListEvents(ctx context.Context, provider, status string, limit, offset int) ([]*WebhookEvent, error)
+}
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"strconv"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// csvWriter writes one row per transaction with the running balance
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w *bufio.Writer) formatWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) begin(*statement) error {
+	return c.w.Write([]string{"date", "transaction_id", "type", "description", "amount", "balance"})
+}
+
+func (c *csvWriter) entry(e *domain.LedgerEntry, balance float64) error {
+	return c.w.Write([]string{
+		e.CreatedAt.Format(time.RFC3339),
+		strconv.Itoa(e.TransactionID),
+		e.Type,
+		description(e),
+		formatAmount(e.Amount),
+		formatAmount(balance),
+	})
+}
+
+func (c *csvWriter) end(*statement, float64) error {
+	c.w.Flush()
+	return c.w.Error()
+}
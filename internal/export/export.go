@@ -0,0 +1,145 @@
+// Package export writes a user's transaction history as CSV, OFX or QIF.
+package export
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// MaxRange is the longest period one export may cover
+const MaxRange = 5 * 366 * 24 * time.Hour
+
+// Config sets how exported statements describe the account
+type Config struct {
+	Currency    string // ISO 4217 code of every amount
+	Institution string // name of the financial institution in OFX files
+	BankID      string // routing number identifying the institution in OFX files
+}
+
+// formatWriter writes one file format.
+type formatWriter interface {
+	begin(st *statement) error
+	entry(e *domain.LedgerEntry, balance float64) error
+	end(st *statement, closing float64) error
+}
+
+// formats maps each supported format to its content type, file extension and writer
+var formats = map[string]struct {
+	contentType string
+	newWriter   func(w *bufio.Writer) formatWriter
+}{
+	"csv": {"text/csv", newCSVWriter},
+	"ofx": {"application/x-ofx", newOFXWriter},
+	"qif": {"application/qif", newQIFWriter},
+}
+
+// statement is an export being written
+type statement struct {
+	*domain.TransactionExport
+	cfg         Config
+	generatedAt time.Time
+}
+
+// Service implements domain.TransactionExportService
+type Service struct {
+	repo domain.TransactionExportRepository
+	cfg  Config
+	now  func() time.Time
+}
+
+// NewService creates a new export Service
+func NewService(repo domain.TransactionExportRepository, cfg Config) *Service {
+	return &Service{
+		repo: repo,
+		cfg:  cfg,
+		now:  time.Now,
+	}
+}
+
+// Prepare validates an export of the user's transactions in [from, to).
+func (s *Service) Prepare(ctx context.Context, userID int, format string, from, to time.Time) (*domain.TransactionExport, error) {
+	f, ok := formats[format]
+	if !ok {
+		return nil, &domain.ValidationError{Msg: "format must be csv, ofx or qif"}
+	}
+	from, to = from.UTC(), to.UTC()
+	if !from.Before(to) {
+		return nil, &domain.ValidationError{Msg: "from must be before to"}
+	}
+	if to.Sub(from) > MaxRange {
+		return nil, &domain.ValidationError{Msg: "an export may cover at most five years"}
+	}
+
+	opening, err := s.repo.OpeningBalance(ctx, userID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute opening balance: %w", err)
+	}
+	return &domain.TransactionExport{
+		UserID:         userID,
+		Format:         format,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening,
+		ContentType:    f.contentType,
+		FileName:       fmt.Sprintf("transactions-%d-%s-%s.%s", userID, from.Format("20060102"), to.Format("20060102"), format),
+	}, nil
+}
+
+// Write streams a prepared export to w
+func (s *Service) Write(ctx context.Context, w io.Writer, export *domain.TransactionExport) error {
+	f, ok := formats[export.Format]
+	if !ok {
+		return &domain.ValidationError{Msg: "format must be csv, ofx or qif"}
+	}
+	st := &statement{TransactionExport: export, cfg: s.cfg, generatedAt: s.now().UTC()}
+	bw := bufio.NewWriter(w)
+	fw := f.newWriter(bw)
+
+	if err := fw.begin(st); err != nil {
+		return err
+	}
+	balance := export.OpeningBalance
+	err := s.repo.StreamLedger(ctx, export.UserID, export.From, export.To, func(e *domain.LedgerEntry) error {
+		balance = round2(balance + e.Amount)
+		return fw.entry(e, balance)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export transactions: %w", err)
+	}
+	if err := fw.end(st, balance); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// description names an entry the way a bank statement would
+func description(e *domain.LedgerEntry) string {
+	switch {
+	case e.Type == "transfer" && e.CounterpartyID != nil && e.Amount < 0:
+		return fmt.Sprintf("Transfer to user %d", *e.CounterpartyID)
+	case e.Type == "transfer" && e.CounterpartyID != nil:
+		return fmt.Sprintf("Transfer from user %d", *e.CounterpartyID)
+	case e.Type == "credit":
+		return "Credit"
+	case e.Type == "debit":
+		return "Debit"
+	}
+	return e.Type
+}
+
+// formatAmount formats an amount with two decimals and a leading minus if negative
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(round2(amount), 'f', 2, 64)
+}
+
+// round2 rounds an amount to cents, keeping a running balance free of float drift
+func round2(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
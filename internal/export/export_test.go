@@ -0,0 +1,132 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// fakeLedger serves a fixed list of entries
+type fakeLedger struct {
+	opening float64
+	entries []*domain.LedgerEntry
+	err     error
+}
+
+func (f *fakeLedger) OpeningBalance(ctx context.Context, userID int, before time.Time) (float64, error) {
+	return f.opening, nil
+}
+
+func (f *fakeLedger) StreamLedger(ctx context.Context, userID int, from, to time.Time, fn func(*domain.LedgerEntry) error) error {
+	for _, e := range f.entries {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+func intPtr(v int) *int { return &v }
+
+var (
+	from = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to   = time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func testLedger() *fakeLedger {
+	return &fakeLedger{
+		opening: 100,
+		entries: []*domain.LedgerEntry{
+			{TransactionID: 11, Type: "credit", Amount: 50.25, CreatedAt: time.Date(2026, 1, 3, 9, 30, 0, 0, time.UTC)},
+			{TransactionID: 12, Type: "transfer", Amount: -20.1, CounterpartyID: intPtr(7), CreatedAt: time.Date(2026, 1, 5, 14, 0, 0, 0, time.UTC)},
+			{TransactionID: 13, Type: "debit", Amount: -0.15, CreatedAt: time.Date(2026, 1, 9, 18, 45, 0, 0, time.UTC)},
+		},
+	}
+}
+
+func writeExport(t *testing.T, repo *fakeLedger, format string) (*domain.TransactionExport, string) {
+	t.Helper()
+	s := NewService(repo, Config{Currency: "EUR", Institution: "Insider & Co", BankID: "123456"})
+	s.now = func() time.Time { return to }
+	exp, err := s.Prepare(context.Background(), 42, format, from, to)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, s.Write(context.Background(), &buf, exp))
+	return exp, buf.String()
+}
+
+func TestPrepare_RejectsBadRequests(t *testing.T) {
+	s := NewService(testLedger(), Config{Currency: "USD"})
+	var verr *domain.ValidationError
+
+	_, err := s.Prepare(context.Background(), 1, "xls", from, to)
+	assert.ErrorAs(t, err, &verr)
+	_, err = s.Prepare(context.Background(), 1, "csv", to, from)
+	assert.ErrorAs(t, err, &verr)
+	_, err = s.Prepare(context.Background(), 1, "csv", from, from.AddDate(6, 0, 0))
+	assert.ErrorAs(t, err, &verr)
+}
+
+func TestPrepare_NamesFile(t *testing.T) {
+	exp, _ := writeExport(t, testLedger(), "ofx")
+	assert.Equal(t, "application/x-ofx", exp.ContentType)
+	assert.Equal(t, "transactions-42-20260101-20260201.ofx", exp.FileName)
+	assert.Equal(t, 100.0, exp.OpeningBalance)
+}
+
+func TestWrite_CSV(t *testing.T) {
+	_, out := writeExport(t, testLedger(), "csv")
+	assert.Equal(t, "date,transaction_id,type,description,amount,balance\n"+
+		"2026-01-03T09:30:00Z,11,credit,Credit,50.25,150.25\n"+
+		"2026-01-05T14:00:00Z,12,transfer,Transfer to user 7,-20.10,130.15\n"+
+		"2026-01-09T18:45:00Z,13,debit,Debit,-0.15,130.00\n", out)
+}
+
+func TestWrite_OFX(t *testing.T) {
+	_, out := writeExport(t, testLedger(), "ofx")
+	assert.True(t, strings.HasPrefix(out, "OFXHEADER:100\r\nDATA:OFXSGML\r\n"))
+	assert.Contains(t, out, "<ORG>Insider &amp; Co\r\n")
+	assert.Contains(t, out, "<CURDEF>EUR\r\n<BANKACCTFROM>\r\n<BANKID>123456\r\n<ACCTID>42\r\n")
+	assert.Contains(t, out, "<DTSTART>20260101000000\r\n<DTEND>20260201000000\r\n")
+	assert.Contains(t, out, "<STMTTRN>\r\n<TRNTYPE>XFER\r\n<DTPOSTED>20260105140000\r\n<TRNAMT>-20.10\r\n<FITID>12\r\n<NAME>Transfer to user 7\r\n")
+	assert.Contains(t, out, "<TRNTYPE>DEBIT\r\n<DTPOSTED>20260109184500\r\n<TRNAMT>-0.15\r\n")
+	assert.Contains(t, out, "<LEDGERBAL>\r\n<BALAMT>130.00\r\n<DTASOF>20260201000000\r\n")
+	assert.Equal(t, 3, strings.Count(out, "<STMTTRN>"))
+	assert.True(t, strings.HasSuffix(out, "</OFX>\r\n"))
+}
+
+func TestWrite_QIF(t *testing.T) {
+	_, out := writeExport(t, testLedger(), "qif")
+	assert.Equal(t, "!Type:Bank\n"+
+		"D01/01/2026\nT100.00\nPOpening Balance\n^\n"+
+		"D01/03/2026\nT50.25\nN11\nPCredit\nMcredit\n^\n"+
+		"D01/05/2026\nT-20.10\nN12\nPTransfer to user 7\nMtransfer\n^\n"+
+		"D01/09/2026\nT-0.15\nN13\nPDebit\nMdebit\n^\n", out)
+}
+
+func TestWrite_IncomingTransfer(t *testing.T) {
+	repo := &fakeLedger{entries: []*domain.LedgerEntry{
+		{TransactionID: 1, Type: "transfer", Amount: 5, CounterpartyID: intPtr(3), CreatedAt: from},
+	}}
+	_, out := writeExport(t, repo, "csv")
+	assert.Contains(t, out, ",Transfer from user 3,5.00,5.00\n")
+}
+
+func TestWrite_ReturnsStreamError(t *testing.T) {
+	repo := testLedger()
+	repo.err = errors.New("connection lost")
+	s := NewService(repo, Config{Currency: "USD"})
+	exp, err := s.Prepare(context.Background(), 42, "csv", from, to)
+	require.NoError(t, err)
+
+	err = s.Write(context.Background(), &bytes.Buffer{}, exp)
+	assert.ErrorContains(t, err, "connection lost")
+}
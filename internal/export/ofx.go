@@ -0,0 +1,112 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// ofxTime is the date and time layout of OFX, in UTC
+const ofxTime = "20060102150405"
+
+// ofxEscaper escapes the characters SGML gives a meaning to
+var ofxEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// ofxWriter writes an OFX 1.0.2 SGML bank statement.
+type ofxWriter struct {
+	w *bufio.Writer
+}
+
+func newOFXWriter(w *bufio.Writer) formatWriter {
+	return &ofxWriter{w: w}
+}
+
+func (o *ofxWriter) begin(st *statement) error {
+	header := []string{
+		"OFXHEADER:100",
+		"DATA:OFXSGML",
+		"VERSION:102",
+		"SECURITY:NONE",
+		"ENCODING:USASCII",
+		"CHARSET:1252",
+		"COMPRESSION:NONE",
+		"OLDFILEUID:NONE",
+		"NEWFILEUID:NONE",
+		"",
+	}
+	for _, line := range header {
+		o.line(line)
+	}
+	o.line("<OFX>")
+	o.line("<SIGNONMSGSRSV1>")
+	o.line("<SONRS>")
+	o.line("<STATUS>")
+	o.line("<CODE>0")
+	o.line("<SEVERITY>INFO")
+	o.line("</STATUS>")
+	o.line("<DTSERVER>" + st.generatedAt.Format(ofxTime))
+	o.line("<LANGUAGE>ENG")
+	if st.cfg.Institution != "" {
+		o.line("<FI>")
+		o.line("<ORG>" + ofxEscaper.Replace(st.cfg.Institution))
+		o.line("</FI>")
+	}
+	o.line("</SONRS>")
+	o.line("</SIGNONMSGSRSV1>")
+	o.line("<BANKMSGSRSV1>")
+	o.line("<STMTTRNRS>")
+	o.line("<TRNUID>0")
+	o.line("<STATUS>")
+	o.line("<CODE>0")
+	o.line("<SEVERITY>INFO")
+	o.line("</STATUS>")
+	o.line("<STMTRS>")
+	o.line("<CURDEF>" + st.cfg.Currency)
+	o.line("<BANKACCTFROM>")
+	o.line("<BANKID>" + ofxEscaper.Replace(st.cfg.BankID))
+	o.line("<ACCTID>" + strconv.Itoa(st.UserID))
+	o.line("<ACCTTYPE>CHECKING")
+	o.line("</BANKACCTFROM>")
+	o.line("<BANKTRANLIST>")
+	o.line("<DTSTART>" + st.From.Format(ofxTime))
+	return o.line("<DTEND>" + st.To.Format(ofxTime))
+}
+
+func (o *ofxWriter) entry(e *domain.LedgerEntry, _ float64) error {
+	trnType := "CREDIT"
+	switch {
+	case e.Type == "transfer":
+		trnType = "XFER"
+	case e.Amount < 0:
+		trnType = "DEBIT"
+	}
+	o.line("<STMTTRN>")
+	o.line("<TRNTYPE>" + trnType)
+	o.line("<DTPOSTED>" + e.CreatedAt.Format(ofxTime))
+	o.line("<TRNAMT>" + formatAmount(e.Amount))
+	o.line("<FITID>" + strconv.Itoa(e.TransactionID))
+	o.line("<NAME>" + ofxEscaper.Replace(description(e)))
+	o.line(fmt.Sprintf("<MEMO>%s transaction %d", e.Type, e.TransactionID))
+	return o.line("</STMTTRN>")
+}
+
+func (o *ofxWriter) end(st *statement, closing float64) error {
+	o.line("</BANKTRANLIST>")
+	o.line("<LEDGERBAL>")
+	o.line("<BALAMT>" + formatAmount(closing))
+	o.line("<DTASOF>" + st.To.Format(ofxTime))
+	o.line("</LEDGERBAL>")
+	o.line("</STMTRS>")
+	o.line("</STMTTRNRS>")
+	o.line("</BANKMSGSRSV1>")
+	return o.line("</OFX>")
+}
+
+// line writes one line.
+func (o *ofxWriter) line(s string) error {
+	_, err := o.w.WriteString(s + "\r\n")
+	return err
+}
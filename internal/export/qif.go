@@ -0,0 +1,47 @@
+package export
+
+import (
+	"bufio"
+	"strconv"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// qifDate is the date layout of QIF, which US-locale importers all accept
+const qifDate = "01/02/2006"
+
+// qifWriter writes a QIF bank register.
+type qifWriter struct {
+	w *bufio.Writer
+}
+
+func newQIFWriter(w *bufio.Writer) formatWriter {
+	return &qifWriter{w: w}
+}
+
+func (q *qifWriter) begin(st *statement) error {
+	q.line("!Type:Bank")
+	q.line("D" + st.From.Format(qifDate))
+	q.line("T" + formatAmount(st.OpeningBalance))
+	q.line("POpening Balance")
+	return q.line("^")
+}
+
+func (q *qifWriter) entry(e *domain.LedgerEntry, _ float64) error {
+	q.line("D" + e.CreatedAt.Format(qifDate))
+	q.line("T" + formatAmount(e.Amount))
+	q.line("N" + strconv.Itoa(e.TransactionID))
+	q.line("P" + description(e))
+	q.line("M" + e.Type)
+	return q.line("^")
+}
+
+func (q *qifWriter) end(*statement, float64) error {
+	return nil
+}
+
+// line writes one line; see ofxWriter.line for why errors are checked last
+func (q *qifWriter) line(s string) error {
+	_, err := q.w.WriteString(s + "\n")
+	return err
+}
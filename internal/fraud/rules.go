@@ -0,0 +1,104 @@
+// Package fraud scores debits and transfers before they run.
+package fraud
+
+import (
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// Rule names, used as assessment reasons and metric labels
+const (
+	RuleVelocity        = "velocity"
+	RuleUnusualAmount   = "unusual_amount"
+	RuleNewCounterparty = "new_counterparty"
+	RuleOddHours        = "odd_hours"
+)
+
+// Config holds the fraud rules' parameters and the decision thresholds.
+type Config struct {
+	FlagScore   float64
+	HoldScore   float64
+	RejectScore float64
+
+	// More than VelocityMaxCount debits and transfers within VelocityWindow
+	VelocityWindow   time.Duration
+	VelocityMaxCount int
+
+	// An amount over AmountMultiplier times the average of the completed
+	// debits and transfers within HistoryWindow, once there are MinHistory of them
+	HistoryWindow    time.Duration
+	MinHistory       int
+	AmountMultiplier float64
+
+	// UTC hours in [OddHoursStart, OddHoursEnd); equal hours disable the rule
+	OddHoursStart int
+	OddHoursEnd   int
+}
+
+// rule is one fraud check and the points it adds when it matches
+type rule struct {
+	name   string
+	points float64
+	match  func(cfg Config, tx *domain.Transaction, s *domain.FraudSignals, at time.Time) bool
+}
+
+// rules add up to 100
+var rules = []rule{
+	{RuleVelocity, 35, func(cfg Config, _ *domain.Transaction, s *domain.FraudSignals, _ time.Time) bool {
+		// The transaction being scored counts towards the window too
+		return cfg.VelocityMaxCount > 0 && s.RecentCount+1 > cfg.VelocityMaxCount
+	}},
+	{RuleUnusualAmount, 35, func(cfg Config, tx *domain.Transaction, s *domain.FraudSignals, _ time.Time) bool {
+		return cfg.AmountMultiplier > 0 && s.HistoryCount >= cfg.MinHistory && s.HistoryCount > 0 &&
+			tx.Amount > cfg.AmountMultiplier*s.HistoryAverage
+	}},
+	{RuleNewCounterparty, 20, func(_ Config, tx *domain.Transaction, s *domain.FraudSignals, _ time.Time) bool {
+		return tx.Type == "transfer" && !s.KnownCounterparty
+	}},
+	{RuleOddHours, 10, func(cfg Config, _ *domain.Transaction, _ *domain.FraudSignals, at time.Time) bool {
+		return inHours(at.UTC().Hour(), cfg.OddHoursStart, cfg.OddHoursEnd)
+	}},
+}
+
+// Score adds up the points of the rules tx matches and names them
+func Score(cfg Config, tx *domain.Transaction, s *domain.FraudSignals, at time.Time) (float64, []string) {
+	score := 0.0
+	reasons := []string{}
+	for _, r := range rules {
+		if r.match(cfg, tx, s, at) {
+			score += r.points
+			reasons = append(reasons, r.name)
+		}
+	}
+	if score > 100 {
+		score = 100
+	}
+	return score, reasons
+}
+
+// Decide maps a score to the most severe decision whose threshold it reaches
+func Decide(cfg Config, score float64) string {
+	switch {
+	case reaches(score, cfg.RejectScore):
+		return domain.FraudDecisionReject
+	case reaches(score, cfg.HoldScore):
+		return domain.FraudDecisionHold
+	case reaches(score, cfg.FlagScore):
+		return domain.FraudDecisionFlag
+	default:
+		return domain.FraudDecisionAllow
+	}
+}
+
+func reaches(score, threshold float64) bool {
+	return threshold > 0 && score >= threshold
+}
+
+// inHours reports whether hour is in [start, end), wrapping past midnight when end < start
+func inHours(hour, start, end int) bool {
+	if start <= end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
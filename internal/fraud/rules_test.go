@@ -0,0 +1,91 @@
+package fraud
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+var testConfig = Config{
+	FlagScore:        30,
+	HoldScore:        55,
+	RejectScore:      80,
+	VelocityWindow:   10 * time.Minute,
+	VelocityMaxCount: 5,
+	HistoryWindow:    90 * 24 * time.Hour,
+	MinHistory:       5,
+	AmountMultiplier: 5,
+	OddHoursStart:    1,
+	OddHoursEnd:      5,
+}
+
+func transfer(amount float64) *domain.Transaction {
+	from, to := 1, 2
+	return &domain.Transaction{FromUserID: &from, ToUserID: &to, Amount: amount, Type: "transfer"}
+}
+
+func TestScore(t *testing.T) {
+	noon := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC)
+	usual := &domain.FraudSignals{RecentCount: 1, HistoryCount: 20, HistoryAverage: 100, KnownCounterparty: true}
+
+	tests := []struct {
+		name    string
+		tx      *domain.Transaction
+		signals domain.FraudSignals
+		at      time.Time
+		score   float64
+		reasons []string
+	}{
+		{"usual transfer", transfer(150), *usual, noon, 0, []string{}},
+		{"velocity", transfer(150), domain.FraudSignals{RecentCount: 5, HistoryCount: 20, HistoryAverage: 100, KnownCounterparty: true}, noon, 35, []string{RuleVelocity}},
+		{"unusual amount", transfer(600), *usual, noon, 35, []string{RuleUnusualAmount}},
+		{"too little history to compare", transfer(600), domain.FraudSignals{HistoryCount: 4, HistoryAverage: 10, KnownCounterparty: true}, noon, 0, []string{}},
+		{"new counterparty at night", transfer(150), domain.FraudSignals{HistoryCount: 20, HistoryAverage: 100}, night, 30, []string{RuleNewCounterparty, RuleOddHours}},
+		{"debit has no counterparty", &domain.Transaction{Amount: 150, Type: "debit"}, domain.FraudSignals{HistoryCount: 20, HistoryAverage: 100}, noon, 0, []string{}},
+		{"everything", transfer(600), domain.FraudSignals{RecentCount: 9, HistoryCount: 20, HistoryAverage: 100}, night, 100, []string{RuleVelocity, RuleUnusualAmount, RuleNewCounterparty, RuleOddHours}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := Score(testConfig, tt.tx, &tt.signals, tt.at)
+			if score != tt.score || !reflect.DeepEqual(reasons, tt.reasons) {
+				t.Errorf("Score() = %v %v, want %v %v", score, reasons, tt.score, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestDecide(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{0, domain.FraudDecisionAllow},
+		{29, domain.FraudDecisionAllow},
+		{30, domain.FraudDecisionFlag},
+		{55, domain.FraudDecisionHold},
+		{100, domain.FraudDecisionReject},
+	}
+	for _, tt := range tests {
+		if got := Decide(testConfig, tt.score); got != tt.want {
+			t.Errorf("Decide(%v) = %q, want %q", tt.score, got, tt.want)
+		}
+	}
+
+	noReject := testConfig
+	noReject.RejectScore = 0
+	if got := Decide(noReject, 100); got != domain.FraudDecisionHold {
+		t.Errorf("Decide with rejection disabled = %q, want %q", got, domain.FraudDecisionHold)
+	}
+}
+
+func TestInHours(t *testing.T) {
+	if !inHours(23, 22, 4) || !inHours(2, 22, 4) || inHours(12, 22, 4) {
+		t.Error("inHours should wrap past midnight")
+	}
+	if inHours(3, 0, 0) {
+		t.Error("equal start and end hours should match nothing")
+	}
+}
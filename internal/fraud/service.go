@@ -0,0 +1,119 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// Service implements domain.FraudService
+type Service struct {
+	repo  domain.FraudRepository
+	audit domain.AuditLogService
+	cfg   Config
+	now   func() time.Time
+}
+
+// NewService creates a new fraud Service
+func NewService(repo domain.FraudRepository, audit domain.AuditLogService, cfg Config) *Service {
+	return &Service{
+		repo:  repo,
+		audit: audit,
+		cfg:   cfg,
+		now:   time.Now,
+	}
+}
+
+// Assess scores a debit or transfer against the paying user's history and stores the score.
+func (s *Service) Assess(ctx context.Context, tx *domain.Transaction) (*domain.FraudAssessment, error) {
+	if tx.FromUserID == nil || (tx.Type != "debit" && tx.Type != "transfer") {
+		return nil, &domain.ValidationError{Msg: "only debits and transfers are scored for fraud"}
+	}
+
+	now := s.now()
+	var counterpartyID *int
+	if tx.Type == "transfer" {
+		counterpartyID = tx.ToUserID
+	}
+	signals, err := s.repo.Signals(ctx, *tx.FromUserID, counterpartyID, now.Add(-s.cfg.VelocityWindow), now.Add(-s.cfg.HistoryWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather fraud signals: %w", err)
+	}
+
+	score, reasons := Score(s.cfg, tx, signals, now)
+	assessment := &domain.FraudAssessment{
+		UserID:         *tx.FromUserID,
+		CounterpartyID: counterpartyID,
+		Type:           tx.Type,
+		Amount:         tx.Amount,
+		Score:          score,
+		Reasons:        reasons,
+		Decision:       Decide(s.cfg, score),
+		CreatedAt:      now.UTC(),
+	}
+	if assessment.Decision == domain.FraudDecisionFlag || assessment.Decision == domain.FraudDecisionHold {
+		assessment.ReviewStatus = "pending"
+	}
+	if err := s.repo.Create(ctx, assessment); err != nil {
+		return nil, fmt.Errorf("failed to store fraud assessment: %w", err)
+	}
+
+	metrics.FraudAssessments.WithLabelValues(assessment.Decision).Inc()
+	metrics.FraudScore.Observe(score)
+	for _, reason := range reasons {
+		metrics.FraudRuleHits.WithLabelValues(reason).Inc()
+	}
+	if assessment.Decision != domain.FraudDecisionAllow {
+		log.Warn().
+			Int("assessment_id", assessment.ID).
+			Int("user_id", assessment.UserID).
+			Float64("amount", tx.Amount).
+			Float64("score", score).
+			Strs("reasons", reasons).
+			Str("decision", assessment.Decision).
+			Msg("Transaction failed fraud screening")
+	}
+	return assessment, nil
+}
+
+// LinkTransaction records the transaction created for an assessment
+func (s *Service) LinkTransaction(ctx context.Context, assessmentID, transactionID int) error {
+	return s.repo.LinkTransaction(ctx, assessmentID, transactionID)
+}
+
+// ListReviewQueue retrieves flagged and held assessments awaiting review, oldest first
+func (s *Service) ListReviewQueue(ctx context.Context, limit, offset int) ([]*domain.FraudAssessment, error) {
+	return s.repo.ListPendingReview(ctx, limit, offset)
+}
+
+// Resolve records an admin's verdict on a queued assessment.
+func (s *Service) Resolve(ctx context.Context, id, reviewerID int, confirmed bool, note string) (*domain.FraudAssessment, error) {
+	assessment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fraud assessment: %w", err)
+	}
+	if assessment == nil {
+		return nil, domain.ErrFraudAssessmentNotFound
+	}
+
+	if err := assessment.Resolve(reviewerID, confirmed, note); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateReview(ctx, assessment); err != nil {
+		return nil, fmt.Errorf("failed to update fraud assessment: %w", err)
+	}
+
+	details := fmt.Sprintf("score %.0f (%s decision): %s", assessment.Score, assessment.Decision, assessment.ReviewStatus)
+	if note != "" {
+		details += ": " + note
+	}
+	if err := s.audit.Record(ctx, &reviewerID, "fraud_assessment", assessment.ID, "fraud_review", details); err != nil {
+		log.Error().Err(err).Int("assessment_id", assessment.ID).Int("actor_id", reviewerID).Msg("Failed to audit fraud review")
+	}
+	return assessment, nil
+}
@@ -0,0 +1,129 @@
+// Package gateway verifies and translates the webhooks of payment providers.
+package gateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// SignatureHeader carries a webhook's signature.
+const SignatureHeader = "Stripe-Signature"
+
+// Event types translated into credits and refunds; other types are ignored
+const (
+	EventChargeSucceeded = "charge.succeeded"
+	EventRefundCreated   = "refund.created"
+)
+
+// Sign returns the signature header value of body signed with secret at timestamp.
+func Sign(secret []byte, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + signature(secret, t, body)
+}
+
+// signature returns the hex HMAC-SHA256 of "timestamp.body"
+func signature(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// event is the JSON of a provider event
+type event struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object struct {
+			ID       string            `json:"id"`
+			Amount   int64             `json:"amount"` // minor currency units
+			Currency string            `json:"currency"`
+			Charge   string            `json:"charge"` // the payment a refund returns
+			Metadata map[string]string `json:"metadata"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// Stripe is a provider signing its webhooks with a shared secret.
+type Stripe struct {
+	secret    []byte
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewStripe creates a Stripe accepting webhooks signed with secret.
+func NewStripe(secret string, tolerance time.Duration) *Stripe {
+	return &Stripe{secret: []byte(secret), tolerance: tolerance, now: time.Now}
+}
+
+// ParseEvent checks a webhook's signature and timestamp, and translates its event.
+func (s *Stripe) ParseEvent(header string, body []byte) (*domain.GatewayEvent, error) {
+	if err := s.verify(header, body); err != nil {
+		return nil, err
+	}
+	var e event
+	if err := json.Unmarshal(body, &e); err != nil || e.ID == "" || e.Type == "" {
+		return nil, domain.ErrWebhookInvalidSignature
+	}
+
+	object := e.Data.Object
+	result := &domain.GatewayEvent{
+		ID:       e.ID,
+		Type:     e.Type,
+		Action:   domain.GatewayActionIgnore,
+		ObjectID: object.ID,
+		Amount:   float64(object.Amount) / 100,
+		Currency: strings.ToUpper(object.Currency),
+	}
+	switch e.Type {
+	case EventChargeSucceeded:
+		result.Action, result.ChargeID = domain.GatewayActionCredit, object.ID
+		// A missing or malformed user ID leaves it zero, which is refused
+		result.UserID, _ = strconv.Atoi(object.Metadata["user_id"])
+	case EventRefundCreated:
+		result.Action, result.ChargeID = domain.GatewayActionRefund, object.Charge
+	}
+	return result, nil
+}
+
+// verify checks the signature header of body and the age of its timestamp
+func (s *Stripe) verify(header string, body []byte) error {
+	if len(s.secret) == 0 {
+		return domain.ErrWebhookInvalidSignature
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return domain.ErrWebhookInvalidSignature
+	}
+	if age := s.now().Sub(time.Unix(unix, 0)); age > s.tolerance || age < -s.tolerance {
+		return domain.ErrWebhookInvalidSignature
+	}
+
+	// Providers send a signature per active secret while rolling secrets
+	want := []byte(signature(s.secret, timestamp, body))
+	for _, sig := range signatures {
+		if hmac.Equal(want, []byte(sig)) {
+			return nil
+		}
+	}
+	return domain.ErrWebhookInvalidSignature
+}
@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+func TestStripe_ParseEvent(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewStripe("whsec", 5*time.Minute)
+	s.now = func() time.Time { return now }
+
+	charge := []byte(`{"id": "evt_1", "type": "charge.succeeded",
+		"data": {"object": {"id": "ch_1", "amount": 2050, "currency": "usd", "metadata": {"user_id": "7"}}}}`)
+	event, err := s.ParseEvent(Sign([]byte("whsec"), now.Add(-time.Minute), charge), charge)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.GatewayEvent{
+		ID: "evt_1", Type: EventChargeSucceeded, Action: domain.GatewayActionCredit,
+		ObjectID: "ch_1", ChargeID: "ch_1", UserID: 7, Amount: 20.5, Currency: "USD",
+	}, event)
+
+	refund := []byte(`{"id": "evt_2", "type": "refund.created",
+		"data": {"object": {"id": "re_1", "amount": 500, "currency": "usd", "charge": "ch_1"}}}`)
+	event, err = s.ParseEvent(Sign([]byte("whsec"), now, refund), refund)
+	require.NoError(t, err)
+	assert.Equal(t, domain.GatewayActionRefund, event.Action)
+	assert.Equal(t, "ch_1", event.ChargeID)
+	assert.Equal(t, 5.0, event.Amount)
+	assert.Zero(t, event.UserID)
+
+	other := []byte(`{"id": "evt_3", "type": "customer.created", "data": {"object": {"id": "cus_1"}}}`)
+	event, err = s.ParseEvent(Sign([]byte("whsec"), now, other), other)
+	require.NoError(t, err)
+	assert.Equal(t, domain.GatewayActionIgnore, event.Action)
+
+	// A second signature, as sent while rolling secrets, is accepted too
+	rolled := Sign([]byte("old"), now, charge) + ",v1=" + signature([]byte("whsec"), "1772366400", charge)
+	_, err = s.ParseEvent(rolled, charge)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		header string
+		body   []byte
+	}{
+		"wrong secret": {Sign([]byte("other"), now, charge), charge},
+		"expired":      {Sign([]byte("whsec"), now.Add(-10*time.Minute), charge), charge},
+		"future":       {Sign([]byte("whsec"), now.Add(10*time.Minute), charge), charge},
+		"tampered":     {Sign([]byte("whsec"), now, charge), refund},
+		"no timestamp": {"v1=" + signature([]byte("whsec"), "", charge), charge},
+		"missing id":   {Sign([]byte("whsec"), now, []byte(`{"type": "charge.succeeded"}`)), []byte(`{"type": "charge.succeeded"}`)},
+		"not json":     {Sign([]byte("whsec"), now, []byte(`nope`)), []byte(`nope`)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.ParseEvent(tt.header, tt.body)
+			assert.ErrorIs(t, err, domain.ErrWebhookInvalidSignature)
+		})
+	}
+
+	_, err = NewStripe("", time.Minute).ParseEvent(Sign(nil, time.Now(), charge), charge)
+	assert.ErrorIs(t, err, domain.ErrWebhookInvalidSignature)
+}
@@ -0,0 +1,140 @@
+// Package geoip finds where IP addresses are using a city-level GeoIP database.
+package geoip
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// ipRange is one row of the database
+type ipRange struct {
+	start, end netip.Addr
+	location   *domain.GeoLocation
+}
+
+// DB locates IP addresses.
+type DB struct {
+	ranges []ipRange // sorted by start
+}
+
+// Load reads a database file; a name ending in .gz is gunzipped.
+func Load(path string) (*DB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("geoip: %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	db, err := Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("geoip: %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// Parse reads a database in CSV form.
+func Parse(r io.Reader) (*DB, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 8
+	cr.ReuseRecord = true
+
+	// Rows of the same place share one location
+	locations := make(map[domain.GeoLocation]*domain.GeoLocation)
+	db := &DB{}
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rng, loc, err := parseRow(rec)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		shared, ok := locations[loc]
+		if !ok {
+			shared = &loc
+			locations[loc] = shared
+		}
+		rng.location = shared
+		db.ranges = append(db.ranges, rng)
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool { return db.ranges[i].start.Less(db.ranges[j].start) })
+	for i := 1; i < len(db.ranges); i++ {
+		if !db.ranges[i-1].end.Less(db.ranges[i].start) {
+			return nil, fmt.Errorf("ranges starting at %s and %s overlap", db.ranges[i-1].start, db.ranges[i].start)
+		}
+	}
+	return db, nil
+}
+
+// parseRow parses the range and location of a CSV row
+func parseRow(rec []string) (ipRange, domain.GeoLocation, error) {
+	start, err := netip.ParseAddr(rec[0])
+	if err != nil {
+		return ipRange{}, domain.GeoLocation{}, err
+	}
+	end, err := netip.ParseAddr(rec[1])
+	if err != nil {
+		return ipRange{}, domain.GeoLocation{}, err
+	}
+	start, end = start.Unmap(), end.Unmap()
+	if start.Is4() != end.Is4() || end.Less(start) {
+		return ipRange{}, domain.GeoLocation{}, fmt.Errorf("invalid range %s-%s", start, end)
+	}
+	lat, err := strconv.ParseFloat(rec[6], 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return ipRange{}, domain.GeoLocation{}, fmt.Errorf("invalid latitude %q", rec[6])
+	}
+	lon, err := strconv.ParseFloat(rec[7], 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return ipRange{}, domain.GeoLocation{}, fmt.Errorf("invalid longitude %q", rec[7])
+	}
+	loc := domain.GeoLocation{Country: rec[3], Region: rec[4], City: rec[5], Latitude: lat, Longitude: lon}
+	return ipRange{start: start, end: end}, loc, nil
+}
+
+// Len returns the number of ranges in the database
+func (db *DB) Len() int {
+	return len(db.ranges)
+}
+
+// Locate returns the location of ip, or nil if ip is invalid, private or not in the database.
+func (db *DB) Locate(ip string) *domain.GeoLocation {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return nil
+	}
+	addr = addr.Unmap()
+	if addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsUnspecified() {
+		return nil
+	}
+	// The last range starting at or before addr is the only one that can hold it
+	i := sort.Search(len(db.ranges), func(i int) bool { return addr.Less(db.ranges[i].start) }) - 1
+	if i < 0 || db.ranges[i].end.Less(addr) || db.ranges[i].start.Is4() != addr.Is4() {
+		return nil
+	}
+	loc := *db.ranges[i].location
+	return &loc
+}
@@ -0,0 +1,65 @@
+package geoip
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDB = `1.0.0.0,1.0.0.255,OC,AU,Queensland,"South Brisbane",-27.4767,153.017
+81.212.0.0,81.215.255.255,AS,TR,Istanbul,Istanbul,41.0138,28.9497
+2001:db8::,2001:db8::ffff,EU,DE,Berlin,Berlin,52.5244,13.4105
+8.8.8.0,8.8.8.255,NA,US,California,"Mountain View",37.4056,-122.0775
+`
+
+func TestLocate(t *testing.T) {
+	db, err := Parse(strings.NewReader(testDB))
+	require.NoError(t, err)
+	assert.Equal(t, 4, db.Len())
+
+	loc := db.Locate("81.213.1.2")
+	require.NotNil(t, loc)
+	assert.Equal(t, "Istanbul, TR", loc.String())
+
+	loc = db.Locate("::ffff:8.8.8.8")
+	require.NotNil(t, loc, "IPv4-mapped addresses are looked up as IPv4")
+	assert.Equal(t, "Mountain View", loc.City)
+
+	loc = db.Locate("2001:db8::42")
+	require.NotNil(t, loc)
+	assert.Equal(t, "DE", loc.Country)
+
+	for _, ip := range []string{"8.8.9.1", "10.0.0.1", "127.0.0.1", "0.9.9.9", "not an ip"} {
+		assert.Nil(t, db.Locate(ip), ip)
+	}
+}
+
+func TestParse_RejectsOverlapsAndBadRows(t *testing.T) {
+	_, err := Parse(strings.NewReader("1.0.0.0,1.0.0.255,OC,AU,,,0,0\n1.0.0.128,1.0.1.0,OC,AU,,,0,0\n"))
+	assert.ErrorContains(t, err, "overlap")
+
+	_, err = Parse(strings.NewReader("1.0.0.9,1.0.0.1,OC,AU,,,0,0\n"))
+	assert.ErrorContains(t, err, "line 1")
+
+	_, err = Parse(strings.NewReader("1.0.0.0,1.0.0.255,OC,AU,,,95,0\n"))
+	assert.ErrorContains(t, err, "latitude")
+}
+
+func TestLoad_Gzipped(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(testDB))
+	require.NoError(t, gz.Close())
+	path := filepath.Join(t.TempDir(), "dbip-city-lite.csv.gz")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+	db, err := Load(path)
+	require.NoError(t, err)
+	assert.NotNil(t, db.Locate("1.0.0.1"))
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// AddAdminIPRuleRequest represents the request body adding a range to an admin's IP allowlist.
+type AddAdminIPRuleRequest struct {
+	CIDR string `json:"cidr"` // such as 203.0.113.0/24, or a single address
+	Note string `json:"note"`
+}
+
+// AdminIPAllowlistHandler handles the admin IP allowlist routes.
+type AdminIPAllowlistHandler struct {
+	service domain.AdminIPAllowlistService
+}
+
+// NewAdminIPAllowlistHandler creates a new AdminIPAllowlistHandler.
+func NewAdminIPAllowlistHandler(service domain.AdminIPAllowlistService) *AdminIPAllowlistHandler {
+	return &AdminIPAllowlistHandler{service: service}
+}
+
+// RegisterRoutes registers the allowlist routes; only admins may use them.
+func (h *AdminIPAllowlistHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/users/{userID}/ip-allowlist", func(r chi.Router) {
+		r.Use(middleware.RequireRoles("admin"))
+		r.Get("/", h.List)
+		r.Post("/", h.Add)
+		r.Delete("/{ruleID}", h.Remove)
+	})
+}
+
+// List handles GET /users/{userID}/ip-allowlist
+func (h *AdminIPAllowlistHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid userID")
+		return
+	}
+	rules, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list IP allowlist")
+		return
+	}
+	if rules == nil {
+		rules = []*domain.AdminIPRule{}
+	}
+	json.NewEncoder(w).Encode(rules)
+}
+
+// Add handles POST /users/{userID}/ip-allowlist.
+func (h *AdminIPAllowlistHandler) Add(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid userID")
+		return
+	}
+	var req AddAdminIPRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	rule := &domain.AdminIPRule{UserID: userID, CIDR: req.CIDR, Note: req.Note}
+	if err := h.service.Add(r.Context(), actorID, rule); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to add IP allowlist entry")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// Remove handles DELETE /users/{userID}/ip-allowlist/{ruleID}.
+func (h *AdminIPAllowlistHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid userID")
+		return
+	}
+	ruleID, err := strconv.Atoi(chi.URLParam(r, "ruleID"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid ruleID")
+		return
+	}
+
+	if err := h.service.Remove(r.Context(), actorID, userID, ruleID); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to remove IP allowlist entry")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondError is a helper method to respond with error
+func (h *AdminIPAllowlistHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// AdminOverviewHandler handles HTTP requests for the admin dashboard
+type AdminOverviewHandler struct {
+	overviewService domain.AdminOverviewService
+}
+
+// NewAdminOverviewHandler creates a new AdminOverviewHandler
+func NewAdminOverviewHandler(overviewService domain.AdminOverviewService) *AdminOverviewHandler {
+	return &AdminOverviewHandler{
+		overviewService: overviewService,
+	}
+}
+
+// RegisterRoutes registers the admin dashboard routes; all of them are admin-only
+func (h *AdminOverviewHandler) RegisterRoutes(r chi.Router) {
+	r.With(middleware.RequireRoles("admin")).Get("/admin/overview", h.GetOverview)
+}
+
+// GetOverview handles GET /admin/overview.
+func (h *AdminOverviewHandler) GetOverview(w http.ResponseWriter, r *http.Request) {
+	overview, err := h.overviewService.GetOverview(r.Context())
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to compute admin overview")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(overview)
+}
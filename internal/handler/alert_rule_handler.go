@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// AlertRuleHandler handles a user's balance and transaction alert rules
+type AlertRuleHandler struct {
+	service domain.AlertRuleService
+}
+
+// NewAlertRuleHandler creates a new AlertRuleHandler
+func NewAlertRuleHandler(service domain.AlertRuleService) *AlertRuleHandler {
+	return &AlertRuleHandler{service: service}
+}
+
+// RegisterRoutes registers the alert rule routes.
+func (h *AlertRuleHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/users/{userID}/alerts", func(r chi.Router) {
+		r.Get("/", h.ListRules)
+		r.Post("/", h.CreateRule)
+		r.Put("/{alertID}", h.UpdateRule)
+		r.Delete("/{alertID}", h.DeleteRule)
+	})
+}
+
+// createAlertRuleRequest is the body of a new alert rule
+type createAlertRuleRequest struct {
+	Type      string  `json:"type"`
+	Threshold float64 `json:"threshold"`
+}
+
+// updateAlertRuleRequest is the body of an alert rule update
+type updateAlertRuleRequest struct {
+	Threshold float64 `json:"threshold"`
+	Active    bool    `json:"active"`
+}
+
+// ListRules lists the user's alert rules
+func (h *AlertRuleHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+
+	rules, err := h.service.ListRules(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list alert rules")
+		return
+	}
+	if rules == nil {
+		rules = []*domain.AlertRule{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+// CreateRule adds an alert rule for the user
+func (h *AlertRuleHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req createAlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	rule := &domain.AlertRule{UserID: userID, Type: req.Type, Threshold: req.Threshold}
+	if err := h.service.CreateRule(r.Context(), rule); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to create alert rule")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// UpdateRule changes the threshold and active flag of an alert rule
+func (h *AlertRuleHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	alertID, err := strconv.Atoi(chi.URLParam(r, "alertID"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid alertID")
+		return
+	}
+
+	var req updateAlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	rule, err := h.service.UpdateRule(r.Context(), userID, alertID, req.Threshold, req.Active)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to update alert rule")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// DeleteRule deletes an alert rule
+func (h *AlertRuleHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	alertID, err := strconv.Atoi(chi.URLParam(r, "alertID"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid alertID")
+		return
+	}
+
+	if err := h.service.DeleteRule(r.Context(), userID, alertID); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to delete alert rule")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondError is a helper method to respond with error
+func (h *AlertRuleHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
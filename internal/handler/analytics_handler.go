@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// AnalyticsHandler handles HTTP requests for per-user analytics
+type AnalyticsHandler struct {
+	analyticsService domain.AnalyticsService
+}
+
+// NewAnalyticsHandler creates a new AnalyticsHandler
+func NewAnalyticsHandler(analyticsService domain.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetUserAnalytics handles GET /users/{id}/analytics.
+func (h *AnalyticsHandler) GetUserAnalytics(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	if !authorizeUser(w, r, userID, "you do not have permission to view this user's analytics") {
+		return
+	}
+
+	months := 6
+	if v := r.URL.Query().Get("months"); v != "" {
+		if months, err = strconv.Atoi(v); err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "months must be an integer")
+			return
+		}
+	}
+
+	analytics, err := h.analyticsService.GetUserAnalytics(r.Context(), userID, months)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get user analytics")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(analytics)
+}
+
+// respondError is a helper method to respond with error
+func (h *AnalyticsHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// ApprovalHandler handles HTTP requests for reviewing transactions held for approval
+type ApprovalHandler struct {
+	approvalService domain.ApprovalService
+}
+
+// NewApprovalHandler creates a new ApprovalHandler
+func NewApprovalHandler(approvalService domain.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{
+		approvalService: approvalService,
+	}
+}
+
+// RegisterRoutes registers the approval routes; all of them are admin-only
+func (h *ApprovalHandler) RegisterRoutes(r chi.Router) {
+	r.With(middleware.RequireRoles("admin")).Get("/transactions/approvals", h.ListPending)
+	r.With(middleware.RequireRoles("admin")).Post("/transactions/{id}/approve", h.Approve)
+	r.With(middleware.RequireRoles("admin")).Post("/transactions/{id}/reject", h.Reject)
+}
+
+// RejectTransactionRequest represents a request to reject a pending transaction
+type RejectTransactionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ListPending handles listing transactions awaiting approval
+func (h *ApprovalHandler) ListPending(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 200 {
+			h.respondError(w, r, http.StatusBadRequest, "limit must be between 1 and 200")
+			return
+		}
+		limit = n
+	}
+	offset := 0
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			h.respondError(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+		offset = n
+	}
+
+	approvals, err := h.approvalService.ListPending(r.Context(), limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list pending approvals")
+		return
+	}
+	if approvals == nil {
+		approvals = []*domain.Approval{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(approvals)
+}
+
+// Approve handles a second admin approving a pending transaction, which moves the money
+func (h *ApprovalHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	txID, reviewerID, ok := h.parseReview(w, r)
+	if !ok {
+		return
+	}
+
+	tx, err := h.approvalService.Approve(r.Context(), txID, reviewerID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to approve transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tx)
+}
+
+// Reject handles a second admin rejecting a pending transaction
+func (h *ApprovalHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	txID, reviewerID, ok := h.parseReview(w, r)
+	if !ok {
+		return
+	}
+
+	var req RejectTransactionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	tx, err := h.approvalService.Reject(r.Context(), txID, reviewerID, req.Reason)
+	if err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to reject transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tx)
+}
+
+// parseReview reads the transaction ID from the URL and the reviewer from the token
+func (h *ApprovalHandler) parseReview(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	reviewerID, ok := callerID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+
+	txID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid transaction ID")
+		return 0, 0, false
+	}
+	return txID, reviewerID, true
+}
+
+// respondError is a helper method to respond with error
+func (h *ApprovalHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// maxAuditExportRows caps the number of entries in one CSV export
+const maxAuditExportRows = 10000
+
+// AuditLogHandler handles HTTP requests for reviewing the audit log
+type AuditLogHandler struct {
+	auditService domain.AuditLogService
+}
+
+// NewAuditLogHandler creates a new AuditLogHandler
+func NewAuditLogHandler(auditService domain.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{
+		auditService: auditService,
+	}
+}
+
+// RegisterRoutes registers the audit log routes; all of them are admin-only
+func (h *AuditLogHandler) RegisterRoutes(r chi.Router) {
+	r.With(middleware.RequireRoles("admin")).Get("/admin/audit", h.Search)
+}
+
+// Search handles querying the audit log.
+func (h *AuditLogHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	exportCSV := q.Get("format") == "csv"
+	if f := q.Get("format"); f != "" && f != "csv" && f != "json" {
+		h.respondError(w, r, http.StatusBadRequest, "format must be json or csv")
+		return
+	}
+
+	filter := domain.AuditLogFilter{
+		EntityType: q.Get("entity_type"),
+		Action:     q.Get("action"),
+		Limit:      50,
+	}
+	maxLimit := 200
+	if exportCSV {
+		filter.Limit = maxAuditExportRows
+		maxLimit = maxAuditExportRows
+	}
+
+	if v := q.Get("actor_id"); v != "" {
+		actorID, err := strconv.Atoi(v)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid actor_id")
+			return
+		}
+		filter.ActorID = &actorID
+	}
+	if v := q.Get("from"); v != "" {
+		from, _, err := parseAuditTime(v)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid from, use RFC 3339 or YYYY-MM-DD")
+			return
+		}
+		filter.From = &from
+	}
+	if v := q.Get("to"); v != "" {
+		to, dateOnly, err := parseAuditTime(v)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid to, use RFC 3339 or YYYY-MM-DD")
+			return
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxLimit {
+			h.respondError(w, r, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxLimit))
+			return
+		}
+		filter.Limit = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			h.respondError(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+		filter.Offset = n
+	}
+
+	logs, err := h.auditService.Search(r.Context(), filter)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to search audit log")
+		return
+	}
+	if logs == nil {
+		logs = []*domain.AuditLog{}
+	}
+
+	if exportCSV {
+		h.writeCSV(w, r, logs)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(logs)
+}
+
+// writeCSV writes audit entries as a CSV attachment
+func (h *AuditLogHandler) writeCSV(w http.ResponseWriter, r *http.Request, logs []*domain.AuditLog) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-%s.csv"`, time.Now().UTC().Format("20060102-150405")))
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "created_at", "actor_id", "entity_type", "entity_id", "action", "details"})
+	for _, l := range logs {
+		actor := ""
+		if l.ActorID != nil {
+			actor = strconv.Itoa(*l.ActorID)
+		}
+		cw.Write([]string{
+			strconv.Itoa(l.ID),
+			l.CreatedAt.UTC().Format(time.RFC3339),
+			actor,
+			l.EntityType,
+			strconv.Itoa(l.EntityID),
+			l.Action,
+			l.Details,
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("Failed to write audit log CSV")
+	}
+}
+
+// parseAuditTime parses an RFC 3339 timestamp or a YYYY-MM-DD date.
+func parseAuditTime(v string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
+
+// respondError is a helper method to respond with error
+func (h *AuditLogHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// AuthBanHandler handles the routes listing and lifting brute-force bans.
+type AuthBanHandler struct {
+	service domain.AuthBanService
+}
+
+// NewAuthBanHandler creates a new AuthBanHandler.
+func NewAuthBanHandler(service domain.AuthBanService) *AuthBanHandler {
+	return &AuthBanHandler{service: service}
+}
+
+// RegisterRoutes registers the ban routes; all of them are admin-only
+func (h *AuthBanHandler) RegisterRoutes(r chi.Router) {
+	r.With(middleware.RequireRoles("admin")).Get("/admin/auth-bans", h.List)
+	r.With(middleware.RequireRoles("admin")).Delete("/admin/auth-bans/{kind}/{value}", h.Lift)
+}
+
+// List handles GET /admin/auth-bans.
+func (h *AuthBanHandler) List(w http.ResponseWriter, r *http.Request) {
+	bans, err := h.service.ListBans(r.Context())
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list bans")
+		return
+	}
+	if bans == nil {
+		bans = []*domain.AuthBan{}
+	}
+	json.NewEncoder(w).Encode(bans)
+}
+
+// Lift handles DELETE /admin/auth-bans/{kind}/{value}.
+func (h *AuthBanHandler) Lift(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	value, err := url.PathUnescape(chi.URLParam(r, "value"))
+	if err != nil || value == "" {
+		h.respondError(w, r, http.StatusBadRequest, "invalid value")
+		return
+	}
+
+	if err := h.service.LiftBan(r.Context(), actorID, chi.URLParam(r, "kind"), value); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to lift ban")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondError is a helper method to respond with error
+func (h *AuthBanHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -27,6 +27,55 @@ func (h *BalanceHandler) RegisterRoutes(r chi.Router) {
 	r.Get("/balances/current", h.GetCurrentBalance)
 	r.Get("/balances/historical", h.GetHistoricalBalance)
 	r.Get("/balances/at-time", h.GetBalanceAtTime)
+	r.Get("/balances/available", h.GetAvailableBalance)
+}
+
+// BalanceV2Response is a balance in API v2, with decimal string amounts
+type BalanceV2Response struct {
+	UserID           int       `json:"user_id"`
+	Balance          string    `json:"balance"`
+	AvailableBalance string    `json:"available_balance"`
+	LastUpdatedAt    time.Time `json:"last_updated_at"`
+}
+
+// balanceResponses renders a balance per API version; v1 returns it as is
+var balanceResponses = responseAdapters[*domain.Balance]{
+	middleware.APIVersion2: func(b *domain.Balance) interface{} {
+		return BalanceV2Response{
+			UserID:           b.UserID,
+			Balance:          formatDecimalAmount(b.GetAmount()),
+			AvailableBalance: formatDecimalAmount(b.AvailableAmount()),
+			LastUpdatedAt:    b.GetLastUpdatedAt(),
+		}
+	},
+}
+
+// AvailableBalanceV2Response is an AvailableBalanceResponse in API v2, with decimal string amounts
+type AvailableBalanceV2Response struct {
+	UserID           int    `json:"user_id"`
+	Balance          string `json:"balance"`
+	HeldAmount       string `json:"held_amount"`
+	AvailableBalance string `json:"available_balance"`
+}
+
+// availableBalanceResponses renders an available balance per API version
+var availableBalanceResponses = responseAdapters[AvailableBalanceResponse]{
+	middleware.APIVersion2: func(b AvailableBalanceResponse) interface{} {
+		return AvailableBalanceV2Response{
+			UserID:           b.UserID,
+			Balance:          formatDecimalAmount(b.Balance),
+			HeldAmount:       formatDecimalAmount(b.HeldAmount),
+			AvailableBalance: formatDecimalAmount(b.AvailableBalance),
+		}
+	},
+}
+
+// AvailableBalanceResponse splits a balance into the amount reserved by holds and the amount that can be spent
+type AvailableBalanceResponse struct {
+	UserID           int     `json:"user_id"`
+	Balance          float64 `json:"balance"`
+	HeldAmount       float64 `json:"held_amount"`
+	AvailableBalance float64 `json:"available_balance"`
 }
 
 func (h *BalanceHandler) GetCurrentBalance(w http.ResponseWriter, r *http.Request) {
@@ -36,19 +85,19 @@ func (h *BalanceHandler) GetCurrentBalance(w http.ResponseWriter, r *http.Reques
 	if err != nil {
 		fmt.Printf("DEBUG: authorizeAndGetTargetID error: %v\n", err)
 		if he, ok := err.(*handlerError); ok {
-			h.respondError(w, he.statusCode, he.message)
+			h.respondError(w, r, he.statusCode, he.message)
 		} else {
-			h.respondError(w, http.StatusInternalServerError, "an internal server error occurred")
+			h.respondError(w, r, http.StatusInternalServerError, "an internal server error occurred")
 		}
 		return
 	}
 
 	fmt.Printf("DEBUG: targetID: %d\n", targetID)
 
-	balance, err := h.service.GetCurrentBalance(targetID)
+	balance, err := h.service.GetCurrentBalance(r.Context(), targetID)
 	if err != nil {
 		fmt.Printf("DEBUG: GetCurrentBalance service error: %v\n", err)
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to get balance")
 		return
 	}
 
@@ -65,12 +114,7 @@ func (h *BalanceHandler) GetCurrentBalance(w http.ResponseWriter, r *http.Reques
 	}
 
 	fmt.Printf("DEBUG: about to encode balance: %+v\n", balance)
-	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(balance); err != nil {
-		fmt.Printf("DEBUG: JSON encode error: %v\n", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
+	balanceResponses.respond(w, r, http.StatusOK, balance)
 	fmt.Printf("DEBUG: GetCurrentBalance completed successfully\n")
 }
 
@@ -78,9 +122,9 @@ func (h *BalanceHandler) GetHistoricalBalance(w http.ResponseWriter, r *http.Req
 	targetID, err := authorizeAndGetTargetID(r)
 	if err != nil {
 		if he, ok := err.(*handlerError); ok {
-			h.respondError(w, he.statusCode, he.message)
+			h.respondError(w, r, he.statusCode, he.message)
 		} else {
-			h.respondError(w, http.StatusInternalServerError, "an internal server error occurred")
+			h.respondError(w, r, http.StatusInternalServerError, "an internal server error occurred")
 		}
 		return
 	}
@@ -93,12 +137,12 @@ func (h *BalanceHandler) GetHistoricalBalance(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	balances, err := h.service.GetHistoricalBalance(targetID, limit)
+	balances, err := h.service.GetHistoricalBalance(r.Context(), targetID, limit)
 	if err != nil {
 		if he, ok := err.(*handlerError); ok {
-			h.respondError(w, he.statusCode, he.message)
+			h.respondError(w, r, he.statusCode, he.message)
 		} else {
-			h.respondError(w, http.StatusInternalServerError, "an internal server error occurred")
+			h.respondError(w, r, http.StatusInternalServerError, "an internal server error occurred")
 		}
 		return
 	}
@@ -112,30 +156,30 @@ func (h *BalanceHandler) GetBalanceAtTime(w http.ResponseWriter, r *http.Request
 	targetID, err := authorizeAndGetTargetID(r)
 	if err != nil {
 		if he, ok := err.(*handlerError); ok {
-			h.respondError(w, he.statusCode, he.message)
+			h.respondError(w, r, he.statusCode, he.message)
 		} else {
-			h.respondError(w, http.StatusInternalServerError, "an internal server error occurred")
+			h.respondError(w, r, http.StatusInternalServerError, "an internal server error occurred")
 		}
 		return
 	}
 
 	timeStr := r.URL.Query().Get("time")
 	if timeStr == "" {
-		h.respondError(w, http.StatusBadRequest, "missing time parameter")
+		h.respondError(w, r, http.StatusBadRequest, "missing time parameter")
 		return
 	}
 	queryTime, err := time.Parse(time.RFC3339, timeStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid time format")
+		h.respondError(w, r, http.StatusBadRequest, "invalid time format")
 		return
 	}
 
-	balance, err := h.service.GetBalanceAtTime(targetID, queryTime)
+	balance, err := h.service.GetBalanceAtTime(r.Context(), targetID, queryTime)
 	if err != nil {
 		if he, ok := err.(*handlerError); ok {
-			h.respondError(w, he.statusCode, he.message)
+			h.respondError(w, r, he.statusCode, he.message)
 		} else {
-			h.respondError(w, http.StatusInternalServerError, "an internal server error occurred")
+			h.respondError(w, r, http.StatusInternalServerError, "an internal server error occurred")
 		}
 		return
 	}
@@ -153,9 +197,36 @@ func (h *BalanceHandler) GetBalanceAtTime(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(balance)
 }
 
-func (h *BalanceHandler) respondError(w http.ResponseWriter, code int, msg string) {
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+// GetAvailableBalance returns the actual balance next to the part of it that is not reserved by holds
+func (h *BalanceHandler) GetAvailableBalance(w http.ResponseWriter, r *http.Request) {
+	targetID, err := authorizeAndGetTargetID(r)
+	if err != nil {
+		if he, ok := err.(*handlerError); ok {
+			h.respondError(w, r, he.statusCode, he.message)
+		} else {
+			h.respondError(w, r, http.StatusInternalServerError, "an internal server error occurred")
+		}
+		return
+	}
+
+	balance, err := h.service.GetCurrentBalance(r.Context(), targetID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get balance")
+		return
+	}
+
+	resp := AvailableBalanceResponse{UserID: targetID}
+	if balance != nil {
+		resp.Balance = balance.GetAmount()
+		resp.AvailableBalance = balance.AvailableAmount()
+		resp.HeldAmount = resp.Balance - resp.AvailableBalance
+	}
+
+	availableBalanceResponses.respond(w, r, http.StatusOK, resp)
+}
+
+func (h *BalanceHandler) respondError(w http.ResponseWriter, r *http.Request, code int, msg string) {
+	middleware.WriteProblem(w, r, code, msg)
 }
 
 func authorizeAndGetTargetID(r *http.Request) (int, error) {
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// InitiateBankTransferRequest represents the request body for starting a bank transfer.
+type InitiateBankTransferRequest struct {
+	FundingSourceID int     `json:"funding_source_id"`
+	Direction       string  `json:"direction"`
+	Rail            string  `json:"rail"`
+	Amount          float64 `json:"amount"`
+}
+
+// ReturnBankTransferRequest represents the request body for returning a bank transfer.
+type ReturnBankTransferRequest struct {
+	Code string `json:"code"`
+}
+
+// BankTransferHandler handles ACH and SEPA bank transfers.
+type BankTransferHandler struct {
+	service domain.BankTransferService
+}
+
+// NewBankTransferHandler creates a new BankTransferHandler
+func NewBankTransferHandler(service domain.BankTransferService) *BankTransferHandler {
+	return &BankTransferHandler{service: service}
+}
+
+// RegisterRoutes registers the bank transfer routes.
+func (h *BankTransferHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/users/{userID}/bank-transfers", h.ListByUser)
+	r.Post("/users/{userID}/bank-transfers", h.Initiate)
+	r.Get("/users/{userID}/bank-transfers/{id}", h.Get)
+	r.Route("/admin/bank-transfers", func(r chi.Router) {
+		r.Use(middleware.RequireRoles("admin"))
+		r.Get("/", h.ListByStatus)
+		r.Post("/settle", h.SettleDue)
+		r.Post("/{id}/return", h.Return)
+	})
+}
+
+// ListByUser handles GET /users/{userID}/bank-transfers, newest first
+func (h *BankTransferHandler) ListByUser(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	limit, offset, err := parsePage(r.URL.Query(), 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	transfers, err := h.service.ListByUser(r.Context(), userID, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list bank transfers")
+		return
+	}
+	h.respondList(w, transfers)
+}
+
+// Initiate handles POST /users/{userID}/bank-transfers
+func (h *BankTransferHandler) Initiate(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	var req InitiateBankTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	transfer, err := h.service.Initiate(r.Context(), userID, &domain.BankTransfer{
+		FundingSourceID: req.FundingSourceID,
+		Direction:       req.Direction,
+		Rail:            req.Rail,
+		Amount:          req.Amount,
+	})
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to initiate bank transfer")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// Get handles GET /users/{userID}/bank-transfers/{id}
+func (h *BankTransferHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+	transfer, err := h.service.Get(r.Context(), userID, id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get bank transfer")
+		return
+	}
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// ListByStatus handles GET /admin/bank-transfers.
+func (h *BankTransferHandler) ListByStatus(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, offset, err := parsePage(q, 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	status := q.Get("status")
+	if status == "" {
+		status = domain.BankTransferPending
+	}
+	transfers, err := h.service.ListByStatus(r.Context(), status, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list bank transfers")
+		return
+	}
+	h.respondList(w, transfers)
+}
+
+// Return handles POST /admin/bank-transfers/{id}/return.
+func (h *BankTransferHandler) Return(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+	var req ReturnBankTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	transfer, err := h.service.Return(r.Context(), id, &actorID, req.Code)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to return bank transfer")
+		return
+	}
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// SettleDue handles POST /admin/bank-transfers/settle.
+func (h *BankTransferHandler) SettleDue(w http.ResponseWriter, r *http.Request) {
+	run, err := h.service.SettleDue(r.Context())
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to settle bank transfers")
+		return
+	}
+	json.NewEncoder(w).Encode(run)
+}
+
+// pathID parses the bank transfer ID of the route
+func (h *BankTransferHandler) pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid bank transfer ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// respondList encodes a page of transfers, empty rather than null
+func (h *BankTransferHandler) respondList(w http.ResponseWriter, transfers []*domain.BankTransfer) {
+	if transfers == nil {
+		transfers = []*domain.BankTransfer{}
+	}
+	json.NewEncoder(w).Encode(transfers)
+}
+
+// respondError is a helper method to respond with error
+func (h *BankTransferHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
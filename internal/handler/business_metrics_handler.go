@@ -38,7 +38,7 @@ func (h *BusinessMetricsHandler) GetMetricsSummary(w http.ResponseWriter, r *htt
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(summary); err != nil {
-		log.Error().Err(err).Msg("Failed to encode metrics summary")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to encode metrics summary")
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -54,16 +54,19 @@ func (h *BusinessMetricsHandler) GetKeyPerformanceIndicators(w http.ResponseWrit
 	// Calculate KPIs
 	kpis := map[string]interface{}{
 		"user_metrics": map[string]interface{}{
-			"active_users":         summary["active_users"],
-			"daily_active_users":   summary["daily_active_users"],
-			"monthly_active_users": summary["monthly_active_users"],
+			"total_users":          summary.TotalUsers,
+			"active_users":         summary.ActiveUsers,
+			"daily_active_users":   summary.DailyActiveUsers,
+			"monthly_active_users": summary.MonthlyActiveUsers,
 		},
 		"financial_metrics": map[string]interface{}{
-			"total_balance":   summary["balance_total"],
-			"cache_hit_ratio": summary["cache_hit_ratio"],
+			"total_balance":             summary.BalanceTotal,
+			"transaction_success_rates": summary.TransactionSuccessRates,
+			"cache_hit_ratio":           summary.CacheHitRatio,
 		},
 		"system_health": map[string]interface{}{
-			"last_update": summary["last_update"],
+			"last_update":      summary.LastUpdate,
+			"database_healthy": summary.DatabaseHealthy,
 		},
 	}
 
@@ -71,7 +74,7 @@ func (h *BusinessMetricsHandler) GetKeyPerformanceIndicators(w http.ResponseWrit
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(kpis); err != nil {
-		log.Error().Err(err).Msg("Failed to encode KPIs")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to encode KPIs")
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// callerID returns the ID of the user making the request.
+func callerID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	claims, ok := middleware.UserClaimsFromContext(r.Context())
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusUnauthorized, "invalid token claims")
+		return 0, false
+	}
+	id, err := strconv.Atoi(claims.UserID)
+	if err != nil {
+		middleware.WriteProblem(w, r, http.StatusUnauthorized, "invalid user_id in token")
+		return 0, false
+	}
+	return id, true
+}
+
+// authorizedUser returns the route's user ID and the caller's ID if they may act on it.
+func authorizedUser(w http.ResponseWriter, r *http.Request) (userID, actorID int, ok bool) {
+	actorID, ok = callerID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "invalid userID")
+		return 0, 0, false
+	}
+	if !authorizeUser(w, r, userID, "you do not have permission to access this user's resources") {
+		return 0, 0, false
+	}
+	return userID, actorID, true
+}
+
+// authorizeUser checks that the caller is userID or an admin, responding with forbidden if not
+func authorizeUser(w http.ResponseWriter, r *http.Request, userID int, forbidden string) bool {
+	claims, ok := middleware.UserClaimsFromContext(r.Context())
+	if !ok {
+		middleware.WriteProblem(w, r, http.StatusUnauthorized, "invalid token claims")
+		return false
+	}
+	if !middleware.IsAdminOrSelf(claims, userID) {
+		middleware.WriteProblem(w, r, http.StatusForbidden, forbidden)
+		return false
+	}
+	return true
+}
+
+// requestedUser returns userID, or the caller's ID if it is zero, if they may act on it.
+func requestedUser(w http.ResponseWriter, r *http.Request, userID int, forbidden string) (int, bool) {
+	if userID == 0 {
+		return callerID(w, r)
+	}
+	return userID, authorizeUser(w, r, userID, forbidden)
+}
+
+// isAdmin reports whether the caller is an admin
+func isAdmin(r *http.Request) bool {
+	claims, ok := middleware.UserClaimsFromContext(r.Context())
+	return ok && claims.Role == "admin"
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// CreateCampaignRequest represents the request body for creating a campaign
+type CreateCampaignRequest struct {
+	Name      string     `json:"name"`
+	Condition string     `json:"condition"`
+	Threshold float64    `json:"threshold"`
+	Bonus     float64    `json:"bonus"`
+	Budget    float64    `json:"budget"`
+	StartsAt  *time.Time `json:"starts_at"` // defaults to now
+	EndsAt    time.Time  `json:"ends_at"`
+}
+
+// UpdateCampaignRequest represents the request body for changing a campaign.
+type UpdateCampaignRequest struct {
+	Name   string     `json:"name"`
+	Budget float64    `json:"budget"`
+	EndsAt *time.Time `json:"ends_at"`
+}
+
+// CampaignHandler handles the campaign routes.
+type CampaignHandler struct {
+	service domain.CampaignService
+}
+
+// NewCampaignHandler creates a new CampaignHandler.
+func NewCampaignHandler(service domain.CampaignService) *CampaignHandler {
+	return &CampaignHandler{service: service}
+}
+
+// RegisterRoutes registers the campaign routes; only admins may use them.
+func (h *CampaignHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/campaigns", func(r chi.Router) {
+		r.Use(middleware.RequireRoles("admin"))
+		r.Get("/", h.List)
+		r.Post("/", h.Create)
+		r.Get("/{id}", h.Get)
+		r.Put("/{id}", h.Update)
+		r.Get("/{id}/stats", h.Stats)
+	})
+}
+
+// List handles GET /admin/campaigns
+func (h *CampaignHandler) List(w http.ResponseWriter, r *http.Request) {
+	campaigns, err := h.service.List(r.Context())
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list campaigns")
+		return
+	}
+	if campaigns == nil {
+		campaigns = []*domain.Campaign{}
+	}
+	json.NewEncoder(w).Encode(campaigns)
+}
+
+// Get handles GET /admin/campaigns/{id}
+func (h *CampaignHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.campaignID(w, r)
+	if !ok {
+		return
+	}
+	campaign, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get campaign")
+		return
+	}
+	json.NewEncoder(w).Encode(campaign)
+}
+
+// Create handles POST /admin/campaigns
+func (h *CampaignHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	var req CreateCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	campaign := &domain.Campaign{
+		Name:      req.Name,
+		Condition: req.Condition,
+		Threshold: req.Threshold,
+		Bonus:     req.Bonus,
+		Budget:    req.Budget,
+		EndsAt:    req.EndsAt,
+	}
+	if req.StartsAt != nil {
+		campaign.StartsAt = *req.StartsAt
+	}
+	if err := h.service.Create(r.Context(), actorID, campaign); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to create campaign")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(campaign)
+}
+
+// Update handles PUT /admin/campaigns/{id}.
+func (h *CampaignHandler) Update(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.campaignID(w, r)
+	if !ok {
+		return
+	}
+	var req UpdateCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	campaign := &domain.Campaign{ID: id, Name: req.Name, Budget: req.Budget}
+	if req.EndsAt != nil {
+		campaign.EndsAt = *req.EndsAt
+	}
+	if err := h.service.Update(r.Context(), actorID, campaign); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to update campaign")
+		return
+	}
+	json.NewEncoder(w).Encode(campaign)
+}
+
+// Stats handles GET /admin/campaigns/{id}/stats
+func (h *CampaignHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.campaignID(w, r)
+	if !ok {
+		return
+	}
+	stats, err := h.service.Stats(r.Context(), id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get campaign stats")
+		return
+	}
+	json.NewEncoder(w).Encode(stats)
+}
+
+// campaignID parses the campaign ID in the path
+func (h *CampaignHandler) campaignID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid campaign id")
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError is a helper method to respond with error
+func (h *CampaignHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
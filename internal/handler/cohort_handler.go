@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// CohortHandler handles HTTP requests for cohort retention and growth analytics
+type CohortHandler struct {
+	cohortService domain.CohortService
+}
+
+// NewCohortHandler creates a new CohortHandler
+func NewCohortHandler(cohortService domain.CohortService) *CohortHandler {
+	return &CohortHandler{
+		cohortService: cohortService,
+	}
+}
+
+// RegisterRoutes registers the cohort routes alongside the business metrics routes
+func (h *CohortHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/cohorts", h.GetCohorts)
+}
+
+// GetCohorts returns signup cohort retention and monthly growth as of the last refresh.
+func (h *CohortHandler) GetCohorts(w http.ResponseWriter, r *http.Request) {
+	months := 12
+	if v := r.URL.Query().Get("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "months must be an integer")
+			return
+		}
+		months = n
+	}
+
+	report, err := h.cohortService.GetReport(r.Context(), months)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get cohort report")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// respondError is a helper method to respond with error
+func (h *CohortHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// DisputeDecisionRequest represents the body of resolving or refunding a dispute.
+type DisputeDecisionRequest struct {
+	Resolution string `json:"resolution"`
+}
+
+// DisputeHandler handles disputes of transactions: users open them and admins triage them.
+type DisputeHandler struct {
+	service domain.DisputeService
+}
+
+// NewDisputeHandler creates a new DisputeHandler
+func NewDisputeHandler(service domain.DisputeService) *DisputeHandler {
+	return &DisputeHandler{service: service}
+}
+
+// RegisterRoutes registers the dispute routes.
+func (h *DisputeHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/transactions/{id}/disputes", h.Open)
+	r.Get("/disputes", h.ListMine)
+	r.Get("/disputes/{id}", h.Get)
+	r.Route("/admin/disputes", func(r chi.Router) {
+		r.Use(middleware.RequireRoles("admin"))
+		r.Get("/", h.ListByStatus)
+		r.Post("/{id}/investigate", h.Investigate)
+		r.Post("/{id}/resolve", h.Resolve)
+		r.Post("/{id}/refund", h.Refund)
+	})
+}
+
+// Open handles POST /transactions/{id}/disputes
+func (h *DisputeHandler) Open(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.parseRequest(w, r, "invalid transaction ID")
+	if !ok {
+		return
+	}
+	var claim domain.DisputeClaim
+	if err := json.NewDecoder(r.Body).Decode(&claim); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	dispute, err := h.service.Open(r.Context(), userID, id, &claim)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to open dispute")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dispute)
+}
+
+// ListMine handles GET /disputes, the caller's disputes, newest first
+func (h *DisputeHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	limit, offset, err := parsePage(r.URL.Query(), 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	disputes, err := h.service.ListByUser(r.Context(), userID, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list disputes")
+		return
+	}
+	h.respondList(w, disputes)
+}
+
+// Get handles GET /disputes/{id}; users see their own disputes, admins any
+func (h *DisputeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.parseRequest(w, r, "invalid dispute ID")
+	if !ok {
+		return
+	}
+	dispute, err := h.service.Get(r.Context(), id, userID, isAdmin(r))
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get dispute")
+		return
+	}
+	json.NewEncoder(w).Encode(dispute)
+}
+
+// ListByStatus handles GET /admin/disputes.
+func (h *DisputeHandler) ListByStatus(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, offset, err := parsePage(q, 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	status := q.Get("status")
+	if status == "" {
+		status = domain.DisputeOpen
+	}
+	disputes, err := h.service.ListByStatus(r.Context(), status, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list disputes")
+		return
+	}
+	h.respondList(w, disputes)
+}
+
+// Investigate handles POST /admin/disputes/{id}/investigate
+func (h *DisputeHandler) Investigate(w http.ResponseWriter, r *http.Request) {
+	actorID, id, ok := h.parseRequest(w, r, "invalid dispute ID")
+	if !ok {
+		return
+	}
+	dispute, err := h.service.Investigate(r.Context(), id, actorID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to investigate dispute")
+		return
+	}
+	json.NewEncoder(w).Encode(dispute)
+}
+
+// Resolve handles POST /admin/disputes/{id}/resolve, closing the dispute without a refund
+func (h *DisputeHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.Resolve, "failed to resolve dispute")
+}
+
+// Refund handles POST /admin/disputes/{id}/refund, closing the dispute by reversing the payment
+func (h *DisputeHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.Refund, "failed to refund dispute")
+}
+
+// decide closes the dispute of the route with close
+func (h *DisputeHandler) decide(w http.ResponseWriter, r *http.Request, close func(ctx context.Context, id, actorID int, resolution string) (*domain.Dispute, error), failure string) {
+	actorID, id, ok := h.parseRequest(w, r, "invalid dispute ID")
+	if !ok {
+		return
+	}
+	var req DisputeDecisionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	dispute, err := close(r.Context(), id, actorID, req.Resolution)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, failure)
+		return
+	}
+	json.NewEncoder(w).Encode(dispute)
+}
+
+// respondList writes a page of disputes, an empty list rather than null
+func (h *DisputeHandler) respondList(w http.ResponseWriter, disputes []*domain.Dispute) {
+	if disputes == nil {
+		disputes = []*domain.Dispute{}
+	}
+	json.NewEncoder(w).Encode(disputes)
+}
+
+// parseRequest reads the caller from the token and the ID in the path.
+func (h *DisputeHandler) parseRequest(w http.ResponseWriter, r *http.Request, invalidID string) (int, int, bool) {
+	callerID, ok := callerID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, invalidID)
+		return 0, 0, false
+	}
+	return callerID, id, true
+}
+
+// respondError is a helper method to respond with error
+func (h *DisputeHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
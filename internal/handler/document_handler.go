@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// DocumentResponse is a document with a signed URL to download it
+type DocumentResponse struct {
+	*domain.Document
+	DownloadURL       string    `json:"download_url"`
+	DownloadExpiresAt time.Time `json:"download_expires_at"`
+}
+
+// DocumentHandler handles the documents users upload
+type DocumentHandler struct {
+	service  domain.DocumentService
+	maxBytes int
+}
+
+// NewDocumentHandler creates a new DocumentHandler accepting files of up to maxBytes
+func NewDocumentHandler(service domain.DocumentService, maxBytes int) *DocumentHandler {
+	return &DocumentHandler{service: service, maxBytes: maxBytes}
+}
+
+// RegisterRoutes registers the authenticated document routes.
+func (h *DocumentHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/users/{userID}/documents", h.Upload)
+	r.Get("/users/{userID}/documents", h.List)
+	r.Get("/users/{userID}/documents/{id}", h.Get)
+	r.Delete("/users/{userID}/documents/{id}", h.Delete)
+}
+
+// Upload handles POST /users/{userID}/documents.
+func (h *DocumentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userID, actorID, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart framing and the kind field around the file
+	r.Body = http.MaxBytesReader(w, r.Body, int64(h.maxBytes)+64<<10)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "request must be multipart/form-data")
+		return
+	}
+	upload := &domain.DocumentUpload{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			h.respondError(w, r, http.StatusRequestEntityTooLarge, "file is too large")
+			return
+		}
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid multipart body")
+			return
+		}
+		switch part.FormName() {
+		case "kind":
+			kind, err := io.ReadAll(io.LimitReader(part, 64))
+			if err != nil {
+				h.respondError(w, r, http.StatusBadRequest, "invalid multipart body")
+				return
+			}
+			upload.Kind = string(kind)
+		case "file":
+			upload.Filename = part.FileName()
+			if upload.Data, err = io.ReadAll(io.LimitReader(part, int64(h.maxBytes)+1)); err != nil {
+				h.respondError(w, r, http.StatusRequestEntityTooLarge, "file is too large")
+				return
+			}
+		}
+	}
+	if upload.Data == nil {
+		h.respondError(w, r, http.StatusBadRequest, "missing file field")
+		return
+	}
+
+	document, err := h.service.Upload(r.Context(), userID, actorID, upload)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to upload document")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(h.response(r, document))
+}
+
+// List handles GET /users/{userID}/documents, newest first.
+func (h *DocumentHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	limit, offset, err := parsePage(r.URL.Query(), 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	documents, err := h.service.List(r.Context(), userID, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list documents")
+		return
+	}
+	resp := make([]DocumentResponse, len(documents))
+	for i, document := range documents {
+		resp[i] = h.response(r, document)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Get handles GET /users/{userID}/documents/{id}
+func (h *DocumentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.documentID(w, r)
+	if !ok {
+		return
+	}
+	document, err := h.service.Get(r.Context(), userID, id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get document")
+		return
+	}
+	json.NewEncoder(w).Encode(h.response(r, document))
+}
+
+// Delete handles DELETE /users/{userID}/documents/{id}
+func (h *DocumentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.documentID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(r.Context(), userID, id); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to delete document")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Download handles GET /documents/download/{token}, answering with the file itself.
+func (h *DocumentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	document, data, err := h.service.Download(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to download document")
+		return
+	}
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Write(data)
+}
+
+// response pairs a document with a download URL under the API version of r
+func (h *DocumentHandler) response(r *http.Request, document *domain.Document) DocumentResponse {
+	link := h.service.Link(document)
+	return DocumentResponse{
+		Document:          document,
+		DownloadURL:       "/api/" + middleware.APIVersionFromContext(r.Context()).String() + "/documents/download/" + link.Token,
+		DownloadExpiresAt: link.ExpiresAt,
+	}
+}
+
+// documentID parses the document ID in the path
+func (h *DocumentHandler) documentID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid document id")
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError is a helper method to respond with error
+func (h *DocumentHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// stateConflict turns a validation error from a state change into a conflict.
+func stateConflict(err error) error {
+	var valErr *domain.ValidationError
+	if errors.As(err, &valErr) {
+		return domain.NewError(domain.ErrorKindConflict, "invalid_state", valErr.Msg)
+	}
+	return err
+}
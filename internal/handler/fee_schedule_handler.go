@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// FeeScheduleRequest represents the request body creating or changing a fee schedule version.
+type FeeScheduleRequest struct {
+	TransactionType string           `json:"transaction_type"`
+	Role            string           `json:"role"`
+	EffectiveFrom   *time.Time       `json:"effective_from"` // defaults to now, or the current time of the version
+	Tiers           []domain.FeeTier `json:"tiers"`
+}
+
+// FeeScheduleHandler handles the fee schedule routes.
+type FeeScheduleHandler struct {
+	service domain.FeeScheduleService
+}
+
+// NewFeeScheduleHandler creates a new FeeScheduleHandler.
+func NewFeeScheduleHandler(service domain.FeeScheduleService) *FeeScheduleHandler {
+	return &FeeScheduleHandler{service: service}
+}
+
+// RegisterRoutes registers the fee schedule routes; only admins may use them.
+func (h *FeeScheduleHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/fee-schedules", func(r chi.Router) {
+		r.Use(middleware.RequireRoles("admin"))
+		r.Get("/", h.List)
+		r.Post("/", h.Create)
+		r.Get("/{id}", h.Get)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+	})
+}
+
+// List handles GET /admin/fee-schedules, optionally of one ?type
+func (h *FeeScheduleHandler) List(w http.ResponseWriter, r *http.Request) {
+	schedules, err := h.service.List(r.Context(), r.URL.Query().Get("type"))
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list fee schedules")
+		return
+	}
+	if schedules == nil {
+		schedules = []*domain.FeeSchedule{}
+	}
+	json.NewEncoder(w).Encode(schedules)
+}
+
+// Get handles GET /admin/fee-schedules/{id}
+func (h *FeeScheduleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid fee schedule id")
+		return
+	}
+	schedule, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get fee schedule")
+		return
+	}
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// Create handles POST /admin/fee-schedules, adding a version that takes effect at effective_from
+func (h *FeeScheduleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	var req FeeScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	schedule := &domain.FeeSchedule{TransactionType: req.TransactionType, Role: req.Role, Tiers: req.Tiers}
+	if req.EffectiveFrom != nil {
+		schedule.EffectiveFrom = *req.EffectiveFrom
+	}
+	if err := h.service.Create(r.Context(), actorID, schedule); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to create fee schedule")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// Update handles PUT /admin/fee-schedules/{id}.
+func (h *FeeScheduleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid fee schedule id")
+		return
+	}
+	var req FeeScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	schedule := &domain.FeeSchedule{ID: id, Tiers: req.Tiers}
+	if req.EffectiveFrom != nil {
+		schedule.EffectiveFrom = *req.EffectiveFrom
+	}
+	if err := h.service.Update(r.Context(), actorID, schedule); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to update fee schedule")
+		return
+	}
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// Delete handles DELETE /admin/fee-schedules/{id}.
+func (h *FeeScheduleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid fee schedule id")
+		return
+	}
+	if err := h.service.Delete(r.Context(), actorID, id); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to delete fee schedule")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondError is a helper method to respond with error
+func (h *FeeScheduleHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// FraudHandler handles HTTP requests for the fraud review queue
+type FraudHandler struct {
+	fraudService domain.FraudService
+}
+
+// NewFraudHandler creates a new FraudHandler
+func NewFraudHandler(fraudService domain.FraudService) *FraudHandler {
+	return &FraudHandler{
+		fraudService: fraudService,
+	}
+}
+
+// RegisterRoutes registers the fraud review routes; all of them are admin-only
+func (h *FraudHandler) RegisterRoutes(r chi.Router) {
+	r.With(middleware.RequireRoles("admin")).Get("/admin/fraud/reviews", h.ListReviewQueue)
+	r.With(middleware.RequireRoles("admin")).Post("/admin/fraud/reviews/{id}/resolve", h.Resolve)
+}
+
+// ResolveFraudReviewRequest represents an admin's verdict on a queued assessment
+type ResolveFraudReviewRequest struct {
+	Outcome string `json:"outcome"` // "confirmed" or "cleared"
+	Note    string `json:"note"`
+}
+
+// ListReviewQueue handles listing flagged and held transactions awaiting review
+func (h *FraudHandler) ListReviewQueue(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 200 {
+			h.respondError(w, r, http.StatusBadRequest, "limit must be between 1 and 200")
+			return
+		}
+		limit = n
+	}
+	offset := 0
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			h.respondError(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+		offset = n
+	}
+
+	assessments, err := h.fraudService.ListReviewQueue(r.Context(), limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list fraud review queue")
+		return
+	}
+	if assessments == nil {
+		assessments = []*domain.FraudAssessment{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assessments)
+}
+
+// Resolve handles an admin confirming or clearing a queued assessment
+func (h *FraudHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	reviewerID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid assessment ID")
+		return
+	}
+
+	var req ResolveFraudReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Outcome != "confirmed" && req.Outcome != "cleared" {
+		h.respondError(w, r, http.StatusBadRequest, `outcome must be "confirmed" or "cleared"`)
+		return
+	}
+
+	assessment, err := h.fraudService.Resolve(r.Context(), id, reviewerID, req.Outcome == "confirmed", req.Note)
+	if err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to resolve fraud review")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assessment)
+}
+
+// respondError is a helper method to respond with error
+func (h *FraudHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// AddFundingSourceRequest represents the request body for adding a funding source
+type AddFundingSourceRequest struct {
+	Kind        string `json:"kind"`
+	Provider    string `json:"provider"`
+	ExternalRef string `json:"external_ref"`
+	Last4       string `json:"last4"`
+}
+
+// FundCreditRequest represents the request body for crediting a payment from a funding source
+type FundCreditRequest struct {
+	Amount     float64 `json:"amount"`
+	ExternalID string  `json:"external_id"`
+}
+
+// ChargebackNoteRequest represents the optional body of moving a chargeback
+type ChargebackNoteRequest struct {
+	Note string `json:"note"`
+}
+
+// FundingHandler handles funding sources and the chargebacks of the credits they fund.
+type FundingHandler struct {
+	service domain.FundingService
+}
+
+// NewFundingHandler creates a new FundingHandler
+func NewFundingHandler(service domain.FundingService) *FundingHandler {
+	return &FundingHandler{service: service}
+}
+
+// RegisterRoutes registers the funding routes.
+func (h *FundingHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/users/{userID}/funding-sources", h.ListSources)
+	r.Post("/users/{userID}/funding-sources", h.AddSource)
+	r.Delete("/users/{userID}/funding-sources/{id}", h.RemoveSource)
+	r.With(middleware.RequireRoles("admin")).Post("/users/{userID}/funding-sources/{id}/credits", h.Fund)
+	r.Get("/users/{userID}/chargebacks", h.ListUserChargebacks)
+	r.With(middleware.RequireRoles("admin")).Post("/admin/users/{userID}/unfreeze", h.Unfreeze)
+	r.Route("/admin/chargebacks", func(r chi.Router) {
+		r.Use(middleware.RequireRoles("admin"))
+		r.Post("/", h.ReceiveChargeback)
+		r.Get("/", h.ListChargebacks)
+		r.Get("/{id}", h.GetChargeback)
+		r.Post("/{id}/dispute", h.DisputeChargeback)
+		r.Post("/{id}/win", h.WinChargeback)
+		r.Post("/{id}/lose", h.LoseChargeback)
+	})
+}
+
+// ListSources handles GET /users/{userID}/funding-sources
+func (h *FundingHandler) ListSources(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	sources, err := h.service.ListSources(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list funding sources")
+		return
+	}
+	if sources == nil {
+		sources = []*domain.FundingSource{}
+	}
+	json.NewEncoder(w).Encode(sources)
+}
+
+// AddSource handles POST /users/{userID}/funding-sources
+func (h *FundingHandler) AddSource(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	var req AddFundingSourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	source, err := h.service.AddSource(r.Context(), userID, &domain.FundingSource{
+		Kind:        req.Kind,
+		Provider:    req.Provider,
+		ExternalRef: req.ExternalRef,
+		Last4:       req.Last4,
+	})
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to add funding source")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(source)
+}
+
+// RemoveSource handles DELETE /users/{userID}/funding-sources/{id}
+func (h *FundingHandler) RemoveSource(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.pathID(w, r, "invalid funding source ID")
+	if !ok {
+		return
+	}
+	if err := h.service.RemoveSource(r.Context(), userID, id); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to remove funding source")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Fund handles POST /users/{userID}/funding-sources/{id}/credits.
+func (h *FundingHandler) Fund(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.pathID(w, r, "invalid funding source ID")
+	if !ok {
+		return
+	}
+	var req FundCreditRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	tx, err := h.service.Fund(r.Context(), userID, id, req.Amount, req.ExternalID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to fund credit")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tx)
+}
+
+// ListUserChargebacks handles GET /users/{userID}/chargebacks, newest first
+func (h *FundingHandler) ListUserChargebacks(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	limit, offset, err := parsePage(r.URL.Query(), 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	chargebacks, err := h.service.ListChargebacksByUser(r.Context(), userID, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list chargebacks")
+		return
+	}
+	h.respondList(w, chargebacks)
+}
+
+// Unfreeze handles POST /admin/users/{userID}/unfreeze
+func (h *FundingHandler) Unfreeze(w http.ResponseWriter, r *http.Request) {
+	userID, actorID, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.Unfreeze(r.Context(), userID, actorID); err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to unfreeze account")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReceiveChargeback handles POST /admin/chargebacks.
+func (h *FundingHandler) ReceiveChargeback(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	var notice domain.ChargebackNotice
+	if err := json.NewDecoder(r.Body).Decode(&notice); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	chargeback, err := h.service.ReceiveChargeback(r.Context(), &actorID, &notice)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to record chargeback")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(chargeback)
+}
+
+// ListChargebacks handles GET /admin/chargebacks.
+func (h *FundingHandler) ListChargebacks(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, offset, err := parsePage(q, 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	status := q.Get("status")
+	if status == "" {
+		status = domain.ChargebackReceived
+	}
+	chargebacks, err := h.service.ListChargebacksByStatus(r.Context(), status, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list chargebacks")
+		return
+	}
+	h.respondList(w, chargebacks)
+}
+
+// GetChargeback handles GET /admin/chargebacks/{id}.
+func (h *FundingHandler) GetChargeback(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r, "invalid chargeback ID")
+	if !ok {
+		return
+	}
+	chargeback, err := h.service.GetChargeback(r.Context(), id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get chargeback")
+		return
+	}
+	json.NewEncoder(w).Encode(chargeback)
+}
+
+// DisputeChargeback handles POST /admin/chargebacks/{id}/dispute
+func (h *FundingHandler) DisputeChargeback(w http.ResponseWriter, r *http.Request) {
+	h.move(w, r, h.service.DisputeChargeback, "failed to dispute chargeback")
+}
+
+// WinChargeback handles POST /admin/chargebacks/{id}/win
+func (h *FundingHandler) WinChargeback(w http.ResponseWriter, r *http.Request) {
+	h.move(w, r, h.service.WinChargeback, "failed to win chargeback")
+}
+
+// LoseChargeback handles POST /admin/chargebacks/{id}/lose
+func (h *FundingHandler) LoseChargeback(w http.ResponseWriter, r *http.Request) {
+	h.move(w, r, h.service.LoseChargeback, "failed to lose chargeback")
+}
+
+// move moves the chargeback of the route with change
+func (h *FundingHandler) move(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, id int, actorID *int, note string) (*domain.Chargeback, error), failure string) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.pathID(w, r, "invalid chargeback ID")
+	if !ok {
+		return
+	}
+	var req ChargebackNoteRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	chargeback, err := change(r.Context(), id, &actorID, req.Note)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, failure)
+		return
+	}
+	json.NewEncoder(w).Encode(chargeback)
+}
+
+// respondList writes a page of chargebacks, an empty list rather than null
+func (h *FundingHandler) respondList(w http.ResponseWriter, chargebacks []*domain.Chargeback) {
+	if chargebacks == nil {
+		chargebacks = []*domain.Chargeback{}
+	}
+	json.NewEncoder(w).Encode(chargebacks)
+}
+
+// pathID parses the ID in the path
+func (h *FundingHandler) pathID(w http.ResponseWriter, r *http.Request, invalid string) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, invalid)
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError is a helper method to respond with error
+func (h *FundingHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
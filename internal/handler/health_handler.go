@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// HealthHandler serves the liveness and readiness probes
+type HealthHandler struct {
+	healthService domain.HealthService
+}
+
+// NewHealthHandler creates a new HealthHandler
+func NewHealthHandler(healthService domain.HealthService) *HealthHandler {
+	return &HealthHandler{healthService: healthService}
+}
+
+// RegisterRoutes registers the probe endpoints, outside the versioned API
+func (h *HealthHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/healthz", h.Liveness)
+	r.Get("/readyz", h.Readiness)
+}
+
+// Liveness handles GET /healthz: 200 while the process works, 503 when it should be restarted
+func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	h.respondReport(w, h.healthService.Liveness(r.Context()))
+}
+
+// Readiness handles GET /readyz.
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	h.respondReport(w, h.healthService.Readiness(r.Context()))
+}
+
+func (h *HealthHandler) respondReport(w http.ResponseWriter, report *domain.HealthReport) {
+	status := http.StatusOK
+	if !report.Up() {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// HoldHandler handles HTTP requests for authorization holds
+type HoldHandler struct {
+	holdService domain.HoldService
+}
+
+// NewHoldHandler creates a new HoldHandler
+func NewHoldHandler(holdService domain.HoldService) *HoldHandler {
+	return &HoldHandler{
+		holdService: holdService,
+	}
+}
+
+// RegisterRoutes registers the hold routes
+func (h *HoldHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/transactions/hold", h.PlaceHold)
+	r.Get("/transactions/holds", h.ListActiveHolds)
+	r.Get("/transactions/holds/{id}", h.GetHold)
+	r.Post("/transactions/holds/{id}/capture", h.CaptureHold)
+	r.Post("/transactions/holds/{id}/release", h.ReleaseHold)
+}
+
+// PlaceHoldRequest represents a request to reserve part of a balance
+type PlaceHoldRequest struct {
+	UserID int     `json:"user_id"`
+	Amount float64 `json:"amount"`
+}
+
+// PlaceHold handles reserving an amount from a user's available balance
+func (h *HoldHandler) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	var req PlaceHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// Default to the caller's own account
+	userID, ok := requestedUser(w, r, req.UserID, "you can only place holds on your own account")
+	if !ok {
+		return
+	}
+	req.UserID = userID
+
+	hold, err := h.holdService.PlaceHold(r.Context(), req.UserID, req.Amount)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to place hold")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hold)
+}
+
+// ListActiveHolds handles listing the unresolved holds on a user's balance
+func (h *HoldHandler) ListActiveHolds(w http.ResponseWriter, r *http.Request) {
+	var userID int
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		var err error
+		userID, err = strconv.Atoi(userIDStr)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+	}
+	userID, ok := requestedUser(w, r, userID, "you do not have permission to view these holds")
+	if !ok {
+		return
+	}
+
+	holds, err := h.holdService.ListActiveHolds(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list holds")
+		return
+	}
+	if holds == nil {
+		holds = []*domain.Hold{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(holds)
+}
+
+// GetHold handles retrieval of a hold by ID
+func (h *HoldHandler) GetHold(w http.ResponseWriter, r *http.Request) {
+	hold, ok := h.authorizedHold(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hold)
+}
+
+// CaptureHold handles debiting a held amount
+func (h *HoldHandler) CaptureHold(w http.ResponseWriter, r *http.Request) {
+	hold, ok := h.authorizedHold(w, r)
+	if !ok {
+		return
+	}
+
+	captured, err := h.holdService.CaptureHold(r.Context(), hold.ID)
+	if errors.Is(err, domain.ErrTransactionHeld) {
+		// The hold stays active until the debit is approved
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(captured)
+		return
+	}
+	if err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to capture hold")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(captured)
+}
+
+// ReleaseHold handles returning a held amount to the available balance
+func (h *HoldHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	hold, ok := h.authorizedHold(w, r)
+	if !ok {
+		return
+	}
+
+	released, err := h.holdService.ReleaseHold(r.Context(), hold.ID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to release hold")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(released)
+}
+
+// authorizedHold loads the hold named in the URL if the caller may access it
+func (h *HoldHandler) authorizedHold(w http.ResponseWriter, r *http.Request) (*domain.Hold, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid hold ID")
+		return nil, false
+	}
+
+	hold, err := h.holdService.GetHold(r.Context(), id)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Int("id", id).Msg("Failed to get hold")
+		h.respondError(w, r, http.StatusInternalServerError, "failed to get hold")
+		return nil, false
+	}
+	if hold == nil {
+		h.respondError(w, r, http.StatusNotFound, "hold not found")
+		return nil, false
+	}
+	if !authorizeUser(w, r, hold.UserID, "you do not have permission to access this hold") {
+		return nil, false
+	}
+
+	return hold, true
+}
+
+// respondError is a helper method to respond with error
+func (h *HoldHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// InvoiceHandler handles the monthly invoices of the fees users and organizations paid.
+type InvoiceHandler struct {
+	service domain.InvoiceService
+}
+
+// NewInvoiceHandler creates a new InvoiceHandler
+func NewInvoiceHandler(service domain.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{service: service}
+}
+
+// RegisterRoutes registers the invoice routes.
+func (h *InvoiceHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/users/{userID}/invoices", h.List)
+	r.Get("/users/{userID}/invoices/{invoiceID}", h.Get)
+	r.Get("/users/{userID}/invoices/{invoiceID}/pdf", h.PDF)
+	r.With(middleware.RequireRoles("admin")).Post("/admin/invoices/run", h.RunInvoicing)
+}
+
+// RegisterOrganizationRoutes registers the routes of an organization's invoices.
+func (h *InvoiceHandler) RegisterOrganizationRoutes(r chi.Router) {
+	r.Get("/{id}/invoices", h.ListForOrganization)
+	r.Get("/{id}/invoices/{invoiceID}", h.GetForOrganization)
+	r.Get("/{id}/invoices/{invoiceID}/pdf", h.OrganizationPDF)
+}
+
+// List handles GET /users/{userID}/invoices, newest first
+func (h *InvoiceHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	limit, offset, err := parsePage(r.URL.Query(), 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	invoices, err := h.service.List(r.Context(), userID, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list invoices")
+		return
+	}
+	h.respondList(w, invoices)
+}
+
+// Get handles GET /users/{userID}/invoices/{invoiceID}, answering with the invoice and its lines
+func (h *InvoiceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.invoiceID(w, r)
+	if !ok {
+		return
+	}
+	invoice, err := h.service.Get(r.Context(), userID, id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get invoice")
+		return
+	}
+	json.NewEncoder(w).Encode(invoice)
+}
+
+// PDF handles GET /users/{userID}/invoices/{invoiceID}/pdf, downloading the invoice
+func (h *InvoiceHandler) PDF(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.invoiceID(w, r)
+	if !ok {
+		return
+	}
+	document, err := h.service.PDF(r.Context(), userID, id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to render invoice")
+		return
+	}
+	h.respondPDF(w, document)
+}
+
+// ListForOrganization handles GET /organizations/{id}/invoices, newest first
+func (h *InvoiceHandler) ListForOrganization(w http.ResponseWriter, r *http.Request) {
+	actorID, orgID, ok := h.organization(w, r)
+	if !ok {
+		return
+	}
+	limit, offset, err := parsePage(r.URL.Query(), 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	invoices, err := h.service.ListForOrganization(r.Context(), actorID, isAdmin(r), orgID, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list organization invoices")
+		return
+	}
+	h.respondList(w, invoices)
+}
+
+// GetForOrganization handles GET /organizations/{id}/invoices/{invoiceID}
+func (h *InvoiceHandler) GetForOrganization(w http.ResponseWriter, r *http.Request) {
+	actorID, orgID, ok := h.organization(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.invoiceID(w, r)
+	if !ok {
+		return
+	}
+	invoice, err := h.service.GetForOrganization(r.Context(), actorID, isAdmin(r), orgID, id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get organization invoice")
+		return
+	}
+	json.NewEncoder(w).Encode(invoice)
+}
+
+// OrganizationPDF handles GET /organizations/{id}/invoices/{invoiceID}/pdf
+func (h *InvoiceHandler) OrganizationPDF(w http.ResponseWriter, r *http.Request) {
+	actorID, orgID, ok := h.organization(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.invoiceID(w, r)
+	if !ok {
+		return
+	}
+	document, err := h.service.OrganizationPDF(r.Context(), actorID, isAdmin(r), orgID, id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to render organization invoice")
+		return
+	}
+	h.respondPDF(w, document)
+}
+
+// RunInvoicing handles POST /admin/invoices/run.
+func (h *InvoiceHandler) RunInvoicing(w http.ResponseWriter, r *http.Request) {
+	run, err := h.service.RunInvoicing(r.Context())
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to run invoicing")
+		return
+	}
+	json.NewEncoder(w).Encode(run)
+}
+
+// organization returns the caller's ID and the organization ID in the path
+func (h *InvoiceHandler) organization(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	orgID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid organization ID")
+		return 0, 0, false
+	}
+	return actorID, orgID, true
+}
+
+// invoiceID parses the invoice ID in the path
+func (h *InvoiceHandler) invoiceID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "invoiceID"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid invoice ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// respondList encodes a page of invoices, empty rather than null
+func (h *InvoiceHandler) respondList(w http.ResponseWriter, invoices []*domain.Invoice) {
+	if invoices == nil {
+		invoices = []*domain.Invoice{}
+	}
+	json.NewEncoder(w).Encode(invoices)
+}
+
+// respondPDF sends a rendered invoice as a download
+func (h *InvoiceHandler) respondPDF(w http.ResponseWriter, document *domain.InvoiceDocument) {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Length", strconv.Itoa(len(document.Content)))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.FileName}))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Write(document.Content)
+}
+
+// respondError is a helper method to respond with error
+func (h *InvoiceHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/kyc"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// maxKYCCallbackBytes caps the body of a provider callback
+const maxKYCCallbackBytes = 64 << 10
+
+// ReviewKYCRequest represents the request body for rejecting a verification
+type ReviewKYCRequest struct {
+	Reason string `json:"reason"`
+}
+
+// KYCTierRuleRequest is a limit rule of a KYC level
+type KYCTierRuleRequest struct {
+	RuleType    string        `json:"rule_type"`
+	LimitAmount float64       `json:"limit_amount"`
+	Window      time.Duration `json:"window"` // nanoseconds, for windowed rule types
+}
+
+// KYCHandler handles identity verification.
+type KYCHandler struct {
+	service domain.KYCService
+}
+
+// NewKYCHandler creates a new KYCHandler
+func NewKYCHandler(service domain.KYCService) *KYCHandler {
+	return &KYCHandler{service: service}
+}
+
+// RegisterRoutes registers the authenticated KYC routes.
+func (h *KYCHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/users/{userID}/kyc", h.GetState)
+	r.Post("/users/{userID}/kyc", h.Submit)
+	r.Route("/admin/kyc", func(r chi.Router) {
+		r.Use(middleware.RequireRoles("admin"))
+		r.Get("/verifications", h.ListVerifications)
+		r.Get("/verifications/{id}", h.GetVerification)
+		r.Post("/verifications/{id}/approve", h.Approve)
+		r.Post("/verifications/{id}/reject", h.Reject)
+		r.Get("/limits", h.ListTierLimits)
+		r.Put("/limits/{level}", h.SetTierLimits)
+	})
+}
+
+// GetState handles GET /users/{userID}/kyc.
+func (h *KYCHandler) GetState(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	state, err := h.service.State(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get KYC state")
+		return
+	}
+	json.NewEncoder(w).Encode(state)
+}
+
+// Submit handles POST /users/{userID}/kyc
+func (h *KYCHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	var submission domain.KYCSubmission
+	if err := json.NewDecoder(r.Body).Decode(&submission); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	verification, err := h.service.Submit(r.Context(), userID, &submission)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to submit KYC verification")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(verification)
+}
+
+// Callback handles POST /kyc/callback, where the provider reports results.
+func (h *KYCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxKYCCallbackBytes))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "request body is too large")
+		return
+	}
+	callback := &domain.KYCCallback{
+		Signature: r.Header.Get(kyc.SignatureHeader),
+		Timestamp: r.Header.Get(kyc.TimestampHeader),
+		Body:      body,
+	}
+	if err := h.service.HandleCallback(r.Context(), callback); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to handle KYC callback")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListVerifications handles GET /admin/kyc/verifications.
+func (h *KYCHandler) ListVerifications(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, offset, err := parsePage(q, 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	status := q.Get("status")
+	if status == "" {
+		status = domain.KYCStatusReview
+	}
+	verifications, err := h.service.ListVerifications(r.Context(), status, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list KYC verifications")
+		return
+	}
+	if verifications == nil {
+		verifications = []*domain.KYCVerification{}
+	}
+	json.NewEncoder(w).Encode(verifications)
+}
+
+// GetVerification handles GET /admin/kyc/verifications/{id}
+func (h *KYCHandler) GetVerification(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.verificationID(w, r)
+	if !ok {
+		return
+	}
+	verification, err := h.service.GetVerification(r.Context(), id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get KYC verification")
+		return
+	}
+	json.NewEncoder(w).Encode(verification)
+}
+
+// Approve handles POST /admin/kyc/verifications/{id}/approve
+func (h *KYCHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, true)
+}
+
+// Reject handles POST /admin/kyc/verifications/{id}/reject; the reason is told to the user
+func (h *KYCHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, false)
+}
+
+// review decides the verification of the route
+func (h *KYCHandler) review(w http.ResponseWriter, r *http.Request, approve bool) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.verificationID(w, r)
+	if !ok {
+		return
+	}
+	var req ReviewKYCRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	verification, err := h.service.Review(r.Context(), id, approve, req.Reason, actorID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to review KYC verification")
+		return
+	}
+	json.NewEncoder(w).Encode(verification)
+}
+
+// ListTierLimits handles GET /admin/kyc/limits.
+func (h *KYCHandler) ListTierLimits(w http.ResponseWriter, r *http.Request) {
+	limits, err := h.service.TierLimits(r.Context())
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list KYC tier limits")
+		return
+	}
+	json.NewEncoder(w).Encode(limits)
+}
+
+// SetTierLimits handles PUT /admin/kyc/limits/{level}.
+func (h *KYCHandler) SetTierLimits(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	level, err := strconv.Atoi(chi.URLParam(r, "level"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid level")
+		return
+	}
+	var req []KYCTierRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "request body must be a list of rules")
+		return
+	}
+	rules := make([]domain.TransactionLimitRule, len(req))
+	for i, rule := range req {
+		rules[i] = domain.TransactionLimitRule{RuleType: domain.RuleType(rule.RuleType), LimitAmount: rule.LimitAmount, Window: rule.Window, Active: true}
+	}
+	if err := h.service.SetTierLimits(r.Context(), level, rules, actorID); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to set KYC tier limits")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verificationID parses the verification ID in the path
+func (h *KYCHandler) verificationID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid verification id")
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError is a helper method to respond with error
+func (h *KYCHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
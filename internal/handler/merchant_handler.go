@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// SaveMerchantRequest represents the request body for saving a merchant account.
+type SaveMerchantRequest struct {
+	BusinessName       string  `json:"business_name"`
+	SettlementSourceID int     `json:"settlement_source_id"`
+	PayoutSchedule     string  `json:"payout_schedule"`
+	MinPayout          float64 `json:"min_payout"`
+}
+
+// MerchantHandler handles merchant accounts and their payouts.
+type MerchantHandler struct {
+	service domain.MerchantService
+}
+
+// NewMerchantHandler creates a new MerchantHandler
+func NewMerchantHandler(service domain.MerchantService) *MerchantHandler {
+	return &MerchantHandler{service: service}
+}
+
+// RegisterRoutes registers the merchant routes.
+func (h *MerchantHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/users/{userID}/merchant", h.GetMerchant)
+	r.Put("/users/{userID}/merchant", h.SaveMerchant)
+	r.Get("/users/{userID}/payouts", h.ListPayouts)
+	r.Get("/users/{userID}/payouts/{id}", h.GetStatement)
+	r.With(middleware.RequireRoles("admin")).Post("/admin/payouts/run", h.RunPayouts)
+}
+
+// GetMerchant handles GET /users/{userID}/merchant
+func (h *MerchantHandler) GetMerchant(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	merchant, err := h.service.GetMerchant(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get merchant")
+		return
+	}
+	json.NewEncoder(w).Encode(merchant)
+}
+
+// SaveMerchant handles PUT /users/{userID}/merchant
+func (h *MerchantHandler) SaveMerchant(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	var req SaveMerchantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	merchant, err := h.service.SaveMerchant(r.Context(), userID, &domain.Merchant{
+		BusinessName:       req.BusinessName,
+		SettlementSourceID: req.SettlementSourceID,
+		PayoutSchedule:     req.PayoutSchedule,
+		MinPayout:          req.MinPayout,
+	})
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to save merchant")
+		return
+	}
+	json.NewEncoder(w).Encode(merchant)
+}
+
+// ListPayouts handles GET /users/{userID}/payouts, newest first
+func (h *MerchantHandler) ListPayouts(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	limit, offset, err := parsePage(r.URL.Query(), 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	payouts, err := h.service.ListPayouts(r.Context(), userID, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list payouts")
+		return
+	}
+	if payouts == nil {
+		payouts = []*domain.Payout{}
+	}
+	json.NewEncoder(w).Encode(payouts)
+}
+
+// GetStatement handles GET /users/{userID}/payouts/{id}.
+func (h *MerchantHandler) GetStatement(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid payout ID")
+		return
+	}
+	statement, err := h.service.GetStatement(r.Context(), userID, id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get payout statement")
+		return
+	}
+	json.NewEncoder(w).Encode(statement)
+}
+
+// RunPayouts handles POST /admin/payouts/run.
+func (h *MerchantHandler) RunPayouts(w http.ResponseWriter, r *http.Request) {
+	run, err := h.service.RunPayouts(r.Context())
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to run payouts")
+		return
+	}
+	json.NewEncoder(w).Encode(run)
+}
+
+// respondError is a helper method to respond with error
+func (h *MerchantHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// MoneyRequestHandler handles HTTP requests for requesting money from other users
+type MoneyRequestHandler struct {
+	requestService domain.MoneyRequestService
+}
+
+// NewMoneyRequestHandler creates a new MoneyRequestHandler
+func NewMoneyRequestHandler(requestService domain.MoneyRequestService) *MoneyRequestHandler {
+	return &MoneyRequestHandler{
+		requestService: requestService,
+	}
+}
+
+// RegisterRoutes registers the money request routes
+func (h *MoneyRequestHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.CreateRequest)
+	r.Get("/", h.ListRequests)
+	r.Get("/{id}", h.GetRequest)
+	r.Post("/{id}/accept", h.AcceptRequest)
+	r.Post("/{id}/decline", h.DeclineRequest)
+	r.Post("/{id}/cancel", h.CancelRequest)
+}
+
+// CreateMoneyRequestRequest represents a request asking another user for money
+type CreateMoneyRequestRequest struct {
+	RequesterID int     `json:"requester_id"`
+	PayerID     int     `json:"payer_id"`
+	Amount      float64 `json:"amount"`
+	Note        string  `json:"note"`
+}
+
+// CreateRequest handles asking another user to pay the caller
+func (h *MoneyRequestHandler) CreateRequest(w http.ResponseWriter, r *http.Request) {
+	var req CreateMoneyRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// Default to requesting money for the caller's own account
+	userID, ok := requestedUser(w, r, req.RequesterID, "you can only request money for your own account")
+	if !ok {
+		return
+	}
+	req.RequesterID = userID
+
+	moneyReq, err := h.requestService.RequestMoney(r.Context(), req.RequesterID, req.PayerID, req.Amount, req.Note)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to request money")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(moneyReq)
+}
+
+// ListRequests handles listing a user's incoming or outgoing money requests.
+func (h *MoneyRequestHandler) ListRequests(w http.ResponseWriter, r *http.Request) {
+	var userID int
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		var err error
+		userID, err = strconv.Atoi(userIDStr)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+	}
+	userID, ok := requestedUser(w, r, userID, "you do not have permission to view these money requests")
+	if !ok {
+		return
+	}
+
+	var requests []*domain.MoneyRequest
+	var err error
+	switch r.URL.Query().Get("direction") {
+	case "", "incoming":
+		requests, err = h.requestService.ListIncoming(r.Context(), userID)
+	case "outgoing":
+		requests, err = h.requestService.ListOutgoing(r.Context(), userID)
+	default:
+		h.respondError(w, r, http.StatusBadRequest, "direction must be incoming or outgoing")
+		return
+	}
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list money requests")
+		return
+	}
+	if requests == nil {
+		requests = []*domain.MoneyRequest{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requests)
+}
+
+// GetRequest handles retrieval of a money request by either party
+func (h *MoneyRequestHandler) GetRequest(w http.ResponseWriter, r *http.Request) {
+	moneyReq, ok := h.loadRequest(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	if userID != moneyReq.PayerID && userID != moneyReq.RequesterID && !isAdmin(r) {
+		h.respondError(w, r, http.StatusForbidden, "you do not have permission to access this money request")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(moneyReq)
+}
+
+// AcceptRequest handles the payer paying a money request
+func (h *MoneyRequestHandler) AcceptRequest(w http.ResponseWriter, r *http.Request) {
+	moneyReq, ok := h.loadRequest(w, r)
+	if !ok {
+		return
+	}
+	if !authorizeUser(w, r, moneyReq.PayerID, "only the payer can accept this money request") {
+		return
+	}
+	if err := moneyReq.CheckPending(); err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to accept money request")
+		return
+	}
+
+	accepted, err := h.requestService.Accept(r.Context(), moneyReq.ID)
+	if errors.Is(err, domain.ErrTransactionHeld) {
+		// The transfer runs once an admin approves it
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(accepted)
+		return
+	}
+	if err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to accept money request")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(accepted)
+}
+
+// DeclineRequest handles the payer refusing a money request
+func (h *MoneyRequestHandler) DeclineRequest(w http.ResponseWriter, r *http.Request) {
+	moneyReq, ok := h.loadRequest(w, r)
+	if !ok {
+		return
+	}
+	if !authorizeUser(w, r, moneyReq.PayerID, "only the payer can decline this money request") {
+		return
+	}
+
+	declined, err := h.requestService.Decline(r.Context(), moneyReq.ID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to decline money request")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(declined)
+}
+
+// CancelRequest handles the requester withdrawing a money request
+func (h *MoneyRequestHandler) CancelRequest(w http.ResponseWriter, r *http.Request) {
+	moneyReq, ok := h.loadRequest(w, r)
+	if !ok {
+		return
+	}
+	if !authorizeUser(w, r, moneyReq.RequesterID, "only the requester can cancel this money request") {
+		return
+	}
+
+	cancelled, err := h.requestService.Cancel(r.Context(), moneyReq.ID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to cancel money request")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cancelled)
+}
+
+// loadRequest loads the money request named in the URL
+func (h *MoneyRequestHandler) loadRequest(w http.ResponseWriter, r *http.Request) (*domain.MoneyRequest, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid money request ID")
+		return nil, false
+	}
+
+	moneyReq, err := h.requestService.GetRequest(r.Context(), id)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Int("id", id).Msg("Failed to get money request")
+		h.respondError(w, r, http.StatusInternalServerError, "failed to get money request")
+		return nil, false
+	}
+	if moneyReq == nil {
+		h.respondError(w, r, http.StatusNotFound, "money request not found")
+		return nil, false
+	}
+
+	return moneyReq, true
+}
+
+// respondError is a helper method to respond with error
+func (h *MoneyRequestHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// NotificationHandler handles a user's notification settings
+type NotificationHandler struct {
+	service domain.NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler
+func NewNotificationHandler(service domain.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// RegisterRoutes registers the notification settings routes.
+func (h *NotificationHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/users/{userID}/notifications", func(r chi.Router) {
+		r.Get("/preferences", h.GetPreferences)
+		r.Put("/preferences", h.SetPreferences)
+		r.Put("/contacts/{channel}", h.SetContact)
+		r.Delete("/contacts/{channel}", h.DeleteContact)
+	})
+}
+
+// setPreferencesRequest is the body of a preferences update.
+type setPreferencesRequest struct {
+	Preferences []domain.NotificationPreference `json:"preferences"`
+}
+
+// setContactRequest is the body of a contact update
+type setContactRequest struct {
+	Address string `json:"address"`
+}
+
+// GetPreferences lists whether each event is sent on each channel
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.service.GetPreferences(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get notification preferences")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// SetPreferences stores the given preferences and returns the full set
+func (h *NotificationHandler) SetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req setPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.service.SetPreferences(r.Context(), userID, req.Preferences); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to set notification preferences")
+		return
+	}
+	h.GetPreferences(w, r)
+}
+
+// SetContact stores the SMS number or push token of the channel
+func (h *NotificationHandler) SetContact(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req setContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Address == "" {
+		h.respondError(w, r, http.StatusBadRequest, "address is required")
+		return
+	}
+	if err := h.service.SetContact(r.Context(), userID, chi.URLParam(r, "channel"), req.Address); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to set notification contact")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteContact removes the address of the channel, which stops its notifications
+func (h *NotificationHandler) DeleteContact(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.SetContact(r.Context(), userID, chi.URLParam(r, "channel"), ""); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to delete notification contact")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondError is a helper method to respond with error
+func (h *NotificationHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
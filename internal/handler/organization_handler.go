@@ -0,0 +1,399 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// OrganizationHandler handles HTTP requests for organizations.
+type OrganizationHandler struct {
+	orgService domain.OrganizationService
+}
+
+// NewOrganizationHandler creates a new OrganizationHandler
+func NewOrganizationHandler(orgService domain.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService: orgService,
+	}
+}
+
+// RegisterRoutes registers the organization routes.
+func (h *OrganizationHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.CreateOrganization)
+	r.Get("/", h.ListOrganizations)
+	r.Get("/{id}", h.GetOrganization)
+	r.Post("/{id}/members", h.AddMember)
+	r.Put("/{id}/members/{userID}", h.UpdateMember)
+	r.Delete("/{id}/members/{userID}", h.RemoveMember)
+	r.Post("/{id}/deposits", h.Deposit)
+	r.Get("/{id}/transactions", h.ListTransactions)
+	r.Post("/{id}/transactions", h.RequestTransaction)
+	r.Get("/{id}/approvals", h.ListSignOffs)
+	r.Post("/{id}/approvals/{transactionID}/approve", h.ApproveTransaction)
+	r.Post("/{id}/approvals/{transactionID}/reject", h.RejectTransaction)
+}
+
+// CreateOrganizationRequest represents a request to create an organization
+type CreateOrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+// OrganizationMemberRequest represents a request to add a member or change their role
+type OrganizationMemberRequest struct {
+	UserID int    `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// OrganizationDepositRequest represents a member moving money into the organization
+type OrganizationDepositRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+// OrganizationTransactionRequest represents a request to spend the organization's balance.
+type OrganizationTransactionRequest struct {
+	Type     string  `json:"type"`
+	ToUserID *int    `json:"to_user_id,omitempty"`
+	Amount   float64 `json:"amount"`
+}
+
+// CreateOrganization handles creation of an organization owned by the caller
+func (h *OrganizationHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	org, err := h.orgService.Create(r.Context(), actorID, req.Name)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to create organization")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(org)
+}
+
+// ListOrganizations handles listing the organizations the caller belongs to
+func (h *OrganizationHandler) ListOrganizations(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	orgs, err := h.orgService.ListForUser(r.Context(), actorID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list organizations")
+		return
+	}
+	if orgs == nil {
+		orgs = []*domain.Organization{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orgs)
+}
+
+// GetOrganization handles retrieval of an organization with its members and balance
+func (h *OrganizationHandler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	orgID, ok := h.pathID(w, r, "id", "invalid organization ID")
+	if !ok {
+		return
+	}
+
+	details, err := h.orgService.Get(r.Context(), actorID, isAdmin(r), orgID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get organization")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(details)
+}
+
+// AddMember handles an owner adding a user to the organization
+func (h *OrganizationHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	orgID, ok := h.pathID(w, r, "id", "invalid organization ID")
+	if !ok {
+		return
+	}
+
+	var req OrganizationMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	member, err := h.orgService.AddMember(r.Context(), actorID, orgID, req.UserID, req.Role)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to add organization member")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(member)
+}
+
+// UpdateMember handles an owner changing a member's role
+func (h *OrganizationHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	orgID, ok := h.pathID(w, r, "id", "invalid organization ID")
+	if !ok {
+		return
+	}
+	userID, ok := h.pathID(w, r, "userID", "invalid user ID")
+	if !ok {
+		return
+	}
+
+	var req OrganizationMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	member, err := h.orgService.UpdateMemberRole(r.Context(), actorID, orgID, userID, req.Role)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to update organization member")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(member)
+}
+
+// RemoveMember handles an owner removing a member, or a member leaving
+func (h *OrganizationHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	orgID, ok := h.pathID(w, r, "id", "invalid organization ID")
+	if !ok {
+		return
+	}
+	userID, ok := h.pathID(w, r, "userID", "invalid user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.orgService.RemoveMember(r.Context(), actorID, orgID, userID); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to remove organization member")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Deposit handles a member moving money from their own balance to the organization
+func (h *OrganizationHandler) Deposit(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	orgID, ok := h.pathID(w, r, "id", "invalid organization ID")
+	if !ok {
+		return
+	}
+
+	var req OrganizationDepositRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	tx, err := h.orgService.Deposit(r.Context(), actorID, orgID, req.Amount)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to deposit to organization")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tx)
+}
+
+// ListTransactions handles listing a page of the transactions of the organization's balance, newest first
+func (h *OrganizationHandler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	orgID, ok := h.pathID(w, r, "id", "invalid organization ID")
+	if !ok {
+		return
+	}
+
+	limit, offset, err := parsePage(r.URL.Query(), 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	txs, err := h.orgService.ListTransactions(r.Context(), actorID, isAdmin(r), orgID, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list organization transactions")
+		return
+	}
+	if txs == nil {
+		txs = []*domain.Transaction{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(txs)
+}
+
+// RequestTransaction handles an approver requesting a payment from the organization.
+func (h *OrganizationHandler) RequestTransaction(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	orgID, ok := h.pathID(w, r, "id", "invalid organization ID")
+	if !ok {
+		return
+	}
+
+	var req OrganizationTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	ot, err := h.orgService.RequestTransaction(r.Context(), actorID, orgID, req.Type, req.ToUserID, req.Amount)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to request organization transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(ot)
+}
+
+// ListSignOffs handles listing the organization's sign-off records, optionally by status
+func (h *OrganizationHandler) ListSignOffs(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	orgID, ok := h.pathID(w, r, "id", "invalid organization ID")
+	if !ok {
+		return
+	}
+	limit, offset, err := parsePage(r.URL.Query(), 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	records, err := h.orgService.ListSignOffs(r.Context(), actorID, isAdmin(r), orgID, r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list organization approvals")
+		return
+	}
+	if records == nil {
+		records = []*domain.OrganizationTransaction{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
+
+// ApproveTransaction handles a second approver signing off a held transaction, which moves the money
+func (h *OrganizationHandler) ApproveTransaction(w http.ResponseWriter, r *http.Request) {
+	actorID, orgID, txID, ok := h.parseReview(w, r)
+	if !ok {
+		return
+	}
+
+	tx, err := h.orgService.ApproveTransaction(r.Context(), actorID, orgID, txID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to approve organization transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tx)
+}
+
+// RejectTransaction handles an approver rejecting a held transaction
+func (h *OrganizationHandler) RejectTransaction(w http.ResponseWriter, r *http.Request) {
+	actorID, orgID, txID, ok := h.parseReview(w, r)
+	if !ok {
+		return
+	}
+
+	var req RejectTransactionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	tx, err := h.orgService.RejectTransaction(r.Context(), actorID, orgID, txID, req.Reason)
+	if err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to reject organization transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tx)
+}
+
+// parseReview reads the reviewer, organization and transaction IDs of a request.
+func (h *OrganizationHandler) parseReview(w http.ResponseWriter, r *http.Request) (int, int, int, bool) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	orgID, ok := h.pathID(w, r, "id", "invalid organization ID")
+	if !ok {
+		return 0, 0, 0, false
+	}
+	txID, ok := h.pathID(w, r, "transactionID", "invalid transaction ID")
+	if !ok {
+		return 0, 0, 0, false
+	}
+	return actorID, orgID, txID, true
+}
+
+// pathID parses an integer URL parameter
+func (h *OrganizationHandler) pathID(w http.ResponseWriter, r *http.Request, name, invalidMsg string) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, name))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, invalidMsg)
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError is a helper method to respond with error
+func (h *OrganizationHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
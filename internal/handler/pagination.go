@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// totalCountHeader carries the number of items of a listing across all its pages.
+const totalCountHeader = "X-Total-Count"
+
+// parsePage reads the limit and offset query parameters of a paginated listing.
+func parsePage(q url.Values, defaultLimit, maxLimit int) (limit, offset int, err error) {
+	limit = defaultLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+		limit = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+		offset = n
+	}
+	return limit, offset, nil
+}
+
+// wantsTotal reports whether a listing request asks for its total count
+func wantsTotal(q url.Values) (bool, error) {
+	v := q.Get("include_total")
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.New("invalid include_total")
+	}
+	return b, nil
+}
+
+// setTotalCount sets the total count header of a listing response
+func setTotalCount(w http.ResponseWriter, total int) {
+	w.Header().Set(totalCountHeader, strconv.Itoa(total))
+}
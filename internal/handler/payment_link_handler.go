@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// defaultPaymentLinkTTL is used when a link is created without an expiry
+const defaultPaymentLinkTTL = 24 * time.Hour
+
+// PaymentLinkHandler handles HTTP requests for shareable payment links
+type PaymentLinkHandler struct {
+	linkService domain.PaymentLinkService
+}
+
+// NewPaymentLinkHandler creates a new PaymentLinkHandler
+func NewPaymentLinkHandler(linkService domain.PaymentLinkService) *PaymentLinkHandler {
+	return &PaymentLinkHandler{
+		linkService: linkService,
+	}
+}
+
+// RegisterRoutes registers the payment link routes
+func (h *PaymentLinkHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.CreateLink)
+	r.Get("/", h.ListLinks)
+	r.Get("/{token}", h.GetLink)
+	r.Post("/{token}/redeem", h.RedeemLink)
+}
+
+// CreatePaymentLinkRequest represents a request to create a payment link
+type CreatePaymentLinkRequest struct {
+	Amount           float64 `json:"amount"`
+	Description      string  `json:"description"`
+	ExpiresInSeconds int     `json:"expires_in_seconds"` // defaults to 24 hours
+}
+
+// CreatePaymentLinkResponse returns a new link together with the token that identifies it
+type CreatePaymentLinkResponse struct {
+	*domain.PaymentLink
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// CreateLink handles creating a payment link that pays the caller
+func (h *PaymentLinkHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	creatorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreatePaymentLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	ttl := defaultPaymentLinkTTL
+	if req.ExpiresInSeconds != 0 {
+		ttl = time.Duration(req.ExpiresInSeconds) * time.Second
+	}
+
+	link, token, err := h.linkService.CreateLink(r.Context(), creatorID, req.Amount, req.Description, ttl)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to create payment link")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreatePaymentLinkResponse{
+		PaymentLink: link,
+		Token:       token,
+		URL:         "/api/" + middleware.APIVersionFromContext(r.Context()).String() + "/payment-links/" + token,
+	})
+}
+
+// ListLinks handles listing the links the caller created
+func (h *PaymentLinkHandler) ListLinks(w http.ResponseWriter, r *http.Request) {
+	var userID int
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		var err error
+		userID, err = strconv.Atoi(userIDStr)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+	}
+	userID, ok := requestedUser(w, r, userID, "you do not have permission to view these payment links")
+	if !ok {
+		return
+	}
+
+	links, err := h.linkService.ListLinks(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list payment links")
+		return
+	}
+	if links == nil {
+		links = []*domain.PaymentLink{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(links)
+}
+
+// GetLink handles showing a payment link to someone holding its token
+func (h *PaymentLinkHandler) GetLink(w http.ResponseWriter, r *http.Request) {
+	link, err := h.linkService.GetLink(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get payment link")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(link)
+}
+
+// RedeemLink handles the authenticated caller paying a payment link
+func (h *PaymentLinkHandler) RedeemLink(w http.ResponseWriter, r *http.Request) {
+	payerID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	token := chi.URLParam(r, "token")
+	link, err := h.linkService.GetLink(r.Context(), token)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get payment link")
+		return
+	}
+	if !link.IsRedeemable(time.Now()) {
+		middleware.RespondServiceError(w, r, domain.ErrPaymentLinkUnavailable, "failed to redeem payment link")
+		return
+	}
+
+	redeemed, err := h.linkService.Redeem(r.Context(), token, payerID)
+	if errors.Is(err, domain.ErrTransactionHeld) {
+		// The transfer runs once an admin approves it
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(redeemed)
+		return
+	}
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to redeem payment link")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(redeemed)
+}
+
+// respondError is a helper method to respond with error
+func (h *PaymentLinkHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
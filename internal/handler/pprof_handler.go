@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// PprofHandler serves Go runtime profiles (CPU, heap, goroutine, mutex, ...) from net/http/pprof
+type PprofHandler struct{}
+
+// NewPprofHandler creates a new PprofHandler
+func NewPprofHandler() *PprofHandler {
+	return &PprofHandler{}
+}
+
+// RegisterRoutes registers the profiling routes; all of them are admin-only.
+func (h *PprofHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/debug/pprof", func(r chi.Router) {
+		r.Use(middleware.RequireRoles("admin"))
+		r.Get("/", pprof.Index)
+		r.Get("/cmdline", pprof.Cmdline)
+		r.Get("/profile", pprof.Profile)
+		r.Get("/symbol", pprof.Symbol)
+		r.Post("/symbol", pprof.Symbol)
+		r.Get("/trace", pprof.Trace)
+		r.Get("/{profile}", h.Lookup)
+	})
+}
+
+// Lookup serves a named runtime profile such as heap, goroutine, mutex or block.
+func (h *PprofHandler) Lookup(w http.ResponseWriter, r *http.Request) {
+	pprof.Handler(chi.URLParam(r, "profile")).ServeHTTP(w, r)
+}
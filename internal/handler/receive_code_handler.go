@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+	"github.com/melihgurlek/backend-path/pkg/qrcode"
+)
+
+const (
+	// defaultReceiveCodeTTL is how long a receive QR code stays redeemable
+	// unless expires_in_seconds asks otherwise
+	defaultReceiveCodeTTL = 24 * time.Hour
+	// defaultQRScale and maxQRScale bound the pixels (or SVG units) per module
+	defaultQRScale = 8
+	maxQRScale     = 32
+)
+
+// RedeemReceiveCodeRequest represents the request body for redeeming a scanned receive QR code
+type RedeemReceiveCodeRequest struct {
+	Token string `json:"token"`
+}
+
+// ReceiveCodeHandler handles the QR codes users show to receive money.
+type ReceiveCodeHandler struct {
+	service domain.ReceiveCodeService
+}
+
+// NewReceiveCodeHandler creates a new ReceiveCodeHandler
+func NewReceiveCodeHandler(service domain.ReceiveCodeService) *ReceiveCodeHandler {
+	return &ReceiveCodeHandler{service: service}
+}
+
+// RegisterRoutes registers the receive QR code routes.
+func (h *ReceiveCodeHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/users/{userID}/receive-qr", h.Issue)
+	r.Post("/receive-qr/redeem", h.Redeem)
+}
+
+// Issue handles GET /users/{userID}/receive-qr.
+func (h *ReceiveCodeHandler) Issue(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid userID")
+		return
+	}
+	if !authorizeUser(w, r, userID, "you do not have permission to issue this user's receive codes") {
+		return
+	}
+
+	q := r.URL.Query()
+	code := &domain.ReceiveCode{UserID: userID, Reference: q.Get("reference")}
+	if v := q.Get("amount"); v != "" {
+		if code.Amount, err = parseDecimalAmount(v); err != nil {
+			h.respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	ttl := defaultReceiveCodeTTL
+	if v := q.Get("expires_in_seconds"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds <= 0 {
+			h.respondError(w, r, http.StatusBadRequest, "expires_in_seconds must be a positive integer")
+			return
+		}
+		ttl = time.Duration(seconds) * time.Second
+	}
+	format := q.Get("format")
+	if format == "" {
+		format = "png"
+	}
+	if format != "png" && format != "svg" {
+		h.respondError(w, r, http.StatusBadRequest, "format must be png or svg")
+		return
+	}
+	scale := defaultQRScale
+	if v := q.Get("scale"); v != "" {
+		scale, err = strconv.Atoi(v)
+		if err != nil || scale <= 0 || scale > maxQRScale {
+			h.respondError(w, r, http.StatusBadRequest, "scale must be between 1 and "+strconv.Itoa(maxQRScale))
+			return
+		}
+	}
+
+	token, err := h.service.Issue(r.Context(), code, ttl)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to issue receive code")
+		return
+	}
+	// Tokens are far below the largest version's capacity
+	qr, err := qrcode.Encode([]byte(token))
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to encode receive code")
+		return
+	}
+	var data []byte
+	if format == "svg" {
+		data = qr.SVG(scale)
+		w.Header().Set("Content-Type", "image/svg+xml")
+	} else {
+		if data, err = qr.PNG(scale); err != nil {
+			middleware.RespondServiceError(w, r, err, "failed to render receive code")
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Receive-Code-Expires-At", code.ExpiresAt.Format(time.RFC3339))
+	w.Write(data)
+}
+
+// Redeem handles POST /receive-qr/redeem.
+func (h *ReceiveCodeHandler) Redeem(w http.ResponseWriter, r *http.Request) {
+	payerID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	var req RedeemReceiveCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	draft, err := h.service.Redeem(r.Context(), req.Token, payerID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to redeem receive code")
+		return
+	}
+	json.NewEncoder(w).Encode(draft)
+}
+
+// respondError is a helper method to respond with error
+func (h *ReceiveCodeHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// ReferralHandler handles a user's referral code, referrals and earnings
+type ReferralHandler struct {
+	service domain.ReferralService
+}
+
+// NewReferralHandler creates a new ReferralHandler
+func NewReferralHandler(service domain.ReferralService) *ReferralHandler {
+	return &ReferralHandler{service: service}
+}
+
+// RegisterRoutes registers the referral routes.
+func (h *ReferralHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/users/{userID}/referral", h.GetSummary)
+	r.Get("/users/{userID}/referrals", h.ListReferrals)
+}
+
+// GetSummary handles GET /users/{userID}/referral.
+func (h *ReferralHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	summary, err := h.service.Summary(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get referral summary")
+		return
+	}
+	json.NewEncoder(w).Encode(summary)
+}
+
+// ListReferrals handles GET /users/{userID}/referrals.
+func (h *ReferralHandler) ListReferrals(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	referrals, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list referrals")
+		return
+	}
+	if referrals == nil {
+		referrals = []*domain.Referral{}
+	}
+	json.NewEncoder(w).Encode(referrals)
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// SetRequestQuotaRequest represents the request body setting a request quota.
+type SetRequestQuotaRequest struct {
+	Limit int64 `json:"limit"`
+}
+
+// RequestQuotaHandler handles the request quota routes.
+type RequestQuotaHandler struct {
+	service domain.RequestQuotaService
+}
+
+// NewRequestQuotaHandler creates a new RequestQuotaHandler.
+func NewRequestQuotaHandler(service domain.RequestQuotaService) *RequestQuotaHandler {
+	return &RequestQuotaHandler{service: service}
+}
+
+// RegisterRoutes registers the quota routes.
+func (h *RequestQuotaHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/quotas", func(r chi.Router) {
+		r.Use(middleware.RequireRoles("admin"))
+		r.Get("/", h.List)
+		r.Put("/roles/{role}/{period}", h.SetRoleQuota)
+		r.Delete("/roles/{role}/{period}", h.RemoveRoleQuota)
+		r.Put("/users/{userID}/{period}", h.SetUserQuota)
+		r.Delete("/users/{userID}/{period}", h.RemoveUserQuota)
+	})
+	r.Get("/users/{id}/quota", h.GetUsage)
+}
+
+// List handles GET /admin/quotas
+func (h *RequestQuotaHandler) List(w http.ResponseWriter, r *http.Request) {
+	quotas, err := h.service.List(r.Context())
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list quotas")
+		return
+	}
+	if quotas == nil {
+		quotas = []*domain.RequestQuota{}
+	}
+	json.NewEncoder(w).Encode(quotas)
+}
+
+// SetRoleQuota handles PUT /admin/quotas/roles/{role}/{period}
+func (h *RequestQuotaHandler) SetRoleQuota(w http.ResponseWriter, r *http.Request) {
+	h.set(w, r, &domain.RequestQuota{Role: chi.URLParam(r, "role"), Period: chi.URLParam(r, "period")})
+}
+
+// SetUserQuota handles PUT /admin/quotas/users/{userID}/{period}.
+func (h *RequestQuotaHandler) SetUserQuota(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid userID")
+		return
+	}
+	h.set(w, r, &domain.RequestQuota{UserID: &userID, Period: chi.URLParam(r, "period")})
+}
+
+// set sets quota to the limit in the request body
+func (h *RequestQuotaHandler) set(w http.ResponseWriter, r *http.Request, quota *domain.RequestQuota) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	var req SetRequestQuotaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	quota.Limit = req.Limit
+	if err := h.service.Set(r.Context(), actorID, quota); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to set quota")
+		return
+	}
+	json.NewEncoder(w).Encode(quota)
+}
+
+// RemoveRoleQuota handles DELETE /admin/quotas/roles/{role}/{period}
+func (h *RequestQuotaHandler) RemoveRoleQuota(w http.ResponseWriter, r *http.Request) {
+	h.remove(w, r, chi.URLParam(r, "role"), nil)
+}
+
+// RemoveUserQuota handles DELETE /admin/quotas/users/{userID}/{period}.
+func (h *RequestQuotaHandler) RemoveUserQuota(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid userID")
+		return
+	}
+	h.remove(w, r, "", &userID)
+}
+
+// remove removes the quota of a role or user for the period in the path
+func (h *RequestQuotaHandler) remove(w http.ResponseWriter, r *http.Request, role string, userID *int) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.Remove(r.Context(), actorID, role, userID, chi.URLParam(r, "period")); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to remove quota")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetUsage handles GET /users/{id}/quota, a user's use of their quotas in the current periods.
+func (h *RequestQuotaHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	if !authorizeUser(w, r, userID, "you do not have permission to view this user's quota") {
+		return
+	}
+
+	usage, err := h.service.Usage(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get quota usage")
+		return
+	}
+	json.NewEncoder(w).Encode(usage)
+}
+
+// respondError is a helper method to respond with error
+func (h *RequestQuotaHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// SavingsGoalHandler handles HTTP requests for savings goals
+type SavingsGoalHandler struct {
+	goalService domain.SavingsGoalService
+}
+
+// NewSavingsGoalHandler creates a new SavingsGoalHandler
+func NewSavingsGoalHandler(goalService domain.SavingsGoalService) *SavingsGoalHandler {
+	return &SavingsGoalHandler{
+		goalService: goalService,
+	}
+}
+
+// RegisterRoutes registers the savings goal routes
+func (h *SavingsGoalHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.CreateGoal)
+	r.Get("/", h.ListGoals)
+	r.Get("/{id}", h.GetGoal)
+	r.Delete("/{id}", h.CancelGoal)
+	r.Get("/{id}/progress", h.GetProgress)
+	r.Post("/{id}/contributions", h.Contribute)
+	r.Post("/{id}/recurring-contribution", h.SetupRecurringContribution)
+}
+
+// CreateSavingsGoalRequest represents a request to create a savings goal
+type CreateSavingsGoalRequest struct {
+	UserID       int        `json:"user_id"`
+	Name         string     `json:"name"`
+	TargetAmount float64    `json:"target_amount"`
+	Deadline     *time.Time `json:"deadline,omitempty"`
+}
+
+// ContributeRequest represents a one-off contribution to a savings goal
+type ContributeRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+// RecurringContributionRequest represents a request to fund a goal on a recurring schedule
+type RecurringContributionRequest struct {
+	Amount     float64   `json:"amount"`
+	Recurrence string    `json:"recurrence"`
+	StartAt    time.Time `json:"start_at"`
+}
+
+// CreateGoal handles creation of a new savings goal
+func (h *SavingsGoalHandler) CreateGoal(w http.ResponseWriter, r *http.Request) {
+	var req CreateSavingsGoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// Default to the caller's own account
+	userID, ok := requestedUser(w, r, req.UserID, "you can only create savings goals for your own account")
+	if !ok {
+		return
+	}
+	req.UserID = userID
+
+	goal := &domain.SavingsGoal{
+		UserID:       req.UserID,
+		Name:         req.Name,
+		TargetAmount: req.TargetAmount,
+		Deadline:     req.Deadline,
+	}
+	if err := h.goalService.CreateGoal(r.Context(), goal); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to create savings goal")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(goal)
+}
+
+// ListGoals handles listing savings goals for a user
+func (h *SavingsGoalHandler) ListGoals(w http.ResponseWriter, r *http.Request) {
+	var userID int
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		var err error
+		userID, err = strconv.Atoi(userIDStr)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+	}
+	userID, ok := requestedUser(w, r, userID, "you do not have permission to view these savings goals")
+	if !ok {
+		return
+	}
+
+	goals, err := h.goalService.ListUserGoals(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list savings goals")
+		return
+	}
+	if goals == nil {
+		goals = []*domain.SavingsGoal{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(goals)
+}
+
+// GetGoal handles retrieval of a savings goal by ID
+func (h *SavingsGoalHandler) GetGoal(w http.ResponseWriter, r *http.Request) {
+	goal, ok := h.authorizedGoal(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(goal)
+}
+
+// CancelGoal handles cancellation of a savings goal
+func (h *SavingsGoalHandler) CancelGoal(w http.ResponseWriter, r *http.Request) {
+	goal, ok := h.authorizedGoal(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.goalService.CancelGoal(r.Context(), goal.ID); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to cancel savings goal")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetProgress handles retrieval of a savings goal's progress and projected completion
+func (h *SavingsGoalHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
+	goal, ok := h.authorizedGoal(w, r)
+	if !ok {
+		return
+	}
+
+	progress, err := h.goalService.GetProgress(r.Context(), goal.ID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get savings goal progress")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(progress)
+}
+
+// Contribute handles a one-off contribution to a savings goal
+func (h *SavingsGoalHandler) Contribute(w http.ResponseWriter, r *http.Request) {
+	goal, ok := h.authorizedGoal(w, r)
+	if !ok {
+		return
+	}
+
+	var req ContributeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	updated, err := h.goalService.Contribute(r.Context(), goal.ID, req.Amount)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to contribute to savings goal")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// SetupRecurringContribution handles linking a recurring scheduled contribution to a savings goal
+func (h *SavingsGoalHandler) SetupRecurringContribution(w http.ResponseWriter, r *http.Request) {
+	goal, ok := h.authorizedGoal(w, r)
+	if !ok {
+		return
+	}
+
+	var req RecurringContributionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	st, err := h.goalService.SetupRecurringContribution(r.Context(), goal.ID, req.Amount, req.Recurrence, req.StartAt)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to set up recurring contribution")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(st)
+}
+
+// authorizedGoal loads the goal named in the URL if the caller may access it
+func (h *SavingsGoalHandler) authorizedGoal(w http.ResponseWriter, r *http.Request) (*domain.SavingsGoal, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid savings goal ID")
+		return nil, false
+	}
+
+	goal, err := h.goalService.GetGoal(r.Context(), id)
+	if err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Int("id", id).Msg("Failed to get savings goal")
+		h.respondError(w, r, http.StatusInternalServerError, "failed to get savings goal")
+		return nil, false
+	}
+	if goal == nil {
+		h.respondError(w, r, http.StatusNotFound, "savings goal not found")
+		return nil, false
+	}
+	if !authorizeUser(w, r, goal.UserID, "you do not have permission to access this savings goal") {
+		return nil, false
+	}
+
+	return goal, true
+}
+
+// respondError is a helper method to respond with error
+func (h *SavingsGoalHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
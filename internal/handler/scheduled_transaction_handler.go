@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/rs/zerolog/log"
 
 	"github.com/melihgurlek/backend-path/internal/domain"
 	"github.com/melihgurlek/backend-path/internal/middleware"
@@ -29,25 +28,51 @@ func NewScheduledTransactionHandler(scheduledService domain.ScheduledTransaction
 // RegisterRoutes registers the scheduled transaction routes
 func (h *ScheduledTransactionHandler) RegisterRoutes(r chi.Router) {
 	r.Post("/", h.CreateScheduledTransaction)
+	r.Post("/preview", h.PreviewScheduledTransaction)
 	r.Get("/", h.ListUserScheduledTransactions)
 	r.Get("/stats", h.GetScheduledTransactionStats)
 	r.Get("/{id}", h.GetScheduledTransaction)
 	r.Put("/{id}", h.UpdateScheduledTransaction)
 	r.Delete("/{id}", h.CancelScheduledTransaction)
+	r.Post("/{id}/pause", h.PauseScheduledTransaction)
+	r.Post("/{id}/resume", h.ResumeScheduledTransaction)
 	r.Post("/execute", h.ExecuteScheduledTransactions)
 }
 
 // CreateScheduledTransactionRequest represents a request to create a scheduled transaction
 type CreateScheduledTransactionRequest struct {
-	UserID      int       `json:"user_id"`
-	ToUserID    *int      `json:"to_user_id,omitempty"`
-	Amount      float64   `json:"amount"`
-	Type        string    `json:"type"`
-	ScheduleAt  time.Time `json:"schedule_at"`
-	Recurring   bool      `json:"recurring"`
-	Recurrence  string    `json:"recurrence,omitempty"`
-	MaxRuns     *int      `json:"max_runs,omitempty"`
-	Description string    `json:"description,omitempty"`
+	UserID         int       `json:"user_id"`
+	ToUserID       *int      `json:"to_user_id,omitempty"`
+	Amount         float64   `json:"amount"`
+	Type           string    `json:"type"`
+	ScheduleAt     time.Time `json:"schedule_at"`
+	Recurring      bool      `json:"recurring"`
+	Recurrence     string    `json:"recurrence,omitempty"`
+	CronExpression string    `json:"cron_expression,omitempty"`
+	MaxRuns        *int      `json:"max_runs,omitempty"`
+	Description    string    `json:"description,omitempty"`
+}
+
+// toScheduledTransaction builds the domain object described by the request
+func (req *CreateScheduledTransactionRequest) toScheduledTransaction() *domain.ScheduledTransaction {
+	return &domain.ScheduledTransaction{
+		UserID:         req.UserID,
+		ToUserID:       req.ToUserID,
+		Amount:         req.Amount,
+		Type:           req.Type,
+		ScheduleAt:     req.ScheduleAt,
+		Recurring:      req.Recurring,
+		Recurrence:     req.Recurrence,
+		CronExpression: req.CronExpression,
+		MaxRuns:        req.MaxRuns,
+		Description:    req.Description,
+	}
+}
+
+// PreviewScheduledTransactionRequest represents a dry-run of a scheduled transaction
+type PreviewScheduledTransactionRequest struct {
+	CreateScheduledTransactionRequest
+	Occurrences int `json:"occurrences,omitempty"` // number of upcoming runs to project, default 10
 }
 
 // CreateScheduledTransaction handles creation of a new scheduled transaction
@@ -59,29 +84,11 @@ func (h *ScheduledTransactionHandler) CreateScheduledTransaction(w http.Response
 		panic("could not retrieve validated body")
 	}
 
-	st := &domain.ScheduledTransaction{
-		UserID:      req.UserID,
-		ToUserID:    req.ToUserID,
-		Amount:      req.Amount,
-		Type:        req.Type,
-		ScheduleAt:  req.ScheduleAt,
-		Recurring:   req.Recurring,
-		Recurrence:  req.Recurrence,
-		MaxRuns:     req.MaxRuns,
-		Description: req.Description,
-	}
+	st := req.toScheduledTransaction()
 
 	// The service layer will perform the final, deeper business logic validation
-	if err := h.scheduledService.CreateScheduledTransaction(st); err != nil {
-		// Check if it's a validation error from the service layer
-		var valErr *domain.ValidationError
-		if errors.As(err, &valErr) {
-			h.respondError(w, http.StatusBadRequest, err.Error())
-			return
-		}
-		// Otherwise, it's an internal server error
-		log.Error().Err(err).Msg("Failed to create scheduled transaction")
-		h.respondError(w, http.StatusInternalServerError, "failed to create scheduled transaction")
+	if err := h.scheduledService.CreateScheduledTransaction(r.Context(), st); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to create scheduled transaction")
 		return
 	}
 
@@ -89,24 +96,40 @@ func (h *ScheduledTransactionHandler) CreateScheduledTransaction(w http.Response
 	json.NewEncoder(w).Encode(st)
 }
 
+// PreviewScheduledTransaction returns a payload's upcoming occurrences without saving it.
+func (h *ScheduledTransactionHandler) PreviewScheduledTransaction(w http.ResponseWriter, r *http.Request) {
+	req, ok := middleware.GetValidatedBody[*PreviewScheduledTransactionRequest](r.Context())
+	if !ok {
+		panic("could not retrieve validated body")
+	}
+
+	preview, err := h.scheduledService.PreviewScheduledTransaction(r.Context(), req.toScheduledTransaction(), req.Occurrences)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to preview scheduled transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(preview)
+}
+
 // GetScheduledTransaction handles retrieval of a scheduled transaction by ID
 func (h *ScheduledTransactionHandler) GetScheduledTransaction(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid scheduled transaction ID")
+		h.respondError(w, r, http.StatusBadRequest, "invalid scheduled transaction ID")
 		return
 	}
 
-	st, err := h.scheduledService.GetScheduledTransaction(id)
+	st, err := h.scheduledService.GetScheduledTransaction(r.Context(), id)
 	if err != nil {
-		log.Error().Err(err).Int("id", id).Msg("Failed to get scheduled transaction")
-		h.respondError(w, http.StatusInternalServerError, "failed to get scheduled transaction: "+err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to get scheduled transaction")
 		return
 	}
 
 	if st == nil {
-		h.respondError(w, http.StatusNotFound, "scheduled transaction not found")
+		h.respondError(w, r, http.StatusNotFound, "scheduled transaction not found")
 		return
 	}
 
@@ -117,20 +140,19 @@ func (h *ScheduledTransactionHandler) GetScheduledTransaction(w http.ResponseWri
 func (h *ScheduledTransactionHandler) ListUserScheduledTransactions(w http.ResponseWriter, r *http.Request) {
 	userIDStr := r.URL.Query().Get("user_id")
 	if userIDStr == "" {
-		h.respondError(w, http.StatusBadRequest, "user_id query parameter is required")
+		h.respondError(w, r, http.StatusBadRequest, "user_id query parameter is required")
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid user_id")
+		h.respondError(w, r, http.StatusBadRequest, "invalid user_id")
 		return
 	}
 
-	transactions, err := h.scheduledService.ListUserScheduledTransactions(userID)
+	transactions, err := h.scheduledService.ListUserScheduledTransactions(r.Context(), userID)
 	if err != nil {
-		log.Error().Err(err).Int("user_id", userID).Msg("Failed to list user scheduled transactions")
-		h.respondError(w, http.StatusInternalServerError, "failed to list scheduled transactions: "+err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to list scheduled transactions")
 		return
 	}
 
@@ -139,12 +161,13 @@ func (h *ScheduledTransactionHandler) ListUserScheduledTransactions(w http.Respo
 
 // UpdateScheduledTransactionRequest represents a request to update a scheduled transaction
 type UpdateScheduledTransactionRequest struct {
-	Amount      *float64   `json:"amount,omitempty" validate:"omitempty,gt=0"`
-	ScheduleAt  *time.Time `json:"schedule_at,omitempty"`
-	Recurring   *bool      `json:"recurring,omitempty"`
-	Recurrence  *string    `json:"recurrence,omitempty" validate:"omitempty,oneof=daily weekly monthly yearly"`
-	MaxRuns     *int       `json:"max_runs,omitempty" validate:"omitempty,min=1"`
-	Description *string    `json:"description,omitempty"`
+	Amount         *float64   `json:"amount,omitempty" validate:"omitempty,gt=0"`
+	ScheduleAt     *time.Time `json:"schedule_at,omitempty"`
+	Recurring      *bool      `json:"recurring,omitempty"`
+	Recurrence     *string    `json:"recurrence,omitempty" validate:"omitempty,oneof=daily weekly monthly yearly cron"`
+	CronExpression *string    `json:"cron_expression,omitempty"`
+	MaxRuns        *int       `json:"max_runs,omitempty" validate:"omitempty,min=1"`
+	Description    *string    `json:"description,omitempty"`
 }
 
 // Validate checks the request data. This method is called by the new middleware.
@@ -164,26 +187,25 @@ func (h *ScheduledTransactionHandler) UpdateScheduledTransaction(w http.Response
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid scheduled transaction ID")
+		h.respondError(w, r, http.StatusBadRequest, "invalid scheduled transaction ID")
 		return
 	}
 
 	var req UpdateScheduledTransactionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid request body")
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	// Get existing scheduled transaction
-	existing, err := h.scheduledService.GetScheduledTransaction(id)
+	existing, err := h.scheduledService.GetScheduledTransaction(r.Context(), id)
 	if err != nil {
-		log.Error().Err(err).Int("id", id).Msg("Failed to get existing scheduled transaction")
-		h.respondError(w, http.StatusInternalServerError, "failed to get scheduled transaction: "+err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to get scheduled transaction")
 		return
 	}
 
 	if existing == nil {
-		h.respondError(w, http.StatusNotFound, "scheduled transaction not found")
+		h.respondError(w, r, http.StatusNotFound, "scheduled transaction not found")
 		return
 	}
 
@@ -200,6 +222,9 @@ func (h *ScheduledTransactionHandler) UpdateScheduledTransaction(w http.Response
 	if req.Recurrence != nil {
 		existing.Recurrence = *req.Recurrence
 	}
+	if req.CronExpression != nil {
+		existing.CronExpression = *req.CronExpression
+	}
 	if req.MaxRuns != nil {
 		existing.MaxRuns = req.MaxRuns
 	}
@@ -212,9 +237,8 @@ func (h *ScheduledTransactionHandler) UpdateScheduledTransaction(w http.Response
 		existing.NextRunAt = existing.CalculateNextRun()
 	}
 
-	if err := h.scheduledService.UpdateScheduledTransaction(existing); err != nil {
-		log.Error().Err(err).Int("id", id).Msg("Failed to update scheduled transaction")
-		h.respondError(w, http.StatusInternalServerError, "failed to update scheduled transaction: "+err.Error())
+	if err := h.scheduledService.UpdateScheduledTransaction(r.Context(), existing); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to update scheduled transaction")
 		return
 	}
 
@@ -226,25 +250,59 @@ func (h *ScheduledTransactionHandler) CancelScheduledTransaction(w http.Response
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid scheduled transaction ID")
+		h.respondError(w, r, http.StatusBadRequest, "invalid scheduled transaction ID")
 		return
 	}
 
-	if err := h.scheduledService.CancelScheduledTransaction(id); err != nil {
-		log.Error().Err(err).Int("id", id).Msg("Failed to cancel scheduled transaction")
-		h.respondError(w, http.StatusInternalServerError, "failed to cancel scheduled transaction: "+err.Error())
+	if err := h.scheduledService.CancelScheduledTransaction(r.Context(), id); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to cancel scheduled transaction")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// PauseScheduledTransaction handles pausing a recurring scheduled transaction
+func (h *ScheduledTransactionHandler) PauseScheduledTransaction(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid scheduled transaction ID")
+		return
+	}
+
+	st, err := h.scheduledService.PauseScheduledTransaction(r.Context(), id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to pause scheduled transaction")
+		return
+	}
+
+	json.NewEncoder(w).Encode(st)
+}
+
+// ResumeScheduledTransaction handles resuming a paused scheduled transaction
+func (h *ScheduledTransactionHandler) ResumeScheduledTransaction(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid scheduled transaction ID")
+		return
+	}
+
+	st, err := h.scheduledService.ResumeScheduledTransaction(r.Context(), id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, stateConflict(err), "failed to resume scheduled transaction")
+		return
+	}
+
+	json.NewEncoder(w).Encode(st)
+}
+
 // GetScheduledTransactionStats handles retrieval of scheduled transaction statistics
 func (h *ScheduledTransactionHandler) GetScheduledTransactionStats(w http.ResponseWriter, r *http.Request) {
-	stats, err := h.scheduledService.GetScheduledTransactionStats()
+	stats, err := h.scheduledService.GetScheduledTransactionStats(r.Context())
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get scheduled transaction stats")
-		h.respondError(w, http.StatusInternalServerError, "failed to get scheduled transaction stats: "+err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to get scheduled transaction stats")
 		return
 	}
 
@@ -253,9 +311,8 @@ func (h *ScheduledTransactionHandler) GetScheduledTransactionStats(w http.Respon
 
 // ExecuteScheduledTransactions handles manual execution of pending scheduled transactions
 func (h *ScheduledTransactionHandler) ExecuteScheduledTransactions(w http.ResponseWriter, r *http.Request) {
-	if err := h.scheduledService.ExecuteScheduledTransactions(); err != nil {
-		log.Error().Err(err).Msg("Failed to execute scheduled transactions")
-		h.respondError(w, http.StatusInternalServerError, "failed to execute scheduled transactions: "+err.Error())
+	if err := h.scheduledService.ExecuteScheduledTransactions(r.Context()); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to execute scheduled transactions")
 		return
 	}
 
@@ -268,8 +325,6 @@ func (h *ScheduledTransactionHandler) ExecuteScheduledTransactions(w http.Respon
 }
 
 // respondError is a helper method to respond with error
-func (h *ScheduledTransactionHandler) respondError(w http.ResponseWriter, statusCode int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
+func (h *ScheduledTransactionHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
 }
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// startupRetryAfter is the Retry-After, in seconds, sent while starting
+const startupRetryAfter = "5"
+
+// StartupHandler serves the probes while the application is starting.
+type StartupHandler struct {
+	probes *HealthHandler
+	router atomic.Pointer[http.Handler]
+}
+
+// NewStartupHandler creates a StartupHandler serving probes until it is ready
+func NewStartupHandler(probes *HealthHandler) *StartupHandler {
+	return &StartupHandler{probes: probes}
+}
+
+// Ready switches every later request over to router
+func (h *StartupHandler) Ready(router http.Handler) {
+	h.router.Store(&router)
+}
+
+func (h *StartupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if router := h.router.Load(); router != nil {
+		(*router).ServeHTTP(w, r)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/healthz":
+		h.probes.Liveness(w, r)
+	case "/readyz":
+		h.probes.Readiness(w, r)
+	default:
+		w.Header().Set("Retry-After", startupRetryAfter)
+		middleware.WriteProblem(w, r, http.StatusServiceUnavailable, "server is starting")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/melihgurlek/backend-path/internal/middleware"
 	"github.com/melihgurlek/backend-path/pkg"
 )
 
@@ -36,7 +37,7 @@ func (h *TestHandler) Echo(w http.ResponseWriter, r *http.Request) {
 	// Parse JSON request body
 	var req EchoRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		middleware.WriteProblem(w, r, http.StatusBadRequest, "Invalid JSON")
 		return
 	}
 
@@ -67,13 +68,13 @@ func (h *TestHandler) Error(w http.ResponseWriter, r *http.Request) {
 		code = 500
 	}
 
-	http.Error(w, "test error", code)
+	middleware.WriteProblem(w, r, code, "test error")
 }
 
 func (h *TestHandler) GenerateTestToken(w http.ResponseWriter, r *http.Request) {
-	token, err := pkg.GenerateToken("your-jwt-secret", "1", "user")
+	token, err := pkg.GenerateToken("your-jwt-secret", "1", "user", 0)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		middleware.WriteProblem(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -94,20 +95,6 @@ func (h *TestHandler) Slow(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// Health handles GET /api/v1/test/health - health check endpoint for Docker and load balancers.
-func (h *TestHandler) Health(w http.ResponseWriter, r *http.Request) {
-	response := map[string]interface{}{
-		"status":    "healthy",
-		"timestamp": time.Now().UTC().Format(time.RFC3339),
-		"service":   "backend-path-api",
-		"version":   "1.0.0",
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
-}
-
 // CacheTest handles GET /api/v1/test/cache - demonstrates caching with timestamp
 func (h *TestHandler) CacheTest(w http.ResponseWriter, r *http.Request) {
 	response := map[string]interface{}{
@@ -126,7 +113,6 @@ func (h *TestHandler) RegisterRoutes(r chi.Router) {
 	r.Get("/panic", h.Panic)
 	r.Get("/error", h.Error)
 	r.Get("/slow", h.Slow)
-	r.Get("/health", h.Health)
 	r.Get("/cache", h.CacheTest)
 	r.Get("/generate-token", h.GenerateTestToken)
 }
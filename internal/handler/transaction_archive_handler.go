@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// TransactionArchiveHandler handles HTTP requests for archived transactions
+type TransactionArchiveHandler struct {
+	archiveService domain.TransactionArchiveService
+}
+
+// NewTransactionArchiveHandler creates a new TransactionArchiveHandler
+func NewTransactionArchiveHandler(archiveService domain.TransactionArchiveService) *TransactionArchiveHandler {
+	return &TransactionArchiveHandler{
+		archiveService: archiveService,
+	}
+}
+
+// RegisterRoutes registers the archive routes; all of them are admin-only
+func (h *TransactionArchiveHandler) RegisterRoutes(r chi.Router) {
+	admin := r.With(middleware.RequireRoles("admin"))
+	admin.Get("/admin/archive/transactions", h.Search)
+	admin.Get("/admin/archive/transactions/{id}", h.Get)
+	admin.Post("/admin/archive/run", h.Run)
+}
+
+// Search handles querying archived transactions.
+func (h *TransactionArchiveHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := domain.ArchivedTransactionFilter{Limit: 50}
+
+	if v := q.Get("user_id"); v != "" {
+		userID, err := strconv.Atoi(v)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+		filter.UserID = &userID
+	}
+	if v := q.Get("from"); v != "" {
+		from, _, err := parseAuditTime(v)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid from, use RFC 3339 or YYYY-MM-DD")
+			return
+		}
+		filter.From = &from
+	}
+	if v := q.Get("to"); v != "" {
+		to, dateOnly, err := parseAuditTime(v)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid to, use RFC 3339 or YYYY-MM-DD")
+			return
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 200 {
+			h.respondError(w, r, http.StatusBadRequest, "limit must be between 1 and 200")
+			return
+		}
+		filter.Limit = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			h.respondError(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+		filter.Offset = n
+	}
+
+	transactions, err := h.archiveService.SearchArchived(r.Context(), filter)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to search archived transactions")
+		return
+	}
+	if transactions == nil {
+		transactions = []*domain.ArchivedTransaction{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transactions)
+}
+
+// Get handles retrieving one archived transaction
+func (h *TransactionArchiveHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid transaction ID")
+		return
+	}
+
+	tx, err := h.archiveService.GetArchived(r.Context(), id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get archived transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tx)
+}
+
+// Run handles archiving eligible transactions now instead of waiting for the next scheduled run
+func (h *TransactionArchiveHandler) Run(w http.ResponseWriter, r *http.Request) {
+	archived, err := h.archiveService.Archive(r.Context())
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to archive transactions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"archived": archived})
+}
+
+// respondError is a helper method to respond with error
+func (h *TransactionArchiveHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// TransactionExportHandler handles downloads of a user's transaction history
+type TransactionExportHandler struct {
+	exportService domain.TransactionExportService
+}
+
+// NewTransactionExportHandler creates a new TransactionExportHandler
+func NewTransactionExportHandler(exportService domain.TransactionExportService) *TransactionExportHandler {
+	return &TransactionExportHandler{
+		exportService: exportService,
+	}
+}
+
+// RegisterRoutes registers the transaction export route
+func (h *TransactionExportHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/transactions/user/{user_id}/export", h.Export)
+}
+
+// Export handles GET /transactions/user/{user_id}/export.
+func (h *TransactionExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	if !authorizeUser(w, r, userID, "you do not have permission to export this user's transactions") {
+		return
+	}
+
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	to := time.Now().UTC()
+	if v := q.Get("to"); v != "" {
+		t, dateOnly, err := parseAuditTime(v)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid to, use RFC 3339 or YYYY-MM-DD")
+			return
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = t
+	}
+	from := to.AddDate(-1, 0, 0)
+	if v := q.Get("from"); v != "" {
+		if from, _, err = parseAuditTime(v); err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid from, use RFC 3339 or YYYY-MM-DD")
+			return
+		}
+	}
+
+	export, err := h.exportService.Prepare(r.Context(), userID, format, from, to)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to prepare transaction export")
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.FileName))
+	// The status is sent with the first bytes, so a failure past this point
+	// can only cut the file short
+	if err := h.exportService.Write(r.Context(), w, export); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Int("user_id", userID).Msg("Failed to write transaction export")
+	}
+}
+
+// respondError is a helper method to respond with error
+func (h *TransactionExportHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -1,28 +1,113 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
+
 	"github.com/melihgurlek/backend-path/internal/domain"
 	"github.com/melihgurlek/backend-path/internal/middleware"
 )
 
+// idempotencyKeyHeader lets clients safely retry a money movement request
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength bounds an Idempotency-Key.
+const maxIdempotencyKeyLength = 200
+
+const (
+	// defaultUserTransactionsLimit is the page size of a user's transaction listing that asks for none
+	defaultUserTransactionsLimit = 100
+	// maxUserTransactionsLimit caps the page size of a user's transaction listing
+	maxUserTransactionsLimit = 1000
+)
+
 // TransactionHandler handles transaction-related HTTP requests.
 type TransactionHandler struct {
-	service      domain.TransactionService
-	limitService domain.TransactionLimitService
+	service domain.TransactionService
 }
 
 // NewTransactionHandler creates a new TransactionHandler.
-func NewTransactionHandler(service domain.TransactionService, limitService domain.TransactionLimitService) *TransactionHandler {
-	return &TransactionHandler{
-		service:      service,
-		limitService: limitService,
-	}
+func NewTransactionHandler(service domain.TransactionService) *TransactionHandler {
+	return &TransactionHandler{service: service}
+}
+
+// errInvalidRequestBody is returned by request adapters for bodies that are not valid JSON
+var errInvalidRequestBody = &domain.ValidationError{Msg: "invalid request body"}
+
+// accountAmountRequest is a version-neutral credit or debit request
+type accountAmountRequest struct {
+	UserID int
+	Amount float64
+}
+
+// accountAmountRequests decodes credit and debit requests of each API version.
+var accountAmountRequests = requestAdapters[accountAmountRequest]{
+	middleware.APIVersion1: func(r *http.Request) (accountAmountRequest, error) {
+		var req struct {
+			UserID int     `json:"user_id"`
+			Amount float64 `json:"amount"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return accountAmountRequest{}, errInvalidRequestBody
+		}
+		return accountAmountRequest{UserID: req.UserID, Amount: req.Amount}, nil
+	},
+	middleware.APIVersion2: func(r *http.Request) (accountAmountRequest, error) {
+		var req struct {
+			UserID int    `json:"user_id"`
+			Amount string `json:"amount"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return accountAmountRequest{}, errInvalidRequestBody
+		}
+		amount, err := parseDecimalAmount(req.Amount)
+		if err != nil {
+			return accountAmountRequest{}, &domain.ValidationError{Msg: err.Error()}
+		}
+		return accountAmountRequest{UserID: req.UserID, Amount: amount}, nil
+	},
+}
+
+// transferRequest is a version-neutral transfer request
+type transferRequest struct {
+	FromUserID int
+	ToUserID   int
+	Amount     float64
+}
+
+// transferRequests decodes transfer requests: v1 takes a JSON number amount, v2 a decimal string
+var transferRequests = requestAdapters[transferRequest]{
+	middleware.APIVersion1: func(r *http.Request) (transferRequest, error) {
+		var req struct {
+			FromUserID int     `json:"from_user_id"`
+			ToUserID   int     `json:"to_user_id"`
+			Amount     float64 `json:"amount"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return transferRequest{}, errInvalidRequestBody
+		}
+		return transferRequest{FromUserID: req.FromUserID, ToUserID: req.ToUserID, Amount: req.Amount}, nil
+	},
+	middleware.APIVersion2: func(r *http.Request) (transferRequest, error) {
+		var req struct {
+			FromUserID int    `json:"from_user_id"`
+			ToUserID   int    `json:"to_user_id"`
+			Amount     string `json:"amount"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return transferRequest{}, errInvalidRequestBody
+		}
+		amount, err := parseDecimalAmount(req.Amount)
+		if err != nil {
+			return transferRequest{}, &domain.ValidationError{Msg: err.Error()}
+		}
+		return transferRequest{FromUserID: req.FromUserID, ToUserID: req.ToUserID, Amount: amount}, nil
+	},
 }
 
 func (h *TransactionHandler) RegisterRoutes(r chi.Router) {
@@ -30,6 +115,7 @@ func (h *TransactionHandler) RegisterRoutes(r chi.Router) {
 	r.Post("/transactions/debit", h.Debit)
 	r.Post("/transactions/transfer", h.Transfer)
 	r.Get("/transactions/history", h.ListAllTransactions)
+	r.Get("/transactions/search", h.SearchTransactions)
 	r.Get("/transactions/{id}", h.GetTransactionByID)
 	r.Get("/transactions/user/{user_id}", h.ListUserTransactions)
 }
@@ -37,27 +123,33 @@ func (h *TransactionHandler) RegisterRoutes(r chi.Router) {
 func (h *TransactionHandler) Credit(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.UserClaimsFromContext(r.Context())
 	if !ok {
-		h.respondError(w, http.StatusUnauthorized, "invalid token claims")
+		h.respondError(w, r, http.StatusUnauthorized, "invalid token claims")
 		return
 	}
 
 	// Only admins can credit an account.
 	if claims.Role != "admin" {
-		h.respondError(w, http.StatusForbidden, "you do not have permission to perform this action")
+		h.respondError(w, r, http.StatusForbidden, "you do not have permission to perform this action")
 		return
 	}
 
-	var req struct {
-		UserID int     `json:"user_id"`
-		Amount float64 `json:"amount"`
+	req, err := accountAmountRequests.decode(r)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "invalid request body")
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid request body")
+
+	key, ok := h.idempotencyKey(w, r, claims)
+	if !ok {
+		return
+	}
+	tx, err := h.service.Credit(h.requesterContext(r, claims), req.UserID, float64(req.Amount), key)
+	if errors.Is(err, domain.ErrTransactionHeld) {
+		h.respondHeld(w, tx)
 		return
 	}
-	err := h.service.Credit(req.UserID, float64(req.Amount))
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to credit")
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -68,29 +160,33 @@ func (h *TransactionHandler) Credit(w http.ResponseWriter, r *http.Request) {
 func (h *TransactionHandler) Debit(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.UserClaimsFromContext(r.Context())
 	if !ok {
-		h.respondError(w, http.StatusUnauthorized, "invalid token claims")
+		h.respondError(w, r, http.StatusUnauthorized, "invalid token claims")
 		return
 	}
 
-	var req struct {
-		UserID int     `json:"user_id"`
-		Amount float64 `json:"amount"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid request body")
+	req, err := accountAmountRequests.decode(r)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "invalid request body")
 		return
 	}
 
 	// A user can only transfer from their own account, unless they are an admin.
 	if claims.Role != "admin" && claims.UserID != strconv.Itoa(req.UserID) {
-		h.respondError(w, http.StatusForbidden, "you can only debit your own account")
+		h.respondError(w, r, http.StatusForbidden, "you can only debit your own account")
 		return
 	}
 
-	err := h.service.Debit(req.UserID, float64(req.Amount))
+	key, ok := h.idempotencyKey(w, r, claims)
+	if !ok {
+		return
+	}
+	tx, err := h.service.Debit(h.requesterContext(r, claims), req.UserID, float64(req.Amount), key)
+	if errors.Is(err, domain.ErrTransactionHeld) {
+		h.respondHeld(w, tx)
+		return
+	}
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to debit")
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -100,35 +196,33 @@ func (h *TransactionHandler) Debit(w http.ResponseWriter, r *http.Request) {
 func (h *TransactionHandler) Transfer(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.UserClaimsFromContext(r.Context())
 	if !ok {
-		h.respondError(w, http.StatusUnauthorized, "invalid token claims")
+		h.respondError(w, r, http.StatusUnauthorized, "invalid token claims")
 		return
 	}
 
-	var req struct {
-		FromUserID int     `json:"from_user_id"`
-		ToUserID   int     `json:"to_user_id"`
-		Amount     float64 `json:"amount"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid request body")
+	req, err := transferRequests.decode(r)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "invalid request body")
 		return
 	}
 
 	// A user can only transfer from their own account, unless they are an admin.
 	if claims.Role != "admin" && claims.UserID != strconv.Itoa(req.FromUserID) {
-		h.respondError(w, http.StatusForbidden, "you can only transfer from your own account")
+		h.respondError(w, r, http.StatusForbidden, "you can only transfer from your own account")
 		return
 	}
 
-	err := h.limitService.CheckAndRecordTransaction(r.Context(), req.FromUserID, req.Amount, "USD", time.Now())
-	if err != nil {
-		h.respondError(w, http.StatusForbidden, err.Error())
+	key, ok := h.idempotencyKey(w, r, claims)
+	if !ok {
+		return
+	}
+	tx, err := h.service.Transfer(h.requesterContext(r, claims), req.FromUserID, req.ToUserID, float64(req.Amount), key)
+	if errors.Is(err, domain.ErrTransactionHeld) {
+		h.respondHeld(w, tx)
 		return
 	}
-
-	err = h.service.Transfer(req.FromUserID, req.ToUserID, float64(req.Amount))
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to transfer")
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -139,7 +233,7 @@ func (h *TransactionHandler) Transfer(w http.ResponseWriter, r *http.Request) {
 func (h *TransactionHandler) ListAllTransactions(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.UserClaimsFromContext(r.Context())
 	if !ok {
-		h.respondError(w, http.StatusUnauthorized, "invalid token claims")
+		h.respondError(w, r, http.StatusUnauthorized, "invalid token claims")
 		return
 	}
 
@@ -163,13 +257,13 @@ func (h *TransactionHandler) ListAllTransactions(w http.ResponseWriter, r *http.
 	}
 
 	if claims.Role != "admin" {
-		h.respondError(w, http.StatusForbidden, "you do not have permission to list transactions")
+		h.respondError(w, r, http.StatusForbidden, "you do not have permission to list transactions")
 		return
 	}
 
 	transactions, err := h.service.ListAllTransactions(r.Context(), limit, offset)
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to list transactions")
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -180,60 +274,123 @@ func (h *TransactionHandler) GetTransactionByID(w http.ResponseWriter, r *http.R
 
 	claims, ok := middleware.UserClaimsFromContext(r.Context())
 	if !ok {
-		h.respondError(w, http.StatusUnauthorized, "invalid token claims")
+		h.respondError(w, r, http.StatusUnauthorized, "invalid token claims")
 		return
 	}
 
 	id := chi.URLParam(r, "id")
 	idInt, err := strconv.Atoi(id)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid transaction id")
+		h.respondError(w, r, http.StatusBadRequest, "invalid transaction id")
 		return
 	}
 
 	if claims.Role != "admin" && claims.UserID != strconv.Itoa(idInt) {
-		h.respondError(w, http.StatusForbidden, "you do not have permission to view this transaction")
+		h.respondError(w, r, http.StatusForbidden, "you do not have permission to view this transaction")
 		return
 	}
 
-	transaction, err := h.service.GetTransaction(idInt)
+	transaction, err := h.service.GetTransaction(r.Context(), idInt)
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to get transaction")
 		return
 	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(transaction)
 }
 
+// ListUserTransactions handles GET /transactions/user/{user_id}.
 func (h *TransactionHandler) ListUserTransactions(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.UserClaimsFromContext(r.Context())
 	if !ok {
-		h.respondError(w, http.StatusUnauthorized, "invalid token claims")
+		h.respondError(w, r, http.StatusUnauthorized, "invalid token claims")
 		return
 	}
 
 	targetIDStr := chi.URLParam(r, "user_id")
 	targetID, err := strconv.Atoi(targetIDStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid user id")
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
 		return
 	}
 
 	// A user can only list their own transactions, unless they are an admin.
 	if claims.Role != "admin" && claims.UserID != strconv.Itoa(targetID) {
-		h.respondError(w, http.StatusForbidden, "you do not have permission to view these transactions")
+		h.respondError(w, r, http.StatusForbidden, "you do not have permission to view these transactions")
+		return
+	}
+
+	q := r.URL.Query()
+	limit, offset, err := parsePage(q, defaultUserTransactionsLimit, maxUserTransactionsLimit)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	includeTotal, err := wantsTotal(q)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	transactions, err := h.service.ListUserTransactions(targetID)
+	transactions, err := h.service.ListUserTransactions(r.Context(), targetID, limit, offset)
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to list transactions")
 		return
 	}
+	if includeTotal {
+		total, err := h.service.CountUserTransactions(r.Context(), targetID)
+		if err != nil {
+			middleware.RespondServiceError(w, r, err, "failed to count transactions")
+			return
+		}
+		setTotalCount(w, total)
+	}
+	if transactions == nil {
+		transactions = []*domain.Transaction{}
+	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(transactions)
 }
-func (h *TransactionHandler) respondError(w http.ResponseWriter, code int, msg string) {
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+
+// idempotencyKey returns the Idempotency-Key of r scoped to the caller, or "" if it has none.
+func (h *TransactionHandler) idempotencyKey(w http.ResponseWriter, r *http.Request, claims *middleware.UserClaims) (string, bool) {
+	key := r.Header.Get(idempotencyKeyHeader)
+	if key == "" {
+		return "", true
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		h.respondError(w, r, http.StatusBadRequest, "Idempotency-Key must be at most 200 characters")
+		return "", false
+	}
+	callerID, err := strconv.Atoi(claims.UserID)
+	if err != nil {
+		h.respondError(w, r, http.StatusUnauthorized, "invalid user_id in token")
+		return "", false
+	}
+	return domain.ClientIdempotencyKey(callerID, key), true
+}
+
+// requesterContext returns the context of r recording the caller as the requester.
+func (h *TransactionHandler) requesterContext(r *http.Request, claims *middleware.UserClaims) context.Context {
+	// Without a numeric user ID the payer stands in as the requester
+	callerID, err := strconv.Atoi(claims.UserID)
+	if err != nil {
+		return r.Context()
+	}
+	return domain.WithRequester(r.Context(), callerID)
+}
+
+// respondHeld answers a money movement held for approval instead of run
+func (h *TransactionHandler) respondHeld(w http.ResponseWriter, tx *domain.Transaction) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":        "transaction requires approval",
+		"transaction_id": tx.ID,
+		"status":         tx.Status,
+	})
+}
+
+func (h *TransactionHandler) respondError(w http.ResponseWriter, r *http.Request, code int, msg string) {
+	middleware.WriteProblem(w, r, code, msg)
 }
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// TransactionImportHandler handles imports of historical transactions
+type TransactionImportHandler struct {
+	importService domain.TransactionImportService
+}
+
+// NewTransactionImportHandler creates a new TransactionImportHandler
+func NewTransactionImportHandler(importService domain.TransactionImportService) *TransactionImportHandler {
+	return &TransactionImportHandler{
+		importService: importService,
+	}
+}
+
+// RegisterRoutes registers the transaction import route.
+func (h *TransactionImportHandler) RegisterRoutes(r chi.Router) {
+	r.With(middleware.RequireRoles("admin")).Post("/transactions/import", h.Import)
+}
+
+// Import handles POST /transactions/import?user_id=N.
+func (h *TransactionImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	userID, err := strconv.Atoi(q.Get("user_id"))
+	if err != nil || userID <= 0 {
+		h.respondError(w, r, http.StatusBadRequest, "user_id is required")
+		return
+	}
+	dryRun := false
+	if v := q.Get("dry_run"); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid dry_run")
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxCSVUploadBytes)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "request must be multipart/form-data")
+		return
+	}
+
+	// Stream the file part straight into the CSV parser instead of buffering the upload
+	var file io.Reader
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid multipart body")
+			return
+		}
+		if part.FormName() == "file" {
+			file = part
+			break
+		}
+	}
+	if file == nil {
+		h.respondError(w, r, http.StatusBadRequest, "missing file field")
+		return
+	}
+
+	report, err := h.importService.Import(r.Context(), actorID, userID, file, dryRun)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to import transactions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// respondError is a helper method to respond with error
+func (h *TransactionImportHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -6,10 +6,11 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
 	"github.com/melihgurlek/backend-path/internal/domain"
 	"github.com/melihgurlek/backend-path/internal/middleware"
-
-	"github.com/go-chi/chi/v5"
 )
 
 type TransactionLimitHandler struct {
@@ -20,35 +21,36 @@ func NewTransactionLimitHandler(service domain.TransactionLimitService) *Transac
 	return &TransactionLimitHandler{Service: service}
 }
 
+// RegisterRoutes registers the limit rule routes.
 func (h *TransactionLimitHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/users/{userID}/limits", func(r chi.Router) {
 		r.Get("/", h.ListRules)
-		r.Post("/", h.AddRule)
-		r.Delete("/{ruleID}", h.RemoveRule)
+		r.With(middleware.RequireRoles("admin")).Post("/", h.AddRule)
+		r.With(middleware.RequireRoles("admin")).Delete("/{ruleID}", h.RemoveRule)
 	})
 }
 
 func (h *TransactionLimitHandler) ListRules(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.UserClaimsFromContext(r.Context())
 	if !ok {
-		http.Error(w, "invalid token claims", http.StatusUnauthorized)
+		h.respondError(w, r, http.StatusUnauthorized, "invalid token claims")
 		return
 	}
 
 	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
 	if err != nil {
-		http.Error(w, "invalid userID", http.StatusBadRequest)
+		h.respondError(w, r, http.StatusBadRequest, "invalid userID")
 		return
 	}
 
-	if claims.Role != "admin" && claims.UserID != strconv.Itoa(userID) {
-		http.Error(w, "you do not have permission to list rules", http.StatusForbidden)
+	if !middleware.IsAdminOrSelf(claims, userID) {
+		h.respondError(w, r, http.StatusForbidden, "you do not have permission to list rules")
 		return
 	}
 
 	rules, err := h.Service.ListRules(r.Context(), userID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		middleware.RespondServiceError(w, r, err, "failed to list rules")
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -58,6 +60,7 @@ func (h *TransactionLimitHandler) ListRules(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(rules)
 }
 
+// addRuleRequest is the body of a new limit rule.
 type addRuleRequest struct {
 	RuleType    string        `json:"rule_type"`
 	LimitAmount float64       `json:"limit_amount"`
@@ -67,34 +70,22 @@ type addRuleRequest struct {
 }
 
 func (h *TransactionLimitHandler) AddRule(w http.ResponseWriter, r *http.Request) {
-	claims, ok := middleware.UserClaimsFromContext(r.Context())
-	if !ok {
-		http.Error(w, "invalid token claims", http.StatusUnauthorized)
-		return
-	}
-
 	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
 	if err != nil {
-		http.Error(w, "invalid userID", http.StatusBadRequest)
-		return
-	}
-
-	if claims.Role != "admin" && claims.UserID != strconv.Itoa(userID) {
-		http.Error(w, "you do not have permission to add rules", http.StatusForbidden)
+		h.respondError(w, r, http.StatusBadRequest, "invalid userID")
 		return
 	}
 
 	var req addRuleRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
 	if req.RuleType == "" || req.LimitAmount <= 0 {
-		http.Error(w, "missing or invalid rule_type or limit_amount", http.StatusBadRequest)
+		h.respondError(w, r, http.StatusBadRequest, "missing or invalid rule_type or limit_amount")
 		return
 	}
 	rule := domain.TransactionLimitRule{
-		ID:          "",
 		UserID:      userID,
 		RuleType:    domain.RuleType(req.RuleType),
 		LimitAmount: req.LimitAmount,
@@ -104,35 +95,36 @@ func (h *TransactionLimitHandler) AddRule(w http.ResponseWriter, r *http.Request
 	}
 	rule, err = h.Service.AddRule(r.Context(), rule)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		middleware.RespondServiceError(w, r, err, "failed to add rule")
 		return
 	}
-	json.NewEncoder(w).Encode(rule)
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
 }
 
+// RemoveRule deletes one of the user's rules; a rule of another user is not found
 func (h *TransactionLimitHandler) RemoveRule(w http.ResponseWriter, r *http.Request) {
-	claims, ok := middleware.UserClaimsFromContext(r.Context())
-	if !ok {
-		http.Error(w, "invalid token claims", http.StatusUnauthorized)
-		return
-	}
-
 	userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
 	if err != nil {
-		http.Error(w, "invalid userID", http.StatusBadRequest)
+		h.respondError(w, r, http.StatusBadRequest, "invalid userID")
 		return
 	}
 
-	if claims.Role != "admin" && claims.UserID != strconv.Itoa(userID) {
-		http.Error(w, "you do not have permission to remove rules", http.StatusForbidden)
+	ruleID := chi.URLParam(r, "ruleID")
+	if _, err := uuid.Parse(ruleID); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid ruleID")
 		return
 	}
 
-	ruleID := chi.URLParam(r, "ruleID")
 	if err := h.Service.RemoveRule(r.Context(), userID, ruleID); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		middleware.RespondServiceError(w, r, err, "failed to remove rule")
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// respondError is a helper method to respond with error
+func (h *TransactionLimitHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+const (
+	// defaultSearchLimit is the page size of a search that asks for none
+	defaultSearchLimit = 50
+	// maxSearchLimit caps the page size of a search
+	maxSearchLimit = 200
+)
+
+var (
+	transactionTypes    = []string{"credit", "debit", "transfer"}
+	transactionStatuses = []string{"pending", "pending_approval", "completed", "failed", "rejected"}
+)
+
+// SearchTransactions handles GET /transactions/search.
+func (h *TransactionHandler) SearchTransactions(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTransactionFilter(r.URL.Query())
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !isAdmin(r) {
+		self, ok := callerID(w, r)
+		if !ok {
+			return
+		}
+		if filter.UserID != nil && *filter.UserID != self {
+			h.respondError(w, r, http.StatusForbidden, "you do not have permission to search these transactions")
+			return
+		}
+		filter.UserID = &self
+	}
+
+	transactions, err := h.service.SearchTransactions(r.Context(), filter)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to search transactions")
+		return
+	}
+	if transactions == nil {
+		transactions = []*domain.Transaction{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transactions)
+}
+
+// parseTransactionFilter reads the search parameters of SearchTransactions
+func parseTransactionFilter(q url.Values) (domain.TransactionFilter, error) {
+	filter := domain.TransactionFilter{Query: strings.TrimSpace(q.Get("q"))}
+
+	for name, dst := range map[string]**int{"user_id": &filter.UserID, "counterparty_id": &filter.CounterpartyID} {
+		if v := q.Get(name); v != "" {
+			id, err := strconv.Atoi(v)
+			if err != nil || id <= 0 {
+				return filter, fmt.Errorf("invalid %s", name)
+			}
+			*dst = &id
+		}
+	}
+	for name, dst := range map[string]**float64{"min_amount": &filter.MinAmount, "max_amount": &filter.MaxAmount} {
+		if v := q.Get(name); v != "" {
+			amount, err := strconv.ParseFloat(v, 64)
+			if err != nil || amount < 0 {
+				return filter, fmt.Errorf("%s must be a non-negative number", name)
+			}
+			*dst = &amount
+		}
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return filter, errors.New("min_amount must not exceed max_amount")
+	}
+
+	var err error
+	if filter.Types, err = parseList(q.Get("type"), transactionTypes); err != nil {
+		return filter, fmt.Errorf("type %w", err)
+	}
+	if filter.Statuses, err = parseList(q.Get("status"), transactionStatuses); err != nil {
+		return filter, fmt.Errorf("status %w", err)
+	}
+
+	if v := q.Get("from"); v != "" {
+		from, _, err := parseAuditTime(v)
+		if err != nil {
+			return filter, errors.New("invalid from, use RFC 3339 or YYYY-MM-DD")
+		}
+		filter.From = &from
+	}
+	if v := q.Get("to"); v != "" {
+		to, dateOnly, err := parseAuditTime(v)
+		if err != nil {
+			return filter, errors.New("invalid to, use RFC 3339 or YYYY-MM-DD")
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+
+	if filter.Limit, filter.Offset, err = parsePage(q, defaultSearchLimit, maxSearchLimit); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// parseList splits a comma-separated parameter, checking every value is allowed
+func parseList(v string, allowed []string) ([]string, error) {
+	if v == "" {
+		return nil, nil
+	}
+	values := strings.Split(v, ",")
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+		if !slices.Contains(allowed, values[i]) {
+			return nil, fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+		}
+	}
+	return values, nil
+}
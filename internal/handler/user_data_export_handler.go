@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// UserDataExportHandler hands users a copy of their personal data
+type UserDataExportHandler struct {
+	service domain.UserDataExportService
+}
+
+// NewUserDataExportHandler creates a new UserDataExportHandler
+func NewUserDataExportHandler(service domain.UserDataExportService) *UserDataExportHandler {
+	return &UserDataExportHandler{service: service}
+}
+
+// RegisterRoutes registers the data export route
+func (h *UserDataExportHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/users/{id}/data-export", h.ExportUserData)
+}
+
+// ExportUserData handles GET /users/{id}/data-export.
+func (h *UserDataExportHandler) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	if !authorizeUser(w, r, userID, "you do not have permission to export this user's data") {
+		return
+	}
+
+	export, err := h.service.ExportUserData(r.Context(), actorID, userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to export user data")
+		return
+	}
+	if export == nil {
+		h.respondError(w, r, http.StatusNotFound, "user not found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%d-data.json"`, userID))
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(export)
+}
+
+// respondError is a helper method to respond with error
+func (h *UserDataExportHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
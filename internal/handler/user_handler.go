@@ -1,18 +1,22 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/melihgurlek/backend-path/internal/domain"
 	"github.com/melihgurlek/backend-path/internal/middleware"
 	"github.com/melihgurlek/backend-path/pkg"
-	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
 )
 
 // RegisterRequest represents the request body for user registration.
@@ -20,13 +24,36 @@ type RegisterRequest struct {
 	Username string `json:"username"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	// ReferralCode is the code of the user who referred this one, if any
+	ReferralCode string `json:"referral_code,omitempty"`
 }
 
 // UpdateRequest represents the request body for user updates.
 type UpdateRequest struct {
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Phone     string `json:"phone"`  // E.164, such as +905551234567
+	Locale    string `json:"locale"` // BCP 47 language tag, such as en-US
+}
+
+// PatchUserRequest represents the request body for partial user updates.
+type PatchUserRequest struct {
+	Username  *string `json:"username,omitempty"`
+	Email     *string `json:"email,omitempty"`
+	Role      *string `json:"role,omitempty"`
+	FirstName *string `json:"first_name,omitempty"`
+	LastName  *string `json:"last_name,omitempty"`
+	Phone     *string `json:"phone,omitempty"`
+	Locale    *string `json:"locale,omitempty"`
+}
+
+// ChangePasswordRequest represents the request body for a password change.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
 }
 
 // LoginRequest represents the request body for user login.
@@ -35,19 +62,49 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// VerifyLoginRequest represents the request body completing a login from a new device.
+type VerifyLoginRequest struct {
+	ChallengeID string `json:"challenge_id"`
+	Code        string `json:"code"`
+}
+
+// IssueTokenRequest represents the request body for a token limited to scopes.
+type IssueTokenRequest struct {
+	Scopes []string `json:"scopes"`
+}
+
+// Validate checks that the scopes are known.
+func (req *IssueTokenRequest) Validate() error {
+	if len(req.Scopes) == 0 {
+		return errors.New("scopes are required")
+	}
+	for _, scope := range req.Scopes {
+		if !middleware.IsKnownScope(scope) {
+			return fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+	return nil
+}
+
+// deviceIDHeader carries an identifier the client app keeps for its installation.
+const deviceIDHeader = "X-Device-ID"
+
+// maxDeviceIDLength bounds the device identifier a client sends
+const maxDeviceIDLength = 128
+
 // UserHandler handles user-related HTTP requests.
 type UserHandler struct {
-	service   domain.UserService
-	jwtSecret string
-	cache     *redis.Client
+	service domain.UserService
+	jwtKeys pkg.KeySource
+	tokens  domain.TokenStore
 }
 
 // NewUserHandler creates a new UserHandler.
-func NewUserHandler(service domain.UserService, jwtSecret string, cache *redis.Client) *UserHandler {
+func NewUserHandler(service domain.UserService, jwtKeys pkg.KeySource, tokens domain.TokenStore) *UserHandler {
 	return &UserHandler{
-		service:   service,
-		jwtSecret: jwtSecret,
-		cache:     cache,
+		service: service,
+		jwtKeys: jwtKeys,
+		tokens:  tokens,
 	}
 }
 
@@ -55,13 +112,24 @@ func NewUserHandler(service domain.UserService, jwtSecret string, cache *redis.C
 func (h *UserHandler) RegisterRoutes(r chi.Router) {
 	r.Post("/auth/register", h.Register)
 	r.Post("/auth/login", h.Login)
+	r.Post("/auth/login/verify", h.VerifyLogin)
 	r.Post("/auth/logout", h.Logout)
+	r.Post("/auth/tokens", h.IssueToken)
 
 	// User CRUD
 	r.Get("/users", h.ListUsers)
 	r.Get("/users/{id}", h.GetUserByID)
 	r.Put("/users/{id}", h.UpdateUser)
+	r.Patch("/users/{id}", h.PatchUser)
+	r.Get("/users/{id}/avatar", h.GetAvatar)
+	r.Put("/users/{id}/avatar", h.UploadAvatar)
+	r.Delete("/users/{id}/avatar", h.DeleteAvatar)
+	r.Get("/users/{id}/logins", h.ListLogins)
+	r.Get("/users/{id}/devices", h.ListDevices)
+	r.Delete("/users/{id}/devices/{fingerprint}", h.RevokeDevice)
 	r.Delete("/users/{id}", h.DeleteUser)
+	r.Put("/users/{id}/password", h.ChangePassword)
+	r.Post("/users/{id}/erase", h.EraseUser)
 }
 
 // Register handles user registration.
@@ -71,9 +139,9 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 		panic("could not retrieve validated body")
 	}
 
-	user, err := h.service.Register(req.Username, req.Email, req.Password)
+	user, err := h.service.Register(r.Context(), req.Username, req.Email, req.Password, req.ReferralCode)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to register user")
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -85,6 +153,24 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// LoginUsername returns the username a validated login request is for.
+func LoginUsername(r *http.Request) string {
+	req, ok := middleware.GetValidatedBody[*LoginRequest](r.Context())
+	if !ok {
+		return ""
+	}
+	return req.Username
+}
+
+// loginClient describes the client making r; the IP is the connection's peer address.
+func loginClient(r *http.Request) domain.LoginClient {
+	deviceID := strings.TrimSpace(r.Header.Get(deviceIDHeader))
+	if len(deviceID) > maxDeviceIDLength {
+		deviceID = deviceID[:maxDeviceIDLength]
+	}
+	return domain.LoginClient{IP: middleware.ClientIP(r), UserAgent: r.UserAgent(), DeviceID: deviceID}
+}
+
 // Login handles user login.
 func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	req, ok := middleware.GetValidatedBody[*LoginRequest](r.Context())
@@ -92,16 +178,55 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		panic("could not retrieve validated body")
 	}
 
-	user, err := h.service.Login(req.Username, req.Password)
+	user, err := h.service.Login(r.Context(), req.Username, req.Password, loginClient(r))
+	var stepUp *domain.StepUpRequiredError
+	if errors.As(err, &stepUp) {
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"verification_required": true,
+			"challenge_id":          stepUp.ChallengeID,
+			"method":                stepUp.Method,
+			"expires_at":            stepUp.ExpiresAt,
+		})
+		return
+	}
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to log in")
+		return
+	}
+	h.respondWithToken(w, r, user)
+}
+
+// VerifyLogin handles POST /auth/login/verify, completing a login from an untrusted device.
+func (h *UserHandler) VerifyLogin(w http.ResponseWriter, r *http.Request) {
+	req, ok := middleware.GetValidatedBody[*VerifyLoginRequest](r.Context())
+	if !ok {
+		panic("could not retrieve validated body")
+	}
+	if req.ChallengeID == "" || req.Code == "" {
+		h.respondError(w, r, http.StatusBadRequest, "challenge_id and code are required")
+		return
+	}
+
+	user, err := h.service.VerifyLogin(r.Context(), req.ChallengeID, req.Code, loginClient(r))
 	if err != nil {
-		h.respondError(w, http.StatusUnauthorized, err.Error())
+		// The code tells a wrong code, worth retrying, from a challenge to start over
+		var domainErr *domain.Error
+		if errors.As(err, &domainErr) {
+			middleware.NewProblem(r, http.StatusUnauthorized, domainErr.Msg).WithCode(domainErr.Code).Write(w)
+			return
+		}
+		middleware.RespondServiceError(w, r, err, "failed to verify login")
 		return
 	}
+	h.respondWithToken(w, r, user)
+}
 
-	// Generate JWT token
-	token, err := pkg.GenerateToken(h.jwtSecret, strconv.Itoa(user.ID), user.Role)
+// respondWithToken answers a completed login with the user and a new token.
+func (h *UserHandler) respondWithToken(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	token, err := h.generateToken(r.Context(), user.ID, user.Role)
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, "failed to generate token")
+		middleware.RespondServiceError(w, r, err, "failed to generate token")
 		return
 	}
 
@@ -114,53 +239,62 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Logout handles token invalidation by adding its JTI to the denylist.
-func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	tokenString := r.Header.Get("Authorization")
-	if tokenString == "" || !strings.HasPrefix(tokenString, "Bearer ") {
-		h.respondError(w, http.StatusUnauthorized, "authorization header missing or malformed")
-		return
+// generateToken signs a token of the user's current version with the current key
+func (h *UserHandler) generateToken(ctx context.Context, userID int, role string, scopes ...string) (string, error) {
+	secret, err := pkg.SigningKey(ctx, h.jwtKeys)
+	if err != nil {
+		return "", err
 	}
-	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
-
-	// We don't need to fully validate the token, just parse its claims.
-	token, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+	version, err := h.tokens.TokenVersion(ctx, userID)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid token")
-		return
+		return "", err
 	}
+	return pkg.GenerateToken(secret, strconv.Itoa(userID), role, version, scopes...)
+}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
+// IssueToken handles POST /auth/tokens.
+func (h *UserHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := callerID(w, r)
 	if !ok {
-		h.respondError(w, http.StatusBadRequest, "invalid token claims")
 		return
 	}
+	claims, _ := middleware.UserClaimsFromContext(r.Context())
+	req, ok := middleware.GetValidatedBody[*IssueTokenRequest](r.Context())
+	if !ok {
+		panic("could not retrieve validated body")
+	}
+	for _, scope := range req.Scopes {
+		if !claims.HasScope(scope) {
+			middleware.NewProblem(r, http.StatusForbidden, "token lacks scope "+scope).WithCode("insufficient_scope").Write(w)
+			return
+		}
+	}
 
-	jti, ok := claims["jti"].(string)
-	expFloat, ok2 := claims["exp"].(float64)
-	if !ok || !ok2 {
-		h.respondError(w, http.StatusBadRequest, "token missing required claims")
+	token, err := h.generateToken(r.Context(), userID, claims.Role, req.Scopes...)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to generate token")
 		return
 	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"token":      token,
+		"scopes":     req.Scopes,
+		"expires_at": time.Now().Add(pkg.TokenTTL).UTC(),
+	})
+}
 
-	// Calculate remaining time until the token expires.
-	exp := time.Unix(int64(expFloat), 0)
-	ttl := time.Until(exp)
-
-	// If the token is already expired, no need to add it to the denylist.
-	if ttl <= 0 {
-		h.respondError(w, http.StatusOK, "token already expired")
+// Logout handles token invalidation by revoking the request's token.
+func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.UserClaimsFromContext(r.Context())
+	if !ok || claims.JTI == "" {
+		h.respondError(w, r, http.StatusUnauthorized, "invalid token claims")
 		return
 	}
 
-	// Add the token's JTI to the denylist in Redis with a TTL.
-	// The TTL ensures the denylist doesn't grow forever.
-	if h.cache != nil {
-		err = h.cache.Set(r.Context(), "denylist:"+jti, "true", ttl).Err()
-		if err != nil {
-			h.respondError(w, http.StatusInternalServerError, "could not log out")
-			return
-		}
+	// The store keeps the revocation only until the token expires anyway
+	if err := h.tokens.Revoke(r.Context(), claims.JTI, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		middleware.RespondServiceError(w, r, err, "could not log out")
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -171,156 +305,493 @@ func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
 func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.UserClaimsFromContext(r.Context())
 	if !ok {
-		h.respondError(w, http.StatusUnauthorized, "invalid token claims")
+		h.respondError(w, r, http.StatusUnauthorized, "invalid token claims")
 		return
 	}
 
 	if claims.Role != "admin" {
-		h.respondError(w, http.StatusForbidden, "you do not have permission to list users")
+		h.respondError(w, r, http.StatusForbidden, "you do not have permission to list users")
 		return
 	}
 
-	users, err := h.service.ListUsers()
+	filter, err := parseUserFilter(r.URL.Query())
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	users, err := h.service.ListUsers(r.Context(), filter)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list users")
+		return
+	}
+	total, err := h.service.CountUsers(r.Context(), filter)
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, "failed to list users")
+		middleware.RespondServiceError(w, r, err, "failed to count users")
 		return
 	}
-	var resp []map[string]interface{}
+	resp := make([]map[string]interface{}, 0, len(users))
 	for _, u := range users {
 		resp = append(resp, map[string]interface{}{
-			"id":       u.ID,
-			"username": u.Username,
-			"email":    u.Email,
-			"role":     u.Role,
+			"id":         u.ID,
+			"username":   u.Username,
+			"email":      u.Email,
+			"role":       u.Role,
+			"created_at": u.CreatedAt,
+			"erased":     u.IsErased(),
 		})
 	}
+	setTotalCount(w, total)
 	json.NewEncoder(w).Encode(resp)
 }
 
+// parseUserFilter reads the query parameters of the admin user list.
+func parseUserFilter(q url.Values) (domain.UserFilter, error) {
+	var filter domain.UserFilter
+	var err error
+	if filter.Limit, filter.Offset, err = parsePage(q, 50, 500); err != nil {
+		return filter, err
+	}
+	filter.Role = q.Get("role")
+	if v := q.Get("erased"); v != "" {
+		erased, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, errors.New("invalid erased")
+		}
+		filter.Erased = &erased
+	}
+	switch filter.Sort = q.Get("sort"); filter.Sort {
+	case "", "id", "created_at", "-created_at":
+	default:
+		return filter, errors.New("sort must be id, created_at or -created_at")
+	}
+	return filter, nil
+}
+
 // GetUserByID handles GET /users/{id}
 func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.UserClaimsFromContext(r.Context())
 	if !ok {
-		h.respondError(w, http.StatusUnauthorized, "invalid token claims")
+		h.respondError(w, r, http.StatusUnauthorized, "invalid token claims")
 		return
 	}
 
 	targetIDStr := chi.URLParam(r, "id")
 	targetID, err := strconv.Atoi(targetIDStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid user id")
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
 		return
 	}
 
 	// Use IsAdminOrSelf for authorization
 	if !middleware.IsAdminOrSelf(claims, targetID) {
-		h.respondError(w, http.StatusForbidden, "you do not have permission to view this user")
+		h.respondError(w, r, http.StatusForbidden, "you do not have permission to view this user")
 		return
 	}
 
-	user, err := h.service.GetUser(targetID) // Use targetID
+	user, err := h.service.GetUser(r.Context(), targetID) // Use targetID
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, "failed to get user")
+		middleware.RespondServiceError(w, r, err, "failed to get user")
 		return
 	}
 	if user == nil {
-		h.respondError(w, http.StatusNotFound, "user not found")
+		h.respondError(w, r, http.StatusNotFound, "user not found")
 		return
 	}
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"id":       user.ID,
-		"username": user.Username,
-		"email":    user.Email,
-		"role":     user.Role,
-	})
+	json.NewEncoder(w).Encode(userResponse(r, user))
 }
 
-// UpdateUser handles PUT /users/{id}
+// UpdateUser handles PUT /users/{id}, replacing the user's account and profile fields.
 func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
-	claims, ok := middleware.UserClaimsFromContext(r.Context())
+	req, ok := middleware.GetValidatedBody[*UpdateRequest](r.Context())
+	if !ok {
+		panic("could not retrieve validated body")
+	}
+	h.editUser(w, r, func(user *domain.User, isAdmin bool) error {
+		user.Username = req.Username
+		user.Email = req.Email
+		user.FirstName = req.FirstName
+		user.LastName = req.LastName
+		user.Phone = req.Phone
+		user.Locale = req.Locale
+
+		// **SECURITY FIX**: Prevents a regular user from making themselves an admin.
+		// Only an existing admin can change a user's role.
+		if isAdmin && req.Role != "" {
+			user.Role = req.Role
+		}
+		return nil
+	})
+}
+
+// PatchUser handles PATCH /users/{id}, changing only the fields present in the request.
+func (h *UserHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	req, ok := middleware.GetValidatedBody[*PatchUserRequest](r.Context())
 	if !ok {
-		h.respondError(w, http.StatusUnauthorized, "invalid token claims")
+		panic("could not retrieve validated body")
+	}
+	h.editUser(w, r, func(user *domain.User, isAdmin bool) error {
+		if req.Username != nil {
+			if strings.TrimSpace(*req.Username) == "" {
+				return &domain.ValidationError{Msg: "username must not be empty"}
+			}
+			user.Username = *req.Username
+		}
+		if req.Email != nil {
+			if strings.TrimSpace(*req.Email) == "" {
+				return &domain.ValidationError{Msg: "email must not be empty"}
+			}
+			user.Email = *req.Email
+		}
+		for _, f := range []struct {
+			value *string
+			field *string
+		}{
+			{req.FirstName, &user.FirstName},
+			{req.LastName, &user.LastName},
+			{req.Phone, &user.Phone},
+			{req.Locale, &user.Locale},
+		} {
+			if f.value != nil {
+				*f.field = *f.value
+			}
+		}
+		if req.Role != nil && *req.Role != "" {
+			if !isAdmin {
+				return domain.NewError(domain.ErrorKindForbidden, "role_change_forbidden", "only admins can change roles")
+			}
+			user.Role = *req.Role
+		}
+		return nil
+	})
+}
+
+// editUser loads the user named by the id URL parameter, changes it and stores it.
+func (h *UserHandler) editUser(w http.ResponseWriter, r *http.Request, apply func(user *domain.User, isAdmin bool) error) {
+	targetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
 		return
 	}
-	targetIDStr := chi.URLParam(r, "id")
-	targetID, err := strconv.Atoi(targetIDStr)
+	if !authorizeUser(w, r, targetID, "you do not have permission to update this user") {
+		return
+	}
+
+	user, err := h.service.GetUser(r.Context(), targetID)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid user id")
+		middleware.RespondServiceError(w, r, err, "failed to get user")
+		return
+	}
+	if user == nil {
+		h.respondError(w, r, http.StatusNotFound, "user not found")
+		return
+	}
+	if user.IsErased() {
+		middleware.RespondServiceError(w, r, domain.ErrUserErased, "failed to update user")
 		return
 	}
 
-	// Use IsAdminOrSelf for authorization
-	if !middleware.IsAdminOrSelf(claims, targetID) {
-		h.respondError(w, http.StatusForbidden, "you do not have permission to update this user")
+	if err := apply(user, isAdmin(r)); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to update user")
 		return
 	}
+	if err := h.service.UpdateUser(r.Context(), user); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to update user")
+		return
+	}
+	json.NewEncoder(w).Encode(userResponse(r, user))
+}
 
-	// --- 4. Original Logic (with Privilege Escalation fix) ---
-	req, ok := middleware.GetValidatedBody[*UpdateRequest](r.Context())
+// userResponse is the JSON representation of a user, with the URL of the avatar if the user has one
+func userResponse(r *http.Request, user *domain.User) map[string]interface{} {
+	resp := map[string]interface{}{
+		"id":         user.ID,
+		"username":   user.Username,
+		"email":      user.Email,
+		"role":       user.Role,
+		"first_name": user.FirstName,
+		"last_name":  user.LastName,
+		"phone":      user.Phone,
+		"locale":     user.Locale,
+	}
+	if user.AvatarKey != "" {
+		resp["avatar_url"] = fmt.Sprintf("/api/%s/users/%d/avatar", middleware.APIVersionFromContext(r.Context()), user.ID)
+	}
+	return resp
+}
+
+// ListLogins handles GET /users/{id}/logins.
+func (h *UserHandler) ListLogins(w http.ResponseWriter, r *http.Request) {
+	targetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	if !authorizeUser(w, r, targetID, "you do not have permission to view this login history") {
+		return
+	}
+	q := r.URL.Query()
+	limit, offset, err := parsePage(q, 50, 500)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	includeTotal, err := wantsTotal(q)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	events, err := h.service.ListLogins(r.Context(), targetID, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list logins")
+		return
+	}
+	if includeTotal {
+		total, err := h.service.CountLogins(r.Context(), targetID)
+		if err != nil {
+			middleware.RespondServiceError(w, r, err, "failed to count logins")
+			return
+		}
+		setTotalCount(w, total)
+	}
+	if events == nil {
+		events = []*domain.LoginEvent{}
+	}
+	json.NewEncoder(w).Encode(events)
+}
+
+// ListDevices handles GET /users/{id}/devices.
+func (h *UserHandler) ListDevices(w http.ResponseWriter, r *http.Request) {
+	targetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	if !authorizeUser(w, r, targetID, "you do not have permission to view these devices") {
+		return
+	}
+
+	devices, err := h.service.ListDevices(r.Context(), targetID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list devices")
+		return
+	}
+	if devices == nil {
+		devices = []*domain.Device{}
+	}
+	json.NewEncoder(w).Encode(devices)
+}
+
+// RevokeDevice handles DELETE /users/{id}/devices/{fingerprint}.
+func (h *UserHandler) RevokeDevice(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
 	if !ok {
-		panic("could not retrieve validated body")
+		return
+	}
+	targetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	if !authorizeUser(w, r, targetID, "you do not have permission to revoke this device") {
+		return
 	}
 
-	user, err := h.service.GetUser(targetID)
+	if err := h.service.RevokeDevice(r.Context(), targetID, chi.URLParam(r, "fingerprint"), actorID); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to revoke device")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UploadAvatar handles PUT /users/{id}/avatar.
+func (h *UserHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	targetID, ok := h.avatarOwner(w, r, "you do not have permission to change this avatar")
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart framing around the image
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxAvatarBytes+64<<10)
+	reader, err := r.MultipartReader()
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, "failed to get user")
+		h.respondError(w, r, http.StatusBadRequest, "request must be multipart/form-data")
 		return
 	}
-	if user == nil {
-		h.respondError(w, http.StatusNotFound, "user not found")
+	var data []byte
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid multipart body")
+			return
+		}
+		if part.FormName() == "file" {
+			if data, err = io.ReadAll(io.LimitReader(part, domain.MaxAvatarBytes+1)); err != nil {
+				h.respondError(w, r, http.StatusRequestEntityTooLarge, "avatar is too large")
+				return
+			}
+			break
+		}
+	}
+	if data == nil {
+		h.respondError(w, r, http.StatusBadRequest, "missing file field")
 		return
 	}
 
-	user.Username = req.Username
-	user.Email = req.Email
+	user, err := h.service.SetAvatar(r.Context(), targetID, data)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to update avatar")
+		return
+	}
+	if user == nil {
+		h.respondError(w, r, http.StatusNotFound, "user not found")
+		return
+	}
+	json.NewEncoder(w).Encode(userResponse(r, user))
+}
 
-	// **SECURITY FIX**: Prevents a regular user from making themselves an admin.
-	// Only an existing admin can change a user's role.
-	if claims.Role == "admin" && req.Role != "" {
-		user.Role = req.Role
+// GetAvatar handles GET /users/{id}/avatar, answering with the image itself.
+func (h *UserHandler) GetAvatar(w http.ResponseWriter, r *http.Request) {
+	targetID, ok := h.avatarOwner(w, r, "you do not have permission to view this avatar")
+	if !ok {
+		return
+	}
+	data, contentType, err := h.service.GetAvatar(r.Context(), targetID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get avatar")
+		return
 	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
 
-	if err := h.service.UpdateUser(user); err != nil {
-		h.respondError(w, http.StatusInternalServerError, "failed to update user")
+// DeleteAvatar handles DELETE /users/{id}/avatar.
+func (h *UserHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	targetID, ok := h.avatarOwner(w, r, "you do not have permission to change this avatar")
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteAvatar(r.Context(), targetID); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to delete avatar")
 		return
 	}
+	w.WriteHeader(http.StatusNoContent)
+}
 
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"id":       user.ID,
-		"username": user.Username,
-		"email":    user.Email,
-		"role":     user.Role,
-	})
+// avatarOwner returns the ID of the user whose avatar r is about.
+func (h *UserHandler) avatarOwner(w http.ResponseWriter, r *http.Request, forbidden string) (int, bool) {
+	targetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
+		return 0, false
+	}
+	return targetID, authorizeUser(w, r, targetID, forbidden)
 }
 
 // DeleteUser handles DELETE /users/{id}
 func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	claims, ok := middleware.UserClaimsFromContext(r.Context())
 	if !ok {
-		h.respondError(w, http.StatusUnauthorized, "invalid token claims")
+		h.respondError(w, r, http.StatusUnauthorized, "invalid token claims")
 		return
 	}
 	targetIDStr := chi.URLParam(r, "id")
 	targetID, err := strconv.Atoi(targetIDStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid user id")
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
 		return
 	}
 
 	// Use IsAdminOrSelf for authorization
 	if !middleware.IsAdminOrSelf(claims, targetID) {
-		h.respondError(w, http.StatusForbidden, "you do not have permission to delete this user")
+		h.respondError(w, r, http.StatusForbidden, "you do not have permission to delete this user")
 		return
 	}
 	// --- Original Logic ---
-	if err := h.service.DeleteUser(targetID); err != nil {
-		h.respondError(w, http.StatusInternalServerError, "failed to delete user")
+	if err := h.service.DeleteUser(r.Context(), targetID); err != nil {
+		h.respondError(w, r, http.StatusInternalServerError, "failed to delete user")
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *UserHandler) respondError(w http.ResponseWriter, code int, msg string) {
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+// ChangePassword handles PUT /users/{id}/password.
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	targetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	if userID != targetID {
+		h.respondError(w, r, http.StatusForbidden, "you can only change your own password")
+		return
+	}
+
+	req, ok := middleware.GetValidatedBody[*ChangePasswordRequest](r.Context())
+	if !ok {
+		panic("could not retrieve validated body")
+	}
+
+	err = h.service.ChangePassword(r.Context(), targetID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to change password")
+		return
+	}
+
+	if err := h.tokens.RevokeAll(r.Context(), targetID); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Int("user_id", targetID).Msg("Failed to revoke tokens after password change")
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": "password changed, please log in again"})
+}
+
+// EraseUser handles POST /users/{id}/erase.
+func (h *UserHandler) EraseUser(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	targetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	if !authorizeUser(w, r, targetID, "you do not have permission to erase this user") {
+		return
+	}
+
+	user, err := h.service.EraseUser(r.Context(), targetID, actorID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to erase user")
+		return
+	}
+	if user == nil {
+		h.respondError(w, r, http.StatusNotFound, "user not found")
+		return
+	}
+
+	// The erasure is already stored; failures here are logged, not reported
+	if err := h.tokens.RevokeAll(r.Context(), targetID); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Int("user_id", targetID).Msg("Failed to revoke tokens of erased user")
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":        user.ID,
+		"username":  user.Username,
+		"email":     user.Email,
+		"role":      user.Role,
+		"erased_at": user.ErasedAt,
+	})
+}
+
+func (h *UserHandler) respondError(w http.ResponseWriter, r *http.Request, code int, msg string) {
+	middleware.WriteProblem(w, r, code, msg)
 }
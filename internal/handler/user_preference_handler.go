@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// maxPreferencesBodyBytes caps the body of a preferences update
+const maxPreferencesBodyBytes = 256 << 10
+
+// UserPreferenceHandler handles a user's key-value preferences
+type UserPreferenceHandler struct {
+	service domain.UserPreferenceService
+}
+
+// NewUserPreferenceHandler creates a new UserPreferenceHandler
+func NewUserPreferenceHandler(service domain.UserPreferenceService) *UserPreferenceHandler {
+	return &UserPreferenceHandler{service: service}
+}
+
+// RegisterRoutes registers the preference routes.
+func (h *UserPreferenceHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/users/{userID}/preferences", func(r chi.Router) {
+		r.Get("/", h.GetPreferences)
+		r.Patch("/", h.UpdatePreferences)
+		r.Put("/{key}", h.SetPreference)
+		r.Delete("/{key}", h.DeletePreference)
+	})
+}
+
+// GetPreferences answers with the user's preferences as one JSON object
+func (h *UserPreferenceHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	prefs, err := h.service.GetPreferences(r.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get preferences")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// UpdatePreferences merges a JSON object into the user's preferences; keys set to null are removed.
+func (h *UserPreferenceHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	var changes map[string]json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPreferencesBodyBytes)).Decode(&changes); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "request body must be a JSON object")
+		return
+	}
+	prefs, err := h.service.UpdatePreferences(r.Context(), userID, changes)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to update preferences")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// SetPreference stores the JSON value in the body under the key of the route
+func (h *UserPreferenceHandler) SetPreference(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	value, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPreferencesBodyBytes))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "request body is too large")
+		return
+	}
+	if err := h.service.SetPreference(r.Context(), userID, chi.URLParam(r, "key"), value); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to set preference")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeletePreference removes the preference of the route
+func (h *UserPreferenceHandler) DeletePreference(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := authorizedUser(w, r)
+	if !ok {
+		return
+	}
+	if err := h.service.DeletePreference(r.Context(), userID, chi.URLParam(r, "key")); err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to delete preference")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondError is a helper method to respond with error
+func (h *UserPreferenceHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
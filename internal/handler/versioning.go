@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// Handlers share one core across API versions and adapt only the bodies that
+// differ: a request adapter decodes the body a version accepts into a
+// version-neutral request, and a response adapter renders a version-neutral
+// result as the body a version returns. A version without its own adapter uses
+// the one of the closest older version.
+
+// requestAdapters decodes the request body of each API version into a T
+type requestAdapters[T any] map[middleware.APIVersion]func(*http.Request) (T, error)
+
+// decode decodes the body of r with the adapter of its API version
+func (a requestAdapters[T]) decode(r *http.Request) (T, error) {
+	for v := middleware.APIVersionFromContext(r.Context()); v >= middleware.APIVersion1; v-- {
+		if adapt, ok := a[v]; ok {
+			return adapt(r)
+		}
+	}
+	var zero T
+	return zero, fmt.Errorf("no request adapter for API %s", middleware.APIVersionFromContext(r.Context()))
+}
+
+// responseAdapters renders a T as the response body of each API version
+type responseAdapters[T any] map[middleware.APIVersion]func(T) interface{}
+
+// respond writes result with the adapter of the request's API version
+func (a responseAdapters[T]) respond(w http.ResponseWriter, r *http.Request, status int, result T) {
+	var body interface{} = result
+	for v := middleware.APIVersionFromContext(r.Context()); v >= middleware.APIVersion1; v-- {
+		if adapt, ok := a[v]; ok {
+			body = adapt(result)
+			break
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// decimalAmountPattern matches the decimal amounts v2 accepts.
+var decimalAmountPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,2})?$`)
+
+// parseDecimalAmount parses a v2 decimal string amount
+func parseDecimalAmount(s string) (float64, error) {
+	if !decimalAmountPattern.MatchString(s) {
+		return 0, fmt.Errorf("amount must be a decimal string with at most two fractional digits, got %q", s)
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// formatDecimalAmount formats an amount as a v2 decimal string
+func formatDecimalAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
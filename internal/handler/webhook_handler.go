@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/gateway"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+// maxWebhookBytes caps the body of a provider webhook
+const maxWebhookBytes = 256 << 10
+
+// WebhookReceipt is the answer to a provider's webhook
+type WebhookReceipt struct {
+	EventID string `json:"event_id"`
+	Status  string `json:"status"`
+}
+
+// WebhookHandler handles payment provider webhooks and the admin view of the events they delivered.
+type WebhookHandler struct {
+	service domain.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler
+func NewWebhookHandler(service domain.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// RegisterRoutes registers the admin routes of received webhook events.
+func (h *WebhookHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/admin/webhooks/events", func(r chi.Router) {
+		r.Use(middleware.RequireRoles("admin"))
+		r.Get("/", h.ListEvents)
+		r.Get("/{id}", h.GetEvent)
+	})
+}
+
+// Receive handles POST /webhooks/{provider}, where payment providers report events.
+func (h *WebhookHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "request body is too large")
+		return
+	}
+	event, err := h.service.Receive(r.Context(), &domain.WebhookDelivery{
+		Provider:  chi.URLParam(r, "provider"),
+		Signature: r.Header.Get(gateway.SignatureHeader),
+		Body:      body,
+	})
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to receive webhook")
+		return
+	}
+	json.NewEncoder(w).Encode(WebhookReceipt{EventID: event.EventID, Status: event.Status})
+}
+
+// ListEvents handles GET /admin/webhooks/events.
+func (h *WebhookHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, offset, err := parsePage(q, 50, 200)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	events, err := h.service.ListEvents(r.Context(), q.Get("provider"), q.Get("status"), limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list webhook events")
+		return
+	}
+	if events == nil {
+		events = []*domain.WebhookEvent{}
+	}
+	json.NewEncoder(w).Encode(events)
+}
+
+// GetEvent handles GET /admin/webhooks/events/{id}.
+func (h *WebhookHandler) GetEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid webhook event ID")
+		return
+	}
+	event, err := h.service.GetEvent(r.Context(), id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get webhook event")
+		return
+	}
+	json.NewEncoder(w).Encode(event)
+}
+
+// respondError is a helper method to respond with error
+func (h *WebhookHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
+)
+
+const (
+	// maxCSVUploadBytes caps the size of an uploaded task CSV
+	maxCSVUploadBytes = 10 << 20
+	// maxCSVRows caps the number of data rows accepted from one upload
+	maxCSVRows = 1000
+)
+
+// CSVRowError reports why a CSV record was rejected.
+type CSVRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// SubmitBatchCSVResponse represents the response for a CSV batch upload
+type SubmitBatchCSVResponse struct {
+	SubmitBatchResponse
+	AcceptedRows int           `json:"accepted_rows"`
+	RejectedRows int           `json:"rejected_rows"`
+	Errors       []CSVRowError `json:"errors"`
+}
+
+// SubmitBatchCSV handles a multipart upload of a CSV file of tasks.
+func (h *WorkerHandler) SubmitBatchCSV(w http.ResponseWriter, r *http.Request) {
+	rollbackOnFailure := false
+	if v := r.URL.Query().Get("rollback_on_failure"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid rollback_on_failure")
+			return
+		}
+		rollbackOnFailure = b
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxCSVUploadBytes)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "request must be multipart/form-data")
+		return
+	}
+
+	// Stream the file part straight into the CSV parser instead of buffering the upload
+	var file io.Reader
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid multipart body")
+			return
+		}
+		if part.FormName() == "file" {
+			file = part
+			break
+		}
+	}
+	if file == nil {
+		h.respondError(w, r, http.StatusBadRequest, "missing file field")
+		return
+	}
+
+	requests, rowErrors, err := h.parseTaskCSV(file)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := SubmitBatchCSVResponse{
+		AcceptedRows: len(requests),
+		RejectedRows: len(rowErrors),
+		Errors:       rowErrors,
+	}
+	if response.Errors == nil {
+		response.Errors = []CSVRowError{}
+	}
+
+	if len(requests) == 0 {
+		response.Status = "rejected"
+		response.Message = "no valid rows in CSV"
+		response.Timestamp = time.Now().Unix()
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	tasks := make([]*domain.TransactionTask, len(requests))
+	taskIDs := make([]string, len(requests))
+	for i, req := range requests {
+		tasks[i] = &domain.TransactionTask{
+			ID:       uuid.New().String(),
+			Type:     req.Type,
+			UserID:   req.UserID,
+			ToUserID: req.ToUserID,
+			Amount:   req.Amount,
+			Priority: req.Priority,
+		}
+		taskIDs[i] = tasks[i].ID
+	}
+
+	batch, err := h.batchProcessor.StartBatch(r.Context(), tasks, rollbackOnFailure)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to submit batch")
+		return
+	}
+
+	response.BatchID = batch.BatchID
+	response.TaskIDs = taskIDs
+	response.Status = "submitted"
+	response.Message = fmt.Sprintf("Batch of %d tasks submitted for asynchronous processing.", len(tasks))
+	response.Timestamp = time.Now().Unix()
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseTaskCSV reads task rows from r one record at a time.
+func (h *WorkerHandler) parseTaskCSV(r io.Reader) ([]SubmitTaskRequest, []CSVRowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // report short or long rows per line instead of aborting
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"type", "user_id", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("CSV header is missing the %s column", required)
+		}
+	}
+
+	var requests []SubmitTaskRequest
+	var rowErrors []CSVRowError
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+			}
+			rowErrors = append(rowErrors, CSVRowError{Row: parseErr.StartLine, Error: parseErr.Err.Error()})
+			continue
+		}
+		row, _ := reader.FieldPos(0)
+
+		if len(requests)+len(rowErrors) >= maxCSVRows {
+			return nil, nil, fmt.Errorf("CSV exceeds the maximum of %d rows", maxCSVRows)
+		}
+
+		req, err := parseTaskCSVRecord(record, columns)
+		if err == nil {
+			err = h.validateSubmitTaskRequest(&req)
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, CSVRowError{Row: row, Error: err.Error()})
+			continue
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, rowErrors, nil
+}
+
+// parseTaskCSVRecord converts one CSV record into a task request
+func parseTaskCSVRecord(record []string, columns map[string]int) (SubmitTaskRequest, error) {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	req := SubmitTaskRequest{Type: strings.ToLower(field("type"))}
+
+	userID, err := strconv.Atoi(field("user_id"))
+	if err != nil {
+		return req, errors.New("user_id must be an integer")
+	}
+	req.UserID = userID
+
+	if v := field("to_user_id"); v != "" {
+		toUserID, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("to_user_id must be an integer")
+		}
+		req.ToUserID = &toUserID
+	}
+
+	amount, err := strconv.ParseFloat(field("amount"), 64)
+	if err != nil {
+		return req, errors.New("amount must be a number")
+	}
+	req.Amount = amount
+
+	if v := field("priority"); v != "" {
+		priority, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("priority must be an integer")
+		}
+		req.Priority = priority
+	}
+
+	return req, nil
+}
@@ -1,18 +1,18 @@
 package handler
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
-	"github.com/rs/zerolog/log"
 
 	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/middleware"
 	"github.com/melihgurlek/backend-path/internal/worker"
 )
 
@@ -33,18 +33,28 @@ func NewWorkerHandler(transactionProcessor domain.TransactionProcessor, bp *work
 // RegisterRoutes registers the worker routes
 func (h *WorkerHandler) RegisterRoutes(r chi.Router) {
 	r.Post("/tasks", h.SubmitTask)
+	r.Get("/tasks/{id}", h.GetTaskStatus)
 	r.Post("/batch", h.SubmitBatch)
+	r.Post("/batch/csv", h.SubmitBatchCSV)
+	r.Get("/batch/{id}", h.GetBatchStatus)
 	r.Get("/stats", h.GetStats)
 	r.Get("/health", h.GetHealth)
+	r.With(middleware.RequireRoles("admin")).Get("/dlq", h.ListDeadLetters)
+	r.With(middleware.RequireRoles("admin")).Post("/dlq/{id}/requeue", h.RequeueDeadLetter)
 }
 
 // SubmitTaskRequest represents a request to submit a single task
 type SubmitTaskRequest struct {
-	Type     string  `json:"type" validate:"required,oneof=credit debit transfer"`
-	UserID   int     `json:"user_id" validate:"required,min=1"`
-	ToUserID *int    `json:"to_user_id,omitempty"` // for transfers
-	Amount   float64 `json:"amount" validate:"required,gt=0"`
-	Priority int     `json:"priority,omitempty" validate:"min=0,max=10"`
+	Type        string  `json:"type" validate:"required,oneof=credit debit transfer"`
+	UserID      int     `json:"user_id" validate:"required,min=1"`
+	ToUserID    *int    `json:"to_user_id,omitempty"` // for transfers
+	Amount      float64 `json:"amount" validate:"required,gt=0"`
+	Priority    int     `json:"priority,omitempty" validate:"min=0,max=10"`
+	CallbackURL string  `json:"callback_url,omitempty"` // receives a signed POST of the result
+
+	// Optional retry overrides for transient failures; zero uses the server defaults
+	MaxAttempts    int `json:"max_attempts,omitempty" validate:"min=0,max=10"`
+	RetryBackoffMs int `json:"retry_backoff_ms,omitempty" validate:"min=0,max=60000"`
 }
 
 // SubmitTaskResponse represents the response for task submission
@@ -59,31 +69,33 @@ type SubmitTaskResponse struct {
 func (h *WorkerHandler) SubmitTask(w http.ResponseWriter, r *http.Request) {
 	var req SubmitTaskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid request body")
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	// Validate request
 	if err := h.validateSubmitTaskRequest(&req); err != nil {
-		h.respondError(w, http.StatusBadRequest, err.Error())
+		h.respondError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// Create task
 	task := &domain.TransactionTask{
-		ID:       uuid.New().String(),
-		Type:     req.Type,
-		UserID:   req.UserID,
-		ToUserID: req.ToUserID,
-		Amount:   req.Amount,
-		Priority: req.Priority,
+		ID:           uuid.New().String(),
+		Type:         req.Type,
+		UserID:       req.UserID,
+		ToUserID:     req.ToUserID,
+		Amount:       req.Amount,
+		Priority:     req.Priority,
+		CallbackURL:  req.CallbackURL,
+		MaxAttempts:  req.MaxAttempts,
+		RetryBackoff: time.Duration(req.RetryBackoffMs) * time.Millisecond,
 	}
 
 	// Submit task
 	err := h.transactionProcessor.SubmitTask(r.Context(), task)
 	if err != nil {
-		log.Error().Err(err).Str("task_id", task.ID).Msg("Failed to submit task")
-		h.respondError(w, http.StatusInternalServerError, "failed to submit task: "+err.Error())
+		middleware.RespondServiceError(w, r, err, "failed to submit task")
 		return
 	}
 
@@ -98,9 +110,51 @@ func (h *WorkerHandler) SubmitTask(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// TaskStatusResponse represents the recorded status and timing of a task
+type TaskStatusResponse struct {
+	*domain.TaskRecord
+	QueueWaitMs    int64 `json:"queue_wait_ms"`
+	ProcessingMs   int64 `json:"processing_ms"`
+	TotalElapsedMs int64 `json:"total_elapsed_ms"`
+}
+
+// GetTaskStatus returns the status and outcome of a previously submitted task
+func (h *WorkerHandler) GetTaskStatus(w http.ResponseWriter, r *http.Request) {
+	taskID := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(taskID); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid task ID")
+		return
+	}
+
+	record, err := h.transactionProcessor.GetTask(r.Context(), taskID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get task status")
+		return
+	}
+	if record == nil {
+		h.respondError(w, r, http.StatusNotFound, "task not found")
+		return
+	}
+
+	queueWait := record.QueueWait()
+	processing := record.ProcessingTime()
+	response := TaskStatusResponse{
+		TaskRecord:     record,
+		QueueWaitMs:    queueWait.Milliseconds(),
+		ProcessingMs:   processing.Milliseconds(),
+		TotalElapsedMs: (queueWait + processing).Milliseconds(),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
 // SubmitBatchRequest represents a request to submit multiple tasks
 type SubmitBatchRequest struct {
 	Tasks []SubmitTaskRequest `json:"tasks" validate:"required,min=1,max=100"`
+
+	// RollbackOnFailure runs the tasks in order and undoes the completed ones if any fails
+	RollbackOnFailure bool `json:"rollback_on_failure,omitempty"`
 }
 
 // SubmitBatchResponse represents the response for batch submission
@@ -116,18 +170,18 @@ type SubmitBatchResponse struct {
 func (h *WorkerHandler) SubmitBatch(w http.ResponseWriter, r *http.Request) {
 	var req SubmitBatchRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid request body")
+		h.respondError(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	// Validate the batch request itself
 	if len(req.Tasks) == 0 {
-		h.respondError(w, http.StatusBadRequest, "at least one task is required")
+		h.respondError(w, r, http.StatusBadRequest, "at least one task is required")
 		return
 	}
 
 	if len(req.Tasks) > 100 {
-		h.respondError(w, http.StatusBadRequest, "maximum 100 tasks allowed per batch")
+		h.respondError(w, r, http.StatusBadRequest, "maximum 100 tasks allowed per batch")
 		return
 	}
 
@@ -136,44 +190,38 @@ func (h *WorkerHandler) SubmitBatch(w http.ResponseWriter, r *http.Request) {
 	for i, taskReq := range req.Tasks {
 		if err := h.validateSubmitTaskRequest(&taskReq); err != nil {
 			msg := fmt.Sprintf("invalid task at index %d: %s", i, err.Error())
-			h.respondError(w, http.StatusBadRequest, msg)
+			h.respondError(w, r, http.StatusBadRequest, msg)
 			return
 		}
 
 		tasks[i] = &domain.TransactionTask{
-			ID:       uuid.New().String(),
-			Type:     taskReq.Type,
-			UserID:   taskReq.UserID,
-			ToUserID: taskReq.ToUserID,
-			Amount:   taskReq.Amount,
-			Priority: taskReq.Priority,
+			ID:           uuid.New().String(),
+			Type:         taskReq.Type,
+			UserID:       taskReq.UserID,
+			ToUserID:     taskReq.ToUserID,
+			Amount:       taskReq.Amount,
+			Priority:     taskReq.Priority,
+			CallbackURL:  taskReq.CallbackURL,
+			MaxAttempts:  taskReq.MaxAttempts,
+			RetryBackoff: time.Duration(taskReq.RetryBackoffMs) * time.Millisecond,
 		}
 	}
 
-	// Run the batch processing in a background goroutine so the API can respond immediately.
-	go func() {
-		// Create a new background context because the original request's context
-		// will be canceled as soon as this HTTP handler returns.
-		bgCtx := context.Background()
+	// Record the batch and process it in the background so the API can respond immediately
+	batch, err := h.batchProcessor.StartBatch(r.Context(), tasks, req.RollbackOnFailure)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to submit batch")
+		return
+	}
+
+	taskIDs := make([]string, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
 
-		log.Info().Int("task_count", len(tasks)).Msg("Starting asynchronous batch processing")
-		result, err := h.batchProcessor.ProcessBatch(bgCtx, tasks)
-		if err != nil {
-			// This log captures errors from the batch execution itself
-			log.Error().Err(err).Msg("Asynchronous batch processing failed")
-			return
-		}
-		// This log confirms the final outcome of the async job
-		log.Info().
-			Str("batch_id", result.BatchID).
-			Int("successful", result.SuccessfulTasks).
-			Int("failed", result.FailedTasks).
-			Msg("Asynchronous batch processing finished")
-	}()
-
-	// Immediately send a response to the client acknowledging the submission.
 	response := SubmitBatchResponse{
-		BatchID:   uuid.New().String(), // This is an acknowledgment ID for the submission
+		BatchID:   batch.BatchID,
+		TaskIDs:   taskIDs,
 		Status:    "submitted",
 		Message:   "Batch submitted for asynchronous processing.",
 		Timestamp: time.Now().Unix(),
@@ -183,6 +231,40 @@ func (h *WorkerHandler) SubmitBatch(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// BatchStatusResponse represents the progress and per-task outcomes of a batch
+type BatchStatusResponse struct {
+	*domain.BatchRecord
+	domain.BatchProgress
+	Tasks []*domain.TaskRecord `json:"tasks"`
+}
+
+// GetBatchStatus returns the progress of a previously submitted batch
+func (h *WorkerHandler) GetBatchStatus(w http.ResponseWriter, r *http.Request) {
+	batchID := chi.URLParam(r, "id")
+
+	batch, tasks, err := h.batchProcessor.GetBatch(r.Context(), batchID)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to get batch status")
+		return
+	}
+	if batch == nil {
+		h.respondError(w, r, http.StatusNotFound, "batch not found")
+		return
+	}
+	if tasks == nil {
+		tasks = []*domain.TaskRecord{}
+	}
+
+	response := BatchStatusResponse{
+		BatchRecord:   batch,
+		BatchProgress: batch.Progress(tasks),
+		Tasks:         tasks,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
 // GetStatsResponse represents the response for processing statistics
 type GetStatsResponse struct {
 	TotalProcessed     int64   `json:"total_processed"`
@@ -245,6 +327,76 @@ func (h *WorkerHandler) GetHealth(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// ListDeadLetters returns tasks that were dead-lettered after exhausting their retries.
+func (h *WorkerHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			h.respondError(w, r, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = min(l, 200)
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		o, err := strconv.Atoi(offsetStr)
+		if err != nil || o < 0 {
+			h.respondError(w, r, http.StatusBadRequest, "invalid offset")
+			return
+		}
+		offset = o
+	}
+
+	includeRequeued := false
+	if includeStr := r.URL.Query().Get("include_requeued"); includeStr != "" {
+		b, err := strconv.ParseBool(includeStr)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, "invalid include_requeued")
+			return
+		}
+		includeRequeued = b
+	}
+
+	entries, err := h.transactionProcessor.ListDeadLetters(r.Context(), includeRequeued, limit, offset)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to list dead letters")
+		return
+	}
+	if entries == nil {
+		entries = []*domain.DeadLetter{}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(entries)
+}
+
+// RequeueDeadLetter resubmits a dead-lettered task to the worker queue
+func (h *WorkerHandler) RequeueDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid dead letter ID")
+		return
+	}
+
+	task, err := h.transactionProcessor.RequeueDeadLetter(r.Context(), id)
+	if err != nil {
+		middleware.RespondServiceError(w, r, err, "failed to requeue task")
+		return
+	}
+
+	response := SubmitTaskResponse{
+		TaskID:    task.ID,
+		Status:    "submitted",
+		Message:   "Task requeued successfully",
+		Timestamp: time.Now().Unix(),
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
 // validateSubmitTaskRequest validates a task submission request
 func (h *WorkerHandler) validateSubmitTaskRequest(req *SubmitTaskRequest) error {
 	if req.Type == "" {
@@ -275,11 +427,24 @@ func (h *WorkerHandler) validateSubmitTaskRequest(req *SubmitTaskRequest) error
 		return errors.New("priority must be between 0 and 10")
 	}
 
+	if req.MaxAttempts < 0 || req.MaxAttempts > 10 {
+		return errors.New("max_attempts must be between 0 and 10")
+	}
+
+	if req.RetryBackoffMs < 0 || req.RetryBackoffMs > 60000 {
+		return errors.New("retry_backoff_ms must be between 0 and 60000")
+	}
+
+	if req.CallbackURL != "" {
+		if err := worker.ValidateCallbackURL(req.CallbackURL); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // respondError sends an error response
-func (h *WorkerHandler) respondError(w http.ResponseWriter, statusCode int, message string) {
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
+func (h *WorkerHandler) respondError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	middleware.WriteProblem(w, r, statusCode, message)
 }
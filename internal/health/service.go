@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// Service implements domain.HealthService.
+type Service struct {
+	checks  []domain.HealthCheck
+	timeout time.Duration
+	now     func() time.Time
+}
+
+// NewService creates a new health Service
+func NewService(timeout time.Duration, checks ...domain.HealthCheck) *Service {
+	return &Service{
+		checks:  checks,
+		timeout: timeout,
+		now:     time.Now,
+	}
+}
+
+// Liveness runs the liveness checks
+func (s *Service) Liveness(ctx context.Context) *domain.HealthReport {
+	var checks []domain.HealthCheck
+	for _, check := range s.checks {
+		if check.Liveness {
+			checks = append(checks, check)
+		}
+	}
+	return s.run(ctx, checks)
+}
+
+// Readiness runs every check
+func (s *Service) Readiness(ctx context.Context) *domain.HealthReport {
+	return s.run(ctx, s.checks)
+}
+
+func (s *Service) run(ctx context.Context, checks []domain.HealthCheck) *domain.HealthReport {
+	report := &domain.HealthReport{
+		Status:       domain.HealthStatusUp,
+		Timestamp:    s.now().UTC(),
+		Dependencies: make(map[string]*domain.DependencyHealth, len(checks)),
+	}
+
+	results := make([]*domain.DependencyHealth, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = s.probe(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for i, check := range checks {
+		report.Dependencies[check.Name] = results[i]
+		if results[i].Status != domain.HealthStatusUp {
+			report.Status = domain.HealthStatusDown
+		}
+	}
+	return report
+}
+
+// probe runs one check, timing it.
+func (s *Service) probe(ctx context.Context, check domain.HealthCheck) *domain.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := s.now()
+	done := make(chan error, 1)
+	go func() { done <- check.Check(ctx) }()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result := &domain.DependencyHealth{
+		Status:    domain.HealthStatusUp,
+		LatencyMS: float64(s.now().Sub(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = domain.HealthStatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
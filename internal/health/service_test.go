@@ -0,0 +1,61 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+func passing(ctx context.Context) error { return nil }
+
+func TestService_Readiness(t *testing.T) {
+	s := NewService(time.Second,
+		domain.HealthCheck{Name: "postgres", Check: passing},
+		domain.HealthCheck{Name: "redis", Check: func(ctx context.Context) error { return errors.New("connection refused") }},
+		domain.HealthCheck{Name: "worker_pool", Check: passing, Liveness: true},
+	)
+
+	report := s.Readiness(context.Background())
+
+	assert.False(t, report.Up())
+	require.Len(t, report.Dependencies, 3)
+	assert.Equal(t, domain.HealthStatusUp, report.Dependencies["postgres"].Status)
+	assert.Equal(t, domain.HealthStatusDown, report.Dependencies["redis"].Status)
+	assert.Equal(t, "connection refused", report.Dependencies["redis"].Error)
+	assert.Equal(t, domain.HealthStatusUp, report.Dependencies["worker_pool"].Status)
+}
+
+func TestService_LivenessRunsOnlyLivenessChecks(t *testing.T) {
+	s := NewService(time.Second,
+		domain.HealthCheck{Name: "postgres", Check: func(ctx context.Context) error { return errors.New("down") }},
+		domain.HealthCheck{Name: "scheduler", Check: passing, Liveness: true},
+	)
+
+	report := s.Liveness(context.Background())
+
+	assert.True(t, report.Up())
+	assert.Len(t, report.Dependencies, 1)
+	assert.Contains(t, report.Dependencies, "scheduler")
+}
+
+func TestService_CheckTimesOut(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	s := NewService(20*time.Millisecond,
+		domain.HealthCheck{Name: "stuck", Check: func(ctx context.Context) error {
+			<-block // ignores ctx
+			return nil
+		}},
+	)
+
+	report := s.Readiness(context.Background())
+
+	assert.False(t, report.Up())
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Dependencies["stuck"].Error)
+}
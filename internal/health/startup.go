@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// ErrStarting is reported by the startup readiness check until Started is called
+var ErrStarting = errors.New("still starting")
+
+// Startup connects to the dependencies the application needs before it can serve.
+type Startup struct {
+	timeout time.Duration
+	policy  domain.RetryPolicy
+
+	mu      sync.Mutex
+	deps    []*dependency
+	started bool
+	wg      sync.WaitGroup
+}
+
+// dependency is the connection state of one dependency, guarded by Startup.mu
+type dependency struct {
+	name      string
+	attempts  int
+	err       error // last connect error; nil once connected
+	connected bool
+	gaveUp    bool
+}
+
+// NewStartup creates a Startup that retries each dependency for up to timeout.
+func NewStartup(timeout time.Duration, policy domain.RetryPolicy) *Startup {
+	return &Startup{timeout: timeout, policy: policy}
+}
+
+// Connect starts connecting to a dependency in the background.
+func (s *Startup) Connect(ctx context.Context, name string, connect func(ctx context.Context) error) {
+	dep := &dependency{name: name}
+	s.mu.Lock()
+	s.deps = append(s.deps, dep)
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.connect(ctx, dep, connect)
+}
+
+// Wait blocks until every dependency has connected or been given up on.
+func (s *Startup) Wait() map[string]error {
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	failed := make(map[string]error)
+	for _, dep := range s.deps {
+		if dep.gaveUp {
+			failed[dep.name] = dep.err
+		}
+	}
+	return failed
+}
+
+// Started marks startup as complete, making the startup check pass
+func (s *Startup) Started() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = true
+}
+
+// Checks returns a readiness check per dependency added so far.
+func (s *Startup) Checks() []domain.HealthCheck {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checks := make([]domain.HealthCheck, 0, len(s.deps)+1)
+	for _, dep := range s.deps {
+		checks = append(checks, domain.HealthCheck{Name: dep.name, Check: func(ctx context.Context) error {
+			return s.status(dep)
+		}})
+	}
+	checks = append(checks, domain.HealthCheck{Name: "startup", Check: func(ctx context.Context) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !s.started {
+			return ErrStarting
+		}
+		return nil
+	}})
+	return checks
+}
+
+func (s *Startup) connect(ctx context.Context, dep *dependency, connect func(ctx context.Context) error) {
+	defer s.wg.Done()
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	for retryCount := 0; ; retryCount++ {
+		err := connect(ctx)
+		s.mu.Lock()
+		dep.attempts++
+		dep.err = err
+		dep.connected = err == nil
+		s.mu.Unlock()
+		if err == nil {
+			log.Info().Str("dependency", dep.name).Int("attempts", retryCount+1).Msg("Dependency connected")
+			return
+		}
+
+		delay := s.policy.Backoff(retryCount)
+		log.Warn().Err(err).Str("dependency", dep.name).Int("attempt", retryCount+1).Dur("retry_in", delay).
+			Msg("Dependency not reachable yet, retrying")
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			dep.gaveUp = true
+			s.mu.Unlock()
+			log.Error().Err(err).Str("dependency", dep.name).Int("attempts", retryCount+1).Msg("Gave up waiting for dependency")
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// status reports why a dependency isn't usable yet, or nil once it connected
+func (s *Startup) status(dep *dependency) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case dep.connected:
+		return nil
+	case dep.gaveUp:
+		return fmt.Errorf("gave up after %d attempts: %w", dep.attempts, dep.err)
+	case dep.err != nil:
+		return fmt.Errorf("connecting (attempt %d failed): %w", dep.attempts, dep.err)
+	default:
+		return errors.New("connecting")
+	}
+}
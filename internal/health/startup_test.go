@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+func startupPolicy() domain.RetryPolicy {
+	return domain.RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond, Multiplier: 2}
+}
+
+func TestStartup_RetriesUntilConnected(t *testing.T) {
+	var attempts atomic.Int32
+	s := NewStartup(time.Second, startupPolicy())
+	s.Connect(context.Background(), "postgres", func(ctx context.Context) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	failed := s.Wait()
+
+	assert.Empty(t, failed)
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestStartup_GivesUpAfterTimeout(t *testing.T) {
+	s := NewStartup(20*time.Millisecond, startupPolicy())
+	s.Connect(context.Background(), "redis", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+	s.Connect(context.Background(), "postgres", passing)
+
+	failed := s.Wait()
+
+	assert.Len(t, failed, 1)
+	assert.EqualError(t, failed["redis"], "connection refused")
+}
+
+func TestStartup_ChecksReportNotReadyUntilStarted(t *testing.T) {
+	release := make(chan struct{})
+	s := NewStartup(time.Second, startupPolicy())
+	s.Connect(context.Background(), "postgres", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	readiness := NewService(time.Second, s.Checks()...)
+
+	report := readiness.Readiness(context.Background())
+	assert.False(t, report.Up())
+	assert.Equal(t, "connecting", report.Dependencies["postgres"].Error)
+	assert.Equal(t, ErrStarting.Error(), report.Dependencies["startup"].Error)
+
+	close(release)
+	s.Wait()
+	report = readiness.Readiness(context.Background())
+	assert.Equal(t, domain.HealthStatusUp, report.Dependencies["postgres"].Status)
+	assert.False(t, report.Up(), "not ready until started")
+
+	s.Started()
+	assert.True(t, readiness.Readiness(context.Background()).Up())
+}
@@ -0,0 +1,194 @@
+// Package kyc verifies user identities with a KYC provider.
+package kyc
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// Headers authenticating provider callbacks
+const (
+	SignatureHeader = "X-KYC-Signature"
+	TimestampHeader = "X-KYC-Timestamp"
+)
+
+// callbackTolerance bounds how old a callback's timestamp may be.
+const callbackTolerance = 5 * time.Minute
+
+// submitTimeout bounds a single request to the provider
+const submitTimeout = 10 * time.Second
+
+// Sign returns the hex HMAC-SHA256 of "timestamp.body" for the X-KYC-Signature header.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// callbackBody is the JSON a provider posts when it decides a verification
+type callbackBody struct {
+	ID     string `json:"id"`
+	Status string `json:"status"` // approved, rejected or review
+	Reason string `json:"reason"`
+}
+
+// parseCallback checks a callback's signature and age, and decodes its result.
+func parseCallback(secret []byte, callback *domain.KYCCallback, now time.Time) (*domain.KYCResult, error) {
+	if len(secret) == 0 {
+		return nil, domain.ErrKYCInvalidCallback
+	}
+	want := Sign(secret, callback.Timestamp, callback.Body)
+	if !hmac.Equal([]byte(want), []byte(callback.Signature)) {
+		return nil, domain.ErrKYCInvalidCallback
+	}
+	unix, err := strconv.ParseInt(callback.Timestamp, 10, 64)
+	if err != nil {
+		return nil, domain.ErrKYCInvalidCallback
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > callbackTolerance || age < -callbackTolerance {
+		return nil, domain.ErrKYCInvalidCallback
+	}
+
+	var body callbackBody
+	if err := json.Unmarshal(callback.Body, &body); err != nil || body.ID == "" {
+		return nil, domain.ErrKYCInvalidCallback
+	}
+	switch body.Status {
+	case domain.KYCStatusApproved, domain.KYCStatusRejected, domain.KYCStatusReview:
+	default:
+		return nil, domain.ErrKYCInvalidCallback
+	}
+	return &domain.KYCResult{ProviderRef: body.ID, Status: body.Status, Reason: body.Reason}, nil
+}
+
+// HTTPProvider submits verifications to a provider's REST API.
+type HTTPProvider struct {
+	client *http.Client
+	url    string
+	apiKey string
+	secret []byte
+	now    func() time.Time
+}
+
+// NewHTTPProvider creates a provider posting verifications to baseURL with apiKey.
+func NewHTTPProvider(baseURL, apiKey, webhookSecret string) (*HTTPProvider, error) {
+	if baseURL == "" || apiKey == "" || webhookSecret == "" {
+		return nil, fmt.Errorf("KYC provider URL, API key and webhook secret are required")
+	}
+	return &HTTPProvider{
+		client: &http.Client{Timeout: submitTimeout},
+		url:    strings.TrimRight(baseURL, "/") + "/verifications",
+		apiKey: apiKey,
+		secret: []byte(webhookSecret),
+		now:    time.Now,
+	}, nil
+}
+
+// Name identifies the provider in stored verifications.
+func (p *HTTPProvider) Name() string {
+	return "http"
+}
+
+// submitRequest is the JSON posted to start a verification
+type submitRequest struct {
+	Reference string `json:"reference"`
+	*domain.KYCSubmission
+}
+
+// submitResponse is the part of the provider's answer the provider reads
+type submitResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// Submit starts a verification.
+func (p *HTTPProvider) Submit(ctx context.Context, userID int, submission *domain.KYCSubmission) (*domain.KYCResult, error) {
+	body, err := json.Marshal(submitRequest{Reference: fmt.Sprintf("user-%d-%s", userID, uuid.NewString()), KYCSubmission: submission})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("KYC provider request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("KYC provider returned status %d: %s", resp.StatusCode, msg)
+	}
+
+	var result submitResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode KYC provider response: %w", err)
+	}
+	if result.ID == "" {
+		return nil, fmt.Errorf("KYC provider response has no id")
+	}
+	switch result.Status {
+	case domain.KYCStatusApproved, domain.KYCStatusRejected, domain.KYCStatusReview:
+	default:
+		result.Status = domain.KYCStatusPending
+	}
+	return &domain.KYCResult{ProviderRef: result.ID, Status: result.Status}, nil
+}
+
+// ParseCallback authenticates a callback and returns the result it reports.
+func (p *HTTPProvider) ParseCallback(callback *domain.KYCCallback) (*domain.KYCResult, error) {
+	return parseCallback(p.secret, callback, p.now())
+}
+
+// Mock decides verifications at once, for development and tests.
+type Mock struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewMock creates a mock provider whose callbacks are signed with secret.
+func NewMock(secret string) *Mock {
+	return &Mock{secret: []byte(secret), now: time.Now}
+}
+
+// Name identifies the provider in stored verifications.
+func (m *Mock) Name() string {
+	return "mock"
+}
+
+// Submit decides the verification from the submission's last name.
+func (m *Mock) Submit(ctx context.Context, userID int, submission *domain.KYCSubmission) (*domain.KYCResult, error) {
+	result := &domain.KYCResult{ProviderRef: "mock-" + uuid.NewString(), Status: domain.KYCStatusApproved}
+	switch strings.TrimSpace(submission.LastName) {
+	case "Reject":
+		result.Status, result.Reason = domain.KYCStatusRejected, "identity could not be confirmed"
+	case "Review":
+		result.Status, result.Reason = domain.KYCStatusReview, "document needs a manual check"
+	}
+	return result, nil
+}
+
+// ParseCallback authenticates a callback and returns the result it reports.
+func (m *Mock) ParseCallback(callback *domain.KYCCallback) (*domain.KYCResult, error) {
+	return parseCallback(m.secret, callback, m.now())
+}
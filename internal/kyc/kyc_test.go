@@ -0,0 +1,102 @@
+package kyc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+func signedCallback(secret string, at time.Time, body string) *domain.KYCCallback {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return &domain.KYCCallback{
+		Signature: Sign([]byte(secret), timestamp, []byte(body)),
+		Timestamp: timestamp,
+		Body:      []byte(body),
+	}
+}
+
+func TestParseCallback(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	body := `{"id": "ref-1", "status": "rejected", "reason": "document expired"}`
+
+	result, err := parseCallback([]byte("secret"), signedCallback("secret", now.Add(-time.Minute), body), now)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.KYCResult{ProviderRef: "ref-1", Status: domain.KYCStatusRejected, Reason: "document expired"}, result)
+
+	tests := map[string]*domain.KYCCallback{
+		"wrong secret":   signedCallback("other", now, body),
+		"expired":        signedCallback("secret", now.Add(-10*time.Minute), body),
+		"unknown status": signedCallback("secret", now, `{"id": "ref-1", "status": "pending"}`),
+		"missing id":     signedCallback("secret", now, `{"status": "approved"}`),
+	}
+	for name, callback := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseCallback([]byte("secret"), callback, now)
+			assert.ErrorIs(t, err, domain.ErrKYCInvalidCallback)
+		})
+	}
+
+	tampered := signedCallback("secret", now, body)
+	tampered.Body = []byte(`{"id": "ref-1", "status": "approved"}`)
+	_, err = parseCallback([]byte("secret"), tampered, now)
+	assert.ErrorIs(t, err, domain.ErrKYCInvalidCallback)
+
+	_, err = parseCallback(nil, signedCallback("", now, body), now)
+	assert.ErrorIs(t, err, domain.ErrKYCInvalidCallback)
+}
+
+func TestHTTPProvider_Submit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/verifications", r.URL.Path)
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "Doe", req["last_name"])
+		w.Write([]byte(`{"id": "ref-1", "status": "processing"}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewHTTPProvider(srv.URL+"/", "key", "secret")
+	require.NoError(t, err)
+
+	result, err := p.Submit(context.Background(), 7, &domain.KYCSubmission{Level: 1, FirstName: "Jane", LastName: "Doe"})
+	require.NoError(t, err)
+	assert.Equal(t, "ref-1", result.ProviderRef)
+	assert.Equal(t, domain.KYCStatusPending, result.Status)
+}
+
+func TestHTTPProvider_ProviderError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	p, err := NewHTTPProvider(srv.URL, "key", "secret")
+	require.NoError(t, err)
+
+	_, err = p.Submit(context.Background(), 7, &domain.KYCSubmission{Level: 1})
+	assert.Error(t, err)
+}
+
+func TestMock_Submit(t *testing.T) {
+	m := NewMock("")
+	for lastName, status := range map[string]string{
+		"Doe":    domain.KYCStatusApproved,
+		"Reject": domain.KYCStatusRejected,
+		"Review": domain.KYCStatusReview,
+	} {
+		result, err := m.Submit(context.Background(), 1, &domain.KYCSubmission{LastName: lastName})
+		require.NoError(t, err)
+		assert.Equal(t, status, result.Status, lastName)
+		assert.NotEmpty(t, result.ProviderRef)
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// AdminIPChecker decides whether an admin may use admin operations from an address
+type AdminIPChecker interface {
+	Allowed(ctx context.Context, userID int, ip, path string) (bool, error)
+}
+
+// ClientIP returns the address of the connection's peer
+func ClientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+// AdminIPAllowlist returns a middleware rejecting admins outside their IP allowlist.
+func AdminIPAllowlist(checker AdminIPChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := UserClaimsFromContext(r.Context())
+			if !ok || claims == nil || claims.Role != "admin" || CertAuthenticated(r.Context()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			userID, err := strconv.Atoi(claims.UserID)
+			if err != nil {
+				WriteProblem(w, r, http.StatusUnauthorized, "invalid user_id in token")
+				return
+			}
+			allowed, err := checker.Allowed(r.Context(), userID, ClientIP(r), r.URL.Path)
+			if err != nil {
+				RespondServiceError(w, r, err, "failed to check admin IP allowlist")
+				return
+			}
+			if !allowed {
+				NewProblem(r, http.StatusForbidden, "admin operations are not allowed from this address").WithCode("admin_ip_not_allowed").Write(w)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// allowIPs allows admin 1 from 203.0.113.7 only
+type allowIPs struct {
+	calls int
+}
+
+func (a *allowIPs) Allowed(_ context.Context, userID int, ip, _ string) (bool, error) {
+	a.calls++
+	return userID == 1 && ip == "203.0.113.7", nil
+}
+
+func TestAdminIPAllowlist(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     *UserClaims
+		remoteAddr string
+		expectCode int
+		checked    bool
+	}{
+		{"admin from allowed address", &UserClaims{UserID: "1", Role: "admin"}, "203.0.113.7:4321", http.StatusOK, true},
+		{"admin from other address", &UserClaims{UserID: "1", Role: "admin"}, "198.51.100.1:4321", http.StatusForbidden, true},
+		{"non-admin is not checked", &UserClaims{UserID: "2", Role: "user"}, "198.51.100.1:4321", http.StatusOK, false},
+		{"unauthenticated is not checked", nil, "198.51.100.1:4321", http.StatusOK, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := &allowIPs{}
+			h := AdminIPAllowlist(checker)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.claims != nil {
+				req = req.WithContext(WithUserClaims(req.Context(), tc.claims))
+			}
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+
+			if rw.Code != tc.expectCode {
+				t.Errorf("expected status %d, got %d", tc.expectCode, rw.Code)
+			}
+			if (checker.calls > 0) != tc.checked {
+				t.Errorf("expected checked=%v, got %d calls", tc.checked, checker.calls)
+			}
+		})
+	}
+}
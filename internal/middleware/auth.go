@@ -4,9 +4,15 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
-	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/tracing"
 )
 
 // JWTValidator defines the interface for validating JWT tokens.
@@ -16,34 +22,42 @@ type JWTValidator interface {
 
 // UserClaims represents the claims extracted from a valid JWT.
 type UserClaims struct {
-	UserID string
-	Role   string
-	JTI    string // JTI is the JWT ID
+	UserID       string
+	Role         string
+	JTI          string   // JTI is the JWT ID
+	ExpiresAt    int64    // Unix time the token expires
+	TokenVersion int64    // version of the user's tokens the token was issued with
+	Scopes       []string // actions the token is limited to; nil if it isn't limited
 }
 
 // AuthMiddleware holds dependencies for authentication middleware.
 type AuthMiddleware struct {
 	validator JWTValidator
-	cache     *redis.Client
+	tokens    domain.TokenStore
 }
 
 // NewAuthMiddleware constructs a new AuthMiddleware with the given validator.
-func NewAuthMiddleware(validator JWTValidator, cache *redis.Client) *AuthMiddleware {
-	return &AuthMiddleware{validator: validator, cache: cache}
+func NewAuthMiddleware(validator JWTValidator, tokens domain.TokenStore) *AuthMiddleware {
+	return &AuthMiddleware{validator: validator, tokens: tokens}
 }
 
 // Middleware is the HTTP middleware function for authentication.
 func (a *AuthMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if CertAuthenticated(r.Context()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		header := r.Header.Get("Authorization")
 		if header == "" {
-			http.Error(w, "Missing Authorization header", http.StatusUnauthorized)
+			WriteProblem(w, r, http.StatusUnauthorized, "Missing Authorization header")
 			return
 		}
 
 		parts := strings.SplitN(header, " ", 2)
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+			WriteProblem(w, r, http.StatusUnauthorized, "Invalid Authorization header format")
 			return
 		}
 
@@ -53,37 +67,51 @@ func (a *AuthMiddleware) Middleware(next http.Handler) http.Handler {
 		claims, err := a.validator.ValidateToken(tokenString)
 		if err != nil {
 			fmt.Printf("Token validation failed: %v\n", err)
-			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+			WriteProblem(w, r, http.StatusUnauthorized, "Invalid or expired token")
 			return
 		}
 
 		fmt.Printf("Token validated successfully for user: %s, role: %s\n", claims.UserID, claims.Role)
 
-		// Check if the token is in the denylist (only if cache is available)
-		if a.cache != nil {
-			// The key exists if Get() returns no error.
-			err := a.cache.Get(r.Context(), "denylist:"+claims.JTI).Err()
-
-			// If err is nil, it means the key was found, so the token IS denied.
-			if err == nil {
-				http.Error(w, "Token has been invalidated", http.StatusUnauthorized)
+		// Check if the token has been revoked (only if a token store is available)
+		if a.tokens != nil {
+			denied, err := a.isRevoked(r.Context(), claims)
+			if err != nil {
+				WriteProblem(w, r, http.StatusInternalServerError, "Internal server error")
 				return
 			}
-
-			// We only proceed if the error is redis.Nil (key not found).
-			// Any other error is a real server error.
-			if err != redis.Nil {
-				// log.Error().Err(err).Msg("Failed to check token denylist")
-				http.Error(w, "Internal server error", http.StatusInternalServerError)
+			if denied {
+				WriteProblem(w, r, http.StatusUnauthorized, "Token has been invalidated")
 				return
 			}
 		}
 
 		ctx := WithUserClaims(r.Context(), claims)
+		logger := zerolog.Ctx(ctx).With().Str("user_id", claims.UserID).Logger()
+		ctx = logger.WithContext(ctx)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// isRevoked reports whether the token was revoked.
+func (a *AuthMiddleware) isRevoked(ctx context.Context, claims *UserClaims) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, "auth.denylist_check")
+	defer span.End()
+
+	userID, err := strconv.Atoi(claims.UserID)
+	if err != nil {
+		return true, nil
+	}
+	revoked, err := a.tokens.IsRevoked(ctx, claims.JTI, userID, claims.TokenVersion)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return false, err
+	}
+	span.SetAttributes(attribute.Bool("auth.revoked", revoked))
+	return revoked, nil
+}
+
 // Context helpers for extracting user claims can be added here.
 
 // contextKey is a private type to avoid context key collisions.
@@ -59,7 +59,7 @@ func TestAuthMiddleware_Middleware(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			validator := &mockValidator{validateFunc: tc.validateFunc}
-			mw := NewAuthMiddleware(validator)
+			mw := NewAuthMiddleware(validator, nil)
 
 			nextCalled := false
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// BruteForceGuard counts failed auth attempts per client address and username.
+type BruteForceGuard interface {
+	// Check returns how long to delay an attempt, or how long until a ban
+	// of the address or username ends
+	Check(ctx context.Context, ip, username string) (delay, retryAfter time.Duration, err error)
+	RecordFailure(ctx context.Context, ip, username string) error
+}
+
+// BruteForceProtection returns a middleware slowing down or refusing failing clients.
+func BruteForceProtection(guard BruteForceGuard, username func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := ClientIP(r)
+			user := ""
+			if username != nil {
+				user = username(r)
+			}
+
+			delay, retryAfter, err := guard.Check(r.Context(), ip, user)
+			if err != nil {
+				log.Ctx(r.Context()).Warn().Err(err).Msg("Failed to check brute-force counters")
+			}
+			if retryAfter > 0 {
+				metrics.BruteForceEventsTotal.WithLabelValues("reject").Inc()
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				NewProblem(r, http.StatusTooManyRequests, "too many failed attempts, try again later").WithCode("too_many_failed_attempts").Write(w)
+				return
+			}
+			if delay > 0 {
+				metrics.BruteForceEventsTotal.WithLabelValues("tarpit").Inc()
+				timer := time.NewTimer(delay)
+				select {
+				case <-timer.C:
+				case <-r.Context().Done():
+					timer.Stop()
+					return
+				}
+			}
+
+			wrapped := &bruteForceResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(wrapped, r)
+			if wrapped.statusCode == http.StatusUnauthorized {
+				if err := guard.RecordFailure(r.Context(), ip, user); err != nil {
+					log.Ctx(r.Context()).Warn().Err(err).Msg("Failed to count failed auth attempt")
+				}
+			}
+		})
+	}
+}
+
+// bruteForceResponseWriter wraps http.ResponseWriter to capture the status code
+type bruteForceResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (rw *bruteForceResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *bruteForceResponseWriter) Write(b []byte) (int, error) {
+	return rw.ResponseWriter.Write(b)
+}
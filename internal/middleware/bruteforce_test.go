@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeBruteForceGuard bans after two failures and records what it was asked
+type fakeBruteForceGuard struct {
+	failures map[string]int
+	checked  []string
+}
+
+func (g *fakeBruteForceGuard) Check(_ context.Context, ip, username string) (time.Duration, time.Duration, error) {
+	g.checked = append(g.checked, ip+"/"+username)
+	if g.failures[username] >= 2 {
+		return 0, 90 * time.Second, nil
+	}
+	return 0, 0, nil
+}
+
+func (g *fakeBruteForceGuard) RecordFailure(_ context.Context, _, username string) error {
+	g.failures[username]++
+	return nil
+}
+
+func TestBruteForceProtection(t *testing.T) {
+	guard := &fakeBruteForceGuard{failures: map[string]int{}}
+	status := http.StatusUnauthorized
+	h := BruteForceProtection(guard, func(r *http.Request) string {
+		return r.URL.Query().Get("user")
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	do := func(user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/auth/login?user="+user, nil)
+		req.RemoteAddr = "203.0.113.7:4321"
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw
+	}
+
+	do("alice")
+	do("alice")
+	rw := do("alice")
+	if rw.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, rw.Code)
+	}
+	if got := rw.Header().Get("Retry-After"); got != "90" {
+		t.Errorf("expected Retry-After 90, got %q", got)
+	}
+	if guard.failures["alice"] != 2 {
+		t.Errorf("expected a refused attempt not to count, got %d failures", guard.failures["alice"])
+	}
+	if guard.checked[0] != "203.0.113.7/alice" {
+		t.Errorf("expected the address and username to be checked, got %q", guard.checked[0])
+	}
+
+	status = http.StatusBadRequest
+	do("bob")
+	if guard.failures["bob"] != 0 {
+		t.Errorf("expected only 401 answers to count, got %d failures", guard.failures["bob"])
+	}
+}
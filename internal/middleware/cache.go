@@ -1,27 +1,62 @@
 package middleware
 
 import (
-	"crypto/md5"
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/rs/zerolog/log"
+
 	"github.com/melihgurlek/backend-path/pkg/cache"
 )
 
-// CacheMiddleware provides HTTP response caching
+// revalidateTimeout bounds a background refresh of a stale response.
+const revalidateTimeout = 30 * time.Second
+
+// varyHeaders are the request headers a cached response may depend on.
+var varyHeaders = []string{"Accept", "Accept-Language"}
+
+// CacheMiddleware provides HTTP response caching.
 type CacheMiddleware struct {
-	cache *cache.RedisCache
-	ttl   time.Duration
+	cache     cache.Cache
+	ttl       time.Duration
+	staleTTL  time.Duration
+	routeTTLs map[string]time.Duration
+
+	// Keys being refreshed in the background, so each is refreshed once at a time
+	revalidating sync.Map
+}
+
+// CacheOption configures a CacheMiddleware.
+type CacheOption func(*CacheMiddleware)
+
+// WithStaleWhileRevalidate serves expired responses for up to staleTTL while refreshing.
+func WithStaleWhileRevalidate(staleTTL time.Duration) CacheOption {
+	return func(m *CacheMiddleware) {
+		m.staleTTL = staleTTL
+	}
+}
+
+// WithRouteTTLs overrides the TTL for paths under each prefix.
+func WithRouteTTLs(ttls map[string]time.Duration) CacheOption {
+	return func(m *CacheMiddleware) {
+		m.routeTTLs = ttls
+	}
 }
 
-// NewCacheMiddleware creates a new cache middleware
-func NewCacheMiddleware(cache *cache.RedisCache, ttl time.Duration) *CacheMiddleware {
-	return &CacheMiddleware{
+// NewCacheMiddleware creates a new cache middleware; ttl is how long a cached response is fresh
+func NewCacheMiddleware(cache cache.Cache, ttl time.Duration, options ...CacheOption) *CacheMiddleware {
+	m := &CacheMiddleware{
 		cache: cache,
 		ttl:   ttl,
 	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
 }
 
 // Middleware caches HTTP responses
@@ -34,7 +69,8 @@ func (m *CacheMiddleware) Middleware(next http.Handler) http.Handler {
 		}
 
 		// Skip caching for certain paths
-		if shouldSkipCache(r.URL.Path) {
+		ttl := m.ttlFor(r.URL.Path)
+		if ttl <= 0 || shouldSkipCache(r.URL.Path) {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -45,15 +81,24 @@ func (m *CacheMiddleware) Middleware(next http.Handler) http.Handler {
 		// Try to get from cache
 		var cachedResponse CachedResponse
 		if found, err := m.cache.Get(r.Context(), cacheKey, &cachedResponse); err == nil && found {
-			// Return cached response
+			cache.ResponseHitRatio.Hit()
+			status := "HIT"
+			if m.staleTTL > 0 && time.Since(cachedResponse.Timestamp) >= ttl {
+				status = "STALE"
+				m.revalidate(next, r, cacheKey, ttl)
+			}
 			w.Header().Set("Content-Type", cachedResponse.ContentType)
-			w.Header().Set("X-Cache", "HIT")
+			setVary(w.Header())
+			w.Header().Set("X-Cache", status)
 			w.WriteHeader(cachedResponse.StatusCode)
 			w.Write(cachedResponse.Body)
 			return
 		}
 
 		// Cache miss, capture response
+		cache.ResponseHitRatio.Miss()
+		setVary(w.Header())
+		w.Header().Set("X-Cache", "MISS")
 		responseWriter := &cacheResponseWriter{
 			ResponseWriter: w,
 			statusCode:     http.StatusOK,
@@ -61,42 +106,94 @@ func (m *CacheMiddleware) Middleware(next http.Handler) http.Handler {
 		}
 
 		next.ServeHTTP(responseWriter, r)
+		m.store(r.Context(), cacheKey, ttl, responseWriter.statusCode, responseWriter.Header().Get("Content-Type"), responseWriter.body)
+	})
+}
 
-		// Cache successful responses
-		if responseWriter.statusCode >= 200 && responseWriter.statusCode < 300 {
-			cachedResponse := CachedResponse{
-				StatusCode:  responseWriter.statusCode,
-				ContentType: responseWriter.Header().Get("Content-Type"),
-				Body:        responseWriter.body,
-				Timestamp:   time.Now(),
-			}
+// store caches a successful response until it is too old to serve even as stale
+func (m *CacheMiddleware) store(ctx context.Context, cacheKey string, ttl time.Duration, statusCode int, contentType string, body []byte) {
+	if statusCode < 200 || statusCode >= 300 {
+		return
+	}
+	cachedResponse := CachedResponse{
+		StatusCode:  statusCode,
+		ContentType: contentType,
+		Body:        body,
+		Timestamp:   time.Now(),
+	}
+	if err := m.cache.Set(ctx, cacheKey, cachedResponse, ttl+m.staleTTL); err != nil {
+		// Log cache set error but don't fail the request
+		log.Warn().Err(err).Str("key", cacheKey).Msg("Failed to cache response")
+	}
+}
+
+// revalidate refreshes the cached response of r in the background.
+func (m *CacheMiddleware) revalidate(next http.Handler, r *http.Request, cacheKey string, ttl time.Duration) {
+	if _, busy := m.revalidating.LoadOrStore(cacheKey, struct{}{}); busy {
+		return
+	}
 
-			if err := m.cache.Set(r.Context(), cacheKey, cachedResponse, m.ttl); err != nil {
-				// Log cache set error but don't fail the request
-				fmt.Printf("Failed to cache response: %v\n", err)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), revalidateTimeout)
+	req := r.Clone(ctx)
+	go func() {
+		defer m.revalidating.Delete(cacheKey)
+		defer cancel()
+		defer func() {
+			if p := recover(); p != nil {
+				log.Error().Interface("panic", p).Str("path", req.URL.Path).Msg("Panic while revalidating cached response")
 			}
-		}
+		}()
 
-		// Add cache miss header
-		w.Header().Set("X-Cache", "MISS")
-	})
+		rec := &bufferedResponseWriter{header: http.Header{}, statusCode: http.StatusOK}
+		next.ServeHTTP(rec, req)
+		m.store(ctx, cacheKey, ttl, rec.statusCode, rec.header.Get("Content-Type"), rec.body)
+	}()
 }
 
-// generateCacheKey creates a unique cache key for the request
+// ttlFor returns how long responses for path stay fresh
+func (m *CacheMiddleware) ttlFor(path string) time.Duration {
+	ttl, longest := m.ttl, -1
+	for prefix, routeTTL := range m.routeTTLs {
+		// Match whole path segments, so /users does not cover /users-export
+		prefix = strings.TrimSuffix(prefix, "/")
+		if len(prefix) <= longest || !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		if len(path) == len(prefix) || path[len(prefix)] == '/' {
+			ttl, longest = routeTTL, len(prefix)
+		}
+	}
+	return ttl
+}
+
+// generateCacheKey creates a cache key for the request scoped to its user.
 func (m *CacheMiddleware) generateCacheKey(r *http.Request) string {
-	// Include method, path, and query parameters
-	key := fmt.Sprintf("%s:%s?%s", r.Method, r.URL.Path, r.URL.RawQuery)
+	scope := cache.PublicScope
+	role := ""
+	if claims, ok := UserClaimsFromContext(r.Context()); ok {
+		scope = cache.UserScope(claims.UserID)
+		role = claims.Role
+	}
+
+	// Include method, path, query parameters, the caller's role and the headers
+	// responses vary on
+	var key strings.Builder
+	fmt.Fprintf(&key, "%s:%s?%s|role=%s", r.Method, r.URL.Path, r.URL.RawQuery, role)
+	for _, h := range varyHeaders {
+		fmt.Fprintf(&key, "|%s=%s", h, strings.Join(r.Header.Values(h), ","))
+	}
+	return cache.ResponseKey(scope, key.String())
+}
 
-	// Create MD5 hash for consistent key length
-	hash := md5.Sum([]byte(key))
-	return fmt.Sprintf("http_cache:%x", hash)
+// setVary tells downstream caches which request headers responses depend on
+func setVary(h http.Header) {
+	h.Set("Vary", "Authorization, "+strings.Join(varyHeaders, ", "))
 }
 
 // shouldSkipCache determines if a request should skip caching
 func shouldSkipCache(path string) bool {
 	skipPaths := []string{
 		"/metrics",
-		"/api/v1/test/health",
 		"/api/v1/test/panic",
 		"/api/v1/test/error",
 	}
@@ -133,3 +230,19 @@ func (rw *cacheResponseWriter) Write(b []byte) (int, error) {
 	rw.body = append(rw.body, b...)
 	return rw.ResponseWriter.Write(b)
 }
+
+// bufferedResponseWriter records a response produced with no client attached
+type bufferedResponseWriter struct {
+	header     http.Header
+	statusCode int
+	body       []byte
+}
+
+func (rw *bufferedResponseWriter) Header() http.Header { return rw.header }
+
+func (rw *bufferedResponseWriter) WriteHeader(code int) { rw.statusCode = code }
+
+func (rw *bufferedResponseWriter) Write(b []byte) (int, error) {
+	rw.body = append(rw.body, b...)
+	return len(b), nil
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/pkg/cache"
+)
+
+// countingHandler answers with the number of times it has been called.
+func countingHandler(calls *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte{byte('0' + n)})
+	})
+}
+
+func serveCached(h http.Handler) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/transactions/history", nil))
+	return rec
+}
+
+func TestCacheMiddleware_HitAndMiss(t *testing.T) {
+	var calls atomic.Int32
+	h := NewCacheMiddleware(cache.NewMemoryCache(100), time.Minute).Middleware(countingHandler(&calls))
+
+	first := serveCached(h)
+	assert.Equal(t, "MISS", first.Header().Get("X-Cache"))
+	assert.Equal(t, "1", first.Body.String())
+
+	second := serveCached(h)
+	assert.Equal(t, "HIT", second.Header().Get("X-Cache"))
+	assert.Equal(t, "1", second.Body.String())
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCacheMiddleware_StaleWhileRevalidate(t *testing.T) {
+	var calls atomic.Int32
+	m := NewCacheMiddleware(cache.NewMemoryCache(100), time.Nanosecond, WithStaleWhileRevalidate(time.Minute))
+	h := m.Middleware(countingHandler(&calls))
+
+	serveCached(h)
+
+	// The fresh period is over: the old response is served while it is refreshed
+	stale := serveCached(h)
+	assert.Equal(t, "STALE", stale.Header().Get("X-Cache"))
+	assert.Equal(t, "1", stale.Body.String())
+
+	require.Eventually(t, func() bool {
+		_, busy := m.revalidating.Load(m.generateCacheKey(httptest.NewRequest(http.MethodGet, "/api/v1/transactions/history", nil)))
+		return !busy
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, "2", serveCached(h).Body.String())
+}
+
+func serveCachedAs(h http.Handler, claims *UserClaims, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/balances/current", nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if claims != nil {
+		req = req.WithContext(WithUserClaims(req.Context(), claims))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCacheMiddleware_IsolatesUsers(t *testing.T) {
+	var calls atomic.Int32
+	h := NewCacheMiddleware(cache.NewMemoryCache(100), time.Minute).Middleware(countingHandler(&calls))
+	alice := &UserClaims{UserID: "1", Role: "user"}
+	bob := &UserClaims{UserID: "2", Role: "user"}
+
+	assert.Equal(t, "1", serveCachedAs(h, alice, nil).Body.String())
+
+	// Another user, or an anonymous caller, never gets alice's response
+	bobRec := serveCachedAs(h, bob, nil)
+	assert.Equal(t, "MISS", bobRec.Header().Get("X-Cache"))
+	assert.Equal(t, "2", bobRec.Body.String())
+	anonRec := serveCachedAs(h, nil, nil)
+	assert.Equal(t, "MISS", anonRec.Header().Get("X-Cache"))
+	assert.Equal(t, "3", anonRec.Body.String())
+
+	// Each user still hits their own entry
+	aliceRec := serveCachedAs(h, alice, nil)
+	assert.Equal(t, "HIT", aliceRec.Header().Get("X-Cache"))
+	assert.Equal(t, "1", aliceRec.Body.String())
+	assert.Equal(t, "2", serveCachedAs(h, bob, nil).Body.String())
+
+	// A role change is not served a response cached under the old role
+	admin := &UserClaims{UserID: "1", Role: "admin"}
+	assert.Equal(t, "MISS", serveCachedAs(h, admin, nil).Header().Get("X-Cache"))
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestCacheMiddleware_VariesOnHeaders(t *testing.T) {
+	var calls atomic.Int32
+	h := NewCacheMiddleware(cache.NewMemoryCache(100), time.Minute).Middleware(countingHandler(&calls))
+	user := &UserClaims{UserID: "1", Role: "user"}
+
+	english := http.Header{"Accept-Language": {"en"}}
+	turkish := http.Header{"Accept-Language": {"tr"}}
+
+	first := serveCachedAs(h, user, english)
+	assert.Equal(t, "MISS", first.Header().Get("X-Cache"))
+	assert.Contains(t, first.Header().Get("Vary"), "Accept-Language")
+	assert.Equal(t, "MISS", serveCachedAs(h, user, turkish).Header().Get("X-Cache"))
+	assert.Equal(t, "HIT", serveCachedAs(h, user, english).Header().Get("X-Cache"))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestCacheMiddleware_RouteTTLs(t *testing.T) {
+	m := NewCacheMiddleware(cache.NewMemoryCache(100), time.Minute, WithRouteTTLs(map[string]time.Duration{
+		"/api/v1/balances":         10 * time.Second,
+		"/api/v1/balances/history": 0,
+		"/api/v1/users/":           5 * time.Minute,
+	}))
+
+	assert.Equal(t, 10*time.Second, m.ttlFor("/api/v1/balances"))
+	assert.Equal(t, 10*time.Second, m.ttlFor("/api/v1/balances/current"))
+	assert.Equal(t, time.Duration(0), m.ttlFor("/api/v1/balances/history"), "longest prefix wins")
+	assert.Equal(t, 5*time.Minute, m.ttlFor("/api/v1/users/7"))
+	assert.Equal(t, time.Minute, m.ttlFor("/api/v1/users-export"), "prefixes match whole segments")
+	assert.Equal(t, time.Minute, m.ttlFor("/api/v1/transactions/history"))
+
+	var calls atomic.Int32
+	h := m.Middleware(countingHandler(&calls))
+	for range 2 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/balances/history", nil))
+		assert.Empty(t, rec.Header().Get("X-Cache"), "uncached routes pass straight through")
+	}
+	assert.Equal(t, int32(2), calls.Load())
+}
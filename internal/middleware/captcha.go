@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// CaptchaTokenHeader carries the client's CAPTCHA response
+const CaptchaTokenHeader = "X-Captcha-Token"
+
+// CaptchaVerifier checks CAPTCHA responses with a provider
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) (bool, error)
+}
+
+// CaptchaFailures counts failed attempts per key
+type CaptchaFailures interface {
+	Failures(ctx context.Context, key string) (int, error)
+	AddFailure(ctx context.Context, key string) error
+}
+
+// CaptchaGuard asks clients for a CAPTCHA once they have failed an endpoint repeatedly.
+type CaptchaGuard struct {
+	verifier  CaptchaVerifier
+	failures  CaptchaFailures
+	threshold int
+}
+
+// NewCaptchaGuard creates a guard requiring a CAPTCHA after threshold failures.
+func NewCaptchaGuard(verifier CaptchaVerifier, failures CaptchaFailures, threshold int) *CaptchaGuard {
+	return &CaptchaGuard{verifier: verifier, failures: failures, threshold: threshold}
+}
+
+// Middleware guards the endpoint named scope.
+func (g *CaptchaGuard) Middleware(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := ClientIP(r)
+			key := scope + ":" + ip
+
+			failures, err := g.failures.Failures(r.Context(), key)
+			if err != nil {
+				log.Ctx(r.Context()).Warn().Err(err).Str("scope", scope).Msg("Failed to count captcha failures")
+			} else if failures >= g.threshold {
+				token := r.Header.Get(CaptchaTokenHeader)
+				if token == "" {
+					metrics.CaptchaChallengesTotal.WithLabelValues(scope, "required").Inc()
+					NewProblem(r, http.StatusForbidden, "a CAPTCHA is required").WithCode("captcha_required").Write(w)
+					return
+				}
+				ok, err := g.verifier.Verify(r.Context(), token, ip)
+				if err != nil {
+					metrics.CaptchaChallengesTotal.WithLabelValues(scope, "error").Inc()
+					log.Ctx(r.Context()).Error().Err(err).Str("scope", scope).Msg("Failed to verify captcha")
+					WriteProblem(w, r, http.StatusServiceUnavailable, "CAPTCHA verification is unavailable")
+					return
+				}
+				if !ok {
+					metrics.CaptchaChallengesTotal.WithLabelValues(scope, "invalid").Inc()
+					g.addFailure(r.Context(), scope, key)
+					NewProblem(r, http.StatusForbidden, "the CAPTCHA is invalid or expired").WithCode("captcha_invalid").Write(w)
+					return
+				}
+				metrics.CaptchaChallengesTotal.WithLabelValues(scope, "passed").Inc()
+			}
+
+			wrapped := &captchaResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(wrapped, r)
+			if wrapped.statusCode >= 400 && wrapped.statusCode < 500 {
+				g.addFailure(r.Context(), scope, key)
+			}
+		})
+	}
+}
+
+// addFailure counts a failed attempt; a failure to count it is only logged
+func (g *CaptchaGuard) addFailure(ctx context.Context, scope, key string) {
+	if err := g.failures.AddFailure(ctx, key); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("scope", scope).Msg("Failed to count captcha failure")
+	}
+}
+
+// captchaResponseWriter wraps http.ResponseWriter to capture the status code
+type captchaResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (rw *captchaResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *captchaResponseWriter) Write(b []byte) (int, error) {
+	return rw.ResponseWriter.Write(b)
+}
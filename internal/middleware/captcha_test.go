@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// tokenVerifier accepts the CAPTCHA response "solved" only
+type tokenVerifier struct{}
+
+func (tokenVerifier) Verify(_ context.Context, token, _ string) (bool, error) {
+	return token == "solved", nil
+}
+
+// countFailures counts failures in a map
+type countFailures map[string]int
+
+func (c countFailures) Failures(_ context.Context, key string) (int, error) {
+	return c[key], nil
+}
+
+func (c countFailures) AddFailure(_ context.Context, key string) error {
+	c[key]++
+	return nil
+}
+
+func TestCaptchaGuard(t *testing.T) {
+	failures := countFailures{}
+	guard := NewCaptchaGuard(tokenVerifier{}, failures, 2)
+	status := http.StatusUnauthorized
+	h := guard.Middleware("login")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	do := func(token string) int {
+		req := httptest.NewRequest("POST", "/auth/login", nil)
+		req.RemoteAddr = "203.0.113.7:4321"
+		if token != "" {
+			req.Header.Set(CaptchaTokenHeader, token)
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw.Code
+	}
+
+	// Failures below the threshold need no CAPTCHA
+	for i := 0; i < 2; i++ {
+		if code := do(""); code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: expected status %d, got %d", i+1, http.StatusUnauthorized, code)
+		}
+	}
+	if code := do(""); code != http.StatusForbidden {
+		t.Errorf("expected a CAPTCHA to be required, got status %d", code)
+	}
+	if code := do("wrong"); code != http.StatusForbidden {
+		t.Errorf("expected an invalid CAPTCHA to be refused, got status %d", code)
+	}
+	if failures["login:203.0.113.7"] != 3 {
+		t.Errorf("expected an invalid CAPTCHA to count as a failure, got %d failures", failures["login:203.0.113.7"])
+	}
+
+	status = http.StatusOK
+	if code := do("solved"); code != http.StatusOK {
+		t.Errorf("expected a solved CAPTCHA to pass, got status %d", code)
+	}
+	if failures["login:203.0.113.7"] != 3 {
+		t.Errorf("expected a success not to count as a failure, got %d failures", failures["login:203.0.113.7"])
+	}
+}
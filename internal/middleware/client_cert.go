@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+type certAuthenticatedKey struct{}
+
+// CertAuthenticated reports whether a request was authenticated by a client certificate.
+func CertAuthenticated(ctx context.Context) bool {
+	ok, _ := ctx.Value(certAuthenticatedKey{}).(bool)
+	return ok
+}
+
+// ClientCertIdentity returns the subject common name of the request's verified client certificate
+func ClientCertIdentity(r *http.Request) (string, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	return r.TLS.VerifiedChains[0][0].Subject.CommonName, true
+}
+
+// ClientCertAuth returns a middleware authenticating requests by client certificate.
+func ClientCertAuth(identities map[string]UserClaims) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, ok := ClientCertIdentity(r)
+			if !ok {
+				WriteProblem(w, r, http.StatusUnauthorized, "a client certificate is required")
+				return
+			}
+			claims, ok := identities[identity]
+			if !ok {
+				NewProblem(r, http.StatusForbidden, "client certificate is not authorized").WithCode("client_cert_unknown").Write(w)
+				return
+			}
+
+			ctx := WithUserClaims(r.Context(), &claims)
+			ctx = context.WithValue(ctx, certAuthenticatedKey{}, true)
+			logger := zerolog.Ctx(ctx).With().Str("user_id", claims.UserID).Str("client_cert", identity).Logger()
+			ctx = logger.WithContext(ctx)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ServeOnlyPaths returns a middleware answering 404 outside the path prefixes.
+func ServeOnlyPaths(prefixes []string) func(http.Handler) http.Handler {
+	return filterPaths(prefixes, true)
+}
+
+// HidePaths returns a middleware answering 404 under the path prefixes.
+func HidePaths(prefixes []string) func(http.Handler) http.Handler {
+	return filterPaths(prefixes, false)
+}
+
+func filterPaths(prefixes []string, serve bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if matchesPathPrefix(r.URL.Path, prefixes) != serve {
+				WriteProblem(w, r, http.StatusNotFound, "not found")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// matchesPathPrefix reports whether p, without its API prefix, is one of prefixes or lies under one
+func matchesPathPrefix(p string, prefixes []string) bool {
+	p = path.Clean("/" + p)
+	if rest, ok := strings.CutPrefix(p, "/api/"); ok {
+		if isVersionSegment(rest) {
+			_, rest, _ = strings.Cut(rest, "/")
+		}
+		p = "/" + rest
+	}
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
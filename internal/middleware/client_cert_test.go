@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// withClientCert makes req look like it came with a verified certificate for name
+func withClientCert(req *http.Request, name string) *http.Request {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: name}}
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	return req
+}
+
+func TestClientCertAuth(t *testing.T) {
+	identities := map[string]UserClaims{"ops-console": {UserID: "1", Role: "admin"}}
+	var got *UserClaims
+	// Authentication by token is skipped for certificate-authenticated requests
+	h := ClientCertAuth(identities)(NewAuthMiddleware(nil, nil).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = UserClaimsFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	tests := []struct {
+		name       string
+		cert       string
+		expectCode int
+	}{
+		{"known identity", "ops-console", http.StatusOK},
+		{"unknown identity", "someone-else", http.StatusForbidden},
+		{"no certificate", "", http.StatusUnauthorized},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got = nil
+			req := httptest.NewRequest("GET", "/api/v2/admin/overview", nil)
+			if tc.cert != "" {
+				req = withClientCert(req, tc.cert)
+			}
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+
+			if rw.Code != tc.expectCode {
+				t.Fatalf("expected status %d, got %d", tc.expectCode, rw.Code)
+			}
+			if tc.expectCode == http.StatusOK && (got == nil || got.UserID != "1" || got.Role != "admin") {
+				t.Errorf("expected the identity's claims, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestPathFilters(t *testing.T) {
+	prefixes := []string{"/admin", "/metrics"}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	only, hide := ServeOnlyPaths(prefixes)(ok), HidePaths(prefixes)(ok)
+
+	tests := []struct {
+		path     string
+		internal bool
+	}{
+		{"/api/v2/admin/overview", true},
+		{"/api/admin/audit", true},
+		{"/api/v1/metrics/business", true},
+		{"/metrics", true},
+		{"/api/v2//admin/overview", true},
+		{"/api/v2/administrators", false},
+		{"/api/v2/users/1", false},
+		{"/healthz", false},
+	}
+	for _, tc := range tests {
+		rw := httptest.NewRecorder()
+		only.ServeHTTP(rw, httptest.NewRequest("GET", tc.path, nil))
+		if (rw.Code == http.StatusOK) != tc.internal {
+			t.Errorf("%s: internal listener answered %d", tc.path, rw.Code)
+		}
+		rw = httptest.NewRecorder()
+		hide.ServeHTTP(rw, httptest.NewRequest("GET", tc.path, nil))
+		if (rw.Code == http.StatusOK) == tc.internal {
+			t.Errorf("%s: public listener answered %d", tc.path, rw.Code)
+		}
+	}
+}
@@ -1,20 +1,48 @@
 package middleware
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"runtime/debug"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
 )
 
-// ErrorResponse represents a standardized error response.
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
-	Code    int    `json:"code"`
+// statusForKind is the HTTP status of each kind of domain error
+var statusForKind = map[domain.ErrorKind]int{
+	domain.ErrorKindValidation:        http.StatusBadRequest,
+	domain.ErrorKindUnauthorized:      http.StatusUnauthorized,
+	domain.ErrorKindNotFound:          http.StatusNotFound,
+	domain.ErrorKindForbidden:         http.StatusForbidden,
+	domain.ErrorKindConflict:          http.StatusConflict,
+	domain.ErrorKindGone:              http.StatusGone,
+	domain.ErrorKindInsufficientFunds: http.StatusUnprocessableEntity,
+	domain.ErrorKindLimitExceeded:     http.StatusUnprocessableEntity,
+	domain.ErrorKindUnavailable:       http.StatusServiceUnavailable,
+	domain.ErrorKindPending:           http.StatusAccepted,
+}
+
+// StatusForError returns the HTTP status for a service error.
+func StatusForError(err error) int {
+	kind, _ := domain.ClassifyError(err)
+	if status, ok := statusForKind[kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// RespondServiceError answers a request whose service call failed with err.
+func RespondServiceError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	kind, code := domain.ClassifyError(err)
+	msg := err.Error()
+	if kind == domain.ErrorKindInternal {
+		log.Ctx(r.Context()).Error().Err(err).Msg(fallback)
+		msg = fallback
+	}
+	NewProblem(r, StatusForError(err), msg).WithCode(code).Write(w)
 }
 
 // ErrorHandler defines the interface for custom error handling.
@@ -32,7 +60,7 @@ func NewDefaultErrorHandler(logger zerolog.Logger) *DefaultErrorHandler {
 	return &DefaultErrorHandler{logger: logger}
 }
 
-// HandleError logs the error and sends a JSON error response.
+// HandleError logs the error and sends a Problem Details response.
 func (h *DefaultErrorHandler) HandleError(w http.ResponseWriter, r *http.Request, err error, statusCode int) {
 	// Log the error with request context
 	h.logger.Error().
@@ -44,28 +72,12 @@ func (h *DefaultErrorHandler) HandleError(w http.ResponseWriter, r *http.Request
 		Int("status_code", statusCode).
 		Msg("request error")
 
-	// Send JSON error response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-
-	response := ErrorResponse{
-		Error: http.StatusText(statusCode),
-		Code:  statusCode,
-	}
-
-	// Include error message for client errors (4xx), but not for server errors (5xx)
+	// Include the error message for client errors (4xx), but not for server errors (5xx)
+	detail := "Internal server error"
 	if statusCode < 500 && err != nil {
-		response.Message = err.Error()
-	} else if statusCode >= 500 {
-		response.Message = "Internal server error"
-		response.Error = "Internal server error"
-	}
-
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		// Fallback to plain text if JSON encoding fails
-		h.logger.Error().Err(err).Msg("failed to encode error response")
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		detail = err.Error()
 	}
+	WriteProblem(w, r, statusCode, detail)
 }
 
 // ErrorHandlingMiddleware returns a middleware that handles panics and errors.
@@ -3,12 +3,16 @@ package middleware
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
 )
 
 func TestErrorHandlingMiddleware(t *testing.T) {
@@ -83,14 +87,14 @@ func TestErrorHandlingMiddleware(t *testing.T) {
 
 			// Check if error response was sent
 			if tt.expectError {
-				var response ErrorResponse
+				var response Problem
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectStatus, response.Code)
-				assert.NotEmpty(t, response.Error)
+				assert.Equal(t, tt.expectStatus, response.Status)
+				assert.NotEmpty(t, response.Title)
 
 				if tt.expectPanic {
-					assert.Equal(t, "Internal server error", response.Message)
+					assert.Equal(t, "Internal server error", response.Detail)
 				}
 			}
 
@@ -143,13 +147,14 @@ func TestDefaultErrorHandler_HandleError(t *testing.T) {
 
 			// Check response
 			assert.Equal(t, tt.statusCode, w.Code)
-			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
 
-			var response ErrorResponse
+			var response Problem
 			err := json.Unmarshal(w.Body.Bytes(), &response)
 			assert.NoError(t, err)
-			assert.Equal(t, tt.statusCode, response.Code)
-			assert.Equal(t, tt.expectMsg, response.Message)
+			assert.Equal(t, tt.statusCode, response.Status)
+			assert.Equal(t, tt.expectMsg, response.Detail)
+			assert.Equal(t, "/test", response.Instance)
 
 			// Check logging
 			assert.Contains(t, logBuffer.String(), tt.expectLogMsg)
@@ -196,9 +201,71 @@ func TestErrorMiddleware_ConvenienceFunction(t *testing.T) {
 	// Should handle panic and return 500
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
-	var response ErrorResponse
+	var response Problem
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Equal(t, http.StatusInternalServerError, response.Code)
-	assert.Equal(t, "Internal server error", response.Message)
+	assert.Equal(t, http.StatusInternalServerError, response.Status)
+	assert.Equal(t, "Internal server error", response.Detail)
+}
+
+func TestRespondServiceError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantError  string
+		wantCode   string
+	}{
+		{
+			name:       "not found",
+			err:        domain.ErrHoldNotFound,
+			wantStatus: http.StatusNotFound,
+			wantError:  domain.ErrHoldNotFound.Error(),
+			wantCode:   "hold_not_found",
+		},
+		{
+			name:       "wrapped insufficient funds",
+			err:        fmt.Errorf("debit: %w", domain.ErrInsufficientFunds),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantError:  "debit: insufficient balance",
+			wantCode:   "insufficient_funds",
+		},
+		{
+			name:       "unauthorized",
+			err:        domain.ErrInvalidCredentials,
+			wantStatus: http.StatusUnauthorized,
+			wantError:  "invalid username or password",
+			wantCode:   "invalid_credentials",
+		},
+		{
+			name:       "validation",
+			err:        &domain.ValidationError{Msg: "amount must be positive"},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "amount must be positive",
+			wantCode:   "validation_failed",
+		},
+		{
+			name:       "internal error is not leaked",
+			err:        errors.New("pq: connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantError:  "failed to place hold",
+			wantCode:   "internal_error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/holds", nil)
+			w := httptest.NewRecorder()
+
+			RespondServiceError(w, req, tt.err, "failed to place hold")
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var body Problem
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, tt.wantError, body.Detail)
+			assert.Equal(t, tt.wantCode, body.Code)
+			assert.Equal(t, ProblemTypePrefix+tt.wantCode, body.Type)
+		})
+	}
 }
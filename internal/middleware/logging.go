@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the request ID.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds a request ID supplied by the client
+const maxRequestIDLength = 128
+
+// RequestLogger returns a middleware putting a request-scoped logger in the context.
+func RequestLogger() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if !validRequestID(requestID) {
+				requestID = uuid.NewString()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			logCtx := log.With().
+				Str("request_id", requestID).
+				Str("method", r.Method).
+				Str("path", r.URL.Path)
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				logCtx = logCtx.Str("trace_id", sc.TraceID().String())
+			}
+			logger := logCtx.Logger()
+
+			// The route pattern is only complete once chi has routed the request,
+			// so it is read when each line is written
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				logger = logger.Hook(zerolog.HookFunc(func(e *zerolog.Event, _ zerolog.Level, _ string) {
+					if pattern := rctx.RoutePattern(); pattern != "" {
+						e.Str("route", pattern)
+					}
+				}))
+			}
+
+			next.ServeHTTP(w, r.WithContext(logger.WithContext(r.Context())))
+		})
+	}
+}
+
+// validRequestID reports whether a client supplied request ID is safe to log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
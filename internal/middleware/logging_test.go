@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var logBuffer bytes.Buffer
+	previous := log.Logger
+	log.Logger = zerolog.New(&logBuffer)
+	defer func() { log.Logger = previous }()
+
+	r := chi.NewRouter()
+	r.Use(RequestLogger())
+	r.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		log.Ctx(r.Context()).Info().Msg("handled")
+	})
+
+	t.Run("uses the client's request ID", func(t *testing.T) {
+		logBuffer.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/items/7", nil)
+		req.Header.Set(RequestIDHeader, "abc-123")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		assert.Equal(t, "abc-123", rec.Header().Get(RequestIDHeader))
+		var line map[string]string
+		require.NoError(t, json.Unmarshal(logBuffer.Bytes(), &line))
+		assert.Equal(t, "abc-123", line["request_id"])
+		assert.Equal(t, "/items/{id}", line["route"])
+		assert.Equal(t, "/items/7", line["path"])
+	})
+
+	t.Run("replaces an unsafe request ID", func(t *testing.T) {
+		logBuffer.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/items/7", nil)
+		req.Header.Set(RequestIDHeader, "forged\nline")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		id := rec.Header().Get(RequestIDHeader)
+		assert.NotEqual(t, "forged\nline", id)
+		assert.Len(t, id, 36, "a generated UUID")
+		assert.Contains(t, logBuffer.String(), id)
+	})
+}
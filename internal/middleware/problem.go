@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProblemContentType is the media type of Problem Details responses (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix prefixes the code of a domain error to form its problem type.
+const ProblemTypePrefix = "/problems/"
+
+// Problem is the body of every error response, following RFC 7807.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code,omitempty"`
+	TraceID  string `json:"trace_id,omitempty"`
+}
+
+// NewProblem creates an "about:blank" Problem for a failed request r
+func NewProblem(r *http.Request, status int, detail string) *Problem {
+	p := &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	}
+	if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+		p.TraceID = sc.TraceID().String()
+	}
+	return p
+}
+
+// WithCode sets the stable code of p and derives its type from it
+func (p *Problem) WithCode(code string) *Problem {
+	p.Code = code
+	p.Type = ProblemTypePrefix + code
+	return p
+}
+
+// Write sends p as the response
+func (p *Problem) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// WriteProblem answers r with an "about:blank" Problem of the given status and detail
+func WriteProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	NewProblem(r, status, detail).Write(w)
+}
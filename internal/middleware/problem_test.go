@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWriteProblem(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/holds/7", nil)
+	w := httptest.NewRecorder()
+
+	WriteProblem(w, req, http.StatusBadRequest, "invalid hold id")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	var body Problem
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, Problem{
+		Type:     "about:blank",
+		Title:    "Bad Request",
+		Status:   http.StatusBadRequest,
+		Detail:   "invalid hold id",
+		Instance: "/api/v1/holds/7",
+	}, body)
+}
+
+func TestWriteProblem_TraceID(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/holds/7", nil)
+	req = req.WithContext(trace.ContextWithSpanContext(req.Context(), sc))
+	w := httptest.NewRecorder()
+
+	WriteProblem(w, req, http.StatusNotFound, "hold not found")
+
+	var body Problem
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", body.TraceID)
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// QuotaConsumer counts a user's request against their request quotas
+type QuotaConsumer interface {
+	Consume(ctx context.Context, userID int, role string) ([]domain.QuotaUsage, error)
+}
+
+// RequestQuota returns a middleware counting requests against their user's quotas.
+func RequestQuota(consumer QuotaConsumer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := UserClaimsFromContext(r.Context())
+			if !ok || claims == nil || CertAuthenticated(r.Context()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			userID, err := strconv.Atoi(claims.UserID)
+			if err != nil {
+				WriteProblem(w, r, http.StatusUnauthorized, "invalid user_id in token")
+				return
+			}
+			usage, err := consumer.Consume(r.Context(), userID, claims.Role)
+			if err != nil {
+				log.Ctx(r.Context()).Warn().Err(err).Int("user_id", userID).Msg("Failed to count request against quota; letting it through")
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(usage) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tightest := usage[0]
+			for _, u := range usage[1:] {
+				if u.Remaining < tightest.Remaining || (u.Remaining == tightest.Remaining && u.ResetAt.Before(tightest.ResetAt)) {
+					tightest = u
+				}
+			}
+			// Over any quota, the request waits for the last of them to reset
+			var exceeded *domain.QuotaUsage
+			for i, u := range usage {
+				if u.Exceeded() && (exceeded == nil || u.ResetAt.After(exceeded.ResetAt)) {
+					exceeded = &usage[i]
+				}
+			}
+			if exceeded != nil {
+				tightest = *exceeded
+			}
+
+			w.Header().Set("X-Quota-Limit", strconv.FormatInt(tightest.Limit, 10))
+			w.Header().Set("X-Quota-Remaining", strconv.FormatInt(tightest.Remaining, 10))
+			w.Header().Set("X-Quota-Reset", strconv.FormatInt(tightest.ResetAt.Unix(), 10))
+			if exceeded != nil {
+				metrics.QuotaExceededTotal.WithLabelValues(exceeded.Period).Inc()
+				retryAfter := int(time.Until(exceeded.ResetAt).Seconds()) + 1
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				NewProblem(r, http.StatusTooManyRequests, "request quota exceeded for this "+exceeded.Period+"; it resets at "+exceeded.ResetAt.Format(time.RFC3339)).
+					WithCode("quota_exceeded").Write(w)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// fakeQuotaConsumer counts requests against a daily and a monthly quota
+type fakeQuotaConsumer struct {
+	daily, monthly int64
+	used           int64
+	err            error
+}
+
+func (c *fakeQuotaConsumer) Consume(_ context.Context, _ int, role string) ([]domain.QuotaUsage, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if role == "admin" {
+		return nil, nil
+	}
+	c.used++
+	reset := time.Now().Add(time.Hour)
+	return []domain.QuotaUsage{
+		{Period: domain.QuotaPeriodDay, Limit: c.daily, Used: c.used, Remaining: max(c.daily-c.used, 0), ResetAt: reset},
+		{Period: domain.QuotaPeriodMonth, Limit: c.monthly, Used: c.used, Remaining: max(c.monthly-c.used, 0), ResetAt: reset.Add(24 * time.Hour)},
+	}, nil
+}
+
+func TestRequestQuota(t *testing.T) {
+	consumer := &fakeQuotaConsumer{daily: 2, monthly: 10}
+	h := RequestQuota(consumer)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/balances/current", nil)
+		req = req.WithContext(WithUserClaims(req.Context(), &UserClaims{UserID: "1", Role: role}))
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw
+	}
+
+	rw := do("user")
+	if rw.Code != http.StatusOK || rw.Header().Get("X-Quota-Limit") != "2" || rw.Header().Get("X-Quota-Remaining") != "1" {
+		t.Errorf("expected 200 with the daily quota's headers, got %d %v", rw.Code, rw.Header())
+	}
+	do("user")
+	rw = do("user")
+	if rw.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 over the daily quota, got %d", rw.Code)
+	}
+	if rw.Header().Get("Retry-After") == "" || rw.Header().Get("X-Quota-Remaining") != "0" {
+		t.Errorf("expected Retry-After and no remaining requests, got %v", rw.Header())
+	}
+
+	rw = do("admin")
+	if rw.Code != http.StatusOK || rw.Header().Get("X-Quota-Limit") != "" {
+		t.Errorf("expected users without quotas to pass without headers, got %d %v", rw.Code, rw.Header())
+	}
+
+	consumer.err = errors.New("redis down")
+	if rw = do("user"); rw.Code != http.StatusOK {
+		t.Errorf("expected requests to pass when they can't be counted, got %d", rw.Code)
+	}
+}
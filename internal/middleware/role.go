@@ -16,11 +16,11 @@ func RequireRoles(allowedRoles ...string) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			claims, ok := UserClaimsFromContext(r.Context())
 			if !ok || claims == nil {
-				http.Error(w, "Unauthorized: missing user claims", http.StatusUnauthorized)
+				WriteProblem(w, r, http.StatusUnauthorized, "missing user claims")
 				return
 			}
 			if _, ok := roleSet[claims.Role]; !ok {
-				http.Error(w, "Forbidden: insufficient role", http.StatusForbidden)
+				WriteProblem(w, r, http.StatusForbidden, "insufficient role")
 				return
 			}
 			next.ServeHTTP(w, r)
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Scopes limit what a token may do to actions on some resources.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// scopeResources maps API path prefixes to the resource whose scopes cover them.
+var scopeResources = map[string]string{
+	"/transactions":           "transactions",
+	"/scheduled-transactions": "transactions",
+	"/transfers":              "transactions",
+	"/payment-links":          "transactions",
+	"/balances":               "balances",
+	"/users":                  "users",
+}
+
+// IsKnownScope reports whether scope names an action on a scoped resource.
+func IsKnownScope(scope string) bool {
+	resource, action, ok := strings.Cut(scope, ":")
+	if !ok || (action != ScopeRead && action != ScopeWrite) {
+		return false
+	}
+	for _, known := range scopeResources {
+		if known == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// Scoped reports whether the token is limited to its scopes.
+func (c *UserClaims) Scoped() bool {
+	return c.Scopes != nil
+}
+
+// HasScope reports whether the token may perform scope.
+func (c *UserClaims) HasScope(scope string) bool {
+	return !c.Scoped() || slices.Contains(c.Scopes, scope)
+}
+
+// RequireScope returns a middleware refusing scoped tokens without every one of scopes with 403.
+func RequireScope(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := UserClaimsFromContext(r.Context())
+			if !ok || claims == nil {
+				WriteProblem(w, r, http.StatusUnauthorized, "missing user claims")
+				return
+			}
+			for _, scope := range scopes {
+				if !claims.HasScope(scope) {
+					NewProblem(r, http.StatusForbidden, "token lacks scope "+scope).WithCode("insufficient_scope").Write(w)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRouteScope returns a middleware requiring scoped tokens to have the route's scope.
+func RequireRouteScope() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := UserClaimsFromContext(r.Context())
+			if !ok || claims == nil {
+				WriteProblem(w, r, http.StatusUnauthorized, "missing user claims")
+				return
+			}
+			if claims.Scoped() {
+				scope, ok := routeScope(r)
+				if !ok {
+					NewProblem(r, http.StatusForbidden, "scoped tokens can't access this resource").WithCode("insufficient_scope").Write(w)
+					return
+				}
+				if !claims.HasScope(scope) {
+					NewProblem(r, http.StatusForbidden, "token lacks scope "+scope).WithCode("insufficient_scope").Write(w)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routeScope returns the scope a request needs, if its path belongs to a scoped resource
+func routeScope(r *http.Request) (string, bool) {
+	for prefix, resource := range scopeResources {
+		if matchesPathPrefix(r.URL.Path, []string{prefix}) {
+			action := ScopeWrite
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				action = ScopeRead
+			}
+			return resource + ":" + action, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     *UserClaims
+		expectCode int
+	}{
+		{"unscoped token", &UserClaims{UserID: "1", Role: "user"}, http.StatusOK},
+		{"token with scope", &UserClaims{UserID: "1", Role: "user", Scopes: []string{"transactions:write"}}, http.StatusOK},
+		{"token without scope", &UserClaims{UserID: "1", Role: "user", Scopes: []string{"transactions:read"}}, http.StatusForbidden},
+		{"missing claims", nil, http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := RequireScope("transactions:write")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest("POST", "/", nil)
+			if tc.claims != nil {
+				req = req.WithContext(WithUserClaims(req.Context(), tc.claims))
+			}
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+			if rw.Code != tc.expectCode {
+				t.Errorf("expected status %d, got %d", tc.expectCode, rw.Code)
+			}
+		})
+	}
+}
+
+func TestRequireRouteScope(t *testing.T) {
+	readOnly := &UserClaims{UserID: "1", Role: "user", Scopes: []string{"transactions:read"}}
+	tests := []struct {
+		name       string
+		claims     *UserClaims
+		method     string
+		path       string
+		expectCode int
+	}{
+		{"read with read scope", readOnly, "GET", "/api/v2/transactions/history", http.StatusOK},
+		{"write with read scope", readOnly, "POST", "/api/v2/transactions/credit", http.StatusForbidden},
+		{"other resource", readOnly, "GET", "/api/v2/balances/current", http.StatusForbidden},
+		{"path of no resource", readOnly, "GET", "/api/v2/audit-logs", http.StatusForbidden},
+		{"unscoped token", &UserClaims{UserID: "1", Role: "user"}, "GET", "/api/v2/audit-logs", http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := RequireRouteScope()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req = req.WithContext(WithUserClaims(req.Context(), tc.claims))
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+			if rw.Code != tc.expectCode {
+				t.Errorf("expected status %d, got %d", tc.expectCode, rw.Code)
+			}
+		})
+	}
+}
+
+func TestIsKnownScope(t *testing.T) {
+	for scope, known := range map[string]bool{
+		"transactions:read": true,
+		"users:write":       true,
+		"users:delete":      false,
+		"audit:read":        false,
+		"transactions":      false,
+	} {
+		if IsKnownScope(scope) != known {
+			t.Errorf("IsKnownScope(%q) = %v, want %v", scope, !known, known)
+		}
+	}
+}
@@ -44,8 +44,8 @@ func (m *TracingMiddleware) Middleware(next http.Handler) http.Handler {
 		// Inject trace context into response headers
 		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(wrapped.Header()))
 
-		// Process request
-		next.ServeHTTP(wrapped, r)
+		// Process request with the span in its context, so downstream spans are its children
+		next.ServeHTTP(wrapped, r.WithContext(ctx))
 
 		// Set final span attributes
 		span.SetAttributes(
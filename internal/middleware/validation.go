@@ -71,7 +71,7 @@ func ValidationMiddleware(validator Validator, vFactory func() interface{}) func
 			v := vFactory()
 			if err := validator.Validate(r.Context(), r, &v); err != nil {
 				// Return a 400 Bad Request for any validation error
-				http.Error(w, err.Error(), http.StatusBadRequest)
+				WriteProblem(w, r, http.StatusBadRequest, err.Error())
 				return
 			}
 			ctx := context.WithValue(r.Context(), validatedBodyKey{}, v)
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIVersion is a major version of the HTTP API, served under /api/v<N>
+type APIVersion int
+
+// API versions
+const (
+	APIVersion1 APIVersion = 1
+	APIVersion2 APIVersion = 2
+
+	LatestAPIVersion = APIVersion2
+)
+
+// APIVersionHeader names the API version a response was served by.
+const APIVersionHeader = "API-Version"
+
+func (v APIVersion) String() string {
+	return "v" + strconv.Itoa(int(v))
+}
+
+// ParseAPIVersion parses "2" or "v2"
+func ParseAPIVersion(s string) (APIVersion, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "v"))
+	if err != nil || n < int(APIVersion1) || n > int(LatestAPIVersion) {
+		return 0, fmt.Errorf("unsupported API version %q", s)
+	}
+	return APIVersion(n), nil
+}
+
+type apiVersionKey struct{}
+
+// WithAPIVersion marks the requests it serves as using version v
+func WithAPIVersion(v APIVersion) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(APIVersionHeader, strconv.Itoa(int(v)))
+			ctx := context.WithValue(r.Context(), apiVersionKey{}, v)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// APIVersionFromContext returns the API version of a request, v1 by default.
+func APIVersionFromContext(ctx context.Context) APIVersion {
+	if v, ok := ctx.Value(apiVersionKey{}).(APIVersion); ok {
+		return v
+	}
+	return APIVersion1
+}
+
+// NegotiateAPIVersion routes unversioned paths by their API-Version header.
+func NegotiateAPIVersion(prefix string, fallback APIVersion) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rest, ok := strings.CutPrefix(r.URL.Path, prefix+"/")
+			if !ok || isVersionSegment(rest) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			v := fallback
+			if requested := r.Header.Get(APIVersionHeader); requested != "" {
+				var err error
+				if v, err = ParseAPIVersion(requested); err != nil {
+					WriteProblem(w, r, http.StatusBadRequest, err.Error())
+					return
+				}
+			}
+
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = prefix + "/" + v.String() + "/" + rest
+			r2.URL.RawPath = ""
+			next.ServeHTTP(w, r2)
+		})
+	}
+}
+
+// isVersionSegment reports whether path starts with a segment such as "v2"
+func isVersionSegment(path string) bool {
+	segment, _, _ := strings.Cut(path, "/")
+	if len(segment) < 2 || segment[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(segment[1:])
+	return err == nil
+}
+
+// Deprecation announces that a version is deprecated in favour of successor.
+func Deprecation(version, successor APIVersion, deprecatedAt, sunset time.Time) func(http.Handler) http.Handler {
+	from := "/" + version.String() + "/"
+	to := "/" + successor.String() + "/"
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			if deprecatedAt.IsZero() {
+				h.Set("Deprecation", "true")
+			} else {
+				h.Set("Deprecation", "@"+strconv.FormatInt(deprecatedAt.Unix(), 10))
+			}
+			if !sunset.IsZero() {
+				h.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			if strings.Contains(r.URL.Path, from) {
+				successorPath := strings.Replace(r.URL.Path, from, to, 1)
+				h.Add("Link", "<"+successorPath+`>; rel="successor-version"`)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAPIVersion(t *testing.T) {
+	for _, s := range []string{"2", "v2", " V2 "} {
+		v, err := ParseAPIVersion(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, APIVersion2, v, s)
+	}
+	for _, s := range []string{"", "0", "v3", "latest"} {
+		_, err := ParseAPIVersion(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestNegotiateAPIVersion(t *testing.T) {
+	var servedPath string
+	var servedVersion APIVersion
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		servedPath = r.URL.Path
+		servedVersion = APIVersionFromContext(r.Context())
+	})
+	handler := NegotiateAPIVersion("/api", APIVersion1)(next)
+
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		wantPath string
+	}{
+		{name: "versioned path is left alone", path: "/api/v1/balances/current", header: "2", wantPath: "/api/v1/balances/current"},
+		{name: "unversioned path uses fallback", path: "/api/balances/current", wantPath: "/api/v1/balances/current"},
+		{name: "unversioned path uses header", path: "/api/balances/current", header: "v2", wantPath: "/api/v2/balances/current"},
+		{name: "paths outside prefix are left alone", path: "/metrics", header: "2", wantPath: "/metrics"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(APIVersionHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantPath, servedPath)
+			assert.Equal(t, APIVersion1, servedVersion, "the version is set by the versioned route, not negotiation")
+		})
+	}
+
+	t.Run("unsupported version", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/balances/current", nil)
+		req.Header.Set(APIVersionHeader, "9")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	})
+}
+
+func TestWithAPIVersion(t *testing.T) {
+	var got APIVersion
+	handler := WithAPIVersion(APIVersion2)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = APIVersionFromContext(r.Context())
+	}))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/balances/current", nil))
+
+	assert.Equal(t, APIVersion2, got)
+	assert.Equal(t, "2", w.Header().Get(APIVersionHeader))
+}
+
+func TestDeprecation(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	t.Run("with dates", func(t *testing.T) {
+		deprecatedAt := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+		sunset := time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+		handler := Deprecation(APIVersion1, APIVersion2, deprecatedAt, sunset)(next)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/balances/current", nil))
+
+		assert.Equal(t, "@1782864000", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Sun, 31 Jan 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+		assert.Equal(t, `</api/v2/balances/current>; rel="successor-version"`, w.Header().Get("Link"))
+	})
+
+	t.Run("without dates", func(t *testing.T) {
+		handler := Deprecation(APIVersion1, APIVersion2, time.Time{}, time.Time{})(next)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/balances/current", nil))
+
+		assert.Equal(t, "true", w.Header().Get("Deprecation"))
+		assert.Empty(t, w.Header().Get("Sunset"))
+	})
+}
@@ -0,0 +1,50 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/melihgurlek/backend-path/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_OrdersAndPairsFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_second.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+		"0001_first.up.sql":    {Data: []byte("CREATE TABLE a ();")},
+		"0001_first.down.sql":  {Data: []byte("DROP TABLE a;")},
+		"README.md":            {Data: []byte("ignored")},
+		"0003_third.down.sql~": {Data: []byte("ignored")},
+	}
+
+	got, err := Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, int64(1), got[0].Version)
+	assert.Equal(t, "first", got[0].Name)
+	assert.Equal(t, "DROP TABLE a;", got[0].Down)
+	assert.Equal(t, int64(2), got[1].Version)
+	assert.Empty(t, got[1].Down)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load(fstest.MapFS{"0001_first.down.sql": {Data: []byte("DROP TABLE a;")}})
+	assert.ErrorContains(t, err, "no up file")
+
+	_, err = Load(fstest.MapFS{
+		"0001_first.up.sql": {Data: []byte("SELECT 1;")},
+		"0001_other.up.sql": {Data: []byte("SELECT 1;")},
+	})
+	assert.ErrorContains(t, err, "used by both")
+}
+
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	got, err := Load(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, got)
+	for i, m := range got {
+		assert.Equal(t, int64(i+1), m.Version, "versions are contiguous")
+		assert.NotEmpty(t, m.Down, "migration %04d_%s has a down file", m.Version, m.Name)
+	}
+}
@@ -0,0 +1,33 @@
+// Package notification tells users about events on their account by email, SMS and push.
+package notification
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Message is a rendered notification.
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Sender delivers messages on one channel.
+type Sender interface {
+	Send(ctx context.Context, to string, msg Message) error
+}
+
+// providerTimeout bounds a single request to an HTTP notification provider
+const providerTimeout = 10 * time.Second
+
+// checkResponse turns a non-2xx provider response into an error.
+func checkResponse(provider string, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("%s returned status %d: %s", provider, resp.StatusCode, body)
+}
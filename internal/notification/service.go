@@ -0,0 +1,283 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// deliveryTimeout bounds the delivery of one notification on all its channels
+const deliveryTimeout = 30 * time.Second
+
+// maxPushTokenLength bounds a stored push token; FCM tokens are far shorter
+const maxPushTokenLength = 4096
+
+// phoneNumberPattern matches an E.164 phone number such as +14155550100
+var phoneNumberPattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// UserLookup finds the users a notification is for
+type UserLookup interface {
+	GetByID(ctx context.Context, id int) (*domain.User, error)
+	ListIDsByRole(ctx context.Context, role string) ([]int, error)
+}
+
+// Config sizes the delivery worker pool
+type Config struct {
+	Workers   int
+	QueueSize int
+}
+
+// job is a notification waiting to be delivered
+type job struct {
+	userID int
+	event  string
+	data   map[string]string
+}
+
+// Service implements domain.NotificationService.
+type Service struct {
+	repo      domain.NotificationRepository
+	users     UserLookup
+	templates *TemplateStore
+	senders   map[string]Sender // by channel; channels without a sender are off
+	workers   int
+
+	queue    chan job
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewService creates a Service delivering through senders, keyed by channel.
+func NewService(repo domain.NotificationRepository, users UserLookup, templates *TemplateStore, senders map[string]Sender, cfg Config) *Service {
+	return &Service{
+		repo:      repo,
+		users:     users,
+		templates: templates,
+		senders:   senders,
+		workers:   cfg.Workers,
+		queue:     make(chan job, cfg.QueueSize),
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// Start starts the delivery workers.
+func (s *Service) Start(ctx context.Context) {
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+	log.Info().Int("workers", s.workers).Int("channels", len(s.senders)).Msg("Notification service started")
+}
+
+// Stop stops the workers once they have delivered the notifications already queued.
+func (s *Service) Stop() {
+	close(s.stopChan)
+	s.wg.Wait()
+	log.Info().Msg("Notification service stopped")
+}
+
+func (s *Service) work() {
+	defer s.wg.Done()
+	defer metrics.TrackGoroutine("notification_worker")()
+	for {
+		select {
+		case j := <-s.queue:
+			s.deliver(j)
+		case <-s.stopChan:
+			for {
+				select {
+				case j := <-s.queue:
+					s.deliver(j)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// Notify queues a notification of event for userID without blocking.
+func (s *Service) Notify(_ context.Context, userID int, event string, data map[string]string) {
+	j := job{userID: userID, event: event, data: make(map[string]string, len(data)+1)}
+	for k, v := range data {
+		j.data[k] = v
+	}
+	select {
+	case s.queue <- j:
+		metrics.NotificationQueueDepth.Set(float64(len(s.queue)))
+	default:
+		metrics.NotificationsSent.WithLabelValues("", event, "dropped").Inc()
+		log.Warn().Int("user_id", userID).Str("event", event).Msg("Notification queue full, dropping notification")
+	}
+}
+
+// NotifyMoneyRequested tells the payer of a money request about it.
+func (s *Service) NotifyMoneyRequested(ctx context.Context, req *domain.MoneyRequest) error {
+	requester, err := s.users.GetByID(ctx, req.RequesterID)
+	if err != nil {
+		return fmt.Errorf("failed to get requester: %w", err)
+	}
+	name := "Someone"
+	if requester != nil {
+		name = requester.Username
+	}
+	s.Notify(ctx, req.PayerID, domain.EventMoneyRequested, map[string]string{
+		"requester":  name,
+		"amount":     strconv.FormatFloat(req.Amount, 'f', 2, 64),
+		"note":       req.Note,
+		"request_id": strconv.Itoa(req.ID),
+	})
+	return nil
+}
+
+// NotifyAdmins queues a notification of event for every admin.
+func (s *Service) NotifyAdmins(ctx context.Context, event string, data map[string]string) error {
+	adminIDs, err := s.users.ListIDsByRole(ctx, "admin")
+	if err != nil {
+		return fmt.Errorf("failed to list admins: %w", err)
+	}
+	for _, id := range adminIDs {
+		s.Notify(ctx, id, event, data)
+	}
+	return nil
+}
+
+// deliver sends one notification on each channel the user wants it on.
+func (s *Service) deliver(j job) {
+	metrics.NotificationQueueDepth.Set(float64(len(s.queue)))
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+	logger := log.With().Int("user_id", j.userID).Str("event", j.event).Logger()
+
+	user, err := s.users.GetByID(ctx, j.userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get user to notify")
+		return
+	}
+	// Organization accounts have no inbox; their members are notified instead
+	if user == nil || user.IsErased() || user.IsOrganizationAccount() {
+		return
+	}
+	if _, ok := j.data["username"]; !ok {
+		j.data["username"] = user.Username
+	}
+
+	prefs, err := s.preferences(ctx, j.userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get notification preferences")
+		return
+	}
+	addresses := map[string]string{domain.ChannelEmail: user.Email}
+	if contacts, err := s.repo.GetContacts(ctx, j.userID); err != nil {
+		logger.Error().Err(err).Msg("Failed to get notification contacts")
+	} else {
+		for channel, address := range contacts {
+			addresses[channel] = address
+		}
+	}
+
+	for _, channel := range domain.NotificationChannels {
+		sender, ok := s.senders[channel]
+		wanted := prefs[j.event+"."+channel]
+		if domain.IsSecurityNotificationEvent(j.event) {
+			wanted = channel == domain.ChannelEmail
+		}
+		if !ok || !wanted || addresses[channel] == "" {
+			continue
+		}
+		msg, ok, err := s.templates.Render(j.event, channel, j.data)
+		if !ok {
+			continue
+		}
+		if err == nil {
+			err = sender.Send(ctx, addresses[channel], msg)
+		}
+		if err != nil {
+			metrics.NotificationsSent.WithLabelValues(channel, j.event, "failed").Inc()
+			logger.Warn().Err(err).Str("channel", channel).Msg("Failed to send notification")
+			continue
+		}
+		metrics.NotificationsSent.WithLabelValues(channel, j.event, "sent").Inc()
+	}
+}
+
+// preferences returns whether the user wants each event on each channel.
+func (s *Service) preferences(ctx context.Context, userID int) (map[string]bool, error) {
+	stored, err := s.repo.ListPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prefs := make(map[string]bool, len(domain.NotificationEvents)*len(domain.NotificationChannels))
+	for _, event := range domain.NotificationEvents {
+		for _, channel := range domain.NotificationChannels {
+			prefs[event+"."+channel] = domain.DefaultNotificationPreference(event, channel)
+		}
+	}
+	for _, p := range stored {
+		prefs[p.Event+"."+p.Channel] = p.Enabled
+	}
+	return prefs, nil
+}
+
+// GetPreferences returns the user's effective preference for every event and channel.
+func (s *Service) GetPreferences(ctx context.Context, userID int) ([]domain.NotificationPreference, error) {
+	prefs, err := s.preferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	result := make([]domain.NotificationPreference, 0, len(prefs))
+	for _, event := range domain.NotificationEvents {
+		for _, channel := range domain.NotificationChannels {
+			result = append(result, domain.NotificationPreference{
+				Event:   event,
+				Channel: channel,
+				Enabled: prefs[event+"."+channel],
+			})
+		}
+	}
+	return result, nil
+}
+
+// SetPreferences validates and stores the user's choices.
+func (s *Service) SetPreferences(ctx context.Context, userID int, prefs []domain.NotificationPreference) error {
+	if len(prefs) == 0 {
+		return &domain.ValidationError{Msg: "at least one preference is required"}
+	}
+	for _, p := range prefs {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.SetPreferences(ctx, userID, prefs); err != nil {
+		return fmt.Errorf("failed to set notification preferences: %w", err)
+	}
+	return nil
+}
+
+// SetContact validates and stores the user's SMS number or push token.
+func (s *Service) SetContact(ctx context.Context, userID int, channel, address string) error {
+	switch channel {
+	case domain.ChannelSMS:
+		if address != "" && !phoneNumberPattern.MatchString(address) {
+			return &domain.ValidationError{Msg: "sms address must be a phone number in E.164 form, such as +14155550100"}
+		}
+	case domain.ChannelPush:
+		if len(address) > maxPushTokenLength {
+			return &domain.ValidationError{Msg: "push token is too long"}
+		}
+	default:
+		return &domain.ValidationError{Msg: "contact channel must be sms or push"}
+	}
+	if err := s.repo.SetContact(ctx, userID, channel, address); err != nil {
+		return fmt.Errorf("failed to set notification contact: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+type fakeRepo struct {
+	prefs    []domain.NotificationPreference
+	contacts map[string]string
+}
+
+func (r *fakeRepo) ListPreferences(_ context.Context, _ int) ([]domain.NotificationPreference, error) {
+	return r.prefs, nil
+}
+
+func (r *fakeRepo) SetPreferences(_ context.Context, _ int, prefs []domain.NotificationPreference) error {
+	r.prefs = append(r.prefs, prefs...)
+	return nil
+}
+
+func (r *fakeRepo) GetContacts(_ context.Context, _ int) (map[string]string, error) {
+	return r.contacts, nil
+}
+
+func (r *fakeRepo) SetContact(_ context.Context, _ int, channel, address string) error {
+	if r.contacts == nil {
+		r.contacts = make(map[string]string)
+	}
+	r.contacts[channel] = address
+	return nil
+}
+
+type fakeUsers map[int]*domain.User
+
+func (u fakeUsers) GetByID(_ context.Context, id int) (*domain.User, error) {
+	return u[id], nil
+}
+
+func (u fakeUsers) ListIDsByRole(_ context.Context, role string) ([]int, error) {
+	var ids []int
+	for id, user := range u {
+		if user.Role == role {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// sent is a message delivered by a recordingSender
+type sent struct {
+	to  string
+	msg Message
+}
+
+type recordingSender struct {
+	mu   sync.Mutex
+	sent []sent
+	err  error
+}
+
+func (s *recordingSender) Send(_ context.Context, to string, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, sent{to: to, msg: msg})
+	return s.err
+}
+
+func newTestService(t *testing.T, repo *fakeRepo, senders map[string]Sender) *Service {
+	t.Helper()
+	templates, err := NewTemplateStore("")
+	require.NoError(t, err)
+	users := fakeUsers{
+		1: {ID: 1, Username: "alice", Email: "alice@example.com"},
+		2: {ID: 2, Username: "bob", Email: "bob@example.com"},
+		3: {ID: 3, Username: "root", Email: "root@example.com", Role: "admin"},
+	}
+	return NewService(repo, users, templates, senders, Config{Workers: 2, QueueSize: 10})
+}
+
+func TestService_DeliversOnEnabledChannels(t *testing.T) {
+	email, sms, push := &recordingSender{}, &recordingSender{}, &recordingSender{}
+	repo := &fakeRepo{contacts: map[string]string{domain.ChannelSMS: "+14155550100"}}
+	svc := newTestService(t, repo, map[string]Sender{
+		domain.ChannelEmail: email,
+		domain.ChannelSMS:   sms,
+		domain.ChannelPush:  push,
+	})
+
+	svc.Start(context.Background())
+	svc.Notify(context.Background(), 1, domain.EventUserRegistered, nil)
+	svc.Stop()
+
+	require.Len(t, email.sent, 1)
+	assert.Equal(t, "alice@example.com", email.sent[0].to)
+	assert.Equal(t, "Welcome, alice", email.sent[0].msg.Subject)
+	assert.Empty(t, sms.sent, "SMS is off by default")
+	assert.Empty(t, push.sent, "no push token on record")
+}
+
+func TestService_HonorsPreferences(t *testing.T) {
+	email, sms := &recordingSender{}, &recordingSender{}
+	repo := &fakeRepo{
+		contacts: map[string]string{domain.ChannelSMS: "+14155550100"},
+		prefs: []domain.NotificationPreference{
+			{Event: domain.EventNewDeviceLogin, Channel: domain.ChannelEmail, Enabled: false},
+			{Event: domain.EventNewDeviceLogin, Channel: domain.ChannelSMS, Enabled: true},
+		},
+	}
+	svc := newTestService(t, repo, map[string]Sender{domain.ChannelEmail: email, domain.ChannelSMS: sms})
+
+	svc.Start(context.Background())
+	svc.Notify(context.Background(), 1, domain.EventNewDeviceLogin, map[string]string{"ip": "203.0.113.7"})
+	svc.Stop()
+
+	assert.Empty(t, email.sent)
+	require.Len(t, sms.sent, 1)
+	assert.Equal(t, "+14155550100", sms.sent[0].to)
+	assert.Contains(t, sms.sent[0].msg.Body, "203.0.113.7")
+}
+
+func TestService_SkipsErasedAndUnknownUsers(t *testing.T) {
+	email := &recordingSender{}
+	svc := newTestService(t, &fakeRepo{}, map[string]Sender{domain.ChannelEmail: email})
+	erased := &domain.User{ID: 4, Username: "erased-4", Email: "erased-4@erased.invalid"}
+	require.NoError(t, erased.Anonymize())
+	svc.users.(fakeUsers)[4] = erased
+
+	svc.Start(context.Background())
+	svc.Notify(context.Background(), 4, domain.EventUserRegistered, nil)
+	svc.Notify(context.Background(), 99, domain.EventUserRegistered, nil)
+	svc.Stop()
+
+	assert.Empty(t, email.sent)
+}
+
+func TestService_SendFailureDoesNotStopOtherChannels(t *testing.T) {
+	email := &recordingSender{err: errors.New("relay down")}
+	push := &recordingSender{}
+	repo := &fakeRepo{contacts: map[string]string{domain.ChannelPush: "device-token"}}
+	svc := newTestService(t, repo, map[string]Sender{domain.ChannelEmail: email, domain.ChannelPush: push})
+
+	svc.Start(context.Background())
+	svc.Notify(context.Background(), 2, domain.EventTransactionCompleted, map[string]string{
+		"type": "debit", "amount": "5.00", "transaction_id": "9", "direction": "out",
+	})
+	svc.Stop()
+
+	assert.Len(t, email.sent, 1)
+	require.Len(t, push.sent, 1)
+	assert.Equal(t, "You sent 5.00 (debit #9).", push.sent[0].msg.Body)
+}
+
+func TestService_NotifyDropsWhenQueueIsFull(t *testing.T) {
+	email := &recordingSender{}
+	templates, err := NewTemplateStore("")
+	require.NoError(t, err)
+	svc := NewService(&fakeRepo{}, fakeUsers{1: {ID: 1, Username: "alice", Email: "a@example.com"}}, templates,
+		map[string]Sender{domain.ChannelEmail: email}, Config{Workers: 1, QueueSize: 1})
+
+	// Not started: the first notification fills the queue, the second is dropped
+	svc.Notify(context.Background(), 1, domain.EventUserRegistered, nil)
+	svc.Notify(context.Background(), 1, domain.EventUserRegistered, nil)
+	svc.Start(context.Background())
+	svc.Stop()
+
+	assert.Len(t, email.sent, 1)
+}
+
+func TestService_NotifyMoneyRequested(t *testing.T) {
+	email := &recordingSender{}
+	svc := newTestService(t, &fakeRepo{}, map[string]Sender{domain.ChannelEmail: email})
+
+	svc.Start(context.Background())
+	err := svc.NotifyMoneyRequested(context.Background(), &domain.MoneyRequest{ID: 7, RequesterID: 1, PayerID: 2, Amount: 12.5})
+	svc.Stop()
+
+	require.NoError(t, err)
+	require.Len(t, email.sent, 1)
+	assert.Equal(t, "bob@example.com", email.sent[0].to)
+	assert.Equal(t, "alice requested 12.50", email.sent[0].msg.Subject)
+}
+
+func TestService_NotifyAdmins(t *testing.T) {
+	email := &recordingSender{}
+	svc := newTestService(t, &fakeRepo{}, map[string]Sender{domain.ChannelEmail: email})
+
+	svc.Start(context.Background())
+	err := svc.NotifyAdmins(context.Background(), domain.EventAdminScheduledTransactionFailed, map[string]string{
+		"scheduled_id": "5", "owner_id": "1", "type": "debit", "amount": "10.00", "reason": "insufficient balance",
+	})
+	svc.Stop()
+
+	require.NoError(t, err)
+	require.Len(t, email.sent, 1)
+	assert.Equal(t, "root@example.com", email.sent[0].to)
+	assert.Contains(t, email.sent[0].msg.Body, "insufficient balance")
+}
+
+func TestService_GetPreferencesFillsDefaults(t *testing.T) {
+	repo := &fakeRepo{prefs: []domain.NotificationPreference{
+		{Event: domain.EventUserRegistered, Channel: domain.ChannelSMS, Enabled: true},
+	}}
+	svc := newTestService(t, repo, nil)
+
+	prefs, err := svc.GetPreferences(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Len(t, prefs, len(domain.NotificationEvents)*len(domain.NotificationChannels))
+	assert.Contains(t, prefs, domain.NotificationPreference{Event: domain.EventUserRegistered, Channel: domain.ChannelSMS, Enabled: true})
+	assert.Contains(t, prefs, domain.NotificationPreference{Event: domain.EventNewDeviceLogin, Channel: domain.ChannelSMS, Enabled: false})
+	assert.Contains(t, prefs, domain.NotificationPreference{Event: domain.EventNewDeviceLogin, Channel: domain.ChannelEmail, Enabled: true})
+}
+
+func TestService_Validation(t *testing.T) {
+	svc := newTestService(t, &fakeRepo{}, nil)
+	ctx := context.Background()
+	var valErr *domain.ValidationError
+
+	err := svc.SetPreferences(ctx, 1, []domain.NotificationPreference{{Event: "nope", Channel: domain.ChannelEmail}})
+	assert.ErrorAs(t, err, &valErr)
+	err = svc.SetPreferences(ctx, 1, []domain.NotificationPreference{{Event: domain.EventUserRegistered, Channel: "fax"}})
+	assert.ErrorAs(t, err, &valErr)
+
+	assert.ErrorAs(t, svc.SetContact(ctx, 1, domain.ChannelEmail, "a@example.com"), &valErr, "email uses the account address")
+	assert.ErrorAs(t, svc.SetContact(ctx, 1, domain.ChannelSMS, "555-0100"), &valErr)
+	assert.NoError(t, svc.SetContact(ctx, 1, domain.ChannelSMS, "+14155550100"))
+	assert.NoError(t, svc.SetContact(ctx, 1, domain.ChannelSMS, ""), "an empty address removes the contact")
+}
+
+func TestService_SecurityEventsIgnorePreferences(t *testing.T) {
+	email, sms := &recordingSender{}, &recordingSender{}
+	repo := &fakeRepo{
+		contacts: map[string]string{domain.ChannelSMS: "+14155550100"},
+		prefs: []domain.NotificationPreference{
+			{Event: domain.EventLoginVerification, Channel: domain.ChannelEmail, Enabled: false},
+		},
+	}
+	svc := newTestService(t, repo, map[string]Sender{domain.ChannelEmail: email, domain.ChannelSMS: sms})
+
+	svc.Start(context.Background())
+	svc.Notify(context.Background(), 1, domain.EventLoginVerification, map[string]string{"code": "123456"})
+	svc.Stop()
+
+	require.Len(t, email.sent, 1)
+	assert.Contains(t, email.sent[0].msg.Body, "123456")
+	assert.Empty(t, sms.sent, "security events go by email only")
+}
@@ -0,0 +1,86 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// subjectPrefix starts the optional subject line of a template.
+const subjectPrefix = "Subject: "
+
+// TemplateStore holds one template per event and channel, named "<event>.<channel>.tmpl".
+type TemplateStore struct {
+	templates map[string]*template.Template
+}
+
+// NewTemplateStore loads the built-in templates and then those in dir.
+func NewTemplateStore(dir string) (*TemplateStore, error) {
+	store := &TemplateStore{templates: make(map[string]*template.Template)}
+	builtins, err := fs.Sub(builtinTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	if err := store.load(builtins); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := store.load(os.DirFS(dir)); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// load parses every .tmpl file at the top of fsys.
+func (s *TemplateStore) load(fsys fs.FS) error {
+	names, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		raw, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("failed to read notification template %s: %w", name, err)
+		}
+		tmpl, err := template.New(name).Option("missingkey=zero").Parse(string(raw))
+		if err != nil {
+			return fmt.Errorf("failed to parse notification template %s: %w", name, err)
+		}
+		s.templates[strings.TrimSuffix(path.Base(name), ".tmpl")] = tmpl
+	}
+	return nil
+}
+
+// Render renders the template of event on channel.
+func (s *TemplateStore) Render(event, channel string, data map[string]string) (Message, bool, error) {
+	tmpl, ok := s.templates[event+"."+channel]
+	if !ok {
+		return Message{}, false, nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return Message{}, true, fmt.Errorf("failed to render %s.%s template: %w", event, channel, err)
+	}
+	return parseMessage(buf.String()), true, nil
+}
+
+// parseMessage splits rendered text into its subject line, if any, and body.
+func parseMessage(text string) Message {
+	var msg Message
+	if rest, ok := strings.CutPrefix(text, subjectPrefix); ok {
+		subject, body, _ := strings.Cut(rest, "\n")
+		msg.Subject = strings.TrimSpace(subject)
+		text = body
+	}
+	msg.Body = strings.TrimSpace(text)
+	return msg
+}
@@ -0,0 +1,81 @@
+package notification
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+func TestTemplateStore_BuiltinsCoverEveryEventAndChannel(t *testing.T) {
+	store, err := NewTemplateStore("")
+	require.NoError(t, err)
+
+	for _, event := range domain.NotificationEvents {
+		for _, channel := range domain.NotificationChannels {
+			msg, ok, err := store.Render(event, channel, map[string]string{"username": "alice"})
+			require.NoError(t, err, "%s.%s", event, channel)
+			require.True(t, ok, "%s.%s has no template", event, channel)
+			assert.NotEmpty(t, msg.Body, "%s.%s", event, channel)
+			if channel != domain.ChannelSMS {
+				assert.NotEmpty(t, msg.Subject, "%s.%s", event, channel)
+			}
+		}
+	}
+}
+
+func TestTemplateStore_Render(t *testing.T) {
+	store, err := NewTemplateStore("")
+	require.NoError(t, err)
+
+	msg, ok, err := store.Render(domain.EventTransactionCompleted, domain.ChannelEmail, map[string]string{
+		"username":       "alice",
+		"type":           "transfer",
+		"amount":         "25.00",
+		"transaction_id": "42",
+		"direction":      "in",
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "You received 25.00", msg.Subject)
+	assert.Contains(t, msg.Body, "25.00 was added to your balance by transfer #42.")
+
+	_, ok, err = store.Render("unknown_event", domain.ChannelEmail, nil)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestTemplateStore_DirOverridesBuiltins(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user_registered.sms.tmpl"), []byte("Hello {{.username}}{{.missing}}!\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "custom_event.email.tmpl"), []byte("Subject: Custom\n\nBody"), 0o600))
+
+	store, err := NewTemplateStore(dir)
+	require.NoError(t, err)
+
+	msg, ok, err := store.Render(domain.EventUserRegistered, domain.ChannelSMS, map[string]string{"username": "bob"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, Message{Body: "Hello bob!"}, msg, "missing keys render empty")
+
+	msg, ok, err = store.Render("custom_event", domain.ChannelEmail, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, Message{Subject: "Custom", Body: "Body"}, msg)
+
+	// Built-ins that aren't overridden are still there
+	_, ok, _ = store.Render(domain.EventUserRegistered, domain.ChannelEmail, nil)
+	assert.True(t, ok)
+}
+
+func TestNewTemplateStore_InvalidTemplate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user_registered.sms.tmpl"), []byte("{{.username"), 0o600))
+
+	_, err := NewTemplateStore(dir)
+	assert.ErrorContains(t, err, "user_registered.sms.tmpl")
+}
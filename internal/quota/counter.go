@@ -0,0 +1,52 @@
+// Package quota counts API requests against request quotas in Redis.
+package quota
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// expiryGrace keeps a period's counter a while after the period ends.
+const expiryGrace = time.Hour
+
+// RedisCounter implements domain.QuotaCounter with a counter per user and period.
+type RedisCounter struct {
+	client *redis.Client
+}
+
+// NewRedisCounter creates a counter on client.
+func NewRedisCounter(client *redis.Client) *RedisCounter {
+	return &RedisCounter{client: client}
+}
+
+// counterKey holds the requests of a user in the period starting at start
+func counterKey(userID int, period string, start time.Time) string {
+	return "quota:" + strconv.Itoa(userID) + ":" + period + ":" + strconv.FormatInt(start.Unix(), 10)
+}
+
+// Increment counts a request, expiring the counter after the period.
+func (c *RedisCounter) Increment(ctx context.Context, userID int, period string, start, end time.Time) (int64, error) {
+	key := counterKey(userID, period, start)
+	n, err := c.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if n == 1 {
+		if err := c.client.ExpireAt(ctx, key, end.Add(expiryGrace)).Err(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Count returns the requests counted in a period, 0 before the first.
+func (c *RedisCounter) Count(ctx context.Context, userID int, period string, start time.Time) (int64, error) {
+	n, err := c.client.Get(ctx, counterKey(userID, period, start)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// adminIPRuleColumns is the column list shared by every allowlist SELECT.
+const adminIPRuleColumns = `id, user_id, cidr::text, note, created_by, created_at`
+
+// AdminIPAllowlistPostgresRepository implements domain.AdminIPAllowlistRepository using PostgreSQL.
+type AdminIPAllowlistPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAdminIPAllowlistPostgresRepository creates a new AdminIPAllowlistPostgresRepository.
+func NewAdminIPAllowlistPostgresRepository(pool *pgxpool.Pool) *AdminIPAllowlistPostgresRepository {
+	return &AdminIPAllowlistPostgresRepository{pool: pool}
+}
+
+// List fetches an admin's allowlist entries, oldest first.
+func (r *AdminIPAllowlistPostgresRepository) List(ctx context.Context, userID int) ([]*domain.AdminIPRule, error) {
+	query := `SELECT ` + adminIPRuleColumns + ` FROM admin_ip_allowlists WHERE user_id = $1 ORDER BY created_at, id`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domain.AdminIPRule
+	for rows.Next() {
+		rule := &domain.AdminIPRule{}
+		if err := rows.Scan(&rule.ID, &rule.UserID, &rule.CIDR, &rule.Note, &rule.CreatedBy, &rule.CreatedAt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// Add stores an allowlist entry unless its range is listed already.
+func (r *AdminIPAllowlistPostgresRepository) Add(ctx context.Context, rule *domain.AdminIPRule) error {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO admin_ip_allowlists (user_id, cidr, note, created_by, created_at)
+		VALUES ($1, $2::cidr, $3, $4, NOW())
+		ON CONFLICT (user_id, cidr) DO NOTHING
+		RETURNING id, created_at`,
+		rule.UserID, rule.CIDR, rule.Note, rule.CreatedBy,
+	).Scan(&rule.ID, &rule.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrAdminIPRuleExists
+	}
+	return err
+}
+
+// Delete removes an allowlist entry of an admin.
+func (r *AdminIPAllowlistPostgresRepository) Delete(ctx context.Context, userID, id int) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM admin_ip_allowlists WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrAdminIPRuleNotFound
+	}
+	return nil
+}
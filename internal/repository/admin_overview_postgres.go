@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// AdminOverviewPostgresRepository implements domain.AdminOverviewRepository using PostgreSQL.
+type AdminOverviewPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAdminOverviewPostgresRepository creates a new AdminOverviewPostgresRepository.
+func NewAdminOverviewPostgresRepository(pool *pgxpool.Pool) *AdminOverviewPostgresRepository {
+	return &AdminOverviewPostgresRepository{pool: pool}
+}
+
+// UserCounts counts users who are people, and those of them created since the given time.
+func (r *AdminOverviewPostgresRepository) UserCounts(ctx context.Context, since time.Time) (*domain.UserOverview, error) {
+	query := `SELECT COUNT(*), COUNT(*) FILTER (WHERE created_at >= $1)
+		FROM users WHERE erased_at IS NULL AND role <> $2`
+	counts := &domain.UserOverview{}
+	if err := r.pool.QueryRow(ctx, query, since, domain.OrganizationAccountRole).Scan(&counts.Total, &counts.SignupsToday); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// TransactionWindows summarizes the transactions created since each of the given times.
+func (r *AdminOverviewPostgresRepository) TransactionWindows(ctx context.Context, since []time.Time) ([]*domain.TransactionWindow, error) {
+	if len(since) == 0 {
+		return nil, nil
+	}
+	earliest := since[0]
+	for _, s := range since[1:] {
+		if s.Before(earliest) {
+			earliest = s
+		}
+	}
+
+	query := `SELECT w.ord,
+			COUNT(t.id),
+			COUNT(t.id) FILTER (WHERE t.status = 'completed'),
+			COUNT(t.id) FILTER (WHERE t.status = 'failed'),
+			COALESCE(SUM(t.amount) FILTER (WHERE t.status = 'completed'), 0)
+		FROM unnest($1::timestamptz[]) WITH ORDINALITY AS w(since, ord)
+		LEFT JOIN (SELECT id, status, amount, created_at FROM transactions WHERE created_at >= $2) t
+			ON t.created_at >= w.since
+		GROUP BY w.ord
+		ORDER BY w.ord`
+	rows, err := r.pool.Query(ctx, query, since, earliest)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var windows []*domain.TransactionWindow
+	for rows.Next() {
+		var ord int
+		w := &domain.TransactionWindow{}
+		if err := rows.Scan(&ord, &w.Count, &w.Completed, &w.Failed, &w.Volume); err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, rows.Err()
+}
+
+// ScheduledBacklog counts pending, due and retrying scheduled transactions.
+func (r *AdminOverviewPostgresRepository) ScheduledBacklog(ctx context.Context, now time.Time) (*domain.ScheduledBacklog, error) {
+	query := `SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE
+				(next_retry_at IS NOT NULL AND next_retry_at <= $1) OR
+				(next_retry_at IS NULL AND recurring = FALSE AND schedule_at <= $1) OR
+				(next_retry_at IS NULL AND recurring = TRUE AND next_run_at <= $1)),
+			COUNT(*) FILTER (WHERE next_retry_at IS NOT NULL)
+		FROM scheduled_transactions WHERE status = 'pending'`
+	backlog := &domain.ScheduledBacklog{}
+	if err := r.pool.QueryRow(ctx, query, now).Scan(&backlog.Pending, &backlog.Overdue, &backlog.Retrying); err != nil {
+		return nil, err
+	}
+	return backlog, nil
+}
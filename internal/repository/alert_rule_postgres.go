@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// alertRuleColumns is the column list shared by every alert rule SELECT.
+const alertRuleColumns = `id, user_id, type, threshold, active, created_at, updated_at`
+
+// AlertRulePostgresRepository implements domain.AlertRuleRepository using PostgreSQL.
+type AlertRulePostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAlertRulePostgresRepository creates a new AlertRulePostgresRepository.
+func NewAlertRulePostgresRepository(pool *pgxpool.Pool) *AlertRulePostgresRepository {
+	return &AlertRulePostgresRepository{pool: pool}
+}
+
+// scanAlertRule scans a row selected with alertRuleColumns.
+func scanAlertRule(row pgx.Row) (*domain.AlertRule, error) {
+	rule := &domain.AlertRule{}
+	err := row.Scan(&rule.ID, &rule.UserID, &rule.Type, &rule.Threshold, &rule.Active, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Create inserts a new alert rule.
+func (r *AlertRulePostgresRepository) Create(ctx context.Context, rule *domain.AlertRule) error {
+	query := `
+		INSERT INTO alert_rules (user_id, type, threshold, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+	return r.pool.QueryRow(ctx, query, rule.UserID, rule.Type, rule.Threshold, rule.Active).
+		Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+// GetByID fetches an alert rule by ID.
+func (r *AlertRulePostgresRepository) GetByID(ctx context.Context, id int) (*domain.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE id = $1`
+	rule, err := scanAlertRule(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// ListByUser fetches all alert rules of a user, oldest first.
+func (r *AlertRulePostgresRepository) ListByUser(ctx context.Context, userID int) ([]*domain.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE user_id = $1 ORDER BY created_at ASC, id ASC`
+	return r.list(ctx, query, userID)
+}
+
+// ListActiveByUsers fetches the active alert rules of the given users.
+func (r *AlertRulePostgresRepository) ListActiveByUsers(ctx context.Context, userIDs []int) ([]*domain.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE user_id = ANY($1) AND active ORDER BY id ASC`
+	return r.list(ctx, query, userIDs)
+}
+
+func (r *AlertRulePostgresRepository) list(ctx context.Context, query string, args ...any) ([]*domain.AlertRule, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domain.AlertRule
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// Update stores an alert rule's threshold and active flag.
+func (r *AlertRulePostgresRepository) Update(ctx context.Context, rule *domain.AlertRule) error {
+	query := `UPDATE alert_rules SET threshold = $2, active = $3, updated_at = NOW() WHERE id = $1 RETURNING updated_at`
+	err := r.pool.QueryRow(ctx, query, rule.ID, rule.Threshold, rule.Active).Scan(&rule.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrAlertRuleNotFound
+	}
+	return err
+}
+
+// Delete deletes one of the user's alert rules.
+func (r *AlertRulePostgresRepository) Delete(ctx context.Context, userID, id int) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM alert_rules WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrAlertRuleNotFound
+	}
+	return nil
+}
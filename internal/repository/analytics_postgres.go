@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// userLedgerFilterSQL selects the completed transactions of user $1 created in [$2, $3)
+const userLedgerFilterSQL = `(to_user_id = $1 OR from_user_id = $1)
+		AND status = 'completed' AND created_at >= $2 AND created_at < $3`
+
+// AnalyticsPostgresRepository implements domain.AnalyticsRepository using PostgreSQL.
+type AnalyticsPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAnalyticsPostgresRepository creates a new AnalyticsPostgresRepository.
+func NewAnalyticsPostgresRepository(pool *pgxpool.Pool) *AnalyticsPostgresRepository {
+	return &AnalyticsPostgresRepository{pool: pool}
+}
+
+// TransactionSummary counts the user's transactions and their average amount.
+func (r *AnalyticsPostgresRepository) TransactionSummary(ctx context.Context, userID int, from, to time.Time) (int, float64, error) {
+	query := `SELECT COUNT(*), COALESCE(AVG(amount), 0) FROM transaction_ledger WHERE ` + userLedgerFilterSQL
+	var count int
+	var average float64
+	if err := r.pool.QueryRow(ctx, query, userID, from, to).Scan(&count, &average); err != nil {
+		return 0, 0, err
+	}
+	return count, average, nil
+}
+
+// SpendByType totals the debits and outgoing transfers of the user, per type.
+func (r *AnalyticsPostgresRepository) SpendByType(ctx context.Context, userID int, from, to time.Time) ([]*domain.TypeSpend, error) {
+	query := `SELECT type, COUNT(*), SUM(amount) FROM transaction_ledger
+		WHERE from_user_id = $1 AND type IN ('debit', 'transfer')
+			AND status = 'completed' AND created_at >= $2 AND created_at < $3
+		GROUP BY type ORDER BY type`
+	rows, err := r.pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var spend []*domain.TypeSpend
+	for rows.Next() {
+		s := &domain.TypeSpend{}
+		if err := rows.Scan(&s.Type, &s.Count, &s.Amount); err != nil {
+			return nil, err
+		}
+		spend = append(spend, s)
+	}
+	return spend, rows.Err()
+}
+
+// MonthlyFlows totals the money entering and leaving the account, per month.
+func (r *AnalyticsPostgresRepository) MonthlyFlows(ctx context.Context, userID int, from, to time.Time) ([]*domain.MonthlyFlow, error) {
+	query := `WITH changes AS (
+			SELECT date_trunc('month', created_at) AS month, ` + signedAmountSQL + ` AS change
+			FROM transaction_ledger WHERE ` + userLedgerFilterSQL + `
+		)
+		SELECT to_char(month, 'YYYY-MM'),
+			COALESCE(SUM(change) FILTER (WHERE change > 0), 0),
+			COALESCE(-SUM(change) FILTER (WHERE change < 0), 0)
+		FROM changes GROUP BY month ORDER BY month`
+	rows, err := r.pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flows []*domain.MonthlyFlow
+	for rows.Next() {
+		f := &domain.MonthlyFlow{}
+		if err := rows.Scan(&f.Month, &f.Inflow, &f.Outflow); err != nil {
+			return nil, err
+		}
+		f.Net = f.Inflow - f.Outflow
+		flows = append(flows, f)
+	}
+	return flows, rows.Err()
+}
+
+// BalanceTrend returns the end-of-day balance of each day in the period on which it changed.
+func (r *AnalyticsPostgresRepository) BalanceTrend(ctx context.Context, userID int, from, to time.Time) ([]*domain.BalancePoint, error) {
+	query := `WITH daily AS (
+			SELECT DATE(created_at) AS day, SUM(` + signedAmountSQL + `) AS change
+			FROM transaction_ledger
+			WHERE (to_user_id = $1 OR from_user_id = $1) AND status = 'completed' AND created_at < $3
+			GROUP BY DATE(created_at)
+		),
+		running AS (
+			SELECT day, SUM(change) OVER (ORDER BY day) AS balance FROM daily
+		)
+		SELECT day, balance FROM running WHERE day >= DATE($2) ORDER BY day`
+	rows, err := r.pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var trend []*domain.BalancePoint
+	for rows.Next() {
+		p := &domain.BalancePoint{}
+		if err := rows.Scan(&p.Date, &p.Balance); err != nil {
+			return nil, err
+		}
+		trend = append(trend, p)
+	}
+	return trend, rows.Err()
+}
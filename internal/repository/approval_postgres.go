@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// approvalColumns is the column list shared by every approval SELECT.
+const approvalColumns = `id, transaction_id, requested_by, reviewed_by, status, reason, created_at, reviewed_at`
+
+// ApprovalPostgresRepository implements domain.ApprovalRepository using PostgreSQL.
+type ApprovalPostgresRepository struct {
+	db DBTX
+}
+
+// NewApprovalPostgresRepository creates a new ApprovalPostgresRepository.
+func NewApprovalPostgresRepository(pool *pgxpool.Pool) *ApprovalPostgresRepository {
+	return &ApprovalPostgresRepository{db: pool}
+}
+
+// scanApproval scans a row selected with approvalColumns.
+func scanApproval(row pgx.Row) (*domain.Approval, error) {
+	a := &domain.Approval{}
+	err := row.Scan(&a.ID, &a.TransactionID, &a.RequestedBy, &a.ReviewedBy, &a.Status, &a.Reason, &a.CreatedAt, &a.ReviewedAt)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Create inserts a new approval request.
+func (r *ApprovalPostgresRepository) Create(ctx context.Context, a *domain.Approval) error {
+	query := `
+		INSERT INTO transaction_approvals (transaction_id, requested_by, reviewed_by, status, reason, created_at, reviewed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	return r.db.QueryRow(ctx, query,
+		a.TransactionID, a.RequestedBy, a.ReviewedBy, a.Status, a.Reason, a.CreatedAt, a.ReviewedAt,
+	).Scan(&a.ID)
+}
+
+// GetByTransactionID fetches the approval request for a transaction.
+func (r *ApprovalPostgresRepository) GetByTransactionID(ctx context.Context, transactionID int) (*domain.Approval, error) {
+	query := `SELECT ` + approvalColumns + ` FROM transaction_approvals WHERE transaction_id = $1`
+	a, err := scanApproval(r.db.QueryRow(ctx, query, transactionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListPending fetches approval requests awaiting review, oldest first.
+func (r *ApprovalPostgresRepository) ListPending(ctx context.Context, limit, offset int) ([]*domain.Approval, error) {
+	query := `SELECT ` + approvalColumns + ` FROM transaction_approvals
+		WHERE status = 'pending'
+		ORDER BY created_at
+		LIMIT $1 OFFSET $2`
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var approvals []*domain.Approval
+	for rows.Next() {
+		a, err := scanApproval(rows)
+		if err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+// Update stores the review decision of an approval request that is still pending.
+func (r *ApprovalPostgresRepository) Update(ctx context.Context, a *domain.Approval) error {
+	result, err := r.db.Exec(ctx,
+		`UPDATE transaction_approvals SET reviewed_by = $1, status = $2, reason = $3, reviewed_at = $4
+		WHERE id = $5 AND status = 'pending'`,
+		a.ReviewedBy, a.Status, a.Reason, a.ReviewedAt, a.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrApprovalAlreadyReviewed
+	}
+	return nil
+}
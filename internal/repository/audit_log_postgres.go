@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// auditLogColumns is the column list shared by every audit log SELECT.
+const auditLogColumns = `id, actor_id, entity_type, entity_id, action, COALESCE(details, ''), created_at`
+
+// AuditLogPostgresRepository implements domain.AuditLogRepository using PostgreSQL.
+type AuditLogPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAuditLogPostgresRepository creates a new AuditLogPostgresRepository.
+func NewAuditLogPostgresRepository(pool *pgxpool.Pool) *AuditLogPostgresRepository {
+	return &AuditLogPostgresRepository{pool: pool}
+}
+
+// scanAuditLog scans a row selected with auditLogColumns.
+func scanAuditLog(row pgx.Row) (*domain.AuditLog, error) {
+	l := &domain.AuditLog{}
+	err := row.Scan(&l.ID, &l.ActorID, &l.EntityType, &l.EntityID, &l.Action, &l.Details, &l.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Create inserts a new audit log entry.
+func (r *AuditLogPostgresRepository) Create(ctx context.Context, l *domain.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (actor_id, entity_type, entity_id, action, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	return r.pool.QueryRow(ctx, query,
+		l.ActorID, l.EntityType, l.EntityID, l.Action, l.Details, l.CreatedAt,
+	).Scan(&l.ID)
+}
+
+// ListByEntity fetches the audit trail of one entity, oldest first.
+func (r *AuditLogPostgresRepository) ListByEntity(ctx context.Context, entityType string, entityID int) ([]*domain.AuditLog, error) {
+	query := `SELECT ` + auditLogColumns + ` FROM audit_logs
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY created_at, id`
+	return r.list(ctx, query, entityType, entityID)
+}
+
+// Search fetches the entries matching filter, newest first.
+func (r *AuditLogPostgresRepository) Search(ctx context.Context, filter domain.AuditLogFilter) ([]*domain.AuditLog, error) {
+	var conditions []string
+	var args []any
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.ActorID != nil {
+		where("actor_id = $%d", *filter.ActorID)
+	}
+	if filter.EntityType != "" {
+		where("entity_type = $%d", filter.EntityType)
+	}
+	if filter.Action != "" {
+		where("action = $%d", filter.Action)
+	}
+	if filter.From != nil {
+		where("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		where("created_at < $%d", *filter.To)
+	}
+
+	query := `SELECT ` + auditLogColumns + ` FROM audit_logs`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	return r.list(ctx, query, args...)
+}
+
+// list runs an audit log SELECT and scans every row.
+func (r *AuditLogPostgresRepository) list(ctx context.Context, query string, args ...any) ([]*domain.AuditLog, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*domain.AuditLog
+	for rows.Next() {
+		l, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
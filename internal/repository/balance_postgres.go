@@ -10,23 +10,34 @@ import (
 	"github.com/melihgurlek/backend-path/internal/domain"
 )
 
+// signedAmountSQL is a transaction_ledger row's effect on the balance of user $1.
+const signedAmountSQL = `CASE
+		WHEN to_user_id = $1 AND type = 'credit' THEN amount - fee
+		WHEN to_user_id = $1 AND type = 'transfer' THEN amount
+		WHEN from_user_id = $1 AND type IN ('debit', 'transfer') THEN -(amount + fee)
+		ELSE 0
+	END`
+
 type BalancePostgresRepository struct {
-	pool *pgxpool.Pool
+	db DBTX
 }
 
 func NewBalancePostgresRepository(pool *pgxpool.Pool) *BalancePostgresRepository {
-	return &BalancePostgresRepository{pool: pool}
+	return &BalancePostgresRepository{db: pool}
 }
 
-func (r *BalancePostgresRepository) Create(balance *domain.Balance) error {
-	_, err := r.pool.Exec(context.Background(), "INSERT INTO balances (user_id, amount, last_updated_at) VALUES ($1, $2, $3)", balance.UserID, balance.Amount, balance.LastUpdatedAt)
+func (r *BalancePostgresRepository) Create(ctx context.Context, balance *domain.Balance) error {
+	_, err := r.db.Exec(ctx, "INSERT INTO balances (user_id, amount, held_amount, version, last_updated_at) VALUES ($1, $2, $3, 1, $4)", balance.UserID, balance.Amount, balance.HeldAmount, balance.LastUpdatedAt)
+	if err == nil {
+		balance.Version = 1
+	}
 	return err
 }
 
-func (r *BalancePostgresRepository) GetByUserID(userID int) (*domain.Balance, error) {
+func (r *BalancePostgresRepository) GetByUserID(ctx context.Context, userID int) (*domain.Balance, error) {
 	balance := &domain.Balance{}
-	query := `SELECT user_id, amount, last_updated_at FROM balances WHERE user_id = $1`
-	err := r.pool.QueryRow(context.Background(), query, userID).Scan(&balance.UserID, &balance.Amount, &balance.LastUpdatedAt)
+	query := `SELECT user_id, amount, held_amount, version, last_updated_at, frozen_at FROM balances WHERE user_id = $1`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&balance.UserID, &balance.Amount, &balance.HeldAmount, &balance.Version, &balance.LastUpdatedAt, &balance.FrozenAt)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -37,53 +48,40 @@ func (r *BalancePostgresRepository) GetByUserID(userID int) (*domain.Balance, er
 	return balance, nil
 }
 
-// Update updates a user's balance with proper locking to prevent race conditions
-func (r *BalancePostgresRepository) Update(balance *domain.Balance) error {
-	// Use a transaction to ensure atomicity and prevent race conditions
-	tx, err := r.pool.Begin(context.Background())
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback(context.Background())
-
-	// Lock the row for update to prevent concurrent modifications
-	query := `SELECT user_id, amount, last_updated_at FROM balances WHERE user_id = $1 FOR UPDATE`
-	var currentBalance domain.Balance
-	err = tx.QueryRow(context.Background(), query, balance.UserID).Scan(
-		&currentBalance.UserID, &currentBalance.Amount, &currentBalance.LastUpdatedAt,
-	)
-
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			// User doesn't have a balance record yet, create one
-			insertQuery := `INSERT INTO balances (user_id, amount, last_updated_at) VALUES ($1, $2, NOW())`
-			_, err = tx.Exec(context.Background(), insertQuery, balance.UserID, balance.Amount)
-		}
+// Update writes a balance if its version is still the one it was read at.
+func (r *BalancePostgresRepository) Update(ctx context.Context, balance *domain.Balance) error {
+	var query string
+	var args []any
+	if balance.Version == 0 {
+		query = `INSERT INTO balances (user_id, amount, held_amount, version, last_updated_at, frozen_at) VALUES ($1, $2, $3, 1, NOW(), $4)
+			ON CONFLICT (user_id) DO NOTHING`
+		args = []any{balance.UserID, balance.Amount, balance.HeldAmount, balance.FrozenAt}
 	} else {
-		// Update existing balance
-		updateQuery := `UPDATE balances SET amount = $1, last_updated_at = NOW() WHERE user_id = $2`
-		_, err = tx.Exec(context.Background(), updateQuery, balance.Amount, balance.UserID)
+		query = `UPDATE balances SET amount = $1, held_amount = $2, frozen_at = $3, version = version + 1, last_updated_at = NOW()
+			WHERE user_id = $4 AND version = $5`
+		args = []any{balance.Amount, balance.HeldAmount, balance.FrozenAt, balance.UserID, balance.Version}
 	}
 
+	result, err := r.db.Exec(ctx, query, args...)
 	if err != nil {
 		return err
 	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrBalanceConflict
+	}
 
-	return tx.Commit(context.Background())
+	balance.Version++
+	return nil
 }
 
 // GetHistoricalBalances calculates balance history from transaction data
-func (r *BalancePostgresRepository) GetHistoricalBalance(userID int, limit int) ([]*domain.Balance, error) {
+func (r *BalancePostgresRepository) GetHistoricalBalance(ctx context.Context, userID int, limit int) ([]*domain.Balance, error) {
 	query := `
 		WITH daily_balances AS (
 			SELECT 
 				DATE(created_at) as balance_date,
-				SUM(CASE 
-					WHEN to_user_id = $1 AND type IN ('credit', 'transfer') THEN amount
-					WHEN from_user_id = $1 AND type IN ('debit', 'transfer') THEN -amount
-					ELSE 0 
-				END) as daily_change
-			FROM transactions 
+				SUM(` + signedAmountSQL + `) as daily_change
+			FROM transaction_ledger
 			WHERE (to_user_id = $1 OR from_user_id = $1) 
 				AND status = 'completed'
 				AND created_at >= CURRENT_DATE - INTERVAL '30 days'
@@ -109,7 +107,7 @@ func (r *BalancePostgresRepository) GetHistoricalBalance(userID int, limit int)
 		LIMIT $2
 	`
 
-	rows, err := r.pool.Query(context.Background(), query, userID, limit)
+	rows, err := r.db.Query(ctx, query, userID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -133,24 +131,20 @@ func (r *BalancePostgresRepository) GetHistoricalBalance(userID int, limit int)
 }
 
 // GetBalanceAtTime calculates the balance at a specific point in time from transaction history
-func (r *BalancePostgresRepository) GetBalanceAtTime(userID int, timestamp time.Time) (*domain.Balance, error) {
+func (r *BalancePostgresRepository) GetBalanceAtTime(ctx context.Context, userID int, timestamp time.Time) (*domain.Balance, error) {
 	query := `
 		SELECT 
 			$1::integer as user_id,
-			COALESCE(SUM(CASE 
-				WHEN to_user_id = $1 AND type IN ('credit', 'transfer') THEN amount
-				WHEN from_user_id = $1 AND type IN ('debit', 'transfer') THEN -amount
-				ELSE 0 
-			END), 0) as amount,
+			COALESCE(SUM(` + signedAmountSQL + `), 0) as amount,
 			$2::timestamp as last_updated_at
-		FROM transactions 
+		FROM transaction_ledger
 		WHERE (to_user_id = $1 OR from_user_id = $1) 
 			AND status = 'completed'
 			AND created_at <= $2
 	`
 
 	balance := &domain.Balance{}
-	err := r.pool.QueryRow(context.Background(), query, userID, timestamp).Scan(
+	err := r.db.QueryRow(ctx, query, userID, timestamp).Scan(
 		&balance.UserID, &balance.Amount, &balance.LastUpdatedAt,
 	)
 
@@ -169,23 +163,19 @@ func (r *BalancePostgresRepository) GetBalanceAtTime(userID int, timestamp time.
 	return balance, nil
 }
 
-func (r *BalancePostgresRepository) GetCurrentBalance(userID int) (*domain.Balance, error) {
+func (r *BalancePostgresRepository) GetCurrentBalance(ctx context.Context, userID int) (*domain.Balance, error) {
 	query := `
 		SELECT 
 			$1::integer as user_id,
-			COALESCE(SUM(CASE 
-				WHEN to_user_id = $1 AND type IN ('credit', 'transfer') THEN amount
-				WHEN from_user_id = $1 AND type IN ('debit', 'transfer') THEN -amount
-				ELSE 0 
-			END), 0) as amount,
+			COALESCE(SUM(` + signedAmountSQL + `), 0) as amount,
 			NOW()::timestamp as last_updated_at
-		FROM transactions 
+		FROM transaction_ledger
 		WHERE (to_user_id = $1 OR from_user_id = $1) 
 			AND status = 'completed'
 	`
 
 	balance := &domain.Balance{}
-	err := r.pool.QueryRow(context.Background(), query, userID).Scan(
+	err := r.db.QueryRow(ctx, query, userID).Scan(
 		&balance.UserID, &balance.Amount, &balance.LastUpdatedAt,
 	)
 
@@ -28,6 +28,7 @@ func getTestConn(t *testing.T) *pgxpool.Pool {
 }
 
 func TestBalancePostgresRepository_GetHistoricalBalance(t *testing.T) {
+	ctx := context.Background()
 	conn := getTestConn(t)
 	repo := NewBalancePostgresRepository(conn)
 	userID := 7771
@@ -77,7 +78,7 @@ func TestBalancePostgresRepository_GetHistoricalBalance(t *testing.T) {
 	conn.Exec(context.Background(), "INSERT INTO transactions (from_user_id, to_user_id, amount, type, status, created_at) VALUES ($1,$2,$3,$4,$5,$6)", tx3.FromUserID, tx3.ToUserID, tx3.Amount, tx3.Type, tx3.Status, tx3.CreatedAt)
 
 	// Call GetHistoricalBalance
-	balances, err := repo.GetHistoricalBalance(userID, 7771)
+	balances, err := repo.GetHistoricalBalance(ctx, userID, 7771)
 	if err != nil {
 		t.Fatalf("GetHistoricalBalance failed: %v", err)
 	}
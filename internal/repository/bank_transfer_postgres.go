@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// bankTransferColumns is the column list shared by every bank transfer SELECT.
+const bankTransferColumns = `id, user_id, funding_source_id, direction, rail, amount, status,
+	COALESCE(return_code, ''), COALESCE(return_reason, ''), transaction_id, created_at, settle_at, completed_at`
+
+// BankTransferPostgresRepository implements domain.BankTransferRepository using PostgreSQL.
+type BankTransferPostgresRepository struct {
+	db DBTX
+}
+
+// NewBankTransferPostgresRepository creates a new BankTransferPostgresRepository.
+func NewBankTransferPostgresRepository(pool *pgxpool.Pool) *BankTransferPostgresRepository {
+	return &BankTransferPostgresRepository{db: pool}
+}
+
+// scanBankTransfer scans a row selected with bankTransferColumns.
+func scanBankTransfer(row pgx.Row) (*domain.BankTransfer, error) {
+	t := &domain.BankTransfer{}
+	err := row.Scan(&t.ID, &t.UserID, &t.FundingSourceID, &t.Direction, &t.Rail, &t.Amount, &t.Status,
+		&t.ReturnCode, &t.ReturnReason, &t.TransactionID, &t.CreatedAt, &t.SettleAt, &t.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// list runs a query selecting bankTransferColumns and scans its rows.
+func (r *BankTransferPostgresRepository) list(ctx context.Context, query string, args ...any) ([]*domain.BankTransfer, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []*domain.BankTransfer
+	for rows.Next() {
+		t, err := scanBankTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, rows.Err()
+}
+
+// Create stores a pending transfer.
+func (r *BankTransferPostgresRepository) Create(ctx context.Context, t *domain.BankTransfer) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO bank_transfers (user_id, funding_source_id, direction, rail, amount, status, created_at, settle_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		t.UserID, t.FundingSourceID, t.Direction, t.Rail, t.Amount, t.Status, t.CreatedAt, t.SettleAt,
+	).Scan(&t.ID)
+}
+
+// Get fetches a transfer by ID.
+func (r *BankTransferPostgresRepository) Get(ctx context.Context, id int) (*domain.BankTransfer, error) {
+	t, err := scanBankTransfer(r.db.QueryRow(ctx, `SELECT `+bankTransferColumns+` FROM bank_transfers WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	return t, err
+}
+
+// ListByUser fetches a page of a user's transfers, newest first.
+func (r *BankTransferPostgresRepository) ListByUser(ctx context.Context, userID, limit, offset int) ([]*domain.BankTransfer, error) {
+	return r.list(ctx, `SELECT `+bankTransferColumns+` FROM bank_transfers
+		WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`, userID, limit, offset)
+}
+
+// ListByStatus fetches a page of the transfers with a status, oldest first.
+func (r *BankTransferPostgresRepository) ListByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.BankTransfer, error) {
+	return r.list(ctx, `SELECT `+bankTransferColumns+` FROM bank_transfers
+		WHERE status = $1 ORDER BY created_at, id LIMIT $2 OFFSET $3`, status, limit, offset)
+}
+
+// ListDue fetches the pending transfers due at now, the earliest first.
+func (r *BankTransferPostgresRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.BankTransfer, error) {
+	return r.list(ctx, `SELECT `+bankTransferColumns+` FROM bank_transfers
+		WHERE status = 'pending' AND settle_at <= $1 ORDER BY settle_at, id LIMIT $2`, now, limit)
+}
+
+// Complete moves a pending transfer to its final status, stamping completed_at.
+func (r *BankTransferPostgresRepository) Complete(ctx context.Context, t *domain.BankTransfer) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE bank_transfers SET status = $2, return_code = NULLIF($3, ''), return_reason = NULLIF($4, ''),
+			transaction_id = $5, completed_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING completed_at`,
+		t.ID, t.Status, t.ReturnCode, t.ReturnReason, t.TransactionID,
+	).Scan(&t.CompletedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrBankTransferNotPending
+	}
+	return err
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// BatchPostgresRepository implements domain.BatchRepository using PostgreSQL.
+type BatchPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewBatchPostgresRepository creates a new BatchPostgresRepository.
+func NewBatchPostgresRepository(pool *pgxpool.Pool) *BatchPostgresRepository {
+	return &BatchPostgresRepository{pool: pool}
+}
+
+// Create inserts a new batch record.
+func (r *BatchPostgresRepository) Create(ctx context.Context, b *domain.BatchRecord) error {
+	query := `
+		INSERT INTO worker_batches (batch_id, status, total_tasks, created_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		b.BatchID, b.Status, b.TotalTasks, b.CreatedAt, b.CompletedAt,
+	)
+	return err
+}
+
+// GetByID fetches a batch record by batch ID.
+func (r *BatchPostgresRepository) GetByID(ctx context.Context, batchID string) (*domain.BatchRecord, error) {
+	query := `
+		SELECT batch_id, status, total_tasks, created_at, completed_at
+		FROM worker_batches WHERE batch_id = $1
+	`
+	b := &domain.BatchRecord{}
+	err := r.pool.QueryRow(ctx, query, batchID).Scan(
+		&b.BatchID, &b.Status, &b.TotalTasks, &b.CreatedAt, &b.CompletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// Update stores the current status of a batch.
+func (r *BatchPostgresRepository) Update(ctx context.Context, b *domain.BatchRecord) error {
+	result, err := r.pool.Exec(ctx,
+		`UPDATE worker_batches SET status = $1, completed_at = $2 WHERE batch_id = $3`,
+		b.Status, b.CompletedAt, b.BatchID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("batch not found")
+	}
+	return nil
+}
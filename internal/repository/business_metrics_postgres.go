@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// BusinessMetricsPostgresRepository implements domain.BusinessMetricsRepository using PostgreSQL.
+type BusinessMetricsPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewBusinessMetricsPostgresRepository creates a new BusinessMetricsPostgresRepository.
+func NewBusinessMetricsPostgresRepository(pool *pgxpool.Pool) *BusinessMetricsPostgresRepository {
+	return &BusinessMetricsPostgresRepository{pool: pool}
+}
+
+// UserActivity counts users not erased, by when they last logged in.
+func (r *BusinessMetricsPostgresRepository) UserActivity(ctx context.Context, now time.Time) (*domain.UserActivityStats, error) {
+	query := `SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE last_login_at > $1),
+			COUNT(*) FILTER (WHERE last_login_at > $2),
+			COUNT(*) FILTER (WHERE last_login_at > $3)
+		FROM users WHERE erased_at IS NULL`
+	stats := &domain.UserActivityStats{}
+	err := r.pool.QueryRow(ctx, query, now.Add(-time.Hour), now.Add(-24*time.Hour), now.AddDate(0, 0, -30)).
+		Scan(&stats.Total, &stats.ActiveLastHour, &stats.ActiveLastDay, &stats.ActiveLastMonth)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// TransactionStats counts the transactions created since a time, per type.
+func (r *BusinessMetricsPostgresRepository) TransactionStats(ctx context.Context, since time.Time) ([]*domain.TransactionTypeStats, error) {
+	query := `SELECT type, COUNT(*), COUNT(*) FILTER (WHERE status = 'completed')
+		FROM transactions WHERE created_at >= $1
+		GROUP BY type ORDER BY type`
+	rows, err := r.pool.Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*domain.TransactionTypeStats
+	for rows.Next() {
+		s := &domain.TransactionTypeStats{}
+		if err := rows.Scan(&s.Type, &s.Total, &s.Completed); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// BalanceStats sums all balances and counts the users at or below each bound.
+func (r *BusinessMetricsPostgresRepository) BalanceStats(ctx context.Context, bounds []float64) (*domain.BalanceStats, error) {
+	stats := &domain.BalanceStats{UsersAtOrBelow: make(map[float64]int, len(bounds))}
+	err := r.pool.QueryRow(ctx, `SELECT COALESCE(SUM(amount), 0), COUNT(*) FROM balances`).Scan(&stats.Total, &stats.Users)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT b.bound, COUNT(bal.user_id)
+		FROM unnest($1::numeric[]) AS b(bound)
+		LEFT JOIN balances bal ON bal.amount <= b.bound
+		GROUP BY b.bound`
+	rows, err := r.pool.Query(ctx, query, bounds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bound float64
+		var users int
+		if err := rows.Scan(&bound, &users); err != nil {
+			return nil, err
+		}
+		stats.UsersAtOrBelow[bound] = users
+	}
+	return stats, rows.Err()
+}
+
+// Ping checks the database connection health.
+func (r *BusinessMetricsPostgresRepository) Ping(ctx context.Context) error {
+	_, err := r.pool.Exec(ctx, "SELECT 1")
+	return err
+}
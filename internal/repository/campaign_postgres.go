@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// campaignColumns is the column list shared by every campaign SELECT.
+const campaignColumns = `id, name, condition, threshold, bonus, budget, spent, starts_at, ends_at, created_by, created_at, updated_at`
+
+// notBonus excludes campaign bonuses and referral rewards from transactions aliased t.
+const notBonus = `NOT EXISTS (SELECT 1 FROM campaign_awards a WHERE a.bonus_transaction_id = t.id)
+	AND NOT EXISTS (SELECT 1 FROM referrals rf WHERE t.id IN (rf.referrer_transaction_id, rf.referee_transaction_id))`
+
+// CampaignPostgresRepository implements domain.CampaignRepository using PostgreSQL.
+type CampaignPostgresRepository struct {
+	db DBTX
+}
+
+// NewCampaignPostgresRepository creates a new CampaignPostgresRepository.
+func NewCampaignPostgresRepository(pool *pgxpool.Pool) *CampaignPostgresRepository {
+	return &CampaignPostgresRepository{db: pool}
+}
+
+// scanCampaign scans a row selected with campaignColumns.
+func scanCampaign(row pgx.Row) (*domain.Campaign, error) {
+	c := &domain.Campaign{}
+	err := row.Scan(&c.ID, &c.Name, &c.Condition, &c.Threshold, &c.Bonus, &c.Budget, &c.Spent,
+		&c.StartsAt, &c.EndsAt, &c.CreatedBy, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// queryCampaigns runs a query selecting campaignColumns.
+func (r *CampaignPostgresRepository) queryCampaigns(ctx context.Context, query string, args ...any) ([]*domain.Campaign, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var campaigns []*domain.Campaign
+	for rows.Next() {
+		c, err := scanCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, c)
+	}
+	return campaigns, rows.Err()
+}
+
+// List fetches every campaign, newest first.
+func (r *CampaignPostgresRepository) List(ctx context.Context) ([]*domain.Campaign, error) {
+	return r.queryCampaigns(ctx, `SELECT `+campaignColumns+` FROM campaigns ORDER BY starts_at DESC, id DESC`)
+}
+
+// GetByID fetches a campaign by ID.
+func (r *CampaignPostgresRepository) GetByID(ctx context.Context, id int) (*domain.Campaign, error) {
+	c, err := scanCampaign(r.db.QueryRow(ctx, `SELECT `+campaignColumns+` FROM campaigns WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	return c, err
+}
+
+// Create stores a campaign.
+func (r *CampaignPostgresRepository) Create(ctx context.Context, c *domain.Campaign) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO campaigns (name, condition, threshold, bonus, budget, spent, starts_at, ends_at, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, 0, $6, $7, $8, NOW(), NOW())
+		RETURNING id, created_at, updated_at`,
+		c.Name, c.Condition, c.Threshold, c.Bonus, c.Budget, c.StartsAt, c.EndsAt, c.CreatedBy,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+}
+
+// Update changes a campaign's name, budget and end.
+func (r *CampaignPostgresRepository) Update(ctx context.Context, c *domain.Campaign) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE campaigns SET name = $1, budget = $2, ends_at = $3, updated_at = NOW()
+		WHERE id = $4 AND spent <= $2
+		RETURNING spent, updated_at`,
+		c.Name, c.Budget, c.EndsAt, c.ID,
+	).Scan(&c.Spent, &c.UpdatedAt)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM campaigns WHERE id = $1)`, c.ID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrCampaignNotFound
+	}
+	return domain.ErrCampaignBudgetBelowSpent
+}
+
+// Open fetches the running campaigns of a condition that can still pay the user.
+func (r *CampaignPostgresRepository) Open(ctx context.Context, condition string, userID int, at time.Time) ([]*domain.Campaign, error) {
+	return r.queryCampaigns(ctx, `SELECT `+campaignColumns+` FROM campaigns c
+		WHERE condition = $1 AND starts_at <= $3 AND ends_at > $3 AND spent + bonus <= budget
+		  AND NOT EXISTS (SELECT 1 FROM campaign_awards a WHERE a.campaign_id = c.id AND a.user_id = $2)
+		ORDER BY id`, condition, userID, at)
+}
+
+// IsFirstDeposit reports whether a credit is the user's first, bonuses aside.
+func (r *CampaignPostgresRepository) IsFirstDeposit(ctx context.Context, userID, transactionID int) (bool, error) {
+	var first bool
+	err := r.db.QueryRow(ctx, `
+		SELECT NOT EXISTS (
+			SELECT 1 FROM transactions t
+			WHERE t.to_user_id = $1 AND t.type = 'credit' AND t.status = 'completed' AND t.id < $2 AND `+notBonus+`
+		)`, userID, transactionID,
+	).Scan(&first)
+	return first, err
+}
+
+// Volume sums the completed transactions a user paid since a time.
+func (r *CampaignPostgresRepository) Volume(ctx context.Context, userID int, since time.Time) (float64, error) {
+	var volume float64
+	err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(SUM(t.amount), 0)::float8 FROM transactions t
+		WHERE t.status = 'completed' AND t.created_at >= $2
+		  AND CASE WHEN t.type = 'credit' THEN t.to_user_id ELSE t.from_user_id END = $1
+		  AND `+notBonus,
+		userID, since.UTC(),
+	).Scan(&volume)
+	return volume, err
+}
+
+// Award charges a bonus to its campaign's budget and records it.
+func (r *CampaignPostgresRepository) Award(ctx context.Context, award *domain.CampaignAward) error {
+	// Charging first locks the campaign, so concurrent awards can't overspend
+	result, err := r.db.Exec(ctx, `
+		UPDATE campaigns SET spent = spent + $2, updated_at = NOW()
+		WHERE id = $1 AND spent + $2 <= budget AND starts_at <= NOW() AND ends_at > NOW()`,
+		award.CampaignID, award.Amount)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrCampaignBudgetExhausted
+	}
+
+	err = r.db.QueryRow(ctx, `
+		INSERT INTO campaign_awards (campaign_id, user_id, transaction_id, bonus_transaction_id, amount, awarded_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (campaign_id, user_id) DO NOTHING
+		RETURNING id, awarded_at`,
+		award.CampaignID, award.UserID, award.TransactionID, award.BonusTransactionID, award.Amount,
+	).Scan(&award.ID, &award.AwardedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrCampaignBonusAwarded
+	}
+	return err
+}
+
+// Stats summarizes a campaign's awards and the transactions that earned them.
+func (r *CampaignPostgresRepository) Stats(ctx context.Context, campaignID int) (*domain.CampaignStats, error) {
+	stats := &domain.CampaignStats{CampaignID: campaignID}
+	var budget float64
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(a.id), c.spent, c.budget, COALESCE(SUM(t.amount), 0)::float8, MIN(a.awarded_at), MAX(a.awarded_at)
+		FROM campaigns c
+		LEFT JOIN campaign_awards a ON a.campaign_id = c.id
+		LEFT JOIN transactions t ON t.id = a.transaction_id
+		WHERE c.id = $1
+		GROUP BY c.id`, campaignID,
+	).Scan(&stats.Awards, &stats.Spent, &budget, &stats.QualifyingVolume, &stats.FirstAwardAt, &stats.LastAwardAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	if err != nil {
+		return nil, err
+	}
+	stats.RemainingBudget = budget - stats.Spent
+	if budget > 0 {
+		stats.BudgetUsed = math.Round(stats.Spent/budget*10000) / 100
+	}
+	return stats, nil
+}
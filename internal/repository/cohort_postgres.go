@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// CohortPostgresRepository implements domain.CohortRepository using PostgreSQL.
+type CohortPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCohortPostgresRepository creates a new CohortPostgresRepository.
+func NewCohortPostgresRepository(pool *pgxpool.Pool) *CohortPostgresRepository {
+	return &CohortPostgresRepository{pool: pool}
+}
+
+// RefreshCohorts recomputes the signup cohorts of every month since the given one.
+func (r *CohortPostgresRepository) RefreshCohorts(ctx context.Context, since time.Time) error {
+	query := `WITH members AS (
+			SELECT u.id, u.created_at,
+				u.created_at + INTERVAL '14 days' <= NOW() AS eligible_7d,
+				u.created_at + INTERVAL '60 days' <= NOW() AS eligible_30d,
+				EXISTS (SELECT 1 FROM transaction_ledger t
+					WHERE (t.from_user_id = u.id OR t.to_user_id = u.id) AND t.status = 'completed'
+						AND t.created_at >= u.created_at + INTERVAL '7 days'
+						AND t.created_at < u.created_at + INTERVAL '14 days') AS active_7d,
+				EXISTS (SELECT 1 FROM transaction_ledger t
+					WHERE (t.from_user_id = u.id OR t.to_user_id = u.id) AND t.status = 'completed'
+						AND t.created_at >= u.created_at + INTERVAL '30 days'
+						AND t.created_at < u.created_at + INTERVAL '60 days') AS active_30d
+			FROM users u
+			WHERE u.created_at >= date_trunc('month', $1::timestamp)
+		)
+		INSERT INTO signup_cohorts (cohort_month, signups, eligible_7d, retained_7d, eligible_30d, retained_30d, computed_at)
+		SELECT date_trunc('month', created_at)::date,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE eligible_7d),
+			COUNT(*) FILTER (WHERE eligible_7d AND active_7d),
+			COUNT(*) FILTER (WHERE eligible_30d),
+			COUNT(*) FILTER (WHERE eligible_30d AND active_30d),
+			NOW()
+		FROM members
+		GROUP BY 1
+		ON CONFLICT (cohort_month) DO UPDATE SET
+			signups = EXCLUDED.signups,
+			eligible_7d = EXCLUDED.eligible_7d,
+			retained_7d = EXCLUDED.retained_7d,
+			eligible_30d = EXCLUDED.eligible_30d,
+			retained_30d = EXCLUDED.retained_30d,
+			computed_at = EXCLUDED.computed_at`
+	_, err := r.pool.Exec(ctx, query, since)
+	return err
+}
+
+// RefreshGrowth recomputes the new and transacting users of every month since the given one.
+func (r *CohortPostgresRepository) RefreshGrowth(ctx context.Context, since time.Time) error {
+	query := `INSERT INTO monthly_growth (month, new_users, active_users, computed_at)
+		SELECT m.month::date,
+			(SELECT COUNT(*) FROM users u
+				WHERE u.created_at >= m.month AND u.created_at < m.month + INTERVAL '1 month'),
+			(SELECT COUNT(DISTINCT a.user_id) FROM (
+				SELECT t.from_user_id AS user_id FROM transaction_ledger t
+					WHERE t.status = 'completed' AND t.created_at >= m.month AND t.created_at < m.month + INTERVAL '1 month'
+				UNION ALL
+				SELECT t.to_user_id FROM transaction_ledger t
+					WHERE t.status = 'completed' AND t.created_at >= m.month AND t.created_at < m.month + INTERVAL '1 month'
+			) a WHERE a.user_id IS NOT NULL),
+			NOW()
+		FROM generate_series(date_trunc('month', $1::timestamp), date_trunc('month', NOW()::timestamp), INTERVAL '1 month') AS m(month)
+		ON CONFLICT (month) DO UPDATE SET
+			new_users = EXCLUDED.new_users,
+			active_users = EXCLUDED.active_users,
+			computed_at = EXCLUDED.computed_at`
+	_, err := r.pool.Exec(ctx, query, since)
+	return err
+}
+
+// ListCohorts retrieves the stored cohorts since the given month, oldest first.
+func (r *CohortPostgresRepository) ListCohorts(ctx context.Context, since time.Time) ([]*domain.SignupCohort, error) {
+	query := `SELECT cohort_month, signups, eligible_7d, retained_7d, eligible_30d, retained_30d, computed_at
+		FROM signup_cohorts WHERE cohort_month >= date_trunc('month', $1::timestamp) ORDER BY cohort_month`
+	rows, err := r.pool.Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cohorts []*domain.SignupCohort
+	for rows.Next() {
+		c := &domain.SignupCohort{}
+		if err := rows.Scan(&c.Month, &c.Signups, &c.Eligible7d, &c.Retained7d, &c.Eligible30d, &c.Retained30d, &c.ComputedAt); err != nil {
+			return nil, err
+		}
+		cohorts = append(cohorts, c)
+	}
+	return cohorts, rows.Err()
+}
+
+// ListGrowth retrieves the stored growth since the given month, oldest first.
+func (r *CohortPostgresRepository) ListGrowth(ctx context.Context, since time.Time) ([]*domain.MonthlyGrowth, error) {
+	query := `SELECT month, new_users, active_users, computed_at
+		FROM monthly_growth WHERE month >= date_trunc('month', $1::timestamp) ORDER BY month`
+	rows, err := r.pool.Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var growth []*domain.MonthlyGrowth
+	for rows.Next() {
+		g := &domain.MonthlyGrowth{}
+		if err := rows.Scan(&g.Month, &g.NewUsers, &g.ActiveUsers, &g.ComputedAt); err != nil {
+			return nil, err
+		}
+		growth = append(growth, g)
+	}
+	return growth, rows.Err()
+}
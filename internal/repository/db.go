@@ -4,27 +4,24 @@ import (
 	"context"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// ConnectDB establishes a connection pool to PostgreSQL using pgxpool.
-// It returns a connected *pgxpool.Pool or an error.
-func ConnectDB(ctx context.Context, dbURL string) (*pgxpool.Pool, error) {
+// DBTX is the query interface shared by *pgxpool.Pool and pgx.Tx.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// ConnectDB opens a connection pool and verifies it with a ping.
+func ConnectDB(ctx context.Context, config *pgxpool.Config) (*pgxpool.Pool, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	config, err := pgxpool.ParseConfig(dbURL)
-	if err != nil {
-		return nil, err
-	}
-
-	// Configure connection pool settings
-	config.MaxConns = 20                      // Maximum number of connections in the pool
-	config.MinConns = 5                       // Minimum number of connections in the pool
-	config.MaxConnLifetime = time.Hour        // Maximum lifetime of a connection
-	config.MaxConnIdleTime = 30 * time.Minute // Maximum idle time of a connection
-	config.HealthCheckPeriod = time.Minute    // How often to check connection health
-
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, err
@@ -38,3 +35,19 @@ func ConnectDB(ctx context.Context, dbURL string) (*pgxpool.Pool, error) {
 
 	return pool, nil
 }
+
+// PoolStats reports connection pool usage for metrics.
+type PoolStats struct {
+	pool *pgxpool.Pool
+}
+
+// NewPoolStats creates a PoolStats for pool.
+func NewPoolStats(pool *pgxpool.Pool) *PoolStats {
+	return &PoolStats{pool: pool}
+}
+
+// PoolStats returns the number of acquired, idle, total and maximum connections.
+func (p *PoolStats) PoolStats() (active, idle, total, max int) {
+	stat := p.pool.Stat()
+	return int(stat.AcquiredConns()), int(stat.IdleConns()), int(stat.TotalConns()), int(stat.MaxConns())
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// deadLetterColumns is the column list shared by every dead letter SELECT.
+const deadLetterColumns = `id, task_id, type, user_id, to_user_id, amount, priority,
+		       callback_url, attempts, last_error, failed_at, requeued_at`
+
+// DeadLetterPostgresRepository implements domain.DeadLetterRepository using PostgreSQL.
+type DeadLetterPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewDeadLetterPostgresRepository creates a new DeadLetterPostgresRepository.
+func NewDeadLetterPostgresRepository(pool *pgxpool.Pool) *DeadLetterPostgresRepository {
+	return &DeadLetterPostgresRepository{pool: pool}
+}
+
+// scanDeadLetter scans a row selected with deadLetterColumns.
+func scanDeadLetter(row pgx.Row) (*domain.DeadLetter, error) {
+	d := &domain.DeadLetter{}
+	err := row.Scan(
+		&d.ID, &d.TaskID, &d.Type, &d.UserID, &d.ToUserID, &d.Amount, &d.Priority,
+		&d.CallbackURL, &d.Attempts, &d.LastError, &d.FailedAt, &d.RequeuedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Create inserts a new dead letter entry.
+func (r *DeadLetterPostgresRepository) Create(ctx context.Context, d *domain.DeadLetter) error {
+	query := `
+		INSERT INTO worker_dead_letters (
+			task_id, type, user_id, to_user_id, amount, priority,
+			callback_url, attempts, last_error, failed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+	return r.pool.QueryRow(ctx, query,
+		d.TaskID, d.Type, d.UserID, d.ToUserID, d.Amount, d.Priority,
+		d.CallbackURL, d.Attempts, d.LastError, d.FailedAt,
+	).Scan(&d.ID)
+}
+
+// GetByID fetches a dead letter entry by ID.
+func (r *DeadLetterPostgresRepository) GetByID(ctx context.Context, id int) (*domain.DeadLetter, error) {
+	query := `SELECT ` + deadLetterColumns + ` FROM worker_dead_letters WHERE id = $1`
+	d, err := scanDeadLetter(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// List fetches dead letter entries, newest first.
+func (r *DeadLetterPostgresRepository) List(ctx context.Context, includeRequeued bool, limit, offset int) ([]*domain.DeadLetter, error) {
+	query := `
+		SELECT ` + deadLetterColumns + `
+		FROM worker_dead_letters
+		WHERE $1 OR requeued_at IS NULL
+		ORDER BY failed_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.pool.Query(ctx, query, includeRequeued, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.DeadLetter
+	for rows.Next() {
+		d, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// MarkRequeued records that an entry was resubmitted.
+func (r *DeadLetterPostgresRepository) MarkRequeued(ctx context.Context, id int) error {
+	result, err := r.pool.Exec(ctx,
+		`UPDATE worker_dead_letters SET requeued_at = NOW() WHERE id = $1 AND requeued_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrDeadLetterRequeued
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// disputeColumns is the column list shared by every dispute SELECT.
+const disputeColumns = `id, transaction_id, user_id, reason, description, document_ids, status,
+	COALESCE(resolution, ''), handled_by, refund_transaction_id, created_at, updated_at, closed_at`
+
+// DisputePostgresRepository implements domain.DisputeRepository using PostgreSQL.
+type DisputePostgresRepository struct {
+	db DBTX
+}
+
+// NewDisputePostgresRepository creates a new DisputePostgresRepository.
+func NewDisputePostgresRepository(pool *pgxpool.Pool) *DisputePostgresRepository {
+	return &DisputePostgresRepository{db: pool}
+}
+
+// scanDispute scans a row selected with disputeColumns.
+func scanDispute(row pgx.Row) (*domain.Dispute, error) {
+	d := &domain.Dispute{}
+	err := row.Scan(&d.ID, &d.TransactionID, &d.UserID, &d.Reason, &d.Description, &d.DocumentIDs, &d.Status,
+		&d.Resolution, &d.HandledBy, &d.RefundTransactionID, &d.CreatedAt, &d.UpdatedAt, &d.ClosedAt)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// listDisputes runs a query selecting disputeColumns.
+func (r *DisputePostgresRepository) listDisputes(ctx context.Context, query string, args ...any) ([]*domain.Dispute, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var disputes []*domain.Dispute
+	for rows.Next() {
+		d, err := scanDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, d)
+	}
+	return disputes, rows.Err()
+}
+
+// Create stores a dispute, refusing a second dispute of the same transaction.
+func (r *DisputePostgresRepository) Create(ctx context.Context, d *domain.Dispute) error {
+	if d.DocumentIDs == nil {
+		d.DocumentIDs = []int{}
+	}
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO disputes (transaction_id, user_id, reason, description, document_ids, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id, created_at, updated_at`,
+		d.TransactionID, d.UserID, d.Reason, d.Description, d.DocumentIDs, d.Status,
+	).Scan(&d.ID, &d.CreatedAt, &d.UpdatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return domain.ErrDisputeExists
+	}
+	return err
+}
+
+// GetByID fetches a dispute by ID.
+func (r *DisputePostgresRepository) GetByID(ctx context.Context, id int) (*domain.Dispute, error) {
+	d, err := scanDispute(r.db.QueryRow(ctx, `SELECT `+disputeColumns+` FROM disputes WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	return d, err
+}
+
+// ListByUser fetches a page of a user's disputes, newest first.
+func (r *DisputePostgresRepository) ListByUser(ctx context.Context, userID, limit, offset int) ([]*domain.Dispute, error) {
+	return r.listDisputes(ctx, `SELECT `+disputeColumns+` FROM disputes
+		WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`, userID, limit, offset)
+}
+
+// ListByStatus fetches a page of the disputes with a status, oldest first.
+func (r *DisputePostgresRepository) ListByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Dispute, error) {
+	return r.listDisputes(ctx, `SELECT `+disputeColumns+` FROM disputes
+		WHERE status = $1 ORDER BY created_at, id LIMIT $2 OFFSET $3`, status, limit, offset)
+}
+
+// UpdateStatus moves a dispute that is still open to its new status.
+func (r *DisputePostgresRepository) UpdateStatus(ctx context.Context, d *domain.Dispute) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE disputes SET status = $2, resolution = NULLIF($3, ''), handled_by = $4, refund_transaction_id = $5,
+			updated_at = NOW(), closed_at = CASE WHEN $2 IN ('resolved', 'refunded') THEN NOW() END
+		WHERE id = $1 AND status IN ('open', 'investigating')
+		RETURNING updated_at, closed_at`,
+		d.ID, d.Status, d.Resolution, d.HandledBy, d.RefundTransactionID,
+	).Scan(&d.UpdatedAt, &d.ClosedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrDisputeClosed
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// documentColumns is the column list shared by every document SELECT.
+const documentColumns = `id, user_id, kind, filename, content_type, size_bytes, sha256, scan_status,
+	uploaded_by, object_key, created_at`
+
+// DocumentPostgresRepository implements domain.DocumentRepository using PostgreSQL.
+type DocumentPostgresRepository struct {
+	db DBTX
+}
+
+// NewDocumentPostgresRepository creates a new DocumentPostgresRepository.
+func NewDocumentPostgresRepository(pool *pgxpool.Pool) *DocumentPostgresRepository {
+	return &DocumentPostgresRepository{db: pool}
+}
+
+// scanDocument scans a row selected with documentColumns.
+func scanDocument(row pgx.Row) (*domain.Document, error) {
+	d := &domain.Document{}
+	err := row.Scan(&d.ID, &d.UserID, &d.Kind, &d.Filename, &d.ContentType, &d.SizeBytes, &d.SHA256, &d.ScanStatus,
+		&d.UploadedBy, &d.ObjectKey, &d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Create stores a document's metadata.
+func (r *DocumentPostgresRepository) Create(ctx context.Context, d *domain.Document) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO documents (user_id, kind, filename, content_type, size_bytes, sha256, scan_status, uploaded_by, object_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		RETURNING id, created_at`,
+		d.UserID, d.Kind, d.Filename, d.ContentType, d.SizeBytes, d.SHA256, d.ScanStatus, d.UploadedBy, d.ObjectKey,
+	).Scan(&d.ID, &d.CreatedAt)
+}
+
+// GetByID fetches a document by ID.
+func (r *DocumentPostgresRepository) GetByID(ctx context.Context, id int) (*domain.Document, error) {
+	d, err := scanDocument(r.db.QueryRow(ctx, `SELECT `+documentColumns+` FROM documents WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	return d, err
+}
+
+// ListByUser fetches a page of a user's documents, newest first.
+func (r *DocumentPostgresRepository) ListByUser(ctx context.Context, userID, limit, offset int) ([]*domain.Document, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+documentColumns+` FROM documents
+		WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var documents []*domain.Document
+	for rows.Next() {
+		d, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, d)
+	}
+	return documents, rows.Err()
+}
+
+// Delete removes a document's metadata.
+func (r *DocumentPostgresRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM documents WHERE id = $1`, id)
+	return err
+}
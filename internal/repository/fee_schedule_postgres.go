@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// feeScheduleColumns is the column list shared by every fee schedule SELECT.
+const feeScheduleColumns = `id, transaction_type, role, effective_from, tiers, created_by, created_at, updated_at`
+
+// FeeSchedulePostgresRepository implements domain.FeeScheduleRepository using PostgreSQL.
+type FeeSchedulePostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewFeeSchedulePostgresRepository creates a new FeeSchedulePostgresRepository.
+func NewFeeSchedulePostgresRepository(pool *pgxpool.Pool) *FeeSchedulePostgresRepository {
+	return &FeeSchedulePostgresRepository{pool: pool}
+}
+
+// scanFeeSchedule scans a row selected with feeScheduleColumns.
+func scanFeeSchedule(row pgx.Row) (*domain.FeeSchedule, error) {
+	s := &domain.FeeSchedule{}
+	var tiers []byte
+	if err := row.Scan(&s.ID, &s.TransactionType, &s.Role, &s.EffectiveFrom, &tiers, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tiers, &s.Tiers); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// List fetches the schedules, of txType unless it is empty, newest version first.
+func (r *FeeSchedulePostgresRepository) List(ctx context.Context, txType string) ([]*domain.FeeSchedule, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+feeScheduleColumns+` FROM fee_schedules
+		WHERE $1 = '' OR transaction_type = $1
+		ORDER BY transaction_type, role, effective_from DESC`, txType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []*domain.FeeSchedule
+	for rows.Next() {
+		s, err := scanFeeSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, s)
+	}
+	return schedules, rows.Err()
+}
+
+// GetByID fetches a schedule by ID.
+func (r *FeeSchedulePostgresRepository) GetByID(ctx context.Context, id int) (*domain.FeeSchedule, error) {
+	s, err := scanFeeSchedule(r.pool.QueryRow(ctx, `SELECT `+feeScheduleColumns+` FROM fee_schedules WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	return s, err
+}
+
+// Create stores a schedule unless its type and role already have a version at that time.
+func (r *FeeSchedulePostgresRepository) Create(ctx context.Context, s *domain.FeeSchedule) error {
+	tiers, err := json.Marshal(s.Tiers)
+	if err != nil {
+		return err
+	}
+	err = r.pool.QueryRow(ctx, `
+		INSERT INTO fee_schedules (transaction_type, role, effective_from, tiers, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (transaction_type, role, effective_from) DO NOTHING
+		RETURNING id, created_at, updated_at`,
+		s.TransactionType, s.Role, s.EffectiveFrom, tiers, s.CreatedBy,
+	).Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrFeeScheduleExists
+	}
+	return err
+}
+
+// Update changes a schedule's effective time and tiers.
+func (r *FeeSchedulePostgresRepository) Update(ctx context.Context, s *domain.FeeSchedule) error {
+	tiers, err := json.Marshal(s.Tiers)
+	if err != nil {
+		return err
+	}
+	err = r.pool.QueryRow(ctx, `
+		UPDATE fee_schedules SET effective_from = $1, tiers = $2, updated_at = NOW()
+		WHERE id = $3
+		RETURNING updated_at`,
+		s.EffectiveFrom, tiers, s.ID,
+	).Scan(&s.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrFeeScheduleNotFound
+	}
+	return err
+}
+
+// Delete removes a schedule.
+func (r *FeeSchedulePostgresRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM fee_schedules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrFeeScheduleNotFound
+	}
+	return nil
+}
+
+// InEffect fetches the latest version in effect at a time.
+func (r *FeeSchedulePostgresRepository) InEffect(ctx context.Context, txType, role string, at time.Time) (*domain.FeeSchedule, error) {
+	s, err := scanFeeSchedule(r.pool.QueryRow(ctx, `SELECT `+feeScheduleColumns+` FROM fee_schedules
+		WHERE transaction_type = $1 AND role IN ($2, '') AND effective_from <= $3
+		ORDER BY role = '', effective_from DESC
+		LIMIT 1`, txType, role, at))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // no schedule applies
+	}
+	return s, err
+}
+
+// MonthlyVolume sums the completed transactions of txType a user paid since a time.
+func (r *FeeSchedulePostgresRepository) MonthlyVolume(ctx context.Context, userID int, txType string, since time.Time) (float64, error) {
+	var volume float64
+	err := r.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0)::float8 FROM transactions
+		WHERE type = $2 AND status = 'completed' AND created_at >= $3
+		  AND CASE WHEN type = 'credit' THEN to_user_id ELSE from_user_id END = $1`,
+		userID, txType, since.UTC(),
+	).Scan(&volume)
+	return volume, err
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// fraudColumns is the column list shared by every fraud assessment SELECT.
+const fraudColumns = `id, transaction_id, user_id, counterparty_id, type, amount, score, reasons,
+	decision, review_status, reviewed_by, review_note, created_at, reviewed_at`
+
+// FraudPostgresRepository implements domain.FraudRepository using PostgreSQL.
+type FraudPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewFraudPostgresRepository creates a new FraudPostgresRepository.
+func NewFraudPostgresRepository(pool *pgxpool.Pool) *FraudPostgresRepository {
+	return &FraudPostgresRepository{pool: pool}
+}
+
+// scanFraudAssessment scans a row selected with fraudColumns.
+func scanFraudAssessment(row pgx.Row) (*domain.FraudAssessment, error) {
+	a := &domain.FraudAssessment{}
+	err := row.Scan(&a.ID, &a.TransactionID, &a.UserID, &a.CounterpartyID, &a.Type, &a.Amount, &a.Score, &a.Reasons,
+		&a.Decision, &a.ReviewStatus, &a.ReviewedBy, &a.ReviewNote, &a.CreatedAt, &a.ReviewedAt)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Signals gathers the user's outgoing transaction history in one pass.
+func (r *FraudPostgresRepository) Signals(ctx context.Context, userID int, counterpartyID *int, velocitySince, historySince time.Time) (*domain.FraudSignals, error) {
+	query := `SELECT
+			COUNT(*) FILTER (WHERE created_at >= $2),
+			COUNT(*) FILTER (WHERE status = 'completed' AND created_at >= $3),
+			COALESCE(AVG(amount) FILTER (WHERE status = 'completed' AND created_at >= $3), 0)
+		FROM transactions
+		WHERE from_user_id = $1 AND type IN ('debit', 'transfer') AND created_at >= LEAST($2, $3)`
+	s := &domain.FraudSignals{}
+	if err := r.pool.QueryRow(ctx, query, userID, velocitySince, historySince).Scan(&s.RecentCount, &s.HistoryCount, &s.HistoryAverage); err != nil {
+		return nil, err
+	}
+
+	if counterpartyID != nil {
+		query = `SELECT EXISTS (SELECT 1 FROM transaction_ledger
+			WHERE from_user_id = $1 AND to_user_id = $2 AND type = 'transfer' AND status = 'completed')`
+		if err := r.pool.QueryRow(ctx, query, userID, *counterpartyID).Scan(&s.KnownCounterparty); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Create inserts a new assessment.
+func (r *FraudPostgresRepository) Create(ctx context.Context, a *domain.FraudAssessment) error {
+	query := `
+		INSERT INTO fraud_assessments (transaction_id, user_id, counterparty_id, type, amount, score, reasons, decision, review_status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+	return r.pool.QueryRow(ctx, query,
+		a.TransactionID, a.UserID, a.CounterpartyID, a.Type, a.Amount, a.Score, a.Reasons, a.Decision, a.ReviewStatus, a.CreatedAt,
+	).Scan(&a.ID)
+}
+
+// LinkTransaction records the transaction an assessment was made for.
+func (r *FraudPostgresRepository) LinkTransaction(ctx context.Context, id, transactionID int) error {
+	_, err := r.pool.Exec(ctx, `UPDATE fraud_assessments SET transaction_id = $2 WHERE id = $1`, id, transactionID)
+	return err
+}
+
+// GetByID fetches an assessment by ID.
+func (r *FraudPostgresRepository) GetByID(ctx context.Context, id int) (*domain.FraudAssessment, error) {
+	query := `SELECT ` + fraudColumns + ` FROM fraud_assessments WHERE id = $1`
+	a, err := scanFraudAssessment(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListPendingReview fetches assessments awaiting review, oldest first.
+func (r *FraudPostgresRepository) ListPendingReview(ctx context.Context, limit, offset int) ([]*domain.FraudAssessment, error) {
+	query := `SELECT ` + fraudColumns + ` FROM fraud_assessments
+		WHERE review_status = 'pending'
+		ORDER BY created_at
+		LIMIT $1 OFFSET $2`
+	rows, err := r.pool.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assessments []*domain.FraudAssessment
+	for rows.Next() {
+		a, err := scanFraudAssessment(rows)
+		if err != nil {
+			return nil, err
+		}
+		assessments = append(assessments, a)
+	}
+	return assessments, rows.Err()
+}
+
+// UpdateReview stores the review fields of an assessment.
+func (r *FraudPostgresRepository) UpdateReview(ctx context.Context, a *domain.FraudAssessment) error {
+	query := `
+		UPDATE fraud_assessments
+		SET review_status = $2, reviewed_by = $3, review_note = $4, reviewed_at = $5
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, query, a.ID, a.ReviewStatus, a.ReviewedBy, a.ReviewNote, a.ReviewedAt)
+	return err
+}
@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// fundingSourceColumns is the column list shared by every funding source SELECT.
+const fundingSourceColumns = `id, user_id, kind, provider, external_ref, COALESCE(last4, ''), status, created_at, removed_at`
+
+// chargebackColumns is the column list shared by every chargeback SELECT.
+const chargebackColumns = `id, transaction_id, user_id, external_id, reason_code, amount, recovered, status,
+	debit_transaction_id, reinstate_transaction_id, froze_account, created_at, updated_at, closed_at`
+
+// FundingPostgresRepository implements domain.FundingRepository using PostgreSQL.
+type FundingPostgresRepository struct {
+	db DBTX
+}
+
+// NewFundingPostgresRepository creates a new FundingPostgresRepository.
+func NewFundingPostgresRepository(pool *pgxpool.Pool) *FundingPostgresRepository {
+	return &FundingPostgresRepository{db: pool}
+}
+
+// scanFundingSource scans a row selected with fundingSourceColumns.
+func scanFundingSource(row pgx.Row) (*domain.FundingSource, error) {
+	s := &domain.FundingSource{}
+	err := row.Scan(&s.ID, &s.UserID, &s.Kind, &s.Provider, &s.ExternalRef, &s.Last4, &s.Status, &s.CreatedAt, &s.RemovedAt)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// scanChargeback scans a row selected with chargebackColumns.
+func scanChargeback(row pgx.Row) (*domain.Chargeback, error) {
+	c := &domain.Chargeback{}
+	err := row.Scan(&c.ID, &c.TransactionID, &c.UserID, &c.ExternalID, &c.ReasonCode, &c.Amount, &c.Recovered, &c.Status,
+		&c.DebitTransactionID, &c.ReinstateTransactionID, &c.FrozeAccount, &c.CreatedAt, &c.UpdatedAt, &c.ClosedAt)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CreateSource stores a funding source unless it is already known.
+func (r *FundingPostgresRepository) CreateSource(ctx context.Context, s *domain.FundingSource) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO funding_sources (user_id, kind, provider, external_ref, last4, status, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, NOW())
+		RETURNING id, created_at`,
+		s.UserID, s.Kind, s.Provider, s.ExternalRef, s.Last4, s.Status,
+	).Scan(&s.ID, &s.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return domain.ErrFundingSourceExists
+	}
+	return err
+}
+
+// GetSource fetches a funding source by ID.
+func (r *FundingPostgresRepository) GetSource(ctx context.Context, id int) (*domain.FundingSource, error) {
+	s, err := scanFundingSource(r.db.QueryRow(ctx, `SELECT `+fundingSourceColumns+` FROM funding_sources WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	return s, err
+}
+
+// ListSources fetches a user's funding sources, newest first.
+func (r *FundingPostgresRepository) ListSources(ctx context.Context, userID int) ([]*domain.FundingSource, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+fundingSourceColumns+` FROM funding_sources
+		WHERE user_id = $1 ORDER BY created_at DESC, id DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sources []*domain.FundingSource
+	for rows.Next() {
+		s, err := scanFundingSource(rows)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, s)
+	}
+	return sources, rows.Err()
+}
+
+// RemoveSource marks a funding source removed; removing it again does nothing.
+func (r *FundingPostgresRepository) RemoveSource(ctx context.Context, id int) error {
+	_, err := r.db.Exec(ctx, `UPDATE funding_sources SET status = 'removed', removed_at = NOW()
+		WHERE id = $1 AND status = 'active'`, id)
+	return err
+}
+
+// CreateCredit links a credit to its funding source.
+func (r *FundingPostgresRepository) CreateCredit(ctx context.Context, c *domain.FundedCredit) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO funded_credits (transaction_id, funding_source_id, user_id, external_id, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT DO NOTHING`,
+		c.TransactionID, c.FundingSourceID, c.UserID, c.ExternalID, c.Amount)
+	return err
+}
+
+// GetCredit fetches the funded credit of a transaction.
+func (r *FundingPostgresRepository) GetCredit(ctx context.Context, transactionID int) (*domain.FundedCredit, error) {
+	c := &domain.FundedCredit{}
+	err := r.db.QueryRow(ctx, `
+		SELECT transaction_id, funding_source_id, user_id, external_id, amount, created_at, reversed_at
+		FROM funded_credits WHERE transaction_id = $1`, transactionID,
+	).Scan(&c.TransactionID, &c.FundingSourceID, &c.UserID, &c.ExternalID, &c.Amount, &c.CreatedAt, &c.ReversedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SetCreditReversed stamps or clears reversed_at of a funded credit.
+func (r *FundingPostgresRepository) SetCreditReversed(ctx context.Context, transactionID int, reversed bool) error {
+	_, err := r.db.Exec(ctx, `UPDATE funded_credits SET reversed_at = CASE WHEN $2 THEN NOW() END
+		WHERE transaction_id = $1`, transactionID, reversed)
+	return err
+}
+
+// CreateChargeback stores a chargeback unless its credit or notice already has one.
+func (r *FundingPostgresRepository) CreateChargeback(ctx context.Context, c *domain.Chargeback) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO chargebacks (transaction_id, user_id, external_id, reason_code, amount, recovered, status,
+			froze_account, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING id, created_at, updated_at`,
+		c.TransactionID, c.UserID, c.ExternalID, c.ReasonCode, c.Amount, c.Recovered, c.Status, c.FrozeAccount,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return domain.ErrChargebackExists
+	}
+	return err
+}
+
+// GetChargeback fetches a chargeback by ID with its events, oldest first.
+func (r *FundingPostgresRepository) GetChargeback(ctx context.Context, id int) (*domain.Chargeback, error) {
+	c, err := scanChargeback(r.db.QueryRow(ctx, `SELECT `+chargebackColumns+` FROM chargebacks WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, chargeback_id, status, COALESCE(note, ''), actor_id, created_at
+		FROM chargeback_events WHERE chargeback_id = $1 ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		e := &domain.ChargebackEvent{}
+		if err := rows.Scan(&e.ID, &e.ChargebackID, &e.Status, &e.Note, &e.ActorID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		c.Events = append(c.Events, e)
+	}
+	return c, rows.Err()
+}
+
+// listChargebacks runs a query selecting chargebackColumns.
+func (r *FundingPostgresRepository) listChargebacks(ctx context.Context, query string, args ...any) ([]*domain.Chargeback, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chargebacks []*domain.Chargeback
+	for rows.Next() {
+		c, err := scanChargeback(rows)
+		if err != nil {
+			return nil, err
+		}
+		chargebacks = append(chargebacks, c)
+	}
+	return chargebacks, rows.Err()
+}
+
+// ListChargebacksByUser fetches a page of a user's chargebacks, newest first.
+func (r *FundingPostgresRepository) ListChargebacksByUser(ctx context.Context, userID, limit, offset int) ([]*domain.Chargeback, error) {
+	return r.listChargebacks(ctx, `SELECT `+chargebackColumns+` FROM chargebacks
+		WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`, userID, limit, offset)
+}
+
+// ListChargebacksByStatus fetches a page of the chargebacks with a status, oldest first.
+func (r *FundingPostgresRepository) ListChargebacksByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Chargeback, error) {
+	return r.listChargebacks(ctx, `SELECT `+chargebackColumns+` FROM chargebacks
+		WHERE status = $1 ORDER BY created_at, id LIMIT $2 OFFSET $3`, status, limit, offset)
+}
+
+// UpdateChargeback stores the status and transactions of a chargeback that is still open.
+func (r *FundingPostgresRepository) UpdateChargeback(ctx context.Context, c *domain.Chargeback) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE chargebacks SET status = $2, debit_transaction_id = $3, reinstate_transaction_id = $4,
+			updated_at = NOW(), closed_at = CASE WHEN $2 IN ('won', 'lost') THEN NOW() END
+		WHERE id = $1 AND status IN ('received', 'disputed')
+		RETURNING updated_at, closed_at`,
+		c.ID, c.Status, c.DebitTransactionID, c.ReinstateTransactionID,
+	).Scan(&c.UpdatedAt, &c.ClosedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrChargebackClosed
+	}
+	return err
+}
+
+// AddChargebackEvent records a chargeback reaching a status.
+func (r *FundingPostgresRepository) AddChargebackEvent(ctx context.Context, e *domain.ChargebackEvent) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO chargeback_events (chargeback_id, status, note, actor_id, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NOW())
+		RETURNING id, created_at`,
+		e.ChargebackID, e.Status, e.Note, e.ActorID,
+	).Scan(&e.ID, &e.CreatedAt)
+}
+
+// SumChargebacks totals the amounts of a user's chargebacks that weren't won.
+func (r *FundingPostgresRepository) SumChargebacks(ctx context.Context, userID int) (float64, error) {
+	var total float64
+	err := r.db.QueryRow(ctx, `SELECT COALESCE(SUM(amount), 0) FROM chargebacks
+		WHERE user_id = $1 AND status <> 'won'`, userID).Scan(&total)
+	return total, err
+}
+
+// CreateGatewayRefund stores a gateway refund unless it was already received.
+func (r *FundingPostgresRepository) CreateGatewayRefund(ctx context.Context, g *domain.GatewayRefund) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO gateway_refunds (provider, charge_id, refund_id, user_id, credit_transaction_id, amount, recovered,
+			debit_transaction_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING id, created_at`,
+		g.Provider, g.ChargeID, g.RefundID, g.UserID, g.CreditTransactionID, g.Amount, g.Recovered, g.DebitTransactionID,
+	).Scan(&g.ID, &g.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return domain.ErrGatewayRefundExists
+	}
+	return err
+}
+
+// GetGatewayRefund fetches a gateway refund by its provider's ID.
+func (r *FundingPostgresRepository) GetGatewayRefund(ctx context.Context, provider, refundID string) (*domain.GatewayRefund, error) {
+	g := &domain.GatewayRefund{}
+	err := r.db.QueryRow(ctx, `
+		SELECT id, provider, charge_id, refund_id, user_id, credit_transaction_id, amount, recovered, debit_transaction_id, created_at
+		FROM gateway_refunds WHERE provider = $1 AND refund_id = $2`, provider, refundID,
+	).Scan(&g.ID, &g.Provider, &g.ChargeID, &g.RefundID, &g.UserID, &g.CreditTransactionID, &g.Amount, &g.Recovered,
+		&g.DebitTransactionID, &g.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// SumGatewayRefunds totals the amounts refunded of a provider's payment.
+func (r *FundingPostgresRepository) SumGatewayRefunds(ctx context.Context, provider, chargeID string) (float64, error) {
+	var total float64
+	err := r.db.QueryRow(ctx, `SELECT COALESCE(SUM(amount), 0) FROM gateway_refunds
+		WHERE provider = $1 AND charge_id = $2`, provider, chargeID).Scan(&total)
+	return total, err
+}
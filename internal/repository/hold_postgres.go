@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// holdColumns is the column list shared by every hold SELECT.
+const holdColumns = `id, user_id, amount, status, transaction_id, created_at, resolved_at`
+
+// HoldPostgresRepository implements domain.HoldRepository using PostgreSQL.
+type HoldPostgresRepository struct {
+	db DBTX
+}
+
+// NewHoldPostgresRepository creates a new HoldPostgresRepository.
+func NewHoldPostgresRepository(pool *pgxpool.Pool) *HoldPostgresRepository {
+	return &HoldPostgresRepository{db: pool}
+}
+
+// scanHold scans a row selected with holdColumns.
+func scanHold(row pgx.Row) (*domain.Hold, error) {
+	h := &domain.Hold{}
+	err := row.Scan(&h.ID, &h.UserID, &h.Amount, &h.Status, &h.TransactionID, &h.CreatedAt, &h.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Create inserts a new hold.
+func (r *HoldPostgresRepository) Create(ctx context.Context, h *domain.Hold) error {
+	query := `
+		INSERT INTO balance_holds (user_id, amount, status, transaction_id, created_at, resolved_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	return r.db.QueryRow(ctx, query,
+		h.UserID, h.Amount, h.Status, h.TransactionID, h.CreatedAt, h.ResolvedAt,
+	).Scan(&h.ID)
+}
+
+// GetByID fetches a hold by ID.
+func (r *HoldPostgresRepository) GetByID(ctx context.Context, id int) (*domain.Hold, error) {
+	query := `SELECT ` + holdColumns + ` FROM balance_holds WHERE id = $1`
+	h, err := scanHold(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return h, nil
+}
+
+// ListActiveByUser fetches a user's unresolved holds, oldest first.
+func (r *HoldPostgresRepository) ListActiveByUser(ctx context.Context, userID int) ([]*domain.Hold, error) {
+	query := `SELECT ` + holdColumns + ` FROM balance_holds WHERE user_id = $1 AND status = 'active' ORDER BY created_at`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []*domain.Hold
+	for rows.Next() {
+		h, err := scanHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
+
+// GetByTransactionID fetches the active hold bound to a pending transaction.
+func (r *HoldPostgresRepository) GetByTransactionID(ctx context.Context, transactionID int) (*domain.Hold, error) {
+	query := `SELECT ` + holdColumns + ` FROM balance_holds WHERE transaction_id = $1 AND status = 'active'`
+	h, err := scanHold(r.db.QueryRow(ctx, query, transactionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return h, nil
+}
+
+// Resolve stores the status and outcome of a hold that is still active.
+func (r *HoldPostgresRepository) Resolve(ctx context.Context, h *domain.Hold) error {
+	result, err := r.db.Exec(ctx,
+		`UPDATE balance_holds SET status = $1, transaction_id = $2, resolved_at = $3
+		 WHERE id = $4 AND status = 'active' AND (transaction_id IS NULL OR transaction_id = $2)`,
+		h.Status, h.TransactionID, h.ResolvedAt, h.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrHoldNotActive
+	}
+	return nil
+}
+
+// Bind stores the pending transaction that is to capture an active, unbound hold.
+func (r *HoldPostgresRepository) Bind(ctx context.Context, holdID, transactionID int) error {
+	result, err := r.db.Exec(ctx,
+		`UPDATE balance_holds SET transaction_id = $1 WHERE id = $2 AND status = 'active' AND transaction_id IS NULL`,
+		transactionID, holdID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrHoldNotActive
+	}
+	return nil
+}
+
+// Unbind clears the pending transaction an active hold is bound to.
+func (r *HoldPostgresRepository) Unbind(ctx context.Context, transactionID int) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE balance_holds SET transaction_id = NULL WHERE transaction_id = $1 AND status = 'active'`,
+		transactionID,
+	)
+	return err
+}
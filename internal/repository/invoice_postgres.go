@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// invoiceColumns is the column list shared by every invoice SELECT.
+const invoiceColumns = `id, number, user_id, period_start, period_end, currency, total, status, issued_at, paid_at`
+
+// uninvoicedFees selects the uninvoiced fees of transactions made before $1.
+const uninvoicedFees = `FROM transactions t
+	WHERE t.status IN ('completed', 'pending_approval') AND t.fee > 0 AND t.created_at < $1
+	  AND NOT EXISTS (SELECT 1 FROM invoice_lines l WHERE l.transaction_id = t.id)`
+
+// feePayer is the user who paid the fee of a transaction t.
+const feePayer = `CASE WHEN t.type = 'credit' THEN t.to_user_id ELSE t.from_user_id END`
+
+// InvoicePostgresRepository implements domain.InvoiceRepository using PostgreSQL.
+type InvoicePostgresRepository struct {
+	db DBTX
+}
+
+// NewInvoicePostgresRepository creates a new InvoicePostgresRepository.
+func NewInvoicePostgresRepository(pool *pgxpool.Pool) *InvoicePostgresRepository {
+	return &InvoicePostgresRepository{db: pool}
+}
+
+// scanInvoice scans a row selected with invoiceColumns.
+func scanInvoice(row pgx.Row) (*domain.Invoice, error) {
+	i := &domain.Invoice{}
+	err := row.Scan(&i.ID, &i.Number, &i.UserID, &i.PeriodStart, &i.PeriodEnd, &i.Currency, &i.Total, &i.Status, &i.IssuedAt, &i.PaidAt)
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// ListFeePayers fetches the users with uninvoiced fees made before end.
+func (r *InvoicePostgresRepository) ListFeePayers(ctx context.Context, end time.Time) ([]int, error) {
+	rows, err := r.db.Query(ctx, `SELECT DISTINCT `+feePayer+` AS user_id `+uninvoicedFees+`
+		AND `+feePayer+` IS NOT NULL ORDER BY user_id`, end.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
+// ListFeeLines fetches a user's uninvoiced fees made before end, oldest first.
+func (r *InvoicePostgresRepository) ListFeeLines(ctx context.Context, userID int, end time.Time) ([]*domain.InvoiceLine, error) {
+	rows, err := r.db.Query(ctx, `SELECT t.id, t.type, t.amount, t.status, t.fee_schedule_id, t.fee, t.created_at `+uninvoicedFees+`
+		AND `+feePayer+` = $2 ORDER BY t.created_at, t.id`, end.UTC(), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []*domain.InvoiceLine
+	for rows.Next() {
+		l := &domain.InvoiceLine{}
+		if err := rows.Scan(&l.TransactionID, &l.TransactionType, &l.TransactionAmount, &l.TransactionStatus, &l.FeeScheduleID, &l.Amount, &l.OccurredAt); err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+	return lines, rows.Err()
+}
+
+// ListOpen fetches the open invoices with their lines, oldest first.
+func (r *InvoicePostgresRepository) ListOpen(ctx context.Context) ([]*domain.Invoice, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+invoiceColumns+` FROM invoices WHERE status = 'open' ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	var invoices []*domain.Invoice
+	byID := map[int]*domain.Invoice{}
+	for rows.Next() {
+		i, err := scanInvoice(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		invoices = append(invoices, i)
+		byID[i.ID] = i
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || len(invoices) == 0 {
+		return invoices, err
+	}
+
+	ids := make([]int, 0, len(invoices))
+	for _, i := range invoices {
+		ids = append(ids, i.ID)
+	}
+	rows, err = r.db.Query(ctx, `
+		SELECT l.invoice_id, l.transaction_id, l.transaction_type, l.transaction_amount,
+		       COALESCE(t.status, a.status, ''), l.fee_schedule_id, COALESCE(t.fee, a.fee, 0), l.occurred_at
+		FROM invoice_lines l
+		LEFT JOIN transactions t ON t.id = l.transaction_id
+		LEFT JOIN transactions_archive a ON a.id = l.transaction_id
+		WHERE l.invoice_id = ANY($1)
+		ORDER BY l.occurred_at, l.transaction_id`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var invoiceID int
+		l := &domain.InvoiceLine{}
+		if err := rows.Scan(&invoiceID, &l.TransactionID, &l.TransactionType, &l.TransactionAmount, &l.TransactionStatus, &l.FeeScheduleID, &l.Amount, &l.OccurredAt); err != nil {
+			return nil, err
+		}
+		byID[invoiceID].Lines = append(byID[invoiceID].Lines, l)
+	}
+	return invoices, rows.Err()
+}
+
+// Settle updates an open invoice and its lines in one transaction.
+func (r *InvoicePostgresRepository) Settle(ctx context.Context, i *domain.Invoice) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE invoices SET status = $2, total = $3, paid_at = $4
+			WHERE id = $1 AND status = 'open'`,
+			i.ID, i.Status, i.Total, i.PaidAt)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrInvoiceNotOpen
+		}
+		kept := make([]int, 0, len(i.Lines))
+		for _, l := range i.Lines {
+			kept = append(kept, l.TransactionID)
+			if _, err := tx.Exec(ctx, `UPDATE invoice_lines SET amount = $3 WHERE invoice_id = $1 AND transaction_id = $2`,
+				i.ID, l.TransactionID, l.Amount); err != nil {
+				return err
+			}
+		}
+		_, err = tx.Exec(ctx, `DELETE FROM invoice_lines WHERE invoice_id = $1 AND transaction_id <> ALL($2)`, i.ID, kept)
+		return err
+	})
+}
+
+// Create inserts an invoice and its lines in one transaction.
+func (r *InvoicePostgresRepository) Create(ctx context.Context, i *domain.Invoice) error {
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO invoices (number, user_id, period_start, period_end, currency, total, status, issued_at, paid_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), $8)
+			RETURNING id, issued_at`,
+			i.Number, i.UserID, i.PeriodStart, i.PeriodEnd, i.Currency, i.Total, i.Status, i.PaidAt,
+		).Scan(&i.ID, &i.IssuedAt)
+		if err != nil {
+			return err
+		}
+		for _, l := range i.Lines {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO invoice_lines (invoice_id, transaction_id, transaction_type, transaction_amount, fee_schedule_id, amount, occurred_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+				i.ID, l.TransactionID, l.TransactionType, l.TransactionAmount, l.FeeScheduleID, l.Amount, l.OccurredAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return domain.ErrInvoiceExists
+	}
+	return err
+}
+
+// Get fetches an invoice by ID with its lines, oldest first.
+func (r *InvoicePostgresRepository) Get(ctx context.Context, id int) (*domain.Invoice, error) {
+	i, err := scanInvoice(r.db.QueryRow(ctx, `SELECT `+invoiceColumns+` FROM invoices WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT transaction_id, transaction_type, transaction_amount, fee_schedule_id, amount, occurred_at
+		FROM invoice_lines WHERE invoice_id = $1 ORDER BY occurred_at, transaction_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		l := &domain.InvoiceLine{}
+		if err := rows.Scan(&l.TransactionID, &l.TransactionType, &l.TransactionAmount, &l.FeeScheduleID, &l.Amount, &l.OccurredAt); err != nil {
+			return nil, err
+		}
+		i.Lines = append(i.Lines, l)
+	}
+	return i, rows.Err()
+}
+
+// ListByUser fetches a page of a user's invoices, newest period first.
+func (r *InvoicePostgresRepository) ListByUser(ctx context.Context, userID, limit, offset int) ([]*domain.Invoice, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+invoiceColumns+` FROM invoices
+		WHERE user_id = $1 ORDER BY period_start DESC, id DESC LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invoices []*domain.Invoice
+	for rows.Next() {
+		i, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, i)
+	}
+	return invoices, rows.Err()
+}
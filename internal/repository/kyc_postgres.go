@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// kycVerificationColumns is the column list shared by every verification SELECT.
+const kycVerificationColumns = `id, user_id, level, status, provider, COALESCE(provider_ref, ''), country,
+	COALESCE(document_type, ''), COALESCE(document_last4, ''), COALESCE(reason, ''), reviewed_by,
+	created_at, updated_at, decided_at`
+
+// kycTierRuleColumns selects a KYC level's limit rules as transaction_limit_rules columns.
+const kycTierRuleColumns = `'kyc-' || l.level || '-' || l.rule_type, u.id, l.rule_type, l.limit_amount,
+	'USD', l."window", TRUE, l.updated_at, l.updated_at`
+
+// KYCPostgresRepository implements domain.KYCRepository using PostgreSQL.
+type KYCPostgresRepository struct {
+	db DBTX
+}
+
+// NewKYCPostgresRepository creates a new KYCPostgresRepository.
+func NewKYCPostgresRepository(pool *pgxpool.Pool) *KYCPostgresRepository {
+	return &KYCPostgresRepository{db: pool}
+}
+
+// scanKYCVerification scans a row selected with kycVerificationColumns.
+func scanKYCVerification(row pgx.Row) (*domain.KYCVerification, error) {
+	v := &domain.KYCVerification{}
+	err := row.Scan(&v.ID, &v.UserID, &v.Level, &v.Status, &v.Provider, &v.ProviderRef, &v.Country,
+		&v.DocumentType, &v.DocumentLast4, &v.Reason, &v.ReviewedBy, &v.CreatedAt, &v.UpdatedAt, &v.DecidedAt)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// getKYCVerification runs a query selecting one verification, returning nil if there is none.
+func (r *KYCPostgresRepository) getKYCVerification(ctx context.Context, query string, args ...any) (*domain.KYCVerification, error) {
+	v, err := scanKYCVerification(r.db.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	return v, err
+}
+
+// Create stores a verification and sets the user's KYC status to its status, together.
+func (r *KYCPostgresRepository) Create(ctx context.Context, v *domain.KYCVerification) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO kyc_verifications (user_id, level, status, provider, provider_ref, country, document_type, document_last4, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, NULLIF($7, ''), NULLIF($8, ''), NOW(), NOW())
+			RETURNING id, created_at, updated_at`,
+			v.UserID, v.Level, v.Status, v.Provider, v.ProviderRef, v.Country, v.DocumentType, v.DocumentLast4,
+		).Scan(&v.ID, &v.CreatedAt, &v.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `UPDATE users SET kyc_status = $2 WHERE id = $1`, v.UserID, v.Status)
+		return err
+	})
+}
+
+// SetProviderRef stores the provider's reference of a verification.
+func (r *KYCPostgresRepository) SetProviderRef(ctx context.Context, id int, ref string) error {
+	_, err := r.db.Exec(ctx, `UPDATE kyc_verifications SET provider_ref = $2, updated_at = NOW() WHERE id = $1`, id, ref)
+	return err
+}
+
+// GetByID fetches a verification by ID.
+func (r *KYCPostgresRepository) GetByID(ctx context.Context, id int) (*domain.KYCVerification, error) {
+	return r.getKYCVerification(ctx, `SELECT `+kycVerificationColumns+` FROM kyc_verifications WHERE id = $1`, id)
+}
+
+// GetByProviderRef fetches a verification by its provider's reference.
+func (r *KYCPostgresRepository) GetByProviderRef(ctx context.Context, provider, ref string) (*domain.KYCVerification, error) {
+	return r.getKYCVerification(ctx, `SELECT `+kycVerificationColumns+` FROM kyc_verifications
+		WHERE provider = $1 AND provider_ref = $2`, provider, ref)
+}
+
+// GetLatest fetches a user's latest verification.
+func (r *KYCPostgresRepository) GetLatest(ctx context.Context, userID int) (*domain.KYCVerification, error) {
+	return r.getKYCVerification(ctx, `SELECT `+kycVerificationColumns+` FROM kyc_verifications
+		WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1`, userID)
+}
+
+// GetLevel fetches a user's KYC level and status; a missing user has none.
+func (r *KYCPostgresRepository) GetLevel(ctx context.Context, userID int) (int, string, error) {
+	var level int
+	var status string
+	err := r.db.QueryRow(ctx, `SELECT kyc_level, kyc_status FROM users WHERE id = $1`, userID).Scan(&level, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.KYCLevelNone, domain.KYCStatusNone, nil
+	}
+	return level, status, err
+}
+
+// ListByStatus fetches a page of the verifications with a status, oldest first.
+func (r *KYCPostgresRepository) ListByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.KYCVerification, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+kycVerificationColumns+` FROM kyc_verifications
+		WHERE status = $1 ORDER BY created_at, id LIMIT $2 OFFSET $3`, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var verifications []*domain.KYCVerification
+	for rows.Next() {
+		v, err := scanKYCVerification(rows)
+		if err != nil {
+			return nil, err
+		}
+		verifications = append(verifications, v)
+	}
+	return verifications, rows.Err()
+}
+
+// Decide stores the decision on an open verification and carries it over to the user.
+func (r *KYCPostgresRepository) Decide(ctx context.Context, v *domain.KYCVerification) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			UPDATE kyc_verifications SET status = $2, reason = NULLIF($3, ''), reviewed_by = $4, updated_at = NOW(),
+				decided_at = CASE WHEN $2 IN ('approved', 'rejected') THEN NOW() END
+			WHERE id = $1 AND status IN ('pending', 'review')
+			RETURNING updated_at, decided_at`,
+			v.ID, v.Status, v.Reason, v.ReviewedBy,
+		).Scan(&v.UpdatedAt, &v.DecidedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrKYCNotPending
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE users SET
+				kyc_level = CASE WHEN $3 = 'approved' THEN GREATEST(kyc_level, $2) ELSE kyc_level END,
+				kyc_status = CASE WHEN NOT EXISTS (
+					SELECT 1 FROM kyc_verifications WHERE user_id = $1 AND id > $4
+				) THEN $3 ELSE kyc_status END
+			WHERE id = $1`,
+			v.UserID, v.Level, v.Status, v.ID)
+		return err
+	})
+}
+
+// ListTierLimits fetches the limit rules of every KYC level.
+func (r *KYCPostgresRepository) ListTierLimits(ctx context.Context) (map[int][]domain.TransactionLimitRule, error) {
+	rows, err := r.db.Query(ctx, `SELECT l.level, `+kycTierRuleColumns+`
+		FROM kyc_tier_limits l CROSS JOIN (SELECT 0 AS id) u
+		ORDER BY l.level, l.rule_type`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	limits := make(map[int][]domain.TransactionLimitRule)
+	for rows.Next() {
+		var level int
+		rule, err := scanTierRule(rows, &level)
+		if err != nil {
+			return nil, err
+		}
+		limits[level] = append(limits[level], rule)
+	}
+	return limits, rows.Err()
+}
+
+// scanTierRule scans a row of a level followed by kycTierRuleColumns.
+func scanTierRule(row pgx.Row, level *int) (domain.TransactionLimitRule, error) {
+	var rule domain.TransactionLimitRule
+	var window *time.Duration
+	err := row.Scan(level, &rule.ID, &rule.UserID, &rule.RuleType, &rule.LimitAmount, &rule.Currency, &window,
+		&rule.Active, &rule.CreatedAt, &rule.UpdatedAt)
+	if window != nil {
+		rule.Window = *window
+	}
+	return rule, err
+}
+
+// SetTierLimits replaces the limit rules of a KYC level in one transaction.
+func (r *KYCPostgresRepository) SetTierLimits(ctx context.Context, level int, rules []domain.TransactionLimitRule, actorID int) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM kyc_tier_limits WHERE level = $1`, level); err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			var window *time.Duration
+			if rule.Window > 0 {
+				window = &rule.Window
+			}
+			_, err := tx.Exec(ctx, `
+				INSERT INTO kyc_tier_limits (level, rule_type, limit_amount, "window", updated_by, updated_at)
+				VALUES ($1, $2, $3, $4, $5, NOW())`,
+				level, rule.RuleType, rule.LimitAmount, window, actorID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
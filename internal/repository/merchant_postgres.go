@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// merchantColumns is the column list shared by every merchant SELECT.
+const merchantColumns = `user_id, business_name, settlement_source_id, payout_schedule, min_payout, created_at, updated_at`
+
+// payoutColumns is the column list shared by every payout SELECT.
+const payoutColumns = `id, merchant_id, settlement_source_id, period_start, period_end, transfer_count, amount, status,
+	COALESCE(batch_id, ''), transaction_id, COALESCE(error, ''), created_at, completed_at`
+
+// merchantTransfers selects the completed transfers a merchant received in [$2, $3).
+const merchantTransfers = `FROM transactions
+	WHERE to_user_id = $1 AND type = 'transfer' AND status = 'completed' AND created_at >= $2 AND created_at < $3`
+
+// MerchantPostgresRepository implements domain.MerchantRepository using PostgreSQL.
+type MerchantPostgresRepository struct {
+	db DBTX
+}
+
+// NewMerchantPostgresRepository creates a new MerchantPostgresRepository.
+func NewMerchantPostgresRepository(pool *pgxpool.Pool) *MerchantPostgresRepository {
+	return &MerchantPostgresRepository{db: pool}
+}
+
+// scanMerchant scans a row selected with merchantColumns.
+func scanMerchant(row pgx.Row) (*domain.Merchant, error) {
+	m := &domain.Merchant{}
+	err := row.Scan(&m.UserID, &m.BusinessName, &m.SettlementSourceID, &m.PayoutSchedule, &m.MinPayout, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// scanPayout scans a row selected with payoutColumns.
+func scanPayout(row pgx.Row) (*domain.Payout, error) {
+	p := &domain.Payout{}
+	err := row.Scan(&p.ID, &p.MerchantID, &p.SettlementSourceID, &p.PeriodStart, &p.PeriodEnd, &p.TransferCount, &p.Amount, &p.Status,
+		&p.BatchID, &p.TransactionID, &p.Error, &p.CreatedAt, &p.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SaveMerchant creates a merchant account or updates its details.
+func (r *MerchantPostgresRepository) SaveMerchant(ctx context.Context, m *domain.Merchant) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO merchants (user_id, business_name, settlement_source_id, payout_schedule, min_payout, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE SET business_name = EXCLUDED.business_name,
+			settlement_source_id = EXCLUDED.settlement_source_id, payout_schedule = EXCLUDED.payout_schedule,
+			min_payout = EXCLUDED.min_payout, updated_at = NOW()
+		RETURNING created_at, updated_at`,
+		m.UserID, m.BusinessName, m.SettlementSourceID, m.PayoutSchedule, m.MinPayout,
+	).Scan(&m.CreatedAt, &m.UpdatedAt)
+}
+
+// GetMerchant fetches a user's merchant account.
+func (r *MerchantPostgresRepository) GetMerchant(ctx context.Context, userID int) (*domain.Merchant, error) {
+	m, err := scanMerchant(r.db.QueryRow(ctx, `SELECT `+merchantColumns+` FROM merchants WHERE user_id = $1`, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	return m, err
+}
+
+// ListMerchants fetches every merchant account in user ID order.
+func (r *MerchantPostgresRepository) ListMerchants(ctx context.Context) ([]*domain.Merchant, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+merchantColumns+` FROM merchants ORDER BY user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var merchants []*domain.Merchant
+	for rows.Next() {
+		m, err := scanMerchant(rows)
+		if err != nil {
+			return nil, err
+		}
+		merchants = append(merchants, m)
+	}
+	return merchants, rows.Err()
+}
+
+// LastPeriodEnd fetches the end of a merchant's latest period with a payout that didn't fail.
+func (r *MerchantPostgresRepository) LastPeriodEnd(ctx context.Context, merchantID int) (*time.Time, error) {
+	var end *time.Time
+	err := r.db.QueryRow(ctx, `SELECT MAX(period_end) FROM payouts
+		WHERE merchant_id = $1 AND status <> 'failed'`, merchantID).Scan(&end)
+	return end, err
+}
+
+// SumTransfers counts and totals the completed transfers a merchant received in [start, end).
+func (r *MerchantPostgresRepository) SumTransfers(ctx context.Context, merchantID int, start, end time.Time) (int, float64, error) {
+	var count int
+	var total float64
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*), COALESCE(SUM(amount), 0) `+merchantTransfers,
+		merchantID, start, end).Scan(&count, &total)
+	return count, total, err
+}
+
+// ListTransfers fetches the completed transfers a merchant received in [start, end), oldest first.
+func (r *MerchantPostgresRepository) ListTransfers(ctx context.Context, merchantID int, start, end time.Time) ([]*domain.Transaction, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+transactionColumns+` `+merchantTransfers+` ORDER BY created_at, id`,
+		merchantID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*domain.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, tx)
+	}
+	return transactions, rows.Err()
+}
+
+// CreatePayout stores a payout unless its period already has one that didn't fail.
+func (r *MerchantPostgresRepository) CreatePayout(ctx context.Context, p *domain.Payout) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO payouts (merchant_id, settlement_source_id, period_start, period_end, transfer_count, amount, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING id, created_at`,
+		p.MerchantID, p.SettlementSourceID, p.PeriodStart, p.PeriodEnd, p.TransferCount, p.Amount, p.Status,
+	).Scan(&p.ID, &p.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return domain.ErrPayoutExists
+	}
+	return err
+}
+
+// GetPayout fetches a payout by ID.
+func (r *MerchantPostgresRepository) GetPayout(ctx context.Context, id int) (*domain.Payout, error) {
+	p, err := scanPayout(r.db.QueryRow(ctx, `SELECT `+payoutColumns+` FROM payouts WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	return p, err
+}
+
+// listPayouts runs a query selecting payoutColumns.
+func (r *MerchantPostgresRepository) listPayouts(ctx context.Context, query string, args ...any) ([]*domain.Payout, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []*domain.Payout
+	for rows.Next() {
+		p, err := scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, p)
+	}
+	return payouts, rows.Err()
+}
+
+// ListPayouts fetches a page of a merchant's payouts, newest period first.
+func (r *MerchantPostgresRepository) ListPayouts(ctx context.Context, merchantID, limit, offset int) ([]*domain.Payout, error) {
+	return r.listPayouts(ctx, `SELECT `+payoutColumns+` FROM payouts
+		WHERE merchant_id = $1 ORDER BY period_end DESC, id DESC LIMIT $2 OFFSET $3`, merchantID, limit, offset)
+}
+
+// ListProcessingPayouts fetches the payouts still processing, oldest first.
+func (r *MerchantPostgresRepository) ListProcessingPayouts(ctx context.Context) ([]*domain.Payout, error) {
+	return r.listPayouts(ctx, `SELECT `+payoutColumns+` FROM payouts WHERE status = 'processing' ORDER BY id`)
+}
+
+// UpdatePayout stores the status, batch and outcome of a processing payout.
+func (r *MerchantPostgresRepository) UpdatePayout(ctx context.Context, p *domain.Payout) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE payouts SET status = $2, batch_id = NULLIF($3, ''), transaction_id = $4, error = NULLIF($5, ''),
+			completed_at = CASE WHEN $2 IN ('paid', 'failed') THEN NOW() END
+		WHERE id = $1 AND status = 'processing'
+		RETURNING completed_at`,
+		p.ID, p.Status, p.BatchID, p.TransactionID, p.Error,
+	).Scan(&p.CompletedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrPayoutNotFound
+	}
+	return err
+}
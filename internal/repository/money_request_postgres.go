@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// moneyRequestColumns is the column list shared by every money request SELECT.
+const moneyRequestColumns = `id, requester_id, payer_id, amount, note, status, transaction_id, created_at, responded_at`
+
+// MoneyRequestPostgresRepository implements domain.MoneyRequestRepository using PostgreSQL.
+type MoneyRequestPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewMoneyRequestPostgresRepository creates a new MoneyRequestPostgresRepository.
+func NewMoneyRequestPostgresRepository(pool *pgxpool.Pool) *MoneyRequestPostgresRepository {
+	return &MoneyRequestPostgresRepository{pool: pool}
+}
+
+// scanMoneyRequest scans a row selected with moneyRequestColumns.
+func scanMoneyRequest(row pgx.Row) (*domain.MoneyRequest, error) {
+	m := &domain.MoneyRequest{}
+	err := row.Scan(&m.ID, &m.RequesterID, &m.PayerID, &m.Amount, &m.Note, &m.Status, &m.TransactionID, &m.CreatedAt, &m.RespondedAt)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Create inserts a new money request.
+func (r *MoneyRequestPostgresRepository) Create(ctx context.Context, m *domain.MoneyRequest) error {
+	query := `
+		INSERT INTO money_requests (requester_id, payer_id, amount, note, status, transaction_id, created_at, responded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+	return r.pool.QueryRow(ctx, query,
+		m.RequesterID, m.PayerID, m.Amount, m.Note, m.Status, m.TransactionID, m.CreatedAt, m.RespondedAt,
+	).Scan(&m.ID)
+}
+
+// GetByID fetches a money request by ID.
+func (r *MoneyRequestPostgresRepository) GetByID(ctx context.Context, id int) (*domain.MoneyRequest, error) {
+	query := `SELECT ` + moneyRequestColumns + ` FROM money_requests WHERE id = $1`
+	m, err := scanMoneyRequest(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// ListByPayer fetches the requests a user has been asked to pay, newest first.
+func (r *MoneyRequestPostgresRepository) ListByPayer(ctx context.Context, payerID int) ([]*domain.MoneyRequest, error) {
+	return r.list(ctx, `SELECT `+moneyRequestColumns+` FROM money_requests WHERE payer_id = $1 ORDER BY created_at DESC`, payerID)
+}
+
+// ListByRequester fetches the requests a user has sent, newest first.
+func (r *MoneyRequestPostgresRepository) ListByRequester(ctx context.Context, requesterID int) ([]*domain.MoneyRequest, error) {
+	return r.list(ctx, `SELECT `+moneyRequestColumns+` FROM money_requests WHERE requester_id = $1 ORDER BY created_at DESC`, requesterID)
+}
+
+// list runs a money request query and scans every row.
+func (r *MoneyRequestPostgresRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.MoneyRequest, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*domain.MoneyRequest
+	for rows.Next() {
+		m, err := scanMoneyRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// Update stores the status and outcome of a money request still in fromStatus.
+func (r *MoneyRequestPostgresRepository) Update(ctx context.Context, m *domain.MoneyRequest, fromStatus string) error {
+	result, err := r.pool.Exec(ctx,
+		`UPDATE money_requests SET status = $1, transaction_id = $2, responded_at = $3 WHERE id = $4 AND status = $5`,
+		m.Status, m.TransactionID, m.RespondedAt, m.ID, fromStatus,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrMoneyRequestChanged
+	}
+	return nil
+}
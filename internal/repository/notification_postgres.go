@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/crypto"
+)
+
+// NotificationPostgresRepository implements domain.NotificationRepository using PostgreSQL.
+type NotificationPostgresRepository struct {
+	pool *pgxpool.Pool
+	keys *crypto.Keyring
+}
+
+// NewNotificationPostgresRepository creates a new NotificationPostgresRepository.
+func NewNotificationPostgresRepository(pool *pgxpool.Pool, keys *crypto.Keyring) *NotificationPostgresRepository {
+	return &NotificationPostgresRepository{pool: pool, keys: keys}
+}
+
+// ListPreferences retrieves the preferences a user has set.
+func (r *NotificationPostgresRepository) ListPreferences(ctx context.Context, userID int) ([]domain.NotificationPreference, error) {
+	rows, err := r.pool.Query(ctx, `SELECT event, channel, enabled FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prefs []domain.NotificationPreference
+	for rows.Next() {
+		var p domain.NotificationPreference
+		if err := rows.Scan(&p.Event, &p.Channel, &p.Enabled); err != nil {
+			return nil, err
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs, rows.Err()
+}
+
+// SetPreferences upserts the preferences in one transaction.
+func (r *NotificationPostgresRepository) SetPreferences(ctx context.Context, userID int, prefs []domain.NotificationPreference) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, event, channel, enabled, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, event, channel) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = NOW()
+	`
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		for _, p := range prefs {
+			if _, err := tx.Exec(ctx, query, userID, p.Event, p.Channel, p.Enabled); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetContacts retrieves and decrypts the user's contact addresses.
+func (r *NotificationPostgresRepository) GetContacts(ctx context.Context, userID int) (map[string]string, error) {
+	rows, err := r.pool.Query(ctx, `SELECT channel, address FROM notification_contacts WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contacts := make(map[string]string)
+	for rows.Next() {
+		var channel, address string
+		if err := rows.Scan(&channel, &address); err != nil {
+			return nil, err
+		}
+		if contacts[channel], err = r.keys.Decrypt(address); err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s contact of user %d: %w", channel, userID, err)
+		}
+	}
+	return contacts, rows.Err()
+}
+
+// SetContact encrypts and stores a contact address, or deletes it if empty.
+func (r *NotificationPostgresRepository) SetContact(ctx context.Context, userID int, channel, address string) error {
+	if address == "" {
+		_, err := r.pool.Exec(ctx, `DELETE FROM notification_contacts WHERE user_id = $1 AND channel = $2`, userID, channel)
+		return err
+	}
+	encrypted, err := r.keys.Encrypt(address)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt %s contact: %w", channel, err)
+	}
+	query := `
+		INSERT INTO notification_contacts (user_id, channel, address, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id, channel) DO UPDATE SET address = EXCLUDED.address, updated_at = NOW()
+	`
+	_, err = r.pool.Exec(ctx, query, userID, channel, encrypted)
+	return err
+}
@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// organizationColumns is the column list shared by every organization SELECT.
+const organizationColumns = `o.id, o.name, o.account_user_id, o.created_by, o.created_at`
+
+// organizationMemberColumns is the column list shared by every member SELECT.
+const organizationMemberColumns = `organization_id, user_id, role, added_at`
+
+// organizationTransactionColumns is the column list shared by every sign-off SELECT.
+const organizationTransactionColumns = `id, organization_id, transaction_id, requested_by, reviewed_by, status, reason, created_at, reviewed_at`
+
+// OrganizationPostgresRepository implements domain.OrganizationRepository using PostgreSQL.
+type OrganizationPostgresRepository struct {
+	db DBTX
+}
+
+// NewOrganizationPostgresRepository creates a new OrganizationPostgresRepository.
+func NewOrganizationPostgresRepository(pool *pgxpool.Pool) *OrganizationPostgresRepository {
+	return &OrganizationPostgresRepository{db: pool}
+}
+
+// scanOrganization scans a row selected with organizationColumns.
+func scanOrganization(row pgx.Row) (*domain.Organization, error) {
+	org := &domain.Organization{}
+	if err := row.Scan(&org.ID, &org.Name, &org.AccountUserID, &org.CreatedBy, &org.CreatedAt); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// scanOrganizationMember scans a row selected with organizationMemberColumns.
+func scanOrganizationMember(row pgx.Row) (*domain.OrganizationMember, error) {
+	m := &domain.OrganizationMember{}
+	if err := row.Scan(&m.OrganizationID, &m.UserID, &m.Role, &m.AddedAt); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// scanOrganizationTransaction scans a row selected with organizationTransactionColumns.
+func scanOrganizationTransaction(row pgx.Row) (*domain.OrganizationTransaction, error) {
+	ot := &domain.OrganizationTransaction{}
+	err := row.Scan(&ot.ID, &ot.OrganizationID, &ot.TransactionID, &ot.RequestedBy, &ot.ReviewedBy,
+		&ot.Status, &ot.Reason, &ot.CreatedAt, &ot.ReviewedAt)
+	if err != nil {
+		return nil, err
+	}
+	return ot, nil
+}
+
+// Create inserts an organization and its first owner in one transaction.
+func (r *OrganizationPostgresRepository) Create(ctx context.Context, org *domain.Organization, owner *domain.OrganizationMember) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO organizations (name, account_user_id, created_by, created_at)
+			VALUES ($1, $2, $3, NOW())
+			RETURNING id, created_at`,
+			org.Name, org.AccountUserID, org.CreatedBy,
+		).Scan(&org.ID, &org.CreatedAt)
+		if err != nil {
+			return err
+		}
+		owner.OrganizationID = org.ID
+		return tx.QueryRow(ctx, `
+			INSERT INTO organization_members (organization_id, user_id, role, added_at)
+			VALUES ($1, $2, $3, NOW())
+			RETURNING added_at`,
+			owner.OrganizationID, owner.UserID, owner.Role,
+		).Scan(&owner.AddedAt)
+	})
+}
+
+// GetByID fetches an organization by ID.
+func (r *OrganizationPostgresRepository) GetByID(ctx context.Context, id int) (*domain.Organization, error) {
+	query := `SELECT ` + organizationColumns + ` FROM organizations o WHERE o.id = $1`
+	org, err := scanOrganization(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return org, nil
+}
+
+// ListByMember fetches the organizations a user is a member of, oldest first.
+func (r *OrganizationPostgresRepository) ListByMember(ctx context.Context, userID int) ([]*domain.Organization, error) {
+	query := `SELECT ` + organizationColumns + ` FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY o.created_at, o.id`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orgs []*domain.Organization
+	for rows.Next() {
+		org, err := scanOrganization(rows)
+		if err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, org)
+	}
+	return orgs, rows.Err()
+}
+
+// GetMember fetches a user's membership of an organization.
+func (r *OrganizationPostgresRepository) GetMember(ctx context.Context, organizationID, userID int) (*domain.OrganizationMember, error) {
+	query := `SELECT ` + organizationMemberColumns + ` FROM organization_members WHERE organization_id = $1 AND user_id = $2`
+	m, err := scanOrganizationMember(r.db.QueryRow(ctx, query, organizationID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not a member
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// ListMembers fetches the members of an organization, oldest first.
+func (r *OrganizationPostgresRepository) ListMembers(ctx context.Context, organizationID int) ([]*domain.OrganizationMember, error) {
+	query := `SELECT ` + organizationMemberColumns + ` FROM organization_members
+		WHERE organization_id = $1
+		ORDER BY added_at, user_id`
+	rows, err := r.db.Query(ctx, query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*domain.OrganizationMember
+	for rows.Next() {
+		m, err := scanOrganizationMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// AddMember inserts a membership unless the user already is a member.
+func (r *OrganizationPostgresRepository) AddMember(ctx context.Context, m *domain.OrganizationMember) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role, added_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (organization_id, user_id) DO NOTHING
+		RETURNING added_at`,
+		m.OrganizationID, m.UserID, m.Role,
+	).Scan(&m.AddedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrOrganizationMemberExists
+	}
+	return err
+}
+
+// UpdateMemberRole changes a member's role.
+func (r *OrganizationPostgresRepository) UpdateMemberRole(ctx context.Context, organizationID, userID int, role string) error {
+	result, err := r.db.Exec(ctx,
+		`UPDATE organization_members SET role = $3 WHERE organization_id = $1 AND user_id = $2`,
+		organizationID, userID, role)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrOrganizationMemberNotFound
+	}
+	return nil
+}
+
+// RemoveMember deletes a membership.
+func (r *OrganizationPostgresRepository) RemoveMember(ctx context.Context, organizationID, userID int) error {
+	result, err := r.db.Exec(ctx,
+		`DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`,
+		organizationID, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrOrganizationMemberNotFound
+	}
+	return nil
+}
+
+// CountOwners counts the owners of an organization.
+func (r *OrganizationPostgresRepository) CountOwners(ctx context.Context, organizationID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM organization_members WHERE organization_id = $1 AND role = 'owner'`,
+		organizationID).Scan(&count)
+	return count, err
+}
+
+// CreateTransaction inserts the sign-off record of an organization transaction.
+func (r *OrganizationPostgresRepository) CreateTransaction(ctx context.Context, ot *domain.OrganizationTransaction) error {
+	query := `
+		INSERT INTO organization_transactions (organization_id, transaction_id, requested_by, reviewed_by, status, reason, created_at, reviewed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+	return r.db.QueryRow(ctx, query,
+		ot.OrganizationID, ot.TransactionID, ot.RequestedBy, ot.ReviewedBy, ot.Status, ot.Reason, ot.CreatedAt, ot.ReviewedAt,
+	).Scan(&ot.ID)
+}
+
+// GetTransaction fetches the sign-off record of a transaction of an organization.
+func (r *OrganizationPostgresRepository) GetTransaction(ctx context.Context, organizationID, transactionID int) (*domain.OrganizationTransaction, error) {
+	query := `SELECT ` + organizationTransactionColumns + ` FROM organization_transactions
+		WHERE organization_id = $1 AND transaction_id = $2`
+	ot, err := scanOrganizationTransaction(r.db.QueryRow(ctx, query, organizationID, transactionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return ot, nil
+}
+
+// ListTransactions fetches an organization's sign-off records, newest first.
+func (r *OrganizationPostgresRepository) ListTransactions(ctx context.Context, organizationID int, status string, limit, offset int) ([]*domain.OrganizationTransaction, error) {
+	query := `SELECT ` + organizationTransactionColumns + ` FROM organization_transactions
+		WHERE organization_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4`
+	rows, err := r.db.Query(ctx, query, organizationID, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*domain.OrganizationTransaction
+	for rows.Next() {
+		ot, err := scanOrganizationTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, ot)
+	}
+	return records, rows.Err()
+}
+
+// UpdateTransaction stores the review of a sign-off record that is still pending.
+func (r *OrganizationPostgresRepository) UpdateTransaction(ctx context.Context, ot *domain.OrganizationTransaction) error {
+	result, err := r.db.Exec(ctx,
+		`UPDATE organization_transactions SET reviewed_by = $1, status = $2, reason = $3, reviewed_at = $4
+		WHERE id = $5 AND status = 'pending'`,
+		ot.ReviewedBy, ot.Status, ot.Reason, ot.ReviewedAt, ot.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrApprovalAlreadyReviewed
+	}
+	return nil
+}
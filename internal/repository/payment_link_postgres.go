@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// paymentLinkColumns is the column list shared by every payment link SELECT.
+const paymentLinkColumns = `id, creator_id, amount, description, status, expires_at, redeemed_by, transaction_id, created_at, redeemed_at`
+
+// PaymentLinkPostgresRepository implements domain.PaymentLinkRepository using PostgreSQL.
+type PaymentLinkPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPaymentLinkPostgresRepository creates a new PaymentLinkPostgresRepository.
+func NewPaymentLinkPostgresRepository(pool *pgxpool.Pool) *PaymentLinkPostgresRepository {
+	return &PaymentLinkPostgresRepository{pool: pool}
+}
+
+// scanPaymentLink scans a row selected with paymentLinkColumns.
+func scanPaymentLink(row pgx.Row) (*domain.PaymentLink, error) {
+	l := &domain.PaymentLink{}
+	err := row.Scan(&l.ID, &l.CreatorID, &l.Amount, &l.Description, &l.Status, &l.ExpiresAt,
+		&l.RedeemedBy, &l.TransactionID, &l.CreatedAt, &l.RedeemedAt)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Create inserts a new payment link.
+func (r *PaymentLinkPostgresRepository) Create(ctx context.Context, l *domain.PaymentLink) error {
+	query := `
+		INSERT INTO payment_links (creator_id, amount, description, status, expires_at, redeemed_by, transaction_id, created_at, redeemed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+	return r.pool.QueryRow(ctx, query,
+		l.CreatorID, l.Amount, l.Description, l.Status, l.ExpiresAt, l.RedeemedBy, l.TransactionID, l.CreatedAt, l.RedeemedAt,
+	).Scan(&l.ID)
+}
+
+// GetByID fetches a payment link by ID.
+func (r *PaymentLinkPostgresRepository) GetByID(ctx context.Context, id int) (*domain.PaymentLink, error) {
+	query := `SELECT ` + paymentLinkColumns + ` FROM payment_links WHERE id = $1`
+	l, err := scanPaymentLink(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+// ListByCreator fetches the links a user has created, newest first.
+func (r *PaymentLinkPostgresRepository) ListByCreator(ctx context.Context, creatorID int) ([]*domain.PaymentLink, error) {
+	query := `SELECT ` + paymentLinkColumns + ` FROM payment_links WHERE creator_id = $1 ORDER BY created_at DESC`
+	rows, err := r.pool.Query(ctx, query, creatorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []*domain.PaymentLink
+	for rows.Next() {
+		l, err := scanPaymentLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// Claim marks an active, unexpired link as redeemed in a single statement.
+func (r *PaymentLinkPostgresRepository) Claim(ctx context.Context, id, payerID int) (bool, error) {
+	result, err := r.pool.Exec(ctx,
+		`UPDATE payment_links SET status = 'redeemed', redeemed_by = $2, redeemed_at = NOW()
+		WHERE id = $1 AND status = 'active' AND expires_at > NOW()`,
+		id, payerID,
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// Update stores the status and outcome of a payment link.
+func (r *PaymentLinkPostgresRepository) Update(ctx context.Context, l *domain.PaymentLink) error {
+	result, err := r.pool.Exec(ctx,
+		`UPDATE payment_links SET status = $1, redeemed_by = $2, transaction_id = $3, redeemed_at = $4 WHERE id = $5`,
+		l.Status, l.RedeemedBy, l.TransactionID, l.RedeemedAt, l.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("payment link not found")
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxTracedStatementLength caps the SQL recorded on a span.
+const maxTracedStatementLength = 1000
+
+var (
+	leadingCommentPattern = regexp.MustCompile(`^(\s+|--[^\n]*\n|/\*.*?\*/)+`)
+	fromTablePattern      = regexp.MustCompile(`(?i)\bFROM\s+([a-z_][a-z0-9_.]*)`)
+	intoTablePattern      = regexp.MustCompile(`(?i)\bINTO\s+([a-z_][a-z0-9_.]*)`)
+	// Requiring SET skips row locking clauses such as FOR UPDATE SKIP LOCKED
+	updateTablePattern = regexp.MustCompile(`(?i)\bUPDATE\s+([a-z_][a-z0-9_.]*)(\s+(AS\s+)?[a-z_]+)?\s+SET\b`)
+)
+
+// queryInfo is the operation and main table of a SQL statement.
+type queryInfo struct {
+	operation string
+	table     string
+}
+
+// QueryTracer is a pgx.QueryTracer recording a span and metrics for every query.
+type QueryTracer struct {
+	tracer trace.Tracer
+	// Statements are constants, so parsing each distinct one once is enough
+	parsed sync.Map // string -> queryInfo
+}
+
+// NewQueryTracer creates a QueryTracer.
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{tracer: otel.Tracer("database")}
+}
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	info  queryInfo
+	start time.Time
+	span  trace.Span
+}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	info := t.info(data.SQL)
+
+	statement := data.SQL
+	if len(statement) > maxTracedStatementLength {
+		statement = statement[:maxTracedStatementLength]
+	}
+	ctx, span := t.tracer.Start(ctx, "db."+info.operation+" "+info.table,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", info.operation),
+			attribute.String("db.sql.table", info.table),
+			attribute.String("db.statement", statement),
+		),
+	)
+	return context.WithValue(ctx, queryTraceKey{}, &queryTrace{info: info, start: time.Now(), span: span})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	qt, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok {
+		return
+	}
+	defer qt.span.End()
+
+	status := "success"
+	// No rows is a normal outcome for lookups, not a failed operation
+	if data.Err != nil && data.Err != pgx.ErrNoRows {
+		status = "error"
+		qt.span.RecordError(data.Err)
+		qt.span.SetStatus(codes.Error, data.Err.Error())
+	} else {
+		qt.span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	}
+
+	metrics.DatabaseOperations.WithLabelValues(qt.info.operation, qt.info.table, status).Inc()
+	metrics.DatabaseOperationDuration.WithLabelValues(qt.info.operation, qt.info.table).Observe(time.Since(qt.start).Seconds())
+}
+
+func (t *QueryTracer) info(sql string) queryInfo {
+	if cached, ok := t.parsed.Load(sql); ok {
+		return cached.(queryInfo)
+	}
+	info := parseQuery(sql)
+	t.parsed.Store(sql, info)
+	return info
+}
+
+// parseQuery extracts the operation and main table of a statement.
+func parseQuery(sql string) queryInfo {
+	sql = leadingCommentPattern.ReplaceAllString(sql, "")
+	operation := "unknown"
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		operation = strings.ToLower(strings.TrimRight(fields[0], ";("))
+	}
+
+	if operation == "with" {
+		// A CTE's main statement decides the operation; default to a read
+		upper := strings.ToUpper(sql)
+		switch {
+		case strings.Contains(upper, "INSERT INTO"):
+			operation = "insert"
+		case strings.Contains(upper, "DELETE FROM"):
+			operation = "delete"
+		case updateTablePattern.MatchString(sql):
+			operation = "update"
+		default:
+			operation = "select"
+		}
+	}
+
+	var match []string
+	switch operation {
+	case "select", "delete":
+		match = fromTablePattern.FindStringSubmatch(sql)
+	case "insert":
+		match = intoTablePattern.FindStringSubmatch(sql)
+	case "update":
+		match = updateTablePattern.FindStringSubmatch(sql)
+	}
+	table := "none"
+	if match != nil {
+		table = strings.ToLower(match[1])
+	}
+	return queryInfo{operation: operation, table: table}
+}
@@ -0,0 +1,30 @@
+package repository
+
+import "testing"
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		sql       string
+		operation string
+		table     string
+	}{
+		{"SELECT id, name FROM users WHERE id = $1", "select", "users"},
+		{"\n\t\tSELECT " + transactionColumns + "\n\t\tFROM transactions WHERE id = $1", "select", "transactions"},
+		{"INSERT INTO balances (user_id, amount) VALUES ($1, $2)", "insert", "balances"},
+		{"UPDATE Users SET role = $1 WHERE id = $2", "update", "users"},
+		{"DELETE FROM tasks WHERE id = $1", "delete", "tasks"},
+		{"-- lock row\nSELECT amount FROM balances FOR UPDATE", "select", "balances"},
+		{"WITH due AS (SELECT id FROM tasks) UPDATE tasks SET status = 'running' FROM due", "update", "tasks"},
+		{"WITH due AS (SELECT id FROM tasks FOR UPDATE SKIP LOCKED) UPDATE tasks t SET status = 'running' FROM due", "update", "tasks"},
+		{"WITH totals AS (SELECT 1) INSERT INTO audit_logs (action) SELECT 'x'", "insert", "audit_logs"},
+		{"begin", "begin", "none"},
+		{"SELECT 1", "select", "none"},
+		{"", "unknown", "none"},
+	}
+	for _, tt := range tests {
+		got := parseQuery(tt.sql)
+		if got.operation != tt.operation || got.table != tt.table {
+			t.Errorf("parseQuery(%q) = %s/%s, want %s/%s", tt.sql, got.operation, got.table, tt.operation, tt.table)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// reconciliationRunColumns is the column list shared by every reconciliation run SELECT.
+const reconciliationRunColumns = `id, status, balances_checked, issues_found, error, started_at, finished_at`
+
+// reconciliationIssueColumns is the column list shared by every reconciliation issue SELECT.
+const reconciliationIssueColumns = `id, run_id, user_id, stored_amount, computed_amount, difference, detected_at`
+
+// ReconciliationPostgresRepository implements domain.ReconciliationRepository using PostgreSQL.
+type ReconciliationPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewReconciliationPostgresRepository creates a new ReconciliationPostgresRepository.
+func NewReconciliationPostgresRepository(pool *pgxpool.Pool) *ReconciliationPostgresRepository {
+	return &ReconciliationPostgresRepository{pool: pool}
+}
+
+// scanReconciliationRun scans a row selected with reconciliationRunColumns.
+func scanReconciliationRun(row pgx.Row) (*domain.ReconciliationRun, error) {
+	run := &domain.ReconciliationRun{}
+	err := row.Scan(&run.ID, &run.Status, &run.BalancesChecked, &run.IssuesFound, &run.Error, &run.StartedAt, &run.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// scanReconciliationIssue scans a row selected with reconciliationIssueColumns.
+func scanReconciliationIssue(row pgx.Row) (*domain.ReconciliationIssue, error) {
+	issue := &domain.ReconciliationIssue{}
+	err := row.Scan(&issue.ID, &issue.RunID, &issue.UserID, &issue.StoredAmount, &issue.ComputedAmount, &issue.Difference, &issue.DetectedAt)
+	if err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// CreateRun inserts a new reconciliation run.
+func (r *ReconciliationPostgresRepository) CreateRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	query := `
+		INSERT INTO reconciliation_runs (status, balances_checked, issues_found, error, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	return r.pool.QueryRow(ctx, query,
+		run.Status, run.BalancesChecked, run.IssuesFound, run.Error, run.StartedAt, run.FinishedAt,
+	).Scan(&run.ID)
+}
+
+// UpdateRun stores the outcome of a reconciliation run.
+func (r *ReconciliationPostgresRepository) UpdateRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	result, err := r.pool.Exec(ctx,
+		`UPDATE reconciliation_runs SET status = $1, balances_checked = $2, issues_found = $3, error = $4, finished_at = $5 WHERE id = $6`,
+		run.Status, run.BalancesChecked, run.IssuesFound, run.Error, run.FinishedAt, run.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("reconciliation run not found")
+	}
+	return nil
+}
+
+// GetLatestRun fetches the most recently started reconciliation run.
+func (r *ReconciliationPostgresRepository) GetLatestRun(ctx context.Context) (*domain.ReconciliationRun, error) {
+	query := `SELECT ` + reconciliationRunColumns + ` FROM reconciliation_runs ORDER BY started_at DESC, id DESC LIMIT 1`
+	run, err := scanReconciliationRun(r.pool.QueryRow(ctx, query))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // no run yet
+		}
+		return nil, err
+	}
+	return run, nil
+}
+
+// SnapshotBalances copies every row of balances into balance_snapshots for the run.
+func (r *ReconciliationPostgresRepository) SnapshotBalances(ctx context.Context, runID int) ([]*domain.BalanceSnapshot, error) {
+	query := `
+		INSERT INTO balance_snapshots (run_id, user_id, amount, held_amount, version, taken_at)
+		SELECT $1, user_id, amount, held_amount, version, NOW() FROM balances
+		RETURNING run_id, user_id, amount, held_amount, version, taken_at
+	`
+	rows, err := r.pool.Query(ctx, query, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []*domain.BalanceSnapshot
+	for rows.Next() {
+		s := &domain.BalanceSnapshot{}
+		if err := rows.Scan(&s.RunID, &s.UserID, &s.Amount, &s.HeldAmount, &s.Version, &s.TakenAt); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// CreateIssue inserts a discrepancy found by a reconciliation run.
+func (r *ReconciliationPostgresRepository) CreateIssue(ctx context.Context, issue *domain.ReconciliationIssue) error {
+	query := `
+		INSERT INTO reconciliation_issues (run_id, user_id, stored_amount, computed_amount, difference, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	return r.pool.QueryRow(ctx, query,
+		issue.RunID, issue.UserID, issue.StoredAmount, issue.ComputedAmount, issue.Difference, issue.DetectedAt,
+	).Scan(&issue.ID)
+}
+
+// ListIssuesByRun fetches the discrepancies found by a run, largest first.
+func (r *ReconciliationPostgresRepository) ListIssuesByRun(ctx context.Context, runID, limit, offset int) ([]*domain.ReconciliationIssue, error) {
+	query := `SELECT ` + reconciliationIssueColumns + ` FROM reconciliation_issues
+		WHERE run_id = $1
+		ORDER BY ABS(difference) DESC, id
+		LIMIT $2 OFFSET $3`
+	rows, err := r.pool.Query(ctx, query, runID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []*domain.ReconciliationIssue
+	for rows.Next() {
+		issue, err := scanReconciliationIssue(rows)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, issue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// referralColumns is the column list shared by every referral SELECT.
+const referralColumns = `rf.id, rf.referrer_id, rf.referee_id, u.username,
+	CASE WHEN rf.status = 'pending' AND rf.expires_at <= NOW() THEN 'expired' ELSE rf.status END,
+	rf.expires_at, rf.transaction_id, rf.referrer_reward, rf.referee_reward,
+	rf.referrer_transaction_id, rf.referee_transaction_id, rf.created_at, rf.rewarded_at`
+
+// ReferralPostgresRepository implements domain.ReferralRepository using PostgreSQL.
+type ReferralPostgresRepository struct {
+	db DBTX
+}
+
+// NewReferralPostgresRepository creates a new ReferralPostgresRepository.
+func NewReferralPostgresRepository(pool *pgxpool.Pool) *ReferralPostgresRepository {
+	return &ReferralPostgresRepository{db: pool}
+}
+
+// scanReferral scans a row selected with referralColumns.
+func scanReferral(row pgx.Row) (*domain.Referral, error) {
+	rf := &domain.Referral{}
+	err := row.Scan(&rf.ID, &rf.ReferrerID, &rf.RefereeID, &rf.RefereeUsername, &rf.Status,
+		&rf.ExpiresAt, &rf.TransactionID, &rf.ReferrerReward, &rf.RefereeReward,
+		&rf.ReferrerTransactionID, &rf.RefereeTransactionID, &rf.CreatedAt, &rf.RewardedAt)
+	if err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// GetCode fetches a user's referral code.
+func (r *ReferralPostgresRepository) GetCode(ctx context.Context, userID int) (string, error) {
+	var code *string
+	err := r.db.QueryRow(ctx, `SELECT referral_code FROM users WHERE id = $1`, userID).Scan(&code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil // not found
+	}
+	if err != nil || code == nil {
+		return "", err
+	}
+	return *code, nil
+}
+
+// SetCode assigns a referral code to a user unless they already have one.
+func (r *ReferralPostgresRepository) SetCode(ctx context.Context, userID int, code string) (string, error) {
+	var assigned string
+	err := r.db.QueryRow(ctx, `
+		UPDATE users SET referral_code = COALESCE(referral_code, $2)
+		WHERE id = $1
+		RETURNING referral_code`, userID, code,
+	).Scan(&assigned)
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return "", domain.ErrReferralUserNotFound
+	case errors.As(err, &pgErr) && pgErr.Code == "23505": // unique_violation
+		return "", domain.ErrReferralCodeTaken
+	}
+	return assigned, err
+}
+
+// GetReferrerByCode fetches the ID of the user whose referral code it is.
+func (r *ReferralPostgresRepository) GetReferrerByCode(ctx context.Context, code string) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx, `SELECT id FROM users WHERE referral_code = $1 AND erased_at IS NULL`, code).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil // not found
+	}
+	return id, err
+}
+
+// Create stores a pending referral.
+func (r *ReferralPostgresRepository) Create(ctx context.Context, rf *domain.Referral) error {
+	rf.Status = domain.ReferralPending
+	return r.db.QueryRow(ctx, `
+		INSERT INTO referrals (referrer_id, referee_id, status, expires_at, created_at)
+		VALUES ($1, $2, 'pending', $3, NOW())
+		RETURNING id, created_at`,
+		rf.ReferrerID, rf.RefereeID, rf.ExpiresAt,
+	).Scan(&rf.ID, &rf.CreatedAt)
+}
+
+// GetPendingByReferee fetches the pending, unexpired referral of a referee.
+func (r *ReferralPostgresRepository) GetPendingByReferee(ctx context.Context, refereeID int, at time.Time) (*domain.Referral, error) {
+	rf, err := scanReferral(r.db.QueryRow(ctx, `SELECT `+referralColumns+`
+		FROM referrals rf JOIN users u ON u.id = rf.referee_id
+		WHERE rf.referee_id = $1 AND rf.status = 'pending' AND (rf.expires_at IS NULL OR rf.expires_at > $2)`,
+		refereeID, at))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	return rf, err
+}
+
+// ListByReferrer fetches a user's referrals, newest first.
+func (r *ReferralPostgresRepository) ListByReferrer(ctx context.Context, referrerID int) ([]*domain.Referral, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+referralColumns+`
+		FROM referrals rf JOIN users u ON u.id = rf.referee_id
+		WHERE rf.referrer_id = $1
+		ORDER BY rf.created_at DESC, rf.id DESC`, referrerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var referrals []*domain.Referral
+	for rows.Next() {
+		rf, err := scanReferral(rows)
+		if err != nil {
+			return nil, err
+		}
+		referrals = append(referrals, rf)
+	}
+	return referrals, rows.Err()
+}
+
+// Summarize counts a user's referrals by status and sums the referrer rewards they were credited.
+func (r *ReferralPostgresRepository) Summarize(ctx context.Context, referrerID int) (*domain.ReferralSummary, error) {
+	summary := &domain.ReferralSummary{UserID: referrerID}
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE status = 'pending' AND (expires_at IS NULL OR expires_at > NOW())),
+		       COUNT(*) FILTER (WHERE status = 'rewarded'),
+		       COUNT(*) FILTER (WHERE status = 'pending' AND expires_at <= NOW()),
+		       COALESCE(SUM(referrer_reward) FILTER (WHERE status = 'rewarded'), 0)::float8
+		FROM referrals WHERE referrer_id = $1`, referrerID,
+	).Scan(&summary.Referred, &summary.Pending, &summary.Rewarded, &summary.Expired, &summary.Earnings)
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// MarkRewarded stores the rewards of a referral that is still pending and unexpired.
+func (r *ReferralPostgresRepository) MarkRewarded(ctx context.Context, rf *domain.Referral) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE referrals SET status = 'rewarded', transaction_id = $2,
+			referrer_reward = $3, referee_reward = $4,
+			referrer_transaction_id = $5, referee_transaction_id = $6, rewarded_at = NOW()
+		WHERE id = $1 AND status = 'pending' AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING rewarded_at`,
+		rf.ID, rf.TransactionID, rf.ReferrerReward, rf.RefereeReward, rf.ReferrerTransactionID, rf.RefereeTransactionID,
+	).Scan(&rf.RewardedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrReferralNotPending
+	}
+	if err != nil {
+		return err
+	}
+	rf.Status = domain.ReferralRewarded
+	return nil
+}
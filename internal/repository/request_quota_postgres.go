@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// RequestQuotaPostgresRepository implements domain.RequestQuotaRepository using PostgreSQL.
+type RequestQuotaPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRequestQuotaPostgresRepository creates a new RequestQuotaPostgresRepository.
+func NewRequestQuotaPostgresRepository(pool *pgxpool.Pool) *RequestQuotaPostgresRepository {
+	return &RequestQuotaPostgresRepository{pool: pool}
+}
+
+// List fetches every quota, role quotas first.
+func (r *RequestQuotaPostgresRepository) List(ctx context.Context) ([]*domain.RequestQuota, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, COALESCE(role, ''), user_id, period, request_limit, updated_by, updated_at
+		FROM request_quotas
+		ORDER BY user_id NULLS FIRST, role, period`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quotas []*domain.RequestQuota
+	for rows.Next() {
+		q := &domain.RequestQuota{}
+		if err := rows.Scan(&q.ID, &q.Role, &q.UserID, &q.Period, &q.Limit, &q.UpdatedBy, &q.UpdatedAt); err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, q)
+	}
+	return quotas, rows.Err()
+}
+
+// Set stores a quota, replacing the one of its role or user and period.
+func (r *RequestQuotaPostgresRepository) Set(ctx context.Context, q *domain.RequestQuota) error {
+	// Each subject has its own partial unique index to conflict on
+	conflict := `(user_id, period) WHERE user_id IS NOT NULL`
+	if q.Role != "" {
+		conflict = `(role, period) WHERE role IS NOT NULL`
+	}
+	return r.pool.QueryRow(ctx, `
+		INSERT INTO request_quotas (role, user_id, period, request_limit, updated_by, updated_at)
+		VALUES (NULLIF($1, ''), $2, $3, $4, $5, NOW())
+		ON CONFLICT `+conflict+` DO UPDATE
+		SET request_limit = EXCLUDED.request_limit, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+		RETURNING id, updated_at`,
+		q.Role, q.UserID, q.Period, q.Limit, q.UpdatedBy,
+	).Scan(&q.ID, &q.UpdatedAt)
+}
+
+// Delete removes the quota of a role or user and period.
+func (r *RequestQuotaPostgresRepository) Delete(ctx context.Context, role string, userID *int, period string) error {
+	result, err := r.pool.Exec(ctx, `
+		DELETE FROM request_quotas
+		WHERE role IS NOT DISTINCT FROM NULLIF($1, '') AND user_id IS NOT DISTINCT FROM $2 AND period = $3`,
+		role, userID, period)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrQuotaNotFound
+	}
+	return nil
+}
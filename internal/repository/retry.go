@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+	"github.com/rs/zerolog/log"
+)
+
+// TransientRetryPolicy bounds how often a unit of work is rerun after a transient database error.
+var TransientRetryPolicy = domain.RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 10 * time.Millisecond,
+	MaxBackoff:     250 * time.Millisecond,
+	Multiplier:     2,
+	Jitter:         0.5,
+}
+
+// IsTransient reports whether err is a database error worth rerunning the transaction for.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001", // serialization_failure
+			"40P01", // deadlock_detected
+			"57P01": // admin_shutdown, sent when the server or a pooler restarts
+			return true
+		}
+		// Class 08 is connection exceptions
+		return len(pgErr.Code) == 5 && pgErr.Code[:2] == "08"
+	}
+	return pgconn.SafeToRetry(err)
+}
+
+// WithRetry runs fn again under policy while it fails with a transient error.
+func WithRetry(ctx context.Context, policy domain.RetryPolicy, fn func() error) error {
+	for retry := 0; ; retry++ {
+		err := fn()
+		if !IsTransient(err) || !policy.CanRetry(retry) {
+			return err
+		}
+		metrics.DatabaseRetries.WithLabelValues(transientErrorCode(err)).Inc()
+		log.Debug().Err(err).Int("retry", retry+1).Msg("Retrying transaction after transient database error")
+
+		timer := time.NewTimer(policy.Backoff(retry))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// transientErrorCode is the metrics label for a transient error.
+func transientErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return "connection"
+}
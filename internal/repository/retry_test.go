@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("boom"), false},
+		{&pgconn.PgError{Code: "40001"}, true},
+		{fmt.Errorf("update balance: %w", &pgconn.PgError{Code: "40P01"}), true},
+		{&pgconn.PgError{Code: "08006"}, true},
+		{&pgconn.PgError{Code: "23505"}, false},
+	}
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.want {
+			t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	policy := domain.RetryPolicy{MaxAttempts: 3}
+	serialization := &pgconn.PgError{Code: "40001"}
+
+	calls := 0
+	err := WithRetry(context.Background(), policy, func() error {
+		calls++
+		if calls < 3 {
+			return serialization
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("got err=%v after %d calls, want success after 3", err, calls)
+	}
+
+	calls = 0
+	err = WithRetry(context.Background(), policy, func() error {
+		calls++
+		return serialization
+	})
+	if !errors.Is(err, serialization) || calls != 3 {
+		t.Fatalf("got err=%v after %d calls, want serialization failure after 3", err, calls)
+	}
+
+	calls = 0
+	permanent := errors.New("insufficient funds")
+	err = WithRetry(context.Background(), policy, func() error {
+		calls++
+		return permanent
+	})
+	if err != permanent || calls != 1 {
+		t.Fatalf("got err=%v after %d calls, want permanent error after 1", err, calls)
+	}
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// savingsGoalColumns is the column list shared by every savings goal SELECT.
+const savingsGoalColumns = `id, user_id, name, target_amount, current_amount, deadline, status,
+		       scheduled_transaction_id, created_at, updated_at`
+
+// SavingsGoalPostgresRepository implements domain.SavingsGoalRepository using PostgreSQL.
+type SavingsGoalPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSavingsGoalPostgresRepository creates a new SavingsGoalPostgresRepository.
+func NewSavingsGoalPostgresRepository(pool *pgxpool.Pool) *SavingsGoalPostgresRepository {
+	return &SavingsGoalPostgresRepository{pool: pool}
+}
+
+// scanSavingsGoal scans a row selected with savingsGoalColumns.
+func scanSavingsGoal(row pgx.Row) (*domain.SavingsGoal, error) {
+	goal := &domain.SavingsGoal{}
+	err := row.Scan(
+		&goal.ID, &goal.UserID, &goal.Name, &goal.TargetAmount, &goal.CurrentAmount, &goal.Deadline, &goal.Status,
+		&goal.ScheduledTransactionID, &goal.CreatedAt, &goal.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// Create inserts a new savings goal into the database.
+func (r *SavingsGoalPostgresRepository) Create(ctx context.Context, goal *domain.SavingsGoal) error {
+	query := `
+		INSERT INTO savings_goals (
+			user_id, name, target_amount, current_amount, deadline, status, scheduled_transaction_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+	return r.pool.QueryRow(ctx, query,
+		goal.UserID, goal.Name, goal.TargetAmount, goal.CurrentAmount, goal.Deadline, goal.Status, goal.ScheduledTransactionID,
+	).Scan(&goal.ID, &goal.CreatedAt, &goal.UpdatedAt)
+}
+
+// GetByID fetches a savings goal by ID.
+func (r *SavingsGoalPostgresRepository) GetByID(ctx context.Context, id int) (*domain.SavingsGoal, error) {
+	query := `SELECT ` + savingsGoalColumns + ` FROM savings_goals WHERE id = $1`
+	goal, err := scanSavingsGoal(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return goal, nil
+}
+
+// ListByUser fetches all savings goals for a user.
+func (r *SavingsGoalPostgresRepository) ListByUser(ctx context.Context, userID int) ([]*domain.SavingsGoal, error) {
+	query := `
+		SELECT ` + savingsGoalColumns + `
+		FROM savings_goals
+		WHERE user_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var goals []*domain.SavingsGoal
+	for rows.Next() {
+		goal, err := scanSavingsGoal(rows)
+		if err != nil {
+			return nil, err
+		}
+		goals = append(goals, goal)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return goals, nil
+}
+
+// Update updates a savings goal.
+func (r *SavingsGoalPostgresRepository) Update(ctx context.Context, goal *domain.SavingsGoal) error {
+	query := `
+		UPDATE savings_goals SET
+			name = $1, target_amount = $2, current_amount = $3, deadline = $4, status = $5,
+			scheduled_transaction_id = $6, updated_at = NOW()
+		WHERE id = $7
+	`
+	result, err := r.pool.Exec(ctx, query,
+		goal.Name, goal.TargetAmount, goal.CurrentAmount, goal.Deadline, goal.Status, goal.ScheduledTransactionID, goal.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("savings goal not found")
+	}
+	return nil
+}
+
+// AddContribution atomically increments the goal's current amount, completing it once the target is reached.
+func (r *SavingsGoalPostgresRepository) AddContribution(ctx context.Context, goalID int, amount float64) (*domain.SavingsGoal, error) {
+	query := `
+		UPDATE savings_goals SET
+			current_amount = current_amount + $1,
+			status = CASE WHEN current_amount + $1 >= target_amount THEN 'completed' ELSE status END,
+			updated_at = NOW()
+		WHERE id = $2 AND status = 'active'
+		RETURNING ` + savingsGoalColumns
+	goal, err := scanSavingsGoal(r.pool.QueryRow(ctx, query, amount, goalID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("active savings goal not found")
+		}
+		return nil, err
+	}
+	return goal, nil
+}
@@ -10,6 +10,11 @@ import (
 	"github.com/melihgurlek/backend-path/internal/domain"
 )
 
+// scheduledTransactionColumns is the column list shared by every scheduled transaction SELECT.
+const scheduledTransactionColumns = `id, user_id, to_user_id, amount, type, status, schedule_at,
+		       recurring, recurrence, COALESCE(cron_expression, ''), next_run_at, max_runs, runs_count,
+		       retry_count, next_retry_at, description, goal_id, COALESCE(trace_parent, ''), created_at, updated_at`
+
 // ScheduledTransactionPostgresRepository implements domain.ScheduledTransactionRepository using PostgreSQL.
 type ScheduledTransactionPostgresRepository struct {
 	pool *pgxpool.Pool
@@ -20,53 +25,23 @@ func NewScheduledTransactionPostgresRepository(pool *pgxpool.Pool) *ScheduledTra
 	return &ScheduledTransactionPostgresRepository{pool: pool}
 }
 
-// Create inserts a new scheduled transaction into the database.
-func (r *ScheduledTransactionPostgresRepository) Create(st *domain.ScheduledTransaction) error {
-	query := `
-		INSERT INTO scheduled_transactions (
-			user_id, to_user_id, amount, type, status, schedule_at, 
-			recurring, recurrence, next_run_at, max_runs, runs_count, description, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW()) 
-		RETURNING id, created_at, updated_at
-	`
-	return r.pool.QueryRow(context.Background(), query,
-		st.UserID, st.ToUserID, st.Amount, st.Type, st.Status, st.ScheduleAt,
-		st.Recurring, st.Recurrence, st.NextRunAt, st.MaxRuns, st.RunsCount, st.Description,
-	).Scan(&st.ID, &st.CreatedAt, &st.UpdatedAt)
-}
-
-// GetByID fetches a scheduled transaction by ID.
-func (r *ScheduledTransactionPostgresRepository) GetByID(id int) (*domain.ScheduledTransaction, error) {
+// scanScheduledTransaction scans a row selected with scheduledTransactionColumns.
+func scanScheduledTransaction(row pgx.Row) (*domain.ScheduledTransaction, error) {
 	st := &domain.ScheduledTransaction{}
-	query := `
-		SELECT id, user_id, to_user_id, amount, type, status, schedule_at, 
-		       recurring, recurrence, next_run_at, max_runs, runs_count, description, created_at, updated_at
-		FROM scheduled_transactions WHERE id = $1
-	`
-	err := r.pool.QueryRow(context.Background(), query, id).Scan(
+	err := row.Scan(
 		&st.ID, &st.UserID, &st.ToUserID, &st.Amount, &st.Type, &st.Status, &st.ScheduleAt,
-		&st.Recurring, &st.Recurrence, &st.NextRunAt, &st.MaxRuns, &st.RunsCount, &st.Description,
-		&st.CreatedAt, &st.UpdatedAt,
+		&st.Recurring, &st.Recurrence, &st.CronExpression, &st.NextRunAt, &st.MaxRuns, &st.RunsCount,
+		&st.RetryCount, &st.NextRetryAt, &st.Description, &st.GoalID, &st.TraceParent, &st.CreatedAt, &st.UpdatedAt,
 	)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil // not found
-		}
 		return nil, err
 	}
 	return st, nil
 }
 
-// ListByUser fetches all scheduled transactions for a user.
-func (r *ScheduledTransactionPostgresRepository) ListByUser(userID int) ([]*domain.ScheduledTransaction, error) {
-	query := `
-		SELECT id, user_id, to_user_id, amount, type, status, schedule_at, 
-		       recurring, recurrence, next_run_at, max_runs, runs_count, description, created_at, updated_at
-		FROM scheduled_transactions 
-		WHERE user_id = $1 
-		ORDER BY schedule_at ASC
-	`
-	rows, err := r.pool.Query(context.Background(), query, userID)
+// queryScheduledTransactions runs a query selecting scheduledTransactionColumns and collects the rows.
+func (r *ScheduledTransactionPostgresRepository) queryScheduledTransactions(ctx context.Context, query string, args ...interface{}) ([]*domain.ScheduledTransaction, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -74,12 +49,7 @@ func (r *ScheduledTransactionPostgresRepository) ListByUser(userID int) ([]*doma
 
 	var transactions []*domain.ScheduledTransaction
 	for rows.Next() {
-		st := &domain.ScheduledTransaction{}
-		err := rows.Scan(
-			&st.ID, &st.UserID, &st.ToUserID, &st.Amount, &st.Type, &st.Status, &st.ScheduleAt,
-			&st.Recurring, &st.Recurrence, &st.NextRunAt, &st.MaxRuns, &st.RunsCount, &st.Description,
-			&st.CreatedAt, &st.UpdatedAt,
-		)
+		st, err := scanScheduledTransaction(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -93,55 +63,76 @@ func (r *ScheduledTransactionPostgresRepository) ListByUser(userID int) ([]*doma
 	return transactions, nil
 }
 
-// ListPending fetches all pending scheduled transactions that should be executed
-func (r *ScheduledTransactionPostgresRepository) ListPending() ([]*domain.ScheduledTransaction, error) {
+// Create inserts a new scheduled transaction into the database.
+func (r *ScheduledTransactionPostgresRepository) Create(ctx context.Context, st *domain.ScheduledTransaction) error {
 	query := `
-		SELECT id, user_id, to_user_id, amount, type, status, schedule_at, 
-		       recurring, recurrence, next_run_at, max_runs, runs_count, description, created_at, updated_at
-		FROM scheduled_transactions 
-		WHERE status = 'pending' AND (
-			(recurring = FALSE AND schedule_at <= NOW()) OR
-			(recurring = TRUE AND next_run_at <= NOW())
-		)
-		ORDER BY schedule_at ASC
+		INSERT INTO scheduled_transactions (
+			user_id, to_user_id, amount, type, status, schedule_at,
+			recurring, recurrence, cron_expression, next_run_at, max_runs, runs_count,
+			retry_count, next_retry_at, description, goal_id, trace_parent, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11, $12, $13, $14, $15, $16, NULLIF($17, ''), NOW(), NOW())
+		RETURNING id, created_at, updated_at
 	`
+	return r.pool.QueryRow(ctx, query,
+		st.UserID, st.ToUserID, st.Amount, st.Type, st.Status, st.ScheduleAt,
+		st.Recurring, st.Recurrence, st.CronExpression, st.NextRunAt, st.MaxRuns, st.RunsCount,
+		st.RetryCount, st.NextRetryAt, st.Description, st.GoalID, st.TraceParent,
+	).Scan(&st.ID, &st.CreatedAt, &st.UpdatedAt)
+}
 
-	rows, err := r.pool.Query(context.Background(), query)
+// GetByID fetches a scheduled transaction by ID.
+func (r *ScheduledTransactionPostgresRepository) GetByID(ctx context.Context, id int) (*domain.ScheduledTransaction, error) {
+	query := `SELECT ` + scheduledTransactionColumns + ` FROM scheduled_transactions WHERE id = $1`
+	st, err := scanScheduledTransaction(r.pool.QueryRow(ctx, query, id))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
 		return nil, err
 	}
-	defer rows.Close()
+	return st, nil
+}
 
-	var transactions []*domain.ScheduledTransaction
-	for rows.Next() {
-		st := &domain.ScheduledTransaction{}
-		err := rows.Scan(
-			&st.ID, &st.UserID, &st.ToUserID, &st.Amount, &st.Type, &st.Status, &st.ScheduleAt,
-			&st.Recurring, &st.Recurrence, &st.NextRunAt, &st.MaxRuns, &st.RunsCount, &st.Description,
-			&st.CreatedAt, &st.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		transactions = append(transactions, st)
-	}
+// ListByUser fetches all scheduled transactions for a user.
+func (r *ScheduledTransactionPostgresRepository) ListByUser(ctx context.Context, userID int) ([]*domain.ScheduledTransaction, error) {
+	query := `
+		SELECT ` + scheduledTransactionColumns + `
+		FROM scheduled_transactions
+		WHERE user_id = $1
+		ORDER BY schedule_at ASC
+	`
+	return r.queryScheduledTransactions(ctx, query, userID)
+}
 
-	return transactions, nil
+// ListPending fetches all pending scheduled transactions that should be executed
+func (r *ScheduledTransactionPostgresRepository) ListPending(ctx context.Context) ([]*domain.ScheduledTransaction, error) {
+	query := `
+		SELECT ` + scheduledTransactionColumns + `
+		FROM scheduled_transactions
+		WHERE status = 'pending' AND (
+			(next_retry_at IS NOT NULL AND next_retry_at <= NOW()) OR
+			(next_retry_at IS NULL AND recurring = FALSE AND schedule_at <= NOW()) OR
+			(next_retry_at IS NULL AND recurring = TRUE AND next_run_at <= NOW())
+		)
+		ORDER BY schedule_at ASC
+	`
+	return r.queryScheduledTransactions(ctx, query)
 }
 
 // Update updates a scheduled transaction
-func (r *ScheduledTransactionPostgresRepository) Update(st *domain.ScheduledTransaction) error {
+func (r *ScheduledTransactionPostgresRepository) Update(ctx context.Context, st *domain.ScheduledTransaction) error {
 	query := `
 		UPDATE scheduled_transactions SET
 			user_id = $1, to_user_id = $2, amount = $3, type = $4, status = $5, schedule_at = $6,
-			recurring = $7, recurrence = $8, next_run_at = $9, max_runs = $10, runs_count = $11, 
-			description = $12, updated_at = NOW()
-		WHERE id = $13
+			recurring = $7, recurrence = $8, cron_expression = NULLIF($9, ''), next_run_at = $10, max_runs = $11,
+			runs_count = $12, retry_count = $13, next_retry_at = $14, description = $15, goal_id = $16, updated_at = NOW()
+		WHERE id = $17
 	`
 
-	result, err := r.pool.Exec(context.Background(), query,
+	result, err := r.pool.Exec(ctx, query,
 		st.UserID, st.ToUserID, st.Amount, st.Type, st.Status, st.ScheduleAt,
-		st.Recurring, st.Recurrence, st.NextRunAt, st.MaxRuns, st.RunsCount, st.Description, st.ID,
+		st.Recurring, st.Recurrence, st.CronExpression, st.NextRunAt, st.MaxRuns, st.RunsCount,
+		st.RetryCount, st.NextRetryAt, st.Description, st.GoalID, st.ID,
 	)
 
 	if err != nil {
@@ -156,9 +147,9 @@ func (r *ScheduledTransactionPostgresRepository) Update(st *domain.ScheduledTran
 }
 
 // Delete deletes a scheduled transaction
-func (r *ScheduledTransactionPostgresRepository) Delete(id int) error {
+func (r *ScheduledTransactionPostgresRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM scheduled_transactions WHERE id = $1`
-	result, err := r.pool.Exec(context.Background(), query, id)
+	result, err := r.pool.Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -169,9 +160,9 @@ func (r *ScheduledTransactionPostgresRepository) Delete(id int) error {
 }
 
 // GetStats returns statistics about scheduled transactions
-func (r *ScheduledTransactionPostgresRepository) GetScheduledTransactionStats(userID int) (*domain.ScheduledTransactionStats, error) {
+func (r *ScheduledTransactionPostgresRepository) GetScheduledTransactionStats(ctx context.Context, userID int) (*domain.ScheduledTransactionStats, error) {
 	query := `
-		SELECT 
+		SELECT
 			COUNT(*) as total_scheduled,
 			COUNT(CASE WHEN status = 'pending' THEN 1 END) as pending_count,
 			COUNT(CASE WHEN status = 'completed' THEN 1 END) as completed_count,
@@ -179,12 +170,12 @@ func (r *ScheduledTransactionPostgresRepository) GetScheduledTransactionStats(us
 			COUNT(CASE WHEN status = 'cancelled' THEN 1 END) as cancelled_count,
 			COUNT(CASE WHEN recurring = TRUE THEN 1 END) as recurring_count,
 			COUNT(CASE WHEN recurring = FALSE THEN 1 END) as one_time_count
-		FROM scheduled_transactions 
+		FROM scheduled_transactions
 		WHERE user_id = $1
 	`
 
 	stats := &domain.ScheduledTransactionStats{}
-	err := r.pool.QueryRow(context.Background(), query, userID).Scan(
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
 		&stats.TotalScheduled, &stats.PendingCount, &stats.CompletedCount,
 		&stats.FailedCount, &stats.CancelledCount, &stats.RecurringCount, &stats.OneTimeCount,
 	)
@@ -196,75 +187,23 @@ func (r *ScheduledTransactionPostgresRepository) GetScheduledTransactionStats(us
 }
 
 // ListByStatus fetches scheduled transactions by status
-func (r *ScheduledTransactionPostgresRepository) ListByStatus(status string) ([]*domain.ScheduledTransaction, error) {
+func (r *ScheduledTransactionPostgresRepository) ListByStatus(ctx context.Context, status string) ([]*domain.ScheduledTransaction, error) {
 	query := `
-		SELECT id, user_id, to_user_id, amount, type, status, schedule_at, 
-		       recurring, recurrence, next_run_at, max_runs, runs_count, description, created_at, updated_at
-		FROM scheduled_transactions 
-		WHERE status = $1 
+		SELECT ` + scheduledTransactionColumns + `
+		FROM scheduled_transactions
+		WHERE status = $1
 		ORDER BY schedule_at ASC
 	`
-
-	rows, err := r.pool.Query(context.Background(), query, status)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var transactions []*domain.ScheduledTransaction
-	for rows.Next() {
-		st := &domain.ScheduledTransaction{}
-		err := rows.Scan(
-			&st.ID, &st.UserID, &st.ToUserID, &st.Amount, &st.Type, &st.Status, &st.ScheduleAt,
-			&st.Recurring, &st.Recurrence, &st.NextRunAt, &st.MaxRuns, &st.RunsCount, &st.Description,
-			&st.CreatedAt, &st.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		transactions = append(transactions, st)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return transactions, nil
+	return r.queryScheduledTransactions(ctx, query, status)
 }
 
 // ListByTimeRange fetches scheduled transactions within a time range
-func (r *ScheduledTransactionPostgresRepository) ListByTimeRange(from, to time.Time) ([]*domain.ScheduledTransaction, error) {
+func (r *ScheduledTransactionPostgresRepository) ListByTimeRange(ctx context.Context, from, to time.Time) ([]*domain.ScheduledTransaction, error) {
 	query := `
-		SELECT id, user_id, to_user_id, amount, type, status, schedule_at, 
-		       recurring, recurrence, next_run_at, max_runs, runs_count, description, created_at, updated_at
-		FROM scheduled_transactions 
+		SELECT ` + scheduledTransactionColumns + `
+		FROM scheduled_transactions
 		WHERE schedule_at >= $1 AND schedule_at <= $2
 		ORDER BY schedule_at ASC
 	`
-
-	rows, err := r.pool.Query(context.Background(), query, from, to)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var transactions []*domain.ScheduledTransaction
-	for rows.Next() {
-		st := &domain.ScheduledTransaction{}
-		err := rows.Scan(
-			&st.ID, &st.UserID, &st.ToUserID, &st.Amount, &st.Type, &st.Status, &st.ScheduleAt,
-			&st.Recurring, &st.Recurrence, &st.NextRunAt, &st.MaxRuns, &st.RunsCount, &st.Description,
-			&st.CreatedAt, &st.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		transactions = append(transactions, st)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return transactions, nil
+	return r.queryScheduledTransactions(ctx, query, from, to)
 }
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// seedChunkSize is how many transactions a single INSERT writes
+const seedChunkSize = 5000
+
+// SeedPostgresRepository implements domain.SeedRepository using PostgreSQL.
+type SeedPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSeedPostgresRepository creates a new SeedPostgresRepository.
+func NewSeedPostgresRepository(pool *pgxpool.Pool) *SeedPostgresRepository {
+	return &SeedPostgresRepository{pool: pool}
+}
+
+// InsertTransactions inserts transactions in chunks, each a single statement over unnested arrays.
+func (r *SeedPostgresRepository) InsertTransactions(ctx context.Context, txs []*domain.Transaction) error {
+	query := `INSERT INTO transactions (from_user_id, to_user_id, amount, type, status, created_at)
+		SELECT * FROM unnest($1::int[], $2::int[], $3::numeric[], $4::text[], $5::text[], $6::timestamp[])`
+	for start := 0; start < len(txs); start += seedChunkSize {
+		chunk := txs[start:min(start+seedChunkSize, len(txs))]
+		fromIDs := make([]*int, len(chunk))
+		toIDs := make([]*int, len(chunk))
+		amounts := make([]float64, len(chunk))
+		types := make([]string, len(chunk))
+		statuses := make([]string, len(chunk))
+		createdAts := make([]time.Time, len(chunk))
+		for i, tx := range chunk {
+			fromIDs[i], toIDs[i] = tx.FromUserID, tx.ToUserID
+			amounts[i], types[i], statuses[i] = tx.Amount, tx.Type, tx.Status
+			createdAts[i] = tx.CreatedAt.UTC()
+		}
+		if _, err := r.pool.Exec(ctx, query, fromIDs, toIDs, amounts, types, statuses, createdAts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetBalance creates or overwrites a user's balance, releasing any holds.
+func (r *SeedPostgresRepository) SetBalance(ctx context.Context, userID int, amount float64) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO balances (user_id, amount, held_amount, version, last_updated_at)
+		VALUES ($1, $2, 0, 1, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET amount = EXCLUDED.amount, held_amount = 0, version = balances.version + 1, last_updated_at = NOW()`,
+		userID, amount)
+	return err
+}
+
+// BackdateUser sets when a user signed up.
+func (r *SeedPostgresRepository) BackdateUser(ctx context.Context, userID int, createdAt time.Time) error {
+	_, err := r.pool.Exec(ctx, `UPDATE users SET created_at = $2, updated_at = $2 WHERE id = $1`, userID, createdAt.UTC())
+	return err
+}
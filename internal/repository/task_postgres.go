@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// taskColumns is the column list shared by every worker task SELECT.
+const taskColumns = `task_id, type, user_id, to_user_id, amount, priority, status,
+		       error, transaction_id, attempts, COALESCE(batch_id, ''), submitted_at, started_at, completed_at`
+
+// TaskPostgresRepository implements domain.TaskRepository using PostgreSQL.
+type TaskPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTaskPostgresRepository creates a new TaskPostgresRepository.
+func NewTaskPostgresRepository(pool *pgxpool.Pool) *TaskPostgresRepository {
+	return &TaskPostgresRepository{pool: pool}
+}
+
+// scanTask scans a row selected with taskColumns.
+func scanTask(row pgx.Row) (*domain.TaskRecord, error) {
+	rec := &domain.TaskRecord{}
+	err := row.Scan(
+		&rec.TaskID, &rec.Type, &rec.UserID, &rec.ToUserID, &rec.Amount, &rec.Priority, &rec.Status,
+		&rec.Error, &rec.TransactionID, &rec.Attempts, &rec.BatchID, &rec.SubmittedAt, &rec.StartedAt, &rec.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// Create inserts a new task record.
+func (r *TaskPostgresRepository) Create(ctx context.Context, rec *domain.TaskRecord) error {
+	query := `
+		INSERT INTO worker_tasks (
+			task_id, type, user_id, to_user_id, amount, priority, status,
+			error, transaction_id, attempts, batch_id, submitted_at, started_at, completed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12, $13, $14)
+	`
+	_, err := r.pool.Exec(ctx, query,
+		rec.TaskID, rec.Type, rec.UserID, rec.ToUserID, rec.Amount, rec.Priority, rec.Status,
+		rec.Error, rec.TransactionID, rec.Attempts, rec.BatchID, rec.SubmittedAt, rec.StartedAt, rec.CompletedAt,
+	)
+	return err
+}
+
+// GetByID fetches a task record by task ID.
+func (r *TaskPostgresRepository) GetByID(ctx context.Context, taskID string) (*domain.TaskRecord, error) {
+	query := `SELECT ` + taskColumns + ` FROM worker_tasks WHERE task_id = $1`
+	rec, err := scanTask(r.pool.QueryRow(ctx, query, taskID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return rec, nil
+}
+
+// ListByBatch fetches the records of every task submitted in a batch.
+func (r *TaskPostgresRepository) ListByBatch(ctx context.Context, batchID string) ([]*domain.TaskRecord, error) {
+	query := `SELECT ` + taskColumns + ` FROM worker_tasks WHERE batch_id = $1 ORDER BY submitted_at`
+	rows, err := r.pool.Query(ctx, query, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*domain.TaskRecord
+	for rows.Next() {
+		rec, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Update stores the current status and outcome of a task.
+func (r *TaskPostgresRepository) Update(ctx context.Context, rec *domain.TaskRecord) error {
+	query := `
+		UPDATE worker_tasks SET
+			status = $1, error = $2, transaction_id = $3, attempts = $4, started_at = $5, completed_at = $6
+		WHERE task_id = $7
+	`
+	result, err := r.pool.Exec(ctx, query,
+		rec.Status, rec.Error, rec.TransactionID, rec.Attempts, rec.StartedAt, rec.CompletedAt, rec.TaskID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("task not found")
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// archivedTransactionColumns is the column list shared by every archived transaction SELECT.
+const archivedTransactionColumns = `id, from_user_id, to_user_id, amount, type, status, created_at, COALESCE(description, ''), fee, archived_at`
+
+// TransactionArchivePostgresRepository implements domain.TransactionArchiveRepository using PostgreSQL.
+type TransactionArchivePostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTransactionArchivePostgresRepository creates a new TransactionArchivePostgresRepository.
+func NewTransactionArchivePostgresRepository(pool *pgxpool.Pool) *TransactionArchivePostgresRepository {
+	return &TransactionArchivePostgresRepository{pool: pool}
+}
+
+// scanArchivedTransaction scans a row selected with archivedTransactionColumns.
+func scanArchivedTransaction(row pgx.Row) (*domain.ArchivedTransaction, error) {
+	tx := &domain.ArchivedTransaction{}
+	err := row.Scan(&tx.ID, &tx.FromUserID, &tx.ToUserID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt, &tx.Description, &tx.Fee, &tx.ArchivedAt)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// ArchiveBefore moves up to limit final transactions created before cutoff to the archive.
+func (r *TransactionArchivePostgresRepository) ArchiveBefore(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	query := `
+		WITH moved AS (
+			DELETE FROM transactions
+			WHERE (id, created_at) IN (
+				SELECT id, created_at FROM transactions
+				WHERE created_at < $1 AND status = ANY($2)
+				ORDER BY created_at
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, from_user_id, to_user_id, amount, type, status, created_at, idempotency_key, description, fee, fee_schedule_id
+		)
+		INSERT INTO transactions_archive (id, from_user_id, to_user_id, amount, type, status, created_at, idempotency_key, description, fee, fee_schedule_id)
+		SELECT id, from_user_id, to_user_id, amount, type, status, created_at, idempotency_key, description, fee, fee_schedule_id FROM moved`
+
+	result, err := r.pool.Exec(ctx, query, cutoff.UTC(), domain.ArchivableStatuses, limit)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
+
+// GetByID fetches an archived transaction by ID.
+func (r *TransactionArchivePostgresRepository) GetByID(ctx context.Context, id int) (*domain.ArchivedTransaction, error) {
+	query := `SELECT ` + archivedTransactionColumns + ` FROM transactions_archive WHERE id = $1`
+	tx, err := scanArchivedTransaction(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Search fetches archived transactions matching filter, newest first.
+func (r *TransactionArchivePostgresRepository) Search(ctx context.Context, filter domain.ArchivedTransactionFilter) ([]*domain.ArchivedTransaction, error) {
+	var conditions []string
+	var args []any
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.ReplaceAll(cond, "$?", fmt.Sprintf("$%d", len(args))))
+	}
+	if filter.UserID != nil {
+		where("(from_user_id = $? OR to_user_id = $?)", *filter.UserID)
+	}
+	if filter.From != nil {
+		where("created_at >= $?", *filter.From)
+	}
+	if filter.To != nil {
+		where("created_at < $?", *filter.To)
+	}
+
+	query := `SELECT ` + archivedTransactionColumns + ` FROM transactions_archive`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*domain.ArchivedTransaction
+	for rows.Next() {
+		tx, err := scanArchivedTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, tx)
+	}
+	return transactions, rows.Err()
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+func TestTransactionArchivePostgresRepository_KeepsIdempotencyKeys(t *testing.T) {
+	ctx := context.Background()
+	conn := getTestConn(t)
+	txRepo := NewTransactionPostgresRepository(conn)
+	archiveRepo := NewTransactionArchivePostgresRepository(conn)
+	const key = "archive-test-key"
+	defer func() {
+		conn.Exec(context.Background(), "DELETE FROM transactions_archive WHERE idempotency_key = $1", key)
+		conn.Exec(context.Background(), "DELETE FROM transactions WHERE idempotency_key = $1", key)
+		conn.Exec(context.Background(), "DELETE FROM transaction_idempotency_keys WHERE idempotency_key = $1", key)
+		conn.Exec(context.Background(), "DELETE FROM users WHERE id = 9993")
+		conn.Close()
+	}()
+	_, _ = conn.Exec(ctx, "INSERT INTO users (id, username, email, password_hash, role, created_at, updated_at) VALUES (9993,'archiveuser','archiveuser@example.com','hash','user',NOW(),NOW()) ON CONFLICT (id) DO NOTHING")
+
+	userID := 9993
+	tx := &domain.Transaction{ToUserID: &userID, Amount: 25, Type: "credit", Status: "completed", IdempotencyKey: key}
+	if err := txRepo.Create(ctx, tx); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	// Archive everything final up to now, which includes tx
+	if _, err := archiveRepo.ArchiveBefore(ctx, time.Now().Add(time.Minute), 1000); err != nil {
+		t.Fatalf("ArchiveBefore failed: %v", err)
+	}
+	archived, err := archiveRepo.GetByID(ctx, tx.ID)
+	if err != nil || archived == nil {
+		t.Fatalf("expected transaction %d in the archive, got %v, %v", tx.ID, archived, err)
+	}
+
+	got, err := txRepo.GetByIdempotencyKey(ctx, key)
+	if err != nil {
+		t.Fatalf("GetByIdempotencyKey failed: %v", err)
+	}
+	if got == nil || got.ID != tx.ID || got.Amount != 25 || got.IdempotencyKey != key {
+		t.Fatalf("expected the archived transaction for the key, got %+v", got)
+	}
+
+	// The key is still taken
+	retry := &domain.Transaction{ToUserID: &userID, Amount: 25, Type: "credit", Status: "completed", IdempotencyKey: key}
+	if err := txRepo.Create(ctx, retry); err == nil {
+		t.Error("expected reusing an archived transaction's key to fail")
+	}
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// TransactionExportPostgresRepository implements domain.TransactionExportRepository.
+type TransactionExportPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTransactionExportPostgresRepository creates a new TransactionExportPostgresRepository.
+func NewTransactionExportPostgresRepository(pool *pgxpool.Pool) *TransactionExportPostgresRepository {
+	return &TransactionExportPostgresRepository{pool: pool}
+}
+
+// OpeningBalance sums the signed amounts of the user's completed transactions created before the given time.
+func (r *TransactionExportPostgresRepository) OpeningBalance(ctx context.Context, userID int, before time.Time) (float64, error) {
+	query := `SELECT COALESCE(SUM(` + signedAmountSQL + `), 0) FROM transaction_ledger
+		WHERE (to_user_id = $1 OR from_user_id = $1) AND status = 'completed' AND created_at < $2`
+	var balance float64
+	err := r.pool.QueryRow(ctx, query, userID, before).Scan(&balance)
+	return balance, err
+}
+
+// StreamLedger hands the user's completed transactions in [from, to) to fn, oldest first.
+func (r *TransactionExportPostgresRepository) StreamLedger(ctx context.Context, userID int, from, to time.Time, fn func(*domain.LedgerEntry) error) error {
+	query := `SELECT id, type, ` + signedAmountSQL + `,
+			CASE WHEN type = 'transfer' THEN CASE WHEN from_user_id = $1 THEN to_user_id ELSE from_user_id END END,
+			created_at
+		FROM transaction_ledger WHERE ` + userLedgerFilterSQL + `
+		ORDER BY created_at, id`
+	rows, err := r.pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := &domain.LedgerEntry{}
+		if err := rows.Scan(&e.TransactionID, &e.Type, &e.Amount, &e.CounterpartyID, &e.CreatedAt); err != nil {
+			return err
+		}
+		e.CreatedAt = e.CreatedAt.UTC()
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
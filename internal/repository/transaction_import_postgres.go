@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// TransactionImportPostgresRepository implements domain.TransactionImportRepository using PostgreSQL.
+type TransactionImportPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTransactionImportPostgresRepository creates a new TransactionImportPostgresRepository.
+func NewTransactionImportPostgresRepository(pool *pgxpool.Pool) *TransactionImportPostgresRepository {
+	return &TransactionImportPostgresRepository{pool: pool}
+}
+
+// ImportedRefs returns which of refs were already imported for the user.
+func (r *TransactionImportPostgresRepository) ImportedRefs(ctx context.Context, userID int, refs []string) (map[string]int, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT external_ref, transaction_id FROM transaction_external_refs WHERE user_id = $1 AND external_ref = ANY($2)`,
+		userID, refs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	imported := make(map[string]int)
+	for rows.Next() {
+		var ref string
+		var id int
+		if err := rows.Scan(&ref, &id); err != nil {
+			return nil, err
+		}
+		imported[ref] = id
+	}
+	return imported, rows.Err()
+}
+
+// Import inserts the transactions and their references and recomputes the touched balances.
+func (r *TransactionImportPostgresRepository) Import(ctx context.Context, userID int, txs []*domain.ImportedTransaction) (map[int]float64, error) {
+	var balances map[int]float64
+	err := WithRetry(ctx, TransientRetryPolicy, func() error {
+		var err error
+		balances, err = r.importOnce(ctx, userID, txs)
+		return err
+	})
+	return balances, err
+}
+
+func (r *TransactionImportPostgresRepository) importOnce(ctx context.Context, userID int, txs []*domain.ImportedTransaction) (map[int]float64, error) {
+	balances := make(map[int]float64)
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		for _, it := range txs {
+			t := it.Transaction
+			err := tx.QueryRow(ctx, `
+				INSERT INTO transactions (from_user_id, to_user_id, amount, type, status, created_at, description)
+				VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+				RETURNING id`,
+				t.FromUserID, t.ToUserID, t.Amount, t.Type, t.Status, t.CreatedAt.UTC(), t.Description,
+			).Scan(&t.ID)
+			if err != nil {
+				return err
+			}
+			var ref string
+			err = tx.QueryRow(ctx, `
+				INSERT INTO transaction_external_refs (user_id, external_ref, transaction_id, imported_at)
+				VALUES ($1, $2, $3, NOW())
+				ON CONFLICT (user_id, external_ref) DO NOTHING
+				RETURNING external_ref`,
+				userID, it.ExternalRef, t.ID,
+			).Scan(&ref)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrImportConcurrent
+			}
+			if err != nil {
+				return err
+			}
+			for _, id := range []*int{t.FromUserID, t.ToUserID} {
+				if id != nil {
+					balances[*id] = 0
+				}
+			}
+		}
+
+		for id := range balances {
+			var amount float64
+			err := tx.QueryRow(ctx, `
+				INSERT INTO balances (user_id, amount, held_amount, version, last_updated_at)
+				SELECT $1, COALESCE(SUM(`+signedAmountSQL+`), 0), 0, 1, NOW()
+				FROM transaction_ledger WHERE (to_user_id = $1 OR from_user_id = $1) AND status = 'completed'
+				ON CONFLICT (user_id) DO UPDATE
+				SET amount = EXCLUDED.amount, version = balances.version + 1, last_updated_at = NOW()
+				RETURNING amount`,
+				id).Scan(&amount)
+			if err != nil {
+				return err
+			}
+			if amount < 0 {
+				return fmt.Errorf("user %d: %w", id, domain.ErrImportNegativeBalance)
+			}
+			balances[id] = amount
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
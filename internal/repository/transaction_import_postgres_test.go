@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+func TestTransactionImportPostgresRepository_Import(t *testing.T) {
+	ctx := context.Background()
+	conn := getTestConn(t)
+	repo := NewTransactionImportPostgresRepository(conn)
+	balRepo := NewBalancePostgresRepository(conn)
+	userID, otherID := 7781, 7782
+	defer func() {
+		conn.Exec(context.Background(), "DELETE FROM transaction_external_refs WHERE user_id = $1", userID)
+		conn.Exec(context.Background(), "DELETE FROM transactions WHERE from_user_id IN ($1, $2) OR to_user_id IN ($1, $2)", userID, otherID)
+		conn.Exec(context.Background(), "DELETE FROM balances WHERE user_id IN ($1, $2)", userID, otherID)
+		conn.Exec(context.Background(), "DELETE FROM users WHERE id IN ($1, $2)", userID, otherID)
+		conn.Close()
+	}()
+	_, _ = conn.Exec(context.Background(), "INSERT INTO users (id, username, email, password_hash, role, created_at, updated_at) VALUES ($1,$2,$3,$4,$5,NOW(),NOW()) ON CONFLICT (id) DO NOTHING", userID, "importuser", "importuser@example.com", "hash", "user")
+	_, _ = conn.Exec(context.Background(), "INSERT INTO users (id, username, email, password_hash, role, created_at, updated_at) VALUES ($1,$2,$3,$4,$5,NOW(),NOW()) ON CONFLICT (id) DO NOTHING", otherID, "importother", "importother@example.com", "hash", "user")
+
+	// The user already has a stored balance that a concurrent writer read at version 1
+	if err := balRepo.Update(ctx, &domain.Balance{UserID: userID}); err != nil {
+		t.Fatalf("failed to create balance: %v", err)
+	}
+	stale, _ := balRepo.GetByUserID(ctx, userID)
+
+	createdAt := time.Now().UTC().AddDate(0, -1, 0).Truncate(time.Second)
+	txs := []*domain.ImportedTransaction{
+		{ExternalRef: "imp-1", Transaction: &domain.Transaction{ToUserID: &userID, Amount: 100, Type: "credit", Status: "completed", CreatedAt: createdAt}},
+		{ExternalRef: "imp-2", Transaction: &domain.Transaction{FromUserID: &userID, ToUserID: &otherID, Amount: 40, Type: "transfer", Status: "completed", CreatedAt: createdAt}},
+	}
+	balances, err := repo.Import(ctx, userID, txs)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if balances[userID] != 60 || balances[otherID] != 40 {
+		t.Errorf("got balances %v, want %d: 60 and %d: 40", balances, userID, otherID)
+	}
+
+	stored, _ := balRepo.GetByUserID(ctx, userID)
+	if stored.Amount != 60 || stored.Version <= stale.Version {
+		t.Errorf("got stored balance %.2f at version %d, want 60 at a version after %d", stored.Amount, stored.Version, stale.Version)
+	}
+	stale.Amount = 1000
+	if err := balRepo.Update(ctx, stale); !errors.Is(err, domain.ErrBalanceConflict) {
+		t.Errorf("an update read before the import must conflict, got %v", err)
+	}
+
+	refs, err := repo.ImportedRefs(ctx, userID, []string{"imp-1", "imp-2", "imp-3"})
+	if err != nil {
+		t.Fatalf("ImportedRefs failed: %v", err)
+	}
+	if len(refs) != 2 || refs["imp-1"] != txs[0].Transaction.ID || refs["imp-2"] != txs[1].Transaction.ID {
+		t.Errorf("got refs %v, want imp-1 and imp-2 mapped to their transactions", refs)
+	}
+
+	// A reference recorded meanwhile rolls the whole import back
+	again := []*domain.ImportedTransaction{
+		{ExternalRef: "imp-3", Transaction: &domain.Transaction{ToUserID: &userID, Amount: 5, Type: "credit", Status: "completed", CreatedAt: createdAt}},
+		{ExternalRef: "imp-1", Transaction: &domain.Transaction{ToUserID: &userID, Amount: 5, Type: "credit", Status: "completed", CreatedAt: createdAt}},
+	}
+	if _, err := repo.Import(ctx, userID, again); !errors.Is(err, domain.ErrImportConcurrent) {
+		t.Errorf("got %v, want ErrImportConcurrent", err)
+	}
+	if refs, _ := repo.ImportedRefs(ctx, userID, []string{"imp-3"}); len(refs) != 0 {
+		t.Errorf("imp-3 must not be recorded after a failed import")
+	}
+
+	// Taking more than the user has leaves the balance alone
+	overdraw := []*domain.ImportedTransaction{
+		{ExternalRef: "imp-4", Transaction: &domain.Transaction{FromUserID: &userID, Amount: 500, Type: "debit", Status: "completed", CreatedAt: createdAt}},
+	}
+	if _, err := repo.Import(ctx, userID, overdraw); !errors.Is(err, domain.ErrImportNegativeBalance) {
+		t.Errorf("got %v, want ErrImportNegativeBalance", err)
+	}
+	if after, _ := balRepo.GetByUserID(ctx, userID); after.Amount != 60 {
+		t.Errorf("got balance %.2f after a failed import, want 60", after.Amount)
+	}
+}
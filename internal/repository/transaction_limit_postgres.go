@@ -2,8 +2,8 @@ package repository
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/melihgurlek/backend-path/internal/domain"
@@ -13,26 +13,31 @@ import (
 )
 
 type transactionLimitPostgresRepository struct {
-	db *pgxpool.Pool
+	db DBTX
+	// inUnitOfWork is set when db is a unit of work's transaction, which
+	// retries transient failures itself
+	inUnitOfWork bool
 }
 
 func NewTransactionLimitPostgresRepository(db *pgxpool.Pool) domain.TransactionLimitRepository {
 	return &transactionLimitPostgresRepository{db: db}
 }
 
+// CheckAndRecordTransaction records the transaction if the user's active rules all pass.
 func (r *transactionLimitPostgresRepository) CheckAndRecordTransaction(ctx context.Context, userID int, amount float64, currency string, timestamp time.Time) error {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
-	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+	run := func() error {
+		return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+			return r.checkAndRecordTx(ctx, tx, userID, amount, currency, timestamp)
+		})
 	}
-	defer func() {
-		if err != nil {
-			tx.Rollback(ctx)
-		} else {
-			tx.Commit(ctx)
-		}
-	}()
+	if r.inUnitOfWork {
+		return run()
+	}
+	return WithRetry(ctx, TransientRetryPolicy, run)
+}
 
+// checkAndRecordTx checks userID's active rules and records the transaction within tx.
+func (r *transactionLimitPostgresRepository) checkAndRecordTx(ctx context.Context, tx pgx.Tx, userID int, amount float64, currency string, timestamp time.Time) error {
 	// 1. Fetch active rules for user (snapshot)
 	rules, err := r.getActiveRulesForUserTx(ctx, tx, userID)
 	if err != nil {
@@ -40,41 +45,46 @@ func (r *transactionLimitPostgresRepository) CheckAndRecordTransaction(ctx conte
 	}
 
 	for _, rule := range rules {
-		switch rule.RuleType {
-		case "max_per_transaction":
+		switch {
+		case rule.RuleType == domain.RuleMaxPerTransaction:
 			if amount > rule.LimitAmount {
-				return errors.New("max per transaction limit exceeded")
+				return limitExceeded(rule, "max per transaction limit exceeded")
 			}
-		case "daily_total":
-			// Sum of today's transactions + this one <= limit
+		case rule.RuleType.IsTotal():
+			// Sum of the period's transactions + this one <= limit
 			var sum float64
-			err = tx.QueryRow(ctx, `SELECT COALESCE(SUM(amount),0) FROM user_transactions WHERE user_id = $1 AND currency = $2 AND created_at >= date_trunc('day', $3)`, userID, currency, timestamp).Scan(&sum)
+			err = tx.QueryRow(ctx, `SELECT COALESCE(SUM(amount),0) FROM user_transactions
+				WHERE user_id = $1 AND currency = $2 AND created_at >= $3 AND created_at <= $4`,
+				userID, currency, rule.PeriodStart(timestamp), timestamp).Scan(&sum)
 			if err != nil {
-				return fmt.Errorf("query daily total: %w", err)
+				return fmt.Errorf("query %s: %w", rule.RuleType, err)
 			}
 			if sum+amount > rule.LimitAmount {
-				return errors.New("daily total limit exceeded")
+				return limitExceeded(rule, strings.ReplaceAll(string(rule.RuleType), "_", " ")+" limit exceeded")
 			}
-		case "tx_count":
+		case rule.RuleType == domain.RuleTxCount:
 			// Count of transactions in window + this one <= limit
-			windowStart := timestamp.Add(-rule.Window)
 			var count int
-			err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM user_transactions WHERE user_id = $1 AND currency = $2 AND created_at >= $3`, userID, currency, windowStart).Scan(&count)
+			err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM user_transactions
+				WHERE user_id = $1 AND currency = $2 AND created_at >= $3 AND created_at <= $4`,
+				userID, currency, rule.PeriodStart(timestamp), timestamp).Scan(&count)
 			if err != nil {
 				return fmt.Errorf("query tx count: %w", err)
 			}
 			if float64(count+1) > rule.LimitAmount {
-				return errors.New("transaction count limit exceeded")
+				return limitExceeded(rule, "transaction count limit exceeded")
 			}
-		case "min_interval":
-			// New transaction must be at least window after last one
-			var lastTime time.Time
-			err = tx.QueryRow(ctx, `SELECT COALESCE(MAX(created_at), 'epoch') FROM user_transactions WHERE user_id = $1 AND currency = $2`, userID, currency).Scan(&lastTime)
+		case rule.RuleType == domain.RuleMinInterval:
+			// No transaction may have been made within the window before this one
+			var tooSoon bool
+			err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM user_transactions
+				WHERE user_id = $1 AND currency = $2 AND created_at >= $3 AND created_at <= $4)`,
+				userID, currency, rule.PeriodStart(timestamp), timestamp).Scan(&tooSoon)
 			if err != nil {
 				return fmt.Errorf("query last tx time: %w", err)
 			}
-			if !lastTime.IsZero() && timestamp.Sub(lastTime) < rule.Window {
-				return errors.New("minimum interval between transactions not met")
+			if tooSoon {
+				return limitExceeded(rule, "minimum interval between transactions not met")
 			}
 		}
 	}
@@ -88,9 +98,16 @@ func (r *transactionLimitPostgresRepository) CheckAndRecordTransaction(ctx conte
 	return nil
 }
 
-// getActiveRulesForUserTx fetches active rules for a user within a transaction
+// limitExceeded describes the rule a transaction broke
+func limitExceeded(rule domain.TransactionLimitRule, msg string) error {
+	return &domain.LimitExceededError{RuleID: rule.ID, RuleType: rule.RuleType, Limit: rule.LimitAmount, Msg: msg}
+}
+
+// getActiveRulesForUserTx fetches a user's own active rules and those of their KYC level.
 func (r *transactionLimitPostgresRepository) getActiveRulesForUserTx(ctx context.Context, tx pgx.Tx, userID int) ([]domain.TransactionLimitRule, error) {
-	rows, err := tx.Query(ctx, `SELECT id, user_id, rule_type, limit_amount, currency, "window", active, created_at, updated_at FROM transaction_limit_rules WHERE user_id = $1 AND active = TRUE`, userID)
+	rows, err := tx.Query(ctx, `SELECT id::text, user_id, rule_type, limit_amount, currency, "window", active, created_at, updated_at FROM transaction_limit_rules WHERE user_id = $1 AND active = TRUE
+		UNION ALL
+		SELECT `+kycTierRuleColumns+` FROM kyc_tier_limits l JOIN users u ON u.kyc_level = l.level WHERE u.id = $1`, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -133,8 +150,9 @@ func (r *transactionLimitPostgresRepository) RemoveRule(ctx context.Context, use
 		return fmt.Errorf("remove rule: %w", err)
 	}
 
+	// A rule of another user is reported as missing
 	if result.RowsAffected() == 0 {
-		return errors.New("rule not found or permission denied")
+		return domain.ErrLimitRuleNotFound
 	}
 
 	return nil
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// partitionBoundLayout formats partition bounds; created_at is a TIMESTAMP without time zone.
+const partitionBoundLayout = "2006-01-02 15:04:05"
+
+// TransactionPartitionPostgresRepository implements domain.TransactionPartitionRepository using PostgreSQL.
+type TransactionPartitionPostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTransactionPartitionPostgresRepository creates a new TransactionPartitionPostgresRepository.
+func NewTransactionPartitionPostgresRepository(pool *pgxpool.Pool) *TransactionPartitionPostgresRepository {
+	return &TransactionPartitionPostgresRepository{pool: pool}
+}
+
+// ListPartitions retrieves the monthly partitions attached to transactions, oldest first.
+func (r *TransactionPartitionPostgresRepository) ListPartitions(ctx context.Context) ([]domain.TransactionPartition, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'transactions'::regclass`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var partitions []domain.TransactionPartition
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		// Monthly partitions are named transactions_pYYYYMM
+		suffix, ok := strings.CutPrefix(name, "transactions_p")
+		if !ok {
+			continue
+		}
+		month, err := time.Parse("200601", suffix)
+		if err != nil {
+			continue
+		}
+		partitions = append(partitions, domain.NewTransactionPartition(month))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].From.Before(partitions[j].From) })
+	return partitions, nil
+}
+
+// CreatePartition creates and attaches a partition unless it already exists.
+func (r *TransactionPartitionPostgresRepository) CreatePartition(ctx context.Context, partition domain.TransactionPartition) error {
+	// DDL takes no parameters; the bounds are formatted from time.Time values
+	_, err := r.pool.Exec(ctx, fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %s PARTITION OF transactions FOR VALUES FROM ('%s') TO ('%s')`,
+		pgx.Identifier{partition.Name}.Sanitize(),
+		partition.From.UTC().Format(partitionBoundLayout),
+		partition.To.UTC().Format(partitionBoundLayout),
+	))
+	return err
+}
+
+// DetachPartition detaches a partition from transactions.
+func (r *TransactionPartitionPostgresRepository) DetachPartition(ctx context.Context, partition domain.TransactionPartition) error {
+	_, err := r.pool.Exec(ctx, `ALTER TABLE transactions DETACH PARTITION `+pgx.Identifier{partition.Name}.Sanitize())
+	return err
+}
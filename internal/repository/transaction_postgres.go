@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -10,32 +12,44 @@ import (
 	"github.com/melihgurlek/backend-path/internal/domain"
 )
 
+// transactionColumns is the column list shared by every transaction SELECT.
+const transactionColumns = `id, from_user_id, to_user_id, amount, type, status, created_at, COALESCE(idempotency_key, ''), COALESCE(description, ''), fee, fee_schedule_id`
+
 // TransactionPostgresRepository implements domain.TransactionRepository using PostgreSQL.
 type TransactionPostgresRepository struct {
-	pool *pgxpool.Pool
+	db DBTX
 }
 
 // NewTransactionPostgresRepository creates a new TransactionPostgresRepository.
 func NewTransactionPostgresRepository(pool *pgxpool.Pool) *TransactionPostgresRepository {
-	return &TransactionPostgresRepository{pool: pool}
+	return &TransactionPostgresRepository{db: pool}
+}
+
+// scanTransaction scans a row selected with transactionColumns.
+func scanTransaction(row pgx.Row) (*domain.Transaction, error) {
+	tx := &domain.Transaction{}
+	err := row.Scan(
+		&tx.ID, &tx.FromUserID, &tx.ToUserID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt, &tx.IdempotencyKey, &tx.Description, &tx.Fee, &tx.FeeScheduleID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
 }
 
 // Create inserts a new transaction into the database.
-func (r *TransactionPostgresRepository) Create(tx *domain.Transaction) error {
-	query := `INSERT INTO transactions (from_user_id, to_user_id, amount, type, status, created_at)
-		VALUES ($1, $2, $3, $4, $5, NOW()) RETURNING id, created_at`
-	return r.pool.QueryRow(context.Background(), query,
-		tx.FromUserID, tx.ToUserID, tx.Amount, tx.Type, tx.Status,
+func (r *TransactionPostgresRepository) Create(ctx context.Context, tx *domain.Transaction) error {
+	query := `INSERT INTO transactions (from_user_id, to_user_id, amount, type, status, created_at, idempotency_key, description, fee, fee_schedule_id)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NULLIF($6, ''), NULLIF($7, ''), $8, $9) RETURNING id, created_at`
+	return r.db.QueryRow(ctx, query,
+		tx.FromUserID, tx.ToUserID, tx.Amount, tx.Type, tx.Status, tx.IdempotencyKey, tx.Description, tx.Fee, tx.FeeScheduleID,
 	).Scan(&tx.ID, &tx.CreatedAt)
 }
 
 // GetByID fetches a transaction by ID.
-func (r *TransactionPostgresRepository) GetByID(id int) (*domain.Transaction, error) {
-	tx := &domain.Transaction{}
-	query := `SELECT id, from_user_id, to_user_id, amount, type, status, created_at FROM transactions WHERE id = $1`
-	err := r.pool.QueryRow(context.Background(), query, id).Scan(
-		&tx.ID, &tx.FromUserID, &tx.ToUserID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt,
-	)
+func (r *TransactionPostgresRepository) GetByID(ctx context.Context, id int) (*domain.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE id = $1`
+	tx, err := scanTransaction(r.db.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil // not found
@@ -45,14 +59,35 @@ func (r *TransactionPostgresRepository) GetByID(id int) (*domain.Transaction, er
 	return tx, nil
 }
 
-// ListByUser fetches all transactions for a user (as sender or receiver).
-func (r *TransactionPostgresRepository) ListByUser(userID int) ([]*domain.Transaction, error) {
-	query := `SELECT id, from_user_id, to_user_id, amount, type, status, created_at 
+// GetByIdempotencyKey fetches the transaction recorded under an idempotency key.
+func (r *TransactionPostgresRepository) GetByIdempotencyKey(ctx context.Context, key string) (*domain.Transaction, error) {
+	// The key table gives created_at, so only one partition is searched
+	query := `WITH k AS (
+			SELECT transaction_id, created_at FROM transaction_idempotency_keys WHERE idempotency_key = $1
+		)
+		SELECT ` + transactionColumns + ` FROM transactions WHERE (id, created_at) = (SELECT transaction_id, created_at FROM k)
+		UNION ALL
+		SELECT ` + transactionColumns + ` FROM transactions_archive WHERE id = (SELECT transaction_id FROM k)
+		LIMIT 1`
+	tx, err := scanTransaction(r.db.QueryRow(ctx, query, key))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not found
+		}
+		return nil, err
+	}
+	return tx, nil
+}
+
+// ListByUser fetches a page of a user's transactions (as sender or receiver), newest first.
+func (r *TransactionPostgresRepository) ListByUser(ctx context.Context, userID int, limit, offset int) ([]*domain.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` 
 		FROM transactions 
 		WHERE from_user_id = $1 OR to_user_id = $1 
-		ORDER BY created_at DESC`
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
 
-	rows, err := r.pool.Query(context.Background(), query, userID)
+	rows, err := r.db.Query(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -60,10 +95,7 @@ func (r *TransactionPostgresRepository) ListByUser(userID int) ([]*domain.Transa
 
 	var transactions []*domain.Transaction
 	for rows.Next() {
-		tx := &domain.Transaction{}
-		err := rows.Scan(
-			&tx.ID, &tx.FromUserID, &tx.ToUserID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt,
-		)
+		tx, err := scanTransaction(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -77,14 +109,23 @@ func (r *TransactionPostgresRepository) ListByUser(userID int) ([]*domain.Transa
 	return transactions, nil
 }
 
+// CountByUser counts a user's transactions (as sender or receiver).
+func (r *TransactionPostgresRepository) CountByUser(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM transactions WHERE from_user_id = $1 OR to_user_id = $1`,
+		userID).Scan(&count)
+	return count, err
+}
+
 // ListByUserAndTimeRange fetches transactions for a user within a time range.
-func (r *TransactionPostgresRepository) ListByUserAndTimeRange(userID int, start, end time.Time) ([]*domain.Transaction, error) {
-	query := `SELECT id, from_user_id, to_user_id, amount, type, status, created_at 
+func (r *TransactionPostgresRepository) ListByUserAndTimeRange(ctx context.Context, userID int, start, end time.Time) ([]*domain.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` 
 		FROM transactions 
 		WHERE (from_user_id = $1 OR to_user_id = $1) AND created_at >= $2 AND created_at <= $3 
 		ORDER BY created_at DESC`
 
-	rows, err := r.pool.Query(context.Background(), query, userID, start, end)
+	rows, err := r.db.Query(ctx, query, userID, start, end)
 	if err != nil {
 		return nil, err
 	}
@@ -92,10 +133,7 @@ func (r *TransactionPostgresRepository) ListByUserAndTimeRange(userID int, start
 
 	var transactions []*domain.Transaction
 	for rows.Next() {
-		tx := &domain.Transaction{}
-		err := rows.Scan(
-			&tx.ID, &tx.FromUserID, &tx.ToUserID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt,
-		)
+		tx, err := scanTransaction(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -110,9 +148,33 @@ func (r *TransactionPostgresRepository) ListByUserAndTimeRange(userID int, start
 }
 
 // UpdateStatus updates the status of a transaction.
-func (r *TransactionPostgresRepository) UpdateStatus(id int, status string) error {
+func (r *TransactionPostgresRepository) UpdateStatus(ctx context.Context, id int, status string) error {
 	query := `UPDATE transactions SET status = $1 WHERE id = $2`
-	result, err := r.pool.Exec(context.Background(), query, status, id)
+	result, err := r.db.Exec(ctx, query, status, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("transaction not found")
+	}
+	return nil
+}
+
+// ResolvePending moves a transaction held for approval to status.
+func (r *TransactionPostgresRepository) ResolvePending(ctx context.Context, id int, status string) error {
+	result, err := r.db.Exec(ctx, `UPDATE transactions SET status = $1 WHERE id = $2 AND status = 'pending_approval'`, status, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrTransactionNotPending
+	}
+	return nil
+}
+
+// SetFee records the fee a transaction was charged.
+func (r *TransactionPostgresRepository) SetFee(ctx context.Context, id int, fee float64, scheduleID *int) error {
+	result, err := r.db.Exec(ctx, `UPDATE transactions SET fee = $1, fee_schedule_id = $2 WHERE id = $3`, fee, scheduleID, id)
 	if err != nil {
 		return err
 	}
@@ -122,13 +184,14 @@ func (r *TransactionPostgresRepository) UpdateStatus(id int, status string) erro
 	return nil
 }
 
+// ListAll fetches a page of all transactions, newest first.
 func (r *TransactionPostgresRepository) ListAll(ctx context.Context, limit int, offset int) ([]*domain.Transaction, error) {
-	query := `SELECT id, from_user_id, to_user_id, amount, type, status, created_at 
+	query := `SELECT ` + transactionColumns + ` 
 		FROM transactions 
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2`
 
-	rows, err := r.pool.Query(ctx, query, limit, offset)
+	rows, err := r.db.Query(ctx, query, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -136,10 +199,7 @@ func (r *TransactionPostgresRepository) ListAll(ctx context.Context, limit int,
 
 	var transactions []*domain.Transaction
 	for rows.Next() {
-		tx := &domain.Transaction{}
-		err := rows.Scan(
-			&tx.ID, &tx.FromUserID, &tx.ToUserID, &tx.Amount, &tx.Type, &tx.Status, &tx.CreatedAt,
-		)
+		tx, err := scanTransaction(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -152,3 +212,72 @@ func (r *TransactionPostgresRepository) ListAll(ctx context.Context, limit int,
 
 	return transactions, nil
 }
+
+// Search fetches the transactions matching filter, newest first.
+func (r *TransactionPostgresRepository) Search(ctx context.Context, filter domain.TransactionFilter) ([]*domain.Transaction, error) {
+	var conditions []string
+	var args []any
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.ReplaceAll(cond, "$?", fmt.Sprintf("$%d", len(args))))
+	}
+	if filter.UserID != nil {
+		where("(from_user_id = $? OR to_user_id = $?)", *filter.UserID)
+	}
+	if filter.CounterpartyID != nil {
+		// Together with the user predicate this puts the counterparty on the other side
+		where("(from_user_id = $? OR to_user_id = $?)", *filter.CounterpartyID)
+	}
+	if filter.Query != "" {
+		where("description ILIKE $?", "%"+escapeLike(filter.Query)+"%")
+	}
+	if filter.MinAmount != nil {
+		where("amount >= $?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		where("amount <= $?", *filter.MaxAmount)
+	}
+	if len(filter.Types) > 0 {
+		where("type = ANY($?)", filter.Types)
+	}
+	if len(filter.Statuses) > 0 {
+		where("status = ANY($?)", filter.Statuses)
+	}
+	if filter.From != nil {
+		where("created_at >= $?", *filter.From)
+	}
+	if filter.To != nil {
+		where("created_at < $?", *filter.To)
+	}
+
+	query := `SELECT ` + transactionColumns + ` FROM transactions`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*domain.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, tx)
+	}
+	return transactions, rows.Err()
+}
+
+// likeEscaper escapes the characters LIKE patterns give a meaning to
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike makes s match itself literally inside a LIKE pattern
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
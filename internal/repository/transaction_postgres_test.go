@@ -8,6 +8,7 @@ import (
 )
 
 func TestTransactionPostgresRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
 	conn := getTestConn(t)
 	repo := NewTransactionPostgresRepository(conn)
 	defer func() {
@@ -30,7 +31,7 @@ func TestTransactionPostgresRepository_CRUD(t *testing.T) {
 		Type:       "transfer",
 		Status:     "completed",
 	}
-	err := repo.Create(tx)
+	err := repo.Create(ctx, tx)
 	if err != nil {
 		t.Fatalf("Create failed: %v", err)
 	}
@@ -39,7 +40,7 @@ func TestTransactionPostgresRepository_CRUD(t *testing.T) {
 	}
 
 	// Test GetByID
-	got, err := repo.GetByID(tx.ID)
+	got, err := repo.GetByID(ctx, tx.ID)
 	if err != nil {
 		t.Fatalf("GetByID failed: %v", err)
 	}
@@ -48,7 +49,7 @@ func TestTransactionPostgresRepository_CRUD(t *testing.T) {
 	}
 
 	// Test ListByUser
-	txs, err := repo.ListByUser(u1.ID)
+	txs, err := repo.ListByUser(ctx, u1.ID, 100, 0)
 	if err != nil {
 		t.Fatalf("ListByUser failed: %v", err)
 	}
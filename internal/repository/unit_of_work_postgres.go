@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// PostgresUnitOfWork implements domain.UnitOfWork with a PostgreSQL transaction.
+type PostgresUnitOfWork struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresUnitOfWork creates a new PostgresUnitOfWork.
+func NewPostgresUnitOfWork(pool *pgxpool.Pool) *PostgresUnitOfWork {
+	return &PostgresUnitOfWork{pool: pool}
+}
+
+// Do runs fn with repositories bound to one transaction.
+func (u *PostgresUnitOfWork) Do(ctx context.Context, fn func(repos domain.UnitOfWorkRepositories) error) error {
+	return WithRetry(ctx, TransientRetryPolicy, func() error {
+		return u.do(ctx, fn)
+	})
+}
+
+func (u *PostgresUnitOfWork) do(ctx context.Context, fn func(repos domain.UnitOfWorkRepositories) error) error {
+	tx, err := u.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after a successful commit
+
+	repos := domain.UnitOfWorkRepositories{
+		Balances:      &BalancePostgresRepository{db: tx},
+		Transactions:  &TransactionPostgresRepository{db: tx},
+		Limits:        &transactionLimitPostgresRepository{db: tx, inUnitOfWork: true},
+		Campaigns:     &CampaignPostgresRepository{db: tx},
+		Referrals:     &ReferralPostgresRepository{db: tx},
+		Disputes:      &DisputePostgresRepository{db: tx},
+		Funding:       &FundingPostgresRepository{db: tx},
+		BankTransfers: &BankTransferPostgresRepository{db: tx},
+		Approvals:     &ApprovalPostgresRepository{db: tx},
+		Holds:         &HoldPostgresRepository{db: tx},
+		Organizations: &OrganizationPostgresRepository{db: tx},
+	}
+	if err := fn(repos); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
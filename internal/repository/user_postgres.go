@@ -3,20 +3,61 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/crypto"
 )
 
+// userColumns is the column list shared by every user SELECT.
+const userColumns = `id, username, email, password_hash, role, created_at, updated_at, erased_at, last_login_at,
+	COALESCE(first_name, ''), COALESCE(last_name, ''), COALESCE(phone, ''), COALESCE(locale, ''), COALESCE(avatar_key, '')`
+
 // UserPostgresRepository implements domain.UserRepository using PostgreSQL.
 type UserPostgresRepository struct {
 	pool *pgxpool.Pool
+	keys *crypto.Keyring
 }
 
 // NewUserPostgresRepository creates a new UserPostgresRepository.
-func NewUserPostgresRepository(pool *pgxpool.Pool) *UserPostgresRepository {
-	return &UserPostgresRepository{pool: pool}
+func NewUserPostgresRepository(pool *pgxpool.Pool, keys *crypto.Keyring) *UserPostgresRepository {
+	return &UserPostgresRepository{pool: pool, keys: keys}
+}
+
+// scanUser scans a row selected with userColumns and decrypts its email and phone.
+func (r *UserPostgresRepository) scanUser(row pgx.Row) (*domain.User, error) {
+	user := &domain.User{}
+	err := row.Scan(
+		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.ErasedAt, &user.LastLoginAt,
+		&user.FirstName, &user.LastName, &user.Phone, &user.Locale, &user.AvatarKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if user.Email, err = r.keys.Decrypt(user.Email); err != nil {
+		return nil, fmt.Errorf("failed to decrypt email of user %d: %w", user.ID, err)
+	}
+	if user.Phone, err = r.keys.Decrypt(user.Phone); err != nil {
+		return nil, fmt.Errorf("failed to decrypt phone of user %d: %w", user.ID, err)
+	}
+	return user, nil
+}
+
+// emailIndex returns the blind index of an email, ignoring case and surrounding space.
+func (r *UserPostgresRepository) emailIndex(email string) string {
+	return r.keys.BlindIndex(strings.ToLower(strings.TrimSpace(email)))
+}
+
+// sealEmail returns the encrypted email and its blind index for storage.
+func (r *UserPostgresRepository) sealEmail(email string) (string, string, error) {
+	encrypted, err := r.keys.Encrypt(email)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encrypt email: %w", err)
+	}
+	return encrypted, r.emailIndex(email), nil
 }
 
 // Ping checks the database connection health.
@@ -27,21 +68,22 @@ func (r *UserPostgresRepository) Ping(ctx context.Context) error {
 }
 
 // Create inserts a new user into the database.
-func (r *UserPostgresRepository) Create(user *domain.User) error {
-	query := `INSERT INTO users (username, email, password_hash, role, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW()) RETURNING id, created_at, updated_at`
-	return r.pool.QueryRow(context.Background(), query,
-		user.Username, user.Email, user.PasswordHash, user.Role,
+func (r *UserPostgresRepository) Create(ctx context.Context, user *domain.User) error {
+	email, emailHash, err := r.sealEmail(user.Email)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO users (username, email, email_hash, password_hash, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW()) RETURNING id, created_at, updated_at`
+	return r.pool.QueryRow(ctx, query,
+		user.Username, email, emailHash, user.PasswordHash, user.Role,
 	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 }
 
 // GetByID fetches a user by ID.
-func (r *UserPostgresRepository) GetByID(id int) (*domain.User, error) {
-	user := &domain.User{}
-	query := `SELECT id, username, email, password_hash, role, created_at, updated_at FROM users WHERE id = $1`
-	err := r.pool.QueryRow(context.Background(), query, id).Scan(
-		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt, &user.UpdatedAt,
-	)
+func (r *UserPostgresRepository) GetByID(ctx context.Context, id int) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	user, err := r.scanUser(r.pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil // not found
@@ -52,12 +94,9 @@ func (r *UserPostgresRepository) GetByID(id int) (*domain.User, error) {
 }
 
 // GetByUsername fetches a user by username.
-func (r *UserPostgresRepository) GetByUsername(username string) (*domain.User, error) {
-	user := &domain.User{}
-	query := `SELECT id, username, email, password_hash, role, created_at, updated_at FROM users WHERE username = $1`
-	err := r.pool.QueryRow(context.Background(), query, username).Scan(
-		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt, &user.UpdatedAt,
-	)
+func (r *UserPostgresRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE username = $1`
+	user, err := r.scanUser(r.pool.QueryRow(ctx, query, username))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil // not found
@@ -68,12 +107,10 @@ func (r *UserPostgresRepository) GetByUsername(username string) (*domain.User, e
 }
 
 // GetByEmail fetches a user by email.
-func (r *UserPostgresRepository) GetByEmail(email string) (*domain.User, error) {
-	user := &domain.User{}
-	query := `SELECT id, username, email, password_hash, role, created_at, updated_at FROM users WHERE email = $1`
-	err := r.pool.QueryRow(context.Background(), query, email).Scan(
-		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt, &user.UpdatedAt,
-	)
+func (r *UserPostgresRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	// Rows written before encryption have no blind index yet and a plaintext email
+	query := `SELECT ` + userColumns + ` FROM users WHERE email_hash = $1 OR (email_hash IS NULL AND email = $2)`
+	user, err := r.scanUser(r.pool.QueryRow(ctx, query, r.emailIndex(email), email))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil // not found
@@ -83,10 +120,46 @@ func (r *UserPostgresRepository) GetByEmail(email string) (*domain.User, error)
 	return user, nil
 }
 
-// List fetches all users.
-func (r *UserPostgresRepository) List() ([]*domain.User, error) {
-	query := `SELECT id, username, email, password_hash, role, created_at, updated_at FROM users ORDER BY id`
-	rows, err := r.pool.Query(context.Background(), query)
+// userSortOrders maps the sorts of a user listing to their ORDER BY clauses.
+var userSortOrders = map[string]string{
+	"":            "id",
+	"id":          "id",
+	"created_at":  "created_at, id",
+	"-created_at": "created_at DESC, id DESC",
+}
+
+// userFilterSQL returns the WHERE clause and arguments selecting filter's users.
+func userFilterSQL(filter domain.UserFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		conditions = append(conditions, fmt.Sprintf("role = $%d", len(args)))
+	}
+	if filter.Erased != nil {
+		if *filter.Erased {
+			conditions = append(conditions, "erased_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "erased_at IS NULL")
+		}
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return ` WHERE ` + strings.Join(conditions, " AND "), args
+}
+
+// List fetches a page of the users matching filter.
+func (r *UserPostgresRepository) List(ctx context.Context, filter domain.UserFilter) ([]*domain.User, error) {
+	order, ok := userSortOrders[filter.Sort]
+	if !ok {
+		return nil, &domain.ValidationError{Msg: "sort must be id, created_at or -created_at"}
+	}
+	where, args := userFilterSQL(filter)
+	args = append(args, filter.Limit, filter.Offset)
+	query := `SELECT ` + userColumns + ` FROM users` + where +
+		fmt.Sprintf(` ORDER BY %s LIMIT $%d OFFSET $%d`, order, len(args)-1, len(args))
+	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -94,10 +167,7 @@ func (r *UserPostgresRepository) List() ([]*domain.User, error) {
 
 	var users []*domain.User
 	for rows.Next() {
-		user := &domain.User{}
-		err := rows.Scan(
-			&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt, &user.UpdatedAt,
-		)
+		user, err := r.scanUser(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -111,10 +181,57 @@ func (r *UserPostgresRepository) List() ([]*domain.User, error) {
 	return users, nil
 }
 
-// Update updates a user (does not change password).
-func (r *UserPostgresRepository) Update(user *domain.User) error {
-	query := `UPDATE users SET username = $1, email = $2, role = $3, updated_at = NOW() WHERE id = $4`
-	result, err := r.pool.Exec(context.Background(), query, user.Username, user.Email, user.Role, user.ID)
+// Count counts the users matching filter.
+func (r *UserPostgresRepository) Count(ctx context.Context, filter domain.UserFilter) (int, error) {
+	where, args := userFilterSQL(filter)
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM users`+where, args...).Scan(&count)
+	return count, err
+}
+
+// Update updates a user's account and profile fields (not the password or avatar).
+func (r *UserPostgresRepository) Update(ctx context.Context, user *domain.User) error {
+	email, emailHash, err := r.sealEmail(user.Email)
+	if err != nil {
+		return err
+	}
+	var phone string
+	if user.Phone != "" {
+		if phone, err = r.keys.Encrypt(user.Phone); err != nil {
+			return fmt.Errorf("failed to encrypt phone: %w", err)
+		}
+	}
+	query := `UPDATE users SET username = $1, email = $2, email_hash = $3, role = $4,
+			first_name = NULLIF($5, ''), last_name = NULLIF($6, ''), phone = NULLIF($7, ''), locale = NULLIF($8, ''), updated_at = NOW()
+		WHERE id = $9`
+	result, err := r.pool.Exec(ctx, query, user.Username, email, emailHash, user.Role,
+		user.FirstName, user.LastName, phone, user.Locale, user.ID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// UpdateAvatar replaces the object store key of a user's avatar; an empty key removes it.
+func (r *UserPostgresRepository) UpdateAvatar(ctx context.Context, id int, key string) error {
+	query := `UPDATE users SET avatar_key = NULLIF($1, ''), updated_at = NOW() WHERE id = $2`
+	result, err := r.pool.Exec(ctx, query, key, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// UpdatePassword replaces a user's password hash.
+func (r *UserPostgresRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2`
+	result, err := r.pool.Exec(ctx, query, passwordHash, id)
 	if err != nil {
 		return err
 	}
@@ -124,10 +241,322 @@ func (r *UserPostgresRepository) Update(user *domain.User) error {
 	return nil
 }
 
+// RecordLogin stores the current time as the user's last login.
+func (r *UserPostgresRepository) RecordLogin(ctx context.Context, id int) error {
+	_, err := r.pool.Exec(ctx, `UPDATE users SET last_login_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+// ListIDsByRole fetches the IDs of the users with a role who haven't been erased.
+func (r *UserPostgresRepository) ListIDsByRole(ctx context.Context, role string) ([]int, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id FROM users WHERE role = $1 AND erased_at IS NULL ORDER BY id`, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// loginEventColumns is the column list shared by every login event SELECT.
+const loginEventColumns = `id, user_id, username, ip, user_agent, success, failure_reason, suspicious,
+	country, region, city, latitude, longitude, created_at`
+
+// scanLoginEvent scans a row selected with loginEventColumns.
+func scanLoginEvent(row pgx.Row) (*domain.LoginEvent, error) {
+	e := &domain.LoginEvent{}
+	var country, region, city *string
+	var lat, lon *float64
+	err := row.Scan(&e.ID, &e.UserID, &e.Username, &e.IP, &e.UserAgent, &e.Success, &e.FailureReason, &e.Suspicious,
+		&country, &region, &city, &lat, &lon, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if country != nil && lat != nil && lon != nil {
+		e.Location = &domain.GeoLocation{Country: *country, Latitude: *lat, Longitude: *lon}
+		if region != nil {
+			e.Location.Region = *region
+		}
+		if city != nil {
+			e.Location.City = *city
+		}
+	}
+	return e, nil
+}
+
+// RecordLoginEvent inserts a login attempt.
+func (r *UserPostgresRepository) RecordLoginEvent(ctx context.Context, event *domain.LoginEvent) error {
+	var country, region, city *string
+	var lat, lon *float64
+	if loc := event.Location; loc != nil {
+		country, region, city = &loc.Country, &loc.Region, &loc.City
+		lat, lon = &loc.Latitude, &loc.Longitude
+	}
+	query := `INSERT INTO login_events (user_id, username, ip, user_agent, success, failure_reason, suspicious,
+			country, region, city, latitude, longitude, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW()) RETURNING id, created_at`
+	return r.pool.QueryRow(ctx, query,
+		event.UserID, event.Username, event.IP, event.UserAgent, event.Success, event.FailureReason, event.Suspicious,
+		country, region, city, lat, lon,
+	).Scan(&event.ID, &event.CreatedAt)
+}
+
+// ListLoginEvents fetches a page of a user's login attempts, newest first.
+func (r *UserPostgresRepository) ListLoginEvents(ctx context.Context, userID, limit, offset int) ([]*domain.LoginEvent, error) {
+	query := `SELECT ` + loginEventColumns + ` FROM login_events
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.LoginEvent
+	for rows.Next() {
+		e, err := scanLoginEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// LastLocatedLogin fetches a user's latest successful login that could be located.
+func (r *UserPostgresRepository) LastLocatedLogin(ctx context.Context, userID int) (*domain.LoginEvent, error) {
+	query := `SELECT ` + loginEventColumns + ` FROM login_events
+		WHERE user_id = $1 AND success AND latitude IS NOT NULL
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1`
+	e, err := scanLoginEvent(r.pool.QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // none
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// CountLoginEvents counts a user's login attempts.
+func (r *UserPostgresRepository) CountLoginEvents(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM login_events WHERE user_id = $1`, userID).Scan(&count)
+	return count, err
+}
+
+// deviceColumns is the column list shared by every device SELECT.
+const deviceColumns = `fingerprint, user_agent, first_seen_at, last_seen_at`
+
+// loginChallengeColumns is the column list shared by every login challenge SELECT.
+const loginChallengeColumns = `id, user_id, fingerprint, user_agent, code_hash, attempts, expires_at, created_at`
+
+// scanDevice scans a row selected with deviceColumns.
+func scanDevice(row pgx.Row) (*domain.Device, error) {
+	d := &domain.Device{}
+	if err := row.Scan(&d.Fingerprint, &d.UserAgent, &d.FirstSeenAt, &d.LastSeenAt); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// GetDevice fetches a trusted device of a user by fingerprint.
+func (r *UserPostgresRepository) GetDevice(ctx context.Context, userID int, fingerprint string) (*domain.Device, error) {
+	query := `SELECT ` + deviceColumns + ` FROM user_devices WHERE user_id = $1 AND fingerprint = $2`
+	d, err := scanDevice(r.pool.QueryRow(ctx, query, userID, fingerprint))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // not trusted
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// HasDevices reports whether a user trusts any device.
+func (r *UserPostgresRepository) HasDevices(ctx context.Context, userID int) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM user_devices WHERE user_id = $1)`, userID).Scan(&exists)
+	return exists, err
+}
+
+// TrustDevice stores the device as trusted or refreshes its last use.
+func (r *UserPostgresRepository) TrustDevice(ctx context.Context, userID int, fingerprint, userAgent string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO user_devices (user_id, fingerprint, user_agent) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, fingerprint) DO UPDATE SET user_agent = EXCLUDED.user_agent, last_seen_at = NOW()`,
+		userID, fingerprint, userAgent)
+	return err
+}
+
+// ListDevices fetches a user's trusted devices, most recently used first.
+func (r *UserPostgresRepository) ListDevices(ctx context.Context, userID int) ([]*domain.Device, error) {
+	query := `SELECT ` + deviceColumns + ` FROM user_devices WHERE user_id = $1 ORDER BY last_seen_at DESC, fingerprint`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var devices []*domain.Device
+	for rows.Next() {
+		d, err := scanDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+	return devices, rows.Err()
+}
+
+// DeleteDevice revokes the trust of a device.
+func (r *UserPostgresRepository) DeleteDevice(ctx context.Context, userID int, fingerprint string) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM user_devices WHERE user_id = $1 AND fingerprint = $2`, userID, fingerprint)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrDeviceNotFound
+	}
+	return nil
+}
+
+// CreateLoginChallenge stores a login challenge, deleting the user's expired ones.
+func (r *UserPostgresRepository) CreateLoginChallenge(ctx context.Context, c *domain.LoginChallenge) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM login_challenges WHERE user_id = $1 AND expires_at <= NOW()`, c.UserID); err != nil {
+			return err
+		}
+		return tx.QueryRow(ctx, `
+			INSERT INTO login_challenges (id, user_id, fingerprint, user_agent, code_hash, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, NOW())
+			RETURNING created_at`,
+			c.ID, c.UserID, c.Fingerprint, c.UserAgent, c.CodeHash, c.ExpiresAt,
+		).Scan(&c.CreatedAt)
+	})
+}
+
+// UseLoginChallengeAttempt counts an attempt at an unexpired challenge and returns it.
+func (r *UserPostgresRepository) UseLoginChallengeAttempt(ctx context.Context, id string, maxAttempts int) (*domain.LoginChallenge, error) {
+	query := `UPDATE login_challenges SET attempts = attempts + 1
+		WHERE id = $1 AND attempts < $2 AND expires_at > NOW()
+		RETURNING ` + loginChallengeColumns
+	c := &domain.LoginChallenge{}
+	err := r.pool.QueryRow(ctx, query, id, maxAttempts).Scan(&c.ID, &c.UserID, &c.Fingerprint, &c.UserAgent,
+		&c.CodeHash, &c.Attempts, &c.ExpiresAt, &c.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil // unknown, expired or used up
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteLoginChallenge deletes a challenge, reporting whether it still existed.
+func (r *UserPostgresRepository) DeleteLoginChallenge(ctx context.Context, id string) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM login_challenges WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// Anonymize overwrites a user's personal data and marks the user as erased.
+func (r *UserPostgresRepository) Anonymize(ctx context.Context, user *domain.User) error {
+	email, emailHash, err := r.sealEmail(user.Email)
+	if err != nil {
+		return err
+	}
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		query := `UPDATE users SET username = $1, email = $2, email_hash = $3, password_hash = $4, erased_at = $5,
+				first_name = NULL, last_name = NULL, phone = NULL, locale = NULL, avatar_key = NULL, updated_at = NOW()
+			WHERE id = $6 AND erased_at IS NULL`
+		result, err := tx.Exec(ctx, query, user.Username, email, emailHash, user.PasswordHash, user.ErasedAt, user.ID)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return domain.ErrUserErased
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM notification_contacts WHERE user_id = $1`, user.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM user_preferences WHERE user_id = $1`, user.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM login_events WHERE user_id = $1`, user.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM user_devices WHERE user_id = $1`, user.ID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `DELETE FROM login_challenges WHERE user_id = $1`, user.ID)
+		return err
+	})
+}
+
+// RotateEncryption re-encrypts every email not under the primary key.
+func (r *UserPostgresRepository) RotateEncryption(ctx context.Context) (int, error) {
+	query := `SELECT id, email FROM users WHERE email_hash IS NULL OR email NOT LIKE $1`
+	rows, err := r.pool.Query(ctx, query, "enc:v1:"+r.keys.PrimaryKeyID()+":%")
+	if err != nil {
+		return 0, err
+	}
+	type pending struct {
+		id    int
+		email string
+	}
+	var stale []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.email); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		stale = append(stale, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+	for _, p := range stale {
+		plaintext, err := r.keys.Decrypt(p.email)
+		if err != nil {
+			return rotated, fmt.Errorf("failed to decrypt email of user %d: %w", p.id, err)
+		}
+		email, err := r.keys.Rotate(p.email)
+		if err != nil {
+			return rotated, fmt.Errorf("failed to rotate email of user %d: %w", p.id, err)
+		}
+		result, err := r.pool.Exec(ctx,
+			`UPDATE users SET email = $1, email_hash = $2 WHERE id = $3 AND email = $4`,
+			email, r.emailIndex(plaintext), p.id, p.email,
+		)
+		if err != nil {
+			return rotated, fmt.Errorf("failed to store rotated email of user %d: %w", p.id, err)
+		}
+		rotated += int(result.RowsAffected())
+	}
+	return rotated, nil
+}
+
 // Delete deletes a user by ID.
-func (r *UserPostgresRepository) Delete(id int) error {
+func (r *UserPostgresRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM users WHERE id = $1`
-	result, err := r.pool.Exec(context.Background(), query, id)
+	result, err := r.pool.Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
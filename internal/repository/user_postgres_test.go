@@ -1,12 +1,15 @@
 package repository
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/crypto"
 )
 
 // getTestPool returns a pgxpool.Pool for testing, using the DB_URL env var or a default.
@@ -26,9 +29,20 @@ func getTestPool(t *testing.T) *pgxpool.Pool {
 	return pool
 }
 
+// getTestKeyring returns a fixed field encryption keyring for tests.
+func getTestKeyring(t *testing.T) *crypto.Keyring {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	keys, err := crypto.NewKeyring("test", map[string][]byte{"test": key}, key)
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	return keys
+}
+
 func TestUserPostgresRepository_CreateAndGet(t *testing.T) {
+	ctx := context.Background()
 	pool := getTestPool(t)
-	repo := NewUserPostgresRepository(pool)
+	repo := NewUserPostgresRepository(pool, getTestKeyring(t))
 	defer func() {
 		// Clean up test user
 		pool.Exec(context.Background(), "DELETE FROM users WHERE username = 'testuser'")
@@ -43,7 +57,7 @@ func TestUserPostgresRepository_CreateAndGet(t *testing.T) {
 	}
 
 	// Test Create
-	err := repo.Create(user)
+	err := repo.Create(ctx, user)
 	if err != nil {
 		t.Fatalf("Create failed: %v", err)
 	}
@@ -51,8 +65,17 @@ func TestUserPostgresRepository_CreateAndGet(t *testing.T) {
 		t.Error("expected user ID to be set")
 	}
 
+	// The email must not be stored in plaintext
+	var storedEmail string
+	if err := pool.QueryRow(context.Background(), "SELECT email FROM users WHERE id = $1", user.ID).Scan(&storedEmail); err != nil {
+		t.Fatalf("failed to read stored email: %v", err)
+	}
+	if !crypto.IsEncrypted(storedEmail) {
+		t.Errorf("expected stored email to be encrypted, got %q", storedEmail)
+	}
+
 	// Test GetByID
-	got, err := repo.GetByID(user.ID)
+	got, err := repo.GetByID(ctx, user.ID)
 	if err != nil {
 		t.Fatalf("GetByID failed: %v", err)
 	}
@@ -61,7 +84,7 @@ func TestUserPostgresRepository_CreateAndGet(t *testing.T) {
 	}
 
 	// Test GetByUsername
-	got, err = repo.GetByUsername("testuser")
+	got, err = repo.GetByUsername(ctx, "testuser")
 	if err != nil {
 		t.Fatalf("GetByUsername failed: %v", err)
 	}
@@ -70,7 +93,7 @@ func TestUserPostgresRepository_CreateAndGet(t *testing.T) {
 	}
 
 	// Test GetByEmail
-	got, err = repo.GetByEmail("testuser@example.com")
+	got, err = repo.GetByEmail(ctx, "testuser@example.com")
 	if err != nil {
 		t.Fatalf("GetByEmail failed: %v", err)
 	}
@@ -80,8 +103,9 @@ func TestUserPostgresRepository_CreateAndGet(t *testing.T) {
 }
 
 func TestUserPostgresRepository_UpdateDeleteList(t *testing.T) {
+	ctx := context.Background()
 	pool := getTestPool(t)
-	repo := NewUserPostgresRepository(pool)
+	repo := NewUserPostgresRepository(pool, getTestKeyring(t))
 	defer func() {
 		pool.Exec(context.Background(), "DELETE FROM users WHERE username LIKE 'testuser%' OR username = 'updateduser'")
 		pool.Close()
@@ -100,10 +124,10 @@ func TestUserPostgresRepository_UpdateDeleteList(t *testing.T) {
 		PasswordHash: "hash2",
 		Role:         "user",
 	}
-	if err := repo.Create(user1); err != nil {
+	if err := repo.Create(ctx, user1); err != nil {
 		t.Fatalf("Create user1 failed: %v", err)
 	}
-	if err := repo.Create(user2); err != nil {
+	if err := repo.Create(ctx, user2); err != nil {
 		t.Fatalf("Create user2 failed: %v", err)
 	}
 
@@ -112,19 +136,86 @@ func TestUserPostgresRepository_UpdateDeleteList(t *testing.T) {
 	user1.Email = "updateduser@example.com"
 	user1.PasswordHash = "newhash"
 	user1.Role = "admin"
-	if err := repo.Update(user1); err != nil {
+	user1.FirstName = "Ada"
+	user1.Phone = "+905551234567"
+	user1.Locale = "tr-TR"
+	if err := repo.Update(ctx, user1); err != nil {
 		t.Fatalf("Update failed: %v", err)
 	}
-	got, err := repo.GetByID(user1.ID)
+	got, err := repo.GetByID(ctx, user1.ID)
 	if err != nil {
 		t.Fatalf("GetByID after update failed: %v", err)
 	}
 	if got.Username != "updateduser" || got.Role != "admin" {
 		t.Errorf("Update: got %+v, want username=updateduser, role=admin", got)
 	}
+	if got.FirstName != "Ada" || got.LastName != "" || got.Phone != "+905551234567" || got.Locale != "tr-TR" {
+		t.Errorf("Update: got profile %+v", got)
+	}
+
+	// Test RecordLoginEvent and ListLoginEvents
+	for _, failure := range []string{domain.LoginFailureWrongPassword, ""} {
+		event := &domain.LoginEvent{UserID: &user1.ID, Username: user1.Username, IP: "10.0.0.1", Success: failure == "", FailureReason: failure}
+		if err := repo.RecordLoginEvent(ctx, event); err != nil {
+			t.Fatalf("RecordLoginEvent failed: %v", err)
+		}
+	}
+	events, err := repo.ListLoginEvents(ctx, user1.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("ListLoginEvents failed: %v", err)
+	}
+	if len(events) != 2 || !events[0].Success || events[1].FailureReason != domain.LoginFailureWrongPassword {
+		t.Errorf("ListLoginEvents: got %+v, want the success first", events)
+	}
+
+	// Test TrustDevice, HasDevices and DeleteDevice
+	fingerprint := domain.LoginClient{UserAgent: "test-agent"}.Fingerprint()
+	if has, err := repo.HasDevices(ctx, user1.ID); err != nil || has {
+		t.Fatalf("HasDevices before trust: got %v, %v; want false", has, err)
+	}
+	if err := repo.TrustDevice(ctx, user1.ID, fingerprint, "test-agent"); err != nil {
+		t.Fatalf("TrustDevice failed: %v", err)
+	}
+	if d, err := repo.GetDevice(ctx, user1.ID, fingerprint); err != nil || d == nil || d.UserAgent != "test-agent" {
+		t.Errorf("GetDevice: got %+v, %v", d, err)
+	}
+	if err := repo.DeleteDevice(ctx, user1.ID, fingerprint); err != nil {
+		t.Fatalf("DeleteDevice failed: %v", err)
+	}
+	if err := repo.DeleteDevice(ctx, user1.ID, fingerprint); err != domain.ErrDeviceNotFound {
+		t.Errorf("DeleteDevice twice: got %v, want ErrDeviceNotFound", err)
+	}
+
+	// Test login challenges run out of attempts
+	challenge := &domain.LoginChallenge{
+		ID: "6f1c1d4e-2b7a-4c55-9d0e-8a3f2b1c0d9e", UserID: user1.ID, Fingerprint: fingerprint,
+		CodeHash: domain.HashLoginCode("6f1c1d4e-2b7a-4c55-9d0e-8a3f2b1c0d9e", "123456"), ExpiresAt: time.Now().Add(time.Minute),
+	}
+	if err := repo.CreateLoginChallenge(ctx, challenge); err != nil {
+		t.Fatalf("CreateLoginChallenge failed: %v", err)
+	}
+	for i := 1; i <= 2; i++ {
+		if c, err := repo.UseLoginChallengeAttempt(ctx, challenge.ID, 2); err != nil || c == nil || c.Attempts != i {
+			t.Fatalf("UseLoginChallengeAttempt %d: got %+v, %v", i, c, err)
+		}
+	}
+	if c, err := repo.UseLoginChallengeAttempt(ctx, challenge.ID, 2); err != nil || c != nil {
+		t.Errorf("UseLoginChallengeAttempt past the limit: got %+v, %v; want nil", c, err)
+	}
+	if deleted, err := repo.DeleteLoginChallenge(ctx, challenge.ID); err != nil || !deleted {
+		t.Errorf("DeleteLoginChallenge: got %v, %v; want true", deleted, err)
+	}
+
+	// Test UpdateAvatar
+	if err := repo.UpdateAvatar(ctx, user1.ID, "avatars/1/a.png"); err != nil {
+		t.Fatalf("UpdateAvatar failed: %v", err)
+	}
+	if got, _ := repo.GetByID(ctx, user1.ID); got == nil || got.AvatarKey != "avatars/1/a.png" {
+		t.Errorf("UpdateAvatar: got %+v, want avatar key avatars/1/a.png", got)
+	}
 
 	// Test List
-	users, err := repo.List()
+	users, err := repo.List(ctx, domain.UserFilter{Limit: 1000})
 	if err != nil {
 		t.Fatalf("List failed: %v", err)
 	}
@@ -142,14 +233,14 @@ func TestUserPostgresRepository_UpdateDeleteList(t *testing.T) {
 	}
 
 	// Test Delete
-	if err := repo.Delete(user1.ID); err != nil {
+	if err := repo.Delete(ctx, user1.ID); err != nil {
 		t.Fatalf("Delete user1 failed: %v", err)
 	}
-	if err := repo.Delete(user2.ID); err != nil {
+	if err := repo.Delete(ctx, user2.ID); err != nil {
 		t.Fatalf("Delete user2 failed: %v", err)
 	}
 	// Should not find after delete
-	got, err = repo.GetByID(user1.ID)
+	got, err = repo.GetByID(ctx, user1.ID)
 	if err != nil {
 		t.Fatalf("GetByID after delete failed: %v", err)
 	}
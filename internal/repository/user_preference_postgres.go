@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UserPreferencePostgresRepository implements domain.UserPreferenceRepository using PostgreSQL.
+type UserPreferencePostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewUserPreferencePostgresRepository creates a new UserPreferencePostgresRepository.
+func NewUserPreferencePostgresRepository(pool *pgxpool.Pool) *UserPreferencePostgresRepository {
+	return &UserPreferencePostgresRepository{pool: pool}
+}
+
+// List retrieves the preferences a user has set by key.
+func (r *UserPreferencePostgresRepository) List(ctx context.Context, userID int) (map[string]json.RawMessage, error) {
+	rows, err := r.pool.Query(ctx, `SELECT key, value FROM user_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prefs := make(map[string]json.RawMessage)
+	for rows.Next() {
+		var key string
+		var value []byte
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		prefs[key] = value
+	}
+	return prefs, rows.Err()
+}
+
+// Update upserts set and deletes the keys in remove in one transaction.
+func (r *UserPreferencePostgresRepository) Update(ctx context.Context, userID int, set map[string]json.RawMessage, remove []string) error {
+	query := `
+		INSERT INTO user_preferences (user_id, key, value, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id, key) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()
+	`
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		for key, value := range set {
+			if _, err := tx.Exec(ctx, query, userID, key, []byte(value)); err != nil {
+				return err
+			}
+		}
+		if len(remove) == 0 {
+			return nil
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM user_preferences WHERE user_id = $1 AND key = ANY($2)`, userID, remove)
+		return err
+	})
+}
+
+// Delete removes a preference, reporting whether it was set.
+func (r *UserPreferencePostgresRepository) Delete(ctx context.Context, userID int, key string) (bool, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM user_preferences WHERE user_id = $1 AND key = $2`, userID, key)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
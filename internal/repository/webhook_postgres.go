@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// webhookEventColumns is the column list shared by every webhook event SELECT, but for the payload.
+const webhookEventColumns = `id, provider, event_id, type, status, transaction_id, COALESCE(error, ''), received_at, processed_at`
+
+// WebhookPostgresRepository implements domain.WebhookRepository using PostgreSQL.
+type WebhookPostgresRepository struct {
+	db DBTX
+}
+
+// NewWebhookPostgresRepository creates a new WebhookPostgresRepository.
+func NewWebhookPostgresRepository(pool *pgxpool.Pool) *WebhookPostgresRepository {
+	return &WebhookPostgresRepository{db: pool}
+}
+
+// scanWebhookEvent scans a row selected with webhookEventColumns.
+func scanWebhookEvent(row pgx.Row, withPayload bool) (*domain.WebhookEvent, error) {
+	e := &domain.WebhookEvent{}
+	dest := []any{&e.ID, &e.Provider, &e.EventID, &e.Type, &e.Status, &e.TransactionID, &e.Error, &e.ReceivedAt, &e.ProcessedAt}
+	var payload string
+	if withPayload {
+		dest = append(dest, &payload)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if withPayload {
+		e.Payload = []byte(payload)
+	}
+	return e, nil
+}
+
+// Create stores a received event unless it was already delivered.
+func (r *WebhookPostgresRepository) Create(ctx context.Context, e *domain.WebhookEvent) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO webhook_events (provider, event_id, type, payload, status, received_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING id, received_at`,
+		e.Provider, e.EventID, e.Type, string(e.Payload), e.Status,
+	).Scan(&e.ID, &e.ReceivedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return domain.ErrWebhookEventExists
+	}
+	return err
+}
+
+// GetByID fetches an event by ID with its payload.
+func (r *WebhookPostgresRepository) GetByID(ctx context.Context, id int) (*domain.WebhookEvent, error) {
+	e, err := scanWebhookEvent(r.db.QueryRow(ctx, `SELECT `+webhookEventColumns+`, payload
+		FROM webhook_events WHERE id = $1`, id), true)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	return e, err
+}
+
+// GetByEventID fetches a provider's event by its ID, without the payload.
+func (r *WebhookPostgresRepository) GetByEventID(ctx context.Context, provider, eventID string) (*domain.WebhookEvent, error) {
+	e, err := scanWebhookEvent(r.db.QueryRow(ctx, `SELECT `+webhookEventColumns+`
+		FROM webhook_events WHERE provider = $1 AND event_id = $2`, provider, eventID), false)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil // not found
+	}
+	return e, err
+}
+
+// List fetches a page of events, newest first, without their payloads.
+func (r *WebhookPostgresRepository) List(ctx context.Context, provider, status string, limit, offset int) ([]*domain.WebhookEvent, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+webhookEventColumns+` FROM webhook_events
+		WHERE ($1 = '' OR provider = $1) AND ($2 = '' OR status = $2)
+		ORDER BY received_at DESC, id DESC LIMIT $3 OFFSET $4`, provider, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.WebhookEvent
+	for rows.Next() {
+		e, err := scanWebhookEvent(rows, false)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// Update stores the outcome of an event, stamping processed_at.
+func (r *WebhookPostgresRepository) Update(ctx context.Context, e *domain.WebhookEvent) error {
+	return r.db.QueryRow(ctx, `
+		UPDATE webhook_events SET status = $2, transaction_id = $3, error = NULLIF($4, ''), processed_at = NOW()
+		WHERE id = $1
+		RETURNING processed_at`,
+		e.ID, e.Status, e.TransactionID, e.Error,
+	).Scan(&e.ProcessedAt)
+}
@@ -0,0 +1,126 @@
+package seed
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// failedShare is the share of debits and transfers recorded as failed
+const failedShare = 0.02
+
+// history is the generated transaction history of the seeded users
+type history struct {
+	transactions []*domain.Transaction // oldest first
+	balances     map[int]float64       // each user's balance after all transactions
+}
+
+// generateHistory generates opts.Months of transactions up to now.
+func generateHistory(rng *rand.Rand, userIDs []int, opts Options, now time.Time) history {
+	start := now.AddDate(0, -opts.Months, 0)
+	span := now.Sub(start)
+
+	var candidates []*domain.Transaction
+	for _, userID := range userIDs {
+		payday := time.Duration(rng.Intn(28*24)) * time.Hour
+		salary := round2(1500 + rng.Float64()*4500)
+		for month := 0; month < opts.Months; month++ {
+			at := start.AddDate(0, month, 0).Add(payday)
+			if at.Before(now) {
+				candidates = append(candidates, &domain.Transaction{ToUserID: intPtr(userID), Amount: salary, Type: "credit", CreatedAt: at})
+			}
+		}
+
+		// Spending varies by ±30% between users around the configured volume
+		spending := float64(opts.TransactionsPerMonth-1) * float64(opts.Months) * (0.7 + rng.Float64()*0.6)
+		for range int(math.Round(spending)) {
+			at := start.Add(time.Duration(rng.Int63n(int64(span))))
+			if rng.Float64() < 0.7 {
+				candidates = append(candidates, &domain.Transaction{FromUserID: intPtr(userID), Amount: round2(5 + rng.ExpFloat64()*40), Type: "debit", CreatedAt: at})
+				continue
+			}
+			toID := userIDs[rng.Intn(len(userIDs))]
+			if toID == userID {
+				continue
+			}
+			candidates = append(candidates, &domain.Transaction{FromUserID: intPtr(userID), ToUserID: intPtr(toID), Amount: round2(10 + rng.ExpFloat64()*100), Type: "transfer", CreatedAt: at})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].CreatedAt.Before(candidates[j].CreatedAt) })
+
+	h := history{balances: make(map[int]float64, len(userIDs))}
+	for _, userID := range userIDs {
+		h.balances[userID] = 0
+	}
+	for _, tx := range candidates {
+		tx.Status = "completed"
+		if tx.FromUserID != nil {
+			if tx.Amount > h.balances[*tx.FromUserID] {
+				continue
+			}
+			if rng.Float64() < failedShare {
+				tx.Status = "failed"
+				h.transactions = append(h.transactions, tx)
+				continue
+			}
+			h.balances[*tx.FromUserID] = round2(h.balances[*tx.FromUserID] - tx.Amount)
+		}
+		if tx.ToUserID != nil {
+			h.balances[*tx.ToUserID] = round2(h.balances[*tx.ToUserID] + tx.Amount)
+		}
+		h.transactions = append(h.transactions, tx)
+	}
+	return h
+}
+
+// generateScheduled generates count upcoming recurring transactions for a user.
+func generateScheduled(rng *rand.Rand, userID int, userIDs []int, count int, now time.Time) []*domain.ScheduledTransaction {
+	var scheduled []*domain.ScheduledTransaction
+	for i := range count {
+		at := now.Add(time.Duration(1+rng.Intn(30*24)) * time.Hour).UTC()
+		st := &domain.ScheduledTransaction{
+			UserID:     userID,
+			Status:     "pending",
+			ScheduleAt: at,
+			NextRunAt:  &at,
+			Recurring:  true,
+		}
+		switch i % 3 {
+		case 0:
+			toID := userIDs[rng.Intn(len(userIDs))]
+			if toID == userID {
+				continue
+			}
+			st.Type, st.ToUserID, st.Recurrence, st.Description = "transfer", intPtr(toID), "monthly", "Rent"
+			st.Amount = round2(400 + rng.Float64()*1200)
+		case 1:
+			st.Type, st.Recurrence, st.Description = "credit", "monthly", "Savings top-up"
+			st.Amount = round2(50 + rng.Float64()*450)
+		case 2:
+			st.Type, st.Recurrence, st.Description = "debit", "weekly", "Subscription"
+			st.Amount = round2(5 + rng.Float64()*25)
+		}
+		scheduled = append(scheduled, st)
+	}
+	return scheduled
+}
+
+// generateLimitRules generates a per-transaction cap and a larger daily total
+func generateLimitRules(rng *rand.Rand, userID int) []domain.TransactionLimitRule {
+	perTransaction := float64(1000 * (1 + rng.Intn(5)))
+	return []domain.TransactionLimitRule{
+		{UserID: userID, RuleType: domain.RuleMaxPerTransaction, LimitAmount: perTransaction, Active: true},
+		{UserID: userID, RuleType: domain.RuleDailyTotal, LimitAmount: 2 * perTransaction, Active: true},
+	}
+}
+
+func round2(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func intPtr(v int) *int {
+	return &v
+}
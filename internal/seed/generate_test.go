@@ -0,0 +1,81 @@
+package seed
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateHistory_NeverOverdraws(t *testing.T) {
+	opts := DefaultOptions()
+	userIDs := []int{1, 2, 3, 4, 5}
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	h := generateHistory(rand.New(rand.NewSource(7)), userIDs, opts, now)
+
+	require.NotEmpty(t, h.transactions)
+	balances := make(map[int]float64)
+	start := now.AddDate(0, -opts.Months, 0)
+	for i, tx := range h.transactions {
+		assert.False(t, tx.CreatedAt.Before(start) || !tx.CreatedAt.Before(now), "transaction outside the history window")
+		if i > 0 {
+			assert.False(t, tx.CreatedAt.Before(h.transactions[i-1].CreatedAt), "transactions are oldest first")
+		}
+		if tx.Status != "completed" {
+			continue
+		}
+		if tx.FromUserID != nil {
+			balances[*tx.FromUserID] = round2(balances[*tx.FromUserID] - tx.Amount)
+			assert.GreaterOrEqual(t, balances[*tx.FromUserID], 0.0, "user %d overdrawn", *tx.FromUserID)
+		}
+		if tx.ToUserID != nil {
+			balances[*tx.ToUserID] = round2(balances[*tx.ToUserID] + tx.Amount)
+		}
+	}
+	for _, userID := range userIDs {
+		assert.InDelta(t, balances[userID], h.balances[userID], 0.001)
+	}
+}
+
+func TestGenerateHistory_PaysSalaryEveryMonth(t *testing.T) {
+	opts := DefaultOptions()
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	h := generateHistory(rand.New(rand.NewSource(7)), []int{1, 2}, opts, now)
+
+	credits := make(map[int]int)
+	for _, tx := range h.transactions {
+		if tx.Type == "credit" {
+			credits[*tx.ToUserID]++
+		}
+	}
+	// The last salary may fall after now
+	for _, userID := range []int{1, 2} {
+		assert.GreaterOrEqual(t, credits[userID], opts.Months-1)
+		assert.LessOrEqual(t, credits[userID], opts.Months)
+	}
+}
+
+func TestGenerateHistory_SameSeedSameData(t *testing.T) {
+	opts := DefaultOptions()
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	first := generateHistory(rand.New(rand.NewSource(3)), []int{1, 2, 3}, opts, now)
+	second := generateHistory(rand.New(rand.NewSource(3)), []int{1, 2, 3}, opts, now)
+
+	assert.Equal(t, first, second)
+}
+
+func TestOptions_Validate(t *testing.T) {
+	assert.NoError(t, DefaultOptions().Validate())
+
+	opts := DefaultOptions()
+	opts.Users = 1
+	opts.LimitRuleShare = 2
+	err := opts.Validate()
+	assert.ErrorContains(t, err, "users must be at least 2")
+	assert.ErrorContains(t, err, "limit rule share must be between 0 and 1")
+}
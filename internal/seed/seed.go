@@ -0,0 +1,191 @@
+// Package seed fills a database with realistic demo data.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/password"
+)
+
+// Options sets how much data is generated
+type Options struct {
+	Prefix               string  // seeded usernames are <prefix>_user_00001 and so on, plus <prefix>_admin
+	Password             string  // password of every seeded user
+	Users                int     // regular users to create
+	Months               int     // months of transaction history
+	TransactionsPerMonth int     // average transactions per user a month, including the salary
+	ScheduledPerUser     int     // upcoming scheduled transactions per user
+	LimitRuleShare       float64 // share of users given transaction limit rules, 0-1
+	RandomSeed           int64   // the same seed and options generate the same data
+}
+
+// DefaultOptions returns a small data set suitable for local development
+func DefaultOptions() Options {
+	return Options{
+		Prefix:               "demo",
+		Password:             "DemoPassw0rd!",
+		Users:                50,
+		Months:               6,
+		TransactionsPerMonth: 20,
+		ScheduledPerUser:     2,
+		LimitRuleShare:       0.3,
+		RandomSeed:           1,
+	}
+}
+
+// Validate reports options that cannot produce a data set
+func (o Options) Validate() error {
+	var errs []error
+	check := func(ok bool, msg string) {
+		if !ok {
+			errs = append(errs, errors.New(msg))
+		}
+	}
+	check(o.Prefix != "" && len(o.Prefix) <= 30, "prefix must be 1-30 characters")
+	check(o.Password != "", "password is required")
+	check(o.Users >= 2, "users must be at least 2")
+	check(o.Months > 0, "months must be positive")
+	check(o.TransactionsPerMonth > 0, "transactions per month must be positive")
+	check(o.ScheduledPerUser >= 0, "scheduled per user must not be negative")
+	check(o.LimitRuleShare >= 0 && o.LimitRuleShare <= 1, "limit rule share must be between 0 and 1")
+	return errors.Join(errs...)
+}
+
+// Summary counts what a run created
+type Summary struct {
+	Users                 int
+	Transactions          int
+	ScheduledTransactions int
+	LimitRules            int
+}
+
+// Seeder creates users, balances, transaction history, scheduled transactions and limit rules
+type Seeder struct {
+	users      domain.UserRepository
+	seeds      domain.SeedRepository
+	partitions domain.TransactionPartitionRepository
+	scheduled  domain.ScheduledTransactionRepository
+	limits     domain.TransactionLimitRepository
+	hasher     password.Hasher
+	now        func() time.Time
+}
+
+// NewSeeder creates a new Seeder
+func NewSeeder(
+	users domain.UserRepository,
+	seeds domain.SeedRepository,
+	partitions domain.TransactionPartitionRepository,
+	scheduled domain.ScheduledTransactionRepository,
+	limits domain.TransactionLimitRepository,
+	hasher password.Hasher,
+) *Seeder {
+	return &Seeder{
+		users:      users,
+		seeds:      seeds,
+		partitions: partitions,
+		scheduled:  scheduled,
+		limits:     limits,
+		hasher:     hasher,
+		now:        time.Now,
+	}
+}
+
+// Run generates and stores a data set.
+func (s *Seeder) Run(ctx context.Context, opts Options) (*Summary, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	admin := opts.Prefix + "_admin"
+	if existing, err := s.users.GetByUsername(ctx, admin); err != nil {
+		return nil, fmt.Errorf("failed to look up existing seed data: %w", err)
+	} else if existing != nil {
+		return nil, fmt.Errorf("data with prefix %q was already seeded", opts.Prefix)
+	}
+
+	rng := rand.New(rand.NewSource(opts.RandomSeed))
+	now := s.now().UTC()
+	start := now.AddDate(0, -opts.Months, 0)
+	summary := &Summary{}
+
+	// Every user shares the password, so it is hashed once
+	passwordHash, err := s.hasher.Hash(opts.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	adminID, err := s.createUser(ctx, admin, "admin", passwordHash, start)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.seeds.SetBalance(ctx, adminID, 0); err != nil {
+		return nil, fmt.Errorf("failed to set balance of user %d: %w", adminID, err)
+	}
+	userIDs := make([]int, 0, opts.Users)
+	for i := 1; i <= opts.Users; i++ {
+		signedUp := start.Add(-time.Duration(rng.Intn(30*24)) * time.Hour)
+		userID, err := s.createUser(ctx, fmt.Sprintf("%s_user_%05d", opts.Prefix, i), "user", passwordHash, signedUp)
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	summary.Users = len(userIDs) + 1
+	log.Info().Int("users", summary.Users).Msg("Seeded users")
+
+	// History months without a partition would land in the default partition
+	for month := start; month.Before(now); month = month.AddDate(0, 1, 0) {
+		if err := s.partitions.CreatePartition(ctx, domain.NewTransactionPartition(month)); err != nil {
+			return nil, fmt.Errorf("failed to create transactions partition: %w", err)
+		}
+	}
+	h := generateHistory(rng, userIDs, opts, now)
+	if err := s.seeds.InsertTransactions(ctx, h.transactions); err != nil {
+		return nil, fmt.Errorf("failed to insert transactions: %w", err)
+	}
+	for _, userID := range userIDs {
+		if err := s.seeds.SetBalance(ctx, userID, h.balances[userID]); err != nil {
+			return nil, fmt.Errorf("failed to set balance of user %d: %w", userID, err)
+		}
+	}
+	summary.Transactions = len(h.transactions)
+	log.Info().Int("transactions", summary.Transactions).Int("months", opts.Months).Msg("Seeded transaction history")
+
+	for _, userID := range userIDs {
+		for _, st := range generateScheduled(rng, userID, userIDs, opts.ScheduledPerUser, now) {
+			if err := s.scheduled.Create(ctx, st); err != nil {
+				return nil, fmt.Errorf("failed to create scheduled transaction: %w", err)
+			}
+			summary.ScheduledTransactions++
+		}
+		if rng.Float64() >= opts.LimitRuleShare {
+			continue
+		}
+		for _, rule := range generateLimitRules(rng, userID) {
+			if _, err := s.limits.AddRule(ctx, rule); err != nil {
+				return nil, fmt.Errorf("failed to add limit rule: %w", err)
+			}
+			summary.LimitRules++
+		}
+	}
+	log.Info().Int("scheduled_transactions", summary.ScheduledTransactions).Int("limit_rules", summary.LimitRules).
+		Msg("Seeded scheduled transactions and limit rules")
+	return summary, nil
+}
+
+// createUser creates a user with an example.com address who signed up at signedUp
+func (s *Seeder) createUser(ctx context.Context, username, role, passwordHash string, signedUp time.Time) (int, error) {
+	user := &domain.User{Username: username, Email: username + "@example.com", PasswordHash: passwordHash, Role: role}
+	if err := s.users.Create(ctx, user); err != nil {
+		return 0, fmt.Errorf("failed to create user %s: %w", username, err)
+	}
+	if err := s.seeds.BackdateUser(ctx, user.ID, signedUp); err != nil {
+		return 0, fmt.Errorf("failed to backdate user %s: %w", username, err)
+	}
+	return user.ID, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// AdminIPAllowlistServiceImpl implements domain.AdminIPAllowlistService.
+type AdminIPAllowlistServiceImpl struct {
+	repo  domain.AdminIPAllowlistRepository
+	users domain.UserRepository
+	audit domain.AuditLogService
+}
+
+// NewAdminIPAllowlistService creates a new AdminIPAllowlistServiceImpl.
+func NewAdminIPAllowlistService(repo domain.AdminIPAllowlistRepository, users domain.UserRepository, audit domain.AuditLogService) *AdminIPAllowlistServiceImpl {
+	return &AdminIPAllowlistServiceImpl{repo: repo, users: users, audit: audit}
+}
+
+// List returns an admin's allowlist entries, oldest first.
+func (s *AdminIPAllowlistServiceImpl) List(ctx context.Context, userID int) ([]*domain.AdminIPRule, error) {
+	return s.repo.List(ctx, userID)
+}
+
+// Add adds a range to the allowlist of rule.UserID, who must be an admin.
+func (s *AdminIPAllowlistServiceImpl) Add(ctx context.Context, actorID int, rule *domain.AdminIPRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	user, err := s.users.GetByID(ctx, rule.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return domain.ErrAdminNotFound
+	}
+	if user.Role != "admin" {
+		return domain.ErrNotAdmin
+	}
+	rules, err := s.repo.List(ctx, rule.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to list allowlist: %w", err)
+	}
+	if len(rules) >= domain.MaxAdminIPRules {
+		return domain.ErrTooManyAdminIPRules
+	}
+
+	rule.CreatedBy = actorID
+	if err := s.repo.Add(ctx, rule); err != nil {
+		return err
+	}
+	if err := s.audit.Record(ctx, &actorID, "user", rule.UserID, "add_ip_rule", "cidr="+rule.CIDR); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("user_id", rule.UserID).Int("actor_id", actorID).Msg("Failed to audit allowlist change")
+	}
+	return nil
+}
+
+// Remove removes an entry from an admin's allowlist.
+func (s *AdminIPAllowlistServiceImpl) Remove(ctx context.Context, actorID, userID, id int) error {
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.audit.Record(ctx, &actorID, "user", userID, "remove_ip_rule", "rule_id="+strconv.Itoa(id)); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("user_id", userID).Int("actor_id", actorID).Msg("Failed to audit allowlist change")
+	}
+	return nil
+}
+
+// Allowed reports whether an admin may use admin operations from ip.
+func (s *AdminIPAllowlistServiceImpl) Allowed(ctx context.Context, userID int, ip, path string) (bool, error) {
+	rules, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to list allowlist: %w", err)
+	}
+	if len(rules) == 0 {
+		return true, nil
+	}
+	if addr, err := netip.ParseAddr(ip); err == nil {
+		addr = addr.Unmap()
+		for _, rule := range rules {
+			if prefix, err := netip.ParsePrefix(rule.CIDR); err == nil && prefix.Contains(addr) {
+				return true, nil
+			}
+		}
+	}
+
+	log.Ctx(ctx).Warn().Int("user_id", userID).Str("ip", ip).Str("path", path).Msg("Admin request from an address outside the allowlist")
+	if err := s.audit.Record(ctx, &userID, "user", userID, "admin_ip_rejected", "ip="+ip+" path="+path); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("user_id", userID).Msg("Failed to audit rejected admin request")
+	}
+	return false, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// overviewWindows are the periods the admin overview summarizes transactions over
+var overviewWindows = []struct {
+	name string
+	span time.Duration
+}{
+	{"24h", 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+	{"30d", 30 * 24 * time.Hour},
+}
+
+// AdminOverviewServiceImpl implements domain.AdminOverviewService
+type AdminOverviewServiceImpl struct {
+	repo      domain.AdminOverviewRepository
+	processor domain.TransactionProcessor
+	now       func() time.Time
+}
+
+// NewAdminOverviewService creates a new AdminOverviewServiceImpl.
+func NewAdminOverviewService(repo domain.AdminOverviewRepository, processor domain.TransactionProcessor) *AdminOverviewServiceImpl {
+	return &AdminOverviewServiceImpl{repo: repo, processor: processor, now: time.Now}
+}
+
+// GetOverview computes the admin overview as of now
+func (s *AdminOverviewServiceImpl) GetOverview(ctx context.Context) (*domain.AdminOverview, error) {
+	now := s.now().UTC()
+	overview := &domain.AdminOverview{GeneratedAt: now}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	users, err := s.repo.UserCounts(ctx, midnight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+	overview.Users = *users
+
+	since := make([]time.Time, len(overviewWindows))
+	for i, w := range overviewWindows {
+		since[i] = now.Add(-w.span)
+	}
+	windows, err := s.repo.TransactionWindows(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize transactions: %w", err)
+	}
+	if len(windows) != len(overviewWindows) {
+		return nil, fmt.Errorf("expected %d transaction windows, got %d", len(overviewWindows), len(windows))
+	}
+	for i, w := range windows {
+		w.Window = overviewWindows[i].name
+		if w.Count > 0 {
+			w.FailureRate = float64(w.Failed) / float64(w.Count) * 100
+		}
+	}
+	overview.Transactions = windows
+
+	backlog, err := s.repo.ScheduledBacklog(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count scheduled backlog: %w", err)
+	}
+	overview.Scheduled = *backlog
+
+	stats := s.processor.GetStats()
+	overview.Queue = domain.QueueOverview{Depth: stats.QueueSize, ActiveWorkers: stats.ActiveWorkers}
+	return overview, nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// stubOverview implements domain.AdminOverviewRepository, answering with its
+// fields and recording the times it was asked about
+type stubOverview struct {
+	users   domain.UserOverview
+	windows []*domain.TransactionWindow
+	backlog domain.ScheduledBacklog
+	err     error
+
+	signupsSince time.Time
+	windowsSince []time.Time
+	backlogNow   time.Time
+}
+
+func (r *stubOverview) UserCounts(ctx context.Context, since time.Time) (*domain.UserOverview, error) {
+	r.signupsSince = since
+	users := r.users
+	return &users, nil
+}
+
+func (r *stubOverview) TransactionWindows(ctx context.Context, since []time.Time) ([]*domain.TransactionWindow, error) {
+	r.windowsSince = since
+	return r.windows, r.err
+}
+
+func (r *stubOverview) ScheduledBacklog(ctx context.Context, now time.Time) (*domain.ScheduledBacklog, error) {
+	r.backlogNow = now
+	backlog := r.backlog
+	return &backlog, nil
+}
+
+// idleProcessor implements the stats of domain.TransactionProcessor
+type idleProcessor struct {
+	domain.TransactionProcessor
+	stats domain.ProcessingStats
+}
+
+func (p idleProcessor) GetStats() *domain.ProcessingStats {
+	stats := p.stats
+	return &stats
+}
+
+func TestAdminOverviewServiceImpl_GetOverview(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 4, 15, 30, 0, 0, time.FixedZone("TRT", 3*60*60))
+	newService := func(repo *stubOverview) *AdminOverviewServiceImpl {
+		svc := NewAdminOverviewService(repo, idleProcessor{stats: domain.ProcessingStats{QueueSize: 12, ActiveWorkers: 4}})
+		svc.now = func() time.Time { return now }
+		return svc
+	}
+
+	t.Run("aggregates every section as of now", func(t *testing.T) {
+		repo := &stubOverview{
+			users: domain.UserOverview{Total: 40, SignupsToday: 3},
+			windows: []*domain.TransactionWindow{
+				{Count: 8, Completed: 6, Failed: 2, Volume: 600},
+				{Count: 0},
+				{Count: 200, Completed: 199, Failed: 1, Volume: 9000},
+			},
+			backlog: domain.ScheduledBacklog{Pending: 5, Overdue: 1, Retrying: 2},
+		}
+		overview, err := newService(repo).GetOverview(ctx)
+		require.NoError(t, err)
+
+		utcNow := now.UTC()
+		assert.Equal(t, utcNow, overview.GeneratedAt)
+		assert.Equal(t, time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), repo.signupsSince, "signups count from midnight UTC")
+		assert.Equal(t, []time.Time{utcNow.Add(-24 * time.Hour), utcNow.AddDate(0, 0, -7), utcNow.AddDate(0, 0, -30)}, repo.windowsSince)
+		assert.Equal(t, utcNow, repo.backlogNow)
+
+		assert.Equal(t, repo.users, overview.Users)
+		require.Len(t, overview.Transactions, 3)
+		assert.Equal(t, "24h", overview.Transactions[0].Window)
+		assert.Equal(t, 25.0, overview.Transactions[0].FailureRate)
+		assert.Equal(t, "7d", overview.Transactions[1].Window)
+		assert.Zero(t, overview.Transactions[1].FailureRate, "no transactions is no failures, not NaN")
+		assert.Equal(t, "30d", overview.Transactions[2].Window)
+		assert.Equal(t, 0.5, overview.Transactions[2].FailureRate)
+		assert.Equal(t, domain.QueueOverview{Depth: 12, ActiveWorkers: 4}, overview.Queue)
+		assert.Equal(t, repo.backlog, overview.Scheduled)
+	})
+
+	t.Run("a missing window fails the overview", func(t *testing.T) {
+		repo := &stubOverview{windows: []*domain.TransactionWindow{{}, {}}}
+		_, err := newService(repo).GetOverview(ctx)
+		assert.ErrorContains(t, err, "expected 3 transaction windows, got 2")
+	})
+
+	t.Run("a failed query fails the overview", func(t *testing.T) {
+		failure := errors.New("canceling statement due to statement timeout")
+		_, err := newService(&stubOverview{err: failure}).GetOverview(ctx)
+		assert.ErrorIs(t, err, failure)
+	})
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// AlertRuleServiceImpl implements domain.AlertRuleService
+type AlertRuleServiceImpl struct {
+	repo     domain.AlertRuleRepository
+	balances domain.BalanceRepository
+	notifier domain.Notifier
+}
+
+// NewAlertRuleService creates a new AlertRuleServiceImpl.
+func NewAlertRuleService(repo domain.AlertRuleRepository, balances domain.BalanceRepository, notifier domain.Notifier) *AlertRuleServiceImpl {
+	return &AlertRuleServiceImpl{repo: repo, balances: balances, notifier: notifier}
+}
+
+// CreateRule validates and creates an alert rule; new rules are active
+func (s *AlertRuleServiceImpl) CreateRule(ctx context.Context, rule *domain.AlertRule) error {
+	rule.Active = true
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return fmt.Errorf("failed to create alert rule: %w", err)
+	}
+	return nil
+}
+
+// ListRules retrieves all alert rules of a user
+func (s *AlertRuleServiceImpl) ListRules(ctx context.Context, userID int) ([]*domain.AlertRule, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// UpdateRule changes the threshold and active flag of one of the user's rules
+func (s *AlertRuleServiceImpl) UpdateRule(ctx context.Context, userID, id int, threshold float64, active bool) (*domain.AlertRule, error) {
+	rule, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+	if rule == nil || rule.UserID != userID {
+		return nil, domain.ErrAlertRuleNotFound
+	}
+
+	rule.Threshold = threshold
+	rule.Active = active
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update alert rule: %w", err)
+	}
+	return rule, nil
+}
+
+// DeleteRule deletes one of the user's rules
+func (s *AlertRuleServiceImpl) DeleteRule(ctx context.Context, userID, id int) error {
+	return s.repo.Delete(ctx, userID, id)
+}
+
+// CheckTransaction notifies the users whose alert rules a committed transaction triggers.
+func (s *AlertRuleServiceImpl) CheckTransaction(ctx context.Context, tx *domain.Transaction) {
+	var userIDs []int
+	for _, id := range []*int{tx.FromUserID, tx.ToUserID} {
+		if id != nil {
+			userIDs = append(userIDs, *id)
+		}
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+	rules, err := s.repo.ListActiveByUsers(ctx, userIDs)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("transaction_id", tx.ID).Msg("Failed to load alert rules")
+		return
+	}
+
+	var available *float64 // the payer's available balance, read once
+	for _, rule := range rules {
+		outgoing := tx.FromUserID != nil && rule.UserID == *tx.FromUserID
+		switch rule.Type {
+		case domain.AlertLargeTransaction:
+			if tx.Amount <= rule.Threshold {
+				continue
+			}
+			direction := "in"
+			if outgoing {
+				direction = "out"
+			}
+			s.fire(ctx, rule, map[string]string{
+				"type":           tx.Type,
+				"amount":         formatAmount(tx.Amount),
+				"transaction_id": strconv.Itoa(tx.ID),
+				"direction":      direction,
+			})
+		case domain.AlertLowBalance:
+			if !outgoing {
+				continue
+			}
+			if available == nil {
+				bal, err := s.balances.GetByUserID(ctx, rule.UserID)
+				if err != nil || bal == nil {
+					log.Ctx(ctx).Error().Err(err).Int("user_id", rule.UserID).Msg("Failed to get balance for low balance alert")
+					return
+				}
+				amount := bal.AvailableAmount()
+				available = &amount
+			}
+			if !rule.LowBalanceCrossed(*available+tx.Amount, *available) {
+				continue
+			}
+			s.fire(ctx, rule, map[string]string{"balance": formatAmount(*available)})
+		}
+	}
+}
+
+// fire sends the rule's alert with data plus the rule's threshold
+func (s *AlertRuleServiceImpl) fire(ctx context.Context, rule *domain.AlertRule, data map[string]string) {
+	metrics.AlertsTriggered.WithLabelValues(rule.Type).Inc()
+	data["threshold"] = formatAmount(rule.Threshold)
+	s.notifier.Notify(ctx, rule.UserID, rule.Event(), data)
+}
+
+// formatAmount formats an amount of money for a notification
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// AnalyticsServiceImpl implements domain.AnalyticsService
+type AnalyticsServiceImpl struct {
+	repo domain.AnalyticsRepository
+}
+
+// NewAnalyticsService creates a new AnalyticsServiceImpl
+func NewAnalyticsService(repo domain.AnalyticsRepository) *AnalyticsServiceImpl {
+	return &AnalyticsServiceImpl{repo: repo}
+}
+
+// GetUserAnalytics summarizes the user's completed transactions over the last months.
+func (s *AnalyticsServiceImpl) GetUserAnalytics(ctx context.Context, userID int, months int) (*domain.UserAnalytics, error) {
+	if months < 1 || months > domain.MaxAnalyticsMonths {
+		return nil, &domain.ValidationError{Msg: fmt.Sprintf("months must be between 1 and %d", domain.MaxAnalyticsMonths)}
+	}
+
+	to := time.Now().UTC()
+	from := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+	analytics := &domain.UserAnalytics{UserID: userID, From: from, To: to}
+
+	var err error
+	if analytics.TransactionCount, analytics.AverageTransactionAmount, err = s.repo.TransactionSummary(ctx, userID, from, to); err != nil {
+		return nil, fmt.Errorf("failed to summarize transactions: %w", err)
+	}
+	if analytics.SpendByType, err = s.repo.SpendByType(ctx, userID, from, to); err != nil {
+		return nil, fmt.Errorf("failed to total spending: %w", err)
+	}
+	if analytics.MonthlyFlows, err = s.repo.MonthlyFlows(ctx, userID, from, to); err != nil {
+		return nil, fmt.Errorf("failed to total monthly flows: %w", err)
+	}
+	if analytics.BalanceTrend, err = s.repo.BalanceTrend(ctx, userID, from, to); err != nil {
+		return nil, fmt.Errorf("failed to compute balance trend: %w", err)
+	}
+
+	// Encode empty results as [] rather than null
+	if analytics.SpendByType == nil {
+		analytics.SpendByType = []*domain.TypeSpend{}
+	}
+	if analytics.MonthlyFlows == nil {
+		analytics.MonthlyFlows = []*domain.MonthlyFlow{}
+	}
+	if analytics.BalanceTrend == nil {
+		analytics.BalanceTrend = []*domain.BalancePoint{}
+	}
+	return analytics, nil
+}
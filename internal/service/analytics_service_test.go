@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// stubAnalytics implements domain.AnalyticsRepository, answering every query
+// with its fields and recording the period it was asked about
+type stubAnalytics struct {
+	count    int
+	average  float64
+	spend    []*domain.TypeSpend
+	flows    []*domain.MonthlyFlow
+	trend    []*domain.BalancePoint
+	trendErr error
+
+	from, to time.Time
+}
+
+func (r *stubAnalytics) TransactionSummary(ctx context.Context, userID int, from, to time.Time) (int, float64, error) {
+	r.from, r.to = from, to
+	return r.count, r.average, nil
+}
+
+func (r *stubAnalytics) SpendByType(ctx context.Context, userID int, from, to time.Time) ([]*domain.TypeSpend, error) {
+	return r.spend, nil
+}
+
+func (r *stubAnalytics) MonthlyFlows(ctx context.Context, userID int, from, to time.Time) ([]*domain.MonthlyFlow, error) {
+	return r.flows, nil
+}
+
+func (r *stubAnalytics) BalanceTrend(ctx context.Context, userID int, from, to time.Time) ([]*domain.BalancePoint, error) {
+	return r.trend, r.trendErr
+}
+
+func TestAnalyticsServiceImpl_GetUserAnalytics(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("covers whole calendar months up to now", func(t *testing.T) {
+		repo := &stubAnalytics{
+			count:   4,
+			average: 12.5,
+			spend:   []*domain.TypeSpend{{Type: "debit", Count: 2, Amount: 30}},
+			flows:   []*domain.MonthlyFlow{{Month: "2026-01", Inflow: 50, Outflow: 30, Net: 20}},
+			trend:   []*domain.BalancePoint{{Balance: 20}},
+		}
+		before := time.Now().UTC()
+		analytics, err := NewAnalyticsService(repo).GetUserAnalytics(ctx, 7, 3)
+		require.NoError(t, err)
+
+		wantFrom := time.Date(before.Year(), before.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -2, 0)
+		assert.Equal(t, wantFrom, analytics.From)
+		assert.Equal(t, wantFrom, repo.from)
+		assert.False(t, analytics.To.Before(before))
+		assert.Equal(t, analytics.To, repo.to)
+
+		assert.Equal(t, 7, analytics.UserID)
+		assert.Equal(t, 4, analytics.TransactionCount)
+		assert.Equal(t, 12.5, analytics.AverageTransactionAmount)
+		assert.Equal(t, repo.spend, analytics.SpendByType)
+		assert.Equal(t, repo.flows, analytics.MonthlyFlows)
+		assert.Equal(t, repo.trend, analytics.BalanceTrend)
+	})
+
+	t.Run("one month is the current month", func(t *testing.T) {
+		repo := &stubAnalytics{}
+		analytics, err := NewAnalyticsService(repo).GetUserAnalytics(ctx, 7, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, analytics.From.Day())
+		assert.Equal(t, analytics.To.Month(), analytics.From.Month())
+	})
+
+	t.Run("no transactions encode as empty lists", func(t *testing.T) {
+		analytics, err := NewAnalyticsService(&stubAnalytics{}).GetUserAnalytics(ctx, 7, 12)
+		require.NoError(t, err)
+
+		body, err := json.Marshal(analytics)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"spend_by_type":[]`)
+		assert.Contains(t, string(body), `"monthly_flows":[]`)
+		assert.Contains(t, string(body), `"balance_trend":[]`)
+	})
+
+	t.Run("months out of range", func(t *testing.T) {
+		svc := NewAnalyticsService(&stubAnalytics{})
+		for _, months := range []int{0, -1, domain.MaxAnalyticsMonths + 1} {
+			_, err := svc.GetUserAnalytics(ctx, 7, months)
+			var verr *domain.ValidationError
+			assert.ErrorAs(t, err, &verr, "months=%d", months)
+		}
+		_, err := svc.GetUserAnalytics(ctx, 7, domain.MaxAnalyticsMonths)
+		assert.NoError(t, err)
+	})
+
+	t.Run("a failed query fails the request", func(t *testing.T) {
+		failure := errors.New("statement timeout")
+		_, err := NewAnalyticsService(&stubAnalytics{trendErr: failure}).GetUserAnalytics(ctx, 7, 3)
+		assert.ErrorIs(t, err, failure)
+		assert.ErrorContains(t, err, "failed to compute balance trend")
+	})
+}
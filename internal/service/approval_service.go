@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// ApprovalServiceImpl implements domain.ApprovalService
+type ApprovalServiceImpl struct {
+	approvalRepo domain.ApprovalRepository
+	txRepo       domain.TransactionRepository
+	txService    domain.TransactionService
+	audit        domain.AuditLogService
+}
+
+// NewApprovalService creates a new ApprovalServiceImpl
+func NewApprovalService(approvalRepo domain.ApprovalRepository, txRepo domain.TransactionRepository, txService domain.TransactionService, audit domain.AuditLogService) *ApprovalServiceImpl {
+	return &ApprovalServiceImpl{
+		approvalRepo: approvalRepo,
+		txRepo:       txRepo,
+		txService:    txService,
+		audit:        audit,
+	}
+}
+
+// Approve executes a pending transaction on behalf of a second admin.
+func (s *ApprovalServiceImpl) Approve(ctx context.Context, transactionID, reviewerID int) (*domain.Transaction, error) {
+	approval, tx, err := s.getPending(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := approval.Approve(reviewerID); err != nil {
+		return nil, err
+	}
+	err = s.txService.ExecutePending(ctx, tx, func(repos domain.UnitOfWorkRepositories) error {
+		return repos.Approvals.Update(ctx, approval)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrApprovalAlreadyReviewed) || errors.Is(err, domain.ErrTransactionNotPending) {
+			return nil, err
+		}
+		if tx.Status != "failed" {
+			return nil, fmt.Errorf("failed to execute transaction: %w", err)
+		}
+		log.Ctx(ctx).Warn().Err(err).Int("transaction_id", tx.ID).Int("reviewed_by", reviewerID).Msg("Approved transaction could not be executed")
+	}
+	s.recordReview(ctx, reviewerID, tx, "approve", "")
+	return tx, nil
+}
+
+// Reject cancels a pending transaction without moving any money
+func (s *ApprovalServiceImpl) Reject(ctx context.Context, transactionID, reviewerID int, reason string) (*domain.Transaction, error) {
+	approval, tx, err := s.getPending(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := approval.Reject(reviewerID, reason); err != nil {
+		return nil, err
+	}
+	err = s.txService.RejectPending(ctx, tx, func(repos domain.UnitOfWorkRepositories) error {
+		return repos.Approvals.Update(ctx, approval)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrApprovalAlreadyReviewed) || errors.Is(err, domain.ErrTransactionNotPending) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to reject transaction: %w", err)
+	}
+	s.recordReview(ctx, reviewerID, tx, "reject", reason)
+	return tx, nil
+}
+
+// ListPending retrieves approval requests awaiting review
+func (s *ApprovalServiceImpl) ListPending(ctx context.Context, limit, offset int) ([]*domain.Approval, error) {
+	return s.approvalRepo.ListPending(ctx, limit, offset)
+}
+
+// getPending loads a transaction and its approval request
+func (s *ApprovalServiceImpl) getPending(ctx context.Context, transactionID int) (*domain.Approval, *domain.Transaction, error) {
+	approval, err := s.approvalRepo.GetByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get approval: %w", err)
+	}
+	if approval == nil {
+		return nil, nil, domain.ErrApprovalNotFound
+	}
+
+	tx, err := s.txRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if tx == nil {
+		return nil, nil, errors.New("transaction of approval request is missing")
+	}
+	return approval, tx, nil
+}
+
+// recordReview writes a step of the maker-checker flow to the audit log.
+func (s *ApprovalServiceImpl) recordReview(ctx context.Context, actorID int, tx *domain.Transaction, action, reason string) {
+	details := fmt.Sprintf("%s of %.2f", tx.Type, tx.Amount)
+	if reason != "" {
+		details += ": " + reason
+	}
+	if err := s.audit.Record(ctx, &actorID, "transaction", tx.ID, action, details); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("transaction_id", tx.ID).Int("actor_id", actorID).Str("action", action).Msg("Failed to audit approval step")
+	}
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// discardAudit implements domain.AuditLogService, keeping nothing
+type discardAudit struct{}
+
+func (discardAudit) Record(ctx context.Context, actorID *int, entityType string, entityID int, action, details string) error {
+	return nil
+}
+
+func (discardAudit) Search(ctx context.Context, filter domain.AuditLogFilter) ([]*domain.AuditLog, error) {
+	return nil, nil
+}
+
+// newApprovalTestServices returns transaction and approval services over store
+// holding movements above 100 for approval
+func newApprovalTestServices(store *memoryStore) (*TransactionServiceImpl, *ApprovalServiceImpl) {
+	txRepo := &memoryTransactions{store: store}
+	txService := NewTransactionService(txRepo, store, nil, nil, nil, nil, nil, TransactionReview{ApprovalThreshold: 100, Audit: discardAudit{}})
+	return txService, NewApprovalService(&memoryApprovals{store: store}, txRepo, txService, discardAudit{})
+}
+
+func TestTransactionServiceImpl_HoldsLargeMovementsForApproval(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 1000, 0)
+	txService, _ := newApprovalTestServices(store)
+
+	for name, move := range map[string]func(ctx context.Context) (*domain.Transaction, error){
+		"credit":   func(ctx context.Context) (*domain.Transaction, error) { return txService.Credit(ctx, 2, 150, "") },
+		"debit":    func(ctx context.Context) (*domain.Transaction, error) { return txService.Debit(ctx, 1, 150, "") },
+		"transfer": func(ctx context.Context) (*domain.Transaction, error) { return txService.Transfer(ctx, 1, 2, 150, "") },
+	} {
+		tx, err := move(ctx)
+		assert.ErrorIs(t, err, domain.ErrTransactionHeld, name)
+		require.NotNil(t, tx, name)
+		assert.Equal(t, "pending_approval", tx.Status, name)
+		approval := store.approvalFor(tx.ID)
+		require.NotNil(t, approval, name)
+		assert.Equal(t, "pending", approval.Status, name)
+	}
+	payer, _ := store.balance(1)
+	payee, _ := store.balance(2)
+	assert.Equal(t, 1000.0, payer, "no money moves while held")
+	assert.Zero(t, payee)
+
+	t.Run("the requester in the context asked for it", func(t *testing.T) {
+		tx, err := txService.Transfer(domain.WithRequester(ctx, 9), 1, 2, 150, "")
+		assert.ErrorIs(t, err, domain.ErrTransactionHeld)
+		assert.Equal(t, 9, store.approvalFor(tx.ID).RequestedBy)
+	})
+
+	t.Run("otherwise the payer did", func(t *testing.T) {
+		tx, err := txService.Transfer(ctx, 1, 2, 150, "")
+		assert.ErrorIs(t, err, domain.ErrTransactionHeld)
+		assert.Equal(t, 1, store.approvalFor(tx.ID).RequestedBy)
+	})
+
+	t.Run("at the threshold nothing is held", func(t *testing.T) {
+		_, err := txService.Transfer(ctx, 1, 2, 100, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("a retry finds the held transaction", func(t *testing.T) {
+		first, err := txService.Transfer(ctx, 1, 2, 150, "scheduled:1:1")
+		assert.ErrorIs(t, err, domain.ErrTransactionHeld)
+		again, err := txService.Transfer(ctx, 1, 2, 150, "scheduled:1:1")
+		assert.ErrorIs(t, err, domain.ErrTransactionHeld)
+		require.NotNil(t, again)
+		assert.Equal(t, first.ID, again.ID)
+	})
+}
+
+func TestApprovalServiceImpl_ApprovesOnce(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 1000, 0)
+	txService, approvals := newApprovalTestServices(store)
+
+	held, err := txService.Transfer(ctx, 1, 2, 150, "")
+	require.ErrorIs(t, err, domain.ErrTransactionHeld)
+
+	tx, err := approvals.Approve(ctx, held.ID, 7)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", tx.Status)
+
+	_, err = approvals.Approve(ctx, held.ID, 8)
+	assert.Error(t, err)
+	_, err = approvals.Reject(ctx, held.ID, 8, "too late")
+	assert.Error(t, err)
+
+	payer, _ := store.balance(1)
+	payee, _ := store.balance(2)
+	assert.Equal(t, 850.0, payer)
+	assert.Equal(t, 150.0, payee)
+}
+
+// staleApprovals returns an approval as it was read before another review
+type staleApprovals struct {
+	*memoryApprovals
+	stale *domain.Approval
+}
+
+func (r staleApprovals) GetByTransactionID(ctx context.Context, transactionID int) (*domain.Approval, error) {
+	copied := *r.stale
+	return &copied, nil
+}
+
+func TestApprovalServiceImpl_StoresTheDecisionWithTheMovement(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 1000, 0)
+	txService, approvals := newApprovalTestServices(store)
+
+	t.Run("a transaction resolved meanwhile leaves the approval pending", func(t *testing.T) {
+		held, err := txService.Debit(ctx, 1, 150, "")
+		require.ErrorIs(t, err, domain.ErrTransactionHeld)
+		require.NoError(t, (&memoryTransactions{store: store}).ResolvePending(ctx, held.ID, "failed"))
+
+		_, err = approvals.Approve(ctx, held.ID, 7)
+		assert.ErrorIs(t, err, domain.ErrTransactionNotPending)
+		assert.Equal(t, "pending", store.approvalFor(held.ID).Status)
+	})
+
+	t.Run("an approval reviewed meanwhile moves no money", func(t *testing.T) {
+		held, err := txService.Debit(ctx, 1, 150, "")
+		require.ErrorIs(t, err, domain.ErrTransactionHeld)
+		stale := store.approvalFor(held.ID)
+		reviewed := *stale
+		require.NoError(t, reviewed.Reject(8, "no"))
+		require.NoError(t, (&memoryApprovals{store: store}).Update(ctx, &reviewed))
+		approvals := NewApprovalService(staleApprovals{memoryApprovals: &memoryApprovals{store: store}, stale: stale},
+			&memoryTransactions{store: store}, txService, discardAudit{})
+
+		_, err = approvals.Approve(ctx, held.ID, 7)
+		assert.ErrorIs(t, err, domain.ErrApprovalAlreadyReviewed)
+		amount, _ := store.balance(1)
+		assert.Equal(t, 1000.0, amount)
+		tx, _ := txService.GetTransaction(ctx, held.ID)
+		assert.Equal(t, "pending_approval", tx.Status)
+	})
+
+	t.Run("a movement that fails still stores the decision", func(t *testing.T) {
+		held, err := txService.Debit(ctx, 1, 150, "")
+		require.ErrorIs(t, err, domain.ErrTransactionHeld)
+		store.setBalance(1, 100, 0)
+
+		tx, err := approvals.Approve(ctx, held.ID, 7)
+		require.NoError(t, err)
+		assert.Equal(t, "failed", tx.Status)
+		approval := store.approvalFor(held.ID)
+		assert.Equal(t, "approved", approval.Status)
+		require.NotNil(t, approval.ReviewedBy)
+		assert.Equal(t, 7, *approval.ReviewedBy)
+	})
+}
+
+func TestApprovalServiceImpl_ConcurrentApprovalsMoveMoneyOnce(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 1000, 0)
+	txService, approvals := newApprovalTestServices(store)
+
+	held, err := txService.Transfer(ctx, 1, 2, 150, "")
+	require.ErrorIs(t, err, domain.ErrTransactionHeld)
+
+	var approved atomic.Int32
+	var wg sync.WaitGroup
+	for reviewer := 10; reviewer < 20; reviewer++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := approvals.Approve(ctx, held.ID, reviewer)
+			if err == nil {
+				approved.Add(1)
+				return
+			}
+			assert.True(t, errors.Is(err, domain.ErrApprovalAlreadyReviewed) || errors.Is(err, domain.ErrTransactionNotPending) ||
+				err.Error() == "transaction has already been approved", "unexpected error: %v", err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), approved.Load())
+	payer, _ := store.balance(1)
+	assert.Equal(t, 850.0, payer)
+}
+
+func TestTransactionServiceImpl_ExecutePendingRunsOnce(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 1000, 0)
+	txService, _ := newApprovalTestServices(store)
+
+	held, err := txService.Debit(ctx, 1, 150, "")
+	require.ErrorIs(t, err, domain.ErrTransactionHeld)
+
+	var executed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx := *held
+			if err := txService.ExecutePending(ctx, &tx, nil); err == nil {
+				executed.Add(1)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrTransactionNotPending)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), executed.Load())
+	amount, _ := store.balance(1)
+	assert.Equal(t, 850.0, amount)
+}
+
+func TestApprovalServiceImpl_Reject(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 1000, 0)
+	txService, approvals := newApprovalTestServices(store)
+
+	held, err := txService.Transfer(ctx, 1, 2, 150, "money-request:3")
+	require.ErrorIs(t, err, domain.ErrTransactionHeld)
+
+	_, err = approvals.Reject(ctx, held.ID, 1, "mine")
+	assert.ErrorIs(t, err, domain.ErrSelfApproval)
+	tx, err := approvals.Reject(ctx, held.ID, 7, "unknown payee")
+	require.NoError(t, err)
+	assert.Equal(t, "rejected", tx.Status)
+
+	assert.Error(t, txService.ExecutePending(ctx, held, nil), "a rejected transaction never runs")
+	_, err = txService.Transfer(ctx, 1, 2, 150, "money-request:3")
+	assert.ErrorIs(t, err, domain.ErrTransactionNotCompleted)
+	amount, _ := store.balance(1)
+	assert.Equal(t, 1000.0, amount)
+}
+
+func TestTransactionServiceImpl_HeldTransactionsReachBothUsersCaches(t *testing.T) {
+	ctx := context.Background()
+	for name, review := range map[string]func(approvals *ApprovalServiceImpl, id int) error{
+		"approved": func(approvals *ApprovalServiceImpl, id int) error {
+			_, err := approvals.Approve(ctx, id, 7)
+			return err
+		},
+		"rejected": func(approvals *ApprovalServiceImpl, id int) error {
+			_, err := approvals.Reject(ctx, id, 7, "unknown payee")
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := newMemoryStore()
+			store.setBalance(1, 1000, 0)
+			txRepo := &memoryTransactions{store: store}
+			held := &recordingInvalidator{}
+			txService := NewTransactionService(txRepo, store, held, nil, nil, nil, nil, TransactionReview{ApprovalThreshold: 100, Audit: discardAudit{}})
+			approvals := NewApprovalService(&memoryApprovals{store: store}, txRepo, txService, discardAudit{})
+
+			tx, err := txService.Transfer(ctx, 1, 2, 150, "")
+			require.ErrorIs(t, err, domain.ErrTransactionHeld)
+			assert.True(t, held.invalidated(1), "the payer lists the held transfer")
+			assert.True(t, held.invalidated(2), "and so does the payee")
+
+			reviewed := &recordingInvalidator{}
+			txService.cache = reviewed
+			require.NoError(t, review(approvals, tx.ID))
+			assert.True(t, reviewed.invalidated(1))
+			assert.True(t, reviewed.invalidated(2), "the payee sees the outcome")
+		})
+	}
+}
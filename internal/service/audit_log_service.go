@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// AuditLogServiceImpl implements domain.AuditLogService
+type AuditLogServiceImpl struct {
+	repo domain.AuditLogRepository
+}
+
+// NewAuditLogService creates a new AuditLogServiceImpl
+func NewAuditLogService(repo domain.AuditLogRepository) *AuditLogServiceImpl {
+	return &AuditLogServiceImpl{repo: repo}
+}
+
+// Record stores an audit entry; actorID is nil for system actions
+func (s *AuditLogServiceImpl) Record(ctx context.Context, actorID *int, entityType string, entityID int, action, details string) error {
+	entry := &domain.AuditLog{
+		ActorID:    actorID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Details:    details,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := s.repo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record audit log: %w", err)
+	}
+	return nil
+}
+
+// Search retrieves the entries matching filter, newest first
+func (s *AuditLogServiceImpl) Search(ctx context.Context, filter domain.AuditLogFilter) ([]*domain.AuditLog, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, &domain.ValidationError{Msg: "from must be before to"}
+	}
+	return s.repo.Search(ctx, filter)
+}
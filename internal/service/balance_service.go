@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"time"
 
 	"github.com/melihgurlek/backend-path/internal/domain"
@@ -14,14 +15,26 @@ func NewBalanceService(repo domain.BalanceRepository) domain.BalanceService {
 	return &BalanceServiceImpl{repo: repo}
 }
 
-func (s *BalanceServiceImpl) GetCurrentBalance(userID int) (*domain.Balance, error) {
-	return s.repo.GetByUserID(userID)
+func (s *BalanceServiceImpl) GetCurrentBalance(ctx context.Context, userID int) (*domain.Balance, error) {
+	return s.repo.GetByUserID(ctx, userID)
 }
 
-func (s *BalanceServiceImpl) GetHistoricalBalance(userID int, limit int) ([]*domain.Balance, error) {
-	return s.repo.GetHistoricalBalance(userID, limit)
+func (s *BalanceServiceImpl) GetHistoricalBalance(ctx context.Context, userID int, limit int) ([]*domain.Balance, error) {
+	return s.repo.GetHistoricalBalance(ctx, userID, limit)
 }
 
-func (s *BalanceServiceImpl) GetBalanceAtTime(userID int, t time.Time) (*domain.Balance, error) {
-	return s.repo.GetBalanceAtTime(userID, t)
+func (s *BalanceServiceImpl) GetBalanceAtTime(ctx context.Context, userID int, t time.Time) (*domain.Balance, error) {
+	return s.repo.GetBalanceAtTime(ctx, userID, t)
+}
+
+// GetAvailableBalance returns the user's balance minus the amount reserved by active holds
+func (s *BalanceServiceImpl) GetAvailableBalance(ctx context.Context, userID int) (float64, error) {
+	bal, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if bal == nil {
+		return 0, nil
+	}
+	return bal.AvailableAmount(), nil
 }
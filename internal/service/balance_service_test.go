@@ -10,7 +10,8 @@ import (
 )
 
 func TestBalanceServiceImpl_GetHistoricalBalance(t *testing.T) {
-	conn := repository.getTestConn(t)
+	ctx := context.Background()
+	conn := getTestPool(t)
 	balRepo := repository.NewBalancePostgresRepository(conn)
 	service := NewBalanceService(balRepo)
 	userID := 8881
@@ -18,7 +19,7 @@ func TestBalanceServiceImpl_GetHistoricalBalance(t *testing.T) {
 		conn.Exec(context.Background(), "DELETE FROM transactions WHERE from_user_id = $1 OR to_user_id = $1", userID)
 		conn.Exec(context.Background(), "DELETE FROM balances WHERE user_id = $1", userID)
 		conn.Exec(context.Background(), "DELETE FROM users WHERE id = $1", userID)
-		_ = conn.Close(context.Background())
+		conn.Close()
 	}()
 
 	// Insert test user
@@ -60,7 +61,7 @@ func TestBalanceServiceImpl_GetHistoricalBalance(t *testing.T) {
 	conn.Exec(context.Background(), "INSERT INTO transactions (from_user_id, to_user_id, amount, type, status, created_at) VALUES ($1,$2,$3,$4,$5,$6)", tx3.FromUserID, tx3.ToUserID, tx3.Amount, tx3.Type, tx3.Status, tx3.CreatedAt)
 
 	// Call GetHistoricalBalance
-	balances, err := service.GetHistoricalBalance(userID, 7771)
+	balances, err := service.GetHistoricalBalance(ctx, userID, 7771)
 	if err != nil {
 		t.Fatalf("GetHistoricalBalance failed: %v", err)
 	}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// bankSettlementBatch bounds the due transfers one settlement run handles.
+const bankSettlementBatch = 500
+
+// BankTransferServiceImpl implements domain.BankTransferService.
+type BankTransferServiceImpl struct {
+	repo           domain.BankTransferRepository
+	funding        domain.FundingRepository
+	users          domain.UserRepository
+	uow            domain.UnitOfWork
+	cache          domain.CacheInvalidator
+	notifier       domain.Notifier
+	audit          domain.AuditLogService
+	settlementDays map[string]int
+	testReturns    map[string]string
+	interval       time.Duration
+	now            func() time.Time
+	runMu          sync.Mutex
+	stopChan       chan struct{}
+}
+
+// NewBankTransferService creates a new BankTransferServiceImpl.
+func NewBankTransferService(repo domain.BankTransferRepository, funding domain.FundingRepository, users domain.UserRepository, uow domain.UnitOfWork, cache domain.CacheInvalidator, notifier domain.Notifier, audit domain.AuditLogService, settlementDays map[string]int, testReturns map[string]string, interval time.Duration) *BankTransferServiceImpl {
+	return &BankTransferServiceImpl{
+		repo:           repo,
+		funding:        funding,
+		users:          users,
+		uow:            uow,
+		cache:          cache,
+		notifier:       notifier,
+		audit:          audit,
+		settlementDays: settlementDays,
+		testReturns:    testReturns,
+		interval:       interval,
+		now:            time.Now,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Initiate starts a pending transfer between userID's balance and a bank account.
+func (s *BankTransferServiceImpl) Initiate(ctx context.Context, userID int, transfer *domain.BankTransfer) (*domain.BankTransfer, error) {
+	transfer.UserID, transfer.Status = userID, domain.BankTransferPending
+	transfer.Direction = strings.ToLower(strings.TrimSpace(transfer.Direction))
+	transfer.Rail = strings.ToLower(strings.TrimSpace(transfer.Rail))
+	transfer.Amount = math.Round(transfer.Amount*100) / 100
+	if err := transfer.Validate(); err != nil {
+		return nil, err
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, domain.ErrBankTransferUserNotFound
+	}
+	source, err := s.funding.GetSource(ctx, transfer.FundingSourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank account: %w", err)
+	}
+	if source == nil || source.UserID != userID || source.Kind != domain.FundingSourceBankAccount || source.Status != domain.FundingSourceActive {
+		return nil, domain.ErrBankAccountInvalid
+	}
+
+	transfer.CreatedAt = s.now().UTC()
+	transfer.SettleAt = domain.AddBusinessDays(transfer.CreatedAt, s.settlementDays[transfer.Rail])
+	err = retryOnBalanceConflict(func() error {
+		return s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+			bal, err := repos.Balances.GetByUserID(ctx, userID)
+			if err != nil {
+				return err
+			}
+			if bal != nil && bal.IsFrozen() {
+				return domain.ErrAccountFrozen
+			}
+			if transfer.Direction == domain.BankTransferOutbound {
+				if err := checkLimits(ctx, repos.Limits, userID, transfer.Amount); err != nil {
+					return err
+				}
+				if bal == nil || bal.AvailableAmount() < transfer.Amount {
+					return domain.ErrInsufficientFunds
+				}
+				bal.HeldAmount += transfer.Amount
+				if err := repos.Balances.Update(ctx, bal); err != nil {
+					return err
+				}
+			}
+			return repos.BankTransfers.Create(ctx, transfer)
+		})
+	})
+	if err != nil {
+		if kind, _ := domain.ClassifyError(err); kind != domain.ErrorKindInternal {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to initiate bank transfer: %w", err)
+	}
+
+	metrics.BankTransfersTotal.WithLabelValues(transfer.Direction, transfer.Status).Inc()
+	invalidateUsers(ctx, s.cache, &userID)
+	details := transfer.Direction + " " + transfer.Rail + " amount=" + formatAmount(transfer.Amount) +
+		" funding_source_id=" + strconv.Itoa(transfer.FundingSourceID)
+	if err := s.audit.Record(ctx, &userID, "bank_transfer", transfer.ID, "initiate_bank_transfer", details); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("bank_transfer_id", transfer.ID).Msg("Failed to audit bank transfer")
+	}
+	return transfer, nil
+}
+
+// Get returns one of userID's transfers.
+func (s *BankTransferServiceImpl) Get(ctx context.Context, userID, id int) (*domain.BankTransfer, error) {
+	transfer, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank transfer: %w", err)
+	}
+	if transfer == nil || transfer.UserID != userID {
+		return nil, domain.ErrBankTransferNotFound
+	}
+	return transfer, nil
+}
+
+// ListByUser returns a page of a user's transfers, newest first.
+func (s *BankTransferServiceImpl) ListByUser(ctx context.Context, userID, limit, offset int) ([]*domain.BankTransfer, error) {
+	return s.repo.ListByUser(ctx, userID, limit, offset)
+}
+
+// ListByStatus returns a page of the transfers with a status, oldest first.
+func (s *BankTransferServiceImpl) ListByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.BankTransfer, error) {
+	switch status {
+	case domain.BankTransferPending, domain.BankTransferSettled, domain.BankTransferReturned:
+	default:
+		return nil, &domain.ValidationError{Msg: "status must be pending, settled or returned"}
+	}
+	return s.repo.ListByStatus(ctx, status, limit, offset)
+}
+
+// Return records that the bank refused a pending transfer with a return code.
+func (s *BankTransferServiceImpl) Return(ctx context.Context, id int, actorID *int, code string) (*domain.BankTransfer, error) {
+	transfer, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank transfer: %w", err)
+	}
+	if transfer == nil {
+		return nil, domain.ErrBankTransferNotFound
+	}
+	if transfer.Status != domain.BankTransferPending {
+		return nil, domain.ErrBankTransferNotPending
+	}
+	returnCode, ok := domain.LookupBankReturnCode(transfer.Rail, strings.ToUpper(strings.TrimSpace(code)))
+	if !ok {
+		return nil, &domain.ValidationError{Msg: "code must be a " + strings.ToUpper(transfer.Rail) + " return code"}
+	}
+	if err := s.returnTransfer(ctx, transfer, returnCode); err != nil {
+		return nil, err
+	}
+	details := "code=" + returnCode.Code + " reason=" + returnCode.Reason
+	if err := s.audit.Record(ctx, actorID, "bank_transfer", transfer.ID, "return_bank_transfer", details); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("bank_transfer_id", transfer.ID).Msg("Failed to audit bank transfer return")
+	}
+	return transfer, nil
+}
+
+// SettleDue settles every pending transfer whose settlement date has come.
+func (s *BankTransferServiceImpl) SettleDue(ctx context.Context) (*domain.BankTransferRun, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	due, err := s.repo.ListDue(ctx, s.now(), bankSettlementBatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due bank transfers: %w", err)
+	}
+	run := &domain.BankTransferRun{}
+	for _, transfer := range due {
+		returnCode, returned, err := s.simulatedReturn(ctx, transfer)
+		if err == nil && returned {
+			err = s.returnTransfer(ctx, transfer, returnCode)
+		} else if err == nil {
+			err = s.settle(ctx, transfer)
+		}
+		switch {
+		case errors.Is(err, domain.ErrBankTransferNotPending):
+			// Completed by another instance or an admin meanwhile
+		case err != nil:
+			log.Ctx(ctx).Error().Err(err).Int("bank_transfer_id", transfer.ID).Msg("Failed to settle bank transfer")
+		case returned:
+			run.Returned++
+		default:
+			run.Settled++
+		}
+	}
+	return run, nil
+}
+
+// simulatedReturn reports whether the simulated bank returns a transfer, and its code.
+func (s *BankTransferServiceImpl) simulatedReturn(ctx context.Context, transfer *domain.BankTransfer) (domain.BankReturnCode, bool, error) {
+	if len(s.testReturns) == 0 {
+		return domain.BankReturnCode{}, false, nil
+	}
+	source, err := s.funding.GetSource(ctx, transfer.FundingSourceID)
+	if err != nil || source == nil {
+		return domain.BankReturnCode{}, false, fmt.Errorf("failed to get bank account: %w", err)
+	}
+	code, ok := s.testReturns[source.Last4]
+	if !ok {
+		return domain.BankReturnCode{}, false, nil
+	}
+	returnCode, ok := domain.LookupBankReturnCode(transfer.Rail, code)
+	if !ok {
+		log.Ctx(ctx).Warn().Str("code", code).Str("rail", transfer.Rail).Msg("Test return code is unknown on the rail; settling instead")
+	}
+	return returnCode, ok, nil
+}
+
+// settle completes a pending transfer by moving its amount.
+func (s *BankTransferServiceImpl) settle(ctx context.Context, transfer *domain.BankTransfer) error {
+	err := retryOnBalanceConflict(func() error {
+		return s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+			tx := &domain.Transaction{
+				Amount:         transfer.Amount,
+				Status:         "completed",
+				IdempotencyKey: transfer.TransactionKey(),
+				Description:    fmt.Sprintf("%s %s bank transfer #%d", strings.ToUpper(transfer.Rail), transfer.Direction, transfer.ID),
+			}
+			if transfer.Direction == domain.BankTransferOutbound {
+				tx.FromUserID, tx.Type = &transfer.UserID, "debit"
+				bal, err := repos.Balances.GetByUserID(ctx, transfer.UserID)
+				if err != nil {
+					return err
+				}
+				if bal == nil || bal.HeldAmount < transfer.Amount {
+					return fmt.Errorf("balance of user %d does not cover bank transfer %d", transfer.UserID, transfer.ID)
+				}
+				bal.Amount -= transfer.Amount
+				bal.HeldAmount -= transfer.Amount
+				if err := repos.Balances.Update(ctx, bal); err != nil {
+					return err
+				}
+			} else {
+				tx.ToUserID, tx.Type = &transfer.UserID, "credit"
+				if err := creditBalance(ctx, repos.Balances, transfer.UserID, transfer.Amount); err != nil {
+					return err
+				}
+			}
+			if err := repos.Transactions.Create(ctx, tx); err != nil {
+				return err
+			}
+			transfer.Status, transfer.TransactionID = domain.BankTransferSettled, &tx.ID
+			return repos.BankTransfers.Complete(ctx, transfer)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	s.completed(ctx, transfer)
+	return nil
+}
+
+// returnTransfer completes a pending transfer as returned with code.
+func (s *BankTransferServiceImpl) returnTransfer(ctx context.Context, transfer *domain.BankTransfer, code domain.BankReturnCode) error {
+	err := retryOnBalanceConflict(func() error {
+		return s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+			if transfer.Direction == domain.BankTransferOutbound {
+				bal, err := repos.Balances.GetByUserID(ctx, transfer.UserID)
+				if err != nil {
+					return err
+				}
+				if bal == nil || bal.HeldAmount < transfer.Amount {
+					return fmt.Errorf("balance of user %d does not cover bank transfer %d", transfer.UserID, transfer.ID)
+				}
+				bal.HeldAmount -= transfer.Amount
+				if err := repos.Balances.Update(ctx, bal); err != nil {
+					return err
+				}
+			}
+			if code.Closes {
+				if err := repos.Funding.RemoveSource(ctx, transfer.FundingSourceID); err != nil {
+					return err
+				}
+			}
+			transfer.Status, transfer.ReturnCode, transfer.ReturnReason = domain.BankTransferReturned, code.Code, code.Reason
+			return repos.BankTransfers.Complete(ctx, transfer)
+		})
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrBankTransferNotPending) {
+			return err
+		}
+		return fmt.Errorf("failed to return bank transfer: %w", err)
+	}
+	log.Ctx(ctx).Info().Int("bank_transfer_id", transfer.ID).Str("code", code.Code).Msg("Bank transfer returned")
+	s.completed(ctx, transfer)
+	return nil
+}
+
+// completed counts a settled or returned transfer and tells its user
+func (s *BankTransferServiceImpl) completed(ctx context.Context, transfer *domain.BankTransfer) {
+	metrics.BankTransfersTotal.WithLabelValues(transfer.Direction, transfer.Status).Inc()
+	invalidateUsers(ctx, s.cache, &transfer.UserID)
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.Notify(ctx, transfer.UserID, domain.EventBankTransferUpdated, map[string]string{
+		"bank_transfer_id": strconv.Itoa(transfer.ID),
+		"direction":        transfer.Direction,
+		"rail":             transfer.Rail,
+		"status":           transfer.Status,
+		"amount":           formatAmount(transfer.Amount),
+		"return_code":      transfer.ReturnCode,
+		"return_reason":    transfer.ReturnReason,
+	})
+}
+
+// Start settles due transfers now and then every interval
+func (s *BankTransferServiceImpl) Start(ctx context.Context) {
+	log.Ctx(ctx).Info().Dur("interval", s.interval).Msg("Starting bank transfer settlement")
+
+	go s.settlementLoop(ctx)
+}
+
+// Stop stops the periodic settlement
+func (s *BankTransferServiceImpl) Stop() {
+	log.Info().Msg("Stopping bank transfer settlement")
+	close(s.stopChan)
+}
+
+func (s *BankTransferServiceImpl) settlementLoop(ctx context.Context) {
+	defer metrics.TrackGoroutine("bank_settlement")()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.SettleDue(ctx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Bank transfer settlement run failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
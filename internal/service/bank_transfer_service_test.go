@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// knownUsers implements the user lookup of domain.UserRepository for users
+// that all exist
+type knownUsers struct {
+	domain.UserRepository
+}
+
+func (knownUsers) GetByID(ctx context.Context, id int) (*domain.User, error) {
+	return &domain.User{ID: id}, nil
+}
+
+// recordingNotifier implements domain.Notifier, keeping what it was asked to send
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []map[string]string
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, userID int, event string, data map[string]string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, data)
+}
+
+// bankTestStart is a Monday, so one business day on is Tuesday
+var bankTestStart = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+// newBankTransferTestService returns a BankTransferServiceImpl over store
+// where user 1 has bank account 5 ending in 6789, ACH settles in one business
+// day and the simulated bank returns what testReturns asks for
+func newBankTransferTestService(store *memoryStore, testReturns map[string]string) (*BankTransferServiceImpl, *recordingNotifier) {
+	store.sources[5] = &domain.FundingSource{ID: 5, UserID: 1, Kind: domain.FundingSourceBankAccount, Last4: "6789", Status: domain.FundingSourceActive}
+	notifier := &recordingNotifier{}
+	service := NewBankTransferService(&memoryBankTransfers{store: store}, &memoryFunding{store: store}, knownUsers{}, store, nil, notifier, discardAudit{},
+		map[string]int{domain.BankRailACH: 1, domain.BankRailSEPA: 1}, testReturns, time.Hour)
+	service.now = func() time.Time { return bankTestStart }
+	return service, notifier
+}
+
+func bankTransfer(direction string, amount float64) *domain.BankTransfer {
+	return &domain.BankTransfer{FundingSourceID: 5, Direction: direction, Rail: domain.BankRailACH, Amount: amount}
+}
+
+func TestBankTransferServiceImpl_OutboundHoldsUntilSettled(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	service, notifier := newBankTransferTestService(store, nil)
+
+	transfer, err := service.Initiate(ctx, 1, bankTransfer(domain.BankTransferOutbound, 40))
+	require.NoError(t, err)
+	assert.Equal(t, bankTestStart.AddDate(0, 0, 1), transfer.SettleAt)
+	amount, held := store.balance(1)
+	assert.Equal(t, 100.0, amount)
+	assert.Equal(t, 40.0, held)
+
+	_, err = service.Initiate(ctx, 1, bankTransfer(domain.BankTransferOutbound, 70))
+	assert.ErrorIs(t, err, domain.ErrInsufficientFunds, "the held 40 is not available")
+
+	run, err := service.SettleDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, run.Settled, "not due yet")
+
+	service.now = func() time.Time { return transfer.SettleAt }
+	run, err = service.SettleDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Settled)
+	amount, held = store.balance(1)
+	assert.Equal(t, 60.0, amount)
+	assert.Zero(t, held)
+
+	settled, err := service.Get(ctx, 1, transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.BankTransferSettled, settled.Status)
+	require.NotNil(t, settled.TransactionID)
+	txs := store.committed()
+	require.Len(t, txs, 1)
+	assert.Equal(t, "debit", txs[0].Type)
+	assert.Equal(t, transfer.TransactionKey(), txs[0].IdempotencyKey)
+
+	run, err = service.SettleDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, run.Settled, "a transfer settles once")
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, domain.BankTransferSettled, notifier.sent[0]["status"])
+}
+
+func TestBankTransferServiceImpl_InboundCreditsOnSettlement(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 10, 0)
+	service, _ := newBankTransferTestService(store, nil)
+
+	transfer, err := service.Initiate(ctx, 1, bankTransfer(domain.BankTransferInbound, 25))
+	require.NoError(t, err)
+	amount, held := store.balance(1)
+	assert.Equal(t, 10.0, amount, "nothing is credited before it settles")
+	assert.Zero(t, held)
+
+	service.now = func() time.Time { return transfer.SettleAt }
+	run, err := service.SettleDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Settled)
+	amount, _ = store.balance(1)
+	assert.Equal(t, 35.0, amount)
+}
+
+func TestBankTransferServiceImpl_Initiate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("outbound transfers keep to the limit rules", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(1, 100, 0)
+		store.spendLimit = 30
+		service, _ := newBankTransferTestService(store, nil)
+
+		_, err := service.Initiate(ctx, 1, bankTransfer(domain.BankTransferOutbound, 40))
+		var limitErr *domain.LimitExceededError
+		assert.ErrorAs(t, err, &limitErr)
+		_, held := store.balance(1)
+		assert.Zero(t, held)
+	})
+
+	t.Run("only the user's own active bank accounts", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(2, 100, 0)
+		service, _ := newBankTransferTestService(store, nil)
+
+		_, err := service.Initiate(ctx, 2, bankTransfer(domain.BankTransferOutbound, 40))
+		assert.ErrorIs(t, err, domain.ErrBankAccountInvalid)
+	})
+
+	t.Run("frozen accounts move nothing", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(1, 100, 0)
+		frozenAt := bankTestStart
+		row := store.balances[1]
+		row.frozenAt = &frozenAt
+		store.balances[1] = row
+		service, _ := newBankTransferTestService(store, nil)
+
+		_, err := service.Initiate(ctx, 1, bankTransfer(domain.BankTransferInbound, 40))
+		assert.ErrorIs(t, err, domain.ErrAccountFrozen)
+	})
+}
+
+func TestBankTransferServiceImpl_Return(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	service, notifier := newBankTransferTestService(store, nil)
+	admin := 9
+
+	transfer, err := service.Initiate(ctx, 1, bankTransfer(domain.BankTransferOutbound, 40))
+	require.NoError(t, err)
+
+	_, err = service.Return(ctx, transfer.ID, &admin, "AM04")
+	var validation *domain.ValidationError
+	assert.ErrorAs(t, err, &validation, "a SEPA code is not an ACH return")
+
+	returned, err := service.Return(ctx, transfer.ID, &admin, " r02 ")
+	require.NoError(t, err)
+	assert.Equal(t, domain.BankTransferReturned, returned.Status)
+	assert.Equal(t, "R02", returned.ReturnCode)
+	assert.Equal(t, "account closed", returned.ReturnReason)
+
+	amount, held := store.balance(1)
+	assert.Equal(t, 100.0, amount, "the held amount is given back")
+	assert.Zero(t, held)
+	assert.Equal(t, domain.FundingSourceRemoved, store.sources[5].Status, "R02 closes the account")
+	assert.Empty(t, store.committed())
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, "R02", notifier.sent[0]["return_code"])
+	assert.Equal(t, "account closed", notifier.sent[0]["return_reason"])
+
+	_, err = service.Return(ctx, transfer.ID, &admin, "R01")
+	assert.ErrorIs(t, err, domain.ErrBankTransferNotPending)
+	service.now = func() time.Time { return transfer.SettleAt }
+	run, err := service.SettleDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, run.Settled, "a returned transfer never settles")
+}
+
+func TestBankTransferServiceImpl_SimulatedReturns(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	service, _ := newBankTransferTestService(store, map[string]string{"6789": "R01"})
+
+	transfer, err := service.Initiate(ctx, 1, bankTransfer(domain.BankTransferOutbound, 40))
+	require.NoError(t, err)
+
+	service.now = func() time.Time { return transfer.SettleAt }
+	run, err := service.SettleDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Returned)
+	assert.Zero(t, run.Settled)
+
+	returned, err := service.Get(ctx, 1, transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "R01", returned.ReturnCode)
+	amount, held := store.balance(1)
+	assert.Equal(t, 100.0, amount)
+	assert.Zero(t, held)
+	assert.Equal(t, domain.FundingSourceActive, store.sources[5].Status, "R01 leaves the account open")
+}
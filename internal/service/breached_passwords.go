@@ -0,0 +1,35 @@
+package service
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+
+	"github.com/melihgurlek/backend-path/pkg/bloom"
+)
+
+// BloomBreachedPasswords checks passwords against a Bloom filter of breached ones.
+type BloomBreachedPasswords struct {
+	filter *bloom.Filter
+}
+
+// LoadBloomBreachedPasswords reads the filter file at path
+func LoadBloomBreachedPasswords(path string) (*BloomBreachedPasswords, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password filter: %w", err)
+	}
+	defer f.Close()
+
+	filter, err := bloom.ReadFrom(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breached password filter: %w", err)
+	}
+	return &BloomBreachedPasswords{filter: filter}, nil
+}
+
+// IsBreached reports whether password's SHA-1 digest is in the filter
+func (b *BloomBreachedPasswords) IsBreached(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	return b.filter.Test(sum[:])
+}
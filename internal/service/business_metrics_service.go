@@ -2,43 +2,73 @@ package service
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/cache"
 	"github.com/melihgurlek/backend-path/pkg/metrics"
 	"github.com/rs/zerolog/log"
 )
 
-// BusinessMetricsService handles business metrics collection and updates
+// poolStatsInterval is how often connection pool and cache hit ratio gauges are refreshed.
+const poolStatsInterval = 15 * time.Second
+
+// PoolStatsProvider reports database connection pool usage.
+type PoolStatsProvider interface {
+	PoolStats() (active, idle, total, max int)
+}
+
+// transactionStatsWindow is the period transaction success rates are computed over
+const transactionStatsWindow = 24 * time.Hour
+
+// BusinessMetricsService handles business metrics collection and updates.
 type BusinessMetricsService struct {
-	userRepo        domain.UserRepository
-	transactionRepo domain.TransactionRepository
-	balanceRepo     domain.BalanceRepository
-	mu              sync.RWMutex
-	lastUpdate      time.Time
-	updateInterval  time.Duration
-	stopChan        chan struct{}
+	metricsRepo    domain.BusinessMetricsRepository
+	poolStats      PoolStatsProvider
+	mu             sync.RWMutex
+	lastUpdate     time.Time
+	updateInterval time.Duration
+	stopChan       chan struct{}
+
+	// Latest collected values, reported by GetMetricsSummary; guarded by mu
+	userActivity    domain.UserActivityStats
+	successRates    map[string]float64
+	balanceTotal    float64
+	databaseHealthy bool
+}
+
+// MetricsSummary is a snapshot of the business metrics as last collected.
+type MetricsSummary struct {
+	LastUpdate              time.Time          `json:"last_update"`
+	TotalUsers              int                `json:"total_users"`
+	ActiveUsers             int                `json:"active_users"`
+	DailyActiveUsers        int                `json:"daily_active_users"`
+	MonthlyActiveUsers      int                `json:"monthly_active_users"`
+	BalanceTotal            float64            `json:"balance_total"`
+	TransactionSuccessRates map[string]float64 `json:"transaction_success_rates"` // percent per type, last 24 hours
+	DatabaseHealthy         bool               `json:"database_healthy"`
+	CacheHitRatio           float64            `json:"cache_hit_ratio"`
+	CacheLookups            int64              `json:"cache_lookups"`
+	RedisHitRatio           float64            `json:"redis_hit_ratio"`
+	RedisLookups            int64              `json:"redis_lookups"`
 }
 
-// NewBusinessMetricsService creates a new business metrics service
-func NewBusinessMetricsService(
-	userRepo domain.UserRepository,
-	transactionRepo domain.TransactionRepository,
-	balanceRepo domain.BalanceRepository,
-) *BusinessMetricsService {
+// NewBusinessMetricsService creates a new business metrics service.
+func NewBusinessMetricsService(metricsRepo domain.BusinessMetricsRepository, poolStats PoolStatsProvider) *BusinessMetricsService {
 	return &BusinessMetricsService{
-		userRepo:        userRepo,
-		transactionRepo: transactionRepo,
-		balanceRepo:     balanceRepo,
-		updateInterval:  5 * time.Minute, // Update metrics every 5 minutes
-		stopChan:        make(chan struct{}),
+		metricsRepo:    metricsRepo,
+		poolStats:      poolStats,
+		successRates:   map[string]float64{},
+		updateInterval: 5 * time.Minute, // Update metrics every 5 minutes
+		stopChan:       make(chan struct{}),
 	}
 }
 
 // Start begins the background metrics collection
 func (s *BusinessMetricsService) Start(ctx context.Context) {
-	log.Info().Msg("Starting business metrics service")
+	log.Ctx(ctx).Info().Msg("Starting business metrics service")
 
 	go s.metricsCollector(ctx)
 }
@@ -53,9 +83,13 @@ func (s *BusinessMetricsService) Stop() {
 func (s *BusinessMetricsService) metricsCollector(ctx context.Context) {
 	ticker := time.NewTicker(s.updateInterval)
 	defer ticker.Stop()
+	poolTicker := time.NewTicker(poolStatsInterval)
+	defer poolTicker.Stop()
 
 	// Initial collection
-	s.collectMetrics(ctx, 1000, 0)
+	s.collectMetrics(ctx)
+	s.collectPoolMetrics()
+	s.collectCacheMetrics()
 
 	for {
 		select {
@@ -64,23 +98,26 @@ func (s *BusinessMetricsService) metricsCollector(ctx context.Context) {
 		case <-s.stopChan:
 			return
 		case <-ticker.C:
-			s.collectMetrics(ctx, 1000, 0)
+			s.collectMetrics(ctx)
+		case <-poolTicker.C:
+			s.collectPoolMetrics()
+			s.collectCacheMetrics()
 		}
 	}
 }
 
 // collectMetrics collects all business metrics from the database
-func (s *BusinessMetricsService) collectMetrics(ctx context.Context, limit int, offset int) {
+func (s *BusinessMetricsService) collectMetrics(ctx context.Context) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	log.Debug().Msg("Collecting business metrics")
+	log.Ctx(ctx).Debug().Msg("Collecting business metrics")
 
 	// Collect user metrics
 	s.collectUserMetrics(ctx)
 
 	// Collect transaction metrics
-	s.collectTransactionMetrics(ctx, limit, offset)
+	s.collectTransactionMetrics(ctx)
 
 	// Collect balance metrics
 	s.collectBalanceMetrics(ctx)
@@ -91,148 +128,67 @@ func (s *BusinessMetricsService) collectMetrics(ctx context.Context, limit int,
 	s.lastUpdate = time.Now()
 }
 
-// collectUserMetrics collects user-related metrics
+// collectUserMetrics counts active users by their last login
 func (s *BusinessMetricsService) collectUserMetrics(ctx context.Context) {
-	// Get total user count
-	users, err := s.userRepo.List()
+	activity, err := s.metricsRepo.UserActivity(ctx, time.Now())
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get users for metrics")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to count active users for metrics")
 		metrics.ErrorRate.WithLabelValues("database", "warning").Inc()
 		return
 	}
 
-	// Calculate active users (users with recent activity)
-	activeUsers := 0
-	dailyActiveUsers := 0
-	monthlyActiveUsers := 0
-	now := time.Now()
-
-	for _, user := range users {
-		// Simple logic: consider users active if they have recent transactions
-		// In a real system, you'd track user sessions or activity timestamps
-		if user.UpdatedAt.After(now.Add(-24 * time.Hour)) {
-			dailyActiveUsers++
-		}
-		if user.UpdatedAt.After(now.Add(-30 * 24 * time.Hour)) {
-			monthlyActiveUsers++
-		}
-		if user.UpdatedAt.After(now.Add(-1 * time.Hour)) {
-			activeUsers++
-		}
-	}
-
-	metrics.ActiveUsers.Set(float64(activeUsers))
-	metrics.DailyActiveUsers.Set(float64(dailyActiveUsers))
-	metrics.MonthlyActiveUsers.Set(float64(monthlyActiveUsers))
+	s.userActivity = *activity
+	metrics.ActiveUsers.Set(float64(activity.ActiveLastHour))
+	metrics.DailyActiveUsers.Set(float64(activity.ActiveLastDay))
+	metrics.MonthlyActiveUsers.Set(float64(activity.ActiveLastMonth))
 }
 
-// collectTransactionMetrics collects transaction-related metrics
-func (s *BusinessMetricsService) collectTransactionMetrics(ctx context.Context, limit int, offset int) {
-	// Get all transactions
-	transactions, err := s.transactionRepo.ListAll(ctx, limit, offset) // Default limit of 1000, offset 0
+// collectTransactionMetrics updates the success rate of each transaction type over the last day.
+func (s *BusinessMetricsService) collectTransactionMetrics(ctx context.Context) {
+	stats, err := s.metricsRepo.TransactionStats(ctx, time.Now().Add(-transactionStatsWindow))
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get transactions for metrics")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to get transaction stats for metrics")
 		metrics.ErrorRate.WithLabelValues("database", "warning").Inc()
 		return
 	}
 
-	// Initialize counters
-	transactionCounts := make(map[string]map[string]int)
-	transactionVolumes := make(map[string]map[string]float64)
-	successCounts := make(map[string]int)
-	totalCounts := make(map[string]int)
-
-	// Initialize maps
-	for _, txnType := range []string{"credit", "debit", "transfer"} {
-		transactionCounts[txnType] = make(map[string]int)
-		transactionVolumes[txnType] = make(map[string]float64)
-	}
-
-	// Process transactions
-	for _, txn := range transactions {
-		txnType := string(txn.Type)
-		status := string(txn.Status)
-
-		// Count transactions
-		transactionCounts[txnType][status]++
-		totalCounts[txnType]++
-
-		// Track volumes
-		transactionVolumes[txnType][status] += float64(txn.Amount)
-
-		// Track success rates
-		if txn.Status == "completed" {
-			successCounts[txnType]++
-		}
-
-		// Record transaction amount for histogram
-		metrics.AverageTransactionAmount.WithLabelValues(txnType).Observe(float64(txn.Amount))
-	}
-
-	// Update Prometheus metrics
-	for txnType, statusCounts := range transactionCounts {
-		for status, count := range statusCounts {
-			metrics.TransactionCount.WithLabelValues(txnType, status).Add(float64(count))
-		}
-	}
-
-	for txnType, statusVolumes := range transactionVolumes {
-		for status, volume := range statusVolumes {
-			metrics.TransactionVolume.WithLabelValues(txnType, status).Add(volume)
-		}
-	}
-
-	// Calculate and update success rates
-	for txnType, total := range totalCounts {
-		if total > 0 {
-			successRate := float64(successCounts[txnType]) / float64(total) * 100
-			metrics.TransactionSuccessRate.WithLabelValues(txnType).Set(successRate)
-		}
+	rates := make(map[string]float64, len(stats))
+	for _, st := range stats {
+		rates[st.Type] = st.SuccessRate()
+		metrics.TransactionSuccessRate.WithLabelValues(st.Type).Set(st.SuccessRate())
 	}
+	s.successRates = rates
 }
 
-// collectBalanceMetrics collects balance-related metrics
+// collectBalanceMetrics updates the balance total and distribution
 func (s *BusinessMetricsService) collectBalanceMetrics(ctx context.Context) {
-	// Get all balances - we'll need to get them from users
-	users, err := s.userRepo.List()
+	stats, err := s.metricsRepo.BalanceStats(ctx, metrics.BalanceDistributionBounds)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get users for balance metrics")
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to get balance stats for metrics")
 		metrics.ErrorRate.WithLabelValues("database", "warning").Inc()
 		return
 	}
 
-	// Calculate total balance
-	totalBalance := float64(0)
-	for _, user := range users {
-		balance, err := s.balanceRepo.GetByUserID(user.ID)
-		if err != nil {
-			log.Error().Err(err).Int("user_id", user.ID).Msg("Failed to get balance for user")
-			continue
-		}
-		if balance != nil {
-			totalBalance += float64(balance.Amount)
-			// Record balance distribution
-			metrics.BalanceDistribution.Observe(float64(balance.Amount))
-		}
+	s.balanceTotal = stats.Total
+	metrics.BalanceTotal.Set(stats.Total)
+	for _, bound := range metrics.BalanceDistributionBounds {
+		metrics.BalanceDistribution.WithLabelValues(strconv.FormatFloat(bound, 'f', -1, 64)).Set(float64(stats.UsersAtOrBelow[bound]))
 	}
-
-	metrics.BalanceTotal.Set(totalBalance)
+	metrics.BalanceDistribution.WithLabelValues("+Inf").Set(float64(stats.Users))
 }
 
 // collectSystemHealthMetrics collects system health indicators
 func (s *BusinessMetricsService) collectSystemHealthMetrics(ctx context.Context) {
 	//Use the Ping method for a real health check.
-	if err := s.userRepo.Ping(ctx); err != nil {
-		log.Error().Err(err).Msg("Database health check failed")
+	err := s.metricsRepo.Ping(ctx)
+	s.databaseHealthy = err == nil
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Database health check failed")
 		metrics.SystemHealth.WithLabelValues("database").Set(0.0) // 0 for unhealthy
 	} else {
 		metrics.SystemHealth.WithLabelValues("database").Set(1.0) // 1 for healthy
 	}
 
-	// Cache hit ratio calculation (this would be updated by cache middleware)
-	// For now, we'll set a default value
-	metrics.CacheHitRatio.Set(85.0) // 85% cache hit ratio
-
 	// API health (assuming healthy if we can reach this point)
 	metrics.SystemHealth.WithLabelValues("api").Set(1.0)
 }
@@ -273,6 +229,22 @@ func (s *BusinessMetricsService) UpdateCacheHitRatio(hitRatio float64) {
 	metrics.CacheHitRatio.Set(hitRatio)
 }
 
+// collectCacheMetrics reports the response cache hit ratio over the last few minutes
+func (s *BusinessMetricsService) collectCacheMetrics() {
+	ratio, _ := cache.ResponseHitRatio.Ratio()
+	s.UpdateCacheHitRatio(ratio)
+}
+
+// collectPoolMetrics reports the current connection pool usage, if a pool is attached.
+func (s *BusinessMetricsService) collectPoolMetrics() {
+	if s.poolStats == nil {
+		return
+	}
+	active, idle, total, max := s.poolStats.PoolStats()
+	s.UpdateDatabaseConnectionPool(active, idle, total)
+	metrics.DatabaseConnectionPool.WithLabelValues("max").Set(float64(max))
+}
+
 // UpdateDatabaseConnectionPool updates database connection pool metrics
 func (s *BusinessMetricsService) UpdateDatabaseConnectionPool(active, idle, total int) {
 	metrics.DatabaseConnectionPool.WithLabelValues("active").Set(float64(active))
@@ -280,20 +252,26 @@ func (s *BusinessMetricsService) UpdateDatabaseConnectionPool(active, idle, tota
 	metrics.DatabaseConnectionPool.WithLabelValues("total").Set(float64(total))
 }
 
-// GetMetricsSummary returns a summary of current metrics
-func (s *BusinessMetricsService) GetMetricsSummary(ctx context.Context) map[string]interface{} {
+// GetMetricsSummary returns the business metrics as last collected
+func (s *BusinessMetricsService) GetMetricsSummary(ctx context.Context) *MetricsSummary {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	// Get current values from Prometheus metrics
-	summary := map[string]interface{}{
-		"last_update":          s.lastUpdate,
-		"active_users":         metrics.ActiveUsers,
-		"daily_active_users":   metrics.DailyActiveUsers,
-		"monthly_active_users": metrics.MonthlyActiveUsers,
-		"balance_total":        metrics.BalanceTotal,
-		"cache_hit_ratio":      metrics.CacheHitRatio,
+	rates := make(map[string]float64, len(s.successRates))
+	for txType, rate := range s.successRates {
+		rates[txType] = rate
 	}
-
+	summary := &MetricsSummary{
+		LastUpdate:              s.lastUpdate,
+		TotalUsers:              s.userActivity.Total,
+		ActiveUsers:             s.userActivity.ActiveLastHour,
+		DailyActiveUsers:        s.userActivity.ActiveLastDay,
+		MonthlyActiveUsers:      s.userActivity.ActiveLastMonth,
+		BalanceTotal:            s.balanceTotal,
+		TransactionSuccessRates: rates,
+		DatabaseHealthy:         s.databaseHealthy,
+	}
+	summary.CacheHitRatio, summary.CacheLookups = cache.ResponseHitRatio.Ratio()
+	summary.RedisHitRatio, summary.RedisLookups = cache.RedisHitRatio.Ratio()
 	return summary
 }
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/cache"
+)
+
+// invalidateUsers drops the cached responses of every user in userIDs.
+func invalidateUsers(ctx context.Context, invalidator domain.CacheInvalidator, userIDs ...*int) {
+	if invalidator == nil {
+		return
+	}
+	for _, userID := range userIDs {
+		if userID == nil {
+			continue
+		}
+		if err := invalidator.DeletePattern(ctx, cache.UserResponsesPattern(*userID)); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Int("user_id", *userID).Msg("Failed to invalidate cached responses")
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// CampaignServiceImpl implements domain.CampaignService.
+type CampaignServiceImpl struct {
+	repo     domain.CampaignRepository
+	uow      domain.UnitOfWork
+	cache    domain.CacheInvalidator
+	notifier domain.Notifier
+	audit    domain.AuditLogService
+	now      func() time.Time
+}
+
+// NewCampaignService creates a new CampaignServiceImpl.
+func NewCampaignService(repo domain.CampaignRepository, uow domain.UnitOfWork, cache domain.CacheInvalidator, notifier domain.Notifier, audit domain.AuditLogService) *CampaignServiceImpl {
+	return &CampaignServiceImpl{repo: repo, uow: uow, cache: cache, notifier: notifier, audit: audit, now: time.Now}
+}
+
+// List returns every campaign, newest first.
+func (s *CampaignServiceImpl) List(ctx context.Context) ([]*domain.Campaign, error) {
+	return s.repo.List(ctx)
+}
+
+// Get returns a campaign by ID.
+func (s *CampaignServiceImpl) Get(ctx context.Context, id int) (*domain.Campaign, error) {
+	campaign, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+	if campaign == nil {
+		return nil, domain.ErrCampaignNotFound
+	}
+	return campaign, nil
+}
+
+// Create creates a campaign.
+func (s *CampaignServiceImpl) Create(ctx context.Context, actorID int, campaign *domain.Campaign) error {
+	now := s.now()
+	if campaign.StartsAt.IsZero() {
+		campaign.StartsAt = now
+	}
+	if err := campaign.Validate(); err != nil {
+		return err
+	}
+	if !campaign.EndsAt.After(now) {
+		return &domain.ValidationError{Msg: "ends_at must be in the future"}
+	}
+
+	campaign.CreatedBy, campaign.Spent = actorID, 0
+	if err := s.repo.Create(ctx, campaign); err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+	s.record(ctx, actorID, campaign.ID, "create_campaign", campaign)
+	return nil
+}
+
+// Update changes a campaign's name, budget and end.
+func (s *CampaignServiceImpl) Update(ctx context.Context, actorID int, campaign *domain.Campaign) error {
+	current, err := s.Get(ctx, campaign.ID)
+	if err != nil {
+		return err
+	}
+	if campaign.EndsAt.IsZero() {
+		campaign.EndsAt = current.EndsAt
+	}
+	now := s.now()
+	if !campaign.EndsAt.Equal(current.EndsAt) && campaign.EndsAt.Before(now.Add(-time.Minute)) {
+		return &domain.ValidationError{Msg: "ends_at must not be in the past"}
+	}
+	campaign.Condition, campaign.Threshold, campaign.Bonus = current.Condition, current.Threshold, current.Bonus
+	campaign.StartsAt, campaign.CreatedBy, campaign.CreatedAt = current.StartsAt, current.CreatedBy, current.CreatedAt
+	if err := campaign.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.Update(ctx, campaign); err != nil {
+		return err
+	}
+	s.record(ctx, actorID, campaign.ID, "update_campaign", campaign)
+	return nil
+}
+
+// Stats returns how a campaign performed.
+func (s *CampaignServiceImpl) Stats(ctx context.Context, id int) (*domain.CampaignStats, error) {
+	stats, err := s.repo.Stats(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign stats: %w", err)
+	}
+	if stats == nil {
+		return nil, domain.ErrCampaignNotFound
+	}
+	return stats, nil
+}
+
+// RewardTransaction pays the payer the bonuses of the campaigns a transaction qualifies for.
+func (s *CampaignServiceImpl) RewardTransaction(ctx context.Context, tx *domain.Transaction) {
+	if tx.Status != "completed" {
+		return
+	}
+	payerID := tx.FromUserID
+	conditions := []string{domain.CampaignVolumeThreshold}
+	if tx.Type == "credit" {
+		payerID = tx.ToUserID
+		conditions = []string{domain.CampaignFirstDeposit, domain.CampaignVolumeThreshold}
+	}
+	if payerID == nil {
+		return
+	}
+
+	now := s.now()
+	for _, condition := range conditions {
+		campaigns, err := s.repo.Open(ctx, condition, *payerID, now)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("transaction_id", tx.ID).Msg("Failed to load campaigns")
+			return
+		}
+		for _, campaign := range campaigns {
+			qualified, err := s.qualifies(ctx, campaign, tx, *payerID)
+			if err != nil {
+				log.Ctx(ctx).Error().Err(err).Int("campaign_id", campaign.ID).Int("transaction_id", tx.ID).Msg("Failed to check campaign condition")
+				continue
+			}
+			if qualified {
+				s.award(ctx, campaign, tx, *payerID)
+			}
+		}
+	}
+}
+
+// qualifies reports whether tx meets a campaign's condition for userID
+func (s *CampaignServiceImpl) qualifies(ctx context.Context, campaign *domain.Campaign, tx *domain.Transaction, userID int) (bool, error) {
+	switch campaign.Condition {
+	case domain.CampaignFirstDeposit:
+		if tx.Amount < campaign.Threshold {
+			return false, nil
+		}
+		return s.repo.IsFirstDeposit(ctx, userID, tx.ID)
+	case domain.CampaignVolumeThreshold:
+		volume, err := s.repo.Volume(ctx, userID, campaign.StartsAt)
+		if err != nil {
+			return false, err
+		}
+		return volume >= campaign.Threshold, nil
+	}
+	return false, nil
+}
+
+// award credits a campaign's bonus to userID and charges it to the budget.
+func (s *CampaignServiceImpl) award(ctx context.Context, campaign *domain.Campaign, tx *domain.Transaction, userID int) {
+	bonus := &domain.Transaction{
+		FromUserID:  nil, // system
+		ToUserID:    &userID,
+		Amount:      campaign.Bonus,
+		Type:        "credit",
+		Status:      "completed",
+		Description: "Bonus: " + campaign.Name,
+	}
+	award := &domain.CampaignAward{CampaignID: campaign.ID, UserID: userID, TransactionID: tx.ID, Amount: campaign.Bonus}
+	err := retryOnBalanceConflict(func() error {
+		return s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+			if err := creditBalance(ctx, repos.Balances, userID, campaign.Bonus); err != nil {
+				return err
+			}
+			if err := repos.Transactions.Create(ctx, bonus); err != nil {
+				return err
+			}
+			award.BonusTransactionID = bonus.ID
+			return repos.Campaigns.Award(ctx, award)
+		})
+	})
+	switch {
+	case errors.Is(err, domain.ErrCampaignBonusAwarded), errors.Is(err, domain.ErrCampaignBudgetExhausted):
+		log.Ctx(ctx).Debug().Err(err).Int("campaign_id", campaign.ID).Int("user_id", userID).Msg("Campaign bonus not awarded")
+		return
+	case err != nil:
+		log.Ctx(ctx).Error().Err(err).Int("campaign_id", campaign.ID).Int("user_id", userID).Msg("Failed to award campaign bonus")
+		return
+	}
+
+	metrics.CampaignBonusesAwarded.WithLabelValues(campaign.Condition).Inc()
+	invalidateUsers(ctx, s.cache, &userID)
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, userID, domain.EventBonusAwarded, map[string]string{
+			"campaign":       campaign.Name,
+			"amount":         formatAmount(campaign.Bonus),
+			"transaction_id": strconv.Itoa(bonus.ID),
+		})
+	}
+}
+
+// record audits a change to a campaign; failures are only logged
+func (s *CampaignServiceImpl) record(ctx context.Context, actorID, id int, action string, campaign *domain.Campaign) {
+	details := "name=" + campaign.Name + " condition=" + campaign.Condition +
+		" bonus=" + formatAmount(campaign.Bonus) + " budget=" + formatAmount(campaign.Budget) +
+		" ends_at=" + campaign.EndsAt.UTC().Format(time.RFC3339)
+	if err := s.audit.Record(ctx, &actorID, "campaign", id, action, details); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("campaign_id", id).Int("actor_id", actorID).Msg("Failed to audit campaign change")
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// maxCohortReportMonths is the most months a cohort report covers
+const maxCohortReportMonths = 36
+
+// CohortServiceImpl implements domain.CohortService
+type CohortServiceImpl struct {
+	repo     domain.CohortRepository
+	months   int // months recomputed by each refresh, the current one included
+	interval time.Duration
+	runMu    sync.Mutex
+	stopChan chan struct{}
+}
+
+// NewCohortService creates a new CohortServiceImpl refreshing every interval.
+func NewCohortService(repo domain.CohortRepository, months int, interval time.Duration) *CohortServiceImpl {
+	return &CohortServiceImpl{
+		repo:     repo,
+		months:   months,
+		interval: interval,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Refresh recomputes the cohort and growth summary tables for the configured number of months.
+func (s *CohortServiceImpl) Refresh(ctx context.Context) error {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	since := monthsAgo(time.Now().UTC(), s.months)
+	if err := s.repo.RefreshCohorts(ctx, since); err != nil {
+		return fmt.Errorf("failed to refresh cohorts: %w", err)
+	}
+	if err := s.repo.RefreshGrowth(ctx, since); err != nil {
+		return fmt.Errorf("failed to refresh growth: %w", err)
+	}
+	log.Ctx(ctx).Debug().Time("since", since).Msg("Refreshed cohort analytics")
+	return nil
+}
+
+// GetReport retrieves the last months of cohorts and growth from the summary tables
+func (s *CohortServiceImpl) GetReport(ctx context.Context, months int) (*domain.CohortReport, error) {
+	if months < 1 || months > maxCohortReportMonths {
+		return nil, &domain.ValidationError{Msg: fmt.Sprintf("months must be between 1 and %d", maxCohortReportMonths)}
+	}
+	since := monthsAgo(time.Now().UTC(), months)
+
+	cohorts, err := s.repo.ListCohorts(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cohorts: %w", err)
+	}
+	growth, err := s.repo.ListGrowth(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list growth: %w", err)
+	}
+
+	report := &domain.CohortReport{
+		Cohorts: make([]*domain.CohortRetention, 0, len(cohorts)),
+		Growth:  make([]*domain.GrowthRate, 0, len(growth)),
+	}
+	for _, c := range cohorts {
+		report.Cohorts = append(report.Cohorts, &domain.CohortRetention{
+			SignupCohort:        c,
+			Retention7dPercent:  c.Retention7d(),
+			Retention30dPercent: c.Retention30d(),
+		})
+	}
+	for i, g := range growth {
+		rate := &domain.GrowthRate{MonthlyGrowth: g}
+		if i > 0 {
+			prev := growth[i-1]
+			rate.NewUsersGrowthPercent = percentChange(prev.NewUsers, g.NewUsers)
+			rate.ActiveUsersGrowthPercent = percentChange(prev.ActiveUsers, g.ActiveUsers)
+		}
+		report.Growth = append(report.Growth, rate)
+	}
+	return report, nil
+}
+
+// Start refreshes now and then every interval
+func (s *CohortServiceImpl) Start(ctx context.Context) {
+	log.Ctx(ctx).Info().Int("months", s.months).Dur("interval", s.interval).Msg("Starting cohort analytics")
+
+	go s.refreshLoop(ctx)
+}
+
+// Stop stops the periodic refresh
+func (s *CohortServiceImpl) Stop() {
+	log.Info().Msg("Stopping cohort analytics")
+	close(s.stopChan)
+}
+
+func (s *CohortServiceImpl) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Cohort analytics refresh failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// monthsAgo returns the first day of the month months-1 months before now's
+func monthsAgo(now time.Time, months int) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+}
+
+// percentChange returns the percent change from prev to cur, or nil when prev is 0
+func percentChange(prev, cur int) *float64 {
+	if prev == 0 {
+		return nil
+	}
+	change := float64(cur-prev) / float64(prev) * 100
+	return &change
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// stubCohorts implements domain.CohortRepository, serving its cohorts and
+// growth and recording the months it was asked for
+type stubCohorts struct {
+	cohorts    []*domain.SignupCohort
+	growth     []*domain.MonthlyGrowth
+	refreshErr error
+
+	refreshedCohorts, refreshedGrowth, listed []time.Time
+}
+
+func (r *stubCohorts) RefreshCohorts(ctx context.Context, since time.Time) error {
+	r.refreshedCohorts = append(r.refreshedCohorts, since)
+	return r.refreshErr
+}
+
+func (r *stubCohorts) RefreshGrowth(ctx context.Context, since time.Time) error {
+	r.refreshedGrowth = append(r.refreshedGrowth, since)
+	return nil
+}
+
+func (r *stubCohorts) ListCohorts(ctx context.Context, since time.Time) ([]*domain.SignupCohort, error) {
+	r.listed = append(r.listed, since)
+	return r.cohorts, nil
+}
+
+func (r *stubCohorts) ListGrowth(ctx context.Context, since time.Time) ([]*domain.MonthlyGrowth, error) {
+	return r.growth, nil
+}
+
+func TestCohortServiceImpl_Refresh(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("recomputes the configured months", func(t *testing.T) {
+		repo := &stubCohorts{}
+		require.NoError(t, NewCohortService(repo, 3, time.Hour).Refresh(ctx))
+
+		want := monthsAgo(time.Now().UTC(), 3)
+		assert.Equal(t, []time.Time{want}, repo.refreshedCohorts)
+		assert.Equal(t, []time.Time{want}, repo.refreshedGrowth)
+	})
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		failure := errors.New("deadlock detected")
+		repo := &stubCohorts{refreshErr: failure}
+
+		err := NewCohortService(repo, 3, time.Hour).Refresh(ctx)
+		assert.ErrorIs(t, err, failure)
+		assert.Empty(t, repo.refreshedGrowth)
+	})
+}
+
+func TestCohortServiceImpl_GetReport(t *testing.T) {
+	ctx := context.Background()
+	jan := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("rates cohorts and month-over-month growth", func(t *testing.T) {
+		repo := &stubCohorts{
+			cohorts: []*domain.SignupCohort{
+				{Month: jan, Signups: 10, Eligible7d: 8, Retained7d: 6, Eligible30d: 4, Retained30d: 1},
+				{Month: jan.AddDate(0, 1, 0), Signups: 3},
+			},
+			growth: []*domain.MonthlyGrowth{
+				{Month: jan, NewUsers: 0, ActiveUsers: 20},
+				{Month: jan.AddDate(0, 1, 0), NewUsers: 10, ActiveUsers: 25},
+				{Month: jan.AddDate(0, 2, 0), NewUsers: 5, ActiveUsers: 25},
+			},
+		}
+		report, err := NewCohortService(repo, 3, time.Hour).GetReport(ctx, 6)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Time{monthsAgo(time.Now().UTC(), 6)}, repo.listed)
+
+		require.Len(t, report.Cohorts, 2)
+		assert.Equal(t, 75.0, report.Cohorts[0].Retention7dPercent)
+		assert.Equal(t, 25.0, report.Cohorts[0].Retention30dPercent)
+		assert.Zero(t, report.Cohorts[1].Retention7dPercent, "no eligible users is no retention, not NaN")
+
+		require.Len(t, report.Growth, 3)
+		assert.Nil(t, report.Growth[0].NewUsersGrowthPercent, "the first month has nothing to compare with")
+		assert.Nil(t, report.Growth[1].NewUsersGrowthPercent, "growth from zero users is undefined")
+		assert.Equal(t, 25.0, *report.Growth[1].ActiveUsersGrowthPercent)
+		assert.Equal(t, -50.0, *report.Growth[2].NewUsersGrowthPercent)
+		assert.Equal(t, 0.0, *report.Growth[2].ActiveUsersGrowthPercent)
+	})
+
+	t.Run("no data is an empty report", func(t *testing.T) {
+		report, err := NewCohortService(&stubCohorts{}, 3, time.Hour).GetReport(ctx, 1)
+		require.NoError(t, err)
+		assert.NotNil(t, report.Cohorts)
+		assert.NotNil(t, report.Growth)
+	})
+
+	t.Run("months out of range", func(t *testing.T) {
+		svc := NewCohortService(&stubCohorts{}, 3, time.Hour)
+		for _, months := range []int{0, maxCohortReportMonths + 1} {
+			_, err := svc.GetReport(ctx, months)
+			var verr *domain.ValidationError
+			assert.ErrorAs(t, err, &verr, "months=%d", months)
+		}
+	})
+}
+
+func TestMonthsAgo(t *testing.T) {
+	tests := []struct {
+		name   string
+		now    time.Time
+		months int
+		want   time.Time
+	}{
+		{name: "this month", now: time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC), months: 1, want: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "across the year", now: time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), months: 3, want: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "from the last day of a long month", now: time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC), months: 4, want: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, monthsAgo(tt.now, tt.months))
+		})
+	}
+}
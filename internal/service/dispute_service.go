@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// disputeRefundKeyPrefix starts the idempotency key of a dispute's refund.
+const disputeRefundKeyPrefix = "dispute-refund:"
+
+// DisputeServiceImpl implements domain.DisputeService.
+type DisputeServiceImpl struct {
+	repo      domain.DisputeRepository
+	txRepo    domain.TransactionRepository
+	documents domain.DocumentRepository
+	uow       domain.UnitOfWork
+	cache     domain.CacheInvalidator
+	notifier  domain.Notifier
+	audit     domain.AuditLogService
+	window    time.Duration
+	now       func() time.Time
+}
+
+// NewDisputeService creates a new DisputeServiceImpl.
+func NewDisputeService(repo domain.DisputeRepository, txRepo domain.TransactionRepository, documents domain.DocumentRepository, uow domain.UnitOfWork, cache domain.CacheInvalidator, notifier domain.Notifier, audit domain.AuditLogService, window time.Duration) *DisputeServiceImpl {
+	return &DisputeServiceImpl{
+		repo:      repo,
+		txRepo:    txRepo,
+		documents: documents,
+		uow:       uow,
+		cache:     cache,
+		notifier:  notifier,
+		audit:     audit,
+		window:    window,
+		now:       time.Now,
+	}
+}
+
+// Open disputes a completed debit or transfer userID paid within the dispute window.
+func (s *DisputeServiceImpl) Open(ctx context.Context, userID, transactionID int, claim *domain.DisputeClaim) (*domain.Dispute, error) {
+	claim.Description = strings.TrimSpace(claim.Description)
+	if err := claim.Validate(); err != nil {
+		return nil, err
+	}
+	tx, err := s.txRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if tx == nil {
+		return nil, domain.ErrDisputeTransactionNotFound
+	}
+	if tx.FromUserID == nil || *tx.FromUserID != userID {
+		if tx.ToUserID != nil && *tx.ToUserID == userID {
+			return nil, domain.ErrDisputeNotPayer
+		}
+		// Strangers don't learn that the transaction exists
+		return nil, domain.ErrDisputeTransactionNotFound
+	}
+	if tx.Status != "completed" || (tx.Type != "debit" && tx.Type != "transfer") {
+		return nil, &domain.ValidationError{Msg: "only completed debits and transfers can be disputed"}
+	}
+	// Keys clients choose are scoped under their own prefix, so only the
+	// server's refunds and chargebacks carry these
+	if strings.HasPrefix(tx.IdempotencyKey, disputeRefundKeyPrefix) {
+		return nil, &domain.ValidationError{Msg: "refunds of disputes can't be disputed"}
+	}
+	if strings.HasPrefix(tx.IdempotencyKey, chargebackKeyPrefix) {
+		return nil, &domain.ValidationError{Msg: "chargebacks can't be disputed"}
+	}
+	if s.now().Sub(tx.CreatedAt) > s.window {
+		return nil, domain.ErrDisputeWindowPassed
+	}
+
+	documentIDs, err := s.ownDocuments(ctx, userID, claim.DocumentIDs)
+	if err != nil {
+		return nil, err
+	}
+	dispute := &domain.Dispute{
+		TransactionID: transactionID,
+		UserID:        userID,
+		Reason:        claim.Reason,
+		Description:   claim.Description,
+		DocumentIDs:   documentIDs,
+		Status:        domain.DisputeOpen,
+	}
+	if err := s.repo.Create(ctx, dispute); err != nil {
+		if errors.Is(err, domain.ErrDisputeExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create dispute: %w", err)
+	}
+	metrics.DisputesTotal.WithLabelValues(dispute.Status).Inc()
+	s.notify(ctx, dispute.UserID, "payer", dispute, tx)
+	return dispute, nil
+}
+
+// ownDocuments returns the distinct IDs of the user's documents cited.
+func (s *DisputeServiceImpl) ownDocuments(ctx context.Context, userID int, ids []int) ([]int, error) {
+	seen := make(map[int]bool, len(ids))
+	documentIDs := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		document, err := s.documents.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get document: %w", err)
+		}
+		if document == nil || document.UserID != userID {
+			return nil, &domain.ValidationError{Msg: fmt.Sprintf("document %d not found", id)}
+		}
+		documentIDs = append(documentIDs, id)
+	}
+	return documentIDs, nil
+}
+
+// Get returns a dispute to the user who opened it or to an admin; others are told it doesn't exist.
+func (s *DisputeServiceImpl) Get(ctx context.Context, id, userID int, isAdmin bool) (*domain.Dispute, error) {
+	dispute, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute: %w", err)
+	}
+	if dispute == nil || (!isAdmin && dispute.UserID != userID) {
+		return nil, domain.ErrDisputeNotFound
+	}
+	return dispute, nil
+}
+
+// ListByUser returns a page of a user's disputes, newest first.
+func (s *DisputeServiceImpl) ListByUser(ctx context.Context, userID, limit, offset int) ([]*domain.Dispute, error) {
+	return s.repo.ListByUser(ctx, userID, limit, offset)
+}
+
+// ListByStatus returns a page of the disputes with a status, oldest first.
+func (s *DisputeServiceImpl) ListByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Dispute, error) {
+	switch status {
+	case domain.DisputeOpen, domain.DisputeInvestigating, domain.DisputeResolved, domain.DisputeRefunded:
+	default:
+		return nil, &domain.ValidationError{Msg: "status must be open, investigating, resolved or refunded"}
+	}
+	return s.repo.ListByStatus(ctx, status, limit, offset)
+}
+
+// Investigate marks an open dispute as being investigated by an admin.
+func (s *DisputeServiceImpl) Investigate(ctx context.Context, id, actorID int) (*domain.Dispute, error) {
+	dispute, tx, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status != domain.DisputeOpen {
+		return nil, domain.ErrDisputeClosed
+	}
+	dispute.Status, dispute.HandledBy = domain.DisputeInvestigating, &actorID
+	if err := s.repo.UpdateStatus(ctx, dispute); err != nil {
+		return nil, err
+	}
+	s.changed(ctx, actorID, "investigate_dispute", dispute, tx)
+	return dispute, nil
+}
+
+// Resolve closes an open dispute without a refund; the resolution tells the user why.
+func (s *DisputeServiceImpl) Resolve(ctx context.Context, id, actorID int, resolution string) (*domain.Dispute, error) {
+	resolution = strings.TrimSpace(resolution)
+	if resolution == "" {
+		return nil, &domain.ValidationError{Msg: "a resolution is required to close a dispute without a refund"}
+	}
+	dispute, tx, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !dispute.IsOpen() {
+		return nil, domain.ErrDisputeClosed
+	}
+	dispute.Status, dispute.Resolution, dispute.HandledBy = domain.DisputeResolved, resolution, &actorID
+	if err := s.repo.UpdateStatus(ctx, dispute); err != nil {
+		return nil, err
+	}
+	s.changed(ctx, actorID, "resolve_dispute", dispute, tx)
+	return dispute, nil
+}
+
+// Refund closes an open dispute by reversing the disputed payment.
+func (s *DisputeServiceImpl) Refund(ctx context.Context, id, actorID int, resolution string) (*domain.Dispute, error) {
+	dispute, tx, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !dispute.IsOpen() {
+		return nil, domain.ErrDisputeClosed
+	}
+	payerID := *tx.FromUserID
+
+	err = retryOnBalanceConflict(func() error {
+		return s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+			refund := &domain.Transaction{
+				ToUserID:       &payerID,
+				Amount:         tx.Amount,
+				Type:           "credit",
+				Status:         "completed",
+				IdempotencyKey: disputeRefundKeyPrefix + strconv.Itoa(dispute.ID),
+				Description:    fmt.Sprintf("Refund of disputed transaction #%d", tx.ID),
+			}
+			if tx.Type == "transfer" {
+				refund.FromUserID, refund.Type = tx.ToUserID, "transfer"
+				if err := transferBalance(ctx, repos.Balances, *tx.ToUserID, payerID, tx.Amount, 0); err != nil {
+					return err
+				}
+			} else if err := creditBalance(ctx, repos.Balances, payerID, tx.Amount); err != nil {
+				return err
+			}
+			if err := repos.Transactions.Create(ctx, refund); err != nil {
+				return err
+			}
+			dispute.Status, dispute.Resolution, dispute.HandledBy = domain.DisputeRefunded, strings.TrimSpace(resolution), &actorID
+			dispute.RefundTransactionID = &refund.ID
+			return repos.Disputes.UpdateStatus(ctx, dispute)
+		})
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInsufficientFunds) || errors.Is(err, domain.ErrAccountFrozen) || errors.Is(err, domain.ErrDisputeClosed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to refund dispute: %w", err)
+	}
+
+	invalidateUsers(ctx, s.cache, tx.FromUserID, tx.ToUserID)
+	s.changed(ctx, actorID, "refund_dispute", dispute, tx)
+	if tx.Type == "transfer" {
+		s.notify(ctx, *tx.ToUserID, "payee", dispute, tx)
+	}
+	return dispute, nil
+}
+
+// load returns an admin's view of a dispute with its transaction
+func (s *DisputeServiceImpl) load(ctx context.Context, id int) (*domain.Dispute, *domain.Transaction, error) {
+	dispute, err := s.Get(ctx, id, 0, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	tx, err := s.txRepo.GetByID(ctx, dispute.TransactionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if tx == nil || tx.FromUserID == nil {
+		return nil, nil, fmt.Errorf("disputed transaction %d is missing", dispute.TransactionID)
+	}
+	return dispute, tx, nil
+}
+
+// changed records an admin's change to a dispute and tells its user
+func (s *DisputeServiceImpl) changed(ctx context.Context, actorID int, action string, dispute *domain.Dispute, tx *domain.Transaction) {
+	metrics.DisputesTotal.WithLabelValues(dispute.Status).Inc()
+	details := "transaction_id=" + strconv.Itoa(dispute.TransactionID) + " status=" + dispute.Status
+	if err := s.audit.Record(ctx, &actorID, "dispute", dispute.ID, action, details); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("dispute_id", dispute.ID).Int("actor_id", actorID).Msg("Failed to audit dispute change")
+	}
+	s.notify(ctx, dispute.UserID, "payer", dispute, tx)
+}
+
+// notify tells userID about a dispute's status.
+func (s *DisputeServiceImpl) notify(ctx context.Context, userID int, role string, dispute *domain.Dispute, tx *domain.Transaction) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.Notify(ctx, userID, domain.EventDisputeUpdated, map[string]string{
+		"role":           role,
+		"dispute_id":     strconv.Itoa(dispute.ID),
+		"transaction_id": strconv.Itoa(tx.ID),
+		"status":         dispute.Status,
+		"amount":         formatAmount(tx.Amount),
+		"resolution":     dispute.Resolution,
+	})
+}
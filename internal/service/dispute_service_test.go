@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// userDocuments implements the document lookup of domain.DocumentRepository
+type userDocuments struct {
+	domain.DocumentRepository
+	owners map[int]int // document ID -> user ID
+}
+
+func (d userDocuments) GetByID(ctx context.Context, id int) (*domain.Document, error) {
+	userID, ok := d.owners[id]
+	if !ok {
+		return nil, nil
+	}
+	return &domain.Document{ID: id, UserID: userID}, nil
+}
+
+// disputeTest is a dispute service over a store where user 1 paid a debit of
+// 20 and a transfer of 30 to user 2, and owns documents 5 and 6
+type disputeTest struct {
+	store       *memoryStore
+	svc         *DisputeServiceImpl
+	notifier    *recordingNotifier
+	invalidator *recordingInvalidator
+	debit       *domain.Transaction
+	transfer    *domain.Transaction
+}
+
+func newDisputeTest(t *testing.T) *disputeTest {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	transactions := newMemoryTransactionService(store)
+	debit, err := transactions.Debit(ctx, 1, 20, "")
+	require.NoError(t, err)
+	transfer, err := transactions.Transfer(ctx, 1, 2, 30, "")
+	require.NoError(t, err)
+
+	notifier, invalidator := &recordingNotifier{}, &recordingInvalidator{}
+	documents := userDocuments{owners: map[int]int{5: 1, 6: 1, 7: 2}}
+	svc := NewDisputeService(&memoryDisputes{store: store}, &memoryTransactions{store: store}, documents, store, invalidator, notifier, discardAudit{}, 24*time.Hour)
+	return &disputeTest{store: store, svc: svc, notifier: notifier, invalidator: invalidator, debit: debit, transfer: transfer}
+}
+
+// open has user 1 dispute a transaction
+func (d *disputeTest) open(t *testing.T, tx *domain.Transaction) *domain.Dispute {
+	dispute, err := d.svc.Open(context.Background(), 1, tx.ID, &domain.DisputeClaim{Reason: "not_received", Description: "never arrived"})
+	require.NoError(t, err)
+	return dispute
+}
+
+func TestDisputeServiceImpl_Open(t *testing.T) {
+	ctx := context.Background()
+	claim := func(documentIDs ...int) *domain.DisputeClaim {
+		return &domain.DisputeClaim{Reason: "unauthorized", Description: "  not me  ", DocumentIDs: documentIDs}
+	}
+
+	t.Run("the payer disputes a payment with their documents", func(t *testing.T) {
+		d := newDisputeTest(t)
+
+		dispute, err := d.svc.Open(ctx, 1, d.transfer.ID, claim(5, 6, 5))
+		require.NoError(t, err)
+		assert.Equal(t, domain.DisputeOpen, dispute.Status)
+		assert.Equal(t, "not me", dispute.Description)
+		assert.Equal(t, []int{5, 6}, dispute.DocumentIDs)
+		require.Len(t, d.notifier.sent, 1)
+		assert.Equal(t, "payer", d.notifier.sent[0]["role"])
+		assert.Equal(t, domain.DisputeOpen, d.notifier.sent[0]["status"])
+
+		_, err = d.svc.Open(ctx, 1, d.transfer.ID, claim())
+		assert.ErrorIs(t, err, domain.ErrDisputeExists)
+	})
+
+	t.Run("only the payer can dispute", func(t *testing.T) {
+		d := newDisputeTest(t)
+
+		_, err := d.svc.Open(ctx, 2, d.transfer.ID, claim())
+		assert.ErrorIs(t, err, domain.ErrDisputeNotPayer)
+		_, err = d.svc.Open(ctx, 3, d.transfer.ID, claim())
+		assert.ErrorIs(t, err, domain.ErrDisputeTransactionNotFound, "strangers don't learn the transaction exists")
+		_, err = d.svc.Open(ctx, 1, 999, claim())
+		assert.ErrorIs(t, err, domain.ErrDisputeTransactionNotFound)
+	})
+
+	t.Run("within the dispute window", func(t *testing.T) {
+		d := newDisputeTest(t)
+		d.svc.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+
+		_, err := d.svc.Open(ctx, 1, d.debit.ID, claim())
+		assert.ErrorIs(t, err, domain.ErrDisputeWindowPassed)
+	})
+
+	t.Run("citing only the user's own documents", func(t *testing.T) {
+		d := newDisputeTest(t)
+
+		for _, id := range []int{7, 8} {
+			_, err := d.svc.Open(ctx, 1, d.debit.ID, claim(5, id))
+			var verr *domain.ValidationError
+			assert.ErrorAs(t, err, &verr, "document %d", id)
+		}
+	})
+
+	t.Run("payments only, and not refunds or chargebacks", func(t *testing.T) {
+		d := newDisputeTest(t)
+		txRepo := &memoryTransactions{store: d.store}
+		one := 1
+		for name, tx := range map[string]*domain.Transaction{
+			"credit":     {FromUserID: &one, Amount: 5, Type: "credit", Status: "completed"},
+			"failed":     {FromUserID: &one, Amount: 5, Type: "debit", Status: "failed"},
+			"refund":     {FromUserID: &one, Amount: 5, Type: "transfer", Status: "completed", IdempotencyKey: disputeRefundKeyPrefix + "9"},
+			"chargeback": {FromUserID: &one, Amount: 5, Type: "debit", Status: "completed", IdempotencyKey: chargebackKeyPrefix + "9"},
+		} {
+			require.NoError(t, txRepo.Create(ctx, tx))
+			_, err := d.svc.Open(ctx, 1, tx.ID, claim())
+			var verr *domain.ValidationError
+			assert.ErrorAs(t, err, &verr, name)
+		}
+	})
+}
+
+func TestDisputeServiceImpl_Triage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("investigate then resolve without a refund", func(t *testing.T) {
+		d := newDisputeTest(t)
+		dispute := d.open(t, d.debit)
+
+		investigated, err := d.svc.Investigate(ctx, dispute.ID, 9)
+		require.NoError(t, err)
+		assert.Equal(t, domain.DisputeInvestigating, investigated.Status)
+		assert.Equal(t, 9, *investigated.HandledBy)
+		_, err = d.svc.Investigate(ctx, dispute.ID, 9)
+		assert.ErrorIs(t, err, domain.ErrDisputeClosed)
+
+		_, err = d.svc.Resolve(ctx, dispute.ID, 9, " ")
+		var verr *domain.ValidationError
+		assert.ErrorAs(t, err, &verr, "a resolution is required")
+		resolved, err := d.svc.Resolve(ctx, dispute.ID, 9, "merchant showed proof of delivery")
+		require.NoError(t, err)
+		assert.Equal(t, domain.DisputeResolved, resolved.Status)
+		assert.NotNil(t, resolved.ClosedAt)
+		assert.Equal(t, "merchant showed proof of delivery", d.notifier.sent[len(d.notifier.sent)-1]["resolution"])
+
+		_, err = d.svc.Refund(ctx, dispute.ID, 9, "")
+		assert.ErrorIs(t, err, domain.ErrDisputeClosed)
+		amount, _ := d.store.balance(1)
+		assert.Equal(t, 50.0, amount, "a closed dispute is never refunded")
+	})
+
+	t.Run("only admins and the payer see a dispute", func(t *testing.T) {
+		d := newDisputeTest(t)
+		dispute := d.open(t, d.debit)
+
+		_, err := d.svc.Get(ctx, dispute.ID, 1, false)
+		assert.NoError(t, err)
+		_, err = d.svc.Get(ctx, dispute.ID, 9, true)
+		assert.NoError(t, err)
+		_, err = d.svc.Get(ctx, dispute.ID, 2, false)
+		assert.ErrorIs(t, err, domain.ErrDisputeNotFound)
+	})
+
+	t.Run("listing by a known status", func(t *testing.T) {
+		d := newDisputeTest(t)
+		d.open(t, d.debit)
+
+		open, err := d.svc.ListByStatus(ctx, domain.DisputeOpen, 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, open, 1)
+		_, err = d.svc.ListByStatus(ctx, "closed", 10, 0)
+		var verr *domain.ValidationError
+		assert.ErrorAs(t, err, &verr)
+	})
+}
+
+func TestDisputeServiceImpl_Refund(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("a debit is credited back to the payer", func(t *testing.T) {
+		d := newDisputeTest(t)
+		dispute := d.open(t, d.debit)
+
+		refunded, err := d.svc.Refund(ctx, dispute.ID, 9, "duplicate charge")
+		require.NoError(t, err)
+		assert.Equal(t, domain.DisputeRefunded, refunded.Status)
+		require.NotNil(t, refunded.RefundTransactionID)
+
+		refund, _ := (&memoryTransactions{store: d.store}).GetByID(ctx, *refunded.RefundTransactionID)
+		assert.Equal(t, "credit", refund.Type)
+		assert.Equal(t, 20.0, refund.Amount)
+		assert.Equal(t, 1, *refund.ToUserID)
+		amount, _ := d.store.balance(1)
+		assert.Equal(t, 70.0, amount)
+		assert.True(t, d.invalidator.invalidated(1))
+	})
+
+	t.Run("a transfer is moved back from its recipient", func(t *testing.T) {
+		d := newDisputeTest(t)
+		dispute := d.open(t, d.transfer)
+
+		_, err := d.svc.Refund(ctx, dispute.ID, 9, "")
+		require.NoError(t, err)
+		payer, _ := d.store.balance(1)
+		payee, _ := d.store.balance(2)
+		assert.Equal(t, 80.0, payer)
+		assert.Zero(t, payee)
+		assert.True(t, d.invalidator.invalidated(1))
+		assert.True(t, d.invalidator.invalidated(2))
+		last := d.notifier.sent[len(d.notifier.sent)-1]
+		assert.Equal(t, "payee", last["role"])
+		assert.Equal(t, domain.DisputeRefunded, last["status"])
+	})
+
+	t.Run("a recipient who spent the funds blocks the refund", func(t *testing.T) {
+		d := newDisputeTest(t)
+		dispute := d.open(t, d.transfer)
+		d.store.setBalance(2, 10, 0)
+
+		_, err := d.svc.Refund(ctx, dispute.ID, 9, "")
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+		stored, _ := d.svc.Get(ctx, dispute.ID, 0, true)
+		assert.Equal(t, domain.DisputeOpen, stored.Status)
+		assert.Len(t, d.store.committed(), 2, "no refund transaction")
+	})
+
+	t.Run("a dispute closed meanwhile is not refunded", func(t *testing.T) {
+		d := newDisputeTest(t)
+		dispute := d.open(t, d.debit)
+		// Another admin resolves it between the load and the commit
+		d.store.beforeUpdate = func() {
+			_, err := d.svc.Resolve(ctx, dispute.ID, 8, "not our fault")
+			require.NoError(t, err)
+		}
+
+		_, err := d.svc.Refund(ctx, dispute.ID, 9, "")
+		assert.ErrorIs(t, err, domain.ErrDisputeClosed)
+		amount, _ := d.store.balance(1)
+		assert.Equal(t, 50.0, amount)
+		assert.Len(t, d.store.committed(), 2, "no refund transaction")
+	})
+}
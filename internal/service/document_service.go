@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// maxDocumentFilename bounds the stored name of a document, in characters
+const maxDocumentFilename = 255
+
+// DocumentServiceImpl implements domain.DocumentService.
+type DocumentServiceImpl struct {
+	repo     domain.DocumentRepository
+	users    domain.UserRepository
+	store    domain.ObjectStore
+	scanner  domain.VirusScanner
+	secret   []byte // HMAC key for download links
+	maxBytes int
+	linkTTL  time.Duration
+	now      func() time.Time
+}
+
+// NewDocumentService creates a new DocumentServiceImpl keeping files in store.
+func NewDocumentService(repo domain.DocumentRepository, users domain.UserRepository, store domain.ObjectStore, scanner domain.VirusScanner, secret string, maxBytes int, linkTTL time.Duration) *DocumentServiceImpl {
+	return &DocumentServiceImpl{
+		repo:     repo,
+		users:    users,
+		store:    store,
+		scanner:  scanner,
+		secret:   []byte(secret),
+		maxBytes: maxBytes,
+		linkTTL:  linkTTL,
+		now:      time.Now,
+	}
+}
+
+// Upload stores a PDF, PNG or JPEG file for a user.
+func (s *DocumentServiceImpl) Upload(ctx context.Context, userID, actorID int, upload *domain.DocumentUpload) (*domain.Document, error) {
+	if len(upload.Data) == 0 {
+		return nil, &domain.ValidationError{Msg: "file must not be empty"}
+	}
+	if len(upload.Data) > s.maxBytes {
+		return nil, &domain.ValidationError{Msg: fmt.Sprintf("file must be at most %d bytes", s.maxBytes)}
+	}
+	contentType := http.DetectContentType(upload.Data)
+	ext, ok := domain.DocumentExtensions[contentType]
+	if !ok {
+		return nil, &domain.ValidationError{Msg: "file must be a PDF, PNG or JPEG"}
+	}
+	kind := upload.Kind
+	if kind == "" {
+		kind = "other"
+	}
+	known := false
+	for _, k := range domain.DocumentKinds {
+		known = known || kind == k
+	}
+	if !known {
+		return nil, &domain.ValidationError{Msg: "kind must be identity, proof_of_address, bank_statement or other"}
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, domain.ErrDocumentUserNotFound
+	}
+	if user.IsErased() {
+		return nil, domain.ErrUserErased
+	}
+
+	scanStatus := domain.DocumentScanUnscanned
+	if s.scanner != nil {
+		threat, err := s.scanner.Scan(ctx, upload.Data)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("user_id", userID).Msg("Virus scan failed")
+			return nil, domain.ErrDocumentScanUnavailable
+		}
+		if threat != "" {
+			metrics.DocumentUploadsTotal.WithLabelValues("infected").Inc()
+			log.Ctx(ctx).Warn().Int("user_id", userID).Int("actor_id", actorID).Str("threat", threat).Msg("Refused infected document")
+			return nil, domain.ErrDocumentInfected
+		}
+		scanStatus = domain.DocumentScanClean
+	}
+
+	sum := sha256.Sum256(upload.Data)
+	document := &domain.Document{
+		UserID:      userID,
+		Kind:        kind,
+		Filename:    documentFilename(upload.Filename, ext),
+		ContentType: contentType,
+		SizeBytes:   len(upload.Data),
+		SHA256:      hex.EncodeToString(sum[:]),
+		ScanStatus:  scanStatus,
+		UploadedBy:  actorID,
+		ObjectKey:   fmt.Sprintf("documents/%d/%s%s", userID, uuid.NewString(), ext),
+	}
+	if err := s.store.Put(ctx, document.ObjectKey, contentType, upload.Data); err != nil {
+		return nil, fmt.Errorf("failed to store document: %w", err)
+	}
+	if err := s.repo.Create(ctx, document); err != nil {
+		s.deleteObject(ctx, document.ObjectKey)
+		return nil, fmt.Errorf("failed to create document: %w", err)
+	}
+	metrics.DocumentUploadsTotal.WithLabelValues("stored").Inc()
+	return document, nil
+}
+
+// documentFilename cleans the name a client gave a file down to its base name.
+func documentFilename(name, ext string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "document" + ext
+	}
+	for utf8.RuneCountInString(name) > maxDocumentFilename {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+// List returns a page of a user's documents, newest first.
+func (s *DocumentServiceImpl) List(ctx context.Context, userID, limit, offset int) ([]*domain.Document, error) {
+	return s.repo.ListByUser(ctx, userID, limit, offset)
+}
+
+// Get returns one of a user's documents.
+func (s *DocumentServiceImpl) Get(ctx context.Context, userID, id int) (*domain.Document, error) {
+	document, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if document == nil || document.UserID != userID {
+		return nil, domain.ErrDocumentNotFound
+	}
+	return document, nil
+}
+
+// Link signs a download link for a document, valid for the link TTL.
+func (s *DocumentServiceImpl) Link(document *domain.Document) *domain.DocumentLink {
+	expiresAt := s.now().Add(s.linkTTL).Truncate(time.Second)
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%d", document.ID, expiresAt.Unix())))
+	return &domain.DocumentLink{Token: payload + "." + s.sign(payload), ExpiresAt: expiresAt}
+}
+
+// Download returns the document a signed link grants and the file's contents.
+func (s *DocumentServiceImpl) Download(ctx context.Context, token string) (*domain.Document, []byte, error) {
+	id, expiresAt, ok := s.verifyToken(token)
+	if !ok {
+		return nil, nil, domain.ErrDocumentLinkInvalid
+	}
+	if s.now().Unix() >= expiresAt {
+		return nil, nil, domain.ErrDocumentLinkExpired
+	}
+	document, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if document == nil {
+		return nil, nil, domain.ErrDocumentNotFound
+	}
+	data, _, err := s.store.Get(ctx, document.ObjectKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read document: %w", err)
+	}
+	if data == nil {
+		return nil, nil, domain.ErrDocumentNotFound
+	}
+	return document, data, nil
+}
+
+// Delete removes one of a user's documents, then its file.
+func (s *DocumentServiceImpl) Delete(ctx context.Context, userID, id int) error {
+	document, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	s.deleteObject(ctx, document.ObjectKey)
+	return nil
+}
+
+// deleteObject deletes a file no longer referenced by a document.
+func (s *DocumentServiceImpl) deleteObject(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("Failed to delete document file")
+	}
+}
+
+// verifyToken checks a link token's signature and returns the document ID and expiry it encodes
+func (s *DocumentServiceImpl) verifyToken(token string) (int, int64, bool) {
+	payload, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(s.sign(payload))) {
+		return 0, 0, false
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return 0, 0, false
+	}
+	idStr, expiresStr, found := strings.Cut(string(raw), ".")
+	if !found {
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	expiresAt, err := strconv.ParseInt(expiresStr, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return id, expiresAt, true
+}
+
+// sign returns the base64url HMAC-SHA256 of payload
+func (s *DocumentServiceImpl) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
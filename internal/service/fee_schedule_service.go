@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// FeeScheduleServiceImpl implements domain.FeeScheduleService.
+type FeeScheduleServiceImpl struct {
+	repo  domain.FeeScheduleRepository
+	users domain.UserRepository
+	audit domain.AuditLogService
+	now   func() time.Time
+}
+
+// NewFeeScheduleService creates a new FeeScheduleServiceImpl.
+func NewFeeScheduleService(repo domain.FeeScheduleRepository, users domain.UserRepository, audit domain.AuditLogService) *FeeScheduleServiceImpl {
+	return &FeeScheduleServiceImpl{repo: repo, users: users, audit: audit, now: time.Now}
+}
+
+// List returns the schedules, of txType unless it is empty, newest version first.
+func (s *FeeScheduleServiceImpl) List(ctx context.Context, txType string) ([]*domain.FeeSchedule, error) {
+	return s.repo.List(ctx, txType)
+}
+
+// Get returns a schedule by ID.
+func (s *FeeScheduleServiceImpl) Get(ctx context.Context, id int) (*domain.FeeSchedule, error) {
+	schedule, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee schedule: %w", err)
+	}
+	if schedule == nil {
+		return nil, domain.ErrFeeScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// Create creates a schedule version.
+func (s *FeeScheduleServiceImpl) Create(ctx context.Context, actorID int, schedule *domain.FeeSchedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	now := s.now()
+	if schedule.EffectiveFrom.IsZero() {
+		schedule.EffectiveFrom = now
+	}
+	if schedule.EffectiveFrom.Before(now.Add(-time.Minute)) {
+		return &domain.ValidationError{Msg: "effective_from must not be in the past"}
+	}
+
+	schedule.CreatedBy = actorID
+	if err := s.repo.Create(ctx, schedule); err != nil {
+		return err
+	}
+	s.record(ctx, actorID, schedule.ID, "create_fee_schedule", schedule)
+	return nil
+}
+
+// Update changes the effective time and tiers of a schedule not yet in effect.
+func (s *FeeScheduleServiceImpl) Update(ctx context.Context, actorID int, schedule *domain.FeeSchedule) error {
+	current, err := s.Get(ctx, schedule.ID)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	if !current.EffectiveFrom.After(now) {
+		return domain.ErrFeeScheduleInEffect
+	}
+	// The type and role identify the schedule being versioned
+	schedule.TransactionType, schedule.Role = current.TransactionType, current.Role
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	if schedule.EffectiveFrom.IsZero() {
+		schedule.EffectiveFrom = current.EffectiveFrom
+	}
+	if schedule.EffectiveFrom.Before(now) {
+		return &domain.ValidationError{Msg: "effective_from must not be in the past"}
+	}
+
+	if err := s.repo.Update(ctx, schedule); err != nil {
+		return err
+	}
+	schedule.CreatedBy, schedule.CreatedAt = current.CreatedBy, current.CreatedAt
+	s.record(ctx, actorID, schedule.ID, "update_fee_schedule", schedule)
+	return nil
+}
+
+// Delete deletes a schedule not yet in effect.
+func (s *FeeScheduleServiceImpl) Delete(ctx context.Context, actorID, id int) error {
+	current, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !current.EffectiveFrom.After(s.now()) {
+		return domain.ErrFeeScheduleInEffect
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.record(ctx, actorID, id, "delete_fee_schedule", current)
+	return nil
+}
+
+// Quote prices a transaction by the schedule in effect now.
+func (s *FeeScheduleServiceImpl) Quote(ctx context.Context, txType string, payerID int, amount float64) (*domain.FeeQuote, error) {
+	payer, err := s.users.GetByID(ctx, payerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payer: %w", err)
+	}
+	role := ""
+	if payer != nil {
+		role = payer.Role
+	}
+	now := s.now()
+	schedule, err := s.repo.InEffect(ctx, txType, role, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee schedule: %w", err)
+	}
+	if schedule == nil {
+		return nil, nil
+	}
+	utc := now.UTC()
+	monthStart := time.Date(utc.Year(), utc.Month(), 1, 0, 0, 0, 0, time.UTC)
+	volume, err := s.repo.MonthlyVolume(ctx, payerID, txType, monthStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monthly volume: %w", err)
+	}
+	return &domain.FeeQuote{Amount: schedule.Fee(amount, volume), ScheduleID: schedule.ID}, nil
+}
+
+// record audits a change to a schedule; failures are only logged
+func (s *FeeScheduleServiceImpl) record(ctx context.Context, actorID, id int, action string, schedule *domain.FeeSchedule) {
+	details := "type=" + schedule.TransactionType + " role=" + schedule.Role +
+		" effective_from=" + schedule.EffectiveFrom.UTC().Format(time.RFC3339) + " tiers=" + strconv.Itoa(len(schedule.Tiers))
+	if err := s.audit.Record(ctx, &actorID, "fee_schedule", id, action, details); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("fee_schedule_id", id).Int("actor_id", actorID).Msg("Failed to audit fee schedule change")
+	}
+}
@@ -0,0 +1,507 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// Idempotency key prefixes of the transactions funding credits and settling their chargebacks
+const (
+	// fundingKeyPrefix is followed by the funding source's ID, a colon and
+	// the provider's payment ID
+	fundingKeyPrefix = "funding:"
+	// chargebackKeyPrefix is followed by the chargeback's ID
+	chargebackKeyPrefix = "chargeback:"
+	// chargebackWonKeyPrefix is followed by the chargeback's ID
+	chargebackWonKeyPrefix = "chargeback-won:"
+)
+
+// maxExternalIDLength bounds the provider IDs of payments and chargebacks.
+const maxExternalIDLength = 200
+
+// FundingServiceImpl implements domain.FundingService.
+type FundingServiceImpl struct {
+	repo         domain.FundingRepository
+	users        domain.UserRepository
+	balances     domain.BalanceRepository
+	transactions domain.TransactionService
+	uow          domain.UnitOfWork
+	cache        domain.CacheInvalidator
+	notifier     domain.Notifier
+	audit        domain.AuditLogService
+	policy       domain.ChargebackPolicy
+	now          func() time.Time
+}
+
+// NewFundingService creates a new FundingServiceImpl.
+func NewFundingService(repo domain.FundingRepository, users domain.UserRepository, balances domain.BalanceRepository, transactions domain.TransactionService, uow domain.UnitOfWork, cache domain.CacheInvalidator, notifier domain.Notifier, audit domain.AuditLogService, policy domain.ChargebackPolicy) *FundingServiceImpl {
+	return &FundingServiceImpl{
+		repo:         repo,
+		users:        users,
+		balances:     balances,
+		transactions: transactions,
+		uow:          uow,
+		cache:        cache,
+		notifier:     notifier,
+		audit:        audit,
+		policy:       policy,
+		now:          time.Now,
+	}
+}
+
+// AddSource registers a card or bank account userID funds their balance from.
+func (s *FundingServiceImpl) AddSource(ctx context.Context, userID int, source *domain.FundingSource) (*domain.FundingSource, error) {
+	source.UserID, source.Status = userID, domain.FundingSourceActive
+	source.Provider = strings.ToLower(strings.TrimSpace(source.Provider))
+	source.ExternalRef = strings.TrimSpace(source.ExternalRef)
+	if err := source.Validate(); err != nil {
+		return nil, err
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, domain.ErrFundingUserNotFound
+	}
+	if err := s.repo.CreateSource(ctx, source); err != nil {
+		if errors.Is(err, domain.ErrFundingSourceExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create funding source: %w", err)
+	}
+	return source, nil
+}
+
+// ListSources returns a user's funding sources, removed ones included.
+func (s *FundingServiceImpl) ListSources(ctx context.Context, userID int) ([]*domain.FundingSource, error) {
+	return s.repo.ListSources(ctx, userID)
+}
+
+// RemoveSource removes one of userID's funding sources.
+func (s *FundingServiceImpl) RemoveSource(ctx context.Context, userID, id int) error {
+	if _, err := s.source(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.RemoveSource(ctx, id)
+}
+
+// source returns one of userID's funding sources
+func (s *FundingServiceImpl) source(ctx context.Context, userID, id int) (*domain.FundingSource, error) {
+	source, err := s.repo.GetSource(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding source: %w", err)
+	}
+	if source == nil || source.UserID != userID {
+		return nil, domain.ErrFundingSourceNotFound
+	}
+	return source, nil
+}
+
+// Fund credits userID with a payment from one of their active funding sources.
+func (s *FundingServiceImpl) Fund(ctx context.Context, userID, sourceID int, amount float64, externalID string) (*domain.Transaction, error) {
+	externalID = strings.TrimSpace(externalID)
+	if externalID == "" || len(externalID) > maxExternalIDLength {
+		return nil, &domain.ValidationError{Msg: "external_id must be 1 to 200 characters"}
+	}
+	if amount <= 0 {
+		return nil, &domain.ValidationError{Msg: "amount must be positive"}
+	}
+	source, err := s.source(ctx, userID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if source.Status != domain.FundingSourceActive {
+		return nil, domain.ErrFundingSourceRemoved
+	}
+	bal, err := s.balances.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	if bal != nil && bal.IsFrozen() {
+		return nil, domain.ErrAccountFrozen
+	}
+
+	tx, err := s.transactions.Credit(ctx, userID, amount, fundingKeyPrefix+strconv.Itoa(sourceID)+":"+externalID)
+	if err != nil {
+		return nil, err
+	}
+	// A retried payment links the credit it returned, in case the first
+	// attempt stopped before doing so
+	credit := &domain.FundedCredit{
+		TransactionID:   tx.ID,
+		FundingSourceID: sourceID,
+		UserID:          userID,
+		ExternalID:      externalID,
+		Amount:          tx.Amount,
+	}
+	if err := s.repo.CreateCredit(ctx, credit); err != nil {
+		return nil, fmt.Errorf("failed to record funded credit: %w", err)
+	}
+	return tx, nil
+}
+
+// ReceiveChargeback takes a funded credit, or part of it, back from its user with a debit.
+func (s *FundingServiceImpl) ReceiveChargeback(ctx context.Context, actorID *int, notice *domain.ChargebackNotice) (*domain.Chargeback, error) {
+	notice.ExternalID, notice.ReasonCode = strings.TrimSpace(notice.ExternalID), strings.TrimSpace(notice.ReasonCode)
+	if notice.ExternalID == "" || len(notice.ExternalID) > maxExternalIDLength {
+		return nil, &domain.ValidationError{Msg: "external_id must be 1 to 200 characters"}
+	}
+	if notice.ReasonCode == "" || len(notice.ReasonCode) > 20 {
+		return nil, &domain.ValidationError{Msg: "reason_code must be 1 to 20 characters"}
+	}
+	if notice.Amount < 0 {
+		return nil, &domain.ValidationError{Msg: "amount must not be negative"}
+	}
+	credit, err := s.repo.GetCredit(ctx, notice.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funded credit: %w", err)
+	}
+	if credit == nil {
+		return nil, domain.ErrFundedCreditNotFound
+	}
+	if credit.ReversedAt != nil {
+		return nil, domain.ErrChargebackExists
+	}
+	amount := notice.Amount
+	if amount == 0 {
+		amount = credit.Amount
+	}
+	if amount > credit.Amount {
+		return nil, &domain.ValidationError{Msg: "amount must not exceed the credit's"}
+	}
+
+	chargeback := &domain.Chargeback{
+		TransactionID: credit.TransactionID,
+		UserID:        credit.UserID,
+		ExternalID:    notice.ExternalID,
+		ReasonCode:    notice.ReasonCode,
+		Amount:        amount,
+		Status:        domain.ChargebackReceived,
+	}
+	err = retryOnBalanceConflict(func() error {
+		return s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+			chargeback.DebitTransactionID, chargeback.FrozeAccount = nil, false
+			bal, err := repos.Balances.GetByUserID(ctx, credit.UserID)
+			if err != nil {
+				return err
+			}
+			if bal == nil {
+				bal = &domain.Balance{UserID: credit.UserID}
+			}
+			chargeback.Recovered = s.recoverable(bal, amount)
+			bal.Amount -= chargeback.Recovered
+
+			total, err := repos.Funding.SumChargebacks(ctx, credit.UserID)
+			if err != nil {
+				return err
+			}
+			if s.policy.FreezeThreshold > 0 && total+amount > s.policy.FreezeThreshold && !bal.IsFrozen() {
+				now := s.now()
+				bal.FrozenAt, chargeback.FrozeAccount = &now, true
+			}
+			if err := repos.Balances.Update(ctx, bal); err != nil {
+				return err
+			}
+
+			// Stored before the debit, whose idempotency key is its ID
+			if err := repos.Funding.CreateChargeback(ctx, chargeback); err != nil {
+				return err
+			}
+			if chargeback.Recovered > 0 {
+				debit := &domain.Transaction{
+					FromUserID:     &credit.UserID,
+					Amount:         chargeback.Recovered,
+					Type:           "debit",
+					Status:         "completed",
+					IdempotencyKey: chargebackKeyPrefix + strconv.Itoa(chargeback.ID),
+					Description:    fmt.Sprintf("Chargeback of transaction #%d", credit.TransactionID),
+				}
+				if err := repos.Transactions.Create(ctx, debit); err != nil {
+					return err
+				}
+				chargeback.DebitTransactionID = &debit.ID
+				if err := repos.Funding.UpdateChargeback(ctx, chargeback); err != nil {
+					return err
+				}
+			}
+			if err := repos.Funding.SetCreditReversed(ctx, credit.TransactionID, true); err != nil {
+				return err
+			}
+			return repos.Funding.AddChargebackEvent(ctx, &domain.ChargebackEvent{
+				ChargebackID: chargeback.ID,
+				Status:       chargeback.Status,
+				Note:         "reason code " + notice.ReasonCode,
+				ActorID:      actorID,
+			})
+		})
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrChargebackExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to record chargeback: %w", err)
+	}
+
+	invalidateUsers(ctx, s.cache, &chargeback.UserID)
+	if chargeback.FrozeAccount {
+		metrics.AccountFreezesTotal.Inc()
+		log.Ctx(ctx).Warn().Int("user_id", chargeback.UserID).Int("chargeback_id", chargeback.ID).Msg("Account frozen by chargebacks")
+	}
+	s.changed(ctx, actorID, "receive_chargeback", chargeback)
+	return chargeback, nil
+}
+
+// ReceiveRefund takes a gateway refund back from the user like a chargeback.
+func (s *FundingServiceImpl) ReceiveRefund(ctx context.Context, credit *domain.Transaction, refund *domain.GatewayRefund) (*domain.GatewayRefund, error) {
+	if credit.ToUserID == nil || credit.Type != "credit" {
+		return nil, &domain.ValidationError{Msg: "only credits are refunded"}
+	}
+	if refund.Amount <= 0 {
+		return nil, &domain.ValidationError{Msg: "amount must be positive"}
+	}
+	if stored, err := s.received(ctx, refund); stored != nil || err != nil {
+		return stored, err
+	}
+	refund.UserID, refund.CreditTransactionID = *credit.ToUserID, credit.ID
+
+	err := retryOnBalanceConflict(func() error {
+		return s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+			refund.Recovered, refund.DebitTransactionID = 0, nil
+			refunded, err := repos.Funding.SumGatewayRefunds(ctx, refund.Provider, refund.ChargeID)
+			if err != nil {
+				return err
+			}
+			if math.Round((refunded+refund.Amount)*100) > math.Round(credit.Amount*100) {
+				return &domain.ValidationError{Msg: fmt.Sprintf("refunds must not total more than their payment of %.2f", credit.Amount)}
+			}
+
+			bal, err := repos.Balances.GetByUserID(ctx, refund.UserID)
+			if err != nil {
+				return err
+			}
+			if bal == nil {
+				bal = &domain.Balance{UserID: refund.UserID}
+			}
+			// Updated even when nothing is recovered, so concurrent refunds
+			// of the payment conflict and the loser totals them again
+			refund.Recovered = s.recoverable(bal, refund.Amount)
+			bal.Amount -= refund.Recovered
+			if err := repos.Balances.Update(ctx, bal); err != nil {
+				return err
+			}
+
+			if refund.Recovered > 0 {
+				debit := &domain.Transaction{
+					FromUserID:     &refund.UserID,
+					Amount:         refund.Recovered,
+					Type:           "debit",
+					Status:         "completed",
+					IdempotencyKey: gatewayRefundKeyPrefix + refund.Provider + ":" + refund.RefundID,
+					Description:    fmt.Sprintf("Refund of transaction #%d", credit.ID),
+				}
+				if err := repos.Transactions.Create(ctx, debit); err != nil {
+					return err
+				}
+				refund.DebitTransactionID = &debit.ID
+			}
+			return repos.Funding.CreateGatewayRefund(ctx, refund)
+		})
+	})
+	if errors.Is(err, domain.ErrGatewayRefundExists) {
+		// Received concurrently
+		return s.received(ctx, refund)
+	}
+	if err != nil {
+		var validation *domain.ValidationError
+		if errors.As(err, &validation) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to record refund: %w", err)
+	}
+
+	invalidateUsers(ctx, s.cache, &refund.UserID)
+	if refund.Recovered < refund.Amount {
+		log.Ctx(ctx).Warn().Int("user_id", refund.UserID).Str("refund_id", refund.RefundID).
+			Float64("unrecovered", refund.Amount-refund.Recovered).Msg("Refund not fully recovered from user")
+	}
+	return refund, nil
+}
+
+// received returns the stored refund of the provider's refund, if any
+func (s *FundingServiceImpl) received(ctx context.Context, refund *domain.GatewayRefund) (*domain.GatewayRefund, error) {
+	stored, err := s.repo.GetGatewayRefund(ctx, refund.Provider, refund.RefundID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refund: %w", err)
+	}
+	return stored, nil
+}
+
+// recoverable returns how much of amount the policy lets a chargeback or refund take from bal
+func (s *FundingServiceImpl) recoverable(bal *domain.Balance, amount float64) float64 {
+	if s.policy.AllowNegativeBalance {
+		return amount
+	}
+	return math.Min(amount, math.Max(bal.AvailableAmount(), 0))
+}
+
+// GetChargeback returns a chargeback with its events.
+func (s *FundingServiceImpl) GetChargeback(ctx context.Context, id int) (*domain.Chargeback, error) {
+	chargeback, err := s.repo.GetChargeback(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chargeback: %w", err)
+	}
+	if chargeback == nil {
+		return nil, domain.ErrChargebackNotFound
+	}
+	return chargeback, nil
+}
+
+// ListChargebacksByUser returns a page of a user's chargebacks, newest first.
+func (s *FundingServiceImpl) ListChargebacksByUser(ctx context.Context, userID, limit, offset int) ([]*domain.Chargeback, error) {
+	return s.repo.ListChargebacksByUser(ctx, userID, limit, offset)
+}
+
+// ListChargebacksByStatus returns a page of the chargebacks with a status, oldest first.
+func (s *FundingServiceImpl) ListChargebacksByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Chargeback, error) {
+	switch status {
+	case domain.ChargebackReceived, domain.ChargebackDisputed, domain.ChargebackWon, domain.ChargebackLost:
+	default:
+		return nil, &domain.ValidationError{Msg: "status must be received, disputed, won or lost"}
+	}
+	return s.repo.ListChargebacksByStatus(ctx, status, limit, offset)
+}
+
+// DisputeChargeback records that a received chargeback is being contested with the provider.
+func (s *FundingServiceImpl) DisputeChargeback(ctx context.Context, id int, actorID *int, note string) (*domain.Chargeback, error) {
+	return s.move(ctx, id, actorID, note, domain.ChargebackDisputed, "dispute_chargeback")
+}
+
+// WinChargeback closes an open chargeback the provider decided for us.
+func (s *FundingServiceImpl) WinChargeback(ctx context.Context, id int, actorID *int, note string) (*domain.Chargeback, error) {
+	return s.move(ctx, id, actorID, note, domain.ChargebackWon, "win_chargeback")
+}
+
+// LoseChargeback closes an open chargeback, leaving the funds charged back.
+func (s *FundingServiceImpl) LoseChargeback(ctx context.Context, id int, actorID *int, note string) (*domain.Chargeback, error) {
+	return s.move(ctx, id, actorID, note, domain.ChargebackLost, "lose_chargeback")
+}
+
+// move takes a chargeback to status, recording the event.
+func (s *FundingServiceImpl) move(ctx context.Context, id int, actorID *int, note, status, action string) (*domain.Chargeback, error) {
+	chargeback, err := s.GetChargeback(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !chargeback.IsOpen() || (status == domain.ChargebackDisputed && chargeback.Status != domain.ChargebackReceived) {
+		return nil, domain.ErrChargebackClosed
+	}
+
+	event := &domain.ChargebackEvent{ChargebackID: id, Status: status, Note: strings.TrimSpace(note), ActorID: actorID}
+	err = retryOnBalanceConflict(func() error {
+		return s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+			chargeback.ReinstateTransactionID = nil
+			if status == domain.ChargebackWon {
+				if chargeback.Recovered > 0 {
+					if err := creditBalance(ctx, repos.Balances, chargeback.UserID, chargeback.Recovered); err != nil {
+						return err
+					}
+					reinstate := &domain.Transaction{
+						ToUserID:       &chargeback.UserID,
+						Amount:         chargeback.Recovered,
+						Type:           "credit",
+						Status:         "completed",
+						IdempotencyKey: chargebackWonKeyPrefix + strconv.Itoa(id),
+						Description:    fmt.Sprintf("Reversal of chargeback of transaction #%d", chargeback.TransactionID),
+					}
+					if err := repos.Transactions.Create(ctx, reinstate); err != nil {
+						return err
+					}
+					chargeback.ReinstateTransactionID = &reinstate.ID
+				}
+				if err := repos.Funding.SetCreditReversed(ctx, chargeback.TransactionID, false); err != nil {
+					return err
+				}
+			}
+			chargeback.Status = status
+			if err := repos.Funding.UpdateChargeback(ctx, chargeback); err != nil {
+				return err
+			}
+			return repos.Funding.AddChargebackEvent(ctx, event)
+		})
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrChargebackClosed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update chargeback: %w", err)
+	}
+	chargeback.Events = append(chargeback.Events, event)
+
+	if status == domain.ChargebackWon {
+		invalidateUsers(ctx, s.cache, &chargeback.UserID)
+	}
+	s.changed(ctx, actorID, action, chargeback)
+	return chargeback, nil
+}
+
+// Unfreeze lifts the freeze of a user's account, letting its funds move again.
+func (s *FundingServiceImpl) Unfreeze(ctx context.Context, userID, actorID int) error {
+	err := retryOnBalanceConflict(func() error {
+		bal, err := s.balances.GetByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if bal == nil || !bal.IsFrozen() {
+			return &domain.ValidationError{Msg: "account is not frozen"}
+		}
+		bal.FrozenAt = nil
+		return s.balances.Update(ctx, bal)
+	})
+	var valErr *domain.ValidationError
+	if errors.As(err, &valErr) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unfreeze account: %w", err)
+	}
+
+	invalidateUsers(ctx, s.cache, &userID)
+	if err := s.audit.Record(ctx, &actorID, "user", userID, "unfreeze_account", ""); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("user_id", userID).Int("actor_id", actorID).Msg("Failed to audit account unfreeze")
+	}
+	return nil
+}
+
+// changed records a chargeback reaching its status and tells its user
+func (s *FundingServiceImpl) changed(ctx context.Context, actorID *int, action string, chargeback *domain.Chargeback) {
+	metrics.ChargebacksTotal.WithLabelValues(chargeback.Status).Inc()
+	details := "transaction_id=" + strconv.Itoa(chargeback.TransactionID) + " status=" + chargeback.Status +
+		" recovered=" + formatAmount(chargeback.Recovered)
+	if err := s.audit.Record(ctx, actorID, "chargeback", chargeback.ID, action, details); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("chargeback_id", chargeback.ID).Msg("Failed to audit chargeback change")
+	}
+	if s.notifier == nil {
+		return
+	}
+	// Only the chargeback that froze the account says so
+	frozen := chargeback.Status == domain.ChargebackReceived && chargeback.FrozeAccount
+	s.notifier.Notify(ctx, chargeback.UserID, domain.EventChargebackUpdated, map[string]string{
+		"chargeback_id":  strconv.Itoa(chargeback.ID),
+		"transaction_id": strconv.Itoa(chargeback.TransactionID),
+		"status":         chargeback.Status,
+		"amount":         formatAmount(chargeback.Amount),
+		"recovered":      formatAmount(chargeback.Recovered),
+		"frozen":         strconv.FormatBool(frozen),
+	})
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// newRefundTestService returns a FundingServiceImpl over store taking refunds
+// back as policy allows
+func newRefundTestService(store *memoryStore, policy domain.ChargebackPolicy) *FundingServiceImpl {
+	return NewFundingService(&memoryFunding{store: store}, nil, nil, nil, store, nil, nil, discardAudit{}, policy)
+}
+
+// gatewayCredit is a payment of 100 a gateway webhook credited to user 1
+func gatewayCredit() *domain.Transaction {
+	userID := 1
+	return &domain.Transaction{ID: 50, ToUserID: &userID, Amount: 100, Type: "credit", Status: "completed"}
+}
+
+func refundOf(refundID string, amount float64) *domain.GatewayRefund {
+	return &domain.GatewayRefund{Provider: "stripe", ChargeID: "ch_1", RefundID: refundID, Amount: amount}
+}
+
+func TestFundingServiceImpl_ReceiveRefund(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("partial refunds may not total more than the payment", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(1, 100, 0)
+		funding := newRefundTestService(store, domain.ChargebackPolicy{})
+
+		first, err := funding.ReceiveRefund(ctx, gatewayCredit(), refundOf("re_1", 60))
+		require.NoError(t, err)
+		assert.Equal(t, 60.0, first.Recovered)
+		require.NotNil(t, first.DebitTransactionID)
+		_, err = funding.ReceiveRefund(ctx, gatewayCredit(), refundOf("re_2", 40))
+		require.NoError(t, err)
+
+		_, err = funding.ReceiveRefund(ctx, gatewayCredit(), refundOf("re_3", 1))
+		var validation *domain.ValidationError
+		assert.ErrorAs(t, err, &validation)
+
+		amount, _ := store.balance(1)
+		assert.Zero(t, amount)
+		assert.Len(t, store.committed(), 2)
+	})
+
+	t.Run("a refund received again is taken once", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(1, 100, 0)
+		funding := newRefundTestService(store, domain.ChargebackPolicy{})
+
+		first, err := funding.ReceiveRefund(ctx, gatewayCredit(), refundOf("re_1", 30))
+		require.NoError(t, err)
+		again, err := funding.ReceiveRefund(ctx, gatewayCredit(), refundOf("re_1", 30))
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, again.ID)
+		amount, _ := store.balance(1)
+		assert.Equal(t, 70.0, amount)
+	})
+
+	t.Run("spent money goes negative when the policy allows", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(1, 10, 0)
+		store.spendLimit = 5 // limit rules don't stop refunds
+		funding := newRefundTestService(store, domain.ChargebackPolicy{AllowNegativeBalance: true})
+
+		refund, err := funding.ReceiveRefund(ctx, gatewayCredit(), refundOf("re_1", 60))
+		require.NoError(t, err)
+		assert.Equal(t, 60.0, refund.Recovered)
+		amount, _ := store.balance(1)
+		assert.Equal(t, -50.0, amount)
+	})
+
+	t.Run("otherwise only what is available is recovered", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(1, 10, 0)
+		funding := newRefundTestService(store, domain.ChargebackPolicy{})
+
+		refund, err := funding.ReceiveRefund(ctx, gatewayCredit(), refundOf("re_1", 60))
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, refund.Recovered)
+		amount, _ := store.balance(1)
+		assert.Zero(t, amount)
+
+		// The unrecovered rest still counts against the payment
+		_, err = funding.ReceiveRefund(ctx, gatewayCredit(), refundOf("re_2", 40))
+		require.NoError(t, err)
+		_, err = funding.ReceiveRefund(ctx, gatewayCredit(), refundOf("re_3", 1))
+		assert.Error(t, err)
+	})
+
+	t.Run("concurrent refunds keep to the payment", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(1, 1000, 0)
+		funding := newRefundTestService(store, domain.ChargebackPolicy{})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				funding.ReceiveRefund(ctx, gatewayCredit(), refundOf(fmt.Sprintf("re_%d", i), 40))
+			}()
+		}
+		wg.Wait()
+
+		amount, _ := store.balance(1)
+		assert.GreaterOrEqual(t, amount, 900.0, "at most 100 is refunded")
+	})
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// HoldServiceImpl implements domain.HoldService
+type HoldServiceImpl struct {
+	holdRepo     domain.HoldRepository
+	uow          domain.UnitOfWork
+	transactions domain.TransactionService
+	cache        domain.CacheInvalidator
+}
+
+// NewHoldService creates a new HoldServiceImpl.
+func NewHoldService(holdRepo domain.HoldRepository, uow domain.UnitOfWork, transactions domain.TransactionService, cache domain.CacheInvalidator) *HoldServiceImpl {
+	return &HoldServiceImpl{
+		holdRepo:     holdRepo,
+		uow:          uow,
+		transactions: transactions,
+		cache:        cache,
+	}
+}
+
+// PlaceHold reserves amount from the user's available balance
+func (s *HoldServiceImpl) PlaceHold(ctx context.Context, userID int, amount float64) (*domain.Hold, error) {
+	hold, err := domain.NewHold(userID, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.atomically(ctx, userID, func(repos domain.UnitOfWorkRepositories, bal *domain.Balance) error {
+		if bal.IsFrozen() {
+			return domain.ErrAccountFrozen
+		}
+		if bal.AvailableAmount() < amount {
+			return domain.ErrInsufficientFunds
+		}
+		bal.HeldAmount += amount
+		if err := repos.Balances.Update(ctx, bal); err != nil {
+			return err
+		}
+		return repos.Holds.Create(ctx, hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// GetHold retrieves a hold by ID
+func (s *HoldServiceImpl) GetHold(ctx context.Context, id int) (*domain.Hold, error) {
+	return s.holdRepo.GetByID(ctx, id)
+}
+
+// ListActiveHolds retrieves the unresolved holds on a user's balance
+func (s *HoldServiceImpl) ListActiveHolds(ctx context.Context, userID int) ([]*domain.Hold, error) {
+	return s.holdRepo.ListActiveByUser(ctx, userID)
+}
+
+// CaptureHold debits the held amount and settles the hold.
+func (s *HoldServiceImpl) CaptureHold(ctx context.Context, id int) (*domain.Hold, error) {
+	hold, err := s.resolveHold(ctx, id, (*domain.Hold).CanCapture)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.transactions.CaptureHold(ctx, hold); err != nil {
+		if errors.Is(err, domain.ErrTransactionHeld) {
+			return hold, err
+		}
+		return nil, err
+	}
+	return hold, nil
+}
+
+// ReleaseHold cancels the hold and makes the amount available again
+func (s *HoldServiceImpl) ReleaseHold(ctx context.Context, id int) (*domain.Hold, error) {
+	hold, err := s.resolveHold(ctx, id, (*domain.Hold).Release)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.atomically(ctx, hold.UserID, func(repos domain.UnitOfWorkRepositories, bal *domain.Balance) error {
+		if err := coversHold(bal, hold); err != nil {
+			return err
+		}
+		bal.HeldAmount -= hold.Amount
+		if err := repos.Balances.Update(ctx, bal); err != nil {
+			return err
+		}
+		return repos.Holds.Resolve(ctx, hold)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// resolveHold loads a hold and checks or applies a transition to it.
+func (s *HoldServiceImpl) resolveHold(ctx context.Context, id int, transition func(*domain.Hold) error) (*domain.Hold, error) {
+	hold, err := s.holdRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+	if hold == nil {
+		return nil, domain.ErrHoldNotFound
+	}
+	if err := transition(hold); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// atomically runs fn with a fresh copy of the user's balance in a unit of work.
+func (s *HoldServiceImpl) atomically(ctx context.Context, userID int, fn func(repos domain.UnitOfWorkRepositories, bal *domain.Balance) error) error {
+	defer invalidateUsers(ctx, s.cache, &userID)
+	return retryOnBalanceConflict(func() error {
+		return s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+			bal, err := repos.Balances.GetByUserID(ctx, userID)
+			if err != nil {
+				return fmt.Errorf("failed to get balance: %w", err)
+			}
+			if bal == nil {
+				return domain.ErrInsufficientFunds
+			}
+			return fn(repos, bal)
+		})
+	})
+}
+
+// coversHold reports an error if the balance no longer has hold's amount reserved
+func coversHold(bal *domain.Balance, hold *domain.Hold) error {
+	if bal.HeldAmount < hold.Amount {
+		return fmt.Errorf("balance of user %d does not cover hold %d", hold.UserID, hold.ID)
+	}
+	return nil
+}
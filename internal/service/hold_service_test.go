@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// newMemoryHoldService returns a HoldServiceImpl over store, with no cache
+// and no review of captures
+func newMemoryHoldService(store *memoryStore) *HoldServiceImpl {
+	return newReviewedHoldService(store, TransactionReview{})
+}
+
+// newReviewedHoldService returns a HoldServiceImpl over store whose captures
+// are reviewed as review configures
+func newReviewedHoldService(store *memoryStore, review TransactionReview) *HoldServiceImpl {
+	txService := NewTransactionService(&memoryTransactions{store: store}, store, nil, nil, nil, nil, nil, review)
+	return NewHoldService(&memoryHolds{store: store}, store, txService, nil)
+}
+
+func TestHoldServiceImpl_PlaceCaptureRelease(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	holds := newMemoryHoldService(store)
+
+	captured, err := holds.PlaceHold(ctx, 1, 30)
+	require.NoError(t, err)
+	released, err := holds.PlaceHold(ctx, 1, 20)
+	require.NoError(t, err)
+	amount, held := store.balance(1)
+	assert.Equal(t, 100.0, amount)
+	assert.Equal(t, 50.0, held)
+
+	_, err = holds.PlaceHold(ctx, 1, 60)
+	assert.ErrorIs(t, err, domain.ErrInsufficientFunds, "only 50 is still available")
+
+	hold, err := holds.CaptureHold(ctx, captured.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "captured", hold.Status)
+	require.NotNil(t, hold.TransactionID)
+	debitID := *hold.TransactionID
+	amount, held = store.balance(1)
+	assert.Equal(t, 70.0, amount)
+	assert.Equal(t, 20.0, held)
+
+	hold, err = holds.ReleaseHold(ctx, released.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "released", hold.Status)
+	amount, held = store.balance(1)
+	assert.Equal(t, 70.0, amount)
+	assert.Zero(t, held)
+
+	active, err := holds.ListActiveHolds(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	txs := store.committed()
+	require.Len(t, txs, 1, "only the capture records a debit")
+	assert.Equal(t, debitID, txs[0].ID)
+	assert.Equal(t, "debit", txs[0].Type)
+	assert.Equal(t, 30.0, txs[0].Amount)
+}
+
+func TestHoldServiceImpl_ResolvesOnce(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	holds := newMemoryHoldService(store)
+
+	hold, err := holds.PlaceHold(ctx, 1, 30)
+	require.NoError(t, err)
+	_, err = holds.CaptureHold(ctx, hold.ID)
+	require.NoError(t, err)
+
+	_, err = holds.CaptureHold(ctx, hold.ID)
+	assert.Error(t, err, "a captured hold is not captured again")
+	_, err = holds.ReleaseHold(ctx, hold.ID)
+	assert.Error(t, err, "nor released")
+
+	amount, held := store.balance(1)
+	assert.Equal(t, 70.0, amount)
+	assert.Zero(t, held)
+	assert.Len(t, store.committed(), 1)
+}
+
+func TestHoldServiceImpl_ConcurrentResolutionsMoveMoneyOnce(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	holds := newMemoryHoldService(store)
+
+	hold, err := holds.PlaceHold(ctx, 1, 30)
+	require.NoError(t, err)
+
+	var resolved atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if i%2 == 0 {
+				_, err = holds.CaptureHold(ctx, hold.ID)
+			} else {
+				_, err = holds.ReleaseHold(ctx, hold.ID)
+			}
+			if err == nil {
+				resolved.Add(1)
+				return
+			}
+			var validation *domain.ValidationError
+			assert.True(t, errors.Is(err, domain.ErrHoldNotActive) || errors.As(err, &validation), "unexpected error: %v", err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), resolved.Load())
+	amount, held := store.balance(1)
+	assert.Zero(t, held)
+	stored, err := holds.GetHold(ctx, hold.ID)
+	require.NoError(t, err)
+	if stored.Status == "captured" {
+		assert.Equal(t, 70.0, amount)
+		assert.Len(t, store.committed(), 1)
+	} else {
+		assert.Equal(t, 100.0, amount)
+		assert.Empty(t, store.committed())
+	}
+}
+
+func TestHoldServiceImpl_CaptureChecksLimits(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	store.spendLimit = 25
+	holds := newMemoryHoldService(store)
+
+	hold, err := holds.PlaceHold(ctx, 1, 30)
+	require.NoError(t, err)
+
+	_, err = holds.CaptureHold(ctx, hold.ID)
+	var limitErr *domain.LimitExceededError
+	assert.ErrorAs(t, err, &limitErr)
+
+	stored, err := holds.GetHold(ctx, hold.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "active", stored.Status, "the hold stays active")
+	amount, held := store.balance(1)
+	assert.Equal(t, 100.0, amount)
+	assert.Equal(t, 30.0, held)
+	assert.Empty(t, store.committed())
+
+	_, err = holds.ReleaseHold(ctx, hold.ID)
+	assert.NoError(t, err, "and can still be released")
+}
+
+func TestHoldServiceImpl_CaptureIsReviewedLikeADebit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("above the approval threshold it waits for approval", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(1, 1000, 0)
+		holds := newReviewedHoldService(store, TransactionReview{ApprovalThreshold: 100, Audit: discardAudit{}})
+		txRepo := &memoryTransactions{store: store}
+		approvals := NewApprovalService(&memoryApprovals{store: store}, txRepo, holds.transactions, discardAudit{})
+
+		hold, err := holds.PlaceHold(ctx, 1, 150)
+		require.NoError(t, err)
+		pending, err := holds.CaptureHold(ctx, hold.ID)
+		assert.ErrorIs(t, err, domain.ErrTransactionHeld)
+		require.NotNil(t, pending)
+		assert.Equal(t, "active", pending.Status)
+		require.NotNil(t, pending.TransactionID)
+		amount, held := store.balance(1)
+		assert.Equal(t, 1000.0, amount, "no money moves while held")
+		assert.Equal(t, 150.0, held)
+
+		_, err = holds.ReleaseHold(ctx, hold.ID)
+		assert.ErrorIs(t, err, domain.ErrHoldAwaitingApproval)
+		_, err = holds.CaptureHold(ctx, hold.ID)
+		assert.ErrorIs(t, err, domain.ErrHoldAwaitingApproval)
+
+		tx, err := approvals.Approve(ctx, *pending.TransactionID, 7)
+		require.NoError(t, err)
+		assert.Equal(t, "completed", tx.Status)
+		amount, held = store.balance(1)
+		assert.Equal(t, 850.0, amount)
+		assert.Zero(t, held)
+		stored, err := holds.GetHold(ctx, hold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "captured", stored.Status)
+		assert.Equal(t, tx.ID, *stored.TransactionID)
+	})
+
+	t.Run("a rejected capture frees the hold", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(1, 1000, 0)
+		holds := newReviewedHoldService(store, TransactionReview{ApprovalThreshold: 100, Audit: discardAudit{}})
+		approvals := NewApprovalService(&memoryApprovals{store: store}, &memoryTransactions{store: store}, holds.transactions, discardAudit{})
+
+		hold, err := holds.PlaceHold(ctx, 1, 150)
+		require.NoError(t, err)
+		pending, err := holds.CaptureHold(ctx, hold.ID)
+		require.ErrorIs(t, err, domain.ErrTransactionHeld)
+
+		_, err = approvals.Reject(ctx, *pending.TransactionID, 7, "not ordered")
+		require.NoError(t, err)
+		stored, err := holds.GetHold(ctx, hold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "active", stored.Status)
+		assert.Nil(t, stored.TransactionID)
+
+		_, err = holds.ReleaseHold(ctx, hold.ID)
+		require.NoError(t, err)
+		amount, held := store.balance(1)
+		assert.Equal(t, 1000.0, amount)
+		assert.Zero(t, held)
+	})
+
+	t.Run("a capture fraud screening rejects leaves the hold active", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(1, 100, 0)
+		fraud := &fixedFraud{decision: domain.FraudDecisionReject}
+		holds := newReviewedHoldService(store, TransactionReview{Fraud: fraud})
+
+		hold, err := holds.PlaceHold(ctx, 1, 30)
+		require.NoError(t, err)
+		_, err = holds.CaptureHold(ctx, hold.ID)
+		assert.ErrorIs(t, err, domain.ErrFraudRejected)
+		assert.Equal(t, []string{"debit"}, fraud.assessed)
+
+		stored, err := holds.GetHold(ctx, hold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "active", stored.Status)
+		amount, held := store.balance(1)
+		assert.Equal(t, 100.0, amount)
+		assert.Equal(t, 30.0, held)
+		assert.Empty(t, store.committed())
+	})
+
+	t.Run("a capture fraud screening holds waits for approval", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(1, 100, 0)
+		fraud := &fixedFraud{decision: domain.FraudDecisionHold}
+		holds := newReviewedHoldService(store, TransactionReview{Fraud: fraud, Audit: discardAudit{}})
+
+		hold, err := holds.PlaceHold(ctx, 1, 30)
+		require.NoError(t, err)
+		pending, err := holds.CaptureHold(ctx, hold.ID)
+		assert.ErrorIs(t, err, domain.ErrTransactionHeld)
+		require.NotNil(t, pending.TransactionID)
+		assert.Equal(t, map[int]int{1: *pending.TransactionID}, fraud.linked)
+		amount, held := store.balance(1)
+		assert.Equal(t, 100.0, amount)
+		assert.Equal(t, 30.0, held)
+	})
+
+	t.Run("the capture is charged its fee", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(1, 100, 0)
+		txService := NewTransactionService(&memoryTransactions{store: store}, store, nil, nil, nil, flatFees(2), nil, TransactionReview{})
+		holds := NewHoldService(&memoryHolds{store: store}, store, txService, nil)
+
+		hold, err := holds.PlaceHold(ctx, 1, 30)
+		require.NoError(t, err)
+		_, err = holds.CaptureHold(ctx, hold.ID)
+		require.NoError(t, err)
+		amount, held := store.balance(1)
+		assert.Equal(t, 68.0, amount)
+		assert.Zero(t, held)
+		txs := store.committed()
+		require.Len(t, txs, 1)
+		assert.Equal(t, 2.0, txs[0].Fee)
+	})
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+	"github.com/melihgurlek/backend-path/pkg/pdf"
+)
+
+// InvoiceServiceImpl implements domain.InvoiceService.
+type InvoiceServiceImpl struct {
+	repo     domain.InvoiceRepository
+	users    domain.UserRepository
+	orgs     domain.OrganizationRepository
+	cache    domain.CacheInvalidator
+	issuer   string
+	currency string
+	interval time.Duration
+	now      func() time.Time
+	runMu    sync.Mutex
+	stopChan chan struct{}
+}
+
+// NewInvoiceService creates a new InvoiceServiceImpl invoicing every interval.
+func NewInvoiceService(repo domain.InvoiceRepository, users domain.UserRepository, orgs domain.OrganizationRepository, cache domain.CacheInvalidator, issuer, currency string, interval time.Duration) *InvoiceServiceImpl {
+	return &InvoiceServiceImpl{
+		repo:     repo,
+		users:    users,
+		orgs:     orgs,
+		cache:    cache,
+		issuer:   issuer,
+		currency: currency,
+		interval: interval,
+		now:      time.Now,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// List returns a page of a user's invoices, newest first.
+func (s *InvoiceServiceImpl) List(ctx context.Context, userID, limit, offset int) ([]*domain.Invoice, error) {
+	invoices, err := s.repo.ListByUser(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// Get returns one of a user's invoices with its lines.
+func (s *InvoiceServiceImpl) Get(ctx context.Context, userID, invoiceID int) (*domain.Invoice, error) {
+	invoice, err := s.repo.Get(ctx, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	if invoice == nil || invoice.UserID != userID {
+		return nil, domain.ErrInvoiceNotFound
+	}
+	return invoice, nil
+}
+
+// PDF renders one of a user's invoices, billed to their name and email.
+func (s *InvoiceServiceImpl) PDF(ctx context.Context, userID, invoiceID int) (*domain.InvoiceDocument, error) {
+	invoice, err := s.Get(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	billTo := []string{"User #" + strconv.Itoa(userID)}
+	if user != nil {
+		name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+		if name == "" {
+			name = user.Username
+		}
+		billTo = []string{name, user.Email}
+	}
+	return s.render(invoice, billTo), nil
+}
+
+// ListForOrganization returns a page of an organization's invoices.
+func (s *InvoiceServiceImpl) ListForOrganization(ctx context.Context, actorID int, isAdmin bool, organizationID, limit, offset int) ([]*domain.Invoice, error) {
+	org, err := s.organization(ctx, actorID, isAdmin, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	return s.List(ctx, org.AccountUserID, limit, offset)
+}
+
+// GetForOrganization returns one of an organization's invoices with its lines.
+func (s *InvoiceServiceImpl) GetForOrganization(ctx context.Context, actorID int, isAdmin bool, organizationID, invoiceID int) (*domain.Invoice, error) {
+	org, err := s.organization(ctx, actorID, isAdmin, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, org.AccountUserID, invoiceID)
+}
+
+// OrganizationPDF renders one of an organization's invoices.
+func (s *InvoiceServiceImpl) OrganizationPDF(ctx context.Context, actorID int, isAdmin bool, organizationID, invoiceID int) (*domain.InvoiceDocument, error) {
+	org, err := s.organization(ctx, actorID, isAdmin, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := s.Get(ctx, org.AccountUserID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.render(invoice, []string{org.Name, "Organization #" + strconv.Itoa(org.ID)}), nil
+}
+
+// organization loads an organization the actor is a member of.
+func (s *InvoiceServiceImpl) organization(ctx context.Context, actorID int, isAdmin bool, organizationID int) (*domain.Organization, error) {
+	org, err := s.orgs.GetByID(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	if org == nil {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	if isAdmin {
+		return org, nil
+	}
+	member, err := s.orgs.GetMember(ctx, organizationID, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization member: %w", err)
+	}
+	if member == nil {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	return org, nil
+}
+
+// RunInvoicing settles the open invoices, then invoices the month just over.
+func (s *InvoiceServiceImpl) RunInvoicing(ctx context.Context) (*domain.InvoiceRun, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	now := s.now().UTC()
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	run := &domain.InvoiceRun{PeriodStart: end.AddDate(0, -1, 0), PeriodEnd: end}
+	open, err := s.repo.ListOpen(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open invoices: %w", err)
+	}
+	for _, invoice := range open {
+		s.settleInto(ctx, run, invoice)
+	}
+
+	payers, err := s.repo.ListFeePayers(ctx, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fee payers: %w", err)
+	}
+	for _, userID := range payers {
+		invoice, err := s.issue(ctx, userID, run.PeriodStart, end)
+		if err != nil {
+			s.fail(ctx, run, userID, 0, err)
+			continue
+		}
+		if invoice != nil {
+			run.Issued++
+			s.settleInto(ctx, run, invoice)
+		}
+	}
+	if run.Issued > 0 || run.Settled > 0 {
+		log.Ctx(ctx).Info().Time("period_start", run.PeriodStart).Int("issued", run.Issued).Int("settled", run.Settled).Msg("Issued fee invoices")
+	}
+	return run, nil
+}
+
+// settleInto settles an open invoice, counting it or its failure on run
+func (s *InvoiceServiceImpl) settleInto(ctx context.Context, run *domain.InvoiceRun, invoice *domain.Invoice) {
+	settled, err := s.settle(ctx, invoice)
+	if err != nil {
+		s.fail(ctx, run, invoice.UserID, invoice.ID, err)
+		return
+	}
+	if settled {
+		run.Settled++
+	}
+}
+
+// fail records on run that a user's invoice could not be issued or settled
+func (s *InvoiceServiceImpl) fail(ctx context.Context, run *domain.InvoiceRun, userID, invoiceID int, err error) {
+	log.Ctx(ctx).Error().Err(err).Int("user_id", userID).Int("invoice_id", invoiceID).Msg("Failed to invoice user")
+	run.Failed = append(run.Failed, &domain.InvoiceFailure{UserID: userID, InvoiceID: invoiceID, Error: err.Error()})
+}
+
+// issue stores a user's open invoice of [start, end), or returns nil if there is none.
+func (s *InvoiceServiceImpl) issue(ctx context.Context, userID int, start, end time.Time) (*domain.Invoice, error) {
+	lines, err := s.repo.ListFeeLines(ctx, userID, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoice fees: %w", err)
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	invoice := &domain.Invoice{
+		Number:      domain.InvoiceNumber(userID, start),
+		UserID:      userID,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Currency:    s.currency,
+		Total:       invoiceTotal(lines),
+		Status:      domain.InvoiceOpen,
+		Lines:       lines,
+	}
+	if err := s.repo.Create(ctx, invoice); err != nil {
+		if errors.Is(err, domain.ErrInvoiceExists) {
+			// Invoiced already, or by another instance in the meantime
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+	metrics.InvoicesIssued.Inc()
+	invalidateUsers(ctx, s.cache, &invoice.UserID)
+	return invoice, nil
+}
+
+// settle marks an open invoice paid once every line's fee was debited.
+func (s *InvoiceServiceImpl) settle(ctx context.Context, invoice *domain.Invoice) (bool, error) {
+	var lines []*domain.InvoiceLine
+	for _, line := range invoice.Lines {
+		switch line.TransactionStatus {
+		case "pending_approval":
+			return false, nil
+		case "completed":
+			if line.Amount > 0 {
+				lines = append(lines, line)
+			}
+		}
+	}
+	invoice.Lines, invoice.Total = lines, invoiceTotal(lines)
+	invoice.Status, invoice.PaidAt = domain.InvoiceVoid, nil
+	if len(lines) > 0 {
+		paidAt := s.now().UTC()
+		invoice.Status, invoice.PaidAt = domain.InvoicePaid, &paidAt
+	}
+	if err := s.repo.Settle(ctx, invoice); err != nil {
+		if errors.Is(err, domain.ErrInvoiceNotOpen) {
+			// Settled by another instance in the meantime
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to settle invoice: %w", err)
+	}
+	invalidateUsers(ctx, s.cache, &invoice.UserID)
+	return true, nil
+}
+
+// invoiceTotal adds up the fees of lines in cents
+func invoiceTotal(lines []*domain.InvoiceLine) float64 {
+	var cents int64
+	for _, line := range lines {
+		cents += int64(math.Round(line.Amount * 100))
+	}
+	return float64(cents) / 100
+}
+
+// Invoice PDF layout, in points on an A4 page
+const (
+	invoiceMarginLeft   = 50.0
+	invoiceMarginRight  = pdf.A4Width - 50
+	invoiceMarginBottom = 70.0
+	invoiceLineHeight   = 15.0
+)
+
+// render lays an invoice out as a PDF billed to the lines of billTo.
+func (s *InvoiceServiceImpl) render(invoice *domain.Invoice, billTo []string) *domain.InvoiceDocument {
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	page := doc.AddPage()
+	y := pdf.A4Height - 60
+
+	page.Text(invoiceMarginLeft, y, pdf.HelveticaBold, 18, s.issuer)
+	page.TextRight(invoiceMarginRight, y, pdf.HelveticaBold, 18, "Invoice")
+	y -= 30
+	details := [][2]string{
+		{"Invoice number", invoice.Number},
+		{"Issued", invoice.IssuedAt.UTC().Format("2006-01-02")},
+		{"Period", invoice.PeriodStart.UTC().Format("2006-01-02") + " to " + invoice.PeriodEnd.UTC().AddDate(0, 0, -1).Format("2006-01-02")},
+		{"Status", invoiceStatus(invoice)},
+	}
+	for _, detail := range details {
+		page.Text(invoiceMarginLeft, y, pdf.HelveticaBold, 10, detail[0])
+		page.Text(invoiceMarginLeft+90, y, pdf.Helvetica, 10, detail[1])
+		y -= invoiceLineHeight
+	}
+	y -= 10
+	page.Text(invoiceMarginLeft, y, pdf.HelveticaBold, 10, "Bill to")
+	for _, line := range billTo {
+		page.Text(invoiceMarginLeft+90, y, pdf.Helvetica, 10, line)
+		y -= invoiceLineHeight
+	}
+	y -= 15
+
+	header := func() {
+		page.Text(invoiceMarginLeft, y, pdf.HelveticaBold, 10, "Date")
+		page.Text(invoiceMarginLeft+80, y, pdf.HelveticaBold, 10, "Transaction")
+		page.Text(invoiceMarginLeft+180, y, pdf.HelveticaBold, 10, "Type")
+		page.TextRight(invoiceMarginRight-100, y, pdf.HelveticaBold, 10, "Amount")
+		page.TextRight(invoiceMarginRight, y, pdf.HelveticaBold, 10, "Fee")
+		page.Line(invoiceMarginLeft, y-5, invoiceMarginRight, y-5, 0.5)
+		y -= invoiceLineHeight + 5
+	}
+	header()
+	for _, line := range invoice.Lines {
+		if y < invoiceMarginBottom {
+			page = doc.AddPage()
+			y = pdf.A4Height - 60
+			header()
+		}
+		page.Text(invoiceMarginLeft, y, pdf.Helvetica, 10, line.OccurredAt.UTC().Format("2006-01-02"))
+		page.Text(invoiceMarginLeft+80, y, pdf.Helvetica, 10, "#"+strconv.Itoa(line.TransactionID))
+		page.Text(invoiceMarginLeft+180, y, pdf.Helvetica, 10, line.TransactionType)
+		page.TextRight(invoiceMarginRight-100, y, pdf.Helvetica, 10, formatAmount(line.TransactionAmount))
+		page.TextRight(invoiceMarginRight, y, pdf.Helvetica, 10, formatAmount(line.Amount))
+		y -= invoiceLineHeight
+	}
+	if y < invoiceMarginBottom {
+		page = doc.AddPage()
+		y = pdf.A4Height - 60
+	}
+	page.Line(invoiceMarginLeft, y+invoiceLineHeight-5, invoiceMarginRight, y+invoiceLineHeight-5, 0.5)
+	y -= 5
+	page.TextRight(invoiceMarginRight-100, y, pdf.HelveticaBold, 10, "Total "+invoice.Currency)
+	page.TextRight(invoiceMarginRight, y, pdf.HelveticaBold, 10, formatAmount(invoice.Total))
+	page.Text(invoiceMarginLeft, invoiceMarginBottom-30, pdf.Helvetica, 8,
+		"Fees are collected with the transactions they are charged on. The invoice is paid once every one of them is.")
+
+	return &domain.InvoiceDocument{FileName: invoice.FileName(), Content: doc.Bytes()}
+}
+
+// invoiceStatus describes an invoice's payment status on its PDF
+func invoiceStatus(invoice *domain.Invoice) string {
+	switch {
+	case invoice.Status == domain.InvoicePaid && invoice.PaidAt != nil:
+		return "Paid " + invoice.PaidAt.UTC().Format("2006-01-02")
+	case invoice.Status == domain.InvoiceOpen:
+		return "Open"
+	case invoice.Status == domain.InvoiceVoid:
+		return "Void"
+	}
+	return invoice.Status
+}
+
+// Start runs invoicing now and then every interval
+func (s *InvoiceServiceImpl) Start(ctx context.Context) {
+	log.Ctx(ctx).Info().Dur("interval", s.interval).Msg("Starting fee invoicing")
+
+	go s.invoiceLoop(ctx)
+}
+
+// Stop stops the periodic invoicing
+func (s *InvoiceServiceImpl) Stop() {
+	log.Info().Msg("Stopping fee invoicing")
+	close(s.stopChan)
+}
+
+func (s *InvoiceServiceImpl) invoiceLoop(ctx context.Context) {
+	defer metrics.TrackGoroutine("invoicing")()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunInvoicing(ctx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Fee invoicing run failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// newInvoiceTestService returns an InvoiceServiceImpl over store running as
+// if it were next month, so this month's transactions are invoiced, and a
+// transaction service charging a fee of 2 on everything
+func newInvoiceTestService(store *memoryStore) (*InvoiceServiceImpl, *TransactionServiceImpl) {
+	invoices := NewInvoiceService(&memoryInvoices{store: store}, knownUsers{}, &memoryOrganizations{store: store}, nil, "Backend Path", "USD", time.Hour)
+	invoices.now = func() time.Time { return nextMonth(time.Now()) }
+	return invoices, NewTransactionService(&memoryTransactions{store: store}, store, nil, nil, nil, flatFees(2), nil, TransactionReview{})
+}
+
+// nextMonth returns the first day of the month after t's, at noon UTC
+func nextMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month()+1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func TestInvoiceServiceImpl_RunInvoicing(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	invoices, txService := newInvoiceTestService(store)
+
+	transfer, err := txService.Transfer(ctx, 1, 2, 50, "")
+	require.NoError(t, err)
+	debit, err := txService.Debit(ctx, 1, 10, "")
+	require.NoError(t, err)
+	_, err = txService.Credit(ctx, 2, 20, "")
+	require.NoError(t, err)
+
+	run, err := invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, run.Issued, "the payers of the transfer, the debit and the credit")
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, start, run.PeriodStart)
+
+	list, err := invoices.List(ctx, 1, 50, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	invoice, err := invoices.Get(ctx, 1, list[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.InvoiceNumber(1, start), invoice.Number)
+	assert.Equal(t, start.AddDate(0, 1, 0), invoice.PeriodEnd)
+	assert.Equal(t, "USD", invoice.Currency)
+	assert.Equal(t, 4.0, invoice.Total)
+	assert.Equal(t, domain.InvoicePaid, invoice.Status)
+	require.NotNil(t, invoice.PaidAt)
+	require.Len(t, invoice.Lines, 2)
+	assert.Equal(t, transfer.ID, invoice.Lines[0].TransactionID)
+	assert.Equal(t, 50.0, invoice.Lines[0].TransactionAmount)
+	assert.Equal(t, 2.0, invoice.Lines[0].Amount)
+	require.NotNil(t, invoice.Lines[0].FeeScheduleID)
+	assert.Equal(t, 7, *invoice.Lines[0].FeeScheduleID)
+	assert.Equal(t, debit.ID, invoice.Lines[1].TransactionID)
+
+	run, err = invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, run.Issued, "a month is invoiced once")
+}
+
+func TestInvoiceServiceImpl_PaidOnceHeldFeesAreDebited(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 1000, 0)
+	invoices, _ := newInvoiceTestService(store)
+	txRepo := &memoryTransactions{store: store}
+	txService := NewTransactionService(txRepo, store, nil, nil, nil, flatFees(2), nil, TransactionReview{ApprovalThreshold: 100, Audit: discardAudit{}})
+
+	_, err := txService.Debit(ctx, 1, 10, "")
+	require.NoError(t, err)
+	held, err := txService.Transfer(ctx, 1, 2, 150, "")
+	require.ErrorIs(t, err, domain.ErrTransactionHeld)
+
+	run, err := invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Issued)
+	assert.Zero(t, run.Settled, "the held fee isn't debited yet")
+	list, err := invoices.List(ctx, 1, 50, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, domain.InvoiceOpen, list[0].Status)
+	assert.Nil(t, list[0].PaidAt)
+	assert.Equal(t, 4.0, list[0].Total)
+
+	require.NoError(t, txService.ExecutePending(ctx, held, nil))
+	run, err = invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, run.Issued)
+	assert.Equal(t, 1, run.Settled)
+	invoice, err := invoices.Get(ctx, 1, list[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.InvoicePaid, invoice.Status)
+	require.NotNil(t, invoice.PaidAt)
+	assert.Equal(t, 4.0, invoice.Total)
+	assert.Len(t, invoice.Lines, 2)
+}
+
+func TestInvoiceServiceImpl_DropsFeesOfRejectedTransactions(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 1000, 0)
+	invoices, _ := newInvoiceTestService(store)
+	txRepo := &memoryTransactions{store: store}
+	txService := NewTransactionService(txRepo, store, nil, nil, nil, flatFees(2), nil, TransactionReview{ApprovalThreshold: 100, Audit: discardAudit{}})
+
+	held, err := txService.Transfer(ctx, 1, 2, 150, "")
+	require.ErrorIs(t, err, domain.ErrTransactionHeld)
+	_, err = invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, txRepo.ResolvePending(ctx, held.ID, "rejected"))
+	run, err := invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Settled)
+	list, err := invoices.List(ctx, 1, 50, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	invoice, err := invoices.Get(ctx, 1, list[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.InvoiceVoid, invoice.Status, "nothing was debited")
+	assert.Nil(t, invoice.PaidAt)
+	assert.Zero(t, invoice.Total)
+	assert.Empty(t, invoice.Lines)
+}
+
+// failingInvoices fails to create the invoice of one user
+type failingInvoices struct {
+	*memoryInvoices
+	userID int
+}
+
+func (r *failingInvoices) Create(ctx context.Context, invoice *domain.Invoice) error {
+	if invoice.UserID == r.userID {
+		return errors.New("connection reset")
+	}
+	return r.memoryInvoices.Create(ctx, invoice)
+}
+
+func TestInvoiceServiceImpl_RunGoesOnAfterAFailure(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	store.setBalance(2, 100, 0)
+	_, txService := newInvoiceTestService(store)
+	invoices := NewInvoiceService(&failingInvoices{memoryInvoices: &memoryInvoices{store: store}, userID: 1}, knownUsers{}, &memoryOrganizations{store: store}, nil, "Backend Path", "USD", time.Hour)
+	invoices.now = func() time.Time { return nextMonth(time.Now()) }
+
+	_, err := txService.Debit(ctx, 1, 10, "")
+	require.NoError(t, err)
+	_, err = txService.Debit(ctx, 2, 10, "")
+	require.NoError(t, err)
+
+	run, err := invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Issued)
+	require.Len(t, run.Failed, 1)
+	assert.Equal(t, 1, run.Failed[0].UserID)
+	assert.Zero(t, run.Failed[0].InvoiceID)
+	assert.Contains(t, run.Failed[0].Error, "connection reset")
+	list, err := invoices.List(ctx, 2, 50, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1, "the next user is still invoiced")
+	assert.Equal(t, domain.InvoicePaid, list[0].Status)
+}
+
+func TestInvoiceServiceImpl_LateFeesGoOnTheNextInvoice(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	invoices, txService := newInvoiceTestService(store)
+
+	_, err := txService.Debit(ctx, 1, 10, "")
+	require.NoError(t, err)
+	_, err = invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+
+	// Made in the invoiced month, but only after it was invoiced, as an
+	// approval completed late would be
+	late, err := txService.Debit(ctx, 1, 10, "")
+	require.NoError(t, err)
+	run, err := invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, run.Issued)
+
+	invoices.now = func() time.Time { return nextMonth(nextMonth(time.Now())) }
+	run, err = invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Issued)
+	list, err := invoices.List(ctx, 1, 50, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	invoice, err := invoices.Get(ctx, 1, list[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, run.PeriodStart, invoice.PeriodStart, "newest first")
+	require.Len(t, invoice.Lines, 1)
+	assert.Equal(t, late.ID, invoice.Lines[0].TransactionID)
+}
+
+func TestInvoiceServiceImpl_NothingToInvoice(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	invoices, txService := newInvoiceTestService(store)
+
+	_, err := newMemoryTransactionService(store).Debit(ctx, 1, 10, "")
+	require.NoError(t, err)
+	run, err := invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, run.Issued, "transactions without fees are not invoiced")
+
+	invoices.now = time.Now
+	_, err = txService.Debit(ctx, 1, 10, "")
+	require.NoError(t, err)
+	run, err = invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, run.Issued, "this month isn't over")
+}
+
+func TestInvoiceServiceImpl_ConcurrentRunsIssueOnce(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	first, txService := newInvoiceTestService(store)
+	second, _ := newInvoiceTestService(store)
+
+	_, err := txService.Debit(ctx, 1, 10, "")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	issued := make([]int, 2)
+	for i, invoices := range []*InvoiceServiceImpl{first, second} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run, err := invoices.RunInvoicing(ctx)
+			assert.NoError(t, err)
+			issued[i] = run.Issued
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, issued[0]+issued[1])
+	assert.Len(t, store.invoices, 1)
+}
+
+func TestInvoiceServiceImpl_Access(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	store.addOrganization(testOrgID, testOrgAccount, map[int]string{2: domain.OrgRoleViewer})
+	store.setBalance(testOrgAccount, 100, 0)
+	invoices, txService := newInvoiceTestService(store)
+
+	_, err := txService.Debit(ctx, 1, 10, "")
+	require.NoError(t, err)
+	_, err = txService.Debit(ctx, testOrgAccount, 10, "")
+	require.NoError(t, err)
+	_, err = invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+	own, err := invoices.List(ctx, 1, 50, 0)
+	require.NoError(t, err)
+	require.Len(t, own, 1)
+
+	_, err = invoices.Get(ctx, 2, own[0].ID)
+	assert.ErrorIs(t, err, domain.ErrInvoiceNotFound, "another user's invoice")
+	_, err = invoices.PDF(ctx, 2, own[0].ID)
+	assert.ErrorIs(t, err, domain.ErrInvoiceNotFound)
+
+	orgInvoices, err := invoices.ListForOrganization(ctx, 2, false, testOrgID, 50, 0)
+	require.NoError(t, err)
+	require.Len(t, orgInvoices, 1, "members see their organization's invoices")
+	_, err = invoices.GetForOrganization(ctx, 2, false, testOrgID, own[0].ID)
+	assert.ErrorIs(t, err, domain.ErrInvoiceNotFound, "only the organization's own")
+	_, err = invoices.ListForOrganization(ctx, 1, false, testOrgID, 50, 0)
+	assert.ErrorIs(t, err, domain.ErrOrganizationNotFound, "outsiders don't")
+	_, err = invoices.OrganizationPDF(ctx, 1, true, testOrgID, orgInvoices[0].ID)
+	assert.NoError(t, err, "admins do")
+}
+
+func TestInvoiceServiceImpl_PDF(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 1000, 0)
+	invoices, txService := newInvoiceTestService(store)
+
+	// Enough lines to run onto a second page
+	for i := 0; i < 60; i++ {
+		_, err := txService.Debit(ctx, 1, 1, "")
+		require.NoError(t, err)
+	}
+	_, err := invoices.RunInvoicing(ctx)
+	require.NoError(t, err)
+	list, err := invoices.List(ctx, 1, 50, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	document, err := invoices.PDF(ctx, 1, list[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, list[0].Number+".pdf", document.FileName)
+	assert.True(t, bytes.HasPrefix(document.Content, []byte("%PDF-")))
+	assert.Contains(t, string(document.Content), "("+list[0].Number+")")
+	assert.Contains(t, string(document.Content), "(120.00)", "the total")
+	assert.Contains(t, string(document.Content), "/Count 2")
+}
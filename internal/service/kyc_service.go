@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// manualKYCProvider names the verifications that go straight to admin review.
+const manualKYCProvider = "manual"
+
+// KYCServiceImpl implements domain.KYCService.
+type KYCServiceImpl struct {
+	repo     domain.KYCRepository
+	provider domain.KYCProvider
+	cache    domain.CacheInvalidator
+	notifier domain.Notifier
+	audit    domain.AuditLogService
+	now      func() time.Time
+}
+
+// NewKYCService creates a new KYCServiceImpl verifying identities with provider.
+func NewKYCService(repo domain.KYCRepository, provider domain.KYCProvider, cache domain.CacheInvalidator, notifier domain.Notifier, audit domain.AuditLogService) *KYCServiceImpl {
+	return &KYCServiceImpl{repo: repo, provider: provider, cache: cache, notifier: notifier, audit: audit, now: time.Now}
+}
+
+// Submit starts verifying a user's identity at a higher level.
+func (s *KYCServiceImpl) Submit(ctx context.Context, userID int, submission *domain.KYCSubmission) (*domain.KYCVerification, error) {
+	submission.Country = strings.ToUpper(strings.TrimSpace(submission.Country))
+	if err := submission.Validate(s.now()); err != nil {
+		return nil, err
+	}
+	level, _, err := s.repo.GetLevel(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KYC level: %w", err)
+	}
+	if submission.Level <= level {
+		return nil, domain.ErrKYCLevelReached
+	}
+	latest, err := s.repo.GetLatest(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KYC verification: %w", err)
+	}
+	if latest != nil && latest.IsOpen() {
+		return nil, domain.ErrKYCInProgress
+	}
+
+	verification := &domain.KYCVerification{
+		UserID:        userID,
+		Level:         submission.Level,
+		Status:        domain.KYCStatusPending,
+		Provider:      manualKYCProvider,
+		Country:       submission.Country,
+		DocumentType:  submission.DocumentType,
+		DocumentLast4: submission.DocumentLast4(),
+	}
+	if s.provider == nil {
+		verification.Status = domain.KYCStatusReview
+	} else {
+		verification.Provider = s.provider.Name()
+	}
+	if err := s.repo.Create(ctx, verification); err != nil {
+		return nil, fmt.Errorf("failed to create KYC verification: %w", err)
+	}
+	metrics.KYCVerificationsTotal.WithLabelValues(verification.Status).Inc()
+	invalidateUsers(ctx, s.cache, &userID)
+	if s.provider == nil {
+		return verification, nil
+	}
+
+	result, err := s.provider.Submit(ctx, userID, submission)
+	if err != nil {
+		// Nobody would ever decide it otherwise, so an admin has to
+		log.Ctx(ctx).Error().Err(err).Int("verification_id", verification.ID).Msg("KYC provider failed; sending verification to review")
+		result = &domain.KYCResult{Status: domain.KYCStatusReview, Reason: "provider unavailable"}
+	} else {
+		if err := s.repo.SetProviderRef(ctx, verification.ID, result.ProviderRef); err != nil {
+			return nil, fmt.Errorf("failed to store KYC provider reference: %w", err)
+		}
+		verification.ProviderRef = result.ProviderRef
+	}
+	if result.Status == domain.KYCStatusPending {
+		return verification, nil
+	}
+	if err := s.decide(ctx, verification, result.Status, result.Reason, nil); err != nil {
+		return nil, err
+	}
+	return verification, nil
+}
+
+// State returns a user's KYC level, status, latest verification and limit rules.
+func (s *KYCServiceImpl) State(ctx context.Context, userID int) (*domain.KYCState, error) {
+	level, status, err := s.repo.GetLevel(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KYC level: %w", err)
+	}
+	latest, err := s.repo.GetLatest(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KYC verification: %w", err)
+	}
+	tiers, err := s.repo.ListTierLimits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list KYC tier limits: %w", err)
+	}
+	limits := tiers[level]
+	if limits == nil {
+		limits = []domain.TransactionLimitRule{}
+	}
+	for i := range limits {
+		limits[i].UserID = userID
+	}
+	return &domain.KYCState{UserID: userID, Level: level, Status: status, Latest: latest, Limits: limits}, nil
+}
+
+// HandleCallback applies the result a provider reports for one of its verifications.
+func (s *KYCServiceImpl) HandleCallback(ctx context.Context, callback *domain.KYCCallback) error {
+	if s.provider == nil {
+		return domain.ErrKYCInvalidCallback
+	}
+	result, err := s.provider.ParseCallback(callback)
+	if err != nil {
+		return err
+	}
+	verification, err := s.repo.GetByProviderRef(ctx, s.provider.Name(), result.ProviderRef)
+	if err != nil {
+		return fmt.Errorf("failed to get KYC verification: %w", err)
+	}
+	if verification == nil {
+		return domain.ErrKYCVerificationNotFound
+	}
+	if !verification.IsOpen() || verification.Status == result.Status {
+		return nil
+	}
+	err = s.decide(ctx, verification, result.Status, result.Reason, nil)
+	if errors.Is(err, domain.ErrKYCNotPending) {
+		return nil
+	}
+	return err
+}
+
+// ListVerifications returns a page of the verifications with a status, oldest first.
+func (s *KYCServiceImpl) ListVerifications(ctx context.Context, status string, limit, offset int) ([]*domain.KYCVerification, error) {
+	switch status {
+	case domain.KYCStatusPending, domain.KYCStatusReview, domain.KYCStatusApproved, domain.KYCStatusRejected:
+	default:
+		return nil, &domain.ValidationError{Msg: "status must be pending, review, approved or rejected"}
+	}
+	return s.repo.ListByStatus(ctx, status, limit, offset)
+}
+
+// GetVerification returns a verification by ID.
+func (s *KYCServiceImpl) GetVerification(ctx context.Context, id int) (*domain.KYCVerification, error) {
+	verification, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KYC verification: %w", err)
+	}
+	if verification == nil {
+		return nil, domain.ErrKYCVerificationNotFound
+	}
+	return verification, nil
+}
+
+// Review approves or rejects an open verification on behalf of an admin.
+func (s *KYCServiceImpl) Review(ctx context.Context, id int, approve bool, reason string, actorID int) (*domain.KYCVerification, error) {
+	reason = strings.TrimSpace(reason)
+	status := domain.KYCStatusApproved
+	if !approve {
+		status = domain.KYCStatusRejected
+		if reason == "" {
+			return nil, &domain.ValidationError{Msg: "a reason is required to reject a verification"}
+		}
+	}
+	verification, err := s.GetVerification(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !verification.IsOpen() {
+		return nil, domain.ErrKYCNotPending
+	}
+	if err := s.decide(ctx, verification, status, reason, &actorID); err != nil {
+		return nil, err
+	}
+	s.record(ctx, actorID, "kyc_verification", id, "review_kyc",
+		"user_id="+strconv.Itoa(verification.UserID)+" level="+strconv.Itoa(verification.Level)+" status="+status)
+	return verification, nil
+}
+
+// decide stores a decision on a verification and carries it over to the user.
+func (s *KYCServiceImpl) decide(ctx context.Context, verification *domain.KYCVerification, status, reason string, reviewerID *int) error {
+	verification.Status, verification.Reason, verification.ReviewedBy = status, reason, reviewerID
+	if err := s.repo.Decide(ctx, verification); err != nil {
+		return err
+	}
+	metrics.KYCVerificationsTotal.WithLabelValues(status).Inc()
+	invalidateUsers(ctx, s.cache, &verification.UserID)
+	if s.notifier != nil && status != domain.KYCStatusReview {
+		s.notifier.Notify(ctx, verification.UserID, domain.EventKYCUpdated, map[string]string{
+			"status": status,
+			"level":  strconv.Itoa(verification.Level),
+			"reason": reason,
+		})
+	}
+	return nil
+}
+
+// TierLimits returns the limit rules of every level.
+func (s *KYCServiceImpl) TierLimits(ctx context.Context) (map[int][]domain.TransactionLimitRule, error) {
+	return s.repo.ListTierLimits(ctx)
+}
+
+// SetTierLimits replaces the limit rules of a level.
+func (s *KYCServiceImpl) SetTierLimits(ctx context.Context, level int, rules []domain.TransactionLimitRule, actorID int) error {
+	if level < domain.KYCLevelNone || level > domain.KYCLevelFull {
+		return &domain.ValidationError{Msg: "level must be 0, 1 or 2"}
+	}
+	seen := make(map[domain.RuleType]bool, len(rules))
+	types := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		if seen[rule.RuleType] {
+			return &domain.ValidationError{Msg: "each rule type may only appear once per level"}
+		}
+		seen[rule.RuleType] = true
+		types = append(types, string(rule.RuleType)+"="+formatAmount(rule.LimitAmount))
+	}
+	if err := s.repo.SetTierLimits(ctx, level, rules, actorID); err != nil {
+		return fmt.Errorf("failed to set KYC tier limits: %w", err)
+	}
+	s.record(ctx, actorID, "kyc_tier", level, "set_kyc_tier_limits", "rules="+strings.Join(types, ","))
+	return nil
+}
+
+// record audits an admin's KYC action; failures are only logged
+func (s *KYCServiceImpl) record(ctx context.Context, actorID int, entityType string, id int, action, details string) {
+	if err := s.audit.Record(ctx, &actorID, entityType, id, action, details); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("entity_type", entityType).Int("entity_id", id).Int("actor_id", actorID).Msg("Failed to audit KYC action")
+	}
+}
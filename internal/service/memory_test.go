@@ -0,0 +1,1140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/cache"
+)
+
+// memoryStore stands in for the database behind the unit of work in service
+// tests: balances with row versions, transactions with unique idempotency keys,
+// approval requests, holds, organizations with their sign-off records,
+// funding sources, bank transfers, gateway refunds, fee invoices, disputes and
+// the amounts recorded against limit rules. A unit of work buffers its writes and
+// commits them together; a balance someone else
+// changed since it was read fails with domain.ErrBalanceConflict, as the
+// version check of the Postgres repository does, and a pending transaction
+// someone else resolved fails with domain.ErrTransactionNotPending, and so
+// does a hold with domain.ErrHoldNotActive and a sign-off record with
+// domain.ErrApprovalAlreadyReviewed, and a bank transfer with
+// domain.ErrBankTransferNotPending, and a closed dispute with
+// domain.ErrDisputeClosed.
+type memoryStore struct {
+	mu           sync.Mutex
+	balances     map[int]memoryBalance
+	transactions map[int]*domain.Transaction
+	archived     map[int]*domain.Transaction // moved out by archive
+	keys         map[string]int              // idempotency key -> transaction ID
+	nextID       int
+	spent        map[int]float64 // recorded against limit rules, by user
+	spendLimit   float64         // what each user may spend in total; 0 is unlimited
+	approvals    map[int]*domain.Approval
+	holds        map[int]*domain.Hold
+	orgs         map[int]*domain.Organization
+	members      map[[2]int]*domain.OrganizationMember // by organization and user ID
+	signOffs     map[int]*domain.OrganizationTransaction
+	refunds      []*domain.GatewayRefund
+	sources      map[int]*domain.FundingSource
+	transfers    map[int]*domain.BankTransfer
+	invoices     map[int]*domain.Invoice
+	disputes     map[int]*domain.Dispute
+
+	// beforeUpdate, if set, runs once before the next balance update, such
+	// as to commit a competing change first
+	beforeUpdate func()
+}
+
+// memoryBalance is a committed balance row
+type memoryBalance struct {
+	amount, held float64
+	version      int
+	frozenAt     *time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		balances:     map[int]memoryBalance{},
+		transactions: map[int]*domain.Transaction{},
+		archived:     map[int]*domain.Transaction{},
+		keys:         map[string]int{},
+		spent:        map[int]float64{},
+		approvals:    map[int]*domain.Approval{},
+		holds:        map[int]*domain.Hold{},
+		orgs:         map[int]*domain.Organization{},
+		members:      map[[2]int]*domain.OrganizationMember{},
+		signOffs:     map[int]*domain.OrganizationTransaction{},
+		sources:      map[int]*domain.FundingSource{},
+		transfers:    map[int]*domain.BankTransfer{},
+		invoices:     map[int]*domain.Invoice{},
+		disputes:     map[int]*domain.Dispute{},
+	}
+}
+
+// approvalFor returns the committed approval request of a transaction
+func (s *memoryStore) approvalFor(transactionID int) *domain.Approval {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range s.approvals {
+		if a.TransactionID == transactionID {
+			copied := *a
+			return &copied
+		}
+	}
+	return nil
+}
+
+// setBalance stores a balance as if committed by an earlier unit of work
+func (s *memoryStore) setBalance(userID int, amount, held float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	row := s.balances[userID]
+	s.balances[userID] = memoryBalance{amount: amount, held: held, version: row.version + 1, frozenAt: row.frozenAt}
+}
+
+// balance returns a user's committed balance and held amount
+func (s *memoryStore) balance(userID int) (amount, held float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	row := s.balances[userID]
+	return row.amount, row.held
+}
+
+// committed returns the committed transactions in ID order
+func (s *memoryStore) committed() []*domain.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*domain.Transaction, 0, len(s.transactions))
+	for id := 1; id <= s.nextID; id++ {
+		if tx, ok := s.transactions[id]; ok {
+			copied := *tx
+			out = append(out, &copied)
+		}
+	}
+	return out
+}
+
+// archive moves a committed transaction out of the store as the archive job
+// does, keeping its idempotency key
+func (s *memoryStore) archive(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.archived[id] = s.transactions[id]
+	delete(s.transactions, id)
+}
+
+// Do implements domain.UnitOfWork
+func (s *memoryStore) Do(ctx context.Context, fn func(repos domain.UnitOfWorkRepositories) error) error {
+	w := &memoryWork{store: s, balances: map[int]*stagedBalance{}, statuses: map[int]string{}, resolved: map[int]string{}, spent: map[int]float64{}}
+	if err := fn(w.repos()); err != nil {
+		return err
+	}
+	return w.commit()
+}
+
+// memoryWork is a unit of work in progress: its writes, not yet visible to others
+type memoryWork struct {
+	store         *memoryStore
+	balances      map[int]*stagedBalance
+	transactions  []*domain.Transaction
+	statuses      map[int]string
+	resolved      map[int]string // pending transactions moved to a status
+	fees          map[int]*domain.Transaction
+	spent         map[int]float64
+	approvals     []*domain.Approval
+	decisions     map[int]*domain.Approval // pending approvals reviewed
+	holds         []*domain.Hold
+	resolvedHolds map[int]*domain.Hold // active holds captured or released
+	boundHolds    map[int]*int         // active holds bound to a pending capture, or unbound
+	signOffs      []*domain.OrganizationTransaction
+	reviewed      map[int]*domain.OrganizationTransaction // pending sign-offs reviewed
+	refunds       []*domain.GatewayRefund
+	removed       []int // funding sources
+	transfers     []*domain.BankTransfer
+	completed     map[int]*domain.BankTransfer // pending transfers settled or returned
+	disputed      map[int]*domain.Dispute      // open disputes moved to a status
+}
+
+// stagedBalance is a balance written in a unit of work, with the committed
+// version it was read at
+type stagedBalance struct {
+	row    memoryBalance
+	readAt int
+}
+
+func (w *memoryWork) repos() domain.UnitOfWorkRepositories {
+	return domain.UnitOfWorkRepositories{
+		Balances:      &memoryBalances{store: w.store, work: w},
+		Transactions:  &memoryTransactions{store: w.store, work: w},
+		Limits:        &memoryLimits{store: w.store, work: w},
+		Approvals:     &memoryApprovals{store: w.store, work: w},
+		Holds:         &memoryHolds{store: w.store, work: w},
+		Organizations: &memoryOrganizations{store: w.store, work: w},
+		Funding:       &memoryFunding{store: w.store, work: w},
+		BankTransfers: &memoryBankTransfers{store: w.store, work: w},
+		Disputes:      &memoryDisputes{store: w.store, work: w},
+	}
+}
+
+func (w *memoryWork) commit() error {
+	s := w.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for userID, staged := range w.balances {
+		if s.balances[userID].version != staged.readAt {
+			return domain.ErrBalanceConflict
+		}
+	}
+	for _, tx := range w.transactions {
+		if _, taken := s.keys[tx.IdempotencyKey]; tx.IdempotencyKey != "" && taken {
+			return errors.New("duplicate key value violates unique constraint")
+		}
+	}
+	for id := range w.resolved {
+		if tx, ok := s.transactions[id]; !ok || tx.Status != "pending_approval" {
+			return domain.ErrTransactionNotPending
+		}
+	}
+	for _, refund := range w.refunds {
+		if s.refund(refund.Provider, refund.RefundID) != nil {
+			return domain.ErrGatewayRefundExists
+		}
+	}
+	for id := range w.completed {
+		if transfer, ok := s.transfers[id]; !ok || transfer.Status != domain.BankTransferPending {
+			return domain.ErrBankTransferNotPending
+		}
+	}
+	for id := range w.disputed {
+		if dispute, ok := s.disputes[id]; !ok || !dispute.IsOpen() {
+			return domain.ErrDisputeClosed
+		}
+	}
+	for id := range w.reviewed {
+		if ot, ok := s.signOffs[id]; !ok || ot.Status != "pending" {
+			return domain.ErrApprovalAlreadyReviewed
+		}
+	}
+	for id := range w.decisions {
+		if a, ok := s.approvals[id]; !ok || a.Status != "pending" {
+			return domain.ErrApprovalAlreadyReviewed
+		}
+	}
+	for id, resolved := range w.resolvedHolds {
+		if hold, ok := s.holds[id]; !ok || hold.Status != "active" || !boundTo(hold, resolved.TransactionID) {
+			return domain.ErrHoldNotActive
+		}
+	}
+	for id, transactionID := range w.boundHolds {
+		if hold, ok := s.holds[id]; !ok || hold.Status != "active" || transactionID != nil && hold.TransactionID != nil {
+			return domain.ErrHoldNotActive
+		}
+	}
+	for userID, staged := range w.balances {
+		s.balances[userID] = staged.row
+	}
+	for _, tx := range w.transactions {
+		s.transactions[tx.ID] = tx
+		if tx.IdempotencyKey != "" {
+			s.keys[tx.IdempotencyKey] = tx.ID
+		}
+	}
+	for id, status := range w.statuses {
+		if tx, ok := s.transactions[id]; ok {
+			tx.Status = status
+		}
+	}
+	for id, status := range w.resolved {
+		s.transactions[id].Status = status
+	}
+	for _, a := range w.approvals {
+		s.approvals[a.ID] = a
+	}
+	for id, a := range w.decisions {
+		s.approvals[id] = a
+	}
+	for _, hold := range w.holds {
+		s.holds[hold.ID] = hold
+	}
+	for id, hold := range w.resolvedHolds {
+		s.holds[id] = hold
+	}
+	for id, transactionID := range w.boundHolds {
+		s.holds[id].TransactionID = transactionID
+	}
+	for _, ot := range w.signOffs {
+		s.signOffs[ot.ID] = ot
+	}
+	for id, ot := range w.reviewed {
+		s.signOffs[id] = ot
+	}
+	s.refunds = append(s.refunds, w.refunds...)
+	for _, id := range w.removed {
+		s.sources[id].Status = domain.FundingSourceRemoved
+	}
+	for _, transfer := range w.transfers {
+		s.transfers[transfer.ID] = transfer
+	}
+	for id, transfer := range w.completed {
+		s.transfers[id] = transfer
+	}
+	for id, dispute := range w.disputed {
+		s.disputes[id] = dispute
+	}
+	for id, fee := range w.fees {
+		if tx, ok := s.transactions[id]; ok {
+			tx.Fee, tx.FeeScheduleID = fee.Fee, fee.FeeScheduleID
+		}
+	}
+	for userID, amount := range w.spent {
+		s.spent[userID] += amount
+	}
+	return nil
+}
+
+// memoryBalances implements domain.BalanceRepository over a memoryStore,
+// inside a unit of work or, with no work, committing each write at once
+type memoryBalances struct {
+	domain.BalanceRepository
+	store *memoryStore
+	work  *memoryWork
+}
+
+func (r *memoryBalances) GetByUserID(ctx context.Context, userID int) (*domain.Balance, error) {
+	if r.work != nil {
+		if staged, ok := r.work.balances[userID]; ok {
+			return staged.row.balance(userID), nil
+		}
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	row, ok := r.store.balances[userID]
+	if !ok {
+		return nil, nil
+	}
+	return row.balance(userID), nil
+}
+
+func (r *memoryBalances) Update(ctx context.Context, balance *domain.Balance) error {
+	if hook := r.store.takeBeforeUpdate(); hook != nil {
+		hook()
+	}
+	row := memoryBalance{amount: balance.Amount, held: balance.HeldAmount, version: balance.Version + 1, frozenAt: balance.FrozenAt}
+	if r.work != nil {
+		if staged, ok := r.work.balances[balance.UserID]; ok {
+			if staged.row.version != balance.Version {
+				return domain.ErrBalanceConflict
+			}
+			staged.row = row
+			balance.Version++
+			return nil
+		}
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if r.store.balances[balance.UserID].version != balance.Version {
+		return domain.ErrBalanceConflict
+	}
+	if r.work != nil {
+		r.work.balances[balance.UserID] = &stagedBalance{row: row, readAt: balance.Version}
+	} else {
+		r.store.balances[balance.UserID] = row
+	}
+	balance.Version++
+	return nil
+}
+
+func (s *memoryStore) takeBeforeUpdate() func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hook := s.beforeUpdate
+	s.beforeUpdate = nil
+	return hook
+}
+
+func (row memoryBalance) balance(userID int) *domain.Balance {
+	return &domain.Balance{UserID: userID, Amount: row.amount, HeldAmount: row.held, Version: row.version, FrozenAt: row.frozenAt}
+}
+
+// memoryTransactions implements domain.TransactionRepository over a
+// memoryStore, inside a unit of work or, with no work, committing at once
+type memoryTransactions struct {
+	domain.TransactionRepository
+	store *memoryStore
+	work  *memoryWork
+}
+
+func (r *memoryTransactions) Create(ctx context.Context, tx *domain.Transaction) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.nextID++
+	tx.ID, tx.CreatedAt = r.store.nextID, time.Now().UTC()
+	copied := *tx
+	if r.work != nil {
+		r.work.transactions = append(r.work.transactions, &copied)
+		return nil
+	}
+	if _, taken := r.store.keys[tx.IdempotencyKey]; tx.IdempotencyKey != "" && taken {
+		return errors.New("duplicate key value violates unique constraint")
+	}
+	r.store.transactions[tx.ID] = &copied
+	if tx.IdempotencyKey != "" {
+		r.store.keys[tx.IdempotencyKey] = tx.ID
+	}
+	return nil
+}
+
+func (r *memoryTransactions) GetByID(ctx context.Context, id int) (*domain.Transaction, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if tx, ok := r.store.transactions[id]; ok {
+		copied := *tx
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryTransactions) GetByIdempotencyKey(ctx context.Context, key string) (*domain.Transaction, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	id, ok := r.store.keys[key]
+	if !ok {
+		return nil, nil
+	}
+	tx, ok := r.store.transactions[id]
+	if !ok {
+		tx = r.store.archived[id]
+	}
+	copied := *tx
+	return &copied, nil
+}
+
+func (r *memoryTransactions) UpdateStatus(ctx context.Context, id int, status string) error {
+	if r.work != nil {
+		r.work.statuses[id] = status
+		return nil
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	tx, ok := r.store.transactions[id]
+	if !ok {
+		return errors.New("transaction not found")
+	}
+	tx.Status = status
+	return nil
+}
+
+func (r *memoryTransactions) ResolvePending(ctx context.Context, id int, status string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	tx, ok := r.store.transactions[id]
+	if !ok || tx.Status != "pending_approval" {
+		return domain.ErrTransactionNotPending
+	}
+	if r.work != nil {
+		if _, taken := r.work.resolved[id]; taken {
+			return domain.ErrTransactionNotPending
+		}
+		r.work.resolved[id] = status
+		return nil
+	}
+	tx.Status = status
+	return nil
+}
+
+func (r *memoryTransactions) SetFee(ctx context.Context, id int, fee float64, scheduleID *int) error {
+	if r.work != nil {
+		if r.work.fees == nil {
+			r.work.fees = map[int]*domain.Transaction{}
+		}
+		r.work.fees[id] = &domain.Transaction{Fee: fee, FeeScheduleID: scheduleID}
+		return nil
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	tx, ok := r.store.transactions[id]
+	if !ok {
+		return errors.New("transaction not found")
+	}
+	tx.Fee, tx.FeeScheduleID = fee, scheduleID
+	return nil
+}
+
+// memoryApprovals implements domain.ApprovalRepository over a memoryStore,
+// inside a unit of work or, with no work, committing at once
+type memoryApprovals struct {
+	store *memoryStore
+	work  *memoryWork
+}
+
+func (r *memoryApprovals) Create(ctx context.Context, a *domain.Approval) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.nextID++
+	a.ID = r.store.nextID
+	copied := *a
+	if r.work != nil {
+		r.work.approvals = append(r.work.approvals, &copied)
+		return nil
+	}
+	r.store.approvals[a.ID] = &copied
+	return nil
+}
+
+func (r *memoryApprovals) GetByTransactionID(ctx context.Context, transactionID int) (*domain.Approval, error) {
+	return r.store.approvalFor(transactionID), nil
+}
+
+func (r *memoryApprovals) ListPending(ctx context.Context, limit, offset int) ([]*domain.Approval, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	var out []*domain.Approval
+	for _, a := range r.store.approvals {
+		if a.Status == "pending" {
+			copied := *a
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryApprovals) Update(ctx context.Context, a *domain.Approval) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.approvals[a.ID]
+	if !ok || stored.Status != "pending" {
+		return domain.ErrApprovalAlreadyReviewed
+	}
+	copied := *a
+	if r.work != nil {
+		if r.work.decisions == nil {
+			r.work.decisions = map[int]*domain.Approval{}
+		}
+		r.work.decisions[a.ID] = &copied
+		return nil
+	}
+	r.store.approvals[a.ID] = &copied
+	return nil
+}
+
+// memoryHolds implements domain.HoldRepository over a memoryStore, inside a
+// unit of work or, with no work, committing at once
+type memoryHolds struct {
+	store *memoryStore
+	work  *memoryWork
+}
+
+func (r *memoryHolds) Create(ctx context.Context, hold *domain.Hold) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.nextID++
+	hold.ID = r.store.nextID
+	copied := *hold
+	if r.work != nil {
+		r.work.holds = append(r.work.holds, &copied)
+		return nil
+	}
+	r.store.holds[hold.ID] = &copied
+	return nil
+}
+
+func (r *memoryHolds) GetByID(ctx context.Context, id int) (*domain.Hold, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if hold, ok := r.store.holds[id]; ok {
+		copied := *hold
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryHolds) ListActiveByUser(ctx context.Context, userID int) ([]*domain.Hold, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	var out []*domain.Hold
+	for _, hold := range r.store.holds {
+		if hold.UserID == userID && hold.Status == "active" {
+			copied := *hold
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryHolds) GetByTransactionID(ctx context.Context, transactionID int) (*domain.Hold, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, hold := range r.store.holds {
+		if hold.Status == "active" && hold.TransactionID != nil && *hold.TransactionID == transactionID {
+			copied := *hold
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryHolds) Resolve(ctx context.Context, hold *domain.Hold) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.holds[hold.ID]
+	if !ok || stored.Status != "active" || !boundTo(stored, hold.TransactionID) {
+		return domain.ErrHoldNotActive
+	}
+	copied := *hold
+	if r.work != nil {
+		if r.work.resolvedHolds == nil {
+			r.work.resolvedHolds = map[int]*domain.Hold{}
+		}
+		r.work.resolvedHolds[hold.ID] = &copied
+		return nil
+	}
+	r.store.holds[hold.ID] = &copied
+	return nil
+}
+
+func (r *memoryHolds) Bind(ctx context.Context, holdID, transactionID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.holds[holdID]
+	if !ok || stored.Status != "active" || stored.TransactionID != nil {
+		return domain.ErrHoldNotActive
+	}
+	r.bind(holdID, &transactionID)
+	return nil
+}
+
+func (r *memoryHolds) Unbind(ctx context.Context, transactionID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for id, hold := range r.store.holds {
+		if hold.Status == "active" && hold.TransactionID != nil && *hold.TransactionID == transactionID {
+			r.bind(id, nil)
+		}
+	}
+	return nil
+}
+
+// bind stages or stores the transaction a hold is bound to; the caller holds the store's lock
+func (r *memoryHolds) bind(holdID int, transactionID *int) {
+	if r.work != nil {
+		if r.work.boundHolds == nil {
+			r.work.boundHolds = map[int]*int{}
+		}
+		r.work.boundHolds[holdID] = transactionID
+		return
+	}
+	r.store.holds[holdID].TransactionID = transactionID
+}
+
+// boundTo reports whether a stored hold may be resolved with transactionID:
+// it is unbound, or bound to that transaction
+func boundTo(stored *domain.Hold, transactionID *int) bool {
+	return stored.TransactionID == nil || transactionID != nil && *stored.TransactionID == *transactionID
+}
+
+// addOrganization stores an organization whose account is accountUserID and
+// its members with their roles
+func (s *memoryStore) addOrganization(id, accountUserID int, roles map[int]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orgs[id] = &domain.Organization{ID: id, Name: "org", AccountUserID: accountUserID}
+	for userID, role := range roles {
+		s.members[[2]int{id, userID}] = &domain.OrganizationMember{OrganizationID: id, UserID: userID, Role: role}
+	}
+}
+
+// memoryOrganizations implements the organization, membership and sign-off
+// lookups of domain.OrganizationRepository over a memoryStore, inside a unit
+// of work or, with no work, committing at once
+type memoryOrganizations struct {
+	domain.OrganizationRepository
+	store *memoryStore
+	work  *memoryWork
+}
+
+func (r *memoryOrganizations) GetByID(ctx context.Context, id int) (*domain.Organization, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if org, ok := r.store.orgs[id]; ok {
+		copied := *org
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryOrganizations) GetMember(ctx context.Context, organizationID, userID int) (*domain.OrganizationMember, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if m, ok := r.store.members[[2]int{organizationID, userID}]; ok {
+		copied := *m
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryOrganizations) CreateTransaction(ctx context.Context, ot *domain.OrganizationTransaction) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.nextID++
+	ot.ID = r.store.nextID
+	copied := *ot
+	if r.work != nil {
+		r.work.signOffs = append(r.work.signOffs, &copied)
+		return nil
+	}
+	r.store.signOffs[ot.ID] = &copied
+	return nil
+}
+
+func (r *memoryOrganizations) GetTransaction(ctx context.Context, organizationID, transactionID int) (*domain.OrganizationTransaction, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, ot := range r.store.signOffs {
+		if ot.OrganizationID == organizationID && ot.TransactionID == transactionID {
+			copied := *ot
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryOrganizations) UpdateTransaction(ctx context.Context, ot *domain.OrganizationTransaction) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.signOffs[ot.ID]
+	if !ok || stored.Status != "pending" {
+		return domain.ErrApprovalAlreadyReviewed
+	}
+	copied := *ot
+	if r.work != nil {
+		if r.work.reviewed == nil {
+			r.work.reviewed = map[int]*domain.OrganizationTransaction{}
+		}
+		r.work.reviewed[ot.ID] = &copied
+		return nil
+	}
+	r.store.signOffs[ot.ID] = &copied
+	return nil
+}
+
+// refund returns a committed gateway refund; the caller holds s.mu
+func (s *memoryStore) refund(provider, refundID string) *domain.GatewayRefund {
+	for _, refund := range s.refunds {
+		if refund.Provider == provider && refund.RefundID == refundID {
+			return refund
+		}
+	}
+	return nil
+}
+
+// memoryFunding implements the funding sources and gateway refunds of
+// domain.FundingRepository over a memoryStore, inside a unit of work or, with
+// no work, reading what is committed
+type memoryFunding struct {
+	domain.FundingRepository
+	store *memoryStore
+	work  *memoryWork
+}
+
+func (r *memoryFunding) CreateGatewayRefund(ctx context.Context, refund *domain.GatewayRefund) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if r.store.refund(refund.Provider, refund.RefundID) != nil {
+		return domain.ErrGatewayRefundExists
+	}
+	r.store.nextID++
+	refund.ID, refund.CreatedAt = r.store.nextID, time.Now().UTC()
+	copied := *refund
+	r.work.refunds = append(r.work.refunds, &copied)
+	return nil
+}
+
+func (r *memoryFunding) GetGatewayRefund(ctx context.Context, provider, refundID string) (*domain.GatewayRefund, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if refund := r.store.refund(provider, refundID); refund != nil {
+		copied := *refund
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryFunding) SumGatewayRefunds(ctx context.Context, provider, chargeID string) (float64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	var total float64
+	for _, refund := range r.store.refunds {
+		if refund.Provider == provider && refund.ChargeID == chargeID {
+			total += refund.Amount
+		}
+	}
+	return total, nil
+}
+
+func (r *memoryFunding) GetSource(ctx context.Context, id int) (*domain.FundingSource, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if source, ok := r.store.sources[id]; ok {
+		copied := *source
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryFunding) RemoveSource(ctx context.Context, id int) error {
+	if r.work != nil {
+		r.work.removed = append(r.work.removed, id)
+		return nil
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.sources[id].Status = domain.FundingSourceRemoved
+	return nil
+}
+
+// memoryBankTransfers implements domain.BankTransferRepository over a
+// memoryStore, inside a unit of work or, with no work, reading what is
+// committed
+type memoryBankTransfers struct {
+	store *memoryStore
+	work  *memoryWork
+}
+
+func (r *memoryBankTransfers) Create(ctx context.Context, transfer *domain.BankTransfer) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.nextID++
+	transfer.ID = r.store.nextID
+	copied := *transfer
+	r.work.transfers = append(r.work.transfers, &copied)
+	return nil
+}
+
+func (r *memoryBankTransfers) Get(ctx context.Context, id int) (*domain.BankTransfer, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if transfer, ok := r.store.transfers[id]; ok {
+		copied := *transfer
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryBankTransfers) ListByUser(ctx context.Context, userID, limit, offset int) ([]*domain.BankTransfer, error) {
+	return r.list(func(t *domain.BankTransfer) bool { return t.UserID == userID }), nil
+}
+
+func (r *memoryBankTransfers) ListByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.BankTransfer, error) {
+	return r.list(func(t *domain.BankTransfer) bool { return t.Status == status }), nil
+}
+
+func (r *memoryBankTransfers) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.BankTransfer, error) {
+	return r.list(func(t *domain.BankTransfer) bool {
+		return t.Status == domain.BankTransferPending && !t.SettleAt.After(now)
+	}), nil
+}
+
+// list returns the committed transfers matching keep in ID order
+func (r *memoryBankTransfers) list(keep func(*domain.BankTransfer) bool) []*domain.BankTransfer {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	var out []*domain.BankTransfer
+	for id := 1; id <= r.store.nextID; id++ {
+		if transfer, ok := r.store.transfers[id]; ok && keep(transfer) {
+			copied := *transfer
+			out = append(out, &copied)
+		}
+	}
+	return out
+}
+
+func (r *memoryBankTransfers) Complete(ctx context.Context, transfer *domain.BankTransfer) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.transfers[transfer.ID]
+	if !ok || stored.Status != domain.BankTransferPending {
+		return domain.ErrBankTransferNotPending
+	}
+	now := time.Now().UTC()
+	transfer.CompletedAt = &now
+	copied := *transfer
+	if r.work.completed == nil {
+		r.work.completed = map[int]*domain.BankTransfer{}
+	}
+	r.work.completed[transfer.ID] = &copied
+	return nil
+}
+
+// memoryDisputes implements domain.DisputeRepository over a memoryStore,
+// inside a unit of work or, with no work, committing each write at once
+type memoryDisputes struct {
+	store *memoryStore
+	work  *memoryWork
+}
+
+func (r *memoryDisputes) Create(ctx context.Context, dispute *domain.Dispute) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, stored := range r.store.disputes {
+		if stored.TransactionID == dispute.TransactionID {
+			return domain.ErrDisputeExists
+		}
+	}
+	r.store.nextID++
+	dispute.ID = r.store.nextID
+	dispute.CreatedAt, dispute.UpdatedAt = time.Now().UTC(), time.Now().UTC()
+	copied := *dispute
+	r.store.disputes[dispute.ID] = &copied
+	return nil
+}
+
+func (r *memoryDisputes) GetByID(ctx context.Context, id int) (*domain.Dispute, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if dispute, ok := r.store.disputes[id]; ok {
+		copied := *dispute
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryDisputes) ListByUser(ctx context.Context, userID, limit, offset int) ([]*domain.Dispute, error) {
+	return r.list(func(d *domain.Dispute) bool { return d.UserID == userID }), nil
+}
+
+func (r *memoryDisputes) ListByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Dispute, error) {
+	return r.list(func(d *domain.Dispute) bool { return d.Status == status }), nil
+}
+
+// list returns the committed disputes matching keep in ID order
+func (r *memoryDisputes) list(keep func(*domain.Dispute) bool) []*domain.Dispute {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	var out []*domain.Dispute
+	for id := 1; id <= r.store.nextID; id++ {
+		if dispute, ok := r.store.disputes[id]; ok && keep(dispute) {
+			copied := *dispute
+			out = append(out, &copied)
+		}
+	}
+	return out
+}
+
+func (r *memoryDisputes) UpdateStatus(ctx context.Context, dispute *domain.Dispute) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.disputes[dispute.ID]
+	if !ok || !stored.IsOpen() {
+		return domain.ErrDisputeClosed
+	}
+	dispute.UpdatedAt = time.Now().UTC()
+	if !dispute.IsOpen() {
+		dispute.ClosedAt = &dispute.UpdatedAt
+	}
+	copied := *dispute
+	if r.work == nil {
+		r.store.disputes[dispute.ID] = &copied
+		return nil
+	}
+	if r.work.disputed == nil {
+		r.work.disputed = map[int]*domain.Dispute{}
+	}
+	r.work.disputed[dispute.ID] = &copied
+	return nil
+}
+
+// memoryInvoices implements domain.InvoiceRepository over a memoryStore,
+// invoicing the fees of its committed transactions
+type memoryInvoices struct {
+	store *memoryStore
+}
+
+// uninvoicedFees returns the fees of completed or pending transactions made
+// before end that aren't on an invoice, in ID order, by who paid them; the
+// caller holds s.mu
+func (s *memoryStore) uninvoicedFees(end time.Time) map[int][]*domain.InvoiceLine {
+	invoiced := map[int]bool{}
+	for _, invoice := range s.invoices {
+		for _, line := range invoice.Lines {
+			invoiced[line.TransactionID] = true
+		}
+	}
+	fees := map[int][]*domain.InvoiceLine{}
+	for id := 1; id <= s.nextID; id++ {
+		tx, ok := s.transactions[id]
+		if !ok || (tx.Status != "completed" && tx.Status != "pending_approval") || tx.Fee <= 0 || !tx.CreatedAt.Before(end) || invoiced[id] {
+			continue
+		}
+		payer := tx.FromUserID
+		if tx.Type == "credit" {
+			payer = tx.ToUserID
+		}
+		fees[*payer] = append(fees[*payer], &domain.InvoiceLine{
+			TransactionID:     tx.ID,
+			TransactionType:   tx.Type,
+			TransactionAmount: tx.Amount,
+			TransactionStatus: tx.Status,
+			FeeScheduleID:     tx.FeeScheduleID,
+			Amount:            tx.Fee,
+			OccurredAt:        tx.CreatedAt,
+		})
+	}
+	return fees
+}
+
+func (r *memoryInvoices) ListFeePayers(ctx context.Context, end time.Time) ([]int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	var payers []int
+	for userID := range r.store.uninvoicedFees(end) {
+		payers = append(payers, userID)
+	}
+	sort.Ints(payers)
+	return payers, nil
+}
+
+func (r *memoryInvoices) ListFeeLines(ctx context.Context, userID int, end time.Time) ([]*domain.InvoiceLine, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.store.uninvoicedFees(end)[userID], nil
+}
+
+func (r *memoryInvoices) Create(ctx context.Context, invoice *domain.Invoice) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	lines := map[int]bool{}
+	for _, line := range invoice.Lines {
+		lines[line.TransactionID] = true
+	}
+	for _, stored := range r.store.invoices {
+		if stored.UserID == invoice.UserID && stored.PeriodStart.Equal(invoice.PeriodStart) {
+			return domain.ErrInvoiceExists
+		}
+		for _, line := range stored.Lines {
+			if lines[line.TransactionID] {
+				return domain.ErrInvoiceExists
+			}
+		}
+	}
+	r.store.nextID++
+	invoice.ID, invoice.IssuedAt = r.store.nextID, time.Now().UTC()
+	copied := *invoice
+	r.store.invoices[invoice.ID] = &copied
+	return nil
+}
+
+func (r *memoryInvoices) ListOpen(ctx context.Context) ([]*domain.Invoice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	var out []*domain.Invoice
+	for id := 1; id <= r.store.nextID; id++ {
+		invoice, ok := r.store.invoices[id]
+		if !ok || invoice.Status != domain.InvoiceOpen {
+			continue
+		}
+		copied := *invoice
+		copied.Lines = nil
+		for _, line := range invoice.Lines {
+			live := *line
+			live.TransactionStatus, live.Amount = "", 0
+			if tx, ok := r.store.transactions[line.TransactionID]; ok {
+				live.TransactionStatus, live.Amount = tx.Status, tx.Fee
+			}
+			copied.Lines = append(copied.Lines, &live)
+		}
+		out = append(out, &copied)
+	}
+	return out, nil
+}
+
+func (r *memoryInvoices) Settle(ctx context.Context, invoice *domain.Invoice) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.invoices[invoice.ID]
+	if !ok || stored.Status != domain.InvoiceOpen {
+		return domain.ErrInvoiceNotOpen
+	}
+	copied := *invoice
+	r.store.invoices[invoice.ID] = &copied
+	return nil
+}
+
+func (r *memoryInvoices) Get(ctx context.Context, id int) (*domain.Invoice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if invoice, ok := r.store.invoices[id]; ok {
+		copied := *invoice
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryInvoices) ListByUser(ctx context.Context, userID, limit, offset int) ([]*domain.Invoice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	var out []*domain.Invoice
+	for id := r.store.nextID; id > 0; id-- {
+		if invoice, ok := r.store.invoices[id]; ok && invoice.UserID == userID {
+			copied := *invoice
+			copied.Lines = nil
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+// memoryLimits implements the limit rule check of domain.TransactionLimitRepository
+// with a single total each user may spend
+type memoryLimits struct {
+	domain.TransactionLimitRepository
+	store *memoryStore
+	work  *memoryWork
+}
+
+func (r *memoryLimits) CheckAndRecordTransaction(ctx context.Context, userID int, amount float64, currency string, timestamp time.Time) error {
+	r.store.mu.Lock()
+	spent := r.store.spent[userID]
+	limit := r.store.spendLimit
+	r.store.mu.Unlock()
+	if limit > 0 && spent+r.work.spent[userID]+amount > limit {
+		return &domain.LimitExceededError{RuleID: "total", RuleType: domain.RuleMaxPerTransaction, Limit: limit, Msg: "limit exceeded"}
+	}
+	r.work.spent[userID] += amount
+	return nil
+}
+
+// newMemoryTransactionService returns a TransactionServiceImpl over store,
+// with no cache, notifications, alerts, fees or rewards
+func newMemoryTransactionService(store *memoryStore) *TransactionServiceImpl {
+	return NewTransactionService(&memoryTransactions{store: store}, store, nil, nil, nil, nil, nil, TransactionReview{})
+}
+
+// recordingInvalidator implements domain.CacheInvalidator, recording the
+// patterns it is asked to drop
+type recordingInvalidator struct {
+	mu       sync.Mutex
+	patterns []string
+}
+
+func (r *recordingInvalidator) DeletePattern(ctx context.Context, pattern string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.patterns = append(r.patterns, pattern)
+	return nil
+}
+
+// invalidated reports whether the cached responses of userID were dropped
+func (r *recordingInvalidator) invalidated(userID int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, pattern := range r.patterns {
+		if pattern == cache.UserResponsesPattern(userID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// MerchantServiceImpl implements domain.MerchantService.
+type MerchantServiceImpl struct {
+	repo     domain.MerchantRepository
+	users    domain.UserRepository
+	funding  domain.FundingRepository
+	tasks    domain.TaskRepository
+	batches  domain.BatchSubmitter
+	audit    domain.AuditLogService
+	interval time.Duration
+	now      func() time.Time
+	runMu    sync.Mutex
+	stopChan chan struct{}
+}
+
+// NewMerchantService creates a new MerchantServiceImpl that runs payouts every interval.
+func NewMerchantService(repo domain.MerchantRepository, users domain.UserRepository, funding domain.FundingRepository, tasks domain.TaskRepository, batches domain.BatchSubmitter, audit domain.AuditLogService, interval time.Duration) *MerchantServiceImpl {
+	return &MerchantServiceImpl{
+		repo:     repo,
+		users:    users,
+		funding:  funding,
+		tasks:    tasks,
+		batches:  batches,
+		audit:    audit,
+		interval: interval,
+		now:      time.Now,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// SaveMerchant makes userID a merchant, or updates their merchant account.
+func (s *MerchantServiceImpl) SaveMerchant(ctx context.Context, userID int, merchant *domain.Merchant) (*domain.Merchant, error) {
+	merchant.UserID = userID
+	merchant.BusinessName = strings.TrimSpace(merchant.BusinessName)
+	merchant.PayoutSchedule = strings.ToLower(strings.TrimSpace(merchant.PayoutSchedule))
+	if err := merchant.Validate(); err != nil {
+		return nil, err
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, domain.ErrMerchantUserNotFound
+	}
+	source, err := s.funding.GetSource(ctx, merchant.SettlementSourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settlement account: %w", err)
+	}
+	if source == nil || source.UserID != userID || source.Kind != domain.FundingSourceBankAccount || source.Status != domain.FundingSourceActive {
+		return nil, domain.ErrSettlementSourceInvalid
+	}
+
+	if err := s.repo.SaveMerchant(ctx, merchant); err != nil {
+		return nil, fmt.Errorf("failed to save merchant: %w", err)
+	}
+	details := "schedule=" + merchant.PayoutSchedule + " settlement_source_id=" + strconv.Itoa(merchant.SettlementSourceID) +
+		" min_payout=" + formatAmount(merchant.MinPayout)
+	if err := s.audit.Record(ctx, &userID, "merchant", userID, "save_merchant", details); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("user_id", userID).Msg("Failed to audit merchant change")
+	}
+	return merchant, nil
+}
+
+// GetMerchant returns a user's merchant account.
+func (s *MerchantServiceImpl) GetMerchant(ctx context.Context, userID int) (*domain.Merchant, error) {
+	merchant, err := s.repo.GetMerchant(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merchant: %w", err)
+	}
+	if merchant == nil {
+		return nil, domain.ErrMerchantNotFound
+	}
+	return merchant, nil
+}
+
+// ListPayouts returns a page of a merchant's payouts, newest first.
+func (s *MerchantServiceImpl) ListPayouts(ctx context.Context, userID, limit, offset int) ([]*domain.Payout, error) {
+	if _, err := s.GetMerchant(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPayouts(ctx, userID, limit, offset)
+}
+
+// GetStatement returns one of a merchant's payouts with the transfers it pays.
+func (s *MerchantServiceImpl) GetStatement(ctx context.Context, userID, payoutID int) (*domain.PayoutStatement, error) {
+	payout, err := s.repo.GetPayout(ctx, payoutID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payout: %w", err)
+	}
+	if payout == nil || payout.MerchantID != userID {
+		return nil, domain.ErrPayoutNotFound
+	}
+	transfers, err := s.repo.ListTransfers(ctx, userID, payout.PeriodStart, payout.PeriodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payout transfers: %w", err)
+	}
+	if transfers == nil {
+		transfers = []*domain.Transaction{}
+	}
+	return &domain.PayoutStatement{Payout: payout, Transfers: transfers}, nil
+}
+
+// RunPayouts settles the processing payouts and pays out every merchant that is due.
+func (s *MerchantServiceImpl) RunPayouts(ctx context.Context) (*domain.PayoutRun, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	run := &domain.PayoutRun{}
+	processing, err := s.repo.ListProcessingPayouts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processing payouts: %w", err)
+	}
+	var submit []*domain.Payout
+	busy := make(map[int]bool)
+	for _, payout := range processing {
+		record, err := s.tasks.GetByID(ctx, payout.TaskID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get payout task: %w", err)
+		}
+		switch {
+		case record == nil:
+			// Never submitted, or lost before it was recorded
+			submit = append(submit, payout)
+			busy[payout.MerchantID] = true
+		case record.Status == "succeeded":
+			payout.Status, payout.TransactionID = domain.PayoutPaid, record.TransactionID
+			if err := s.settle(ctx, payout); err != nil {
+				return nil, err
+			}
+			run.Paid++
+		case record.Status == "failed":
+			payout.Status, payout.Error = domain.PayoutFailed, record.Error
+			if err := s.settle(ctx, payout); err != nil {
+				return nil, err
+			}
+			run.Failed++
+		default:
+			busy[payout.MerchantID] = true
+		}
+	}
+
+	merchants, err := s.repo.ListMerchants(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merchants: %w", err)
+	}
+	now := s.now()
+	for _, merchant := range merchants {
+		if busy[merchant.UserID] {
+			continue
+		}
+		payout, err := s.newPayout(ctx, merchant, now)
+		if err != nil {
+			return nil, err
+		}
+		if payout != nil {
+			submit = append(submit, payout)
+			run.Created++
+		}
+	}
+	if len(submit) == 0 {
+		return run, nil
+	}
+
+	tasks := make([]*domain.TransactionTask, 0, len(submit))
+	for _, payout := range submit {
+		tasks = append(tasks, &domain.TransactionTask{
+			ID:     payout.TaskID(),
+			Type:   "debit",
+			UserID: payout.MerchantID,
+			Amount: payout.Amount,
+		})
+	}
+	batch, err := s.batches.StartBatch(ctx, tasks, false)
+	if err != nil {
+		// The payouts stay processing without a task and are submitted again
+		return nil, fmt.Errorf("failed to submit payouts: %w", err)
+	}
+	run.BatchID = batch.BatchID
+	for _, payout := range submit {
+		payout.BatchID = batch.BatchID
+		if err := s.repo.UpdatePayout(ctx, payout); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Int("payout_id", payout.ID).Str("batch_id", batch.BatchID).Msg("Failed to record payout batch")
+		}
+	}
+	log.Ctx(ctx).Info().Str("batch_id", batch.BatchID).Int("payouts", len(submit)).Msg("Submitted merchant payouts")
+	return run, nil
+}
+
+// newPayout stores a processing payout of the merchant's latest period, if it is due.
+func (s *MerchantServiceImpl) newPayout(ctx context.Context, merchant *domain.Merchant, now time.Time) (*domain.Payout, error) {
+	start := merchant.CreatedAt
+	last, err := s.repo.LastPeriodEnd(ctx, merchant.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last payout period: %w", err)
+	}
+	if last != nil {
+		start = *last
+	}
+	end := merchant.PayoutCutoff(now)
+	if !end.After(start) {
+		return nil, nil
+	}
+	count, total, err := s.repo.SumTransfers(ctx, merchant.UserID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum merchant transfers: %w", err)
+	}
+	amount := math.Round(total*100) / 100
+	if count == 0 || amount <= 0 || amount < merchant.MinPayout {
+		return nil, nil
+	}
+
+	payout := &domain.Payout{
+		MerchantID:         merchant.UserID,
+		SettlementSourceID: merchant.SettlementSourceID,
+		PeriodStart:        start,
+		PeriodEnd:          end,
+		TransferCount:      count,
+		Amount:             amount,
+		Status:             domain.PayoutProcessing,
+	}
+	if err := s.repo.CreatePayout(ctx, payout); err != nil {
+		if errors.Is(err, domain.ErrPayoutExists) {
+			// Another instance got there first
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to create payout: %w", err)
+	}
+	metrics.PayoutsTotal.WithLabelValues(payout.Status).Inc()
+	return payout, nil
+}
+
+// settle stores the outcome of a processing payout
+func (s *MerchantServiceImpl) settle(ctx context.Context, payout *domain.Payout) error {
+	if err := s.repo.UpdatePayout(ctx, payout); err != nil {
+		if errors.Is(err, domain.ErrPayoutNotFound) {
+			// Settled by another instance
+			return nil
+		}
+		return fmt.Errorf("failed to update payout: %w", err)
+	}
+	metrics.PayoutsTotal.WithLabelValues(payout.Status).Inc()
+	if payout.Status == domain.PayoutFailed {
+		log.Ctx(ctx).Warn().Int("payout_id", payout.ID).Int("merchant_id", payout.MerchantID).Str("error", payout.Error).Msg("Merchant payout failed")
+	}
+	return nil
+}
+
+// Start runs payouts now and then every interval
+func (s *MerchantServiceImpl) Start(ctx context.Context) {
+	log.Ctx(ctx).Info().Dur("interval", s.interval).Msg("Starting merchant payouts")
+
+	go s.payoutLoop(ctx)
+}
+
+// Stop stops the periodic payouts
+func (s *MerchantServiceImpl) Stop() {
+	log.Info().Msg("Stopping merchant payouts")
+	close(s.stopChan)
+}
+
+func (s *MerchantServiceImpl) payoutLoop(ctx context.Context) {
+	defer metrics.TrackGoroutine("payouts")()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunPayouts(ctx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Merchant payout run failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
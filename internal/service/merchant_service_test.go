@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// memoryMerchants implements domain.MerchantRepository, keeping merchants and
+// payouts and paying out the transfers it is given
+type memoryMerchants struct {
+	merchants map[int]*domain.Merchant
+	payouts   []*domain.Payout
+	transfers []*domain.Transaction
+}
+
+func newMemoryMerchants() *memoryMerchants {
+	return &memoryMerchants{merchants: make(map[int]*domain.Merchant)}
+}
+
+func (r *memoryMerchants) SaveMerchant(ctx context.Context, merchant *domain.Merchant) error {
+	if existing, ok := r.merchants[merchant.UserID]; ok {
+		merchant.CreatedAt = existing.CreatedAt
+	} else {
+		merchant.CreatedAt = time.Now().UTC()
+	}
+	copied := *merchant
+	r.merchants[merchant.UserID] = &copied
+	return nil
+}
+
+func (r *memoryMerchants) GetMerchant(ctx context.Context, userID int) (*domain.Merchant, error) {
+	if merchant, ok := r.merchants[userID]; ok {
+		copied := *merchant
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryMerchants) ListMerchants(ctx context.Context) ([]*domain.Merchant, error) {
+	var merchants []*domain.Merchant
+	for _, merchant := range r.merchants {
+		copied := *merchant
+		merchants = append(merchants, &copied)
+	}
+	sort.Slice(merchants, func(i, j int) bool { return merchants[i].UserID < merchants[j].UserID })
+	return merchants, nil
+}
+
+func (r *memoryMerchants) LastPeriodEnd(ctx context.Context, merchantID int) (*time.Time, error) {
+	var last *time.Time
+	for _, payout := range r.payouts {
+		if payout.MerchantID == merchantID && payout.Status != domain.PayoutFailed && (last == nil || payout.PeriodEnd.After(*last)) {
+			end := payout.PeriodEnd
+			last = &end
+		}
+	}
+	return last, nil
+}
+
+func (r *memoryMerchants) SumTransfers(ctx context.Context, merchantID int, start, end time.Time) (int, float64, error) {
+	transfers, _ := r.ListTransfers(ctx, merchantID, start, end)
+	var total float64
+	for _, tx := range transfers {
+		total += tx.Amount
+	}
+	return len(transfers), total, nil
+}
+
+func (r *memoryMerchants) ListTransfers(ctx context.Context, merchantID int, start, end time.Time) ([]*domain.Transaction, error) {
+	var transfers []*domain.Transaction
+	for _, tx := range r.transfers {
+		if *tx.ToUserID == merchantID && !tx.CreatedAt.Before(start) && tx.CreatedAt.Before(end) {
+			transfers = append(transfers, tx)
+		}
+	}
+	return transfers, nil
+}
+
+func (r *memoryMerchants) CreatePayout(ctx context.Context, payout *domain.Payout) error {
+	for _, existing := range r.payouts {
+		if existing.MerchantID == payout.MerchantID && existing.PeriodStart.Equal(payout.PeriodStart) && existing.Status != domain.PayoutFailed {
+			return domain.ErrPayoutExists
+		}
+	}
+	payout.ID = len(r.payouts) + 1
+	copied := *payout
+	r.payouts = append(r.payouts, &copied)
+	return nil
+}
+
+func (r *memoryMerchants) GetPayout(ctx context.Context, id int) (*domain.Payout, error) {
+	for _, payout := range r.payouts {
+		if payout.ID == id {
+			copied := *payout
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryMerchants) ListPayouts(ctx context.Context, merchantID, limit, offset int) ([]*domain.Payout, error) {
+	var payouts []*domain.Payout
+	for i := len(r.payouts) - 1; i >= 0; i-- {
+		if r.payouts[i].MerchantID == merchantID {
+			copied := *r.payouts[i]
+			payouts = append(payouts, &copied)
+		}
+	}
+	return payouts, nil
+}
+
+func (r *memoryMerchants) ListProcessingPayouts(ctx context.Context) ([]*domain.Payout, error) {
+	var payouts []*domain.Payout
+	for _, payout := range r.payouts {
+		if payout.Status == domain.PayoutProcessing {
+			copied := *payout
+			payouts = append(payouts, &copied)
+		}
+	}
+	return payouts, nil
+}
+
+func (r *memoryMerchants) UpdatePayout(ctx context.Context, payout *domain.Payout) error {
+	for _, stored := range r.payouts {
+		if stored.ID == payout.ID && stored.Status == domain.PayoutProcessing {
+			stored.Status, stored.BatchID, stored.TransactionID, stored.Error = payout.Status, payout.BatchID, payout.TransactionID, payout.Error
+			return nil
+		}
+	}
+	return domain.ErrPayoutNotFound
+}
+
+// received records a completed transfer of amount to a merchant at
+func (r *memoryMerchants) received(merchantID int, amount float64, at time.Time) {
+	r.transfers = append(r.transfers, &domain.Transaction{ID: len(r.transfers) + 1, ToUserID: &merchantID, Amount: amount, Type: "transfer", Status: "completed", CreatedAt: at})
+}
+
+// memoryTasks implements the task lookup of domain.TaskRepository
+type memoryTasks struct {
+	domain.TaskRepository
+	records map[string]*domain.TaskRecord
+}
+
+func (r memoryTasks) GetByID(ctx context.Context, taskID string) (*domain.TaskRecord, error) {
+	return r.records[taskID], nil
+}
+
+// recordingBatches implements domain.BatchSubmitter, keeping the batches it
+// was asked to submit or failing with err
+type recordingBatches struct {
+	batches [][]*domain.TransactionTask
+	err     error
+}
+
+func (b *recordingBatches) StartBatch(ctx context.Context, tasks []*domain.TransactionTask, rollbackOnFailure bool) (*domain.BatchRecord, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	b.batches = append(b.batches, tasks)
+	return domain.NewBatchRecord("batch-"+strconv.Itoa(len(b.batches)), len(tasks)), nil
+}
+
+// merchantTestNow is the clock of the merchant tests, a Wednesday afternoon
+var merchantTestNow = time.Date(2026, 4, 15, 14, 0, 0, 0, time.UTC)
+
+// newMerchantTestService returns a merchant service over repo where user 1
+// owns active bank account 10, removed bank account 11 and card 12
+func newMerchantTestService(repo *memoryMerchants, tasks memoryTasks, batches *recordingBatches) *MerchantServiceImpl {
+	store := newMemoryStore()
+	store.sources[10] = &domain.FundingSource{ID: 10, UserID: 1, Kind: domain.FundingSourceBankAccount, Status: domain.FundingSourceActive}
+	store.sources[11] = &domain.FundingSource{ID: 11, UserID: 1, Kind: domain.FundingSourceBankAccount, Status: domain.FundingSourceRemoved}
+	store.sources[12] = &domain.FundingSource{ID: 12, UserID: 1, Kind: domain.FundingSourceCard, Status: domain.FundingSourceActive}
+	svc := NewMerchantService(repo, someUsers{ids: map[int]bool{1: true, 2: true}}, &memoryFunding{store: store}, tasks, batches, discardAudit{}, time.Hour)
+	svc.now = func() time.Time { return merchantTestNow }
+	return svc
+}
+
+func TestMerchantServiceImpl_SaveMerchant(t *testing.T) {
+	ctx := context.Background()
+	merchant := func(sourceID int) *domain.Merchant {
+		return &domain.Merchant{BusinessName: " Corner Shop ", SettlementSourceID: sourceID, PayoutSchedule: " Weekly ", MinPayout: 50}
+	}
+
+	t.Run("settles to the merchant's active bank account", func(t *testing.T) {
+		repo := newMemoryMerchants()
+		svc := newMerchantTestService(repo, memoryTasks{}, &recordingBatches{})
+
+		saved, err := svc.SaveMerchant(ctx, 1, merchant(10))
+		require.NoError(t, err)
+		assert.Equal(t, "Corner Shop", saved.BusinessName)
+		assert.Equal(t, domain.PayoutScheduleWeekly, saved.PayoutSchedule)
+		got, err := svc.GetMerchant(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 10, got.SettlementSourceID)
+	})
+
+	t.Run("not to another account", func(t *testing.T) {
+		svc := newMerchantTestService(newMemoryMerchants(), memoryTasks{}, &recordingBatches{})
+
+		for name, sourceID := range map[string]int{"removed": 11, "card": 12, "missing": 13} {
+			_, err := svc.SaveMerchant(ctx, 1, merchant(sourceID))
+			assert.ErrorIs(t, err, domain.ErrSettlementSourceInvalid, name)
+		}
+		_, err := svc.SaveMerchant(ctx, 2, merchant(10))
+		assert.ErrorIs(t, err, domain.ErrSettlementSourceInvalid, "someone else's account")
+	})
+
+	t.Run("of an existing user with a valid schedule", func(t *testing.T) {
+		svc := newMerchantTestService(newMemoryMerchants(), memoryTasks{}, &recordingBatches{})
+
+		_, err := svc.SaveMerchant(ctx, 3, merchant(10))
+		assert.ErrorIs(t, err, domain.ErrMerchantUserNotFound)
+		monthly := merchant(10)
+		monthly.PayoutSchedule = "monthly"
+		_, err = svc.SaveMerchant(ctx, 1, monthly)
+		var verr *domain.ValidationError
+		assert.ErrorAs(t, err, &verr)
+		_, err = svc.GetMerchant(ctx, 1)
+		assert.ErrorIs(t, err, domain.ErrMerchantNotFound)
+	})
+}
+
+func TestMerchantServiceImpl_RunPayouts(t *testing.T) {
+	ctx := context.Background()
+	monday := time.Date(2026, 4, 13, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+	// newRepo returns merchants 1, paid daily, and 2, paid weekly, who joined
+	// on the first of the month
+	newRepo := func() *memoryMerchants {
+		repo := newMemoryMerchants()
+		joined := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+		repo.merchants[1] = &domain.Merchant{UserID: 1, SettlementSourceID: 10, PayoutSchedule: domain.PayoutScheduleDaily, MinPayout: 20, CreatedAt: joined}
+		repo.merchants[2] = &domain.Merchant{UserID: 2, SettlementSourceID: 20, PayoutSchedule: domain.PayoutScheduleWeekly, CreatedAt: joined}
+		return repo
+	}
+
+	t.Run("pays each merchant's finished period as one batch", func(t *testing.T) {
+		repo := newRepo()
+		repo.received(1, 10.005, today.Add(-time.Hour))
+		repo.received(1, 15, time.Date(2026, 4, 2, 9, 0, 0, 0, time.UTC))
+		repo.received(1, 99, today.Add(time.Hour)) // today's, paid tomorrow
+		repo.received(2, 40, monday.Add(-time.Minute))
+		repo.received(2, 60, monday) // this week's
+		batches := &recordingBatches{}
+
+		run, err := newMerchantTestService(repo, memoryTasks{}, batches).RunPayouts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.PayoutRun{Created: 2, BatchID: "batch-1"}, run)
+
+		require.Len(t, repo.payouts, 2)
+		daily, weekly := repo.payouts[0], repo.payouts[1]
+		assert.Equal(t, today, daily.PeriodEnd)
+		assert.Equal(t, 2, daily.TransferCount)
+		assert.Equal(t, 25.01, daily.Amount, "rounded to cents")
+		assert.Equal(t, monday, weekly.PeriodEnd)
+		assert.Equal(t, 40.0, weekly.Amount)
+		assert.Equal(t, 20, weekly.SettlementSourceID)
+		for _, payout := range repo.payouts {
+			assert.Equal(t, domain.PayoutProcessing, payout.Status)
+			assert.Equal(t, "batch-1", payout.BatchID)
+		}
+		require.Len(t, batches.batches, 1)
+		assert.Equal(t, &domain.TransactionTask{ID: daily.TaskID(), Type: "debit", UserID: 1, Amount: 25.01}, batches.batches[0][0])
+
+		queued := memoryTasks{records: map[string]*domain.TaskRecord{
+			daily.TaskID():  {Status: "queued"},
+			weekly.TaskID(): {Status: "processing"},
+		}}
+		run, err = newMerchantTestService(repo, queued, batches).RunPayouts(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, run.Created, "processing payouts are not paid again")
+		assert.Len(t, batches.batches, 1)
+	})
+
+	t.Run("waits for the minimum payout", func(t *testing.T) {
+		repo := newRepo()
+		repo.received(1, 19.99, today.Add(-time.Hour))
+		batches := &recordingBatches{}
+
+		run, err := newMerchantTestService(repo, memoryTasks{}, batches).RunPayouts(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, run.Created)
+		assert.Empty(t, batches.batches)
+	})
+
+	t.Run("settles payouts from their tasks and pays failed periods again", func(t *testing.T) {
+		repo := newRepo()
+		repo.received(1, 30, today.Add(-48*time.Hour))
+		repo.received(2, 30, monday.Add(-time.Hour))
+		paidTx := 77
+		repo.payouts = []*domain.Payout{
+			{ID: 1, MerchantID: 1, PeriodStart: repo.merchants[1].CreatedAt, PeriodEnd: today.Add(-24 * time.Hour), Amount: 30, Status: domain.PayoutProcessing},
+			{ID: 2, MerchantID: 2, PeriodStart: repo.merchants[2].CreatedAt, PeriodEnd: monday, Amount: 30, Status: domain.PayoutProcessing},
+		}
+		tasks := memoryTasks{records: map[string]*domain.TaskRecord{
+			"payout-1": {Status: "succeeded", TransactionID: &paidTx},
+			"payout-2": {Status: "failed", Error: "insufficient funds"},
+		}}
+		batches := &recordingBatches{}
+
+		run, err := newMerchantTestService(repo, tasks, batches).RunPayouts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, run.Paid)
+		assert.Equal(t, 1, run.Failed)
+		assert.Equal(t, 1, run.Created)
+
+		assert.Equal(t, domain.PayoutPaid, repo.payouts[0].Status)
+		assert.Equal(t, &paidTx, repo.payouts[0].TransactionID)
+		assert.Equal(t, domain.PayoutFailed, repo.payouts[1].Status)
+		assert.Equal(t, "insufficient funds", repo.payouts[1].Error)
+		require.Len(t, repo.payouts, 3)
+		retry := repo.payouts[2]
+		assert.Equal(t, 2, retry.MerchantID)
+		assert.Equal(t, repo.payouts[1].PeriodStart, retry.PeriodStart, "the failed period is paid with the next one")
+		assert.Equal(t, 30.0, retry.Amount)
+	})
+
+	t.Run("resubmits processing payouts whose task was lost", func(t *testing.T) {
+		repo := newRepo()
+		repo.received(1, 30, today.Add(-time.Hour))
+		failure := errors.New("redis: connection refused")
+		batches := &recordingBatches{err: failure}
+
+		_, err := newMerchantTestService(repo, memoryTasks{}, batches).RunPayouts(ctx)
+		assert.ErrorIs(t, err, failure)
+		require.Len(t, repo.payouts, 1)
+		assert.Equal(t, domain.PayoutProcessing, repo.payouts[0].Status)
+
+		batches.err = nil
+		run, err := newMerchantTestService(repo, memoryTasks{}, batches).RunPayouts(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, run.Created)
+		require.Len(t, batches.batches, 1)
+		assert.Equal(t, repo.payouts[0].TaskID(), batches.batches[0][0].ID)
+		assert.Len(t, repo.payouts, 1)
+	})
+}
+
+func TestMerchantServiceImpl_GetStatement(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryMerchants()
+	start := time.Date(2026, 4, 14, 0, 0, 0, 0, time.UTC)
+	repo.received(1, 5, start.Add(-time.Minute))
+	repo.received(1, 7, start.Add(time.Hour))
+	repo.payouts = []*domain.Payout{{ID: 1, MerchantID: 1, PeriodStart: start, PeriodEnd: start.AddDate(0, 0, 1), Amount: 7, Status: domain.PayoutPaid}}
+	svc := newMerchantTestService(repo, memoryTasks{}, &recordingBatches{})
+
+	statement, err := svc.GetStatement(ctx, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, statement.Transfers, 1)
+	assert.Equal(t, 7.0, statement.Transfers[0].Amount)
+
+	_, err = svc.GetStatement(ctx, 2, 1)
+	assert.ErrorIs(t, err, domain.ErrPayoutNotFound, "only the merchant sees their payouts")
+	_, err = svc.GetStatement(ctx, 1, 2)
+	assert.ErrorIs(t, err, domain.ErrPayoutNotFound)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// MoneyRequestServiceImpl implements domain.MoneyRequestService
+type MoneyRequestServiceImpl struct {
+	repo      domain.MoneyRequestRepository
+	txService domain.TransactionService
+	notifier  domain.MoneyRequestNotifier
+	cache     domain.CacheInvalidator
+}
+
+// NewMoneyRequestService creates a new MoneyRequestServiceImpl.
+func NewMoneyRequestService(repo domain.MoneyRequestRepository, txService domain.TransactionService, notifier domain.MoneyRequestNotifier, cache domain.CacheInvalidator) *MoneyRequestServiceImpl {
+	return &MoneyRequestServiceImpl{
+		repo:      repo,
+		txService: txService,
+		notifier:  notifier,
+		cache:     cache,
+	}
+}
+
+// RequestMoney asks payerID to pay amount to requesterID and notifies the payer
+func (s *MoneyRequestServiceImpl) RequestMoney(ctx context.Context, requesterID, payerID int, amount float64, note string) (*domain.MoneyRequest, error) {
+	req, err := domain.NewMoneyRequest(requesterID, payerID, amount, note)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, req); err != nil {
+		return nil, fmt.Errorf("failed to create money request: %w", err)
+	}
+	invalidateUsers(ctx, s.cache, &requesterID, &payerID)
+
+	// The request stands even if the payer can't be notified; it shows up in their incoming list
+	if err := s.notifier.NotifyMoneyRequested(ctx, req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int("money_request_id", req.ID).Int("payer_id", payerID).Msg("Failed to notify payer of money request")
+	}
+	return req, nil
+}
+
+// GetRequest retrieves a money request by ID
+func (s *MoneyRequestServiceImpl) GetRequest(ctx context.Context, id int) (*domain.MoneyRequest, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// ListIncoming retrieves requests a user has been asked to pay
+func (s *MoneyRequestServiceImpl) ListIncoming(ctx context.Context, userID int) ([]*domain.MoneyRequest, error) {
+	return s.repo.ListByPayer(ctx, userID)
+}
+
+// ListOutgoing retrieves requests a user has sent
+func (s *MoneyRequestServiceImpl) ListOutgoing(ctx context.Context, userID int) ([]*domain.MoneyRequest, error) {
+	return s.repo.ListByRequester(ctx, userID)
+}
+
+// Accept pays a pending request by transferring from the payer to the requester.
+func (s *MoneyRequestServiceImpl) Accept(ctx context.Context, id int) (*domain.MoneyRequest, error) {
+	req, err := s.getRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Accept(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, req, "pending"); err != nil {
+		return nil, fmt.Errorf("failed to claim money request: %w", err)
+	}
+	defer invalidateUsers(ctx, s.cache, &req.RequesterID, &req.PayerID)
+
+	tx, err := s.txService.Transfer(ctx, req.PayerID, req.RequesterID, req.Amount, fmt.Sprintf("money-request:%d", req.ID))
+	if err != nil && !errors.Is(err, domain.ErrTransactionHeld) {
+		req.Reopen()
+		if reopenErr := s.repo.Update(ctx, req, "accepted"); reopenErr != nil {
+			log.Ctx(ctx).Error().Err(reopenErr).Int("money_request_id", req.ID).Msg("Failed to reopen money request after failed transfer")
+		}
+		return nil, fmt.Errorf("failed to transfer requested amount: %w", err)
+	}
+
+	req.TransactionID = &tx.ID
+	if updateErr := s.repo.Update(ctx, req, "accepted"); updateErr != nil {
+		log.Ctx(ctx).Error().Err(updateErr).Int("money_request_id", req.ID).Int("transaction_id", tx.ID).Msg("Paid money request could not record its transaction")
+		return nil, fmt.Errorf("failed to update money request: %w", updateErr)
+	}
+	return req, err
+}
+
+// Decline refuses a pending request without moving money
+func (s *MoneyRequestServiceImpl) Decline(ctx context.Context, id int) (*domain.MoneyRequest, error) {
+	return s.resolve(ctx, id, (*domain.MoneyRequest).Decline)
+}
+
+// Cancel withdraws a pending request
+func (s *MoneyRequestServiceImpl) Cancel(ctx context.Context, id int) (*domain.MoneyRequest, error) {
+	return s.resolve(ctx, id, (*domain.MoneyRequest).Cancel)
+}
+
+// resolve applies a status transition that doesn't move money and stores it
+func (s *MoneyRequestServiceImpl) resolve(ctx context.Context, id int, transition func(*domain.MoneyRequest) error) (*domain.MoneyRequest, error) {
+	req, err := s.getRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := transition(req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, req, "pending"); err != nil {
+		return nil, fmt.Errorf("failed to update money request: %w", err)
+	}
+	invalidateUsers(ctx, s.cache, &req.RequesterID, &req.PayerID)
+	return req, nil
+}
+
+// getRequest loads a money request, returning ErrMoneyRequestNotFound if it doesn't exist
+func (s *MoneyRequestServiceImpl) getRequest(ctx context.Context, id int) (*domain.MoneyRequest, error) {
+	req, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get money request: %w", err)
+	}
+	if req == nil {
+		return nil, domain.ErrMoneyRequestNotFound
+	}
+	return req, nil
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// memoryMoneyRequests implements domain.MoneyRequestRepository in memory
+type memoryMoneyRequests struct {
+	mu       sync.Mutex
+	requests map[int]*domain.MoneyRequest
+	nextID   int
+}
+
+func newMemoryMoneyRequests() *memoryMoneyRequests {
+	return &memoryMoneyRequests{requests: map[int]*domain.MoneyRequest{}}
+}
+
+func (r *memoryMoneyRequests) Create(ctx context.Context, req *domain.MoneyRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	req.ID = r.nextID
+	copied := *req
+	r.requests[req.ID] = &copied
+	return nil
+}
+
+func (r *memoryMoneyRequests) GetByID(ctx context.Context, id int) (*domain.MoneyRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req, ok := r.requests[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *req
+	return &copied, nil
+}
+
+func (r *memoryMoneyRequests) ListByPayer(ctx context.Context, payerID int) ([]*domain.MoneyRequest, error) {
+	return r.list(func(req *domain.MoneyRequest) bool { return req.PayerID == payerID }), nil
+}
+
+func (r *memoryMoneyRequests) ListByRequester(ctx context.Context, requesterID int) ([]*domain.MoneyRequest, error) {
+	return r.list(func(req *domain.MoneyRequest) bool { return req.RequesterID == requesterID }), nil
+}
+
+func (r *memoryMoneyRequests) list(match func(*domain.MoneyRequest) bool) []*domain.MoneyRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*domain.MoneyRequest
+	for _, req := range r.requests {
+		if match(req) {
+			copied := *req
+			out = append(out, &copied)
+		}
+	}
+	return out
+}
+
+func (r *memoryMoneyRequests) Update(ctx context.Context, req *domain.MoneyRequest, fromStatus string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.requests[req.ID]
+	if !ok || stored.Status != fromStatus {
+		return domain.ErrMoneyRequestChanged
+	}
+	copied := *req
+	r.requests[req.ID] = &copied
+	return nil
+}
+
+// discardMoneyRequests implements domain.MoneyRequestNotifier, telling nobody
+type discardMoneyRequests struct{}
+
+func (discardMoneyRequests) NotifyMoneyRequested(ctx context.Context, req *domain.MoneyRequest) error {
+	return nil
+}
+
+// newMoneyRequestTestService returns a MoneyRequestServiceImpl whose
+// transfers run through txService
+func newMoneyRequestTestService(txService domain.TransactionService) (*MoneyRequestServiceImpl, *memoryMoneyRequests, *recordingInvalidator) {
+	repo := newMemoryMoneyRequests()
+	invalidator := &recordingInvalidator{}
+	return NewMoneyRequestService(repo, txService, discardMoneyRequests{}, invalidator), repo, invalidator
+}
+
+func TestMoneyRequestServiceImpl_Accept(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pays the requester and tells both users' caches", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(2, 100, 0)
+		svc, _, invalidator := newMoneyRequestTestService(newMemoryTransactionService(store))
+		req, err := svc.RequestMoney(ctx, 1, 2, 30, "dinner")
+		require.NoError(t, err)
+		invalidator = &recordingInvalidator{}
+		svc.cache = invalidator
+
+		accepted, err := svc.Accept(ctx, req.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "accepted", accepted.Status)
+		require.NotNil(t, accepted.TransactionID)
+		payer, _ := store.balance(2)
+		requester, _ := store.balance(1)
+		assert.Equal(t, 70.0, payer)
+		assert.Equal(t, 30.0, requester)
+		assert.True(t, invalidator.invalidated(1), "the requester's list changed")
+		assert.True(t, invalidator.invalidated(2))
+
+		_, err = svc.Accept(ctx, req.ID)
+		assert.Error(t, err, "a request is paid once")
+		_, err = svc.Decline(ctx, req.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("concurrent accepts pay once", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(2, 100, 0)
+		svc, _, _ := newMoneyRequestTestService(newMemoryTransactionService(store))
+		req, err := svc.RequestMoney(ctx, 1, 2, 30, "")
+		require.NoError(t, err)
+
+		var paid atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := svc.Accept(ctx, req.ID); err == nil {
+					paid.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), paid.Load())
+		assert.Len(t, store.committed(), 1)
+		payer, _ := store.balance(2)
+		assert.Equal(t, 70.0, payer)
+	})
+
+	t.Run("reopens the request when the transfer fails", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(2, 10, 0)
+		svc, repo, _ := newMoneyRequestTestService(newMemoryTransactionService(store))
+		req, err := svc.RequestMoney(ctx, 1, 2, 30, "")
+		require.NoError(t, err)
+
+		_, err = svc.Accept(ctx, req.ID)
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+		reopened, _ := repo.GetByID(ctx, req.ID)
+		assert.Equal(t, "pending", reopened.Status)
+		assert.Nil(t, reopened.TransactionID)
+		assert.Nil(t, reopened.RespondedAt)
+
+		store.setBalance(2, 100, 0)
+		_, err = svc.Accept(ctx, req.ID)
+		assert.NoError(t, err, "the payer can try again")
+	})
+
+	t.Run("a transfer held for approval keeps the request accepted", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(2, 1000, 0)
+		txService, _ := newApprovalTestServices(store)
+		svc, repo, _ := newMoneyRequestTestService(txService)
+		req, err := svc.RequestMoney(ctx, 1, 2, 150, "")
+		require.NoError(t, err)
+
+		accepted, err := svc.Accept(ctx, req.ID)
+		assert.ErrorIs(t, err, domain.ErrTransactionHeld)
+		require.NotNil(t, accepted)
+		stored, _ := repo.GetByID(ctx, req.ID)
+		assert.Equal(t, "accepted", stored.Status)
+		require.NotNil(t, stored.TransactionID)
+		assert.NotNil(t, store.approvalFor(*stored.TransactionID))
+		payer, _ := store.balance(2)
+		assert.Equal(t, 1000.0, payer, "no money moves while held")
+	})
+}
+
+func TestMoneyRequestServiceImpl_DeclineAndCancel(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	svc, _, invalidator := newMoneyRequestTestService(newMemoryTransactionService(store))
+
+	declined, err := svc.RequestMoney(ctx, 1, 2, 30, "")
+	require.NoError(t, err)
+	assert.True(t, invalidator.invalidated(2), "the payer lists the new request")
+
+	invalidator = &recordingInvalidator{}
+	svc.cache = invalidator
+	req, err := svc.Decline(ctx, declined.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "declined", req.Status)
+	assert.NotNil(t, req.RespondedAt)
+	assert.True(t, invalidator.invalidated(1), "the requester sees the answer")
+
+	cancelled, err := svc.RequestMoney(ctx, 1, 2, 30, "")
+	require.NoError(t, err)
+	invalidator = &recordingInvalidator{}
+	svc.cache = invalidator
+	req, err = svc.Cancel(ctx, cancelled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", req.Status)
+	assert.True(t, invalidator.invalidated(2), "the payer sees it withdrawn")
+
+	_, err = svc.Accept(ctx, cancelled.ID)
+	assert.Error(t, err, "a withdrawn request can't be paid")
+	_, err = svc.Cancel(ctx, declined.ID)
+	assert.Error(t, err, "nor a declined one withdrawn")
+	_, err = svc.Decline(ctx, 99)
+	assert.ErrorIs(t, err, domain.ErrMoneyRequestNotFound)
+	assert.Empty(t, store.committed())
+}
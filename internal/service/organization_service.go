@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// OrganizationServiceImpl implements domain.OrganizationService
+type OrganizationServiceImpl struct {
+	repo      domain.OrganizationRepository
+	users     domain.UserRepository
+	txRepo    domain.TransactionRepository
+	txService domain.TransactionService
+	balances  domain.BalanceRepository
+	audit     domain.AuditLogService
+}
+
+// NewOrganizationService creates a new OrganizationServiceImpl.
+func NewOrganizationService(repo domain.OrganizationRepository, users domain.UserRepository, txRepo domain.TransactionRepository,
+	txService domain.TransactionService, balances domain.BalanceRepository, audit domain.AuditLogService) *OrganizationServiceImpl {
+	return &OrganizationServiceImpl{
+		repo:      repo,
+		users:     users,
+		txRepo:    txRepo,
+		txService: txService,
+		balances:  balances,
+		audit:     audit,
+	}
+}
+
+// Create creates an organization with its account user and makes actorID its owner
+func (s *OrganizationServiceImpl) Create(ctx context.Context, actorID int, name string) (*domain.Organization, error) {
+	org := &domain.Organization{Name: strings.TrimSpace(name), CreatedBy: actorID}
+	if err := org.Validate(); err != nil {
+		return nil, err
+	}
+
+	account := domain.NewOrganizationAccount()
+	if err := s.users.Create(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to create organization account: %w", err)
+	}
+	org.AccountUserID = account.ID
+	owner := &domain.OrganizationMember{UserID: actorID, Role: domain.OrgRoleOwner}
+	if err := s.repo.Create(ctx, org, owner); err != nil {
+		// The account would hold the balance of an organization that doesn't exist
+		if deleteErr := s.users.Delete(ctx, account.ID); deleteErr != nil {
+			log.Ctx(ctx).Error().Err(deleteErr).Int("user_id", account.ID).Msg("Failed to delete account of organization not created")
+		}
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	s.record(ctx, actorID, org.ID, "create", org.Name)
+	return org, nil
+}
+
+// ListForUser retrieves the organizations a user is a member of
+func (s *OrganizationServiceImpl) ListForUser(ctx context.Context, userID int) ([]*domain.Organization, error) {
+	return s.repo.ListByMember(ctx, userID)
+}
+
+// Get retrieves an organization with its members and balance
+func (s *OrganizationServiceImpl) Get(ctx context.Context, actorID int, isAdmin bool, organizationID int) (*domain.OrganizationDetails, error) {
+	org, _, err := s.access(ctx, actorID, isAdmin, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.ListMembers(ctx, org.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+	bal, err := s.balances.GetByUserID(ctx, org.AccountUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization balance: %w", err)
+	}
+
+	details := &domain.OrganizationDetails{Organization: org, Members: members}
+	if bal != nil {
+		details.Balance = bal.Amount
+	}
+	return details, nil
+}
+
+// AddMember adds a user to the organization with a role
+func (s *OrganizationServiceImpl) AddMember(ctx context.Context, actorID, organizationID, userID int, role string) (*domain.OrganizationMember, error) {
+	org, actor, err := s.access(ctx, actorID, false, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanManage() {
+		return nil, domain.ErrOrganizationRoleRequired
+	}
+	if !domain.ValidOrganizationRole(role) {
+		return nil, &domain.ValidationError{Msg: "role must be owner, approver or viewer"}
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.IsErased() || user.IsOrganizationAccount() {
+		return nil, &domain.ValidationError{Msg: "user does not exist"}
+	}
+
+	member := &domain.OrganizationMember{OrganizationID: org.ID, UserID: userID, Role: role}
+	if err := s.repo.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+	s.record(ctx, actorID, org.ID, "add_member", fmt.Sprintf("user %d as %s", userID, role))
+	return member, nil
+}
+
+// UpdateMemberRole changes a member's role, keeping at least one owner
+func (s *OrganizationServiceImpl) UpdateMemberRole(ctx context.Context, actorID, organizationID, userID int, role string) (*domain.OrganizationMember, error) {
+	org, actor, err := s.access(ctx, actorID, false, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanManage() {
+		return nil, domain.ErrOrganizationRoleRequired
+	}
+	if !domain.ValidOrganizationRole(role) {
+		return nil, &domain.ValidationError{Msg: "role must be owner, approver or viewer"}
+	}
+	member, err := s.member(ctx, org.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == domain.OrgRoleOwner && role != domain.OrgRoleOwner {
+		if err := s.keepOwner(ctx, org.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateMemberRole(ctx, org.ID, userID, role); err != nil {
+		return nil, err
+	}
+	member.Role = role
+	s.record(ctx, actorID, org.ID, "update_member", fmt.Sprintf("user %d to %s", userID, role))
+	return member, nil
+}
+
+// RemoveMember removes a member, keeping at least one owner.
+func (s *OrganizationServiceImpl) RemoveMember(ctx context.Context, actorID, organizationID, userID int) error {
+	org, actor, err := s.access(ctx, actorID, false, organizationID)
+	if err != nil {
+		return err
+	}
+	if actorID != userID && !actor.CanManage() {
+		return domain.ErrOrganizationRoleRequired
+	}
+	member, err := s.member(ctx, org.ID, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role == domain.OrgRoleOwner {
+		if err := s.keepOwner(ctx, org.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.RemoveMember(ctx, org.ID, userID); err != nil {
+		return err
+	}
+	s.record(ctx, actorID, org.ID, "remove_member", fmt.Sprintf("user %d", userID))
+	return nil
+}
+
+// Deposit transfers amount from the member's own balance to the organization
+func (s *OrganizationServiceImpl) Deposit(ctx context.Context, actorID, organizationID int, amount float64) (*domain.Transaction, error) {
+	org, _, err := s.access(ctx, actorID, false, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	return s.txService.Transfer(ctx, actorID, org.AccountUserID, amount, "")
+}
+
+// RequestTransaction records a payment from the organization's balance for sign-off.
+func (s *OrganizationServiceImpl) RequestTransaction(ctx context.Context, actorID, organizationID int, txType string, toUserID *int, amount float64) (*domain.OrganizationTransaction, error) {
+	org, actor, err := s.access(ctx, actorID, false, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanSpend() {
+		return nil, domain.ErrOrganizationRoleRequired
+	}
+	switch txType {
+	case "debit":
+		toUserID = nil
+	case "transfer":
+		if toUserID == nil || *toUserID == org.AccountUserID {
+			return nil, &domain.ValidationError{Msg: "transfers need a to_user_id other than the organization"}
+		}
+	default:
+		return nil, &domain.ValidationError{Msg: "type must be debit or transfer"}
+	}
+
+	tx := &domain.Transaction{FromUserID: &org.AccountUserID, ToUserID: toUserID, Amount: amount, Type: txType, Status: "pending_approval"}
+	if err := tx.Validate(); err != nil {
+		return nil, &domain.ValidationError{Msg: err.Error()}
+	}
+	// Fail early when the money isn't there; it is checked again on approval
+	bal, err := s.balances.GetByUserID(ctx, org.AccountUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization balance: %w", err)
+	}
+	if bal == nil || bal.AvailableAmount() < amount {
+		return nil, domain.ErrInsufficientFunds
+	}
+
+	var ot *domain.OrganizationTransaction
+	err = s.txService.HoldForSignOff(ctx, tx, func(repos domain.UnitOfWorkRepositories) error {
+		ot = domain.NewOrganizationTransaction(org.ID, tx.ID, actorID)
+		return repos.Organizations.CreateTransaction(ctx, ot)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, actorID, org.ID, "request_transaction", fmt.Sprintf("%s of %.2f (transaction %d)", tx.Type, tx.Amount, tx.ID))
+	return ot, nil
+}
+
+// ApproveTransaction signs off a held transaction and executes it.
+func (s *OrganizationServiceImpl) ApproveTransaction(ctx context.Context, actorID, organizationID, transactionID int) (*domain.Transaction, error) {
+	org, ot, tx, err := s.pendingTransaction(ctx, actorID, organizationID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ot.Approve(actorID); err != nil {
+		return nil, err
+	}
+	err = s.txService.ExecutePending(ctx, tx, func(repos domain.UnitOfWorkRepositories) error {
+		return repos.Organizations.UpdateTransaction(ctx, ot)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrApprovalAlreadyReviewed) || errors.Is(err, domain.ErrTransactionNotPending) {
+			return nil, err
+		}
+		if tx.Status != "failed" {
+			return nil, fmt.Errorf("failed to execute transaction: %w", err)
+		}
+		log.Ctx(ctx).Warn().Err(err).Int("transaction_id", tx.ID).Int("organization_id", org.ID).Msg("Approved organization transaction could not be executed")
+	}
+	s.record(ctx, actorID, org.ID, "approve_transaction", fmt.Sprintf("transaction %d", tx.ID))
+	return tx, nil
+}
+
+// RejectTransaction cancels a held transaction without moving any money
+func (s *OrganizationServiceImpl) RejectTransaction(ctx context.Context, actorID, organizationID, transactionID int, reason string) (*domain.Transaction, error) {
+	org, ot, tx, err := s.pendingTransaction(ctx, actorID, organizationID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ot.Reject(actorID, reason); err != nil {
+		return nil, err
+	}
+	err = s.txService.RejectPending(ctx, tx, func(repos domain.UnitOfWorkRepositories) error {
+		return repos.Organizations.UpdateTransaction(ctx, ot)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrApprovalAlreadyReviewed) || errors.Is(err, domain.ErrTransactionNotPending) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to reject transaction: %w", err)
+	}
+	s.record(ctx, actorID, org.ID, "reject_transaction", fmt.Sprintf("transaction %d: %s", tx.ID, reason))
+	return tx, nil
+}
+
+// ListTransactions retrieves a page of the transactions of the organization's balance
+func (s *OrganizationServiceImpl) ListTransactions(ctx context.Context, actorID int, isAdmin bool, organizationID int, limit, offset int) ([]*domain.Transaction, error) {
+	org, _, err := s.access(ctx, actorID, isAdmin, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	return s.txService.ListUserTransactions(ctx, org.AccountUserID, limit, offset)
+}
+
+// ListSignOffs retrieves the organization's sign-off records, newest first
+func (s *OrganizationServiceImpl) ListSignOffs(ctx context.Context, actorID int, isAdmin bool, organizationID int, status string, limit, offset int) ([]*domain.OrganizationTransaction, error) {
+	org, _, err := s.access(ctx, actorID, isAdmin, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if status != "" && status != "pending" && status != "approved" && status != "rejected" {
+		return nil, &domain.ValidationError{Msg: "status must be pending, approved or rejected"}
+	}
+	return s.repo.ListTransactions(ctx, org.ID, status, limit, offset)
+}
+
+// access loads an organization and the actor's membership.
+func (s *OrganizationServiceImpl) access(ctx context.Context, actorID int, isAdmin bool, organizationID int) (*domain.Organization, *domain.OrganizationMember, error) {
+	org, err := s.repo.GetByID(ctx, organizationID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	if org == nil {
+		return nil, nil, domain.ErrOrganizationNotFound
+	}
+	member, err := s.repo.GetMember(ctx, organizationID, actorID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get organization member: %w", err)
+	}
+	if member == nil && !isAdmin {
+		return nil, nil, domain.ErrOrganizationNotFound
+	}
+	return org, member, nil
+}
+
+// member loads a membership that must exist
+func (s *OrganizationServiceImpl) member(ctx context.Context, organizationID, userID int) (*domain.OrganizationMember, error) {
+	member, err := s.repo.GetMember(ctx, organizationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization member: %w", err)
+	}
+	if member == nil {
+		return nil, domain.ErrOrganizationMemberNotFound
+	}
+	return member, nil
+}
+
+// keepOwner fails if the organization has only one owner, who is about to go
+func (s *OrganizationServiceImpl) keepOwner(ctx context.Context, organizationID int) error {
+	owners, err := s.repo.CountOwners(ctx, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to count organization owners: %w", err)
+	}
+	if owners <= 1 {
+		return domain.ErrLastOrganizationOwner
+	}
+	return nil
+}
+
+// pendingTransaction loads a held transaction of the organization for a member allowed to review it
+func (s *OrganizationServiceImpl) pendingTransaction(ctx context.Context, actorID, organizationID, transactionID int) (*domain.Organization, *domain.OrganizationTransaction, *domain.Transaction, error) {
+	org, actor, err := s.access(ctx, actorID, false, organizationID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !actor.CanSpend() {
+		return nil, nil, nil, domain.ErrOrganizationRoleRequired
+	}
+	ot, err := s.repo.GetTransaction(ctx, org.ID, transactionID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get organization transaction: %w", err)
+	}
+	if ot == nil {
+		return nil, nil, nil, domain.ErrOrganizationTransactionNotFound
+	}
+	tx, err := s.txRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if tx == nil {
+		return nil, nil, nil, errors.New("transaction of organization sign-off is missing")
+	}
+	return org, ot, tx, nil
+}
+
+// record writes an organization change to the audit log.
+func (s *OrganizationServiceImpl) record(ctx context.Context, actorID, organizationID int, action, details string) {
+	if err := s.audit.Record(ctx, &actorID, "organization", organizationID, action, details); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("organization_id", organizationID).Int("actor_id", actorID).Str("action", action).Msg("Failed to audit organization change")
+	}
+}
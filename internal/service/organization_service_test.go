@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// Organization 1 spends from the balance of account user 100. User 1 owns it,
+// user 2 approves and user 3 only views.
+const (
+	testOrgID      = 1
+	testOrgAccount = 100
+)
+
+// newOrganizationTestService returns an OrganizationServiceImpl over store,
+// screening for fraud with fraud if it isn't nil
+func newOrganizationTestService(store *memoryStore, fraud *fixedFraud) *OrganizationServiceImpl {
+	store.addOrganization(testOrgID, testOrgAccount, map[int]string{1: domain.OrgRoleOwner, 2: domain.OrgRoleApprover, 3: domain.OrgRoleViewer})
+	store.setBalance(testOrgAccount, 500, 0)
+	review := TransactionReview{}
+	if fraud != nil {
+		review.Fraud = fraud
+	}
+	txRepo := &memoryTransactions{store: store}
+	txService := NewTransactionService(txRepo, store, nil, nil, nil, nil, nil, review)
+	return NewOrganizationService(&memoryOrganizations{store: store}, nil, txRepo, txService, &memoryBalances{store: store}, discardAudit{})
+}
+
+func TestOrganizationServiceImpl_RequestTransaction(t *testing.T) {
+	ctx := context.Background()
+	to := 9
+
+	t.Run("holds the transfer for sign-off without moving money", func(t *testing.T) {
+		store := newMemoryStore()
+		orgs := newOrganizationTestService(store, nil)
+
+		ot, err := orgs.RequestTransaction(ctx, 1, testOrgID, "transfer", &to, 200)
+		require.NoError(t, err)
+		assert.Equal(t, "pending", ot.Status)
+		assert.Equal(t, 1, ot.RequestedBy)
+
+		txs := store.committed()
+		require.Len(t, txs, 1)
+		assert.Equal(t, ot.TransactionID, txs[0].ID)
+		assert.Equal(t, "pending_approval", txs[0].Status)
+		amount, _ := store.balance(testOrgAccount)
+		assert.Equal(t, 500.0, amount)
+	})
+
+	t.Run("viewers cannot spend", func(t *testing.T) {
+		store := newMemoryStore()
+		orgs := newOrganizationTestService(store, nil)
+
+		_, err := orgs.RequestTransaction(ctx, 3, testOrgID, "debit", nil, 50)
+		assert.ErrorIs(t, err, domain.ErrOrganizationRoleRequired)
+		_, err = orgs.RequestTransaction(ctx, 4, testOrgID, "debit", nil, 50)
+		assert.ErrorIs(t, err, domain.ErrOrganizationNotFound, "nor can outsiders")
+		assert.Empty(t, store.committed())
+	})
+
+	t.Run("more than the balance is refused", func(t *testing.T) {
+		store := newMemoryStore()
+		orgs := newOrganizationTestService(store, nil)
+
+		_, err := orgs.RequestTransaction(ctx, 1, testOrgID, "debit", nil, 600)
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+		assert.Empty(t, store.committed())
+	})
+
+	t.Run("fraud screening rejects it before it is recorded", func(t *testing.T) {
+		store := newMemoryStore()
+		fraud := &fixedFraud{decision: domain.FraudDecisionReject}
+		orgs := newOrganizationTestService(store, fraud)
+
+		_, err := orgs.RequestTransaction(ctx, 1, testOrgID, "transfer", &to, 200)
+		assert.ErrorIs(t, err, domain.ErrFraudRejected)
+		assert.Equal(t, []string{"transfer"}, fraud.assessed)
+		assert.Empty(t, store.committed())
+	})
+
+	t.Run("the fraud assessment is linked to the held transaction", func(t *testing.T) {
+		store := newMemoryStore()
+		fraud := &fixedFraud{decision: domain.FraudDecisionAllow}
+		orgs := newOrganizationTestService(store, fraud)
+
+		ot, err := orgs.RequestTransaction(ctx, 1, testOrgID, "debit", nil, 200)
+		require.NoError(t, err)
+		assert.Equal(t, ot.TransactionID, fraud.linked[1])
+	})
+}
+
+func TestOrganizationServiceImpl_ApproveTransaction(t *testing.T) {
+	ctx := context.Background()
+	to := 9
+	store := newMemoryStore()
+	orgs := newOrganizationTestService(store, nil)
+
+	ot, err := orgs.RequestTransaction(ctx, 1, testOrgID, "transfer", &to, 200)
+	require.NoError(t, err)
+
+	_, err = orgs.ApproveTransaction(ctx, 1, testOrgID, ot.TransactionID)
+	assert.ErrorIs(t, err, domain.ErrSelfApproval)
+	_, err = orgs.ApproveTransaction(ctx, 3, testOrgID, ot.TransactionID)
+	assert.ErrorIs(t, err, domain.ErrOrganizationRoleRequired)
+
+	tx, err := orgs.ApproveTransaction(ctx, 2, testOrgID, ot.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", tx.Status)
+
+	_, err = orgs.ApproveTransaction(ctx, 2, testOrgID, ot.TransactionID)
+	assert.Error(t, err, "a transaction is approved once")
+	_, err = orgs.RejectTransaction(ctx, 2, testOrgID, ot.TransactionID, "too late")
+	assert.Error(t, err)
+
+	amount, _ := store.balance(testOrgAccount)
+	payee, _ := store.balance(to)
+	assert.Equal(t, 300.0, amount)
+	assert.Equal(t, 200.0, payee)
+}
+
+func TestOrganizationServiceImpl_ConcurrentApprovalsMoveMoneyOnce(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	orgs := newOrganizationTestService(store, nil)
+	store.addOrganization(testOrgID, testOrgAccount, map[int]string{
+		10: domain.OrgRoleApprover, 11: domain.OrgRoleApprover, 12: domain.OrgRoleApprover, 13: domain.OrgRoleApprover, 14: domain.OrgRoleApprover,
+	})
+
+	ot, err := orgs.RequestTransaction(ctx, 1, testOrgID, "debit", nil, 200)
+	require.NoError(t, err)
+
+	var approved atomic.Int32
+	var wg sync.WaitGroup
+	for reviewer := 10; reviewer < 15; reviewer++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := orgs.ApproveTransaction(ctx, reviewer, testOrgID, ot.TransactionID)
+			if err == nil {
+				approved.Add(1)
+				return
+			}
+			var validation *domain.ValidationError
+			assert.True(t, errors.Is(err, domain.ErrApprovalAlreadyReviewed) || errors.As(err, &validation), "unexpected error: %v", err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), approved.Load())
+	amount, _ := store.balance(testOrgAccount)
+	assert.Equal(t, 300.0, amount)
+}
+
+func TestOrganizationServiceImpl_ApprovalKeepsToLimits(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	orgs := newOrganizationTestService(store, nil)
+	store.spendLimit = 100
+
+	ot, err := orgs.RequestTransaction(ctx, 1, testOrgID, "debit", nil, 200)
+	require.NoError(t, err)
+
+	tx, err := orgs.ApproveTransaction(ctx, 2, testOrgID, ot.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, "failed", tx.Status, "the limit stops it on approval")
+	amount, _ := store.balance(testOrgAccount)
+	assert.Equal(t, 500.0, amount)
+}
+
+func TestOrganizationServiceImpl_RejectTransaction(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	orgs := newOrganizationTestService(store, nil)
+
+	ot, err := orgs.RequestTransaction(ctx, 1, testOrgID, "debit", nil, 200)
+	require.NoError(t, err)
+
+	tx, err := orgs.RejectTransaction(ctx, 2, testOrgID, ot.TransactionID, "not budgeted")
+	require.NoError(t, err)
+	assert.Equal(t, "rejected", tx.Status)
+	assert.Equal(t, "rejected", store.committed()[0].Status)
+
+	_, err = orgs.ApproveTransaction(ctx, 1, testOrgID, ot.TransactionID)
+	assert.Error(t, err, "a rejected transaction is never approved")
+	amount, _ := store.balance(testOrgAccount)
+	assert.Equal(t, 500.0, amount)
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// PaymentLinkServiceImpl implements domain.PaymentLinkService
+type PaymentLinkServiceImpl struct {
+	repo      domain.PaymentLinkRepository
+	txService domain.TransactionService
+	cache     domain.CacheInvalidator
+	secret    []byte // HMAC key for link tokens
+}
+
+// NewPaymentLinkService creates a new PaymentLinkServiceImpl.
+func NewPaymentLinkService(repo domain.PaymentLinkRepository, txService domain.TransactionService, cache domain.CacheInvalidator, secret string) *PaymentLinkServiceImpl {
+	return &PaymentLinkServiceImpl{
+		repo:      repo,
+		txService: txService,
+		cache:     cache,
+		secret:    []byte(secret),
+	}
+}
+
+// CreateLink creates a payment link and returns it with its signed token
+func (s *PaymentLinkServiceImpl) CreateLink(ctx context.Context, creatorID int, amount float64, description string, ttl time.Duration) (*domain.PaymentLink, string, error) {
+	link, err := domain.NewPaymentLink(creatorID, amount, description, ttl)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.repo.Create(ctx, link); err != nil {
+		return nil, "", fmt.Errorf("failed to create payment link: %w", err)
+	}
+	invalidateUsers(ctx, s.cache, &creatorID)
+	return link, s.signToken(link), nil
+}
+
+// GetLink resolves a signed token to its payment link
+func (s *PaymentLinkServiceImpl) GetLink(ctx context.Context, token string) (*domain.PaymentLink, error) {
+	id, expiresAt, ok := s.verifyToken(token)
+	if !ok {
+		return nil, domain.ErrPaymentLinkNotFound
+	}
+
+	link, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment link: %w", err)
+	}
+	if link == nil || link.ExpiresAt.Unix() != expiresAt {
+		return nil, domain.ErrPaymentLinkNotFound
+	}
+	return link, nil
+}
+
+// ListLinks retrieves the links a user has created
+func (s *PaymentLinkServiceImpl) ListLinks(ctx context.Context, creatorID int) ([]*domain.PaymentLink, error) {
+	return s.repo.ListByCreator(ctx, creatorID)
+}
+
+// Redeem pays the link's amount from payerID to the link's creator.
+func (s *PaymentLinkServiceImpl) Redeem(ctx context.Context, token string, payerID int) (*domain.PaymentLink, error) {
+	link, err := s.GetLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if link.CreatorID == payerID {
+		return nil, &domain.ValidationError{Msg: "cannot pay your own payment link"}
+	}
+	if !link.IsRedeemable(time.Now()) {
+		return nil, domain.ErrPaymentLinkUnavailable
+	}
+
+	claimed, err := s.repo.Claim(ctx, link.ID, payerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim payment link: %w", err)
+	}
+	if !claimed {
+		return nil, domain.ErrPaymentLinkUnavailable
+	}
+	defer invalidateUsers(ctx, s.cache, &link.CreatorID, &payerID)
+
+	tx, err := s.txService.Transfer(ctx, payerID, link.CreatorID, link.Amount, fmt.Sprintf("payment-link:%d", link.ID))
+	if err != nil && !errors.Is(err, domain.ErrTransactionHeld) {
+		// Reopen the link so it can still be paid
+		link.Status = "active"
+		link.RedeemedBy = nil
+		link.RedeemedAt = nil
+		if updateErr := s.repo.Update(ctx, link); updateErr != nil {
+			log.Ctx(ctx).Error().Err(updateErr).Int("payment_link_id", link.ID).Msg("Failed to reopen payment link after failed transfer")
+		}
+		return nil, fmt.Errorf("failed to pay payment link: %w", err)
+	}
+
+	now := time.Now().UTC()
+	link.Status = "redeemed"
+	link.RedeemedBy = &payerID
+	link.RedeemedAt = &now
+	link.TransactionID = &tx.ID
+	if updateErr := s.repo.Update(ctx, link); updateErr != nil {
+		log.Ctx(ctx).Error().Err(updateErr).Int("payment_link_id", link.ID).Int("transaction_id", tx.ID).Msg("Paid payment link could not record its transaction")
+		return nil, fmt.Errorf("failed to update payment link: %w", updateErr)
+	}
+	return link, err
+}
+
+// signToken builds "<payload>.<signature>" encoding the link ID and expiry.
+func (s *PaymentLinkServiceImpl) signToken(link *domain.PaymentLink) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%d", link.ID, link.ExpiresAt.Unix())))
+	return payload + "." + s.sign(payload)
+}
+
+// verifyToken checks a token's signature and returns the link ID and expiry it encodes
+func (s *PaymentLinkServiceImpl) verifyToken(token string) (int, int64, bool) {
+	payload, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(s.sign(payload))) {
+		return 0, 0, false
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return 0, 0, false
+	}
+	idStr, expiresStr, found := strings.Cut(string(raw), ".")
+	if !found {
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	expiresAt, err := strconv.ParseInt(expiresStr, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return id, expiresAt, true
+}
+
+// sign returns the base64url HMAC-SHA256 of payload
+func (s *PaymentLinkServiceImpl) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// memoryPaymentLinkRepository implements domain.PaymentLinkRepository in memory
+type memoryPaymentLinkRepository struct {
+	mu     sync.Mutex
+	links  map[int]*domain.PaymentLink
+	nextID int
+}
+
+func newMemoryPaymentLinkRepository() *memoryPaymentLinkRepository {
+	return &memoryPaymentLinkRepository{links: map[int]*domain.PaymentLink{}}
+}
+
+func (r *memoryPaymentLinkRepository) Create(ctx context.Context, link *domain.PaymentLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	link.ID = r.nextID
+	copied := *link
+	r.links[link.ID] = &copied
+	return nil
+}
+
+func (r *memoryPaymentLinkRepository) GetByID(ctx context.Context, id int) (*domain.PaymentLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	link, ok := r.links[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *link
+	return &copied, nil
+}
+
+func (r *memoryPaymentLinkRepository) ListByCreator(ctx context.Context, creatorID int) ([]*domain.PaymentLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*domain.PaymentLink
+	for _, link := range r.links {
+		if link.CreatorID == creatorID {
+			copied := *link
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryPaymentLinkRepository) Claim(ctx context.Context, id, payerID int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	link, ok := r.links[id]
+	if !ok || !link.IsRedeemable(time.Now()) {
+		return false, nil
+	}
+	link.Status = "redeemed"
+	link.RedeemedBy = &payerID
+	return true, nil
+}
+
+func (r *memoryPaymentLinkRepository) Update(ctx context.Context, link *domain.PaymentLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *link
+	r.links[link.ID] = &copied
+	return nil
+}
+
+func TestPaymentLinkServiceImpl_Tokens(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryPaymentLinkRepository()
+	svc := NewPaymentLinkService(repo, nil, nil, "link-secret")
+	link, token, err := svc.CreateLink(ctx, 1, 30, "dinner", time.Hour)
+	require.NoError(t, err)
+
+	got, err := svc.GetLink(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, link.ID, got.ID)
+
+	payload, signature, _ := strings.Cut(token, ".")
+	other, _, err := svc.CreateLink(ctx, 1, 5000, "", time.Hour)
+	require.NoError(t, err)
+	forgedPayload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%d", other.ID, other.ExpiresAt.Unix())))
+	extended := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%d", link.ID, link.ExpiresAt.Add(24*time.Hour).Unix())))
+	_, foreignToken, err := NewPaymentLinkService(repo, nil, nil, "another-secret").CreateLink(ctx, 1, 30, "", time.Hour)
+	require.NoError(t, err)
+
+	tampered := map[string]string{
+		"another link's ID":          forgedPayload + "." + signature,
+		"a later expiry":             extended + "." + signature,
+		"an altered signature":       payload + "." + flipLast(signature),
+		"no signature":               payload,
+		"signed with another key":    foreignToken,
+		"a signature and no payload": "." + signature,
+	}
+	for name, token := range tampered {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.GetLink(ctx, token)
+			assert.ErrorIs(t, err, domain.ErrPaymentLinkNotFound)
+			_, err = svc.Redeem(ctx, token, 2)
+			assert.ErrorIs(t, err, domain.ErrPaymentLinkNotFound)
+		})
+	}
+}
+
+func TestPaymentLinkServiceImpl_Redeem(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pays the creator once", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(2, 100, 0)
+		created := &recordingInvalidator{}
+		svc := NewPaymentLinkService(newMemoryPaymentLinkRepository(), newMemoryTransactionService(store), created, "link-secret")
+		_, token, err := svc.CreateLink(ctx, 1, 30, "dinner", time.Hour)
+		require.NoError(t, err)
+		assert.True(t, created.invalidated(1), "the creator lists the new link")
+
+		invalidator := &recordingInvalidator{}
+		svc.cache = invalidator
+
+		link, err := svc.Redeem(ctx, token, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "redeemed", link.Status)
+		require.NotNil(t, link.TransactionID)
+		assert.True(t, invalidator.invalidated(1), "the creator's link list shows it paid")
+		assert.True(t, invalidator.invalidated(2))
+
+		_, err = svc.Redeem(ctx, token, 2)
+		assert.ErrorIs(t, err, domain.ErrPaymentLinkUnavailable)
+		payer, _ := store.balance(2)
+		creator, _ := store.balance(1)
+		assert.Equal(t, 70.0, payer)
+		assert.Equal(t, 30.0, creator)
+	})
+
+	t.Run("refuses an expired link", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(2, 100, 0)
+		repo := newMemoryPaymentLinkRepository()
+		svc := NewPaymentLinkService(repo, newMemoryTransactionService(store), nil, "link-secret")
+		link, _, err := svc.CreateLink(ctx, 1, 30, "", time.Hour)
+		require.NoError(t, err)
+		// A genuine token for a link whose time has run out
+		link.ExpiresAt = time.Unix(time.Now().Add(-time.Minute).Unix(), 0)
+		require.NoError(t, repo.Update(ctx, link))
+		token := svc.signToken(link)
+
+		_, err = svc.Redeem(ctx, token, 2)
+		assert.ErrorIs(t, err, domain.ErrPaymentLinkUnavailable)
+		assert.Empty(t, store.committed(), "no money moves for an expired link")
+	})
+
+	t.Run("reopens the link when the transfer fails", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(2, 10, 0)
+		repo := newMemoryPaymentLinkRepository()
+		svc := NewPaymentLinkService(repo, newMemoryTransactionService(store), nil, "link-secret")
+		link, token, err := svc.CreateLink(ctx, 1, 30, "", time.Hour)
+		require.NoError(t, err)
+
+		_, err = svc.Redeem(ctx, token, 2)
+		assert.Error(t, err)
+		reopened, _ := repo.GetByID(ctx, link.ID)
+		assert.Equal(t, "active", reopened.Status)
+		assert.Nil(t, reopened.RedeemedBy)
+	})
+
+	t.Run("keeps the link claimed while the transfer awaits approval", func(t *testing.T) {
+		store := newMemoryStore()
+		store.setBalance(2, 1000, 0)
+		txService, _ := newApprovalTestServices(store)
+		repo := newMemoryPaymentLinkRepository()
+		svc := NewPaymentLinkService(repo, txService, nil, "link-secret")
+		link, token, err := svc.CreateLink(ctx, 1, 150, "", time.Hour)
+		require.NoError(t, err)
+
+		redeemed, err := svc.Redeem(ctx, token, 2)
+		assert.ErrorIs(t, err, domain.ErrTransactionHeld)
+		require.NotNil(t, redeemed)
+		stored, _ := repo.GetByID(ctx, link.ID)
+		assert.Equal(t, "redeemed", stored.Status)
+		require.NotNil(t, stored.TransactionID)
+		assert.NotNil(t, store.approvalFor(*stored.TransactionID))
+		payer, _ := store.balance(2)
+		assert.Equal(t, 1000.0, payer, "no money moves while held")
+
+		_, err = svc.Redeem(ctx, token, 3)
+		assert.ErrorIs(t, err, domain.ErrPaymentLinkUnavailable, "nobody else can pay it meanwhile")
+	})
+}
+
+// flipLast returns s with its last character changed
+func flipLast(s string) string {
+	last := "A"
+	if strings.HasSuffix(s, last) {
+		last = "B"
+	}
+	return s[:len(s)-1] + last
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// receiveCodeKeyPrefix starts the idempotency key of a fixed-amount receive code payment.
+const receiveCodeKeyPrefix = "receive-code:"
+
+// receivePayload is the signed part of a receive code token.
+type receivePayload struct {
+	UserID    int    `json:"u"`
+	Cents     int64  `json:"a,omitempty"`
+	Reference string `json:"r,omitempty"`
+	Expires   int64  `json:"e"`
+}
+
+// ReceiveCodeServiceImpl implements domain.ReceiveCodeService
+type ReceiveCodeServiceImpl struct {
+	users  domain.UserRepository
+	secret []byte // HMAC key for receive code tokens
+	now    func() time.Time
+}
+
+// NewReceiveCodeService creates a new ReceiveCodeServiceImpl signing tokens with secret.
+func NewReceiveCodeService(users domain.UserRepository, secret string) *ReceiveCodeServiceImpl {
+	return &ReceiveCodeServiceImpl{
+		users:  users,
+		secret: []byte(secret),
+		now:    time.Now,
+	}
+}
+
+// Issue signs a receive code for its user, setting its expiry ttl from now.
+func (s *ReceiveCodeServiceImpl) Issue(ctx context.Context, code *domain.ReceiveCode, ttl time.Duration) (string, error) {
+	code.Reference = strings.TrimSpace(code.Reference)
+	code.Amount = math.Round(code.Amount*100) / 100
+	if err := code.Validate(); err != nil {
+		return "", err
+	}
+	if ttl <= 0 || ttl > domain.MaxReceiveCodeTTL {
+		return "", &domain.ValidationError{Msg: "expiry must be between 1 second and 30 days"}
+	}
+	if _, err := s.recipient(ctx, code.UserID); err != nil {
+		return "", err
+	}
+	// Whole seconds so the expiry survives the round trip through the token
+	code.ExpiresAt = s.now().UTC().Add(ttl).Truncate(time.Second)
+
+	raw, err := json.Marshal(receivePayload{
+		UserID:    code.UserID,
+		Cents:     int64(math.Round(code.Amount * 100)),
+		Reference: code.Reference,
+		Expires:   code.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode receive code: %w", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	return payload + "." + s.sign(payload), nil
+}
+
+// Redeem verifies a receive code token and pre-fills the transfer payerID makes to its user.
+func (s *ReceiveCodeServiceImpl) Redeem(ctx context.Context, token string, payerID int) (*domain.TransferDraft, error) {
+	payload, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(s.sign(payload))) {
+		return nil, domain.ErrReceiveCodeInvalid
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, domain.ErrReceiveCodeInvalid
+	}
+	var p receivePayload
+	if err := json.Unmarshal(raw, &p); err != nil || p.UserID <= 0 || p.Cents < 0 {
+		return nil, domain.ErrReceiveCodeInvalid
+	}
+
+	expiresAt := time.Unix(p.Expires, 0).UTC()
+	if !s.now().Before(expiresAt) {
+		return nil, domain.ErrReceiveCodeExpired
+	}
+	if p.UserID == payerID {
+		return nil, &domain.ValidationError{Msg: "cannot pay your own receive code"}
+	}
+	recipient, err := s.recipient(ctx, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	draft := &domain.TransferDraft{
+		FromUserID:  payerID,
+		ToUserID:    p.UserID,
+		ToUsername:  recipient.Username,
+		Amount:      float64(p.Cents) / 100,
+		AmountFixed: p.Cents > 0,
+		Reference:   p.Reference,
+		ExpiresAt:   expiresAt,
+	}
+	if draft.AmountFixed {
+		draft.IdempotencyKey = receiveCodeKeyPrefix + strconv.Itoa(payerID) + ":" + signature
+	}
+	return draft, nil
+}
+
+// recipient returns the user a receive code pays, who must still have an account
+func (s *ReceiveCodeServiceImpl) recipient(ctx context.Context, userID int) (*domain.User, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.ErasedAt != nil {
+		return nil, domain.ErrReceiveUserNotFound
+	}
+	return user, nil
+}
+
+// sign returns the base64url HMAC-SHA256 of payload
+func (s *ReceiveCodeServiceImpl) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
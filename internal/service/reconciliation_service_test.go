@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/internal/repository"
+)
+
+func TestReconciliationServiceImpl_FeeBearingTransfers(t *testing.T) {
+	ctx := context.Background()
+	pool := getTestPool(t)
+	balRepo := repository.NewBalancePostgresRepository(pool)
+	reconRepo := repository.NewReconciliationPostgresRepository(pool)
+	transactions := NewTransactionService(repository.NewTransactionPostgresRepository(pool), repository.NewPostgresUnitOfWork(pool), nil, nil, nil, flatFees(2), nil, TransactionReview{})
+	defer func() {
+		pool.Exec(context.Background(), "DELETE FROM reconciliation_issues WHERE user_id IN (8891,8892)")
+		pool.Exec(context.Background(), "DELETE FROM balance_snapshots WHERE user_id IN (8891,8892)")
+		pool.Exec(context.Background(), "DELETE FROM transactions WHERE from_user_id IN (8891,8892) OR to_user_id IN (8891,8892)")
+		pool.Exec(context.Background(), "DELETE FROM balances WHERE user_id IN (8891,8892)")
+		pool.Exec(context.Background(), "DELETE FROM users WHERE id IN (8891,8892)")
+		pool.Close()
+	}()
+	for _, id := range []int{8891, 8892} {
+		_, err := pool.Exec(ctx, "INSERT INTO users (id, username, email, password_hash, role, created_at, updated_at) VALUES ($1,$2,$3,'hash','user',NOW(),NOW()) ON CONFLICT (id) DO NOTHING",
+			id, fmt.Sprintf("reconuser%d", id), fmt.Sprintf("reconuser%d@example.com", id))
+		require.NoError(t, err)
+	}
+
+	_, err := transactions.Credit(ctx, 8891, 200, "")
+	require.NoError(t, err)
+	_, err = transactions.Transfer(ctx, 8891, 8892, 100, "")
+	require.NoError(t, err)
+
+	// The ledger charges the payer the fee just as moveFunds did
+	payer, err := balRepo.GetCurrentBalance(ctx, 8891)
+	require.NoError(t, err)
+	assert.Equal(t, 96.0, payer.Amount)
+	payee, err := balRepo.GetCurrentBalance(ctx, 8892)
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, payee.Amount)
+
+	run, err := NewReconciliationService(reconRepo, balRepo, 0).Run(ctx)
+	require.NoError(t, err)
+	var flagged int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM reconciliation_issues WHERE run_id = $1 AND user_id IN (8891,8892)", run.ID).Scan(&flagged))
+	assert.Zero(t, flagged, "fees must not show up as discrepancies")
+}
+
+// memoryReconciliations implements domain.ReconciliationRepository in memory,
+// snapshotting the balances of its ledger
+type memoryReconciliations struct {
+	ledger *memoryLedger
+	runs   []*domain.ReconciliationRun
+	issues []*domain.ReconciliationIssue
+}
+
+func (r *memoryReconciliations) CreateRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	run.ID = len(r.runs) + 1
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+func (r *memoryReconciliations) UpdateRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	return nil
+}
+
+func (r *memoryReconciliations) GetLatestRun(ctx context.Context) (*domain.ReconciliationRun, error) {
+	if len(r.runs) == 0 {
+		return nil, nil
+	}
+	return r.runs[len(r.runs)-1], nil
+}
+
+func (r *memoryReconciliations) SnapshotBalances(ctx context.Context, runID int) ([]*domain.BalanceSnapshot, error) {
+	var snapshots []*domain.BalanceSnapshot
+	for _, id := range r.ledger.userIDs() {
+		b := r.ledger.stored[id]
+		snapshots = append(snapshots, &domain.BalanceSnapshot{RunID: runID, UserID: id, Amount: b.Amount, Version: b.Version})
+	}
+	return snapshots, nil
+}
+
+func (r *memoryReconciliations) CreateIssue(ctx context.Context, issue *domain.ReconciliationIssue) error {
+	issue.ID = len(r.issues) + 1
+	r.issues = append(r.issues, issue)
+	return nil
+}
+
+func (r *memoryReconciliations) ListIssuesByRun(ctx context.Context, runID, limit, offset int) ([]*domain.ReconciliationIssue, error) {
+	var issues []*domain.ReconciliationIssue
+	for _, issue := range r.issues {
+		if issue.RunID == runID {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// memoryLedger implements the parts of domain.BalanceRepository reconciliation
+// uses: stored balances, and the amounts their transactions add up to
+type memoryLedger struct {
+	domain.BalanceRepository
+	stored     map[int]*domain.Balance
+	computed   map[int]float64
+	err        error            // returned by GetCurrentBalance, if set
+	recomputed func(userID int) // called after a balance is recomputed, if set
+}
+
+func (l *memoryLedger) userIDs() []int {
+	ids := make([]int, 0, len(l.stored))
+	for id := range l.stored {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (l *memoryLedger) GetByUserID(ctx context.Context, userID int) (*domain.Balance, error) {
+	b := l.stored[userID]
+	return &domain.Balance{UserID: b.UserID, Amount: b.Amount, Version: b.Version}, nil
+}
+
+func (l *memoryLedger) GetCurrentBalance(ctx context.Context, userID int) (*domain.Balance, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	if l.recomputed != nil {
+		l.recomputed(userID)
+	}
+	return &domain.Balance{UserID: userID, Amount: l.computed[userID]}, nil
+}
+
+// newReconciliationTestService returns a ReconciliationServiceImpl over a
+// ledger where users 1 to 3 have stored balances of 100, 50 and 20 at version 1
+func newReconciliationTestService() (*ReconciliationServiceImpl, *memoryReconciliations, *memoryLedger) {
+	ledger := &memoryLedger{
+		stored: map[int]*domain.Balance{
+			1: {UserID: 1, Amount: 100, Version: 1},
+			2: {UserID: 2, Amount: 50, Version: 1},
+			3: {UserID: 3, Amount: 20, Version: 1},
+		},
+		computed: map[int]float64{1: 100, 2: 50, 3: 20},
+	}
+	recon := &memoryReconciliations{ledger: ledger}
+	return NewReconciliationService(recon, ledger, 2), recon, ledger
+}
+
+func TestReconciliationServiceImpl_Run(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("agreeing balances", func(t *testing.T) {
+		svc, recon, ledger := newReconciliationTestService()
+		ledger.computed[2] = 50.004 // within the tolerance
+
+		run, err := svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "completed", run.Status)
+		assert.Equal(t, 3, run.BalancesChecked)
+		assert.Zero(t, run.IssuesFound)
+		assert.NotNil(t, run.FinishedAt)
+		assert.Empty(t, recon.issues)
+	})
+
+	t.Run("records the balances that disagree", func(t *testing.T) {
+		svc, recon, ledger := newReconciliationTestService()
+		ledger.computed[1] = 90
+		ledger.computed[3] = 20.01
+
+		run, err := svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, run.IssuesFound)
+		require.Len(t, recon.issues, 2)
+		assert.Equal(t, 1, recon.issues[0].UserID)
+		assert.Equal(t, 100.0, recon.issues[0].StoredAmount)
+		assert.Equal(t, 90.0, recon.issues[0].ComputedAmount)
+		assert.Equal(t, 10.0, recon.issues[0].Difference)
+		assert.Equal(t, -0.01, recon.issues[1].Difference)
+		assert.Equal(t, run.ID, recon.issues[1].RunID)
+	})
+
+	t.Run("skips a balance that changed after the snapshot", func(t *testing.T) {
+		svc, recon, ledger := newReconciliationTestService()
+		// A transfer of 30 commits between the snapshot and the recomputation
+		ledger.recomputed = func(userID int) {
+			if userID == 2 {
+				ledger.stored[2] = &domain.Balance{UserID: 2, Amount: 80, Version: 2}
+			}
+		}
+		ledger.computed[2] = 80
+
+		run, err := svc.Run(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, run.IssuesFound)
+		assert.Empty(t, recon.issues)
+	})
+
+	t.Run("fails the run when a balance can't be recomputed", func(t *testing.T) {
+		svc, recon, ledger := newReconciliationTestService()
+		ledger.err = errors.New("connection reset")
+
+		run, err := svc.Run(ctx)
+		assert.ErrorContains(t, err, "connection reset")
+		assert.Equal(t, "failed", run.Status)
+		assert.Contains(t, run.Error, "failed to recompute balance of user 1")
+		assert.Empty(t, recon.issues)
+	})
+}
+
+func TestReconciliationServiceImpl_GetReport(t *testing.T) {
+	ctx := context.Background()
+	svc, _, ledger := newReconciliationTestService()
+
+	report, err := svc.GetReport(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Nil(t, report.LastRun)
+	assert.NotNil(t, report.Issues, "no issues are reported as an empty list")
+
+	ledger.computed[2] = 40
+	run, err := svc.Run(ctx)
+	require.NoError(t, err)
+	report, err = svc.GetReport(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, run.ID, report.LastRun.ID)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, 2, report.Issues[0].UserID)
+
+	ledger.computed[2] = 50
+	_, err = svc.Run(ctx)
+	require.NoError(t, err)
+	report, err = svc.GetReport(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues, "only the latest run's issues are reported")
+}
+
+func TestNextReconciliationAt(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{name: "later today", now: time.Date(2026, 3, 4, 1, 30, 0, 0, time.UTC), want: time.Date(2026, 3, 4, 2, 0, 0, 0, time.UTC)},
+		{name: "exactly at the hour runs tomorrow", now: time.Date(2026, 3, 4, 2, 0, 0, 0, time.UTC), want: time.Date(2026, 3, 5, 2, 0, 0, 0, time.UTC)},
+		{name: "past the hour", now: time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC), want: time.Date(2026, 3, 5, 2, 0, 0, 0, time.UTC)},
+		{name: "local time is read as UTC", now: time.Date(2026, 3, 4, 4, 0, 0, 0, time.FixedZone("TRT", 3*60*60)), want: time.Date(2026, 3, 4, 2, 0, 0, 0, time.UTC)},
+		{name: "across the month", now: time.Date(2026, 2, 28, 3, 0, 0, 0, time.UTC), want: time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextReconciliationAt(tt.now, 2))
+		})
+	}
+}
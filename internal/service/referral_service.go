@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// referralCodeAlphabet leaves out characters easily mistaken for one another.
+const referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// referralCodeLength is the number of characters in a referral code
+const referralCodeLength = 8
+
+// maxReferralCodeAttempts bounds how often a new code is drawn after colliding with another user's
+const maxReferralCodeAttempts = 5
+
+// ReferralServiceImpl implements domain.ReferralService.
+type ReferralServiceImpl struct {
+	repo     domain.ReferralRepository
+	uow      domain.UnitOfWork
+	cache    domain.CacheInvalidator
+	notifier domain.Notifier
+	program  domain.ReferralProgram
+	now      func() time.Time
+}
+
+// NewReferralService creates a new ReferralServiceImpl paying the rewards of program.
+func NewReferralService(repo domain.ReferralRepository, uow domain.UnitOfWork, cache domain.CacheInvalidator, notifier domain.Notifier, program domain.ReferralProgram) *ReferralServiceImpl {
+	return &ReferralServiceImpl{repo: repo, uow: uow, cache: cache, notifier: notifier, program: program, now: time.Now}
+}
+
+// Referrer returns the ID of the user whose referral code it is.
+func (s *ReferralServiceImpl) Referrer(ctx context.Context, code string) (int, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" || len(code) > referralCodeLength {
+		return 0, domain.ErrReferralCodeInvalid
+	}
+	referrerID, err := s.repo.GetReferrerByCode(ctx, code)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up referral code: %w", err)
+	}
+	if referrerID == 0 {
+		return 0, domain.ErrReferralCodeInvalid
+	}
+	return referrerID, nil
+}
+
+// Attribute records that refereeID registered with referrerID's code.
+func (s *ReferralServiceImpl) Attribute(ctx context.Context, referrerID, refereeID int) error {
+	referral := &domain.Referral{ReferrerID: referrerID, RefereeID: refereeID}
+	if s.program.Window > 0 {
+		expiresAt := s.now().Add(s.program.Window)
+		referral.ExpiresAt = &expiresAt
+	}
+	if err := s.repo.Create(ctx, referral); err != nil {
+		return fmt.Errorf("failed to create referral: %w", err)
+	}
+	metrics.ReferralsTotal.WithLabelValues(domain.ReferralPending).Inc()
+	return nil
+}
+
+// Summary returns a user's referral code and how their referrals went.
+func (s *ReferralServiceImpl) Summary(ctx context.Context, userID int) (*domain.ReferralSummary, error) {
+	code, err := s.code(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.repo.Summarize(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize referrals: %w", err)
+	}
+	summary.Code = code
+	return summary, nil
+}
+
+// List returns a user's referrals, newest first.
+func (s *ReferralServiceImpl) List(ctx context.Context, userID int) ([]*domain.Referral, error) {
+	referrals, err := s.repo.ListByReferrer(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list referrals: %w", err)
+	}
+	return referrals, nil
+}
+
+// code returns a user's referral code, drawing a new one if they have none
+func (s *ReferralServiceImpl) code(ctx context.Context, userID int) (string, error) {
+	code, err := s.repo.GetCode(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get referral code: %w", err)
+	}
+	if code != "" {
+		return code, nil
+	}
+	for attempt := 1; attempt <= maxReferralCodeAttempts; attempt++ {
+		candidate, err := newReferralCode()
+		if err != nil {
+			return "", err
+		}
+		code, err = s.repo.SetCode(ctx, userID, candidate)
+		if !errors.Is(err, domain.ErrReferralCodeTaken) {
+			return code, err
+		}
+	}
+	return "", domain.ErrReferralCodeTaken
+}
+
+// newReferralCode returns a random referral code
+func newReferralCode() (string, error) {
+	buf := make([]byte, referralCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	// 256 is a multiple of the alphabet's 32 characters, so this is unbiased
+	for i, b := range buf {
+		buf[i] = referralCodeAlphabet[int(b)%len(referralCodeAlphabet)]
+	}
+	return string(buf), nil
+}
+
+// RewardTransaction rewards the pending referral of a transaction's payer.
+func (s *ReferralServiceImpl) RewardTransaction(ctx context.Context, tx *domain.Transaction) {
+	if tx.Status != "completed" || tx.Amount < s.program.MinAmount {
+		return
+	}
+	payerID := tx.FromUserID
+	if tx.Type == "credit" {
+		payerID = tx.ToUserID
+	}
+	if payerID == nil {
+		return
+	}
+
+	referral, err := s.repo.GetPendingByReferee(ctx, *payerID, s.now())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("transaction_id", tx.ID).Msg("Failed to load referral")
+		return
+	}
+	if referral != nil {
+		s.reward(ctx, referral, tx)
+	}
+}
+
+// reward credits both rewards and marks the referral rewarded in one unit of work.
+func (s *ReferralServiceImpl) reward(ctx context.Context, referral *domain.Referral, tx *domain.Transaction) {
+	referral.TransactionID = &tx.ID
+	referral.ReferrerReward, referral.RefereeReward = s.program.ReferrerReward, s.program.RefereeReward
+	var referrerCredit, refereeCredit *domain.Transaction
+	err := retryOnBalanceConflict(func() error {
+		return s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+			var err error
+			referrerCredit, err = creditReward(ctx, repos, referral.ReferrerID, referral.ReferrerReward, "Referral reward: "+referral.RefereeUsername)
+			if err != nil {
+				return err
+			}
+			refereeCredit, err = creditReward(ctx, repos, referral.RefereeID, referral.RefereeReward, "Referral welcome reward")
+			if err != nil {
+				return err
+			}
+			referral.ReferrerTransactionID, referral.RefereeTransactionID = transactionID(referrerCredit), transactionID(refereeCredit)
+			return repos.Referrals.MarkRewarded(ctx, referral)
+		})
+	})
+	switch {
+	case errors.Is(err, domain.ErrReferralNotPending):
+		log.Ctx(ctx).Debug().Err(err).Int("referral_id", referral.ID).Msg("Referral not rewarded")
+		return
+	case err != nil:
+		log.Ctx(ctx).Error().Err(err).Int("referral_id", referral.ID).Int("transaction_id", tx.ID).Msg("Failed to reward referral")
+		return
+	}
+
+	metrics.ReferralsTotal.WithLabelValues(domain.ReferralRewarded).Inc()
+	invalidateUsers(ctx, s.cache, &referral.ReferrerID, &referral.RefereeID)
+	s.notifyRewarded(ctx, referral.ReferrerID, referral.RefereeUsername, referrerCredit)
+	s.notifyRewarded(ctx, referral.RefereeID, "", refereeCredit)
+}
+
+// creditReward credits a reward to userID, returning nil if it is zero.
+func creditReward(ctx context.Context, repos domain.UnitOfWorkRepositories, userID int, amount float64, description string) (*domain.Transaction, error) {
+	if amount <= 0 {
+		return nil, nil
+	}
+	credit := &domain.Transaction{
+		FromUserID:  nil, // system
+		ToUserID:    &userID,
+		Amount:      amount,
+		Type:        "credit",
+		Status:      "completed",
+		Description: description,
+	}
+	if err := creditBalance(ctx, repos.Balances, userID, amount); err != nil {
+		return nil, err
+	}
+	if err := repos.Transactions.Create(ctx, credit); err != nil {
+		return nil, err
+	}
+	return credit, nil
+}
+
+// transactionID returns the ID of tx, or nil if there is no tx
+func transactionID(tx *domain.Transaction) *int {
+	if tx == nil {
+		return nil
+	}
+	return &tx.ID
+}
+
+// notifyRewarded tells userID about the reward credited to them.
+func (s *ReferralServiceImpl) notifyRewarded(ctx context.Context, userID int, referee string, credit *domain.Transaction) {
+	if s.notifier == nil || credit == nil {
+		return
+	}
+	s.notifier.Notify(ctx, userID, domain.EventReferralRewarded, map[string]string{
+		"referee":        referee,
+		"amount":         formatAmount(credit.Amount),
+		"transaction_id": strconv.Itoa(credit.ID),
+	})
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// quotaRulesTTL is how long quotas are kept in memory between reloads.
+const quotaRulesTTL = time.Minute
+
+// RequestQuotaServiceImpl implements domain.RequestQuotaService.
+type RequestQuotaServiceImpl struct {
+	repo    domain.RequestQuotaRepository
+	users   domain.UserRepository
+	counter domain.QuotaCounter
+	audit   domain.AuditLogService
+	now     func() time.Time
+
+	mu       sync.Mutex
+	quotas   []*domain.RequestQuota
+	loadedAt time.Time
+}
+
+// NewRequestQuotaService creates a new RequestQuotaServiceImpl counting requests with counter.
+func NewRequestQuotaService(repo domain.RequestQuotaRepository, users domain.UserRepository, counter domain.QuotaCounter, audit domain.AuditLogService) *RequestQuotaServiceImpl {
+	return &RequestQuotaServiceImpl{repo: repo, users: users, counter: counter, audit: audit, now: time.Now}
+}
+
+// List returns every quota, role quotas first.
+func (s *RequestQuotaServiceImpl) List(ctx context.Context) ([]*domain.RequestQuota, error) {
+	return s.repo.List(ctx)
+}
+
+// Set sets the quota of a role or user for a period, replacing any it had.
+func (s *RequestQuotaServiceImpl) Set(ctx context.Context, actorID int, quota *domain.RequestQuota) error {
+	if err := quota.Validate(); err != nil {
+		return err
+	}
+	if quota.UserID != nil {
+		user, err := s.users.GetByID(ctx, *quota.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil {
+			return domain.ErrQuotaUserNotFound
+		}
+	}
+
+	quota.UpdatedBy = actorID
+	if err := s.repo.Set(ctx, quota); err != nil {
+		return err
+	}
+	s.invalidate()
+	details := fmt.Sprintf("%s period=%s limit=%d", quotaSubject(quota.Role, quota.UserID), quota.Period, quota.Limit)
+	if err := s.audit.Record(ctx, &actorID, "request_quota", quota.ID, "set_request_quota", details); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("actor_id", actorID).Msg("Failed to audit quota change")
+	}
+	return nil
+}
+
+// Remove removes the quota of a role or user for a period.
+func (s *RequestQuotaServiceImpl) Remove(ctx context.Context, actorID int, role string, userID *int, period string) error {
+	if err := s.repo.Delete(ctx, role, userID, period); err != nil {
+		return err
+	}
+	s.invalidate()
+	details := fmt.Sprintf("%s period=%s", quotaSubject(role, userID), period)
+	if err := s.audit.Record(ctx, &actorID, "request_quota", 0, "remove_request_quota", details); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("actor_id", actorID).Msg("Failed to audit quota change")
+	}
+	return nil
+}
+
+// Usage returns a user's use of their quotas in the current periods.
+func (s *RequestQuotaServiceImpl) Usage(ctx context.Context, userID int) ([]domain.QuotaUsage, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, domain.ErrQuotaUserNotFound
+	}
+	return s.use(ctx, userID, user.Role, false)
+}
+
+// Consume counts a request of a user against each of their quotas.
+func (s *RequestQuotaServiceImpl) Consume(ctx context.Context, userID int, role string) ([]domain.QuotaUsage, error) {
+	return s.use(ctx, userID, role, true)
+}
+
+// use returns a user's use of their quotas, first counting a request if count
+func (s *RequestQuotaServiceImpl) use(ctx context.Context, userID int, role string, count bool) ([]domain.QuotaUsage, error) {
+	quotas, err := s.effective(ctx, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	usage := make([]domain.QuotaUsage, 0, len(quotas))
+	for _, quota := range quotas {
+		start, end := domain.QuotaPeriodBounds(quota.Period, now)
+		var used int64
+		if count {
+			used, err = s.counter.Increment(ctx, userID, quota.Period, start, end)
+		} else {
+			used, err = s.counter.Count(ctx, userID, quota.Period, start)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to count requests: %w", err)
+		}
+		usage = append(usage, domain.QuotaUsage{
+			Period:    quota.Period,
+			Limit:     quota.Limit,
+			Used:      used,
+			Remaining: max(quota.Limit-used, 0),
+			ResetAt:   end,
+		})
+	}
+	return usage, nil
+}
+
+// effective returns the quotas applying to a user.
+func (s *RequestQuotaServiceImpl) effective(ctx context.Context, userID int, role string) ([]*domain.RequestQuota, error) {
+	quotas, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byPeriod := make(map[string]*domain.RequestQuota)
+	for _, quota := range quotas {
+		switch {
+		case quota.UserID != nil && *quota.UserID == userID:
+			byPeriod[quota.Period] = quota
+		case quota.Role == role && role != "":
+			if _, ok := byPeriod[quota.Period]; !ok {
+				byPeriod[quota.Period] = quota
+			}
+		}
+	}
+	var effective []*domain.RequestQuota
+	for _, period := range []string{domain.QuotaPeriodDay, domain.QuotaPeriodMonth} {
+		if quota, ok := byPeriod[period]; ok {
+			effective = append(effective, quota)
+		}
+	}
+	return effective, nil
+}
+
+// load returns every quota, reloading them when they are older than quotaRulesTTL
+func (s *RequestQuotaServiceImpl) load(ctx context.Context) ([]*domain.RequestQuota, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.quotas != nil && s.now().Sub(s.loadedAt) < quotaRulesTTL {
+		return s.quotas, nil
+	}
+	quotas, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quotas: %w", err)
+	}
+	if quotas == nil {
+		quotas = []*domain.RequestQuota{}
+	}
+	s.quotas, s.loadedAt = quotas, s.now()
+	return quotas, nil
+}
+
+// invalidate makes the next request reload the quotas
+func (s *RequestQuotaServiceImpl) invalidate() {
+	s.mu.Lock()
+	s.quotas = nil
+	s.mu.Unlock()
+}
+
+// quotaSubject describes whom a quota applies to, for audit details
+func quotaSubject(role string, userID *int) string {
+	if userID != nil {
+		return "user_id=" + strconv.Itoa(*userID)
+	}
+	return "role=" + role
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// maxProjectedRuns bounds how many scheduled occurrences are simulated when projecting completion.
+const maxProjectedRuns = 10000
+
+// maxProjectionHorizon caps rate-based projections; anything further out is reported as unknown.
+const maxProjectionHorizon = 100 * 365 * 24 * time.Hour
+
+// SavingsGoalServiceImpl implements domain.SavingsGoalService
+type SavingsGoalServiceImpl struct {
+	goalRepo           domain.SavingsGoalRepository
+	scheduledService   domain.ScheduledTransactionService
+	transactionService domain.TransactionService
+}
+
+// NewSavingsGoalService creates a new SavingsGoalServiceImpl
+func NewSavingsGoalService(
+	goalRepo domain.SavingsGoalRepository,
+	scheduledService domain.ScheduledTransactionService,
+	transactionService domain.TransactionService,
+) *SavingsGoalServiceImpl {
+	return &SavingsGoalServiceImpl{
+		goalRepo:           goalRepo,
+		scheduledService:   scheduledService,
+		transactionService: transactionService,
+	}
+}
+
+// CreateGoal creates a new savings goal
+func (s *SavingsGoalServiceImpl) CreateGoal(ctx context.Context, goal *domain.SavingsGoal) error {
+	if goal.Status == "" {
+		goal.Status = "active"
+	}
+	goal.CurrentAmount = 0
+	goal.ScheduledTransactionID = nil
+
+	if err := goal.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.goalRepo.Create(ctx, goal); err != nil {
+		return fmt.Errorf("failed to create savings goal: %w", err)
+	}
+
+	log.Ctx(ctx).Info().
+		Int("id", goal.ID).
+		Int("user_id", goal.UserID).
+		Float64("target_amount", goal.TargetAmount).
+		Msg("Savings goal created")
+
+	return nil
+}
+
+// GetGoal retrieves a savings goal by ID
+func (s *SavingsGoalServiceImpl) GetGoal(ctx context.Context, id int) (*domain.SavingsGoal, error) {
+	goal, err := s.goalRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get savings goal: %w", err)
+	}
+	return goal, nil
+}
+
+// ListUserGoals retrieves all savings goals for a user
+func (s *SavingsGoalServiceImpl) ListUserGoals(ctx context.Context, userID int) ([]*domain.SavingsGoal, error) {
+	goals, err := s.goalRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list savings goals: %w", err)
+	}
+	return goals, nil
+}
+
+// CancelGoal cancels a savings goal and its recurring contribution, if any
+func (s *SavingsGoalServiceImpl) CancelGoal(ctx context.Context, id int) error {
+	goal, err := s.getActiveGoal(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if goal.ScheduledTransactionID != nil {
+		if err := s.scheduledService.CancelScheduledTransaction(ctx, *goal.ScheduledTransactionID); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Int("goal_id", goal.ID).Msg("Failed to cancel savings goal contribution schedule")
+		}
+	}
+
+	goal.Status = "cancelled"
+	if err := s.goalRepo.Update(ctx, goal); err != nil {
+		return fmt.Errorf("failed to cancel savings goal: %w", err)
+	}
+
+	log.Ctx(ctx).Info().Int("id", goal.ID).Msg("Savings goal cancelled")
+	return nil
+}
+
+// Contribute moves amount from the user's balance into the goal
+func (s *SavingsGoalServiceImpl) Contribute(ctx context.Context, goalID int, amount float64) (*domain.SavingsGoal, error) {
+	if amount <= 0 {
+		return nil, &domain.ValidationError{Msg: "amount must be positive"}
+	}
+
+	goal, err := s.getActiveGoal(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.transactionService.Debit(ctx, goal.UserID, amount, ""); err != nil {
+		return nil, fmt.Errorf("failed to debit contribution: %w", err)
+	}
+
+	updated, err := s.goalRepo.AddContribution(ctx, goal.ID, amount)
+	if err != nil {
+		// The debit has already happened; surface loudly so it can be reconciled.
+		log.Ctx(ctx).Error().Err(err).Int("goal_id", goal.ID).Float64("amount", amount).Msg("Debited contribution could not be recorded on savings goal")
+		return nil, fmt.Errorf("failed to record contribution: %w", err)
+	}
+
+	return updated, nil
+}
+
+// SetupRecurringContribution links a recurring scheduled debit that funds the goal
+func (s *SavingsGoalServiceImpl) SetupRecurringContribution(ctx context.Context, goalID int, amount float64, recurrence string, startAt time.Time) (*domain.ScheduledTransaction, error) {
+	goal, err := s.getActiveGoal(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &domain.ScheduledTransaction{
+		UserID:      goal.UserID,
+		Amount:      amount,
+		Type:        "debit",
+		Status:      "pending",
+		ScheduleAt:  startAt,
+		Recurring:   true,
+		Recurrence:  recurrence,
+		Description: fmt.Sprintf("Savings goal contribution: %s", goal.Name),
+		GoalID:      &goal.ID,
+	}
+	if err := s.scheduledService.CreateScheduledTransaction(ctx, st); err != nil {
+		return nil, err
+	}
+
+	// Replace any previous contribution schedule so the goal is funded only once per period.
+	if goal.ScheduledTransactionID != nil {
+		if err := s.scheduledService.CancelScheduledTransaction(ctx, *goal.ScheduledTransactionID); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Int("goal_id", goal.ID).Msg("Failed to cancel previous contribution schedule")
+		}
+	}
+
+	goal.ScheduledTransactionID = &st.ID
+	if err := s.goalRepo.Update(ctx, goal); err != nil {
+		return nil, fmt.Errorf("failed to link contribution schedule: %w", err)
+	}
+
+	return st, nil
+}
+
+// GetProgress returns the goal's progress and projected completion date
+func (s *SavingsGoalServiceImpl) GetProgress(ctx context.Context, goalID int) (*domain.SavingsGoalProgress, error) {
+	goal, err := s.goalRepo.GetByID(ctx, goalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get savings goal: %w", err)
+	}
+	if goal == nil {
+		return nil, fmt.Errorf("savings goal not found")
+	}
+
+	var schedule *domain.ScheduledTransaction
+	if goal.ScheduledTransactionID != nil {
+		schedule, err = s.scheduledService.GetScheduledTransaction(ctx, *goal.ScheduledTransactionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	progress := &domain.SavingsGoalProgress{
+		GoalID:              goal.ID,
+		TargetAmount:        goal.TargetAmount,
+		CurrentAmount:       goal.CurrentAmount,
+		RemainingAmount:     goal.RemainingAmount(),
+		PercentComplete:     goal.PercentComplete(),
+		Deadline:            goal.Deadline,
+		ProjectedCompletion: projectGoalCompletion(goal, schedule, time.Now().UTC()),
+	}
+	progress.OnTrack = goal.Deadline == nil ||
+		(progress.ProjectedCompletion != nil && !progress.ProjectedCompletion.After(*goal.Deadline))
+
+	return progress, nil
+}
+
+// getActiveGoal loads a goal and ensures it can still receive contributions.
+func (s *SavingsGoalServiceImpl) getActiveGoal(ctx context.Context, id int) (*domain.SavingsGoal, error) {
+	goal, err := s.goalRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get savings goal: %w", err)
+	}
+	if goal == nil {
+		return nil, fmt.Errorf("savings goal not found")
+	}
+	if goal.Status != "active" {
+		return nil, fmt.Errorf("cannot modify %s savings goal", goal.Status)
+	}
+	return goal, nil
+}
+
+// projectGoalCompletion estimates when the goal will be reached.
+func projectGoalCompletion(goal *domain.SavingsGoal, schedule *domain.ScheduledTransaction, now time.Time) *time.Time {
+	if goal.IsReached() {
+		completedAt := goal.UpdatedAt
+		return &completedAt
+	}
+	if goal.Status != "active" {
+		return nil
+	}
+
+	remaining := goal.RemainingAmount()
+
+	if schedule != nil && schedule.Status == "pending" && schedule.Recurring && schedule.Amount > 0 {
+		sim := *schedule
+		for i := 0; i < maxProjectedRuns && !sim.ShouldStop(); i++ {
+			next := sim.ScheduleAt
+			if sim.NextRunAt != nil {
+				next = *sim.NextRunAt
+			}
+			remaining -= sim.Amount
+			if remaining <= 0 {
+				return &next
+			}
+			sim.RunsCount++
+			sim.NextRunAt = sim.CalculateNextRun()
+		}
+		remaining = goal.RemainingAmount()
+	}
+
+	elapsed := now.Sub(goal.CreatedAt)
+	if goal.CurrentAmount <= 0 || elapsed <= 0 {
+		return nil
+	}
+	ratePerSecond := goal.CurrentAmount / elapsed.Seconds()
+	secondsLeft := math.Ceil(remaining / ratePerSecond)
+	if secondsLeft > maxProjectionHorizon.Seconds() {
+		return nil // too far out to be a meaningful projection
+	}
+	projected := now.Add(time.Duration(secondsLeft) * time.Second)
+	return &projected
+}
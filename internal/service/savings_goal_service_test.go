@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// memorySavingsGoals implements domain.SavingsGoalRepository in memory
+type memorySavingsGoals struct {
+	mu     sync.Mutex
+	goals  map[int]*domain.SavingsGoal
+	nextID int
+}
+
+func (r *memorySavingsGoals) Create(ctx context.Context, goal *domain.SavingsGoal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	goal.ID = r.nextID
+	goal.CreatedAt, goal.UpdatedAt = time.Now().UTC(), time.Now().UTC()
+	stored := *goal
+	r.goals[goal.ID] = &stored
+	return nil
+}
+
+func (r *memorySavingsGoals) GetByID(ctx context.Context, id int) (*domain.SavingsGoal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.goals[id]
+	if !ok {
+		return nil, nil
+	}
+	goal := *stored
+	return &goal, nil
+}
+
+func (r *memorySavingsGoals) ListByUser(ctx context.Context, userID int) ([]*domain.SavingsGoal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var goals []*domain.SavingsGoal
+	for _, stored := range r.goals {
+		if stored.UserID == userID {
+			goal := *stored
+			goals = append(goals, &goal)
+		}
+	}
+	return goals, nil
+}
+
+func (r *memorySavingsGoals) Update(ctx context.Context, goal *domain.SavingsGoal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *goal
+	r.goals[goal.ID] = &stored
+	return nil
+}
+
+func (r *memorySavingsGoals) AddContribution(ctx context.Context, goalID int, amount float64) (*domain.SavingsGoal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.goals[goalID]
+	stored.CurrentAmount += amount
+	if stored.IsReached() {
+		stored.Status = "completed"
+	}
+	goal := *stored
+	return &goal, nil
+}
+
+// memorySchedules implements the parts of domain.ScheduledTransactionService
+// savings goals use
+type memorySchedules struct {
+	domain.ScheduledTransactionService
+	schedules map[int]*domain.ScheduledTransaction
+}
+
+func (s *memorySchedules) CreateScheduledTransaction(ctx context.Context, st *domain.ScheduledTransaction) error {
+	if err := st.Validate(); err != nil {
+		return err
+	}
+	st.ID = len(s.schedules) + 1
+	s.schedules[st.ID] = st
+	return nil
+}
+
+func (s *memorySchedules) GetScheduledTransaction(ctx context.Context, id int) (*domain.ScheduledTransaction, error) {
+	return s.schedules[id], nil
+}
+
+func (s *memorySchedules) CancelScheduledTransaction(ctx context.Context, id int) error {
+	s.schedules[id].Status = "cancelled"
+	return nil
+}
+
+// newSavingsGoalTestService returns a SavingsGoalServiceImpl over store where
+// user 1 has a balance of 100 and goal 1 of user 1 aims for 80
+func newSavingsGoalTestService(t *testing.T, store *memoryStore) (*SavingsGoalServiceImpl, *memorySavingsGoals, *memorySchedules) {
+	store.setBalance(1, 100, 0)
+	goals := &memorySavingsGoals{goals: make(map[int]*domain.SavingsGoal)}
+	schedules := &memorySchedules{schedules: make(map[int]*domain.ScheduledTransaction)}
+	svc := NewSavingsGoalService(goals, schedules, newMemoryTransactionService(store))
+	require.NoError(t, svc.CreateGoal(context.Background(), &domain.SavingsGoal{UserID: 1, Name: "Bike", TargetAmount: 80}))
+	return svc, goals, schedules
+}
+
+func TestSavingsGoalServiceImpl_CreateGoal(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newSavingsGoalTestService(t, newMemoryStore())
+
+	scheduleID := 9
+	goal := &domain.SavingsGoal{UserID: 1, Name: "Trip", TargetAmount: 500, CurrentAmount: 200, ScheduledTransactionID: &scheduleID}
+	require.NoError(t, svc.CreateGoal(ctx, goal))
+	assert.Equal(t, "active", goal.Status)
+	assert.Zero(t, goal.CurrentAmount, "a new goal starts empty")
+	assert.Nil(t, goal.ScheduledTransactionID)
+
+	err := svc.CreateGoal(ctx, &domain.SavingsGoal{UserID: 1, Name: " ", TargetAmount: 500})
+	var verr *domain.ValidationError
+	assert.ErrorAs(t, err, &verr)
+}
+
+func TestSavingsGoalServiceImpl_Contribute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("debits the user and fills the goal", func(t *testing.T) {
+		store := newMemoryStore()
+		svc, _, _ := newSavingsGoalTestService(t, store)
+
+		goal, err := svc.Contribute(ctx, 1, 30)
+		require.NoError(t, err)
+		assert.Equal(t, 30.0, goal.CurrentAmount)
+		assert.Equal(t, "active", goal.Status)
+		amount, _ := store.balance(1)
+		assert.Equal(t, 70.0, amount)
+
+		goal, err = svc.Contribute(ctx, 1, 50)
+		require.NoError(t, err)
+		assert.Equal(t, "completed", goal.Status)
+
+		_, err = svc.Contribute(ctx, 1, 1)
+		assert.ErrorContains(t, err, "cannot modify completed savings goal")
+	})
+
+	t.Run("records nothing when the debit fails", func(t *testing.T) {
+		store := newMemoryStore()
+		svc, goals, _ := newSavingsGoalTestService(t, store)
+
+		_, err := svc.Contribute(ctx, 1, 150)
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+		goal, _ := goals.GetByID(ctx, 1)
+		assert.Zero(t, goal.CurrentAmount)
+	})
+
+	t.Run("rejects non-positive amounts and missing goals", func(t *testing.T) {
+		svc, _, _ := newSavingsGoalTestService(t, newMemoryStore())
+
+		_, err := svc.Contribute(ctx, 1, 0)
+		var verr *domain.ValidationError
+		assert.ErrorAs(t, err, &verr)
+		_, err = svc.Contribute(ctx, 2, 10)
+		assert.ErrorContains(t, err, "not found")
+	})
+}
+
+func TestSavingsGoalServiceImpl_RecurringContributions(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now().UTC().Add(time.Hour)
+
+	t.Run("a new schedule replaces the previous one", func(t *testing.T) {
+		svc, goals, schedules := newSavingsGoalTestService(t, newMemoryStore())
+
+		first, err := svc.SetupRecurringContribution(ctx, 1, 10, "weekly", start)
+		require.NoError(t, err)
+		assert.Equal(t, "debit", first.Type)
+		assert.Equal(t, 1, *first.GoalID)
+		second, err := svc.SetupRecurringContribution(ctx, 1, 20, "monthly", start)
+		require.NoError(t, err)
+
+		assert.Equal(t, "cancelled", schedules.schedules[first.ID].Status)
+		assert.Equal(t, "pending", schedules.schedules[second.ID].Status)
+		goal, _ := goals.GetByID(ctx, 1)
+		assert.Equal(t, second.ID, *goal.ScheduledTransactionID)
+	})
+
+	t.Run("an invalid schedule leaves the goal's schedule alone", func(t *testing.T) {
+		svc, goals, schedules := newSavingsGoalTestService(t, newMemoryStore())
+		first, err := svc.SetupRecurringContribution(ctx, 1, 10, "weekly", start)
+		require.NoError(t, err)
+
+		_, err = svc.SetupRecurringContribution(ctx, 1, -5, "weekly", start)
+		assert.Error(t, err)
+		assert.Equal(t, "pending", schedules.schedules[first.ID].Status)
+		goal, _ := goals.GetByID(ctx, 1)
+		assert.Equal(t, first.ID, *goal.ScheduledTransactionID)
+	})
+
+	t.Run("cancelling the goal cancels its schedule", func(t *testing.T) {
+		svc, goals, schedules := newSavingsGoalTestService(t, newMemoryStore())
+		st, err := svc.SetupRecurringContribution(ctx, 1, 10, "weekly", start)
+		require.NoError(t, err)
+
+		require.NoError(t, svc.CancelGoal(ctx, 1))
+		goal, _ := goals.GetByID(ctx, 1)
+		assert.Equal(t, "cancelled", goal.Status)
+		assert.Equal(t, "cancelled", schedules.schedules[st.ID].Status)
+		assert.ErrorContains(t, svc.CancelGoal(ctx, 1), "cannot modify cancelled savings goal")
+	})
+
+	t.Run("progress follows the schedule", func(t *testing.T) {
+		svc, goals, _ := newSavingsGoalTestService(t, newMemoryStore())
+		deadline := start.AddDate(0, 0, 30)
+		goal, _ := goals.GetByID(ctx, 1)
+		goal.Deadline = &deadline
+		require.NoError(t, goals.Update(ctx, goal))
+		_, err := svc.SetupRecurringContribution(ctx, 1, 20, "weekly", start)
+		require.NoError(t, err)
+
+		progress, err := svc.GetProgress(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 80.0, progress.RemainingAmount)
+		require.NotNil(t, progress.ProjectedCompletion)
+		assert.True(t, start.AddDate(0, 0, 21).Equal(*progress.ProjectedCompletion), "the fourth weekly run fills the goal")
+		assert.True(t, progress.OnTrack)
+	})
+}
+
+func TestScheduledTransactionServiceImpl_RecordGoalContribution(t *testing.T) {
+	ctx := context.Background()
+	_, goals, _ := newSavingsGoalTestService(t, newMemoryStore())
+	scheduled := &ScheduledTransactionServiceImpl{goalRepo: goals}
+	goalID := 1
+	st := &domain.ScheduledTransaction{ID: 3, Amount: 50, Status: "pending", Recurring: true, GoalID: &goalID}
+
+	scheduled.recordGoalContribution(ctx, st)
+	assert.Equal(t, "pending", st.Status)
+
+	scheduled.recordGoalContribution(ctx, st)
+	assert.Equal(t, "completed", st.Status, "the schedule stops once the goal is reached")
+	goal, _ := goals.GetByID(ctx, 1)
+	assert.Equal(t, 100.0, goal.CurrentAmount)
+	assert.Equal(t, "completed", goal.Status)
+}
+
+func TestProjectGoalCompletion(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	start := now.AddDate(0, 0, 1)
+	twoRuns := 2
+
+	tests := []struct {
+		name     string
+		goal     domain.SavingsGoal
+		schedule *domain.ScheduledTransaction
+		want     *time.Time
+	}{
+		{
+			name: "a reached goal completed when it was last updated",
+			goal: domain.SavingsGoal{Status: "completed", TargetAmount: 50, CurrentAmount: 50, UpdatedAt: now.AddDate(0, 0, -3)},
+			want: ptrTime(now.AddDate(0, 0, -3)),
+		},
+		{
+			name: "a cancelled goal never completes",
+			goal: domain.SavingsGoal{Status: "cancelled", TargetAmount: 50, CurrentAmount: 10, CreatedAt: now.AddDate(0, 0, -10)},
+		},
+		{
+			name:     "the run that covers the remaining amount",
+			goal:     domain.SavingsGoal{Status: "active", TargetAmount: 100, CurrentAmount: 10, CreatedAt: now},
+			schedule: &domain.ScheduledTransaction{Status: "pending", Recurring: true, Recurrence: "monthly", Amount: 30, ScheduleAt: start},
+			want:     ptrTime(start.AddDate(0, 2, 0)),
+		},
+		{
+			name:     "a schedule that resumes at its next run",
+			goal:     domain.SavingsGoal{Status: "active", TargetAmount: 100, CurrentAmount: 50, CreatedAt: now},
+			schedule: &domain.ScheduledTransaction{Status: "pending", Recurring: true, Recurrence: "daily", Amount: 25, ScheduleAt: start, NextRunAt: ptrTime(start.AddDate(0, 0, 5))},
+			want:     ptrTime(start.AddDate(0, 0, 6)),
+		},
+		{
+			name:     "a schedule that stops too early falls back to the saving rate",
+			goal:     domain.SavingsGoal{Status: "active", TargetAmount: 100, CurrentAmount: 20, CreatedAt: now.AddDate(0, 0, -4)},
+			schedule: &domain.ScheduledTransaction{Status: "pending", Recurring: true, Recurrence: "daily", Amount: 10, ScheduleAt: start, MaxRuns: &twoRuns},
+			want:     ptrTime(now.AddDate(0, 0, 16)),
+		},
+		{
+			name:     "a paused schedule is not counted on",
+			goal:     domain.SavingsGoal{Status: "active", TargetAmount: 100},
+			schedule: &domain.ScheduledTransaction{Status: "paused", Recurring: true, Recurrence: "daily", Amount: 100, ScheduleAt: start},
+		},
+		{
+			name: "the saving rate so far",
+			goal: domain.SavingsGoal{Status: "active", TargetAmount: 100, CurrentAmount: 25, CreatedAt: now.AddDate(0, 0, -10)},
+			want: ptrTime(now.AddDate(0, 0, 30)),
+		},
+		{
+			name: "nothing saved yet",
+			goal: domain.SavingsGoal{Status: "active", TargetAmount: 100, CreatedAt: now.AddDate(0, 0, -10)},
+		},
+		{
+			name: "beyond the projection horizon",
+			goal: domain.SavingsGoal{Status: "active", TargetAmount: 1e9, CurrentAmount: 1, CreatedAt: now.AddDate(0, 0, -10)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := projectGoalCompletion(&tt.goal, tt.schedule, now)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.True(t, tt.want.Equal(*got), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
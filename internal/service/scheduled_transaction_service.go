@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
@@ -12,32 +14,62 @@ import (
 
 	"github.com/melihgurlek/backend-path/internal/domain"
 	"github.com/melihgurlek/backend-path/pkg/metrics"
+	"github.com/melihgurlek/backend-path/pkg/tracing"
+)
+
+// Bounds for the number of occurrences returned by a preview
+const (
+	defaultPreviewOccurrences = 10
+	maxPreviewOccurrences     = 100
+)
+
+// The executor counts as stalled once it has not woken up for schedulerStallAfter.
+const (
+	schedulerTickInterval = time.Minute
+	schedulerStallAfter   = 3 * schedulerTickInterval
 )
 
 // ScheduledTransactionServiceImpl implements domain.ScheduledTransactionService
 type ScheduledTransactionServiceImpl struct {
 	scheduledRepo      domain.ScheduledTransactionRepository
 	transactionService domain.TransactionService
+	balanceService     domain.BalanceService
+	goalRepo           domain.SavingsGoalRepository
+	retryPolicy        domain.RetryPolicy
+	notifier           domain.Notifier
+	admins             domain.AdminNotifier
 	mu                 sync.RWMutex
 	executionTicker    *time.Ticker
 	stopChan           chan struct{}
 	isRunning          bool
+	lastTick           time.Time      // when the executor last woke up
+	loopWg             sync.WaitGroup // the running execution loop
 }
 
-// NewScheduledTransactionService creates a new ScheduledTransactionServiceImpl
+// NewScheduledTransactionService creates a new ScheduledTransactionServiceImpl.
 func NewScheduledTransactionService(
 	scheduledRepo domain.ScheduledTransactionRepository,
 	transactionService domain.TransactionService,
+	balanceService domain.BalanceService,
+	goalRepo domain.SavingsGoalRepository,
+	retryPolicy domain.RetryPolicy,
+	notifier domain.Notifier,
+	admins domain.AdminNotifier,
 ) *ScheduledTransactionServiceImpl {
 	return &ScheduledTransactionServiceImpl{
 		scheduledRepo:      scheduledRepo,
 		transactionService: transactionService,
+		balanceService:     balanceService,
+		goalRepo:           goalRepo,
+		retryPolicy:        retryPolicy,
+		notifier:           notifier,
+		admins:             admins,
 		stopChan:           make(chan struct{}),
 	}
 }
 
 // CreateScheduledTransaction creates a new scheduled transaction
-func (s *ScheduledTransactionServiceImpl) CreateScheduledTransaction(st *domain.ScheduledTransaction) error {
+func (s *ScheduledTransactionServiceImpl) CreateScheduledTransaction(ctx context.Context, st *domain.ScheduledTransaction) error {
 	// Validate the scheduled transaction
 	if err := st.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
@@ -55,11 +87,14 @@ func (s *ScheduledTransactionServiceImpl) CreateScheduledTransaction(st *domain.
 
 	// Calculate next run for recurring transactions
 	if st.Recurring {
-		st.NextRunAt = &st.ScheduleAt
+		st.NextRunAt = st.FirstRun()
 	}
 
+	// Executions run long after this request; they link back to its trace
+	st.TraceParent = tracing.TraceParent(ctx)
+
 	// Create the scheduled transaction
-	if err := s.scheduledRepo.Create(st); err != nil {
+	if err := s.scheduledRepo.Create(ctx, st); err != nil {
 		return fmt.Errorf("failed to create scheduled transaction: %w", err)
 	}
 
@@ -69,7 +104,7 @@ func (s *ScheduledTransactionServiceImpl) CreateScheduledTransaction(st *domain.
 		metrics.ScheduledTransactionCount.WithLabelValues("recurring", st.Recurrence).Inc()
 	}
 
-	log.Info().
+	log.Ctx(ctx).Info().
 		Int("id", st.ID).
 		Int("user_id", st.UserID).
 		Str("type", st.Type).
@@ -82,8 +117,8 @@ func (s *ScheduledTransactionServiceImpl) CreateScheduledTransaction(st *domain.
 }
 
 // GetScheduledTransaction retrieves a scheduled transaction by ID
-func (s *ScheduledTransactionServiceImpl) GetScheduledTransaction(id int) (*domain.ScheduledTransaction, error) {
-	st, err := s.scheduledRepo.GetByID(id)
+func (s *ScheduledTransactionServiceImpl) GetScheduledTransaction(ctx context.Context, id int) (*domain.ScheduledTransaction, error) {
+	st, err := s.scheduledRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get scheduled transaction: %w", err)
 	}
@@ -91,8 +126,8 @@ func (s *ScheduledTransactionServiceImpl) GetScheduledTransaction(id int) (*doma
 }
 
 // ListUserScheduledTransactions retrieves all scheduled transactions for a user
-func (s *ScheduledTransactionServiceImpl) ListUserScheduledTransactions(userID int) ([]*domain.ScheduledTransaction, error) {
-	transactions, err := s.scheduledRepo.ListByUser(userID)
+func (s *ScheduledTransactionServiceImpl) ListUserScheduledTransactions(ctx context.Context, userID int) ([]*domain.ScheduledTransaction, error) {
+	transactions, err := s.scheduledRepo.ListByUser(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list user scheduled transactions: %w", err)
 	}
@@ -100,14 +135,14 @@ func (s *ScheduledTransactionServiceImpl) ListUserScheduledTransactions(userID i
 }
 
 // UpdateScheduledTransaction updates a scheduled transaction
-func (s *ScheduledTransactionServiceImpl) UpdateScheduledTransaction(st *domain.ScheduledTransaction) error {
+func (s *ScheduledTransactionServiceImpl) UpdateScheduledTransaction(ctx context.Context, st *domain.ScheduledTransaction) error {
 	// Validate the scheduled transaction
 	if err := st.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
 	// Get existing transaction to check if it can be updated
-	existing, err := s.scheduledRepo.GetByID(st.ID)
+	existing, err := s.scheduledRepo.GetByID(ctx, st.ID)
 	if err != nil {
 		return fmt.Errorf("failed to get existing scheduled transaction: %w", err)
 	}
@@ -121,11 +156,11 @@ func (s *ScheduledTransactionServiceImpl) UpdateScheduledTransaction(st *domain.
 	}
 
 	// Update the scheduled transaction
-	if err := s.scheduledRepo.Update(st); err != nil {
+	if err := s.scheduledRepo.Update(ctx, st); err != nil {
 		return fmt.Errorf("failed to update scheduled transaction: %w", err)
 	}
 
-	log.Info().
+	log.Ctx(ctx).Info().
 		Int("id", st.ID).
 		Str("status", st.Status).
 		Msg("Scheduled transaction updated")
@@ -134,8 +169,8 @@ func (s *ScheduledTransactionServiceImpl) UpdateScheduledTransaction(st *domain.
 }
 
 // CancelScheduledTransaction cancels a scheduled transaction
-func (s *ScheduledTransactionServiceImpl) CancelScheduledTransaction(id int) error {
-	st, err := s.scheduledRepo.GetByID(id)
+func (s *ScheduledTransactionServiceImpl) CancelScheduledTransaction(ctx context.Context, id int) error {
+	st, err := s.scheduledRepo.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get scheduled transaction: %w", err)
 	}
@@ -150,24 +185,131 @@ func (s *ScheduledTransactionServiceImpl) CancelScheduledTransaction(id int) err
 
 	st.MarkCancelled()
 
-	if err := s.scheduledRepo.Update(st); err != nil {
+	if err := s.scheduledRepo.Update(ctx, st); err != nil {
 		return fmt.Errorf("failed to cancel scheduled transaction: %w", err)
 	}
 
 	// Record metrics
 	metrics.ScheduledTransactionCount.WithLabelValues(st.Type, "cancelled").Inc()
 
-	log.Info().
+	log.Ctx(ctx).Info().
 		Int("id", st.ID).
 		Msg("Scheduled transaction cancelled")
 
 	return nil
 }
 
+// PauseScheduledTransaction suspends a recurring scheduled transaction
+func (s *ScheduledTransactionServiceImpl) PauseScheduledTransaction(ctx context.Context, id int) (*domain.ScheduledTransaction, error) {
+	st, err := s.scheduledRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled transaction: %w", err)
+	}
+	if st == nil {
+		return nil, fmt.Errorf("scheduled transaction not found")
+	}
+
+	if err := st.Pause(); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduledRepo.Update(ctx, st); err != nil {
+		return nil, fmt.Errorf("failed to pause scheduled transaction: %w", err)
+	}
+
+	metrics.ScheduledTransactionCount.WithLabelValues(st.Type, "paused").Inc()
+
+	log.Ctx(ctx).Info().
+		Int("id", st.ID).
+		Msg("Scheduled transaction paused")
+
+	return st, nil
+}
+
+// ResumeScheduledTransaction reactivates a paused scheduled transaction
+func (s *ScheduledTransactionServiceImpl) ResumeScheduledTransaction(ctx context.Context, id int) (*domain.ScheduledTransaction, error) {
+	st, err := s.scheduledRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled transaction: %w", err)
+	}
+	if st == nil {
+		return nil, fmt.Errorf("scheduled transaction not found")
+	}
+
+	if err := st.Resume(time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduledRepo.Update(ctx, st); err != nil {
+		return nil, fmt.Errorf("failed to resume scheduled transaction: %w", err)
+	}
+
+	metrics.ScheduledTransactionCount.WithLabelValues(st.Type, "resumed").Inc()
+
+	log.Ctx(ctx).Info().
+		Int("id", st.ID).
+		Interface("next_run_at", st.NextRunAt).
+		Msg("Scheduled transaction resumed")
+
+	return st, nil
+}
+
+// PreviewScheduledTransaction projects st's next occurrences without saving it.
+func (s *ScheduledTransactionServiceImpl) PreviewScheduledTransaction(ctx context.Context, st *domain.ScheduledTransaction, occurrences int) (*domain.ScheduledTransactionPreview, error) {
+	if st.Status == "" {
+		st.Status = "pending"
+	}
+	if err := st.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if occurrences <= 0 {
+		occurrences = defaultPreviewOccurrences
+	}
+	if occurrences > maxPreviewOccurrences {
+		occurrences = maxPreviewOccurrences
+	}
+
+	preview := &domain.ScheduledTransactionPreview{
+		Occurrences: []domain.ScheduledOccurrence{},
+	}
+
+	balance, err := s.balanceService.GetCurrentBalance(ctx, st.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current balance: %w", err)
+	}
+	if balance != nil {
+		preview.CurrentBalance = balance.GetAmount()
+	}
+
+	// Ask for one extra occurrence so we can tell the caller the schedule continues.
+	runTimes := st.Occurrences(occurrences + 1)
+	if len(runTimes) > occurrences {
+		preview.Truncated = true
+		runTimes = runTimes[:occurrences]
+	}
+
+	projected := preview.CurrentBalance
+	impact := st.BalanceImpact()
+	for _, runAt := range runTimes {
+		projected += impact
+		preview.Occurrences = append(preview.Occurrences, domain.ScheduledOccurrence{
+			RunAt:             runAt,
+			BalanceImpact:     impact,
+			ProjectedBalance:  projected,
+			InsufficientFunds: projected < 0,
+		})
+	}
+	preview.TotalImpact = impact * float64(len(runTimes))
+	preview.ProjectedBalance = projected
+
+	return preview, nil
+}
+
 // ExecuteScheduledTransactions executes all pending scheduled transactions
-func (s *ScheduledTransactionServiceImpl) ExecuteScheduledTransactions() error {
+func (s *ScheduledTransactionServiceImpl) ExecuteScheduledTransactions(ctx context.Context) error {
 	// Get pending transactions
-	pending, err := s.scheduledRepo.ListPending()
+	pending, err := s.scheduledRepo.ListPending(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get pending scheduled transactions: %w", err)
 	}
@@ -176,12 +318,12 @@ func (s *ScheduledTransactionServiceImpl) ExecuteScheduledTransactions() error {
 		return nil // No pending transactions
 	}
 
-	log.Info().Int("count", len(pending)).Msg("Executing scheduled transactions")
+	log.Ctx(ctx).Info().Int("count", len(pending)).Msg("Executing scheduled transactions")
 
 	// Execute each pending transaction
 	for _, st := range pending {
-		if err := s.ExecuteSingleScheduledTransaction(st); err != nil {
-			log.Error().Err(err).Int("id", st.ID).Msg("Failed to execute scheduled transaction")
+		if err := s.ExecuteSingleScheduledTransaction(ctx, st); err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("id", st.ID).Msg("Failed to execute scheduled transaction")
 			// Continue with other transactions
 		}
 	}
@@ -190,9 +332,10 @@ func (s *ScheduledTransactionServiceImpl) ExecuteScheduledTransactions() error {
 }
 
 // ExecuteSingleScheduledTransaction executes a single scheduled transaction
-func (s *ScheduledTransactionServiceImpl) ExecuteSingleScheduledTransaction(st *domain.ScheduledTransaction) error {
-	// Create span for tracing
-	ctx, span := otel.Tracer("scheduled-transaction-service").Start(context.Background(), "execute-scheduled-transaction")
+func (s *ScheduledTransactionServiceImpl) ExecuteSingleScheduledTransaction(ctx context.Context, st *domain.ScheduledTransaction) error {
+	// Create span for tracing, linked to the request that created the transaction
+	ctx, span := otel.Tracer("scheduled-transaction-service").Start(ctx, "execute-scheduled-transaction",
+		tracing.LinkToTraceParent(st.TraceParent))
 	defer span.End()
 
 	span.SetAttributes(
@@ -205,18 +348,20 @@ func (s *ScheduledTransactionServiceImpl) ExecuteSingleScheduledTransaction(st *
 
 	startTime := time.Now()
 
-	// Execute the transaction based on type
+	// Execute the transaction based on type. The key identifies this run, so a
+	// run that moved money but wasn't marked completed isn't executed twice.
 	var err error
+	idempotencyKey := fmt.Sprintf("scheduled:%d:%d", st.ID, st.RunsCount)
 	switch st.Type {
 	case "credit":
-		err = s.transactionService.Credit(st.UserID, st.Amount)
+		_, err = s.transactionService.Credit(ctx, st.UserID, st.Amount, idempotencyKey)
 	case "debit":
-		err = s.transactionService.Debit(st.UserID, st.Amount)
+		_, err = s.transactionService.Debit(ctx, st.UserID, st.Amount, idempotencyKey)
 	case "transfer":
 		if st.ToUserID == nil {
 			err = fmt.Errorf("transfer requires to_user_id")
 		} else {
-			err = s.transactionService.Transfer(st.UserID, *st.ToUserID, st.Amount)
+			_, err = s.transactionService.Transfer(ctx, st.UserID, *st.ToUserID, st.Amount, idempotencyKey)
 		}
 	default:
 		err = fmt.Errorf("unknown transaction type: %s", st.Type)
@@ -229,17 +374,21 @@ func (s *ScheduledTransactionServiceImpl) ExecuteSingleScheduledTransaction(st *
 
 	// Update the scheduled transaction status
 	if err != nil {
-		st.MarkFailed()
 		span.RecordError(err)
 		metrics.ScheduledTransactionExecutionFailure.WithLabelValues(st.Type).Inc()
+		s.handleExecutionFailure(ctx, st, err)
 	} else {
 		st.MarkCompleted()
 		metrics.ScheduledTransactionExecutionSuccess.WithLabelValues(st.Type).Inc()
+		s.recordGoalContribution(ctx, st)
 	}
 
 	// Update the scheduled transaction in the database
-	if updateErr := s.scheduledRepo.Update(st); updateErr != nil {
-		log.Error().Err(updateErr).Int("id", st.ID).Msg("Failed to update scheduled transaction status")
+	if updateErr := s.scheduledRepo.Update(ctx, st); updateErr != nil {
+		log.Ctx(ctx).Error().Err(updateErr).Int("id", st.ID).Msg("Failed to update scheduled transaction status")
+	}
+	if err != nil && st.Status == "failed" {
+		s.notifyFailure(ctx, st, err)
 	}
 
 	// Record execution time
@@ -248,7 +397,7 @@ func (s *ScheduledTransactionServiceImpl) ExecuteSingleScheduledTransaction(st *
 
 	span.SetAttributes(attribute.Float64("execution_time_seconds", executionTime.Seconds()))
 
-	log.Info().
+	log.Ctx(ctx).Info().
 		Int("id", st.ID).
 		Str("type", st.Type).
 		Bool("success", err == nil).
@@ -258,14 +407,88 @@ func (s *ScheduledTransactionServiceImpl) ExecuteSingleScheduledTransaction(st *
 	return err
 }
 
+// handleExecutionFailure schedules a retry with backoff, or marks the transaction failed.
+func (s *ScheduledTransactionServiceImpl) handleExecutionFailure(ctx context.Context, st *domain.ScheduledTransaction, execErr error) {
+	if !s.retryPolicy.CanRetry(st.RetryCount) {
+		st.MarkFailed()
+		if s.retryPolicy.MaxAttempts > 1 {
+			metrics.ScheduledTransactionRetriesExhausted.WithLabelValues(st.Type).Inc()
+		}
+		log.Ctx(ctx).Warn().
+			Err(execErr).
+			Int("id", st.ID).
+			Int("retry_count", st.RetryCount).
+			Msg("Scheduled transaction failed permanently")
+		return
+	}
+
+	retryAt := time.Now().UTC().Add(s.retryPolicy.Backoff(st.RetryCount))
+	st.ScheduleRetry(retryAt)
+	metrics.ScheduledTransactionRetries.WithLabelValues(st.Type).Inc()
+
+	log.Ctx(ctx).Warn().
+		Err(execErr).
+		Int("id", st.ID).
+		Int("retry_count", st.RetryCount).
+		Time("next_retry_at", retryAt).
+		Msg("Scheduled transaction failed, retry scheduled")
+}
+
+// notifyFailure tells the owner of a scheduled transaction that failed for good.
+func (s *ScheduledTransactionServiceImpl) notifyFailure(ctx context.Context, st *domain.ScheduledTransaction, execErr error) {
+	data := map[string]string{
+		"scheduled_id": strconv.Itoa(st.ID),
+		"type":         st.Type,
+		"amount":       formatAmount(st.Amount),
+		"attempts":     strconv.Itoa(st.RetryCount + 1),
+		"reason":       scheduledFailureReason(execErr),
+	}
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, st.UserID, domain.EventScheduledTransactionFailed, data)
+	}
+	if s.admins != nil {
+		data["owner_id"] = strconv.Itoa(st.UserID)
+		data["reason"] = execErr.Error()
+		if err := s.admins.NotifyAdmins(ctx, domain.EventAdminScheduledTransactionFailed, data); err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("id", st.ID).Msg("Failed to notify admins of scheduled transaction failure")
+		}
+	}
+}
+
+// scheduledFailureReason describes why a scheduled transaction failed.
+func scheduledFailureReason(err error) string {
+	if kind, _ := domain.ClassifyError(err); kind != domain.ErrorKindInternal {
+		return err.Error()
+	}
+	return "the transaction could not be processed"
+}
+
+// recordGoalContribution credits an execution to the savings goal it funds.
+func (s *ScheduledTransactionServiceImpl) recordGoalContribution(ctx context.Context, st *domain.ScheduledTransaction) {
+	if st.GoalID == nil || s.goalRepo == nil {
+		return
+	}
+
+	goal, err := s.goalRepo.AddContribution(ctx, *st.GoalID, st.Amount)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("id", st.ID).Int("goal_id", *st.GoalID).Msg("Failed to record savings goal contribution")
+		return
+	}
+
+	if goal.IsReached() {
+		st.Status = "completed"
+		log.Ctx(ctx).Info().Int("goal_id", goal.ID).Msg("Savings goal reached, stopping contributions")
+	}
+}
+
 // GetScheduledTransactionStats returns statistics about scheduled transactions
-func (s *ScheduledTransactionServiceImpl) GetScheduledTransactionStats() (*domain.ScheduledTransactionStats, error) {
+func (s *ScheduledTransactionServiceImpl) GetScheduledTransactionStats(ctx context.Context) (*domain.ScheduledTransactionStats, error) {
 	stats := &domain.ScheduledTransactionStats{}
 
 	// Get counts by status
-	statuses := []string{"pending", "completed", "failed", "cancelled"}
+	statuses := []string{"pending", "paused", "completed", "failed", "cancelled"}
 	for _, status := range statuses {
-		transactions, err := s.scheduledRepo.ListByStatus(status)
+		transactions, err := s.scheduledRepo.ListByStatus(ctx, status)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get %s scheduled transactions: %w", status, err)
 		}
@@ -274,6 +497,8 @@ func (s *ScheduledTransactionServiceImpl) GetScheduledTransactionStats() (*domai
 		switch status {
 		case "pending":
 			stats.PendingCount = count
+		case "paused":
+			stats.PausedCount = count
 		case "completed":
 			stats.CompletedCount = count
 		case "failed":
@@ -285,7 +510,7 @@ func (s *ScheduledTransactionServiceImpl) GetScheduledTransactionStats() (*domai
 	}
 
 	// Get recurring vs one-time counts
-	allTransactions, err := s.scheduledRepo.ListByStatus("pending")
+	allTransactions, err := s.scheduledRepo.ListByStatus(ctx, "pending")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pending scheduled transactions: %w", err)
 	}
@@ -332,19 +557,20 @@ func (s *ScheduledTransactionServiceImpl) Start(ctx context.Context) {
 	}
 
 	s.isRunning = true
-	s.executionTicker = time.NewTicker(1 * time.Minute) // Check every minute
+	s.executionTicker = time.NewTicker(schedulerTickInterval)
+	s.lastTick = time.Now()
 
-	log.Info().Msg("Starting scheduled transaction executor")
+	log.Ctx(ctx).Info().Msg("Starting scheduled transaction executor")
 
+	s.loopWg.Add(1)
 	go s.executionLoop(ctx)
 }
 
-// Stop stops the background execution of scheduled transactions
+// Stop stops the background execution of scheduled transactions.
 func (s *ScheduledTransactionServiceImpl) Stop() {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	if !s.isRunning {
+		s.mu.Unlock()
 		return
 	}
 
@@ -353,12 +579,30 @@ func (s *ScheduledTransactionServiceImpl) Stop() {
 		s.executionTicker.Stop()
 	}
 	close(s.stopChan)
+	s.mu.Unlock()
 
+	s.loopWg.Wait()
 	log.Info().Msg("Stopped scheduled transaction executor")
 }
 
+// CheckHealth reports an error if the executor is stopped or stalled.
+func (s *ScheduledTransactionServiceImpl) CheckHealth(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.isRunning {
+		return errors.New("scheduler is not running")
+	}
+	if since := time.Since(s.lastTick); since > schedulerStallAfter {
+		return fmt.Errorf("scheduler last ran %s ago", since.Round(time.Second))
+	}
+	return nil
+}
+
 // executionLoop runs in the background to execute scheduled transactions
 func (s *ScheduledTransactionServiceImpl) executionLoop(ctx context.Context) {
+	defer s.loopWg.Done()
+	defer metrics.TrackGoroutine("scheduler")()
 	for {
 		select {
 		case <-ctx.Done():
@@ -366,8 +610,11 @@ func (s *ScheduledTransactionServiceImpl) executionLoop(ctx context.Context) {
 		case <-s.stopChan:
 			return
 		case <-s.executionTicker.C:
-			if err := s.ExecuteScheduledTransactions(); err != nil {
-				log.Error().Err(err).Msg("Failed to execute scheduled transactions")
+			s.mu.Lock()
+			s.lastTick = time.Now()
+			s.mu.Unlock()
+			if err := s.ExecuteScheduledTransactions(ctx); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("Failed to execute scheduled transactions")
 			}
 		}
 	}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// TransactionArchiveServiceImpl implements domain.TransactionArchiveService
+type TransactionArchiveServiceImpl struct {
+	archiveRepo domain.TransactionArchiveRepository
+	maxAge      time.Duration // transactions older than this are archived; 0 disables archiving
+	batchSize   int
+	interval    time.Duration
+	runMu       sync.Mutex
+	stopChan    chan struct{}
+}
+
+// NewTransactionArchiveService creates a new TransactionArchiveServiceImpl.
+func NewTransactionArchiveService(archiveRepo domain.TransactionArchiveRepository, maxAge time.Duration, batchSize int, interval time.Duration) *TransactionArchiveServiceImpl {
+	return &TransactionArchiveServiceImpl{
+		archiveRepo: archiveRepo,
+		maxAge:      maxAge,
+		batchSize:   batchSize,
+		interval:    interval,
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Archive moves final transactions older than the retention age to the archive.
+func (s *TransactionArchiveServiceImpl) Archive(ctx context.Context) (int, error) {
+	if s.maxAge <= 0 {
+		return 0, nil
+	}
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	cutoff := time.Now().UTC().Add(-s.maxAge)
+	total := 0
+	for {
+		moved, err := s.archiveRepo.ArchiveBefore(ctx, cutoff, s.batchSize)
+		total += moved
+		metrics.TransactionsArchived.Add(float64(moved))
+		if err != nil {
+			return total, fmt.Errorf("failed to archive transactions: %w", err)
+		}
+		if moved < s.batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Ctx(ctx).Info().Int("archived", total).Time("cutoff", cutoff).Msg("Archived old transactions")
+	}
+	return total, nil
+}
+
+// GetArchived retrieves an archived transaction
+func (s *TransactionArchiveServiceImpl) GetArchived(ctx context.Context, id int) (*domain.ArchivedTransaction, error) {
+	tx, err := s.archiveRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived transaction: %w", err)
+	}
+	if tx == nil {
+		return nil, domain.ErrArchivedTransactionNotFound
+	}
+	return tx, nil
+}
+
+// SearchArchived retrieves archived transactions matching filter
+func (s *TransactionArchiveServiceImpl) SearchArchived(ctx context.Context, filter domain.ArchivedTransactionFilter) ([]*domain.ArchivedTransaction, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, &domain.ValidationError{Msg: "from must be before to"}
+	}
+	transactions, err := s.archiveRepo.Search(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search archived transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+// Start archives now and then every interval; it does nothing when archiving is disabled
+func (s *TransactionArchiveServiceImpl) Start(ctx context.Context) {
+	if s.maxAge <= 0 {
+		log.Ctx(ctx).Info().Msg("Transaction archiving disabled")
+		return
+	}
+	log.Ctx(ctx).Info().Dur("max_age", s.maxAge).Dur("interval", s.interval).Msg("Starting transaction archiving")
+
+	go s.archiveLoop(ctx)
+}
+
+// Stop stops the periodic archiving
+func (s *TransactionArchiveServiceImpl) Stop() {
+	log.Info().Msg("Stopping transaction archiving")
+	close(s.stopChan)
+}
+
+func (s *TransactionArchiveServiceImpl) archiveLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Archive(ctx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Transaction archiving failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// batchArchiveRepository archives from a fixed number of waiting
+// transactions, failing once fail batches have been moved
+type batchArchiveRepository struct {
+	domain.TransactionArchiveRepository
+	waiting int
+	calls   int
+	fail    int // fail on this call; 0 never fails
+	limits  []int
+}
+
+func (r *batchArchiveRepository) ArchiveBefore(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	r.calls++
+	r.limits = append(r.limits, limit)
+	if r.calls == r.fail {
+		return 0, errors.New("connection reset")
+	}
+	moved := min(limit, r.waiting)
+	r.waiting -= moved
+	return moved, nil
+}
+
+func TestTransactionArchiveServiceImpl_Archive(t *testing.T) {
+	t.Run("moves batches until one is short", func(t *testing.T) {
+		repo := &batchArchiveRepository{waiting: 25}
+		moved, err := NewTransactionArchiveService(repo, time.Hour, 10, time.Hour).Archive(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 25, moved)
+		assert.Equal(t, []int{10, 10, 10}, repo.limits)
+	})
+
+	t.Run("returns what moved before a failure", func(t *testing.T) {
+		repo := &batchArchiveRepository{waiting: 25, fail: 2}
+		moved, err := NewTransactionArchiveService(repo, time.Hour, 10, time.Hour).Archive(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, 10, moved)
+	})
+
+	t.Run("disabled without a retention age", func(t *testing.T) {
+		repo := &batchArchiveRepository{waiting: 25}
+		moved, err := NewTransactionArchiveService(repo, 0, 10, time.Hour).Archive(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, moved)
+		assert.Zero(t, repo.calls)
+	})
+}
+
+func TestTransactionServiceImpl_ReplaysArchivedTransaction(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	store.setBalance(1, 100, 0)
+	svc := newMemoryTransactionService(store)
+
+	key := domain.ClientIdempotencyKey(1, "rent")
+	first, err := svc.Debit(ctx, 1, 40, key)
+	require.NoError(t, err)
+	store.archive(first.ID)
+
+	retry, err := svc.Debit(ctx, 1, 40, key)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, retry.ID)
+	amount, _ := store.balance(1)
+	assert.Equal(t, 60.0, amount, "a retry of an archived debit must not debit again")
+}
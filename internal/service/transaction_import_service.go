@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// MaxImportRows is the most transactions one import file may hold
+const MaxImportRows = 10000
+
+// maxExternalRefLength is the longest external reference the database stores
+const maxExternalRefLength = 255
+
+// maxImportDescriptionLength is the longest description an imported row may have
+const maxImportDescriptionLength = 500
+
+// importColumns are the columns an import file must have.
+var importColumns = []string{"external_ref", "date", "type", "amount"}
+
+// TransactionImportServiceImpl implements domain.TransactionImportService.
+type TransactionImportServiceImpl struct {
+	repo  domain.TransactionImportRepository
+	users domain.UserRepository
+	cache domain.CacheInvalidator
+	audit domain.AuditLogService
+	now   func() time.Time
+}
+
+// NewTransactionImportService creates a new TransactionImportServiceImpl.
+func NewTransactionImportService(repo domain.TransactionImportRepository, users domain.UserRepository, cache domain.CacheInvalidator, audit domain.AuditLogService) *TransactionImportServiceImpl {
+	return &TransactionImportServiceImpl{repo: repo, users: users, cache: cache, audit: audit, now: time.Now}
+}
+
+// Import reads a CSV file of a user's settled transactions.
+func (s *TransactionImportServiceImpl) Import(ctx context.Context, actorID, userID int, file io.Reader, dryRun bool) (*domain.TransactionImportReport, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, domain.ErrImportUserNotFound
+	}
+
+	rows, err := readImportFile(file)
+	if err != nil {
+		return nil, err
+	}
+	report := &domain.TransactionImportReport{UserID: userID, DryRun: dryRun, Rows: make([]domain.ImportRowResult, len(rows))}
+
+	// Parse every row, then look up the references of the valid ones at once
+	parsed := make([]*domain.ImportedTransaction, len(rows))
+	seen := make(map[string]int)
+	var refs []string
+	counterparties := make(map[int]bool)
+	for i, row := range rows {
+		result := &report.Rows[i]
+		result.Row, result.ExternalRef = row.line, row.ref
+		if line, ok := seen[row.ref]; ok && row.ref != "" {
+			result.Status, result.Error = domain.ImportRowInvalid, fmt.Sprintf("external_ref repeats row %d", line)
+			continue
+		}
+		seen[row.ref] = row.line
+		it, err := s.parseRow(row, userID, counterparties)
+		if err != nil {
+			result.Status, result.Error = domain.ImportRowInvalid, err.Error()
+			continue
+		}
+		parsed[i] = it
+		refs = append(refs, it.ExternalRef)
+	}
+	if err := s.checkCounterparties(ctx, report, parsed, counterparties); err != nil {
+		return nil, err
+	}
+
+	imported := make(map[string]int)
+	if len(refs) > 0 {
+		if imported, err = s.repo.ImportedRefs(ctx, userID, refs); err != nil {
+			return nil, fmt.Errorf("failed to look up imported references: %w", err)
+		}
+	}
+	var txs []*domain.ImportedTransaction
+	for i, it := range parsed {
+		if it == nil {
+			continue
+		}
+		if id, ok := imported[it.ExternalRef]; ok {
+			report.Rows[i].Status, report.Rows[i].TransactionID = domain.ImportRowDuplicate, &id
+			parsed[i] = nil
+			continue
+		}
+		txs = append(txs, it)
+	}
+
+	if len(txs) > 0 && !dryRun {
+		if report.Balances, err = s.repo.Import(ctx, userID, txs); err != nil {
+			if errors.Is(err, domain.ErrImportNegativeBalance) || errors.Is(err, domain.ErrImportConcurrent) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to import transactions: %w", err)
+		}
+	}
+	for i, it := range parsed {
+		if it == nil {
+			continue
+		}
+		report.Rows[i].Status = domain.ImportRowImported
+		if !dryRun {
+			id := it.Transaction.ID
+			report.Rows[i].TransactionID = &id
+		}
+	}
+	for _, r := range report.Rows {
+		switch r.Status {
+		case domain.ImportRowImported:
+			report.Imported++
+		case domain.ImportRowDuplicate:
+			report.Duplicates++
+		case domain.ImportRowInvalid:
+			report.Invalid++
+		}
+	}
+
+	if !dryRun && len(txs) > 0 {
+		touched := make([]*int, 0, len(report.Balances))
+		for id := range report.Balances {
+			touched = append(touched, &id)
+		}
+		invalidateUsers(ctx, s.cache, touched...)
+		details := fmt.Sprintf("imported %d transactions, skipped %d duplicates and %d invalid rows", report.Imported, report.Duplicates, report.Invalid)
+		if err := s.audit.Record(ctx, &actorID, "user", userID, "import_transactions", details); err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("user_id", userID).Int("actor_id", actorID).Msg("Failed to audit transaction import")
+		}
+	}
+	return report, nil
+}
+
+// importRow is a row of an import file as read, before validation
+type importRow struct {
+	line                                                 int
+	ref, date, txType, amount, counterparty, description string
+}
+
+// readImportFile reads the rows of an import file.
+func readImportFile(file io.Reader) ([]importRow, error) {
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.ReuseRecord = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, &domain.ValidationError{Msg: "import file is empty"}
+	}
+	if err != nil {
+		return nil, &domain.ValidationError{Msg: fmt.Sprintf("invalid CSV: %v", err)}
+	}
+	col := make(map[string]int)
+	for i, name := range header {
+		col[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := col[name]; !ok {
+			return nil, &domain.ValidationError{Msg: fmt.Sprintf("import file has no %s column", name)}
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := col[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []importRow
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, &domain.ValidationError{Msg: fmt.Sprintf("invalid CSV: %v", err)}
+		}
+		if len(rows) == MaxImportRows {
+			return nil, &domain.ValidationError{Msg: fmt.Sprintf("import file has more than %d rows", MaxImportRows)}
+		}
+		line, _ := r.FieldPos(0)
+		rows = append(rows, importRow{
+			line:         line,
+			ref:          field(record, "external_ref"),
+			date:         field(record, "date"),
+			txType:       field(record, "type"),
+			amount:       field(record, "amount"),
+			counterparty: field(record, "counterparty_id"),
+			description:  field(record, "description"),
+		})
+	}
+	if len(rows) == 0 {
+		return nil, &domain.ValidationError{Msg: "import file has no rows"}
+	}
+	return rows, nil
+}
+
+// parseRow validates a row and turns it into a completed transaction of the user.
+func (s *TransactionImportServiceImpl) parseRow(row importRow, userID int, counterparties map[int]bool) (*domain.ImportedTransaction, error) {
+	if row.ref == "" {
+		return nil, errors.New("external_ref is required")
+	}
+	if len(row.ref) > maxExternalRefLength {
+		return nil, fmt.Errorf("external_ref must be at most %d characters", maxExternalRefLength)
+	}
+
+	createdAt, err := parseImportDate(row.date)
+	if err != nil {
+		return nil, errors.New("date must be RFC 3339 or YYYY-MM-DD")
+	}
+	if createdAt.After(s.now()) {
+		return nil, errors.New("date is in the future")
+	}
+
+	amount, err := strconv.ParseFloat(row.amount, 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return nil, errors.New("amount must be a number")
+	}
+	if amount == 0 {
+		return nil, errors.New("amount must not be zero")
+	}
+	if math.Abs(amount*100-math.Round(amount*100)) > 1e-6 {
+		return nil, errors.New("amount must have at most two decimals")
+	}
+
+	if len(row.description) > maxImportDescriptionLength {
+		return nil, fmt.Errorf("description must be at most %d characters", maxImportDescriptionLength)
+	}
+
+	var counterpartyID *int
+	if row.counterparty != "" {
+		id, err := strconv.Atoi(row.counterparty)
+		if err != nil || id <= 0 {
+			return nil, errors.New("counterparty_id must be a user ID")
+		}
+		if id == userID {
+			return nil, errors.New("counterparty_id must be another user")
+		}
+		counterpartyID = &id
+	}
+
+	tx := &domain.Transaction{
+		Amount:      math.Abs(amount),
+		Type:        row.txType,
+		Status:      "completed",
+		CreatedAt:   createdAt,
+		Description: row.description,
+	}
+	switch row.txType {
+	case "credit", "debit":
+		if counterpartyID != nil {
+			return nil, fmt.Errorf("a %s has no counterparty_id", row.txType)
+		}
+		if row.txType == "credit" && amount < 0 {
+			return nil, errors.New("a credit amount must be positive")
+		}
+		if row.txType == "debit" && amount > 0 {
+			return nil, errors.New("a debit amount must be negative")
+		}
+		if amount > 0 {
+			tx.ToUserID = &userID
+		} else {
+			tx.FromUserID = &userID
+		}
+	case "transfer":
+		if counterpartyID == nil {
+			return nil, errors.New("a transfer needs a counterparty_id")
+		}
+		if amount > 0 {
+			tx.FromUserID, tx.ToUserID = counterpartyID, &userID
+		} else {
+			tx.FromUserID, tx.ToUserID = &userID, counterpartyID
+		}
+		counterparties[*counterpartyID] = true
+	default:
+		return nil, errors.New("type must be credit, debit or transfer")
+	}
+	return &domain.ImportedTransaction{Transaction: tx, ExternalRef: row.ref}, nil
+}
+
+// checkCounterparties marks the rows whose counterparty does not exist as invalid
+func (s *TransactionImportServiceImpl) checkCounterparties(ctx context.Context, report *domain.TransactionImportReport, parsed []*domain.ImportedTransaction, counterparties map[int]bool) error {
+	missing := make(map[int]bool)
+	for id := range counterparties {
+		u, err := s.users.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get counterparty: %w", err)
+		}
+		if u == nil {
+			missing[id] = true
+		}
+	}
+	for i, it := range parsed {
+		if it == nil || it.Transaction.Type != "transfer" {
+			continue
+		}
+		other := it.Transaction.FromUserID
+		if *other == report.UserID {
+			other = it.Transaction.ToUserID
+		}
+		if missing[*other] {
+			report.Rows[i].Status, report.Rows[i].Error = domain.ImportRowInvalid, fmt.Sprintf("counterparty user %d does not exist", *other)
+			parsed[i] = nil
+		}
+	}
+	return nil
+}
+
+// parseImportDate parses an RFC 3339 timestamp or a YYYY-MM-DD date (UTC)
+func parseImportDate(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", v)
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// importTestNow is the clock of the import tests; rows dated after it are in the future
+var importTestNow = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// someUsers implements the user lookup of domain.UserRepository for the users it lists
+type someUsers struct {
+	domain.UserRepository
+	ids map[int]bool
+}
+
+func (u someUsers) GetByID(ctx context.Context, id int) (*domain.User, error) {
+	if !u.ids[id] {
+		return nil, nil
+	}
+	return &domain.User{ID: id}, nil
+}
+
+// memoryImports implements domain.TransactionImportRepository, keeping what
+// was imported and computing balances from it alone
+type memoryImports struct {
+	refs   map[string]int
+	txs    []*domain.Transaction
+	nextID int
+	err    error // returned by Import instead of importing, if set
+}
+
+func (r *memoryImports) ImportedRefs(ctx context.Context, userID int, refs []string) (map[string]int, error) {
+	imported := make(map[string]int)
+	for _, ref := range refs {
+		if id, ok := r.refs[ref]; ok {
+			imported[ref] = id
+		}
+	}
+	return imported, nil
+}
+
+func (r *memoryImports) Import(ctx context.Context, userID int, txs []*domain.ImportedTransaction) (map[int]float64, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	balances := make(map[int]float64)
+	for _, it := range txs {
+		r.nextID++
+		it.Transaction.ID = r.nextID
+		r.refs[it.ExternalRef] = r.nextID
+		r.txs = append(r.txs, it.Transaction)
+	}
+	for _, t := range r.txs {
+		if t.ToUserID != nil {
+			balances[*t.ToUserID] += t.Amount
+		}
+		if t.FromUserID != nil {
+			balances[*t.FromUserID] -= t.Amount
+		}
+	}
+	return balances, nil
+}
+
+// newImportTestService returns a TransactionImportServiceImpl where users 1
+// and 2 exist and ref "old" was imported for user 1 as transaction 40
+func newImportTestService() (*TransactionImportServiceImpl, *memoryImports, *recordingInvalidator) {
+	repo := &memoryImports{refs: map[string]int{"old": 40}, nextID: 100}
+	invalidator := &recordingInvalidator{}
+	svc := NewTransactionImportService(repo, someUsers{ids: map[int]bool{1: true, 2: true}}, invalidator, discardAudit{})
+	svc.now = func() time.Time { return importTestNow }
+	return svc, repo, invalidator
+}
+
+func TestReadImportFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+		want    []importRow
+	}{
+		{name: "empty file", file: "", wantErr: "import file is empty"},
+		{name: "header only", file: "external_ref,date,type,amount\n", wantErr: "import file has no rows"},
+		{name: "missing column", file: "external_ref,date,type\na,2026-01-01,credit\n", wantErr: "import file has no amount column"},
+		{name: "malformed CSV", file: "external_ref,date,type,amount\n\"a,2026-01-01,credit,1\n", wantErr: "invalid CSV"},
+		{
+			name: "columns in any order, case and BOM ignored",
+			file: "\ufeffAmount, TYPE,date,external_ref,description,extra\n10.50,credit,2026-01-01,a,salary,x\n",
+			want: []importRow{{line: 2, ref: "a", date: "2026-01-01", txType: "credit", amount: "10.50", description: "salary"}},
+		},
+		{
+			name: "short records leave the missing fields empty",
+			file: "external_ref,date,type,amount,counterparty_id\nb,2026-01-02,transfer,-5\n",
+			want: []importRow{{line: 2, ref: "b", date: "2026-01-02", txType: "transfer", amount: "-5"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := readImportFile(strings.NewReader(tt.file))
+			if tt.wantErr != "" {
+				var verr *domain.ValidationError
+				require.ErrorAs(t, err, &verr)
+				assert.Contains(t, verr.Msg, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rows)
+		})
+	}
+
+	t.Run("more than MaxImportRows", func(t *testing.T) {
+		var b strings.Builder
+		b.WriteString("external_ref,date,type,amount\n")
+		for i := 0; i <= MaxImportRows; i++ {
+			b.WriteString("r,2026-01-01,credit,1\n")
+		}
+		_, err := readImportFile(strings.NewReader(b.String()))
+		var verr *domain.ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Contains(t, verr.Msg, "more than")
+	})
+}
+
+func TestTransactionImportServiceImpl_ParseRow(t *testing.T) {
+	svc, _, _ := newImportTestService()
+	one, two := 1, 2
+	row := func(txType, amount, counterparty string) importRow {
+		return importRow{ref: "r", date: "2026-04-01", txType: txType, amount: amount, counterparty: counterparty}
+	}
+
+	valid := []struct {
+		name     string
+		row      importRow
+		from, to *int
+		amount   float64
+	}{
+		{name: "credit", row: row("credit", "10.25", ""), to: &one, amount: 10.25},
+		{name: "debit", row: row("debit", "-3", ""), from: &one, amount: 3},
+		{name: "transfer sent", row: row("transfer", "-7.5", "2"), from: &one, to: &two, amount: 7.5},
+		{name: "transfer received", row: row("transfer", "7.5", "2"), from: &two, to: &one, amount: 7.5},
+	}
+	for _, tt := range valid {
+		t.Run(tt.name, func(t *testing.T) {
+			it, err := svc.parseRow(tt.row, 1, map[int]bool{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.from, it.Transaction.FromUserID)
+			assert.Equal(t, tt.to, it.Transaction.ToUserID)
+			assert.Equal(t, tt.amount, it.Transaction.Amount)
+			assert.Equal(t, "completed", it.Transaction.Status)
+			assert.Equal(t, "r", it.ExternalRef)
+		})
+	}
+
+	invalid := []struct {
+		name    string
+		row     importRow
+		wantErr string
+	}{
+		{name: "no reference", row: importRow{date: "2026-04-01", txType: "credit", amount: "1"}, wantErr: "external_ref is required"},
+		{name: "long reference", row: importRow{ref: strings.Repeat("x", maxExternalRefLength+1), date: "2026-04-01", txType: "credit", amount: "1"}, wantErr: "external_ref must be at most"},
+		{name: "bad date", row: importRow{ref: "r", date: "01/04/2026", txType: "credit", amount: "1"}, wantErr: "date must be"},
+		{name: "future date", row: importRow{ref: "r", date: "2026-05-02", txType: "credit", amount: "1"}, wantErr: "date is in the future"},
+		{name: "not a number", row: row("credit", "ten", ""), wantErr: "amount must be a number"},
+		{name: "NaN", row: row("credit", "NaN", ""), wantErr: "amount must be a number"},
+		{name: "zero", row: row("credit", "0", ""), wantErr: "amount must not be zero"},
+		{name: "three decimals", row: row("credit", "1.005", ""), wantErr: "at most two decimals"},
+		{name: "long description", row: importRow{ref: "r", date: "2026-04-01", txType: "credit", amount: "1", description: strings.Repeat("x", maxImportDescriptionLength+1)}, wantErr: "description must be at most"},
+		{name: "counterparty not an ID", row: row("transfer", "1", "bob"), wantErr: "counterparty_id must be a user ID"},
+		{name: "counterparty is the user", row: row("transfer", "1", "1"), wantErr: "counterparty_id must be another user"},
+		{name: "credit with counterparty", row: row("credit", "1", "2"), wantErr: "a credit has no counterparty_id"},
+		{name: "negative credit", row: row("credit", "-1", ""), wantErr: "a credit amount must be positive"},
+		{name: "positive debit", row: row("debit", "1", ""), wantErr: "a debit amount must be negative"},
+		{name: "transfer without counterparty", row: row("transfer", "1", ""), wantErr: "a transfer needs a counterparty_id"},
+		{name: "unknown type", row: row("refund", "1", ""), wantErr: "type must be credit, debit or transfer"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.parseRow(tt.row, 1, map[int]bool{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("RFC 3339 dates are kept in UTC", func(t *testing.T) {
+		r := row("credit", "1", "")
+		r.date = "2026-04-01T10:00:00+03:00"
+		it, err := svc.parseRow(r, 1, map[int]bool{})
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 4, 1, 7, 0, 0, 0, time.UTC), it.Transaction.CreatedAt)
+	})
+}
+
+func TestTransactionImportServiceImpl_Import(t *testing.T) {
+	ctx := context.Background()
+	file := "external_ref,date,type,amount,counterparty_id\n" +
+		"a,2026-04-01,credit,100,\n" +
+		"old,2026-04-02,credit,5,\n" +
+		"b,2026-04-03,transfer,-30,2\n" +
+		"a,2026-04-04,credit,1,\n" +
+		"c,2026-04-05,transfer,-1,3\n" +
+		"d,2026-04-06,debit,2,\n"
+
+	t.Run("reports every row and imports the valid new ones", func(t *testing.T) {
+		svc, repo, invalidator := newImportTestService()
+
+		report, err := svc.Import(ctx, 9, 1, strings.NewReader(file), false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.Imported)
+		assert.Equal(t, 1, report.Duplicates)
+		assert.Equal(t, 3, report.Invalid)
+		statuses := make([]string, len(report.Rows))
+		for i, r := range report.Rows {
+			statuses[i] = r.Status
+		}
+		assert.Equal(t, []string{"imported", "duplicate", "imported", "invalid", "invalid", "invalid"}, statuses)
+		assert.Equal(t, 40, *report.Rows[1].TransactionID)
+		assert.Contains(t, report.Rows[3].Error, "repeats row 2")
+		assert.Contains(t, report.Rows[4].Error, "counterparty user 3 does not exist")
+
+		assert.Len(t, repo.txs, 2)
+		assert.Equal(t, map[int]float64{1: 70, 2: 30}, report.Balances)
+		assert.True(t, invalidator.invalidated(1))
+		assert.True(t, invalidator.invalidated(2))
+	})
+
+	t.Run("a dry run stores nothing", func(t *testing.T) {
+		svc, repo, invalidator := newImportTestService()
+
+		report, err := svc.Import(ctx, 9, 1, strings.NewReader(file), true)
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.Imported)
+		assert.Nil(t, report.Rows[0].TransactionID)
+		assert.Empty(t, repo.txs)
+		assert.Empty(t, invalidator.patterns)
+	})
+
+	t.Run("a balance below zero fails the whole import", func(t *testing.T) {
+		svc, repo, _ := newImportTestService()
+		repo.err = domain.ErrImportNegativeBalance
+
+		_, err := svc.Import(ctx, 9, 1, strings.NewReader(file), false)
+		assert.ErrorIs(t, err, domain.ErrImportNegativeBalance)
+	})
+
+	t.Run("an unknown user", func(t *testing.T) {
+		svc, _, _ := newImportTestService()
+
+		_, err := svc.Import(ctx, 9, 5, strings.NewReader(file), false)
+		assert.ErrorIs(t, err, domain.ErrImportUserNotFound)
+	})
+}
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,32 +22,13 @@ func (s *transactionLimitService) CheckAndRecordTransaction(ctx context.Context,
 }
 
 func (s *transactionLimitService) AddRule(ctx context.Context, rule domain.TransactionLimitRule) (domain.TransactionLimitRule, error) {
-	// Validate RuleType
-	switch rule.RuleType {
-	case domain.RuleMaxPerTransaction, domain.RuleDailyTotal, domain.RuleTxCount, domain.RuleMinInterval:
-		// valid
-	default:
-		return domain.TransactionLimitRule{}, errors.New("invalid rule type")
-	}
-	// Validate LimitAmount
-	if rule.LimitAmount <= 0 {
-		return domain.TransactionLimitRule{}, errors.New("limit amount must be positive")
-	}
-	// Validate Window for rules that require it
-	if (rule.RuleType == domain.RuleDailyTotal || rule.RuleType == domain.RuleTxCount || rule.RuleType == domain.RuleMinInterval) && rule.Window <= 0 {
-		return domain.TransactionLimitRule{}, errors.New("window must be positive for this rule type")
-	}
-	// Generate UUID if not set
-	if rule.ID == "" {
-		rule.ID = uuid.NewString()
-	}
-	// Set CreatedAt/UpdatedAt if not set
-	if rule.CreatedAt.IsZero() {
-		rule.CreatedAt = time.Now().UTC()
-	}
-	if rule.UpdatedAt.IsZero() {
-		rule.UpdatedAt = rule.CreatedAt
+	if err := rule.Validate(); err != nil {
+		return domain.TransactionLimitRule{}, err
 	}
+	// IDs and timestamps are always assigned here, never taken from the caller
+	rule.ID = uuid.NewString()
+	rule.CreatedAt = time.Now().UTC()
+	rule.UpdatedAt = rule.CreatedAt
 	rule, err := s.repo.AddRule(ctx, rule)
 	if err != nil {
 		return domain.TransactionLimitRule{}, err
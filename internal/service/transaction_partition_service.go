@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// TransactionPartitionServiceImpl implements domain.TransactionPartitionService
+type TransactionPartitionServiceImpl struct {
+	partitionRepo   domain.TransactionPartitionRepository
+	monthsAhead     int // future months that must always have a partition
+	retentionMonths int // attached months kept before detaching; 0 keeps all
+	interval        time.Duration
+	stopChan        chan struct{}
+}
+
+// NewTransactionPartitionService creates a new TransactionPartitionServiceImpl.
+func NewTransactionPartitionService(partitionRepo domain.TransactionPartitionRepository, monthsAhead, retentionMonths int, interval time.Duration) *TransactionPartitionServiceImpl {
+	return &TransactionPartitionServiceImpl{
+		partitionRepo:   partitionRepo,
+		monthsAhead:     monthsAhead,
+		retentionMonths: retentionMonths,
+		interval:        interval,
+		stopChan:        make(chan struct{}),
+	}
+}
+
+// Maintain creates upcoming partitions and detaches those past retention.
+func (s *TransactionPartitionServiceImpl) Maintain(ctx context.Context) error {
+	// Step from the first of the month; adding months to the 31st skips short months
+	current := domain.NewTransactionPartition(time.Now()).From
+	var errs []error
+
+	for i := 0; i <= s.monthsAhead; i++ {
+		partition := domain.NewTransactionPartition(current.AddDate(0, i, 0))
+		if err := s.partitionRepo.CreatePartition(ctx, partition); err != nil {
+			errs = append(errs, fmt.Errorf("failed to create partition %s: %w", partition.Name, err))
+		}
+	}
+
+	if s.retentionMonths > 0 {
+		cutoff := current.AddDate(0, -s.retentionMonths, 0)
+		partitions, err := s.partitionRepo.ListPartitions(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list partitions: %w", err))
+		}
+		for _, partition := range partitions {
+			if partition.To.After(cutoff) {
+				break
+			}
+			if err := s.partitionRepo.DetachPartition(ctx, partition); err != nil {
+				errs = append(errs, fmt.Errorf("failed to detach partition %s: %w", partition.Name, err))
+				continue
+			}
+			log.Ctx(ctx).Info().Str("partition", partition.Name).Msg("Detached expired transaction partition")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Start runs maintenance now and then every interval
+func (s *TransactionPartitionServiceImpl) Start(ctx context.Context) {
+	log.Ctx(ctx).Info().
+		Int("months_ahead", s.monthsAhead).
+		Int("retention_months", s.retentionMonths).
+		Dur("interval", s.interval).
+		Msg("Starting transaction partition maintenance")
+
+	go s.maintenanceLoop(ctx)
+}
+
+// Stop stops the periodic maintenance
+func (s *TransactionPartitionServiceImpl) Stop() {
+	log.Info().Msg("Stopping transaction partition maintenance")
+	close(s.stopChan)
+}
+
+func (s *TransactionPartitionServiceImpl) maintenanceLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Maintain(ctx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Transaction partition maintenance failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
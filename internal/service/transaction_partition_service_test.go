@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// memoryPartitionRepository records the partitions it is asked to create and detach
+type memoryPartitionRepository struct {
+	attached   []domain.TransactionPartition
+	created    []string
+	detached   []string
+	failCreate string // partition name whose creation fails
+}
+
+func (r *memoryPartitionRepository) ListPartitions(ctx context.Context) ([]domain.TransactionPartition, error) {
+	return r.attached, nil
+}
+
+func (r *memoryPartitionRepository) CreatePartition(ctx context.Context, partition domain.TransactionPartition) error {
+	if partition.Name == r.failCreate {
+		return errors.New("default partition holds rows for this month")
+	}
+	r.created = append(r.created, partition.Name)
+	return nil
+}
+
+func (r *memoryPartitionRepository) DetachPartition(ctx context.Context, partition domain.TransactionPartition) error {
+	r.detached = append(r.detached, partition.Name)
+	return nil
+}
+
+func TestNewTransactionPartition(t *testing.T) {
+	p := domain.NewTransactionPartition(time.Date(2024, time.January, 31, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, "transactions_p202401", p.Name)
+	assert.Equal(t, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), p.From)
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), p.To)
+}
+
+func TestTransactionPartitionServiceImpl_Maintain(t *testing.T) {
+	ctx := context.Background()
+	current := domain.NewTransactionPartition(time.Now()).From
+	month := func(offset int) domain.TransactionPartition {
+		return domain.NewTransactionPartition(current.AddDate(0, offset, 0))
+	}
+
+	t.Run("creates the current month and the months ahead", func(t *testing.T) {
+		repo := &memoryPartitionRepository{}
+		require.NoError(t, NewTransactionPartitionService(repo, 2, 0, time.Hour).Maintain(ctx))
+		assert.Equal(t, []string{month(0).Name, month(1).Name, month(2).Name}, repo.created)
+		assert.Empty(t, repo.detached, "no retention keeps every partition")
+	})
+
+	t.Run("detaches only months wholly past retention", func(t *testing.T) {
+		repo := &memoryPartitionRepository{attached: []domain.TransactionPartition{month(-4), month(-3), month(-2), month(-1), month(0)}}
+		require.NoError(t, NewTransactionPartitionService(repo, 0, 3, time.Hour).Maintain(ctx))
+		assert.Equal(t, []string{month(-4).Name}, repo.detached, "three months are kept before the current one")
+	})
+
+	t.Run("keeps going after a failure", func(t *testing.T) {
+		repo := &memoryPartitionRepository{failCreate: month(1).Name}
+		err := NewTransactionPartitionService(repo, 2, 0, time.Hour).Maintain(ctx)
+		assert.ErrorContains(t, err, month(1).Name)
+		assert.Equal(t, []string{month(0).Name, month(2).Name}, repo.created)
+	})
+}
@@ -3,6 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
 
 	"github.com/melihgurlek/backend-path/internal/domain"
 	"github.com/melihgurlek/backend-path/pkg/metrics"
@@ -10,13 +17,36 @@ import (
 
 // TransactionServiceImpl implements domain.TransactionService.
 type TransactionServiceImpl struct {
-	txRepo  domain.TransactionRepository
-	balRepo domain.BalanceRepository
+	txRepo   domain.TransactionRepository
+	uow      domain.UnitOfWork
+	cache    domain.CacheInvalidator
+	notifier domain.Notifier
+	alerts   domain.TransactionAlerter
+	fees     domain.FeeCalculator
+	rewards  domain.TransactionRewarder
+	review   TransactionReview
+}
+
+// TransactionReview configures the review money movements get before they run
+type TransactionReview struct {
+	// ApprovalThreshold is the amount above which a movement is held for an
+	// admin's approval; 0 disables approvals
+	ApprovalThreshold float64
+	// Audit records the movements held for approval; may be nil
+	Audit domain.AuditLogService
+	// Fraud scores debits and transfers before they run, rejecting them or
+	// holding them for approval as it decides; may be nil
+	Fraud domain.FraudService
 }
 
 // NewTransactionService creates a new TransactionServiceImpl.
-func NewTransactionService(txRepo domain.TransactionRepository, balRepo domain.BalanceRepository) *TransactionServiceImpl {
-	return &TransactionServiceImpl{txRepo: txRepo, balRepo: balRepo}
+func NewTransactionService(txRepo domain.TransactionRepository, uow domain.UnitOfWork, cache domain.CacheInvalidator, notifier domain.Notifier, alerts domain.TransactionAlerter, fees domain.FeeCalculator, rewards domain.TransactionRewarder, review TransactionReview) *TransactionServiceImpl {
+	return &TransactionServiceImpl{txRepo: txRepo, uow: uow, cache: cache, notifier: notifier, alerts: alerts, fees: fees, rewards: rewards, review: review}
+}
+
+// RequiresApproval reports whether a movement of amount must be approved before it runs
+func (s *TransactionServiceImpl) RequiresApproval(amount float64) bool {
+	return s.review.ApprovalThreshold > 0 && amount > s.review.ApprovalThreshold
 }
 
 // recordTransactionMetrics is a helper function to avoid repetition.
@@ -30,25 +60,10 @@ func (s *TransactionServiceImpl) recordTransactionMetrics(txType string, amount
 	metrics.AverageTransactionAmount.WithLabelValues(txType).Observe(amount)
 }
 
-// Credit adds amount to a user's balance and records a transaction.
-func (s *TransactionServiceImpl) Credit(userID int, amount float64) error {
+// Credit adds amount to a user's balance and returns the recorded transaction.
+func (s *TransactionServiceImpl) Credit(ctx context.Context, userID int, amount float64, idempotencyKey string) (*domain.Transaction, error) {
 	if amount <= 0 {
-		return errors.New("amount must be positive")
-	}
-	bal, err := s.balRepo.GetByUserID(userID)
-	if err != nil {
-		// Record transaction failure
-		s.recordTransactionMetrics("credit", amount, false)
-		return err
-	}
-	if bal == nil {
-		bal = &domain.Balance{UserID: userID, Amount: 0}
-	}
-	bal.Amount += amount
-	if err := s.balRepo.Update(bal); err != nil {
-		// Record transaction failure
-		s.recordTransactionMetrics("credit", amount, false)
-		return err
+		return nil, &domain.ValidationError{Msg: "amount must be positive"}
 	}
 	tx := &domain.Transaction{
 		FromUserID: nil, // system
@@ -56,40 +71,42 @@ func (s *TransactionServiceImpl) Credit(userID int, amount float64) error {
 		Amount:     amount,
 		Type:       "credit",
 		Status:     "completed",
+
+		IdempotencyKey: idempotencyKey,
+	}
+	if prior, err := s.findPrior(ctx, tx); prior != nil || err != nil {
+		return prior, err
+	}
+	if err := s.priceFee(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	if s.RequiresApproval(amount) {
+		return s.holdForApproval(ctx, tx, nil)
 	}
-	if err := s.txRepo.Create(tx); err != nil {
+	err := s.atomically(ctx, func(repos domain.UnitOfWorkRepositories) error {
+		if err := moveFunds(ctx, repos, tx); err != nil {
+			return err
+		}
+		return repos.Transactions.Create(ctx, tx)
+	})
+	if err != nil {
 		// Record transaction failure
 		s.recordTransactionMetrics("credit", amount, false)
-		return err
+		return nil, err
 	}
 
 	// Record successful transaction
 	s.recordTransactionMetrics("credit", amount, true)
+	invalidateUsers(ctx, s.cache, tx.FromUserID, tx.ToUserID)
+	s.notifyCompleted(ctx, tx)
 
-	return nil
+	return tx, nil
 }
 
-// Debit subtracts amount from a user's balance and records a transaction.
-func (s *TransactionServiceImpl) Debit(userID int, amount float64) error {
+// Debit subtracts amount from a user's balance and returns the recorded transaction.
+func (s *TransactionServiceImpl) Debit(ctx context.Context, userID int, amount float64, idempotencyKey string) (*domain.Transaction, error) {
 	if amount <= 0 {
-		return errors.New("amount must be positive")
-	}
-	bal, err := s.balRepo.GetByUserID(userID)
-	if err != nil {
-		// Record transaction failure
-		s.recordTransactionMetrics("debit", amount, false)
-		return err
-	}
-	if bal == nil || bal.Amount < amount {
-		// Record transaction failure
-		s.recordTransactionMetrics("debit", amount, false)
-		return errors.New("insufficient balance")
-	}
-	bal.Amount -= amount
-	if err := s.balRepo.Update(bal); err != nil {
-		// Record transaction failure
-		s.recordTransactionMetrics("debit", amount, false)
-		return err
+		return nil, &domain.ValidationError{Msg: "amount must be positive"}
 	}
 	tx := &domain.Transaction{
 		FromUserID: &userID,
@@ -97,89 +114,596 @@ func (s *TransactionServiceImpl) Debit(userID int, amount float64) error {
 		Amount:     amount,
 		Type:       "debit",
 		Status:     "completed",
+
+		IdempotencyKey: idempotencyKey,
+	}
+	if prior, err := s.findPrior(ctx, tx); prior != nil || err != nil {
+		return prior, err
+	}
+	if err := s.priceFee(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	assessment, err := s.screen(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if s.RequiresApproval(amount) || held(assessment) {
+		pending, err := s.holdForApproval(ctx, tx, nil)
+		if pending != nil {
+			s.linkAssessment(ctx, assessment, pending)
+		}
+		return pending, err
 	}
-	if err := s.txRepo.Create(tx); err != nil {
+	err = s.atomically(ctx, func(repos domain.UnitOfWorkRepositories) error {
+		if err := moveFunds(ctx, repos, tx); err != nil {
+			return err
+		}
+		return repos.Transactions.Create(ctx, tx)
+	})
+	if err != nil {
 		// Record transaction failure
 		s.recordTransactionMetrics("debit", amount, false)
-		return err
+		return nil, err
 	}
 
 	// Record successful transaction
 	s.recordTransactionMetrics("debit", amount, true)
+	s.linkAssessment(ctx, assessment, tx)
+	invalidateUsers(ctx, s.cache, tx.FromUserID, tx.ToUserID)
+	s.notifyCompleted(ctx, tx)
 
-	return nil
+	return tx, nil
 }
 
-// Transfer moves amount from one user to another, updating balances and recording a transaction.
-func (s *TransactionServiceImpl) Transfer(fromUserID, toUserID int, amount float64) error {
+// Transfer moves amount from one user to another.
+func (s *TransactionServiceImpl) Transfer(ctx context.Context, fromUserID, toUserID int, amount float64, idempotencyKey string) (*domain.Transaction, error) {
 	if amount <= 0 {
-		return errors.New("amount must be positive")
+		return nil, &domain.ValidationError{Msg: "amount must be positive"}
 	}
 	if fromUserID == toUserID {
-		return errors.New("cannot transfer to self")
+		return nil, &domain.ValidationError{Msg: "cannot transfer to self"}
+	}
+	tx := &domain.Transaction{
+		FromUserID: &fromUserID,
+		ToUserID:   &toUserID,
+		Amount:     amount,
+		Type:       "transfer",
+		Status:     "completed",
+
+		IdempotencyKey: idempotencyKey,
 	}
-	fromBal, err := s.balRepo.GetByUserID(fromUserID)
+	if prior, err := s.findPrior(ctx, tx); prior != nil || err != nil {
+		return prior, err
+	}
+	if err := s.priceFee(ctx, tx, fromUserID); err != nil {
+		return nil, err
+	}
+	assessment, err := s.screen(ctx, tx)
 	if err != nil {
-		// Record transaction failure
-		s.recordTransactionMetrics("transfer", amount, false)
-		return err
+		return nil, err
 	}
-	if fromBal == nil || fromBal.Amount < amount {
+	if s.RequiresApproval(amount) || held(assessment) {
+		pending, err := s.holdForApproval(ctx, tx, nil)
+		if pending != nil {
+			s.linkAssessment(ctx, assessment, pending)
+		}
+		return pending, err
+	}
+	err = s.atomically(ctx, func(repos domain.UnitOfWorkRepositories) error {
+		if err := moveFunds(ctx, repos, tx); err != nil {
+			return err
+		}
+		return repos.Transactions.Create(ctx, tx)
+	})
+	if err != nil {
 		// Record transaction failure
 		s.recordTransactionMetrics("transfer", amount, false)
-		return errors.New("insufficient balance")
+		return nil, err
 	}
-	toBal, err := s.balRepo.GetByUserID(toUserID)
+
+	// Record successful transaction
+	s.recordTransactionMetrics("transfer", amount, true)
+	s.linkAssessment(ctx, assessment, tx)
+	invalidateUsers(ctx, s.cache, tx.FromUserID, tx.ToUserID)
+	s.notifyCompleted(ctx, tx)
+
+	return tx, nil
+}
+
+// CaptureHold debits the amount an active hold reserves and settles the hold.
+func (s *TransactionServiceImpl) CaptureHold(ctx context.Context, hold *domain.Hold) (*domain.Transaction, error) {
+	if err := hold.CanCapture(); err != nil {
+		return nil, err
+	}
+	tx := &domain.Transaction{
+		FromUserID: &hold.UserID,
+		ToUserID:   nil, // system
+		Amount:     hold.Amount,
+		Type:       "debit",
+		Status:     "completed",
+	}
+	if err := s.priceFee(ctx, tx, hold.UserID); err != nil {
+		return nil, err
+	}
+	assessment, err := s.screen(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if s.RequiresApproval(tx.Amount) || held(assessment) {
+		pending, err := s.holdForApproval(ctx, tx, func(repos domain.UnitOfWorkRepositories) error {
+			return repos.Holds.Bind(ctx, hold.ID, tx.ID)
+		})
+		if pending != nil {
+			s.linkAssessment(ctx, assessment, pending)
+			hold.TransactionID = &pending.ID
+		}
+		return pending, err
+	}
+	var captured *domain.Hold
+	err = s.atomically(ctx, func(repos domain.UnitOfWorkRepositories) error {
+		if err := repos.Transactions.Create(ctx, tx); err != nil {
+			return err
+		}
+		if captured, err = captureHold(ctx, repos, hold, tx); err != nil {
+			return err
+		}
+		return moveFunds(ctx, repos, tx)
+	})
 	if err != nil {
 		// Record transaction failure
-		s.recordTransactionMetrics("transfer", amount, false)
+		s.recordTransactionMetrics("debit", tx.Amount, false)
+		return nil, err
+	}
+	*hold = *captured
+
+	// Record successful transaction
+	s.recordTransactionMetrics("debit", tx.Amount, true)
+	s.linkAssessment(ctx, assessment, tx)
+	invalidateUsers(ctx, s.cache, tx.FromUserID, tx.ToUserID)
+	s.notifyCompleted(ctx, tx)
+
+	return tx, nil
+}
+
+// captureHold releases the amount hold reserves for tx to debit and stores it as captured.
+func captureHold(ctx context.Context, repos domain.UnitOfWorkRepositories, hold *domain.Hold, tx *domain.Transaction) (*domain.Hold, error) {
+	bal, err := repos.Balances.GetByUserID(ctx, hold.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	if bal == nil {
+		return nil, domain.ErrInsufficientFunds
+	}
+	if err := coversHold(bal, hold); err != nil {
+		return nil, err
+	}
+	bal.HeldAmount -= hold.Amount
+	if err := repos.Balances.Update(ctx, bal); err != nil {
+		return nil, err
+	}
+	captured := *hold
+	if err := captured.Capture(); err != nil {
+		return nil, err
+	}
+	captured.TransactionID = &tx.ID
+	return &captured, repos.Holds.Resolve(ctx, &captured)
+}
+
+// captureBound captures the hold a pending debit was held with, if any
+func captureBound(ctx context.Context, repos domain.UnitOfWorkRepositories, tx *domain.Transaction) error {
+	hold, err := repos.Holds.GetByTransactionID(ctx, tx.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get hold: %w", err)
+	}
+	if hold == nil {
+		return nil
+	}
+	_, err = captureHold(ctx, repos, hold, tx)
+	return err
+}
+
+// atomically runs fn in a unit of work, retrying it on a balance conflict.
+func (s *TransactionServiceImpl) atomically(ctx context.Context, fn func(repos domain.UnitOfWorkRepositories) error) error {
+	return retryOnBalanceConflict(func() error {
+		return s.uow.Do(ctx, fn)
+	})
+}
+
+// priceFee sets the fee payerID is charged for tx by the schedule in effect.
+func (s *TransactionServiceImpl) priceFee(ctx context.Context, tx *domain.Transaction, payerID int) error {
+	if s.fees == nil {
+		return nil
+	}
+	quote, err := s.fees.Quote(ctx, tx.Type, payerID, tx.Amount)
+	if err != nil {
+		return fmt.Errorf("failed to price fee: %w", err)
+	}
+	if quote != nil {
+		tx.Fee, tx.FeeScheduleID = quote.Amount, &quote.ScheduleID
+	}
+	return nil
+}
+
+// screen scores a debit or transfer for fraud before it runs.
+func (s *TransactionServiceImpl) screen(ctx context.Context, tx *domain.Transaction) (*domain.FraudAssessment, error) {
+	if s.review.Fraud == nil {
+		return nil, nil
+	}
+	assessment, err := s.review.Fraud.Assess(ctx, tx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("type", tx.Type).Msg("Fraud screening failed; continuing without it")
+		return nil, nil
+	}
+	if assessment.Decision == domain.FraudDecisionReject {
+		return nil, domain.ErrFraudRejected
+	}
+	return assessment, nil
+}
+
+// held reports whether fraud screening asked for the transaction to be held for approval
+func held(assessment *domain.FraudAssessment) bool {
+	return assessment != nil && assessment.Decision == domain.FraudDecisionHold
+}
+
+// linkAssessment records the transaction a fraud assessment was made for
+func (s *TransactionServiceImpl) linkAssessment(ctx context.Context, assessment *domain.FraudAssessment, tx *domain.Transaction) {
+	if assessment == nil {
+		return
+	}
+	if err := s.review.Fraud.LinkTransaction(ctx, assessment.ID, tx.ID); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int("assessment_id", assessment.ID).Int("transaction_id", tx.ID).Msg("Failed to link fraud assessment to transaction")
+	}
+}
+
+// holdForApproval records tx as "pending_approval" instead of moving any money.
+func (s *TransactionServiceImpl) holdForApproval(ctx context.Context, tx *domain.Transaction, record func(repos domain.UnitOfWorkRepositories) error) (*domain.Transaction, error) {
+	requestedBy, ok := domain.RequesterFromContext(ctx)
+	if !ok && tx.FromUserID != nil {
+		requestedBy = *tx.FromUserID
+	} else if !ok {
+		requestedBy = *tx.ToUserID
+	}
+	tx.Status = "pending_approval"
+	err := s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+		if err := repos.Transactions.Create(ctx, tx); err != nil {
+			return err
+		}
+		if err := repos.Approvals.Create(ctx, domain.NewApproval(tx.ID, requestedBy)); err != nil {
+			return err
+		}
+		return recordPending(repos, record)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to hold transaction for approval: %w", err)
+	}
+	// The payer and payee see the held transaction in their lists
+	invalidateUsers(ctx, s.cache, tx.FromUserID, tx.ToUserID)
+
+	if s.review.Audit != nil {
+		details := fmt.Sprintf("%s of %.2f", tx.Type, tx.Amount)
+		if err := s.review.Audit.Record(ctx, &requestedBy, "transaction", tx.ID, "submit_for_approval", details); err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("transaction_id", tx.ID).Msg("Failed to audit transaction held for approval")
+		}
+	}
+	log.Ctx(ctx).Info().Int("transaction_id", tx.ID).Int("requested_by", requestedBy).Float64("amount", tx.Amount).Msg("Transaction held for approval")
+	return tx, domain.ErrTransactionHeld
+}
+
+// HoldForSignOff screens tx and records it as "pending_approval" together with record.
+func (s *TransactionServiceImpl) HoldForSignOff(ctx context.Context, tx *domain.Transaction, record func(repos domain.UnitOfWorkRepositories) error) error {
+	if tx.Type != "debit" && tx.Type != "transfer" {
+		return &domain.ValidationError{Msg: "only debits and transfers are signed off"}
+	}
+	assessment, err := s.screen(ctx, tx)
+	if err != nil {
 		return err
 	}
-	if toBal == nil {
-		toBal = &domain.Balance{UserID: toUserID, Amount: 0}
+	tx.Status = "pending_approval"
+	err = s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+		if err := repos.Transactions.Create(ctx, tx); err != nil {
+			return err
+		}
+		return record(repos)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to hold transaction for sign-off: %w", err)
 	}
-	fromBal.Amount -= amount
-	toBal.Amount += amount
-	if err := s.balRepo.Update(fromBal); err != nil {
-		// Record transaction failure
-		s.recordTransactionMetrics("transfer", amount, false)
+	invalidateUsers(ctx, s.cache, tx.FromUserID, tx.ToUserID)
+	s.linkAssessment(ctx, assessment, tx)
+	return nil
+}
+
+// notifyCompleted runs the follow-ups of a committed transaction.
+func (s *TransactionServiceImpl) notifyCompleted(ctx context.Context, tx *domain.Transaction) {
+	if s.alerts != nil {
+		s.alerts.CheckTransaction(ctx, tx)
+	}
+	// Bonuses are paid after the transaction's own notifications go out
+	if s.rewards != nil {
+		defer s.rewards.RewardTransaction(ctx, tx)
+	}
+	if s.notifier == nil {
+		return
+	}
+	data := func(direction string) map[string]string {
+		return map[string]string{
+			"type":           tx.Type,
+			"amount":         formatAmount(tx.Amount),
+			"transaction_id": strconv.Itoa(tx.ID),
+			"direction":      direction,
+		}
+	}
+	if tx.FromUserID != nil {
+		s.notifier.Notify(ctx, *tx.FromUserID, domain.EventTransactionCompleted, data("out"))
+	}
+	if tx.ToUserID != nil {
+		s.notifier.Notify(ctx, *tx.ToUserID, domain.EventTransactionCompleted, data("in"))
+	}
+}
+
+// findPrior returns the transaction already recorded under tx's idempotency key, if any.
+func (s *TransactionServiceImpl) findPrior(ctx context.Context, tx *domain.Transaction) (*domain.Transaction, error) {
+	if tx.IdempotencyKey == "" {
+		return nil, nil
+	}
+	prior, err := s.txRepo.GetByIdempotencyKey(ctx, tx.IdempotencyKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check idempotency key: %w", err)
+	}
+	if prior == nil {
+		return nil, nil
+	}
+	if !sameMovement(prior, tx) {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	metrics.TransactionIdempotentReplays.WithLabelValues(prior.Type).Inc()
+	switch prior.Status {
+	case "completed":
+		return prior, nil
+	case "pending_approval":
+		return prior, domain.ErrTransactionHeld
+	default:
+		return prior, domain.ErrTransactionNotCompleted
+	}
+}
+
+// sameMovement reports whether two transactions move the same amount between the same users.
+func sameMovement(a, b *domain.Transaction) bool {
+	sameUser := func(x, y *int) bool {
+		return (x == nil && y == nil) || (x != nil && y != nil && *x == *y)
+	}
+	return a.Type == b.Type && sameUser(a.FromUserID, b.FromUserID) && sameUser(a.ToUserID, b.ToUserID) &&
+		math.Abs(a.Amount-b.Amount) < 0.005
+}
+
+// ExecutePending moves the funds of a transaction recorded as "pending_approval".
+func (s *TransactionServiceImpl) ExecutePending(ctx context.Context, tx *domain.Transaction, record func(repos domain.UnitOfWorkRepositories) error) error {
+	if tx.Status != "pending_approval" {
+		return domain.ErrTransactionNotPending
+	}
+	// The fee is that of the schedule in effect when the funds move
+	payerID := tx.FromUserID
+	if tx.Type == "credit" {
+		payerID = tx.ToUserID
+	}
+	if payerID != nil {
+		if err := s.priceFee(ctx, tx, *payerID); err != nil {
+			return err
+		}
+	}
+
+	var recordErr error
+	err := s.atomically(ctx, func(repos domain.UnitOfWorkRepositories) error {
+		// Claimed first: of two concurrent approvals only one moves the money
+		if err := repos.Transactions.ResolvePending(ctx, tx.ID, "completed"); err != nil {
+			return err
+		}
+		if recordErr = recordPending(repos, record); recordErr != nil {
+			return recordErr
+		}
+		if err := captureBound(ctx, repos, tx); err != nil {
+			return err
+		}
+		if err := moveFunds(ctx, repos, tx); err != nil {
+			return err
+		}
+		if tx.FeeScheduleID != nil {
+			return repos.Transactions.SetFee(ctx, tx.ID, tx.Fee, tx.FeeScheduleID)
+		}
+		return nil
+	})
+	if errors.Is(err, domain.ErrTransactionNotPending) || recordErr != nil {
 		return err
 	}
-	if err := s.balRepo.Update(toBal); err != nil {
-		// Record transaction failure
-		s.recordTransactionMetrics("transfer", amount, false)
+	s.recordTransactionMetrics(tx.Type, tx.Amount, err == nil)
+	defer invalidateUsers(ctx, s.cache, tx.FromUserID, tx.ToUserID)
+	if err == nil {
+		tx.Status = "completed"
+		s.notifyCompleted(ctx, tx)
+		return nil
+	}
+
+	failErr := s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+		if err := repos.Transactions.ResolvePending(ctx, tx.ID, "failed"); err != nil {
+			return err
+		}
+		if err := repos.Holds.Unbind(ctx, tx.ID); err != nil {
+			return err
+		}
+		return recordPending(repos, record)
+	})
+	if failErr != nil {
+		if errors.Is(failErr, domain.ErrTransactionNotPending) || errors.Is(failErr, domain.ErrApprovalAlreadyReviewed) {
+			return failErr
+		}
 		return err
 	}
-	tx := &domain.Transaction{
-		FromUserID: &fromUserID,
-		ToUserID:   &toUserID,
-		Amount:     amount,
-		Type:       "transfer",
-		Status:     "completed",
+	tx.Status = "failed"
+	return err
+}
+
+// RejectPending rejects a transaction recorded as "pending_approval" without moving money.
+func (s *TransactionServiceImpl) RejectPending(ctx context.Context, tx *domain.Transaction, record func(repos domain.UnitOfWorkRepositories) error) error {
+	err := s.uow.Do(ctx, func(repos domain.UnitOfWorkRepositories) error {
+		if err := repos.Transactions.ResolvePending(ctx, tx.ID, "rejected"); err != nil {
+			return err
+		}
+		if err := repos.Holds.Unbind(ctx, tx.ID); err != nil {
+			return err
+		}
+		return recordPending(repos, record)
+	})
+	if err != nil {
+		return err
 	}
-	if err := s.txRepo.Create(tx); err != nil {
-		// Record transaction failure
-		s.recordTransactionMetrics("transfer", amount, false)
+	tx.Status = "rejected"
+	invalidateUsers(ctx, s.cache, tx.FromUserID, tx.ToUserID)
+	return nil
+}
+
+// recordPending runs the record of a pending transaction's resolution, if any
+func recordPending(repos domain.UnitOfWorkRepositories, record func(repos domain.UnitOfWorkRepositories) error) error {
+	if record == nil {
+		return nil
+	}
+	return record(repos)
+}
+
+// maxBalanceConflictRetries bounds how often a balance change is re-run.
+const maxBalanceConflictRetries = 5
+
+// retryOnBalanceConflict runs fn again while it fails with domain.ErrBalanceConflict.
+func retryOnBalanceConflict(fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxBalanceConflictRetries; attempt++ {
+		err = fn()
+		if !errors.Is(err, domain.ErrBalanceConflict) {
+			return err
+		}
+		metrics.BalanceUpdateConflicts.Inc()
+		time.Sleep(time.Duration(attempt) * time.Duration(1+rand.Intn(5)) * time.Millisecond)
+	}
+	return err
+}
+
+// limitCurrency is the currency money movements are recorded in against limit rules
+const limitCurrency = "USD"
+
+// checkLimits applies the paying user's limit rules and records amount against them.
+func checkLimits(ctx context.Context, limits domain.TransactionLimitRepository, userID int, amount float64) error {
+	if limits == nil {
+		return nil
+	}
+	return limits.CheckAndRecordTransaction(ctx, userID, amount, limitCurrency, time.Now())
+}
+
+// moveFunds applies tx to its users' balances in a unit of work.
+func moveFunds(ctx context.Context, repos domain.UnitOfWorkRepositories, tx *domain.Transaction) error {
+	switch {
+	case tx.Type == "credit" && tx.ToUserID != nil:
+		net := tx.Amount - tx.Fee
+		if net < 0 {
+			// A fee above the amount comes out of the balance
+			return debitBalance(ctx, repos.Balances, *tx.ToUserID, -net)
+		}
+		return creditBalance(ctx, repos.Balances, *tx.ToUserID, net)
+	case tx.Type == "debit" && tx.FromUserID != nil:
+		if err := checkLimits(ctx, repos.Limits, *tx.FromUserID, tx.Amount+tx.Fee); err != nil {
+			return err
+		}
+		return debitBalance(ctx, repos.Balances, *tx.FromUserID, tx.Amount+tx.Fee)
+	case tx.Type == "transfer" && tx.FromUserID != nil && tx.ToUserID != nil:
+		if err := checkLimits(ctx, repos.Limits, *tx.FromUserID, tx.Amount+tx.Fee); err != nil {
+			return err
+		}
+		return transferBalance(ctx, repos.Balances, *tx.FromUserID, *tx.ToUserID, tx.Amount, tx.Fee)
+	default:
+		return errors.New("invalid transaction")
+	}
+}
+
+// creditBalance adds amount to a user's balance, creating the balance if needed.
+func creditBalance(ctx context.Context, balRepo domain.BalanceRepository, userID int, amount float64) error {
+	bal, err := balRepo.GetByUserID(ctx, userID)
+	if err != nil {
 		return err
 	}
+	if bal == nil {
+		bal = &domain.Balance{UserID: userID, Amount: 0}
+	}
+	bal.Amount += amount
+	return balRepo.Update(ctx, bal)
+}
 
-	// Record successful transaction
-	s.recordTransactionMetrics("transfer", amount, true)
+// debitBalance subtracts amount from a user's available balance, unless it is frozen.
+func debitBalance(ctx context.Context, balRepo domain.BalanceRepository, userID int, amount float64) error {
+	bal, err := balRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if bal != nil && bal.IsFrozen() {
+		return domain.ErrAccountFrozen
+	}
+	if bal == nil || bal.AvailableAmount() < amount {
+		return domain.ErrInsufficientFunds
+	}
+	bal.Amount -= amount
+	return balRepo.Update(ctx, bal)
+}
 
-	return nil
+// transferBalance moves amount between two users' balances, charging the payer fee on top.
+func transferBalance(ctx context.Context, balRepo domain.BalanceRepository, fromUserID, toUserID int, amount, fee float64) error {
+	fromBal, err := balRepo.GetByUserID(ctx, fromUserID)
+	if err != nil {
+		return err
+	}
+	if fromBal != nil && fromBal.IsFrozen() {
+		return domain.ErrAccountFrozen
+	}
+	if fromBal == nil || fromBal.AvailableAmount() < amount+fee {
+		return domain.ErrInsufficientFunds
+	}
+	toBal, err := balRepo.GetByUserID(ctx, toUserID)
+	if err != nil {
+		return err
+	}
+	if toBal == nil {
+		toBal = &domain.Balance{UserID: toUserID, Amount: 0}
+	}
+	fromBal.Amount -= amount + fee
+	toBal.Amount += amount
+
+	first, second := fromBal, toBal
+	if toUserID < fromUserID {
+		first, second = toBal, fromBal
+	}
+	if err := balRepo.Update(ctx, first); err != nil {
+		return err
+	}
+	return balRepo.Update(ctx, second)
 }
 
 // GetTransaction returns a transaction by ID.
-func (s *TransactionServiceImpl) GetTransaction(id int) (*domain.Transaction, error) {
-	return s.txRepo.GetByID(id)
+func (s *TransactionServiceImpl) GetTransaction(ctx context.Context, id int) (*domain.Transaction, error) {
+	return s.txRepo.GetByID(ctx, id)
 }
 
-// ListUserTransactions returns all transactions for a user.
-func (s *TransactionServiceImpl) ListUserTransactions(userID int) ([]*domain.Transaction, error) {
-	return s.txRepo.ListByUser(userID)
+// ListUserTransactions returns a page of a user's transactions, newest first.
+func (s *TransactionServiceImpl) ListUserTransactions(ctx context.Context, userID int, limit, offset int) ([]*domain.Transaction, error) {
+	return s.txRepo.ListByUser(ctx, userID, limit, offset)
+}
+
+// CountUserTransactions returns how many transactions a user has.
+func (s *TransactionServiceImpl) CountUserTransactions(ctx context.Context, userID int) (int, error) {
+	return s.txRepo.CountByUser(ctx, userID)
 }
 
 // ListAllTransactions returns all transactions.
 func (s *TransactionServiceImpl) ListAllTransactions(ctx context.Context, limit int, offset int) ([]*domain.Transaction, error) {
 	return s.txRepo.ListAll(ctx, limit, offset)
 }
+
+// SearchTransactions returns a page of the transactions matching filter, newest first.
+func (s *TransactionServiceImpl) SearchTransactions(ctx context.Context, filter domain.TransactionFilter) ([]*domain.Transaction, error) {
+	return s.txRepo.Search(ctx, filter)
+}
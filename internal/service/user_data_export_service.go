@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// maxExportedLogins bounds the login history included in an export
+const maxExportedLogins = 1000
+
+// UserDataExportServiceImpl implements domain.UserDataExportService.
+type UserDataExportServiceImpl struct {
+	users         domain.UserRepository
+	preferences   domain.UserPreferenceRepository
+	notifications domain.NotificationService
+	audit         domain.AuditLogService
+}
+
+// NewUserDataExportService creates a new UserDataExportServiceImpl.
+func NewUserDataExportService(users domain.UserRepository, preferences domain.UserPreferenceRepository, notifications domain.NotificationService, audit domain.AuditLogService) *UserDataExportServiceImpl {
+	return &UserDataExportServiceImpl{users: users, preferences: preferences, notifications: notifications, audit: audit}
+}
+
+// ExportUserData gathers the user's account, profile, preferences and recent login history.
+func (s *UserDataExportServiceImpl) ExportUserData(ctx context.Context, actorID, userID int) (*domain.UserDataExport, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, nil
+	}
+	if user.IsErased() {
+		return nil, domain.ErrUserErased
+	}
+
+	prefs, err := s.preferences.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+	notificationPrefs, err := s.notifications.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	logins, err := s.users.ListLoginEvents(ctx, userID, maxExportedLogins, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login history: %w", err)
+	}
+	devices, err := s.users.ListDevices(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trusted devices: %w", err)
+	}
+
+	export := &domain.UserDataExport{
+		ExportedAt: time.Now().UTC(),
+		Account: domain.UserDataAccount{
+			ID:          user.ID,
+			Username:    user.Username,
+			Email:       user.Email,
+			Role:        user.Role,
+			FirstName:   user.FirstName,
+			LastName:    user.LastName,
+			Phone:       user.Phone,
+			Locale:      user.Locale,
+			HasAvatar:   user.AvatarKey != "",
+			CreatedAt:   user.CreatedAt,
+			LastLoginAt: user.LastLoginAt,
+		},
+		Preferences:             prefs,
+		NotificationPreferences: notificationPrefs,
+		Logins:                  logins,
+		Devices:                 devices,
+	}
+
+	if err := s.audit.Record(ctx, &actorID, "user", userID, "export_data", ""); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("user_id", userID).Int("actor_id", actorID).Msg("Failed to audit user data export")
+	}
+	return export, nil
+}
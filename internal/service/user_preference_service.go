@@ -0,0 +1,85 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// UserPreferenceServiceImpl implements domain.UserPreferenceService.
+type UserPreferenceServiceImpl struct {
+	repo domain.UserPreferenceRepository
+}
+
+// NewUserPreferenceService creates a new UserPreferenceServiceImpl.
+func NewUserPreferenceService(repo domain.UserPreferenceRepository) *UserPreferenceServiceImpl {
+	return &UserPreferenceServiceImpl{repo: repo}
+}
+
+// GetPreferences returns the preferences a user has set by key.
+func (s *UserPreferenceServiceImpl) GetPreferences(ctx context.Context, userID int) (map[string]json.RawMessage, error) {
+	return s.repo.List(ctx, userID)
+}
+
+// UpdatePreferences validates every change before storing any.
+func (s *UserPreferenceServiceImpl) UpdatePreferences(ctx context.Context, userID int, changes map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	set := make(map[string]json.RawMessage, len(changes))
+	var remove []string
+	for key, value := range changes {
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			remove = append(remove, key)
+			continue
+		}
+		if err := domain.ValidatePreference(key, value); err != nil {
+			return nil, err
+		}
+		set[key] = value
+	}
+
+	current, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	count := len(current)
+	for key := range set {
+		if _, ok := current[key]; !ok {
+			count++
+		}
+	}
+	for _, key := range remove {
+		if _, ok := current[key]; ok {
+			count--
+		}
+	}
+	if count > domain.MaxUserPreferences {
+		return nil, domain.ErrTooManyPreferences
+	}
+
+	if err := s.repo.Update(ctx, userID, set, remove); err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, userID)
+}
+
+// SetPreference stores one preference; removing one takes DeletePreference.
+func (s *UserPreferenceServiceImpl) SetPreference(ctx context.Context, userID int, key string, value json.RawMessage) error {
+	if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+		return &domain.ValidationError{Msg: "preference value must not be null"}
+	}
+	_, err := s.UpdatePreferences(ctx, userID, map[string]json.RawMessage{key: value})
+	return err
+}
+
+// DeletePreference removes one preference.
+func (s *UserPreferenceServiceImpl) DeletePreference(ctx context.Context, userID int, key string) error {
+	deleted, err := s.repo.Delete(ctx, userID, key)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return domain.ErrPreferenceNotFound
+	}
+	return nil
+}
@@ -1,94 +1,592 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
 	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
 	"strings"
+	"time"
 
-	"golang.org/x/crypto/bcrypt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 
 	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/cache"
 	"github.com/melihgurlek/backend-path/pkg/metrics"
 )
 
 // UserServiceImpl implements domain.UserService.
 type UserServiceImpl struct {
-	repo domain.UserRepository
+	repo      domain.UserRepository
+	audit     domain.AuditLogService
+	policy    domain.PasswordPolicy
+	hasher    domain.PasswordHasher
+	cache     domain.CacheInvalidator
+	notifier  domain.Notifier
+	avatars   domain.ObjectStore
+	security  LoginSecurity
+	referrals domain.ReferralAttributor
+}
+
+// LoginSecurity configures the checks logins go through beyond the password.
+type LoginSecurity struct {
+	VerifyDevices bool
+	Locator       domain.GeoLocator
+	Travel        domain.TravelPolicy
 }
 
 // NewUserService creates a new UserServiceImpl.
-func NewUserService(repo domain.UserRepository) *UserServiceImpl {
-	return &UserServiceImpl{repo: repo}
+func NewUserService(repo domain.UserRepository, audit domain.AuditLogService, policy domain.PasswordPolicy, hasher domain.PasswordHasher, cache domain.CacheInvalidator, notifier domain.Notifier, avatars domain.ObjectStore, security LoginSecurity, referrals domain.ReferralAttributor) *UserServiceImpl {
+	return &UserServiceImpl{repo: repo, audit: audit, policy: policy, hasher: hasher, cache: cache, notifier: notifier, avatars: avatars,
+		security: security, referrals: referrals}
 }
 
 // Register creates a new user with hashed password after validation.
-func (s *UserServiceImpl) Register(username, email, password string) (*domain.User, error) {
+func (s *UserServiceImpl) Register(ctx context.Context, username, email, password, referralCode string) (*domain.User, error) {
 	username = strings.TrimSpace(username)
 	email = strings.TrimSpace(email)
 	if username == "" || email == "" || password == "" {
-		return nil, errors.New("username, email, and password are required")
+		return nil, &domain.ValidationError{Msg: "username, email, and password are required"}
+	}
+	if err := s.policy.Check(password, username, email); err != nil {
+		return nil, err
 	}
-	if existing, _ := s.repo.GetByUsername(username); existing != nil {
-		return nil, errors.New("username already exists")
+	referrerID, err := s.referrer(ctx, referralCode)
+	if err != nil {
+		return nil, err
 	}
-	if existing, _ := s.repo.GetByEmail(email); existing != nil {
-		return nil, errors.New("email already exists")
+	if existing, _ := s.repo.GetByUsername(ctx, username); existing != nil {
+		return nil, domain.ErrUsernameTaken
 	}
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if existing, _ := s.repo.GetByEmail(ctx, email); existing != nil {
+		return nil, domain.ErrEmailTaken
+	}
+	hash, err := s.hasher.Hash(password)
 	if err != nil {
 		return nil, errors.New("failed to hash password")
 	}
 	user := &domain.User{
 		Username:     username,
 		Email:        email,
-		PasswordHash: string(hash),
+		PasswordHash: hash,
 		Role:         "user",
 	}
-	if err := s.repo.Create(user); err != nil {
+	if err := s.repo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 
+	// The user exists either way, so a failed attribution is only logged
+	if referrerID != 0 {
+		if err := s.referrals.Attribute(ctx, referrerID, user.ID); err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("user_id", user.ID).Int("referrer_id", referrerID).Msg("Failed to attribute referral")
+		}
+	}
+
 	// Record business metrics
 	metrics.UserRegistrationTotal.Inc()
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, user.ID, domain.EventUserRegistered, nil)
+	}
 
 	return user, nil
 }
 
+// referrer returns the ID of the user whose referral code it is, or 0 for no code.
+func (s *UserServiceImpl) referrer(ctx context.Context, code string) (int, error) {
+	if strings.TrimSpace(code) == "" {
+		return 0, nil
+	}
+	if s.referrals == nil {
+		return 0, domain.ErrReferralCodeInvalid
+	}
+	return s.referrals.Referrer(ctx, code)
+}
+
 // Login checks username and password, returns user if valid.
-func (s *UserServiceImpl) Login(username, password string) (*domain.User, error) {
-	user, err := s.repo.GetByUsername(username)
-	if err != nil || user == nil {
+func (s *UserServiceImpl) Login(ctx context.Context, username, password string, client domain.LoginClient) (*domain.User, error) {
+	client.Location = s.locate(client.IP)
+	user, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
 		// Record failed login
 		metrics.UserLoginTotal.WithLabelValues("failure").Inc()
-		return nil, errors.New("invalid username or password")
+		s.recordLoginEvent(ctx, nil, username, client, domain.LoginFailureUnknownUser, false)
+		return nil, domain.ErrInvalidCredentials
 	}
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+	// An unrecognized hash (e.g. of an erased user) is treated like a wrong password
+	if ok, _ := s.hasher.Verify(user.PasswordHash, password); !ok {
 		// Record failed login
 		metrics.UserLoginTotal.WithLabelValues("failure").Inc()
-		return nil, errors.New("invalid username or password")
+		s.recordLoginEvent(ctx, &user.ID, username, client, domain.LoginFailureWrongPassword, false)
+		return nil, domain.ErrInvalidCredentials
 	}
 
-	// Record successful login
-	metrics.UserLoginTotal.WithLabelValues("success").Inc()
+	trusted, err := s.deviceTrusted(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	travel := s.checkTravel(ctx, user, client)
+	if !trusted || (travel != nil && s.canStepUp()) {
+		// The password is right, so it can be upgraded before the login completes
+		s.rehashIfNeeded(ctx, user, password)
+		reason := ""
+		if travel != nil {
+			reason = fmt.Sprintf("Someone is signing in to your account from %s, %.0f km from %s where you last signed in %s ago.",
+				travel.To, travel.DistanceKm, travel.From, formatElapsed(travel.Elapsed))
+		}
+		return nil, s.startStepUp(ctx, user, client, reason, travel != nil)
+	}
 
+	if travel != nil {
+		s.notifySuspiciousLogin(ctx, user, client, travel)
+	}
+	s.completeLogin(ctx, user, client, travel != nil)
+	s.rehashIfNeeded(ctx, user, password)
 	return user, nil
 }
 
+// locate returns where ip is, or nil if it is unknown or no locator is set
+func (s *UserServiceImpl) locate(ip string) *domain.GeoLocation {
+	if s.security.Locator == nil {
+		return nil
+	}
+	return s.security.Locator.Locate(ip)
+}
+
+// canStepUp reports whether logins can be held back for verification
+func (s *UserServiceImpl) canStepUp() bool {
+	return s.security.VerifyDevices && s.notifier != nil
+}
+
+// checkTravel returns the impossible travel a login implies, if any.
+func (s *UserServiceImpl) checkTravel(ctx context.Context, user *domain.User, client domain.LoginClient) *domain.ImpossibleTravel {
+	if client.Location == nil {
+		return nil
+	}
+	prev, err := s.repo.LastLocatedLogin(ctx, user.ID)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int("user_id", user.ID).Msg("Failed to get previous login location")
+		return nil
+	}
+	if prev == nil {
+		return nil
+	}
+	travel := s.security.Travel.Check(prev.Location, prev.CreatedAt, client.Location, time.Now().UTC())
+	if travel == nil {
+		return nil
+	}
+
+	metrics.UserLoginTotal.WithLabelValues("impossible_travel").Inc()
+	log.Ctx(ctx).Warn().Int("user_id", user.ID).Str("from", travel.From.String()).Str("to", travel.To.String()).
+		Float64("speed_kmh", travel.SpeedKmh).Msg("Login implies impossible travel")
+	details := fmt.Sprintf("from=%s to=%s distance_km=%.0f speed_kmh=%.0f elapsed=%s ip=%s",
+		travel.From, travel.To, travel.DistanceKm, travel.SpeedKmh, formatElapsed(travel.Elapsed), client.IP)
+	if err := s.audit.Record(ctx, nil, "user", user.ID, "impossible_travel", details); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int("user_id", user.ID).Msg("Failed to audit impossible travel")
+	}
+	return travel
+}
+
+// notifySuspiciousLogin tells the user about a login implying impossible travel.
+func (s *UserServiceImpl) notifySuspiciousLogin(ctx context.Context, user *domain.User, client domain.LoginClient, travel *domain.ImpossibleTravel) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.Notify(ctx, user.ID, domain.EventSuspiciousLogin, map[string]string{
+		"location":          travel.To.String(),
+		"previous_location": travel.From.String(),
+		"distance_km":       fmt.Sprintf("%.0f", travel.DistanceKm),
+		"elapsed":           formatElapsed(travel.Elapsed),
+		"ip":                client.IP,
+		"user_agent":        client.UserAgent,
+		"time":              time.Now().UTC().Format(time.RFC1123),
+	})
+}
+
+// formatElapsed rounds the time between two logins for people to read
+func formatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return "less than a minute"
+	}
+	return d.Round(time.Minute).String()
+}
+
+// deviceTrusted reports whether the user may log in from the client's device without a code.
+func (s *UserServiceImpl) deviceTrusted(ctx context.Context, user *domain.User, client domain.LoginClient) (bool, error) {
+	if !s.canStepUp() {
+		return true, nil
+	}
+	device, err := s.repo.GetDevice(ctx, user.ID, client.Fingerprint())
+	if err != nil {
+		return false, fmt.Errorf("failed to check login device: %w", err)
+	}
+	if device != nil {
+		return true, nil
+	}
+	hasDevices, err := s.repo.HasDevices(ctx, user.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check login device: %w", err)
+	}
+	return !hasDevices, nil
+}
+
+// startStepUp holds back a login and emails the user a verification code for it.
+func (s *UserServiceImpl) startStepUp(ctx context.Context, user *domain.User, client domain.LoginClient, reason string, suspicious bool) error {
+	code, err := newLoginCode()
+	if err != nil {
+		return fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	challenge := &domain.LoginChallenge{
+		ID:          uuid.NewString(),
+		UserID:      user.ID,
+		Fingerprint: client.Fingerprint(),
+		UserAgent:   client.UserAgent,
+		ExpiresAt:   time.Now().UTC().Add(domain.LoginChallengeTTL),
+	}
+	challenge.CodeHash = domain.HashLoginCode(challenge.ID, code)
+	if err := s.repo.CreateLoginChallenge(ctx, challenge); err != nil {
+		return fmt.Errorf("failed to create login challenge: %w", err)
+	}
+
+	metrics.UserLoginTotal.WithLabelValues("step_up").Inc()
+	s.recordLoginEvent(ctx, &user.ID, user.Username, client, domain.LoginFailureStepUpRequired, suspicious)
+	s.notifier.Notify(ctx, user.ID, domain.EventLoginVerification, map[string]string{
+		"reason":     reason,
+		"code":       code,
+		"expires_in": fmt.Sprintf("%d minutes", int(domain.LoginChallengeTTL.Minutes())),
+		"ip":         client.IP,
+		"user_agent": client.UserAgent,
+		"time":       time.Now().UTC().Format(time.RFC1123),
+	})
+	return &domain.StepUpRequiredError{
+		ChallengeID: challenge.ID,
+		Method:      domain.StepUpMethodEmail,
+		ExpiresAt:   challenge.ExpiresAt,
+	}
+}
+
+// newLoginCode returns a random numeric verification code
+func newLoginCode() (string, error) {
+	bound := big.NewInt(1)
+	for i := 0; i < domain.LoginChallengeCodeDigits; i++ {
+		bound.Mul(bound, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, bound)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", domain.LoginChallengeCodeDigits, n), nil
+}
+
+// VerifyLogin completes a login held back by step-up verification.
+func (s *UserServiceImpl) VerifyLogin(ctx context.Context, challengeID, code string, client domain.LoginClient) (*domain.User, error) {
+	if _, err := uuid.Parse(challengeID); err != nil {
+		return nil, domain.ErrLoginChallengeInvalid
+	}
+	client.Location = s.locate(client.IP)
+	challenge, err := s.repo.UseLoginChallengeAttempt(ctx, challengeID, domain.MaxLoginChallengeAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login challenge: %w", err)
+	}
+	if challenge == nil || challenge.Fingerprint != client.Fingerprint() {
+		return nil, domain.ErrLoginChallengeInvalid
+	}
+	user, err := s.repo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.IsErased() {
+		return nil, domain.ErrLoginChallengeInvalid
+	}
+
+	want := domain.HashLoginCode(challenge.ID, strings.TrimSpace(code))
+	if subtle.ConstantTimeCompare([]byte(want), []byte(challenge.CodeHash)) != 1 {
+		metrics.UserLoginTotal.WithLabelValues("failure").Inc()
+		s.recordLoginEvent(ctx, &user.ID, user.Username, client, domain.LoginFailureWrongCode, false)
+		return nil, domain.ErrWrongLoginCode
+	}
+	// Of concurrent right answers only the one that deletes the challenge wins
+	deleted, err := s.repo.DeleteLoginChallenge(ctx, challenge.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete login challenge: %w", err)
+	}
+	if !deleted {
+		return nil, domain.ErrLoginChallengeInvalid
+	}
+
+	s.completeLogin(ctx, user, client, false)
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, user.ID, domain.EventNewDeviceLogin, map[string]string{
+			"ip":         client.IP,
+			"user_agent": client.UserAgent,
+			"time":       time.Now().UTC().Format(time.RFC1123),
+		})
+	}
+	return user, nil
+}
+
+// completeLogin records a successful login and trusts its device.
+func (s *UserServiceImpl) completeLogin(ctx context.Context, user *domain.User, client domain.LoginClient, suspicious bool) {
+	metrics.UserLoginTotal.WithLabelValues("success").Inc()
+	s.recordLoginEvent(ctx, &user.ID, user.Username, client, "", suspicious)
+	if err := s.repo.RecordLogin(ctx, user.ID); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int("user_id", user.ID).Msg("Failed to record login time")
+	}
+	if err := s.repo.TrustDevice(ctx, user.ID, client.Fingerprint(), client.UserAgent); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int("user_id", user.ID).Msg("Failed to record login device")
+	}
+}
+
+// recordLoginEvent adds a login attempt to the history; an empty failureReason means it succeeded.
+func (s *UserServiceImpl) recordLoginEvent(ctx context.Context, userID *int, username string, client domain.LoginClient, failureReason string, suspicious bool) {
+	event := &domain.LoginEvent{
+		UserID:        userID,
+		Username:      username,
+		IP:            client.IP,
+		UserAgent:     client.UserAgent,
+		Location:      client.Location,
+		Success:       failureReason == "",
+		FailureReason: failureReason,
+		Suspicious:    suspicious,
+	}
+	if err := s.repo.RecordLoginEvent(ctx, event); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("username", username).Msg("Failed to record login event")
+	}
+}
+
+// rehashIfNeeded upgrades a password hash made with a legacy algorithm or parameters.
+func (s *UserServiceImpl) rehashIfNeeded(ctx context.Context, user *domain.User, password string) {
+	if !s.hasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int("user_id", user.ID).Msg("Failed to rehash password")
+		return
+	}
+	if err := s.repo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int("user_id", user.ID).Msg("Failed to store rehashed password")
+		return
+	}
+	user.PasswordHash = hash
+	log.Ctx(ctx).Debug().Int("user_id", user.ID).Msg("Upgraded password hash")
+}
+
 // GetUser returns a user by ID.
-func (s *UserServiceImpl) GetUser(id int) (*domain.User, error) {
-	return s.repo.GetByID(id)
+func (s *UserServiceImpl) GetUser(ctx context.Context, id int) (*domain.User, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
-// ListUsers returns all users.
-func (s *UserServiceImpl) ListUsers() ([]*domain.User, error) {
-	return s.repo.List()
+// ListUsers returns a page of the users matching filter.
+func (s *UserServiceImpl) ListUsers(ctx context.Context, filter domain.UserFilter) ([]*domain.User, error) {
+	return s.repo.List(ctx, filter)
 }
 
-// UpdateUser updates a user (does not change password).
-func (s *UserServiceImpl) UpdateUser(user *domain.User) error {
-	return s.repo.Update(user)
+// CountUsers returns how many users match filter.
+func (s *UserServiceImpl) CountUsers(ctx context.Context, filter domain.UserFilter) (int, error) {
+	return s.repo.Count(ctx, filter)
+}
+
+// UpdateUser updates a user's account and profile fields (does not change password or avatar).
+func (s *UserServiceImpl) UpdateUser(ctx context.Context, user *domain.User) error {
+	if err := user.ValidateProfile(); err != nil {
+		return err
+	}
+	if err := s.repo.Update(ctx, user); err != nil {
+		return err
+	}
+	invalidateUsers(ctx, s.cache, &user.ID)
+	return nil
 }
 
 // DeleteUser deletes a user by ID.
-func (s *UserServiceImpl) DeleteUser(id int) error {
-	return s.repo.Delete(id)
+func (s *UserServiceImpl) DeleteUser(ctx context.Context, id int) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	invalidateUsers(ctx, s.cache, &id)
+	return nil
+}
+
+// ChangePassword replaces a user's password after verifying the current one.
+func (s *UserServiceImpl) ChangePassword(ctx context.Context, id int, currentPassword, newPassword string) error {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return domain.ErrUserNotFound
+	}
+	if user.IsErased() {
+		return domain.ErrUserErased
+	}
+	if ok, _ := s.hasher.Verify(user.PasswordHash, currentPassword); !ok {
+		return domain.ErrWrongPassword
+	}
+	if currentPassword == newPassword {
+		return &domain.ValidationError{Msg: "new password must differ from the current one"}
+	}
+	if err := s.policy.Check(newPassword, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+	if err := s.repo.UpdatePassword(ctx, id, hash); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.audit.Record(ctx, &id, "user", id, "change_password", ""); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("user_id", id).Msg("Failed to audit password change")
+	}
+	return nil
+}
+
+// EraseUser anonymizes a user and deletes their profile and avatar.
+func (s *UserServiceImpl) EraseUser(ctx context.Context, id, actorID int) (*domain.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, nil
+	}
+	avatarKey := user.AvatarKey
+	if err := user.Anonymize(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Anonymize(ctx, user); err != nil {
+		return nil, err
+	}
+	s.deleteAvatarObject(ctx, id, avatarKey)
+
+	// Other users (admins) may have cached responses showing this user's data,
+	// so every cached response is dropped, not just the user's own
+	if s.cache != nil {
+		if err := s.cache.DeletePattern(ctx, cache.AllResponsesPattern()); err != nil {
+			log.Ctx(ctx).Error().Err(err).Int("user_id", id).Msg("Failed to purge cached responses of erased user")
+		}
+	}
+
+	// No PII in the details; the point of the record is that erasure happened
+	if err := s.audit.Record(ctx, &actorID, "user", id, "erase", "personal data anonymized"); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("user_id", id).Int("actor_id", actorID).Msg("Failed to audit user erasure")
+	}
+	log.Ctx(ctx).Info().Int("user_id", id).Int("actor_id", actorID).Msg("User personal data erased")
+	return user, nil
+}
+
+// ListLogins returns a page of a user's login attempts, newest first.
+func (s *UserServiceImpl) ListLogins(ctx context.Context, userID, limit, offset int) ([]*domain.LoginEvent, error) {
+	return s.repo.ListLoginEvents(ctx, userID, limit, offset)
+}
+
+// ListDevices returns a user's trusted devices, most recently used first.
+func (s *UserServiceImpl) ListDevices(ctx context.Context, userID int) ([]*domain.Device, error) {
+	return s.repo.ListDevices(ctx, userID)
+}
+
+// RevokeDevice stops trusting a device of a user.
+func (s *UserServiceImpl) RevokeDevice(ctx context.Context, userID int, fingerprint string, actorID int) error {
+	if err := s.repo.DeleteDevice(ctx, userID, fingerprint); err != nil {
+		return err
+	}
+	if err := s.audit.Record(ctx, &actorID, "user", userID, "revoke_device", "fingerprint="+fingerprint); err != nil {
+		log.Ctx(ctx).Error().Err(err).Int("user_id", userID).Int("actor_id", actorID).Msg("Failed to audit device revocation")
+	}
+	return nil
+}
+
+// CountLogins counts a user's login attempts.
+func (s *UserServiceImpl) CountLogins(ctx context.Context, userID int) (int, error) {
+	return s.repo.CountLoginEvents(ctx, userID)
+}
+
+// SetAvatar replaces a user's avatar with data, an image of at most MaxAvatarBytes.
+func (s *UserServiceImpl) SetAvatar(ctx context.Context, id int, data []byte) (*domain.User, error) {
+	if len(data) > domain.MaxAvatarBytes {
+		return nil, &domain.ValidationError{Msg: fmt.Sprintf("avatar must be at most %d bytes", domain.MaxAvatarBytes)}
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := domain.AvatarExtensions[contentType]
+	if !ok {
+		return nil, &domain.ValidationError{Msg: "avatar must be a PNG, JPEG, GIF or WebP image"}
+	}
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, nil
+	}
+	if user.IsErased() {
+		return nil, domain.ErrUserErased
+	}
+
+	key := fmt.Sprintf("avatars/%d/%s%s", id, uuid.NewString(), ext)
+	if err := s.avatars.Put(ctx, key, contentType, data); err != nil {
+		return nil, fmt.Errorf("failed to store avatar: %w", err)
+	}
+	if err := s.repo.UpdateAvatar(ctx, id, key); err != nil {
+		s.deleteAvatarObject(ctx, id, key)
+		return nil, fmt.Errorf("failed to update avatar: %w", err)
+	}
+	s.deleteAvatarObject(ctx, id, user.AvatarKey)
+	user.AvatarKey = key
+	invalidateUsers(ctx, s.cache, &id)
+	return user, nil
+}
+
+// GetAvatar returns a user's avatar image and its content type.
+func (s *UserServiceImpl) GetAvatar(ctx context.Context, id int) ([]byte, string, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.AvatarKey == "" {
+		return nil, "", domain.ErrAvatarNotFound
+	}
+	data, contentType, err := s.avatars.Get(ctx, user.AvatarKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read avatar: %w", err)
+	}
+	if data == nil {
+		return nil, "", domain.ErrAvatarNotFound
+	}
+	return data, contentType, nil
+}
+
+// DeleteAvatar removes a user's avatar.
+func (s *UserServiceImpl) DeleteAvatar(ctx context.Context, id int) error {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.AvatarKey == "" {
+		return domain.ErrAvatarNotFound
+	}
+	if err := s.repo.UpdateAvatar(ctx, id, ""); err != nil {
+		return fmt.Errorf("failed to update avatar: %w", err)
+	}
+	s.deleteAvatarObject(ctx, id, user.AvatarKey)
+	invalidateUsers(ctx, s.cache, &id)
+	return nil
+}
+
+// deleteAvatarObject deletes an avatar image no longer referenced by its user.
+func (s *UserServiceImpl) deleteAvatarObject(ctx context.Context, id int, key string) {
+	if key == "" || s.avatars == nil {
+		return
+	}
+	if err := s.avatars.Delete(ctx, key); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int("user_id", id).Str("key", key).Msg("Failed to delete avatar image")
+	}
 }
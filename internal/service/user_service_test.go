@@ -1,15 +1,20 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/melihgurlek/backend-path/internal/domain"
 	"github.com/melihgurlek/backend-path/internal/repository"
+	"github.com/melihgurlek/backend-path/pkg/crypto"
+	"github.com/melihgurlek/backend-path/pkg/password"
 )
 
-// getTestPool returns a pgxpool.Pool for testing, using the DB_URL env var or a default.
+// getTestPool returns a pgxpool.Pool for testing, using the DB_URL env var or
+// a default. Tests needing the database are skipped when it can't be reached.
 func getTestPool(t *testing.T) *pgxpool.Pool {
 	dbURL := os.Getenv("DB_URL")
 	if dbURL == "" {
@@ -23,20 +28,30 @@ func getTestPool(t *testing.T) *pgxpool.Pool {
 	if err != nil {
 		t.Fatalf("failed to connect to db: %v", err)
 	}
+	if err := pool.Ping(context.Background()); err != nil {
+		pool.Close()
+		t.Skipf("database unavailable: %v", err)
+	}
 	return pool
 }
 
 func TestUserServiceImpl_RegisterAndLogin(t *testing.T) {
+	ctx := context.Background()
 	pool := getTestPool(t)
-	repo := repository.NewUserPostgresRepository(pool) // This already implements domain.UserRepository
-	service := NewUserService(repo)
+	key := bytes.Repeat([]byte{0x42}, 32)
+	keys, err := crypto.NewKeyring("test", map[string][]byte{"test": key}, key)
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	repo := repository.NewUserPostgresRepository(pool, keys) // This already implements domain.UserRepository
+	service := NewUserService(repo, NewAuditLogService(repository.NewAuditLogPostgresRepository(pool)), domain.PasswordPolicy{}, password.NewChain(password.NewArgon2idHasher(password.Argon2idParams{}), password.NewBcryptHasher(0)), nil, nil, nil, LoginSecurity{}, nil)
 	defer func() {
 		pool.Exec(context.Background(), "DELETE FROM users WHERE username = 'servicetestuser'")
 		pool.Close()
 	}()
 
 	// Test Register
-	user, err := service.Register("servicetestuser", "servicetestuser@example.com", "password123")
+	user, err := service.Register(ctx, "servicetestuser", "servicetestuser@example.com", "password123", "")
 	if err != nil {
 		t.Fatalf("Register failed: %v", err)
 	}
@@ -45,19 +60,19 @@ func TestUserServiceImpl_RegisterAndLogin(t *testing.T) {
 	}
 
 	// Test duplicate username
-	_, err = service.Register("servicetestuser", "other@example.com", "password123")
+	_, err = service.Register(ctx, "servicetestuser", "other@example.com", "password123", "")
 	if err == nil {
 		t.Error("expected error for duplicate username, got nil")
 	}
 
 	// Test duplicate email
-	_, err = service.Register("otheruser", "servicetestuser@example.com", "password123")
+	_, err = service.Register(ctx, "otheruser", "servicetestuser@example.com", "password123", "")
 	if err == nil {
 		t.Error("expected error for duplicate email, got nil")
 	}
 
 	// Test Login (correct password)
-	loggedIn, err := service.Login("servicetestuser", "password123")
+	loggedIn, err := service.Login(ctx, "servicetestuser", "password123", domain.LoginClient{})
 	if err != nil {
 		t.Fatalf("Login failed: %v", err)
 	}
@@ -66,13 +81,13 @@ func TestUserServiceImpl_RegisterAndLogin(t *testing.T) {
 	}
 
 	// Test Login (wrong password)
-	_, err = service.Login("servicetestuser", "wrongpassword")
+	_, err = service.Login(ctx, "servicetestuser", "wrongpassword", domain.LoginClient{})
 	if err == nil {
 		t.Error("expected error for wrong password, got nil")
 	}
 
 	// Test Login (nonexistent user)
-	_, err = service.Login("doesnotexist", "password123")
+	_, err = service.Login(ctx, "doesnotexist", "password123", domain.LoginClient{})
 	if err == nil {
 		t.Error("expected error for nonexistent user, got nil")
 	}
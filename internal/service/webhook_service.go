@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// Idempotency key prefixes of the transactions made by gateway webhooks.
+const (
+	gatewayCreditKeyPrefix = "gateway:"
+	gatewayRefundKeyPrefix = "gateway-refund:"
+)
+
+// WebhookServiceImpl implements domain.WebhookService.
+type WebhookServiceImpl struct {
+	repo         domain.WebhookRepository
+	gateways     map[string]domain.PaymentGateway
+	users        domain.UserRepository
+	txRepo       domain.TransactionRepository
+	transactions domain.TransactionService
+	funding      domain.FundingService
+	currency     string
+}
+
+// NewWebhookService creates a new WebhookServiceImpl.
+func NewWebhookService(repo domain.WebhookRepository, gateways map[string]domain.PaymentGateway, users domain.UserRepository, txRepo domain.TransactionRepository,
+	transactions domain.TransactionService, funding domain.FundingService, currency string) *WebhookServiceImpl {
+	return &WebhookServiceImpl{
+		repo:         repo,
+		gateways:     gateways,
+		users:        users,
+		txRepo:       txRepo,
+		transactions: transactions,
+		funding:      funding,
+		currency:     strings.ToUpper(currency),
+	}
+}
+
+// Receive verifies a webhook, stores its event verbatim and applies it.
+func (s *WebhookServiceImpl) Receive(ctx context.Context, delivery *domain.WebhookDelivery) (*domain.WebhookEvent, error) {
+	provider := strings.ToLower(delivery.Provider)
+	gateway, ok := s.gateways[provider]
+	if !ok {
+		return nil, domain.ErrWebhookProviderNotFound
+	}
+	event, err := gateway.ParseEvent(delivery.Signature, delivery.Body)
+	if err != nil {
+		metrics.WebhookEventsTotal.WithLabelValues(provider, "rejected").Inc()
+		return nil, err
+	}
+
+	record := &domain.WebhookEvent{
+		Provider: provider,
+		EventID:  event.ID,
+		Type:     event.Type,
+		Payload:  delivery.Body,
+		Status:   domain.WebhookReceived,
+	}
+	if err := s.repo.Create(ctx, record); err != nil {
+		if !errors.Is(err, domain.ErrWebhookEventExists) {
+			return nil, fmt.Errorf("failed to store webhook event: %w", err)
+		}
+		stored, err := s.repo.GetByEventID(ctx, provider, event.ID)
+		if err != nil || stored == nil {
+			return nil, fmt.Errorf("failed to get webhook event: %w", err)
+		}
+		if stored.Status != domain.WebhookReceived {
+			metrics.WebhookEventsTotal.WithLabelValues(provider, "duplicate").Inc()
+			return stored, nil
+		}
+		record = stored
+	}
+
+	transactionID, err := s.apply(ctx, provider, event)
+	switch kind, _ := domain.ClassifyError(err); {
+	case err == nil && event.Action == domain.GatewayActionIgnore:
+		record.Status = domain.WebhookIgnored
+	case err == nil:
+		record.Status, record.TransactionID = domain.WebhookProcessed, transactionID
+	case kind == domain.ErrorKindInternal || kind == domain.ErrorKindUnavailable:
+		// Left received for the provider's retry
+		return nil, fmt.Errorf("failed to apply webhook event: %w", err)
+	default:
+		record.Status, record.Error = domain.WebhookFailed, err.Error()
+		log.Ctx(ctx).Warn().Err(err).Str("provider", provider).Str("event_id", event.ID).Msg("Webhook event can't be applied")
+	}
+	if err := s.repo.Update(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to update webhook event: %w", err)
+	}
+	metrics.WebhookEventsTotal.WithLabelValues(provider, record.Status).Inc()
+	return record, nil
+}
+
+// apply makes the credit or refund an event asks for, returning its transaction's ID.
+func (s *WebhookServiceImpl) apply(ctx context.Context, provider string, event *domain.GatewayEvent) (*int, error) {
+	if event.Action == domain.GatewayActionIgnore {
+		return nil, nil
+	}
+	if event.ObjectID == "" || event.ChargeID == "" || len(event.ObjectID) > maxExternalIDLength || len(event.ChargeID) > maxExternalIDLength {
+		return nil, &domain.ValidationError{Msg: "event must name its payment"}
+	}
+	if event.Amount <= 0 {
+		return nil, &domain.ValidationError{Msg: "amount must be positive"}
+	}
+	if event.Currency != s.currency {
+		return nil, &domain.ValidationError{Msg: "currency must be " + s.currency}
+	}
+
+	if event.Action == domain.GatewayActionCredit {
+		if event.UserID <= 0 {
+			return nil, &domain.ValidationError{Msg: "payment must carry a user_id in its metadata"}
+		}
+		user, err := s.users.GetByID(ctx, event.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil {
+			return nil, &domain.ValidationError{Msg: "payment's user " + strconv.Itoa(event.UserID) + " does not exist"}
+		}
+		tx, err := s.transactions.Credit(ctx, event.UserID, event.Amount, gatewayCreditKeyPrefix+provider+":"+event.ChargeID)
+		if err != nil {
+			return nil, err
+		}
+		return &tx.ID, nil
+	}
+
+	credit, err := s.txRepo.GetByIdempotencyKey(ctx, gatewayCreditKeyPrefix+provider+":"+event.ChargeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refunded payment: %w", err)
+	}
+	if credit == nil || credit.ToUserID == nil {
+		return nil, &domain.ValidationError{Msg: "refunded payment " + event.ChargeID + " was never credited"}
+	}
+	refund, err := s.funding.ReceiveRefund(ctx, credit, &domain.GatewayRefund{
+		Provider: provider,
+		ChargeID: event.ChargeID,
+		RefundID: event.ObjectID,
+		Amount:   event.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refund.DebitTransactionID, nil
+}
+
+// GetEvent returns an event with its payload.
+func (s *WebhookServiceImpl) GetEvent(ctx context.Context, id int) (*domain.WebhookEvent, error) {
+	event, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook event: %w", err)
+	}
+	if event == nil {
+		return nil, domain.ErrWebhookEventNotFound
+	}
+	return event, nil
+}
+
+// ListEvents returns a page of events, newest first, optionally of one provider or status.
+func (s *WebhookServiceImpl) ListEvents(ctx context.Context, provider, status string, limit, offset int) ([]*domain.WebhookEvent, error) {
+	switch status {
+	case "", domain.WebhookReceived, domain.WebhookProcessed, domain.WebhookIgnored, domain.WebhookFailed:
+	default:
+		return nil, &domain.ValidationError{Msg: "status must be received, processed, ignored or failed"}
+	}
+	return s.repo.List(ctx, strings.ToLower(provider), status, limit, offset)
+}
@@ -0,0 +1,130 @@
+// Package tokenstore implements domain.TokenStore in Redis or in process memory.
+package tokenstore
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps revoked token IDs and each user's token version.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a token store on client.
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// denylistKey holds a revoked token ID
+func denylistKey(jti string) string {
+	return "denylist:" + jti
+}
+
+// versionKey holds the version of a user's tokens
+func versionKey(userID int) string {
+	return "token_version:" + strconv.Itoa(userID)
+}
+
+// Revoke invalidates a token until it expires.
+func (s *RedisStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.client.Set(ctx, denylistKey(jti), "true", ttl).Err()
+}
+
+// RevokeAll moves a user to the next token version.
+func (s *RedisStore) RevokeAll(ctx context.Context, userID int) error {
+	return s.client.Incr(ctx, versionKey(userID)).Err()
+}
+
+// TokenVersion returns a user's token version, 0 if it was never moved.
+func (s *RedisStore) TokenVersion(ctx context.Context, userID int) (int64, error) {
+	version, err := s.client.Get(ctx, versionKey(userID)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return version, err
+}
+
+// IsRevoked checks the denylist and the user's token version in one round trip.
+func (s *RedisStore) IsRevoked(ctx context.Context, jti string, userID int, version int64) (bool, error) {
+	pipe := s.client.Pipeline()
+	denied := pipe.Exists(ctx, denylistKey(jti))
+	current := pipe.Get(ctx, versionKey(userID))
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return false, err
+	}
+	if denied.Val() > 0 {
+		return true, nil
+	}
+	currentVersion, err := current.Int64()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return version < currentVersion, nil
+}
+
+// MemoryStore keeps revocations in process memory.
+type MemoryStore struct {
+	now func() time.Time
+
+	mu       sync.Mutex
+	denied   map[string]time.Time // token ID to expiry
+	versions map[int]int64
+}
+
+// NewMemoryStore creates an in-process token store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{now: time.Now, denied: make(map[string]time.Time), versions: make(map[int]int64)}
+}
+
+// Revoke invalidates a token until it expires.
+func (s *MemoryStore) Revoke(_ context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for id, exp := range s.denied {
+		if !now.Before(exp) {
+			delete(s.denied, id)
+		}
+	}
+	if now.Before(expiresAt) {
+		s.denied[jti] = expiresAt
+	}
+	return nil
+}
+
+// RevokeAll moves a user to the next token version.
+func (s *MemoryStore) RevokeAll(_ context.Context, userID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versions[userID]++
+	return nil
+}
+
+// TokenVersion returns a user's token version.
+func (s *MemoryStore) TokenVersion(_ context.Context, userID int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.versions[userID], nil
+}
+
+// IsRevoked reports whether a token was revoked on its own or by RevokeAll.
+func (s *MemoryStore) IsRevoked(_ context.Context, jti string, userID int, version int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if exp, ok := s.denied[jti]; ok && s.now().Before(exp) {
+		return true, nil
+	}
+	return version < s.versions[userID], nil
+}
@@ -0,0 +1,52 @@
+package tokenstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Revoke(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.Revoke(ctx, "a", now.Add(time.Minute)))
+	require.NoError(t, s.Revoke(ctx, "expired", now.Add(-time.Minute)))
+
+	revoked, _ := s.IsRevoked(ctx, "a", 1, 0)
+	assert.True(t, revoked)
+	revoked, _ = s.IsRevoked(ctx, "b", 1, 0)
+	assert.False(t, revoked)
+	assert.NotContains(t, s.denied, "expired", "expired tokens need no entry")
+
+	now = now.Add(2 * time.Minute)
+	revoked, _ = s.IsRevoked(ctx, "a", 1, 0)
+	assert.False(t, revoked, "the entry lapses with the token")
+	require.NoError(t, s.Revoke(ctx, "c", now.Add(time.Minute)))
+	assert.NotContains(t, s.denied, "a", "lapsed entries are dropped")
+}
+
+func TestMemoryStore_RevokeAll(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	version, err := s.TokenVersion(ctx, 1)
+	require.NoError(t, err)
+	assert.Zero(t, version)
+
+	require.NoError(t, s.RevokeAll(ctx, 1))
+	revoked, _ := s.IsRevoked(ctx, "a", 1, version)
+	assert.True(t, revoked, "tokens of the previous version are revoked")
+	revoked, _ = s.IsRevoked(ctx, "a", 2, version)
+	assert.False(t, revoked, "other users are unaffected")
+
+	version, _ = s.TokenVersion(ctx, 1)
+	assert.Equal(t, int64(1), version)
+	revoked, _ = s.IsRevoked(ctx, "b", 1, version)
+	assert.False(t, revoked, "tokens issued afterwards are valid")
+}
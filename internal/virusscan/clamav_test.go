@@ -0,0 +1,71 @@
+package virusscan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClamd accepts one connection, reads an INSTREAM request and answers
+// with reply; the streamed file is sent on the returned channel
+func fakeClamd(t *testing.T, reply string) (string, <-chan []byte) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		if cmd, err := r.ReadString('\x00'); err != nil || cmd != "zINSTREAM\x00" {
+			return
+		}
+		var data bytes.Buffer
+		var size [4]byte
+		for {
+			if _, err := io.ReadFull(r, size[:]); err != nil {
+				return
+			}
+			n := binary.BigEndian.Uint32(size[:])
+			if n == 0 {
+				break
+			}
+			if _, err := io.CopyN(&data, r, int64(n)); err != nil {
+				return
+			}
+		}
+		received <- data.Bytes()
+		conn.Write([]byte(reply + "\x00"))
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestClamAV_Scan(t *testing.T) {
+	data := bytes.Repeat([]byte("document"), chunkSize/4) // spans several chunks
+
+	addr, received := fakeClamd(t, "stream: OK")
+	threat, err := NewClamAV(addr).Scan(context.Background(), data)
+	require.NoError(t, err)
+	assert.Empty(t, threat)
+	assert.Equal(t, data, <-received)
+
+	addr, _ = fakeClamd(t, "stream: Eicar-Test-Signature FOUND")
+	threat, err = NewClamAV(addr).Scan(context.Background(), []byte("X5O!P%@AP"))
+	require.NoError(t, err)
+	assert.Equal(t, "Eicar-Test-Signature", threat)
+
+	addr, _ = fakeClamd(t, "INSTREAM size limit exceeded. ERROR")
+	_, err = NewClamAV(addr).Scan(context.Background(), []byte("big"))
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -11,11 +12,14 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/melihgurlek/backend-path/internal/domain"
+	"github.com/melihgurlek/backend-path/pkg/metrics"
 )
 
 // BatchProcessor handles concurrent processing of multiple transaction tasks
 type BatchProcessor struct {
 	transactionProcessor domain.TransactionProcessor
+	batchRepo            domain.BatchRepository
+	taskRepo             domain.TaskRepository
 	maxConcurrency       int
 	batchTimeout         time.Duration
 }
@@ -28,6 +32,8 @@ type BatchResult struct {
 	FailedTasks     int
 	ProcessingTime  time.Duration
 	Errors          []BatchError
+	TimedOut        bool
+	RolledBack      bool // completed tasks were compensated after a failure
 	CompletedAt     time.Time
 }
 
@@ -40,21 +46,98 @@ type BatchError struct {
 // NewBatchProcessor creates a new batch processor
 func NewBatchProcessor(
 	transactionProcessor domain.TransactionProcessor,
+	batchRepo domain.BatchRepository,
+	taskRepo domain.TaskRepository,
 	maxConcurrency int,
 	batchTimeout time.Duration,
 ) *BatchProcessor {
 	return &BatchProcessor{
 		transactionProcessor: transactionProcessor,
+		batchRepo:            batchRepo,
+		taskRepo:             taskRepo,
 		maxConcurrency:       maxConcurrency,
 		batchTimeout:         batchTimeout,
 	}
 }
 
+// StartBatch records a new batch and processes it in the background.
+func (bp *BatchProcessor) StartBatch(ctx context.Context, tasks []*domain.TransactionTask, rollbackOnFailure bool) (*domain.BatchRecord, error) {
+	batch := domain.NewBatchRecord(generateBatchID(), len(tasks))
+	for _, task := range tasks {
+		task.BatchID = batch.BatchID
+	}
+
+	if bp.batchRepo != nil {
+		if err := bp.batchRepo.Create(ctx, batch); err != nil {
+			return nil, fmt.Errorf("failed to record batch: %w", err)
+		}
+	}
+
+	go func() {
+		defer metrics.TrackGoroutine("batch")()
+		// The submitting request's context ends as soon as it responds, so detach
+		// from its cancellation while keeping its trace
+		ctx := context.WithoutCancel(ctx)
+		var result *BatchResult
+		var err error
+		if rollbackOnFailure {
+			result, err = bp.processBatchWithRollback(ctx, batch.BatchID, tasks)
+		} else {
+			result, err = bp.processBatch(ctx, batch.BatchID, tasks)
+		}
+		if err != nil {
+			log.Error().Err(err).Str("batch_id", batch.BatchID).Msg("Asynchronous batch processing failed")
+			return
+		}
+
+		switch {
+		case result.RolledBack:
+			batch.Status = "rolled_back"
+		case result.TimedOut:
+			batch.Status = "timed_out"
+		default:
+			batch.Status = "completed"
+		}
+		completedAt := result.CompletedAt.UTC()
+		batch.CompletedAt = &completedAt
+		if bp.batchRepo != nil {
+			if err := bp.batchRepo.Update(ctx, batch); err != nil {
+				log.Warn().Err(err).Str("batch_id", batch.BatchID).Msg("Failed to record batch completion")
+			}
+		}
+	}()
+
+	return batch, nil
+}
+
+// GetBatch returns a batch and the records of its tasks, or a nil batch if unknown
+func (bp *BatchProcessor) GetBatch(ctx context.Context, batchID string) (*domain.BatchRecord, []*domain.TaskRecord, error) {
+	if bp.batchRepo == nil || bp.taskRepo == nil {
+		return nil, nil, errors.New("batch tracking is not configured")
+	}
+
+	batch, err := bp.batchRepo.GetByID(ctx, batchID)
+	if err != nil || batch == nil {
+		return nil, nil, err
+	}
+
+	tasks, err := bp.taskRepo.ListByBatch(ctx, batchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return batch, tasks, nil
+}
+
 // ProcessBatch processes a batch of transaction tasks concurrently
 func (bp *BatchProcessor) ProcessBatch(ctx context.Context, tasks []*domain.TransactionTask) (*BatchResult, error) {
+	return bp.processBatch(ctx, generateBatchID(), tasks)
+}
+
+// processBatch processes tasks under the given batch ID and waits for their outcomes
+func (bp *BatchProcessor) processBatch(ctx context.Context, batchID string, tasks []*domain.TransactionTask) (*BatchResult, error) {
 	if len(tasks) == 0 {
 		return &BatchResult{
-			BatchID:         generateBatchID(),
+			BatchID:         batchID,
 			TotalTasks:      0,
 			SuccessfulTasks: 0,
 			FailedTasks:     0,
@@ -67,7 +150,6 @@ func (bp *BatchProcessor) ProcessBatch(ctx context.Context, tasks []*domain.Tran
 	spanCtx, span := otel.Tracer("batch-processor").Start(ctx, "process-batch")
 	defer span.End()
 
-	batchID := generateBatchID()
 	span.SetAttributes(
 		attribute.String("batch.id", batchID),
 		attribute.Int("batch.size", len(tasks)),
@@ -88,13 +170,12 @@ func (bp *BatchProcessor) ProcessBatch(ctx context.Context, tasks []*domain.Tran
 	// Create channels for coordination
 	taskChan := make(chan *domain.TransactionTask, len(tasks))
 	resultChan := make(chan *domain.TransactionResult, len(tasks))
-	errorChan := make(chan error, len(tasks))
 
 	// Start worker goroutines
 	var wg sync.WaitGroup
 	for i := 0; i < bp.maxConcurrency; i++ {
 		wg.Add(1)
-		go bp.worker(batchCtx, i, taskChan, resultChan, errorChan, &wg)
+		go bp.worker(batchCtx, i, taskChan, resultChan, &wg)
 	}
 
 	// Send tasks to workers
@@ -114,37 +195,40 @@ func (bp *BatchProcessor) ProcessBatch(ctx context.Context, tasks []*domain.Tran
 	go func() {
 		wg.Wait()
 		close(resultChan)
-		close(errorChan)
 	}()
 
 	// Process results
-	var errors []BatchError
+	var batchErrors []BatchError
+	finished := make(map[string]bool, len(tasks))
 ResultLoop:
 	for {
 		select {
-		case res := <-resultChan:
-			if res != nil {
-				if res.Success {
-					result.SuccessfulTasks++
-				} else {
-					result.FailedTasks++
-					errors = append(errors, BatchError{
-						TaskID: res.TaskID,
-						Error:  res.Message,
-					})
-				}
+		case res, ok := <-resultChan:
+			if !ok {
+				break ResultLoop
 			}
-		case err := <-errorChan:
-			if err != nil {
+			finished[res.TaskID] = true
+			if res.Success {
+				result.SuccessfulTasks++
+			} else {
 				result.FailedTasks++
-				errors = append(errors, BatchError{
-					TaskID: "unknown",
-					Error:  err.Error(),
+				batchErrors = append(batchErrors, BatchError{
+					TaskID: res.TaskID,
+					Error:  res.Message,
 				})
 			}
 		case <-batchCtx.Done():
-			// Timeout or cancellation
-			result.FailedTasks += len(tasks) - result.SuccessfulTasks - result.FailedTasks
+			// Timeout or cancellation; tasks already queued keep running but are reported as failed here
+			for _, task := range tasks {
+				if !finished[task.ID] {
+					result.FailedTasks++
+					batchErrors = append(batchErrors, BatchError{
+						TaskID: task.ID,
+						Error:  "batch timed out before the task finished",
+					})
+				}
+			}
+			result.TimedOut = true
 			span.RecordError(batchCtx.Err())
 			log.Warn().Str("batch_id", batchID).Err(batchCtx.Err()).Msg("Batch processing timeout or cancelled")
 			break ResultLoop
@@ -157,7 +241,8 @@ ResultLoop:
 	}
 
 	result.ProcessingTime = time.Since(startTime)
-	result.Errors = errors
+	result.Errors = batchErrors
+	result.CompletedAt = time.Now()
 
 	span.SetAttributes(
 		attribute.Int("successful_tasks", result.SuccessfulTasks),
@@ -176,40 +261,16 @@ ResultLoop:
 	return result, nil
 }
 
-// ProcessBatchWithRollback processes a batch with rollback capability
-func (bp *BatchProcessor) ProcessBatchWithRollback(ctx context.Context, tasks []*domain.TransactionTask) (*BatchResult, error) {
-	// For now, we'll implement a simple version
-	// In a real implementation, you might want to:
-	// 1. Use database transactions
-	// 2. Implement compensation logic
-	// 3. Use saga pattern for complex workflows
-
-	result, err := bp.ProcessBatch(ctx, tasks)
-	if err != nil {
-		return result, err
-	}
-
-	// If any task failed, we could implement rollback logic here
-	if result.FailedTasks > 0 {
-		log.Warn().
-			Str("batch_id", result.BatchID).
-			Int("failed_tasks", result.FailedTasks).
-			Msg("Batch had failed tasks - consider implementing rollback logic")
-	}
-
-	return result, nil
-}
-
-// worker processes tasks from the task channel
+// worker submits tasks from the task channel and waits for each outcome
 func (bp *BatchProcessor) worker(
 	ctx context.Context,
 	workerID int,
 	taskChan <-chan *domain.TransactionTask,
 	resultChan chan<- *domain.TransactionResult,
-	errorChan chan<- error,
 	wg *sync.WaitGroup,
 ) {
 	defer wg.Done()
+	defer metrics.TrackGoroutine("batch_worker")()
 
 	log.Debug().Int("worker_id", workerID).Msg("Batch worker started")
 
@@ -220,20 +281,19 @@ func (bp *BatchProcessor) worker(
 				return
 			}
 
-			// Submit task to transaction processor
-			err := bp.transactionProcessor.SubmitTask(ctx, task)
+			result, err := bp.transactionProcessor.SubmitTaskAndWait(ctx, task)
 			if err != nil {
-				errorChan <- fmt.Errorf("failed to submit task %s: %w", task.ID, err)
-				continue
-			}
-
-			// For batch processing, we'll create a simple result
-			// In a real implementation, you might want to wait for the actual result
-			result := &domain.TransactionResult{
-				TaskID:    task.ID,
-				Success:   true, // Assume success for now
-				Message:   "Task submitted successfully",
-				Timestamp: time.Now().Unix(),
+				if ctx.Err() != nil {
+					// The batch timed out; ProcessBatch accounts for unfinished tasks
+					return
+				}
+				result = &domain.TransactionResult{
+					TaskID:    task.ID,
+					Success:   false,
+					Error:     err,
+					Message:   fmt.Sprintf("failed to submit task: %s", err.Error()),
+					Timestamp: time.Now().Unix(),
+				}
 			}
 
 			select {
@@ -0,0 +1,164 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+func TestBatchProcessor_ReportsActualTaskOutcomes(t *testing.T) {
+	// Credits succeed, debits fail permanently in the recording service
+	txService := newRecordingTransactionService(-1)
+	processor := NewTransactionProcessor(txService, nil, nil, nil, nil, 2, 10, fastRetryPolicy(1))
+
+	ctx := context.Background()
+	require.NoError(t, processor.Start(ctx))
+	defer processor.Stop(ctx)
+
+	var tasks []*domain.TransactionTask
+	for i := 0; i < 3; i++ {
+		tasks = append(tasks, &domain.TransactionTask{ID: fmt.Sprintf("credit-%d", i), Type: "credit", UserID: 1, Amount: 10})
+	}
+	tasks = append(tasks, &domain.TransactionTask{ID: "debit-0", Type: "debit", UserID: 1, Amount: 10})
+
+	bp := NewBatchProcessor(processor, nil, nil, 2, 5*time.Second)
+	result, err := bp.ProcessBatch(ctx, tasks)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, result.TotalTasks)
+	assert.Equal(t, 3, result.SuccessfulTasks)
+	assert.Equal(t, 1, result.FailedTasks)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "debit-0", result.Errors[0].TaskID)
+	assert.Equal(t, "not implemented", result.Errors[0].Error)
+}
+
+func TestBatchProcessor_TimedOutTasksAreReportedAsFailed(t *testing.T) {
+	txService := newRecordingTransactionService(-1)
+	// The processor is never started, so nothing completes before the batch timeout
+	processor := NewTransactionProcessor(txService, nil, nil, nil, nil, 1, 10, fastRetryPolicy(1))
+
+	tasks := []*domain.TransactionTask{
+		{ID: "credit-0", Type: "credit", UserID: 1, Amount: 10},
+		{ID: "credit-1", Type: "credit", UserID: 1, Amount: 10},
+	}
+
+	bp := NewBatchProcessor(processor, nil, nil, 2, 50*time.Millisecond)
+	result, err := bp.ProcessBatch(context.Background(), tasks)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, result.SuccessfulTasks)
+	assert.Equal(t, 2, result.FailedTasks)
+	assert.Len(t, result.Errors, 2)
+}
+
+// memoryBatchRepository is an in-memory domain.BatchRepository
+type memoryBatchRepository struct {
+	mu      sync.Mutex
+	batches map[string]domain.BatchRecord
+}
+
+func (r *memoryBatchRepository) Create(ctx context.Context, b *domain.BatchRecord) error {
+	return r.Update(ctx, b)
+}
+
+func (r *memoryBatchRepository) GetByID(ctx context.Context, batchID string) (*domain.BatchRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.batches[batchID]
+	if !ok {
+		return nil, nil
+	}
+	return &b, nil
+}
+
+func (r *memoryBatchRepository) Update(ctx context.Context, b *domain.BatchRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches[b.BatchID] = *b
+	return nil
+}
+
+// memoryTaskRepository is an in-memory domain.TaskRepository
+type memoryTaskRepository struct {
+	mu      sync.Mutex
+	records map[string]domain.TaskRecord
+}
+
+func (r *memoryTaskRepository) Create(ctx context.Context, rec *domain.TaskRecord) error {
+	return r.Update(ctx, rec)
+}
+
+func (r *memoryTaskRepository) GetByID(ctx context.Context, taskID string) (*domain.TaskRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec, ok := r.records[taskID]
+	if !ok {
+		return nil, nil
+	}
+	return &rec, nil
+}
+
+func (r *memoryTaskRepository) ListByBatch(ctx context.Context, batchID string) ([]*domain.TaskRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*domain.TaskRecord
+	for _, rec := range r.records {
+		if rec.BatchID == batchID {
+			rec := rec
+			out = append(out, &rec)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryTaskRepository) Update(ctx context.Context, rec *domain.TaskRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[rec.TaskID] = *rec
+	return nil
+}
+
+func TestBatchProcessor_StartBatchTracksProgress(t *testing.T) {
+	txService := newRecordingTransactionService(-1)
+	taskRepo := &memoryTaskRepository{records: map[string]domain.TaskRecord{}}
+	batchRepo := &memoryBatchRepository{batches: map[string]domain.BatchRecord{}}
+	processor := NewTransactionProcessor(txService, nil, taskRepo, nil, nil, 2, 10, fastRetryPolicy(1))
+
+	ctx := context.Background()
+	require.NoError(t, processor.Start(ctx))
+	defer processor.Stop(ctx)
+
+	tasks := []*domain.TransactionTask{
+		{ID: "credit-0", Type: "credit", UserID: 1, Amount: 10},
+		{ID: "debit-0", Type: "debit", UserID: 1, Amount: 10},
+	}
+
+	bp := NewBatchProcessor(processor, batchRepo, taskRepo, 2, 5*time.Second)
+	batch, err := bp.StartBatch(ctx, tasks, false)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		b, _, err := bp.GetBatch(ctx, batch.BatchID)
+		return err == nil && b.Status == "completed"
+	}, 5*time.Second, 5*time.Millisecond)
+
+	b, records, err := bp.GetBatch(ctx, batch.BatchID)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	progress := b.Progress(records)
+	assert.Equal(t, 1, progress.SuccessfulTasks)
+	assert.Equal(t, 1, progress.FailedTasks)
+	assert.Equal(t, 0, progress.PendingTasks)
+	assert.Equal(t, float64(100), progress.ProgressPercent)
+	require.Len(t, progress.Errors, 1)
+	assert.Equal(t, "debit-0", progress.Errors[0].TaskID)
+}
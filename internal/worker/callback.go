@@ -0,0 +1,174 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// Headers set on every completion callback request
+const (
+	CallbackSignatureHeader = "X-Signature"
+	CallbackTimestampHeader = "X-Signature-Timestamp"
+)
+
+// ErrCallbackAddressNotAllowed is returned for a callback URL to a non-public address.
+var ErrCallbackAddressNotAllowed = errors.New("callback address is not public")
+
+// CallbackPayload is the JSON body POSTed to a task's callback URL
+type CallbackPayload struct {
+	TaskID        string `json:"task_id"`
+	Success       bool   `json:"success"`
+	Message       string `json:"message"`
+	TransactionID *int   `json:"transaction_id,omitempty"`
+	Timestamp     int64  `json:"timestamp"`
+}
+
+// CallbackSender delivers signed task results to client-supplied URLs
+type CallbackSender struct {
+	client         *http.Client
+	secret         []byte
+	maxAttempts    int
+	initialBackoff time.Duration
+}
+
+// NewCallbackSender creates a sender that signs payloads with secret and retries failures.
+func NewCallbackSender(secret string, maxAttempts int, initialBackoff time.Duration) *CallbackSender {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &CallbackSender{
+		client:         newPublicClient(10 * time.Second),
+		secret:         []byte(secret),
+		maxAttempts:    maxAttempts,
+		initialBackoff: initialBackoff,
+	}
+}
+
+// newPublicClient returns a client that only connects to public addresses.
+func newPublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialPublicOnly}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+	}
+}
+
+// dialPublicOnly refuses connections to addresses that are not public
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrCallbackAddressNotAllowed, addrPort.Addr())
+	}
+	return nil
+}
+
+// isPublicAddr reports whether addr is a global unicast address outside the private ranges
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// ValidateCallbackURL checks that raw is an http or https URL to a public host.
+func ValidateCallbackURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("callback_url must be an absolute http or https URL")
+	}
+	host := strings.ToLower(u.Hostname())
+	addr, err := netip.ParseAddr(host)
+	if (err == nil && !isPublicAddr(addr)) || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errors.New("callback_url must not point at a private, loopback or link-local address")
+	}
+	return nil
+}
+
+// SignCallback returns the hex HMAC-SHA256 of "timestamp.body", as sent in the X-Signature header.
+func SignCallback(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send POSTs result to url, retrying on network errors and non-2xx responses
+func (s *CallbackSender) Send(ctx context.Context, url string, result *domain.TransactionResult) error {
+	body, err := json.Marshal(CallbackPayload{
+		TaskID:        result.TaskID,
+		Success:       result.Success,
+		Message:       result.Message,
+		TransactionID: result.TransactionID,
+		Timestamp:     result.Timestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode callback payload: %w", err)
+	}
+
+	backoff := s.initialBackoff
+	for attempt := 1; ; attempt++ {
+		err = s.deliver(ctx, url, body)
+		if err == nil {
+			return nil
+		}
+		if attempt >= s.maxAttempts {
+			return fmt.Errorf("callback failed after %d attempts: %w", attempt, err)
+		}
+
+		log.Warn().Err(err).Str("task_id", result.TaskID).Int("attempt", attempt).Msg("Callback delivery failed, retrying")
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// deliver makes a single signed callback request
+func (s *CallbackSender) deliver(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CallbackTimestampHeader, timestamp)
+	req.Header.Set(CallbackSignatureHeader, SignCallback(s.secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
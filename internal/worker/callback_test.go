@@ -0,0 +1,192 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+func TestCallbackSender_SendsSignedPayload(t *testing.T) {
+	secret := "callback-secret"
+	txID := 42
+
+	var received CallbackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		expected := SignCallback([]byte(secret), r.Header.Get(CallbackTimestampHeader), body)
+		assert.Equal(t, expected, r.Header.Get(CallbackSignatureHeader))
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewCallbackSender(secret, 1, time.Millisecond)
+	sender.client = server.Client() // the test server listens on loopback
+	err := sender.Send(context.Background(), server.URL, &domain.TransactionResult{
+		TaskID:        "task-1",
+		Success:       true,
+		Message:       "Task processed successfully",
+		TransactionID: &txID,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", received.TaskID)
+	assert.True(t, received.Success)
+	require.NotNil(t, received.TransactionID)
+	assert.Equal(t, txID, *received.TransactionID)
+}
+
+func TestCallbackSender_RetriesUntilSuccess(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := NewCallbackSender("secret", 3, time.Millisecond)
+	sender.client = server.Client() // the test server listens on loopback
+	err := sender.Send(context.Background(), server.URL, &domain.TransactionResult{TaskID: "task-2"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestCallbackSender_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sender := NewCallbackSender("secret", 2, time.Millisecond)
+	sender.client = server.Client() // the test server listens on loopback
+	err := sender.Send(context.Background(), server.URL, &domain.TransactionResult{TaskID: "task-3"})
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCallbackSender_RefusesNonPublicAddresses(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := NewCallbackSender("secret", 1, time.Millisecond)
+	err := sender.Send(context.Background(), server.URL, &domain.TransactionResult{TaskID: "task-4"})
+
+	assert.ErrorIs(t, err, ErrCallbackAddressNotAllowed)
+	assert.Zero(t, atomic.LoadInt32(&calls))
+}
+
+func TestCallbackSender_RefusesRedirectsToNonPublicAddresses(t *testing.T) {
+	var calls int32
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer internal.Close()
+
+	sender := NewCallbackSender("secret", 1, time.Millisecond)
+	// A public endpoint answering with a redirect is stood in for by a
+	// transport that serves the redirect itself and dials everything else
+	// through the guarded one
+	guarded := sender.client.Transport
+	sender.client.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Host == "callbacks.example.com" {
+			return &http.Response{
+				StatusCode: http.StatusTemporaryRedirect,
+				Header:     http.Header{"Location": []string{internal.URL}},
+				Body:       http.NoBody,
+				Request:    r,
+			}, nil
+		}
+		return guarded.RoundTrip(r)
+	})
+	err := sender.Send(context.Background(), "https://callbacks.example.com/hook", &domain.TransactionResult{TaskID: "task-5"})
+
+	assert.ErrorIs(t, err, ErrCallbackAddressNotAllowed)
+	assert.Zero(t, atomic.LoadInt32(&calls))
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:4700::1111", true},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.public, isPublicAddr(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestValidateCallbackURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://callbacks.example.com/hook", false},
+		{"http://93.184.216.34:8080/hook", false},
+		{"ftp://callbacks.example.com/hook", true},
+		{"/relative/hook", true},
+		{"https://", true},
+		{"http://localhost:8080/hook", true},
+		{"http://api.LOCALHOST/hook", true},
+		{"http://127.0.0.1/hook", true},
+		{"http://[::1]/hook", true},
+		{"http://169.254.169.254/latest/meta-data", true},
+		{"http://10.0.0.5/hook", true},
+		{"http://[::ffff:192.168.0.1]/hook", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := ValidateCallbackURL(tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package worker
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// errQueueFull is returned when a task cannot be queued before the timeout
+var errQueueFull = errors.New("queue is full, task submission timeout")
+
+// priorityQueue is a bounded, blocking task queue that dispatches higher priority tasks first.
+type priorityQueue struct {
+	mu    sync.Mutex
+	items taskHeap
+	seq   uint64
+
+	// slots holds one token per free position, ready one token per queued task.
+	// Together they make push block when full and pop block when empty.
+	slots chan struct{}
+	ready chan struct{}
+}
+
+// newPriorityQueue creates a queue holding at most capacity tasks
+func newPriorityQueue(capacity int) *priorityQueue {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &priorityQueue{
+		slots: make(chan struct{}, capacity),
+		ready: make(chan struct{}, capacity),
+	}
+}
+
+// push queues a task, waiting up to timeout for space
+func (q *priorityQueue) push(ctx context.Context, task *domain.TransactionTask, timeout time.Duration) error {
+	select {
+	case q.slots <- struct{}{}:
+	case <-time.After(timeout):
+		return errQueueFull
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	q.mu.Lock()
+	heap.Push(&q.items, &queuedTask{task: task, seq: q.seq})
+	q.seq++
+	q.mu.Unlock()
+
+	q.ready <- struct{}{}
+	return nil
+}
+
+// readyChan yields once for every queued task; receive from it before calling pop
+func (q *priorityQueue) readyChan() <-chan struct{} {
+	return q.ready
+}
+
+// pop removes the highest priority task.
+func (q *priorityQueue) pop() *domain.TransactionTask {
+	q.mu.Lock()
+	item := heap.Pop(&q.items).(*queuedTask)
+	q.mu.Unlock()
+
+	<-q.slots
+	return item.task
+}
+
+// len returns the number of queued tasks
+func (q *priorityQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Len()
+}
+
+// queuedTask wraps a task with its arrival order for stable ordering
+type queuedTask struct {
+	task *domain.TransactionTask
+	seq  uint64
+}
+
+// taskHeap implements heap.Interface ordered by priority desc, then arrival asc
+type taskHeap []*queuedTask
+
+func (h taskHeap) Len() int { return len(h) }
+
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].task.Priority != h[j].task.Priority {
+		return h[i].task.Priority > h[j].task.Priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h taskHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *taskHeap) Push(x any) { *h = append(*h, x.(*queuedTask)) }
+
+func (h *taskHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,160 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// recordingTransactionService records the order in which credits are executed
+type recordingTransactionService struct {
+	mu      sync.Mutex
+	amounts []float64
+	done    chan struct{}
+	expect  int
+}
+
+func newRecordingTransactionService(expect int) *recordingTransactionService {
+	return &recordingTransactionService{done: make(chan struct{}), expect: expect}
+}
+
+func (s *recordingTransactionService) Credit(ctx context.Context, userID int, amount float64, idempotencyKey string) (*domain.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.amounts = append(s.amounts, amount)
+	if len(s.amounts) == s.expect {
+		close(s.done)
+	}
+	return &domain.Transaction{ID: len(s.amounts), Amount: amount, Type: "credit"}, nil
+}
+
+func (s *recordingTransactionService) Debit(ctx context.Context, userID int, amount float64, idempotencyKey string) (*domain.Transaction, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *recordingTransactionService) Transfer(ctx context.Context, fromUserID, toUserID int, amount float64, idempotencyKey string) (*domain.Transaction, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *recordingTransactionService) CaptureHold(ctx context.Context, hold *domain.Hold) (*domain.Transaction, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *recordingTransactionService) ExecutePending(ctx context.Context, tx *domain.Transaction, record func(repos domain.UnitOfWorkRepositories) error) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (s *recordingTransactionService) RejectPending(ctx context.Context, tx *domain.Transaction, record func(repos domain.UnitOfWorkRepositories) error) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (s *recordingTransactionService) HoldForSignOff(ctx context.Context, tx *domain.Transaction, record func(repos domain.UnitOfWorkRepositories) error) error {
+	return nil
+}
+
+func (s *recordingTransactionService) RequiresApproval(amount float64) bool {
+	return false
+}
+
+func (s *recordingTransactionService) GetTransaction(ctx context.Context, id int) (*domain.Transaction, error) {
+	return nil, nil
+}
+
+func (s *recordingTransactionService) ListUserTransactions(ctx context.Context, userID int, limit, offset int) ([]*domain.Transaction, error) {
+	return nil, nil
+}
+
+func (s *recordingTransactionService) CountUserTransactions(ctx context.Context, userID int) (int, error) {
+	return 0, nil
+}
+
+func (s *recordingTransactionService) ListAllTransactions(ctx context.Context, limit int, offset int) ([]*domain.Transaction, error) {
+	return nil, nil
+}
+
+func (s *recordingTransactionService) SearchTransactions(ctx context.Context, filter domain.TransactionFilter) ([]*domain.Transaction, error) {
+	return nil, nil
+}
+
+func TestPriorityQueue_OrdersByPriorityThenArrival(t *testing.T) {
+	q := newPriorityQueue(10)
+	ctx := context.Background()
+
+	tasks := []*domain.TransactionTask{
+		{ID: "low-1", Priority: 0},
+		{ID: "high-1", Priority: 10},
+		{ID: "mid-1", Priority: 5},
+		{ID: "high-2", Priority: 10},
+		{ID: "low-2", Priority: 0},
+	}
+	for _, task := range tasks {
+		require.NoError(t, q.push(ctx, task, time.Second))
+	}
+	assert.Equal(t, len(tasks), q.len())
+
+	var order []string
+	for range tasks {
+		<-q.readyChan()
+		order = append(order, q.pop().ID)
+	}
+
+	assert.Equal(t, []string{"high-1", "high-2", "mid-1", "low-1", "low-2"}, order)
+	assert.Equal(t, 0, q.len())
+}
+
+func TestPriorityQueue_PushTimesOutWhenFull(t *testing.T) {
+	q := newPriorityQueue(1)
+	ctx := context.Background()
+
+	require.NoError(t, q.push(ctx, &domain.TransactionTask{ID: "first"}, time.Second))
+	err := q.push(ctx, &domain.TransactionTask{ID: "second"}, 10*time.Millisecond)
+	assert.ErrorIs(t, err, errQueueFull)
+
+	<-q.readyChan()
+	q.pop()
+	assert.NoError(t, q.push(ctx, &domain.TransactionTask{ID: "third"}, time.Second))
+}
+
+func TestTransactionProcessor_DispatchesHighPriorityFirstUnderLoad(t *testing.T) {
+	const perPriority = 20
+	txService := newRecordingTransactionService(2 * perPriority)
+	processor := NewTransactionProcessor(txService, nil, nil, nil, nil, 1, 2*perPriority, domain.DefaultRetryPolicy())
+
+	// Build up a backlog before any worker is running, interleaving priorities.
+	// The amount encodes the priority so the recorded order can be checked.
+	ctx := context.Background()
+	for i := 0; i < perPriority; i++ {
+		require.NoError(t, processor.SubmitTask(ctx, &domain.TransactionTask{
+			ID: fmt.Sprintf("low-%d", i), Type: "credit", UserID: 1, Amount: 1, Priority: 0,
+		}))
+		require.NoError(t, processor.SubmitTask(ctx, &domain.TransactionTask{
+			ID: fmt.Sprintf("high-%d", i), Type: "credit", UserID: 1, Amount: 10, Priority: 10,
+		}))
+	}
+
+	require.NoError(t, processor.Start(ctx))
+	defer processor.Stop(ctx)
+
+	select {
+	case <-txService.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for tasks to be processed")
+	}
+
+	txService.mu.Lock()
+	defer txService.mu.Unlock()
+	for i, amount := range txService.amounts {
+		if i < perPriority {
+			assert.Equal(t, float64(10), amount, "task %d should be high priority", i)
+		} else {
+			assert.Equal(t, float64(1), amount, "task %d should be low priority", i)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// retryableSQLStateClasses are PostgreSQL error classes caused by transient conditions.
+var retryableSQLStateClasses = map[string]bool{
+	"08": true, // connection exception
+	"40": true, // transaction rollback (serialization failure, deadlock)
+	"53": true, // insufficient resources
+	"57": true, // operator intervention (e.g. admin shutdown, query cancelled)
+}
+
+// IsRetryable reports whether a task error is transient and worth retrying.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if kind, _ := domain.ClassifyError(err); kind != domain.ErrorKindInternal {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return len(pgErr.Code) >= 2 && retryableSQLStateClasses[pgErr.Code[:2]]
+	}
+
+	if pgconn.Timeout(err) || pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryPolicyFor applies a task's retry overrides to the processor default
+func retryPolicyFor(task *domain.TransactionTask, defaults domain.RetryPolicy) domain.RetryPolicy {
+	policy := defaults
+	if task.MaxAttempts > 0 {
+		policy.MaxAttempts = task.MaxAttempts
+	}
+	if task.RetryBackoff > 0 {
+		policy.InitialBackoff = task.RetryBackoff
+		if policy.MaxBackoff > 0 && policy.MaxBackoff < task.RetryBackoff {
+			policy.MaxBackoff = task.RetryBackoff
+		}
+	}
+	return policy
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"business rule", errors.New("insufficient balance"), false},
+		{"validation", &domain.ValidationError{Msg: "amount must be positive"}, false},
+		{"limit exceeded", fmt.Errorf("debit: %w", &domain.LimitExceededError{RuleType: domain.RuleDailyTotal, Msg: "daily total limit exceeded"}), false},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), true},
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestRetryPolicyFor_AppliesTaskOverrides(t *testing.T) {
+	defaults := domain.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+
+	assert.Equal(t, defaults, retryPolicyFor(&domain.TransactionTask{}, defaults))
+
+	policy := retryPolicyFor(&domain.TransactionTask{MaxAttempts: 5, RetryBackoff: 10 * time.Second}, defaults)
+	assert.Equal(t, 5, policy.MaxAttempts)
+	assert.Equal(t, 10*time.Second, policy.InitialBackoff)
+	assert.Equal(t, 10*time.Second, policy.MaxBackoff)
+}
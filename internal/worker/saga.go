@@ -0,0 +1,157 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+const (
+	// compensationPriority puts compensating tasks ahead of regular work
+	compensationPriority = 10
+	// compensationMaxAttempts gives compensations more retries than regular tasks
+	// since a lost compensation leaves the batch half applied
+	compensationMaxAttempts = 5
+)
+
+// compensationFor returns the task that undoes a successfully applied task
+func compensationFor(task *domain.TransactionTask) (*domain.TransactionTask, error) {
+	compensation := &domain.TransactionTask{
+		ID:          uuid.New().String(),
+		UserID:      task.UserID,
+		Amount:      task.Amount,
+		Priority:    compensationPriority,
+		MaxAttempts: compensationMaxAttempts,
+	}
+
+	switch task.Type {
+	case "credit":
+		compensation.Type = "debit"
+	case "debit":
+		compensation.Type = "credit"
+	case "transfer":
+		if task.ToUserID == nil {
+			return nil, fmt.Errorf("transfer task %s has no recipient to reverse", task.ID)
+		}
+		fromUserID := task.UserID
+		compensation.Type = "transfer"
+		compensation.UserID = *task.ToUserID
+		compensation.ToUserID = &fromUserID
+	default:
+		return nil, fmt.Errorf("no compensation for transaction type: %s", task.Type)
+	}
+	return compensation, nil
+}
+
+// ProcessBatchWithRollback processes a batch as a saga, undoing it if any task fails.
+func (bp *BatchProcessor) ProcessBatchWithRollback(ctx context.Context, tasks []*domain.TransactionTask) (*BatchResult, error) {
+	return bp.processBatchWithRollback(ctx, generateBatchID(), tasks)
+}
+
+// processBatchWithRollback runs the saga for tasks under the given batch ID
+func (bp *BatchProcessor) processBatchWithRollback(ctx context.Context, batchID string, tasks []*domain.TransactionTask) (*BatchResult, error) {
+	spanCtx, span := otel.Tracer("batch-processor").Start(ctx, "process-batch-with-rollback")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("batch.id", batchID),
+		attribute.Int("batch.size", len(tasks)),
+	)
+
+	startTime := time.Now()
+	result := &BatchResult{
+		BatchID:    batchID,
+		TotalTasks: len(tasks),
+	}
+
+	// The timeout is only checked between tasks; each task is awaited in full so
+	// the saga always knows whether it needs compensating
+	deadline := time.Now().Add(bp.batchTimeout)
+
+	var completed []*domain.TransactionTask
+	var failure *BatchError
+	for i, task := range tasks {
+		if time.Now().After(deadline) || spanCtx.Err() != nil {
+			result.TimedOut = true
+			failure = &BatchError{TaskID: task.ID, Error: "batch timed out before the task started"}
+			break
+		}
+
+		res, err := bp.transactionProcessor.SubmitTaskAndWait(spanCtx, task)
+		if err != nil {
+			failure = &BatchError{TaskID: task.ID, Error: fmt.Sprintf("failed to submit task: %s", err.Error())}
+		} else if !res.Success {
+			failure = &BatchError{TaskID: task.ID, Error: res.Message}
+		}
+		if failure != nil {
+			log.Warn().Str("batch_id", batchID).Str("task_id", task.ID).Int("index", i).Str("error", failure.Error).Msg("Batch task failed, rolling back")
+			break
+		}
+		completed = append(completed, task)
+	}
+
+	if failure == nil {
+		result.SuccessfulTasks = len(completed)
+	} else {
+		// Every task is reported as failed: the one that broke the saga, the ones
+		// that never ran and the ones that were rolled back
+		result.FailedTasks = len(tasks)
+		result.Errors = append(result.Errors, *failure)
+		result.Errors = append(result.Errors, bp.compensate(spanCtx, batchID, completed)...)
+		result.RolledBack = true
+		span.SetAttributes(attribute.String("batch.failed_task_id", failure.TaskID))
+	}
+
+	result.ProcessingTime = time.Since(startTime)
+	result.CompletedAt = time.Now()
+
+	span.SetAttributes(
+		attribute.Int("successful_tasks", result.SuccessfulTasks),
+		attribute.Int("failed_tasks", result.FailedTasks),
+		attribute.Bool("rolled_back", result.RolledBack),
+	)
+
+	log.Info().
+		Str("batch_id", batchID).
+		Int("total_tasks", result.TotalTasks).
+		Int("successful_tasks", result.SuccessfulTasks).
+		Int("failed_tasks", result.FailedTasks).
+		Bool("rolled_back", result.RolledBack).
+		Dur("processing_time", result.ProcessingTime).
+		Msg("Batch processing with rollback completed")
+
+	return result, nil
+}
+
+// compensate undoes completed tasks in reverse order.
+func (bp *BatchProcessor) compensate(ctx context.Context, batchID string, completed []*domain.TransactionTask) []BatchError {
+	var failures []BatchError
+	for i := len(completed) - 1; i >= 0; i-- {
+		task := completed[i]
+
+		compensation, err := compensationFor(task)
+		if err == nil {
+			var res *domain.TransactionResult
+			res, err = bp.transactionProcessor.SubmitTaskAndWait(ctx, compensation)
+			if err == nil && !res.Success {
+				err = errors.New(res.Message)
+			}
+		}
+
+		if err != nil {
+			log.Error().Err(err).Str("batch_id", batchID).Str("task_id", task.ID).Msg("Compensation failed, manual intervention required")
+			failures = append(failures, BatchError{TaskID: task.ID, Error: "compensation failed: " + err.Error()})
+			continue
+		}
+		log.Info().Str("batch_id", batchID).Str("task_id", task.ID).Str("compensation_task_id", compensation.ID).Msg("Task compensated")
+	}
+	return failures
+}
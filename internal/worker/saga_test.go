@@ -0,0 +1,136 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// ledgerTransactionService keeps in-memory balances and rejects overdrafts
+type ledgerTransactionService struct {
+	recordingTransactionService
+	mu       sync.Mutex
+	balances map[int]float64
+	nextID   int
+}
+
+func newLedgerTransactionService() *ledgerTransactionService {
+	return &ledgerTransactionService{balances: map[int]float64{}}
+}
+
+func (s *ledgerTransactionService) apply(txType string, changes map[int]float64) (*domain.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for userID, delta := range changes {
+		if s.balances[userID]+delta < 0 {
+			return nil, errors.New("insufficient balance")
+		}
+	}
+	for userID, delta := range changes {
+		s.balances[userID] += delta
+	}
+	s.nextID++
+	return &domain.Transaction{ID: s.nextID, Type: txType}, nil
+}
+
+func (s *ledgerTransactionService) Credit(ctx context.Context, userID int, amount float64, idempotencyKey string) (*domain.Transaction, error) {
+	return s.apply("credit", map[int]float64{userID: amount})
+}
+
+func (s *ledgerTransactionService) Debit(ctx context.Context, userID int, amount float64, idempotencyKey string) (*domain.Transaction, error) {
+	return s.apply("debit", map[int]float64{userID: -amount})
+}
+
+func (s *ledgerTransactionService) Transfer(ctx context.Context, fromUserID, toUserID int, amount float64, idempotencyKey string) (*domain.Transaction, error) {
+	return s.apply("transfer", map[int]float64{fromUserID: -amount, toUserID: amount})
+}
+
+func (s *ledgerTransactionService) balance(userID int) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.balances[userID]
+}
+
+func TestCompensationFor(t *testing.T) {
+	toUserID := 2
+
+	credit, err := compensationFor(&domain.TransactionTask{ID: "t1", Type: "credit", UserID: 1, Amount: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "debit", credit.Type)
+	assert.Equal(t, 1, credit.UserID)
+
+	debit, err := compensationFor(&domain.TransactionTask{ID: "t2", Type: "debit", UserID: 1, Amount: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "credit", debit.Type)
+
+	transfer, err := compensationFor(&domain.TransactionTask{ID: "t3", Type: "transfer", UserID: 1, ToUserID: &toUserID, Amount: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "transfer", transfer.Type)
+	assert.Equal(t, 2, transfer.UserID)
+	require.NotNil(t, transfer.ToUserID)
+	assert.Equal(t, 1, *transfer.ToUserID)
+	assert.Equal(t, float64(10), transfer.Amount)
+
+	_, err = compensationFor(&domain.TransactionTask{ID: "t4", Type: "transfer", UserID: 1, Amount: 10})
+	assert.Error(t, err)
+}
+
+func TestBatchProcessor_RollsBackCompletedTasksOnFailure(t *testing.T) {
+	ledger := newLedgerTransactionService()
+	processor := NewTransactionProcessor(ledger, nil, nil, nil, nil, 2, 10, fastRetryPolicy(1))
+
+	ctx := context.Background()
+	require.NoError(t, processor.Start(ctx))
+	defer processor.Stop(ctx)
+
+	toUserID := 2
+	tasks := []*domain.TransactionTask{
+		{ID: "credit-1", Type: "credit", UserID: 1, Amount: 100},
+		{ID: "transfer-1", Type: "transfer", UserID: 1, ToUserID: &toUserID, Amount: 40},
+		{ID: "debit-3", Type: "debit", UserID: 3, Amount: 10}, // user 3 has no funds
+		{ID: "credit-4", Type: "credit", UserID: 4, Amount: 5},
+	}
+
+	bp := NewBatchProcessor(processor, nil, nil, 2, 5*time.Second)
+	result, err := bp.ProcessBatchWithRollback(ctx, tasks)
+	require.NoError(t, err)
+
+	assert.True(t, result.RolledBack)
+	assert.Equal(t, 0, result.SuccessfulTasks)
+	assert.Equal(t, 4, result.FailedTasks)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "debit-3", result.Errors[0].TaskID)
+
+	for _, userID := range []int{1, 2, 3, 4} {
+		assert.Equal(t, float64(0), ledger.balance(userID), "balance of user %d", userID)
+	}
+}
+
+func TestBatchProcessor_RollbackNotNeededWhenAllSucceed(t *testing.T) {
+	ledger := newLedgerTransactionService()
+	processor := NewTransactionProcessor(ledger, nil, nil, nil, nil, 2, 10, fastRetryPolicy(1))
+
+	ctx := context.Background()
+	require.NoError(t, processor.Start(ctx))
+	defer processor.Stop(ctx)
+
+	tasks := []*domain.TransactionTask{
+		{ID: "credit-1", Type: "credit", UserID: 1, Amount: 100},
+		{ID: "debit-1", Type: "debit", UserID: 1, Amount: 30},
+	}
+
+	bp := NewBatchProcessor(processor, nil, nil, 2, 5*time.Second)
+	result, err := bp.ProcessBatchWithRollback(ctx, tasks)
+	require.NoError(t, err)
+
+	assert.False(t, result.RolledBack)
+	assert.Equal(t, 2, result.SuccessfulTasks)
+	assert.Equal(t, float64(70), ledger.balance(1))
+}
@@ -14,19 +14,27 @@ import (
 
 	"github.com/melihgurlek/backend-path/internal/domain"
 	"github.com/melihgurlek/backend-path/pkg/metrics"
+	"github.com/melihgurlek/backend-path/pkg/tracing"
 )
 
+// drainPollInterval is how often Stop checks whether the queue has drained
+const drainPollInterval = 20 * time.Millisecond
+
 // TransactionProcessorImpl implements domain.TransactionProcessor
 type TransactionProcessorImpl struct {
 	transactionService domain.TransactionService
 	balanceService     domain.BalanceService
+	taskRepo           domain.TaskRepository
+	deadLetters        domain.DeadLetterRepository
+	callbacks          *CallbackSender // nil disables completion callbacks
 
 	// Worker pool configuration
-	numWorkers int
-	queueSize  int
+	numWorkers  int
+	queueSize   int
+	retryPolicy domain.RetryPolicy // default retries before a task is dead-lettered
 
-	// Channels for task processing
-	taskQueue   chan *domain.TransactionTask
+	// Queues for task processing
+	taskQueue   *priorityQueue
 	resultQueue chan *domain.TransactionResult
 	stopChan    chan struct{}
 
@@ -44,9 +52,18 @@ type TransactionProcessorImpl struct {
 	processTimes     []time.Duration
 	processTimeMutex sync.RWMutex
 
+	// Callers blocked in SubmitTaskAndWait, keyed by task ID
+	waiters     map[string]chan *domain.TransactionResult
+	waitersLock sync.Mutex
+
 	// Context for graceful shutdown
 	ctx    context.Context
 	cancel context.CancelFunc
+
+	// Set between Start and Stop
+	running atomic.Bool
+	// Set once StopIntake has been called; new tasks are refused
+	intakeStopped atomic.Bool
 }
 
 // worker represents a single worker in the pool
@@ -60,20 +77,29 @@ type worker struct {
 func NewTransactionProcessor(
 	transactionService domain.TransactionService,
 	balanceService domain.BalanceService,
+	taskRepo domain.TaskRepository,
+	deadLetters domain.DeadLetterRepository,
+	callbacks *CallbackSender,
 	numWorkers int,
 	queueSize int,
+	retryPolicy domain.RetryPolicy,
 ) *TransactionProcessorImpl {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &TransactionProcessorImpl{
 		transactionService: transactionService,
 		balanceService:     balanceService,
+		taskRepo:           taskRepo,
+		deadLetters:        deadLetters,
+		callbacks:          callbacks,
 		numWorkers:         numWorkers,
 		queueSize:          queueSize,
-		taskQueue:          make(chan *domain.TransactionTask, queueSize),
+		retryPolicy:        retryPolicy,
+		taskQueue:          newPriorityQueue(queueSize),
 		resultQueue:        make(chan *domain.TransactionResult, queueSize),
 		stopChan:           make(chan struct{}),
 		workers:            make([]*worker, 0, numWorkers),
+		waiters:            make(map[string]chan *domain.TransactionResult),
 		ctx:                ctx,
 		cancel:             cancel,
 	}
@@ -98,14 +124,28 @@ func (p *TransactionProcessorImpl) Start(ctx context.Context) error {
 
 	// Start result processor
 	go p.processResults()
+	p.running.Store(true)
 
 	log.Info().Msg("Transaction processor started successfully")
 	return nil
 }
 
-// Stop gracefully stops the worker pool
+// StopIntake refuses tasks submitted from now on with domain.ErrProcessorStopped.
+func (p *TransactionProcessorImpl) StopIntake() {
+	if !p.intakeStopped.Swap(true) {
+		log.Info().Int("queued", p.taskQueue.len()).Msg("Transaction processor no longer accepting tasks")
+	}
+}
+
+// Stop gracefully stops the worker pool.
 func (p *TransactionProcessorImpl) Stop(ctx context.Context) error {
 	log.Info().Msg("Stopping transaction processor")
+	p.StopIntake()
+	p.running.Store(false)
+
+	if err := p.drain(ctx); err != nil {
+		log.Warn().Err(err).Int("abandoned", p.taskQueue.len()).Msg("Stopped draining the task queue")
+	}
 
 	// Signal all workers to stop
 	close(p.stopChan)
@@ -114,20 +154,37 @@ func (p *TransactionProcessorImpl) Stop(ctx context.Context) error {
 	// Wait for all workers to finish
 	p.workerWg.Wait()
 
-	// Close channels
-	close(p.taskQueue)
+	// Close the result channel; tasks still queued are abandoned
 	close(p.resultQueue)
 
 	log.Info().Msg("Transaction processor stopped successfully")
 	return nil
 }
 
+// drain waits until the queue is empty and no worker is busy, or ctx is done
+func (p *TransactionProcessorImpl) drain(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for p.taskQueue.len() > 0 || atomic.LoadInt32(&p.activeWorkers) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
 // SubmitTask submits a transaction task to the processing queue
 func (p *TransactionProcessorImpl) SubmitTask(ctx context.Context, task *domain.TransactionTask) error {
 	if task == nil {
 		return errors.New("task cannot be nil")
 	}
 
+	if p.intakeStopped.Load() {
+		return domain.ErrProcessorStopped
+	}
+
 	if task.ID == "" {
 		return errors.New("task ID cannot be empty")
 	}
@@ -136,9 +193,12 @@ func (p *TransactionProcessorImpl) SubmitTask(ctx context.Context, task *domain.
 		return errors.New("task amount must be positive")
 	}
 
-	// Create span for tracing
-	_, span := otel.Tracer("transaction-processor").Start(ctx, "submit-task")
+	// Create span for tracing; the task's processing span becomes its child
+	ctx, span := otel.Tracer("transaction-processor").Start(ctx, "submit-task")
 	defer span.End()
+	if task.TraceParent == "" {
+		task.TraceParent = tracing.TraceParent(ctx)
+	}
 
 	span.SetAttributes(
 		attribute.String("task.id", task.ID),
@@ -148,18 +208,240 @@ func (p *TransactionProcessorImpl) SubmitTask(ctx context.Context, task *domain.
 		attribute.Int("task.priority", task.Priority),
 	)
 
+	// Record the task before it is queued so a worker never updates a missing record
+	record := domain.NewTaskRecord(task)
+	if p.taskRepo != nil {
+		if err := p.taskRepo.Create(ctx, record); err != nil {
+			span.RecordError(err)
+			return fmt.Errorf("failed to record task: %w", err)
+		}
+	}
+
 	// Try to submit task to queue with timeout
-	select {
-	case p.taskQueue <- task:
-		log.Debug().Str("task_id", task.ID).Msg("Task submitted to queue")
-		metrics.TransactionQueueSize.Set(float64(len(p.taskQueue)))
+	err := p.taskQueue.push(ctx, task, 5*time.Second)
+	if err == nil {
+		log.Debug().Str("task_id", task.ID).Int("priority", task.Priority).Msg("Task submitted to queue")
+		metrics.TransactionQueueSize.Set(float64(p.taskQueue.len()))
 		return nil
-	case <-time.After(5 * time.Second):
-		span.RecordError(errors.New("queue timeout"))
-		return errors.New("queue is full, task submission timeout")
+	}
+
+	span.RecordError(err)
+	p.finishTaskRecord(ctx, record, err, nil)
+	return err
+}
+
+// SubmitTaskAndWait submits a task and blocks until it finishes or ctx is done.
+func (p *TransactionProcessorImpl) SubmitTaskAndWait(ctx context.Context, task *domain.TransactionTask) (*domain.TransactionResult, error) {
+	if task == nil {
+		return nil, errors.New("task cannot be nil")
+	}
+
+	// Register before submitting so a fast worker can't finish first
+	done := make(chan *domain.TransactionResult, 1)
+	p.waitersLock.Lock()
+	p.waiters[task.ID] = done
+	p.waitersLock.Unlock()
+	defer func() {
+		p.waitersLock.Lock()
+		delete(p.waiters, task.ID)
+		p.waitersLock.Unlock()
+	}()
+
+	if err := p.SubmitTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	select {
+	case result := <-done:
+		return result, nil
 	case <-ctx.Done():
-		span.RecordError(ctx.Err())
-		return ctx.Err()
+		return nil, ctx.Err()
+	}
+}
+
+// notifyWaiter hands a final result to the caller waiting on its task, if any
+func (p *TransactionProcessorImpl) notifyWaiter(result *domain.TransactionResult) {
+	p.waitersLock.Lock()
+	done, ok := p.waiters[result.TaskID]
+	p.waitersLock.Unlock()
+
+	if ok {
+		select {
+		case done <- result:
+		default:
+		}
+	}
+}
+
+// ListDeadLetters returns tasks that failed after all retries
+func (p *TransactionProcessorImpl) ListDeadLetters(ctx context.Context, includeRequeued bool, limit, offset int) ([]*domain.DeadLetter, error) {
+	if p.deadLetters == nil {
+		return nil, errors.New("dead-letter queue is not configured")
+	}
+	return p.deadLetters.List(ctx, includeRequeued, limit, offset)
+}
+
+// RequeueDeadLetter resubmits a dead-lettered task under its original ID with a fresh attempt count
+func (p *TransactionProcessorImpl) RequeueDeadLetter(ctx context.Context, id int) (*domain.TransactionTask, error) {
+	if p.deadLetters == nil {
+		return nil, errors.New("dead-letter queue is not configured")
+	}
+
+	entry, err := p.deadLetters.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead letter: %w", err)
+	}
+	if entry == nil {
+		return nil, domain.ErrDeadLetterNotFound
+	}
+	if entry.RequeuedAt != nil {
+		return nil, domain.ErrDeadLetterRequeued
+	}
+
+	task := entry.Task()
+	task.TraceParent = tracing.TraceParent(ctx)
+	record := domain.NewTaskRecord(task)
+	if p.taskRepo != nil {
+		if err := p.taskRepo.Update(ctx, record); err != nil {
+			// The original record may have been pruned; start a new one
+			if err := p.taskRepo.Create(ctx, record); err != nil {
+				return nil, fmt.Errorf("failed to record task: %w", err)
+			}
+		}
+	}
+
+	if err := p.taskQueue.push(ctx, task, 5*time.Second); err != nil {
+		p.finishTaskRecord(ctx, record, err, nil)
+		return nil, err
+	}
+	metrics.TransactionQueueSize.Set(float64(p.taskQueue.len()))
+
+	if err := p.deadLetters.MarkRequeued(ctx, id); err != nil {
+		log.Warn().Err(err).Int("dead_letter_id", id).Msg("Requeued task but failed to mark dead letter")
+	}
+
+	log.Info().Int("dead_letter_id", id).Str("task_id", task.ID).Msg("Dead-lettered task requeued")
+	return task, nil
+}
+
+// retryTask requeues a failed transient task after a backoff delay.
+func (p *TransactionProcessorImpl) retryTask(ctx context.Context, task *domain.TransactionTask, record *domain.TaskRecord, taskErr error) bool {
+	policy := retryPolicyFor(task, p.retryPolicy)
+	if !IsRetryable(taskErr) || !policy.CanRetry(task.Attempts-1) {
+		return false
+	}
+	delay := policy.Backoff(task.Attempts - 1)
+
+	record.Status = "queued"
+	record.Error = taskErr.Error()
+	record.Attempts = task.Attempts
+	if p.taskRepo != nil {
+		if err := p.taskRepo.Update(ctx, record); err != nil {
+			log.Warn().Err(err).Str("task_id", task.ID).Msg("Failed to record task retry")
+		}
+	}
+
+	metrics.TaskRetries.WithLabelValues(task.Type).Inc()
+	log.Warn().Err(taskErr).Str("task_id", task.ID).Int("attempt", task.Attempts).Dur("backoff", delay).Msg("Task failed, retrying after backoff")
+
+	// Wait and requeue from a separate goroutine so the worker stays free and
+	// never blocks on a full queue it is responsible for draining
+	go func() {
+		defer metrics.TrackGoroutine("worker_retry")()
+		// The record must still be written after shutdown has cancelled p.ctx
+		recordCtx := context.WithoutCancel(ctx)
+		select {
+		case <-time.After(delay):
+		case <-p.ctx.Done():
+			// Shutting down; park the task so it can be requeued later
+			p.abandonRetry(recordCtx, task, record, taskErr)
+			return
+		}
+
+		if err := p.taskQueue.push(p.ctx, task, 5*time.Second); err != nil {
+			log.Error().Err(err).Str("task_id", task.ID).Msg("Failed to requeue task for retry")
+			p.abandonRetry(recordCtx, task, record, taskErr)
+		}
+	}()
+	return true
+}
+
+// abandonRetry fails a task whose retry could not be scheduled
+func (p *TransactionProcessorImpl) abandonRetry(ctx context.Context, task *domain.TransactionTask, record *domain.TaskRecord, taskErr error) {
+	p.finishTaskRecord(ctx, record, taskErr, nil)
+	p.deadLetter(ctx, task, taskErr)
+	p.notifyWaiter(&domain.TransactionResult{
+		TaskID:      task.ID,
+		Success:     false,
+		Error:       taskErr,
+		Message:     taskErr.Error(),
+		CallbackURL: task.CallbackURL,
+		Timestamp:   time.Now().Unix(),
+	})
+}
+
+// deadLetter moves an exhausted task to the dead-letter store
+func (p *TransactionProcessorImpl) deadLetter(ctx context.Context, task *domain.TransactionTask, taskErr error) {
+	metrics.TaskDeadLettered.WithLabelValues(task.Type).Inc()
+	if p.deadLetters == nil {
+		return
+	}
+
+	entry := domain.NewDeadLetter(task, taskErr)
+	if err := p.deadLetters.Create(ctx, entry); err != nil {
+		log.Error().Err(err).Str("task_id", task.ID).Msg("Failed to dead-letter task")
+		return
+	}
+	log.Warn().Str("task_id", task.ID).Int("dead_letter_id", entry.ID).Int("attempts", task.Attempts).Msg("Task moved to dead-letter queue")
+}
+
+// GetTask returns the recorded status of a submitted task, or nil if unknown
+func (p *TransactionProcessorImpl) GetTask(ctx context.Context, taskID string) (*domain.TaskRecord, error) {
+	if p.taskRepo == nil {
+		return nil, errors.New("task tracking is not configured")
+	}
+	return p.taskRepo.GetByID(ctx, taskID)
+}
+
+// startTaskRecord marks a task as picked up by a worker
+func (p *TransactionProcessorImpl) startTaskRecord(ctx context.Context, task *domain.TransactionTask) *domain.TaskRecord {
+	record := domain.NewTaskRecord(task)
+	if p.taskRepo == nil {
+		return record
+	}
+
+	if stored, err := p.taskRepo.GetByID(ctx, task.ID); err == nil && stored != nil {
+		record = stored
+	}
+
+	now := time.Now().UTC()
+	record.Status = "processing"
+	record.StartedAt = &now
+	if err := p.taskRepo.Update(ctx, record); err != nil {
+		log.Warn().Err(err).Str("task_id", task.ID).Msg("Failed to record task start")
+	}
+	return record
+}
+
+// finishTaskRecord stores the outcome of a task
+func (p *TransactionProcessorImpl) finishTaskRecord(ctx context.Context, record *domain.TaskRecord, taskErr error, transactionID *int) {
+	if p.taskRepo == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	record.CompletedAt = &now
+	record.TransactionID = transactionID
+	if taskErr != nil {
+		record.Status = "failed"
+		record.Error = taskErr.Error()
+	} else {
+		record.Status = "succeeded"
+		record.Error = ""
+	}
+
+	if err := p.taskRepo.Update(ctx, record); err != nil {
+		log.Warn().Err(err).Str("task_id", record.TaskID).Msg("Failed to record task outcome")
 	}
 }
 
@@ -181,25 +463,38 @@ func (p *TransactionProcessorImpl) GetStats() *domain.ProcessingStats {
 		TotalProcessed:     atomic.LoadInt64(&p.totalProcessed),
 		SuccessfulTasks:    atomic.LoadInt64(&p.successfulTasks),
 		FailedTasks:        atomic.LoadInt64(&p.failedTasks),
-		QueueSize:          len(p.taskQueue),
+		QueueSize:          p.taskQueue.len(),
 		ActiveWorkers:      int(atomic.LoadInt32(&p.activeWorkers)),
 		AverageProcessTime: avgProcessTime,
 	}
 }
 
+// CheckHealth reports an error if the pool is not running or its queue is full
+func (p *TransactionProcessorImpl) CheckHealth(ctx context.Context) error {
+	if !p.running.Load() {
+		return errors.New("worker pool is not running")
+	}
+	if queued := p.taskQueue.len(); queued >= p.queueSize {
+		return fmt.Errorf("task queue is full (%d tasks)", queued)
+	}
+	return nil
+}
+
 // start starts a worker goroutine
 func (w *worker) start() {
 	defer w.processor.workerWg.Done()
+	defer metrics.TrackGoroutine("worker")()
 
 	log.Debug().Int("worker_id", w.id).Msg("Worker started")
 
+	// A task that has started runs to completion even if shutdown cancels w.ctx
+	ctx := context.WithoutCancel(w.ctx)
 	for {
 		select {
-		case task := <-w.processor.taskQueue:
-			if task == nil {
-				return
-			}
-			w.processTask(task)
+		case <-w.processor.taskQueue.readyChan():
+			task := w.processor.taskQueue.pop()
+			metrics.TransactionQueueSize.Set(float64(w.processor.taskQueue.len()))
+			w.processTask(ctx, task)
 		case <-w.processor.stopChan:
 			log.Debug().Int("worker_id", w.id).Msg("Worker stopping")
 			return
@@ -211,13 +506,14 @@ func (w *worker) start() {
 }
 
 // processTask processes a single transaction task
-func (w *worker) processTask(task *domain.TransactionTask) {
+func (w *worker) processTask(ctx context.Context, task *domain.TransactionTask) {
 	startTime := time.Now()
 	atomic.AddInt32(&w.processor.activeWorkers, 1)
 	defer atomic.AddInt32(&w.processor.activeWorkers, -1)
 
-	// Create span for tracing
-	_, span := otel.Tracer("transaction-processor").Start(context.Background(), "process-task")
+	// Create span for tracing, continuing the trace of the request that submitted the task
+	ctx = tracing.ContextWithTraceParent(ctx, task.TraceParent)
+	ctx, span := otel.Tracer("transaction-processor").Start(ctx, "process-task")
 	defer span.End()
 
 	span.SetAttributes(
@@ -229,26 +525,42 @@ func (w *worker) processTask(task *domain.TransactionTask) {
 	)
 
 	result := &domain.TransactionResult{
-		TaskID:    task.ID,
-		Timestamp: time.Now().Unix(),
+		TaskID:      task.ID,
+		CallbackURL: task.CallbackURL,
+		Timestamp:   time.Now().Unix(),
 	}
+	record := w.processor.startTaskRecord(ctx, task)
 
-	// Process the task based on type
+	// Process the task based on type. Keying on the task ID means a retry or
+	// requeue of a task that already went through doesn't move money twice.
+	var tx *domain.Transaction
 	var err error
+	idempotencyKey := "task:" + task.ID
 	switch task.Type {
 	case "credit":
-		err = w.processor.transactionService.Credit(task.UserID, task.Amount)
+		tx, err = w.processor.transactionService.Credit(ctx, task.UserID, task.Amount, idempotencyKey)
 	case "debit":
-		err = w.processor.transactionService.Debit(task.UserID, task.Amount)
+		tx, err = w.processor.transactionService.Debit(ctx, task.UserID, task.Amount, idempotencyKey)
 	case "transfer":
 		if task.ToUserID == nil {
 			err = errors.New("transfer requires to_user_id")
 		} else {
-			err = w.processor.transactionService.Transfer(task.UserID, *task.ToUserID, task.Amount)
+			tx, err = w.processor.transactionService.Transfer(ctx, task.UserID, *task.ToUserID, task.Amount, idempotencyKey)
 		}
 	default:
 		err = fmt.Errorf("unknown transaction type: %s", task.Type)
 	}
+	if tx != nil {
+		result.TransactionID = &tx.ID
+	}
+
+	task.Attempts++
+	if err != nil && w.processor.retryTask(ctx, task, record, err) {
+		span.RecordError(err)
+		return
+	}
+	record.Attempts = task.Attempts
+	w.processor.finishTaskRecord(ctx, record, err, result.TransactionID)
 
 	// Record result
 	if err != nil {
@@ -258,6 +570,7 @@ func (w *worker) processTask(task *domain.TransactionTask) {
 		atomic.AddInt64(&w.processor.failedTasks, 1)
 		span.RecordError(err)
 		log.Error().Err(err).Str("task_id", task.ID).Int("worker_id", w.id).Msg("Task processing failed")
+		w.processor.deadLetter(ctx, task, err)
 	} else {
 		result.Success = true
 		result.Message = "Task processed successfully"
@@ -279,12 +592,23 @@ func (w *worker) processTask(task *domain.TransactionTask) {
 
 	span.SetAttributes(attribute.Float64("process_time_seconds", processTime.Seconds()))
 
-	// Send result to result queue
-	select {
-	case w.processor.resultQueue <- result:
-		// Result sent successfully
-	default:
-		log.Warn().Str("task_id", task.ID).Msg("Result queue full, dropping result")
+	w.processor.notifyWaiter(result)
+
+	// Send result to result queue. Results with a callback must not be dropped,
+	// so wait for space unless the processor is shutting down.
+	if result.CallbackURL != "" {
+		select {
+		case w.processor.resultQueue <- result:
+		case <-w.ctx.Done():
+			log.Warn().Str("task_id", task.ID).Msg("Shutting down, callback result dropped")
+		}
+	} else {
+		select {
+		case w.processor.resultQueue <- result:
+			// Result sent successfully
+		default:
+			log.Warn().Str("task_id", task.ID).Msg("Result queue full, dropping result")
+		}
 	}
 
 	// Update metrics
@@ -310,7 +634,27 @@ func (p *TransactionProcessorImpl) processResults() {
 			log.Error().Str("task_id", result.TaskID).Err(result.Error).Msg("Task failed")
 		}
 
-		// Here you could add additional result processing logic
-		// such as sending notifications, updating audit logs, etc.
+		if result.CallbackURL != "" {
+			// Deliver in the background so slow endpoints and retries don't stall the queue
+			go p.deliverCallback(result)
+		}
+	}
+}
+
+// deliverCallback POSTs a finished task's result to its callback URL
+func (p *TransactionProcessorImpl) deliverCallback(result *domain.TransactionResult) {
+	defer metrics.TrackGoroutine("worker_callback")()
+	if p.callbacks == nil {
+		log.Warn().Str("task_id", result.TaskID).Msg("Callbacks are not configured, skipping task callback")
+		return
 	}
+
+	if err := p.callbacks.Send(p.ctx, result.CallbackURL, result); err != nil {
+		metrics.TaskCallbackDeliveries.WithLabelValues("failed").Inc()
+		log.Error().Err(err).Str("task_id", result.TaskID).Str("callback_url", result.CallbackURL).Msg("Task callback delivery failed")
+		return
+	}
+
+	metrics.TaskCallbackDeliveries.WithLabelValues("delivered").Inc()
+	log.Debug().Str("task_id", result.TaskID).Msg("Task callback delivered")
 }
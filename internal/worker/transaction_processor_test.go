@@ -0,0 +1,244 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/melihgurlek/backend-path/internal/domain"
+)
+
+// failingTransactionService fails the first failures credits with err and counts the calls
+type failingTransactionService struct {
+	recordingTransactionService
+	err      error
+	failures int32
+	calls    int32
+}
+
+func (s *failingTransactionService) Credit(ctx context.Context, userID int, amount float64, idempotencyKey string) (*domain.Transaction, error) {
+	if atomic.AddInt32(&s.calls, 1) <= s.failures {
+		return nil, s.err
+	}
+	return &domain.Transaction{ID: 1, Amount: amount, Type: "credit"}, nil
+}
+
+// fastRetryPolicy retries quickly so tests don't wait on real backoff delays
+func fastRetryPolicy(maxAttempts int) domain.RetryPolicy {
+	return domain.RetryPolicy{MaxAttempts: maxAttempts, InitialBackoff: time.Millisecond, Multiplier: 2}
+}
+
+// memoryDeadLetterRepository is an in-memory domain.DeadLetterRepository
+type memoryDeadLetterRepository struct {
+	mu      sync.Mutex
+	entries []*domain.DeadLetter
+	created chan struct{}
+}
+
+func newMemoryDeadLetterRepository() *memoryDeadLetterRepository {
+	return &memoryDeadLetterRepository{created: make(chan struct{}, 10)}
+}
+
+func (r *memoryDeadLetterRepository) Create(ctx context.Context, d *domain.DeadLetter) error {
+	r.mu.Lock()
+	d.ID = len(r.entries) + 1
+	r.entries = append(r.entries, d)
+	r.mu.Unlock()
+	r.created <- struct{}{}
+	return nil
+}
+
+func (r *memoryDeadLetterRepository) GetByID(ctx context.Context, id int) (*domain.DeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.entries {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryDeadLetterRepository) List(ctx context.Context, includeRequeued bool, limit, offset int) ([]*domain.DeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*domain.DeadLetter
+	for _, d := range r.entries {
+		if includeRequeued || d.RequeuedAt == nil {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryDeadLetterRepository) MarkRequeued(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.entries {
+		if d.ID == id {
+			now := time.Now()
+			d.RequeuedAt = &now
+			return nil
+		}
+	}
+	return domain.ErrDeadLetterRequeued
+}
+
+func TestTransactionProcessor_DeadLettersAfterMaxAttempts(t *testing.T) {
+	txService := &failingTransactionService{err: fmt.Errorf("ledger unavailable: %w", context.DeadlineExceeded), failures: 100}
+	dlq := newMemoryDeadLetterRepository()
+	processor := NewTransactionProcessor(txService, nil, nil, dlq, nil, 1, 10, fastRetryPolicy(3))
+
+	ctx := context.Background()
+	require.NoError(t, processor.Start(ctx))
+	defer processor.Stop(ctx)
+
+	require.NoError(t, processor.SubmitTask(ctx, &domain.TransactionTask{
+		ID: "task-1", Type: "credit", UserID: 1, Amount: 5,
+	}))
+
+	select {
+	case <-dlq.created:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for task to be dead-lettered")
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&txService.calls))
+	entries, err := processor.ListDeadLetters(ctx, false, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "task-1", entries[0].TaskID)
+	assert.Equal(t, 3, entries[0].Attempts)
+	assert.Equal(t, "ledger unavailable: context deadline exceeded", entries[0].LastError)
+
+	// Requeueing runs the task again with a fresh attempt budget
+	task, err := processor.RequeueDeadLetter(ctx, entries[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", task.ID)
+
+	_, err = processor.RequeueDeadLetter(ctx, entries[0].ID)
+	assert.ErrorIs(t, err, domain.ErrDeadLetterRequeued)
+
+	_, err = processor.RequeueDeadLetter(ctx, 999)
+	assert.ErrorIs(t, err, domain.ErrDeadLetterNotFound)
+}
+
+func TestTransactionProcessor_RetriesTransientErrorsUntilSuccess(t *testing.T) {
+	txService := &failingTransactionService{err: &pgconn.PgError{Code: "40001"}, failures: 2}
+	dlq := newMemoryDeadLetterRepository()
+	processor := NewTransactionProcessor(txService, nil, nil, dlq, nil, 1, 10, fastRetryPolicy(1))
+
+	ctx := context.Background()
+	require.NoError(t, processor.Start(ctx))
+	defer processor.Stop(ctx)
+
+	// The per-task override allows more attempts than the processor default
+	require.NoError(t, processor.SubmitTask(ctx, &domain.TransactionTask{
+		ID: "task-1", Type: "credit", UserID: 1, Amount: 5, MaxAttempts: 3,
+	}))
+
+	require.Eventually(t, func() bool {
+		return processor.GetStats().SuccessfulTasks == 1
+	}, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&txService.calls))
+	assert.Zero(t, processor.GetStats().FailedTasks)
+	assert.Empty(t, dlq.entries)
+}
+
+func TestTransactionProcessor_DoesNotRetryPermanentErrors(t *testing.T) {
+	txService := &failingTransactionService{err: errors.New("insufficient balance"), failures: 100}
+	dlq := newMemoryDeadLetterRepository()
+	processor := NewTransactionProcessor(txService, nil, nil, dlq, nil, 1, 10, fastRetryPolicy(5))
+
+	ctx := context.Background()
+	require.NoError(t, processor.Start(ctx))
+	defer processor.Stop(ctx)
+
+	require.NoError(t, processor.SubmitTask(ctx, &domain.TransactionTask{
+		ID: "task-1", Type: "credit", UserID: 1, Amount: 5,
+	}))
+
+	select {
+	case <-dlq.created:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for task to be dead-lettered")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&txService.calls))
+}
+
+func TestTransactionProcessor_ProcessingJoinsSubmittersTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	processor := NewTransactionProcessor(&failingTransactionService{}, nil, nil, nil, nil, 1, 10, fastRetryPolicy(1))
+	require.NoError(t, processor.Start(context.Background()))
+	defer processor.Stop(context.Background())
+
+	ctx, request := otel.Tracer("test").Start(context.Background(), "request")
+	result, err := processor.SubmitTaskAndWait(ctx, &domain.TransactionTask{ID: "task-1", Type: "credit", UserID: 1, Amount: 5})
+	request.End()
+	require.NoError(t, err)
+	require.True(t, result.Success)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	require.Eventually(t, func() bool {
+		for _, span := range recorder.Ended() {
+			spans[span.Name()] = span
+		}
+		return spans["process-task"] != nil
+	}, 5*time.Second, 5*time.Millisecond)
+	submit, process := spans["submit-task"], spans["process-task"]
+	require.NotNil(t, submit)
+	assert.Equal(t, request.SpanContext().TraceID(), process.SpanContext().TraceID())
+	assert.Equal(t, submit.SpanContext().SpanID(), process.Parent().SpanID())
+}
+
+func TestTransactionProcessor_CheckHealth(t *testing.T) {
+	processor := NewTransactionProcessor(&recordingTransactionService{}, nil, nil, nil, nil, 1, 10, fastRetryPolicy(1))
+	ctx := context.Background()
+
+	assert.EqualError(t, processor.CheckHealth(ctx), "worker pool is not running")
+
+	require.NoError(t, processor.Start(ctx))
+	assert.NoError(t, processor.CheckHealth(ctx))
+
+	require.NoError(t, processor.Stop(ctx))
+	assert.EqualError(t, processor.CheckHealth(ctx), "worker pool is not running")
+}
+
+func TestTransactionProcessor_StopDrainsQueuedTasks(t *testing.T) {
+	txService := newRecordingTransactionService(5)
+	processor := NewTransactionProcessor(txService, nil, nil, nil, nil, 1, 10, fastRetryPolicy(1))
+	ctx := context.Background()
+	require.NoError(t, processor.Start(ctx))
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, processor.SubmitTask(ctx, &domain.TransactionTask{
+			ID: fmt.Sprintf("task-%d", i), Type: "credit", UserID: 1, Amount: float64(i),
+		}))
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, processor.Stop(stopCtx))
+
+	txService.mu.Lock()
+	assert.Len(t, txService.amounts, 5, "queued tasks are processed before the workers stop")
+	txService.mu.Unlock()
+
+	err := processor.SubmitTask(ctx, &domain.TransactionTask{ID: "late", Type: "credit", UserID: 1, Amount: 1})
+	assert.ErrorIs(t, err, domain.ErrProcessorStopped)
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_scheduled_transactions_goal_id;
+ALTER TABLE scheduled_transactions DROP COLUMN IF EXISTS goal_id;
+
+DROP INDEX IF EXISTS idx_savings_goals_user_id;
+DROP TABLE IF EXISTS savings_goals;
@@ -0,0 +1,13 @@
+ALTER TABLE scheduled_transactions DROP CONSTRAINT IF EXISTS valid_cron_expression;
+
+UPDATE scheduled_transactions SET status = 'cancelled' WHERE recurrence = 'cron' AND status = 'pending';
+UPDATE scheduled_transactions SET recurrence = 'monthly' WHERE recurrence = 'cron';
+
+ALTER TABLE scheduled_transactions
+    DROP CONSTRAINT IF EXISTS scheduled_transactions_recurrence_check;
+
+ALTER TABLE scheduled_transactions
+    ADD CONSTRAINT scheduled_transactions_recurrence_check
+    CHECK (recurrence IN ('daily', 'weekly', 'monthly', 'yearly'));
+
+ALTER TABLE scheduled_transactions DROP COLUMN IF EXISTS cron_expression;
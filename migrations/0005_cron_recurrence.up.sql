@@ -0,0 +1,16 @@
+-- Allow cron-expression recurrence for scheduled transactions
+ALTER TABLE scheduled_transactions
+    ADD COLUMN IF NOT EXISTS cron_expression VARCHAR(255);
+
+ALTER TABLE scheduled_transactions
+    DROP CONSTRAINT IF EXISTS scheduled_transactions_recurrence_check;
+
+ALTER TABLE scheduled_transactions
+    ADD CONSTRAINT scheduled_transactions_recurrence_check
+    CHECK (recurrence IN ('daily', 'weekly', 'monthly', 'yearly', 'cron'));
+
+ALTER TABLE scheduled_transactions
+    ADD CONSTRAINT valid_cron_expression CHECK (
+        (recurrence = 'cron' AND cron_expression IS NOT NULL) OR
+        (recurrence IS DISTINCT FROM 'cron' AND cron_expression IS NULL)
+    );
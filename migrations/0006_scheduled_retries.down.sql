@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_scheduled_transactions_retry_pending;
+ALTER TABLE scheduled_transactions
+    DROP COLUMN IF EXISTS next_retry_at,
+    DROP COLUMN IF EXISTS retry_count;
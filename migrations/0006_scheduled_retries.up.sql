@@ -0,0 +1,6 @@
+-- Track retries of failed scheduled executions
+ALTER TABLE scheduled_transactions
+    ADD COLUMN IF NOT EXISTS retry_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS next_retry_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transactions_retry_pending ON scheduled_transactions(status, next_retry_at) WHERE status = 'pending' AND next_retry_at IS NOT NULL;
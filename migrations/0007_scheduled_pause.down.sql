@@ -0,0 +1,8 @@
+UPDATE scheduled_transactions SET status = 'pending' WHERE status = 'paused';
+
+ALTER TABLE scheduled_transactions
+    DROP CONSTRAINT IF EXISTS scheduled_transactions_status_check;
+
+ALTER TABLE scheduled_transactions
+    ADD CONSTRAINT scheduled_transactions_status_check
+    CHECK (status IN ('pending', 'completed', 'failed', 'cancelled'));
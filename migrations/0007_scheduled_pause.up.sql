@@ -0,0 +1,7 @@
+-- Allow recurring scheduled transactions to be paused
+ALTER TABLE scheduled_transactions
+    DROP CONSTRAINT IF EXISTS scheduled_transactions_status_check;
+
+ALTER TABLE scheduled_transactions
+    ADD CONSTRAINT scheduled_transactions_status_check
+    CHECK (status IN ('pending', 'paused', 'completed', 'failed', 'cancelled'));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_worker_tasks_status;
+DROP INDEX IF EXISTS idx_worker_tasks_user_id;
+DROP TABLE IF EXISTS worker_tasks;
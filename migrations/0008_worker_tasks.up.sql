@@ -0,0 +1,18 @@
+-- Worker Tasks Table: lifecycle and outcome of tasks submitted to the worker pool
+CREATE TABLE IF NOT EXISTS worker_tasks (
+    task_id VARCHAR(64) PRIMARY KEY,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('credit', 'debit', 'transfer')),
+    user_id INTEGER NOT NULL,
+    to_user_id INTEGER,
+    amount DECIMAL(15,2) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'processing', 'succeeded', 'failed')),
+    error TEXT NOT NULL DEFAULT '',
+    transaction_id INTEGER REFERENCES transactions(id) ON DELETE SET NULL,
+    submitted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_worker_tasks_user_id ON worker_tasks(user_id);
+CREATE INDEX IF NOT EXISTS idx_worker_tasks_status ON worker_tasks(status);
@@ -0,0 +1,5 @@
+ALTER TABLE worker_tasks DROP COLUMN IF EXISTS attempts;
+
+DROP INDEX IF EXISTS idx_worker_dead_letters_pending;
+DROP INDEX IF EXISTS idx_worker_dead_letters_failed_at;
+DROP TABLE IF EXISTS worker_dead_letters;
@@ -0,0 +1,21 @@
+-- Dead-letter queue for worker tasks that failed after all retries
+CREATE TABLE IF NOT EXISTS worker_dead_letters (
+    id SERIAL PRIMARY KEY,
+    task_id VARCHAR(64) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    user_id INTEGER NOT NULL,
+    to_user_id INTEGER,
+    amount DECIMAL(15,2) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    callback_url TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    failed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    requeued_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_worker_dead_letters_failed_at ON worker_dead_letters(failed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_worker_dead_letters_pending ON worker_dead_letters(failed_at DESC) WHERE requeued_at IS NULL;
+
+-- Track how many times each task has been attempted
+ALTER TABLE worker_tasks ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
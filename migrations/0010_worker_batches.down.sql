@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_worker_tasks_batch_id;
+ALTER TABLE worker_tasks DROP COLUMN IF EXISTS batch_id;
+DROP TABLE IF EXISTS worker_batches;
@@ -0,0 +1,12 @@
+-- Batches of worker tasks; per-task outcomes stay in worker_tasks
+CREATE TABLE IF NOT EXISTS worker_batches (
+    batch_id VARCHAR(64) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'processing' CHECK (status IN ('processing', 'completed', 'timed_out')),
+    total_tasks INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+ALTER TABLE worker_tasks ADD COLUMN IF NOT EXISTS batch_id VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_worker_tasks_batch_id ON worker_tasks(batch_id) WHERE batch_id IS NOT NULL;
@@ -0,0 +1,8 @@
+UPDATE worker_batches SET status = 'completed' WHERE status = 'rolled_back';
+
+ALTER TABLE worker_batches
+    DROP CONSTRAINT IF EXISTS worker_batches_status_check;
+
+ALTER TABLE worker_batches
+    ADD CONSTRAINT worker_batches_status_check
+    CHECK (status IN ('processing', 'completed', 'timed_out'));
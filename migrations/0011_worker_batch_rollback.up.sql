@@ -0,0 +1,7 @@
+-- Allow batches to be marked as rolled back after saga compensation
+ALTER TABLE worker_batches
+    DROP CONSTRAINT IF EXISTS worker_batches_status_check;
+
+ALTER TABLE worker_batches
+    ADD CONSTRAINT worker_batches_status_check
+    CHECK (status IN ('processing', 'completed', 'timed_out', 'rolled_back'));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_balance_holds_user_active;
+DROP TABLE IF EXISTS balance_holds;
+ALTER TABLE balances DROP COLUMN IF EXISTS held_amount;
@@ -0,0 +1,15 @@
+-- Amount of each balance reserved by active holds
+ALTER TABLE balances ADD COLUMN IF NOT EXISTS held_amount NUMERIC(18,2) NOT NULL DEFAULT 0 CHECK (held_amount >= 0);
+
+-- Authorization holds: reserved funds that are later captured (debited) or released
+CREATE TABLE IF NOT EXISTS balance_holds (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount NUMERIC(18,2) NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'captured', 'released')),
+    transaction_id INTEGER REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_balance_holds_user_active ON balance_holds(user_id) WHERE status = 'active';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transaction_approvals_pending;
+DROP TABLE IF EXISTS transaction_approvals;
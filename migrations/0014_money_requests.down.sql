@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_money_requests_requester;
+DROP INDEX IF EXISTS idx_money_requests_payer;
+DROP TABLE IF EXISTS money_requests;
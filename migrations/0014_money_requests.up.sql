@@ -0,0 +1,16 @@
+-- Requests from one user asking another user to send them money
+CREATE TABLE IF NOT EXISTS money_requests (
+    id SERIAL PRIMARY KEY,
+    requester_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    payer_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount NUMERIC(18,2) NOT NULL CHECK (amount > 0),
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled')),
+    transaction_id INTEGER REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMP WITH TIME ZONE,
+    CHECK (requester_id <> payer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_money_requests_payer ON money_requests(payer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_money_requests_requester ON money_requests(requester_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_payment_links_creator;
+DROP TABLE IF EXISTS payment_links;
@@ -0,0 +1,15 @@
+-- Shareable one-time payment links; the signed token handed out is derived from id and expires_at
+CREATE TABLE IF NOT EXISTS payment_links (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount NUMERIC(18,2) NOT NULL CHECK (amount > 0),
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'redeemed')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    redeemed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    transaction_id INTEGER REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    redeemed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_links_creator ON payment_links(creator_id, created_at DESC);
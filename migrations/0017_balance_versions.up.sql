@@ -0,0 +1,2 @@
+-- Row version for optimistic concurrency; every balance write bumps it and only succeeds against the version it read
+ALTER TABLE balances ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1 CHECK (version > 0);
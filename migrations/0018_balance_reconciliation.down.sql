@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_reconciliation_issues_run;
+DROP TABLE IF EXISTS reconciliation_issues;
+DROP TABLE IF EXISTS balance_snapshots;
+DROP TABLE IF EXISTS reconciliation_runs;
@@ -0,0 +1,34 @@
+-- Nightly reconciliation of stored balances against the transaction ledger
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id SERIAL PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'completed', 'failed')),
+    balances_checked INTEGER NOT NULL DEFAULT 0,
+    issues_found INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Stored balances as they were at the start of a run
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    run_id INTEGER NOT NULL REFERENCES reconciliation_runs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount NUMERIC(18,2) NOT NULL,
+    held_amount NUMERIC(18,2) NOT NULL,
+    version INTEGER NOT NULL,
+    taken_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (run_id, user_id)
+);
+
+-- Balances whose stored amount disagrees with the sum of their completed transactions
+CREATE TABLE IF NOT EXISTS reconciliation_issues (
+    id SERIAL PRIMARY KEY,
+    run_id INTEGER NOT NULL REFERENCES reconciliation_runs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    stored_amount NUMERIC(18,2) NOT NULL,
+    computed_amount NUMERIC(18,2) NOT NULL,
+    difference NUMERIC(18,2) NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reconciliation_issues_run ON reconciliation_issues(run_id);
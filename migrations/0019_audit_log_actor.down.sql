@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_audit_logs_entity;
+DROP INDEX IF EXISTS idx_audit_logs_actor;
+DROP INDEX IF EXISTS idx_audit_logs_created_at;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS actor_id;
@@ -0,0 +1,2 @@
+-- When a user's personal data was erased; the row stays for transaction integrity
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP WITH TIME ZONE;
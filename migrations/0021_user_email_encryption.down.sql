@@ -0,0 +1,5 @@
+-- Encrypted emails must be decrypted back to plaintext before this runs,
+-- otherwise they won't fit the original column type.
+DROP INDEX IF EXISTS idx_users_email_hash;
+ALTER TABLE users DROP COLUMN IF EXISTS email_hash;
+ALTER TABLE users ALTER COLUMN email TYPE VARCHAR(100);
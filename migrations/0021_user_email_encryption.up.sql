@@ -0,0 +1,6 @@
+-- Emails are stored encrypted (pkg/crypto envelopes), which no longer fit in VARCHAR(100).
+-- email_hash is a keyed blind index used for lookups and uniqueness instead of the ciphertext.
+ALTER TABLE users ALTER COLUMN email TYPE TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_hash CHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_hash ON users(email_hash);
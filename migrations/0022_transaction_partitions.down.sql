@@ -0,0 +1,40 @@
+DROP TRIGGER IF EXISTS transactions_idempotency_key ON transactions;
+DROP FUNCTION IF EXISTS record_transaction_idempotency_key();
+DROP TABLE IF EXISTS transaction_idempotency_keys;
+
+ALTER TABLE transactions RENAME TO transactions_partitioned;
+ALTER SEQUENCE transactions_id_seq OWNED BY NONE;
+
+CREATE TABLE transactions (
+    id INTEGER PRIMARY KEY DEFAULT nextval('transactions_id_seq'),
+    from_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    to_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    amount NUMERIC(18,2) NOT NULL CHECK (amount > 0),
+    type VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    idempotency_key VARCHAR(255)
+);
+
+ALTER SEQUENCE transactions_id_seq OWNED BY transactions.id;
+
+INSERT INTO transactions (id, from_user_id, to_user_id, amount, type, status, created_at, idempotency_key)
+SELECT id, from_user_id, to_user_id, amount, type, status, created_at, idempotency_key FROM transactions_partitioned;
+
+-- Drops every attached partition with it; detached ones are left alone
+DROP TABLE transactions_partitioned;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_idempotency_key ON transactions(idempotency_key) WHERE idempotency_key IS NOT NULL;
+
+-- NOT VALID: rows may point at transactions in detached partitions
+
+ALTER TABLE worker_tasks ADD CONSTRAINT worker_tasks_transaction_id_fkey
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL NOT VALID;
+ALTER TABLE balance_holds ADD CONSTRAINT balance_holds_transaction_id_fkey
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL NOT VALID;
+ALTER TABLE transaction_approvals ADD CONSTRAINT transaction_approvals_transaction_id_fkey
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE NOT VALID;
+ALTER TABLE money_requests ADD CONSTRAINT money_requests_transaction_id_fkey
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL NOT VALID;
+ALTER TABLE payment_links ADD CONSTRAINT payment_links_transaction_id_fkey
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL NOT VALID;
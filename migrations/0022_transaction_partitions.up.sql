@@ -0,0 +1,78 @@
+-- Partition transactions by created_at month. A partitioned table's primary key
+-- must include the partition key, so transactions(id) can no longer be the target
+-- of a foreign key; the references from other tables are dropped and kept by the
+-- application instead.
+ALTER TABLE worker_tasks DROP CONSTRAINT IF EXISTS worker_tasks_transaction_id_fkey;
+ALTER TABLE balance_holds DROP CONSTRAINT IF EXISTS balance_holds_transaction_id_fkey;
+ALTER TABLE transaction_approvals DROP CONSTRAINT IF EXISTS transaction_approvals_transaction_id_fkey;
+ALTER TABLE money_requests DROP CONSTRAINT IF EXISTS money_requests_transaction_id_fkey;
+ALTER TABLE payment_links DROP CONSTRAINT IF EXISTS payment_links_transaction_id_fkey;
+
+ALTER TABLE transactions RENAME TO transactions_unpartitioned;
+ALTER SEQUENCE transactions_id_seq OWNED BY NONE;
+
+CREATE TABLE transactions (
+    id INTEGER NOT NULL DEFAULT nextval('transactions_id_seq'),
+    from_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    to_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    amount NUMERIC(18,2) NOT NULL CHECK (amount > 0),
+    type VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    idempotency_key VARCHAR(255),
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+ALTER SEQUENCE transactions_id_seq OWNED BY transactions.id;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_created_at ON transactions(created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_from_user ON transactions(from_user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_to_user ON transactions(to_user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_idempotency_key ON transactions(idempotency_key) WHERE idempotency_key IS NOT NULL;
+
+-- Catches rows outside every monthly partition so inserts never fail; the
+-- maintenance job creates partitions ahead of time so it normally stays empty
+CREATE TABLE IF NOT EXISTS transactions_default PARTITION OF transactions DEFAULT;
+
+-- One partition per month from the oldest transaction through three months ahead
+DO $$
+DECLARE
+    month DATE := date_trunc('month', LEAST(COALESCE((SELECT MIN(created_at) FROM transactions_unpartitioned), NOW()), NOW()));
+BEGIN
+    WHILE month <= date_trunc('month', NOW() + INTERVAL '3 months') LOOP
+        EXECUTE format(
+            'CREATE TABLE IF NOT EXISTS %I PARTITION OF transactions FOR VALUES FROM (%L) TO (%L)',
+            'transactions_p' || to_char(month, 'YYYYMM'), month, month + INTERVAL '1 month');
+        month := month + INTERVAL '1 month';
+    END LOOP;
+END $$;
+
+INSERT INTO transactions (id, from_user_id, to_user_id, amount, type, status, created_at, idempotency_key)
+SELECT id, from_user_id, to_user_id, amount, type, status, created_at, idempotency_key FROM transactions_unpartitioned;
+
+DROP TABLE transactions_unpartitioned;
+
+-- A unique index on a partitioned table must include created_at, which would let
+-- the same idempotency key be reused in another month. Keys are kept unique in
+-- this table instead, filled by a trigger so a duplicate insert still fails.
+CREATE TABLE IF NOT EXISTS transaction_idempotency_keys (
+    idempotency_key VARCHAR(255) PRIMARY KEY,
+    transaction_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+INSERT INTO transaction_idempotency_keys (idempotency_key, transaction_id, created_at)
+SELECT idempotency_key, id, created_at FROM transactions WHERE idempotency_key IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION record_transaction_idempotency_key() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO transaction_idempotency_keys (idempotency_key, transaction_id, created_at)
+    VALUES (NEW.idempotency_key, NEW.id, NEW.created_at);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_idempotency_key
+    AFTER INSERT ON transactions
+    FOR EACH ROW WHEN (NEW.idempotency_key IS NOT NULL)
+    EXECUTE FUNCTION record_transaction_idempotency_key();
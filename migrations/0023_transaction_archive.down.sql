@@ -0,0 +1,7 @@
+DROP VIEW IF EXISTS transaction_ledger;
+
+-- Put archived transactions back so rolling back loses nothing
+INSERT INTO transactions (id, from_user_id, to_user_id, amount, type, status, created_at, idempotency_key)
+SELECT id, from_user_id, to_user_id, amount, type, status, created_at, idempotency_key FROM transactions_archive;
+
+DROP TABLE IF EXISTS transactions_archive;
@@ -0,0 +1,22 @@
+-- Transactions moved out of the hot table by the retention job
+CREATE TABLE IF NOT EXISTS transactions_archive (
+    id INTEGER PRIMARY KEY,
+    from_user_id INTEGER,
+    to_user_id INTEGER,
+    amount NUMERIC(18,2) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    idempotency_key VARCHAR(255),
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_archive_from_user ON transactions_archive(from_user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_archive_to_user ON transactions_archive(to_user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_archive_created_at ON transactions_archive(created_at);
+
+-- Every transaction, hot or archived, for computing balances from the full history
+CREATE OR REPLACE VIEW transaction_ledger AS
+SELECT id, from_user_id, to_user_id, amount, type, status, created_at FROM transactions
+UNION ALL
+SELECT id, from_user_id, to_user_id, amount, type, status, created_at FROM transactions_archive;
@@ -0,0 +1,4 @@
+-- When the user last logged in successfully; drives the active user metrics
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_users_last_login_at ON users(last_login_at);
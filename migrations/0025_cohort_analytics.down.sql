@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS monthly_growth;
+DROP TABLE IF EXISTS signup_cohorts;
@@ -0,0 +1,21 @@
+-- Signup cohorts and their retention, recomputed periodically by the cohort job.
+-- A user is retained at N days when they have a completed transaction in the
+-- N days following their first N days; eligible counts the users whose window
+-- has already ended.
+CREATE TABLE IF NOT EXISTS signup_cohorts (
+    cohort_month DATE PRIMARY KEY,
+    signups INTEGER NOT NULL,
+    eligible_7d INTEGER NOT NULL,
+    retained_7d INTEGER NOT NULL,
+    eligible_30d INTEGER NOT NULL,
+    retained_30d INTEGER NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- New and transacting users per month
+CREATE TABLE IF NOT EXISTS monthly_growth (
+    month DATE PRIMARY KEY,
+    new_users INTEGER NOT NULL,
+    active_users INTEGER NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,23 @@
+-- Fraud scores of debits and transfers, taken before they run. Flagged and
+-- held assessments wait in the review queue until an admin resolves them.
+-- transaction_id is filled in once the transaction exists; rejected attempts
+-- never get one. transactions is partitioned, so it is not a foreign key.
+CREATE TABLE IF NOT EXISTS fraud_assessments (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    counterparty_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    type VARCHAR(20) NOT NULL,
+    amount NUMERIC(18,2) NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    decision VARCHAR(10) NOT NULL CHECK (decision IN ('allow', 'flag', 'hold', 'reject')),
+    review_status VARCHAR(10) NOT NULL DEFAULT '' CHECK (review_status IN ('', 'pending', 'cleared', 'confirmed')),
+    reviewed_by INTEGER REFERENCES users(id),
+    review_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_fraud_assessments_review ON fraud_assessments(created_at) WHERE review_status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_fraud_assessments_transaction ON fraud_assessments(transaction_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_user_transactions_user_currency_created_at;
@@ -0,0 +1,2 @@
+-- Limit rules sum or count a user's usage in one currency over a time range
+CREATE INDEX IF NOT EXISTS idx_user_transactions_user_currency_created_at ON user_transactions(user_id, currency, created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_devices;
+DROP TABLE IF EXISTS notification_contacts;
+DROP TABLE IF EXISTS notification_preferences;
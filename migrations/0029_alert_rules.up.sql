@@ -0,0 +1,14 @@
+-- Users' balance and transaction alerts. A low_balance rule fires when the
+-- available balance drops below the threshold, a large_transaction rule when
+-- a single transaction of the user exceeds it.
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('low_balance', 'large_transaction')),
+    threshold NUMERIC(18,2) NOT NULL CHECK (threshold > 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_user ON alert_rules(user_id) WHERE active;
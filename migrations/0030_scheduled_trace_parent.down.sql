@@ -0,0 +1 @@
+ALTER TABLE scheduled_transactions DROP COLUMN IF EXISTS trace_parent;
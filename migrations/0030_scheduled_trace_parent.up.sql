@@ -0,0 +1,4 @@
+-- Remember the trace of the request that created a scheduled transaction so
+-- its executions can link back to it
+ALTER TABLE scheduled_transactions
+    ADD COLUMN IF NOT EXISTS trace_parent VARCHAR(55);
@@ -0,0 +1,11 @@
+-- External references of imported transactions, unique per user so that a file
+-- imported twice skips the rows already imported. They are kept apart from
+-- transactions, whose unique indexes must include created_at, which also keeps
+-- them in place when the transactions are archived.
+CREATE TABLE IF NOT EXISTS transaction_external_refs (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    external_ref VARCHAR(255) NOT NULL,
+    transaction_id INTEGER NOT NULL,
+    imported_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, external_ref)
+);
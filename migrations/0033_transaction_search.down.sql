@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transactions_amount;
+DROP INDEX IF EXISTS idx_transactions_type_status;
+DROP INDEX IF EXISTS idx_transactions_description_trgm;
+ALTER TABLE transactions_archive DROP COLUMN IF EXISTS description;
+ALTER TABLE transactions DROP COLUMN IF EXISTS description;
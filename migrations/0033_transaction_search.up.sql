@@ -0,0 +1,12 @@
+-- Free-text description of a transaction, such as the memo of an imported one
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS description TEXT;
+ALTER TABLE transactions_archive ADD COLUMN IF NOT EXISTS description TEXT;
+
+-- Trigram index so that description substring searches (ILIKE '%...%') use an
+-- index instead of scanning every partition
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS idx_transactions_description_trgm ON transactions USING GIN (description gin_trgm_ops) WHERE description IS NOT NULL;
+
+-- Searches by type and status, and by amount range, without a user to narrow them
+CREATE INDEX IF NOT EXISTS idx_transactions_type_status ON transactions(type, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_amount ON transactions(amount);
@@ -0,0 +1,7 @@
+-- Optional profile fields; avatar_key is the object store key of the avatar
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS first_name TEXT,
+    ADD COLUMN IF NOT EXISTS last_name TEXT,
+    ADD COLUMN IF NOT EXISTS phone TEXT, -- encrypted like the email
+    ADD COLUMN IF NOT EXISTS locale TEXT,
+    ADD COLUMN IF NOT EXISTS avatar_key TEXT;
@@ -0,0 +1,11 @@
+-- Address ranges admins may use admin operations from. An admin without
+-- entries is not restricted.
+CREATE TABLE IF NOT EXISTS admin_ip_allowlists (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    cidr CIDR NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, cidr)
+);
@@ -0,0 +1,7 @@
+ALTER TABLE login_events
+    DROP COLUMN IF EXISTS suspicious,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS city,
+    DROP COLUMN IF EXISTS region,
+    DROP COLUMN IF EXISTS country;
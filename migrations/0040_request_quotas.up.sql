@@ -0,0 +1,15 @@
+-- Caps on the API requests users make per day or month, set for a role or,
+-- overriding it, for a user. Requests are counted in Redis.
+CREATE TABLE IF NOT EXISTS request_quotas (
+    id SERIAL PRIMARY KEY,
+    role TEXT,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    period TEXT NOT NULL CHECK (period IN ('day', 'month')),
+    request_limit BIGINT NOT NULL CHECK (request_limit >= 0),
+    updated_by INTEGER NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((role IS NULL) <> (user_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_request_quotas_role ON request_quotas (role, period) WHERE role IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_request_quotas_user ON request_quotas (user_id, period) WHERE user_id IS NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE transactions_archive DROP COLUMN IF EXISTS fee_schedule_id;
+ALTER TABLE transactions_archive DROP COLUMN IF EXISTS fee;
+ALTER TABLE transactions DROP COLUMN IF EXISTS fee_schedule_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS fee;
+DROP TABLE IF EXISTS fee_schedules;
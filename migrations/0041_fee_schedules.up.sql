@@ -0,0 +1,20 @@
+-- Versioned fee schedules per transaction type, for every role (role '') or
+-- one. A version applies from effective_from until the next one does.
+CREATE TABLE IF NOT EXISTS fee_schedules (
+    id SERIAL PRIMARY KEY,
+    transaction_type VARCHAR(20) NOT NULL CHECK (transaction_type IN ('credit', 'debit', 'transfer')),
+    role TEXT NOT NULL DEFAULT '',
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    tiers JSONB NOT NULL,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (transaction_type, role, effective_from)
+);
+
+-- The fee each transaction was charged, and by which schedule version, so
+-- later versions leave history alone
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fee NUMERIC(18,2) NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fee_schedule_id INTEGER;
+ALTER TABLE transactions_archive ADD COLUMN IF NOT EXISTS fee NUMERIC(18,2) NOT NULL DEFAULT 0;
+ALTER TABLE transactions_archive ADD COLUMN IF NOT EXISTS fee_schedule_id INTEGER;
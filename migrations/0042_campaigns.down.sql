@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS campaign_awards;
+DROP TABLE IF EXISTS campaigns;
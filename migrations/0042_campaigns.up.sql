@@ -0,0 +1,33 @@
+-- Time-boxed promotions crediting a bonus to each user who meets their
+-- condition, until the budget is spent
+CREATE TABLE IF NOT EXISTS campaigns (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    condition VARCHAR(20) NOT NULL CHECK (condition IN ('first_deposit', 'volume_threshold')),
+    threshold NUMERIC(18,2) NOT NULL CHECK (threshold >= 0),
+    bonus NUMERIC(18,2) NOT NULL CHECK (bonus > 0),
+    budget NUMERIC(18,2) NOT NULL,
+    spent NUMERIC(18,2) NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (spent <= budget),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_running ON campaigns (condition, ends_at);
+
+-- One bonus per user and campaign. Transactions are partitioned, so their IDs
+-- are not foreign keys.
+CREATE TABLE IF NOT EXISTS campaign_awards (
+    id SERIAL PRIMARY KEY,
+    campaign_id INTEGER NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    transaction_id INTEGER NOT NULL,
+    bonus_transaction_id INTEGER NOT NULL UNIQUE,
+    amount NUMERIC(18,2) NOT NULL,
+    awarded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (campaign_id, user_id)
+);
@@ -0,0 +1,23 @@
+-- Each user's code for inviting others; assigned when first asked for
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referral_code VARCHAR(16) UNIQUE;
+
+-- Users who registered with another user's referral code. A referral is
+-- rewarded once, when the referee completes a qualifying transaction before
+-- it expires. Transactions are partitioned, so their IDs are not foreign keys.
+CREATE TABLE IF NOT EXISTS referrals (
+    id SERIAL PRIMARY KEY,
+    referrer_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    referee_id INTEGER NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'rewarded')),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    transaction_id INTEGER,
+    referrer_reward NUMERIC(18,2) NOT NULL DEFAULT 0,
+    referee_reward NUMERIC(18,2) NOT NULL DEFAULT 0,
+    referrer_transaction_id INTEGER,
+    referee_transaction_id INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    rewarded_at TIMESTAMP WITH TIME ZONE,
+    CHECK (referrer_id <> referee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer ON referrals (referrer_id, created_at DESC);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS kyc_tier_limits;
+DROP TABLE IF EXISTS kyc_verifications;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS kyc_status,
+    DROP COLUMN IF EXISTS kyc_level;
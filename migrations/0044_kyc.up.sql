@@ -0,0 +1,49 @@
+-- The identity verification level a user reached and the status of their
+-- latest verification
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS kyc_level INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS kyc_status VARCHAR(10) NOT NULL DEFAULT 'none';
+
+-- Identity verifications submitted by users and decided by the KYC provider
+-- or, for those it sends to review, by an admin. Only the last four
+-- characters of document numbers are kept.
+CREATE TABLE IF NOT EXISTS kyc_verifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    level INTEGER NOT NULL CHECK (level IN (1, 2)),
+    status VARCHAR(10) NOT NULL CHECK (status IN ('pending', 'review', 'approved', 'rejected')),
+    provider VARCHAR(20) NOT NULL,
+    provider_ref TEXT,
+    country VARCHAR(2) NOT NULL,
+    document_type VARCHAR(20),
+    document_last4 VARCHAR(4),
+    reason TEXT,
+    reviewed_by INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_verifications_user ON kyc_verifications (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_kyc_verifications_status ON kyc_verifications (status, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kyc_verifications_provider_ref ON kyc_verifications (provider, provider_ref)
+    WHERE provider_ref IS NOT NULL;
+
+-- Limit rules applying to every user at a KYC level, checked by the limits
+-- engine alongside each user's own rules. A level without rules is unlimited.
+CREATE TABLE IF NOT EXISTS kyc_tier_limits (
+    level INTEGER NOT NULL CHECK (level IN (0, 1, 2)),
+    rule_type VARCHAR(32) NOT NULL,
+    limit_amount NUMERIC(18,2) NOT NULL CHECK (limit_amount > 0),
+    "window" INTERVAL,
+    updated_by INTEGER,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (level, rule_type)
+);
+
+INSERT INTO kyc_tier_limits (level, rule_type, limit_amount) VALUES
+    (0, 'max_per_transaction', 1000),
+    (0, 'monthly_total', 5000),
+    (1, 'max_per_transaction', 10000),
+    (1, 'monthly_total', 50000)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,17 @@
+-- Files users upload, such as identity documents. The files live in the
+-- object store under object_key; only their metadata is kept here.
+CREATE TABLE IF NOT EXISTS documents (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('identity', 'proof_of_address', 'bank_statement', 'other')),
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes INTEGER NOT NULL CHECK (size_bytes > 0),
+    sha256 CHAR(64) NOT NULL,
+    scan_status VARCHAR(10) NOT NULL CHECK (scan_status IN ('clean', 'unscanned')),
+    uploaded_by INTEGER NOT NULL,
+    object_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_documents_user ON documents (user_id, created_at DESC);
@@ -0,0 +1,23 @@
+-- Disputes users open on payments they made. Admins triage them from open
+-- to investigating, then close them as resolved, without a refund, or
+-- refunded, reversing the payment with refund_transaction_id. A transaction
+-- is disputed at most once. Transactions are partitioned, so their IDs are
+-- not foreign keys.
+CREATE TABLE IF NOT EXISTS disputes (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL UNIQUE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('unauthorized', 'not_received', 'duplicate', 'incorrect_amount', 'other')),
+    description TEXT NOT NULL,
+    document_ids INTEGER[] NOT NULL DEFAULT '{}',
+    status VARCHAR(15) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'investigating', 'resolved', 'refunded')),
+    resolution TEXT,
+    handled_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    refund_transaction_id INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_disputes_user ON disputes (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_disputes_status ON disputes (status, created_at);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS chargeback_events;
+DROP TABLE IF EXISTS chargebacks;
+DROP TABLE IF EXISTS funded_credits;
+DROP TABLE IF EXISTS funding_sources;
+ALTER TABLE balances DROP COLUMN IF EXISTS frozen_at;
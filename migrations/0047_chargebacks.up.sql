@@ -0,0 +1,71 @@
+-- Set while a balance is frozen; frozen balances can't be debited, moved or
+-- held until an admin unfreezes them
+ALTER TABLE balances ADD COLUMN IF NOT EXISTS frozen_at TIMESTAMP WITH TIME ZONE;
+
+-- Cards and bank accounts outside the system that users fund their balance
+-- from, known by the provider's reference. Removed sources are kept for the
+-- credits they funded.
+CREATE TABLE IF NOT EXISTS funding_sources (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('card', 'bank_account')),
+    provider VARCHAR(20) NOT NULL,
+    external_ref TEXT NOT NULL,
+    last4 VARCHAR(4),
+    status VARCHAR(10) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'removed')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    removed_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (provider, external_ref)
+);
+
+CREATE INDEX IF NOT EXISTS idx_funding_sources_user ON funding_sources (user_id, created_at DESC);
+
+-- Credits paid from a funding source, with the provider's ID of the payment.
+-- reversed_at is set while a chargeback has taken the credit back.
+-- Transactions are partitioned, so their IDs are not foreign keys.
+CREATE TABLE IF NOT EXISTS funded_credits (
+    transaction_id INTEGER PRIMARY KEY,
+    funding_source_id INTEGER NOT NULL REFERENCES funding_sources(id),
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    external_id TEXT NOT NULL,
+    amount NUMERIC(18,2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reversed_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (funding_source_id, external_id)
+);
+
+-- Chargebacks of funded credits. recovered is what was debited from the
+-- user, less than amount when their balance couldn't go negative. They are
+-- received, may be disputed with the provider, and end won, giving the
+-- recovered funds back, or lost. A credit is charged back at most once.
+CREATE TABLE IF NOT EXISTS chargebacks (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL UNIQUE REFERENCES funded_credits(transaction_id),
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    external_id TEXT NOT NULL UNIQUE,
+    reason_code VARCHAR(20) NOT NULL,
+    amount NUMERIC(18,2) NOT NULL CHECK (amount > 0),
+    recovered NUMERIC(18,2) NOT NULL CHECK (recovered >= 0),
+    status VARCHAR(10) NOT NULL DEFAULT 'received' CHECK (status IN ('received', 'disputed', 'won', 'lost')),
+    debit_transaction_id INTEGER,
+    reinstate_transaction_id INTEGER,
+    froze_account BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_chargebacks_user ON chargebacks (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_chargebacks_status ON chargebacks (status, created_at);
+
+-- Every status a chargeback went through, with who moved it and why
+CREATE TABLE IF NOT EXISTS chargeback_events (
+    id SERIAL PRIMARY KEY,
+    chargeback_id INTEGER NOT NULL REFERENCES chargebacks(id) ON DELETE CASCADE,
+    status VARCHAR(10) NOT NULL,
+    note TEXT,
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_chargeback_events_chargeback ON chargeback_events (chargeback_id, id);
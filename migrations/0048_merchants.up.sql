@@ -0,0 +1,37 @@
+-- Users taking payments as a business. Their incoming transfers are paid out
+-- to settlement_source_id, one of their bank accounts, on their schedule once
+-- they reach min_payout.
+CREATE TABLE IF NOT EXISTS merchants (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    business_name VARCHAR(100) NOT NULL,
+    settlement_source_id INTEGER NOT NULL REFERENCES funding_sources(id),
+    payout_schedule VARCHAR(10) NOT NULL CHECK (payout_schedule IN ('daily', 'weekly')),
+    min_payout NUMERIC(18,2) NOT NULL DEFAULT 0 CHECK (min_payout >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Payouts of the transfers a merchant received in [period_start, period_end).
+-- Each is paid by a worker task debiting the merchant; transaction_id is its
+-- debit once paid. A failed payout's period is paid with the next one.
+CREATE TABLE IF NOT EXISTS payouts (
+    id SERIAL PRIMARY KEY,
+    merchant_id INTEGER NOT NULL REFERENCES merchants(user_id) ON DELETE CASCADE,
+    settlement_source_id INTEGER NOT NULL REFERENCES funding_sources(id),
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    transfer_count INTEGER NOT NULL,
+    amount NUMERIC(18,2) NOT NULL CHECK (amount > 0),
+    status VARCHAR(10) NOT NULL DEFAULT 'processing' CHECK (status IN ('processing', 'paid', 'failed')),
+    batch_id TEXT,
+    transaction_id INTEGER,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    CHECK (period_end > period_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payouts_merchant ON payouts (merchant_id, period_end DESC);
+-- A period is paid out once; only a failed payout's period can be tried again
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payouts_period ON payouts (merchant_id, period_start) WHERE status <> 'failed';
+CREATE INDEX IF NOT EXISTS idx_payouts_processing ON payouts (id) WHERE status = 'processing';
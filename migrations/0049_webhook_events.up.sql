@@ -0,0 +1,18 @@
+-- Payment provider webhook events, kept verbatim for audit. A provider
+-- delivers an event at least once; the unique index applies it only once.
+-- transaction_id is the credit or refund it made.
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(20) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'received' CHECK (status IN ('received', 'processed', 'ignored', 'failed')),
+    transaction_id INTEGER,
+    error TEXT,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_events_event ON webhook_events (provider, event_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_events_received ON webhook_events (received_at DESC);
@@ -0,0 +1,22 @@
+-- ACH and SEPA transfers between a balance and one of its user's bank
+-- accounts. Pending transfers settle at settle_at, some business days after
+-- they were made, unless the bank returns them with return_code first.
+-- transaction_id is the debit or credit that settled the transfer.
+CREATE TABLE IF NOT EXISTS bank_transfers (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    funding_source_id INTEGER NOT NULL REFERENCES funding_sources(id),
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('outbound', 'inbound')),
+    rail VARCHAR(10) NOT NULL CHECK (rail IN ('ach', 'sepa')),
+    amount NUMERIC(18,2) NOT NULL CHECK (amount > 0),
+    status VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'settled', 'returned')),
+    return_code VARCHAR(10),
+    return_reason TEXT,
+    transaction_id INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    settle_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_transfers_user ON bank_transfers (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bank_transfers_due ON bank_transfers (settle_at) WHERE status = 'pending';
@@ -0,0 +1,5 @@
+-- Release the keys of archived transactions again, so rolling back the archive
+-- can put them back into transactions
+DELETE FROM transaction_idempotency_keys k
+USING transactions_archive a
+WHERE k.idempotency_key = a.idempotency_key AND k.transaction_id = a.id;
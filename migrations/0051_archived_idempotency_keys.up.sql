@@ -0,0 +1,7 @@
+-- Archiving used to release the idempotency keys of the transactions it moved,
+-- so a retry of an archived transaction ran again. Keys now stay recorded;
+-- record again the keys already released.
+INSERT INTO transaction_idempotency_keys (idempotency_key, transaction_id, created_at)
+SELECT idempotency_key, id, created_at FROM transactions_archive
+WHERE idempotency_key IS NOT NULL
+ON CONFLICT (idempotency_key) DO NOTHING;
@@ -0,0 +1,21 @@
+-- Refunds of payments credited by gateway webhooks, taken back from their
+-- user like chargebacks. charge_id and refund_id are the provider's IDs of
+-- the payment and the refund. recovered is what was debited from the user,
+-- less than amount when their balance couldn't go negative. The refunds of a
+-- payment never total more than it; a refund is received at most once.
+-- Transactions are partitioned, so their IDs are not foreign keys.
+CREATE TABLE IF NOT EXISTS gateway_refunds (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(20) NOT NULL,
+    charge_id TEXT NOT NULL,
+    refund_id TEXT NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credit_transaction_id INTEGER NOT NULL,
+    amount NUMERIC(18,2) NOT NULL CHECK (amount > 0),
+    recovered NUMERIC(18,2) NOT NULL CHECK (recovered >= 0),
+    debit_transaction_id INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, refund_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_gateway_refunds_charge ON gateway_refunds (provider, charge_id);
@@ -0,0 +1,38 @@
+-- Monthly invoices of the fees users were charged, organizations through
+-- their account user. Invoices are issued open and paid once the fee of
+-- every line was debited, or void if none was. An invoice covers
+-- [period_start, period_end) and any earlier fee that wasn't invoiced yet,
+-- such as that of a transaction approved after its month.
+CREATE TABLE IF NOT EXISTS invoices (
+    id SERIAL PRIMARY KEY,
+    number VARCHAR(32) NOT NULL UNIQUE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    currency CHAR(3) NOT NULL,
+    total NUMERIC(18,2) NOT NULL CHECK (total >= 0),
+    status VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'paid', 'void')),
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    paid_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (user_id, period_start),
+    CHECK (period_end > period_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_user ON invoices (user_id, period_start DESC);
+CREATE INDEX IF NOT EXISTS idx_invoices_open ON invoices (id) WHERE status = 'open';
+
+-- One line per fee: the transaction charged it, its type and amount, and the
+-- fee schedule version that set it. A fee is invoiced once. Transactions are
+-- partitioned, so their IDs are not foreign keys.
+CREATE TABLE IF NOT EXISTS invoice_lines (
+    id SERIAL PRIMARY KEY,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    transaction_id INTEGER NOT NULL UNIQUE,
+    transaction_type VARCHAR(20) NOT NULL,
+    transaction_amount NUMERIC(18,2) NOT NULL,
+    fee_schedule_id INTEGER,
+    amount NUMERIC(18,2) NOT NULL CHECK (amount > 0),
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoice_lines_invoice ON invoice_lines (invoice_id, occurred_at);
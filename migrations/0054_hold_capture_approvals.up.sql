@@ -0,0 +1,2 @@
+-- Active holds whose capture is held for approval, looked up when it is reviewed
+CREATE INDEX IF NOT EXISTS idx_balance_holds_pending_capture ON balance_holds(transaction_id) WHERE status = 'active' AND transaction_id IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP VIEW IF EXISTS transaction_ledger;
+CREATE VIEW transaction_ledger AS
+SELECT id, from_user_id, to_user_id, amount, type, status, created_at FROM transactions
+UNION ALL
+SELECT id, from_user_id, to_user_id, amount, type, status, created_at FROM transactions_archive;
@@ -0,0 +1,5 @@
+-- Fees in the ledger, so balances recomputed from it match what the payer was charged
+CREATE OR REPLACE VIEW transaction_ledger AS
+SELECT id, from_user_id, to_user_id, amount, type, status, created_at, fee FROM transactions
+UNION ALL
+SELECT id, from_user_id, to_user_id, amount, type, status, created_at, fee FROM transactions_archive;
@@ -0,0 +1,120 @@
+// Package bloom implements a space-efficient probabilistic set membership filter.
+package bloom
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// magic identifies the serialized filter format
+const magic = "BLM1"
+
+// ErrInvalidFormat is returned when ReadFrom is given data that isn't a serialized filter
+var ErrInvalidFormat = errors.New("bloom: invalid filter format")
+
+// Filter is a Bloom filter of m bits probed k times per item.
+type Filter struct {
+	m    uint64
+	k    uint32
+	bits []uint64
+}
+
+// New creates an empty filter of m bits using k probes per item
+func New(m uint64, k uint32) *Filter {
+	if m == 0 {
+		m = 1
+	}
+	if k == 0 {
+		k = 1
+	}
+	return &Filter{m: m, k: k, bits: make([]uint64, (m+63)/64)}
+}
+
+// NewWithEstimates sizes a filter to hold n items with a false positive rate of about p
+func NewWithEstimates(n uint64, p float64) *Filter {
+	if n == 0 {
+		n = 1
+	}
+	if p <= 0 || p >= 1 {
+		p = 0.01
+	}
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(n) * math.Ln2)
+	return New(uint64(m), uint32(math.Max(k, 1)))
+}
+
+// Add inserts item into the filter
+func (f *Filter) Add(item []byte) {
+	h1, h2 := hashes(item)
+	for i := uint64(0); i < uint64(f.k); i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// Test reports whether item may be in the filter. false means it definitely isn't.
+func (f *Filter) Test(item []byte) bool {
+	h1, h2 := hashes(item)
+	for i := uint64(0); i < uint64(f.k); i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteTo serializes the filter to w
+func (f *Filter) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	header := make([]byte, len(magic)+12)
+	copy(header, magic)
+	binary.LittleEndian.PutUint64(header[len(magic):], f.m)
+	binary.LittleEndian.PutUint32(header[len(magic)+8:], f.k)
+	if _, err := bw.Write(header); err != nil {
+		return 0, err
+	}
+	if err := binary.Write(bw, binary.LittleEndian, f.bits); err != nil {
+		return 0, err
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, err
+	}
+	return int64(len(header) + 8*len(f.bits)), nil
+}
+
+// ReadFrom loads a filter serialized by WriteTo
+func ReadFrom(r io.Reader) (*Filter, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(magic)+12)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, ErrInvalidFormat
+	}
+	m := binary.LittleEndian.Uint64(header[len(magic):])
+	k := binary.LittleEndian.Uint32(header[len(magic)+8:])
+	if m == 0 || k == 0 {
+		return nil, ErrInvalidFormat
+	}
+
+	f := New(m, k)
+	if err := binary.Read(br, binary.LittleEndian, f.bits); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	return f, nil
+}
+
+// hashes derives the two base hashes used for double hashing.
+func hashes(item []byte) (uint64, uint64) {
+	sum := sha256.Sum256(item)
+	h1 := binary.LittleEndian.Uint64(sum[0:8])
+	h2 := binary.LittleEndian.Uint64(sum[8:16]) | 1
+	return h1, h2
+}
@@ -0,0 +1,59 @@
+package bloom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFilter_AddTest(t *testing.T) {
+	f := NewWithEstimates(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		f.Add([]byte(fmt.Sprintf("item-%d", i)))
+	}
+
+	for i := 0; i < 1000; i++ {
+		if !f.Test([]byte(fmt.Sprintf("item-%d", i))) {
+			t.Fatalf("expected item-%d to be in the filter", i)
+		}
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if f.Test([]byte(fmt.Sprintf("other-%d", i))) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / 10000; rate > 0.03 {
+		t.Errorf("false positive rate %.3f, want about 0.01", rate)
+	}
+}
+
+func TestFilter_RoundTrip(t *testing.T) {
+	f := New(1024, 5)
+	f.Add([]byte("password"))
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo returned error: %v", err)
+	}
+	loaded, err := ReadFrom(&buf)
+	if err != nil {
+		t.Fatalf("ReadFrom returned error: %v", err)
+	}
+	if !loaded.Test([]byte("password")) {
+		t.Error("expected loaded filter to contain the added item")
+	}
+	if loaded.m != f.m || loaded.k != f.k {
+		t.Errorf("loaded filter has m=%d k=%d, want m=%d k=%d", loaded.m, loaded.k, f.m, f.k)
+	}
+}
+
+func TestReadFrom_Invalid(t *testing.T) {
+	for _, data := range [][]byte{nil, []byte("nope"), []byte("XXXX000000000000")} {
+		if _, err := ReadFrom(bytes.NewReader(data)); !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("ReadFrom(%q) error = %v, want ErrInvalidFormat", data, err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Cache stores JSON-encoded values under string keys.
+type Cache interface {
+	// Get decodes the value stored under key into dest and reports whether it was found
+	Get(ctx context.Context, key string, dest interface{}) (bool, error)
+
+	// Set stores value under key for ttl
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+
+	// GetMany returns the encoded values of the keys that were found, in one round trip
+	GetMany(ctx context.Context, keys []string) (map[string]json.RawMessage, error)
+
+	// SetMany stores every value under its key for ttl, in one round trip
+	SetMany(ctx context.Context, values map[string]interface{}, ttl time.Duration) error
+
+	// Delete removes key
+	Delete(ctx context.Context, key string) error
+
+	// DeletePattern removes every key matching a Redis glob pattern
+	DeletePattern(ctx context.Context, pattern string) error
+}
+
+var (
+	_ Cache = (*RedisCache)(nil)
+	_ Cache = (*MemoryCache)(nil)
+	_ Cache = (*TieredCache)(nil)
+)
+
+// globToRegexp converts a Redis glob pattern to an anchored regular expression.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
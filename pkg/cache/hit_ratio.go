@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// hitRatioWindow is how far back hit ratios look
+	hitRatioWindow = 5 * time.Minute
+	// hitRatioBuckets is how many slices the window is split into; lookups age
+	// out one slice at a time
+	hitRatioBuckets = 30
+)
+
+var (
+	// ResponseHitRatio tracks lookups of cached HTTP responses
+	ResponseHitRatio = NewHitRatio(hitRatioWindow, hitRatioBuckets)
+	// RedisHitRatio tracks reads from Redis
+	RedisHitRatio = NewHitRatio(hitRatioWindow, hitRatioBuckets)
+)
+
+// HitRatio counts cache hits and misses over a rolling window.
+type HitRatio struct {
+	mu         sync.Mutex
+	bucketSize time.Duration
+	buckets    []hitBucket
+	now        func() time.Time
+}
+
+// hitBucket holds the lookups of one slice of the window.
+type hitBucket struct {
+	slot   int64
+	hits   int64
+	misses int64
+}
+
+// NewHitRatio creates a HitRatio over window, split into the given number of buckets
+func NewHitRatio(window time.Duration, buckets int) *HitRatio {
+	return &HitRatio{
+		bucketSize: window / time.Duration(buckets),
+		buckets:    make([]hitBucket, buckets),
+		now:        time.Now,
+	}
+}
+
+// Hit records a lookup that found its entry
+func (h *HitRatio) Hit() {
+	h.record(true)
+}
+
+// Miss records a lookup that did not
+func (h *HitRatio) Miss() {
+	h.record(false)
+}
+
+func (h *HitRatio) record(hit bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	slot := h.slot()
+	b := &h.buckets[slot%int64(len(h.buckets))]
+	if b.slot != slot {
+		*b = hitBucket{slot: slot}
+	}
+	if hit {
+		b.hits++
+	} else {
+		b.misses++
+	}
+}
+
+// Ratio returns the percentage of lookups in the window that were hits.
+func (h *HitRatio) Ratio() (float64, int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	oldest := h.slot() - int64(len(h.buckets)) + 1
+	var hits, total int64
+	for _, b := range h.buckets {
+		if b.slot >= oldest {
+			hits += b.hits
+			total += b.hits + b.misses
+		}
+	}
+	if total == 0 {
+		return 0, 0
+	}
+	return float64(hits) / float64(total) * 100, total
+}
+
+func (h *HitRatio) slot() int64 {
+	return h.now().UnixNano() / int64(h.bucketSize)
+}
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHitRatio_RollingWindow(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	h := NewHitRatio(time.Minute, 6)
+	h.now = func() time.Time { return now }
+
+	ratio, total := h.Ratio()
+	assert.Equal(t, 0.0, ratio)
+	assert.Equal(t, int64(0), total)
+
+	h.Miss()
+	h.Miss()
+	now = now.Add(30 * time.Second)
+	h.Hit()
+	h.Hit()
+	ratio, total = h.Ratio()
+	assert.Equal(t, 50.0, ratio)
+	assert.Equal(t, int64(4), total)
+
+	// The misses leave the window first
+	now = now.Add(45 * time.Second)
+	ratio, total = h.Ratio()
+	assert.Equal(t, 100.0, ratio)
+	assert.Equal(t, int64(2), total)
+
+	now = now.Add(time.Minute)
+	_, total = h.Ratio()
+	assert.Equal(t, int64(0), total)
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"crypto/md5"
+	"fmt"
+	"strconv"
+)
+
+// Cached HTTP responses are stored under http_cache:<scope>:<request hash>.
+const (
+	// ResponseKeyPrefix prefixes every cached response key
+	ResponseKeyPrefix = "http_cache:"
+
+	// PublicScope holds responses served without an authenticated user
+	PublicScope = "public"
+)
+
+// UserScope returns the scope of responses served to userID
+func UserScope(userID string) string {
+	return "user:" + userID
+}
+
+// ResponseKey returns the key of a cached response for request in scope.
+func ResponseKey(scope, request string) string {
+	return fmt.Sprintf("%s%s:%x", ResponseKeyPrefix, scope, md5.Sum([]byte(request)))
+}
+
+// ScopePattern matches every cached response in scope
+func ScopePattern(scope string) string {
+	return ResponseKeyPrefix + scope + ":*"
+}
+
+// UserResponsesPattern matches every cached response served to userID
+func UserResponsesPattern(userID int) string {
+	return ScopePattern(UserScope(strconv.Itoa(userID)))
+}
+
+// AllResponsesPattern matches every cached response
+func AllResponsesPattern() string {
+	return ResponseKeyPrefix + "*"
+}
@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/melihgurlek/backend-path/pkg/metrics"
+)
+
+// MemoryCache is an in-process LRU cache holding at most a fixed number of entries.
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	entries  map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates a MemoryCache that keeps at most capacity entries
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get retrieves a value from cache
+func (c *MemoryCache) Get(_ context.Context, key string, dest interface{}) (bool, error) {
+	data, ok := c.getRaw(key)
+	if !ok {
+		metrics.CacheOperations.WithLabelValues("l1_get", "miss").Inc()
+		return false, nil
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		metrics.CacheOperations.WithLabelValues("l1_get", "error").Inc()
+		return false, fmt.Errorf("failed to unmarshal cached value: %w", err)
+	}
+	metrics.CacheOperations.WithLabelValues("l1_get", "hit").Inc()
+	return true, nil
+}
+
+// Set stores a value in cache with TTL, evicting the least recently used entry when full
+func (c *MemoryCache) Set(_ context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+	c.setRaw(key, data, ttl)
+	return nil
+}
+
+// GetMany retrieves the values of the keys that are present
+func (c *MemoryCache) GetMany(_ context.Context, keys []string) (map[string]json.RawMessage, error) {
+	found := make(map[string]json.RawMessage, len(keys))
+	for _, key := range keys {
+		if data, ok := c.getRaw(key); ok {
+			metrics.CacheOperations.WithLabelValues("l1_get", "hit").Inc()
+			found[key] = data
+		} else {
+			metrics.CacheOperations.WithLabelValues("l1_get", "miss").Inc()
+		}
+	}
+	return found, nil
+}
+
+// SetMany stores several values with TTL
+func (c *MemoryCache) SetMany(_ context.Context, values map[string]interface{}, ttl time.Duration) error {
+	encoded, err := encodeAll(values)
+	if err != nil {
+		return err
+	}
+	for key, data := range encoded {
+		c.setRaw(key, data, ttl)
+	}
+	return nil
+}
+
+// Delete removes a key from cache
+func (c *MemoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+	return nil
+}
+
+// DeletePattern removes all keys matching a Redis glob pattern
+func (c *MemoryCache) DeletePattern(_ context.Context, pattern string) error {
+	re, err := globToRegexp(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, el := range c.entries {
+		if re.MatchString(key) {
+			c.removeElement(el)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries, including expired ones not yet evicted
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *MemoryCache) getRaw(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*memoryEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry.data, true
+}
+
+func (c *MemoryCache) setRaw(key string, data []byte, ttl time.Duration) {
+	if c.capacity <= 0 || ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*memoryEntry)
+		entry.data, entry.expiresAt = data, expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, data: data, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// encodeAll JSON-encodes every value, failing on the first that cannot be
+func encodeAll(values map[string]interface{}) (map[string][]byte, error) {
+	encoded := make(map[string][]byte, len(values))
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal value for %s: %w", key, err)
+		}
+		encoded[key] = data
+	}
+	return encoded, nil
+}
+
+func (c *MemoryCache) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(2)
+
+	require.NoError(t, c.Set(ctx, "a", 1, time.Minute))
+	require.NoError(t, c.Set(ctx, "b", 2, time.Minute))
+	var v int
+	found, _ := c.Get(ctx, "a", &v) // a is now more recent than b
+	require.True(t, found)
+	require.NoError(t, c.Set(ctx, "c", 3, time.Minute))
+
+	found, _ = c.Get(ctx, "b", &v)
+	assert.False(t, found, "b was least recently used")
+	found, _ = c.Get(ctx, "a", &v)
+	assert.True(t, found)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestMemoryCache_Expires(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+	require.NoError(t, c.Set(ctx, "k", "v", time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+
+	var v string
+	found, err := c.Get(ctx, "k", &v)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestMemoryCache_DeletePattern(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+	for _, key := range []string{ResponseKey(UserScope("1"), "a"), ResponseKey(UserScope("1"), "b"), ResponseKey(UserScope("12"), "a")} {
+		require.NoError(t, c.Set(ctx, key, true, time.Minute))
+	}
+
+	require.NoError(t, c.DeletePattern(ctx, UserResponsesPattern(1)))
+
+	assert.Equal(t, 1, c.Len(), "only user 12's response is left")
+	var v bool
+	found, _ := c.Get(ctx, ResponseKey(UserScope("12"), "a"), &v)
+	assert.True(t, found)
+}
+
+func TestMemoryCache_GetManySetMany(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+	require.NoError(t, c.SetMany(ctx, map[string]interface{}{"a": 1, "b": 2}, time.Minute))
+
+	found, err := c.GetMany(ctx, []string{"a", "b", "c"})
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+	assert.JSONEq(t, "2", string(found["b"]))
+}
@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// invalidationChannel is the Redis pub/sub channel on which instances announce writes.
+const invalidationChannel = "cache:l1:invalidate"
+
+// TieredCache puts an in-process MemoryCache (L1) in front of a RedisCache (L2).
+type TieredCache struct {
+	l1         *MemoryCache
+	l2         *RedisCache
+	l1TTL      time.Duration
+	instanceID string
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// invalidation is a pub/sub message naming the key or pattern to evict
+type invalidation struct {
+	Origin  string   `json:"origin"`
+	Key     string   `json:"key,omitempty"`
+	Keys    []string `json:"keys,omitempty"`
+	Pattern string   `json:"pattern,omitempty"`
+}
+
+// NewTieredCache creates a TieredCache over l2 with an L1 of l1Size entries.
+func NewTieredCache(l2 *RedisCache, l1Size int, l1TTL time.Duration) *TieredCache {
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &TieredCache{
+		l1:         NewMemoryCache(l1Size),
+		l2:         l2,
+		l1TTL:      l1TTL,
+		instanceID: hex.EncodeToString(id),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+	go c.listen(ctx)
+	return c
+}
+
+// Get retrieves a value from L1, falling back to L2 and keeping a copy in L1
+func (c *TieredCache) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
+	if found, err := c.l1.Get(ctx, key, dest); err == nil && found {
+		return true, nil
+	}
+	found, err := c.l2.Get(ctx, key, dest)
+	if err != nil || !found {
+		return found, err
+	}
+	c.l1.Set(ctx, key, dest, c.l1TTL)
+	return true, nil
+}
+
+// Set stores a value in both tiers and evicts it from other instances' L1
+func (c *TieredCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := c.l2.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	c.l1.Set(ctx, key, value, min(ttl, c.l1TTL))
+	c.publish(ctx, invalidation{Key: key})
+	return nil
+}
+
+// GetMany retrieves values from L1, fetching the rest from L2 in one round trip.
+func (c *TieredCache) GetMany(ctx context.Context, keys []string) (map[string]json.RawMessage, error) {
+	found, _ := c.l1.GetMany(ctx, keys)
+	var missing []string
+	for _, key := range keys {
+		if _, ok := found[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return found, nil
+	}
+
+	fromL2, err := c.l2.GetMany(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for key, data := range fromL2 {
+		c.l1.setRaw(key, data, c.l1TTL)
+		found[key] = data
+	}
+	return found, nil
+}
+
+// SetMany stores several values in both tiers and evicts them from other instances' L1
+func (c *TieredCache) SetMany(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	if err := c.l2.SetMany(ctx, values, ttl); err != nil {
+		return err
+	}
+	c.l1.SetMany(ctx, values, min(ttl, c.l1TTL))
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	c.publish(ctx, invalidation{Keys: keys})
+	return nil
+}
+
+// Delete removes a key from both tiers on every instance
+func (c *TieredCache) Delete(ctx context.Context, key string) error {
+	c.l1.Delete(ctx, key)
+	err := c.l2.Delete(ctx, key)
+	c.publish(ctx, invalidation{Key: key})
+	return err
+}
+
+// DeletePattern removes all keys matching a pattern from both tiers on every instance
+func (c *TieredCache) DeletePattern(ctx context.Context, pattern string) error {
+	c.l1.DeletePattern(ctx, pattern)
+	err := c.l2.DeletePattern(ctx, pattern)
+	c.publish(ctx, invalidation{Pattern: pattern})
+	return err
+}
+
+// Close stops listening for invalidations.
+func (c *TieredCache) Close() error {
+	c.cancel()
+	<-c.done
+	return nil
+}
+
+func (c *TieredCache) publish(ctx context.Context, msg invalidation) {
+	msg.Origin = c.instanceID
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	if err := c.l2.GetClient().Publish(ctx, invalidationChannel, data).Err(); err != nil {
+		log.Warn().Err(err).Msg("Failed to publish cache invalidation")
+	}
+}
+
+// listen applies invalidations published by other instances to L1 until ctx is cancelled
+func (c *TieredCache) listen(ctx context.Context) {
+	defer close(c.done)
+	pubsub := c.l2.GetClient().Subscribe(ctx, invalidationChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-messages:
+			if !ok {
+				return
+			}
+			var msg invalidation
+			if err := json.Unmarshal([]byte(m.Payload), &msg); err != nil {
+				log.Warn().Err(err).Msg("Ignoring malformed cache invalidation")
+				continue
+			}
+			if msg.Origin == c.instanceID {
+				continue
+			}
+			switch {
+			case msg.Pattern != "":
+				c.l1.DeletePattern(ctx, msg.Pattern)
+			case len(msg.Keys) > 0:
+				for _, key := range msg.Keys {
+					c.l1.Delete(ctx, key)
+				}
+			default:
+				c.l1.Delete(ctx, msg.Key)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newRecordedHook() (*TracingHook, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return &TracingHook{tracer: provider.Tracer("redis")}, recorder
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracingHook_Process(t *testing.T) {
+	hook, recorder := newRecordedHook()
+	ctx := context.Background()
+
+	miss := hook.ProcessHook(func(context.Context, redis.Cmder) error { return redis.Nil })
+	assert.ErrorIs(t, miss(ctx, redis.NewStringCmd(ctx, "get", "denylist:abc123")), redis.Nil)
+	fail := hook.ProcessHook(func(context.Context, redis.Cmder) error { return errors.New("connection reset") })
+	assert.Error(t, fail(ctx, redis.NewStatusCmd(ctx, "set", "user:7", "v")))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "redis.get", spans[0].Name())
+	assert.Equal(t, "denylist", spanAttr(spans[0], "db.redis.key_prefix").AsString(), "only the key prefix is recorded")
+	assert.Equal(t, codes.Unset, spans[0].Status().Code, "a cache miss is not an error")
+	assert.Equal(t, "redis.set", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestTracingHook_Pipeline(t *testing.T) {
+	hook, recorder := newRecordedHook()
+	ctx := context.Background()
+
+	pipe := hook.ProcessPipelineHook(func(context.Context, []redis.Cmder) error { return nil })
+	require.NoError(t, pipe(ctx, []redis.Cmder{
+		redis.NewIntCmd(ctx, "incr", "rate:1"),
+		redis.NewBoolCmd(ctx, "expire", "rate:1", 60),
+	}))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "redis.pipeline", spans[0].Name())
+	assert.Equal(t, "incr expire", spanAttr(spans[0], "db.operation").AsString())
+	assert.Equal(t, int64(2), spanAttr(spans[0], "db.redis.num_cmd").AsInt64())
+}
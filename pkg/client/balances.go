@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// balanceQuery selects whose balance to read.
+func balanceQuery(userID int) url.Values {
+	query := url.Values{}
+	if userID != 0 {
+		query.Set("user_id", strconv.Itoa(userID))
+	}
+	return query
+}
+
+// GetBalance retrieves a user's current balance; a zero userID reads the caller's own.
+func (c *Client) GetBalance(ctx context.Context, userID int) (*BalanceV2Response, error) {
+	var balance BalanceV2Response
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/balances/current", query: balanceQuery(userID)}, &balance); err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// GetAvailableBalance retrieves a user's balance split into held and spendable parts.
+func (c *Client) GetAvailableBalance(ctx context.Context, userID int) (*AvailableBalanceV2Response, error) {
+	var balance AvailableBalanceV2Response
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/balances/available", query: balanceQuery(userID)}, &balance); err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// GetBalanceHistory retrieves up to limit past balances of a user
+func (c *Client) GetBalanceHistory(ctx context.Context, userID, limit int) ([]Balance, error) {
+	query := balanceQuery(userID)
+	query.Set("limit", strconv.Itoa(limit))
+	var balances []Balance
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/balances/historical", query: query}, &balances); err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+// GetBalanceAt retrieves a user's balance as it was at a point in time
+func (c *Client) GetBalanceAt(ctx context.Context, userID int, at time.Time) (*Balance, error) {
+	query := balanceQuery(userID)
+	query.Set("time", at.Format(time.RFC3339))
+	var balance Balance
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/balances/at-time", query: query}, &balance); err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
@@ -0,0 +1,278 @@
+// Package client is a typed Go client for the API.
+package client
+
+//go:generate go run ../../cmd/clientgen -root ../.. -out types_gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// idempotencyKeyHeader carries the key that makes a retried money movement run once
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// deviceIDHeader carries the identifier of the device the client runs on
+const deviceIDHeader = "X-Device-ID"
+
+// RetryPolicy sets how failed requests are retried.
+type RetryPolicy struct {
+	MaxAttempts    int           // attempts per request, including the first; 1 disables retries
+	InitialBackoff time.Duration // wait before the first retry, doubled for each further one
+	MaxBackoff     time.Duration // upper bound of the wait, unless the server asks for longer
+}
+
+// DefaultRetryPolicy returns the policy clients use unless told otherwise
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, InitialBackoff: 200 * time.Millisecond, MaxBackoff: 2 * time.Second}
+}
+
+// backoff returns the wait before retry number attempt, counting from 1
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxBackoff)
+}
+
+// Client calls the API on behalf of one user.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	retry      RetryPolicy
+	newKey     func() string
+	deviceID   string
+
+	mu    sync.RWMutex
+	token string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests through hc instead of http.DefaultClient
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithToken authenticates requests with a token obtained earlier
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithDeviceID identifies the device the client runs on.
+func WithDeviceID(id string) Option {
+	return func(c *Client) { c.deviceID = id }
+}
+
+// WithRetryPolicy replaces the default retry policy
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// New creates a Client for the API at baseURL, such as "https://api.example.com"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/") + "/api/v2",
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy(),
+		newKey:     uuid.NewString,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c
+}
+
+// SetToken authenticates further requests with token; Login calls it itself
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+// Token returns the token requests are authenticated with, if any
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+// Error is a response with an error status.
+type Error struct {
+	StatusCode int
+	Problem
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	msg := e.Detail
+	if msg == "" {
+		msg = e.Title
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("api: %d %s (%s)", e.StatusCode, msg, e.Code)
+	}
+	return fmt.Sprintf("api: %d %s", e.StatusCode, msg)
+}
+
+// IsStatus reports whether err is an API error with the given status code
+func IsStatus(err error, status int) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+// CallOption adjusts a single request
+type CallOption func(*call)
+
+// WithIdempotencyKey sends key instead of a generated idempotency key.
+func WithIdempotencyKey(key string) CallOption {
+	return func(c *call) { c.idempotencyKey = key }
+}
+
+// call describes one request
+type call struct {
+	method         string
+	path           string
+	query          url.Values
+	body           interface{}
+	idempotent     bool // the API deduplicates the request by its idempotency key
+	idempotencyKey string
+}
+
+// do sends a request, retrying it if it is safe to, and decodes the response into out.
+func (c *Client) do(ctx context.Context, cl *call, out interface{}, opts ...CallOption) error {
+	for _, opt := range opts {
+		opt(cl)
+	}
+	var body []byte
+	if cl.body != nil {
+		var err error
+		if body, err = json.Marshal(cl.body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+	if cl.idempotent && cl.idempotencyKey == "" {
+		cl.idempotencyKey = c.newKey()
+	}
+	retryable := cl.method != http.MethodPost || cl.idempotent
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, cl, body)
+		if err == nil && resp.StatusCode < 300 {
+			defer resp.Body.Close()
+			if out == nil || resp.StatusCode == http.StatusNoContent {
+				return nil
+			}
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+			return nil
+		}
+
+		var wait time.Duration
+		if err == nil {
+			err = responseError(resp)
+			if !retryableStatus(resp.StatusCode) {
+				return err
+			}
+			wait = retryAfter(resp)
+		} else if ctx.Err() != nil || !retryableNetError(err) {
+			return err
+		}
+		if !retryable || attempt >= c.retry.MaxAttempts {
+			return err
+		}
+
+		wait = max(wait, c.retry.backoff(attempt))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// send makes a single attempt of a request
+func (c *Client) send(ctx context.Context, cl *call, body []byte) (*http.Response, error) {
+	u := c.baseURL + cl.path
+	if len(cl.query) > 0 {
+		u += "?" + cl.query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, cl.method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token := c.Token(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if cl.idempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, cl.idempotencyKey)
+	}
+	if c.deviceID != "" {
+		req.Header.Set(deviceIDHeader, c.deviceID)
+	}
+	return c.httpClient.Do(req)
+}
+
+// responseError reads an error response into an *Error and closes its body
+func responseError(resp *http.Response) error {
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	if err := json.Unmarshal(data, &apiErr.Problem); err != nil || apiErr.Problem.Status == 0 {
+		apiErr.Problem = Problem{Status: resp.StatusCode, Title: http.StatusText(resp.StatusCode), Detail: strings.TrimSpace(string(data))}
+	}
+	return apiErr
+}
+
+// retryableStatus reports whether a request failing with status may succeed later
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryableNetError reports whether a request failing with err may succeed later.
+func retryableNetError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// retryAfter returns the wait a Retry-After header in seconds asks for
+func retryAfter(resp *http.Response) time.Duration {
+	secs, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastRetries keeps retrying tests quick
+var fastRetries = WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+func TestCredit_RetriesWithSameIdempotencyKey(t *testing.T) {
+	var attempts atomic.Int32
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/transactions/credit", r.URL.Path)
+		keys = append(keys, r.Header.Get(idempotencyKeyHeader))
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "10.50", body["amount"])
+
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"message": "credit successful"})
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, fastRetries)
+	result, err := c.Credit(context.Background(), 7, 10.5)
+
+	require.NoError(t, err)
+	assert.Equal(t, "credit successful", result.Message)
+	assert.Equal(t, int32(3), attempts.Load())
+	require.Len(t, keys, 3)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+	assert.Equal(t, keys[0], keys[2])
+}
+
+func TestDo_DoesNotRetryPostsWithoutIdempotency(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		assert.Empty(t, r.Header.Get(idempotencyKeyHeader))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, fastRetries).SubmitTask(context.Background(), SubmitTaskRequest{Type: "credit", UserID: 1, Amount: 5})
+
+	assert.True(t, IsStatus(err, http.StatusServiceUnavailable))
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestDo_DecodesProblemDetails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(Problem{Type: "/problems/user_not_found", Title: "Not Found", Status: 404, Detail: "user not found", Code: "user_not_found"})
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, fastRetries).GetUser(context.Background(), 42)
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "user_not_found", apiErr.Code)
+	assert.EqualError(t, err, "api: 404 user not found (user_not_found)")
+}
+
+func TestLogin_AuthenticatesLaterRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 3, "username": "alice", "role": "user", "token": "tok"})
+		case "/api/v2/balances/current":
+			assert.Equal(t, "Bearer tok", r.Header.Get("Authorization"))
+			assert.Empty(t, r.URL.Query().Get("user_id"))
+			json.NewEncoder(w).Encode(BalanceV2Response{UserID: 3, Balance: "12.00", AvailableBalance: "12.00"})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	user, err := c.Login(context.Background(), "alice", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, 3, user.ID)
+	assert.Equal(t, "tok", c.Token())
+
+	balance, err := c.GetBalance(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, "12.00", balance.Balance)
+}
+
+func TestLogin_VerifiesUntrustedDevice(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "phone-1", r.Header.Get("X-Device-ID"))
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(map[string]interface{}{"verification_required": true, "challenge_id": "ch-1", "method": "email"})
+		case "/api/v2/auth/login/verify":
+			var req VerifyLoginRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, VerifyLoginRequest{ChallengeID: "ch-1", Code: "123456"}, req)
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 3, "username": "alice", "role": "user", "token": "tok"})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithDeviceID("phone-1"))
+	resp, err := c.Login(context.Background(), "alice", "secret")
+	require.NoError(t, err)
+	assert.True(t, resp.VerificationRequired)
+	assert.Empty(t, c.Token(), "no token until the login is verified")
+
+	user, err := c.VerifyLogin(context.Background(), resp.ChallengeID, "123456")
+	require.NoError(t, err)
+	assert.Equal(t, 3, user.ID)
+	assert.Equal(t, "tok", c.Token())
+}
+
+func TestDo_StopsRetryingWhenContextIsDone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := New(srv.URL, fastRetries).GetWorkerStats(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestSearchTransactions_SendsOnlySetParameters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/transactions/search", r.URL.Path)
+		assert.Equal(t, "counterparty_id=7&from=2026-01-01T00%3A00%3A00Z&min_amount=10.00&q=rent&type=debit%2Ctransfer", r.URL.RawQuery)
+		json.NewEncoder(w).Encode([]map[string]interface{}{{"ID": 3, "Type": "transfer", "Description": "rent"}})
+	}))
+	defer srv.Close()
+
+	txs, err := New(srv.URL).SearchTransactions(context.Background(), TransactionSearch{
+		Query:          "rent",
+		CounterpartyID: 7,
+		MinAmount:      10,
+		Types:          []string{"debit", "transfer"},
+		From:           time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, "rent", txs[0].Description)
+}
+
+func TestUpdatePreferences_SendsNullForRemovedKeys(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/api/v2/users/4/preferences", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"ui.theme":"dark","ui.compact":null}`, string(body))
+		io.WriteString(w, `{"ui.theme":"dark"}`)
+	}))
+	defer srv.Close()
+
+	prefs, err := New(srv.URL).UpdatePreferences(context.Background(), 4, map[string]any{"ui.theme": "dark", "ui.compact": nil})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `"dark"`, string(prefs["ui.theme"]))
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// scheduledPath returns the path of a scheduled transaction, or of the collection if id is zero
+func scheduledPath(id int) string {
+	if id == 0 {
+		return "/scheduled-transactions/"
+	}
+	return "/scheduled-transactions/" + strconv.Itoa(id)
+}
+
+// CreateScheduledTransaction schedules a one-off or recurring transaction
+func (c *Client) CreateScheduledTransaction(ctx context.Context, req CreateScheduledTransactionRequest) (*ScheduledTransaction, error) {
+	var st ScheduledTransaction
+	if err := c.do(ctx, &call{method: http.MethodPost, path: scheduledPath(0), body: req}, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// PreviewScheduledTransaction projects a scheduled transaction's upcoming runs.
+func (c *Client) PreviewScheduledTransaction(ctx context.Context, req PreviewScheduledTransactionRequest) (*ScheduledTransactionPreview, error) {
+	var preview ScheduledTransactionPreview
+	if err := c.do(ctx, &call{method: http.MethodPost, path: "/scheduled-transactions/preview", body: req}, &preview); err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+// GetScheduledTransaction retrieves a scheduled transaction
+func (c *Client) GetScheduledTransaction(ctx context.Context, id int) (*ScheduledTransaction, error) {
+	var st ScheduledTransaction
+	if err := c.do(ctx, &call{method: http.MethodGet, path: scheduledPath(id)}, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// ListScheduledTransactions lists the scheduled transactions of a user
+func (c *Client) ListScheduledTransactions(ctx context.Context, userID int) ([]ScheduledTransaction, error) {
+	query := url.Values{"user_id": {strconv.Itoa(userID)}}
+	var sts []ScheduledTransaction
+	if err := c.do(ctx, &call{method: http.MethodGet, path: scheduledPath(0), query: query}, &sts); err != nil {
+		return nil, err
+	}
+	return sts, nil
+}
+
+// UpdateScheduledTransaction changes the fields of a scheduled transaction that are set in req
+func (c *Client) UpdateScheduledTransaction(ctx context.Context, id int, req UpdateScheduledTransactionRequest) (*ScheduledTransaction, error) {
+	var st ScheduledTransaction
+	if err := c.do(ctx, &call{method: http.MethodPut, path: scheduledPath(id), body: req}, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// CancelScheduledTransaction cancels a scheduled transaction
+func (c *Client) CancelScheduledTransaction(ctx context.Context, id int) error {
+	return c.do(ctx, &call{method: http.MethodDelete, path: scheduledPath(id)}, nil)
+}
+
+// PauseScheduledTransaction stops a scheduled transaction from running until it is resumed
+func (c *Client) PauseScheduledTransaction(ctx context.Context, id int) (*ScheduledTransaction, error) {
+	var st ScheduledTransaction
+	if err := c.do(ctx, &call{method: http.MethodPost, path: scheduledPath(id) + "/pause"}, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// ResumeScheduledTransaction lets a paused scheduled transaction run again
+func (c *Client) ResumeScheduledTransaction(ctx context.Context, id int) (*ScheduledTransaction, error) {
+	var st ScheduledTransaction
+	if err := c.do(ctx, &call{method: http.MethodPost, path: scheduledPath(id) + "/resume"}, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// GetScheduledTransactionStats retrieves counts of scheduled transactions by status
+func (c *Client) GetScheduledTransactionStats(ctx context.Context) (*ScheduledTransactionStats, error) {
+	var stats ScheduledTransactionStats
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/scheduled-transactions/stats"}, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Credit adds amount to a user's balance; admins only.
+func (c *Client) Credit(ctx context.Context, userID int, amount float64, opts ...CallOption) (*TransactionResult, error) {
+	req := amountRequest{UserID: userID, Amount: formatAmount(amount)}
+	return c.moveMoney(ctx, "/transactions/credit", req, opts)
+}
+
+// Debit takes amount from a user's balance
+func (c *Client) Debit(ctx context.Context, userID int, amount float64, opts ...CallOption) (*TransactionResult, error) {
+	req := amountRequest{UserID: userID, Amount: formatAmount(amount)}
+	return c.moveMoney(ctx, "/transactions/debit", req, opts)
+}
+
+// Transfer moves amount from one user's balance to another's
+func (c *Client) Transfer(ctx context.Context, fromUserID, toUserID int, amount float64, opts ...CallOption) (*TransactionResult, error) {
+	req := transferRequest{FromUserID: fromUserID, ToUserID: toUserID, Amount: formatAmount(amount)}
+	return c.moveMoney(ctx, "/transactions/transfer", req, opts)
+}
+
+// moveMoney posts a credit, debit or transfer with an idempotency key
+func (c *Client) moveMoney(ctx context.Context, path string, req interface{}, opts []CallOption) (*TransactionResult, error) {
+	var result TransactionResult
+	if err := c.do(ctx, &call{method: http.MethodPost, path: path, body: req, idempotent: true}, &result, opts...); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetTransaction retrieves a transaction
+func (c *Client) GetTransaction(ctx context.Context, id int) (*Transaction, error) {
+	var tx Transaction
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/transactions/" + strconv.Itoa(id)}, &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// ListUserTransactions lists a page of a user's transactions, newest first.
+func (c *Client) ListUserTransactions(ctx context.Context, userID, limit, offset int) ([]Transaction, error) {
+	query := url.Values{"offset": {strconv.Itoa(offset)}}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var txs []Transaction
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/transactions/user/" + strconv.Itoa(userID), query: query}, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// ListAllTransactions lists the transactions of every user, newest first; admins only
+func (c *Client) ListAllTransactions(ctx context.Context, limit, offset int) ([]Transaction, error) {
+	query := url.Values{"limit": {strconv.Itoa(limit)}, "offset": {strconv.Itoa(offset)}}
+	var txs []Transaction
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/transactions/history", query: query}, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// TransactionSearch holds the parameters of SearchTransactions; zero fields don't narrow the search
+type TransactionSearch struct {
+	Query          string // description contains, ignoring case
+	UserID         int    // ignored for users other than admins, who only search their own
+	CounterpartyID int
+	MinAmount      float64
+	MaxAmount      float64
+	Types          []string
+	Statuses       []string
+	From           time.Time
+	To             time.Time
+	Limit          int
+	Offset         int
+}
+
+// values encodes the search as query parameters
+func (s TransactionSearch) values() url.Values {
+	q := url.Values{}
+	set := func(name, v string, ok bool) {
+		if ok {
+			q.Set(name, v)
+		}
+	}
+	set("q", s.Query, s.Query != "")
+	set("user_id", strconv.Itoa(s.UserID), s.UserID > 0)
+	set("counterparty_id", strconv.Itoa(s.CounterpartyID), s.CounterpartyID > 0)
+	set("min_amount", formatAmount(s.MinAmount), s.MinAmount > 0)
+	set("max_amount", formatAmount(s.MaxAmount), s.MaxAmount > 0)
+	set("type", strings.Join(s.Types, ","), len(s.Types) > 0)
+	set("status", strings.Join(s.Statuses, ","), len(s.Statuses) > 0)
+	set("from", s.From.Format(time.RFC3339), !s.From.IsZero())
+	set("to", s.To.Format(time.RFC3339), !s.To.IsZero())
+	set("limit", strconv.Itoa(s.Limit), s.Limit > 0)
+	set("offset", strconv.Itoa(s.Offset), s.Offset > 0)
+	return q
+}
+
+// SearchTransactions finds transactions matching every set parameter, newest first
+func (c *Client) SearchTransactions(ctx context.Context, search TransactionSearch) ([]Transaction, error) {
+	var txs []Transaction
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/transactions/search", query: search.values()}, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"strconv"
+	"time"
+)
+
+// The handlers below answer with ad hoc JSON objects rather than named
+// structs, so their responses are described here by hand.
+
+// User is a user as the API returns it
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+
+	// Only set when a single user is returned
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Phone     string `json:"phone"`
+	Locale    string `json:"locale"`
+	AvatarURL string `json:"avatar_url"` // empty if the user has no avatar
+
+	// Only set in user listings
+	CreatedAt time.Time `json:"created_at"`
+	Erased    bool      `json:"erased"`
+}
+
+// LoginResponse is the user who logged in and their token.
+type LoginResponse struct {
+	User
+	Token string `json:"token"`
+
+	VerificationRequired bool      `json:"verification_required"`
+	ChallengeID          string    `json:"challenge_id"`
+	Method               string    `json:"method"`
+	ExpiresAt            time.Time `json:"expires_at"`
+}
+
+// MessageResponse is the acknowledgement of a request that returns no resource
+type MessageResponse struct {
+	Message string `json:"message"`
+	Status  string `json:"status,omitempty"`
+}
+
+// TransactionResult is the outcome of a credit, debit or transfer.
+type TransactionResult struct {
+	Message       string `json:"message"`
+	TransactionID int    `json:"transaction_id,omitempty"`
+	Status        string `json:"status,omitempty"`
+}
+
+// PendingApproval reports whether the transaction was held for approval
+func (r *TransactionResult) PendingApproval() bool {
+	return r.Status == "pending_approval"
+}
+
+// amountRequest is the v2 body of credits and debits
+type amountRequest struct {
+	UserID int    `json:"user_id"`
+	Amount string `json:"amount"`
+}
+
+// transferRequest is the v2 body of transfers
+type transferRequest struct {
+	FromUserID int    `json:"from_user_id"`
+	ToUserID   int    `json:"to_user_id"`
+	Amount     string `json:"amount"`
+}
+
+// formatAmount formats an amount as the decimal string v2 takes
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,332 @@
+// Code generated by clientgen from the API handlers; DO NOT EDIT.
+
+package client
+
+import (
+	"time"
+)
+
+// RegisterRequest represents the request body for user registration.
+type RegisterRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	// ReferralCode is the code of the user who referred this one, if any
+	ReferralCode string `json:"referral_code,omitempty"`
+}
+
+// LoginRequest represents the request body for user login.
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// VerifyLoginRequest represents the request body completing a login from a new device.
+type VerifyLoginRequest struct {
+	ChallengeID string `json:"challenge_id"`
+	Code        string `json:"code"`
+}
+
+// Device is a device a user has logged in from and trusts.
+type Device struct {
+	Fingerprint string    `json:"fingerprint"`
+	UserAgent   string    `json:"user_agent"`
+	FirstSeenAt time.Time `json:"first_seen_at"` // when the device became trusted
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// UpdateRequest represents the request body for user updates.
+type UpdateRequest struct {
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Phone     string `json:"phone"`  // E.164, such as +905551234567
+	Locale    string `json:"locale"` // BCP 47 language tag, such as en-US
+}
+
+// PatchUserRequest represents the request body for partial user updates.
+type PatchUserRequest struct {
+	Username  *string `json:"username,omitempty"`
+	Email     *string `json:"email,omitempty"`
+	Role      *string `json:"role,omitempty"`
+	FirstName *string `json:"first_name,omitempty"`
+	LastName  *string `json:"last_name,omitempty"`
+	Phone     *string `json:"phone,omitempty"`
+	Locale    *string `json:"locale,omitempty"`
+}
+
+// ChangePasswordRequest represents the request body for a password change.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// Transaction represents a money transfer or operation.
+type Transaction struct {
+	ID         int
+	FromUserID *int
+	ToUserID   *int
+	Amount     float64
+	Type       string // credit, debit, transfer
+	Status     string // pending, pending_approval, completed, failed, rejected
+	CreatedAt  time.Time
+	// IdempotencyKey identifies the request that produced the transaction, if any
+	IdempotencyKey string
+	// Description is free text, such as the memo of an imported transaction
+	Description string
+	// Fee is what the payer was charged by the fee schedule in effect at the
+	// time, FeeScheduleID; zero and nil if none applied
+	Fee           float64
+	FeeScheduleID *int
+}
+
+// Balance represents a user's account balance with thread-safe operations.
+type Balance struct {
+	UserID        int
+	Amount        float64
+	HeldAmount    float64
+	Version       int
+	LastUpdatedAt time.Time
+	FrozenAt      *time.Time
+}
+
+// BalanceV2Response is a balance in API v2, with decimal string amounts
+type BalanceV2Response struct {
+	UserID           int       `json:"user_id"`
+	Balance          string    `json:"balance"`
+	AvailableBalance string    `json:"available_balance"`
+	LastUpdatedAt    time.Time `json:"last_updated_at"`
+}
+
+// AvailableBalanceV2Response is an AvailableBalanceResponse in API v2, with decimal string amounts
+type AvailableBalanceV2Response struct {
+	UserID           int    `json:"user_id"`
+	Balance          string `json:"balance"`
+	HeldAmount       string `json:"held_amount"`
+	AvailableBalance string `json:"available_balance"`
+}
+
+// ScheduledTransaction represents a transaction that will be executed at a future time
+type ScheduledTransaction struct {
+	ID             int        `json:"id"`
+	UserID         int        `json:"user_id"`
+	ToUserID       *int       `json:"to_user_id,omitempty"` // for transfers
+	Amount         float64    `json:"amount"`
+	Type           string     `json:"type"`   // "credit", "debit", "transfer"
+	Status         string     `json:"status"` // "pending", "paused", "completed", "failed", "cancelled"
+	ScheduleAt     time.Time  `json:"schedule_at"`
+	Recurring      bool       `json:"recurring"`
+	Recurrence     string     `json:"recurrence,omitempty"`      // "daily", "weekly", "monthly", "yearly", "cron"
+	CronExpression string     `json:"cron_expression,omitempty"` // required when recurrence is "cron"
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+	MaxRuns        *int       `json:"max_runs,omitempty"`
+	RunsCount      int        `json:"runs_count"`
+	RetryCount     int        `json:"retry_count"`             // failed attempts retried for the current run
+	NextRetryAt    *time.Time `json:"next_retry_at,omitempty"` // set while a failed run is waiting to be retried
+	Description    string     `json:"description,omitempty"`
+	GoalID         *int       `json:"goal_id,omitempty"` // savings goal funded by this transaction
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// CreateScheduledTransactionRequest represents a request to create a scheduled transaction
+type CreateScheduledTransactionRequest struct {
+	UserID         int       `json:"user_id"`
+	ToUserID       *int      `json:"to_user_id,omitempty"`
+	Amount         float64   `json:"amount"`
+	Type           string    `json:"type"`
+	ScheduleAt     time.Time `json:"schedule_at"`
+	Recurring      bool      `json:"recurring"`
+	Recurrence     string    `json:"recurrence,omitempty"`
+	CronExpression string    `json:"cron_expression,omitempty"`
+	MaxRuns        *int      `json:"max_runs,omitempty"`
+	Description    string    `json:"description,omitempty"`
+}
+
+// PreviewScheduledTransactionRequest represents a dry-run of a scheduled transaction
+type PreviewScheduledTransactionRequest struct {
+	CreateScheduledTransactionRequest
+	Occurrences int `json:"occurrences,omitempty"` // number of upcoming runs to project, default 10
+}
+
+// UpdateScheduledTransactionRequest represents a request to update a scheduled transaction
+type UpdateScheduledTransactionRequest struct {
+	Amount         *float64   `json:"amount,omitempty"`
+	ScheduleAt     *time.Time `json:"schedule_at,omitempty"`
+	Recurring      *bool      `json:"recurring,omitempty"`
+	Recurrence     *string    `json:"recurrence,omitempty"`
+	CronExpression *string    `json:"cron_expression,omitempty"`
+	MaxRuns        *int       `json:"max_runs,omitempty"`
+	Description    *string    `json:"description,omitempty"`
+}
+
+// ScheduledTransactionPreview describes what a scheduled transaction would do if created
+type ScheduledTransactionPreview struct {
+	Occurrences      []ScheduledOccurrence `json:"occurrences"`
+	CurrentBalance   float64               `json:"current_balance"`
+	TotalImpact      float64               `json:"total_impact"`
+	ProjectedBalance float64               `json:"projected_balance"`
+	Truncated        bool                  `json:"truncated"` // more occurrences exist beyond those listed
+}
+
+// ScheduledTransactionStats holds statistics about scheduled transactions
+type ScheduledTransactionStats struct {
+	TotalScheduled    int64
+	PendingCount      int64
+	PausedCount       int64
+	CompletedCount    int64
+	FailedCount       int64
+	CancelledCount    int64
+	RecurringCount    int64
+	OneTimeCount      int64
+	NextExecutionTime *string // ISO format string
+}
+
+// SubmitTaskRequest represents a request to submit a single task
+type SubmitTaskRequest struct {
+	Type        string  `json:"type"`
+	UserID      int     `json:"user_id"`
+	ToUserID    *int    `json:"to_user_id,omitempty"` // for transfers
+	Amount      float64 `json:"amount"`
+	Priority    int     `json:"priority,omitempty"`
+	CallbackURL string  `json:"callback_url,omitempty"` // receives a signed POST of the result
+	// Optional retry overrides for transient failures; zero uses the server defaults
+	MaxAttempts    int `json:"max_attempts,omitempty"`
+	RetryBackoffMs int `json:"retry_backoff_ms,omitempty"`
+}
+
+// SubmitTaskResponse represents the response for task submission
+type SubmitTaskResponse struct {
+	TaskID    string `json:"task_id"`
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// TaskStatusResponse represents the recorded status and timing of a task
+type TaskStatusResponse struct {
+	*TaskRecord
+	QueueWaitMs    int64 `json:"queue_wait_ms"`
+	ProcessingMs   int64 `json:"processing_ms"`
+	TotalElapsedMs int64 `json:"total_elapsed_ms"`
+}
+
+// SubmitBatchRequest represents a request to submit multiple tasks
+type SubmitBatchRequest struct {
+	Tasks []SubmitTaskRequest `json:"tasks"`
+	// RollbackOnFailure runs the tasks in order and undoes the completed ones if any fails
+	RollbackOnFailure bool `json:"rollback_on_failure,omitempty"`
+}
+
+// SubmitBatchResponse represents the response for batch submission
+type SubmitBatchResponse struct {
+	BatchID   string   `json:"batch_id"`
+	TaskIDs   []string `json:"task_ids"`
+	Status    string   `json:"status"`
+	Message   string   `json:"message"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+// BatchStatusResponse represents the progress and per-task outcomes of a batch
+type BatchStatusResponse struct {
+	*BatchRecord
+	BatchProgress
+	Tasks []*TaskRecord `json:"tasks"`
+}
+
+// GetStatsResponse represents the response for processing statistics
+type GetStatsResponse struct {
+	TotalProcessed     int64   `json:"total_processed"`
+	SuccessfulTasks    int64   `json:"successful_tasks"`
+	FailedTasks        int64   `json:"failed_tasks"`
+	QueueSize          int     `json:"queue_size"`
+	ActiveWorkers      int     `json:"active_workers"`
+	AverageProcessTime float64 `json:"average_process_time_seconds"`
+	Timestamp          int64   `json:"timestamp"`
+}
+
+// GetHealthResponse represents the health check response
+type GetHealthResponse struct {
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// DeadLetter is a task that kept failing and was set aside for operator review
+type DeadLetter struct {
+	ID          int        `json:"id"`
+	TaskID      string     `json:"task_id"`
+	Type        string     `json:"type"`
+	UserID      int        `json:"user_id"`
+	ToUserID    *int       `json:"to_user_id,omitempty"`
+	Amount      float64    `json:"amount"`
+	Priority    int        `json:"priority"`
+	CallbackURL string     `json:"callback_url,omitempty"`
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"last_error"`
+	FailedAt    time.Time  `json:"failed_at"`
+	RequeuedAt  *time.Time `json:"requeued_at,omitempty"`
+}
+
+// Problem is the body of every error response, following RFC 7807.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code,omitempty"`
+	TraceID  string `json:"trace_id,omitempty"`
+}
+
+// ScheduledOccurrence is a single projected execution in a preview
+type ScheduledOccurrence struct {
+	RunAt             time.Time `json:"run_at"`
+	BalanceImpact     float64   `json:"balance_impact"`
+	ProjectedBalance  float64   `json:"projected_balance"`
+	InsufficientFunds bool      `json:"insufficient_funds,omitempty"`
+}
+
+// TaskRecord is the persisted lifecycle and outcome of a submitted task
+type TaskRecord struct {
+	TaskID        string     `json:"task_id"`
+	Type          string     `json:"type"`
+	UserID        int        `json:"user_id"`
+	ToUserID      *int       `json:"to_user_id,omitempty"`
+	Amount        float64    `json:"amount"`
+	Priority      int        `json:"priority"`
+	Status        string     `json:"status"` // "queued", "processing", "succeeded", "failed"
+	Error         string     `json:"error,omitempty"`
+	TransactionID *int       `json:"transaction_id,omitempty"`
+	Attempts      int        `json:"attempts"`
+	BatchID       string     `json:"batch_id,omitempty"`
+	SubmittedAt   time.Time  `json:"submitted_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// BatchRecord is the persisted metadata of a batch of tasks.
+type BatchRecord struct {
+	BatchID     string     `json:"batch_id"`
+	Status      string     `json:"status"` // "processing", "completed", "timed_out", "rolled_back"
+	TotalTasks  int        `json:"total_tasks"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// BatchProgress summarises how far a batch has got
+type BatchProgress struct {
+	SuccessfulTasks int              `json:"successful_tasks"`
+	FailedTasks     int              `json:"failed_tasks"`
+	PendingTasks    int              `json:"pending_tasks"`
+	ProgressPercent float64          `json:"progress_percent"`
+	Errors          []BatchTaskError `json:"errors"`
+}
+
+// BatchTaskError is the failure of a single task within a batch
+type BatchTaskError struct {
+	TaskID string `json:"task_id"`
+	Error  string `json:"error"`
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Register creates a user
+func (c *Client) Register(ctx context.Context, req RegisterRequest) (*User, error) {
+	var user User
+	if err := c.do(ctx, &call{method: http.MethodPost, path: "/auth/register", body: req}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Login logs in and authenticates further requests of the client with the returned token.
+func (c *Client) Login(ctx context.Context, username, password string) (*LoginResponse, error) {
+	var resp LoginResponse
+	req := LoginRequest{Username: username, Password: password}
+	if err := c.do(ctx, &call{method: http.MethodPost, path: "/auth/login", body: req}, &resp); err != nil {
+		return nil, err
+	}
+	if !resp.VerificationRequired {
+		c.SetToken(resp.Token)
+	}
+	return &resp, nil
+}
+
+// VerifyLogin completes a login that required verification with the code sent to the user.
+func (c *Client) VerifyLogin(ctx context.Context, challengeID, code string) (*LoginResponse, error) {
+	var resp LoginResponse
+	req := VerifyLoginRequest{ChallengeID: challengeID, Code: code}
+	if err := c.do(ctx, &call{method: http.MethodPost, path: "/auth/login/verify", body: req}, &resp); err != nil {
+		return nil, err
+	}
+	c.SetToken(resp.Token)
+	return &resp, nil
+}
+
+// Logout revokes the client's token and stops sending it
+func (c *Client) Logout(ctx context.Context) error {
+	if err := c.do(ctx, &call{method: http.MethodPost, path: "/auth/logout"}, nil); err != nil {
+		return err
+	}
+	c.SetToken("")
+	return nil
+}
+
+// UserListing selects and orders the users ListUsers returns.
+type UserListing struct {
+	Role   string
+	Erased *bool  // whether the user's personal data has been erased
+	Sort   string // "id" (the default), "created_at" or "-created_at"
+	Limit  int
+	Offset int
+}
+
+// values encodes the listing as query parameters
+func (l UserListing) values() url.Values {
+	q := url.Values{"offset": {strconv.Itoa(l.Offset)}}
+	if l.Role != "" {
+		q.Set("role", l.Role)
+	}
+	if l.Erased != nil {
+		q.Set("erased", strconv.FormatBool(*l.Erased))
+	}
+	if l.Sort != "" {
+		q.Set("sort", l.Sort)
+	}
+	if l.Limit > 0 {
+		q.Set("limit", strconv.Itoa(l.Limit))
+	}
+	return q
+}
+
+// ListUsers lists a page of users; admins only
+func (c *Client) ListUsers(ctx context.Context, listing UserListing) ([]User, error) {
+	var users []User
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/users", query: listing.values()}, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetUser retrieves a user
+func (c *Client) GetUser(ctx context.Context, id int) (*User, error) {
+	var user User
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/users/" + strconv.Itoa(id)}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser replaces a user's username, email and profile.
+func (c *Client) UpdateUser(ctx context.Context, id int, req UpdateRequest) (*User, error) {
+	var user User
+	if err := c.do(ctx, &call{method: http.MethodPut, path: "/users/" + strconv.Itoa(id), body: req}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// PatchUser changes the fields set in req, keeping the others
+func (c *Client) PatchUser(ctx context.Context, id int, req PatchUserRequest) (*User, error) {
+	var user User
+	if err := c.do(ctx, &call{method: http.MethodPatch, path: "/users/" + strconv.Itoa(id), body: req}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeleteAvatar removes a user's avatar
+func (c *Client) DeleteAvatar(ctx context.Context, id int) error {
+	return c.do(ctx, &call{method: http.MethodDelete, path: "/users/" + strconv.Itoa(id) + "/avatar"}, nil)
+}
+
+// ListDevices lists the devices a user trusts, most recently used first
+func (c *Client) ListDevices(ctx context.Context, userID int) ([]Device, error) {
+	var devices []Device
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/users/" + strconv.Itoa(userID) + "/devices"}, &devices); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// RevokeDevice stops trusting a device of a user; logging in from it needs verification again
+func (c *Client) RevokeDevice(ctx context.Context, userID int, fingerprint string) error {
+	path := "/users/" + strconv.Itoa(userID) + "/devices/" + url.PathEscape(fingerprint)
+	return c.do(ctx, &call{method: http.MethodDelete, path: path}, nil)
+}
+
+// GetPreferences retrieves the preferences a user has set by key
+func (c *Client) GetPreferences(ctx context.Context, userID int) (map[string]json.RawMessage, error) {
+	var prefs map[string]json.RawMessage
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/users/" + strconv.Itoa(userID) + "/preferences"}, &prefs); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences merges changes into a user's preferences and returns the result.
+func (c *Client) UpdatePreferences(ctx context.Context, userID int, changes map[string]any) (map[string]json.RawMessage, error) {
+	var prefs map[string]json.RawMessage
+	if err := c.do(ctx, &call{method: http.MethodPatch, path: "/users/" + strconv.Itoa(userID) + "/preferences", body: changes}, &prefs); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// DeleteUser deletes a user
+func (c *Client) DeleteUser(ctx context.Context, id int) error {
+	return c.do(ctx, &call{method: http.MethodDelete, path: "/users/" + strconv.Itoa(id)}, nil)
+}
+
+// ChangePassword changes a user's password; they have to log in again afterwards
+func (c *Client) ChangePassword(ctx context.Context, id int, currentPassword, newPassword string) error {
+	req := ChangePasswordRequest{CurrentPassword: currentPassword, NewPassword: newPassword}
+	return c.do(ctx, &call{method: http.MethodPut, path: "/users/" + strconv.Itoa(id) + "/password", body: req}, nil)
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// SubmitTask queues a credit, debit or transfer for the worker pool
+func (c *Client) SubmitTask(ctx context.Context, req SubmitTaskRequest) (*SubmitTaskResponse, error) {
+	var resp SubmitTaskResponse
+	if err := c.do(ctx, &call{method: http.MethodPost, path: "/worker/tasks", body: req}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetTask retrieves the status and outcome of a submitted task
+func (c *Client) GetTask(ctx context.Context, taskID string) (*TaskStatusResponse, error) {
+	var resp TaskStatusResponse
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/worker/tasks/" + url.PathEscape(taskID)}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SubmitBatch queues up to 100 tasks as one batch
+func (c *Client) SubmitBatch(ctx context.Context, req SubmitBatchRequest) (*SubmitBatchResponse, error) {
+	var resp SubmitBatchResponse
+	if err := c.do(ctx, &call{method: http.MethodPost, path: "/worker/batch", body: req}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetBatch retrieves the progress of a submitted batch and the outcome of its tasks
+func (c *Client) GetBatch(ctx context.Context, batchID string) (*BatchStatusResponse, error) {
+	var resp BatchStatusResponse
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/worker/batch/" + url.PathEscape(batchID)}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetWorkerStats retrieves the worker pool's processing statistics
+func (c *Client) GetWorkerStats(ctx context.Context) (*GetStatsResponse, error) {
+	var resp GetStatsResponse
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/worker/stats"}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetWorkerHealth retrieves the health of the worker pool
+func (c *Client) GetWorkerHealth(ctx context.Context) (*GetHealthResponse, error) {
+	var resp GetHealthResponse
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/worker/health"}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListDeadLetters lists tasks set aside after exhausting their retries; admins only
+func (c *Client) ListDeadLetters(ctx context.Context, includeRequeued bool, limit, offset int) ([]DeadLetter, error) {
+	query := url.Values{
+		"include_requeued": {strconv.FormatBool(includeRequeued)},
+		"limit":            {strconv.Itoa(limit)},
+		"offset":           {strconv.Itoa(offset)},
+	}
+	var entries []DeadLetter
+	if err := c.do(ctx, &call{method: http.MethodGet, path: "/worker/dlq", query: query}, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// RequeueDeadLetter submits a dead-lettered task again; admins only
+func (c *Client) RequeueDeadLetter(ctx context.Context, id int) (*SubmitTaskResponse, error) {
+	var resp SubmitTaskResponse
+	if err := c.do(ctx, &call{method: http.MethodPost, path: "/worker/dlq/" + strconv.Itoa(id) + "/requeue"}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,374 @@
+// Package cron parses cron expressions and computes their next activation times.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears bounds how far ahead Next looks for a matching time.
+const maxSearchYears = 5
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var dayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// nthWeekday matches the n-th occurrence of a weekday in the month.
+type nthWeekday struct {
+	weekday time.Weekday
+	n       int
+}
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minutes uint64
+	hours   uint64
+	months  uint64
+
+	days           uint64
+	daysAny        bool
+	lastDay        bool
+	lastWeekday    bool
+	nearestWeekday []int
+
+	weekdays     uint64
+	weekdaysAny  bool
+	nthWeekdays  []nthWeekday
+	lastWeekdays []time.Weekday
+}
+
+// Parse parses a cron expression into a Schedule.
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if d, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = d
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields, got %d", len(fields))
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.minutes, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minute field: %w", err)
+	}
+	if s.hours, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("hour field: %w", err)
+	}
+	if err = s.parseDayOfMonth(fields[2]); err != nil {
+		return nil, fmt.Errorf("day-of-month field: %w", err)
+	}
+	if s.months, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("month field: %w", err)
+	}
+	if err = s.parseDayOfWeek(fields[4]); err != nil {
+		return nil, fmt.Errorf("day-of-week field: %w", err)
+	}
+
+	return s, nil
+}
+
+// Validate reports whether expr is a valid cron expression.
+func Validate(expr string) error {
+	_, err := Parse(expr)
+	return err
+}
+
+// Next returns the first activation time strictly after t, in t's location.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	first := true
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	limit := day.AddDate(maxSearchYears, 0, 0)
+
+	for ; day.Before(limit); day, first = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc), false {
+		if !has(s.months, int(day.Month())) || !s.dayMatches(day) {
+			continue
+		}
+
+		startHour, startMinute := 0, 0
+		if first {
+			startHour, startMinute = t.Hour(), t.Minute()
+		}
+		for h := startHour; h < 24; h++ {
+			if !has(s.hours, h) {
+				continue
+			}
+			m := 0
+			if h == startHour {
+				m = startMinute
+			}
+			for ; m < 60; m++ {
+				if has(s.minutes, m) {
+					return time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, loc)
+				}
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// dayMatches applies the day-of-month and day-of-week rules.
+func (s *Schedule) dayMatches(day time.Time) bool {
+	switch {
+	case s.daysAny && s.weekdaysAny:
+		return true
+	case s.daysAny:
+		return s.weekdayMatches(day)
+	case s.weekdaysAny:
+		return s.dayOfMonthMatches(day)
+	default:
+		return s.dayOfMonthMatches(day) || s.weekdayMatches(day)
+	}
+}
+
+func (s *Schedule) dayOfMonthMatches(day time.Time) bool {
+	d := day.Day()
+	last := daysIn(day.Year(), day.Month())
+
+	if has(s.days, d) {
+		return true
+	}
+	if s.lastDay && d == last {
+		return true
+	}
+	if s.lastWeekday && d == lastBusinessDay(day.Year(), day.Month(), day.Location()) {
+		return true
+	}
+	for _, n := range s.nearestWeekday {
+		if d == nearestBusinessDay(day.Year(), day.Month(), n, day.Location()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schedule) weekdayMatches(day time.Time) bool {
+	wd := day.Weekday()
+	if has(s.weekdays, int(wd)) {
+		return true
+	}
+	for _, nth := range s.nthWeekdays {
+		if nth.weekday == wd && (day.Day()-1)/7+1 == nth.n {
+			return true
+		}
+	}
+	for _, lw := range s.lastWeekdays {
+		if lw == wd && day.Day()+7 > daysIn(day.Year(), day.Month()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schedule) parseDayOfMonth(field string) error {
+	if field == "*" || field == "?" {
+		s.daysAny = true
+		return nil
+	}
+
+	for _, part := range strings.Split(field, ",") {
+		upper := strings.ToUpper(part)
+		switch {
+		case upper == "L":
+			s.lastDay = true
+		case upper == "LW":
+			s.lastWeekday = true
+		case strings.HasSuffix(upper, "W"):
+			n, err := strconv.Atoi(strings.TrimSuffix(upper, "W"))
+			if err != nil || n < 1 || n > 31 {
+				return fmt.Errorf("invalid nearest-weekday value %q", part)
+			}
+			s.nearestWeekday = append(s.nearestWeekday, n)
+		default:
+			bits, err := parseField(part, 1, 31, nil)
+			if err != nil {
+				return err
+			}
+			s.days |= bits
+		}
+	}
+	return nil
+}
+
+func (s *Schedule) parseDayOfWeek(field string) error {
+	if field == "*" || field == "?" {
+		s.weekdaysAny = true
+		return nil
+	}
+
+	for _, part := range strings.Split(field, ",") {
+		upper := strings.ToUpper(part)
+		switch {
+		case strings.Contains(upper, "#"):
+			pieces := strings.SplitN(upper, "#", 2)
+			wd, err := parseValue(pieces[0], 0, 7, dayNames)
+			if err != nil {
+				return err
+			}
+			n, err := strconv.Atoi(pieces[1])
+			if err != nil || n < 1 || n > 5 {
+				return fmt.Errorf("invalid occurrence in %q", part)
+			}
+			s.nthWeekdays = append(s.nthWeekdays, nthWeekday{weekday: time.Weekday(wd % 7), n: n})
+		case len(upper) > 1 && strings.HasSuffix(upper, "L"):
+			wd, err := parseValue(strings.TrimSuffix(upper, "L"), 0, 7, dayNames)
+			if err != nil {
+				return err
+			}
+			s.lastWeekdays = append(s.lastWeekdays, time.Weekday(wd%7))
+		default:
+			bits, err := parseField(part, 0, 7, dayNames)
+			if err != nil {
+				return err
+			}
+			// 7 is an alias for Sunday
+			if has(bits, 7) {
+				bits |= 1
+			}
+			s.weekdays |= bits
+		}
+	}
+	return nil
+}
+
+// parseField parses a comma-separated list of values, ranges and steps into a bitset.
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		b, err := parseRange(part, min, max, names)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	return bits, nil
+}
+
+// parseRange parses one of: *, */step, n, a-b, a-b/step, n/step.
+func parseRange(part string, min, max int, names map[string]int) (uint64, error) {
+	if part == "" {
+		return 0, fmt.Errorf("empty value")
+	}
+
+	rangePart, step := part, 1
+	if i := strings.Index(part, "/"); i >= 0 {
+		var err error
+		step, err = strconv.Atoi(part[i+1:])
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step in %q", part)
+		}
+		rangePart = part[:i]
+	}
+
+	var lo, hi int
+	switch {
+	case rangePart == "*":
+		lo, hi = min, max
+	case strings.Contains(rangePart, "-"):
+		bounds := strings.SplitN(rangePart, "-", 2)
+		var err error
+		if lo, err = parseValue(bounds[0], min, max, names); err != nil {
+			return 0, err
+		}
+		if hi, err = parseValue(bounds[1], min, max, names); err != nil {
+			return 0, err
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q", part)
+		}
+	default:
+		var err error
+		if lo, err = parseValue(rangePart, min, max, names); err != nil {
+			return 0, err
+		}
+		hi = lo
+		if step > 1 {
+			hi = max // "n/step" means starting at n
+		}
+	}
+
+	var bits uint64
+	for v := lo; v <= hi; v += step {
+		bits |= 1 << uint(v)
+	}
+	return bits, nil
+}
+
+// parseValue parses a single numeric or named value and checks its bounds.
+func parseValue(s string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, min, max)
+	}
+	return v, nil
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// lastBusinessDay returns the day number of the last Monday-Friday of the month.
+func lastBusinessDay(year int, month time.Month, loc *time.Location) int {
+	d := daysIn(year, month)
+	switch time.Date(year, month, d, 0, 0, 0, 0, loc).Weekday() {
+	case time.Saturday:
+		return d - 1
+	case time.Sunday:
+		return d - 2
+	}
+	return d
+}
+
+// nearestBusinessDay returns the weekday closest to day n without crossing into another month.
+func nearestBusinessDay(year int, month time.Month, n int, loc *time.Location) int {
+	last := daysIn(year, month)
+	if n > last {
+		n = last
+	}
+	switch time.Date(year, month, n, 0, 0, 0, 0, loc).Weekday() {
+	case time.Saturday:
+		if n == 1 {
+			return 3 // the following Monday
+		}
+		return n - 1
+	case time.Sunday:
+		if n == last {
+			return n - 2 // the preceding Friday
+		}
+		return n + 1
+	}
+	return n
+}
@@ -0,0 +1,79 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestSchedule_Next(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"every 15 minutes", "*/15 * * * *", date(2026, 10, 16, 10, 7), date(2026, 10, 16, 10, 15)},
+		{"strictly after from", "30 9 * * *", date(2026, 10, 16, 9, 30), date(2026, 10, 17, 9, 30)},
+		{"monthly descriptor", "@monthly", date(2026, 10, 16, 0, 0), date(2026, 11, 1, 0, 0)},
+		{"last day of month", "0 0 L * *", date(2026, 2, 3, 0, 0), date(2026, 2, 28, 0, 0)},
+		{"last business day skips weekend", "0 9 LW * *", date(2026, 10, 1, 0, 0), date(2026, 10, 30, 9, 0)},
+		{"second friday", "0 9 * * 5#2", date(2026, 10, 16, 0, 0), date(2026, 11, 13, 9, 0)},
+		{"last monday", "0 8 * * MON#5,1L", date(2026, 11, 1, 0, 0), date(2026, 11, 30, 8, 0)},
+		{"nearest weekday to 1st on saturday", "0 9 1W * *", date(2026, 7, 31, 0, 0), date(2026, 8, 3, 9, 0)},
+		{"nearest weekday to 15th on sunday", "0 9 15W * *", date(2026, 11, 1, 0, 0), date(2026, 11, 16, 9, 0)},
+		{"weekdays range with names", "0 17 * JAN-MAR MON-FRI", date(2026, 10, 16, 0, 0), date(2027, 1, 1, 17, 0)},
+		{"sunday as 7", "0 0 * * 7", date(2026, 10, 16, 0, 0), date(2026, 10, 18, 0, 0)},
+		{"day-of-month or day-of-week", "0 0 13 * 5", date(2026, 10, 10, 0, 0), date(2026, 10, 13, 0, 0)},
+		{"leap day", "0 0 29 2 *", date(2026, 3, 1, 0, 0), date(2028, 2, 29, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.expr, err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_NextNoMatch(t *testing.T) {
+	s, err := Parse("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := s.Next(date(2026, 1, 1, 0, 0)); !got.IsZero() {
+		t.Errorf("expected zero time for impossible schedule, got %v", got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * 32W * *",
+		"* * * * 5#6",
+		"* * * * FOO",
+		"@every-minute",
+	}
+
+	for _, expr := range invalid {
+		if err := Validate(expr); err == nil {
+			t.Errorf("Validate(%q) expected error, got nil", expr)
+		}
+	}
+}
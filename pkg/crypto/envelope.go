@@ -0,0 +1,249 @@
+// Package crypto provides envelope encryption for individual database fields.
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// keySize is the length of every key: AES-256
+const keySize = 32
+
+// envelopePrefix marks a value produced by Encrypt
+const envelopePrefix = "enc:v1:"
+
+var (
+	// ErrUnknownKey is returned when a value was encrypted with a key the keyring doesn't hold
+	ErrUnknownKey = errors.New("crypto: unknown key id")
+	// ErrMalformed is returned when an encrypted value can't be parsed
+	ErrMalformed = errors.New("crypto: malformed encrypted value")
+)
+
+// Keyring holds the key-encryption keys by ID.
+type Keyring struct {
+	keys     map[string][]byte
+	primary  string
+	indexKey []byte
+}
+
+// NewKeyring creates a keyring whose primary key is primaryID.
+func NewKeyring(primaryID string, keys map[string][]byte, indexKey []byte) (*Keyring, error) {
+	if _, ok := keys[primaryID]; !ok {
+		return nil, fmt.Errorf("crypto: primary key %q is not in the keyring", primaryID)
+	}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("crypto: invalid key id %q", id)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("crypto: key %q must be %d bytes, got %d", id, keySize, len(key))
+		}
+	}
+	if len(indexKey) < keySize {
+		return nil, fmt.Errorf("crypto: index key must be at least %d bytes", keySize)
+	}
+	return &Keyring{keys: keys, primary: primaryID, indexKey: indexKey}, nil
+}
+
+// ParseKeyring builds a keyring from a spec of comma-separated "id:base64key" entries.
+func ParseKeyring(spec, indexKey string) (*Keyring, error) {
+	keys := make(map[string][]byte)
+	primary := ""
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("crypto: key entry %q must be id:base64key", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("crypto: key %q is not valid base64: %w", id, err)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("crypto: duplicate key id %q", id)
+		}
+		keys[id] = key
+		if primary == "" {
+			primary = id
+		}
+	}
+	if primary == "" {
+		return nil, errors.New("crypto: no keys given")
+	}
+
+	index, err := base64.StdEncoding.DecodeString(indexKey)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: index key is not valid base64: %w", err)
+	}
+	return NewKeyring(primary, keys, index)
+}
+
+// Encrypt encrypts plaintext under a new data key wrapped with the primary key
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("crypto: failed to generate data key: %w", err)
+	}
+
+	ciphertext, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := seal(k.keys[k.primary], dataKey)
+	if err != nil {
+		return "", err
+	}
+	return format(k.primary, wrapped, ciphertext), nil
+}
+
+// Decrypt returns the plaintext of a value produced by Encrypt.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	keyID, wrapped, ciphertext, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := k.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether value is legacy plaintext or wrapped with a key other than the primary
+func (k *Keyring) NeedsRotation(value string) bool {
+	if !IsEncrypted(value) {
+		return true
+	}
+	keyID, _, _, err := parse(value)
+	return err != nil || keyID != k.primary
+}
+
+// Rotate rewraps value's data key with the primary key.
+func (k *Keyring) Rotate(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return k.Encrypt(value)
+	}
+	keyID, wrapped, ciphertext, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	if keyID == k.primary {
+		return value, nil
+	}
+	dataKey, err := k.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	rewrapped, err := seal(k.keys[k.primary], dataKey)
+	if err != nil {
+		return "", err
+	}
+	return format(k.primary, rewrapped, ciphertext), nil
+}
+
+// PrimaryKeyID returns the ID of the key new values are wrapped with
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primary
+}
+
+// BlindIndex returns a deterministic keyed hash of value for equality lookups.
+func (k *Keyring) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether value carries the envelope prefix
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+// unwrap decrypts a data key with the named key-encryption key
+func (k *Keyring) unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	kek, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+	return open(kek, wrapped)
+}
+
+// format serializes the parts of an encrypted value
+func format(keyID string, wrapped, ciphertext []byte) string {
+	return envelopePrefix + keyID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext)
+}
+
+// parse splits an encrypted value into its parts
+func parse(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, envelopePrefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, ErrMalformed
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	return parts[0], wrapped, ciphertext, nil
+}
+
+// seal encrypts plaintext with AES-GCM, prefixing the random nonce
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("crypto: failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts the output of seal
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: decryption failed: %w", err)
+	}
+	return plaintext, nil
+}
+
+// newGCM creates an AES-GCM cipher for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,132 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKeyring(t *testing.T, primary string, ids ...string) *Keyring {
+	t.Helper()
+	keys := make(map[string][]byte)
+	for i, id := range ids {
+		keys[id] = bytes.Repeat([]byte{byte(i + 1)}, keySize)
+	}
+	k, err := NewKeyring(primary, keys, bytes.Repeat([]byte{0xAA}, keySize))
+	if err != nil {
+		t.Fatalf("NewKeyring returned error: %v", err)
+	}
+	return k
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	k := testKeyring(t, "k1", "k1")
+
+	a, err := k.Encrypt("alice@example.com")
+	if err != nil {
+		t.Fatalf("Encrypt returned error: %v", err)
+	}
+	b, _ := k.Encrypt("alice@example.com")
+	if a == b {
+		t.Error("expected two encryptions of the same value to differ")
+	}
+	if strings.Contains(a, "alice") {
+		t.Errorf("ciphertext leaks plaintext: %s", a)
+	}
+
+	got, err := k.Decrypt(a)
+	if err != nil {
+		t.Fatalf("Decrypt returned error: %v", err)
+	}
+	if got != "alice@example.com" {
+		t.Errorf("Decrypt = %q, want %q", got, "alice@example.com")
+	}
+}
+
+func TestKeyring_DecryptLegacyPlaintext(t *testing.T) {
+	k := testKeyring(t, "k1", "k1")
+
+	got, err := k.Decrypt("bob@example.com")
+	if err != nil || got != "bob@example.com" {
+		t.Errorf("Decrypt(plaintext) = %q, %v; want value unchanged", got, err)
+	}
+	if !k.NeedsRotation("bob@example.com") {
+		t.Error("expected legacy plaintext to need rotation")
+	}
+}
+
+func TestKeyring_Rotate(t *testing.T) {
+	old := testKeyring(t, "k1", "k1")
+	value, _ := old.Encrypt("carol@example.com")
+
+	rotated := testKeyring(t, "k2", "k1", "k2")
+	if !rotated.NeedsRotation(value) {
+		t.Fatal("expected value under old primary key to need rotation")
+	}
+	newValue, err := rotated.Rotate(value)
+	if err != nil {
+		t.Fatalf("Rotate returned error: %v", err)
+	}
+	if rotated.NeedsRotation(newValue) {
+		t.Error("expected rotated value to be under the primary key")
+	}
+	// The data ciphertext is kept; only the wrapped key changes
+	if value[strings.LastIndex(value, ":"):] != newValue[strings.LastIndex(newValue, ":"):] {
+		t.Error("expected rotation to keep the value ciphertext")
+	}
+
+	// Once k1 is retired, the rotated value still decrypts
+	retired := testKeyring(t, "k2", "k2")
+	retired.keys["k2"] = rotated.keys["k2"]
+	got, err := retired.Decrypt(newValue)
+	if err != nil || got != "carol@example.com" {
+		t.Errorf("Decrypt after retiring old key = %q, %v", got, err)
+	}
+	if _, err := retired.Decrypt(value); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Decrypt with retired key error = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestKeyring_DecryptTampered(t *testing.T) {
+	k := testKeyring(t, "k1", "k1")
+	value, _ := k.Encrypt("dave@example.com")
+
+	tampered := value[:len(value)-2] + "AA"
+	if tampered == value {
+		tampered = value[:len(value)-2] + "BB"
+	}
+	if _, err := k.Decrypt(tampered); err == nil {
+		t.Error("expected tampered ciphertext to fail authentication")
+	}
+	if _, err := k.Decrypt("enc:v1:k1:not-base64"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Decrypt(malformed) error = %v, want ErrMalformed", err)
+	}
+}
+
+func TestKeyring_BlindIndex(t *testing.T) {
+	k := testKeyring(t, "k1", "k1")
+	if k.BlindIndex("erin@example.com") != k.BlindIndex("erin@example.com") {
+		t.Error("expected blind index to be deterministic")
+	}
+	if k.BlindIndex("erin@example.com") == k.BlindIndex("frank@example.com") {
+		t.Error("expected different values to have different blind indexes")
+	}
+}
+
+func TestParseKeyring(t *testing.T) {
+	key := strings.Repeat("A", 43) + "=" // 32 zero bytes
+	k, err := ParseKeyring("new:"+key+", old:"+key, key)
+	if err != nil {
+		t.Fatalf("ParseKeyring returned error: %v", err)
+	}
+	if k.PrimaryKeyID() != "new" {
+		t.Errorf("PrimaryKeyID = %q, want %q", k.PrimaryKeyID(), "new")
+	}
+
+	for _, spec := range []string{"", "nokey", "k1:!!!", "k1:" + key + ",k1:" + key, "short:AAAA"} {
+		if _, err := ParseKeyring(spec, key); err == nil {
+			t.Errorf("ParseKeyring(%q) expected error", spec)
+		}
+	}
+}
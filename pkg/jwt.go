@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"time"
@@ -10,26 +11,68 @@ import (
 	"github.com/melihgurlek/backend-path/internal/middleware"
 )
 
+// TokenTTL is how long an issued token stays valid.
+const TokenTTL = 15 * time.Minute
+
+// KeySource supplies the HMAC keys for signing and verifying tokens.
+type KeySource interface {
+	Keys(ctx context.Context) ([]string, error)
+}
+
+// StaticKey is a KeySource with a single fixed key.
+type StaticKey string
+
+// Keys returns the key itself.
+func (k StaticKey) Keys(context.Context) ([]string, error) {
+	return []string{string(k)}, nil
+}
+
+// SigningKey returns the key new tokens should be signed with.
+func SigningKey(ctx context.Context, keys KeySource) (string, error) {
+	all, err := keys.Keys(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(all) == 0 {
+		return "", errors.New("no signing key available")
+	}
+	return all[0], nil
+}
+
 // JWTValidatorImpl implements the JWTValidator interface for validating JWT tokens.
 type JWTValidatorImpl struct {
-	secret string
+	keys KeySource
 }
 
 // NewJWTValidator creates a new JWTValidatorImpl with the given secret key.
 func NewJWTValidator(secret string) *JWTValidatorImpl {
-	return &JWTValidatorImpl{secret: secret}
+	return &JWTValidatorImpl{keys: StaticKey(secret)}
+}
+
+// NewRotatingJWTValidator creates a JWTValidatorImpl accepting any key offered by keys.
+func NewRotatingJWTValidator(keys KeySource) *JWTValidatorImpl {
+	return &JWTValidatorImpl{keys: keys}
 }
 
 // ValidateToken parses and validates a JWT token string, returning user claims if valid.
 func (j *JWTValidatorImpl) ValidateToken(tokenString string) (*middleware.UserClaims, error) {
 	errWrongMethod := errors.New("unexpected signing method")
 
+	secrets, err := j.keys.Keys(context.Background())
+	if err != nil {
+		return nil, errors.New("token keys unavailable")
+	}
+	verificationKeys := jwt.VerificationKeySet{}
+	for _, secret := range secrets {
+		verificationKeys.Keys = append(verificationKeys.Keys, []byte(secret))
+	}
+
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// Ensure the signing method is HMAC
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errWrongMethod
 		}
-		return []byte(j.secret), nil
+		return verificationKeys, nil
 	})
 	if err != nil {
 		if strings.Contains(err.Error(), errWrongMethod.Error()) {
@@ -64,22 +107,45 @@ func (j *JWTValidatorImpl) ValidateToken(tokenString string) (*middleware.UserCl
 		return nil, errors.New("jti claim missing or invalid")
 	}
 
+	// exp was checked by the parser; ver is optional and a token without it
+	// has the first version
+	var expiresAt, version int64
+	if exp, ok := claims["exp"].(float64); ok {
+		expiresAt = int64(exp)
+	}
+	if ver, ok := claims["ver"].(float64); ok {
+		version = int64(ver)
+	}
+
+	// scope is space-separated; a token without it isn't limited
+	var scopes []string
+	if scope, ok := claims["scope"].(string); ok {
+		scopes = strings.Fields(scope)
+	}
+
 	return &middleware.UserClaims{
-		UserID: userID,
-		Role:   role,
-		JTI:    jti,
+		UserID:       userID,
+		Role:         role,
+		JTI:          jti,
+		ExpiresAt:    expiresAt,
+		TokenVersion: version,
+		Scopes:       scopes,
 	}, nil
 }
 
 // GenerateToken creates a new JWT token with the given user claims.
-func GenerateToken(secret string, userID string, role string) (string, error) {
+func GenerateToken(secret string, userID string, role string, version int64, scopes ...string) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"role":    role,
+		"ver":     version,
 		"jti":     uuid.New().String(),
-		"exp":     time.Now().Add(15 * time.Minute).Unix(), // 15 minute expiration
+		"exp":     time.Now().Add(TokenTTL).Unix(),
 		"iat":     time.Now().Unix(),
 	}
+	if len(scopes) > 0 {
+		claims["scope"] = strings.Join(scopes, " ")
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secret))
@@ -91,3 +91,28 @@ func TestJWTValidatorImpl_ValidateToken(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateToken_Scopes(t *testing.T) {
+	validator := NewJWTValidator("testsecret")
+
+	token, err := GenerateToken("testsecret", "1", "user", 3, "transactions:read", "balances:read")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claims, err := validator.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(claims.Scopes) != 2 || claims.Scopes[0] != "transactions:read" || claims.Scopes[1] != "balances:read" {
+		t.Errorf("expected the token's scopes, got %v", claims.Scopes)
+	}
+	if claims.TokenVersion != 3 {
+		t.Errorf("expected token version 3, got %d", claims.TokenVersion)
+	}
+
+	token, _ = GenerateToken("testsecret", "1", "user", 0)
+	claims, _ = validator.ValidateToken(token)
+	if claims.Scoped() {
+		t.Errorf("expected an unscoped token, got scopes %v", claims.Scopes)
+	}
+}
@@ -0,0 +1,137 @@
+// Package lifecycle shuts an application's subsystems down in order.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// StopFunc stops one subsystem, giving up when ctx is done
+type StopFunc func(ctx context.Context) error
+
+// Func adapts a stop function that takes no context and cannot fail
+func Func(stop func()) StopFunc {
+	return func(ctx context.Context) error {
+		stop()
+		return nil
+	}
+}
+
+// Closer adapts a close function that takes no context, such as io.Closer's
+func Closer(close func() error) StopFunc {
+	return func(ctx context.Context) error {
+		return close()
+	}
+}
+
+// Manager runs shutdown stages in the order they were added.
+type Manager struct {
+	mu     sync.Mutex
+	stages []*Stage
+}
+
+// Stage is one step of shutdown, such as closing connections
+type Stage struct {
+	name    string
+	timeout time.Duration
+
+	mu    sync.Mutex
+	hooks []hook
+}
+
+type hook struct {
+	name string
+	stop StopFunc
+}
+
+// New creates a new Manager with no stages
+func New() *Manager {
+	return &Manager{}
+}
+
+// Stage adds a stage that runs after every stage added before it
+func (m *Manager) Stage(name string, timeout time.Duration) *Stage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := &Stage{name: name, timeout: timeout}
+	m.stages = append(m.stages, s)
+	return s
+}
+
+// Add registers a subsystem to stop in this stage
+func (s *Stage) Add(name string, stop StopFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook{name: name, stop: stop})
+}
+
+// Shutdown runs every stage and returns the errors of the hooks that failed or timed out.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	stages := append([]*Stage(nil), m.stages...)
+	m.mu.Unlock()
+
+	var errs []error
+	for _, s := range stages {
+		errs = append(errs, s.run(ctx)...)
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Stage) run(ctx context.Context) []error {
+	s.mu.Lock()
+	hooks := append([]hook(nil), s.hooks...)
+	s.mu.Unlock()
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	start := time.Now()
+	log.Info().Str("stage", s.name).Int("hooks", len(hooks)).Dur("timeout", s.timeout).Msg("Shutdown stage starting")
+
+	errCh := make(chan error, len(hooks))
+	for _, h := range hooks {
+		go func() {
+			errCh <- s.stop(ctx, h)
+		}()
+	}
+
+	var errs []error
+	for range hooks {
+		if err := <-errCh; err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	log.Info().Str("stage", s.name).Dur("took", time.Since(start)).Int("failed", len(errs)).Msg("Shutdown stage finished")
+	return errs
+}
+
+// stop runs one hook, giving up on it at the stage's deadline
+func (s *Stage) stop(ctx context.Context, h hook) error {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- h.stop(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("gave up waiting: %w", ctx.Err())
+	}
+	if err != nil {
+		log.Error().Err(err).Str("stage", s.name).Str("hook", h.name).Dur("took", time.Since(start)).Msg("Failed to stop subsystem")
+		return fmt.Errorf("%s/%s: %w", s.name, h.name, err)
+	}
+	log.Info().Str("stage", s.name).Str("hook", h.name).Dur("took", time.Since(start)).Msg("Stopped subsystem")
+	return nil
+}
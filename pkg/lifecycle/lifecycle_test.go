@@ -0,0 +1,55 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_RunsStagesInOrder(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) StopFunc {
+		return Func(func() {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		})
+	}
+
+	m := New()
+	intake := m.Stage("intake", time.Second)
+	drain := m.Stage("drain", time.Second)
+	closing := m.Stage("close", time.Second)
+	closing.Add("database", record("database"))
+	drain.Add("workers", record("workers"))
+	intake.Add("http", record("http"))
+
+	assert.NoError(t, m.Shutdown(context.Background()))
+	assert.Equal(t, []string{"http", "workers", "database"}, order)
+}
+
+func TestManager_ContinuesPastFailuresAndTimeouts(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	closed := false
+
+	m := New()
+	drain := m.Stage("drain", 20*time.Millisecond)
+	drain.Add("stuck", func(ctx context.Context) error {
+		<-block // ignores ctx
+		return nil
+	})
+	drain.Add("broken", func(ctx context.Context) error { return errors.New("boom") })
+	m.Stage("close", time.Second).Add("database", Func(func() { closed = true }))
+
+	err := m.Shutdown(context.Background())
+
+	assert.True(t, closed, "later stages run after a failed one")
+	assert.ErrorContains(t, err, "drain/stuck: gave up waiting: context deadline exceeded")
+	assert.ErrorContains(t, err, "drain/broken: boom")
+}
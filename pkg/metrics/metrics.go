@@ -5,6 +5,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// BalanceDistributionBounds are the upper bounds BalanceDistribution counts users under
+var BalanceDistributionBounds = []float64{0, 100, 500, 1000, 5000, 10000, 50000, 100000}
+
 var (
 	// HTTPRequestsTotal tracks total number of HTTP requests
 	HTTPRequestsTotal = promauto.NewCounterVec(
@@ -108,6 +111,33 @@ var (
 		[]string{"transaction_type"},
 	)
 
+	// TaskCallbackDeliveries tracks completion callback deliveries by outcome
+	TaskCallbackDeliveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "task_callback_deliveries_total",
+			Help: "Total number of task completion callbacks by delivery status",
+		},
+		[]string{"status"}, // delivered, failed
+	)
+
+	// TaskRetries tracks worker task attempts that failed transiently and were retried
+	TaskRetries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "task_retries_total",
+			Help: "Total number of worker task retries after transient failures",
+		},
+		[]string{"transaction_type"},
+	)
+
+	// TaskDeadLettered tracks tasks moved to the dead-letter queue after exhausting retries
+	TaskDeadLettered = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "task_dead_lettered_total",
+			Help: "Total number of worker tasks moved to the dead-letter queue",
+		},
+		[]string{"transaction_type"},
+	)
+
 	// ===== BUSINESS METRICS =====
 
 	// UserRegistrationTotal tracks total user registrations
@@ -132,7 +162,34 @@ var (
 			Name: "user_login_total",
 			Help: "Total number of user logins",
 		},
-		[]string{"status"}, // success, failure
+		[]string{"status"}, // success, failure, step_up, impossible_travel
+	)
+
+	// CaptchaChallengesTotal tracks CAPTCHAs asked of clients after repeated failures
+	CaptchaChallengesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "captcha_challenges_total",
+			Help: "Total number of CAPTCHA checks on auth endpoints",
+		},
+		[]string{"scope", "result"}, // result: required, invalid, passed, error
+	)
+
+	// BruteForceEventsTotal tracks auth attempts slowed down or refused after repeated failures
+	BruteForceEventsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "brute_force_events_total",
+			Help: "Total number of auth attempts tarpitted or rejected, and of bans",
+		},
+		[]string{"action"}, // tarpit, reject, ban_ip, ban_username
+	)
+
+	// QuotaExceededTotal tracks requests refused for exceeding their user's request quota
+	QuotaExceededTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "quota_exceeded_total",
+			Help: "Total number of requests refused for exceeding a request quota",
+		},
+		[]string{"period"}, // day, month
 	)
 
 	// TransactionVolume tracks total transaction volume in currency units
@@ -153,6 +210,40 @@ var (
 		[]string{"transaction_type", "status"}, // credit, debit, transfer, success, failed
 	)
 
+	// BalanceUpdateConflicts counts balance writes rejected because the balance changed after it was read
+	BalanceUpdateConflicts = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "balance_update_conflicts_total",
+			Help: "Total number of balance updates retried after an optimistic concurrency conflict",
+		},
+	)
+
+	// DatabaseRetries counts transactions rerun after a transient database error
+	DatabaseRetries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "database_retries_total",
+			Help: "Total number of transactions retried after a serialization failure, deadlock or dropped connection",
+		},
+		[]string{"code"}, // SQLSTATE, or "connection" for errors before the server answered
+	)
+
+	// TransactionsArchived counts transactions moved to the archive by the retention job
+	TransactionsArchived = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "transactions_archived_total",
+			Help: "Total number of transactions moved from the hot table to the archive",
+		},
+	)
+
+	// TransactionIdempotentReplays counts requests answered with an earlier transaction for a reused idempotency key
+	TransactionIdempotentReplays = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "transaction_idempotent_replays_total",
+			Help: "Total number of transaction requests answered from an earlier transaction with the same idempotency key",
+		},
+		[]string{"transaction_type"},
+	)
+
 	// AverageTransactionAmount tracks average transaction amount
 	AverageTransactionAmount = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -171,12 +262,38 @@ var (
 		},
 	)
 
-	// BalanceDistribution tracks balance distribution across users
-	BalanceDistribution = promauto.NewHistogram(
-		prometheus.HistogramOpts{
-			Name:    "user_balance_distribution",
-			Help:    "Distribution of user balances",
-			Buckets: []float64{0, 100, 500, 1000, 5000, 10000, 50000, 100000},
+	// BalanceDistribution tracks balance distribution across users, counted
+	// cumulatively like histogram buckets: users with a balance of at most le
+	BalanceDistribution = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "user_balance_distribution_users",
+			Help: "Number of users with a balance at or below le",
+		},
+		[]string{"le"}, // BalanceDistributionBounds and +Inf
+	)
+
+	// ReconciliationRuns counts balance reconciliation runs by outcome
+	ReconciliationRuns = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reconciliation_runs_total",
+			Help: "Total number of balance reconciliation runs by status",
+		},
+		[]string{"status"}, // completed, failed
+	)
+
+	// ReconciliationIssues tracks the balance discrepancies found by the latest reconciliation run
+	ReconciliationIssues = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "reconciliation_issues",
+			Help: "Number of stored balances that disagreed with their transactions in the latest reconciliation run",
+		},
+	)
+
+	// ReconciliationLastSuccess tracks when reconciliation last completed, for staleness alerts
+	ReconciliationLastSuccess = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "reconciliation_last_success_timestamp_seconds",
+			Help: "Unix time of the last completed balance reconciliation run",
 		},
 	)
 
@@ -246,7 +363,7 @@ var (
 			Name: "database_connection_pool",
 			Help: "Database connection pool metrics",
 		},
-		[]string{"state"}, // active, idle, total
+		[]string{"state"}, // active, idle, total, max
 	)
 
 	// APIResponseTimePercentiles tracks API response time percentiles
@@ -288,6 +405,24 @@ var (
 		[]string{"transaction_type"},
 	)
 
+	// ScheduledTransactionRetries tracks retries scheduled after a failed execution
+	ScheduledTransactionRetries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "scheduled_transaction_retries_total",
+			Help: "Total number of retries scheduled for failed scheduled transaction executions",
+		},
+		[]string{"transaction_type"},
+	)
+
+	// ScheduledTransactionRetriesExhausted tracks executions that failed after using every retry
+	ScheduledTransactionRetriesExhausted = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "scheduled_transaction_retries_exhausted_total",
+			Help: "Total number of scheduled transactions marked failed after exhausting retries",
+		},
+		[]string{"transaction_type"},
+	)
+
 	// ScheduledTransactionExecutionDuration tracks scheduled transaction execution duration
 	ScheduledTransactionExecutionDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -305,4 +440,158 @@ var (
 			Help: "Current number of pending scheduled transactions",
 		},
 	)
+
+	// FraudAssessments tracks fraud screening outcomes by decision
+	FraudAssessments = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fraud_assessments_total",
+			Help: "Total number of transactions scored for fraud, by decision",
+		},
+		[]string{"decision"},
+	)
+
+	// FraudRuleHits tracks how often each fraud rule contributes to a score
+	FraudRuleHits = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fraud_rule_hits_total",
+			Help: "Total number of fraud assessments each rule contributed to",
+		},
+		[]string{"rule"},
+	)
+
+	// FraudScore tracks the distribution of fraud scores
+	FraudScore = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "fraud_score",
+			Help:    "Fraud scores given to transactions, from 0 to 100",
+			Buckets: []float64{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100},
+		},
+	)
+
+	// NotificationsSent tracks notification deliveries by channel, event and
+	// status (sent, failed, or dropped with no channel when the delivery queue was full)
+	NotificationsSent = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "notifications_sent_total",
+			Help: "Total number of notifications handled, by channel, event and status",
+		},
+		[]string{"channel", "event", "status"},
+	)
+
+	// AlertsTriggered tracks user alert rules firing, by rule type
+	AlertsTriggered = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "alerts_triggered_total",
+			Help: "Total number of user balance and transaction alerts triggered, by rule type",
+		},
+		[]string{"type"},
+	)
+
+	// CampaignBonusesAwarded tracks campaign bonuses credited, by campaign condition
+	CampaignBonusesAwarded = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "campaign_bonuses_awarded_total",
+			Help: "Total number of campaign bonuses credited, by campaign condition",
+		},
+		[]string{"condition"},
+	)
+
+	// ReferralsTotal tracks referrals made at registration and rewarded, by status
+	ReferralsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "referrals_total",
+			Help: "Total number of referrals made and rewarded, by status",
+		},
+		[]string{"status"},
+	)
+
+	// KYCVerificationsTotal tracks identity verifications submitted and decided, by status
+	KYCVerificationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kyc_verifications_total",
+			Help: "Total number of KYC verifications submitted and decided, by status",
+		},
+		[]string{"status"},
+	)
+
+	// DocumentUploadsTotal tracks document uploads that were stored or refused
+	// by the virus scanner, by result
+	DocumentUploadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "document_uploads_total",
+			Help: "Total number of document uploads, by result (stored, infected)",
+		},
+		[]string{"result"},
+	)
+
+	// DisputesTotal tracks disputes opened and moved to each status, by status
+	DisputesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "disputes_total",
+			Help: "Total number of disputes opened and moved to each status, by status",
+		},
+		[]string{"status"},
+	)
+
+	// ChargebacksTotal tracks chargebacks received and moved to each status, by status
+	ChargebacksTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "chargebacks_total",
+			Help: "Total number of chargebacks received and moved to each status, by status",
+		},
+		[]string{"status"},
+	)
+
+	// AccountFreezesTotal tracks accounts frozen by chargebacks
+	AccountFreezesTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "account_freezes_total",
+			Help: "Total number of accounts frozen by chargebacks",
+		},
+	)
+
+	// PayoutsTotal tracks merchant payouts created and settled, by status
+	PayoutsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "payouts_total",
+			Help: "Total number of merchant payouts created and settled, by status",
+		},
+		[]string{"status"},
+	)
+
+	// WebhookEventsTotal tracks payment provider webhooks by provider and
+	// outcome: an event status, rejected or duplicate
+	WebhookEventsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_events_total",
+			Help: "Total number of payment provider webhooks received, by provider and outcome",
+		},
+		[]string{"provider", "outcome"},
+	)
+
+	// BankTransfersTotal tracks ACH and SEPA transfers initiated, settled
+	// and returned, by direction and status
+	BankTransfersTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "bank_transfers_total",
+			Help: "Total number of bank transfers initiated, settled and returned, by direction and status",
+		},
+		[]string{"direction", "status"},
+	)
+
+	// InvoicesIssued tracks monthly fee invoices issued
+	InvoicesIssued = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "invoices_issued_total",
+			Help: "Total number of monthly fee invoices issued",
+		},
+	)
+
+	// NotificationQueueDepth tracks notifications waiting for a delivery worker
+	NotificationQueueDepth = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "notification_queue_depth",
+			Help: "Current number of notifications waiting to be delivered",
+		},
+	)
 )
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Goroutines tracks the goroutines running for each background component
+var Goroutines = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "component_goroutines",
+		Help: "Goroutines currently running per background component",
+	},
+	[]string{"component"}, // worker, worker_retry, worker_callback, batch, batch_worker, scheduler
+)
+
+// TrackGoroutine counts a goroutine of component as running until the returned function is called.
+func TrackGoroutine(component string) func() {
+	gauge := Goroutines.WithLabelValues(component)
+	gauge.Inc()
+	return gauge.Dec
+}
+
+// RegisterRuntimeCollectors registers the process and Go runtime collectors.
+func RegisterRuntimeCollectors() error {
+	prometheus.Unregister(collectors.NewGoCollector())
+	goCollector := collectors.NewGoCollector(collectors.WithGoCollectorRuntimeMetrics(
+		collectors.MetricsGC,
+		collectors.MetricsMemory,
+		collectors.MetricsScheduler,
+	))
+	if err := register(goCollector); err != nil {
+		return err
+	}
+	return register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+}
+
+// register registers c with the default registry, accepting one that already is
+func register(c prometheus.Collector) error {
+	var already prometheus.AlreadyRegisteredError
+	if err := prometheus.Register(c); err != nil && !errors.As(err, &already) {
+		return err
+	}
+	return nil
+}
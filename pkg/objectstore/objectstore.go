@@ -0,0 +1,20 @@
+// Package objectstore keeps binary objects on the local disk or in an S3 bucket.
+package objectstore
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkKey rejects keys that are empty or could escape the store's root
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid object key %q", key)
+		}
+	}
+	return nil
+}
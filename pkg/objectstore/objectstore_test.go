@@ -0,0 +1,114 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/melihgurlek/backend-path/pkg/secrets"
+)
+
+var png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestCheckKey(t *testing.T) {
+	for _, key := range []string{"avatars/1/a.png", "a"} {
+		assert.NoError(t, checkKey(key), key)
+	}
+	for _, key := range []string{"", "/etc/passwd", "avatars/../../x", "avatars//a", "a\\b", "avatars/."} {
+		assert.Error(t, checkKey(key), key)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+
+	data, _, err := store.Get(ctx, "avatars/1/a.png")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	require.NoError(t, store.Put(ctx, "avatars/1/a.png", "image/png", png))
+	data, contentType, err := store.Get(ctx, "avatars/1/a.png")
+	require.NoError(t, err)
+	assert.Equal(t, png, data)
+	assert.Equal(t, "image/png", contentType)
+
+	require.NoError(t, store.Delete(ctx, "avatars/1/a.png"))
+	require.NoError(t, store.Delete(ctx, "avatars/1/a.png"))
+	data, _, err = store.Get(ctx, "avatars/1/a.png")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	assert.Error(t, store.Put(ctx, "../escape", "image/png", png))
+}
+
+// fakeS3 keeps the objects of a bucket in memory
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/bucket/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = string(body)
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		io.WriteString(w, body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	srv := httptest.NewServer(&fakeS3{objects: map[string]string{}, types: map[string]string{}})
+	defer srv.Close()
+	store := NewS3Store("bucket", "eu-west-1", srv.URL, secrets.AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"})
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "avatars/1/a.png", "image/png", png))
+	data, contentType, err := store.Get(ctx, "avatars/1/a.png")
+	require.NoError(t, err)
+	assert.Equal(t, png, data)
+	assert.Equal(t, "image/png", contentType)
+
+	require.NoError(t, store.Delete(ctx, "avatars/1/a.png"))
+	data, _, err = store.Get(ctx, "avatars/1/a.png")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	denied := NewS3Store("bucket", "eu-west-1", srv.URL, secrets.AWSCredentials{AccessKeyID: "OTHER", SecretAccessKey: "secret"})
+	assert.Error(t, denied.Put(ctx, "avatars/1/a.png", "image/png", png))
+}
+
+func TestNewS3Store_BucketURL(t *testing.T) {
+	assert.Equal(t, "https://bucket.s3.eu-west-1.amazonaws.com", NewS3Store("bucket", "eu-west-1", "", secrets.AWSCredentials{}).bucketURL)
+	assert.Equal(t, "http://minio:9000/bucket", NewS3Store("bucket", "us-east-1", "http://minio:9000/", secrets.AWSCredentials{}).bucketURL)
+}
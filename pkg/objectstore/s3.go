@@ -0,0 +1,125 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/melihgurlek/backend-path/pkg/secrets"
+)
+
+// maxObjectSize bounds how much of an object Get reads into memory
+const maxObjectSize = 32 << 20
+
+// S3Store keeps objects in an S3 bucket, or in a bucket of an S3-compatible service.
+type S3Store struct {
+	bucketURL string // objects live at bucketURL + "/" + key
+	region    string
+	creds     secrets.AWSCredentials
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3Store creates a store for bucket in region.
+func NewS3Store(bucket, region, endpoint string, creds secrets.AWSCredentials) *S3Store {
+	bucketURL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, region)
+	if endpoint != "" {
+		bucketURL = strings.TrimRight(endpoint, "/") + "/" + bucket
+	}
+	return &S3Store{
+		bucketURL: bucketURL,
+		region:    region,
+		creds:     creds,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		now:       time.Now,
+	}
+}
+
+// do sends a signed request for the object under key
+func (s *S3Store) do(ctx context.Context, method, key, contentType string, body []byte) (*http.Response, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	escaped := make([]string, 0, strings.Count(key, "/")+1)
+	for _, part := range strings.Split(key, "/") {
+		escaped = append(escaped, url.PathEscape(part))
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.bucketURL+"/"+strings.Join(escaped, "/"), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	// S3 refuses requests that don't state the hash of their payload
+	sum := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	secrets.SignV4(req, body, s.creds, s.region, "s3", s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s failed: %w", method, key, err)
+	}
+	return resp, nil
+}
+
+// responseError describes an S3 error response and closes its body
+func responseError(resp *http.Response, method, key string) error {
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	return fmt.Errorf("s3 %s %s returned %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(msg)))
+}
+
+// Put uploads data under key
+func (s *S3Store) Put(ctx context.Context, key, contentType string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, contentType, data)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, http.MethodPut, key)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads the object under key, returning nil data if there is none
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, string, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, "", nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", responseError(resp, http.MethodGet, key)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxObjectSize))
+	if err != nil {
+		return nil, "", fmt.Errorf("read s3 object %s: %w", key, err)
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// Delete removes the object under key.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, "", nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return responseError(resp, http.MethodDelete, key)
+	}
+	resp.Body.Close()
+	return nil
+}
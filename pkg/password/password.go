@@ -0,0 +1,72 @@
+// Package password hashes and verifies user passwords.
+package password
+
+import (
+	"errors"
+)
+
+// ErrUnsupportedHash is returned when a stored hash isn't in a format the hasher understands
+var ErrUnsupportedHash = errors.New("password: unsupported hash format")
+
+// Hasher creates and checks password hashes in a self-describing encoded form
+type Hasher interface {
+	// Hash returns the encoded hash of password
+	Hash(password string) (string, error)
+	// Verify reports whether password matches encoded. A mismatch is not an error.
+	Verify(encoded, password string) (bool, error)
+	// NeedsRehash reports whether encoded was made with weaker or different settings than Hash uses now
+	NeedsRehash(encoded string) bool
+	// Recognizes reports whether encoded is in this hasher's format
+	Recognizes(encoded string) bool
+}
+
+// Chain hashes with a primary hasher and verifies with whichever hasher recognizes the stored hash
+type Chain struct {
+	primary Hasher
+	legacy  []Hasher
+}
+
+// NewChain creates a Chain that hashes with primary and also verifies hashes made by legacy
+func NewChain(primary Hasher, legacy ...Hasher) *Chain {
+	return &Chain{primary: primary, legacy: legacy}
+}
+
+// Hash returns the encoded hash of password made by the primary hasher
+func (c *Chain) Hash(password string) (string, error) {
+	return c.primary.Hash(password)
+}
+
+// Verify checks password with the hasher that made encoded
+func (c *Chain) Verify(encoded, password string) (bool, error) {
+	h := c.hasherFor(encoded)
+	if h == nil {
+		return false, ErrUnsupportedHash
+	}
+	return h.Verify(encoded, password)
+}
+
+// NeedsRehash reports whether encoded was made by a legacy hasher or with outdated primary settings
+func (c *Chain) NeedsRehash(encoded string) bool {
+	if !c.primary.Recognizes(encoded) {
+		return true
+	}
+	return c.primary.NeedsRehash(encoded)
+}
+
+// Recognizes reports whether any hasher in the chain recognizes encoded
+func (c *Chain) Recognizes(encoded string) bool {
+	return c.hasherFor(encoded) != nil
+}
+
+// hasherFor returns the first hasher that recognizes encoded, or nil
+func (c *Chain) hasherFor(encoded string) Hasher {
+	if c.primary.Recognizes(encoded) {
+		return c.primary
+	}
+	for _, h := range c.legacy {
+		if h.Recognizes(encoded) {
+			return h
+		}
+	}
+	return nil
+}
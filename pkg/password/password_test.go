@@ -0,0 +1,89 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fastArgon2id keeps the tests quick; production uses DefaultArgon2idParams
+func fastArgon2id() *Argon2idHasher {
+	return NewArgon2idHasher(Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1})
+}
+
+func TestArgon2idHasher_HashVerify(t *testing.T) {
+	h := fastArgon2id()
+
+	encoded, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash returned error: %v", err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("unexpected encoding %q", encoded)
+	}
+
+	if ok, err := h.Verify(encoded, "correct horse"); err != nil || !ok {
+		t.Errorf("Verify(right password) = %v, %v; want true, nil", ok, err)
+	}
+	if ok, err := h.Verify(encoded, "wrong horse"); err != nil || ok {
+		t.Errorf("Verify(wrong password) = %v, %v; want false, nil", ok, err)
+	}
+	if h.NeedsRehash(encoded) {
+		t.Error("expected hash with current params not to need rehash")
+	}
+
+	stronger := NewArgon2idHasher(Argon2idParams{Memory: 2048, Iterations: 1, Parallelism: 1})
+	if !stronger.NeedsRehash(encoded) {
+		t.Error("expected hash with weaker params to need rehash")
+	}
+	// Verification uses the params stored in the hash, not the hasher's
+	if ok, _ := stronger.Verify(encoded, "correct horse"); !ok {
+		t.Error("expected hash made with other params to still verify")
+	}
+}
+
+func TestArgon2idHasher_Malformed(t *testing.T) {
+	h := fastArgon2id()
+	for _, encoded := range []string{
+		"",
+		"$argon2id$v=19$m=1024,t=1,p=1$salt",
+		"$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=0,t=1,p=1$c2FsdA$a2V5",
+		"$argon2i$v=19$m=1024,t=1,p=1$c2FsdA$a2V5",
+	} {
+		if _, err := h.Verify(encoded, "pw"); !errors.Is(err, ErrUnsupportedHash) {
+			t.Errorf("Verify(%q) error = %v, want ErrUnsupportedHash", encoded, err)
+		}
+	}
+}
+
+func TestChain_MigratesBcrypt(t *testing.T) {
+	bc := NewBcryptHasher(4)
+	legacy, err := bc.Hash("s3cret")
+	if err != nil {
+		t.Fatalf("bcrypt Hash returned error: %v", err)
+	}
+
+	chain := NewChain(fastArgon2id(), bc)
+	if ok, err := chain.Verify(legacy, "s3cret"); err != nil || !ok {
+		t.Errorf("Verify(bcrypt hash) = %v, %v; want true, nil", ok, err)
+	}
+	if !chain.NeedsRehash(legacy) {
+		t.Error("expected bcrypt hash to need rehash under an argon2id chain")
+	}
+
+	upgraded, err := chain.Hash("s3cret")
+	if err != nil {
+		t.Fatalf("Hash returned error: %v", err)
+	}
+	if chain.NeedsRehash(upgraded) {
+		t.Error("expected argon2id hash not to need rehash")
+	}
+	if ok, _ := chain.Verify(upgraded, "s3cret"); !ok {
+		t.Error("expected upgraded hash to verify")
+	}
+
+	if _, err := chain.Verify("!erased", "s3cret"); !errors.Is(err, ErrUnsupportedHash) {
+		t.Errorf("Verify(unknown format) error = %v, want ErrUnsupportedHash", err)
+	}
+}
@@ -0,0 +1,67 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_Structure(t *testing.T) {
+	doc := New(A4Width, A4Height)
+	first := doc.AddPage()
+	first.Text(50, 800, HelveticaBold, 18, "Invoice")
+	first.Line(50, 790, 545, 790, 0.5)
+	doc.AddPage().Text(50, 800, Helvetica, 10, "Page 2")
+	out := doc.Bytes()
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), "/Count 2")
+	assert.Contains(t, string(out), "/BaseFont /Helvetica-Bold")
+	assert.Contains(t, string(out), "BT /F2 18 Tf 50 800 Td (Invoice) Tj ET\n")
+	assert.Contains(t, string(out), "0.5 w 50 790 m 545 790 l S\n")
+
+	// Every cross-reference entry points at its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(out[xref:], []byte("xref\n0 9\n")), "2 fonts and 2 pages of 2 objects each, after the catalog and page tree")
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	require.Len(t, entries, 8)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+
+	// Stream lengths match their content
+	for _, stream := range regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)endstream`).FindAllSubmatch(out, -1) {
+		assert.Equal(t, string(stream[1]), strconv.Itoa(len(stream[2])))
+	}
+}
+
+func TestDocument_WithoutPages(t *testing.T) {
+	out := New(A4Width, A4Height).Bytes()
+	assert.Contains(t, string(out), "/Count 1", "a PDF needs a page")
+}
+
+func TestEncode(t *testing.T) {
+	assert.Equal(t, `a \(b\) c\\d`, encode(`a (b) c\d`))
+	assert.Equal(t, "caf\xe9 ? x y", encode("café € x\ny"))
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.InDelta(t, 27.8, TextWidth(Helvetica, 10, "12345"), 0.001)
+	assert.InDelta(t, 27.8, TextWidth(HelveticaBold, 10, "12345"), 0.001, "digits are as wide in bold")
+	assert.Greater(t, TextWidth(HelveticaBold, 10, "Total"), TextWidth(Helvetica, 10, "Total"))
+
+	page := &Page{}
+	page.TextRight(100, 10, Helvetica, 10, "1.00")
+	assert.Equal(t, "BT /F1 10 Tf 80.54 10 Td (1.00) Tj ET\n", page.content.String())
+}
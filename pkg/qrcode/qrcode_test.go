@@ -0,0 +1,254 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestLayouts_FillVersion(t *testing.T) {
+	for v := 1; v <= maxVersion; v++ {
+		// Modules left for codewords once the function patterns are drawn
+		raw := (v*16+128)*v + 64
+		if v >= 2 {
+			n := v/7 + 2
+			raw -= (25*n-10)*n - 55
+			if v >= 7 {
+				raw -= 36
+			}
+		}
+		l := layouts[v]
+		if got := l.dataCodewords() + (l.Blocks1+l.Blocks2)*l.ECC; got != raw/8 {
+			t.Errorf("version %d has %d codewords, want %d", v, got, raw/8)
+		}
+	}
+}
+
+func TestRSRemainder(t *testing.T) {
+	// "HELLO WORLD" as version 1-M alphanumeric data, from the worked example
+	// of the standard's tutorials
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder = %v, want %v", got, want)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	c := &Code{Size: 45, Version: 7, modules: make([]bool, 45*45), isFunc: make([]bool, 45*45)}
+	c.drawFormatBits(5)
+	c.drawVersionBits()
+
+	// Level M with mask 5, read along the top left copy
+	format := 0
+	for _, p := range [][2]int{{0, 8}, {1, 8}, {2, 8}, {3, 8}, {4, 8}, {5, 8}, {7, 8}, {8, 8}, {8, 7}, {8, 5}, {8, 4}, {8, 3}, {8, 2}, {8, 1}, {8, 0}} {
+		format <<= 1
+		if c.Dark(p[0], p[1]) {
+			format |= 1
+		}
+	}
+	if want := 0b100000011001110; format != want {
+		t.Errorf("format bits = %015b, want %015b", format, want)
+	}
+
+	// Version 7, most significant bit at the bottom right of the top right block
+	version := 0
+	for i := 17; i >= 0; i-- {
+		version <<= 1
+		if c.Dark(c.Size-11+i%3, i/3) {
+			version |= 1
+		}
+	}
+	if want := 0b000111110010010100; version != want {
+		t.Errorf("version bits = %018b, want %018b", version, want)
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		version int
+	}{
+		{"empty", "", 1},
+		{"fills version 1", strings.Repeat("a", 14), 1},
+		{"needs version 2", strings.Repeat("a", 15), 2},
+		{"signed token", "eyJ1Ijo3LCJhIjoiMTIuNTAiLCJlIjoxNzkwMDAwMDAwfQ.h7Ht0qVbq3p3fH9Q2cV1a0s8a6cQb3uB2nqKjY1F7mE", 6},
+		{"version info", strings.Repeat("0123456789", 12), 7},
+		{"two block sizes", strings.Repeat("xyz", 60), 9},
+		{"16-bit count", strings.Repeat("q", 300), 13},
+		{"largest", strings.Repeat("z", 666), 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Encode([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("Encode returned error: %v", err)
+			}
+			if c.Version != tt.version || c.Size != tt.version*4+17 {
+				t.Errorf("version %d of size %d, want version %d", c.Version, c.Size, tt.version)
+			}
+			if got := decode(t, c); got != tt.data {
+				t.Errorf("decoded %q, want %q", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestEncode_TooLong(t *testing.T) {
+	if _, err := Encode(bytes.Repeat([]byte("z"), 667)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Encode error = %v, want ErrTooLong", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	c, err := Encode([]byte("hello"))
+	if err != nil {
+		t.Fatalf("Encode returned error: %v", err)
+	}
+
+	data, err := c.PNG(4)
+	if err != nil {
+		t.Fatalf("PNG returned error: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("PNG is unreadable: %v", err)
+	}
+	if width := (c.Size + 2*QuietZone) * 4; img.Bounds().Dx() != width || img.Bounds().Dy() != width {
+		t.Errorf("PNG is %v, want %dx%d", img.Bounds(), width, width)
+	}
+	// The quiet zone is light and the top left finder dark
+	if r, _, _, _ := img.At(0, 0).RGBA(); r == 0 {
+		t.Error("quiet zone is dark")
+	}
+	if r, _, _, _ := img.At(QuietZone*4, QuietZone*4).RGBA(); r != 0 {
+		t.Error("finder pattern is light")
+	}
+
+	svg := string(c.SVG(4))
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>") || !strings.Contains(svg, `viewBox="0 0 29 29"`) {
+		t.Errorf("unexpected SVG %q", svg)
+	}
+}
+
+// decode reads a code back: it checks the format information, removes the
+// mask, de-interleaves the codewords, checks each block's error correction
+// and returns the byte mode data
+func decode(t *testing.T, c *Code) string {
+	t.Helper()
+	format := 0
+	for i := 14; i >= 0; i-- {
+		format <<= 1
+		if c.Dark(c.Size-1-i, 8) && i < 8 || i >= 8 && c.Dark(8, c.Size-15+i) {
+			format |= 1
+		}
+	}
+	format ^= 0x5412
+	if level := format >> 13; level != formatLevelM {
+		t.Fatalf("format level = %d, want M", level)
+	}
+	mask := format >> 10 & 7
+	check := &Code{Size: c.Size, Version: c.Version, modules: make([]bool, c.Size*c.Size), isFunc: make([]bool, c.Size*c.Size)}
+	check.drawFormatBits(mask)
+	for i := 0; i < 8; i++ {
+		if i == 6 {
+			continue // timing patterns
+		}
+		if check.Dark(8, i) != c.Dark(8, i) || check.Dark(i, 8) != c.Dark(i, 8) {
+			t.Fatal("format information copies differ")
+		}
+	}
+
+	plain := &Code{Size: c.Size, Version: c.Version, modules: append([]bool(nil), c.modules...), isFunc: make([]bool, c.Size*c.Size)}
+	plain.drawFunctionPatterns()
+	for i := range plain.modules {
+		if plain.isFunc[i] {
+			plain.modules[i] = c.modules[i]
+		}
+	}
+	plain.applyMask(mask)
+
+	var bits bitBuffer
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if (right+1)&2 == 0 {
+				y = c.Size - 1 - vert
+			}
+			for x := right; x >= right-1; x-- {
+				if !plain.isFunc[y*c.Size+x] {
+					bits = append(bits, plain.Dark(x, y))
+				}
+			}
+		}
+	}
+	layout := layouts[c.Version]
+	blockCount := layout.Blocks1 + layout.Blocks2
+	codewords := bits[:len(bits)/8*8].bytes()[:layout.dataCodewords()+blockCount*layout.ECC]
+
+	blocks := make([][]byte, blockCount)
+	next := 0
+	for i := 0; i <= layout.Data1; i++ {
+		for b := range blocks {
+			if i < layout.Data1 || b >= layout.Blocks1 {
+				blocks[b] = append(blocks[b], codewords[next])
+				next++
+			}
+		}
+	}
+	var data []byte
+	for _, block := range blocks {
+		data = append(data, block...)
+	}
+	for i := 0; i < layout.ECC; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[next])
+			next++
+		}
+	}
+	for b, block := range blocks {
+		// A valid block is divisible by the generator, so it vanishes at
+		// each of its roots
+		root := byte(1)
+		for i := 0; i < layout.ECC; i++ {
+			var sum byte
+			for _, cw := range block {
+				sum = gfMultiply(sum, root) ^ cw
+			}
+			if sum != 0 {
+				t.Fatalf("block %d has a nonzero syndrome at root %d", b, i)
+			}
+			root = gfMultiply(root, 0x02)
+		}
+	}
+
+	if data[0]>>4 != 0b0100 {
+		t.Fatalf("mode = %04b, want byte mode", data[0]>>4)
+	}
+	var stream bitBuffer
+	for _, b := range data {
+		stream.append(int(b), 8)
+	}
+	read := func(from, n int) int {
+		v := 0
+		for _, set := range stream[from : from+n] {
+			v <<= 1
+			if set {
+				v |= 1
+			}
+		}
+		return v
+	}
+	count, pos := read(4, countBits(c.Version)), 4+countBits(c.Version)
+	out := make([]byte, count)
+	for i := range out {
+		out[i] = byte(read(pos+8*i, 8))
+	}
+	return string(out)
+}
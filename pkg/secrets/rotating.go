@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Rotating is a secret that is re-read from its provider lazily.
+type Rotating struct {
+	provider Provider
+	ref      string
+	refresh  time.Duration
+	grace    time.Duration
+	now      func() time.Time
+
+	mu             sync.Mutex
+	current        string
+	previous       string
+	fetchedAt      time.Time
+	previousExpiry time.Time
+}
+
+// NewRotating creates a lazily refreshed secret.
+func NewRotating(provider Provider, ref string, refresh, grace time.Duration) *Rotating {
+	return &Rotating{
+		provider: provider,
+		ref:      ref,
+		refresh:  refresh,
+		grace:    grace,
+		now:      time.Now,
+	}
+}
+
+// Current returns the current secret value, fetching it if it is stale.
+func (r *Rotating) Current(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.refreshLocked(ctx); err != nil {
+		return "", err
+	}
+	return r.current, nil
+}
+
+// Keys returns the current value followed by the previous one while it is still in grace.
+func (r *Rotating) Keys(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.refreshLocked(ctx); err != nil {
+		return nil, err
+	}
+	keys := []string{r.current}
+	if r.previous != "" && r.now().Before(r.previousExpiry) {
+		keys = append(keys, r.previous)
+	}
+	return keys, nil
+}
+
+func (r *Rotating) refreshLocked(ctx context.Context) error {
+	now := r.now()
+	if r.current != "" && now.Sub(r.fetchedAt) < r.refresh {
+		return nil
+	}
+
+	value, err := r.provider.GetSecret(ctx, r.ref)
+	if err != nil {
+		if r.current != "" {
+			// Keep serving the last known value until the next refresh interval
+			r.fetchedAt = now
+			return nil
+		}
+		return err
+	}
+
+	if r.current != "" && value != r.current {
+		r.previous = r.current
+		r.previousExpiry = now.Add(r.grace)
+	}
+	r.current = value
+	r.fetchedAt = now
+	return nil
+}
@@ -0,0 +1,53 @@
+// Package secrets reads application secrets from an external secrets manager.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrNotFound is returned when the backend has no secret (or field) for a reference.
+var ErrNotFound = errors.New("secret not found")
+
+// Provider fetches secret values by reference.
+type Provider interface {
+	GetSecret(ctx context.Context, ref string) (string, error)
+}
+
+// splitRef splits a "path#key" reference into its path and key.
+func splitRef(ref string) (path, key string) {
+	path, key, _ = strings.Cut(ref, "#")
+	return path, key
+}
+
+// field extracts key from a JSON object holding string values.
+func field(raw []byte, key, ref string) (string, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object: %w", ref, err)
+	}
+	v, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, ref)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("secret %s field %q is not a string", ref, key)
+	}
+	return s, nil
+}
+
+// EnvProvider resolves a reference as the name of an environment variable.
+type EnvProvider struct{}
+
+// GetSecret returns the value of the environment variable named by ref.
+func (EnvProvider) GetSecret(_ context.Context, ref string) (string, error) {
+	if v := os.Getenv(ref); v != "" {
+		return v, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrNotFound, ref)
+}
@@ -0,0 +1,152 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	value string
+	err   error
+	calls int
+}
+
+func (f *fakeProvider) GetSecret(context.Context, string) (string, error) {
+	f.calls++
+	return f.value, f.err
+}
+
+func TestRotating_RefreshAndGrace(t *testing.T) {
+	provider := &fakeProvider{value: "v1"}
+	r := NewRotating(provider, "jwt", time.Minute, 15*time.Minute)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	keys, err := r.Keys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1"}, keys)
+
+	// Within the refresh interval the provider is not asked again
+	provider.value = "v2"
+	keys, _ = r.Keys(ctx)
+	assert.Equal(t, []string{"v1"}, keys)
+	assert.Equal(t, 1, provider.calls)
+
+	now = now.Add(2 * time.Minute)
+	keys, _ = r.Keys(ctx)
+	assert.Equal(t, []string{"v2", "v1"}, keys)
+
+	// The old secret is dropped once its grace period ends
+	now = now.Add(16 * time.Minute)
+	keys, _ = r.Keys(ctx)
+	assert.Equal(t, []string{"v2"}, keys)
+}
+
+func TestRotating_KeepsLastValueOnError(t *testing.T) {
+	provider := &fakeProvider{err: errors.New("unavailable")}
+	r := NewRotating(provider, "jwt", time.Minute, time.Minute)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	_, err := r.Current(context.Background())
+	assert.Error(t, err, "no value fetched yet")
+
+	provider.value, provider.err = "v1", nil
+	_, err = r.Current(context.Background())
+	require.NoError(t, err)
+
+	provider.err = errors.New("unavailable")
+	now = now.Add(2 * time.Minute)
+	got, err := r.Current(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "v1", got)
+}
+
+func TestVaultProvider_GetSecret(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/backend/jwt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"data": map[string]string{"value": "s3cret", "old": "prev"}},
+		})
+	}))
+	defer srv.Close()
+
+	p := NewVaultProvider(srv.URL, "token", "secret")
+	ctx := context.Background()
+
+	got, err := p.GetSecret(ctx, "backend/jwt")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", got)
+
+	got, err = p.GetSecret(ctx, "backend/jwt#old")
+	require.NoError(t, err)
+	assert.Equal(t, "prev", got)
+
+	_, err = p.GetSecret(ctx, "backend/jwt#missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = p.GetSecret(ctx, "backend/other")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestAWSProvider_GetSecret(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+
+		var req struct{ SecretId string }
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.SecretId != "prod/db" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"not found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"SecretString": `{"url":"postgres://db"}`})
+	}))
+	defer srv.Close()
+
+	p := NewAWSProvider("eu-west-1", AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"})
+	p.endpoint = srv.URL + "/"
+	ctx := context.Background()
+
+	got, err := p.GetSecret(ctx, "prod/db#url")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://db", got)
+
+	got, err = p.GetSecret(ctx, "prod/db")
+	require.NoError(t, err)
+	assert.Equal(t, `{"url":"postgres://db"}`, got)
+
+	_, err = p.GetSecret(ctx, "prod/missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// Vector from the AWS Signature Version 4 test suite ("get-vanilla").
+func TestSignV4_KnownVector(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+	creds := AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+	SignV4(req, nil, creds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"))
+}
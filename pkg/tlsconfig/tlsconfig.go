@@ -0,0 +1,131 @@
+// Package tlsconfig builds the TLS settings of the HTTP servers.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Options selects where certificates come from
+type Options struct {
+	CertFile         string
+	KeyFile          string
+	AutocertDomains  []string
+	AutocertCacheDir string
+	AutocertEmail    string
+	MinVersion       string
+}
+
+// Server is the TLS setup of a server.
+type Server struct {
+	Config   *tls.Config
+	redirect func(http.Handler) http.Handler
+}
+
+// New builds the TLS setup described by opts.
+func New(opts Options) (*Server, error) {
+	cfg, err := modern(opts.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	server := &Server{Config: cfg, redirect: func(h http.Handler) http.Handler { return h }}
+
+	switch {
+	case len(opts.AutocertDomains) > 0:
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(opts.AutocertDomains...),
+			Cache:      autocert.DirCache(opts.AutocertCacheDir),
+			Email:      opts.AutocertEmail,
+		}
+		cfg.GetCertificate = manager.GetCertificate
+		// TLS-ALPN-01 challenges are answered on the TLS port itself
+		cfg.NextProtos = append(cfg.NextProtos, "acme-tls/1")
+		server.redirect = manager.HTTPHandler
+	case opts.CertFile != "" && opts.KeyFile != "":
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	default:
+		return nil, errors.New("a certificate and key file or autocert domains are required")
+	}
+	return server, nil
+}
+
+// NewMutual builds the settings of a server that only accepts clients with a certificate.
+func NewMutual(certFile, keyFile, clientCAFile, minVersion string) (*tls.Config, error) {
+	cfg, err := modern(minVersion)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	pemCAs, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(pemCAs) {
+		return nil, fmt.Errorf("no certificates found in client CA file %s", clientCAFile)
+	}
+	cfg.Certificates = []tls.Certificate{cert}
+	cfg.ClientCAs = clientCAs
+	cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	return cfg, nil
+}
+
+// modern returns settings without legacy protocols and ciphers.
+func modern(minVersion string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+		NextProtos:       []string{"h2", "http/1.1"},
+	}
+	switch minVersion {
+	case "", "1.2":
+		cfg.MinVersion = tls.VersionTLS12
+		cfg.CipherSuites = []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		}
+	case "1.3":
+		cfg.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported TLS version %q", minVersion)
+	}
+	return cfg, nil
+}
+
+// Redirect returns the handler of the plaintext port.
+func (s *Server) Redirect(httpsPort string) http.Handler {
+	return s.redirect(RedirectHandler(httpsPort))
+}
+
+// RedirectHandler permanently redirects requests to HTTPS on the same host.
+func RedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
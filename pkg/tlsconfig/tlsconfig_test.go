@@ -0,0 +1,101 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSigned writes a self-signed certificate for localhost and its key
+func writeSelfSigned(t *testing.T) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestNew_CertFiles(t *testing.T) {
+	certFile, keyFile := writeSelfSigned(t)
+
+	server, err := New(Options{CertFile: certFile, KeyFile: keyFile})
+	require.NoError(t, err)
+	assert.Len(t, server.Config.Certificates, 1)
+	assert.Equal(t, uint16(tls.VersionTLS12), server.Config.MinVersion)
+	assert.Equal(t, []string{"h2", "http/1.1"}, server.Config.NextProtos)
+
+	server, err = New(Options{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3"})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), server.Config.MinVersion)
+}
+
+func TestNew_Errors(t *testing.T) {
+	_, err := New(Options{})
+	assert.Error(t, err)
+
+	_, err = New(Options{CertFile: "missing.crt", KeyFile: "missing.key"})
+	assert.ErrorContains(t, err, "failed to load TLS certificate")
+
+	_, err = New(Options{AutocertDomains: []string{"api.example.com"}, MinVersion: "1.0"})
+	assert.ErrorContains(t, err, "unsupported TLS version")
+}
+
+func TestNewMutual(t *testing.T) {
+	certFile, keyFile := writeSelfSigned(t)
+
+	// The self-signed certificate serves as its own client CA
+	cfg, err := NewMutual(certFile, keyFile, certFile, "1.2")
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, cfg.ClientAuth)
+	assert.NotNil(t, cfg.ClientCAs)
+
+	_, err = NewMutual(certFile, keyFile, keyFile, "1.2")
+	assert.ErrorContains(t, err, "no certificates found in client CA file")
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		port     string
+		host     string
+		expected string
+	}{
+		{"443", "api.example.com", "https://api.example.com/users?page=2"},
+		{"8443", "api.example.com:8080", "https://api.example.com:8443/users?page=2"},
+	}
+	for _, tc := range tests {
+		req := httptest.NewRequest("GET", "http://"+tc.host+"/users?page=2", nil)
+		rw := httptest.NewRecorder()
+		RedirectHandler(tc.port).ServeHTTP(rw, req)
+
+		assert.Equal(t, http.StatusPermanentRedirect, rw.Code)
+		assert.Equal(t, tc.expected, rw.Header().Get("Location"))
+	}
+}
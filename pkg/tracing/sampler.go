@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Sampler names accepted by Config.Sampler
+const (
+	SamplerAlways      = "always"       // sample every trace
+	SamplerRatio       = "ratio"        // sample a fraction of new traces
+	SamplerRateLimited = "rate_limited" // sample at most a number of new traces a second
+)
+
+// newSampler builds the sampler named by cfg.
+func newSampler(cfg Config) (sdktrace.Sampler, error) {
+	switch cfg.Sampler {
+	case SamplerAlways, "":
+		return sdktrace.AlwaysSample(), nil
+	case SamplerRatio:
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio)), nil
+	case SamplerRateLimited:
+		if cfg.RateLimit <= 0 {
+			return nil, fmt.Errorf("rate limited sampler needs a positive rate, got %g", cfg.RateLimit)
+		}
+		return sdktrace.ParentBased(newRateLimitingSampler(cfg.RateLimit)), nil
+	default:
+		return nil, fmt.Errorf("unknown sampler %q", cfg.Sampler)
+	}
+}
+
+// rateLimitingSampler samples at most perSecond traces a second.
+type rateLimitingSampler struct {
+	perSecond float64
+	capacity  float64
+	now       func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimitingSampler(perSecond float64) *rateLimitingSampler {
+	capacity := math.Max(perSecond, 1)
+	return &rateLimitingSampler{perSecond: perSecond, capacity: capacity, now: time.Now, tokens: capacity}
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s *rateLimitingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	decision := sdktrace.Drop
+	if s.take() {
+		decision = sdktrace.RecordAndSample
+	}
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+// Description implements sdktrace.Sampler.
+func (s *rateLimitingSampler) Description() string {
+	return fmt.Sprintf("RateLimitingSampler{%g}", s.perSecond)
+}
+
+// take spends a token if one is available.
+func (s *rateLimitingSampler) take() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if !s.last.IsZero() {
+		s.tokens += now.Sub(s.last).Seconds() * s.perSecond
+		s.tokens = math.Min(s.tokens, s.capacity)
+	}
+	s.last = now
+
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestRateLimitingSampler(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newRateLimitingSampler(2)
+	s.now = func() time.Time { return now }
+
+	sampled := func() bool {
+		return s.ShouldSample(sdktrace.SamplingParameters{}).Decision == sdktrace.RecordAndSample
+	}
+	assert.True(t, sampled())
+	assert.True(t, sampled())
+	assert.False(t, sampled(), "the burst of one second's worth is spent")
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, sampled())
+	assert.False(t, sampled())
+
+	now = now.Add(time.Hour)
+	assert.True(t, sampled())
+	assert.True(t, sampled())
+	assert.False(t, sampled(), "idle time does not build up more than a second's worth")
+}
+
+func TestRateLimitingSampler_BelowOnePerSecond(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newRateLimitingSampler(0.5)
+	s.now = func() time.Time { return now }
+
+	assert.True(t, s.take())
+	now = now.Add(time.Second)
+	assert.False(t, s.take())
+	now = now.Add(time.Second)
+	assert.True(t, s.take())
+}
+
+func TestNewSampler(t *testing.T) {
+	_, err := newSampler(Config{Sampler: "sometimes"})
+	assert.Error(t, err)
+	_, err = newSampler(Config{Sampler: SamplerRateLimited})
+	assert.Error(t, err)
+
+	sampler, err := newSampler(Config{Sampler: SamplerRatio, SampleRatio: 0.25})
+	require.NoError(t, err)
+	assert.Contains(t, sampler.Description(), "TraceIDRatioBased{0.25}")
+}
@@ -3,6 +3,7 @@ package tracing
 import (
 	"context"
 	"fmt"
+	"net"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -15,25 +16,47 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// Config configures the tracer provider
+type Config struct {
+	Endpoint       string // OTLP/HTTP collector address
+	ServiceName    string
+	ServiceVersion string
+	Environment    string // deployment environment, such as production; omitted if empty
+
+	Sampler     string  // one of the Sampler constants; empty samples everything
+	SampleRatio float64 // fraction of new traces kept by SamplerRatio
+	RateLimit   float64 // new traces a second kept by SamplerRateLimited
+}
+
 // InitTracer initializes OpenTelemetry tracing
-func InitTracer(serviceName, serviceVersion string, jaegerURL string) (func(), error) {
+func InitTracer(cfg Config) (func(), error) {
 	ctx := context.Background()
 
+	sampler, err := newSampler(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create OTLP exporter
 	exporter, err := otlptracehttp.New(ctx,
-		otlptracehttp.WithEndpoint(jaegerURL),
+		otlptracehttp.WithEndpoint(cfg.Endpoint),
 		otlptracehttp.WithInsecure(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
 	}
 
-	// Create resource with service information
+	// Create resource with service, deployment and host information
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+	}
+	if cfg.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironment(cfg.Environment))
+	}
 	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceName(serviceName),
-			semconv.ServiceVersion(serviceVersion),
-		),
+		resource.WithAttributes(attrs...),
+		resource.WithHost(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource: %w", err)
@@ -45,6 +68,7 @@ func InitTracer(serviceName, serviceVersion string, jaegerURL string) (func(), e
 			sdktrace.WithBatchTimeout(5*time.Second),
 		),
 		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
 	)
 
 	// Set global trace provider
@@ -66,6 +90,16 @@ func InitTracer(serviceName, serviceVersion string, jaegerURL string) (func(), e
 	return cleanup, nil
 }
 
+// PingCollector checks that the collector at endpoint accepts connections.
+func PingCollector(ctx context.Context, endpoint string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to reach OTLP collector: %w", err)
+	}
+	return conn.Close()
+}
+
 // GetTracer returns a tracer for the given name
 func GetTracer(name string) trace.Tracer {
 	return otel.Tracer(name)
@@ -90,3 +124,35 @@ func SetSpanAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
 		span.SetAttributes(attrs...)
 	}
 }
+
+// TraceParent returns the W3C traceparent of the span in ctx, or "" if ctx has no valid span.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// SpanContextFromTraceParent parses a traceparent produced by TraceParent.
+func SpanContextFromTraceParent(traceParent string) trace.SpanContext {
+	carrier := propagation.MapCarrier{"traceparent": traceParent}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), carrier)
+	return trace.SpanContextFromContext(ctx)
+}
+
+// ContextWithTraceParent returns ctx with the span context of traceParent as remote parent.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	sc := SpanContextFromTraceParent(traceParent)
+	if !sc.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// LinkToTraceParent returns a span start option linking the new span to traceParent's.
+func LinkToTraceParent(traceParent string) trace.SpanStartOption {
+	sc := SpanContextFromTraceParent(traceParent)
+	if !sc.IsValid() {
+		return trace.WithLinks()
+	}
+	return trace.WithLinks(trace.Link{SpanContext: sc})
+}
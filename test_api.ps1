@@ -4,7 +4,7 @@ Write-Host "Testing Backend Path API endpoints..." -ForegroundColor Green
 # Test health endpoint
 Write-Host "`n1. Testing health endpoint..." -ForegroundColor Yellow
 try {
-    $health = Invoke-RestMethod -Uri "http://localhost:8080/api/v1/test/health" -Method GET
+    $health = Invoke-RestMethod -Uri "http://localhost:8080/healthz" -Method GET
     Write-Host "Health check: $($health.status)" -ForegroundColor Green
 }
 catch {
@@ -46,8 +46,8 @@ catch {
 # Test non-cached endpoint for comparison
 Write-Host "`n4. Testing non-cached endpoint (health) for comparison..." -ForegroundColor Yellow
 try {
-    $health1 = Invoke-RestMethod -Uri "http://localhost:8080/api/v1/test/health" -Method GET
-    $health2 = Invoke-RestMethod -Uri "http://localhost:8080/api/v1/test/health" -Method GET
+    $health1 = Invoke-RestMethod -Uri "http://localhost:8080/healthz" -Method GET
+    $health2 = Invoke-RestMethod -Uri "http://localhost:8080/healthz" -Method GET
     Write-Host "Health endpoint timestamps: $($health1.timestamp) vs $($health2.timestamp)" -ForegroundColor Cyan
     
     if ($health1.timestamp -ne $health2.timestamp) {
@@ -64,5 +64,5 @@ catch {
 Write-Host "`nCache testing completed!" -ForegroundColor Green
 Write-Host "`nCache behavior:" -ForegroundColor Cyan
 Write-Host "- GET requests to /api/v1/test/cache should be cached for 5 minutes" -ForegroundColor White
-Write-Host "- GET requests to /api/v1/test/health should NOT be cached" -ForegroundColor White
+Write-Host "- GET requests to /healthz should NOT be cached" -ForegroundColor White
 Write-Host "- Check X-Cache header in responses for HIT/MISS indication" -ForegroundColor White 
\ No newline at end of file
@@ -46,7 +46,7 @@ catch {
 Write-Host ""
 Write-Host "Testing health endpoint..." -ForegroundColor Yellow
 try {
-    $healthResponse = Invoke-RestMethod -Uri "http://localhost:8080/api/v1/test/health" -Method GET
+    $healthResponse = Invoke-RestMethod -Uri "http://localhost:8080/healthz" -Method GET
     Write-Host "Health check successful: $($healthResponse.status)" -ForegroundColor Green
 }
 catch {